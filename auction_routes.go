@@ -0,0 +1,9 @@
+package rep
+
+// AuctionRoutesStatus tells whether the auction routes of the cell, through
+// which the auctioneer fetches its state and places work on it, are closed.
+// The admin routes and those managing the containers stay open either way.
+type AuctionRoutesStatus struct {
+	Closed   bool  `json:"closed"`
+	ClosedAt int64 `json:"closed_at,omitempty"`
+}
@@ -1,25 +1,71 @@
 package auctioncellrep
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
+	"time"
 
 	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/ecrhelper"
 	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/containermetrics"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/clockskew"
+	"code.cloudfoundry.org/rep/contacts"
+	"code.cloudfoundry.org/rep/cordon"
+	"code.cloudfoundry.org/rep/crashloop"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+	"code.cloudfoundry.org/rep/featureflags"
+	"code.cloudfoundry.org/rep/hostmetrics"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/lifecycles"
+	"code.cloudfoundry.org/rep/maintenance"
+	"code.cloudfoundry.org/rep/placementpolicy"
 )
 
+// StateReporter reports the state of the cell the auctioneer scores it by.
+type StateReporter interface {
+	State(ctx context.Context, logger lager.Logger) (rep.CellState, bool, error)
+}
+
+// CapacitySummarizer summarizes the capacity of the cell for auctioneers that
+// poll it often.
+type CapacitySummarizer interface {
+	CapacitySummary(ctx context.Context, logger lager.Logger) (rep.CapacitySummary, bool, error)
+}
+
+// ContainerStateConverter converts containers listed from the primary
+// executor into the LRPs and tasks of a cell state, without asking the
+// executor for anything else.
+type ContainerStateConverter interface {
+	ContainerState(logger lager.Logger, containers []executor.Container) rep.CellState
+}
+
+// WorkPerformer allocates the work the auctioneer placed on the cell and
+// returns the work that failed to allocate.
+type WorkPerformer interface {
+	Perform(ctx context.Context, logger lager.Logger, work rep.Work) (rep.Work, error)
+}
+
+// Resetter resets a simulated cell.
+type Resetter interface {
+	Reset(ctx context.Context) error
+}
+
 //go:generate counterfeiter . AuctionCellClient
 
 type AuctionCellClient interface {
-	State(logger lager.Logger) (rep.CellState, bool, error)
-	Perform(logger lager.Logger, work rep.Work) (rep.Work, error)
-	Reset() error
+	StateReporter
+	CapacitySummarizer
+	WorkPerformer
+	Resetter
 }
 
 var ErrCellUnhealthy = errors.New("internal cell healthcheck failed")
@@ -31,16 +77,62 @@ type AuctionCellRep struct {
 	cellIndex                int
 	repURL                   string
 	stackPathMap             rep.StackPathMap
+	pullCredentials          rep.RunRequestConversionHelper
 	rootFSProviders          rep.RootFSProviders
 	containerMetricsProvider rep.ContainerMetricsProvider
 	zone                     string
+	instanceID               string
+	instanceType             string
+	osFamily                 string
+	imageOverhead            rep.Resource
+	maxContainerResource     rep.Resource
+	securityPermissions      rep.SecurityPermissions
+	hostPortPoolSize         int32
+	cpuEntitlement           float64
+	tenantCaps               *rep.TenantCaps
+	stackContainerLimits     map[string]int
+	cgroups                  *rep.CgroupInfo
+	logDrops                 LogDropCounter
+	ioLimits                 IOLimitReporter
 	client                   executor.Client
 	evacuationReporter       evacuation_context.EvacuationReporter
-	placementTags            []string
-	optionalPlacementTags    []string
+	maintenanceReporter      maintenance.MaintenanceReporter
+	cordon                   cordon.Reporter
+	contacts                 contacts.Reporter
+	cellConditions           []CellCondition
+	healthReport             *healthReport
+	placementTags            *placementTags
 	enableContainerProxy     bool
 	proxyMemoryAllocation    int
 	allocator                BatchContainerAllocator
+	additionalBackends       []Backend
+	hostPressureReader       hostmetrics.Reader
+	hostPressureWeight       float64
+	recentLRPs               *RecentLRPTracker
+	recentLRPScoreBonus      float64
+	placementHistory         *PlacementHistory
+	usageForecaster          *UsageForecaster
+	usageForecastWeight      float64
+	rootFSUsageReader        imagecache.UsageReader
+	imageSizeChecker         imagecache.SizeChecker
+	imageDigestPolicy        imagecache.DigestPolicy
+	lifecycles               lifecycles.Catalog
+	initStepPaths            []string
+	reservations             *CapacityReservations
+	maintenanceSchedule      *MaintenanceSchedule
+	failureDomains           []string
+	failureDomainPenalty     float64
+	crashLoopDetector        crashloop.Detector
+	workGroupHolds           *rep.WorkGroupHolds
+	placementPolicy          placementpolicy.Policy
+	clockSkew                *clockskew.Monitor
+	diskQuotaGrower          DiskQuotaGrower
+	diskGrowth               *DiskQuotaGrowth
+	inFlight                 *inFlightWork
+	placementBlocks          *placementBlocks
+	auctionRoutes            *auctionRoutes
+	clock                    clock.Clock
+	featureFlags             *featureflags.Flags
 }
 
 func New(
@@ -51,36 +143,129 @@ func New(
 	containerMetricsProvider rep.ContainerMetricsProvider,
 	arbitraryRootFSes []string,
 	zone string,
+	instanceID string,
+	instanceType string,
+	osFamily string,
+	imageOverhead rep.Resource,
+	maxContainerResource rep.Resource,
+	securityPermissions rep.SecurityPermissions,
+	hostPortPoolSize int32,
+	cpuEntitlement float64,
+	tenantCaps *rep.TenantCaps,
+	stackContainerLimits map[string]int,
+	cgroups *rep.CgroupInfo,
+	logDrops LogDropCounter,
+	ioLimits IOLimitReporter,
 	client executor.Client,
 	evacuationReporter evacuation_context.EvacuationReporter,
+	maintenanceReporter maintenance.MaintenanceReporter,
+	cordonReporter cordon.Reporter,
+	contactReporter contacts.Reporter,
+	cellConditions []CellCondition,
 	placementTags []string,
 	optionalPlacementTags []string,
+	isolationSegment string,
 	proxyMemoryAllocation int,
 	enableContainerProxy bool,
 	allocator BatchContainerAllocator,
+	additionalBackends []Backend,
+	hostPressureReader hostmetrics.Reader,
+	hostPressureWeight float64,
+	recentLRPs *RecentLRPTracker,
+	recentLRPScoreBonus float64,
+	placementHistory *PlacementHistory,
+	usageForecaster *UsageForecaster,
+	usageForecastWeight float64,
+	rootFSUsageReader imagecache.UsageReader,
+	imageSizeChecker imagecache.SizeChecker,
+	imageDigestPolicy imagecache.DigestPolicy,
+	lifecycles lifecycles.Catalog,
+	initStepPaths []string,
+	reservations *CapacityReservations,
+	maintenanceSchedule *MaintenanceSchedule,
+	failureDomains []string,
+	failureDomainPenalty float64,
+	crashLoopDetector crashloop.Detector,
+	workGroupHolds *rep.WorkGroupHolds,
+	placementPolicy placementpolicy.Policy,
+	clockSkew *clockskew.Monitor,
+	diskQuotaGrower DiskQuotaGrower,
+	diskGrowth *DiskQuotaGrowth,
+	clock clock.Clock,
+	featureFlags *featureflags.Flags,
 ) *AuctionCellRep {
 	return &AuctionCellRep{
 		cellID:                   cellID,
 		cellIndex:                cellIndex,
 		repURL:                   repURL,
 		stackPathMap:             preloadedStackPathMap,
-		rootFSProviders:          rootFSProviders(preloadedStackPathMap, arbitraryRootFSes),
+		pullCredentials:          rep.RunRequestConversionHelper{ECRHelper: ecrhelper.NewECRHelper()},
+		rootFSProviders:          rootFSProviders(osFamily, preloadedStackPathMap, arbitraryRootFSes),
 		containerMetricsProvider: containerMetricsProvider,
 		zone:                     zone,
+		instanceID:               instanceID,
+		instanceType:             instanceType,
+		osFamily:                 osFamily,
+		imageOverhead:            imageOverhead,
+		maxContainerResource:     maxContainerResource,
+		securityPermissions:      securityPermissions,
+		hostPortPoolSize:         hostPortPoolSize,
+		cpuEntitlement:           cpuEntitlement,
+		tenantCaps:               tenantCaps,
+		stackContainerLimits:     stackContainerLimits,
+		cgroups:                  cgroups,
+		logDrops:                 logDrops,
+		ioLimits:                 ioLimits,
 		client:                   client,
 		evacuationReporter:       evacuationReporter,
-		placementTags:            placementTags,
-		optionalPlacementTags:    optionalPlacementTags,
+		maintenanceReporter:      maintenanceReporter,
+		cordon:                   cordonReporter,
+		contacts:                 contactReporter,
+		cellConditions:           cellConditions,
+		healthReport:             &healthReport{},
+		placementTags:            newPlacementTags(rep.CellPlacementTags{PlacementTags: placementTags, OptionalPlacementTags: optionalPlacementTags, IsolationSegment: isolationSegment}),
 		enableContainerProxy:     enableContainerProxy,
 		proxyMemoryAllocation:    proxyMemoryAllocation,
 		allocator:                allocator,
+		additionalBackends:       additionalBackends,
+		hostPressureReader:       hostPressureReader,
+		hostPressureWeight:       hostPressureWeight,
+		recentLRPs:               recentLRPs,
+		recentLRPScoreBonus:      recentLRPScoreBonus,
+		placementHistory:         placementHistory,
+		usageForecaster:          usageForecaster,
+		usageForecastWeight:      usageForecastWeight,
+		rootFSUsageReader:        rootFSUsageReader,
+		imageSizeChecker:         imageSizeChecker,
+		imageDigestPolicy:        imageDigestPolicy,
+		lifecycles:               lifecycles,
+		initStepPaths:            initStepPaths,
+		reservations:             reservations,
+		maintenanceSchedule:      maintenanceSchedule,
+		failureDomains:           failureDomains,
+		failureDomainPenalty:     failureDomainPenalty,
+		crashLoopDetector:        crashLoopDetector,
+		workGroupHolds:           workGroupHolds,
+		placementPolicy:          placementPolicy,
+		clockSkew:                clockSkew,
+		diskQuotaGrower:          diskQuotaGrower,
+		diskGrowth:               diskGrowth,
+		inFlight:                 newInFlightWork(),
+		placementBlocks:          newPlacementBlocks(clock),
+		auctionRoutes:            &auctionRoutes{},
+		clock:                    clock,
+		featureFlags:             featureFlags,
 	}
 }
 
-func rootFSProviders(preloaded rep.StackPathMap, arbitrary []string) rep.RootFSProviders {
+// rootFSProviders returns the providers for the preloaded stacks and the
+// supported rootfs schemes of a cell, using the provider registered for a
+// scheme when there is one. Windows cells cannot layer droplets onto their
+// stacks, so they do not provide the preloaded+layer scheme.
+func rootFSProviders(osFamily string, preloaded rep.StackPathMap, supported []string) rep.RootFSProviders {
 	rootFSProviders := rep.RootFSProviders{}
-	for _, scheme := range arbitrary {
-		rootFSProviders[scheme] = rep.ArbitraryRootFSProvider{}
+	for _, scheme := range supported {
+		rootFSProviders[scheme] = rep.RootFSProviderForScheme(scheme)
 	}
 
 	stacks := make([]string, 0, len(preloaded))
@@ -88,7 +273,9 @@ func rootFSProviders(preloaded rep.StackPathMap, arbitrary []string) rep.RootFSP
 		stacks = append(stacks, stack)
 	}
 	rootFSProviders[models.PreloadedRootFSScheme] = rep.NewFixedSetRootFSProvider(stacks...)
-	rootFSProviders[models.PreloadedOCIRootFSScheme] = rep.NewFixedSetRootFSProvider(stacks...)
+	if osFamily != rep.OSFamilyWindows {
+		rootFSProviders[models.PreloadedOCIRootFSScheme] = rep.NewFixedSetRootFSProvider(stacks...)
+	}
 
 	return rootFSProviders
 }
@@ -109,34 +296,269 @@ func rootFSURLFromPath(rootfsPath string, stackPathMap rep.StackPathMap) string
 	return rootfsPath
 }
 
-func (a *AuctionCellRep) State(logger lager.Logger) (rep.CellState, bool, error) {
+// State stops gathering the state of the backends once ctx is done.
+func (a *AuctionCellRep) State(ctx context.Context, logger lager.Logger) (rep.CellState, bool, error) {
 	logger = logger.Session("auction-state")
 	logger.Info("providing")
 
-	containers, err := a.client.ListContainers(logger)
+	lrps := []rep.LRP{}
+	tasks := []rep.Task{}
+	startingContainerCount := 0
+	availableResources := rep.Resources{}
+	totalResources := rep.Resources{}
+	rootFSProviders := rep.RootFSProviders{}
+	backendStates := []rep.BackendState{}
+	var unhealthyReasons []string
+
+	var reservations []rep.CapacityReservation
+	if a.reservations != nil {
+		reservations = a.reservations.Active()
+	}
+
+	for _, backend := range a.backends() {
+		if err := ctx.Err(); err != nil {
+			logger.Error("state-cancelled", err)
+			return rep.CellState{}, false, err
+		}
+
+		backendLogger := logger
+		if len(a.additionalBackends) > 0 {
+			backendLogger = logger.WithData(lager.Data{"backend": backend.Name})
+		}
+
+		backendState, backendLRPs, backendTasks, backendStartingCount, err := a.backendState(backendLogger, backend)
+		if err != nil {
+			return rep.CellState{}, false, err
+		}
+		backendState.AvailableResources = withoutReserved(backendState.AvailableResources, reservationsOn(reservations, backend.Name))
+
+		lrps = append(lrps, backendLRPs...)
+		tasks = append(tasks, backendTasks...)
+		startingContainerCount += backendStartingCount
+		availableResources.Add(backendState.AvailableResources)
+		totalResources.Add(backendState.TotalResources)
+		rootFSProviders = rootFSProviders.Merge(backendState.RootFSProviders)
+		backendStates = append(backendStates, backendState)
+
+		if !backend.Client.Healthy(backendLogger) {
+			unhealthyReasons = appendReason(unhealthyReasons, rep.UnhealthyReasonGardenUnresponsive)
+		}
+	}
+
+	var conditions []string
+	for _, condition := range a.cellConditions {
+		if reported := condition.Condition(); reported != "" {
+			conditions = appendReason(conditions, reported)
+		}
+	}
+	a.healthReport.update(logger, unhealthyReasons, conditions)
+
+	volumeDrivers, err := a.client.VolumeDrivers(logger)
 	if err != nil {
-		logger.Error("failed-to-fetch-containers", err)
+		logger.Error("failed-to-get-volume-drivers", err)
 		return rep.CellState{}, false, err
 	}
 
-	totalResources, err := a.client.TotalResources(logger)
+	if a.cpuEntitlement > 0 {
+		deriveCPUEntitlements(lrps, tasks, totalResources.MemoryMB, a.cpuEntitlement)
+		totalResources.CPUEntitlement = a.cpuEntitlement
+		availableResources.CPUEntitlement = a.cpuEntitlement - allocatedCPUEntitlement(lrps, tasks)
+	}
+
+	allocatedProxyMemory := 0
+	if a.proxyOverheadEnabled() {
+		allocatedProxyMemory = a.proxyMemoryAllocation
+	}
+
+	tags := a.placementTags.get()
+	state := rep.NewCellState(
+		a.cellID,
+		a.cellIndex,
+		a.repURL,
+		rootFSProviders,
+		availableResources,
+		totalResources,
+		lrps,
+		tasks,
+		a.zone,
+		startingContainerCount,
+		a.evacuationReporter.Evacuating(),
+		volumeDrivers,
+		tags.Required(),
+		tags.OptionalPlacementTags,
+		allocatedProxyMemory,
+	)
+	state.InstanceID = a.instanceID
+	state.InstanceType = a.instanceType
+	state.OSFamily = a.osFamily
+	if a.osFamily == rep.OSFamilyWindows {
+		imageOverhead := a.imageOverhead
+		state.ImageOverhead = &imageOverhead
+	}
+	state.MaxContainerMemoryMB = a.maxContainerResource.MemoryMB
+	state.MaxContainerDiskMB = a.maxContainerResource.DiskMB
+	state.AllowedCapabilities = a.securityPermissions.Capabilities
+	state.AllowedSeccompProfiles = a.securityPermissions.SeccompProfiles
+	state.AllowedAppArmorProfiles = a.securityPermissions.AppArmorProfiles
+	if a.hostPortPoolSize > 0 {
+		state.TotalHostPorts = a.hostPortPoolSize
+		state.AvailableHostPorts = a.hostPortPoolSize - allocatedHostPorts(lrps, tasks)
+		if state.AvailableHostPorts < 0 {
+			state.AvailableHostPorts = 0
+		}
+	}
+	state.FeatureFlags = a.featureFlags.EnabledFlags()
+	state.Maintenance = a.maintenanceReporter.InMaintenance()
+	state.Cordoned = a.cordoned()
+	if a.contacts != nil {
+		lastContacts := a.contacts.LastContacts()
+		state.LastBBSContact = lastContacts.LastBBSContact
+		state.LastGardenContact = lastContacts.LastGardenContact
+	}
+	if a.ioLimits != nil {
+		state.SupportedIOLimits = a.ioLimits.SupportedIOLimits()
+	}
+	state.UnhealthyReasons = unhealthyReasons
+	state.Conditions = conditions
+	if len(a.additionalBackends) > 0 {
+		state.Backends = backendStates
+	}
+	if a.hostPressureReader != nil {
+		hostPressure, err := a.hostPressureReader.Read(logger)
+		if err == nil {
+			state.HostPressure = &hostPressure
+			state.HostPressureWeight = a.hostPressureWeight
+		}
+	}
+	if a.recentLRPs != nil {
+		a.recentLRPs.Observe(lrps)
+		state.RecentLRPs = a.recentLRPs.Recent()
+		state.RecentLRPScoreBonus = a.recentLRPScoreBonus
+	}
+	if a.placementHistory != nil {
+		a.placementHistory.SampleUtilization(state.TotalResources, state.AvailableResources)
+	}
+	if a.usageForecaster != nil {
+		if forecast, ok := a.usageForecaster.Forecast(); ok {
+			state.UsageForecast = &forecast
+			state.UsageForecastWeight = a.usageForecastWeight
+		}
+	}
+	if a.rootFSUsageReader != nil {
+		rootFSDiskUsage, err := a.rootFSUsageReader.Read(logger)
+		if err == nil {
+			state.RootFSDiskUsage = rootFSDiskUsage
+		}
+	}
+	if len(reservations) > 0 {
+		state.CapacityReservations = reservations
+	}
+	if a.maintenanceSchedule != nil {
+		if windows := a.maintenanceSchedule.Upcoming(); len(windows) > 0 {
+			state.MaintenanceWindows = windows
+			state.MaintenanceScorePenalty = a.maintenanceSchedule.ScorePenalty()
+		}
+	}
+	if len(a.failureDomains) > 0 {
+		state.FailureDomains = a.failureDomains
+		state.FailureDomainPenalty = a.failureDomainPenalty
+	}
+	if a.crashLoopDetector != nil {
+		if quarantines := a.crashLoopDetector.Quarantines(); len(quarantines) > 0 {
+			state.QuarantinedLRPs = quarantines
+		}
+	}
+	if blocks := a.placementBlocks.active(); len(blocks) > 0 {
+		state.PlacementBlocks = blocks
+	}
+	state.TenantUsage = rep.TenantUsages(state.LRPs, state.Tasks)
+	state.TenantCaps = a.tenantCaps
+	state.StackContainersLeft = rep.StackContainersLeft(a.stackContainerLimits, state.LRPs, state.Tasks)
+	state.Cgroups = a.cgroups
+	if a.lifecycles != nil {
+		state.Lifecycles = a.lifecycles.Available()
+	}
+
+	logger.Info("provided", lager.Data{
+		"available-resources": state.AvailableResources,
+		"total-resources":     state.TotalResources,
+		"num-lrps":            len(state.LRPs),
+		"zone":                state.Zone,
+		"evacuating":          state.Evacuating,
+		"maintenance":         state.Maintenance,
+	})
+
+	return state, len(unhealthyReasons) == 0, nil
+}
+
+// appendReason adds reason to reasons unless it is already there, as when
+// several backends are unresponsive.
+func appendReason(reasons []string, reason string) []string {
+	for _, existing := range reasons {
+		if existing == reason {
+			return reasons
+		}
+	}
+	return append(reasons, reason)
+}
+
+// cordoned reports whether the cell is cordoned centrally. Cells without a
+// cordon reporter are never cordoned.
+func (a *AuctionCellRep) cordoned() bool {
+	return a.cordon != nil && a.cordon.Cordoned()
+}
+
+func (a *AuctionCellRep) backends() []Backend {
+	primary := Backend{
+		Name:            DefaultBackendName,
+		Client:          a.client,
+		MetricsProvider: a.containerMetricsProvider,
+		StackPathMap:    a.stackPathMap,
+		RootFSProviders: a.rootFSProviders,
+		Allocator:       a.allocator,
+	}
+	return append([]Backend{primary}, a.additionalBackends...)
+}
+
+func (a *AuctionCellRep) backendState(logger lager.Logger, backend Backend) (rep.BackendState, []rep.LRP, []rep.Task, int, error) {
+	containers, err := backend.Client.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-fetch-containers", err)
+		return rep.BackendState{}, nil, nil, 0, err
+	}
+
+	totalResources, err := backend.Client.TotalResources(logger)
 	if err != nil {
 		logger.Error("failed-to-get-total-resources", err)
-		return rep.CellState{}, false, err
+		return rep.BackendState{}, nil, nil, 0, err
 	}
 
-	availableResources, err := a.client.RemainingResources(logger)
+	availableResources, err := backend.Client.RemainingResources(logger)
 	if err != nil {
 		logger.Error("failed-to-get-remaining-resource", err)
-		return rep.CellState{}, false, err
+		return rep.BackendState{}, nil, nil, 0, err
 	}
 
-	volumeDrivers, err := a.client.VolumeDrivers(logger)
-	if err != nil {
-		logger.Error("failed-to-get-volume-drivers", err)
-		return rep.CellState{}, false, err
+	if backend.Name == DefaultBackendName {
+		availableResources.DiskMB -= int(a.diskGrowth.apply(logger, containers))
 	}
 
+	lrps, tasks, startingContainerCount := a.convertContainers(logger, containers, backend.StackPathMap)
+
+	return rep.BackendState{
+		Name:               backend.Name,
+		RootFSProviders:    backend.RootFSProviders,
+		AvailableResources: a.convertResources(availableResources),
+		TotalResources:     a.convertResources(totalResources),
+	}, lrps, tasks, startingContainerCount, nil
+}
+
+func (a *AuctionCellRep) ContainerState(logger lager.Logger, containers []executor.Container) rep.CellState {
+	lrps, tasks, _ := a.convertContainers(logger, containers, a.stackPathMap)
+	return rep.CellState{CellID: a.cellID, LRPs: lrps, Tasks: tasks}
+}
+
+func (a *AuctionCellRep) convertContainers(logger lager.Logger, containers []executor.Container, stackPathMap rep.StackPathMap) ([]rep.LRP, []rep.Task, int) {
 	lrps := []rep.LRP{}
 	tasks := []rep.Task{}
 	startingContainerCount := 0
@@ -167,9 +589,31 @@ func (a *AuctionCellRep) State(logger lager.Logger) (rep.CellState, bool, error)
 			logger.Error("cannot-unmarshal-volume-drivers", err, lager.Data{"volume-drivers": volumeDriversJSON})
 		}
 
-		resource := rep.Resource{MemoryMB: int32(container.MemoryMB), DiskMB: int32(container.DiskMB), MaxPids: int32(container.MaxPids)}
+		resource := rep.Resource{MemoryMB: int32(container.MemoryMB), DiskMB: int32(container.DiskMB), MaxPids: int32(container.MaxPids), HostPorts: hostPorts(container.Ports)}
+		resource.StaticHostPorts, err = rep.HostPortsOfContainer(*container)
+		if err != nil {
+			logger.Error("cannot-unmarshal-static-host-ports", err, lager.Data{"static-host-ports": container.Tags[rep.StaticHostPortsTag]})
+		}
+		if cpuEntitlement, ok := container.Tags[rep.CPUEntitlementTag]; ok {
+			resource.CPUEntitlement, err = strconv.ParseFloat(cpuEntitlement, 64)
+			if err != nil {
+				logger.Error("cannot-parse-cpu-entitlement", err, lager.Data{"cpu-entitlement": cpuEntitlement})
+			}
+		}
+		resource.IOLimits, err = rep.IOLimitsFromTags(container.Tags)
+		if err != nil {
+			logger.Error("cannot-unmarshal-io-limits", err, lager.Data{"io-limits": container.Tags[rep.IOLimitsTag]})
+		}
+		resource.Proportional, err = rep.ProportionalResourceFromTags(container.Tags)
+		if err != nil {
+			logger.Error("cannot-unmarshal-proportional-resource", err, lager.Data{"proportional-resource": container.Tags[rep.ProportionalResourceTag]})
+		}
+		resource.Security, err = rep.SecurityFromTags(container.Tags)
+		if err != nil {
+			logger.Error("cannot-unmarshal-security", err, lager.Data{"security": container.Tags[rep.SecurityTag]})
+		}
 		placementConstraint := rep.PlacementConstraint{
-			RootFs:        rootFSURLFromPath(container.RootFSPath, a.stackPathMap),
+			RootFs:        rootFSURLFromPath(container.RootFSPath, stackPathMap),
 			VolumeDrivers: volumeDrivers,
 			PlacementTags: placementTags,
 		}
@@ -200,6 +644,14 @@ func (a *AuctionCellRep) State(logger lager.Logger) (rep.CellState, bool, error)
 			}
 			lrp := rep.NewLRP(instanceKey.InstanceGuid, *key, resource, placementConstraint)
 			lrp.State = state
+			lrp.Network = rep.ContainerNetworkFromContainer(*container)
+			lrp.Labels = rep.LabelsFromTags(container.Tags)
+			lrp.InitSteps, err = rep.InitStepsFromTags(container.Tags)
+			if err != nil {
+				logger.Error("cannot-unmarshal-init-steps", err, lager.Data{"init-steps": container.Tags[rep.InitStepsTag]})
+			}
+			lrp.InitStepStatuses = rep.InitStepStatuses(container, lrp.InitSteps)
+			lrp.Provenance = rep.ProvenanceFromTags(container.Tags)
 			lrps = append(lrps, lrp)
 		case rep.TaskLifecycle:
 			domain := container.Tags[rep.DomainTag]
@@ -210,47 +662,73 @@ func (a *AuctionCellRep) State(logger lager.Logger) (rep.CellState, bool, error)
 			task := rep.NewTask(container.Guid, domain, resource, placementConstraint)
 			task.State = state
 			task.Failed = container.RunResult.Failed
+			task.Network = rep.ContainerNetworkFromContainer(*container)
+			task.Labels = rep.LabelsFromTags(container.Tags)
+			task.Provenance = rep.ProvenanceFromTags(container.Tags)
 			tasks = append(tasks, task)
 		}
 	}
 
-	allocatedProxyMemory := 0
-	if a.enableContainerProxy {
-		allocatedProxyMemory = a.proxyMemoryAllocation
+	return lrps, tasks, startingContainerCount
+}
+
+// hostPorts counts the host ports the port mappings of a container take from
+// the pool, including those of its TLS proxy. Containers that are not created
+// yet have no host ports assigned but will take them once they are.
+func hostPorts(ports []executor.PortMapping) int32 {
+	count := int32(0)
+	for _, port := range ports {
+		if port.ContainerPort != 0 {
+			count++
+		}
+		if port.ContainerTLSProxyPort != 0 {
+			count++
+		}
 	}
+	return count
+}
 
-	state := rep.NewCellState(
-		a.cellID,
-		a.cellIndex,
-		a.repURL,
-		a.rootFSProviders,
-		a.convertResources(availableResources),
-		a.convertResources(totalResources),
-		lrps,
-		tasks,
-		a.zone,
-		startingContainerCount,
-		a.evacuationReporter.Evacuating(),
-		volumeDrivers,
-		a.placementTags,
-		a.optionalPlacementTags,
-		allocatedProxyMemory,
-	)
+func allocatedHostPorts(lrps []rep.LRP, tasks []rep.Task) int32 {
+	allocated := int32(0)
+	for i := range lrps {
+		allocated += lrps[i].HostPorts
+	}
+	for i := range tasks {
+		allocated += tasks[i].HostPorts
+	}
+	return allocated
+}
 
-	healthy := a.client.Healthy(logger)
-	if !healthy {
-		logger.Error("failed-garden-health-check", nil)
+// deriveCPUEntitlements gives the containers created without an explicit
+// CPU entitlement the share of the CPUs of the cell their memory is of its
+// memory, which is how their CPU shares are derived.
+func deriveCPUEntitlements(lrps []rep.LRP, tasks []rep.Task, totalMemoryMB int32, cpuEntitlement float64) {
+	if totalMemoryMB <= 0 {
+		return
 	}
 
-	logger.Info("provided", lager.Data{
-		"available-resources": state.AvailableResources,
-		"total-resources":     state.TotalResources,
-		"num-lrps":            len(state.LRPs),
-		"zone":                state.Zone,
-		"evacuating":          state.Evacuating,
-	})
+	derive := func(resource *rep.Resource) {
+		if resource.CPUEntitlement == 0 {
+			resource.CPUEntitlement = float64(resource.MemoryMB) / float64(totalMemoryMB) * cpuEntitlement
+		}
+	}
+	for i := range lrps {
+		derive(&lrps[i].Resource)
+	}
+	for i := range tasks {
+		derive(&tasks[i].Resource)
+	}
+}
 
-	return state, healthy, nil
+func allocatedCPUEntitlement(lrps []rep.LRP, tasks []rep.Task) float64 {
+	allocated := 0.0
+	for i := range lrps {
+		allocated += lrps[i].CPUEntitlement
+	}
+	for i := range tasks {
+		allocated += tasks[i].CPUEntitlement
+	}
+	return allocated
 }
 
 func (a *AuctionCellRep) Metrics(logger lager.Logger) (*rep.ContainerMetricsCollection, error) {
@@ -261,13 +739,17 @@ func (a *AuctionCellRep) Metrics(logger lager.Logger) (*rep.ContainerMetricsColl
 	logger.Info("starting")
 	defer logger.Info("complete")
 
-	containers, err := a.client.ListContainers(logger)
-	if err != nil {
-		logger.Error("failed-to-fetch-containers", err)
-		return nil, err
+	containers := []executor.Container{}
+	for _, backend := range a.backends() {
+		backendContainers, err := backend.Client.ListContainers(logger)
+		if err != nil {
+			logger.Error("failed-to-fetch-containers", err, lager.Data{"backend": backend.Name})
+			return nil, err
+		}
+		containers = append(containers, backendContainers...)
 	}
 
-	metrics := a.containerMetricsProvider.Metrics()
+	metrics := a.containerMetrics()
 
 	for _, container := range containers {
 		if container.Tags == nil {
@@ -300,12 +782,18 @@ func (a *AuctionCellRep) Metrics(logger lager.Logger) (*rep.ContainerMetricsColl
 				Index:                  key.Index,
 				InstanceGUID:           instanceKey.InstanceGuid,
 				CachedContainerMetrics: *containerMetrics,
+				Network:                rep.ContainerNetworkFromContainer(container),
+				LogBytesDropped:        a.droppedLogBytes(&container),
+				IOLimits:               a.appliedIOLimits(guid),
 			}
 			lrpMetrics = append(lrpMetrics, lrpMetric)
 		case rep.TaskLifecycle:
 			taskMetric := rep.TaskMetric{
 				TaskGUID:               container.Guid,
 				CachedContainerMetrics: *containerMetrics,
+				Network:                rep.ContainerNetworkFromContainer(container),
+				LogBytesDropped:        a.droppedLogBytes(&container),
+				IOLimits:               a.appliedIOLimits(guid),
 			}
 			taskMetrics = append(taskMetrics, taskMetric)
 		}
@@ -318,13 +806,59 @@ func (a *AuctionCellRep) Metrics(logger lager.Logger) (*rep.ContainerMetricsColl
 	}, nil
 }
 
+func (a *AuctionCellRep) droppedLogBytes(container *executor.Container) int64 {
+	if a.logDrops == nil {
+		return 0
+	}
+	return a.logDrops.DroppedBytes(container.LogConfig.Guid, container.LogConfig.Index)
+}
+
+func (a *AuctionCellRep) appliedIOLimits(guid string) *rep.IOLimits {
+	if a.ioLimits == nil {
+		return nil
+	}
+	limits, ok := a.ioLimits.AppliedIOLimits(guid)
+	if !ok {
+		return nil
+	}
+	return &limits
+}
+
+// MetricsBatch returns the selected metrics of every container the executor
+// has metrics for. It does not list the containers, which makes it cheaper
+// than Metrics on cells with many containers.
+func (a *AuctionCellRep) MetricsBatch(logger lager.Logger, fields rep.ContainerMetricsFields) *rep.ContainerMetricsBatch {
+	batch := rep.NewContainerMetricsBatch(a.cellID, a.containerMetrics(), fields)
+	return &batch
+}
+
+// containerMetrics returns the metrics of the containers of every backend.
+func (a *AuctionCellRep) containerMetrics() map[string]*containermetrics.CachedContainerMetrics {
+	if len(a.additionalBackends) == 0 {
+		return a.containerMetricsProvider.Metrics()
+	}
+
+	metrics := map[string]*containermetrics.CachedContainerMetrics{}
+	for _, backend := range a.backends() {
+		if backend.MetricsProvider == nil {
+			continue
+		}
+		for guid, containerMetrics := range backend.MetricsProvider.Metrics() {
+			metrics[guid] = containerMetrics
+		}
+	}
+	return metrics
+}
+
 func containerIsStarting(container *executor.Container) bool {
 	return container.State == executor.StateReserved ||
 		container.State == executor.StateInitializing ||
 		container.State == executor.StateCreated
 }
 
-func (a *AuctionCellRep) Perform(logger lager.Logger, work rep.Work) (rep.Work, error) {
+// Perform does not allocate any of the work once ctx is done, as whoever
+// placed it no longer learns which of it failed.
+func (a *AuctionCellRep) Perform(ctx context.Context, logger lager.Logger, work rep.Work) (rep.Work, error) {
 	var failedWork = rep.Work{}
 
 	logger = logger.Session("auction-work", lager.Data{
@@ -338,42 +872,753 @@ func (a *AuctionCellRep) Perform(logger lager.Logger, work rep.Work) (rep.Work,
 		return work, ErrCellIdMismatch
 	}
 
-	remainingResources, err := a.client.RemainingResources(logger)
-	if err != nil {
-		logger.Error("failed-gathering-remaining-reosurces", err)
-		return work, err
+	// work the cell turns down as a whole is returned as it was requested
+	requested := work
+	rejected := newRejectionReasons()
+	work = a.inFlight.claim(logger, work, &failedWork)
+	defer a.inFlight.release(work)
+	rejected.mark(&failedWork, rep.PlacementReasonDuplicate)
+	work = a.rejectBlockedWork(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonBlocked)
+	work = a.rejectInvalidRegistries(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonInvalidRegistry)
+	work = a.rejectUndigestedImages(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonImageDigestRequired)
+	work = a.rejectOversizedImages(ctx, logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonImageTooLarge)
+	work = a.rejectMissingLifecycles(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonMissingLifecycle)
+	work = a.rejectInvalidInitSteps(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonInvalidInitSteps)
+	work = rejectInvalidProportions(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonInvalidProportions)
+	work = a.rejectUnsupportedIOLimits(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonUnsupportedIOLimits)
+	work = a.rejectUnpermittedSecurity(logger, work, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonSecurityNotPermitted)
+	work, heldHostPorts := a.inFlight.holdHostPorts(logger, work, &failedWork)
+	defer a.inFlight.releaseHostPorts(heldHostPorts)
+	rejected.mark(&failedWork, rep.PlacementReasonHostPortConflict)
+	// the checks of the work against the cell share one state of it, taken
+	// once the host ports of the work are held
+	cellState := a.stateOnce(ctx, logger)
+	work = rejectHostPortConflicts(logger, work, cellState, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonHostPortConflict)
+	work = a.checkDirectedPlacements(logger, work, cellState, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonDirected)
+	work = a.rejectPolicyDeniedWork(ctx, logger, work, cellState, &failedWork)
+	rejected.mark(&failedWork, rep.PlacementReasonPolicyDenied)
+	work = withTraceContext(ctx, work)
+
+	backends := a.backends()
+	partitions := partitionWork(backends, work)
+	lrpRequests := make([][]rep.LRP, len(backends))
+
+	var reservations []rep.CapacityReservation
+	if a.reservations != nil {
+		reservations = a.reservations.Active()
+	}
+	// the places taken from reservations are settled once the work is
+	// allocated, and returned when it is not
+	claims := map[string]string{}
+	var allocated []rep.LRP
+	if a.reservations != nil {
+		defer func() { settleReservations(a.reservations, claims, allocated) }()
 	}
 
-	var lrpRequests []rep.LRP
-	remainingMemory := int32(remainingResources.MemoryMB)
+	for i, backend := range backends {
+		if i > 0 && len(partitions[i].LRPs) == 0 && len(partitions[i].Tasks) == 0 {
+			continue
+		}
 
-	sort.SliceStable(work.LRPs, func(i, j int) bool {
-		return work.LRPs[i].MemoryMB > work.LRPs[j].MemoryMB
-	})
+		remainingResources, err := backend.Client.RemainingResources(logger)
+		if err != nil {
+			logger.Error("failed-gathering-remaining-reosurces", err, lager.Data{"backend": backend.Name})
+			return requested, err
+		}
 
-	for _, lrp := range work.LRPs {
-		requiredMemory := lrp.MemoryMB
-		if a.enableContainerProxy {
-			requiredMemory += int32(a.proxyMemoryAllocation)
+		if requestsProportions(partitions[i]) {
+			totalResources, err := backend.Client.TotalResources(logger)
+			if err != nil {
+				logger.Error("failed-resolving-proportional-resources", err, lager.Data{"backend": backend.Name})
+				return requested, err
+			}
+			partitions[i] = resolveProportions(partitions[i], a.convertResources(totalResources))
+		}
+
+		// the work may not use the capacity the reservations of the backend
+		// hold, in any dimension, unless it is held for it
+		backendReservations := reservationsOn(reservations, backend.Name)
+		remaining := withoutReserved(a.convertResources(remainingResources), backendReservations)
+		reserved := len(backendReservations) > 0
+
+		sort.SliceStable(partitions[i].LRPs, func(j, k int) bool {
+			return partitions[i].LRPs[j].MemoryMB > partitions[i].LRPs[k].MemoryMB
+		})
+
+		for _, lrp := range partitions[i].LRPs {
+			if a.crashLoopDetector != nil && a.crashLoopDetector.Quarantined(lrp.ProcessGuid, lrp.Index) {
+				logger.Info("rejecting-quarantined-lrp", lager.Data{"process-guid": lrp.ProcessGuid, "index": lrp.Index})
+				failedWork.LRPs = append(failedWork.LRPs, lrp)
+				rejected.mark(&failedWork, rep.PlacementReasonQuarantined)
+				continue
+			}
+
+			requiredMemory := lrp.MemoryMB
+			if i == 0 && a.osFamily == rep.OSFamilyWindows {
+				requiredMemory += a.imageOverhead.MemoryMB
+			}
+			if a.proxyOverheadEnabled() {
+				requiredMemory += int32(a.proxyMemoryAllocation)
+			}
+			// an instance held by a reservation takes its place in it, which
+			// no concurrent perform can take in the meantime
+			available := remaining
+			var reservation rep.CapacityReservation
+			held := false
+			if reserved {
+				reservation, held = a.reservations.Take(backend.Name, lrp.ProcessGuid, &lrp.Resource)
+			}
+			if held {
+				available.Add(oneReservedInstance(&reservation))
+			}
+			required := rep.Resources{MemoryMB: requiredMemory, DiskMB: lrp.DiskMB, Containers: 1}
+			if fitsRemaining(required, available, reserved) {
+				if held {
+					claims[lrp.Identifier()] = reservation.ID
+				}
+				remaining = available
+				remaining.Add(negated(required))
+				lrpRequests[i] = append(lrpRequests[i], lrp)
+			} else {
+				if held {
+					a.reservations.Return(reservation.ID)
+				}
+				failedWork.LRPs = append(failedWork.LRPs, lrp)
+				rejected.mark(&failedWork, rep.PlacementReasonInsufficientResources)
+			}
 		}
-		if requiredMemory <= remainingMemory {
-			remainingMemory -= requiredMemory
-			lrpRequests = append(lrpRequests, lrp)
-		} else {
-			failedWork.LRPs = append(failedWork.LRPs, lrp)
+
+		// tasks cannot be held by reservations, so they only fit in what the
+		// reservations leave of the backend
+		if reserved {
+			var tasks []rep.Task
+			for _, task := range partitions[i].Tasks {
+				required := rep.Resources{MemoryMB: task.MemoryMB, DiskMB: task.DiskMB, Containers: 1}
+				if !fitsRemaining(required, remaining, reserved) {
+					failedWork.Tasks = append(failedWork.Tasks, task)
+					rejected.mark(&failedWork, rep.PlacementReasonInsufficientResources)
+					continue
+				}
+				remaining.Add(negated(required))
+				tasks = append(tasks, task)
+			}
+			partitions[i].Tasks = tasks
+		}
+	}
+
+	// no member of a group is allocated once another one is turned down
+	if groups := workGroups(failedWork); len(groups) > 0 {
+		for i := range backends {
+			pending := rejectIncompleteGroups(logger, rep.Work{LRPs: lrpRequests[i], Tasks: partitions[i].Tasks}, groups, &failedWork)
+			lrpRequests[i], partitions[i].Tasks = pending.LRPs, pending.Tasks
 		}
 	}
+	rejected.mark(&failedWork, rep.PlacementReasonWorkGroupIncomplete)
 
 	if a.evacuationReporter.Evacuating() {
-		return work, nil
+		a.recordPlacements(requested, rejected, rep.PlacementReasonEvacuating)
+		return requested, nil
 	}
 
-	failedWork.LRPs = append(failedWork.LRPs, a.allocator.BatchLRPAllocationRequest(logger, a.enableContainerProxy, a.proxyMemoryAllocation, lrpRequests)...)
-	failedWork.Tasks = a.allocator.BatchTaskAllocationRequest(logger, work.Tasks)
+	if a.maintenanceReporter.InMaintenance() {
+		logger.Info("rejecting-work-in-maintenance")
+		a.recordPlacements(requested, rejected, rep.PlacementReasonInMaintenance)
+		return requested, nil
+	}
+
+	if a.cordoned() {
+		logger.Info("rejecting-work-cordoned")
+		a.recordPlacements(requested, rejected, rep.PlacementReasonCordoned)
+		return requested, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		logger.Error("perform-cancelled", err)
+		return requested, err
+	}
+
+	// the claims of the containers of a group are held until every member of
+	// the group is allocated, so that the containers of an incomplete group
+	// are released before any of them is claimed
+	if a.workGroupHolds != nil {
+		allocating := rep.NewStringSet()
+		for i := range backends {
+			for group := range workGroups(rep.Work{LRPs: lrpRequests[i], Tasks: partitions[i].Tasks}) {
+				allocating[group] = struct{}{}
+			}
+		}
+		for group := range allocating {
+			a.workGroupHolds.Hold(group)
+			defer a.workGroupHolds.Release(group)
+		}
+	}
+
+	allocationFailed := make([]rep.Work, len(backends))
+	containerGuids := make([]map[string]string, len(backends))
+	for i, backend := range backends {
+		if i > 0 && len(partitions[i].LRPs) == 0 && len(partitions[i].Tasks) == 0 {
+			continue
+		}
+
+		allocationFailed[i].LRPs, containerGuids[i] = backend.Allocator.BatchLRPAllocationRequest(logger, a.proxyOverheadEnabled(), a.proxyMemoryAllocation, lrpRequests[i])
+		allocationFailed[i].Tasks = backend.Allocator.BatchTaskAllocationRequest(logger, partitions[i].Tasks)
+		failedWork.LRPs = append(failedWork.LRPs, allocationFailed[i].LRPs...)
+		failedWork.Tasks = append(failedWork.Tasks, allocationFailed[i].Tasks...)
+	}
+	rejected.mark(&failedWork, rep.PlacementReasonAllocationFailed)
+
+	// the members of a group already allocated when another one fails to be
+	// are released, so that the group is placed on another cell as a whole
+	groups := rep.NewStringSet()
+	for i := range allocationFailed {
+		for group := range workGroups(allocationFailed[i]) {
+			groups[group] = struct{}{}
+		}
+	}
+	if len(groups) > 0 {
+		for i, backend := range backends {
+			releaseIncompleteGroups(logger, backend, rep.Work{LRPs: lrpRequests[i], Tasks: partitions[i].Tasks}, allocationFailed[i], containerGuids[i], groups, &failedWork)
+		}
+	}
+	rejected.mark(&failedWork, rep.PlacementReasonWorkGroupIncomplete)
+	allocated = allocatedLRPs(lrpRequests, failedWork)
+	a.recordPlacements(requested, rejected, "")
 
 	return failedWork, nil
 }
 
+// rejectInvalidRegistries moves the LRPs and tasks of work whose registry
+// credentials fail validation, once converted as they are for the pull, into
+// failed, so that they fail at auction rather than when the container pulls
+// its rootfs. The failed work is returned without the registry passwords.
+func (a *AuctionCellRep) rejectInvalidRegistries(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	now := a.clock.Now()
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if err := a.validateRegistry(lrp.RootFs, lrp.Registry, now); err != nil {
+			logger.Info("rejecting-lrp-with-invalid-registry", lager.Data{"instance-guid": lrp.InstanceGUID, "reason": err.Reason})
+			lrp.Registry = lrp.Registry.Redacted()
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.RegistryValidationFailures = append(failed.RegistryValidationFailures, rep.RegistryValidationFailure{InstanceGUID: lrp.InstanceGUID, Error: *err})
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		if err := a.validateRegistry(task.RootFs, task.Registry, now); err != nil {
+			logger.Info("rejecting-task-with-invalid-registry", lager.Data{"task-guid": task.TaskGuid, "reason": err.Reason})
+			task.Registry = task.Registry.Redacted()
+			failed.Tasks = append(failed.Tasks, task)
+			failed.RegistryValidationFailures = append(failed.RegistryValidationFailures, rep.RegistryValidationFailure{TaskGuid: task.TaskGuid, Error: *err})
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// rejectUndigestedImages moves the LRPs and tasks of work whose docker image
+// is not referenced by digest into failed, when the cell requires digests.
+func (a *AuctionCellRep) rejectUndigestedImages(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	if a.imageDigestPolicy == nil {
+		return work
+	}
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if err := a.imageDigestPolicy.Check(logger, lrp.RootFs); err != nil {
+			logger.Info("rejecting-lrp-with-undigested-image", lager.Data{"instance-guid": lrp.InstanceGUID, "rootfs": lrp.RootFs})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.ImageDigestFailures = append(failed.ImageDigestFailures, rep.ImageDigestFailure{InstanceGUID: lrp.InstanceGUID, Error: err.Error()})
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		if err := a.imageDigestPolicy.Check(logger, task.RootFs); err != nil {
+			logger.Info("rejecting-task-with-undigested-image", lager.Data{"task-guid": task.TaskGuid, "rootfs": task.RootFs})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.ImageDigestFailures = append(failed.ImageDigestFailures, rep.ImageDigestFailure{TaskGuid: task.TaskGuid, Error: err.Error()})
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// rejectOversizedImages moves the LRPs and tasks of work whose rootfs image
+// cannot fit in the image store of the cell, with the images of the work
+// before them, into failed, so that they fail at auction rather than after
+// pulling part of the image. Work whose image fails to be checked is
+// performed as if it fits.
+func (a *AuctionCellRep) rejectOversizedImages(ctx context.Context, logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	if a.imageSizeChecker == nil || len(work.LRPs)+len(work.Tasks) == 0 {
+		return work
+	}
+
+	images := make([]imagecache.ImageCheck, 0, len(work.LRPs)+len(work.Tasks))
+	for _, lrp := range work.LRPs {
+		images = append(images, imagecache.ImageCheck{RootFS: lrp.RootFs, Registry: lrp.Registry})
+	}
+	for _, task := range work.Tasks {
+		images = append(images, imagecache.ImageCheck{RootFS: task.RootFs, Registry: task.Registry})
+	}
+	results := a.imageSizeChecker.Check(ctx, logger, images)
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for i, lrp := range work.LRPs {
+		if tooLarge := results[i]; tooLarge != nil {
+			logger.Info("rejecting-lrp-with-oversized-image", lager.Data{"instance-guid": lrp.InstanceGUID, "required-mb": tooLarge.RequiredMB})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.ImageSizeFailures = append(failed.ImageSizeFailures, rep.ImageSizeFailure{InstanceGUID: lrp.InstanceGUID, Error: *tooLarge})
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for i, task := range work.Tasks {
+		if tooLarge := results[len(work.LRPs)+i]; tooLarge != nil {
+			logger.Info("rejecting-task-with-oversized-image", lager.Data{"task-guid": task.TaskGuid, "required-mb": tooLarge.RequiredMB})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.ImageSizeFailures = append(failed.ImageSizeFailures, rep.ImageSizeFailure{TaskGuid: task.TaskGuid, Error: *tooLarge})
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// withTraceContext records the trace context of ctx on the work that does
+// not carry one of its own, and its trace id on the provenance of the work.
+func withTraceContext(ctx context.Context, work rep.Work) rep.Work {
+	trace := rep.TraceContextFromContext(ctx)
+	if trace == nil {
+		return work
+	}
+
+	traced := work
+	traced.LRPs = make([]rep.LRP, len(work.LRPs))
+	for i := range work.LRPs {
+		traced.LRPs[i] = work.LRPs[i]
+		if traced.LRPs[i].TraceContext == nil {
+			traced.LRPs[i].TraceContext = trace
+		}
+		traced.LRPs[i].Provenance = traced.LRPs[i].Provenance.WithTraceID(traced.LRPs[i].TraceContext)
+	}
+	traced.Tasks = make([]rep.Task, len(work.Tasks))
+	for i := range work.Tasks {
+		traced.Tasks[i] = work.Tasks[i]
+		if traced.Tasks[i].TraceContext == nil {
+			traced.Tasks[i].TraceContext = trace
+		}
+		traced.Tasks[i].Provenance = traced.Tasks[i].Provenance.WithTraceID(traced.Tasks[i].TraceContext)
+	}
+	return traced
+}
+
+// stateOnce returns a function returning a copy of the state of the cell,
+// which is only computed on its first call.
+func (a *AuctionCellRep) stateOnce(ctx context.Context, logger lager.Logger) func() (rep.CellState, error) {
+	var (
+		computed bool
+		state    rep.CellState
+		err      error
+	)
+	return func() (rep.CellState, error) {
+		if !computed {
+			state, _, err = a.State(ctx, logger)
+			computed = true
+		}
+		return state.Copy(), err
+	}
+}
+
+// checkDirectedPlacements fails the directed LRPs and tasks of work that are
+// directed to another cell, or that do not fit on the cell as the auction
+// would have found had it scored the cells for them. Every directed
+// placement the cell performs is logged, so that it can be audited.
+func (a *AuctionCellRep) checkDirectedPlacements(logger lager.Logger, work rep.Work, cellState func() (rep.CellState, error), failed *rep.Work) rep.Work {
+	directed := false
+	for i := range work.LRPs {
+		directed = directed || work.LRPs[i].Directed != nil
+	}
+	for i := range work.Tasks {
+		directed = directed || work.Tasks[i].Directed != nil
+	}
+	if !directed {
+		return work
+	}
+
+	state, stateErr := cellState()
+
+	match := func(directed *rep.DirectedPlacement, resourceMatch func() error) error {
+		if err := directed.Validate(a.cellID); err != nil {
+			return err
+		}
+		if stateErr != nil {
+			return stateErr
+		}
+		return resourceMatch()
+	}
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		lrp := lrp
+		if lrp.Directed == nil {
+			valid.LRPs = append(valid.LRPs, lrp)
+			continue
+		}
+		data := lager.Data{"instance-guid": lrp.InstanceGUID, "requested-by": lrp.Directed.RequestedBy, "reason": lrp.Directed.Reason}
+		if err := match(lrp.Directed, func() error { return state.LRPResourceMatch(&lrp) }); err != nil {
+			logger.Info("rejecting-directed-lrp", data, lager.Data{"error": err.Error()})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.DirectedPlacementFailures = append(failed.DirectedPlacementFailures, rep.DirectedPlacementFailure{InstanceGUID: lrp.InstanceGUID, Error: err.Error()})
+			continue
+		}
+		logger.Info("accepting-directed-lrp", data)
+		state.AddLRP(&lrp)
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		task := task
+		if task.Directed == nil {
+			valid.Tasks = append(valid.Tasks, task)
+			continue
+		}
+		data := lager.Data{"task-guid": task.TaskGuid, "requested-by": task.Directed.RequestedBy, "reason": task.Directed.Reason}
+		if err := match(task.Directed, func() error { return state.TaskResourceMatch(&task) }); err != nil {
+			logger.Info("rejecting-directed-task", data, lager.Data{"error": err.Error()})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.DirectedPlacementFailures = append(failed.DirectedPlacementFailures, rep.DirectedPlacementFailure{TaskGuid: task.TaskGuid, Error: err.Error()})
+			continue
+		}
+		logger.Info("accepting-directed-task", data)
+		state.AddTask(&task)
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// rejectHostPortConflicts moves the LRPs and tasks of work requesting static
+// host ports that containers on the cell, or work placed before them in the
+// same batch, already hold into failed, rather than failing them once their
+// containers are created.
+func rejectHostPortConflicts(logger lager.Logger, work rep.Work, cellState func() (rep.CellState, error), failed *rep.Work) rep.Work {
+	requested := false
+	for i := range work.LRPs {
+		requested = requested || len(work.LRPs[i].StaticHostPorts) > 0
+	}
+	for i := range work.Tasks {
+		requested = requested || len(work.Tasks[i].StaticHostPorts) > 0
+	}
+	if !requested {
+		return work
+	}
+
+	state, err := cellState()
+	if err != nil {
+		// without the state the conflicts are found when the containers are
+		// created instead
+		logger.Error("failed-to-check-host-ports", err)
+		return work
+	}
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		lrp := lrp
+		if err := state.HostPortsMatch(&lrp.Resource); err != nil {
+			logger.Info("rejecting-lrp-with-host-port-conflict", lager.Data{"instance-guid": lrp.InstanceGUID, "error": err.Error()})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.HostPortConflictFailures = append(failed.HostPortConflictFailures, rep.HostPortConflictFailure{InstanceGUID: lrp.InstanceGUID, Error: err.(rep.HostPortConflictError)})
+			continue
+		}
+		state.LRPs = append(state.LRPs, lrp)
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		task := task
+		if err := state.HostPortsMatch(&task.Resource); err != nil {
+			logger.Info("rejecting-task-with-host-port-conflict", lager.Data{"task-guid": task.TaskGuid, "error": err.Error()})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.HostPortConflictFailures = append(failed.HostPortConflictFailures, rep.HostPortConflictFailure{TaskGuid: task.TaskGuid, Error: err.(rep.HostPortConflictError)})
+			continue
+		}
+		state.Tasks = append(state.Tasks, task)
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// rejectPolicyDeniedWork moves the LRPs and tasks of work the placement
+// policy does not admit into failed. Each is evaluated against the state of
+// the cell with the work admitted before it, and is rejected when the state or
+// the policy cannot be evaluated.
+func (a *AuctionCellRep) rejectPolicyDeniedWork(ctx context.Context, logger lager.Logger, work rep.Work, cellState func() (rep.CellState, error), failed *rep.Work) rep.Work {
+	if a.placementPolicy == nil || len(work.LRPs)+len(work.Tasks) == 0 {
+		return work
+	}
+
+	state, stateErr := cellState()
+
+	admit := func(input placementpolicy.Input) error {
+		if stateErr != nil {
+			return stateErr
+		}
+		input.Cell = state
+		return a.placementPolicy.Admit(ctx, logger, input)
+	}
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		lrp := lrp
+		if err := admit(placementpolicy.Input{LRP: &lrp}); err != nil {
+			logger.Info("rejecting-policy-denied-lrp", lager.Data{"instance-guid": lrp.InstanceGUID, "error": err.Error()})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.PolicyFailures = append(failed.PolicyFailures, rep.PolicyFailure{InstanceGUID: lrp.InstanceGUID, Error: err.Error()})
+			continue
+		}
+		state.AddLRP(&lrp)
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		task := task
+		if err := admit(placementpolicy.Input{Task: &task}); err != nil {
+			logger.Info("rejecting-policy-denied-task", lager.Data{"task-guid": task.TaskGuid, "error": err.Error()})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.PolicyFailures = append(failed.PolicyFailures, rep.PolicyFailure{TaskGuid: task.TaskGuid, Error: err.Error()})
+			continue
+		}
+		state.AddTask(&task)
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// rejectMissingLifecycles moves the LRPs and tasks of work requiring a
+// lifecycle the cell does not have into failed.
+func (a *AuctionCellRep) rejectMissingLifecycles(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	if a.lifecycles == nil {
+		return work
+	}
+	available := a.lifecycles.Available()
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if missing := rep.MissingLifecycle(available, lrp.RequiredLifecycles); missing != nil {
+			logger.Info("rejecting-lrp-with-missing-lifecycle", lager.Data{"instance-guid": lrp.InstanceGUID, "lifecycle": missing.Lifecycle})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.LifecycleFailures = append(failed.LifecycleFailures, rep.LifecycleFailure{InstanceGUID: lrp.InstanceGUID, Error: *missing})
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		if missing := rep.MissingLifecycle(available, task.RequiredLifecycles); missing != nil {
+			logger.Info("rejecting-task-with-missing-lifecycle", lager.Data{"task-guid": task.TaskGuid, "lifecycle": missing.Lifecycle})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.LifecycleFailures = append(failed.LifecycleFailures, rep.LifecycleFailure{TaskGuid: task.TaskGuid, Error: *missing})
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// rejectInvalidInitSteps fails the LRPs of work with an init step the cell
+// could not run, or whose path the cell does not allow.
+func (a *AuctionCellRep) rejectInvalidInitSteps(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	valid := work
+	valid.LRPs = nil
+
+	for _, lrp := range work.LRPs {
+		if err := validateInitSteps(lrp.InitSteps, a.initStepPaths); err != nil {
+			logger.Info("rejecting-lrp-with-invalid-init-steps", lager.Data{"instance-guid": lrp.InstanceGUID, "error": err.Error()})
+			failed.LRPs = append(failed.LRPs, lrp)
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	return valid
+}
+
+func validateInitSteps(steps []rep.InitStep, allowedPaths []string) error {
+	for i := range steps {
+		if err := steps[i].Validate(); err != nil {
+			return err
+		}
+		if err := steps[i].ValidateAllowed(allowedPaths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRegistry validates the credentials the rootfs is pulled with.
+func (a *AuctionCellRep) validateRegistry(rootFS string, registry *rep.RegistryCredentials, now time.Time) *rep.RegistryValidationError {
+	if registry == nil {
+		return nil
+	}
+
+	rootFSPath, err := a.stackPathMap.PathForRootFS(rootFS)
+	if err != nil {
+		rootFSPath = rootFS
+	}
+	pull, err := a.pullCredentials.PullCredentials(rootFSPath, registry)
+	if err != nil {
+		return &rep.RegistryValidationError{Reason: rep.RegistryUnconvertedCredentials, Message: err.Error()}
+	}
+
+	err = pull.Validate(now)
+	if err == nil {
+		return nil
+	}
+
+	validationErr := err.(rep.RegistryValidationError)
+	return &validationErr
+}
+
+// ReserveCapacity holds the capacity for request on the backend its
+// instances are placed on until the reservation expires or all its instances
+// are placed.
+func (a *AuctionCellRep) ReserveCapacity(logger lager.Logger, request rep.CapacityReservationRequest) (rep.CapacityReservation, error) {
+	logger = logger.Session("reserve-capacity", lager.Data{"process-guid": request.ProcessGuid, "instances": request.Instances})
+
+	if a.reservations == nil {
+		return rep.CapacityReservation{}, ErrCapacityReservationsDisabled
+	}
+
+	backends := a.backends()
+	backend := backends[backendIndexFor(backends, request.RootFs)]
+	remainingResources, err := backend.Client.RemainingResources(logger)
+	if err != nil {
+		logger.Error("failed-gathering-remaining-resources", err, lager.Data{"backend": backend.Name})
+		return rep.CapacityReservation{}, err
+	}
+
+	reservation, err := a.reservations.Reserve(request, backend.Name, a.ttl(logger, request.TTLSeconds, request.Deadline), a.convertResources(remainingResources))
+	if err != nil {
+		logger.Error("failed-to-reserve-capacity", err)
+		return rep.CapacityReservation{}, err
+	}
+
+	logger.Info("reserved", lager.Data{"reservation-id": reservation.ID})
+	return reservation, nil
+}
+
+// ttl returns how long to hold what a request asks the cell for, for
+// ttlSeconds or until deadline when it is given.
+func (a *AuctionCellRep) ttl(logger lager.Logger, ttlSeconds int64, deadline *rep.Deadline) time.Duration {
+	if deadline == nil {
+		return time.Duration(ttlSeconds) * time.Second
+	}
+	if a.clockSkew == nil {
+		remaining, _ := deadline.Remaining(a.clock.Now(), 0)
+		return remaining
+	}
+	return a.clockSkew.Remaining(logger, *deadline)
+}
+
+// ReleaseCapacity releases the capacity held by a reservation before it
+// expires.
+func (a *AuctionCellRep) ReleaseCapacity(logger lager.Logger, reservationID string) error {
+	logger = logger.Session("release-capacity", lager.Data{"reservation-id": reservationID})
+
+	if a.reservations == nil {
+		return ErrCapacityReservationsDisabled
+	}
+
+	err := a.reservations.Release(reservationID)
+	if err != nil {
+		logger.Error("failed-to-release-capacity", err)
+		return err
+	}
+
+	logger.Info("released")
+	return nil
+}
+
+// partitionWork assigns every LRP and Task to the first backend that provides
+// its rootfs. Work no backend provides the rootfs for is assigned to the
+// primary executor, which rejects it.
+func partitionWork(backends []Backend, work rep.Work) []rep.Work {
+	partitions := make([]rep.Work, len(backends))
+
+	for _, lrp := range work.LRPs {
+		i := backendIndexFor(backends, lrp.RootFs)
+		partitions[i].LRPs = append(partitions[i].LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		i := backendIndexFor(backends, task.RootFs)
+		partitions[i].Tasks = append(partitions[i].Tasks, task)
+	}
+
+	return partitions
+}
+
+func backendIndexFor(backends []Backend, rootfs string) int {
+	if len(backends) == 1 {
+		return 0
+	}
+
+	for i, backend := range backends {
+		if backend.matchRootFS(rootfs) {
+			return i
+		}
+	}
+	return 0
+}
+
+func (a *AuctionCellRep) proxyOverheadEnabled() bool {
+	return a.enableContainerProxy && a.featureFlags.Enabled(featureflags.ProxyOverhead)
+}
+
 func (a *AuctionCellRep) convertResources(resources executor.ExecutorResources) rep.Resources {
 	return rep.Resources{
 		MemoryMB:   int32(resources.MemoryMB),
@@ -382,6 +1627,6 @@ func (a *AuctionCellRep) convertResources(resources executor.ExecutorResources)
 	}
 }
 
-func (a *AuctionCellRep) Reset() error {
+func (a *AuctionCellRep) Reset(ctx context.Context) error {
 	return errors.New("not-a-simulation-rep")
 }
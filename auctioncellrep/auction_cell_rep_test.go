@@ -1,19 +1,44 @@
 package auctioncellrep_test
 
 import (
+	"context"
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 
 	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/executor/containermetrics"
 	fake_client "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/rep"
 	"code.cloudfoundry.org/rep/auctioncellrep"
 	fakes "code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
+	"code.cloudfoundry.org/rep/clockskew"
+	"code.cloudfoundry.org/rep/contacts/contactsfakes"
+	"code.cloudfoundry.org/rep/cordon/cordonfakes"
+	"code.cloudfoundry.org/rep/crashloop"
+	"code.cloudfoundry.org/rep/crashloop/crashloopfakes"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context/fake_evacuation_context"
+	"code.cloudfoundry.org/rep/featureflags"
+	"code.cloudfoundry.org/rep/hostmetrics"
+	"code.cloudfoundry.org/rep/hostmetrics/hostmetricsfakes"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+	"code.cloudfoundry.org/rep/lifecycles"
+	"code.cloudfoundry.org/rep/lifecycles/lifecyclesfakes"
+	"code.cloudfoundry.org/rep/maintenance/fake_maintenance"
+	"code.cloudfoundry.org/rep/placementpolicy"
+	"code.cloudfoundry.org/rep/placementpolicy/placementpolicyfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit/ginkgomon"
 )
 
 const (
@@ -30,22 +55,68 @@ var _ = Describe("AuctionCellRep", func() {
 		client                       *fake_client.FakeClient
 		logger                       *lagertest.TestLogger
 		evacuationReporter           *fake_evacuation_context.FakeEvacuationReporter
+		maintenanceReporter          *fake_maintenance.FakeMaintenanceReporter
+		cordonReporter               *cordonfakes.FakeReporter
+		contactReporter              *contactsfakes.FakeReporter
+		cellCondition                *fakes.FakeCellCondition
 		fakeContainerMetricsProvider *fakes.FakeContainerMetricsProvider
 
 		linuxRootFSURL string
 		commonErr      error
 
 		placementTags, optionalPlacementTags []string
+		isolationSegment                     string
+		instanceID, instanceType             string
+		osFamily                             string
+		imageOverhead                        rep.Resource
+		maxContainerResource                 rep.Resource
+		securityPermissions                  rep.SecurityPermissions
+		hostPortPoolSize                     int32
+		cpuEntitlement                       float64
+		tenantCaps                           *rep.TenantCaps
+		stackContainerLimits                 map[string]int
+		cgroupInfo                           *rep.CgroupInfo
+		logDropCounter                       *fakes.FakeLogDropCounter
+		ioLimitReporter                      *fakes.FakeIOLimitReporter
 		enableContainerProxy                 bool
 		proxyMemoryAllocation                int
 
 		fakeContainerAllocator *fakes.FakeBatchContainerAllocator
+		additionalBackends     []auctioncellrep.Backend
+		hostPressureReader     *hostmetricsfakes.FakeReader
+		hostPressureWeight     float64
+		recentLRPs             *auctioncellrep.RecentLRPTracker
+		recentLRPScoreBonus    float64
+		placementHistory       *auctioncellrep.PlacementHistory
+		usageForecaster        *auctioncellrep.UsageForecaster
+		usageForecastWeight    float64
+		rootFSUsageReader      *imagecachefakes.FakeUsageReader
+		imageSizeChecker       *imagecachefakes.FakeSizeChecker
+		imageDigestPolicy      *imagecachefakes.FakeDigestPolicy
+		lifecycleCatalog       *lifecyclesfakes.FakeCatalog
+		initStepPaths          []string
+		workGroupHolds         *rep.WorkGroupHolds
+		reservations           *auctioncellrep.CapacityReservations
+		maintenanceSchedule    *auctioncellrep.MaintenanceSchedule
+		failureDomains         []string
+		failureDomainPenalty   float64
+		crashLoopDetector      *crashloopfakes.FakeDetector
+		placementPolicy        *placementpolicyfakes.FakePolicy
+		clockSkew              *clockskew.Monitor
+		diskQuotaGrower        *fakes.FakeDiskQuotaGrower
+		diskGrowth             *auctioncellrep.DiskQuotaGrowth
+		repClock               *fakeclock.FakeClock
+		featureFlags           *featureflags.Flags
 	)
 
 	BeforeEach(func() {
 		client = new(fake_client.FakeClient)
 		logger = lagertest.NewTestLogger("test")
 		evacuationReporter = &fake_evacuation_context.FakeEvacuationReporter{}
+		maintenanceReporter = &fake_maintenance.FakeMaintenanceReporter{}
+		cordonReporter = &cordonfakes.FakeReporter{}
+		contactReporter = &contactsfakes.FakeReporter{}
+		cellCondition = &fakes.FakeCellCondition{}
 		fakeContainerMetricsProvider = new(fakes.FakeContainerMetricsProvider)
 		fakeContainerAllocator = new(fakes.FakeBatchContainerAllocator)
 
@@ -54,10 +125,92 @@ var _ = Describe("AuctionCellRep", func() {
 		commonErr = errors.New("Failed to fetch")
 		enableContainerProxy = false
 		proxyMemoryAllocation = 12
+		isolationSegment = ""
+		placementTags = nil
+		optionalPlacementTags = nil
+		instanceID = ""
+		instanceType = ""
+		osFamily = rep.OSFamilyLinux
+		imageOverhead = rep.Resource{}
+		maxContainerResource = rep.Resource{}
+		securityPermissions = rep.SecurityPermissions{}
+		hostPortPoolSize = 0
+		cpuEntitlement = 0
+		tenantCaps = nil
+		stackContainerLimits = nil
+		cgroupInfo = nil
+		logDropCounter = nil
+		ioLimitReporter = nil
+		additionalBackends = nil
+		hostPressureReader = nil
+		hostPressureWeight = 0
+		recentLRPs = nil
+		recentLRPScoreBonus = 0
+		placementHistory = nil
+		usageForecaster = nil
+		usageForecastWeight = 0
+		rootFSUsageReader = nil
+		imageSizeChecker = nil
+		imageDigestPolicy = nil
+		lifecycleCatalog = nil
+		initStepPaths = nil
+		workGroupHolds = rep.NewWorkGroupHolds()
+		reservations = nil
+		maintenanceSchedule = nil
+		failureDomains = nil
+		failureDomainPenalty = 0
+		crashLoopDetector = nil
+		placementPolicy = nil
+		clockSkew = nil
+		diskQuotaGrower = nil
+		diskGrowth, _ = auctioncellrep.NewDiskQuotaGrowth(logger, "")
+		repClock = fakeclock.NewFakeClock(time.Now())
+		featureFlags = featureflags.New(nil)
 		client.HealthyReturns(true)
 	})
 
 	JustBeforeEach(func() {
+		var reader hostmetrics.Reader
+		if hostPressureReader != nil {
+			reader = hostPressureReader
+		}
+		var usageReader imagecache.UsageReader
+		if rootFSUsageReader != nil {
+			usageReader = rootFSUsageReader
+		}
+		var sizeChecker imagecache.SizeChecker
+		if imageSizeChecker != nil {
+			sizeChecker = imageSizeChecker
+		}
+		var digestPolicy imagecache.DigestPolicy
+		if imageDigestPolicy != nil {
+			digestPolicy = imageDigestPolicy
+		}
+		var logDrops auctioncellrep.LogDropCounter
+		if logDropCounter != nil {
+			logDrops = logDropCounter
+		}
+		var ioLimits auctioncellrep.IOLimitReporter
+		if ioLimitReporter != nil {
+			ioLimits = ioLimitReporter
+		}
+		var catalog lifecycles.Catalog
+		if lifecycleCatalog != nil {
+			catalog = lifecycleCatalog
+		}
+		var detector crashloop.Detector
+		if crashLoopDetector != nil {
+			detector = crashLoopDetector
+		}
+		var policy placementpolicy.Policy
+		if placementPolicy != nil {
+			policy = placementPolicy
+		}
+		var grower auctioncellrep.DiskQuotaGrower
+		if diskQuotaGrower != nil {
+			grower = diskQuotaGrower
+		}
+
 		cellRep = auctioncellrep.New(
 			cellID,
 			cellIndex,
@@ -66,13 +219,56 @@ var _ = Describe("AuctionCellRep", func() {
 			fakeContainerMetricsProvider,
 			[]string{"docker"},
 			"the-zone",
+			instanceID,
+			instanceType,
+			osFamily,
+			imageOverhead,
+			maxContainerResource,
+			securityPermissions,
+			hostPortPoolSize,
+			cpuEntitlement,
+			tenantCaps,
+			stackContainerLimits,
+			cgroupInfo,
+			logDrops,
+			ioLimits,
 			client,
 			evacuationReporter,
+			maintenanceReporter,
+			cordonReporter,
+			contactReporter,
+			[]auctioncellrep.CellCondition{cellCondition},
 			placementTags,
 			optionalPlacementTags,
+			isolationSegment,
 			proxyMemoryAllocation,
 			enableContainerProxy,
 			fakeContainerAllocator,
+			additionalBackends,
+			reader,
+			hostPressureWeight,
+			recentLRPs,
+			recentLRPScoreBonus,
+			placementHistory,
+			usageForecaster,
+			usageForecastWeight,
+			usageReader,
+			sizeChecker,
+			digestPolicy,
+			catalog,
+			initStepPaths,
+			reservations,
+			maintenanceSchedule,
+			failureDomains,
+			failureDomainPenalty,
+			detector,
+			workGroupHolds,
+			policy,
+			clockSkew,
+			grower,
+			diskGrowth,
+			repClock,
+			featureFlags,
 		)
 	})
 
@@ -124,6 +320,36 @@ var _ = Describe("AuctionCellRep", func() {
 				Expect(lrpMetrics.InstanceGUID).To(Equal("some-instance-guid"))
 				Expect(lrpMetrics.Index).To(Equal(int32(1)))
 				Expect(lrpMetrics.CachedContainerMetrics).To(Equal(metricValues))
+				Expect(lrpMetrics.LogBytesDropped).To(BeZero())
+			})
+
+			Context("when the cell rate limits the logs of containers", func() {
+				BeforeEach(func() {
+					logDropCounter = new(fakes.FakeLogDropCounter)
+					logDropCounter.DroppedBytesReturns(2048)
+				})
+
+				It("reports the log output dropped for the container", func() {
+					Expect(metrics.LRPs).To(HaveLen(1))
+					Expect(metrics.LRPs[0].LogBytesDropped).To(Equal(int64(2048)))
+				})
+			})
+
+			It("does not report I/O limits", func() {
+				Expect(metrics.LRPs[0].IOLimits).To(BeNil())
+			})
+
+			Context("when the cell throttled the I/O of the container", func() {
+				BeforeEach(func() {
+					ioLimitReporter = new(fakes.FakeIOLimitReporter)
+					ioLimitReporter.AppliedIOLimitsReturns(rep.IOLimits{ReadIOPS: 100, WriteBytesPerSecond: 1024}, true)
+				})
+
+				It("reports the limits it applied", func() {
+					Expect(metrics.LRPs).To(HaveLen(1))
+					Expect(metrics.LRPs[0].IOLimits).To(Equal(&rep.IOLimits{ReadIOPS: 100, WriteBytesPerSecond: 1024}))
+					Expect(ioLimitReporter.AppliedIOLimitsArgsForCall(0)).To(Equal("some-container-guid"))
+				})
 			})
 		})
 
@@ -154,6 +380,89 @@ var _ = Describe("AuctionCellRep", func() {
 				Expect(taskMetrics.CachedContainerMetrics).To(Equal(metricValues))
 			})
 		})
+
+		Context("when the cell has additional backends", func() {
+			BeforeEach(func() {
+				windowsClient := new(fake_client.FakeClient)
+				windowsContainer := createContainer(executor.StateRunning, rep.TaskLifecycle)
+				windowsContainer.Guid = "windows-container-guid"
+				windowsClient.ListContainersReturns([]executor.Container{windowsContainer}, nil)
+				windowsMetrics := new(fakes.FakeContainerMetricsProvider)
+				windowsMetrics.MetricsReturns(map[string]*containermetrics.CachedContainerMetrics{
+					"windows-container-guid": {MetricGUID: "windows-metric-guid"},
+				})
+
+				client.ListContainersReturns([]executor.Container{createContainer(executor.StateRunning, rep.LRPLifecycle)}, nil)
+				fakeContainerMetricsProvider.MetricsReturns(map[string]*containermetrics.CachedContainerMetrics{
+					"some-container-guid": {MetricGUID: "some-metric-guid"},
+				})
+
+				additionalBackends = []auctioncellrep.Backend{
+					auctioncellrep.NewBackend("windows", windowsClient, windowsMetrics, rep.StackPathMap{}, nil, new(fakes.FakeBatchContainerAllocator)),
+				}
+			})
+
+			It("returns the metrics of the containers of every backend", func() {
+				Expect(metrics.LRPs).To(HaveLen(1))
+				Expect(metrics.LRPs[0].CachedContainerMetrics.MetricGUID).To(Equal("some-metric-guid"))
+				Expect(metrics.Tasks).To(HaveLen(1))
+				Expect(metrics.Tasks[0].TaskGUID).To(Equal("windows-container-guid"))
+				Expect(metrics.Tasks[0].CachedContainerMetrics.MetricGUID).To(Equal("windows-metric-guid"))
+			})
+		})
+	})
+
+	Describe("MetricsBatch", func() {
+		It("returns the selected metrics of the containers without listing them", func() {
+			fakeContainerMetricsProvider.MetricsReturns(map[string]*containermetrics.CachedContainerMetrics{
+				"some-container-guid": {MetricGUID: "some-metric-guid", MemoryUsageBytes: 5, CPUUsageFraction: 0.8},
+			})
+
+			batch := cellRep.MetricsBatch(logger, rep.ContainerMetricsFields{Memory: true})
+			Expect(batch.CellID).To(Equal(cellID))
+			Expect(batch.Containers).To(HaveLen(1))
+			Expect(batch.Containers[0].Guid).To(Equal("some-container-guid"))
+			Expect(*batch.Containers[0].MemoryUsageBytes).To(Equal(uint64(5)))
+			Expect(batch.Containers[0].CPUUsageFraction).To(BeNil())
+			Expect(client.ListContainersCallCount()).To(BeZero())
+		})
+	})
+
+	Describe("CapacitySummary", func() {
+		BeforeEach(func() {
+			client.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 1024, DiskMB: 2048, Containers: 4}, nil)
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 512, DiskMB: 256, Containers: 2}, nil)
+		})
+
+		It("summarizes the capacity without listing the containers", func() {
+			evacuationReporter.EvacuatingReturns(true)
+
+			summary, healthy, err := cellRep.CapacitySummary(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(healthy).To(BeTrue())
+
+			Expect(summary.CellID).To(Equal(cellID))
+			Expect(summary.Zone).To(Equal("the-zone"))
+			Expect(summary.Evacuating).To(BeTrue())
+			Expect(summary.AvailableResources).To(Equal(rep.Resources{MemoryMB: 512, DiskMB: 256, Containers: 2}))
+			Expect(summary.TotalResources).To(Equal(rep.Resources{MemoryMB: 1024, DiskMB: 2048, Containers: 4}))
+			Expect(client.ListContainersCallCount()).To(BeZero())
+		})
+
+		It("is not healthy when the executor is not", func() {
+			client.HealthyReturns(false)
+
+			_, healthy, err := cellRep.CapacitySummary(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(healthy).To(BeFalse())
+		})
+
+		It("fails when the remaining resources cannot be fetched", func() {
+			client.RemainingResourcesReturns(executor.ExecutorResources{}, errors.New("boom"))
+
+			_, _, err := cellRep.CapacitySummary(context.Background(), logger)
+			Expect(err).To(MatchError("boom"))
+		})
 	})
 
 	Describe("State", func() {
@@ -161,6 +470,30 @@ var _ = Describe("AuctionCellRep", func() {
 			containers []executor.Container
 		)
 
+		Context("when the context is done", func() {
+			It("stops gathering the state", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, _, err := cellRep.State(ctx, logger)
+				Expect(err).To(Equal(context.Canceled))
+				Expect(client.ListContainersCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when converting listed containers", func() {
+			It("returns their LRPs and tasks without asking the executor", func() {
+				state := cellRep.ContainerState(logger, []executor.Container{
+					createContainer(executor.StateRunning, rep.LRPLifecycle),
+					createContainer(executor.StateRunning, rep.TaskLifecycle),
+				})
+				Expect(state.LRPs).To(HaveLen(1))
+				Expect(state.Tasks).To(HaveLen(1))
+				Expect(client.ListContainersCallCount()).To(BeZero())
+				Expect(client.RemainingResourcesCallCount()).To(BeZero())
+			})
+		})
+
 		Context("when the rep has a container", func() {
 			var (
 				state rep.CellState
@@ -170,7 +503,7 @@ var _ = Describe("AuctionCellRep", func() {
 				client.ListContainersReturns(containers, nil)
 				var healthy bool
 				var err error
-				state, healthy, err = cellRep.State(logger)
+				state, healthy, err = cellRep.State(context.Background(), logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(healthy).To(BeTrue())
 			})
@@ -377,6 +710,90 @@ var _ = Describe("AuctionCellRep", func() {
 						})
 					})
 
+					Context("with labels", func() {
+						BeforeEach(func() {
+							containers[0].Tags[rep.LabelTagPrefix+"team"] = "payments"
+						})
+
+						It("returns the labels", func() {
+							Expect(state.LRPs).To(HaveLen(1))
+							Expect(state.LRPs[0].Labels).To(Equal(map[string]string{"team": "payments"}))
+						})
+					})
+
+					Context("with a provenance", func() {
+						BeforeEach(func() {
+							containers[0].Tags[rep.ProvenanceTag] = `{"auction_id":"auction-1","auctioneer_id":"auctioneer-0","attempt":2}`
+						})
+
+						It("returns the provenance", func() {
+							Expect(state.LRPs).To(HaveLen(1))
+							Expect(state.LRPs[0].Provenance).To(Equal(&rep.Provenance{AuctionID: "auction-1", AuctioneerID: "auctioneer-0", Attempt: 2}))
+						})
+					})
+
+					Context("with tenant labels", func() {
+						BeforeEach(func() {
+							containers[0].Tags[rep.LabelTagPrefix+rep.OrganizationLabel] = "org-guid"
+							containers[0].Tags[rep.LabelTagPrefix+rep.SpaceLabel] = "space-guid"
+							tenantCaps = &rep.TenantCaps{MemoryMB: 4096}
+						})
+
+						It("reports the resources the tenant reserves and the caps of the cell", func() {
+							Expect(state.LRPs).To(HaveLen(1))
+							Expect(state.TenantUsage).To(Equal([]rep.TenantUsage{{
+								OrganizationID: "org-guid",
+								SpaceID:        "space-guid",
+								MemoryMB:       state.LRPs[0].MemoryMB,
+								DiskMB:         state.LRPs[0].DiskMB,
+								Containers:     1,
+							}}))
+							Expect(state.TenantCaps).To(Equal(&rep.TenantCaps{MemoryMB: 4096}))
+						})
+					})
+
+					Context("with stack container limits", func() {
+						BeforeEach(func() {
+							containers[0].RootFSPath = "docker://cloudfoundry/grace"
+							stackContainerLimits = map[string]int{"docker": 3, "cflinuxfs3": 2}
+						})
+
+						It("reports the containers of each stack left on the cell", func() {
+							Expect(state.StackContainersLeft).To(Equal(map[string]int{"docker": 2, "cflinuxfs3": 2}))
+						})
+					})
+
+					Context("with the cgroups of the host detected", func() {
+						BeforeEach(func() {
+							cgroupInfo = &rep.CgroupInfo{Version: 2, Controllers: []string{"cpu", "io", "memory", "pids"}}
+						})
+
+						It("reports the cgroups of the cell", func() {
+							Expect(state.Cgroups).To(Equal(&rep.CgroupInfo{Version: 2, Controllers: []string{"cpu", "io", "memory", "pids"}}))
+						})
+					})
+
+					Context("with a network assignment", func() {
+						BeforeEach(func() {
+							containers[0].InternalIP = "10.255.0.4"
+							containers[0].ExternalIP = "10.0.16.4"
+							containers[0].Ports = []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001}}
+							containers[0].Network = &executor.Network{
+								Properties: map[string]string{rep.PolicyGroupIDProperty: "some-app-guid"},
+							}
+						})
+
+						It("returns the network assignment", func() {
+							Expect(state.LRPs).To(HaveLen(1))
+							Expect(state.LRPs[0].Network).To(Equal(&rep.ContainerNetwork{
+								InstanceAddress: "10.255.0.4",
+								HostAddress:     "10.0.16.4",
+								PolicyGroupID:   "some-app-guid",
+								Ports:           []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001}},
+							}))
+						})
+					})
+
 					Context("with different resource usage", func() {
 						BeforeEach(func() {
 							containers[0].Resource = executor.Resource{
@@ -420,7 +837,7 @@ var _ = Describe("AuctionCellRep", func() {
 			client.ListContainersReturns(containers, nil)
 			client.VolumeDriversReturns(volumeDrivers, nil)
 
-			state, healthy, err := cellRep.State(logger)
+			state, healthy, err := cellRep.State(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(healthy).To(BeTrue())
@@ -430,6 +847,7 @@ var _ = Describe("AuctionCellRep", func() {
 			Expect(state.RepURL).To(Equal(repURL))
 
 			Expect(state.Evacuating).To(BeTrue())
+			Expect(state.Maintenance).To(BeFalse())
 			Expect(state.RootFSProviders).To(Equal(rep.RootFSProviders{
 				models.PreloadedRootFSScheme:    rep.NewFixedSetRootFSProvider("linux"),
 				models.PreloadedOCIRootFSScheme: rep.NewFixedSetRootFSProvider("linux"),
@@ -452,267 +870,2609 @@ var _ = Describe("AuctionCellRep", func() {
 			Expect(state.ProxyMemoryAllocationMB).To(Equal(0))
 		})
 
-		Context("when enableContainerProxy is true", func() {
+		It("does not report host pressure by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.HostPressure).To(BeNil())
+		})
+
+		Context("when a host pressure reader is configured", func() {
 			BeforeEach(func() {
-				enableContainerProxy = true
+				hostPressureReader = new(hostmetricsfakes.FakeReader)
+				hostPressureReader.ReadReturns(rep.HostPressure{MemorySomeAvg10: 12.5, LoadAverage1: 3}, nil)
+				hostPressureWeight = 0.5
 			})
 
-			It("returns a state with a proxyMemoryAllocation greater than 0", func() {
-				state, _, err := cellRep.State(logger)
+			It("reports the host pressure and its scoring weight", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
 				Expect(err).NotTo(HaveOccurred())
 
-				Expect(state.ProxyMemoryAllocationMB).To(Equal(proxyMemoryAllocation))
+				Expect(state.HostPressure).To(Equal(&rep.HostPressure{MemorySomeAvg10: 12.5, LoadAverage1: 3}))
+				Expect(state.HostPressureWeight).To(Equal(0.5))
+			})
+
+			Context("when reading the host pressure fails", func() {
+				BeforeEach(func() {
+					hostPressureReader.ReadReturns(rep.HostPressure{}, commonErr)
+				})
+
+				It("reports the state without it", func() {
+					state, _, err := cellRep.State(context.Background(), logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(state.HostPressure).To(BeNil())
+				})
 			})
 		})
 
-		Context("when the cell is not healthy", func() {
+		It("does not report rootfs disk usage by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.RootFSDiskUsage).To(BeNil())
+		})
+
+		Context("when a rootfs disk usage reader is configured", func() {
 			BeforeEach(func() {
-				client.HealthyReturns(false)
+				rootFSUsageReader = new(imagecachefakes.FakeUsageReader)
+				rootFSUsageReader.ReadReturns(map[string]rep.RootFSDiskUsage{
+					"docker": {DiskMB: 2048, ReclaimableDiskMB: 512},
+				}, nil)
 			})
 
-			It("errors when reporting state", func() {
-				_, healthy, err := cellRep.State(logger)
+			It("reports the disk usage of each rootfs provider", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(healthy).To(BeFalse())
+
+				Expect(state.RootFSDiskUsage).To(Equal(map[string]rep.RootFSDiskUsage{
+					"docker": {DiskMB: 2048, ReclaimableDiskMB: 512},
+				}))
+			})
+
+			Context("when reading the disk usage fails", func() {
+				BeforeEach(func() {
+					rootFSUsageReader.ReadReturns(nil, commonErr)
+				})
+
+				It("reports the state without it", func() {
+					state, _, err := cellRep.State(context.Background(), logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(state.RootFSDiskUsage).To(BeNil())
+				})
 			})
 		})
 
-		Context("when the client fails to fetch total resources", func() {
+		It("does not report recent LRPs by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.RecentLRPs).To(BeEmpty())
+		})
+
+		Context("when recent LRPs are tracked", func() {
+			var fakeClock *fakeclock.FakeClock
+
 			BeforeEach(func() {
-				client.TotalResourcesReturns(executor.ExecutorResources{}, commonErr)
+				fakeClock = fakeclock.NewFakeClock(time.Now())
+				recentLRPs = auctioncellrep.NewRecentLRPTracker(fakeClock, time.Minute, 100)
+				recentLRPScoreBonus = 0.1
 			})
 
-			It("should return an error and no state", func() {
-				_, _, err := cellRep.State(logger)
-				Expect(err).To(MatchError(commonErr))
+			It("reports the LRPs that ran on the cell within the retention period", func() {
+				client.ListContainersReturns([]executor.Container{createContainer(executor.StateRunning, rep.LRPLifecycle)}, nil)
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.RecentLRPScoreBonus).To(Equal(0.1))
+
+				expectedRecentLRPs := []rep.RecentLRP{}
+				for _, lrp := range state.LRPs {
+					expectedRecentLRPs = append(expectedRecentLRPs, rep.RecentLRP{ProcessGUID: lrp.ProcessGuid, Index: lrp.Index})
+				}
+				Expect(expectedRecentLRPs).To(HaveLen(1))
+				Expect(state.RecentLRPs).To(ConsistOf(expectedRecentLRPs))
+
+				client.ListContainersReturns(nil, nil)
+				fakeClock.Increment(30 * time.Second)
+				state, _, err = cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.RecentLRPs).To(ConsistOf(expectedRecentLRPs))
+
+				fakeClock.Increment(31 * time.Second)
+				state, _, err = cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.RecentLRPs).To(BeEmpty())
 			})
 		})
 
-		Context("when the client fails to fetch available resources", func() {
+		It("does not report a usage forecast by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.UsageForecast).To(BeNil())
+		})
+
+		Context("when usage is forecast", func() {
+			var usage map[string]*containermetrics.CachedContainerMetrics
+
 			BeforeEach(func() {
-				client.RemainingResourcesReturns(executor.ExecutorResources{}, commonErr)
+				usage = map[string]*containermetrics.CachedContainerMetrics{
+					"container-1": {MemoryUsageBytes: 100 * 1024 * 1024, DiskUsageBytes: 200 * 1024 * 1024},
+				}
+				fakeContainerMetricsProvider.MetricsStub = func() map[string]*containermetrics.CachedContainerMetrics {
+					return usage
+				}
+				usageForecaster = auctioncellrep.NewUsageForecaster(fakeContainerMetricsProvider, repClock, time.Minute, 5*time.Minute)
+				usageForecastWeight = 0.5
 			})
 
-			It("should return an error and no state", func() {
-				_, _, err := cellRep.State(logger)
-				Expect(err).To(MatchError(commonErr))
+			It("does not report a forecast before the trend is known", func() {
+				usageForecaster.Sample()
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.UsageForecast).To(BeNil())
 			})
-		})
 
-		Context("when the client fails to list containers", func() {
-			BeforeEach(func() {
-				client.ListContainersReturns(nil, commonErr)
+			It("reports the usage the trend reaches at the horizon", func() {
+				usageForecaster.Sample()
+				usage["container-2"] = &containermetrics.CachedContainerMetrics{MemoryUsageBytes: 10 * 1024 * 1024, DiskUsageBytes: 20 * 1024 * 1024}
+				usageForecaster.Sample()
+
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.UsageForecastWeight).To(Equal(0.5))
+				Expect(state.UsageForecast).To(Equal(&rep.UsageForecast{
+					HorizonSeconds: 300,
+					MemoryMB:       160,
+					DiskMB:         320,
+				}))
 			})
 
-			It("should return an error and no state", func() {
-				_, _, err := cellRep.State(logger)
-				Expect(err).To(MatchError(commonErr))
+			It("does not forecast negative usage when containers go away", func() {
+				usageForecaster.Sample()
+				usage = nil
+				usageForecaster.Sample()
+
+				forecast, ok := usageForecaster.Forecast()
+				Expect(ok).To(BeTrue())
+				Expect(forecast.MemoryMB).To(BeZero())
+				Expect(forecast.DiskMB).To(BeZero())
+			})
+
+			It("samples the usage every interval while running", func() {
+				process := ginkgomon.Invoke(usageForecaster)
+				defer ginkgomon.Interrupt(process)
+
+				repClock.WaitForWatcherAndIncrement(time.Minute)
+				Eventually(fakeContainerMetricsProvider.MetricsCallCount).Should(Equal(1))
+				repClock.WaitForWatcherAndIncrement(time.Minute)
+				Eventually(func() bool {
+					_, ok := usageForecaster.Forecast()
+					return ok
+				}).Should(BeTrue())
 			})
 		})
 
-		Context("when placement tags have been set", func() {
+		It("reports the OS family without an image overhead on Linux cells", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(state.OSFamily).To(Equal(rep.OSFamilyLinux))
+			Expect(state.ImageOverhead).To(BeNil())
+		})
+
+		Context("when the cell is a Windows cell", func() {
 			BeforeEach(func() {
-				placementTags = []string{"quack", "oink"}
+				osFamily = rep.OSFamilyWindows
+				imageOverhead = rep.Resource{MemoryMB: 100, DiskMB: 1000}
 			})
 
-			It("returns the tags as part of the state", func() {
-				state, healthy, err := cellRep.State(logger)
+			It("reports the OS family and the image overhead", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(healthy).To(BeTrue())
-				Expect(state.PlacementTags).To(ConsistOf(placementTags))
+
+				Expect(state.OSFamily).To(Equal(rep.OSFamilyWindows))
+				Expect(state.ImageOverhead).To(Equal(&rep.Resource{MemoryMB: 100, DiskMB: 1000}))
+			})
+
+			It("does not provide the preloaded+layer rootfs scheme", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.RootFSProviders).To(Equal(rep.RootFSProviders{
+					models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("linux"),
+					"docker":                     rep.ArbitraryRootFSProvider{},
+				}))
 			})
 		})
 
-		Context("when optional placement tags have been set", func() {
+		It("does not report a maximum container size by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(state.MaxContainerMemoryMB).To(BeZero())
+			Expect(state.MaxContainerDiskMB).To(BeZero())
+		})
+
+		Context("when a maximum container size is configured", func() {
 			BeforeEach(func() {
-				optionalPlacementTags = []string{"baa", "cluck"}
+				maxContainerResource = rep.Resource{MemoryMB: 4096, DiskMB: 8192}
 			})
 
-			It("returns the tags as part of the state", func() {
-				state, healthy, err := cellRep.State(logger)
+			It("reports the maximum container size", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(healthy).To(BeTrue())
-				Expect(state.OptionalPlacementTags).To(ConsistOf(optionalPlacementTags))
+
+				Expect(state.MaxContainerMemoryMB).To(BeEquivalentTo(4096))
+				Expect(state.MaxContainerDiskMB).To(BeEquivalentTo(8192))
 			})
 		})
-	})
-
-	Describe("Perform", func() {
-		var (
-			remainingCellMemory int
 
-			lrpAuctionOne, lrpAuctionTwo, lrpAuctionThree rep.LRP
-			lrpAuctions                                   []rep.LRP
-			work                                          rep.Work
+		It("does not allow any capabilities or profiles by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
 
-			successfulLRP, unsuccessfulLRP   rep.LRP
-			successfulTask, unsuccessfulTask rep.Task
-		)
+			Expect(state.AllowedCapabilities).To(BeEmpty())
+			Expect(state.AllowedSeccompProfiles).To(BeEmpty())
+			Expect(state.AllowedAppArmorProfiles).To(BeEmpty())
+		})
 
-		BeforeEach(func() {
-			remainingCellMemory = 8192
+		Context("when capabilities and profiles are allowed", func() {
+			BeforeEach(func() {
+				securityPermissions = rep.SecurityPermissions{
+					Capabilities:     []string{"CAP_NET_ADMIN"},
+					SeccompProfiles:  []string{"unconfined"},
+					AppArmorProfiles: []string{"diego-privileged"},
+				}
+			})
 
-			successfulLRP = rep.NewLRP(
-				"ig-1",
-				models.ActualLRPKey{
-					ProcessGuid: "process-guid",
-					Index:       0,
-					Domain:      "domain",
-				},
-				rep.Resource{},
-				rep.PlacementConstraint{},
-			)
+			It("reports them", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
 
-			unsuccessfulLRP = rep.NewLRP(
-				"ig-2",
-				models.ActualLRPKey{
-					ProcessGuid: "process-guid",
-					Index:       1,
-					Domain:      "domain",
-				},
-				rep.Resource{},
-				rep.PlacementConstraint{},
-			)
+				Expect(state.AllowedCapabilities).To(Equal([]string{"CAP_NET_ADMIN"}))
+				Expect(state.AllowedSeccompProfiles).To(Equal([]string{"unconfined"}))
+				Expect(state.AllowedAppArmorProfiles).To(Equal([]string{"diego-privileged"}))
+			})
+		})
 
-			successfulTask = rep.NewTask(
-				"ig-1",
-				"domain",
-				rep.Resource{},
-				rep.PlacementConstraint{},
-			)
+		It("does not track host ports by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
 
-			unsuccessfulTask = rep.NewTask(
-				"ig-2",
-				"domain",
-				rep.Resource{},
-				rep.PlacementConstraint{},
-			)
+			Expect(state.TotalHostPorts).To(BeZero())
+			Expect(state.AvailableHostPorts).To(BeZero())
 		})
 
-		JustBeforeEach(func() {
-			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: remainingCellMemory}, nil)
-			lrpAuctions = []rep.LRP{lrpAuctionOne, lrpAuctionTwo, lrpAuctionThree}
-		})
+		Context("when the host port pool size is configured", func() {
+			BeforeEach(func() {
+				hostPortPoolSize = 10
 
-		It("requests container allocation for all provided LRPs and Tasks", func() {
-			fakeContainerAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{unsuccessfulLRP})
-			fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{unsuccessfulTask})
+				lrpContainer := createContainer(executor.StateRunning, rep.LRPLifecycle)
+				lrpContainer.Ports = []executor.PortMapping{
+					{ContainerPort: 8080, HostPort: 61001, ContainerTLSProxyPort: 61443, HostTLSProxyPort: 61002},
+					{ContainerPort: 2222, HostPort: 61003},
+				}
+				taskContainer := createContainer(executor.StateReserved, rep.TaskLifecycle)
+				taskContainer.Guid = "some-task-guid"
+				taskContainer.Ports = []executor.PortMapping{{ContainerPort: 8080}}
+				client.ListContainersReturns([]executor.Container{lrpContainer, taskContainer}, nil)
+			})
 
-			cellRep.Perform(logger, rep.Work{
+			It("reports the host ports taken by the containers on the cell", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.TotalHostPorts).To(BeEquivalentTo(10))
+				Expect(state.AvailableHostPorts).To(BeEquivalentTo(6))
+				Expect(state.LRPs[0].HostPorts).To(BeEquivalentTo(3))
+				Expect(state.Tasks[0].HostPorts).To(BeEquivalentTo(1))
+			})
+
+			It("reports the host ports each container holds", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.LRPs[0].StaticHostPorts).To(Equal([]uint16{61001, 61002, 61003}))
+				Expect(state.Tasks[0].StaticHostPorts).To(BeEmpty())
+			})
+
+			Context("when the containers take more host ports than the pool has", func() {
+				BeforeEach(func() {
+					hostPortPoolSize = 2
+				})
+
+				It("reports no available host ports", func() {
+					state, _, err := cellRep.State(context.Background(), logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(state.AvailableHostPorts).To(BeZero())
+				})
+			})
+		})
+
+		It("does not track CPU entitlement by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(state.TotalResources.CPUEntitlement).To(BeZero())
+			Expect(state.AvailableResources.CPUEntitlement).To(BeZero())
+		})
+
+		Context("when the CPU entitlement of the cell is configured", func() {
+			BeforeEach(func() {
+				cpuEntitlement = 4
+				client.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 1000, DiskMB: 2000, Containers: 4}, nil)
+
+				lrpContainer := createContainer(executor.StateRunning, rep.LRPLifecycle)
+				lrpContainer.Tags[rep.CPUEntitlementTag] = "1.5"
+				taskContainer := createContainer(executor.StateRunning, rep.TaskLifecycle)
+				taskContainer.Guid = "some-task-guid"
+				taskContainer.MemoryMB = 250
+				client.ListContainersReturns([]executor.Container{lrpContainer, taskContainer}, nil)
+			})
+
+			It("reports the entitlement of the containers on the cell", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.TotalResources.CPUEntitlement).To(Equal(4.0))
+				Expect(state.LRPs[0].CPUEntitlement).To(Equal(1.5))
+				Expect(state.Tasks[0].CPUEntitlement).To(Equal(1.0))
+				Expect(state.AvailableResources.CPUEntitlement).To(Equal(1.5))
+			})
+		})
+
+		Context("when the cell has additional backends", func() {
+			var windowsClient *fake_client.FakeClient
+
+			BeforeEach(func() {
+				windowsClient = new(fake_client.FakeClient)
+				windowsClient.HealthyReturns(true)
+				windowsClient.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 2048, DiskMB: 4096, Containers: 8}, nil)
+				windowsClient.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1024, DiskMB: 1024, Containers: 4}, nil)
+				windowsClient.ListContainersReturns([]executor.Container{createContainer(executor.StateRunning, rep.TaskLifecycle)}, nil)
+
+				client.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 1024, DiskMB: 2048, Containers: 4}, nil)
+				client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 512, DiskMB: 256, Containers: 2}, nil)
+				client.ListContainersReturns([]executor.Container{createContainer(executor.StateRunning, rep.LRPLifecycle)}, nil)
+
+				additionalBackends = []auctioncellrep.Backend{
+					auctioncellrep.NewBackend(
+						"windows",
+						windowsClient,
+						nil,
+						rep.StackPathMap{"windows": "/data/rootfs/windows"},
+						nil,
+						new(fakes.FakeBatchContainerAllocator),
+					),
+				}
+			})
+
+			It("aggregates the resources and work across all backends", func() {
+				state, healthy, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeTrue())
+
+				Expect(state.AvailableResources).To(Equal(rep.Resources{MemoryMB: 1536, DiskMB: 1280, Containers: 6}))
+				Expect(state.TotalResources).To(Equal(rep.Resources{MemoryMB: 3072, DiskMB: 6144, Containers: 12}))
+				Expect(state.LRPs).To(HaveLen(1))
+				Expect(state.Tasks).To(HaveLen(1))
+			})
+
+			It("merges the rootfs providers of all backends", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.RootFSProviders).To(Equal(rep.RootFSProviders{
+					models.PreloadedRootFSScheme:    rep.NewFixedSetRootFSProvider("linux", "windows"),
+					models.PreloadedOCIRootFSScheme: rep.NewFixedSetRootFSProvider("linux", "windows"),
+					"docker":                        rep.ArbitraryRootFSProvider{},
+				}))
+			})
+
+			It("reports each backend separately", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.Backends).To(HaveLen(2))
+				Expect(state.Backends[0].Name).To(Equal(auctioncellrep.DefaultBackendName))
+				Expect(state.Backends[0].AvailableResources).To(Equal(rep.Resources{MemoryMB: 512, DiskMB: 256, Containers: 2}))
+				Expect(state.Backends[1].Name).To(Equal("windows"))
+				Expect(state.Backends[1].AvailableResources).To(Equal(rep.Resources{MemoryMB: 1024, DiskMB: 1024, Containers: 4}))
+				Expect(state.Backends[1].RootFSProviders).To(Equal(rep.RootFSProviders{
+					models.PreloadedRootFSScheme:    rep.NewFixedSetRootFSProvider("windows"),
+					models.PreloadedOCIRootFSScheme: rep.NewFixedSetRootFSProvider("windows"),
+				}))
+			})
+
+			Context("when an additional backend is unhealthy", func() {
+				BeforeEach(func() {
+					windowsClient.HealthyReturns(false)
+				})
+
+				It("reports the cell as unhealthy", func() {
+					_, healthy, err := cellRep.State(context.Background(), logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(healthy).To(BeFalse())
+				})
+			})
+
+			Context("when an additional backend fails to report its resources", func() {
+				BeforeEach(func() {
+					windowsClient.TotalResourcesReturns(executor.ExecutorResources{}, commonErr)
+				})
+
+				It("returns the error", func() {
+					_, _, err := cellRep.State(context.Background(), logger)
+					Expect(err).To(MatchError(commonErr))
+				})
+			})
+		})
+
+		Context("when enableContainerProxy is true", func() {
+			BeforeEach(func() {
+				enableContainerProxy = true
+			})
+
+			It("returns a state with a proxyMemoryAllocation greater than 0", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.ProxyMemoryAllocationMB).To(Equal(proxyMemoryAllocation))
+			})
+
+			Context("when the proxy overhead feature flag is disabled", func() {
+				BeforeEach(func() {
+					featureFlags = featureflags.New(map[string]bool{featureflags.ProxyOverhead: false})
+				})
+
+				It("does not report a proxyMemoryAllocation", func() {
+					state, _, err := cellRep.State(context.Background(), logger)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(state.ProxyMemoryAllocationMB).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when feature flags have been enabled", func() {
+			BeforeEach(func() {
+				featureFlags = featureflags.New(map[string]bool{featureflags.LocalRestart: true})
+			})
+
+			It("returns the enabled flags as part of the state", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.FeatureFlags).To(ConsistOf(featureflags.LocalRestart, featureflags.ProxyOverhead))
+			})
+		})
+
+		Context("when the cell is in maintenance", func() {
+			BeforeEach(func() {
+				maintenanceReporter.InMaintenanceReturns(true)
+			})
+
+			It("reports maintenance as part of the state", func() {
+				state, healthy, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeTrue())
+				Expect(state.Maintenance).To(BeTrue())
+			})
+		})
+
+		Context("when the cell is cordoned", func() {
+			BeforeEach(func() {
+				cordonReporter.CordonedReturns(true)
+			})
+
+			It("reports the cordon as part of the state", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Cordoned).To(BeTrue())
+			})
+		})
+
+		Context("when the cell has reached the BBS and Garden", func() {
+			BeforeEach(func() {
+				contactReporter.LastContactsReturns(rep.LastContacts{LastBBSContact: 1000, LastGardenContact: 2000})
+			})
+
+			It("reports the last contacts as part of the state", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.LastBBSContact).To(BeEquivalentTo(1000))
+				Expect(state.LastGardenContact).To(BeEquivalentTo(2000))
+			})
+		})
+
+		Context("when the cell throttles the I/O of containers", func() {
+			BeforeEach(func() {
+				ioLimitReporter = new(fakes.FakeIOLimitReporter)
+				ioLimitReporter.SupportedIOLimitsReturns([]string{rep.IOLimitKindDisk})
+			})
+
+			It("reports the kinds of limits it supports as part of the state", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.SupportedIOLimits).To(Equal([]string{rep.IOLimitKindDisk}))
+			})
+		})
+
+		Context("when the cell is not healthy", func() {
+			BeforeEach(func() {
+				client.HealthyReturns(false)
+			})
+
+			It("errors when reporting state", func() {
+				_, healthy, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeFalse())
+			})
+
+			It("reports garden as unresponsive", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.UnhealthyReasons).To(Equal([]string{rep.UnhealthyReasonGardenUnresponsive}))
+			})
+
+			It("logs that the cell became unhealthy once", func() {
+				_, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).To(Say("cell-became-unhealthy"))
+
+				_, _, err = cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).NotTo(Say("cell-became-unhealthy"))
+
+				client.HealthyReturns(true)
+				_, _, err = cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).To(Say("cell-became-healthy"))
+			})
+		})
+
+		Context("when a condition of the cell holds", func() {
+			BeforeEach(func() {
+				cellCondition.ConditionReturns(rep.CellConditionPresenceLost)
+			})
+
+			It("reports the condition without the cell being unhealthy", func() {
+				state, healthy, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeTrue())
+				Expect(state.UnhealthyReasons).To(BeEmpty())
+				Expect(state.Conditions).To(Equal([]string{rep.CellConditionPresenceLost}))
+			})
+
+			It("logs the conditions when they change", func() {
+				_, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).To(Say("cell-conditions-changed"))
+
+				_, _, err = cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).NotTo(Say("cell-conditions-changed"))
+
+				cellCondition.ConditionReturns("")
+				_, _, err = cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger).To(Say("cell-conditions-changed"))
+			})
+		})
+
+		Context("when the client fails to fetch total resources", func() {
+			BeforeEach(func() {
+				client.TotalResourcesReturns(executor.ExecutorResources{}, commonErr)
+			})
+
+			It("should return an error and no state", func() {
+				_, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).To(MatchError(commonErr))
+			})
+		})
+
+		Context("when the client fails to fetch available resources", func() {
+			BeforeEach(func() {
+				client.RemainingResourcesReturns(executor.ExecutorResources{}, commonErr)
+			})
+
+			It("should return an error and no state", func() {
+				_, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).To(MatchError(commonErr))
+			})
+		})
+
+		Context("when the client fails to list containers", func() {
+			BeforeEach(func() {
+				client.ListContainersReturns(nil, commonErr)
+			})
+
+			It("should return an error and no state", func() {
+				_, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).To(MatchError(commonErr))
+			})
+		})
+
+		Context("when the instance metadata has been discovered", func() {
+			BeforeEach(func() {
+				instanceID = "i-0123456789"
+				instanceType = "m5.large"
+			})
+
+			It("returns the instance metadata as part of the state", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Zone).To(Equal("the-zone"))
+				Expect(state.InstanceID).To(Equal("i-0123456789"))
+				Expect(state.InstanceType).To(Equal("m5.large"))
+			})
+		})
+
+		Context("when placement tags have been set", func() {
+			BeforeEach(func() {
+				placementTags = []string{"quack", "oink"}
+			})
+
+			It("returns the tags as part of the state", func() {
+				state, healthy, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeTrue())
+				Expect(state.PlacementTags).To(ConsistOf(placementTags))
+			})
+		})
+
+		Context("when optional placement tags have been set", func() {
+			BeforeEach(func() {
+				optionalPlacementTags = []string{"baa", "cluck"}
+			})
+
+			It("returns the tags as part of the state", func() {
+				state, healthy, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeTrue())
+				Expect(state.OptionalPlacementTags).To(ConsistOf(optionalPlacementTags))
+			})
+		})
+
+		Context("when the cell is in an isolation segment", func() {
+			BeforeEach(func() {
+				isolationSegment = "segment"
+			})
+
+			It("requires the isolation segment as a placement tag", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.PlacementTags).To(ContainElement("segment"))
+			})
+		})
+
+		Context("when the placement tags are updated", func() {
+			var tags rep.CellPlacementTags
+
+			BeforeEach(func() {
+				tags = rep.CellPlacementTags{PlacementTags: []string{"pt"}, OptionalPlacementTags: []string{"ssd"}}
+				container := createContainer(executor.StateRunning, rep.LRPLifecycle)
+				client.ListContainersReturns([]executor.Container{container}, nil)
+			})
+
+			It("returns the updated tags as part of subsequent states", func() {
+				_, err := cellRep.UpdatePlacementTags(context.Background(), logger, tags, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cellRep.PlacementTags(logger)).To(Equal(tags))
+
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.PlacementTags).To(Equal([]string{"pt"}))
+				Expect(state.OptionalPlacementTags).To(Equal([]string{"ssd"}))
+			})
+
+			It("rejects blank tags", func() {
+				tags.OptionalPlacementTags = []string{""}
+				_, err := cellRep.UpdatePlacementTags(context.Background(), logger, tags, false)
+				Expect(err).To(MatchError(rep.ErrInvalidPlacementTags))
+			})
+
+			Context("when validating the resident work", func() {
+				It("reports none when the tags still match", func() {
+					update, err := cellRep.UpdatePlacementTags(context.Background(), logger, tags, true)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(update.Mismatches).To(BeEmpty())
+				})
+
+				It("reports the LRP instances and tasks the tags no longer match", func() {
+					tags.IsolationSegment = "segment"
+					update, err := cellRep.UpdatePlacementTags(context.Background(), logger, tags, true)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(update.Mismatches).To(Equal([]rep.PlacementTagsMismatch{
+						{InstanceGUID: "some-instance-guid", PlacementTags: []string{"pt"}},
+					}))
+				})
+			})
+		})
+	})
+
+	Describe("Perform", func() {
+		var (
+			remainingCellMemory int
+
+			lrpAuctionOne, lrpAuctionTwo, lrpAuctionThree rep.LRP
+			lrpAuctions                                   []rep.LRP
+			work                                          rep.Work
+
+			successfulLRP, unsuccessfulLRP   rep.LRP
+			successfulTask, unsuccessfulTask rep.Task
+		)
+
+		BeforeEach(func() {
+			remainingCellMemory = 8192
+
+			successfulLRP = rep.NewLRP(
+				"ig-1",
+				models.ActualLRPKey{
+					ProcessGuid: "process-guid",
+					Index:       0,
+					Domain:      "domain",
+				},
+				rep.Resource{},
+				rep.PlacementConstraint{},
+			)
+
+			unsuccessfulLRP = rep.NewLRP(
+				"ig-2",
+				models.ActualLRPKey{
+					ProcessGuid: "process-guid",
+					Index:       1,
+					Domain:      "domain",
+				},
+				rep.Resource{},
+				rep.PlacementConstraint{},
+			)
+
+			successfulTask = rep.NewTask(
+				"ig-1",
+				"domain",
+				rep.Resource{},
+				rep.PlacementConstraint{},
+			)
+
+			unsuccessfulTask = rep.NewTask(
+				"ig-2",
+				"domain",
+				rep.Resource{},
+				rep.PlacementConstraint{},
+			)
+		})
+
+		JustBeforeEach(func() {
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: remainingCellMemory}, nil)
+			lrpAuctions = []rep.LRP{lrpAuctionOne, lrpAuctionTwo, lrpAuctionThree}
+		})
+
+		It("requests container allocation for all provided LRPs and Tasks", func() {
+			fakeContainerAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{unsuccessfulLRP}, nil)
+			fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{unsuccessfulTask})
+
+			cellRep.Perform(context.Background(), logger, rep.Work{
 				LRPs:  []rep.LRP{successfulLRP, unsuccessfulLRP},
 				Tasks: []rep.Task{successfulTask, unsuccessfulTask},
 			})
 
 			Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
-			_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
-			Expect(lrpRequests).To(ConsistOf(successfulLRP, unsuccessfulLRP))
+			_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+			Expect(lrpRequests).To(ConsistOf(successfulLRP, unsuccessfulLRP))
+
+			Expect(fakeContainerAllocator.BatchTaskAllocationRequestCallCount()).To(Equal(1))
+			_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+			Expect(taskRequests).To(ConsistOf(successfulTask, unsuccessfulTask))
+		})
+
+		It("returns LRPs and Tasks that could not be allocated", func() {
+			fakeContainerAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{unsuccessfulLRP}, nil)
+			fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{unsuccessfulTask})
+
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+				LRPs:  []rep.LRP{successfulLRP, unsuccessfulLRP},
+				Tasks: []rep.Task{successfulTask, unsuccessfulTask},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.LRPs).To(ConsistOf(unsuccessfulLRP))
+			Expect(failedWork.Tasks).To(ConsistOf(unsuccessfulTask))
+		})
+
+		Context("with a placement history", func() {
+			var oversizedLRP rep.LRP
+
+			BeforeEach(func() {
+				placementHistory = auctioncellrep.NewPlacementHistory(repClock, 24)
+
+				oversizedLRP = successfulLRP.Copy()
+				oversizedLRP.InstanceGUID = "ig-oversized"
+				oversizedLRP.Index = 6
+				oversizedLRP.MemoryMB = 16384
+
+				fakeContainerAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{unsuccessfulLRP}, nil)
+				fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{unsuccessfulTask})
+			})
+
+			It("counts the placements it accepted and why it rejected the others", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, unsuccessfulLRP, oversizedLRP},
+					Tasks: []rep.Task{successfulTask, unsuccessfulTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(placementHistory.CapacityReport(1).Placements).To(Equal([]rep.PlacementSummary{
+					{Kind: rep.LRPLifecycle, Shape: "0MB/0MB", Accepted: 1, Rejected: map[string]int{rep.PlacementReasonAllocationFailed: 1}},
+					{Kind: rep.LRPLifecycle, Shape: "16384MB/0MB", Rejected: map[string]int{rep.PlacementReasonInsufficientResources: 1}},
+					{Kind: rep.TaskLifecycle, Shape: "0MB/0MB", Accepted: 1, Rejected: map[string]int{rep.PlacementReasonAllocationFailed: 1}},
+				}))
+			})
+
+			Context("when evacuating", func() {
+				BeforeEach(func() {
+					evacuationReporter.EvacuatingReturns(true)
+				})
+
+				It("counts all of the work as rejected", func() {
+					_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{successfulLRP}})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(placementHistory.CapacityReport(1).Placements).To(Equal([]rep.PlacementSummary{
+						{Kind: rep.LRPLifecycle, Shape: "0MB/0MB", Rejected: map[string]int{rep.PlacementReasonEvacuating: 1}},
+					}))
+				})
+			})
+		})
+
+		Context("when a concurrent perform holds some of the work", func() {
+			var release chan struct{}
+
+			BeforeEach(func() {
+				release = make(chan struct{})
+				fakeContainerAllocator.BatchLRPAllocationRequestStub = func(_ lager.Logger, _ bool, _ int, lrps []rep.LRP) ([]rep.LRP, map[string]string) {
+					for _, lrp := range lrps {
+						if lrp.InstanceGUID == successfulLRP.InstanceGUID {
+							<-release
+						}
+					}
+					return nil, nil
+				}
+			})
+
+			It("rejects the duplicates and performs the rest", func() {
+				performed := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					defer close(performed)
+					_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{successfulLRP}, Tasks: []rep.Task{successfulTask}})
+					Expect(err).NotTo(HaveOccurred())
+				}()
+				Eventually(fakeContainerAllocator.BatchLRPAllocationRequestCallCount).Should(Equal(1))
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, unsuccessfulLRP},
+					Tasks: []rep.Task{successfulTask, unsuccessfulTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				close(release)
+				Eventually(performed).Should(BeClosed())
+
+				Expect(failedWork.LRPs).To(ConsistOf(successfulLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(successfulTask))
+				Expect(failedWork.DuplicateWorkFailures).To(ConsistOf(
+					rep.DuplicateWorkFailure{ProcessGuid: successfulLRP.ProcessGuid, Index: successfulLRP.Index, Error: rep.ErrDuplicateWork.Error()},
+					rep.DuplicateWorkFailure{TaskGuid: successfulTask.TaskGuid, Error: rep.ErrDuplicateWork.Error()},
+				))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(1)
+				Expect(lrpRequests).To(ConsistOf(unsuccessfulLRP))
+			})
+
+			It("accepts the work again once the concurrent perform is done", func() {
+				close(release)
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{successfulLRP}})
+				Expect(err).NotTo(HaveOccurred())
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{successfulLRP}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.DuplicateWorkFailures).To(BeEmpty())
+				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when work requests resources in proportion to the cell", func() {
+			var proportionalLRP rep.LRP
+			var proportionalTask rep.Task
+
+			BeforeEach(func() {
+				client.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 8192, DiskMB: 16384, Containers: 100}, nil)
+				proportionalLRP = successfulLRP.Copy()
+				proportionalLRP.Proportional = &rep.ProportionalResource{MemoryPercent: 5, MaxMemoryMB: 256}
+				proportionalTask = successfulTask
+				proportionalTask.Proportional = &rep.ProportionalResource{DiskPercent: 10}
+			})
+
+			It("allocates the amounts they resolve to", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{proportionalLRP},
+					Tasks: []rep.Task{proportionalTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(HaveLen(1))
+				Expect(lrpRequests[0].MemoryMB).To(Equal(int32(256)))
+				Expect(lrpRequests[0].Proportional).To(Equal(proportionalLRP.Proportional))
+				_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(HaveLen(1))
+				Expect(taskRequests[0].DiskMB).To(Equal(int32(1639)))
+			})
+
+			It("fails the work whose proportions are invalid", func() {
+				proportionalLRP.Proportional.MemoryPercent = 150
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{proportionalLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(proportionalLRP))
+			})
+
+			It("fails when the total resources of the cell cannot be read", func() {
+				client.TotalResourcesReturns(executor.ExecutorResources{}, errors.New("boom"))
+
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{proportionalLRP},
+				})
+				Expect(err).To(MatchError("boom"))
+			})
+		})
+
+		Context("when work has network I/O limits", func() {
+			var limitedLRP rep.LRP
+			var limitedTask rep.Task
+
+			BeforeEach(func() {
+				limitedLRP = successfulLRP.Copy()
+				limitedLRP.IOLimits = &rep.IOLimits{EgressBytesPerSecond: 1024}
+				limitedTask = successfulTask
+				limitedTask.IOLimits = &rep.IOLimits{ReadIOPS: 100}
+			})
+
+			It("fails the work with network limits when the cell does not apply them", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{limitedLRP},
+					Tasks: []rep.Task{limitedTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(limitedLRP))
+				Expect(failedWork.Tasks).To(BeEmpty())
+				Expect(logger).To(Say("rejecting-lrp-with-unsupported-network-limits"))
+			})
+
+			Context("when the cell applies network limits", func() {
+				BeforeEach(func() {
+					ioLimitReporter = new(fakes.FakeIOLimitReporter)
+					ioLimitReporter.SupportedIOLimitsReturns([]string{rep.IOLimitKindDisk, rep.IOLimitKindNetwork})
+				})
+
+				It("allocates the work", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+						LRPs: []rep.LRP{limitedLRP},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(BeEmpty())
+					Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when work has security requirements", func() {
+			var privilegedLRP rep.LRP
+			var privilegedTask rep.Task
+
+			BeforeEach(func() {
+				privilegedLRP = successfulLRP.Copy()
+				privilegedLRP.Security = &rep.SecurityRequirements{Capabilities: []string{"CAP_NET_ADMIN"}}
+				privilegedTask = successfulTask
+				privilegedTask.Security = &rep.SecurityRequirements{SeccompProfile: "unconfined"}
+			})
+
+			It("fails the work the cell does not permit", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, privilegedLRP},
+					Tasks: []rep.Task{privilegedTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(privilegedLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(privilegedTask))
+				Expect(logger).To(Say("rejecting-lrp-with-unpermitted-security"))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(successfulLRP))
+			})
+
+			Context("when the cell permits them", func() {
+				BeforeEach(func() {
+					securityPermissions = rep.SecurityPermissions{
+						Capabilities:    []string{"CAP_NET_ADMIN"},
+						SeccompProfiles: []string{"unconfined"},
+					}
+				})
+
+				It("allocates the work", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+						LRPs:  []rep.LRP{privilegedLRP},
+						Tasks: []rep.Task{privilegedTask},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(BeEmpty())
+					Expect(failedWork.Tasks).To(BeEmpty())
+					_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+					Expect(lrpRequests).To(ConsistOf(privilegedLRP))
+				})
+			})
+		})
+
+		Context("when work has invalid registry credentials", func() {
+			var invalidLRP rep.LRP
+			var invalidTask rep.Task
+
+			BeforeEach(func() {
+				invalidLRP = successfulLRP.Copy()
+				invalidLRP.InstanceGUID = "ig-invalid"
+				invalidLRP.Index = 3
+				invalidLRP.Registry = &rep.RegistryCredentials{Username: "user"}
+				invalidTask = successfulTask
+				invalidTask.TaskGuid = "tg-invalid"
+				invalidTask.Registry = &rep.RegistryCredentials{Username: "user", Password: "secret", CACertificates: "not a certificate"}
+			})
+
+			It("does not allocate it", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, invalidLRP},
+					Tasks: []rep.Task{successfulTask, invalidTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(successfulLRP))
+				_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(ConsistOf(successfulTask))
+			})
+
+			It("returns it as failed work with the validation failures", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, invalidLRP},
+					Tasks: []rep.Task{successfulTask, invalidTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(invalidLRP))
+				redactedTask := invalidTask
+				redactedTask.Registry = &rep.RegistryCredentials{Username: "user", Password: "[REDACTED]", CACertificates: "not a certificate"}
+				Expect(failedWork.Tasks).To(ConsistOf(redactedTask))
+				Expect(failedWork.RegistryValidationFailures).To(ConsistOf(
+					rep.RegistryValidationFailure{
+						InstanceGUID: "ig-invalid",
+						Error:        rep.RegistryValidationError{Reason: rep.RegistryIncompleteCredentials, Message: "a username and a password are both required"},
+					},
+					rep.RegistryValidationFailure{
+						TaskGuid: "tg-invalid",
+						Error:    rep.RegistryValidationError{Reason: rep.RegistryInvalidCertificate, Message: "the certificate authorities are not PEM encoded"},
+					},
+				))
+			})
+		})
+
+		Context("when the work is performed with a trace context", func() {
+			It("records the trace context on the work", func() {
+				trace := &rep.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+				ctx := rep.WithTraceContext(context.Background(), trace)
+
+				_, err := cellRep.Perform(ctx, logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP},
+					Tasks: []rep.Task{successfulTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(HaveLen(1))
+				Expect(lrpRequests[0].TraceContext).To(Equal(trace))
+				_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(HaveLen(1))
+				Expect(taskRequests[0].TraceContext).To(Equal(trace))
+			})
+
+			It("records the id of the trace on the provenance of the work", func() {
+				trace := &rep.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+				ctx := rep.WithTraceContext(context.Background(), trace)
+				successfulLRP.Provenance = &rep.Provenance{AuctionID: "auction-1", AuctioneerID: "auctioneer-0", Attempt: 1}
+
+				_, err := cellRep.Perform(ctx, logger, rep.Work{
+					LRPs: []rep.LRP{successfulLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(HaveLen(1))
+				Expect(lrpRequests[0].Provenance).To(Equal(&rep.Provenance{
+					AuctionID:    "auction-1",
+					AuctioneerID: "auctioneer-0",
+					Attempt:      1,
+					TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+				}))
+			})
+		})
+
+		Context("when the image of work cannot fit on the cell", func() {
+			var oversizedLRP rep.LRP
+			var oversizedTask rep.Task
+
+			BeforeEach(func() {
+				oversizedLRP = successfulLRP.Copy()
+				oversizedLRP.InstanceGUID = "ig-oversized"
+				oversizedLRP.Index = 2
+				oversizedLRP.RootFs = "docker:///cloudfoundry/huge"
+				oversizedTask = successfulTask
+				oversizedTask.TaskGuid = "tg-oversized"
+				oversizedTask.RootFs = "docker:///cloudfoundry/huge"
+
+				imageSizeChecker = new(imagecachefakes.FakeSizeChecker)
+				imageSizeChecker.CheckStub = func(_ context.Context, _ lager.Logger, images []imagecache.ImageCheck) []*rep.ImageTooLargeError {
+					results := make([]*rep.ImageTooLargeError, len(images))
+					for i := range images {
+						if images[i].RootFS == "docker:///cloudfoundry/huge" {
+							results[i] = &rep.ImageTooLargeError{RequiredMB: 20000, AvailableMB: 8000}
+						}
+					}
+					return results
+				}
+			})
+
+			It("returns it as failed work with the size failures, checking all the images at once", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, oversizedLRP},
+					Tasks: []rep.Task{successfulTask, oversizedTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(oversizedLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(oversizedTask))
+				Expect(failedWork.ImageSizeFailures).To(ConsistOf(
+					rep.ImageSizeFailure{InstanceGUID: "ig-oversized", Error: rep.ImageTooLargeError{RequiredMB: 20000, AvailableMB: 8000}},
+					rep.ImageSizeFailure{TaskGuid: "tg-oversized", Error: rep.ImageTooLargeError{RequiredMB: 20000, AvailableMB: 8000}},
+				))
+				Expect(imageSizeChecker.CheckCallCount()).To(Equal(1))
+				_, _, images := imageSizeChecker.CheckArgsForCall(0)
+				Expect(images).To(Equal([]imagecache.ImageCheck{
+					{RootFS: successfulLRP.RootFs, Registry: successfulLRP.Registry},
+					{RootFS: "docker:///cloudfoundry/huge", Registry: oversizedLRP.Registry},
+					{RootFS: successfulTask.RootFs, Registry: successfulTask.Registry},
+					{RootFS: "docker:///cloudfoundry/huge", Registry: oversizedTask.Registry},
+				}))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(successfulLRP))
+			})
+
+			Context("when the image size cannot be checked", func() {
+				BeforeEach(func() {
+					imageSizeChecker.CheckStub = nil
+					imageSizeChecker.CheckReturns([]*rep.ImageTooLargeError{nil})
+				})
+
+				It("performs the work", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{oversizedLRP}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when the cell requires images referenced by digest", func() {
+			var taggedLRP rep.LRP
+			var taggedTask rep.Task
+
+			BeforeEach(func() {
+				taggedLRP = successfulLRP.Copy()
+				taggedLRP.InstanceGUID = "ig-tagged"
+				taggedLRP.Index = 7
+				taggedLRP.RootFs = "docker:///cloudfoundry/grace#v1"
+				taggedTask = successfulTask
+				taggedTask.TaskGuid = "tg-tagged"
+				taggedTask.RootFs = "docker:///cloudfoundry/grace#v1"
+
+				imageDigestPolicy = new(imagecachefakes.FakeDigestPolicy)
+				imageDigestPolicy.CheckStub = func(_ lager.Logger, rootfs string) error {
+					if rootfs == "docker:///cloudfoundry/grace#v1" {
+						return rep.ErrImageDigestRequired
+					}
+					return nil
+				}
+			})
+
+			It("returns the work with undigested images as failed work with the digest failures", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, taggedLRP},
+					Tasks: []rep.Task{successfulTask, taggedTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(taggedLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(taggedTask))
+				Expect(failedWork.ImageDigestFailures).To(ConsistOf(
+					rep.ImageDigestFailure{InstanceGUID: "ig-tagged", Error: rep.ErrImageDigestRequired.Error()},
+					rep.ImageDigestFailure{TaskGuid: "tg-tagged", Error: rep.ErrImageDigestRequired.Error()},
+				))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(successfulLRP))
+			})
+
+			It("keeps advertising the docker provider older auctioneers understand", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.RootFSProviders["docker"]).To(Equal(rep.ArbitraryRootFSProvider{}))
+			})
+		})
+
+		Context("when work is grouped", func() {
+			var groupedLRP rep.LRP
+			var groupedTask rep.Task
+
+			BeforeEach(func() {
+				groupedLRP = successfulLRP.Copy()
+				groupedLRP.InstanceGUID = "ig-grouped"
+				groupedLRP.Index = 8
+				groupedLRP.Group = "web-with-migration"
+				groupedTask = successfulTask
+				groupedTask.TaskGuid = "tg-grouped"
+				groupedTask.Group = "web-with-migration"
+			})
+
+			Context("when a member is turned down before allocation", func() {
+				BeforeEach(func() {
+					groupedLRP.InitSteps = []rep.InitStep{{Name: "migrate"}}
+				})
+
+				It("does not allocate the other members", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+						LRPs:  []rep.LRP{successfulLRP, groupedLRP},
+						Tasks: []rep.Task{groupedTask},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(ConsistOf(groupedLRP))
+					Expect(failedWork.Tasks).To(ConsistOf(groupedTask))
+					Expect(failedWork.WorkGroupFailures).To(ConsistOf(
+						rep.WorkGroupFailure{Group: "web-with-migration", TaskGuid: "tg-grouped", Error: rep.ErrWorkGroupIncomplete.Error()},
+					))
+
+					_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+					Expect(taskRequests).To(BeEmpty())
+				})
+			})
+
+			Context("when a member fails to be allocated", func() {
+				var claimsReleased chan struct{}
+
+				BeforeEach(func() {
+					fakeContainerAllocator.BatchLRPAllocationRequestStub = func(lager.Logger, bool, int, []rep.LRP) ([]rep.LRP, map[string]string) {
+						claimsReleased = make(chan struct{})
+						go func() {
+							workGroupHolds.Wait("web-with-migration")
+							close(claimsReleased)
+						}()
+						return nil, map[string]string{
+							successfulLRP.Identifier(): "other-container",
+							groupedLRP.Identifier():    "grouped-container",
+						}
+					}
+					fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{groupedTask})
+				})
+
+				It("releases the containers allocated for the other members before they are claimed", func() {
+					client.DeleteContainerStub = func(lager.Logger, string) error {
+						Expect(claimsReleased).NotTo(BeClosed())
+						return nil
+					}
+
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+						LRPs:  []rep.LRP{successfulLRP, groupedLRP},
+						Tasks: []rep.Task{groupedTask},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(ConsistOf(groupedLRP))
+					Expect(failedWork.Tasks).To(ConsistOf(groupedTask))
+					Expect(failedWork.WorkGroupFailures).To(ConsistOf(
+						rep.WorkGroupFailure{Group: "web-with-migration", InstanceGUID: "ig-grouped", Error: rep.ErrWorkGroupIncomplete.Error()},
+					))
+
+					Expect(client.DeleteContainerCallCount()).To(Equal(1))
+					_, guid := client.DeleteContainerArgsForCall(0)
+					Expect(guid).To(Equal("grouped-container"))
+					Eventually(claimsReleased).Should(BeClosed())
+				})
+
+				Context("when a container fails to be released", func() {
+					BeforeEach(func() {
+						client.DeleteContainerReturns(errors.New("boom"))
+					})
+
+					It("leaves its member placed on the cell", func() {
+						failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+							LRPs:  []rep.LRP{successfulLRP, groupedLRP},
+							Tasks: []rep.Task{groupedTask},
+						})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(failedWork.LRPs).To(BeEmpty())
+						Expect(failedWork.Tasks).To(ConsistOf(groupedTask))
+						Expect(failedWork.WorkGroupFailures).To(BeEmpty())
+					})
+				})
+			})
+		})
+
+		Context("when an LRP has an invalid init step", func() {
+			var invalidLRP rep.LRP
+
+			BeforeEach(func() {
+				invalidLRP = successfulLRP.Copy()
+				invalidLRP.InstanceGUID = "ig-invalid"
+				invalidLRP.Index = 3
+				invalidLRP.InitSteps = []rep.InitStep{{Name: "migrate"}}
+			})
+
+			It("returns it as failed work without allocating it", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{successfulLRP, invalidLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(invalidLRP))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(successfulLRP))
+			})
+		})
+
+		Context("when an LRP has an init step outside of the allowed paths", func() {
+			var allowedLRP, disallowedLRP rep.LRP
+
+			BeforeEach(func() {
+				initStepPaths = []string{"/bin/migrate", "/home/vcap/app/bin/"}
+
+				allowedLRP = successfulLRP.Copy()
+				allowedLRP.InstanceGUID = "ig-allowed"
+				allowedLRP.Index = 3
+				allowedLRP.InitSteps = []rep.InitStep{
+					{Name: "migrate", Path: "/bin/migrate"},
+					{Name: "warm", Path: "/home/vcap/app/bin/warm"},
+				}
+
+				disallowedLRP = successfulLRP.Copy()
+				disallowedLRP.InstanceGUID = "ig-disallowed"
+				disallowedLRP.Index = 4
+				disallowedLRP.InitSteps = []rep.InitStep{{Name: "shell", Path: "/bin/sh"}}
+			})
+
+			It("returns it as failed work without allocating it", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{allowedLRP, disallowedLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(disallowedLRP))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(allowedLRP))
+			})
+		})
+
+		Context("when work requires a lifecycle the cell does not have", func() {
+			var buildpackLRP rep.LRP
+			var dockerTask rep.Task
+
+			BeforeEach(func() {
+				lifecycleCatalog = new(lifecyclesfakes.FakeCatalog)
+				lifecycleCatalog.AvailableReturns([]rep.Lifecycle{{Name: "buildpack", Version: "1.2.3"}})
+
+				buildpackLRP = successfulLRP.Copy()
+				buildpackLRP.InstanceGUID = "ig-buildpack"
+				buildpackLRP.Index = 5
+				buildpackLRP.RequiredLifecycles = []string{"buildpack/1.2.3"}
+				dockerTask = successfulTask
+				dockerTask.TaskGuid = "tg-docker"
+				dockerTask.RequiredLifecycles = []string{"buildpack", "docker"}
+			})
+
+			It("returns it as failed work without allocating it", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{buildpackLRP},
+					Tasks: []rep.Task{dockerTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(BeEmpty())
+				Expect(failedWork.Tasks).To(ConsistOf(dockerTask))
+				Expect(failedWork.LifecycleFailures).To(ConsistOf(rep.LifecycleFailure{
+					TaskGuid: "tg-docker",
+					Error:    rep.LifecycleUnavailableError{Lifecycle: "docker"},
+				}))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(buildpackLRP))
+			})
+
+			It("advertises the lifecycles it has in its state", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Lifecycles).To(Equal([]rep.Lifecycle{{Name: "buildpack", Version: "1.2.3"}}))
+			})
+		})
+
+		Context("when work is directed to the cell", func() {
+			var directedLRP rep.LRP
+			var directedTask rep.Task
+
+			BeforeEach(func() {
+				directedLRP = successfulLRP.Copy()
+				directedLRP.InstanceGUID = "ig-directed"
+				directedLRP.Index = 4
+				directedLRP.Resource = rep.NewResource(1024, 1024, 10)
+				directedLRP.Directed = &rep.DirectedPlacement{CellID: cellID, RequestedBy: "ops-pinning-tool", Reason: "hardware canary"}
+				directedTask = successfulTask
+				directedTask.TaskGuid = "tg-directed"
+				directedTask.Resource = rep.NewResource(1024, 1024, 10)
+				directedTask.Directed = &rep.DirectedPlacement{CellID: cellID, RequestedBy: "ops-pinning-tool"}
+			})
+
+			JustBeforeEach(func() {
+				client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1536, DiskMB: 4096, Containers: 10}, nil)
+				client.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 4096, DiskMB: 4096, Containers: 10}, nil)
+			})
+
+			It("performs the work that fits", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{directedLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(BeEmpty())
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(directedLRP))
+			})
+
+			It("fails the work that no longer fits once the rest of the directed work is placed", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{directedLRP},
+					Tasks: []rep.Task{directedTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.Tasks).To(ConsistOf(directedTask))
+				Expect(failedWork.DirectedPlacementFailures).To(ConsistOf(rep.DirectedPlacementFailure{
+					TaskGuid: "tg-directed",
+					Error:    rep.InsufficientResourcesError{Problems: map[string]struct{}{"memory": {}}}.Error(),
+				}))
+			})
+
+			It("fails the work directed to another cell", func() {
+				directedLRP.Directed.CellID = "other-cell"
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{directedLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(directedLRP))
+				Expect(failedWork.DirectedPlacementFailures).To(ConsistOf(rep.DirectedPlacementFailure{
+					InstanceGUID: "ig-directed",
+					Error:        rep.ErrDirectedToAnotherCell.Error(),
+				}))
+			})
+
+			It("fails the work not requested with a client certificate", func() {
+				directedTask.Directed.RequestedBy = ""
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					Tasks: []rep.Task{directedTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.Tasks).To(ConsistOf(directedTask))
+				Expect(failedWork.DirectedPlacementFailures).To(HaveLen(1))
+				Expect(failedWork.DirectedPlacementFailures[0].Error).To(Equal(rep.ErrDirectedPlacementUnattributed.Error()))
+			})
+
+			Context("when the cell is dedicated to work with placement tags", func() {
+				BeforeEach(func() {
+					placementTags = []string{"pci"}
+				})
+
+				It("fails the work without the tags", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+						LRPs: []rep.LRP{directedLRP},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(ConsistOf(directedLRP))
+					Expect(failedWork.DirectedPlacementFailures).To(ConsistOf(rep.DirectedPlacementFailure{
+						InstanceGUID: "ig-directed",
+						Error:        rep.ErrPlacementTagsMismatch.Error(),
+					}))
+				})
+			})
+
+			It("fails the work whose volume drivers the cell does not advertise", func() {
+				client.VolumeDriversReturns([]string{"nfs"}, nil)
+				directedLRP.VolumeDrivers = []string{"cephfs"}
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs: []rep.LRP{directedLRP},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(directedLRP))
+				Expect(failedWork.DirectedPlacementFailures).To(ConsistOf(rep.DirectedPlacementFailure{
+					InstanceGUID: "ig-directed",
+					Error:        rep.ErrorIncompatibleVolumeDrivers.Error(),
+				}))
+			})
+		})
+
+		Context("when work requests static host ports", func() {
+			var lrp, conflictingLRP, secondLRP rep.LRP
+			var task rep.Task
+
+			BeforeEach(func() {
+				lrpContainer := createContainer(executor.StateRunning, rep.LRPLifecycle)
+				lrpContainer.Ports = []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001}}
+				taskContainer := createContainer(executor.StateReserved, rep.TaskLifecycle)
+				taskContainer.Guid = "some-task-guid"
+				taskContainer.Tags[rep.StaticHostPortsTag] = "[9000]"
+				client.ListContainersReturns([]executor.Container{lrpContainer, taskContainer}, nil)
+
+				lrp = rep.NewLRP("ig-ports-1", models.NewActualLRPKey("pg-ports", 0, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{})
+				lrp.StaticHostPorts = []uint16{8000}
+				conflictingLRP = rep.NewLRP("ig-ports-2", models.NewActualLRPKey("pg-ports", 1, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{})
+				conflictingLRP.StaticHostPorts = []uint16{61001, 8001}
+				secondLRP = rep.NewLRP("ig-ports-3", models.NewActualLRPKey("pg-ports", 2, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{})
+				secondLRP.StaticHostPorts = []uint16{8000}
+				task = rep.NewTask("tg-ports", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{})
+				task.StaticHostPorts = []uint16{9000}
+			})
+
+			It("fails the work requesting host ports the cell or the work placed before it holds", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{lrp, conflictingLRP, secondLRP},
+					Tasks: []rep.Task{task},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(conflictingLRP, secondLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(task))
+				Expect(failedWork.HostPortConflictFailures).To(ConsistOf(
+					rep.HostPortConflictFailure{InstanceGUID: "ig-ports-2", Error: rep.HostPortConflictError{Ports: []uint16{61001}}},
+					rep.HostPortConflictFailure{InstanceGUID: "ig-ports-3", Error: rep.HostPortConflictError{Ports: []uint16{8000}}},
+					rep.HostPortConflictFailure{TaskGuid: "tg-ports", Error: rep.HostPortConflictError{Ports: []uint16{9000}}},
+				))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(lrp))
+				_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(BeEmpty())
+			})
+
+			It("checks the work against one state of the cell", func() {
+				lrp.Directed = &rep.DirectedPlacement{CellID: cellID, RequestedBy: "operator", Reason: "pin"}
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{lrp}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.ListContainersCallCount()).To(Equal(1))
+			})
+
+			Context("when a concurrent perform holds the host ports", func() {
+				var release chan struct{}
+
+				BeforeEach(func() {
+					release = make(chan struct{})
+					fakeContainerAllocator.BatchLRPAllocationRequestStub = func(_ lager.Logger, _ bool, _ int, lrps []rep.LRP) ([]rep.LRP, map[string]string) {
+						for _, requested := range lrps {
+							if requested.InstanceGUID == lrp.InstanceGUID {
+								<-release
+							}
+						}
+						return nil, nil
+					}
+				})
+
+				It("fails the work requesting them until that perform is done", func() {
+					performed := make(chan struct{})
+					go func() {
+						defer GinkgoRecover()
+						defer close(performed)
+						_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{lrp}})
+						Expect(err).NotTo(HaveOccurred())
+					}()
+					Eventually(fakeContainerAllocator.BatchLRPAllocationRequestCallCount).Should(Equal(1))
+
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{secondLRP}})
+					Expect(err).NotTo(HaveOccurred())
+					close(release)
+					Eventually(performed).Should(BeClosed())
+
+					Expect(failedWork.LRPs).To(ConsistOf(secondLRP))
+					Expect(failedWork.HostPortConflictFailures).To(ConsistOf(
+						rep.HostPortConflictFailure{InstanceGUID: "ig-ports-3", Error: rep.HostPortConflictError{Ports: []uint16{8000}}},
+					))
+
+					failedWork, err = cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{secondLRP}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.HostPortConflictFailures).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when the cell has a placement policy", func() {
+			BeforeEach(func() {
+				placementPolicy = new(placementpolicyfakes.FakePolicy)
+				placementPolicy.AdmitStub = func(ctx context.Context, logger lager.Logger, input placementpolicy.Input) error {
+					if input.Task != nil && input.Task.TaskGuid == unsuccessfulTask.TaskGuid {
+						return rep.PolicyDeniedError{Reasons: []string{"no tasks from ig-2"}}
+					}
+					if input.LRP != nil && len(input.Cell.LRPs) > 0 {
+						return rep.PolicyDeniedError{Reasons: []string{"one LRP per cell"}}
+					}
+					return nil
+				}
+			})
+
+			It("fails the work the policy denies, evaluating each against the cell with the work admitted before it", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{successfulLRP, unsuccessfulLRP},
+					Tasks: []rep.Task{successfulTask, unsuccessfulTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(unsuccessfulLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(unsuccessfulTask))
+				Expect(failedWork.PolicyFailures).To(ConsistOf(
+					rep.PolicyFailure{InstanceGUID: "ig-2", Error: "denied by the placement policy of the cell: one LRP per cell"},
+					rep.PolicyFailure{TaskGuid: "ig-2", Error: "denied by the placement policy of the cell: no tasks from ig-2"},
+				))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(successfulLRP))
+				_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(ConsistOf(successfulTask))
+			})
+
+			It("passes the state of the cell to the policy", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{successfulLRP}})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(placementPolicy.AdmitCallCount()).To(Equal(1))
+				_, _, input := placementPolicy.AdmitArgsForCall(0)
+				Expect(input.LRP).To(Equal(&successfulLRP))
+				Expect(input.Cell.CellID).To(Equal(cellID))
+			})
+
+			It("fails the work when the policy cannot be evaluated", func() {
+				placementPolicy.AdmitStub = nil
+				placementPolicy.AdmitReturns(errors.New("bundle exploded"))
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{successfulLRP}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(successfulLRP))
+				Expect(failedWork.PolicyFailures).To(ConsistOf(rep.PolicyFailure{InstanceGUID: "ig-1", Error: "bundle exploded"}))
+			})
+		})
+
+		Context("when the cell is a Windows cell", func() {
+			var lrp rep.LRP
+
+			BeforeEach(func() {
+				osFamily = rep.OSFamilyWindows
+				imageOverhead = rep.Resource{MemoryMB: 100}
+				remainingCellMemory = 1000
+				lrp = rep.NewLRP("ig-1", models.NewActualLRPKey("process-guid", 0, "domain"), rep.NewResource(950, 10, 10), rep.PlacementConstraint{RootFs: linuxRootFSURL})
+			})
+
+			It("rejects LRPs that do not fit together with the image overhead", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{lrp}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(lrp))
+
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(BeEmpty())
+			})
+		})
+
+		Context("when the cell has additional backends", func() {
+			var (
+				windowsClient    *fake_client.FakeClient
+				windowsAllocator *fakes.FakeBatchContainerAllocator
+				linuxLRP         rep.LRP
+				windowsLRP       rep.LRP
+				linuxTask        rep.Task
+				windowsTask      rep.Task
+			)
+
+			BeforeEach(func() {
+				windowsClient = new(fake_client.FakeClient)
+				windowsClient.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1024}, nil)
+				windowsAllocator = new(fakes.FakeBatchContainerAllocator)
+
+				additionalBackends = []auctioncellrep.Backend{
+					auctioncellrep.NewBackend(
+						"windows",
+						windowsClient,
+						nil,
+						rep.StackPathMap{"windows": "/data/rootfs/windows"},
+						nil,
+						windowsAllocator,
+					),
+				}
+
+				linuxLRP = rep.NewLRP("ig-1", models.NewActualLRPKey("process-guid", 0, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: linuxRootFSURL})
+				windowsLRP = rep.NewLRP("ig-2", models.NewActualLRPKey("process-guid", 1, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: models.PreloadedRootFS("windows")})
+				linuxTask = rep.NewTask("tg-1", "domain", rep.Resource{}, rep.PlacementConstraint{RootFs: linuxRootFSURL})
+				windowsTask = rep.NewTask("tg-2", "domain", rep.Resource{}, rep.PlacementConstraint{RootFs: models.PreloadedRootFS("windows")})
+			})
+
+			It("sends the work to the backend that provides its rootfs", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{linuxLRP, windowsLRP},
+					Tasks: []rep.Task{linuxTask, windowsTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+				_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(linuxLRP))
+				_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(ConsistOf(linuxTask))
+
+				Expect(windowsAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+				_, _, _, lrpRequests = windowsAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(lrpRequests).To(ConsistOf(windowsLRP))
+				_, taskRequests = windowsAllocator.BatchTaskAllocationRequestArgsForCall(0)
+				Expect(taskRequests).To(ConsistOf(windowsTask))
+			})
+
+			It("returns the work that failed on any backend", func() {
+				windowsAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{windowsLRP}, nil)
+				fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{linuxTask})
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{linuxLRP, windowsLRP},
+					Tasks: []rep.Task{linuxTask, windowsTask},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(windowsLRP))
+				Expect(failedWork.Tasks).To(ConsistOf(linuxTask))
+			})
+
+			It("does not query backends that received no work", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{linuxLRP}})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(windowsClient.RemainingResourcesCallCount()).To(Equal(0))
+				Expect(windowsAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(0))
+			})
+
+			Context("when the backend does not have enough memory", func() {
+				BeforeEach(func() {
+					windowsClient.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 5}, nil)
+				})
+
+				It("rejects the LRPs without requesting allocation", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{linuxLRP, windowsLRP}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(ConsistOf(windowsLRP))
+
+					_, _, _, lrpRequests := windowsAllocator.BatchLRPAllocationRequestArgsForCall(0)
+					Expect(lrpRequests).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when evacuating", func() {
+			BeforeEach(func() {
+				evacuationReporter.EvacuatingReturns(true)
+
+				lrp := rep.NewLRP(
+					"ig-1",
+					models.NewActualLRPKey("process-guid", 1, "tests"),
+					rep.NewResource(2048, 1024, 100),
+					rep.NewPlacementConstraint(linuxRootFSURL, nil, []string{}),
+				)
+
+				task := rep.NewTask(
+					"the-task-guid",
+					"tests",
+					rep.NewResource(2048, 1024, 100),
+					rep.NewPlacementConstraint(linuxRootFSURL, nil, []string{}),
+				)
+
+				work = rep.Work{
+					LRPs:  []rep.LRP{lrp},
+					Tasks: []rep.Task{task},
+				}
+			})
+
+			It("returns all work it was given", func() {
+				Expect(cellRep.Perform(context.Background(), logger, work)).To(Equal(work))
+			})
+		})
+
+		Context("when in maintenance", func() {
+			BeforeEach(func() {
+				maintenanceReporter.InMaintenanceReturns(true)
+
+				work = rep.Work{
+					LRPs:  []rep.LRP{successfulLRP},
+					Tasks: []rep.Task{successfulTask},
+				}
+			})
+
+			It("returns all work it was given", func() {
+				Expect(cellRep.Perform(context.Background(), logger, work)).To(Equal(work))
+			})
+
+			It("does not allocate any containers", func() {
+				cellRep.Perform(context.Background(), logger, work)
+				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(0))
+				Expect(fakeContainerAllocator.BatchTaskAllocationRequestCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when cordoned", func() {
+			BeforeEach(func() {
+				cordonReporter.CordonedReturns(true)
+
+				work = rep.Work{
+					LRPs:  []rep.LRP{successfulLRP},
+					Tasks: []rep.Task{successfulTask},
+				}
+			})
+
+			It("returns all work it was given without allocating any containers", func() {
+				Expect(cellRep.Perform(context.Background(), logger, work)).To(Equal(work))
+				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(0))
+				Expect(fakeContainerAllocator.BatchTaskAllocationRequestCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the context is done", func() {
+			var ctx context.Context
+
+			BeforeEach(func() {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(context.Background())
+				cancel()
+
+				work = rep.Work{
+					LRPs:  []rep.LRP{successfulLRP},
+					Tasks: []rep.Task{successfulTask},
+				}
+			})
+
+			It("returns all work it was given without allocating it", func() {
+				failedWork, err := cellRep.Perform(ctx, logger, work)
+				Expect(err).To(Equal(context.Canceled))
+				Expect(failedWork).To(Equal(work))
+				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(0))
+				Expect(fakeContainerAllocator.BatchTaskAllocationRequestCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the cell only has enough resources to run a subset of the workloads", func() {
+			var smallestLRP, middleLRP, largestLRP rep.LRP
+
+			BeforeEach(func() {
+				remainingCellMemory = 8192
+				largestLRP = rep.LRP{Resource: rep.Resource{MemoryMB: 6144}}
+				middleLRP = rep.LRP{Resource: rep.Resource{MemoryMB: int32(remainingCellMemory) - largestLRP.MemoryMB}}
+				smallestLRP = rep.LRP{Resource: rep.Resource{MemoryMB: 1}}
+			})
+
+			It("allocates containers for the largest workloads it can run", func() {
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:  []rep.LRP{smallestLRP, middleLRP, largestLRP},
+					Tasks: []rep.Task{},
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(smallestLRP))
+
+				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+
+				_, proxyEnabledArg, proxyMemFootprintArg, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+				Expect(proxyEnabledArg).To(BeFalse())
+				Expect(proxyMemFootprintArg).To(Equal(12))
+				Expect(lrpRequests).To(ConsistOf(largestLRP, middleLRP))
+			})
+
+			Context("when envoy needs to be placed in the container", func() {
+				BeforeEach(func() {
+					enableContainerProxy = true
+					proxyMemoryAllocation = remainingCellMemory - int(largestLRP.MemoryMB)
+				})
+
+				It("accounts for the proxy overhead when determining which workloads to run and which to reject", func() {
+					failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+						LRPs:  []rep.LRP{smallestLRP, middleLRP, largestLRP},
+						Tasks: []rep.Task{},
+					})
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(ConsistOf(smallestLRP, middleLRP))
+
+					Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+
+					_, proxyEnabledArg, proxyMemFootprintArg, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+					Expect(proxyEnabledArg).To(BeTrue())
+					Expect(proxyMemFootprintArg).To(Equal(proxyMemoryAllocation))
+					Expect(lrpRequests).To(ConsistOf(largestLRP))
+				})
+
+				Context("when the proxy overhead feature flag is disabled", func() {
+					BeforeEach(func() {
+						featureFlags = featureflags.New(map[string]bool{featureflags.ProxyOverhead: false})
+					})
+
+					It("does not account for the proxy overhead", func() {
+						failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{
+							LRPs:  []rep.LRP{smallestLRP, middleLRP, largestLRP},
+							Tasks: []rep.Task{},
+						})
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(failedWork.LRPs).To(ConsistOf(smallestLRP))
+
+						_, proxyEnabledArg, _, _ := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+						Expect(proxyEnabledArg).To(BeFalse())
+					})
+				})
+			})
+		})
+
+		Context("when the workload's cell ID does not match the cell's ID", func() {
+			It("rejects the workload", func() {
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{
+					LRPs:   lrpAuctions,
+					CellID: "do-not-want-your-work",
+				})
+				Expect(err).To(MatchError(auctioncellrep.ErrCellIdMismatch))
+			})
+		})
+	})
+
+	Describe("Capacity reservations", func() {
+		var fakeClock *fakeclock.FakeClock
+
+		BeforeEach(func() {
+			fakeClock = fakeclock.NewFakeClock(time.Now())
+			reservations = auctioncellrep.NewCapacityReservations(fakeClock, 10*time.Minute)
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1000, DiskMB: 1000, Containers: 4}, nil)
+		})
+
+		reserve := func(memoryMB int32, instances int32, ttlSeconds int64) (rep.CapacityReservation, error) {
+			request := rep.NewCapacityReservationRequest("pg-new", rep.NewResource(memoryMB, memoryMB, 10), instances, ttlSeconds)
+			return cellRep.ReserveCapacity(logger, request)
+		}
+
+		It("holds the reserved capacity back from the state", func() {
+			reservation, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reservation.ExpiresAt).To(Equal(fakeClock.Now().Add(time.Minute).UnixNano()))
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.CapacityReservations).To(ConsistOf(reservation))
+			Expect(state.AvailableResources).To(Equal(rep.NewResources(400, 400, 2)))
+		})
+
+		It("rejects reservations the cell does not have the capacity for", func() {
+			_, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reserve(300, 2, 60)
+			Expect(err).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"memory": {}, "disk": {}}}))
+		})
+
+		It("rejects invalid reservations", func() {
+			_, err := reserve(300, 0, 60)
+			Expect(err).To(Equal(rep.ErrInvalidCapacityReservation))
+		})
+
+		It("caps the ttl of reservations", func() {
+			reservation, err := reserve(300, 2, 3600)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reservation.ExpiresAt).To(Equal(fakeClock.Now().Add(10 * time.Minute).UnixNano()))
+		})
+
+		It("releases the capacity once the reservation expires", func() {
+			_, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeClock.Increment(time.Minute)
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.CapacityReservations).To(BeEmpty())
+			Expect(state.AvailableResources).To(Equal(rep.NewResources(1000, 1000, 4)))
+		})
+
+		It("releases the capacity of a released reservation", func() {
+			reservation, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cellRep.ReleaseCapacity(logger, reservation.ID)).To(Succeed())
+			Expect(cellRep.ReleaseCapacity(logger, reservation.ID)).To(Equal(auctioncellrep.ErrCapacityReservationNotFound))
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.CapacityReservations).To(BeEmpty())
+		})
+
+		It("lets only the instances held by a reservation use the reserved capacity", func() {
+			_, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			reservedLRP := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-new", 0, "domain"), rep.NewResource(300, 300, 10), rep.PlacementConstraint{})
+			otherLRP := rep.NewLRP("ig-2", models.NewActualLRPKey("pg-other", 0, "domain"), rep.NewResource(500, 500, 10), rep.PlacementConstraint{})
+
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{reservedLRP, otherLRP}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.LRPs).To(ConsistOf(otherLRP))
+
+			_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+			Expect(lrpRequests).To(ConsistOf(reservedLRP))
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.CapacityReservations).To(HaveLen(1))
+			Expect(state.CapacityReservations[0].Instances).To(Equal(int32(1)))
+		})
+
+		It("keeps tasks from the reserved capacity", func() {
+			_, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			largeTask := rep.NewTask("tg-large", "domain", rep.NewResource(500, 100, 10), rep.PlacementConstraint{})
+			smallTask := rep.NewTask("tg-small", "domain", rep.NewResource(300, 100, 10), rep.PlacementConstraint{})
+
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{Tasks: []rep.Task{largeTask, smallTask}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.Tasks).To(ConsistOf(largeTask))
+
+			_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
+			Expect(taskRequests).To(ConsistOf(smallTask))
+		})
+
+		It("keeps work from the reserved disk and containers", func() {
+			_, err := reserve(300, 2, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			diskLRP := rep.NewLRP("ig-disk", models.NewActualLRPKey("pg-other", 0, "domain"), rep.NewResource(100, 500, 10), rep.PlacementConstraint{})
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{diskLRP}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.LRPs).To(ConsistOf(diskLRP))
+		})
+
+		It("lets a held place be taken by only one of concurrent performs", func() {
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 300, DiskMB: 300, Containers: 4}, nil)
+			_, err := reserve(300, 1, 60)
+			Expect(err).NotTo(HaveOccurred())
+
+			firstLRP := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-new", 0, "domain"), rep.NewResource(300, 300, 10), rep.PlacementConstraint{})
+			secondLRP := rep.NewLRP("ig-2", models.NewActualLRPKey("pg-new", 1, "domain"), rep.NewResource(300, 300, 10), rep.PlacementConstraint{})
+
+			var concurrentlyFailed rep.Work
+			fakeContainerAllocator.BatchLRPAllocationRequestStub = func(_ lager.Logger, _ bool, _ int, lrps []rep.LRP) ([]rep.LRP, map[string]string) {
+				if fakeContainerAllocator.BatchLRPAllocationRequestCallCount() == 1 {
+					var err error
+					concurrentlyFailed, err = cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{secondLRP}})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				return nil, nil
+			}
+
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{firstLRP}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.LRPs).To(BeEmpty())
+			Expect(concurrentlyFailed.LRPs).To(ConsistOf(secondLRP))
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.CapacityReservations).To(BeEmpty())
+		})
+
+		Context("when the held instance is not allocated", func() {
+			var reservedLRP rep.LRP
+
+			BeforeEach(func() {
+				_, err := reserve(300, 2, 60)
+				Expect(err).NotTo(HaveOccurred())
+
+				reservedLRP = rep.NewLRP("ig-1", models.NewActualLRPKey("pg-new", 0, "domain"), rep.NewResource(300, 300, 10), rep.PlacementConstraint{})
+			})
+
+			expectReservationUnclaimed := func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.CapacityReservations).To(HaveLen(1))
+				Expect(state.CapacityReservations[0].Instances).To(Equal(int32(2)))
+			}
+
+			It("leaves the reservation unclaimed on a cordoned cell", func() {
+				cordonReporter.CordonedReturns(true)
+
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{reservedLRP}})
+				Expect(err).NotTo(HaveOccurred())
+				expectReservationUnclaimed()
+			})
+
+			It("leaves the reservation unclaimed on an evacuating cell", func() {
+				evacuationReporter.EvacuatingReturns(true)
+
+				_, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{reservedLRP}})
+				Expect(err).NotTo(HaveOccurred())
+				expectReservationUnclaimed()
+			})
+
+			It("leaves the reservation unclaimed when the allocation fails", func() {
+				fakeContainerAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{reservedLRP}, nil)
+
+				failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{reservedLRP}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(ConsistOf(reservedLRP))
+				expectReservationUnclaimed()
+			})
+		})
+
+		Context("when capacity reservations are not enabled", func() {
+			BeforeEach(func() {
+				reservations = nil
+			})
+
+			It("rejects reservations", func() {
+				_, err := reserve(300, 2, 60)
+				Expect(err).To(Equal(auctioncellrep.ErrCapacityReservationsDisabled))
+				Expect(cellRep.ReleaseCapacity(logger, "reservation-id")).To(Equal(auctioncellrep.ErrCapacityReservationsDisabled))
+			})
+		})
+	})
+
+	Describe("Maintenance windows", func() {
+		var (
+			fakeClock *fakeclock.FakeClock
+			past      rep.MaintenanceWindow
+			next      rep.MaintenanceWindow
+			later     rep.MaintenanceWindow
+		)
+
+		BeforeEach(func() {
+			fakeClock = fakeclock.NewFakeClock(time.Now())
+			now := fakeClock.Now()
+			past = rep.MaintenanceWindow{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}
+			next = rep.MaintenanceWindow{Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour)}
+			later = rep.MaintenanceWindow{Start: now.Add(48 * time.Hour), End: now.Add(49 * time.Hour)}
+			maintenanceSchedule = auctioncellrep.NewMaintenanceSchedule(fakeClock, []rep.MaintenanceWindow{later, past, next}, time.Hour, 0.25)
+		})
+
+		It("advertises the upcoming windows, earliest first", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.MaintenanceWindows).To(Equal([]rep.MaintenanceWindow{next, later}))
+			Expect(state.NextMaintenanceWindow()).To(Equal(&next))
+		})
+
+		It("does not penalize the cell before the lead time", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.MaintenanceScorePenalty).To(BeZero())
+		})
+
+		It("penalizes the cell within the lead time and during the window", func() {
+			fakeClock.Increment(2*time.Hour + time.Minute)
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.MaintenanceScorePenalty).To(Equal(0.25))
+
+			fakeClock.Increment(time.Hour)
+			state, _, err = cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.NextMaintenanceWindow()).To(Equal(&next))
+			Expect(state.MaintenanceScorePenalty).To(Equal(0.25))
+		})
+
+		Context("when every window has ended", func() {
+			BeforeEach(func() {
+				maintenanceSchedule = auctioncellrep.NewMaintenanceSchedule(fakeClock, []rep.MaintenanceWindow{past}, time.Hour, 0.25)
+			})
+
+			It("advertises no windows", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.MaintenanceWindows).To(BeNil())
+				Expect(state.NextMaintenanceWindow()).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Failure domains", func() {
+		It("advertises no failure domains by default", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.FailureDomains).To(BeNil())
+			Expect(state.FailureDomainPenalty).To(BeZero())
+		})
+
+		Context("when the cell belongs to failure domains", func() {
+			BeforeEach(func() {
+				failureDomains = []string{"array-a", "rack-r1"}
+				failureDomainPenalty = 0.2
+			})
+
+			It("advertises them with the penalty", func() {
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.FailureDomains).To(Equal([]string{"array-a", "rack-r1"}))
+				Expect(state.FailureDomainPenalty).To(Equal(0.2))
+			})
+		})
+	})
+
+	Describe("Crash loop quarantine", func() {
+		var quarantined, healthy rep.LRP
+
+		BeforeEach(func() {
+			crashLoopDetector = new(crashloopfakes.FakeDetector)
+			crashLoopDetector.QuarantinedStub = func(processGuid string, index int32) bool {
+				return processGuid == "pg-crashing" && index == 1
+			}
+			crashLoopDetector.QuarantinesReturns([]rep.QuarantinedLRP{{ProcessGUID: "pg-crashing", Index: 1, Until: 1234}})
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1000, DiskMB: 1000, Containers: 4}, nil)
+
+			quarantined = rep.NewLRP("ig-1", models.NewActualLRPKey("pg-crashing", 1, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: linuxRootFSURL})
+			healthy = rep.NewLRP("ig-2", models.NewActualLRPKey("pg-crashing", 0, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: linuxRootFSURL})
+		})
+
+		It("advertises the quarantined instances", func() {
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.QuarantinedLRPs).To(ConsistOf(rep.QuarantinedLRP{ProcessGUID: "pg-crashing", Index: 1, Until: 1234}))
+			Expect(state.Quarantined("pg-crashing", 1)).To(BeTrue())
+		})
+
+		It("refuses to start quarantined instances", func() {
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{quarantined, healthy}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.LRPs).To(ConsistOf(quarantined))
+
+			Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+			_, _, _, lrps := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+			Expect(lrps).To(ConsistOf(healthy))
+		})
+	})
 
-			Expect(fakeContainerAllocator.BatchTaskAllocationRequestCallCount()).To(Equal(1))
+	Describe("Placement blocks", func() {
+		var blockedLRP, otherLRP rep.LRP
+		var blockedTask rep.Task
+
+		BeforeEach(func() {
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1000, DiskMB: 1000, Containers: 4}, nil)
+			blockedLRP = rep.NewLRP("ig-1", models.NewActualLRPKey("noisy-pg", 0, "noisy-domain"), rep.NewResource(100, 100, 10), rep.PlacementConstraint{})
+			otherLRP = rep.NewLRP("ig-2", models.NewActualLRPKey("pg-other", 0, "domain"), rep.NewResource(100, 100, 10), rep.PlacementConstraint{})
+			blockedTask = rep.NewTask("tg-1", "noisy-domain", rep.NewResource(100, 100, 10), rep.PlacementConstraint{})
+		})
+
+		It("surfaces the blocks in the state until they expire", func() {
+			block, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", Reason: "noisy neighbour", TTLSeconds: 60})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(block.ExpiresAt).To(Equal(repClock.Now().Add(time.Minute).UnixNano()))
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.PlacementBlocks).To(ConsistOf(block))
+			Expect(cellRep.PlacementBlocks(logger)).To(ConsistOf(block))
+
+			repClock.Increment(time.Minute)
+
+			state, _, err = cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.PlacementBlocks).To(BeEmpty())
+		})
+
+		It("rejects invalid blocks", func() {
+			_, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", Domain: "noisy-domain", TTLSeconds: 60})
+			Expect(err).To(Equal(rep.ErrInvalidPlacementBlock))
+		})
+
+		It("rejects the blocked work at perform", func() {
+			_, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{Domain: "noisy-domain", TTLSeconds: 60})
+			Expect(err).NotTo(HaveOccurred())
+
+			failedWork, err := cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{blockedLRP, otherLRP}, Tasks: []rep.Task{blockedTask}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork.LRPs).To(ConsistOf(blockedLRP))
+			Expect(failedWork.Tasks).To(ConsistOf(blockedTask))
+
+			_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+			Expect(lrpRequests).To(ConsistOf(otherLRP))
 			_, taskRequests := fakeContainerAllocator.BatchTaskAllocationRequestArgsForCall(0)
-			Expect(taskRequests).To(ConsistOf(successfulTask, unsuccessfulTask))
+			Expect(taskRequests).To(BeEmpty())
 		})
 
-		It("returns LRPs and Tasks that could not be allocated", func() {
-			fakeContainerAllocator.BatchLRPAllocationRequestReturns([]rep.LRP{unsuccessfulLRP})
-			fakeContainerAllocator.BatchTaskAllocationRequestReturns([]rep.Task{unsuccessfulTask})
+		It("accepts the work again once the block is lifted", func() {
+			block, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", TTLSeconds: 60})
+			Expect(err).NotTo(HaveOccurred())
 
-			failedWork, err := cellRep.Perform(logger, rep.Work{
-				LRPs:  []rep.LRP{successfulLRP, unsuccessfulLRP},
-				Tasks: []rep.Task{successfulTask, unsuccessfulTask},
+			Expect(cellRep.UnblockPlacement(logger, block.ID)).To(Succeed())
+			Expect(cellRep.UnblockPlacement(logger, block.ID)).To(Equal(auctioncellrep.ErrPlacementBlockNotFound))
+
+			_, err = cellRep.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{blockedLRP}})
+			Expect(err).NotTo(HaveOccurred())
+			_, _, _, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
+			Expect(lrpRequests).To(ConsistOf(blockedLRP))
+		})
+
+		Context("when the block is given a deadline", func() {
+			var fakeMetronClient *mfakes.FakeIngressClient
+
+			BeforeEach(func() {
+				fakeMetronClient = new(mfakes.FakeIngressClient)
+				clockSkew = clockskew.NewMonitor(fakeMetronClient, repClock, time.Second)
+			})
+
+			It("blocks until the deadline on the clock of the cell when the clocks agree", func() {
+				deadline := rep.NewDeadline(repClock.Now().Add(time.Minute), repClock.Now().Add(-500*time.Millisecond))
+
+				block, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", Deadline: &deadline})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(block.ExpiresAt).To(Equal(repClock.Now().Add(time.Minute).UnixNano()))
+				Expect(fakeMetronClient.SendDurationCallCount()).To(Equal(0))
+			})
+
+			It("measures the deadline on the clock of the caller and emits the skew when the clocks disagree", func() {
+				callerNow := repClock.Now().Add(-time.Hour)
+				deadline := rep.NewDeadline(callerNow.Add(time.Minute), callerNow)
+
+				block, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", Deadline: &deadline})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(block.ExpiresAt).To(Equal(repClock.Now().Add(time.Minute).UnixNano()))
+
+				Expect(fakeMetronClient.SendDurationCallCount()).To(Equal(1))
+				name, value, _ := fakeMetronClient.SendDurationArgsForCall(0)
+				Expect(name).To(Equal("ClockSkew"))
+				Expect(value).To(Equal(time.Hour))
+			})
+
+			It("rejects a deadline that has already passed", func() {
+				deadline := rep.NewDeadline(repClock.Now().Add(-time.Minute), repClock.Now())
+
+				_, err := cellRep.BlockPlacement(logger, rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", Deadline: &deadline})
+				Expect(err).To(Equal(rep.ErrDeadlinePassed))
 			})
+		})
+	})
+
+	Describe("Auction routes", func() {
+		It("are open until closed", func() {
+			Expect(cellRep.AuctionRoutesStatus()).To(Equal(rep.AuctionRoutesStatus{}))
+
+			status := cellRep.CloseAuctionRoutes(logger)
+			Expect(status).To(Equal(rep.AuctionRoutesStatus{Closed: true, ClosedAt: repClock.Now().UnixNano()}))
+			Expect(cellRep.AuctionRoutesStatus()).To(Equal(status))
+
+			Expect(cellRep.OpenAuctionRoutes(logger)).To(Equal(rep.AuctionRoutesStatus{}))
+			Expect(cellRep.AuctionRoutesStatus().Closed).To(BeFalse())
+		})
+
+		It("keeps the time they were first closed at when closed again", func() {
+			closedAt := repClock.Now().UnixNano()
+			cellRep.CloseAuctionRoutes(logger)
+
+			repClock.Increment(time.Minute)
+			Expect(cellRep.CloseAuctionRoutes(logger).ClosedAt).To(Equal(closedAt))
+		})
+	})
+
+	Describe("Fragmentation", func() {
+		var windowsClient *fake_client.FakeClient
+
+		BeforeEach(func() {
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1000, DiskMB: 1000, Containers: 2}, nil)
+			client.ListContainersReturns([]executor.Container{createContainer(executor.StateRunning, rep.LRPLifecycle)}, nil)
+
+			windowsClient = new(fake_client.FakeClient)
+			windowsClient.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 600, DiskMB: 600, Containers: 2}, nil)
+			additionalBackends = []auctioncellrep.Backend{
+				auctioncellrep.NewBackend("windows", windowsClient, nil, rep.StackPathMap{}, nil, new(fakes.FakeBatchContainerAllocator)),
+			}
+		})
+
+		It("scores the free resources of each backend as a separate pool", func() {
+			report, err := cellRep.Fragmentation(logger)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(failedWork.LRPs).To(ConsistOf(unsuccessfulLRP))
-			Expect(failedWork.Tasks).To(ConsistOf(unsuccessfulTask))
+			Expect(report.FreeMemoryMB).To(BeEquivalentTo(1600))
+			Expect(report.LargestPlaceableMemoryMB).To(BeEquivalentTo(1000))
+			Expect(report.MemoryScore).To(Equal(0.375))
 		})
 
-		Context("when evacuating", func() {
+		It("suggests relocating the instances that would defragment the cell", func() {
+			report, err := cellRep.Fragmentation(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Relocations).To(HaveLen(1))
+			Expect(report.Relocations[0].InstanceGUID).To(Equal("some-instance-guid"))
+			Expect(report.Relocations[0].Backend).To(Equal(auctioncellrep.DefaultBackendName))
+			Expect(report.Relocations[0].LargestPlaceableMemoryMB).To(BeEquivalentTo(1020))
+		})
+
+		Context("when a backend cannot be listed", func() {
 			BeforeEach(func() {
-				evacuationReporter.EvacuatingReturns(true)
+				windowsClient.ListContainersReturns(nil, errors.New("boom"))
+			})
 
-				lrp := rep.NewLRP(
-					"ig-1",
-					models.NewActualLRPKey("process-guid", 1, "tests"),
-					rep.NewResource(2048, 1024, 100),
-					rep.NewPlacementConstraint(linuxRootFSURL, nil, []string{}),
-				)
+			It("returns the error", func() {
+				_, err := cellRep.Fragmentation(logger)
+				Expect(err).To(MatchError("boom"))
+			})
+		})
+	})
 
-				task := rep.NewTask(
-					"the-task-guid",
-					"tests",
-					rep.NewResource(2048, 1024, 100),
-					rep.NewPlacementConstraint(linuxRootFSURL, nil, []string{}),
-				)
+	Describe("GrowDiskQuota", func() {
+		var container executor.Container
 
-				work = rep.Work{
-					LRPs:  []rep.LRP{lrp},
-					Tasks: []rep.Task{task},
-				}
+		BeforeEach(func() {
+			diskQuotaGrower = new(fakes.FakeDiskQuotaGrower)
+			container = executor.Container{
+				Guid:     "task-guid",
+				State:    executor.StateRunning,
+				Resource: executor.NewResource(256, 1024, 10),
+				Tags: executor.Tags{
+					rep.LifecycleTag: rep.TaskLifecycle,
+					rep.DomainTag:    "domain",
+				},
+			}
+			client.GetContainerReturns(container, nil)
+			client.ListContainersReturns([]executor.Container{container}, nil)
+			client.RemainingResourcesReturns(executor.ExecutorResources{MemoryMB: 1000, DiskMB: 2000, Containers: 4}, nil)
+			client.TotalResourcesReturns(executor.ExecutorResources{MemoryMB: 2000, DiskMB: 4000, Containers: 8}, nil)
+		})
+
+		It("grows the disk quota of the container", func() {
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(Succeed())
+
+			Expect(diskQuotaGrower.GrowDiskQuotaCallCount()).To(Equal(1))
+			_, guid, diskMB := diskQuotaGrower.GrowDiskQuotaArgsForCall(0)
+			Expect(guid).To(Equal("task-guid"))
+			Expect(diskMB).To(Equal(1536))
+		})
+
+		It("accounts for the grown disk in the state", func() {
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(Succeed())
+
+			state, _, err := cellRep.State(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.Tasks).To(HaveLen(1))
+			Expect(state.Tasks[0].DiskMB).To(BeEquivalentTo(1536))
+			Expect(state.AvailableResources.DiskMB).To(BeEquivalentTo(1488))
+		})
+
+		It("grows the quota relative to earlier growth", func() {
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(Succeed())
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1280)).To(MatchError(rep.ErrInvalidDiskQuotaGrowth))
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 2048)).To(Succeed())
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 3584)).To(BeAssignableToTypeOf(rep.InsufficientResourcesError{}))
+		})
+
+		It("refuses to shrink the disk quota", func() {
+			Expect(cellRep.GrowDiskQuota(logger, "task-guid", 512)).To(MatchError(rep.ErrInvalidDiskQuotaGrowth))
+			Expect(diskQuotaGrower.GrowDiskQuotaCallCount()).To(Equal(0))
+		})
+
+		It("refuses to grow past the remaining disk of the cell", func() {
+			err := cellRep.GrowDiskQuota(logger, "task-guid", 4096)
+			Expect(err).To(BeAssignableToTypeOf(rep.InsufficientResourcesError{}))
+			Expect(diskQuotaGrower.GrowDiskQuotaCallCount()).To(Equal(0))
+		})
+
+		Context("when the container is not running", func() {
+			BeforeEach(func() {
+				container.State = executor.StateCreated
+				client.GetContainerReturns(container, nil)
 			})
 
-			It("returns all work it was given", func() {
-				Expect(cellRep.Perform(logger, work)).To(Equal(work))
+			It("refuses to grow its disk quota", func() {
+				Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(MatchError(auctioncellrep.ErrContainerNotRunning))
 			})
 		})
 
-		Context("when the cell only has enough resources to run a subset of the workloads", func() {
-			var smallestLRP, middleLRP, largestLRP rep.LRP
+		Context("when the container does not exist", func() {
+			BeforeEach(func() {
+				client.GetContainerReturns(executor.Container{}, executor.ErrContainerNotFound)
+			})
+
+			It("returns the error", func() {
+				Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(MatchError(executor.ErrContainerNotFound))
+			})
+		})
 
+		Context("when the backend cannot grow disk quotas", func() {
 			BeforeEach(func() {
-				remainingCellMemory = 8192
-				largestLRP = rep.LRP{Resource: rep.Resource{MemoryMB: 6144}}
-				middleLRP = rep.LRP{Resource: rep.Resource{MemoryMB: int32(remainingCellMemory) - largestLRP.MemoryMB}}
-				smallestLRP = rep.LRP{Resource: rep.Resource{MemoryMB: 1}}
+				diskQuotaGrower = nil
 			})
 
-			It("allocates containers for the largest workloads it can run", func() {
-				failedWork, err := cellRep.Perform(logger, rep.Work{
-					LRPs:  []rep.LRP{smallestLRP, middleLRP, largestLRP},
-					Tasks: []rep.Task{},
-				})
+			It("returns ErrDiskQuotaGrowthUnsupported", func() {
+				Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(MatchError(auctioncellrep.ErrDiskQuotaGrowthUnsupported))
+			})
+		})
+
+		Context("when the growth is saved to a file", func() {
+			var (
+				tmpDir     string
+				growthPath string
+			)
 
+			BeforeEach(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "disk-quota-growth")
 				Expect(err).NotTo(HaveOccurred())
-				Expect(failedWork.LRPs).To(ConsistOf(smallestLRP))
+				growthPath = filepath.Join(tmpDir, "growth.json")
 
-				Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+				diskGrowth, err = auctioncellrep.NewDiskQuotaGrowth(logger, growthPath)
+				Expect(err).NotTo(HaveOccurred())
+			})
 
-				_, proxyEnabledArg, proxyMemFootprintArg, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
-				Expect(proxyEnabledArg).To(BeFalse())
-				Expect(proxyMemFootprintArg).To(Equal(12))
-				Expect(lrpRequests).To(ConsistOf(largestLRP, middleLRP))
+			AfterEach(func() {
+				os.RemoveAll(tmpDir)
 			})
 
-			Context("when envoy needs to be placed in the container", func() {
-				BeforeEach(func() {
-					enableContainerProxy = true
-					proxyMemoryAllocation = remainingCellMemory - int(largestLRP.MemoryMB)
-				})
+			It("saves the growth", func() {
+				Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(Succeed())
 
-				It("accounts for the proxy overhead when determining which workloads to run and which to reject", func() {
-					failedWork, err := cellRep.Perform(logger, rep.Work{
-						LRPs:  []rep.LRP{smallestLRP, middleLRP, largestLRP},
-						Tasks: []rep.Task{},
-					})
+				contents, err := ioutil.ReadFile(growthPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contents).To(MatchJSON(`{"task-guid": 512}`))
+			})
+
+			Context("when the growth was saved before the rep restarted", func() {
+				BeforeEach(func() {
+					Expect(ioutil.WriteFile(growthPath, []byte(`{"task-guid": 512}`), 0600)).To(Succeed())
 
+					var err error
+					diskGrowth, err = auctioncellrep.NewDiskQuotaGrowth(logger, growthPath)
 					Expect(err).NotTo(HaveOccurred())
-					Expect(failedWork.LRPs).To(ConsistOf(smallestLRP, middleLRP))
+				})
 
-					Expect(fakeContainerAllocator.BatchLRPAllocationRequestCallCount()).To(Equal(1))
+				It("accounts for the saved growth", func() {
+					state, _, err := cellRep.State(context.Background(), logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(state.Tasks[0].DiskMB).To(BeEquivalentTo(1536))
+					Expect(state.AvailableResources.DiskMB).To(BeEquivalentTo(1488))
+				})
 
-					_, proxyEnabledArg, proxyMemFootprintArg, lrpRequests := fakeContainerAllocator.BatchLRPAllocationRequestArgsForCall(0)
-					Expect(proxyEnabledArg).To(BeTrue())
-					Expect(proxyMemFootprintArg).To(Equal(proxyMemoryAllocation))
-					Expect(lrpRequests).To(ConsistOf(largestLRP))
+				It("grows the quota relative to the saved growth", func() {
+					Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1280)).To(MatchError(rep.ErrInvalidDiskQuotaGrowth))
 				})
 			})
+
+			It("forgets the growth of containers that are gone", func() {
+				Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(Succeed())
+				client.ListContainersReturns(nil, nil)
+
+				_, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				contents, err := ioutil.ReadFile(growthPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contents).To(MatchJSON(`{}`))
+			})
+
+			It("fails to load a growth file that cannot be parsed", func() {
+				Expect(ioutil.WriteFile(growthPath, []byte("{"), 0600)).To(Succeed())
+				_, err := auctioncellrep.NewDiskQuotaGrowth(logger, growthPath)
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
-		Context("when the workload's cell ID does not match the cell's ID", func() {
-			It("rejects the workload", func() {
-				_, err := cellRep.Perform(logger, rep.Work{
-					LRPs:   lrpAuctions,
-					CellID: "do-not-want-your-work",
-				})
-				Expect(err).To(MatchError(auctioncellrep.ErrCellIdMismatch))
+		Context("when growing the disk quota fails", func() {
+			BeforeEach(func() {
+				diskQuotaGrower.GrowDiskQuotaReturns(errors.New("boom"))
+			})
+
+			It("does not account for the growth", func() {
+				Expect(cellRep.GrowDiskQuota(logger, "task-guid", 1536)).To(MatchError("boom"))
+
+				state, _, err := cellRep.State(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Tasks[0].DiskMB).To(BeEquivalentTo(1024))
 			})
 		})
 	})
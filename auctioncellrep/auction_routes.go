@@ -0,0 +1,51 @@
+package auctioncellrep
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// auctionRoutes records whether an operator closed the auction routes of the
+// cell, to freeze the scheduling of work onto it while it is being debugged.
+// The record is kept in memory only, so a restarted rep opens the routes
+// again.
+type auctionRoutes struct {
+	lock   sync.Mutex
+	status rep.AuctionRoutesStatus
+}
+
+// CloseAuctionRoutes closes the auction routes of the cell until they are
+// opened again, so that the auctioneer neither sees the cell nor places work
+// on it. Closing routes already closed keeps the time they were closed at.
+// The routes stay closed until the rep restarts at the latest.
+func (a *AuctionCellRep) CloseAuctionRoutes(logger lager.Logger) rep.AuctionRoutesStatus {
+	a.auctionRoutes.lock.Lock()
+	defer a.auctionRoutes.lock.Unlock()
+
+	if !a.auctionRoutes.status.Closed {
+		a.auctionRoutes.status = rep.AuctionRoutesStatus{Closed: true, ClosedAt: a.clock.Now().UnixNano()}
+		logger.Info("closed-auction-routes")
+	}
+	return a.auctionRoutes.status
+}
+
+// OpenAuctionRoutes opens the auction routes of the cell again.
+func (a *AuctionCellRep) OpenAuctionRoutes(logger lager.Logger) rep.AuctionRoutesStatus {
+	a.auctionRoutes.lock.Lock()
+	defer a.auctionRoutes.lock.Unlock()
+
+	if a.auctionRoutes.status.Closed {
+		a.auctionRoutes.status = rep.AuctionRoutesStatus{}
+		logger.Info("opened-auction-routes")
+	}
+	return a.auctionRoutes.status
+}
+
+// AuctionRoutesStatus tells whether the auction routes of the cell are closed.
+func (a *AuctionCellRep) AuctionRoutesStatus() rep.AuctionRoutesStatus {
+	a.auctionRoutes.lock.Lock()
+	defer a.auctionRoutes.lock.Unlock()
+	return a.auctionRoutes.status
+}
@@ -2,6 +2,7 @@
 package auctioncellrepfakes
 
 import (
+	"context"
 	"sync"
 
 	"code.cloudfoundry.org/lager"
@@ -10,11 +11,28 @@ import (
 )
 
 type FakeAuctionCellClient struct {
-	PerformStub        func(lager.Logger, rep.Work) (rep.Work, error)
+	CapacitySummaryStub        func(context.Context, lager.Logger) (rep.CapacitySummary, bool, error)
+	capacitySummaryMutex       sync.RWMutex
+	capacitySummaryArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+	}
+	capacitySummaryReturns struct {
+		result1 rep.CapacitySummary
+		result2 bool
+		result3 error
+	}
+	capacitySummaryReturnsOnCall map[int]struct {
+		result1 rep.CapacitySummary
+		result2 bool
+		result3 error
+	}
+	PerformStub        func(context.Context, lager.Logger, rep.Work) (rep.Work, error)
 	performMutex       sync.RWMutex
 	performArgsForCall []struct {
-		arg1 lager.Logger
-		arg2 rep.Work
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.Work
 	}
 	performReturns struct {
 		result1 rep.Work
@@ -24,9 +42,10 @@ type FakeAuctionCellClient struct {
 		result1 rep.Work
 		result2 error
 	}
-	ResetStub        func() error
+	ResetStub        func(context.Context) error
 	resetMutex       sync.RWMutex
 	resetArgsForCall []struct {
+		arg1 context.Context
 	}
 	resetReturns struct {
 		result1 error
@@ -34,10 +53,11 @@ type FakeAuctionCellClient struct {
 	resetReturnsOnCall map[int]struct {
 		result1 error
 	}
-	StateStub        func(lager.Logger) (rep.CellState, bool, error)
+	StateStub        func(context.Context, lager.Logger) (rep.CellState, bool, error)
 	stateMutex       sync.RWMutex
 	stateArgsForCall []struct {
-		arg1 lager.Logger
+		arg1 context.Context
+		arg2 lager.Logger
 	}
 	stateReturns struct {
 		result1 rep.CellState
@@ -53,19 +73,88 @@ type FakeAuctionCellClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeAuctionCellClient) Perform(arg1 lager.Logger, arg2 rep.Work) (rep.Work, error) {
+func (fake *FakeAuctionCellClient) CapacitySummary(arg1 context.Context, arg2 lager.Logger) (rep.CapacitySummary, bool, error) {
+	fake.capacitySummaryMutex.Lock()
+	ret, specificReturn := fake.capacitySummaryReturnsOnCall[len(fake.capacitySummaryArgsForCall)]
+	fake.capacitySummaryArgsForCall = append(fake.capacitySummaryArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+	}{arg1, arg2})
+	stub := fake.CapacitySummaryStub
+	fakeReturns := fake.capacitySummaryReturns
+	fake.recordInvocation("CapacitySummary", []interface{}{arg1, arg2})
+	fake.capacitySummaryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAuctionCellClient) CapacitySummaryCallCount() int {
+	fake.capacitySummaryMutex.RLock()
+	defer fake.capacitySummaryMutex.RUnlock()
+	return len(fake.capacitySummaryArgsForCall)
+}
+
+func (fake *FakeAuctionCellClient) CapacitySummaryCalls(stub func(context.Context, lager.Logger) (rep.CapacitySummary, bool, error)) {
+	fake.capacitySummaryMutex.Lock()
+	defer fake.capacitySummaryMutex.Unlock()
+	fake.CapacitySummaryStub = stub
+}
+
+func (fake *FakeAuctionCellClient) CapacitySummaryArgsForCall(i int) (context.Context, lager.Logger) {
+	fake.capacitySummaryMutex.RLock()
+	defer fake.capacitySummaryMutex.RUnlock()
+	argsForCall := fake.capacitySummaryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAuctionCellClient) CapacitySummaryReturns(result1 rep.CapacitySummary, result2 bool, result3 error) {
+	fake.capacitySummaryMutex.Lock()
+	defer fake.capacitySummaryMutex.Unlock()
+	fake.CapacitySummaryStub = nil
+	fake.capacitySummaryReturns = struct {
+		result1 rep.CapacitySummary
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAuctionCellClient) CapacitySummaryReturnsOnCall(i int, result1 rep.CapacitySummary, result2 bool, result3 error) {
+	fake.capacitySummaryMutex.Lock()
+	defer fake.capacitySummaryMutex.Unlock()
+	fake.CapacitySummaryStub = nil
+	if fake.capacitySummaryReturnsOnCall == nil {
+		fake.capacitySummaryReturnsOnCall = make(map[int]struct {
+			result1 rep.CapacitySummary
+			result2 bool
+			result3 error
+		})
+	}
+	fake.capacitySummaryReturnsOnCall[i] = struct {
+		result1 rep.CapacitySummary
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAuctionCellClient) Perform(arg1 context.Context, arg2 lager.Logger, arg3 rep.Work) (rep.Work, error) {
 	fake.performMutex.Lock()
 	ret, specificReturn := fake.performReturnsOnCall[len(fake.performArgsForCall)]
 	fake.performArgsForCall = append(fake.performArgsForCall, struct {
-		arg1 lager.Logger
-		arg2 rep.Work
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.Work
+	}{arg1, arg2, arg3})
 	stub := fake.PerformStub
 	fakeReturns := fake.performReturns
-	fake.recordInvocation("Perform", []interface{}{arg1, arg2})
+	fake.recordInvocation("Perform", []interface{}{arg1, arg2, arg3})
 	fake.performMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -79,17 +168,17 @@ func (fake *FakeAuctionCellClient) PerformCallCount() int {
 	return len(fake.performArgsForCall)
 }
 
-func (fake *FakeAuctionCellClient) PerformCalls(stub func(lager.Logger, rep.Work) (rep.Work, error)) {
+func (fake *FakeAuctionCellClient) PerformCalls(stub func(context.Context, lager.Logger, rep.Work) (rep.Work, error)) {
 	fake.performMutex.Lock()
 	defer fake.performMutex.Unlock()
 	fake.PerformStub = stub
 }
 
-func (fake *FakeAuctionCellClient) PerformArgsForCall(i int) (lager.Logger, rep.Work) {
+func (fake *FakeAuctionCellClient) PerformArgsForCall(i int) (context.Context, lager.Logger, rep.Work) {
 	fake.performMutex.RLock()
 	defer fake.performMutex.RUnlock()
 	argsForCall := fake.performArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeAuctionCellClient) PerformReturns(result1 rep.Work, result2 error) {
@@ -118,17 +207,18 @@ func (fake *FakeAuctionCellClient) PerformReturnsOnCall(i int, result1 rep.Work,
 	}{result1, result2}
 }
 
-func (fake *FakeAuctionCellClient) Reset() error {
+func (fake *FakeAuctionCellClient) Reset(arg1 context.Context) error {
 	fake.resetMutex.Lock()
 	ret, specificReturn := fake.resetReturnsOnCall[len(fake.resetArgsForCall)]
 	fake.resetArgsForCall = append(fake.resetArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.ResetStub
 	fakeReturns := fake.resetReturns
-	fake.recordInvocation("Reset", []interface{}{})
+	fake.recordInvocation("Reset", []interface{}{arg1})
 	fake.resetMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -142,12 +232,19 @@ func (fake *FakeAuctionCellClient) ResetCallCount() int {
 	return len(fake.resetArgsForCall)
 }
 
-func (fake *FakeAuctionCellClient) ResetCalls(stub func() error) {
+func (fake *FakeAuctionCellClient) ResetCalls(stub func(context.Context) error) {
 	fake.resetMutex.Lock()
 	defer fake.resetMutex.Unlock()
 	fake.ResetStub = stub
 }
 
+func (fake *FakeAuctionCellClient) ResetArgsForCall(i int) context.Context {
+	fake.resetMutex.RLock()
+	defer fake.resetMutex.RUnlock()
+	argsForCall := fake.resetArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeAuctionCellClient) ResetReturns(result1 error) {
 	fake.resetMutex.Lock()
 	defer fake.resetMutex.Unlock()
@@ -171,18 +268,19 @@ func (fake *FakeAuctionCellClient) ResetReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeAuctionCellClient) State(arg1 lager.Logger) (rep.CellState, bool, error) {
+func (fake *FakeAuctionCellClient) State(arg1 context.Context, arg2 lager.Logger) (rep.CellState, bool, error) {
 	fake.stateMutex.Lock()
 	ret, specificReturn := fake.stateReturnsOnCall[len(fake.stateArgsForCall)]
 	fake.stateArgsForCall = append(fake.stateArgsForCall, struct {
-		arg1 lager.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 lager.Logger
+	}{arg1, arg2})
 	stub := fake.StateStub
 	fakeReturns := fake.stateReturns
-	fake.recordInvocation("State", []interface{}{arg1})
+	fake.recordInvocation("State", []interface{}{arg1, arg2})
 	fake.stateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -196,17 +294,17 @@ func (fake *FakeAuctionCellClient) StateCallCount() int {
 	return len(fake.stateArgsForCall)
 }
 
-func (fake *FakeAuctionCellClient) StateCalls(stub func(lager.Logger) (rep.CellState, bool, error)) {
+func (fake *FakeAuctionCellClient) StateCalls(stub func(context.Context, lager.Logger) (rep.CellState, bool, error)) {
 	fake.stateMutex.Lock()
 	defer fake.stateMutex.Unlock()
 	fake.StateStub = stub
 }
 
-func (fake *FakeAuctionCellClient) StateArgsForCall(i int) lager.Logger {
+func (fake *FakeAuctionCellClient) StateArgsForCall(i int) (context.Context, lager.Logger) {
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
 	argsForCall := fake.stateArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAuctionCellClient) StateReturns(result1 rep.CellState, result2 bool, result3 error) {
@@ -241,6 +339,8 @@ func (fake *FakeAuctionCellClient) StateReturnsOnCall(i int, result1 rep.CellSta
 func (fake *FakeAuctionCellClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.capacitySummaryMutex.RLock()
+	defer fake.capacitySummaryMutex.RUnlock()
 	fake.performMutex.RLock()
 	defer fake.performMutex.RUnlock()
 	fake.resetMutex.RLock()
@@ -10,7 +10,7 @@ import (
 )
 
 type FakeBatchContainerAllocator struct {
-	BatchLRPAllocationRequestStub        func(lager.Logger, bool, int, []rep.LRP) []rep.LRP
+	BatchLRPAllocationRequestStub        func(lager.Logger, bool, int, []rep.LRP) ([]rep.LRP, map[string]string)
 	batchLRPAllocationRequestMutex       sync.RWMutex
 	batchLRPAllocationRequestArgsForCall []struct {
 		arg1 lager.Logger
@@ -20,9 +20,11 @@ type FakeBatchContainerAllocator struct {
 	}
 	batchLRPAllocationRequestReturns struct {
 		result1 []rep.LRP
+		result2 map[string]string
 	}
 	batchLRPAllocationRequestReturnsOnCall map[int]struct {
 		result1 []rep.LRP
+		result2 map[string]string
 	}
 	BatchTaskAllocationRequestStub        func(lager.Logger, []rep.Task) []rep.Task
 	batchTaskAllocationRequestMutex       sync.RWMutex
@@ -40,7 +42,7 @@ type FakeBatchContainerAllocator struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequest(arg1 lager.Logger, arg2 bool, arg3 int, arg4 []rep.LRP) []rep.LRP {
+func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequest(arg1 lager.Logger, arg2 bool, arg3 int, arg4 []rep.LRP) ([]rep.LRP, map[string]string) {
 	var arg4Copy []rep.LRP
 	if arg4 != nil {
 		arg4Copy = make([]rep.LRP, len(arg4))
@@ -62,9 +64,9 @@ func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequest(arg1 lager.Lo
 		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestCallCount() int {
@@ -73,7 +75,7 @@ func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestCallCount() in
 	return len(fake.batchLRPAllocationRequestArgsForCall)
 }
 
-func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestCalls(stub func(lager.Logger, bool, int, []rep.LRP) []rep.LRP) {
+func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestCalls(stub func(lager.Logger, bool, int, []rep.LRP) ([]rep.LRP, map[string]string)) {
 	fake.batchLRPAllocationRequestMutex.Lock()
 	defer fake.batchLRPAllocationRequestMutex.Unlock()
 	fake.BatchLRPAllocationRequestStub = stub
@@ -86,27 +88,30 @@ func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestArgsForCall(i
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestReturns(result1 []rep.LRP) {
+func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestReturns(result1 []rep.LRP, result2 map[string]string) {
 	fake.batchLRPAllocationRequestMutex.Lock()
 	defer fake.batchLRPAllocationRequestMutex.Unlock()
 	fake.BatchLRPAllocationRequestStub = nil
 	fake.batchLRPAllocationRequestReturns = struct {
 		result1 []rep.LRP
-	}{result1}
+		result2 map[string]string
+	}{result1, result2}
 }
 
-func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestReturnsOnCall(i int, result1 []rep.LRP) {
+func (fake *FakeBatchContainerAllocator) BatchLRPAllocationRequestReturnsOnCall(i int, result1 []rep.LRP, result2 map[string]string) {
 	fake.batchLRPAllocationRequestMutex.Lock()
 	defer fake.batchLRPAllocationRequestMutex.Unlock()
 	fake.BatchLRPAllocationRequestStub = nil
 	if fake.batchLRPAllocationRequestReturnsOnCall == nil {
 		fake.batchLRPAllocationRequestReturnsOnCall = make(map[int]struct {
 			result1 []rep.LRP
+			result2 map[string]string
 		})
 	}
 	fake.batchLRPAllocationRequestReturnsOnCall[i] = struct {
 		result1 []rep.LRP
-	}{result1}
+		result2 map[string]string
+	}{result1, result2}
 }
 
 func (fake *FakeBatchContainerAllocator) BatchTaskAllocationRequest(arg1 lager.Logger, arg2 []rep.Task) []rep.Task {
@@ -0,0 +1,153 @@
+package auctioncellrep
+
+import (
+	"io"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// DefaultBackendName is the name the cell's primary executor is reported
+// under when the cell has additional backends.
+const DefaultBackendName = "default"
+
+// Backend is an executor the cell schedules onto. Every cell has its primary
+// executor as a backend and may be configured with additional ones, e.g. for
+// a different container runtime. Each backend has its own resources and
+// rootfs providers.
+type Backend struct {
+	Name            string
+	Client          executor.Client
+	MetricsProvider rep.ContainerMetricsProvider
+	StackPathMap    rep.StackPathMap
+	RootFSProviders rep.RootFSProviders
+	Allocator       BatchContainerAllocator
+}
+
+func NewBackend(
+	name string,
+	client executor.Client,
+	metricsProvider rep.ContainerMetricsProvider,
+	preloadedStackPathMap rep.StackPathMap,
+	arbitraryRootFSes []string,
+	allocator BatchContainerAllocator,
+) Backend {
+	return Backend{
+		Name:            name,
+		Client:          client,
+		MetricsProvider: metricsProvider,
+		StackPathMap:    preloadedStackPathMap,
+		RootFSProviders: rootFSProviders("", preloadedStackPathMap, arbitraryRootFSes),
+		Allocator:       allocator,
+	}
+}
+
+func (b Backend) matchRootFS(rootfs string) bool {
+	rootFSURL, err := rep.ParseRootFS(rootfs)
+	if err != nil {
+		return false
+	}
+
+	return b.RootFSProviders.Match(*rootFSURL)
+}
+
+// NewBackendsClient returns a client for the containers of every backend of
+// a cell: the containers of all the backends are listed together, and the
+// operations on one container are sent to the backend that holds it. Its
+// other operations, such as reserving containers or reporting resources, go
+// to the primary executor. It returns primary for a cell without additional
+// backends.
+func NewBackendsClient(primary executor.Client, additionalBackends []Backend) executor.Client {
+	if len(additionalBackends) == 0 {
+		return primary
+	}
+
+	clients := []executor.Client{primary}
+	for _, backend := range additionalBackends {
+		clients = append(clients, backend.Client)
+	}
+	return &backendsClient{Client: primary, clients: clients}
+}
+
+type backendsClient struct {
+	executor.Client
+
+	clients []executor.Client
+}
+
+func (c *backendsClient) ListContainers(logger lager.Logger) ([]executor.Container, error) {
+	containers := []executor.Container{}
+	for _, client := range c.clients {
+		backendContainers, err := client.ListContainers(logger)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, backendContainers...)
+	}
+	return containers, nil
+}
+
+func (c *backendsClient) GetContainer(logger lager.Logger, guid string) (executor.Container, error) {
+	for _, client := range c.clients {
+		container, err := client.GetContainer(logger, guid)
+		if err != executor.ErrContainerNotFound {
+			return container, err
+		}
+	}
+	return executor.Container{}, executor.ErrContainerNotFound
+}
+
+func (c *backendsClient) RunContainer(logger lager.Logger, request *executor.RunRequest) error {
+	client, err := c.owner(logger, request.Guid)
+	if err != nil {
+		return err
+	}
+	return client.RunContainer(logger, request)
+}
+
+func (c *backendsClient) UpdateContainer(logger lager.Logger, request *executor.UpdateRequest) error {
+	client, err := c.owner(logger, request.Guid)
+	if err != nil {
+		return err
+	}
+	return client.UpdateContainer(logger, request)
+}
+
+func (c *backendsClient) StopContainer(logger lager.Logger, guid string) error {
+	client, err := c.owner(logger, guid)
+	if err != nil {
+		return err
+	}
+	return client.StopContainer(logger, guid)
+}
+
+func (c *backendsClient) DeleteContainer(logger lager.Logger, guid string) error {
+	client, err := c.owner(logger, guid)
+	if err != nil {
+		return err
+	}
+	return client.DeleteContainer(logger, guid)
+}
+
+func (c *backendsClient) GetFiles(logger lager.Logger, guid, path string) (io.ReadCloser, error) {
+	client, err := c.owner(logger, guid)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetFiles(logger, guid, path)
+}
+
+// owner returns the client of the backend that holds the container with guid.
+func (c *backendsClient) owner(logger lager.Logger, guid string) (executor.Client, error) {
+	for _, client := range c.clients {
+		_, err := client.GetContainer(logger, guid)
+		if err == nil {
+			return client, nil
+		}
+		if err != executor.ErrContainerNotFound {
+			return nil, err
+		}
+	}
+	return nil, executor.ErrContainerNotFound
+}
@@ -0,0 +1,88 @@
+package auctioncellrep_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/executor"
+	fake_client "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+	fakes "code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BackendsClient", func() {
+	var (
+		logger        *lagertest.TestLogger
+		primary       *fake_client.FakeClient
+		windowsClient *fake_client.FakeClient
+		client        executor.Client
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		primary = new(fake_client.FakeClient)
+		windowsClient = new(fake_client.FakeClient)
+
+		primary.ListContainersReturns([]executor.Container{{Guid: "linux-container"}}, nil)
+		primary.GetContainerReturns(executor.Container{}, executor.ErrContainerNotFound)
+		windowsClient.ListContainersReturns([]executor.Container{{Guid: "windows-container"}}, nil)
+		windowsClient.GetContainerReturns(executor.Container{Guid: "windows-container"}, nil)
+
+		client = auctioncellrep.NewBackendsClient(primary, []auctioncellrep.Backend{
+			auctioncellrep.NewBackend("windows", windowsClient, nil, rep.StackPathMap{}, nil, new(fakes.FakeBatchContainerAllocator)),
+		})
+	})
+
+	It("is the primary client without additional backends", func() {
+		Expect(auctioncellrep.NewBackendsClient(primary, nil)).To(BeIdenticalTo(primary))
+	})
+
+	It("lists the containers of every backend", func() {
+		containers, err := client.ListContainers(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(containers).To(Equal([]executor.Container{{Guid: "linux-container"}, {Guid: "windows-container"}}))
+	})
+
+	It("fails to list the containers when a backend does", func() {
+		windowsClient.ListContainersReturns(nil, errors.New("boom"))
+		_, err := client.ListContainers(logger)
+		Expect(err).To(MatchError("boom"))
+	})
+
+	It("gets a container from the backend that holds it", func() {
+		container, err := client.GetContainer(logger, "windows-container")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(container.Guid).To(Equal("windows-container"))
+	})
+
+	It("sends the operations on a container to the backend that holds it", func() {
+		Expect(client.StopContainer(logger, "windows-container")).To(Succeed())
+		Expect(client.DeleteContainer(logger, "windows-container")).To(Succeed())
+		updateRequest := executor.NewUpdateRequest("windows-container", nil, nil)
+		Expect(client.UpdateContainer(logger, &updateRequest)).To(Succeed())
+
+		Expect(windowsClient.StopContainerCallCount()).To(Equal(1))
+		Expect(windowsClient.DeleteContainerCallCount()).To(Equal(1))
+		Expect(windowsClient.UpdateContainerCallCount()).To(Equal(1))
+		Expect(primary.StopContainerCallCount()).To(Equal(0))
+		Expect(primary.DeleteContainerCallCount()).To(Equal(0))
+		Expect(primary.UpdateContainerCallCount()).To(Equal(0))
+	})
+
+	It("fails the operations on a container no backend holds", func() {
+		windowsClient.GetContainerReturns(executor.Container{}, executor.ErrContainerNotFound)
+		Expect(client.StopContainer(logger, "missing")).To(Equal(executor.ErrContainerNotFound))
+		_, err := client.GetContainer(logger, "missing")
+		Expect(err).To(Equal(executor.ErrContainerNotFound))
+	})
+
+	It("sends the other operations to the primary executor", func() {
+		client.AllocateContainers(logger, nil)
+		Expect(primary.AllocateContainersCallCount()).To(Equal(1))
+		Expect(windowsClient.AllocateContainersCallCount()).To(Equal(0))
+	})
+})
@@ -10,8 +10,13 @@ import (
 )
 
 //go:generate counterfeiter . BatchContainerAllocator
+
+// BatchContainerAllocator allocates the containers of LRP instances and
+// tasks, returning the work it failed to allocate. The containers of LRP
+// instances are also returned by the Identifier of their instance, as their
+// guids are generated on allocation.
 type BatchContainerAllocator interface {
-	BatchLRPAllocationRequest(lager.Logger, bool, int, []rep.LRP) []rep.LRP
+	BatchLRPAllocationRequest(lager.Logger, bool, int, []rep.LRP) ([]rep.LRP, map[string]string)
 	BatchTaskAllocationRequest(lager.Logger, []rep.Task) []rep.Task
 }
 
@@ -41,6 +46,17 @@ func buildLRPTags(lrp rep.LRP, instanceGuid string) executor.Tags {
 	volumeDrivers, _ := json.Marshal(lrp.PlacementConstraint.VolumeDrivers)
 	tags[rep.PlacementTagsTag] = string(placementTags)
 	tags[rep.VolumeDriversTag] = string(volumeDrivers)
+	addCPUEntitlementTag(tags, lrp.CPUEntitlement)
+	rep.AddIOLimitsTag(tags, lrp.IOLimits)
+	rep.AddSecurityTag(tags, lrp.Security)
+	rep.AddProportionalResourceTag(tags, lrp.Proportional)
+	rep.AddStaticHostPortsTag(tags, lrp.StaticHostPorts)
+	rep.AddLabelTags(tags, lrp.Labels)
+	rep.AddInitStepsTag(tags, lrp.InitSteps)
+	rep.AddTraceContextTags(tags, lrp.TraceContext)
+	rep.AddDirectedPlacementTags(tags, lrp.Directed)
+	rep.AddProvenanceTag(tags, lrp.Provenance)
+	addWorkGroupTag(tags, lrp.Group)
 
 	return tags
 }
@@ -54,10 +70,36 @@ func buildTaskTags(task rep.Task) executor.Tags {
 	volumeDrivers, _ := json.Marshal(task.PlacementConstraint.VolumeDrivers)
 	tags[rep.PlacementTagsTag] = string(placementTags)
 	tags[rep.VolumeDriversTag] = string(volumeDrivers)
+	addCPUEntitlementTag(tags, task.CPUEntitlement)
+	rep.AddIOLimitsTag(tags, task.IOLimits)
+	rep.AddSecurityTag(tags, task.Security)
+	rep.AddProportionalResourceTag(tags, task.Proportional)
+	rep.AddStaticHostPortsTag(tags, task.StaticHostPorts)
+	rep.AddLabelTags(tags, task.Labels)
+	rep.AddTraceContextTags(tags, task.TraceContext)
+	rep.AddDirectedPlacementTags(tags, task.Directed)
+	rep.AddProvenanceTag(tags, task.Provenance)
+	addWorkGroupTag(tags, task.Group)
 	return tags
 }
 
-func (ca containerAllocator) BatchLRPAllocationRequest(logger lager.Logger, enableContainerProxy bool, proxyMemoryAllocation int, lrps []rep.LRP) (unallocatedLRPs []rep.LRP) {
+// addCPUEntitlementTag records the CPU entitlement of the work on its
+// container, so that the cell can report it with the container.
+func addCPUEntitlementTag(tags executor.Tags, cpuEntitlement float64) {
+	if cpuEntitlement > 0 {
+		tags[rep.CPUEntitlementTag] = strconv.FormatFloat(cpuEntitlement, 'f', -1, 64)
+	}
+}
+
+// addWorkGroupTag records the group of the work on its container, so that
+// its claim is held until every member of the group is allocated.
+func addWorkGroupTag(tags executor.Tags, group string) {
+	if group != "" {
+		tags[rep.WorkGroupTag] = group
+	}
+}
+
+func (ca containerAllocator) BatchLRPAllocationRequest(logger lager.Logger, enableContainerProxy bool, proxyMemoryAllocation int, lrps []rep.LRP) (unallocatedLRPs []rep.LRP, containerGuids map[string]string) {
 	logger = logger.Session("lrp-allocate-instances")
 	requests := make([]executor.AllocationRequest, 0, len(lrps))
 	lrpGuidMap := make(map[string]rep.LRP, len(lrps))
@@ -103,10 +145,16 @@ func (ca containerAllocator) BatchLRPAllocationRequest(logger lager.Logger, enab
 		logger.Error("container-allocation-failure", &failure, lager.Data{"failed-request": failure.AllocationRequest})
 		if lrp, found := lrpGuidMap[failure.Guid]; found {
 			unallocatedLRPs = append(unallocatedLRPs, lrp)
+			delete(lrpGuidMap, failure.Guid)
 		}
 	}
 
-	return unallocatedLRPs
+	containerGuids = make(map[string]string, len(lrpGuidMap))
+	for containerGuid, lrp := range lrpGuidMap {
+		containerGuids[lrp.Identifier()] = containerGuid
+	}
+
+	return unallocatedLRPs, containerGuids
 }
 
 func (ca containerAllocator) BatchTaskAllocationRequest(logger lager.Logger, tasks []rep.Task) (unallocatedTasks []rep.Task) {
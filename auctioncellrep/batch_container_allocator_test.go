@@ -69,6 +69,10 @@ var _ = Describe("ContainerAllocator", func() {
 				rep.NewResource(2048, 1024, 100),
 				rep.NewPlacementConstraint(linuxRootFSURL, []string{"pt-1"}, []string{"vd-1"}),
 			)
+			lrp1.CPUEntitlement = 1.5
+			lrp1.TraceContext = &rep.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+			lrp1.Directed = &rep.DirectedPlacement{CellID: "cell-id", RequestedBy: "ops-pinning-tool", Reason: "hardware canary"}
+			lrp1.Provenance = &rep.Provenance{AuctionID: "auction-1", AuctioneerID: "auctioneer-0", Attempt: 2}
 
 			lrp2 = rep.NewLRP(
 				"ig-2",
@@ -90,10 +94,46 @@ var _ = Describe("ContainerAllocator", func() {
 		})
 
 		It("does not mark any LRP Auctions as failed", func() {
-			failedWork := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1, lrp2})
+			failedWork, _ := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1, lrp2})
 			Expect(failedWork).To(BeEmpty())
 		})
 
+		It("returns the containers allocated for the LRPs", func() {
+			_, containerGuids := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1, lrp2})
+			Expect(containerGuids).To(Equal(map[string]string{
+				lrp1.Identifier(): "ig-1",
+				lrp2.Identifier(): "ig-2",
+			}))
+		})
+
+		Context("when the LRP has labels", func() {
+			BeforeEach(func() {
+				lrp1.Labels = map[string]string{"team": "payments"}
+			})
+
+			It("labels its container", func() {
+				allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1})
+
+				_, arg := executorClient.AllocateContainersArgsForCall(0)
+				Expect(arg).To(ConsistOf(allocationRequestFromLRP(lrp1)))
+				Expect(arg[0].Tags).To(HaveKeyWithValue(rep.LabelTagPrefix+"team", "payments"))
+			})
+		})
+
+		Context("when the LRP belongs to a work group", func() {
+			BeforeEach(func() {
+				lrp1.Group = "web-with-migration"
+			})
+
+			It("tags its container with the group", func() {
+				allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1})
+
+				_, arg := executorClient.AllocateContainersArgsForCall(0)
+				Expect(arg).To(ConsistOf(allocationRequestFromLRP(lrp1)))
+				Expect(arg[0].Tags).To(HaveKeyWithValue(rep.WorkGroupTag, "web-with-migration"))
+			})
+		})
+
 		Context("when a container fails to be allocated", func() {
 			BeforeEach(func() {
 				allocationRequest := allocationRequestFromLRP(lrp2)
@@ -102,9 +142,14 @@ var _ = Describe("ContainerAllocator", func() {
 			})
 
 			It("marks the corresponding LRP Auctions as failed", func() {
-				failedWork := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1, lrp2})
+				failedWork, _ := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1, lrp2})
 				Expect(failedWork).To(ConsistOf(lrp2))
 			})
+
+			It("only returns the containers that were allocated", func() {
+				_, containerGuids := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{lrp1, lrp2})
+				Expect(containerGuids).To(Equal(map[string]string{lrp1.Identifier(): "ig-1"}))
+			})
 		})
 
 		Context("when envoy needs to be placed in the container", func() {
@@ -194,7 +239,7 @@ var _ = Describe("ContainerAllocator", func() {
 				})
 
 				It("marks the other LRP as failed", func() {
-					failedLRPs := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{validLRP, invalidLRP})
+					failedLRPs, _ := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{validLRP, invalidLRP})
 					Expect(failedLRPs).To(ConsistOf(invalidLRP))
 				})
 			})
@@ -215,7 +260,7 @@ var _ = Describe("ContainerAllocator", func() {
 				})
 
 				It("marks the LRPs with invalid RootFS paths as failed", func() {
-					failedLRPs := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{validLRP, invalidLRP})
+					failedLRPs, _ := allocator.BatchLRPAllocationRequest(logger, enableContainerProxy, proxyMemoryAllocation, []rep.LRP{validLRP, invalidLRP})
 					Expect(failedLRPs).To(HaveLen(1))
 					Expect(failedLRPs).To(ContainElement(invalidLRP))
 				})
@@ -265,6 +310,9 @@ var _ = Describe("ContainerAllocator", func() {
 			placement1 := rep.NewPlacementConstraint("tests", []string{"pt-1"}, []string{"vd-1"})
 			task1 = rep.NewTask("the-task-guid-1", "tests", resource1, placement1)
 			task1.RootFs = linuxRootFSURL
+			task1.TraceContext = &rep.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+			task1.Provenance = &rep.Provenance{AuctionID: "auction-1", AuctioneerID: "auctioneer-0", Attempt: 1}
+			task1.CPUEntitlement = 0.5
 
 			resource2 := rep.NewResource(512, 1024, 256)
 			placement2 := rep.NewPlacementConstraint("linux", []string{"pt-2"}, []string{})
@@ -283,6 +331,20 @@ var _ = Describe("ContainerAllocator", func() {
 			))
 		})
 
+		Context("when the Task has labels", func() {
+			BeforeEach(func() {
+				task1.Labels = map[string]string{"team": "payments"}
+			})
+
+			It("labels its container", func() {
+				allocator.BatchTaskAllocationRequest(logger, []rep.Task{task1})
+
+				_, arg := executorClient.AllocateContainersArgsForCall(0)
+				Expect(arg).To(ConsistOf(allocationRequestFromTask(task1, `["pt-1"]`, `["vd-1"]`)))
+				Expect(arg[0].Tags).To(HaveKeyWithValue(rep.LabelTagPrefix+"team", "payments"))
+			})
+		})
+
 		Context("when all containers can be successfully allocated", func() {
 			BeforeEach(func() {
 				executorClient.AllocateContainersReturns([]executor.AllocationFailure{})
@@ -414,31 +476,65 @@ func allocationRequestFromLRP(lrp rep.LRP) executor.AllocationRequest {
 	volumeDriversBytes, err := json.Marshal(lrp.VolumeDrivers)
 	ExpectWithOffset(1, err).NotTo(HaveOccurred())
 
-	return executor.NewAllocationRequest(
-		lrp.InstanceGUID,
-		&resource,
-		executor.Tags{
-			rep.LifecycleTag:     rep.LRPLifecycle,
-			rep.DomainTag:        lrp.Domain,
-			rep.PlacementTagsTag: string(placementTagsBytes),
-			rep.VolumeDriversTag: string(volumeDriversBytes),
-			rep.ProcessGuidTag:   lrp.ProcessGuid,
-			rep.ProcessIndexTag:  strconv.Itoa(int(lrp.Index)),
-			rep.InstanceGuidTag:  lrp.InstanceGUID,
-		},
-	)
+	tags := executor.Tags{
+		rep.LifecycleTag:     rep.LRPLifecycle,
+		rep.DomainTag:        lrp.Domain,
+		rep.PlacementTagsTag: string(placementTagsBytes),
+		rep.VolumeDriversTag: string(volumeDriversBytes),
+		rep.ProcessGuidTag:   lrp.ProcessGuid,
+		rep.ProcessIndexTag:  strconv.Itoa(int(lrp.Index)),
+		rep.InstanceGuidTag:  lrp.InstanceGUID,
+	}
+	if lrp.CPUEntitlement > 0 {
+		tags[rep.CPUEntitlementTag] = strconv.FormatFloat(lrp.CPUEntitlement, 'f', -1, 64)
+	}
+	for key, value := range lrp.Labels {
+		tags[rep.LabelTagPrefix+key] = value
+	}
+	if lrp.TraceContext != nil {
+		tags[rep.TraceParentTag] = lrp.TraceContext.TraceParent
+	}
+	if lrp.Directed != nil {
+		tags[rep.DirectedByTag] = lrp.Directed.RequestedBy
+		tags[rep.DirectedReasonTag] = lrp.Directed.Reason
+	}
+	if lrp.Provenance != nil {
+		provenance, err := json.Marshal(lrp.Provenance)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		tags[rep.ProvenanceTag] = string(provenance)
+	}
+	if lrp.Group != "" {
+		tags[rep.WorkGroupTag] = lrp.Group
+	}
+
+	return executor.NewAllocationRequest(lrp.InstanceGUID, &resource, tags)
 }
 
 func allocationRequestFromTask(task rep.Task, placementTags, volumeDrivers string) executor.AllocationRequest {
 	resource := executor.NewResource(int(task.MemoryMB), int(task.DiskMB), int(task.MaxPids))
-	return executor.NewAllocationRequest(
-		task.TaskGuid,
-		&resource,
-		executor.Tags{
-			rep.LifecycleTag:     rep.TaskLifecycle,
-			rep.DomainTag:        task.Domain,
-			rep.PlacementTagsTag: placementTags,
-			rep.VolumeDriversTag: volumeDrivers,
-		},
-	)
+	tags := executor.Tags{
+		rep.LifecycleTag:     rep.TaskLifecycle,
+		rep.DomainTag:        task.Domain,
+		rep.PlacementTagsTag: placementTags,
+		rep.VolumeDriversTag: volumeDrivers,
+	}
+	if task.CPUEntitlement > 0 {
+		tags[rep.CPUEntitlementTag] = strconv.FormatFloat(task.CPUEntitlement, 'f', -1, 64)
+	}
+	for key, value := range task.Labels {
+		tags[rep.LabelTagPrefix+key] = value
+	}
+	if task.TraceContext != nil {
+		tags[rep.TraceParentTag] = task.TraceContext.TraceParent
+	}
+	if task.Provenance != nil {
+		provenance, err := json.Marshal(task.Provenance)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		tags[rep.ProvenanceTag] = string(provenance)
+	}
+	if task.Group != "" {
+		tags[rep.WorkGroupTag] = task.Group
+	}
+
+	return executor.NewAllocationRequest(task.TaskGuid, &resource, tags)
 }
@@ -0,0 +1,304 @@
+package auctioncellrep
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+var ErrCapacityReservationNotFound = errors.New("capacity reservation not found")
+var ErrCapacityReservationsDisabled = errors.New("capacity reservations are not enabled on this cell")
+
+// CapacityReservations holds the capacity reserved on the backends of the
+// cell ahead of rolling deployments. Reservations expire after their ttl,
+// which is capped at maxTTL and measured on the monotonic clock, or once every
+// instance they hold has been placed. An instance takes the place it is held
+// under the lock of the reservations, so that concurrent performs cannot both
+// place an instance in the same place.
+type CapacityReservations struct {
+	clock  clock.Clock
+	maxTTL time.Duration
+
+	lock         sync.Mutex
+	reservations map[string]*heldReservation
+}
+
+type heldReservation struct {
+	rep.CapacityReservation
+	expires time.Time
+	// taken counts the instances that took a place of the reservation and
+	// are still being allocated.
+	taken int32
+}
+
+func NewCapacityReservations(clock clock.Clock, maxTTL time.Duration) *CapacityReservations {
+	return &CapacityReservations{
+		clock:        clock,
+		maxTTL:       maxTTL,
+		reservations: map[string]*heldReservation{},
+	}
+}
+
+// Reserve records a reservation for request on backend lasting ttl when
+// available, the capacity of the backend not taken by containers, also fits
+// it next to the reservations already held there. It returns an
+// InsufficientResourcesError otherwise.
+func (r *CapacityReservations) Reserve(request rep.CapacityReservationRequest, backend string, ttl time.Duration, available rep.Resources) (rep.CapacityReservation, error) {
+	err := request.Validate()
+	if err != nil {
+		return rep.CapacityReservation{}, err
+	}
+	if ttl <= 0 {
+		return rep.CapacityReservation{}, rep.ErrDeadlinePassed
+	}
+
+	id, err := GenerateGuid()
+	if err != nil {
+		return rep.CapacityReservation{}, err
+	}
+
+	if ttl > r.maxTTL {
+		ttl = r.maxTTL
+	}
+
+	expires := r.clock.Now().Add(ttl)
+	reservation := rep.CapacityReservation{
+		ID:          id,
+		ProcessGuid: request.ProcessGuid,
+		Backend:     backend,
+		Resource:    request.Resource,
+		Instances:   request.Instances,
+		ExpiresAt:   expires.UnixNano(),
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	unreserved := withoutReserved(available, reservationsOn(r.active(), backend))
+	err = fits(reservation.Reserved(), unreserved)
+	if err != nil {
+		return rep.CapacityReservation{}, err
+	}
+
+	r.reservations[id] = &heldReservation{CapacityReservation: reservation, expires: expires}
+	return reservation, nil
+}
+
+// Release removes the reservation with the given id.
+func (r *CapacityReservations) Release(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.reservations[id]; !ok {
+		return ErrCapacityReservationNotFound
+	}
+	delete(r.reservations, id)
+	return nil
+}
+
+// Active returns the reservations that have not expired, soonest to expire
+// first, forgetting the expired ones.
+func (r *CapacityReservations) Active() []rep.CapacityReservation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.active()
+}
+
+func (r *CapacityReservations) active() []rep.CapacityReservation {
+	now := r.clock.Now()
+
+	held := make([]*heldReservation, 0, len(r.reservations))
+	for id, reservation := range r.reservations {
+		if !now.Before(reservation.expires) {
+			delete(r.reservations, id)
+			continue
+		}
+		if reservation.Instances+reservation.taken < 1 {
+			continue
+		}
+		held = append(held, reservation)
+	}
+
+	sort.Slice(held, func(i, j int) bool {
+		if held[i].expires.Equal(held[j].expires) {
+			return held[i].ID < held[j].ID
+		}
+		return held[i].expires.Before(held[j].expires)
+	})
+
+	// the places taken by instances still being allocated stay reserved
+	active := make([]rep.CapacityReservation, len(held))
+	for i := range held {
+		active[i] = held[i].CapacityReservation
+		active[i].Instances += held[i].taken
+	}
+	return active
+}
+
+// Take takes the place of one of the instances that a reservation on backend
+// holds for an instance of processGuid requesting res, and returns the
+// reservation as it was before. The place is kept until it is claimed once
+// the instance is allocated, or returned when it is not.
+func (r *CapacityReservations) Take(backend, processGuid string, res *rep.Resource) (rep.CapacityReservation, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	active := r.active()
+	for i := range active {
+		reservation := r.reservations[active[i].ID]
+		if reservation.Backend != backend || !reservation.Holds(processGuid, res) {
+			continue
+		}
+		reservation.Instances--
+		reservation.taken++
+		return active[i], true
+	}
+	return rep.CapacityReservation{}, false
+}
+
+// Claim keeps the place taken from the reservation with the given id.
+func (r *CapacityReservations) Claim(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if reservation, ok := r.reservations[id]; ok && reservation.taken > 0 {
+		reservation.taken--
+		if reservation.Instances < 1 && reservation.taken < 1 {
+			delete(r.reservations, id)
+		}
+	}
+}
+
+// Return gives the place taken from the reservation with the given id back
+// to it.
+func (r *CapacityReservations) Return(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if reservation, ok := r.reservations[id]; ok && reservation.taken > 0 {
+		reservation.taken--
+		reservation.Instances++
+	}
+}
+
+func reservedResources(reservations []rep.CapacityReservation) rep.Resources {
+	reserved := rep.Resources{}
+	for i := range reservations {
+		reserved.Add(reservations[i].Reserved())
+	}
+	return reserved
+}
+
+// reservationsOn returns the reservations held on backend.
+func reservationsOn(reservations []rep.CapacityReservation, backend string) []rep.CapacityReservation {
+	var on []rep.CapacityReservation
+	for i := range reservations {
+		if reservations[i].Backend == backend {
+			on = append(on, reservations[i])
+		}
+	}
+	return on
+}
+
+// oneReservedInstance returns the capacity reservation holds for one of its
+// instances.
+func oneReservedInstance(reservation *rep.CapacityReservation) rep.Resources {
+	return rep.Resources{
+		MemoryMB:   reservation.Resource.MemoryMB,
+		DiskMB:     reservation.Resource.DiskMB,
+		Containers: 1,
+	}
+}
+
+// fitsRemaining reports whether required fits in remaining, what is left of
+// a backend. The executor turns down the containers that do not fit in what
+// is left of it, so besides the memory the cell fits its work in, the disk
+// and containers are only checked when reservations hold capacity on it.
+func fitsRemaining(required, remaining rep.Resources, reserved bool) bool {
+	if !reserved {
+		return required.MemoryMB <= remaining.MemoryMB
+	}
+	return fits(required, remaining) == nil
+}
+
+// allocatedLRPs returns the LRPs of lrpRequests that are not in failed.
+func allocatedLRPs(lrpRequests [][]rep.LRP, failed rep.Work) []rep.LRP {
+	unplaced := rep.NewStringSet()
+	for i := range failed.LRPs {
+		unplaced[failed.LRPs[i].Identifier()] = struct{}{}
+	}
+
+	var allocated []rep.LRP
+	for _, lrps := range lrpRequests {
+		for i := range lrps {
+			if !unplaced.Contains(lrps[i].Identifier()) {
+				allocated = append(allocated, lrps[i])
+			}
+		}
+	}
+	return allocated
+}
+
+// settleReservations claims the places that the lrps allocated were taken
+// with, as recorded by claims against the identifier of each LRP, and
+// returns the places of the others to their reservation.
+func settleReservations(reservations *CapacityReservations, claims map[string]string, allocated []rep.LRP) {
+	placed := rep.NewStringSet()
+	for i := range allocated {
+		placed[allocated[i].Identifier()] = struct{}{}
+	}
+
+	for identifier, id := range claims {
+		if placed.Contains(identifier) {
+			reservations.Claim(id)
+		} else {
+			reservations.Return(id)
+		}
+	}
+}
+
+// withoutReserved returns available less the capacity held by reservations,
+// never going below zero.
+func withoutReserved(available rep.Resources, reservations []rep.CapacityReservation) rep.Resources {
+	available.Add(negated(reservedResources(reservations)))
+	if available.MemoryMB < 0 {
+		available.MemoryMB = 0
+	}
+	if available.DiskMB < 0 {
+		available.DiskMB = 0
+	}
+	if available.Containers < 0 {
+		available.Containers = 0
+	}
+	return available
+}
+
+func negated(resources rep.Resources) rep.Resources {
+	return rep.Resources{
+		MemoryMB:   -resources.MemoryMB,
+		DiskMB:     -resources.DiskMB,
+		Containers: -resources.Containers,
+	}
+}
+
+func fits(required, available rep.Resources) error {
+	problems := map[string]struct{}{}
+	if required.MemoryMB > available.MemoryMB {
+		problems["memory"] = struct{}{}
+	}
+	if required.DiskMB > available.DiskMB {
+		problems["disk"] = struct{}{}
+	}
+	if required.Containers > available.Containers {
+		problems["containers"] = struct{}{}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return rep.InsufficientResourcesError{Problems: problems}
+}
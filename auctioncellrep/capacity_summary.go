@@ -0,0 +1,75 @@
+package auctioncellrep
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// CapacitySummary summarizes the capacity of the cell from the resources its
+// executor backends account for and the capacity reserved on them, without
+// listing their containers. The disk quota growth is subtracted as last
+// recorded, and the CPU entitlements, which are derived from the containers,
+// are left out.
+func (a *AuctionCellRep) CapacitySummary(ctx context.Context, logger lager.Logger) (rep.CapacitySummary, bool, error) {
+	logger = logger.Session("capacity-summary")
+
+	var reservations []rep.CapacityReservation
+	if a.reservations != nil {
+		reservations = a.reservations.Active()
+	}
+
+	availableResources := rep.Resources{}
+	totalResources := rep.Resources{}
+	rootFSProviders := rep.RootFSProviders{}
+	healthy := true
+	for _, backend := range a.backends() {
+		if err := ctx.Err(); err != nil {
+			logger.Error("capacity-summary-cancelled", err)
+			return rep.CapacitySummary{}, false, err
+		}
+
+		backendLogger := logger
+		if len(a.additionalBackends) > 0 {
+			backendLogger = logger.WithData(lager.Data{"backend": backend.Name})
+		}
+
+		total, err := backend.Client.TotalResources(backendLogger)
+		if err != nil {
+			backendLogger.Error("failed-to-get-total-resources", err)
+			return rep.CapacitySummary{}, false, err
+		}
+
+		available, err := backend.Client.RemainingResources(backendLogger)
+		if err != nil {
+			backendLogger.Error("failed-to-get-remaining-resource", err)
+			return rep.CapacitySummary{}, false, err
+		}
+		if backend.Name == DefaultBackendName {
+			available.DiskMB -= int(a.diskGrowth.total())
+		}
+
+		availableResources.Add(withoutReserved(a.convertResources(available), reservationsOn(reservations, backend.Name)))
+		totalResources.Add(a.convertResources(total))
+		rootFSProviders = rootFSProviders.Merge(backend.RootFSProviders)
+
+		if !backend.Client.Healthy(backendLogger) {
+			healthy = false
+		}
+	}
+
+	tags := a.placementTags.get()
+	return rep.CapacitySummary{
+		CellID:                a.cellID,
+		Zone:                  a.zone,
+		RootFSProviders:       rootFSProviders,
+		AvailableResources:    availableResources,
+		TotalResources:        totalResources,
+		Evacuating:            a.evacuationReporter.Evacuating(),
+		Maintenance:           a.maintenanceReporter.InMaintenance(),
+		Cordoned:              a.cordoned(),
+		PlacementTags:         tags.Required(),
+		OptionalPlacementTags: tags.OptionalPlacementTags,
+	}, healthy, nil
+}
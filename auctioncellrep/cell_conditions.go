@@ -0,0 +1,56 @@
+package auctioncellrep
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter -o auctioncellrepfakes/fake_cell_condition.go . CellCondition
+
+// CellCondition reports a condition of the cell, such as the cell losing its
+// presence, that the cell reports in its state for the auctioneer and
+// operators to take into account. Only Garden being unresponsive makes the
+// cell unhealthy. Condition is empty while the condition does not hold.
+type CellCondition interface {
+	Condition() string
+}
+
+// healthReport remembers what the cell last reported about its health, so
+// that a change is logged once rather than on every state.
+type healthReport struct {
+	lock       sync.Mutex
+	unhealthy  []string
+	conditions []string
+}
+
+func (r *healthReport) update(logger lager.Logger, unhealthyReasons, conditions []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !sameReasons(r.unhealthy, unhealthyReasons) {
+		if len(unhealthyReasons) > 0 {
+			logger.Error("cell-became-unhealthy", nil, lager.Data{"reasons": unhealthyReasons})
+		} else {
+			logger.Info("cell-became-healthy")
+		}
+		r.unhealthy = unhealthyReasons
+	}
+
+	if !sameReasons(r.conditions, conditions) {
+		logger.Info("cell-conditions-changed", lager.Data{"conditions": conditions})
+		r.conditions = conditions
+	}
+}
+
+func sameReasons(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
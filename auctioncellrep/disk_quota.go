@@ -0,0 +1,192 @@
+package auctioncellrep
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+var ErrDiskQuotaGrowthUnsupported = errors.New("the executor backend cannot grow disk quotas in place")
+var ErrContainerNotRunning = errors.New("the container is not running")
+
+//go:generate counterfeiter -o auctioncellrepfakes/fake_disk_quota_grower.go . DiskQuotaGrower
+
+// DiskQuotaGrower grows the disk quota of a running container on the primary
+// executor backend without restarting it.
+type DiskQuotaGrower interface {
+	GrowDiskQuota(logger lager.Logger, guid string, diskMB int) error
+}
+
+// DiskQuotaGrowth remembers how far the disk quota of containers was grown
+// beyond what the executor allocated for them, as the executor keeps
+// accounting for the allocated quota only. The growth is saved to a file, if
+// one is given, so that a restarted rep keeps accounting for the disk the
+// containers it finds running were grown to.
+type DiskQuotaGrowth struct {
+	path string
+
+	lock  sync.Mutex
+	grown map[string]int32
+}
+
+// NewDiskQuotaGrowth returns the growth saved at path, or no growth when
+// there is no file at path. The growth is only kept in memory when path is
+// empty.
+func NewDiskQuotaGrowth(logger lager.Logger, path string) (*DiskQuotaGrowth, error) {
+	growth := &DiskQuotaGrowth{path: path, grown: map[string]int32{}}
+	if path == "" {
+		return growth, nil
+	}
+
+	logger = logger.Session("load-disk-quota-growth", lager.Data{"path": path})
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return growth, nil
+	}
+	if err != nil {
+		logger.Error("failed-to-read", err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(contents, &growth.grown)
+	if err != nil {
+		logger.Error("failed-to-unmarshal", err)
+		return nil, err
+	}
+	return growth, nil
+}
+
+func (g *DiskQuotaGrowth) of(guid string) int32 {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.grown[guid]
+}
+
+// record saves the growth of the container with guid. The growth is
+// recorded once the quota has grown, so it is kept in memory even when it
+// cannot be saved.
+func (g *DiskQuotaGrowth) record(logger lager.Logger, guid string, diskMB int32) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.grown[guid] = diskMB
+	return g.save(logger)
+}
+
+func (g *DiskQuotaGrowth) total() int32 {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	total := int32(0)
+	for _, grown := range g.grown {
+		total += grown
+	}
+	return total
+}
+
+// apply adds the growth to the disk of containers and returns the total
+// growth, forgetting the growth of containers that no longer exist.
+func (g *DiskQuotaGrowth) apply(logger lager.Logger, containers []executor.Container) int32 {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	existing := make(map[string]int32, len(g.grown))
+	total := int32(0)
+	for i := range containers {
+		if grown, ok := g.grown[containers[i].Guid]; ok {
+			existing[containers[i].Guid] = grown
+			containers[i].DiskMB += int(grown)
+			total += grown
+		}
+	}
+	forgotten := len(existing) != len(g.grown)
+	g.grown = existing
+	if forgotten {
+		g.save(logger)
+	}
+	return total
+}
+
+// save writes the growth to the file of g in one step, so that it is never
+// read half written. It must be called with the lock held.
+func (g *DiskQuotaGrowth) save(logger lager.Logger) error {
+	if g.path == "" {
+		return nil
+	}
+	logger = logger.Session("save-disk-quota-growth", lager.Data{"path": g.path})
+
+	payload, err := json.Marshal(g.grown)
+	if err != nil {
+		logger.Error("failed-to-marshal", err)
+		return err
+	}
+
+	err = ioutil.WriteFile(g.path+".tmp", payload, 0600)
+	if err != nil {
+		logger.Error("failed-to-write", err)
+		return err
+	}
+
+	err = os.Rename(g.path+".tmp", g.path)
+	if err != nil {
+		logger.Error("failed-to-move", err)
+		return err
+	}
+	return nil
+}
+
+// GrowDiskQuota grows the disk quota of the running container with guid on
+// the primary executor to diskMB, taking the additional disk from the cell.
+func (a *AuctionCellRep) GrowDiskQuota(logger lager.Logger, guid string, diskMB int32) error {
+	logger = logger.Session("grow-disk-quota", lager.Data{"container-guid": guid, "disk-mb": diskMB})
+
+	if a.diskQuotaGrower == nil {
+		return ErrDiskQuotaGrowthUnsupported
+	}
+
+	container, err := a.client.GetContainer(logger, guid)
+	if err != nil {
+		logger.Error("failed-to-get-container", err)
+		return err
+	}
+	if container.State != executor.StateRunning {
+		return ErrContainerNotRunning
+	}
+
+	grown := a.diskGrowth.of(guid)
+	currentDiskMB := int32(container.DiskMB) + grown
+	if diskMB <= currentDiskMB {
+		return rep.ErrInvalidDiskQuotaGrowth
+	}
+
+	remainingResources, err := a.client.RemainingResources(logger)
+	if err != nil {
+		logger.Error("failed-gathering-remaining-resources", err)
+		return err
+	}
+	if available := int32(remainingResources.DiskMB) - a.diskGrowth.total(); diskMB-currentDiskMB > available {
+		return rep.InsufficientResourcesError{Problems: map[string]struct{}{"disk": {}}}
+	}
+
+	err = a.diskQuotaGrower.GrowDiskQuota(logger, guid, int(diskMB))
+	if err != nil {
+		logger.Error("failed-to-grow-disk-quota", err)
+		return err
+	}
+
+	// the grown quota is enforced already, so a growth that cannot be saved
+	// is still accounted for until the rep restarts
+	err = a.diskGrowth.record(logger, guid, diskMB-int32(container.DiskMB))
+	if err != nil {
+		logger.Error("failed-to-save-disk-quota-growth", err)
+	}
+	logger.Info("grew-disk-quota")
+	return nil
+}
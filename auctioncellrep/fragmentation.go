@@ -0,0 +1,39 @@
+package auctioncellrep
+
+import (
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Fragmentation scores how much of the free capacity of the cell no single
+// instance can use, and which LRP instances a rebalancer could move off the
+// cell to make it usable.
+func (a *AuctionCellRep) Fragmentation(logger lager.Logger) (rep.FragmentationReport, error) {
+	logger = logger.Session("fragmentation")
+
+	pools := []rep.FragmentationPool{}
+	for _, backend := range a.backends() {
+		backendLogger := logger
+		if len(a.additionalBackends) > 0 {
+			backendLogger = logger.WithData(lager.Data{"backend": backend.Name})
+		}
+
+		backendState, lrps, _, _, err := a.backendState(backendLogger, backend)
+		if err != nil {
+			return rep.FragmentationReport{}, err
+		}
+
+		available := backendState.AvailableResources
+		if a.reservations != nil {
+			available = withoutReserved(available, reservationsOn(a.reservations.Active(), backend.Name))
+		}
+
+		pools = append(pools, rep.FragmentationPool{
+			Backend:   backend.Name,
+			Available: available,
+			LRPs:      lrps,
+		})
+	}
+
+	return rep.AnalyzeFragmentation(pools, a.maxContainerResource), nil
+}
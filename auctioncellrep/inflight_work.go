@@ -0,0 +1,150 @@
+package auctioncellrep
+
+import (
+	"sort"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+type lrpInstanceKey struct {
+	processGuid string
+	index       int32
+}
+
+// inFlightWork holds the LRP instances and tasks of the performs in progress,
+// so that the auctioneer racing itself cannot have the same instance or task
+// allocated twice, along with the static host ports they request, which the
+// state of the cell only shows once their containers are allocated.
+type inFlightWork struct {
+	lock      sync.Mutex
+	lrps      map[lrpInstanceKey]struct{}
+	tasks     map[string]struct{}
+	hostPorts map[uint16]struct{}
+}
+
+func newInFlightWork() *inFlightWork {
+	return &inFlightWork{
+		lrps:      map[lrpInstanceKey]struct{}{},
+		tasks:     map[string]struct{}{},
+		hostPorts: map[uint16]struct{}{},
+	}
+}
+
+// claim returns the part of work no other perform holds, and holds it until
+// it is released. The rest of work is moved into failed.
+func (w *inFlightWork) claim(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	claimed := rep.Work{CellID: work.CellID}
+
+	for _, lrp := range work.LRPs {
+		key := lrpInstanceKey{lrp.ProcessGuid, lrp.Index}
+		if _, held := w.lrps[key]; held {
+			logger.Info("rejecting-duplicate-lrp", lager.Data{"process-guid": lrp.ProcessGuid, "index": lrp.Index})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.DuplicateWorkFailures = append(failed.DuplicateWorkFailures, rep.DuplicateWorkFailure{
+				ProcessGuid: lrp.ProcessGuid,
+				Index:       lrp.Index,
+				Error:       rep.ErrDuplicateWork.Error(),
+			})
+			continue
+		}
+		w.lrps[key] = struct{}{}
+		claimed.LRPs = append(claimed.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		if _, held := w.tasks[task.TaskGuid]; held {
+			logger.Info("rejecting-duplicate-task", lager.Data{"task-guid": task.TaskGuid})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.DuplicateWorkFailures = append(failed.DuplicateWorkFailures, rep.DuplicateWorkFailure{
+				TaskGuid: task.TaskGuid,
+				Error:    rep.ErrDuplicateWork.Error(),
+			})
+			continue
+		}
+		w.tasks[task.TaskGuid] = struct{}{}
+		claimed.Tasks = append(claimed.Tasks, task)
+	}
+
+	return claimed
+}
+
+func (w *inFlightWork) release(claimed rep.Work) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, lrp := range claimed.LRPs {
+		delete(w.lrps, lrpInstanceKey{lrp.ProcessGuid, lrp.Index})
+	}
+	for _, task := range claimed.Tasks {
+		delete(w.tasks, task.TaskGuid)
+	}
+}
+
+// holdHostPorts returns the part of work whose static host ports no other
+// perform, nor work before it, holds, and holds those ports until they are
+// released. The rest of work is moved into failed. The ports are held before
+// the work is checked against the state of the cell, so that a concurrent
+// perform either sees them held or finds their containers allocated.
+func (w *inFlightWork) holdHostPorts(logger lager.Logger, work rep.Work, failed *rep.Work) (rep.Work, []uint16) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	held := []uint16{}
+	hold := func(res *rep.Resource) error {
+		conflicts := []uint16{}
+		for _, port := range res.StaticHostPorts {
+			if _, ok := w.hostPorts[port]; ok {
+				conflicts = append(conflicts, port)
+			}
+		}
+		if len(conflicts) > 0 {
+			sort.Slice(conflicts, func(i, j int) bool { return conflicts[i] < conflicts[j] })
+			return rep.HostPortConflictError{Ports: conflicts}
+		}
+		for _, port := range res.StaticHostPorts {
+			w.hostPorts[port] = struct{}{}
+			held = append(held, port)
+		}
+		return nil
+	}
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if err := hold(&lrp.Resource); err != nil {
+			logger.Info("rejecting-lrp-with-held-host-ports", lager.Data{"instance-guid": lrp.InstanceGUID, "error": err.Error()})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.HostPortConflictFailures = append(failed.HostPortConflictFailures, rep.HostPortConflictFailure{InstanceGUID: lrp.InstanceGUID, Error: err.(rep.HostPortConflictError)})
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		if err := hold(&task.Resource); err != nil {
+			logger.Info("rejecting-task-with-held-host-ports", lager.Data{"task-guid": task.TaskGuid, "error": err.Error()})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.HostPortConflictFailures = append(failed.HostPortConflictFailures, rep.HostPortConflictFailure{TaskGuid: task.TaskGuid, Error: err.(rep.HostPortConflictError)})
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid, held
+}
+
+func (w *inFlightWork) releaseHostPorts(held []uint16) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, port := range held {
+		delete(w.hostPorts, port)
+	}
+}
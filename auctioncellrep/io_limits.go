@@ -0,0 +1,64 @@
+package auctioncellrep
+
+import (
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o auctioncellrepfakes/fake_io_limit_reporter.go . IOLimitReporter
+
+// IOLimitReporter reports the kinds of I/O limits a cell applies to its
+// containers, and the limits it applied to each container by its guid.
+type IOLimitReporter interface {
+	SupportedIOLimits() []string
+	AppliedIOLimits(guid string) (rep.IOLimits, bool)
+}
+
+// rejectUnsupportedIOLimits fails the work with network limits unless the
+// cell applies them, so that the work does not run unthrottled on the
+// network it asked to be limited on. Disk limits stay applied where the
+// cell supports them.
+func (a *AuctionCellRep) rejectUnsupportedIOLimits(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	if a.supportsIOLimit(rep.IOLimitKindNetwork) {
+		return work
+	}
+
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if limitsNetwork(lrp.IOLimits) {
+			logger.Info("rejecting-lrp-with-unsupported-network-limits", lager.Data{"instance-guid": lrp.InstanceGUID})
+			failed.LRPs = append(failed.LRPs, lrp)
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+	for _, task := range work.Tasks {
+		if limitsNetwork(task.IOLimits) {
+			logger.Info("rejecting-task-with-unsupported-network-limits", lager.Data{"task-guid": task.TaskGuid})
+			failed.Tasks = append(failed.Tasks, task)
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+func (a *AuctionCellRep) supportsIOLimit(kind string) bool {
+	if a.ioLimits == nil {
+		return false
+	}
+	for _, supported := range a.ioLimits.SupportedIOLimits() {
+		if supported == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func limitsNetwork(limits *rep.IOLimits) bool {
+	return limits != nil && !limits.Network().Empty()
+}
@@ -0,0 +1,9 @@
+package auctioncellrep
+
+//go:generate counterfeiter -o auctioncellrepfakes/fake_log_drop_counter.go . LogDropCounter
+
+// LogDropCounter counts the log output of containers a cell that rate limits
+// their logs dropped, by the log guid and index of the container.
+type LogDropCounter interface {
+	DroppedBytes(logGuid string, index int) int64
+}
@@ -0,0 +1,60 @@
+package auctioncellrep
+
+import (
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+// MaintenanceSchedule holds the maintenance windows operators planned for the
+// cell. Within the lead time before a window, and during it, the cell asks to
+// be scored worse for long-running work so that it is placed elsewhere.
+type MaintenanceSchedule struct {
+	clock        clock.Clock
+	windows      []rep.MaintenanceWindow
+	leadTime     time.Duration
+	scorePenalty float64
+}
+
+func NewMaintenanceSchedule(clock clock.Clock, windows []rep.MaintenanceWindow, leadTime time.Duration, scorePenalty float64) *MaintenanceSchedule {
+	sorted := append([]rep.MaintenanceWindow{}, windows...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	return &MaintenanceSchedule{
+		clock:        clock,
+		windows:      sorted,
+		leadTime:     leadTime,
+		scorePenalty: scorePenalty,
+	}
+}
+
+// Upcoming returns the windows that have not ended yet, earliest first.
+func (s *MaintenanceSchedule) Upcoming() []rep.MaintenanceWindow {
+	now := s.clock.Now()
+
+	upcoming := []rep.MaintenanceWindow{}
+	for _, window := range s.windows {
+		if window.End.After(now) {
+			upcoming = append(upcoming, window)
+		}
+	}
+	return upcoming
+}
+
+// ScorePenalty returns the score penalty for long-running work when a window
+// starts within the lead time or is ongoing, and 0 otherwise.
+func (s *MaintenanceSchedule) ScorePenalty() float64 {
+	upcoming := s.Upcoming()
+	if len(upcoming) == 0 {
+		return 0
+	}
+
+	if upcoming[0].Start.Sub(s.clock.Now()) > s.leadTime {
+		return 0
+	}
+	return s.scorePenalty
+}
@@ -0,0 +1,175 @@
+package auctioncellrep
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+var ErrPlacementBlockNotFound = errors.New("placement block not found")
+
+// placementBlocks holds the placement blocks operators put on the cell, for
+// instance to keep a noisy neighbour off it. Blocks expire after their ttl,
+// which is measured on the monotonic clock so that the wall clock of the cell
+// being stepped neither lifts them early nor keeps them longer.
+type placementBlocks struct {
+	clock clock.Clock
+
+	lock   sync.Mutex
+	blocks map[string]heldBlock
+}
+
+type heldBlock struct {
+	rep.PlacementBlock
+	expires time.Time
+}
+
+func newPlacementBlocks(clock clock.Clock) *placementBlocks {
+	return &placementBlocks{
+		clock:  clock,
+		blocks: map[string]heldBlock{},
+	}
+}
+
+// block records a block for request lasting ttl.
+func (p *placementBlocks) block(request rep.PlacementBlockRequest, ttl time.Duration) (rep.PlacementBlock, error) {
+	err := request.Validate()
+	if err != nil {
+		return rep.PlacementBlock{}, err
+	}
+	if ttl <= 0 {
+		return rep.PlacementBlock{}, rep.ErrDeadlinePassed
+	}
+
+	id, err := GenerateGuid()
+	if err != nil {
+		return rep.PlacementBlock{}, err
+	}
+
+	expires := p.clock.Now().Add(ttl)
+	block := rep.PlacementBlock{
+		ID:          id,
+		ProcessGuid: request.ProcessGuid,
+		Domain:      request.Domain,
+		Reason:      request.Reason,
+		ExpiresAt:   expires.UnixNano(),
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.blocks[id] = heldBlock{PlacementBlock: block, expires: expires}
+	return block, nil
+}
+
+func (p *placementBlocks) unblock(id string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.blocks[id]; !ok {
+		return ErrPlacementBlockNotFound
+	}
+	delete(p.blocks, id)
+	return nil
+}
+
+// active returns the blocks that have not expired, soonest to expire first,
+// forgetting the expired ones.
+func (p *placementBlocks) active() []rep.PlacementBlock {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.clock.Now()
+
+	held := make([]heldBlock, 0, len(p.blocks))
+	for id, block := range p.blocks {
+		if !now.Before(block.expires) {
+			delete(p.blocks, id)
+			continue
+		}
+		held = append(held, block)
+	}
+
+	sort.Slice(held, func(i, j int) bool {
+		if held[i].expires.Equal(held[j].expires) {
+			return held[i].ID < held[j].ID
+		}
+		return held[i].expires.Before(held[j].expires)
+	})
+
+	active := make([]rep.PlacementBlock, len(held))
+	for i := range held {
+		active[i] = held[i].PlacementBlock
+	}
+	return active
+}
+
+// BlockPlacement keeps the work request names off the cell until the block
+// expires or is lifted.
+func (a *AuctionCellRep) BlockPlacement(logger lager.Logger, request rep.PlacementBlockRequest) (rep.PlacementBlock, error) {
+	logger = logger.Session("block-placement", lager.Data{"process-guid": request.ProcessGuid, "domain": request.Domain})
+
+	block, err := a.placementBlocks.block(request, a.ttl(logger, request.TTLSeconds, request.Deadline))
+	if err != nil {
+		logger.Error("failed-to-block-placement", err)
+		return rep.PlacementBlock{}, err
+	}
+
+	logger.Info("blocked", lager.Data{"block-id": block.ID, "reason": block.Reason})
+	return block, nil
+}
+
+// UnblockPlacement lifts a placement block before it expires.
+func (a *AuctionCellRep) UnblockPlacement(logger lager.Logger, blockID string) error {
+	logger = logger.Session("unblock-placement", lager.Data{"block-id": blockID})
+
+	err := a.placementBlocks.unblock(blockID)
+	if err != nil {
+		logger.Error("failed-to-unblock-placement", err)
+		return err
+	}
+
+	logger.Info("unblocked")
+	return nil
+}
+
+// PlacementBlocks returns the placement blocks that have not expired.
+func (a *AuctionCellRep) PlacementBlocks(logger lager.Logger) []rep.PlacementBlock {
+	return a.placementBlocks.active()
+}
+
+// rejectBlockedWork moves the LRPs and tasks of work the placement blocks
+// keep off the cell into failed.
+func (a *AuctionCellRep) rejectBlockedWork(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	state := rep.CellState{PlacementBlocks: a.placementBlocks.active()}
+	if len(state.PlacementBlocks) == 0 {
+		return work
+	}
+
+	allowed := rep.Work{CellID: work.CellID}
+
+	for _, lrp := range work.LRPs {
+		if state.PlacementBlocked(lrp.ProcessGuid, lrp.Domain) {
+			logger.Info("rejecting-blocked-lrp", lager.Data{"process-guid": lrp.ProcessGuid, "domain": lrp.Domain})
+			failed.LRPs = append(failed.LRPs, lrp)
+			continue
+		}
+		allowed.LRPs = append(allowed.LRPs, lrp)
+	}
+
+	for _, task := range work.Tasks {
+		if state.PlacementBlocked("", task.Domain) {
+			logger.Info("rejecting-blocked-task", lager.Data{"task-guid": task.TaskGuid, "domain": task.Domain})
+			failed.Tasks = append(failed.Tasks, task)
+			continue
+		}
+		allowed.Tasks = append(allowed.Tasks, task)
+	}
+
+	return allowed
+}
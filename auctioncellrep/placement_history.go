@@ -0,0 +1,191 @@
+package auctioncellrep
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+// PlacementHistory counts, by hour, the LRP instances and tasks auctioned to
+// the cell by resource shape and outcome, and the peak utilization of the
+// cell, for the retention hours that the capacity report covers.
+type PlacementHistory struct {
+	clock     clock.Clock
+	retention int
+
+	lock  sync.Mutex
+	hours map[time.Time]*placementHour
+}
+
+type placementHour struct {
+	placements map[placementKey]int
+	peak       rep.Utilization
+}
+
+// placementKey identifies the placements of one kind and shape, accepted when
+// reason is empty and rejected for reason otherwise.
+type placementKey struct {
+	kind   string
+	shape  string
+	reason string
+}
+
+func NewPlacementHistory(clock clock.Clock, retentionHours int) *PlacementHistory {
+	return &PlacementHistory{
+		clock:     clock,
+		retention: retentionHours,
+		hours:     map[time.Time]*placementHour{},
+	}
+}
+
+// Record counts a placement of kind, rep.LRPLifecycle or rep.TaskLifecycle,
+// that was accepted when reason is empty and rejected for reason otherwise.
+func (h *PlacementHistory) Record(kind string, resource rep.Resource, reason string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	key := placementKey{kind: kind, shape: rep.PlacementShape(resource.MemoryMB, resource.DiskMB), reason: reason}
+	h.currentHour().placements[key]++
+}
+
+// SampleUtilization raises the peak utilization of the current hour to that
+// of total resources of which available are free, if it is higher.
+func (h *PlacementHistory) SampleUtilization(total, available rep.Resources) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	hour := h.currentHour()
+	hour.peak = hour.peak.Max(rep.NewUtilization(total, available))
+}
+
+// CapacityReport summarizes the last hours hours, including the current one.
+// It covers the whole retention when hours is not positive or exceeds it.
+func (h *PlacementHistory) CapacityReport(hours int) rep.CapacityReport {
+	if hours <= 0 || hours > h.retention {
+		hours = h.retention
+	}
+	since := h.clock.Now().Truncate(time.Hour).Add(-time.Duration(hours-1) * time.Hour)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	counts := map[placementKey]int{}
+	peak := rep.Utilization{}
+	for start, hour := range h.hours {
+		if start.Before(since) {
+			continue
+		}
+		for key, count := range hour.placements {
+			counts[key] += count
+		}
+		peak = peak.Max(hour.peak)
+	}
+
+	summaries := map[placementKey]*rep.PlacementSummary{}
+	for key, count := range counts {
+		shapeKey := placementKey{kind: key.kind, shape: key.shape}
+		summary, ok := summaries[shapeKey]
+		if !ok {
+			summary = &rep.PlacementSummary{Kind: key.kind, Shape: key.shape}
+			summaries[shapeKey] = summary
+		}
+		if key.reason == "" {
+			summary.Accepted += count
+			continue
+		}
+		if summary.Rejected == nil {
+			summary.Rejected = map[string]int{}
+		}
+		summary.Rejected[key.reason] += count
+	}
+
+	placements := make([]rep.PlacementSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		placements = append(placements, *summary)
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].Kind != placements[j].Kind {
+			return placements[i].Kind < placements[j].Kind
+		}
+		return placements[i].Shape < placements[j].Shape
+	})
+
+	return rep.CapacityReport{
+		Since:           since,
+		Hours:           hours,
+		Placements:      placements,
+		PeakUtilization: peak,
+	}
+}
+
+// currentHour returns the counts of the current hour, forgetting the hours
+// past the retention. It must be called with the lock held.
+func (h *PlacementHistory) currentHour() *placementHour {
+	now := h.clock.Now().Truncate(time.Hour)
+	hour, ok := h.hours[now]
+	if !ok {
+		hour = &placementHour{placements: map[placementKey]int{}}
+		h.hours[now] = hour
+
+		cutoff := now.Add(-time.Duration(h.retention-1) * time.Hour)
+		for start := range h.hours {
+			if start.Before(cutoff) {
+				delete(h.hours, start)
+			}
+		}
+	}
+	return hour
+}
+
+// rejectionReasons attributes the LRP instances and tasks a perform moves
+// into its failed work to the step that rejected them.
+type rejectionReasons struct {
+	lrps        int
+	tasks       int
+	lrpReasons  map[string]string
+	taskReasons map[string]string
+}
+
+func newRejectionReasons() *rejectionReasons {
+	return &rejectionReasons{lrpReasons: map[string]string{}, taskReasons: map[string]string{}}
+}
+
+// mark attributes the work added to failed since the last mark to reason.
+func (r *rejectionReasons) mark(failed *rep.Work, reason string) {
+	for _, lrp := range failed.LRPs[r.lrps:] {
+		if _, ok := r.lrpReasons[lrp.InstanceGUID]; !ok {
+			r.lrpReasons[lrp.InstanceGUID] = reason
+		}
+	}
+	for _, task := range failed.Tasks[r.tasks:] {
+		if _, ok := r.taskReasons[task.TaskGuid]; !ok {
+			r.taskReasons[task.TaskGuid] = reason
+		}
+	}
+	r.lrps, r.tasks = len(failed.LRPs), len(failed.Tasks)
+}
+
+// recordPlacements counts the outcome of every LRP instance and task of
+// requested in the placement history. All of them are rejected for reason
+// when it is not empty.
+func (a *AuctionCellRep) recordPlacements(requested rep.Work, rejected *rejectionReasons, reason string) {
+	if a.placementHistory == nil {
+		return
+	}
+
+	outcome := func(reasons map[string]string, guid string) string {
+		if reason != "" {
+			return reason
+		}
+		return reasons[guid]
+	}
+	for _, lrp := range requested.LRPs {
+		a.placementHistory.Record(rep.LRPLifecycle, lrp.Resource, outcome(rejected.lrpReasons, lrp.InstanceGUID))
+	}
+	for _, task := range requested.Tasks {
+		a.placementHistory.Record(rep.TaskLifecycle, task.Resource, outcome(rejected.taskReasons, task.TaskGuid))
+	}
+}
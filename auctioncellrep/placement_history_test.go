@@ -0,0 +1,76 @@
+package auctioncellrep_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlacementHistory", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		history   *auctioncellrep.PlacementHistory
+		small     rep.Resource
+		large     rep.Resource
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Date(2026, 10, 14, 12, 30, 0, 0, time.UTC))
+		history = auctioncellrep.NewPlacementHistory(fakeClock, 3)
+		small = rep.NewResource(256, 1024, 0)
+		large = rep.NewResource(4000, 8000, 0)
+	})
+
+	It("counts the placements by kind, shape and outcome", func() {
+		history.Record(rep.LRPLifecycle, small, "")
+		history.Record(rep.LRPLifecycle, small, "")
+		history.Record(rep.LRPLifecycle, small, rep.PlacementReasonDuplicate)
+		history.Record(rep.LRPLifecycle, large, rep.PlacementReasonInsufficientResources)
+		history.Record(rep.TaskLifecycle, small, "")
+
+		report := history.CapacityReport(0)
+		Expect(report.Hours).To(Equal(3))
+		Expect(report.Since).To(Equal(time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)))
+		Expect(report.Placements).To(Equal([]rep.PlacementSummary{
+			{Kind: rep.LRPLifecycle, Shape: "256MB/1024MB", Accepted: 2, Rejected: map[string]int{rep.PlacementReasonDuplicate: 1}},
+			{Kind: rep.LRPLifecycle, Shape: "4096MB/8192MB", Rejected: map[string]int{rep.PlacementReasonInsufficientResources: 1}},
+			{Kind: rep.TaskLifecycle, Shape: "256MB/1024MB", Accepted: 1},
+		}))
+	})
+
+	It("reports the peak utilization of the hours it covers", func() {
+		total := rep.NewResources(1000, 2000, 10)
+		history.SampleUtilization(total, rep.NewResources(500, 2000, 10))
+		history.SampleUtilization(total, rep.NewResources(900, 1000, 8))
+
+		Expect(history.CapacityReport(1).PeakUtilization).To(Equal(rep.Utilization{Memory: 0.5, Disk: 0.5, Containers: 0.2}))
+	})
+
+	It("covers only the hours asked for", func() {
+		history.Record(rep.LRPLifecycle, small, "")
+		fakeClock.Increment(time.Hour)
+		history.Record(rep.LRPLifecycle, small, "")
+
+		Expect(history.CapacityReport(1).Placements).To(Equal([]rep.PlacementSummary{
+			{Kind: rep.LRPLifecycle, Shape: "256MB/1024MB", Accepted: 1},
+		}))
+		Expect(history.CapacityReport(2).Placements).To(Equal([]rep.PlacementSummary{
+			{Kind: rep.LRPLifecycle, Shape: "256MB/1024MB", Accepted: 2},
+		}))
+	})
+
+	It("forgets the hours past the retention", func() {
+		history.Record(rep.LRPLifecycle, small, "")
+		fakeClock.Increment(3 * time.Hour)
+		history.Record(rep.TaskLifecycle, small, "")
+
+		Expect(history.CapacityReport(24).Placements).To(Equal([]rep.PlacementSummary{
+			{Kind: rep.TaskLifecycle, Shape: "256MB/1024MB", Accepted: 1},
+		}))
+	})
+})
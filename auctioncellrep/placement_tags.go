@@ -0,0 +1,114 @@
+package auctioncellrep
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+	"github.com/tedsuo/ifrit"
+)
+
+// placementTags holds the placement tags the cell advertises, which operators
+// may change while the cell runs.
+type placementTags struct {
+	lock sync.RWMutex
+	tags rep.CellPlacementTags
+}
+
+func newPlacementTags(tags rep.CellPlacementTags) *placementTags {
+	return &placementTags{tags: tags}
+}
+
+func (p *placementTags) get() rep.CellPlacementTags {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.tags
+}
+
+func (p *placementTags) set(tags rep.CellPlacementTags) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.tags = tags
+}
+
+// PlacementTags returns the placement tags the cell currently advertises.
+func (a *AuctionCellRep) PlacementTags(logger lager.Logger) rep.CellPlacementTags {
+	return a.placementTags.get()
+}
+
+// UpdatePlacementTags changes the placement tags the cell advertises in its
+// subsequent states. Resident LRP instances and tasks keep running; when
+// validate is set, those the tags no longer match are returned.
+func (a *AuctionCellRep) UpdatePlacementTags(ctx context.Context, logger lager.Logger, tags rep.CellPlacementTags, validate bool) (rep.PlacementTagsUpdate, error) {
+	logger = logger.Session("update-placement-tags", lager.Data{"placement-tags": tags.PlacementTags, "optional-placement-tags": tags.OptionalPlacementTags, "isolation-segment": tags.IsolationSegment})
+
+	err := tags.Validate()
+	if err != nil {
+		logger.Error("invalid-placement-tags", err)
+		return rep.PlacementTagsUpdate{}, err
+	}
+
+	a.placementTags.set(tags)
+	logger.Info("updated")
+
+	update := rep.PlacementTagsUpdate{CellPlacementTags: tags}
+	if !validate {
+		return update, nil
+	}
+
+	state, _, err := a.State(ctx, logger)
+	if err != nil {
+		logger.Error("failed-to-validate-placement-tags", err)
+		return rep.PlacementTagsUpdate{}, err
+	}
+
+	update.Mismatches = rep.MismatchedPlacementTags(state)
+	if len(update.Mismatches) > 0 {
+		logger.Info("resident-work-no-longer-matches", lager.Data{"mismatches": update.Mismatches})
+	}
+	return update, nil
+}
+
+// PlacementTagsLoadFunc returns the currently configured placement tags.
+type PlacementTagsLoadFunc func() (rep.CellPlacementTags, error)
+
+type placementTagsReloader struct {
+	logger lager.Logger
+	rep    *AuctionCellRep
+	reload <-chan os.Signal
+	load   PlacementTagsLoadFunc
+}
+
+// NewPlacementTagsReloader returns a runner that updates the placement tags of
+// auctionCellRep with those returned by load every time a signal is received
+// on reload, warning about the resident work they no longer match. A failed
+// load leaves the placement tags untouched.
+func NewPlacementTagsReloader(logger lager.Logger, auctionCellRep *AuctionCellRep, reload <-chan os.Signal, load PlacementTagsLoadFunc) ifrit.Runner {
+	return &placementTagsReloader{
+		logger: logger.Session("placement-tags-reloader"),
+		rep:    auctionCellRep,
+		reload: reload,
+		load:   load,
+	}
+}
+
+func (r *placementTagsReloader) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	for {
+		select {
+		case <-signals:
+			return nil
+		case <-r.reload:
+			tags, err := r.load()
+			if err != nil {
+				r.logger.Error("failed-to-reload", err)
+				continue
+			}
+
+			r.rep.UpdatePlacementTags(context.Background(), r.logger, tags, true)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package auctioncellrep
+
+import (
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// rejectInvalidProportions fails the work whose proportional resources
+// cannot be resolved.
+func rejectInvalidProportions(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if lrp.Proportional != nil {
+			if err := lrp.Proportional.Validate(); err != nil {
+				logger.Info("rejecting-lrp-with-invalid-proportions", lager.Data{"instance-guid": lrp.InstanceGUID, "error": err.Error()})
+				failed.LRPs = append(failed.LRPs, lrp)
+				continue
+			}
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+	for _, task := range work.Tasks {
+		if task.Proportional != nil {
+			if err := task.Proportional.Validate(); err != nil {
+				logger.Info("rejecting-task-with-invalid-proportions", lager.Data{"task-guid": task.TaskGuid, "error": err.Error()})
+				failed.Tasks = append(failed.Tasks, task)
+				continue
+			}
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// requestsProportions reports whether some of work requests its resources in
+// proportion to the cell.
+func requestsProportions(work rep.Work) bool {
+	for i := range work.LRPs {
+		if work.LRPs[i].Proportional != nil {
+			return true
+		}
+	}
+	for i := range work.Tasks {
+		if work.Tasks[i].Proportional != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveProportions resolves the proportional resources of work against
+// total, the total resources the backend it is placed on advertises in the
+// state of the cell, so that its containers are allocated the amounts the
+// auctioneer placed it by.
+func resolveProportions(work rep.Work, total rep.Resources) rep.Work {
+	resolved := work
+	resolved.LRPs = make([]rep.LRP, len(work.LRPs))
+	for i := range work.LRPs {
+		resolved.LRPs[i] = work.LRPs[i]
+		resolved.LRPs[i].Resource = work.LRPs[i].Resource.Resolve(&total)
+	}
+	resolved.Tasks = make([]rep.Task, len(work.Tasks))
+	for i := range work.Tasks {
+		resolved.Tasks[i] = work.Tasks[i]
+		resolved.Tasks[i].Resource = work.Tasks[i].Resource.Resolve(&total)
+	}
+	return resolved
+}
@@ -0,0 +1,82 @@
+package auctioncellrep
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+// RecentLRPTracker remembers the LRP instances that ran on the cell within
+// the retention period, so that the cell can advertise them and be preferred
+// when one of them is auctioned again after crashing.
+type RecentLRPTracker struct {
+	clock      clock.Clock
+	retention  time.Duration
+	maxEntries int
+
+	lock     sync.Mutex
+	lastSeen map[rep.RecentLRP]time.Time
+}
+
+func NewRecentLRPTracker(clock clock.Clock, retention time.Duration, maxEntries int) *RecentLRPTracker {
+	return &RecentLRPTracker{
+		clock:      clock,
+		retention:  retention,
+		maxEntries: maxEntries,
+		lastSeen:   map[rep.RecentLRP]time.Time{},
+	}
+}
+
+// Observe records that lrps are running on the cell now.
+func (t *RecentLRPTracker) Observe(lrps []rep.LRP) {
+	now := t.clock.Now()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, lrp := range lrps {
+		t.lastSeen[rep.RecentLRP{ProcessGUID: lrp.ProcessGuid, Index: lrp.Index}] = now
+	}
+}
+
+// Recent returns the instances seen within the retention period, most
+// recently seen first, forgetting the ones seen before it. Only the
+// maxEntries most recently seen instances are kept.
+func (t *RecentLRPTracker) Recent() []rep.RecentLRP {
+	cutoff := t.clock.Now().Add(-t.retention)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	recent := make([]rep.RecentLRP, 0, len(t.lastSeen))
+	for lrp, seen := range t.lastSeen {
+		if seen.Before(cutoff) {
+			delete(t.lastSeen, lrp)
+			continue
+		}
+		recent = append(recent, lrp)
+	}
+
+	sort.Slice(recent, func(i, j int) bool {
+		seenI, seenJ := t.lastSeen[recent[i]], t.lastSeen[recent[j]]
+		if !seenI.Equal(seenJ) {
+			return seenI.After(seenJ)
+		}
+		if recent[i].ProcessGUID != recent[j].ProcessGUID {
+			return recent[i].ProcessGUID < recent[j].ProcessGUID
+		}
+		return recent[i].Index < recent[j].Index
+	})
+
+	if t.maxEntries > 0 && len(recent) > t.maxEntries {
+		for _, lrp := range recent[t.maxEntries:] {
+			delete(t.lastSeen, lrp)
+		}
+		recent = recent[:t.maxEntries]
+	}
+
+	return recent
+}
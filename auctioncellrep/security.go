@@ -0,0 +1,35 @@
+package auctioncellrep
+
+import (
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// rejectUnpermittedSecurity fails the work whose security requirements the
+// cell does not permit, so that work sent to the cell without an auction
+// matching its requirements cannot gain capabilities or profiles the cell
+// was not configured to allow.
+func (a *AuctionCellRep) rejectUnpermittedSecurity(logger lager.Logger, work rep.Work, failed *rep.Work) rep.Work {
+	valid := work
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range work.LRPs {
+		if !a.securityPermissions.Permits(lrp.Security) {
+			logger.Info("rejecting-lrp-with-unpermitted-security", lager.Data{"instance-guid": lrp.InstanceGUID, "security": lrp.Security})
+			failed.LRPs = append(failed.LRPs, lrp)
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+	for _, task := range work.Tasks {
+		if !a.securityPermissions.Permits(task.Security) {
+			logger.Info("rejecting-task-with-unpermitted-security", lager.Data{"task-guid": task.TaskGuid, "security": task.Security})
+			failed.Tasks = append(failed.Tasks, task)
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
@@ -0,0 +1,135 @@
+package auctioncellrep
+
+import (
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	bytesPerMB = 1024 * 1024
+
+	// The smoothing factors of the level and the trend of the usage. The
+	// level follows new samples quickly, while the trend is smoothed more so
+	// that a single spike does not read as growth.
+	forecastLevelSmoothing = 0.5
+	forecastTrendSmoothing = 0.3
+)
+
+// UsageForecaster samples the memory and disk the containers on the cell use
+// every interval and forecasts their usage horizon ahead with Holt's linear
+// smoothing, so that the cell can advertise that it is about to fill up even
+// while it has room left now.
+type UsageForecaster struct {
+	metricsProvider rep.ContainerMetricsProvider
+	clock           clock.Clock
+	interval        time.Duration
+	horizon         time.Duration
+
+	lock   sync.Mutex
+	memory holtSmoothing
+	disk   holtSmoothing
+}
+
+func NewUsageForecaster(metricsProvider rep.ContainerMetricsProvider, clock clock.Clock, interval, horizon time.Duration) *UsageForecaster {
+	return &UsageForecaster{
+		metricsProvider: metricsProvider,
+		clock:           clock,
+		interval:        interval,
+		horizon:         horizon,
+	}
+}
+
+func (f *UsageForecaster) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := f.clock.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	close(ready)
+
+	for {
+		select {
+		case <-signals:
+			return nil
+		case <-ticker.C():
+			f.Sample()
+		}
+	}
+}
+
+// Sample adds the usage the containers on the cell report now to the series
+// the forecast follows.
+func (f *UsageForecaster) Sample() {
+	var memoryBytes, diskBytes uint64
+	for _, metrics := range f.metricsProvider.Metrics() {
+		if metrics == nil {
+			continue
+		}
+		memoryBytes += metrics.MemoryUsageBytes
+		diskBytes += metrics.DiskUsageBytes
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.memory.observe(float64(memoryBytes) / bytesPerMB)
+	f.disk.observe(float64(diskBytes) / bytesPerMB)
+}
+
+// Forecast returns the usage forecast horizon ahead. It returns false until
+// enough samples have been taken to tell the trend.
+func (f *UsageForecaster) Forecast() (rep.UsageForecast, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.memory.samples < 2 {
+		return rep.UsageForecast{}, false
+	}
+
+	steps := float64(f.horizon) / float64(f.interval)
+	return rep.UsageForecast{
+		HorizonSeconds: int64(f.horizon / time.Second),
+		MemoryMB:       forecastMB(f.memory.forecast(steps)),
+		DiskMB:         forecastMB(f.disk.forecast(steps)),
+	}, true
+}
+
+// holtSmoothing tracks the level and the trend per sample of a series.
+type holtSmoothing struct {
+	samples int
+	level   float64
+	trend   float64
+}
+
+func (h *holtSmoothing) observe(value float64) {
+	switch h.samples {
+	case 0:
+		h.level = value
+	case 1:
+		h.trend = value - h.level
+		h.level = value
+	default:
+		previous := h.level
+		h.level = forecastLevelSmoothing*value + (1-forecastLevelSmoothing)*(h.level+h.trend)
+		h.trend = forecastTrendSmoothing*(h.level-previous) + (1-forecastTrendSmoothing)*h.trend
+	}
+	h.samples++
+}
+
+func (h *holtSmoothing) forecast(steps float64) float64 {
+	return h.level + steps*h.trend
+}
+
+func forecastMB(value float64) int32 {
+	switch {
+	case value <= 0:
+		return 0
+	case value >= math.MaxInt32:
+		return math.MaxInt32
+	default:
+		return int32(math.Round(value))
+	}
+}
@@ -0,0 +1,98 @@
+package auctioncellrep
+
+import (
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// workGroups returns the groups of the LRP instances and tasks of work.
+func workGroups(work rep.Work) rep.StringSet {
+	groups := rep.NewStringSet()
+	for i := range work.LRPs {
+		if work.LRPs[i].Group != "" {
+			groups[work.LRPs[i].Group] = struct{}{}
+		}
+	}
+	for i := range work.Tasks {
+		if work.Tasks[i].Group != "" {
+			groups[work.Tasks[i].Group] = struct{}{}
+		}
+	}
+	return groups
+}
+
+// rejectIncompleteGroups moves the LRPs and tasks of pending that belong to
+// one of groups into failed, so that no member of a group is allocated once
+// another member has been turned down.
+func rejectIncompleteGroups(logger lager.Logger, pending rep.Work, groups rep.StringSet, failed *rep.Work) rep.Work {
+	valid := pending
+	valid.LRPs = nil
+	valid.Tasks = nil
+
+	for _, lrp := range pending.LRPs {
+		if groups.Contains(lrp.Group) {
+			logger.Info("rejecting-lrp-of-incomplete-group", lager.Data{"instance-guid": lrp.InstanceGUID, "group": lrp.Group})
+			failed.LRPs = append(failed.LRPs, lrp)
+			failed.WorkGroupFailures = append(failed.WorkGroupFailures, rep.WorkGroupFailure{Group: lrp.Group, InstanceGUID: lrp.InstanceGUID, Error: rep.ErrWorkGroupIncomplete.Error()})
+			continue
+		}
+		valid.LRPs = append(valid.LRPs, lrp)
+	}
+
+	for _, task := range pending.Tasks {
+		if groups.Contains(task.Group) {
+			logger.Info("rejecting-task-of-incomplete-group", lager.Data{"task-guid": task.TaskGuid, "group": task.Group})
+			failed.Tasks = append(failed.Tasks, task)
+			failed.WorkGroupFailures = append(failed.WorkGroupFailures, rep.WorkGroupFailure{Group: task.Group, TaskGuid: task.TaskGuid, Error: rep.ErrWorkGroupIncomplete.Error()})
+			continue
+		}
+		valid.Tasks = append(valid.Tasks, task)
+	}
+
+	return valid
+}
+
+// releaseIncompleteGroups deletes the containers allocated on backend for
+// the LRPs and tasks of requested that belong to one of groups, and moves
+// them into failed. The allocations that failed are left out, as they have
+// no container. The containers of LRP instances are found in containerGuids
+// by the Identifier of their instance, and the claims of all of them are
+// held until the groups are released, so that they are still reserved. A member whose container fails to be deleted is left placed on
+// the cell rather than reported failed, as it could still be claimed.
+func releaseIncompleteGroups(logger lager.Logger, backend Backend, requested, allocationFailed rep.Work, containerGuids map[string]string, groups rep.StringSet, failed *rep.Work) {
+	unallocated := rep.NewStringSet()
+	for i := range allocationFailed.Tasks {
+		unallocated[allocationFailed.Tasks[i].TaskGuid] = struct{}{}
+	}
+
+	released := rep.Work{}
+	for _, lrp := range requested.LRPs {
+		if !groups.Contains(lrp.Group) {
+			continue
+		}
+		containerGuid, ok := containerGuids[lrp.Identifier()]
+		if !ok {
+			continue
+		}
+		err := backend.Client.DeleteContainer(logger, containerGuid)
+		if err != nil {
+			logger.Error("failed-to-release-lrp-container", err, lager.Data{"container-guid": containerGuid, "group": lrp.Group})
+			continue
+		}
+		released.LRPs = append(released.LRPs, lrp)
+	}
+
+	for _, task := range requested.Tasks {
+		if !groups.Contains(task.Group) || unallocated.Contains(task.TaskGuid) {
+			continue
+		}
+		err := backend.Client.DeleteContainer(logger, task.TaskGuid)
+		if err != nil {
+			logger.Error("failed-to-release-task-container", err, lager.Data{"task-guid": task.TaskGuid, "group": task.Group})
+			continue
+		}
+		released.Tasks = append(released.Tasks, task)
+	}
+
+	rejectIncompleteGroups(logger, released, groups, failed)
+}
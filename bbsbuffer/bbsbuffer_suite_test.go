@@ -0,0 +1,13 @@
+package bbsbuffer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestBBSBuffer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BBS Buffer Suite")
+}
@@ -0,0 +1,211 @@
+package bbsbuffer
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/bbs"
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+// Client is a bbs.InternalClient that queues the container lifecycle
+// notifications the BBS cannot be reached for, and replays them in order
+// once it can, rather than leaving them to convergence. Only the latest
+// notification of an instance or task is kept: a notification replaces the
+// one queued for the same instance or task, and is sent directly while the
+// BBS can be reached. The notifications the bulk pass sends again once the
+// BBS is back are so sent rather than queued behind the others, and the BBS
+// still never learns about the changes of an instance out of order. The
+// calls that are queued succeed; those the full queue turns away fail as
+// they would have without it.
+type Client struct {
+	bbs.InternalClient
+
+	logger        lager.Logger
+	queue         *Queue
+	clock         clock.Clock
+	retryInterval time.Duration
+
+	// lock keeps a notification from being sent while a replay sends the
+	// one it replaces.
+	lock sync.Mutex
+}
+
+func NewClient(logger lager.Logger, bbsClient bbs.InternalClient, queue *Queue, clock clock.Clock, retryInterval time.Duration) *Client {
+	return &Client{
+		InternalClient: bbsClient,
+		logger:         logger.Session("bbs-notification-buffer"),
+		queue:          queue,
+		clock:          clock,
+		retryInterval:  retryInterval,
+	}
+}
+
+func (c *Client) StartActualLRP(logger lager.Logger, key *models.ActualLRPKey, instanceKey *models.ActualLRPInstanceKey, netInfo *models.ActualLRPNetInfo, internalRoutes []*models.ActualLRPInternalRoute, metricTags map[string]string) error {
+	return c.notify(logger, Notification{
+		Kind:                 KindStarted,
+		ActualLRPKey:         key,
+		ActualLRPInstanceKey: instanceKey,
+		NetInfo:              netInfo,
+		InternalRoutes:       internalRoutes,
+		MetricTags:           metricTags,
+	})
+}
+
+func (c *Client) CrashActualLRP(logger lager.Logger, key *models.ActualLRPKey, instanceKey *models.ActualLRPInstanceKey, reason string) error {
+	return c.notify(logger, Notification{
+		Kind:                 KindCrashed,
+		ActualLRPKey:         key,
+		ActualLRPInstanceKey: instanceKey,
+		CrashReason:          reason,
+	})
+}
+
+func (c *Client) CompleteTask(logger lager.Logger, taskGuid, cellID string, failed bool, failureReason, result string) error {
+	return c.notify(logger, Notification{
+		Kind:          KindCompleted,
+		TaskGuid:      taskGuid,
+		CellID:        cellID,
+		Failed:        failed,
+		FailureReason: failureReason,
+		Result:        result,
+	})
+}
+
+// Run replays the queued notifications every retry interval until signalled.
+func (c *Client) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	c.Replay(c.logger)
+	close(ready)
+
+	ticker := c.clock.NewTicker(c.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.Replay(c.logger)
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// Replay sends the queued notifications to the BBS in order until the queue
+// drains or the BBS cannot be reached. Notifications the BBS rejects are
+// dropped, as they would have been had they not been queued.
+func (c *Client) Replay(logger lager.Logger) {
+	if c.queue.Len() == 0 {
+		return
+	}
+
+	logger = logger.Session("replay", lager.Data{"queued": c.queue.Len()})
+	logger.Info("starting")
+
+	replayed := 0
+	for {
+		done, sent := c.replayOldest(logger)
+		if sent {
+			replayed++
+		}
+		if done {
+			break
+		}
+	}
+
+	logger.Info("finished", lager.Data{"replayed": replayed})
+}
+
+// replayOldest sends the oldest queued notification and removes it from the
+// queue. It reports whether the replay is done, and whether it sent one.
+func (c *Client) replayOldest(logger lager.Logger) (bool, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	notification, ok, err := c.queue.Peek()
+	if err != nil {
+		logger.Error("dropping-unreadable-notification", err)
+		return !c.pop(logger), false
+	}
+	if !ok {
+		return true, false
+	}
+
+	err = c.send(logger, notification)
+	if unavailable(err) {
+		logger.Info("bbs-still-unavailable", lager.Data{"error": err.Error()})
+		return true, false
+	}
+	if err != nil {
+		logger.Error("bbs-rejected-notification", err, lager.Data{"kind": notification.Kind})
+	}
+
+	return !c.pop(logger), true
+}
+
+func (c *Client) notify(logger lager.Logger, notification Notification) error {
+	if c.queue.Len() > 0 {
+		c.lock.Lock()
+		superseded, err := c.queue.Remove(notification)
+		c.lock.Unlock()
+		if err != nil {
+			logger.Error("failed-to-remove-notification", err)
+		} else if superseded {
+			logger.Info("superseded-queued-bbs-notification", lager.Data{"kind": notification.Kind})
+		}
+	}
+
+	err := c.send(logger, notification)
+	if !unavailable(err) {
+		return err
+	}
+	return c.push(logger, notification, err)
+}
+
+func (c *Client) push(logger lager.Logger, notification Notification, sendErr error) error {
+	c.lock.Lock()
+	err := c.queue.Push(notification)
+	c.lock.Unlock()
+	if err != nil {
+		logger.Error("failed-to-queue-bbs-notification", err, lager.Data{"kind": notification.Kind})
+		if sendErr != nil {
+			return sendErr
+		}
+		return err
+	}
+
+	logger.Info("queued-bbs-notification", lager.Data{"kind": notification.Kind, "queued": c.queue.Len()})
+	return nil
+}
+
+func (c *Client) pop(logger lager.Logger) bool {
+	err := c.queue.Pop()
+	if err != nil {
+		logger.Error("failed-to-remove-notification", err)
+		return false
+	}
+	return true
+}
+
+func (c *Client) send(logger lager.Logger, n Notification) error {
+	switch n.Kind {
+	case KindStarted:
+		return c.InternalClient.StartActualLRP(logger, n.ActualLRPKey, n.ActualLRPInstanceKey, n.NetInfo, n.InternalRoutes, n.MetricTags)
+	case KindCrashed:
+		return c.InternalClient.CrashActualLRP(logger, n.ActualLRPKey, n.ActualLRPInstanceKey, n.CrashReason)
+	default:
+		return c.InternalClient.CompleteTask(logger, n.TaskGuid, n.CellID, n.Failed, n.FailureReason, n.Result)
+	}
+}
+
+// unavailable reports whether err means the BBS could not be reached rather
+// than that it turned the request down, which it does with a models.Error.
+func unavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*models.Error)
+	return !ok
+}
@@ -0,0 +1,166 @@
+package bbsbuffer_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/bbs/fake_bbs"
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/bbsbuffer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit/ginkgomon"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		logger        *lagertest.TestLogger
+		dir           string
+		queue         *bbsbuffer.Queue
+		fakeBBSClient *fake_bbs.FakeInternalClient
+		fakeClock     *fakeclock.FakeClock
+		client        *bbsbuffer.Client
+
+		key         models.ActualLRPKey
+		instanceKey models.ActualLRPInstanceKey
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		var err error
+		dir, err = ioutil.TempDir("", "bbsbuffer")
+		Expect(err).NotTo(HaveOccurred())
+		queue, err = bbsbuffer.NewQueue(dir, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeBBSClient = new(fake_bbs.FakeInternalClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		client = bbsbuffer.NewClient(logger, fakeBBSClient, queue, fakeClock, time.Second)
+
+		key = models.NewActualLRPKey("process-guid", 0, "domain")
+		instanceKey = models.NewActualLRPInstanceKey("instance-guid", "cell-id")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("notifies the BBS directly while it is reachable", func() {
+		Expect(client.CrashActualLRP(logger, &key, &instanceKey, "boom")).To(Succeed())
+		Expect(fakeBBSClient.CrashActualLRPCallCount()).To(Equal(1))
+		Expect(queue.Len()).To(BeZero())
+	})
+
+	It("returns the errors the BBS answers with", func() {
+		bbsErr := models.NewError(models.Error_ActualLRPCannotBeStarted, "nope")
+		fakeBBSClient.StartActualLRPReturns(bbsErr)
+
+		err := client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+		Expect(err).To(Equal(bbsErr))
+		Expect(queue.Len()).To(BeZero())
+	})
+
+	Context("when the BBS cannot be reached", func() {
+		BeforeEach(func() {
+			fakeBBSClient.StartActualLRPReturns(errors.New("connection refused"))
+			fakeBBSClient.CrashActualLRPReturns(errors.New("connection refused"))
+			fakeBBSClient.CompleteTaskReturns(errors.New("connection refused"))
+		})
+
+		It("queues the notification", func() {
+			Expect(client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{Address: "1.2.3.4"}, nil, nil)).To(Succeed())
+			Expect(queue.Len()).To(Equal(1))
+		})
+
+		It("keeps only the latest notification of an instance", func() {
+			Expect(client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{Address: "1.2.3.4"}, nil, nil)).To(Succeed())
+			Expect(client.CrashActualLRP(logger, &key, &instanceKey, "boom")).To(Succeed())
+
+			Expect(queue.Len()).To(Equal(1))
+			notification, _, err := queue.Peek()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notification.Kind).To(Equal(bbsbuffer.KindCrashed))
+		})
+
+		It("sends the notifications it can directly rather than queueing them behind the others", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+
+			fakeBBSClient.CompleteTaskReturns(nil)
+			Expect(client.CompleteTask(logger, "tg-1", "cell-id", false, "", "")).To(Succeed())
+			Expect(fakeBBSClient.CompleteTaskCallCount()).To(Equal(1))
+			Expect(queue.Len()).To(Equal(1))
+		})
+
+		It("drops the queued notification of an instance once a later one is sent", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+
+			fakeBBSClient.CrashActualLRPReturns(nil)
+			Expect(client.CrashActualLRP(logger, &key, &instanceKey, "boom")).To(Succeed())
+			Expect(queue.Len()).To(BeZero())
+			Expect(logger).To(gbytes.Say("superseded-queued-bbs-notification"))
+		})
+
+		It("fails the notifications the full queue turns away", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+			client.CompleteTask(logger, "tg-1", "cell-id", false, "", "")
+
+			err := client.CompleteTask(logger, "tg-2", "cell-id", false, "", "")
+			Expect(err).To(MatchError(bbsbuffer.ErrQueueFull))
+		})
+
+		It("replays the queue in order once the BBS is back", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{Address: "1.2.3.4"}, nil, nil)
+			client.CompleteTask(logger, "tg-1", "cell-id", true, "failed", "")
+
+			fakeBBSClient.StartActualLRPReturns(nil)
+			fakeBBSClient.CompleteTaskReturns(nil)
+			client.Replay(logger)
+
+			Expect(queue.Len()).To(BeZero())
+			Expect(fakeBBSClient.StartActualLRPCallCount()).To(Equal(2))
+			_, actualKey, actualInstanceKey, netInfo, _, _ := fakeBBSClient.StartActualLRPArgsForCall(1)
+			Expect(*actualKey).To(Equal(key))
+			Expect(*actualInstanceKey).To(Equal(instanceKey))
+			Expect(netInfo.Address).To(Equal("1.2.3.4"))
+
+			Expect(fakeBBSClient.CompleteTaskCallCount()).To(Equal(2))
+			_, taskGuid, cellID, failed, reason, _ := fakeBBSClient.CompleteTaskArgsForCall(1)
+			Expect(taskGuid).To(Equal("tg-1"))
+			Expect(cellID).To(Equal("cell-id"))
+			Expect(failed).To(BeTrue())
+			Expect(reason).To(Equal("failed"))
+		})
+
+		It("keeps the queue while the BBS is still unavailable", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+			client.Replay(logger)
+			Expect(queue.Len()).To(Equal(1))
+		})
+
+		It("drops the notifications the BBS rejects on replay", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+
+			fakeBBSClient.StartActualLRPReturns(models.ErrResourceNotFound)
+			client.Replay(logger)
+			Expect(queue.Len()).To(BeZero())
+		})
+
+		It("replays every retry interval", func() {
+			client.StartActualLRP(logger, &key, &instanceKey, &models.ActualLRPNetInfo{}, nil, nil)
+
+			process := ginkgomon.Invoke(client)
+			defer ginkgomon.Interrupt(process)
+			Expect(fakeBBSClient.StartActualLRPCallCount()).To(Equal(2))
+
+			fakeBBSClient.StartActualLRPReturns(nil)
+			Eventually(fakeClock.WatcherCount).Should(Equal(1))
+			fakeClock.Increment(time.Second)
+			Eventually(queue.Len).Should(BeZero())
+		})
+	})
+})
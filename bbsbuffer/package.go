@@ -0,0 +1 @@
+package bbsbuffer // import "code.cloudfoundry.org/rep/bbsbuffer"
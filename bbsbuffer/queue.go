@@ -0,0 +1,239 @@
+package bbsbuffer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/bbs/models"
+)
+
+var ErrQueueFull = errors.New("bbs notification queue is full")
+
+// The kinds of Notification.
+const (
+	KindStarted   = "started"
+	KindCrashed   = "crashed"
+	KindCompleted = "completed"
+)
+
+// Notification is a container lifecycle change the rep reports to the BBS:
+// an actual LRP that started or crashed, or a task that completed.
+type Notification struct {
+	Kind string `json:"kind"`
+
+	ActualLRPKey         *models.ActualLRPKey             `json:"actual_lrp_key,omitempty"`
+	ActualLRPInstanceKey *models.ActualLRPInstanceKey     `json:"actual_lrp_instance_key,omitempty"`
+	NetInfo              *models.ActualLRPNetInfo         `json:"net_info,omitempty"`
+	InternalRoutes       []*models.ActualLRPInternalRoute `json:"internal_routes,omitempty"`
+	MetricTags           map[string]string                `json:"metric_tags,omitempty"`
+	CrashReason          string                           `json:"crash_reason,omitempty"`
+
+	TaskGuid      string `json:"task_guid,omitempty"`
+	CellID        string `json:"cell_id,omitempty"`
+	Failed        bool   `json:"failed,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	Result        string `json:"result,omitempty"`
+}
+
+// key identifies the instance or task a notification is about. Of the
+// notifications with the same key only the latest one is worth sending.
+func (n *Notification) key() string {
+	if n.ActualLRPKey != nil {
+		instanceGuid := ""
+		if n.ActualLRPInstanceKey != nil {
+			instanceGuid = n.ActualLRPInstanceKey.InstanceGuid
+		}
+		return fmt.Sprintf("lrp/%s/%d/%s", n.ActualLRPKey.ProcessGuid, n.ActualLRPKey.Index, instanceGuid)
+	}
+	return "task/" + n.TaskGuid
+}
+
+const notificationSuffix = ".json"
+
+// Queue is a bounded queue of notifications kept in dir, one file per
+// notification named after its position, so that the notifications queued
+// before the rep restarts are still replayed in order. The queue holds one
+// notification per instance or task, the latest one pushed for it.
+type Queue struct {
+	dir     string
+	maxSize int
+
+	lock    sync.Mutex
+	entries []queued
+	next    uint64
+}
+
+type queued struct {
+	seq uint64
+	// key is empty for notifications left unreadable by a previous process.
+	key string
+}
+
+// NewQueue opens the queue kept in dir, creating dir when it does not exist,
+// with the notifications a previous process left in it. The queue holds at
+// most maxSize notifications.
+func NewQueue(dir string, maxSize int) (*Queue, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Queue{dir: dir, maxSize: maxSize}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, notificationSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, notificationSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		e := queued{seq: seq}
+		if notification, err := q.read(seq); err == nil {
+			e.key = notification.key()
+		}
+		q.entries = append(q.entries, e)
+	}
+	sort.Slice(q.entries, func(i, j int) bool { return q.entries[i].seq < q.entries[j].seq })
+	if len(q.entries) > 0 {
+		q.next = q.entries[len(q.entries)-1].seq + 1
+	}
+
+	return q, nil
+}
+
+// Len returns how many notifications are queued.
+func (q *Queue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.entries)
+}
+
+// Push queues notification after the others, replacing the notification
+// queued for the same instance or task, if there is one. It returns
+// ErrQueueFull when the queue already holds its maximum.
+func (q *Queue) Push(notification Notification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	key := notification.key()
+	replaced := q.index(key)
+	if replaced < 0 && len(q.entries) >= q.maxSize {
+		return ErrQueueFull
+	}
+
+	path := q.path(q.next)
+	err = ioutil.WriteFile(path+".tmp", payload, 0600)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return err
+	}
+
+	if replaced >= 0 {
+		err = q.remove(replaced)
+		if err != nil {
+			return err
+		}
+	}
+
+	q.entries = append(q.entries, queued{seq: q.next, key: key})
+	q.next++
+	return nil
+}
+
+// Remove removes the notification queued for the same instance or task as
+// notification, reporting whether there was one.
+func (q *Queue) Remove(notification Notification) (bool, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	i := q.index(notification.key())
+	if i < 0 {
+		return false, nil
+	}
+	return true, q.remove(i)
+}
+
+// Peek returns the oldest queued notification, if there is one.
+func (q *Queue) Peek() (Notification, bool, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.entries) == 0 {
+		return Notification{}, false, nil
+	}
+
+	notification, err := q.read(q.entries[0].seq)
+	if err != nil {
+		return Notification{}, false, err
+	}
+	return notification, true, nil
+}
+
+// Pop removes the oldest queued notification.
+func (q *Queue) Pop() error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.entries) == 0 {
+		return nil
+	}
+	return q.remove(0)
+}
+
+func (q *Queue) index(key string) int {
+	for i := range q.entries {
+		if q.entries[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *Queue) remove(i int) error {
+	err := os.Remove(q.path(q.entries[i].seq))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	return nil
+}
+
+func (q *Queue) read(seq uint64) (Notification, error) {
+	payload, err := ioutil.ReadFile(q.path(seq))
+	if err != nil {
+		return Notification{}, err
+	}
+
+	var notification Notification
+	err = json.Unmarshal(payload, &notification)
+	if err != nil {
+		return Notification{}, fmt.Errorf("unreadable notification %d: %s", seq, err)
+	}
+	return notification, nil
+}
+
+func (q *Queue) path(seq uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", seq, notificationSuffix))
+}
@@ -0,0 +1,107 @@
+package bbsbuffer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/rep/bbsbuffer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue", func() {
+	var (
+		dir   string
+		queue *bbsbuffer.Queue
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "bbsbuffer")
+		Expect(err).NotTo(HaveOccurred())
+
+		queue, err = bbsbuffer.NewQueue(dir, 2)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns the notifications in the order they were pushed", func() {
+		Expect(queue.Push(bbsbuffer.Notification{Kind: bbsbuffer.KindCompleted, TaskGuid: "tg-1"})).To(Succeed())
+		Expect(queue.Push(bbsbuffer.Notification{Kind: bbsbuffer.KindCompleted, TaskGuid: "tg-2"})).To(Succeed())
+		Expect(queue.Len()).To(Equal(2))
+
+		notification, ok, err := queue.Peek()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(notification.TaskGuid).To(Equal("tg-1"))
+
+		Expect(queue.Pop()).To(Succeed())
+		notification, _, _ = queue.Peek()
+		Expect(notification.TaskGuid).To(Equal("tg-2"))
+
+		Expect(queue.Pop()).To(Succeed())
+		_, ok, err = queue.Peek()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("turns notifications away once full", func() {
+		Expect(queue.Push(bbsbuffer.Notification{TaskGuid: "tg-1"})).To(Succeed())
+		Expect(queue.Push(bbsbuffer.Notification{TaskGuid: "tg-2"})).To(Succeed())
+		Expect(queue.Push(bbsbuffer.Notification{TaskGuid: "tg-3"})).To(MatchError(bbsbuffer.ErrQueueFull))
+	})
+
+	It("keeps only the latest notification of an instance or task, after the others", func() {
+		Expect(queue.Push(bbsbuffer.Notification{Kind: bbsbuffer.KindCompleted, TaskGuid: "tg-1", Result: "first"})).To(Succeed())
+		Expect(queue.Push(bbsbuffer.Notification{Kind: bbsbuffer.KindCompleted, TaskGuid: "tg-2"})).To(Succeed())
+		Expect(queue.Push(bbsbuffer.Notification{Kind: bbsbuffer.KindCompleted, TaskGuid: "tg-1", Result: "latest"})).To(Succeed())
+		Expect(queue.Len()).To(Equal(2))
+
+		notification, _, _ := queue.Peek()
+		Expect(notification.TaskGuid).To(Equal("tg-2"))
+		Expect(queue.Pop()).To(Succeed())
+		notification, _, _ = queue.Peek()
+		Expect(notification.Result).To(Equal("latest"))
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+	})
+
+	It("removes the notification queued for an instance or task", func() {
+		Expect(queue.Push(bbsbuffer.Notification{TaskGuid: "tg-1"})).To(Succeed())
+
+		removed, err := queue.Remove(bbsbuffer.Notification{TaskGuid: "tg-2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeFalse())
+
+		removed, err = queue.Remove(bbsbuffer.Notification{TaskGuid: "tg-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeTrue())
+		Expect(queue.Len()).To(BeZero())
+	})
+
+	It("keeps the notifications across processes", func() {
+		Expect(queue.Push(bbsbuffer.Notification{TaskGuid: "tg-1"})).To(Succeed())
+		Expect(queue.Push(bbsbuffer.Notification{TaskGuid: "tg-2"})).To(Succeed())
+		Expect(queue.Pop()).To(Succeed())
+
+		reopened, err := bbsbuffer.NewQueue(dir, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reopened.Len()).To(Equal(1))
+
+		Expect(reopened.Push(bbsbuffer.Notification{TaskGuid: "tg-3"})).To(Succeed())
+		notification, _, _ := reopened.Peek()
+		Expect(notification.TaskGuid).To(Equal("tg-2"))
+		Expect(reopened.Push(bbsbuffer.Notification{TaskGuid: "tg-2"})).To(Succeed())
+		Expect(reopened.Len()).To(Equal(2))
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,109 @@
+package rep
+
+import (
+	"fmt"
+	"time"
+)
+
+// The reasons a cell turns down an LRP instance or task at auction, as they
+// are counted in a CapacityReport.
+const (
+	PlacementReasonDuplicate             = "duplicate"
+	PlacementReasonBlocked               = "blocked"
+	PlacementReasonInvalidRegistry       = "invalid-registry"
+	PlacementReasonImageDigestRequired   = "image-digest-required"
+	PlacementReasonImageTooLarge         = "image-too-large"
+	PlacementReasonMissingLifecycle      = "missing-lifecycle"
+	PlacementReasonInvalidInitSteps      = "invalid-init-steps"
+	PlacementReasonInvalidProportions    = "invalid-proportions"
+	PlacementReasonUnsupportedIOLimits   = "unsupported-io-limits"
+	PlacementReasonSecurityNotPermitted  = "security-not-permitted"
+	PlacementReasonHostPortConflict      = "host-port-conflict"
+	PlacementReasonDirected              = "directed-placement"
+	PlacementReasonPolicyDenied          = "policy-denied"
+	PlacementReasonQuarantined           = "quarantined"
+	PlacementReasonInsufficientResources = "insufficient-resources"
+	PlacementReasonWorkGroupIncomplete   = "work-group-incomplete"
+	PlacementReasonEvacuating            = "evacuating"
+	PlacementReasonInMaintenance         = "in-maintenance"
+	PlacementReasonCordoned              = "cordoned"
+	PlacementReasonAllocationFailed      = "allocation-failed"
+	PlacementReasonRootFSMismatch        = "rootfs-mismatch"
+	PlacementReasonPlacementTagMismatch  = "placement-tag-mismatch"
+	PlacementReasonVolumeDriverMismatch  = "volume-driver-mismatch"
+)
+
+// CapacityReport summarizes the placements a cell accepted and rejected over
+// the last Hours hours, since Since, and the peak fraction of each of its
+// resources in use over that time.
+type CapacityReport struct {
+	Since           time.Time          `json:"since"`
+	Hours           int                `json:"hours"`
+	Placements      []PlacementSummary `json:"placements"`
+	PeakUtilization Utilization        `json:"peak_utilization"`
+}
+
+// PlacementSummary counts the LRP instances or tasks of one resource shape
+// the cell accepted, and those it rejected by reason.
+type PlacementSummary struct {
+	Kind     string         `json:"kind"`
+	Shape    string         `json:"shape"`
+	Accepted int            `json:"accepted"`
+	Rejected map[string]int `json:"rejected,omitempty"`
+}
+
+// Utilization is the fraction of the memory, disk and containers of a cell
+// in use, from 0 to 1.
+type Utilization struct {
+	Memory     float64 `json:"memory"`
+	Disk       float64 `json:"disk"`
+	Containers float64 `json:"containers"`
+}
+
+// NewUtilization returns the fraction of total that is not available.
+// Dimensions the cell has none of are not utilized.
+func NewUtilization(total, available Resources) Utilization {
+	used := func(total, available float64) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return (total - available) / total
+	}
+	return Utilization{
+		Memory:     used(float64(total.MemoryMB), float64(available.MemoryMB)),
+		Disk:       used(float64(total.DiskMB), float64(available.DiskMB)),
+		Containers: used(float64(total.Containers), float64(available.Containers)),
+	}
+}
+
+// Max returns the larger of u and other in every dimension.
+func (u Utilization) Max(other Utilization) Utilization {
+	if other.Memory > u.Memory {
+		u.Memory = other.Memory
+	}
+	if other.Disk > u.Disk {
+		u.Disk = other.Disk
+	}
+	if other.Containers > u.Containers {
+		u.Containers = other.Containers
+	}
+	return u
+}
+
+// PlacementShape buckets the memory and disk of an LRP instance or task by
+// rounding each up to a power of two, as memory/disk such as 512MB/1024MB,
+// so that placements of similar size are counted together.
+func PlacementShape(memoryMB, diskMB int32) string {
+	return fmt.Sprintf("%dMB/%dMB", nextPowerOfTwo(memoryMB), nextPowerOfTwo(diskMB))
+}
+
+func nextPowerOfTwo(n int32) int32 {
+	if n <= 0 {
+		return 0
+	}
+	power := int32(1)
+	for power < n {
+		power <<= 1
+	}
+	return power
+}
@@ -0,0 +1,29 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CapacityReport", func() {
+	Describe("PlacementShape", func() {
+		It("rounds memory and disk up to powers of two", func() {
+			Expect(rep.PlacementShape(512, 1024)).To(Equal("512MB/1024MB"))
+			Expect(rep.PlacementShape(700, 1)).To(Equal("1024MB/1MB"))
+			Expect(rep.PlacementShape(0, 3000)).To(Equal("0MB/4096MB"))
+		})
+	})
+
+	Describe("NewUtilization", func() {
+		It("returns the fraction of every resource in use", func() {
+			utilization := rep.NewUtilization(rep.NewResources(1000, 4000, 10), rep.NewResources(250, 4000, 5))
+			Expect(utilization).To(Equal(rep.Utilization{Memory: 0.75, Disk: 0, Containers: 0.5}))
+		})
+
+		It("does not utilize resources the cell has none of", func() {
+			Expect(rep.NewUtilization(rep.Resources{}, rep.Resources{})).To(Equal(rep.Utilization{}))
+		})
+	})
+})
@@ -0,0 +1,67 @@
+package rep
+
+import "errors"
+
+var ErrInvalidCapacityReservation = errors.New("a capacity reservation needs a process guid, a valid resource, at least one instance and either a positive ttl or a deadline")
+
+// CapacityReservationRequest asks a cell to hold the capacity for Instances
+// instances of Resource for TTLSeconds, or until Deadline when it is given,
+// so that a rolling deployment of ProcessGuid can place its new instances on
+// the cell even when other work is auctioned in the meantime. The capacity is
+// held on the backend of the cell the instances, on RootFs, are placed on.
+type CapacityReservationRequest struct {
+	ProcessGuid string    `json:"process_guid"`
+	Resource    Resource  `json:"resource"`
+	RootFs      string    `json:"rootfs,omitempty"`
+	Instances   int32     `json:"instances"`
+	TTLSeconds  int64     `json:"ttl_seconds"`
+	Deadline    *Deadline `json:"deadline,omitempty"`
+}
+
+func NewCapacityReservationRequest(processGuid string, resource Resource, instances int32, ttlSeconds int64) CapacityReservationRequest {
+	return CapacityReservationRequest{
+		ProcessGuid: processGuid,
+		Resource:    resource,
+		Instances:   instances,
+		TTLSeconds:  ttlSeconds,
+	}
+}
+
+func (r CapacityReservationRequest) Validate() error {
+	if r.ProcessGuid == "" || !r.Resource.Valid() || r.Instances < 1 || (r.TTLSeconds < 1 && r.Deadline == nil) {
+		return ErrInvalidCapacityReservation
+	}
+	return nil
+}
+
+// CapacityReservation is capacity a cell holds for the instances of
+// ProcessGuid on its Backend until ExpiresAt, in unix nanoseconds. Every
+// instance of ProcessGuid placed on that backend that fits in Resource takes
+// the place of one of the Instances the reservation still holds.
+type CapacityReservation struct {
+	ID          string   `json:"id"`
+	ProcessGuid string   `json:"process_guid"`
+	Backend     string   `json:"backend,omitempty"`
+	Resource    Resource `json:"resource"`
+	Instances   int32    `json:"instances"`
+	ExpiresAt   int64    `json:"expires_at"`
+}
+
+// Holds reports whether an instance of processGuid requesting res can take
+// the place of one of the instances the reservation holds.
+func (r *CapacityReservation) Holds(processGuid string, res *Resource) bool {
+	return r.Instances > 0 &&
+		r.ProcessGuid == processGuid &&
+		res.MemoryMB <= r.Resource.MemoryMB &&
+		res.DiskMB <= r.Resource.DiskMB
+}
+
+// Reserved returns the resources the reservation holds for the instances it
+// has left.
+func (r *CapacityReservation) Reserved() Resources {
+	return Resources{
+		MemoryMB:   r.Resource.MemoryMB * r.Instances,
+		DiskMB:     r.Resource.DiskMB * r.Instances,
+		Containers: int(r.Instances),
+	}
+}
@@ -0,0 +1,34 @@
+package rep
+
+// CapacitySummary is the aggregate capacity of a cell, without the LRP
+// instances and tasks of its CellState. It is small enough for auctioneers to
+// poll often, leaving the full state to their reconciliation passes.
+type CapacitySummary struct {
+	CellID                string `json:"cell_id"`
+	Zone                  string `json:"zone"`
+	RootFSProviders       RootFSProviders
+	AvailableResources    Resources
+	TotalResources        Resources
+	Evacuating            bool
+	Maintenance           bool `json:",omitempty"`
+	Cordoned              bool `json:",omitempty"`
+	PlacementTags         []string
+	OptionalPlacementTags []string
+}
+
+// NewCapacitySummary summarizes state, leaving out its LRP instances and
+// tasks.
+func NewCapacitySummary(state CellState) CapacitySummary {
+	return CapacitySummary{
+		CellID:                state.CellID,
+		Zone:                  state.Zone,
+		RootFSProviders:       state.RootFSProviders,
+		AvailableResources:    state.AvailableResources,
+		TotalResources:        state.TotalResources,
+		Evacuating:            state.Evacuating,
+		Maintenance:           state.Maintenance,
+		Cordoned:              state.Cordoned,
+		PlacementTags:         state.PlacementTags,
+		OptionalPlacementTags: state.OptionalPlacementTags,
+	}
+}
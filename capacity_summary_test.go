@@ -0,0 +1,37 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CapacitySummary", func() {
+	It("leaves out the LRP instances and tasks of the state", func() {
+		state := rep.CellState{
+			CellID:                 "cell-id",
+			Zone:                   "z1",
+			RootFSProviders:        rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+			AvailableResources:     rep.NewResources(512, 1024, 3),
+			TotalResources:         rep.NewResources(1024, 2048, 10),
+			LRPs:                   []rep.LRP{{InstanceGUID: "ig-1"}, {InstanceGUID: "ig-2"}},
+			Tasks:                  []rep.Task{{TaskGuid: "tg-1"}},
+			StartingContainerCount: 1,
+			Evacuating:             true,
+			PlacementTags:          []string{"gpu"},
+			OptionalPlacementTags:  []string{"ssd"},
+		}
+
+		Expect(rep.NewCapacitySummary(state)).To(Equal(rep.CapacitySummary{
+			CellID:                "cell-id",
+			Zone:                  "z1",
+			RootFSProviders:       rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+			AvailableResources:    rep.NewResources(512, 1024, 3),
+			TotalResources:        rep.NewResources(1024, 2048, 10),
+			Evacuating:            true,
+			PlacementTags:         []string{"gpu"},
+			OptionalPlacementTags: []string{"ssd"},
+		}))
+	})
+})
@@ -0,0 +1,78 @@
+package rep
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The version of the cell state snapshots this rep writes. Fields added to
+// the CellState bump the minor version and are ignored by reps that do not
+// know them, so that a snapshot written by a newer rep still decodes. An
+// incompatible change, such as a field changing type, bumps the major
+// version, which older reps refuse to decode.
+const (
+	CellStateSnapshotMajorVersion = 1
+	CellStateSnapshotMinorVersion = 7
+)
+
+var cellStateSnapshotMagic = []byte("CSNP")
+
+var ErrNotCellStateSnapshot = errors.New("not a cell state snapshot")
+
+// UnsupportedSnapshotVersionError is returned for a snapshot of a major
+// version this rep cannot decode.
+type UnsupportedSnapshotVersionError struct {
+	Major, Minor byte
+}
+
+func (e UnsupportedSnapshotVersionError) Error() string {
+	return fmt.Sprintf("cell state snapshot version %d.%d is not supported, the latest supported major version is %d", e.Major, e.Minor, CellStateSnapshotMajorVersion)
+}
+
+// EncodeCellStateSnapshot writes state to w as a versioned binary snapshot,
+// a header followed by the gob encoding of the state. It is smaller and
+// faster to encode and decode than JSON for cells running many containers,
+// which suits persisting the state and diagnostics, but it is not a wire
+// format: the auctioneer keeps exchanging the JSON CellState.
+func EncodeCellStateSnapshot(w io.Writer, state CellState) error {
+	header := append(append([]byte{}, cellStateSnapshotMagic...), CellStateSnapshotMajorVersion, CellStateSnapshotMinorVersion)
+	_, err := w.Write(header)
+	if err != nil {
+		return err
+	}
+	return gob.NewEncoder(w).Encode(state)
+}
+
+// DecodeCellStateSnapshot reads a snapshot EncodeCellStateSnapshot wrote from
+// r. It returns ErrNotCellStateSnapshot when r does not hold a snapshot, and
+// an UnsupportedSnapshotVersionError for a snapshot of a newer major version.
+func DecodeCellStateSnapshot(r io.Reader) (CellState, error) {
+	reader := bufio.NewReader(r)
+
+	header := make([]byte, len(cellStateSnapshotMagic)+2)
+	_, err := io.ReadFull(reader, header)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return CellState{}, ErrNotCellStateSnapshot
+	}
+	if err != nil {
+		return CellState{}, err
+	}
+	if string(header[:len(cellStateSnapshotMagic)]) != string(cellStateSnapshotMagic) {
+		return CellState{}, ErrNotCellStateSnapshot
+	}
+
+	major, minor := header[len(cellStateSnapshotMagic)], header[len(cellStateSnapshotMagic)+1]
+	if major != CellStateSnapshotMajorVersion {
+		return CellState{}, UnsupportedSnapshotVersionError{Major: major, Minor: minor}
+	}
+
+	var state CellState
+	err = gob.NewDecoder(reader).Decode(&state)
+	if err != nil {
+		return CellState{}, err
+	}
+	return state, nil
+}
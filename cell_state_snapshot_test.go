@@ -0,0 +1,166 @@
+package rep_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CellStateSnapshot", func() {
+	var state rep.CellState
+
+	BeforeEach(func() {
+		state = denseCellState(3)
+	})
+
+	encode := func(state rep.CellState) []byte {
+		buffer := &bytes.Buffer{}
+		Expect(rep.EncodeCellStateSnapshot(buffer, state)).To(Succeed())
+		return buffer.Bytes()
+	}
+
+	It("decodes the state it encoded", func() {
+		decoded, err := rep.DecodeCellStateSnapshot(bytes.NewReader(encode(state)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(state))
+	})
+
+	It("is smaller than the JSON state", func() {
+		state = denseCellState(200)
+		payload, err := json.Marshal(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(len(encode(state))).To(BeNumerically("<", len(payload)))
+	})
+
+	It("decodes a snapshot of a newer minor version, ignoring the fields it does not know", func() {
+		type futureCellState struct {
+			CellID          string
+			LRPs            []rep.LRP
+			Zone            string
+			GPUs            int
+			NetworkClasses  map[string]string
+			AvailableHostIP string
+		}
+
+		buffer := bytes.NewBuffer([]byte{'C', 'S', 'N', 'P', rep.CellStateSnapshotMajorVersion, rep.CellStateSnapshotMinorVersion + 3})
+		Expect(gob.NewEncoder(buffer).Encode(futureCellState{
+			CellID:          "cell-id",
+			LRPs:            state.LRPs,
+			Zone:            "z1",
+			GPUs:            2,
+			NetworkClasses:  map[string]string{"fast": "10g"},
+			AvailableHostIP: "10.0.0.1",
+		})).To(Succeed())
+
+		decoded, err := rep.DecodeCellStateSnapshot(buffer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(rep.CellState{CellID: "cell-id", LRPs: state.LRPs, Zone: "z1"}))
+	})
+
+	It("refuses a snapshot of a newer major version", func() {
+		payload := encode(state)
+		payload[4] = rep.CellStateSnapshotMajorVersion + 1
+
+		_, err := rep.DecodeCellStateSnapshot(bytes.NewReader(payload))
+		Expect(err).To(Equal(rep.UnsupportedSnapshotVersionError{Major: rep.CellStateSnapshotMajorVersion + 1, Minor: rep.CellStateSnapshotMinorVersion}))
+	})
+
+	It("refuses what is not a snapshot", func() {
+		payload, err := json.Marshal(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = rep.DecodeCellStateSnapshot(bytes.NewReader(payload))
+		Expect(err).To(Equal(rep.ErrNotCellStateSnapshot))
+
+		_, err = rep.DecodeCellStateSnapshot(bytes.NewReader(nil))
+		Expect(err).To(Equal(rep.ErrNotCellStateSnapshot))
+	})
+})
+
+// denseCellState returns the state of a cell running lrps LRP instances and
+// as many tasks. Its collections are never empty, as empty collections decode
+// from a snapshot as nil.
+func denseCellState(lrps int) rep.CellState {
+	state := rep.CellState{
+		RepURL:    "https://cell-id.cell.service.cf.internal:1801",
+		CellID:    "cell-id",
+		CellIndex: 3,
+		RootFSProviders: rep.RootFSProviders{
+			"preloaded": rep.NewFixedSetRootFSProvider("cflinuxfs4"),
+			"docker":    rep.ArbitraryRootFSProvider{},
+		},
+		AvailableResources:  rep.NewResources(4096, 8192, 50),
+		TotalResources:      rep.NewResources(16384, 32768, 250),
+		Zone:                "z1",
+		OSFamily:            rep.OSFamilyLinux,
+		ImageOverhead:       &rep.Resource{MemoryMB: 10, DiskMB: 20},
+		VolumeDrivers:       []string{"nfs"},
+		PlacementTags:       []string{"tag"},
+		StackContainersLeft: map[string]int{"cflinuxfs4": 10},
+		MaintenanceWindows: []rep.MaintenanceWindow{{
+			Start: time.Unix(1700000000, 0).UTC(),
+			End:   time.Unix(1700003600, 0).UTC(),
+		}},
+		Lifecycles: []rep.Lifecycle{{Name: "buildpack", Version: "1.2.3"}},
+	}
+
+	for i := 0; i < lrps; i++ {
+		lrp := rep.NewLRP(
+			fmt.Sprintf("instance-guid-%d", i),
+			models.NewActualLRPKey(fmt.Sprintf("process-guid-%d", i), int32(i), "domain"),
+			rep.NewResource(256, 512, 1024),
+			rep.NewPlacementConstraint("preloaded:cflinuxfs4", []string{"tag"}, []string{"nfs"}),
+		)
+		lrp.State = "RUNNING"
+		lrp.Labels = map[string]string{"organization_guid": "org-guid"}
+		state.LRPs = append(state.LRPs, lrp)
+
+		task := rep.NewTask(
+			fmt.Sprintf("task-guid-%d", i),
+			"domain",
+			rep.NewResource(128, 256, 0),
+			rep.NewPlacementConstraint("docker:///busybox", []string{"tag"}, []string{"nfs"}),
+		)
+		task.State = models.Task_Running
+		state.Tasks = append(state.Tasks, task)
+	}
+
+	return state
+}
+
+func BenchmarkCellStateSnapshot(b *testing.B) {
+	state := denseCellState(500)
+	for i := 0; i < b.N; i++ {
+		buffer := &bytes.Buffer{}
+		if err := rep.EncodeCellStateSnapshot(buffer, state); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := rep.DecodeCellStateSnapshot(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCellStateJSON(b *testing.B) {
+	state := denseCellState(500)
+	for i := 0; i < b.N; i++ {
+		payload, err := json.Marshal(state)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var decoded rep.CellState
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
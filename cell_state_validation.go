@@ -0,0 +1,74 @@
+package rep
+
+import "fmt"
+
+// CellStateValidationError is returned when a cell state lacks fields every
+// rep sends, which usually means the rep and its client disagree on the
+// encoding of the state. Field is the JSON path of the offending field.
+type CellStateValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e CellStateValidationError) Error() string {
+	return fmt.Sprintf("invalid cell state: %s %s", e.Field, e.Message)
+}
+
+// Validate returns a CellStateValidationError for the first field of the
+// state that no rep would send, so that a state decoded from an incompatible
+// rep is rejected rather than auctioned with zero values.
+func (c *CellState) Validate() error {
+	if c.CellID == "" {
+		return CellStateValidationError{Field: "cell_id", Message: "is missing"}
+	}
+
+	err := validateResources("TotalResources", c.TotalResources)
+	if err != nil {
+		return err
+	}
+	if c.StartingContainerCount < 0 {
+		return CellStateValidationError{Field: "StartingContainerCount", Message: "is negative"}
+	}
+
+	for i := range c.LRPs {
+		lrp := &c.LRPs[i]
+		if lrp.ProcessGuid == "" {
+			return CellStateValidationError{Field: fmt.Sprintf("LRPs[%d].process_guid", i), Message: "is missing"}
+		}
+		if lrp.InstanceGUID == "" {
+			return CellStateValidationError{Field: fmt.Sprintf("LRPs[%d].instance_guid", i), Message: "is missing"}
+		}
+	}
+
+	for i := range c.Tasks {
+		if c.Tasks[i].TaskGuid == "" {
+			return CellStateValidationError{Field: fmt.Sprintf("Tasks[%d].TaskGuid", i), Message: "is missing"}
+		}
+	}
+
+	for i := range c.Backends {
+		backend := &c.Backends[i]
+		if backend.Name == "" {
+			return CellStateValidationError{Field: fmt.Sprintf("Backends[%d].Name", i), Message: "is missing"}
+		}
+		err := validateResources(fmt.Sprintf("Backends[%d].TotalResources", i), backend.TotalResources)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateResources(field string, resources Resources) error {
+	if resources.MemoryMB < 0 {
+		return CellStateValidationError{Field: field + ".MemoryMB", Message: "is negative"}
+	}
+	if resources.DiskMB < 0 {
+		return CellStateValidationError{Field: field + ".DiskMB", Message: "is negative"}
+	}
+	if resources.Containers < 0 {
+		return CellStateValidationError{Field: field + ".Containers", Message: "is negative"}
+	}
+	return nil
+}
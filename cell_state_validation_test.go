@@ -0,0 +1,55 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CellState Validate", func() {
+	var state rep.CellState
+
+	BeforeEach(func() {
+		state = rep.CellState{
+			CellID:         "cell-id",
+			TotalResources: rep.NewResources(1024, 2048, 10),
+			LRPs: []rep.LRP{
+				rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{}),
+			},
+			Tasks: []rep.Task{
+				rep.NewTask("tg-1", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{}),
+			},
+			Backends: []rep.BackendState{{Name: "windows", TotalResources: rep.NewResources(1024, 2048, 10)}},
+		}
+	})
+
+	It("accepts a complete state", func() {
+		Expect(state.Validate()).To(Succeed())
+	})
+
+	It("rejects a state without a cell id", func() {
+		state.CellID = ""
+		Expect(state.Validate()).To(Equal(rep.CellStateValidationError{Field: "cell_id", Message: "is missing"}))
+	})
+
+	It("rejects negative resources", func() {
+		state.TotalResources.DiskMB = -1
+		Expect(state.Validate()).To(MatchError("invalid cell state: TotalResources.DiskMB is negative"))
+	})
+
+	It("rejects LRPs without an instance guid", func() {
+		state.LRPs[0].InstanceGUID = ""
+		Expect(state.Validate()).To(MatchError("invalid cell state: LRPs[0].instance_guid is missing"))
+	})
+
+	It("rejects tasks without a task guid", func() {
+		state.Tasks[0].TaskGuid = ""
+		Expect(state.Validate()).To(MatchError("invalid cell state: Tasks[0].TaskGuid is missing"))
+	})
+
+	It("rejects unnamed backends", func() {
+		state.Backends[0].Name = ""
+		Expect(state.Validate()).To(MatchError("invalid cell state: Backends[0].Name is missing"))
+	})
+})
@@ -0,0 +1,18 @@
+package rep
+
+// CgroupInfo describes the cgroup hierarchy of the host a cell runs on.
+// Version is 1 or 2, and a host with a version 1 hierarchy that also mounts
+// the unified hierarchy is Hybrid. Controllers are the controllers available
+// to containers.
+type CgroupInfo struct {
+	Version     int      `json:"version"`
+	Hybrid      bool     `json:"hybrid,omitempty"`
+	Controllers []string `json:"controllers,omitempty"`
+}
+
+// ContainerCgroup describes the cgroup of a container, relative to the root
+// of the hierarchy, and the controllers enabled for it.
+type ContainerCgroup struct {
+	Path        string   `json:"path"`
+	Controllers []string `json:"controllers"`
+}
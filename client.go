@@ -2,14 +2,18 @@ package rep
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/bbs/models"
@@ -139,11 +143,20 @@ func (factory *clientFactory) CreateClient(address, url string) (Client, error)
 //go:generate counterfeiter -o repfakes/fake_client.go . Client
 
 type Client interface {
-	State(logger lager.Logger) (CellState, error)
-	Perform(logger lager.Logger, work Work) (Work, error)
-	UpdateLRPInstance(logger lager.Logger, update LRPUpdate) error
-	StopLRPInstance(logger lager.Logger, key models.ActualLRPKey, instanceKey models.ActualLRPInstanceKey) error
-	CancelTask(logger lager.Logger, taskGuid string) error
+	State(ctx context.Context, logger lager.Logger) (CellState, error)
+	StateSince(ctx context.Context, logger lager.Logger, base CellState, etag string) (CellState, string, error)
+	CapacitySummary(ctx context.Context, logger lager.Logger) (CapacitySummary, error)
+	Info(ctx context.Context, logger lager.Logger) (Info, error)
+	Containers(ctx context.Context, logger lager.Logger, selector string) (ContainerInventory, error)
+	Perform(ctx context.Context, logger lager.Logger, work Work) (Work, error)
+	CanPlace(ctx context.Context, logger lager.Logger, work Work, startingContainerWeight float64, scoringStrategy string) (PlacementChecks, error)
+	UpdateLRPInstance(ctx context.Context, logger lager.Logger, update LRPUpdate) error
+	StopLRPInstance(ctx context.Context, logger lager.Logger, key models.ActualLRPKey, instanceKey models.ActualLRPInstanceKey) error
+	StopLRPInstances(ctx context.Context, logger lager.Logger, instances []StopLRPInstanceRequest) ([]StopLRPInstanceResult, error)
+	CancelTask(ctx context.Context, logger lager.Logger, taskGuid string) error
+	ReserveCapacity(ctx context.Context, logger lager.Logger, request CapacityReservationRequest) (CapacityReservation, error)
+	ReleaseCapacity(ctx context.Context, logger lager.Logger, reservationID string) error
+	GrowDiskQuota(ctx context.Context, logger lager.Logger, containerGuid string, diskMB int32) error
 	SetStateClient(stateClient *http.Client)
 	StateClientTimeout() time.Duration
 }
@@ -152,7 +165,7 @@ type Client interface {
 
 type SimClient interface {
 	Client
-	Reset() error
+	Reset(ctx context.Context) error
 }
 
 type client struct {
@@ -160,6 +173,11 @@ type client struct {
 	stateClient      *http.Client
 	address          string
 	requestGenerator *rata.RequestGenerator
+
+	// batchLock guards the MaxWorkBatchSize last advertised by the cell,
+	// which Perform splits larger work into batches of.
+	batchLock        sync.Mutex
+	maxWorkBatchSize int
 }
 
 func newClient(httpClient, stateClient *http.Client, address string) Client {
@@ -179,8 +197,8 @@ func (c *client) StateClientTimeout() time.Duration {
 	return c.stateClient.Timeout
 }
 
-func (c *client) State(logger lager.Logger) (CellState, error) {
-	req, err := c.requestGenerator.CreateRequest(StateRoute, nil, nil)
+func (c *client) State(ctx context.Context, logger lager.Logger) (CellState, error) {
+	req, err := c.createRequest(ctx, StateRoute, nil, nil)
 	if err != nil {
 		return CellState{}, err
 	}
@@ -191,6 +209,10 @@ func (c *client) State(logger lager.Logger) (CellState, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		bs, _ := ioutil.ReadAll(resp.Body)
+		return CellState{}, unhealthyError(bs, false)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return CellState{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -205,16 +227,208 @@ func (c *client) State(logger lager.Logger) (CellState, error) {
 		return CellState{}, err
 	}
 
+	err = state.Validate()
+	if err != nil {
+		return CellState{}, err
+	}
+
 	return state, nil
 }
 
-func (c *client) Perform(logger lager.Logger, work Work) (Work, error) {
+// StateSince fetches the state relative to base, the state previously
+// fetched with etag, and returns the current state and its etag. Only the
+// changes are transferred when the cell still remembers base; otherwise the
+// full state is. An empty etag fetches the full state.
+func (c *client) StateSince(ctx context.Context, logger lager.Logger, base CellState, etag string) (CellState, string, error) {
+	req, err := c.createRequest(ctx, StateRoute, nil, nil)
+	if err != nil {
+		return CellState{}, "", err
+	}
+	if etag != "" {
+		req.URL.RawQuery = url.Values{"since": []string{etag}}.Encode()
+	}
+
+	resp, err := c.stateClient.Do(req)
+	if err != nil {
+		return CellState{}, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		bs, _ := ioutil.ReadAll(resp.Body)
+		return CellState{}, "", unhealthyError(bs, resp.Header.Get(StateDeltaHeader) != "")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CellState{}, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return CellState{}, "", err
+	}
+	newETag := strings.Trim(resp.Header.Get("ETag"), `"`)
+
+	if resp.Header.Get(StateDeltaHeader) == "" {
+		var state CellState
+		err = json.Unmarshal(bs, &state)
+		if err != nil {
+			return CellState{}, "", err
+		}
+		err = state.Validate()
+		if err != nil {
+			return CellState{}, "", err
+		}
+		return state, newETag, nil
+	}
+
+	var delta CellStateDelta
+	err = json.Unmarshal(bs, &delta)
+	if err != nil {
+		return CellState{}, "", err
+	}
+	if delta.Since != etag {
+		return CellState{}, "", fmt.Errorf("state delta is relative to %q rather than %q", delta.Since, etag)
+	}
+
+	state := delta.Apply(base)
+	err = state.Validate()
+	if err != nil {
+		return CellState{}, "", err
+	}
+	return state, newETag, nil
+}
+
+// CapacitySummary fetches the aggregate capacity of the cell without its LRP
+// instances and tasks. It is cheaper to poll often than State.
+func (c *client) CapacitySummary(ctx context.Context, logger lager.Logger) (CapacitySummary, error) {
+	req, err := c.createRequest(ctx, CapacitySummaryRoute, nil, nil)
+	if err != nil {
+		return CapacitySummary{}, err
+	}
+
+	resp, err := c.stateClient.Do(req)
+	if err != nil {
+		return CapacitySummary{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return CapacitySummary{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var summary CapacitySummary
+	err = json.NewDecoder(resp.Body).Decode(&summary)
+	if err != nil {
+		return CapacitySummary{}, err
+	}
+
+	return summary, nil
+}
+
+func (c *client) Info(ctx context.Context, logger lager.Logger) (Info, error) {
+	req, err := c.createRequest(ctx, InfoRoute, nil, nil)
+	if err != nil {
+		return Info{}, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Info{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Info{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var info Info
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	if err != nil {
+		return Info{}, err
+	}
+
+	c.setMaxWorkBatchSize(info.Limits.MaxWorkBatchSize)
+	return info, nil
+}
+
+// Containers lists the LRP instances and tasks on the cell whose labels match
+// selector. An empty selector lists all of them.
+func (c *client) Containers(ctx context.Context, logger lager.Logger, selector string) (ContainerInventory, error) {
+	req, err := c.createRequest(ctx, ContainersRoute, nil, nil)
+	if err != nil {
+		return ContainerInventory{}, err
+	}
+	if selector != "" {
+		req.URL.RawQuery = url.Values{"selector": []string{selector}}.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ContainerInventory{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ContainerInventory{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var inventory ContainerInventory
+	err = json.NewDecoder(resp.Body).Decode(&inventory)
+	if err != nil {
+		return ContainerInventory{}, err
+	}
+
+	return inventory, nil
+}
+
+// Perform allocates work on the cell. Work larger than the MaxWorkBatchSize
+// the cell advertised, in its info or when rejecting a batch, is performed in
+// batches of that size. Once a batch has been performed, the batches that
+// fail are returned as failed work rather than as an error, since the work
+// of the others was allocated.
+func (c *client) Perform(ctx context.Context, logger lager.Logger, work Work) (Work, error) {
+	batches := work.Chunks(c.getMaxWorkBatchSize())
+	if len(batches) == 1 {
+		failedWork, err := c.perform(ctx, logger, work)
+		tooLarge, ok := err.(WorkBatchTooLargeError)
+		if !ok {
+			return failedWork, err
+		}
+
+		c.setMaxWorkBatchSize(tooLarge.MaxWorkBatchSize)
+		batches = work.Chunks(tooLarge.MaxWorkBatchSize)
+		if len(batches) == 1 {
+			return Work{}, err
+		}
+	}
+
+	logger = logger.Session("perform-batches", lager.Data{"batches": len(batches)})
+
+	failedWork := Work{}
+	performed := false
+	for i := range batches {
+		failed, err := c.perform(ctx, logger, batches[i])
+		if err != nil {
+			if !performed {
+				return Work{}, err
+			}
+			logger.Error("failed-to-perform-batch", err, lager.Data{"batch": i})
+			failed = Work{LRPs: batches[i].LRPs, Tasks: batches[i].Tasks}
+		}
+		performed = true
+		appendWork(&failedWork, failed)
+	}
+
+	return failedWork, nil
+}
+
+func (c *client) perform(ctx context.Context, logger lager.Logger, work Work) (Work, error) {
 	body, err := json.Marshal(work)
 	if err != nil {
 		return Work{}, err
 	}
 
-	req, err := c.requestGenerator.CreateRequest(PerformRoute, nil, bytes.NewReader(body))
+	req, err := c.createRequest(ctx, PerformRoute, nil, bytes.NewReader(body))
 	if err != nil {
 		return Work{}, err
 	}
@@ -225,6 +439,17 @@ func (c *client) Perform(logger lager.Logger, work Work) (Work, error) {
 	}
 	defer resp.Body.Close()
 
+	if timing, ok := RequestTimingFromHeader(resp.Header); ok {
+		logger.Debug("perform-timing", lager.Data{"queue-time": timing.Queue.String(), "handler-time": timing.Handler.String()})
+	}
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		var tooLarge WorkBatchTooLargeError
+		if json.NewDecoder(resp.Body).Decode(&tooLarge) == nil && tooLarge.MaxWorkBatchSize > 0 {
+			return Work{}, tooLarge
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return Work{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -238,8 +463,58 @@ func (c *client) Perform(logger lager.Logger, work Work) (Work, error) {
 	return failedWork, nil
 }
 
-func (c *client) Reset() error {
-	req, err := c.requestGenerator.CreateRequest(SimResetRoute, nil, nil)
+func (c *client) getMaxWorkBatchSize() int {
+	c.batchLock.Lock()
+	defer c.batchLock.Unlock()
+	return c.maxWorkBatchSize
+}
+
+func (c *client) setMaxWorkBatchSize(size int) {
+	c.batchLock.Lock()
+	defer c.batchLock.Unlock()
+	c.maxWorkBatchSize = size
+}
+
+// CanPlace asks the cell whether it would accept each LRP instance and task of
+// work, scoring it with startingContainerWeight by the built-in strategy named
+// scoringStrategy, or spreading when it is empty, without performing the work.
+func (c *client) CanPlace(ctx context.Context, logger lager.Logger, work Work, startingContainerWeight float64, scoringStrategy string) (PlacementChecks, error) {
+	body, err := json.Marshal(work)
+	if err != nil {
+		return PlacementChecks{}, err
+	}
+
+	req, err := c.createRequest(ctx, CanPlaceRoute, nil, bytes.NewReader(body))
+	if err != nil {
+		return PlacementChecks{}, err
+	}
+	query := url.Values{"starting_container_weight": []string{strconv.FormatFloat(startingContainerWeight, 'f', -1, 64)}}
+	if scoringStrategy != "" {
+		query.Set("scoring_strategy", scoringStrategy)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PlacementChecks{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return PlacementChecks{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var checks PlacementChecks
+	err = json.NewDecoder(resp.Body).Decode(&checks)
+	if err != nil {
+		return PlacementChecks{}, err
+	}
+
+	return checks, nil
+}
+
+func (c *client) Reset(ctx context.Context) error {
+	req, err := c.createRequest(ctx, SimResetRoute, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -258,6 +533,7 @@ func (c *client) Reset() error {
 }
 
 func (c *client) UpdateLRPInstance(
+	ctx context.Context,
 	logger lager.Logger,
 	update LRPUpdate,
 ) error {
@@ -279,7 +555,7 @@ func (c *client) UpdateLRPInstance(
 		logger.Error("marshal-failed", err)
 		return err
 	}
-	req, err := c.requestGenerator.CreateRequest(UpdateLRPInstanceRoute, params, bytes.NewReader(body))
+	req, err := c.createRequest(ctx, UpdateLRPInstanceRoute, params, bytes.NewReader(body))
 	if err != nil {
 		logger.Error("connection-failed", err)
 		return err
@@ -302,7 +578,7 @@ func (c *client) UpdateLRPInstance(
 		// on old versions of rep. This is for backwards compatibility.
 		if update.InternalRoutes != nil {
 			update.MetricTags = nil
-			return c.updateLRPInstanceRoute_r0(loggerCopy, update)
+			return c.updateLRPInstanceRoute_r0(ctx, loggerCopy, update)
 		}
 		return nil
 	}
@@ -318,6 +594,7 @@ func (c *client) UpdateLRPInstance(
 }
 
 func (c *client) updateLRPInstanceRoute_r0(
+	ctx context.Context,
 	logger lager.Logger,
 	update LRPUpdate) error {
 	start := time.Now()
@@ -338,7 +615,7 @@ func (c *client) updateLRPInstanceRoute_r0(
 		"process_guid":  update.ProcessGuid,
 		"instance_guid": update.InstanceGUID,
 	}
-	req, err := c.requestGenerator.CreateRequest(UpdateLRPInstanceRoute_r0, params, bytes.NewReader(body))
+	req, err := c.createRequest(ctx, UpdateLRPInstanceRoute_r0, params, bytes.NewReader(body))
 	if err != nil {
 		logger.Error("connection-failed", err)
 		return err
@@ -369,6 +646,7 @@ func (c *client) updateLRPInstanceRoute_r0(
 }
 
 func (c *client) StopLRPInstance(
+	ctx context.Context,
 	logger lager.Logger,
 	key models.ActualLRPKey,
 	instanceKey models.ActualLRPInstanceKey,
@@ -381,7 +659,7 @@ func (c *client) StopLRPInstance(
 	})
 	logger.Info("starting")
 
-	req, err := c.requestGenerator.CreateRequest(StopLRPInstanceRoute, stopParamsFromLRP(key, instanceKey), nil)
+	req, err := c.createRequest(ctx, StopLRPInstanceRoute, stopParamsFromLRP(key, instanceKey), nil)
 	if err != nil {
 		logger.Error("connection-failed", err)
 		return err
@@ -406,12 +684,57 @@ func (c *client) StopLRPInstance(
 	return nil
 }
 
-func (c *client) CancelTask(logger lager.Logger, taskGuid string) error {
+// StopLRPInstances stops many instances with a single request. The results
+// are in the order of the instances.
+func (c *client) StopLRPInstances(ctx context.Context, logger lager.Logger, instances []StopLRPInstanceRequest) ([]StopLRPInstanceResult, error) {
+	start := time.Now()
+	logger = logger.Session("stop-lrps", lager.Data{"num-instances": len(instances)})
+	logger.Info("starting")
+
+	body, err := json.Marshal(instances)
+	if err != nil {
+		logger.Error("marshal-failed", err)
+		return nil, err
+	}
+
+	req, err := c.createRequest(ctx, StopLRPInstancesRoute, nil, bytes.NewReader(body))
+	if err != nil {
+		logger.Error("connection-failed", err)
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("request-failed", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("http error: status code %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+		logger.Error("failed-with-status", err, lager.Data{"status-code": resp.StatusCode, "msg": http.StatusText(resp.StatusCode)})
+		return nil, err
+	}
+
+	var results []StopLRPInstanceResult
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	if err != nil {
+		logger.Error("failed-to-decode-results", err)
+		return nil, err
+	}
+
+	logger.Info("completed", lager.Data{"duration": time.Since(start)})
+	return results, nil
+}
+
+func (c *client) CancelTask(ctx context.Context, logger lager.Logger, taskGuid string) error {
 	start := time.Now()
 	logger = logger.Session("cancel-task", lager.Data{"task-guid": taskGuid})
 	logger.Info("starting")
 
-	req, err := c.requestGenerator.CreateRequest(CancelTaskRoute, rata.Params{"task_guid": taskGuid}, nil)
+	req, err := c.createRequest(ctx, CancelTaskRoute, rata.Params{"task_guid": taskGuid}, nil)
 	if err != nil {
 		logger.Error("connection-failed", err)
 		return err
@@ -435,6 +758,128 @@ func (c *client) CancelTask(logger lager.Logger, taskGuid string) error {
 	return nil
 }
 
+func (c *client) ReserveCapacity(ctx context.Context, logger lager.Logger, request CapacityReservationRequest) (CapacityReservation, error) {
+	start := time.Now()
+	logger = logger.Session("reserve-capacity", lager.Data{"process-guid": request.ProcessGuid, "instances": request.Instances})
+	logger.Info("starting")
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		logger.Error("marshal-failed", err)
+		return CapacityReservation{}, err
+	}
+
+	req, err := c.createRequest(ctx, ReserveCapacityRoute, nil, bytes.NewReader(body))
+	if err != nil {
+		logger.Error("connection-failed", err)
+		return CapacityReservation{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("request-failed", err)
+		return CapacityReservation{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		err := fmt.Errorf("http error: status code %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+		logger.Error("failed-with-status", err, lager.Data{"status-code": resp.StatusCode, "msg": http.StatusText(resp.StatusCode)})
+		return CapacityReservation{}, err
+	}
+
+	var reservation CapacityReservation
+	err = json.NewDecoder(resp.Body).Decode(&reservation)
+	if err != nil {
+		logger.Error("failed-to-decode-reservation", err)
+		return CapacityReservation{}, err
+	}
+
+	logger.Info("completed", lager.Data{"duration": time.Since(start), "reservation-id": reservation.ID})
+	return reservation, nil
+}
+
+func (c *client) ReleaseCapacity(ctx context.Context, logger lager.Logger, reservationID string) error {
+	start := time.Now()
+	logger = logger.Session("release-capacity", lager.Data{"reservation-id": reservationID})
+	logger.Info("starting")
+
+	req, err := c.createRequest(ctx, ReleaseCapacityRoute, rata.Params{"reservation_id": reservationID}, nil)
+	if err != nil {
+		logger.Error("connection-failed", err)
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("request-failed", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err := fmt.Errorf("http error: status code %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+		logger.Error("failed-with-status", err, lager.Data{"status-code": resp.StatusCode, "msg": http.StatusText(resp.StatusCode)})
+		return err
+	}
+
+	logger.Info("completed", lager.Data{"duration": time.Since(start)})
+	return nil
+}
+
+// GrowDiskQuota grows the disk quota of the running container with
+// containerGuid to diskMB without restarting it.
+func (c *client) GrowDiskQuota(ctx context.Context, logger lager.Logger, containerGuid string, diskMB int32) error {
+	start := time.Now()
+	logger = logger.Session("grow-disk-quota", lager.Data{"container-guid": containerGuid, "disk-mb": diskMB})
+	logger.Info("starting")
+
+	body, err := json.Marshal(DiskQuotaGrowthRequest{DiskMB: diskMB})
+	if err != nil {
+		logger.Error("marshal-failed", err)
+		return err
+	}
+
+	req, err := c.createRequest(ctx, GrowDiskQuotaRoute, rata.Params{"container_guid": containerGuid}, bytes.NewReader(body))
+	if err != nil {
+		logger.Error("connection-failed", err)
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("request-failed", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		err := fmt.Errorf("http error: status code %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+		logger.Error("failed-with-status", err, lager.Data{"status-code": resp.StatusCode, "msg": http.StatusText(resp.StatusCode)})
+		return err
+	}
+
+	logger.Info("completed", lager.Data{"duration": time.Since(start)})
+	return nil
+}
+
+// createRequest creates the request for the named route, cancelled when ctx
+// is done.
+func (c *client) createRequest(ctx context.Context, name string, params rata.Params, body io.Reader) (*http.Request, error) {
+	req, err := c.requestGenerator.CreateRequest(name, params, body)
+	if err != nil {
+		return nil, err
+	}
+	if trace := TraceContextFromContext(ctx); trace != nil {
+		trace.SetHeader(req.Header)
+	}
+	return req.WithContext(ctx), nil
+}
+
 func stopParamsFromLRP(
 	key models.ActualLRPKey,
 	instanceKey models.ActualLRPInstanceKey,
@@ -1,6 +1,7 @@
 package rep_test
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"path"
@@ -113,10 +114,515 @@ var _ = Describe("Client", func() {
 		// cannot think of a better way. see
 		// https://www.pivotaltracker.com/story/show/144907419 for more info
 		It("reads the entire body", func() {
-			client.State(logger)
-			client.State(logger)
+			client.State(context.Background(), logger)
+			client.State(context.Background(), logger)
 			Expect(addrs).To(HaveLen(1))
 		})
+
+		Context("when the cell state is invalid", func() {
+			BeforeEach(func() {
+				fakeServer.RouteToHandler("GET", "/state", ghttp.RespondWithJSONEncoded(http.StatusOK, rep.CellState{RepURL: "https://cell-id"}))
+			})
+
+			It("returns a validation error", func() {
+				_, err := client.State(context.Background(), logger)
+				Expect(err).To(Equal(rep.CellStateValidationError{Field: "cell_id", Message: "is missing"}))
+			})
+		})
+
+		Context("when the cell is unhealthy", func() {
+			BeforeEach(func() {
+				state := rep.CellState{CellID: "cell-id", UnhealthyReasons: []string{rep.UnhealthyReasonGardenUnresponsive}}
+				fakeServer.RouteToHandler("GET", "/state", ghttp.RespondWithJSONEncoded(http.StatusServiceUnavailable, state))
+			})
+
+			It("returns the reasons the cell gave", func() {
+				_, err := client.State(context.Background(), logger)
+				Expect(err).To(Equal(rep.CellUnhealthyError{Reasons: []string{rep.UnhealthyReasonGardenUnresponsive}}))
+				Expect(err).To(MatchError("cell is unhealthy: garden unresponsive"))
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("does not send the request", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := client.State(ctx, logger)
+				Expect(err).To(MatchError(ContainSubstring(context.Canceled.Error())))
+				Expect(fakeServer.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("StateSince", func() {
+		var (
+			logger  = lagertest.NewTestLogger("test")
+			base    rep.CellState
+			state   rep.CellState
+			etag    string
+			err     error
+			current rep.CellState
+		)
+
+		BeforeEach(func() {
+			base = rep.CellState{CellID: "cell-id", Tasks: []rep.Task{
+				rep.NewTask("task-1", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{}),
+			}}
+			current = rep.CellState{CellID: "cell-id", LRPs: []rep.LRP{}, Tasks: []rep.Task{
+				rep.NewTask("task-2", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{}),
+			}}
+		})
+
+		JustBeforeEach(func() {
+			state, etag, err = client.StateSince(context.Background(), logger, base, "base-etag")
+		})
+
+		Context("when the rep responds with a delta", func() {
+			BeforeEach(func() {
+				delta := rep.NewCellStateDelta("base-etag", base, current)
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/state", "since=base-etag"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, delta, http.Header{
+							"ETag":               []string{`"current-etag"`},
+							rep.StateDeltaHeader: []string{"true"},
+						}),
+					),
+				)
+			})
+
+			It("applies the delta to the base state", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(etag).To(Equal("current-etag"))
+				Expect(state).To(Equal(current))
+			})
+		})
+
+		Context("when the cell is unhealthy", func() {
+			BeforeEach(func() {
+				current.UnhealthyReasons = []string{rep.UnhealthyReasonGardenUnresponsive}
+				delta := rep.NewCellStateDelta("base-etag", base, current)
+				fakeServer.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusServiceUnavailable, delta, http.Header{
+						rep.StateDeltaHeader: []string{"true"},
+					}),
+				)
+			})
+
+			It("returns the reasons the cell gave", func() {
+				Expect(err).To(Equal(rep.CellUnhealthyError{Reasons: []string{rep.UnhealthyReasonGardenUnresponsive}}))
+			})
+		})
+
+		Context("when the rep responds with the full state", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusOK, current, http.Header{
+						"ETag": []string{`"current-etag"`},
+					}),
+				)
+			})
+
+			It("returns the full state", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(etag).To(Equal("current-etag"))
+				Expect(state).To(Equal(current))
+			})
+		})
+
+		Context("when the delta is relative to another snapshot", func() {
+			BeforeEach(func() {
+				delta := rep.NewCellStateDelta("other-etag", base, current)
+				fakeServer.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusOK, delta, http.Header{
+						rep.StateDeltaHeader: []string{"true"},
+					}),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("other-etag")))
+			})
+		})
+
+		Context("when the delta leaves the state invalid", func() {
+			BeforeEach(func() {
+				current.Tasks[0].TaskGuid = ""
+				delta := rep.NewCellStateDelta("base-etag", base, current)
+				fakeServer.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusOK, delta, http.Header{
+						rep.StateDeltaHeader: []string{"true"},
+					}),
+				)
+			})
+
+			It("returns a validation error", func() {
+				Expect(err).To(Equal(rep.CellStateValidationError{Field: "Tasks[0].TaskGuid", Message: "is missing"}))
+			})
+		})
+	})
+
+	Describe("Info", func() {
+		var logger = lagertest.NewTestLogger("test")
+
+		Context("when the request is successful", func() {
+			var info rep.Info
+
+			BeforeEach(func() {
+				info = rep.Info{
+					Version:      "1.2.3",
+					APIVersions:  []string{"v1", "v2"},
+					FeatureFlags: []string{"local_restart"},
+					Limits:       rep.Limits{MaxRequestBodyBytes: 1024, MaxWorkBatchSize: 10},
+				}
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/info"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, info),
+					),
+				)
+			})
+
+			It("returns the info of the rep", func() {
+				actual, err := client.Info(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(info))
+			})
+		})
+
+		Context("when the rep does not serve the info", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+			})
+
+			It("returns an error", func() {
+				_, err := client.Info(context.Background(), logger)
+				Expect(err).To(MatchError("unexpected status code: 404"))
+			})
+		})
+	})
+
+	Describe("Perform", func() {
+		var logger = lagertest.NewTestLogger("test")
+
+		Context("when the context carries a trace context", func() {
+			const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/work"),
+						ghttp.VerifyHeaderKV(rep.TraceParentHeader, traceParent),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, rep.Work{}),
+					),
+				)
+			})
+
+			It("forwards it to the rep", func() {
+				ctx := rep.WithTraceContext(context.Background(), &rep.TraceContext{TraceParent: traceParent})
+				_, err := client.Perform(ctx, logger, rep.Work{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		Context("when the work exceeds the max work batch size of the cell", func() {
+			var work rep.Work
+
+			BeforeEach(func() {
+				work = rep.Work{
+					LRPs:  []rep.LRP{{InstanceGUID: "ig-1"}, {InstanceGUID: "ig-2"}},
+					Tasks: []rep.Task{{TaskGuid: "tg-1"}},
+				}
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/work"),
+						ghttp.RespondWithJSONEncoded(http.StatusRequestEntityTooLarge, rep.NewWorkBatchTooLargeError(2)),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/work"),
+						ghttp.VerifyJSONRepresenting(rep.Work{LRPs: work.LRPs}),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, rep.Work{LRPs: work.LRPs[1:]}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/work"),
+						ghttp.VerifyJSONRepresenting(rep.Work{Tasks: work.Tasks}),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, rep.Work{}),
+					),
+				)
+			})
+
+			It("performs the work in batches of the advertised size", func() {
+				failedWork, err := client.Perform(context.Background(), logger, work)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failedWork.LRPs).To(Equal(work.LRPs[1:]))
+				Expect(failedWork.Tasks).To(BeEmpty())
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(3))
+			})
+
+			Context("when a later batch fails", func() {
+				BeforeEach(func() {
+					fakeServer.SetHandler(2, ghttp.RespondWith(http.StatusInternalServerError, ""))
+				})
+
+				It("returns its work as failed", func() {
+					failedWork, err := client.Perform(context.Background(), logger, work)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(failedWork.LRPs).To(Equal(work.LRPs[1:]))
+					Expect(failedWork.Tasks).To(Equal(work.Tasks))
+				})
+			})
+		})
+
+		Context("when the cell advertised its max work batch size", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusOK, rep.Info{Limits: rep.Limits{MaxWorkBatchSize: 1}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, rep.Work{}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, rep.Work{}),
+				)
+			})
+
+			It("splits the work without being rejected first", func() {
+				_, err := client.Info(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = client.Perform(context.Background(), logger, rep.Work{LRPs: []rep.LRP{{InstanceGUID: "ig-1"}}, Tasks: []rep.Task{{TaskGuid: "tg-1"}}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(3))
+			})
+		})
+	})
+
+	Describe("CapacitySummary", func() {
+		var logger = lagertest.NewTestLogger("test")
+
+		Context("when the request is successful", func() {
+			var summary rep.CapacitySummary
+
+			BeforeEach(func() {
+				summary = rep.CapacitySummary{
+					CellID:             "cell-id",
+					Zone:               "z1",
+					RootFSProviders:    rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+					AvailableResources: rep.NewResources(512, 1024, 3),
+					TotalResources:     rep.NewResources(1024, 2048, 10),
+					PlacementTags:      []string{"gpu"},
+				}
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/capacity"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, summary),
+					),
+				)
+			})
+
+			It("returns the capacity summary", func() {
+				actual, err := client.CapacitySummary(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(summary))
+			})
+		})
+
+		Context("when the cell is unhealthy", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, ""))
+			})
+
+			It("returns an error", func() {
+				_, err := client.CapacitySummary(context.Background(), logger)
+				Expect(err).To(MatchError("unexpected status code: 503"))
+			})
+		})
+	})
+
+	Describe("CanPlace", func() {
+		var (
+			logger = lagertest.NewTestLogger("test")
+			work   rep.Work
+		)
+
+		BeforeEach(func() {
+			work = rep.Work{LRPs: []rep.LRP{{InstanceGUID: "ig-1"}}}
+		})
+
+		Context("when the request is successful", func() {
+			var checks rep.PlacementChecks
+
+			BeforeEach(func() {
+				checks = rep.PlacementChecks{
+					CellID: "cell-id",
+					LRPs:   []rep.PlacementCheck{{InstanceGUID: "ig-1", Placeable: true, Score: 0.5}},
+					Tasks:  []rep.PlacementCheck{},
+					Score:  0.5,
+				}
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/can_place", "scoring_strategy=bin-pack&starting_container_weight=0.25"),
+						ghttp.VerifyJSONRepresenting(work),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, checks),
+					),
+				)
+			})
+
+			It("returns the placement checks", func() {
+				actual, err := client.CanPlace(context.Background(), logger, work, 0.25, rep.ScoringStrategyBinPack)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(checks))
+			})
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				_, err := client.CanPlace(context.Background(), logger, work, 0, "")
+				Expect(err).To(MatchError("unexpected status code: 500"))
+			})
+		})
+	})
+
+	Describe("Containers", func() {
+		var logger = lagertest.NewTestLogger("test")
+
+		Context("when the request is successful", func() {
+			var inventory rep.ContainerInventory
+
+			BeforeEach(func() {
+				inventory = rep.ContainerInventory{
+					LRPs:  []rep.LRP{{InstanceGUID: "ig-1", Labels: map[string]string{"tier": "web"}}},
+					Tasks: []rep.Task{},
+				}
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/containers", "selector=tier%3Dweb"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, inventory),
+					),
+				)
+			})
+
+			It("returns the matching containers", func() {
+				actual, err := client.Containers(context.Background(), logger, "tier=web")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(inventory))
+			})
+		})
+
+		Context("when the selector is rejected", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusBadRequest, ""))
+			})
+
+			It("returns an error", func() {
+				_, err := client.Containers(context.Background(), logger, "tier==web")
+				Expect(err).To(MatchError("unexpected status code: 400"))
+			})
+		})
+	})
+
+	Describe("ReserveCapacity", func() {
+		var (
+			logger  = lagertest.NewTestLogger("test")
+			request rep.CapacityReservationRequest
+		)
+
+		BeforeEach(func() {
+			request = rep.NewCapacityReservationRequest("pg-new", rep.NewResource(256, 512, 10), 3, 300)
+		})
+
+		Context("when the request is successful", func() {
+			var reservation rep.CapacityReservation
+
+			BeforeEach(func() {
+				reservation = rep.CapacityReservation{ID: "reservation-id", ProcessGuid: "pg-new", Resource: request.Resource, Instances: 3, ExpiresAt: 1234}
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/v1/capacity_reservations"),
+						ghttp.VerifyJSONRepresenting(request),
+						ghttp.RespondWithJSONEncoded(http.StatusCreated, reservation),
+					),
+				)
+			})
+
+			It("returns the reservation", func() {
+				actual, err := client.ReserveCapacity(context.Background(), logger, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(reservation))
+			})
+		})
+
+		Context("when the cell does not have the capacity", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusConflict, ""))
+			})
+
+			It("returns an error", func() {
+				_, err := client.ReserveCapacity(context.Background(), logger, request)
+				Expect(err).To(MatchError(ContainSubstring("http error: status code 409")))
+			})
+		})
+	})
+
+	Describe("ReleaseCapacity", func() {
+		var logger = lagertest.NewTestLogger("test")
+
+		Context("when the request is successful", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("DELETE", "/v1/capacity_reservations/reservation-id"),
+						ghttp.RespondWith(http.StatusNoContent, ""),
+					),
+				)
+			})
+
+			It("succeeds", func() {
+				Expect(client.ReleaseCapacity(context.Background(), logger, "reservation-id")).To(Succeed())
+			})
+		})
+
+		Context("when the reservation does not exist", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+			})
+
+			It("returns an error", func() {
+				Expect(client.ReleaseCapacity(context.Background(), logger, "reservation-id")).To(MatchError(ContainSubstring("http error: status code 404")))
+			})
+		})
+	})
+
+	Describe("GrowDiskQuota", func() {
+		var logger = lagertest.NewTestLogger("test")
+
+		Context("when the request is successful", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/v1/containers/task-guid/disk_quota"),
+						ghttp.VerifyJSONRepresenting(rep.DiskQuotaGrowthRequest{DiskMB: 2048}),
+						ghttp.RespondWith(http.StatusNoContent, ""),
+					),
+				)
+			})
+
+			It("succeeds", func() {
+				Expect(client.GrowDiskQuota(context.Background(), logger, "task-guid", 2048)).To(Succeed())
+			})
+		})
+
+		Context("when the backend cannot grow disk quotas", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusNotImplemented, ""))
+			})
+
+			It("returns an error", func() {
+				Expect(client.GrowDiskQuota(context.Background(), logger, "task-guid", 2048)).To(MatchError(ContainSubstring("http error: status code 501")))
+			})
+		})
 	})
 
 	Describe("UpdateLRPInstance", func() {
@@ -140,7 +646,7 @@ var _ = Describe("Client", func() {
 		})
 
 		JustBeforeEach(func() {
-			updateErr = client.UpdateLRPInstance(logger, lrpUpdate)
+			updateErr = client.UpdateLRPInstance(context.Background(), logger, lrpUpdate)
 		})
 
 		Context("when the request is successful", func() {
@@ -329,7 +835,7 @@ var _ = Describe("Client", func() {
 		)
 
 		JustBeforeEach(func() {
-			stopErr = client.StopLRPInstance(logger, actualLRP.ActualLRPKey, actualLRP.ActualLRPInstanceKey)
+			stopErr = client.StopLRPInstance(context.Background(), logger, actualLRP.ActualLRPKey, actualLRP.ActualLRPInstanceKey)
 		})
 
 		Context("when the request is successful", func() {
@@ -418,6 +924,60 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("StopLRPInstances", func() {
+		var (
+			logger    = lagertest.NewTestLogger("test")
+			instances []rep.StopLRPInstanceRequest
+			results   []rep.StopLRPInstanceResult
+			stopErr   error
+		)
+
+		BeforeEach(func() {
+			instances = []rep.StopLRPInstanceRequest{
+				rep.NewStopLRPInstanceRequest(models.NewActualLRPKey("pg-1", 0, "domain"), models.NewActualLRPInstanceKey("ig-1", "cell-id")),
+				rep.NewStopLRPInstanceRequest(models.NewActualLRPKey("pg-1", 1, "domain"), models.NewActualLRPInstanceKey("ig-2", "cell-id")),
+			}
+		})
+
+		JustBeforeEach(func() {
+			results, stopErr = client.StopLRPInstances(context.Background(), logger, instances)
+		})
+
+		Context("when the request is successful", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/v1/lrps/instances/stop"),
+						ghttp.VerifyJSONRepresenting(instances),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, []rep.StopLRPInstanceResult{
+							{ProcessGuid: "pg-1", InstanceGuid: "ig-1"},
+							{ProcessGuid: "pg-1", InstanceGuid: "ig-2", Error: "boom"},
+						}),
+					),
+				)
+			})
+
+			It("returns the result for each instance", func() {
+				Expect(stopErr).NotTo(HaveOccurred())
+				Expect(results).To(Equal([]rep.StopLRPInstanceResult{
+					{ProcessGuid: "pg-1", InstanceGuid: "ig-1"},
+					{ProcessGuid: "pg-1", InstanceGuid: "ig-2", Error: "boom"},
+				}))
+			})
+		})
+
+		Context("when the request returns 500", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				Expect(stopErr).To(MatchError(ContainSubstring("http error: status code 500")))
+				Expect(results).To(BeNil())
+			})
+		})
+	})
+
 	Describe("CancelTask", func() {
 		const cellAddr = "cell.example.com"
 		var (
@@ -427,7 +987,7 @@ var _ = Describe("Client", func() {
 		)
 
 		JustBeforeEach(func() {
-			cancelErr = client.CancelTask(logger, taskGuid)
+			cancelErr = client.CancelTask(context.Background(), logger, taskGuid)
 		})
 
 		Context("when the request is successful", func() {
@@ -0,0 +1,13 @@
+package clockskew_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestClockSkew(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clock Skew Suite")
+}
@@ -0,0 +1,46 @@
+package clockskew
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const clockSkewMetric = "ClockSkew"
+
+// Monitor turns the absolute deadlines callers give the cell into durations
+// on the monotonic clock of the cell, and emits the skew between the clocks
+// whenever it exceeds the tolerance.
+type Monitor struct {
+	metronClient loggingclient.IngressClient
+	clock        clock.Clock
+	tolerance    time.Duration
+}
+
+func NewMonitor(metronClient loggingclient.IngressClient, clock clock.Clock, tolerance time.Duration) *Monitor {
+	return &Monitor{
+		metronClient: metronClient,
+		clock:        clock,
+		tolerance:    tolerance,
+	}
+}
+
+// Remaining returns how long remains until deadline, as rep.Deadline
+// Remaining measures it within the tolerance of the monitor.
+func (m *Monitor) Remaining(logger lager.Logger, deadline rep.Deadline) time.Duration {
+	remaining, skew := deadline.Remaining(m.clock.Now(), m.tolerance)
+	if skew > m.tolerance || skew < -m.tolerance {
+		logger.Info("clock-skew-detected", lager.Data{"skew": skew.String(), "tolerance": m.tolerance.String()})
+		if skew < 0 {
+			skew = -skew
+		}
+		err := m.metronClient.SendDuration(clockSkewMetric, skew)
+		if err != nil {
+			logger.Error("failed-to-send-clock-skew-metric", err)
+		}
+	}
+	return remaining
+}
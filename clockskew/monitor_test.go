@@ -0,0 +1,51 @@
+package clockskew_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/clockskew"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Monitor", func() {
+	var (
+		fakeClock        *fakeclock.FakeClock
+		fakeMetronClient *mfakes.FakeIngressClient
+		logger           *lagertest.TestLogger
+		monitor          *clockskew.Monitor
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		fakeMetronClient = new(mfakes.FakeIngressClient)
+		logger = lagertest.NewTestLogger("test")
+		monitor = clockskew.NewMonitor(fakeMetronClient, fakeClock, 2*time.Second)
+	})
+
+	It("does not emit the skew while it is within tolerance", func() {
+		deadline := rep.NewDeadline(fakeClock.Now().Add(time.Minute), fakeClock.Now().Add(time.Second))
+
+		Expect(monitor.Remaining(logger, deadline)).To(Equal(time.Minute))
+		Expect(fakeMetronClient.SendDurationCallCount()).To(Equal(0))
+	})
+
+	It("emits the size of the skew and logs it when it exceeds the tolerance", func() {
+		callerNow := fakeClock.Now().Add(10 * time.Second)
+		deadline := rep.NewDeadline(callerNow.Add(time.Minute), callerNow)
+
+		Expect(monitor.Remaining(logger, deadline)).To(Equal(time.Minute))
+
+		Expect(fakeMetronClient.SendDurationCallCount()).To(Equal(1))
+		name, value, _ := fakeMetronClient.SendDurationArgsForCall(0)
+		Expect(name).To(Equal("ClockSkew"))
+		Expect(value).To(Equal(10 * time.Second))
+		Expect(logger).To(gbytes.Say("clock-skew-detected"))
+	})
+})
@@ -2,10 +2,12 @@ package config
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/debugserver"
 	loggingclient "code.cloudfoundry.org/diego-logging-client"
@@ -22,31 +24,135 @@ type RootFS struct {
 
 type RootFSes []RootFS
 
+// The limits on the preloaded rootfses of a cell, so that a malformed or
+// malicious config cannot make the rep build a huge stack path map.
+const (
+	MaxPreloadedRootFSes     = 256
+	MaxPreloadedRootFSLength = 4096
+)
+
+// The reasons of an InvalidRootFSError.
+const (
+	RootFSReasonTooMany        = "too many preloaded rootfses"
+	RootFSReasonTooLarge       = "payload larger than the maximum size"
+	RootFSReasonTooLong        = "longer than the maximum length"
+	RootFSReasonMalformed      = "not of the form 'stack-name:path'"
+	RootFSReasonBlankStack     = "blank stack"
+	RootFSReasonBlankPath      = "blank path"
+	RootFSReasonDuplicateStack = "duplicate stack"
+	RootFSReasonRelativePath   = "relative path"
+	RootFSReasonUnresolvable   = "path cannot be resolved"
+	RootFSReasonOutsideDir     = "path resolves outside of the preloaded rootfs dir"
+)
+
+// InvalidRootFSError is returned for the preloaded rootfs at Index of the
+// config the cell cannot run containers on, so that the rep fails to start
+// rather than its first container create using the stack failing. Index is
+// -1 when the preloaded rootfses are rejected as a whole.
+type InvalidRootFSError struct {
+	Index  int
+	Stack  string
+	Reason string
+}
+
+func (e InvalidRootFSError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("Invalid preloaded RootFS value: %s", e.Reason)
+	}
+	if e.Stack == "" {
+		return fmt.Sprintf("Invalid preloaded RootFS value at index %d: %s", e.Index, e.Reason)
+	}
+	return fmt.Sprintf("Invalid preloaded RootFS value for stack %q at index %d: %s", e.Stack, e.Index, e.Reason)
+}
+
 func (m *RootFSes) UnmarshalJSON(data []byte) error {
 	*m = make(RootFSes, 0)
+	if len(data) > MaxPreloadedRootFSes*(MaxPreloadedRootFSLength+3) {
+		return InvalidRootFSError{Index: -1, Reason: RootFSReasonTooLarge}
+	}
+
 	arr := []string{}
 	err := json.Unmarshal(data, &arr)
 	if err != nil {
 		return err
 	}
+	if len(arr) > MaxPreloadedRootFSes {
+		return InvalidRootFSError{Index: MaxPreloadedRootFSes, Reason: RootFSReasonTooMany}
+	}
+
+	stacks := make(map[string]struct{}, len(arr))
+	for i, s := range arr {
+		if len(s) > MaxPreloadedRootFSLength {
+			return InvalidRootFSError{Index: i, Reason: RootFSReasonTooLong}
+		}
 
-	for _, s := range arr {
 		parts := strings.SplitN(s, ":", 2)
 		if len(parts) != 2 {
-			return errors.New("Invalid preloaded RootFS value: not of the form 'stack-name:path'")
+			return InvalidRootFSError{Index: i, Reason: RootFSReasonMalformed}
 		}
 
 		if parts[0] == "" {
-			return errors.New("Invalid preloaded RootFS value: blank stack")
+			return InvalidRootFSError{Index: i, Reason: RootFSReasonBlankStack}
 		}
 
 		if parts[1] == "" {
-			return errors.New("Invalid preloaded RootFS value: blank path")
+			return InvalidRootFSError{Index: i, Stack: parts[0], Reason: RootFSReasonBlankPath}
 		}
 
-		*m = append(*m, RootFS{parts[0], parts[1]})
+		if _, ok := stacks[parts[0]]; ok {
+			return InvalidRootFSError{Index: i, Stack: parts[0], Reason: RootFSReasonDuplicateStack}
+		}
+		stacks[parts[0]] = struct{}{}
+
+		*m = append(*m, RootFS{parts[0], canonicalRootFSPath(parts[1])})
+	}
+
+	return nil
+}
+
+// canonicalRootFSPath cleans an absolute rootfs path. A relative path, or a
+// rootfs given as a URL, such as the oci:/// rootfses of Windows cells, is
+// kept as it is.
+func canonicalRootFSPath(path string) string {
+	if isRootFSURL(path) || !filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Clean(path)
+}
+
+// isRootFSURL reports whether path is a URL rather than a path. A scheme of a
+// single letter is the drive of a Windows path.
+func isRootFSURL(path string) bool {
+	u, err := url.Parse(path)
+	return err == nil && len(u.Scheme) > 1
+}
+
+// CheckContainedIn returns an InvalidRootFSError for the first rootfs whose
+// path is relative, does not resolve, or resolves outside of dir once its
+// symlinks are followed. Rootfses given as URLs are not checked.
+func (rootFSes RootFSes) CheckContainedIn(dir string) error {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
 	}
 
+	for i, rootFS := range rootFSes {
+		if isRootFSURL(rootFS.Path) {
+			continue
+		}
+		if !filepath.IsAbs(rootFS.Path) {
+			return InvalidRootFSError{Index: i, Stack: rootFS.Name, Reason: RootFSReasonRelativePath}
+		}
+
+		resolved, err := filepath.EvalSymlinks(rootFS.Path)
+		if err != nil {
+			return InvalidRootFSError{Index: i, Stack: rootFS.Name, Reason: RootFSReasonUnresolvable}
+		}
+		relative, err := filepath.Rel(root, resolved)
+		if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return InvalidRootFSError{Index: i, Stack: rootFS.Name, Reason: RootFSReasonOutsideDir}
+		}
+	}
 	return nil
 }
 
@@ -75,43 +181,217 @@ func (m RootFSes) MarshalJSON() (b []byte, err error) {
 }
 
 type RepConfig struct {
-	AdvertiseDomain           string                `json:"advertise_domain,omitempty"`
-	BBSAddress                string                `json:"bbs_address"`
-	BBSClientSessionCacheSize int                   `json:"bbs_client_session_cache_size,omitempty"`
-	BBSMaxIdleConnsPerHost    int                   `json:"bbs_max_idle_conns_per_host,omitempty"`
-	BBSCACertFile             string                `json:"bbs_ca_cert_file"`     // DEPRECATED. Kept around for dusts compatability
-	BBSClientCertFile         string                `json:"bbs_client_cert_file"` // DEPRECATED. Kept around for dusts compatability
-	BBSClientKeyFile          string                `json:"bbs_client_key_file"`  // DEPRECATED. Kept around for dusts compatability
-	CaCertFile                string                `json:"ca_cert_file"`
-	CellID                    string                `json:"cell_id"`
-	CellIndex                 int                   `json:"cell_index"`
-	CommunicationTimeout      durationjson.Duration `json:"communication_timeout,omitempty"`
-	EvacuationPollingInterval durationjson.Duration `json:"evacuation_polling_interval,omitempty"`
-	EvacuationTimeout         durationjson.Duration `json:"evacuation_timeout,omitempty"`
-	LayeringMode              string                `json:"layering_mode,omitempty"`
-	ListenAddr                string                `json:"listen_addr,omitempty"`
-	ListenAddrSecurable       string                `json:"listen_addr_securable,omitempty"`
-	LockRetryInterval         durationjson.Duration `json:"lock_retry_interval,omitempty"`
-	LockTTL                   durationjson.Duration `json:"lock_ttl,omitempty"`
-	OptionalPlacementTags     []string              `json:"optional_placement_tags"`
-	PlacementTags             []string              `json:"placement_tags"`
-	PollingInterval           durationjson.Duration `json:"polling_interval,omitempty"`
-	PreloadedRootFS           RootFSes              `json:"preloaded_root_fs"`
-	ServerCertFile            string                `json:"server_cert_file"` // DEPRECATED. Kept around for dusts compatability
-	ServerKeyFile             string                `json:"server_key_file"`  // DEPRECATED. Kept around for dusts compatability
-	CertFile                  string                `json:"cert_file"`
-	KeyFile                   string                `json:"key_file"`
-	SessionName               string                `json:"session_name,omitempty"`
-	SupportedProviders        []string              `json:"supported_providers"`
-	Zone                      string                `json:"zone"`
-	ReportInterval            durationjson.Duration `json:"report_interval,omitempty"`
-	LoggregatorConfig         loggingclient.Config  `json:"loggregator"`
+	AdminCaCertFile              string                  `json:"admin_ca_cert_file,omitempty"`
+	AdminCertFile                string                  `json:"admin_cert_file,omitempty"`
+	AdminKeyFile                 string                  `json:"admin_key_file,omitempty"`
+	AdvertiseDomain              string                  `json:"advertise_domain,omitempty"`
+	AllowedAppArmorProfiles      []string                `json:"allowed_apparmor_profiles,omitempty"`
+	AllowedCapabilities          []string                `json:"allowed_capabilities,omitempty"`
+	AllowedSeccompProfiles       []string                `json:"allowed_seccomp_profiles,omitempty"`
+	BBSAddress                   string                  `json:"bbs_address"`
+	BBSClientSessionCacheSize    int                     `json:"bbs_client_session_cache_size,omitempty"`
+	BBSMaxIdleConnsPerHost       int                     `json:"bbs_max_idle_conns_per_host,omitempty"`
+	BBSNotificationQueueDir      string                  `json:"bbs_notification_queue_dir,omitempty"`
+	BBSNotificationQueueMaxSize  int                     `json:"bbs_notification_queue_max_size,omitempty"`
+	BBSNotificationRetryInterval durationjson.Duration   `json:"bbs_notification_retry_interval,omitempty"`
+	BBSCACertFile                string                  `json:"bbs_ca_cert_file"`     // DEPRECATED. Kept around for dusts compatability
+	BBSClientCertFile            string                  `json:"bbs_client_cert_file"` // DEPRECATED. Kept around for dusts compatability
+	BBSClientKeyFile             string                  `json:"bbs_client_key_file"`  // DEPRECATED. Kept around for dusts compatability
+	CapacityReportRetentionHours int                     `json:"capacity_report_retention_hours,omitempty"`
+	CapacityReservationMaxTTL    durationjson.Duration   `json:"capacity_reservation_max_ttl,omitempty"`
+	CaCertFile                   string                  `json:"ca_cert_file"`
+	CellID                       string                  `json:"cell_id"`
+	CellIndex                    int                     `json:"cell_index"`
+	CgroupContainersParent       string                  `json:"cgroup_containers_parent,omitempty"`
+	CgroupRoot                   string                  `json:"cgroup_root,omitempty"`
+	ClockSkewTolerance           durationjson.Duration   `json:"clock_skew_tolerance,omitempty"`
+	ConsistencyCheckInterval     durationjson.Duration   `json:"consistency_check_interval,omitempty"`
+	ConsistencyRepair            bool                    `json:"consistency_repair,omitempty"`
+	ContainerEventsMaxContainers int                     `json:"container_events_max_containers,omitempty"`
+	ContainerEventsPerContainer  int                     `json:"container_events_per_container,omitempty"`
+	ContainerdAddress            string                  `json:"containerd_address,omitempty"`
+	ContainerdCtrPath            string                  `json:"containerd_ctr_path,omitempty"`
+	ContainerdMetricsMaxInFlight int                     `json:"containerd_metrics_max_in_flight,omitempty"`
+	ContainerdNamespace          string                  `json:"containerd_namespace,omitempty"`
+	CommunicationTimeout         durationjson.Duration   `json:"communication_timeout,omitempty"`
+	CordonFetchTimeout           durationjson.Duration   `json:"cordon_fetch_timeout,omitempty"`
+	CordonLocketKeyPrefix        string                  `json:"cordon_locket_key_prefix,omitempty"`
+	CordonPollInterval           durationjson.Duration   `json:"cordon_poll_interval,omitempty"`
+	CPUEntitlement               float64                 `json:"cpu_entitlement,omitempty"`
+	CrashLoopMaxCrashes          int                     `json:"crash_loop_max_crashes,omitempty"`
+	CrashLoopQuarantineDuration  durationjson.Duration   `json:"crash_loop_quarantine_duration,omitempty"`
+	CrashLoopWindow              durationjson.Duration   `json:"crash_loop_window,omitempty"`
+	DiskQuotaGrowthFile          string                  `json:"disk_quota_growth_file,omitempty"`
+	DownloadCacheStatsInterval   durationjson.Duration   `json:"download_cache_stats_interval,omitempty"`
+	EvacuationPollingInterval    durationjson.Duration   `json:"evacuation_polling_interval,omitempty"`
+	EvacuationTimeout            durationjson.Duration   `json:"evacuation_timeout,omitempty"`
+	ExecutorBackends             []ExecutorBackendConfig `json:"executor_backends,omitempty"`
+	FailureDomainScorePenalty    float64                 `json:"failure_domain_score_penalty,omitempty"`
+	FailureDomains               map[string][]string     `json:"failure_domains,omitempty"`
+	FeatureFlags                 map[string]bool         `json:"feature_flags,omitempty"`
+	HostPortPoolSize             int32                   `json:"host_port_pool_size,omitempty"`
+	HostPressureEnabled          bool                    `json:"host_pressure_enabled,omitempty"`
+	HostPressureInodePath        string                  `json:"host_pressure_inode_path,omitempty"`
+	HostPressureScoreWeight      float64                 `json:"host_pressure_score_weight,omitempty"`
+	ImageCacheMinFreeDiskMB      int64                   `json:"image_cache_min_free_disk_mb,omitempty"`
+	ImageCachePinnedVolumes      []string                `json:"image_cache_pinned_volumes,omitempty"`
+	ImageCachePruneInterval      durationjson.Duration   `json:"image_cache_prune_interval,omitempty"`
+	ImageSizeCheckCacheTTL       durationjson.Duration   `json:"image_size_check_cache_ttl,omitempty"`
+	ImageSizeCheckEnabled        bool                    `json:"image_size_check_enabled,omitempty"`
+	ImageSizeCheckTimeout        durationjson.Duration   `json:"image_size_check_timeout,omitempty"`
+	IaaSMetadataProvider         string                  `json:"iaas_metadata_provider,omitempty"`
+	IaaSMetadataTimeout          durationjson.Duration   `json:"iaas_metadata_timeout,omitempty"`
+	IaaSMetadataURL              string                  `json:"iaas_metadata_url,omitempty"`
+	InitStepPaths                []string                `json:"init_step_paths,omitempty"`
+	InsecureImageRegistries      []string                `json:"insecure_image_registries,omitempty"`
+	IOLimitsDevice               string                  `json:"io_limits_device,omitempty"`
+	IOLimitsPollInterval         durationjson.Duration   `json:"io_limits_poll_interval,omitempty"`
+	IsolationSegment             string                  `json:"isolation_segment,omitempty"`
+	KubernetesAPIURL             string                  `json:"kubernetes_api_url,omitempty"`
+	KubernetesCACertFile         string                  `json:"kubernetes_ca_cert_file,omitempty"`
+	KubernetesNodeName           string                  `json:"kubernetes_node_name,omitempty"`
+	KubernetesNodeSyncInterval   durationjson.Duration   `json:"kubernetes_node_sync_interval,omitempty"`
+	KubernetesTokenFile          string                  `json:"kubernetes_token_file,omitempty"`
+	LayeringMode                 string                  `json:"layering_mode,omitempty"`
+	LifecycleBundleCheckTimeout  durationjson.Duration   `json:"lifecycle_bundle_check_timeout,omitempty"`
+	LifecycleBundles             []LifecycleBundle       `json:"lifecycle_bundles,omitempty"`
+	LifecycleHintsNATSAddresses  []string                `json:"lifecycle_hints_nats_addresses,omitempty"`
+	LifecycleHintsNATSPassword   string                  `json:"lifecycle_hints_nats_password,omitempty"`
+	LifecycleHintsNATSUsername   string                  `json:"lifecycle_hints_nats_username,omitempty"`
+	LoadBalancerAWSAccessKeyID   string                  `json:"load_balancer_aws_access_key_id,omitempty"`
+	LoadBalancerAWSEndpoint      string                  `json:"load_balancer_aws_endpoint,omitempty"`
+	LoadBalancerAWSRegion        string                  `json:"load_balancer_aws_region,omitempty"`
+	LoadBalancerAWSSecretKey     string                  `json:"load_balancer_aws_secret_key,omitempty"`
+	LoadBalancerAWSSessionToken  string                  `json:"load_balancer_aws_session_token,omitempty"`
+	LoadBalancerAWSTargetGroups  []string                `json:"load_balancer_aws_target_groups,omitempty"`
+	LoadBalancerDrainTimeout     durationjson.Duration   `json:"load_balancer_drain_timeout,omitempty"`
+	LoadBalancerWebhookURL       string                  `json:"load_balancer_webhook_url,omitempty"`
+	MaintenanceMode              bool                    `json:"maintenance_mode,omitempty"`
+	MaintenanceScorePenalty      float64                 `json:"maintenance_score_penalty,omitempty"`
+	MaintenanceWindowLeadTime    durationjson.Duration   `json:"maintenance_window_lead_time,omitempty"`
+	MaintenanceWindows           []MaintenanceWindow     `json:"maintenance_windows,omitempty"`
+	MaxContainerDiskMB           int32                   `json:"max_container_disk_mb,omitempty"`
+	MaxContainerMemoryMB         int32                   `json:"max_container_memory_mb,omitempty"`
+	MaxRequestBodyBytes          int64                   `json:"max_request_body_bytes,omitempty"`
+	MaxWorkBatchSize             int                     `json:"max_work_batch_size,omitempty"`
+	ListenAddr                   string                  `json:"listen_addr,omitempty"`
+	ListenAddrAdmin              string                  `json:"listen_addr_admin,omitempty"`
+	ListenAddrGRPC               string                  `json:"listen_addr_grpc,omitempty"`
+	ListenAddrSecurable          string                  `json:"listen_addr_securable,omitempty"`
+	LockMinRetryInterval         durationjson.Duration   `json:"lock_min_retry_interval,omitempty"`
+	LockRetryInterval            durationjson.Duration   `json:"lock_retry_interval,omitempty"`
+	LockSlowRenewalThreshold     durationjson.Duration   `json:"lock_slow_renewal_threshold,omitempty"`
+	LockTTL                      durationjson.Duration   `json:"lock_ttl,omitempty"`
+	LogRateLimitBytesPerSecond   int64                   `json:"log_rate_limit_bytes_per_second,omitempty"`
+	OptionalPlacementTags        []string                `json:"optional_placement_tags"`
+	OSFamily                     string                  `json:"os_family,omitempty"`
+	PerformMaxInFlight           int                     `json:"perform_max_in_flight,omitempty"`
+	PerformMaxQueuedPerCaller    int                     `json:"perform_max_queued_per_caller,omitempty"`
+	PlacementPolicyPath          string                  `json:"placement_policy_path,omitempty"`
+	PlacementTags                []string                `json:"placement_tags"`
+	PollingInterval              durationjson.Duration   `json:"polling_interval,omitempty"`
+	PollingMaxInterval           durationjson.Duration   `json:"polling_max_interval,omitempty"`
+	PollingMinInterval           durationjson.Duration   `json:"polling_min_interval,omitempty"`
+	PreloadedRootFS              RootFSes                `json:"preloaded_root_fs"`
+	PreloadedRootFSDir           string                  `json:"preloaded_root_fs_dir,omitempty"`
+	PresenceOwnerFile            string                  `json:"presence_owner_file,omitempty"`
+	PressureEvictionCooldown     durationjson.Duration   `json:"pressure_eviction_cooldown,omitempty"`
+	PressureEvictionDiskPath     string                  `json:"pressure_eviction_disk_path,omitempty"`
+	PressureEvictionInterval     durationjson.Duration   `json:"pressure_eviction_interval,omitempty"`
+	PressureMinAvailableDiskMB   int64                   `json:"pressure_min_available_disk_mb,omitempty"`
+	PressureMinAvailableMemoryMB int64                   `json:"pressure_min_available_memory_mb,omitempty"`
+	ProxyReadinessCheckDir       string                  `json:"proxy_readiness_check_dir,omitempty"`
+	ProxyReadinessCheckInterval  durationjson.Duration   `json:"proxy_readiness_check_interval,omitempty"`
+	ProxyReadinessCheckPath      string                  `json:"proxy_readiness_check_path,omitempty"`
+	ProxyReadinessCheckPort      uint16                  `json:"proxy_readiness_check_port,omitempty"`
+	ProxyReadinessTimeout        durationjson.Duration   `json:"proxy_readiness_timeout,omitempty"`
+	RecentLRPRetention           durationjson.Duration   `json:"recent_lrp_retention,omitempty"`
+	RecentLRPScoreBonus          float64                 `json:"recent_lrp_score_bonus,omitempty"`
+	RequireImageDigests          bool                    `json:"require_image_digests,omitempty"`
+	RequestRecordingMaxBytes     int64                   `json:"request_recording_max_bytes,omitempty"`
+	RequestRecordingPath         string                  `json:"request_recording_path,omitempty"`
+	RootFSImageStores            map[string]string       `json:"root_fs_image_stores,omitempty"`
+	SaturationMetricsInterval    durationjson.Duration   `json:"saturation_metrics_interval,omitempty"`
+	SelfTestRootFS               string                  `json:"self_test_root_fs,omitempty"`
+	SelfTestTimeout              durationjson.Duration   `json:"self_test_timeout,omitempty"`
+	ServerCertFile               string                  `json:"server_cert_file"` // DEPRECATED. Kept around for dusts compatability
+	ServerKeyFile                string                  `json:"server_key_file"`  // DEPRECATED. Kept around for dusts compatability
+	CertFile                     string                  `json:"cert_file"`
+	KeyFile                      string                  `json:"key_file"`
+	SessionName                  string                  `json:"session_name,omitempty"`
+	StackContainerLimits         map[string]int          `json:"stack_container_limits,omitempty"`
+	SupervisorMaxBackoff         durationjson.Duration   `json:"supervisor_max_backoff,omitempty"`
+	SupervisorMinBackoff         durationjson.Duration   `json:"supervisor_min_backoff,omitempty"`
+	SupportedProviders           []string                `json:"supported_providers"`
+	TaskCompletionBatchSize      int                     `json:"task_completion_batch_size,omitempty"`
+	TaskCompletionFlushInterval  durationjson.Duration   `json:"task_completion_flush_interval,omitempty"`
+	TaskCompletionMaxAttempts    int                     `json:"task_completion_max_attempts,omitempty"`
+	TenantMaxContainers          int                     `json:"tenant_max_containers,omitempty"`
+	TenantMaxDiskMB              int32                   `json:"tenant_max_disk_mb,omitempty"`
+	TenantMaxMemoryMB            int32                   `json:"tenant_max_memory_mb,omitempty"`
+	TenantMetricsInterval        durationjson.Duration   `json:"tenant_metrics_interval,omitempty"`
+	UsageForecastHorizon         durationjson.Duration   `json:"usage_forecast_horizon,omitempty"`
+	UsageForecastInterval        durationjson.Duration   `json:"usage_forecast_interval,omitempty"`
+	UsageForecastScoreWeight     float64                 `json:"usage_forecast_score_weight,omitempty"`
+	WarmStandbyHandoffSocket     string                  `json:"warm_standby_handoff_socket,omitempty"`
+	WarmStandbyInventoryFile     string                  `json:"warm_standby_inventory_file,omitempty"`
+	WarmStandbyTimeout           durationjson.Duration   `json:"warm_standby_timeout,omitempty"`
+	WindowsImageOverheadDiskMB   int32                   `json:"windows_image_overhead_disk_mb,omitempty"`
+	WindowsImageOverheadMemoryMB int32                   `json:"windows_image_overhead_memory_mb,omitempty"`
+	XFSIOPath                    string                  `json:"xfs_io_path,omitempty"`
+	XFSQuotaPath                 string                  `json:"xfs_quota_path,omitempty"`
+	Zone                         string                  `json:"zone"`
+	ReportInterval               durationjson.Duration   `json:"report_interval,omitempty"`
+	LoggregatorConfig            loggingclient.Config    `json:"loggregator"`
 	debugserver.DebugServerConfig
 	executorinit.ExecutorConfig
 	lagerflags.LagerConfig
 	locket.ClientLocketConfig
 }
 
+// ExecutorBackendConfig configures an additional executor the cell schedules
+// work onto, next to the one configured at the top level of RepConfig.
+type ExecutorBackendConfig struct {
+	Name               string   `json:"name"`
+	PreloadedRootFS    RootFSes `json:"preloaded_root_fs"`
+	SupportedProviders []string `json:"supported_providers"`
+	executorinit.ExecutorConfig
+}
+
+// MaintenanceWindow schedules a window of Duration starting at Start,
+// given in RFC 3339, in which the cell is planned to be restarted.
+type MaintenanceWindow struct {
+	Start    time.Time             `json:"start"`
+	Duration durationjson.Duration `json:"duration"`
+}
+
+// LifecycleBundle configures a lifecycle the cell can run work with. The
+// location is a directory on the cell or the http(s) URL of the bundle, and
+// the binaries are the executables a directory must contain.
+type LifecycleBundle struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Location string   `json:"location"`
+	Binaries []string `json:"binaries,omitempty"`
+}
+
+// AdminTLSFiles returns the certificate, key and CA files used by the admin
+// listener. Any of them that is not configured falls back to the one used by
+// the rep's other listeners.
+func (c RepConfig) AdminTLSFiles() (certFile, keyFile, caCertFile string) {
+	certFile, keyFile, caCertFile = c.CertFile, c.KeyFile, c.CaCertFile
+	if c.AdminCertFile != "" {
+		certFile = c.AdminCertFile
+	}
+	if c.AdminKeyFile != "" {
+		keyFile = c.AdminKeyFile
+	}
+	if c.AdminCaCertFile != "" {
+		caCertFile = c.AdminCaCertFile
+	}
+	return certFile, keyFile, caCertFile
+}
+
 func NewRepConfig(configPath string) (RepConfig, error) {
 	repConfig := RepConfig{}
 	configFile, err := os.Open(configPath)
@@ -24,15 +24,46 @@ var _ = Describe("RepConfig", func() {
 		configData = `{
 			"proxy_memory_allocation_mb": 6,
 			"proxy_enable_http2": true,
+			"admin_ca_cert_file": "/tmp/admin_ca_cert",
+			"admin_cert_file": "/tmp/admin_cert",
+			"admin_key_file": "/tmp/admin_key",
 			"advertise_domain": "test-domain",
+			"allowed_apparmor_profiles": ["diego-privileged"],
+			"allowed_capabilities": ["CAP_NET_ADMIN", "CAP_SYS_PTRACE"],
+			"allowed_seccomp_profiles": ["unconfined"],
 			"bbs_address": "1.1.1.1:9091",
 			"bbs_client_session_cache_size": 100,
 			"bbs_max_idle_conns_per_host": 10,
+			"bbs_notification_queue_dir": "/var/vcap/data/rep/bbs_notifications",
+			"bbs_notification_queue_max_size": 1000,
+			"bbs_notification_retry_interval": "10s",
 			"ca_cert_file": "/tmp/ca_cert",
+			"capacity_report_retention_hours": 48,
+			"capacity_reservation_max_ttl": "30m",
 			"cache_path": "/tmp/cache",
 			"cell_id" : "cell_z1/10",
 			"cell_index": 10,
+			"cgroup_containers_parent": "garden",
+			"cgroup_root": "/sys/fs/cgroup",
+			"clock_skew_tolerance": "2s",
 			"communication_timeout": "11s",
+			"cordon_fetch_timeout": "3s",
+			"cordon_locket_key_prefix": "cordon-",
+			"cordon_poll_interval": "15s",
+			"cpu_entitlement": 7.5,
+			"crash_loop_max_crashes": 5,
+			"crash_loop_quarantine_duration": "1h",
+			"crash_loop_window": "10m",
+			"disk_quota_growth_file": "/var/vcap/data/rep/disk_quota_growth.json",
+			"download_cache_stats_interval": "5m",
+			"containerd_address": "/run/containerd/containerd.sock",
+			"containerd_ctr_path": "/var/vcap/packages/containerd/bin/ctr",
+			"containerd_metrics_max_in_flight": 8,
+			"containerd_namespace": "garden",
+			"consistency_check_interval": "5m",
+			"consistency_repair": true,
+			"container_events_max_containers": 500,
+			"container_events_per_container": 20,
 			"container_inode_limit": 1000,
 			"container_max_cpu_shares": 4,
 			"container_metrics_report_interval": "16s",
@@ -47,12 +78,22 @@ var _ = Describe("RepConfig", func() {
 			"enable_legacy_api_endpoints": true,
 			"evacuation_polling_interval" : "13s",
 			"evacuation_timeout" : "12s",
+			"executor_backends": [{
+				"name": "windows",
+				"preloaded_root_fs": ["windows:/tmp/windows"],
+				"supported_providers": ["docker"],
+				"garden_addr": "100.0.0.2",
+				"memory_mb": "2000"
+			}],
 			"enable_container_proxy": true,
 			"container_proxy_ads_addresses": ["10.0.0.2:15010", "10.0.0.3:15010"],
 			"enable_unproxied_port_mappings": true,
 			"envoy_config_refresh_delay": "1s",
 			"envoy_config_reload_duration": "5s",
 			"envoy_drain_timeout": "15m",
+			"failure_domain_score_penalty": 0.2,
+			"failure_domains": {"rack-r1": ["cell-1", "cell-2"], "array-a": ["cell-2", "cell-3"]},
+			"feature_flags": {"local_restart": true, "proxy_overhead": false},
 			"garden_addr": "100.0.0.1",
 			"garden_healthcheck_command_retry_pause": "15s",
 			"garden_healthcheck_emission_interval": "13s",
@@ -69,12 +110,65 @@ var _ = Describe("RepConfig", func() {
 			"healthcheck_work_pool_size": 10,
 			"healthy_monitoring_interval": "5s",
 			"healthy_monitoring_interval": "5s",
+			"host_port_pool_size": 5000,
+			"host_pressure_enabled": true,
+			"host_pressure_inode_path": "/var/vcap/data",
+			"host_pressure_score_weight": 0.25,
+			"image_cache_min_free_disk_mb": 10240,
+			"image_cache_pinned_volumes": ["cflinuxfs3-volume"],
+			"image_cache_prune_interval": "1h",
+			"image_size_check_cache_ttl": "15m",
+			"image_size_check_enabled": true,
+			"image_size_check_timeout": "10s",
+			"iaas_metadata_provider": "aws",
+			"iaas_metadata_timeout": "3s",
+			"iaas_metadata_url": "http://127.0.0.1:8000",
+			"init_step_paths": ["/home/vcap/app/bin/"],
+			"insecure_image_registries": ["registry.service.cf.internal:8080"],
+			"io_limits_device": "8:0",
+			"io_limits_poll_interval": "2s",
+			"isolation_segment": "payments",
+			"kubernetes_api_url": "https://10.0.0.1:6443",
+			"kubernetes_ca_cert_file": "/var/vcap/jobs/rep/config/certs/kubernetes-ca.crt",
+			"kubernetes_node_name": "node-1",
+			"kubernetes_node_sync_interval": "15s",
+			"kubernetes_token_file": "/var/vcap/jobs/rep/config/kubernetes-token",
 			"layering_mode": "single-layer",
+			"lifecycle_bundle_check_timeout": "3s",
+			"lifecycle_bundles": [{
+				"name": "buildpack",
+				"version": "1.2.3",
+				"location": "/var/vcap/packages/buildpack_app_lifecycle",
+				"binaries": ["healthcheck", "launcher"]
+			}],
+			"lifecycle_hints_nats_addresses": ["nats://127.0.0.1:4222"],
+			"lifecycle_hints_nats_password": "nats-password",
+			"lifecycle_hints_nats_username": "nats",
+			"load_balancer_aws_access_key_id": "AKIDEXAMPLE",
+			"load_balancer_aws_endpoint": "https://elb.example.com",
+			"load_balancer_aws_region": "us-east-1",
+			"load_balancer_aws_secret_key": "aws-secret",
+			"load_balancer_aws_session_token": "aws-session-token",
+			"load_balancer_aws_target_groups": ["arn:tg-1", "arn:tg-2"],
+			"load_balancer_drain_timeout": "20s",
+			"load_balancer_webhook_url": "http://127.0.0.1:9000/deregister",
+			"maintenance_mode": true,
+			"maintenance_score_penalty": 0.5,
+			"maintenance_window_lead_time": "2h",
+			"maintenance_windows": [{"start": "2026-11-01T02:00:00Z", "duration": "1h"}],
+			"max_container_disk_mb": 8192,
+			"max_container_memory_mb": 4096,
+			"max_request_body_bytes": 1048576,
+			"max_work_batch_size": 100,
 			"listen_addr": "0.0.0.0:8080",
 			"listen_addr_admin": "0.0.0.1:8081",
+			"listen_addr_grpc": "0.0.0.0:8082",
 			"listen_addr_securable": "0.0.0.0:8081",
+			"lock_min_retry_interval": "1s",
 			"lock_retry_interval": "5s",
+			"lock_slow_renewal_threshold": "3s",
 			"lock_ttl": "5s",
+			"log_rate_limit_bytes_per_second": 16384,
 			"cell_registrations_locket_enabled": true,
 			"locket_address": "0.0.0.0:909090909",
 			"locket_ca_cert_file": "locket-ca-cert",
@@ -100,23 +194,70 @@ var _ = Describe("RepConfig", func() {
 			"memory_mb": "1000",
 			"metrics_work_pool_size": 5,
 			"optional_placement_tags": ["otag1", "otag2"],
+			"os_family": "windows",
+			"perform_max_in_flight": 4,
+			"perform_max_queued_per_caller": 16,
 			"path_to_ca_certs_for_downloads": "/tmp/ca-certs",
+			"placement_policy_path": "/var/vcap/jobs/rep/config/placement-policy.json",
 			"placement_tags": ["tag1", "tag2"],
 			"polling_interval": "10s",
+			"polling_max_interval": "1m",
+			"polling_min_interval": "5s",
 			"post_setup_hook": "post_setup_hook",
 			"post_setup_user": "post_setup_user",
 			"preloaded_root_fs": ["test:value", "test2:value2"],
+			"preloaded_root_fs_dir": "/var/vcap/packages",
+			"presence_owner_file": "/tmp/presence_owner",
+			"pressure_eviction_cooldown": "30s",
+			"pressure_eviction_disk_path": "/var/vcap/data",
+			"pressure_eviction_interval": "5s",
+			"pressure_min_available_disk_mb": 1024,
+			"pressure_min_available_memory_mb": 512,
+			"proxy_readiness_check_dir": "/var/vcap/data/proxy-ready",
+			"proxy_readiness_check_interval": "250ms",
+			"proxy_readiness_check_path": "/ready",
+			"proxy_readiness_check_port": 61003,
+			"proxy_readiness_timeout": "30s",
+			"recent_lrp_retention": "10m",
+			"recent_lrp_score_bonus": 0.05,
+			"require_image_digests": true,
+			"request_recording_max_bytes": 1048576,
+			"request_recording_path": "/var/vcap/data/rep/requests.jsonl",
+			"root_fs_image_stores": {"docker": "/var/vcap/data/grootfs/store/unprivileged"},
+			"saturation_metrics_interval": "30s",
+			"self_test_root_fs": "cflinuxfs3",
+			"self_test_timeout": "45s",
 			"read_work_pool_size": 15,
 			"reserved_expiration_time": "10s",
 			"cert_file": "/tmp/server_cert",
 			"key_file": "/tmp/server_key",
 			"session_name": "test",
+			"stack_container_limits": {"windows2016": 10},
 			"skip_cert_verify": true,
+			"supervisor_max_backoff": "2m",
+			"supervisor_min_backoff": "2s",
 			"supported_providers": ["provider1", "provider2"],
+			"task_completion_batch_size": 50,
+			"task_completion_flush_interval": "2s",
+			"task_completion_max_attempts": 3,
+			"tenant_max_containers": 50,
+			"tenant_max_disk_mb": 102400,
+			"tenant_max_memory_mb": 16384,
+			"tenant_metrics_interval": "1m",
+			"usage_forecast_horizon": "5m",
+			"usage_forecast_interval": "30s",
+			"usage_forecast_score_weight": 0.5,
+			"warm_standby_handoff_socket": "/var/vcap/data/rep/handoff.sock",
+			"warm_standby_inventory_file": "/var/vcap/data/rep/inventory.json",
+			"warm_standby_timeout": "10m",
 			"temp_dir": "/tmp/test",
 			"trusted_system_certificates_path": "/tmp/trusted",
 			"unhealthy_monitoring_interval": "10s",
 			"volman_driver_paths": "/tmp/volman1:/tmp/volman2",
+			"windows_image_overhead_disk_mb": 2048,
+			"windows_image_overhead_memory_mb": 128,
+			"xfs_io_path": "/sbin/xfs_io",
+			"xfs_quota_path": "/sbin/xfs_quota",
 			"zone": "test-zone",
 			"report_interval": "2m"
 		}`
@@ -146,25 +287,91 @@ var _ = Describe("RepConfig", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(repConfig).To(test_helpers.DeepEqual(config.RepConfig{
-			AdvertiseDomain:           "test-domain",
-			BBSAddress:                "1.1.1.1:9091",
-			BBSClientSessionCacheSize: 100,
-			BBSMaxIdleConnsPerHost:    10,
-			CaCertFile:                "/tmp/ca_cert",
-			CellID:                    "cell_z1/10",
-			CellIndex:                 10,
+			AdminCaCertFile:              "/tmp/admin_ca_cert",
+			AdminCertFile:                "/tmp/admin_cert",
+			AdminKeyFile:                 "/tmp/admin_key",
+			AdvertiseDomain:              "test-domain",
+			AllowedAppArmorProfiles:      []string{"diego-privileged"},
+			AllowedCapabilities:          []string{"CAP_NET_ADMIN", "CAP_SYS_PTRACE"},
+			AllowedSeccompProfiles:       []string{"unconfined"},
+			BBSAddress:                   "1.1.1.1:9091",
+			BBSClientSessionCacheSize:    100,
+			BBSMaxIdleConnsPerHost:       10,
+			BBSNotificationQueueDir:      "/var/vcap/data/rep/bbs_notifications",
+			BBSNotificationQueueMaxSize:  1000,
+			BBSNotificationRetryInterval: durationjson.Duration(10 * time.Second),
+			CaCertFile:                   "/tmp/ca_cert",
+			CapacityReportRetentionHours: 48,
+			CapacityReservationMaxTTL:    durationjson.Duration(30 * time.Minute),
+			CellID:                       "cell_z1/10",
+			CellIndex:                    10,
+			CgroupContainersParent:       "garden",
+			CgroupRoot:                   "/sys/fs/cgroup",
+			ClockSkewTolerance:           durationjson.Duration(2 * time.Second),
 			ClientLocketConfig: locket.ClientLocketConfig{
 				LocketAddress:        "0.0.0.0:909090909",
 				LocketCACertFile:     "locket-ca-cert",
 				LocketClientCertFile: "locket-client-cert",
 				LocketClientKeyFile:  "locket-client-key",
 			},
-			CommunicationTimeout: durationjson.Duration(11 * time.Second),
+			CommunicationTimeout:         durationjson.Duration(11 * time.Second),
+			CordonFetchTimeout:           durationjson.Duration(3 * time.Second),
+			CordonLocketKeyPrefix:        "cordon-",
+			CordonPollInterval:           durationjson.Duration(15 * time.Second),
+			CPUEntitlement:               7.5,
+			CrashLoopMaxCrashes:          5,
+			CrashLoopQuarantineDuration:  durationjson.Duration(time.Hour),
+			CrashLoopWindow:              durationjson.Duration(10 * time.Minute),
+			DiskQuotaGrowthFile:          "/var/vcap/data/rep/disk_quota_growth.json",
+			DownloadCacheStatsInterval:   durationjson.Duration(5 * time.Minute),
+			ContainerdAddress:            "/run/containerd/containerd.sock",
+			ContainerdCtrPath:            "/var/vcap/packages/containerd/bin/ctr",
+			ContainerdMetricsMaxInFlight: 8,
+			ContainerdNamespace:          "garden",
+			ConsistencyCheckInterval:     durationjson.Duration(5 * time.Minute),
+			ConsistencyRepair:            true,
+			ContainerEventsMaxContainers: 500,
+			ContainerEventsPerContainer:  20,
 			DebugServerConfig: debugserver.DebugServerConfig{
 				DebugAddress: "5.5.5.5:9090",
 			},
 			EvacuationPollingInterval: durationjson.Duration(13 * time.Second),
 			EvacuationTimeout:         durationjson.Duration(12 * time.Second),
+			ExecutorBackends: []config.ExecutorBackendConfig{{
+				Name:               "windows",
+				PreloadedRootFS:    []config.RootFS{{"windows", "/tmp/windows"}},
+				SupportedProviders: []string{"docker"},
+				ExecutorConfig: executorinit.ExecutorConfig{
+					GardenAddr: "100.0.0.2",
+					MemoryMB:   "2000",
+				},
+			}},
+			FailureDomainScorePenalty:  0.2,
+			FailureDomains:             map[string][]string{"rack-r1": {"cell-1", "cell-2"}, "array-a": {"cell-2", "cell-3"}},
+			FeatureFlags:               map[string]bool{"local_restart": true, "proxy_overhead": false},
+			HostPortPoolSize:           5000,
+			HostPressureEnabled:        true,
+			HostPressureInodePath:      "/var/vcap/data",
+			HostPressureScoreWeight:    0.25,
+			ImageCacheMinFreeDiskMB:    10240,
+			ImageCachePinnedVolumes:    []string{"cflinuxfs3-volume"},
+			ImageCachePruneInterval:    durationjson.Duration(time.Hour),
+			ImageSizeCheckCacheTTL:     durationjson.Duration(15 * time.Minute),
+			ImageSizeCheckEnabled:      true,
+			ImageSizeCheckTimeout:      durationjson.Duration(10 * time.Second),
+			IaaSMetadataProvider:       "aws",
+			IaaSMetadataTimeout:        durationjson.Duration(3 * time.Second),
+			IaaSMetadataURL:            "http://127.0.0.1:8000",
+			InitStepPaths:              []string{"/home/vcap/app/bin/"},
+			InsecureImageRegistries:    []string{"registry.service.cf.internal:8080"},
+			IOLimitsDevice:             "8:0",
+			IOLimitsPollInterval:       durationjson.Duration(2 * time.Second),
+			IsolationSegment:           "payments",
+			KubernetesAPIURL:           "https://10.0.0.1:6443",
+			KubernetesCACertFile:       "/var/vcap/jobs/rep/config/certs/kubernetes-ca.crt",
+			KubernetesNodeName:         "node-1",
+			KubernetesNodeSyncInterval: durationjson.Duration(15 * time.Second),
+			KubernetesTokenFile:        "/var/vcap/jobs/rep/config/kubernetes-token",
 			ExecutorConfig: executorinit.ExecutorConfig{
 				ProxyMemoryAllocationMB:            6,
 				ProxyEnableHttp2:                   true,
@@ -219,21 +426,94 @@ var _ = Describe("RepConfig", func() {
 			LagerConfig: lagerflags.LagerConfig{
 				LogLevel: lagerflags.DEBUG,
 			},
-			LayeringMode:          "single-layer",
-			ListenAddr:            "0.0.0.0:8080",
-			ListenAddrSecurable:   "0.0.0.0:8081",
-			LockRetryInterval:     durationjson.Duration(5 * time.Second),
-			LockTTL:               durationjson.Duration(5 * time.Second),
-			OptionalPlacementTags: []string{"otag1", "otag2"},
-			PlacementTags:         []string{"tag1", "tag2"},
-			PollingInterval:       durationjson.Duration(10 * time.Second),
-			PreloadedRootFS:       []config.RootFS{{"test", "value"}, {"test2", "value2"}},
-			CertFile:              "/tmp/server_cert",
-			KeyFile:               "/tmp/server_key",
-			SessionName:           "test",
-			SupportedProviders:    []string{"provider1", "provider2"},
-			Zone:                  "test-zone",
-			ReportInterval:        durationjson.Duration(2 * time.Minute),
+			LayeringMode:                 "single-layer",
+			LifecycleBundleCheckTimeout:  durationjson.Duration(3 * time.Second),
+			LifecycleBundles:             []config.LifecycleBundle{{Name: "buildpack", Version: "1.2.3", Location: "/var/vcap/packages/buildpack_app_lifecycle", Binaries: []string{"healthcheck", "launcher"}}},
+			LifecycleHintsNATSAddresses:  []string{"nats://127.0.0.1:4222"},
+			LifecycleHintsNATSPassword:   "nats-password",
+			LifecycleHintsNATSUsername:   "nats",
+			LoadBalancerAWSAccessKeyID:   "AKIDEXAMPLE",
+			LoadBalancerAWSEndpoint:      "https://elb.example.com",
+			LoadBalancerAWSRegion:        "us-east-1",
+			LoadBalancerAWSSecretKey:     "aws-secret",
+			LoadBalancerAWSSessionToken:  "aws-session-token",
+			LoadBalancerAWSTargetGroups:  []string{"arn:tg-1", "arn:tg-2"},
+			LoadBalancerDrainTimeout:     durationjson.Duration(20 * time.Second),
+			LoadBalancerWebhookURL:       "http://127.0.0.1:9000/deregister",
+			MaintenanceMode:              true,
+			MaintenanceScorePenalty:      0.5,
+			MaintenanceWindowLeadTime:    durationjson.Duration(2 * time.Hour),
+			MaintenanceWindows:           []config.MaintenanceWindow{{Start: time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC), Duration: durationjson.Duration(time.Hour)}},
+			MaxContainerDiskMB:           8192,
+			MaxContainerMemoryMB:         4096,
+			MaxRequestBodyBytes:          1048576,
+			MaxWorkBatchSize:             100,
+			ListenAddr:                   "0.0.0.0:8080",
+			ListenAddrAdmin:              "0.0.0.1:8081",
+			ListenAddrGRPC:               "0.0.0.0:8082",
+			ListenAddrSecurable:          "0.0.0.0:8081",
+			LockMinRetryInterval:         durationjson.Duration(1 * time.Second),
+			LockRetryInterval:            durationjson.Duration(5 * time.Second),
+			LockSlowRenewalThreshold:     durationjson.Duration(3 * time.Second),
+			LockTTL:                      durationjson.Duration(5 * time.Second),
+			LogRateLimitBytesPerSecond:   16384,
+			OptionalPlacementTags:        []string{"otag1", "otag2"},
+			OSFamily:                     "windows",
+			PerformMaxInFlight:           4,
+			PerformMaxQueuedPerCaller:    16,
+			PlacementPolicyPath:          "/var/vcap/jobs/rep/config/placement-policy.json",
+			PlacementTags:                []string{"tag1", "tag2"},
+			PollingInterval:              durationjson.Duration(10 * time.Second),
+			PollingMaxInterval:           durationjson.Duration(time.Minute),
+			PollingMinInterval:           durationjson.Duration(5 * time.Second),
+			PreloadedRootFS:              []config.RootFS{{"test", "value"}, {"test2", "value2"}},
+			PreloadedRootFSDir:           "/var/vcap/packages",
+			PresenceOwnerFile:            "/tmp/presence_owner",
+			PressureEvictionCooldown:     durationjson.Duration(30 * time.Second),
+			PressureEvictionDiskPath:     "/var/vcap/data",
+			PressureEvictionInterval:     durationjson.Duration(5 * time.Second),
+			PressureMinAvailableDiskMB:   1024,
+			PressureMinAvailableMemoryMB: 512,
+			ProxyReadinessCheckDir:       "/var/vcap/data/proxy-ready",
+			ProxyReadinessCheckInterval:  durationjson.Duration(250 * time.Millisecond),
+			ProxyReadinessCheckPath:      "/ready",
+			ProxyReadinessCheckPort:      61003,
+			ProxyReadinessTimeout:        durationjson.Duration(30 * time.Second),
+			RecentLRPRetention:           durationjson.Duration(10 * time.Minute),
+			RecentLRPScoreBonus:          0.05,
+			RequireImageDigests:          true,
+			RequestRecordingMaxBytes:     1048576,
+			RequestRecordingPath:         "/var/vcap/data/rep/requests.jsonl",
+			RootFSImageStores:            map[string]string{"docker": "/var/vcap/data/grootfs/store/unprivileged"},
+			SaturationMetricsInterval:    durationjson.Duration(30 * time.Second),
+			SelfTestRootFS:               "cflinuxfs3",
+			SelfTestTimeout:              durationjson.Duration(45 * time.Second),
+			CertFile:                     "/tmp/server_cert",
+			KeyFile:                      "/tmp/server_key",
+			SessionName:                  "test",
+			StackContainerLimits:         map[string]int{"windows2016": 10},
+			SupervisorMaxBackoff:         durationjson.Duration(2 * time.Minute),
+			SupervisorMinBackoff:         durationjson.Duration(2 * time.Second),
+			SupportedProviders:           []string{"provider1", "provider2"},
+			TaskCompletionBatchSize:      50,
+			TaskCompletionFlushInterval:  durationjson.Duration(2 * time.Second),
+			TaskCompletionMaxAttempts:    3,
+			TenantMaxContainers:          50,
+			TenantMaxDiskMB:              102400,
+			TenantMaxMemoryMB:            16384,
+			TenantMetricsInterval:        durationjson.Duration(time.Minute),
+			UsageForecastHorizon:         durationjson.Duration(5 * time.Minute),
+			UsageForecastInterval:        durationjson.Duration(30 * time.Second),
+			UsageForecastScoreWeight:     0.5,
+			WarmStandbyHandoffSocket:     "/var/vcap/data/rep/handoff.sock",
+			WarmStandbyInventoryFile:     "/var/vcap/data/rep/inventory.json",
+			WarmStandbyTimeout:           durationjson.Duration(10 * time.Minute),
+			WindowsImageOverheadDiskMB:   2048,
+			WindowsImageOverheadMemoryMB: 128,
+			XFSIOPath:                    "/sbin/xfs_io",
+			XFSQuotaPath:                 "/sbin/xfs_quota",
+			Zone:                         "test-zone",
+			ReportInterval:               durationjson.Duration(2 * time.Minute),
 			LoggregatorConfig: loggingclient.Config{
 				UseV2API:      true,
 				APIPort:       1234,
@@ -249,6 +529,40 @@ var _ = Describe("RepConfig", func() {
 		}))
 	})
 
+	Describe("AdminTLSFiles", func() {
+		var repConfig config.RepConfig
+
+		BeforeEach(func() {
+			repConfig = config.RepConfig{
+				CertFile:   "cert",
+				KeyFile:    "key",
+				CaCertFile: "ca",
+			}
+		})
+
+		It("falls back to the rep's certificates", func() {
+			certFile, keyFile, caCertFile := repConfig.AdminTLSFiles()
+			Expect(certFile).To(Equal("cert"))
+			Expect(keyFile).To(Equal("key"))
+			Expect(caCertFile).To(Equal("ca"))
+		})
+
+		Context("when admin certificates are configured", func() {
+			BeforeEach(func() {
+				repConfig.AdminCertFile = "admin-cert"
+				repConfig.AdminKeyFile = "admin-key"
+				repConfig.AdminCaCertFile = "admin-ca"
+			})
+
+			It("uses the admin certificates", func() {
+				certFile, keyFile, caCertFile := repConfig.AdminTLSFiles()
+				Expect(certFile).To(Equal("admin-cert"))
+				Expect(keyFile).To(Equal("admin-key"))
+				Expect(caCertFile).To(Equal("admin-ca"))
+			})
+		})
+	})
+
 	Context("when the file does not exist", func() {
 		It("returns an error", func() {
 			_, err := config.NewRepConfig("foobar")
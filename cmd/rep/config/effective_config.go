@@ -0,0 +1,183 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeyFragments are matched against the JSON keys of the
+// configuration. The value of any matching key is redacted before the
+// configuration is exposed.
+var sensitiveKeyFragments = []string{"key", "password", "secret", "token", "credential"}
+
+// Redacted returns the configuration as a JSON document with the values of
+// sensitive fields (private keys, passwords, tokens) replaced by
+// RedactedValue.
+func (c RepConfig) Redacted() (map[string]interface{}, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	err = json.Unmarshal(payload, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	redact(document)
+	return document, nil
+}
+
+// redact replaces the whole value of every sensitive key, be it a string, a
+// document or an array, and redacts the documents under the other keys.
+func redact(document map[string]interface{}) {
+	for key, value := range document {
+		if isSensitiveKey(key) {
+			if !isZeroValue(value) {
+				document[key] = RedactedValue
+			}
+			continue
+		}
+
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			redact(nested)
+		case []interface{}:
+			redactElements(nested)
+		}
+	}
+}
+
+// redactElements redacts the documents among the elements of an array, such
+// as each of the executor backends.
+func redactElements(elements []interface{}) {
+	for _, element := range elements {
+		switch nested := element.(type) {
+		case map[string]interface{}:
+			redact(nested)
+		case []interface{}:
+			redactElements(nested)
+		}
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range sensitiveKeyFragments {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func isZeroValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// ConfigEvent records a single attempt at (re)loading the configuration.
+type ConfigEvent struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// EffectiveConfig is the document served by the debug config endpoint.
+type EffectiveConfig struct {
+	Config  map[string]interface{} `json:"config"`
+	History []ConfigEvent          `json:"history"`
+}
+
+// ConfigHistory holds the configuration the rep is currently running with
+// along with the history of every load and reload of it.
+type ConfigHistory struct {
+	clock clock.Clock
+
+	mu      sync.RWMutex
+	current RepConfig
+	events  []ConfigEvent
+}
+
+func NewConfigHistory(clock clock.Clock, source string, initial RepConfig) *ConfigHistory {
+	history := &ConfigHistory{clock: clock}
+	history.Record(source, initial)
+	return history
+}
+
+// Record makes cfg the current configuration and appends a successful load
+// event for it.
+func (h *ConfigHistory) Record(source string, cfg RepConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.current = cfg
+	h.events = append(h.events, ConfigEvent{Time: h.clock.Now(), Source: source})
+}
+
+// Update changes the current configuration with update and appends a
+// successful load event for it. The change is made under the lock of the
+// history, so that reloads changing different parts of the configuration at
+// the same time do not undo each other.
+func (h *ConfigHistory) Update(source string, update func(*RepConfig)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	update(&h.current)
+	h.events = append(h.events, ConfigEvent{Time: h.clock.Now(), Source: source})
+}
+
+// RecordFailure appends a failed load event, leaving the current
+// configuration untouched.
+func (h *ConfigHistory) RecordFailure(source string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events = append(h.events, ConfigEvent{Time: h.clock.Now(), Source: source, Error: err.Error()})
+}
+
+func (h *ConfigHistory) Current() RepConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.current
+}
+
+func (h *ConfigHistory) Events() []ConfigEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	events := make([]ConfigEvent, len(h.events))
+	copy(events, h.events)
+	return events
+}
+
+// EffectiveConfig returns the redacted current configuration together with
+// its load history.
+func (h *ConfigHistory) EffectiveConfig() (interface{}, error) {
+	redacted, err := h.Current().Redacted()
+	if err != nil {
+		return nil, err
+	}
+
+	return EffectiveConfig{
+		Config:  redacted,
+		History: h.Events(),
+	}, nil
+}
@@ -0,0 +1,161 @@
+package config_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	executorinit "code.cloudfoundry.org/executor/initializer"
+	"code.cloudfoundry.org/locket"
+	"code.cloudfoundry.org/rep/cmd/rep/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EffectiveConfig", func() {
+	var repConfig config.RepConfig
+
+	BeforeEach(func() {
+		repConfig = config.RepConfig{
+			CellID:     "cell-id",
+			CertFile:   "/tmp/cert",
+			KeyFile:    "/tmp/key",
+			CaCertFile: "/tmp/ca",
+			ClientLocketConfig: locket.ClientLocketConfig{
+				LocketAddress:       "locket:8891",
+				LocketClientKeyFile: "/tmp/locket-key",
+			},
+		}
+	})
+
+	Describe("Redacted", func() {
+		It("redacts the values of sensitive keys", func() {
+			redacted, err := repConfig.Redacted()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(redacted["key_file"]).To(Equal(config.RedactedValue))
+			Expect(redacted["locket_client_key_file"]).To(Equal(config.RedactedValue))
+		})
+
+		It("leaves non sensitive values intact", func() {
+			redacted, err := repConfig.Redacted()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(redacted["cell_id"]).To(Equal("cell-id"))
+			Expect(redacted["cert_file"]).To(Equal("/tmp/cert"))
+			Expect(redacted["locket_address"]).To(Equal("locket:8891"))
+		})
+
+		It("does not redact unset sensitive values", func() {
+			repConfig.KeyFile = ""
+
+			redacted, err := repConfig.Redacted()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(redacted["key_file"]).To(Equal(""))
+		})
+
+		It("redacts the sensitive values of the documents in an array", func() {
+			repConfig.ExecutorBackends = []config.ExecutorBackendConfig{{
+				Name: "windows",
+				ExecutorConfig: executorinit.ExecutorConfig{
+					GardenAddr:   "100.0.0.2",
+					PathToTLSKey: "/tmp/windows-key",
+				},
+			}}
+
+			redacted, err := repConfig.Redacted()
+			Expect(err).NotTo(HaveOccurred())
+
+			backends := redacted["executor_backends"].([]interface{})
+			Expect(backends).To(HaveLen(1))
+			backend := backends[0].(map[string]interface{})
+			Expect(backend["path_to_tls_key"]).To(Equal(config.RedactedValue))
+			Expect(backend["garden_addr"]).To(Equal("100.0.0.2"))
+		})
+
+		It("redacts the whole value of a sensitive key holding an array", func() {
+			repConfig.FailureDomains = map[string][]string{
+				"rack":        {"rack-1"},
+				"token-realm": {"realm-1", "realm-2"},
+			}
+
+			redacted, err := repConfig.Redacted()
+			Expect(err).NotTo(HaveOccurred())
+
+			domains := redacted["failure_domains"].(map[string]interface{})
+			Expect(domains["token-realm"]).To(Equal(config.RedactedValue))
+			Expect(domains["rack"]).To(Equal([]interface{}{"rack-1"}))
+		})
+	})
+
+	Describe("ConfigHistory", func() {
+		var (
+			fakeClock *fakeclock.FakeClock
+			history   *config.ConfigHistory
+		)
+
+		BeforeEach(func() {
+			fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+			history = config.NewConfigHistory(fakeClock, "/path/to/config.json", repConfig)
+		})
+
+		It("records the initial load", func() {
+			Expect(history.Current()).To(Equal(repConfig))
+			Expect(history.Events()).To(ConsistOf(config.ConfigEvent{
+				Time:   time.Unix(123, 0),
+				Source: "/path/to/config.json",
+			}))
+		})
+
+		It("records subsequent reloads", func() {
+			fakeClock.Increment(time.Minute)
+			reloaded := repConfig
+			reloaded.Zone = "z2"
+			history.Record("reload", reloaded)
+
+			Expect(history.Current().Zone).To(Equal("z2"))
+			Expect(history.Events()).To(HaveLen(2))
+			Expect(history.Events()[1]).To(Equal(config.ConfigEvent{
+				Time:   time.Unix(123, 0).Add(time.Minute),
+				Source: "reload",
+			}))
+		})
+
+		It("updates the current configuration in place", func() {
+			fakeClock.Increment(time.Minute)
+			history.Update("reload", func(current *config.RepConfig) {
+				current.Zone = "z2"
+			})
+			history.Update("reload", func(current *config.RepConfig) {
+				current.IsolationSegment = "segment"
+			})
+
+			Expect(history.Current().Zone).To(Equal("z2"))
+			Expect(history.Current().IsolationSegment).To(Equal("segment"))
+			Expect(history.Current().CellID).To(Equal("cell-id"))
+			Expect(history.Events()).To(HaveLen(3))
+			Expect(history.Events()[2]).To(Equal(config.ConfigEvent{
+				Time:   time.Unix(123, 0).Add(time.Minute),
+				Source: "reload",
+			}))
+		})
+
+		It("keeps the current configuration when a reload fails", func() {
+			history.RecordFailure("reload", errors.New("boom"))
+
+			Expect(history.Current()).To(Equal(repConfig))
+			Expect(history.Events()).To(HaveLen(2))
+			Expect(history.Events()[1].Error).To(Equal("boom"))
+		})
+
+		It("returns the redacted configuration along with its history", func() {
+			effective, err := history.EffectiveConfig()
+			Expect(err).NotTo(HaveOccurred())
+
+			effectiveConfig, ok := effective.(config.EffectiveConfig)
+			Expect(ok).To(BeTrue())
+			Expect(effectiveConfig.Config["key_file"]).To(Equal(config.RedactedValue))
+			Expect(effectiveConfig.History).To(Equal(history.Events()))
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package config_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"code.cloudfoundry.org/rep/cmd/rep/config"
+)
+
+// FuzzRootFSesUnmarshalJSON checks that the preloaded rootfses a cell is
+// configured with, and so its stack path map, survive being encoded again.
+func FuzzRootFSesUnmarshalJSON(f *testing.F) {
+	f.Add([]byte(`["cflinuxfs4:/var/vcap/packages/cflinuxfs4/rootfs.tar"]`))
+	f.Add([]byte(`["windows:oci:///C:/var/vcap/packages/windows", "cflinuxfs4:"]`))
+	f.Add([]byte(`[":/path", "no-path"]`))
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		var rootFSes config.RootFSes
+		if json.Unmarshal(payload, &rootFSes) != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(rootFSes)
+		if err != nil {
+			t.Fatalf("failed to encode the decoded rootfses: %s", err)
+		}
+
+		var decoded config.RootFSes
+		err = json.Unmarshal(encoded, &decoded)
+		if err != nil {
+			t.Fatalf("failed to decode the re-encoded rootfses %s: %s", encoded, err)
+		}
+		if !reflect.DeepEqual(decoded.StackPathMap(), rootFSes.StackPathMap()) {
+			t.Fatalf("re-encoded stack path map %v differs from %v", decoded.StackPathMap(), rootFSes.StackPathMap())
+		}
+	})
+}
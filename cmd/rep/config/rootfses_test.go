@@ -0,0 +1,115 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/rep/cmd/rep/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RootFSes", func() {
+	unmarshal := func(values ...string) (config.RootFSes, error) {
+		payload, err := json.Marshal(values)
+		Expect(err).NotTo(HaveOccurred())
+
+		var rootFSes config.RootFSes
+		err = json.Unmarshal(payload, &rootFSes)
+		return rootFSes, err
+	}
+
+	It("cleans the absolute paths and keeps relative paths and the rootfses given as URLs", func() {
+		rootFSes, err := unmarshal("cflinuxfs4:/var/vcap/packages/../packages/cflinuxfs4/", "windows:oci:///C:/var/vcap/packages/windows", "relative:rootfs/../rootfs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rootFSes).To(Equal(config.RootFSes{
+			{"cflinuxfs4", "/var/vcap/packages/cflinuxfs4"},
+			{"windows", "oci:///C:/var/vcap/packages/windows"},
+			{"relative", "rootfs/../rootfs"},
+		}))
+	})
+
+	It("rejects invalid rootfses with the reason and where they are", func() {
+		_, err := unmarshal("cflinuxfs4:/rootfs", "no-path")
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: 1, Reason: config.RootFSReasonMalformed}))
+
+		_, err = unmarshal(":/rootfs")
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: 0, Reason: config.RootFSReasonBlankStack}))
+
+		_, err = unmarshal("cflinuxfs4:")
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: 0, Stack: "cflinuxfs4", Reason: config.RootFSReasonBlankPath}))
+
+		_, err = unmarshal("cflinuxfs4:/rootfs", "cflinuxfs4:/other")
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: 1, Stack: "cflinuxfs4", Reason: config.RootFSReasonDuplicateStack}))
+		Expect(err).To(MatchError(`Invalid preloaded RootFS value for stack "cflinuxfs4" at index 1: duplicate stack`))
+	})
+
+	It("rejects too many or too long rootfses", func() {
+		_, err := unmarshal("cflinuxfs4:/" + strings.Repeat("a", config.MaxPreloadedRootFSLength))
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: 0, Reason: config.RootFSReasonTooLong}))
+
+		values := make([]string, config.MaxPreloadedRootFSes+1)
+		for i := range values {
+			values[i] = fmt.Sprintf("stack-%d:/rootfs", i)
+		}
+		_, err = unmarshal(values...)
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: config.MaxPreloadedRootFSes, Reason: config.RootFSReasonTooMany}))
+	})
+
+	It("rejects payloads larger than the maximum size without decoding them", func() {
+		payload := "[" + strings.Repeat(" ", config.MaxPreloadedRootFSes*(config.MaxPreloadedRootFSLength+3)) + "]"
+
+		var rootFSes config.RootFSes
+		err := json.Unmarshal([]byte(payload), &rootFSes)
+		Expect(err).To(Equal(config.InvalidRootFSError{Index: -1, Reason: config.RootFSReasonTooLarge}))
+		Expect(err).To(MatchError("Invalid preloaded RootFS value: payload larger than the maximum size"))
+	})
+
+	Describe("CheckContainedIn", func() {
+		var dir, outside string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "preloaded-rootfses")
+			Expect(err).NotTo(HaveOccurred())
+			outside, err = ioutil.TempDir("", "outside")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.Mkdir(filepath.Join(dir, "cflinuxfs4"), 0755)).To(Succeed())
+			Expect(os.Symlink(filepath.Join(dir, "cflinuxfs4"), filepath.Join(dir, "current"))).To(Succeed())
+			Expect(os.Symlink(outside, filepath.Join(dir, "escape"))).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+			Expect(os.RemoveAll(outside)).To(Succeed())
+		})
+
+		It("accepts rootfses that resolve within the dir", func() {
+			rootFSes := config.RootFSes{
+				{"cflinuxfs4", filepath.Join(dir, "current")},
+				{"windows", "oci:///C:/var/vcap/packages/windows"},
+			}
+			Expect(rootFSes.CheckContainedIn(dir)).To(Succeed())
+		})
+
+		It("rejects rootfses whose symlinks escape the dir", func() {
+			rootFSes := config.RootFSes{{"cflinuxfs4", filepath.Join(dir, "escape")}}
+			Expect(rootFSes.CheckContainedIn(dir)).To(Equal(config.InvalidRootFSError{Index: 0, Stack: "cflinuxfs4", Reason: config.RootFSReasonOutsideDir}))
+		})
+
+		It("rejects rootfses with relative paths", func() {
+			rootFSes := config.RootFSes{{"cflinuxfs4", "cflinuxfs4"}}
+			Expect(rootFSes.CheckContainedIn(dir)).To(Equal(config.InvalidRootFSError{Index: 0, Stack: "cflinuxfs4", Reason: config.RootFSReasonRelativePath}))
+		})
+
+		It("rejects rootfses that do not exist", func() {
+			rootFSes := config.RootFSes{{"cflinuxfs4", filepath.Join(dir, "missing")}}
+			Expect(rootFSes.CheckContainedIn(dir)).To(Equal(config.InvalidRootFSError{Index: 0, Stack: "cflinuxfs4", Reason: config.RootFSReasonUnresolvable}))
+		})
+	})
+})
@@ -12,7 +12,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"code.cloudfoundry.org/bbs"
@@ -24,28 +27,64 @@ import (
 	"code.cloudfoundry.org/executor"
 	executorinit "code.cloudfoundry.org/executor/initializer"
 	"code.cloudfoundry.org/go-loggregator/v8/runtimeemitter"
+	"code.cloudfoundry.org/grootfs/store/filesystems/overlayxfs"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagerflags"
 	"code.cloudfoundry.org/localip"
 	"code.cloudfoundry.org/locket"
-	"code.cloudfoundry.org/locket/lock"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 	locketmodels "code.cloudfoundry.org/locket/models"
 	"code.cloudfoundry.org/operationq"
 	"code.cloudfoundry.org/rep"
 	"code.cloudfoundry.org/rep/auctioncellrep"
+	"code.cloudfoundry.org/rep/bbsbuffer"
+	"code.cloudfoundry.org/rep/clockskew"
 	"code.cloudfoundry.org/rep/cmd/rep/config"
+	"code.cloudfoundry.org/rep/consistency"
+	"code.cloudfoundry.org/rep/contacts"
+	"code.cloudfoundry.org/rep/containerd"
+	"code.cloudfoundry.org/rep/containerevents"
+	"code.cloudfoundry.org/rep/cordon"
+	"code.cloudfoundry.org/rep/crashloop"
+	"code.cloudfoundry.org/rep/diskquota"
+	"code.cloudfoundry.org/rep/downloadcache"
 	"code.cloudfoundry.org/rep/evacuation"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+	"code.cloudfoundry.org/rep/fairqueue"
+	"code.cloudfoundry.org/rep/featureflags"
 	"code.cloudfoundry.org/rep/generator"
 	"code.cloudfoundry.org/rep/handlers"
 	"code.cloudfoundry.org/rep/harmonizer"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"code.cloudfoundry.org/rep/hostmetrics"
+	"code.cloudfoundry.org/rep/iaasmetadata"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/iothrottle"
+	"code.cloudfoundry.org/rep/lifecyclehints"
+	"code.cloudfoundry.org/rep/lifecycles"
+	"code.cloudfoundry.org/rep/loadbalancer"
+	"code.cloudfoundry.org/rep/logratelimit"
+	"code.cloudfoundry.org/rep/maintenance"
+	"code.cloudfoundry.org/rep/nodeshim"
+	"code.cloudfoundry.org/rep/placementpolicy"
+	"code.cloudfoundry.org/rep/presence"
+	"code.cloudfoundry.org/rep/pressure"
+	"code.cloudfoundry.org/rep/proxyreadiness"
+	"code.cloudfoundry.org/rep/repgrpc"
+	"code.cloudfoundry.org/rep/saturation"
+	"code.cloudfoundry.org/rep/selftest"
+	"code.cloudfoundry.org/rep/standby"
+	"code.cloudfoundry.org/rep/supervisor"
+	"code.cloudfoundry.org/rep/taskcompletion"
+	"code.cloudfoundry.org/rep/tenancy"
 	"code.cloudfoundry.org/tlsconfig"
-	uuid "github.com/nu7hatch/gouuid"
+	nats "github.com/nats-io/nats.go"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/grouper"
 	"github.com/tedsuo/ifrit/sigmon"
 	"github.com/tedsuo/rata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var configFilePath = flag.String(
@@ -60,6 +99,18 @@ var zoneOverride = flag.String(
 	"The availability zone associated with the rep. This overrides the zone value in the config file, if specified.",
 )
 
+var warmStandby = flag.Bool(
+	"standby",
+	false,
+	"Start as a warm standby that waits for the rep running on the host to mark a planned restart, then takes over from it.",
+)
+
+const warmStandbyPollInterval = time.Second
+
+// healthCheckRestoreInterval bounds how long an instance keeps its relaxed
+// health checks once their relaxation is over.
+const healthCheckRestoreInterval = 5 * time.Second
+
 func main() {
 	flag.Parse()
 
@@ -78,6 +129,21 @@ func main() {
 	clock := clock.NewClock()
 	logger, reconfigurableSink := lagerflags.NewFromConfig(repConfig.SessionName, repConfig.LagerConfig)
 
+	instanceMetadata := discoverInstanceMetadata(logger, repConfig)
+	if repConfig.Zone == "" {
+		repConfig.Zone = instanceMetadata.Zone
+	}
+	if repConfig.CellID == "" {
+		repConfig.CellID = instanceMetadata.InstanceID
+	}
+	configHistory := config.NewConfigHistory(clock, *configFilePath, repConfig)
+	err = featureflags.Validate(repConfig.FeatureFlags)
+	if err != nil {
+		logger.Error("invalid-feature-flags", err)
+		os.Exit(1)
+	}
+	featureFlags := featureflags.New(repConfig.FeatureFlags)
+
 	if !repConfig.ExecutorConfig.Validate(logger) {
 		logger.Fatal("", errors.New("failed-to-configure-executor"))
 	}
@@ -87,64 +153,323 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := checkPreloadedRootFSes(repConfig); err != nil {
+		logger.Error("invalid-preloaded-rootfs", err)
+		os.Exit(1)
+	}
+
+	listeners := standby.NewListeners(repConfig.WarmStandbyHandoffSocket)
+	if *warmStandby {
+		if repConfig.WarmStandbyHandoffSocket == "" {
+			logger.Error("invalid-warm-standby", errors.New("warm_standby_handoff_socket must be configured"))
+			os.Exit(1)
+		}
+
+		logger.Info("waiting-for-handoff")
+		err := presence.WaitForHandoff(repConfig.PresenceOwnerFile, clock, warmStandbyPollInterval, time.Duration(repConfig.WarmStandbyTimeout))
+		if err != nil {
+			logger.Error("failed-to-take-over-from-active-rep", err)
+			os.Exit(1)
+		}
+
+		replacedExited, err := listeners.TakeOver(logger)
+		if err != nil {
+			logger.Error("failed-to-take-over-from-active-rep", err)
+			os.Exit(1)
+		}
+		// the executor of the replaced rep manages the containers until it exits
+		logger.Info("waiting-for-active-rep-to-exit")
+		<-replacedExited
+		logger.Info("taking-over")
+	}
+
 	metronClient, err := initializeMetron(logger, repConfig)
 	if err != nil {
 		logger.Error("failed-to-initialize-metron-client", err)
 		os.Exit(1)
 	}
 
+	osFamily, err := cellOSFamily(repConfig)
+	if err != nil {
+		logger.Error("invalid-os-family", err)
+		os.Exit(1)
+	}
+
 	rootFSMap := repConfig.PreloadedRootFS.StackPathMap()
 
-	executorClient, containerMetricsProvider, executorMembers, err := executorinit.Initialize(logger, repConfig.ExecutorConfig, repConfig.CellID, repConfig.Zone, rootFSMap, metronClient, clock)
+	// the executor sends the logs of containers through the limiter, when the
+	// cell rate limits them
+	logLimiter := logRateLimiter(repConfig, metronClient, clock)
+	var executorMetronClient loggingclient.IngressClient = metronClient
+	if logLimiter != nil {
+		executorMetronClient = logLimiter
+	}
+
+	executorClient, containerMetricsProvider, executorMembers, err := executorinit.Initialize(logger, repConfig.ExecutorConfig, repConfig.CellID, repConfig.Zone, rootFSMap, executorMetronClient, clock)
 	if err != nil {
 		logger.Error("failed-to-initialize-executor", err)
 		os.Exit(1)
 	}
 	defer executorClient.Cleanup(logger)
 
+	containerEvents := containerEventHistory(repConfig, clock)
+	if containerEvents != nil {
+		executorClient = containerevents.NewRecordingClient(executorClient, containerEvents)
+	}
+
+	if repConfig.WarmStandbyInventoryFile != "" {
+		err := standby.RestoreInventory(logger, executorClient, repConfig.WarmStandbyInventoryFile)
+		if err != nil {
+			logger.Error("failed-to-restore-inventory", err)
+			os.Exit(1)
+		}
+	}
+
+	var metricsProvider rep.ContainerMetricsProvider = containerMetricsProvider
+	var containerdMetricsProvider *containerd.MetricsProvider
+	if repConfig.ContainerdAddress != "" {
+		containerdMetricsProvider = initializeContainerdMetricsProvider(logger, repConfig, containerMetricsProvider, metronClient, clock)
+		metricsProvider = containerdMetricsProvider
+	}
+
 	evacuatable, evacuationReporter, evacuationNotifier := evacuation_context.New()
+	var hintPublisher lifecyclehints.Publisher
+	if len(repConfig.LifecycleHintsNATSAddresses) > 0 {
+		natsConn, err := connectToLifecycleHintsNATS(logger, repConfig)
+		if err != nil {
+			logger.Error("failed-to-connect-to-nats", err)
+			os.Exit(1)
+		}
+		defer natsConn.Close()
+		hintPublisher = lifecyclehints.NewPublisher(lifecyclehints.NewNATSBus(natsConn), repConfig.CellID)
+	}
+
+	crashLoopDetector := initializeCrashLoopDetector(repConfig, clock)
+	healthCheckRelaxer := healthchecks.NewRelaxer(clock)
+	workGroupHolds := rep.NewWorkGroupHolds()
+	bbsClient := initializeBBSClient(logger, repConfig)
+	notificationBuffer, err := bbsNotificationBuffer(logger, repConfig, bbsClient, clock)
+	if err != nil {
+		logger.Error("failed-to-initialize-bbs-notification-buffer", err)
+		os.Exit(1)
+	}
+	if notificationBuffer != nil {
+		bbsClient = notificationBuffer
+	}
+
+	taskCompleter, taskCompletionBatcher := initializeTaskCompleter(logger, repConfig, bbsClient, clock)
+	backends, backendMembers, err := initializeExecutorBackends(logger, repConfig, bbsClient, metronClient, evacuationReporter, hintPublisher, crashLoopDetector, healthCheckRelaxer, workGroupHolds, taskCompleter, featureFlags, clock)
+	if err != nil {
+		logger.Error("failed-to-initialize-executor-backends", err)
+		os.Exit(1)
+	}
+	for _, backend := range backends {
+		defer backend.Client.Cleanup(logger)
+	}
+	// the operations on the containers of the cell go to the backend that
+	// holds them
+	containersClient := auctioncellrep.NewBackendsClient(executorClient, backends)
+	drainTimeout := time.Duration(repConfig.LoadBalancerDrainTimeout)
+	if drainTimeout == 0 {
+		drainTimeout = defaultLoadBalancerDrainTimeout
+	}
+	if deregisterers := loadBalancerDeregisterers(repConfig, drainTimeout, clock); len(deregisterers) > 0 {
+		evacuatable = loadbalancer.NewDrainingEvacuatable(
+			logger,
+			evacuatable,
+			containersClient,
+			deregisterers,
+			repConfig.CellID,
+			drainTimeout,
+			clock,
+		)
+	}
+	maintainable, maintenanceReporter := maintenance.New(repConfig.MaintenanceMode)
+	cordonWatcher := initializeCordonWatcher(logger, repConfig, clock)
 
 	// only one outstanding operation per container is necessary
-	queue := operationq.NewSlidingQueue(1)
+	queue := harmonizer.NewPendingQueue(operationq.NewSlidingQueue(1))
 
 	evacuator := evacuation.NewEvacuator(
 		logger,
 		clock,
-		executorClient,
+		containersClient,
 		evacuationNotifier,
 		repConfig.CellID,
 		time.Duration(repConfig.EvacuationTimeout),
 		time.Duration(repConfig.EvacuationPollingInterval),
 	)
 
-	bbsClient := initializeBBSClient(logger, repConfig)
 	url := repURL(repConfig)
 	address := repAddress(logger, repConfig)
-	cellPresence := initializeCellPresence(address, executorClient, logger, repConfig, repConfig.PreloadedRootFS.Names(), url)
+	presenceHandoff, err := presence.NewHandoff(repConfig.PresenceOwnerFile)
+	if err != nil {
+		logger.Error("failed-to-initialize-presence-handoff", err)
+		os.Exit(1)
+	}
+	rootFSNames := repConfig.PreloadedRootFS.Names()
+	for _, backendConfig := range repConfig.ExecutorBackends {
+		rootFSNames = append(rootFSNames, backendConfig.PreloadedRootFS.Names()...)
+	}
+	presenceStatus := presence.NewStatus()
+	cellPresence := initializeCellPresence(address, executorClient, logger, repConfig, rootFSNames, url, presenceHandoff, presenceStatus, metronClient)
 	batchContainerAllocator := auctioncellrep.NewContainerAllocator(auctioncellrep.GenerateGuid, rootFSMap, executorClient)
+	imageStores := initializeImageStores(repConfig)
+	diskGrowth, err := auctioncellrep.NewDiskQuotaGrowth(logger, repConfig.DiskQuotaGrowthFile)
+	if err != nil {
+		logger.Error("failed-to-load-disk-quota-growth", err)
+		os.Exit(1)
+	}
+	pruner := imageCachePruner(repConfig, imageStores, metronClient)
+	cacheTracker := downloadCacheTracker(repConfig, executorClient, metronClient)
+	forecaster := usageForecaster(repConfig, metricsProvider, clock)
+	schedule, err := maintenanceSchedule(repConfig, clock)
+	if err != nil {
+		logger.Error("invalid-maintenance-windows", err)
+		os.Exit(1)
+	}
+	lifecycleCatalog := initializeLifecycleCatalog(logger, repConfig)
+	policy, err := placementPolicy(repConfig)
+	if err != nil {
+		logger.Error("failed-to-load-placement-policy", err)
+		os.Exit(1)
+	}
+	var logDrops auctioncellrep.LogDropCounter
+	var logRateLimits handlers.LogRateLimitReporter
+	if logLimiter != nil {
+		logDrops = logLimiter
+		logRateLimits = logratelimit.NewReporter(executorClient, logLimiter)
+	}
+	placements := placementHistory(repConfig, clock)
+	cgroups := cgroupInspector(repConfig, osFamily)
+	cgroupInfo := hostCgroups(logger, cgroups)
+	ioThrottler := initializeIOThrottler(logger, repConfig, cgroupInfo, executorClient, backends, clock)
+	contactTracker := contacts.NewTracker(clock)
+	evictor := pressureEvictor(logger, repConfig, executorClient, metricsProvider, bbsClient, metronClient, clock)
+	cellConditions := []auctioncellrep.CellCondition{presenceStatus}
+	if evictor != nil {
+		cellConditions = append(cellConditions, evictor)
+	}
 	auctionCellRep := auctioncellrep.New(
 		repConfig.CellID,
 		repConfig.CellIndex,
 		url,
 		rootFSMap,
-		containerMetricsProvider,
+		metricsProvider,
 		repConfig.SupportedProviders,
 		repConfig.Zone,
+		instanceMetadata.InstanceID,
+		instanceMetadata.InstanceType,
+		osFamily,
+		rep.NewResource(repConfig.WindowsImageOverheadMemoryMB, repConfig.WindowsImageOverheadDiskMB, 0),
+		rep.NewResource(repConfig.MaxContainerMemoryMB, repConfig.MaxContainerDiskMB, 0),
+		rep.SecurityPermissions{
+			Capabilities:     repConfig.AllowedCapabilities,
+			SeccompProfiles:  repConfig.AllowedSeccompProfiles,
+			AppArmorProfiles: repConfig.AllowedAppArmorProfiles,
+		},
+		repConfig.HostPortPoolSize,
+		repConfig.CPUEntitlement,
+		tenantCaps(repConfig),
+		repConfig.StackContainerLimits,
+		cgroupInfo,
+		logDrops,
+		ioLimitReporter(ioThrottler),
 		executorClient,
 		evacuationReporter,
+		maintenanceReporter,
+		cordonReporter(cordonWatcher),
+		contactTracker,
+		cellConditions,
 		repConfig.PlacementTags,
 		repConfig.OptionalPlacementTags,
+		repConfig.IsolationSegment,
 		repConfig.ProxyMemoryAllocationMB,
 		repConfig.EnableContainerProxy,
 		batchContainerAllocator,
+		backends,
+		hostPressureReader(repConfig),
+		repConfig.HostPressureScoreWeight,
+		recentLRPTracker(repConfig, clock),
+		repConfig.RecentLRPScoreBonus,
+		placements,
+		forecaster,
+		repConfig.UsageForecastScoreWeight,
+		rootFSUsageReader(imageStores),
+		imageSizeChecker(repConfig, imageStores, clock),
+		imageDigestPolicy(repConfig, metronClient),
+		lifecycleCatalog,
+		repConfig.InitStepPaths,
+		capacityReservations(repConfig, clock),
+		schedule,
+		rep.FailureDomainsOf(repConfig.FailureDomains, repConfig.CellID),
+		repConfig.FailureDomainScorePenalty,
+		crashLoopDetector,
+		workGroupHolds,
+		policy,
+		clockSkewMonitor(repConfig, metronClient, clock),
+		diskQuotaGrower(repConfig, osFamily),
+		diskGrowth,
+		clock,
+		featureFlags,
 	)
 
 	requestTypes := []string{
-		"State", "ContainerMetrics", "Perform", "Reset", "UpdateLRPInstance", "StopLRPInstance", "CancelTask", //over https only
+		"State", "ContainerMetrics", "Perform", "Info", "Containers", "Reset", "UpdateLRPInstance", "StopLRPInstance", "StopLRPInstances", "CancelTask", "ReserveCapacity", "ReleaseCapacity", "GrowDiskQuota", "ContainerMetricsBatch", "CapacitySummary", "CanPlace", //over https only
+		"DebugConfig", "OpenAPI", "ImageCachePrune", "BlockPlacement", "UnblockPlacement", "PlacementBlocks", "PlacementTags", "UpdatePlacementTags", "Fragmentation", "Consistency", "CacheStats", "ContainerEvents", "SelfTest", "CapacityReport", "AuctionRoutes", "CloseAuctionRoutes", "OpenAuctionRoutes", "RelaxHealthCheck", "RestoreHealthCheck", "HealthCheckRelaxations",
+	}
+	for _, route := range rep.Routes {
+		requestTypes = append(requestTypes, handlers.QueueRequestType(route.Name))
 	}
 	requestMetrics := helpers.NewRequestMetricsNotifier(logger, clock, metronClient, time.Duration(repConfig.ReportInterval), requestTypes)
-	httpServer := initializeServer(auctionCellRep, executorClient, evacuatable, requestMetrics, logger, repConfig, false)
-	httpsServer := initializeServer(auctionCellRep, executorClient, evacuatable, requestMetrics, logger, repConfig, true)
+
+	infoReporter := cellInfoReporter(repConfig, featureFlags)
+	performQueue := initializePerformQueue(repConfig, metronClient)
+
+	requestRecorder, err := initializeRequestRecorder(logger, repConfig, clock)
+	if err != nil {
+		logger.Error("failed-to-initialize-request-recorder", err)
+		os.Exit(1)
+	}
+
+	localRoutes := rep.NewRoutes(false)
+	localHandlers := handlers.New(auctionCellRep, auctionCellRep, containersClient, evacuatable, maintainable, presenceHandoff, infoReporter, performQueue, auctionCellRep, auctionCellRep, containerEvents, cgroups, logRateLimits, healthchecks.NewReporter(containersClient, healthCheckRelaxer), auctionCellRep, contactTracker, requestMetrics, clock, logger, false)
+	var capacityReporter handlers.CapacityReporter
+	if placements != nil {
+		capacityReporter = placements
+	}
+	checker := consistencyChecker(logger, repConfig, containersClient, bbsClient, metronClient, clock)
+	var consistencyReporter handlers.ConsistencyReporter
+	if checker != nil {
+		consistencyReporter = checker
+	}
+	adminHandlers := handlers.NewAdmin(configHistory, pruner, auctionCellRep, auctionCellRep, auctionCellRep, consistencyReporter, cacheTracker, selfTester(logger, repConfig, osFamily, executorClient, clock), capacityReporter, auctionCellRep, healthCheckRelaxer, requestMetrics, clock, logger)
+
+	var adminServer ifrit.Runner
+	if repConfig.ListenAddrAdmin == "" {
+		localRoutes = append(localRoutes, rep.NewAdminRoutes()...)
+		for name, handler := range adminHandlers {
+			localHandlers[name] = handler
+		}
+	} else {
+		adminCertFile, adminKeyFile, adminCaCertFile := repConfig.AdminTLSFiles()
+		adminServer = initializeServer(logger, rep.NewAdminRoutes(), adminHandlers, repConfig.ListenAddrAdmin, adminCertFile, adminKeyFile, adminCaCertFile, false, listeners, requestMetrics, clock)
+	}
+
+	httpServer := initializeServer(logger, localRoutes, localHandlers, repConfig.ListenAddr, repConfig.CertFile, repConfig.KeyFile, repConfig.CaCertFile, true, listeners, requestMetrics, clock)
+	httpsServer := initializeServer(
+		logger,
+		rep.NewRoutes(true),
+		handlers.RecordRequests(handlers.New(auctionCellRep, auctionCellRep, containersClient, evacuatable, maintainable, presenceHandoff, infoReporter, performQueue, auctionCellRep, auctionCellRep, containerEvents, cgroups, logRateLimits, healthchecks.NewReporter(containersClient, healthCheckRelaxer), auctionCellRep, contactTracker, requestMetrics, clock, logger, true), requestRecorder),
+		repConfig.ListenAddrSecurable,
+		repConfig.CertFile,
+		repConfig.KeyFile,
+		repConfig.CaCertFile,
+		false,
+		listeners,
+		requestMetrics,
+		clock,
+	)
 
 	opGenerator := generator.New(
 		repConfig.CellID,
@@ -154,6 +479,14 @@ func main() {
 		executorClient,
 		metronClient,
 		evacuationReporter,
+		proxyReadinessWaiter(repConfig, clock),
+		hintPublisher,
+		crashLoopDetector,
+		healthCheckRelaxer,
+		workGroupHolds,
+		taskCompleter,
+		contactTracker,
+		featureFlags,
 	)
 
 	cleanup := evacuation.NewEvacuationCleanup(
@@ -162,34 +495,136 @@ func main() {
 		time.Duration(repConfig.GracefulShutdownInterval),
 		time.Duration(repConfig.ExecutorConfig.EnvoyConfigReloadDuration),
 		bbsClient,
-		executorClient,
+		containersClient,
 		clock,
 		metronClient,
 	)
 
+	adaptiveInterval, err := adaptivePollingInterval(repConfig, queue)
+	if err != nil {
+		logger.Error("invalid-polling-interval-bounds", err)
+		os.Exit(1)
+	}
+
 	bulker := harmonizer.NewBulker(
 		logger,
 		time.Duration(repConfig.PollingInterval),
 		time.Duration(repConfig.EvacuationPollingInterval),
+		adaptiveInterval,
 		evacuationNotifier,
 		clock,
 		opGenerator,
 		queue,
 		metronClient,
+		auctionCellRep,
 	)
 
+	supervise := supervisorFor(logger, repConfig, metronClient, clock)
+
 	members := grouper.Members{
 		{"presence", cellPresence},
 		{"http_server", httpServer},
 		{"https_server", httpsServer},
 		{"evacuation-cleanup", cleanup},
-		{"bulker", bulker},
-		{"event-consumer", harmonizer.NewEventConsumer(logger, opGenerator, queue)},
-		{"evacuator", evacuator},
-		{"request-metrics-notifier", requestMetrics},
+		{"bulker", supervise("bulker", bulker)},
+		{"event-consumer", supervise("event-consumer", harmonizer.NewEventConsumer(logger, opGenerator, queue))},
+		{"evacuator", supervise("evacuator", evacuator)},
+		{"request-metrics-notifier", supervise("request-metrics-notifier", requestMetrics)},
+		{"feature-flags-reloader", initializeFeatureFlagsReloader(logger, featureFlags, configHistory)},
+		{"placement-tags-reloader", initializePlacementTagsReloader(logger, auctionCellRep, configHistory)},
+	}
+
+	if adminServer != nil {
+		members = append(members, grouper.Member{Name: "admin_server", Runner: adminServer})
+	}
+
+	if repConfig.ListenAddrGRPC != "" {
+		grpcServer := repgrpc.NewServer(auctionCellRep, containersClient, infoReporter, performQueue, auctionCellRep, requestMetrics, clock, logger)
+		members = append(members, grouper.Member{Name: "grpc_server", Runner: initializeGRPCServer(logger, grpcServer, repConfig, listeners)})
+	}
+
+	if cordonWatcher != nil {
+		members = append(members, grouper.Member{Name: "cordon-watcher", Runner: cordonWatcher})
+	}
+
+	if repConfig.WarmStandbyHandoffSocket != "" {
+		members = append(members, grouper.Member{Name: "listener-handoff", Runner: listeners.HandOff(logger, presenceHandoff, inventorySaver(logger, repConfig, executorClient))})
+	}
+
+	if ioThrottler != nil {
+		members = append(members, grouper.Member{Name: "io-throttler", Runner: ioThrottler})
+	}
+
+	if lifecycleCatalog != nil {
+		members = append(members, grouper.Member{Name: "lifecycle-bundles-reloader", Runner: initializeLifecycleBundlesReloader(logger, lifecycleCatalog, configHistory)})
+	}
+
+	if containerdMetricsProvider != nil {
+		members = append(members, grouper.Member{Name: "containerd-metrics", Runner: containerdMetricsProvider})
+	}
+
+	if pruner != nil && repConfig.ImageCachePruneInterval > 0 {
+		pruneRunner := imagecache.NewPruneRunner(logger, pruner, clock, time.Duration(repConfig.ImageCachePruneInterval))
+		members = append(members, grouper.Member{Name: "image-cache-pruner", Runner: pruneRunner})
+	}
+
+	if cacheTracker != nil {
+		trackerRunner := downloadcache.NewRunner(logger, cacheTracker, clock, time.Duration(repConfig.DownloadCacheStatsInterval))
+		members = append(members, grouper.Member{Name: "download-cache-tracker", Runner: trackerRunner})
+	}
+
+	if forecaster != nil {
+		members = append(members, grouper.Member{Name: "usage-forecaster", Runner: forecaster})
+	}
+
+	if containerEvents != nil {
+		recorder := containerevents.NewRecorder(logger, executorClient, containerEvents)
+		members = append(members, grouper.Member{Name: "container-event-recorder", Runner: recorder})
+	}
+
+	if repConfig.TenantMetricsInterval > 0 {
+		tenantEmitter := tenancy.NewEmitter(logger, auctionCellRep, metronClient, clock, time.Duration(repConfig.TenantMetricsInterval))
+		members = append(members, grouper.Member{Name: "tenant-usage-emitter", Runner: tenantEmitter})
+	}
+
+	if repConfig.SaturationMetricsInterval > 0 {
+		gauges := saturationGauges(queue, evacuationReporter, performQueue, taskCompletionBatcher)
+		saturationEmitter := saturation.NewEmitter(logger, metronClient, clock, time.Duration(repConfig.SaturationMetricsInterval), gauges)
+		members = append(members, grouper.Member{Name: "saturation-emitter", Runner: saturationEmitter})
+	}
+
+	if evictor != nil {
+		members = append(members, grouper.Member{Name: "pressure-evictor", Runner: evictor})
+	}
+
+	if checker != nil {
+		members = append(members, grouper.Member{Name: "consistency-checker", Runner: checker})
+	}
+
+	members = append(members, grouper.Member{Name: "health-check-restorer", Runner: healthchecks.NewRestorer(logger, executorClient, healthCheckRelaxer, clock, healthCheckRestoreInterval)})
+
+	if notificationBuffer != nil {
+		members = append(members, grouper.Member{Name: "bbs-notification-buffer", Runner: notificationBuffer})
+	}
+
+	if repConfig.KubernetesNodeName != "" {
+		shim, err := initializeNodeShim(logger, repConfig, auctionCellRep, clock)
+		if err != nil {
+			logger.Error("failed-to-initialize-node-shim", err)
+			os.Exit(1)
+		}
+		members = append(members, grouper.Member{Name: "node-shim", Runner: shim})
 	}
 
-	members = append(executorMembers, members...)
+	members = append(executorMembers, append(backendMembers, members...)...)
+
+	// the batcher is stopped last so that it reports every completion made
+	// while the generators drain
+	if taskCompletionBatcher != nil {
+		members = append(grouper.Members{
+			{"task-completion-batcher", taskCompletionBatcher},
+		}, members...)
+	}
 
 	if repConfig.DebugAddress != "" {
 		members = append(grouper.Members{
@@ -204,6 +639,9 @@ func main() {
 	logger.Info("started", lager.Data{"cell-id": repConfig.CellID})
 
 	err = <-monitor.Wait()
+	if requestRecorder != nil {
+		requestRecorder.Close()
+	}
 	if err != nil {
 		logger.Error("exited-with-failure", err)
 		os.Exit(1)
@@ -212,154 +650,1206 @@ func main() {
 	logger.Info("exited")
 }
 
-func initializeCellPresence(
-	address string,
-	executorClient executor.Client,
+func initializeContainerdMetricsProvider(
 	logger lager.Logger,
 	repConfig config.RepConfig,
-	preloadedRootFSes []string,
-	repUrl string,
-) ifrit.Runner {
-	locketClient, err := locket.NewClient(logger, repConfig.ClientLocketConfig)
-	if err != nil {
-		logger.Fatal("failed-to-construct-locket-client", err)
+	fallback rep.ContainerMetricsProvider,
+	metronClient loggingclient.IngressClient,
+	clock clock.Clock,
+) *containerd.MetricsProvider {
+	ctrPath := repConfig.ContainerdCtrPath
+	if ctrPath == "" {
+		ctrPath = "ctr"
 	}
 
-	guid, err := uuid.NewV4()
-	if err != nil {
-		logger.Fatal("failed-to-generate-guid", err)
+	namespace := repConfig.ContainerdNamespace
+	if namespace == "" {
+		namespace = "garden"
 	}
 
-	resources, err := executorClient.TotalResources(logger)
-	if err != nil {
-		logger.Fatal("failed-to-get-total-resources", err)
-	}
-	cellCapacity := models.NewCellCapacity(int32(resources.MemoryMB), int32(resources.DiskMB), int32(resources.Containers))
-	cellPresence := models.NewCellPresence(repConfig.CellID, address, repUrl,
-		repConfig.Zone, cellCapacity, repConfig.SupportedProviders,
-		preloadedRootFSes, repConfig.PlacementTags, repConfig.OptionalPlacementTags)
+	interval := time.Duration(repConfig.ExecutorConfig.ContainerMetricsReportInterval)
+	client := containerd.NewCtrClient(ctrPath, repConfig.ContainerdAddress, namespace, interval)
 
-	payload, err := json.Marshal(cellPresence)
-	if err != nil {
-		logger.Fatal("failed-to-encode-cell-presence", err)
+	return containerd.NewMetricsProvider(
+		logger,
+		client,
+		fallback,
+		clock,
+		interval,
+		repConfig.ContainerdMetricsMaxInFlight,
+		metronClient,
+	)
+}
+
+func hostPressureReader(repConfig config.RepConfig) hostmetrics.Reader {
+	if !repConfig.HostPressureEnabled {
+		return nil
 	}
+	return hostmetrics.NewReader("/proc", repConfig.HostPressureInodePath)
+}
 
-	lockPayload := &locketmodels.Resource{
-		Key:      repConfig.CellID,
-		Owner:    guid.String(),
-		Value:    string(payload),
-		TypeCode: locketmodels.PRESENCE,
-		Type:     locketmodels.PresenceType,
+const defaultPressureEvictionCooldown = 30 * time.Second
+
+// pressureEvictor returns nil unless a pressure eviction interval and a
+// minimum of available memory or disk are configured.
+func pressureEvictor(logger lager.Logger, repConfig config.RepConfig, executorClient executor.Client, metricsProvider rep.ContainerMetricsProvider, bbsClient bbs.InternalClient, metronClient loggingclient.IngressClient, clock clock.Clock) *pressure.Evictor {
+	if repConfig.PressureEvictionInterval <= 0 || (repConfig.PressureMinAvailableMemoryMB <= 0 && repConfig.PressureMinAvailableDiskMB <= 0) {
+		return nil
 	}
 
-	logger.Debug("presence-payload", lager.Data{"payload": lockPayload})
-	return lock.NewPresenceRunner(
+	cooldown := time.Duration(repConfig.PressureEvictionCooldown)
+	if cooldown <= 0 {
+		cooldown = defaultPressureEvictionCooldown
+	}
+	return pressure.NewEvictor(
 		logger,
-		locketClient,
-		lockPayload,
-		int64(time.Duration(repConfig.LockTTL)/time.Second),
-		clock.NewClock(),
-		locket.RetryInterval,
+		repConfig.CellID,
+		pressure.NewReader("/proc", repConfig.PressureEvictionDiskPath),
+		executorClient,
+		metricsProvider,
+		bbsClient,
+		metronClient,
+		clock,
+		time.Duration(repConfig.PressureEvictionInterval),
+		cooldown,
+		pressure.Available{MemoryMB: repConfig.PressureMinAvailableMemoryMB, DiskMB: repConfig.PressureMinAvailableDiskMB},
 	)
 }
 
-func initializeServer(
-	auctionCellRep *auctioncellrep.AuctionCellRep,
-	executorClient executor.Client,
-	evacuatable evacuation_context.Evacuatable,
-	requestMetrics helpers.RequestMetrics,
-	logger lager.Logger,
-	repConfig config.RepConfig,
-	networkAccessible bool,
-) ifrit.Runner {
-	handlers := handlers.New(auctionCellRep, auctionCellRep, executorClient, evacuatable, requestMetrics, logger, networkAccessible)
-	routes := rep.NewRoutes(networkAccessible)
-	router, err := rata.NewRouter(routes, handlers)
+const (
+	defaultBBSNotificationQueueMaxSize  = 10000
+	defaultBBSNotificationRetryInterval = 5 * time.Second
+)
 
-	if err != nil {
-		logger.Fatal("failed-to-construct-router", err)
+// bbsNotificationBuffer returns nil unless a directory is configured to queue
+// the notifications the BBS cannot be reached for in.
+func bbsNotificationBuffer(logger lager.Logger, repConfig config.RepConfig, bbsClient bbs.InternalClient, clock clock.Clock) (*bbsbuffer.Client, error) {
+	if repConfig.BBSNotificationQueueDir == "" {
+		return nil, nil
 	}
 
-	listenAddress := repConfig.ListenAddr
-	if networkAccessible {
-		listenAddress = repConfig.ListenAddrSecurable
+	maxSize := repConfig.BBSNotificationQueueMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultBBSNotificationQueueMaxSize
 	}
 
-	if !networkAccessible {
-		err = verifyCertificate(repConfig.CertFile)
-		if err != nil {
-			logger.Fatal("tls-configuration-failed", err)
-		}
+	queue, err := bbsbuffer.NewQueue(repConfig.BBSNotificationQueueDir, maxSize)
+	if err != nil {
+		return nil, err
 	}
 
-	tlsConfig, err := tlsconfig.Build(
-		tlsconfig.WithInternalServiceDefaults(),
-		tlsconfig.WithIdentityFromFile(repConfig.CertFile, repConfig.KeyFile),
-	).Server(tlsconfig.WithClientAuthenticationFromFile(repConfig.CaCertFile))
-	if err != nil {
-		logger.Fatal("tls-configuration-failed", err)
+	retryInterval := time.Duration(repConfig.BBSNotificationRetryInterval)
+	if retryInterval <= 0 {
+		retryInterval = defaultBBSNotificationRetryInterval
 	}
-	return startTLSServer(listenAddress, router, tlsConfig)
+	return bbsbuffer.NewClient(logger, bbsClient, queue, clock, retryInterval), nil
 }
 
-func startTLSServer(addr string, handler http.Handler, tlsConfig *tls.Config) ifrit.Runner {
-	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
-		listener, err := net.Listen("tcp", addr)
-		if err != nil {
-			return err
-		}
-		listener = tls.NewListener(listener, tlsConfig)
-		close(ready)
-		go http.Serve(listener, handler)
-		<-signals
-		return listener.Close()
-	})
+// consistencyChecker returns nil unless a consistency check interval is
+// configured.
+func consistencyChecker(logger lager.Logger, repConfig config.RepConfig, executorClient executor.Client, bbsClient bbs.InternalClient, metronClient loggingclient.IngressClient, clock clock.Clock) *consistency.Checker {
+	if repConfig.ConsistencyCheckInterval <= 0 {
+		return nil
+	}
+
+	return consistency.NewChecker(
+		logger,
+		repConfig.CellID,
+		executorClient,
+		bbsClient,
+		metronClient,
+		clock,
+		time.Duration(repConfig.ConsistencyCheckInterval),
+		repConfig.ConsistencyRepair,
+	)
 }
 
-func initializeBBSClient(
-	logger lager.Logger,
-	repConfig config.RepConfig,
-) bbs.InternalClient {
-	bbsClient, err := bbs.NewClientWithConfig(bbs.ClientConfig{
-		URL:                    repConfig.BBSAddress,
-		IsTLS:                  true,
-		CAFile:                 repConfig.CaCertFile,
-		CertFile:               repConfig.CertFile,
-		KeyFile:                repConfig.KeyFile,
-		ClientSessionCacheSize: repConfig.BBSClientSessionCacheSize,
-		MaxIdleConnsPerHost:    repConfig.BBSMaxIdleConnsPerHost,
-		RequestTimeout:         time.Duration(repConfig.CommunicationTimeout),
-	})
-	if err != nil {
-		logger.Fatal("failed-to-configure-secure-BBS-client", err)
+// initializeImageStores returns the GrootFS stores of the rootfs providers.
+// Their volumes are destroyed by the overlay-xfs driver of GrootFS, which
+// needs no tardis to destroy volumes.
+func initializeImageStores(repConfig config.RepConfig) map[string]imagecache.Store {
+	stores := map[string]imagecache.Store{}
+	for provider, path := range repConfig.RootFSImageStores {
+		stores[provider] = imagecache.NewGrootFSStore(path, overlayxfs.NewDriver(path, "", 0))
 	}
-	return bbsClient
+	return stores
 }
 
-func repHost(cellID string) string {
-	return strings.Replace(cellID, "_", "-", -1)
+const defaultDiskQuotaGrowthTimeout = 10 * time.Second
+
+// diskQuotaGrower returns nil unless a file to save the growth to and image
+// stores holding the images of the containers are configured, in which case
+// the cell refuses to grow disk quotas.
+func diskQuotaGrower(repConfig config.RepConfig, osFamily string) auctioncellrep.DiskQuotaGrower {
+	if repConfig.DiskQuotaGrowthFile == "" || len(repConfig.RootFSImageStores) == 0 || osFamily == rep.OSFamilyWindows {
+		return nil
+	}
+
+	xfsIOPath := repConfig.XFSIOPath
+	if xfsIOPath == "" {
+		xfsIOPath = "xfs_io"
+	}
+	xfsQuotaPath := repConfig.XFSQuotaPath
+	if xfsQuotaPath == "" {
+		xfsQuotaPath = "xfs_quota"
+	}
+
+	storePaths := make([]string, 0, len(repConfig.RootFSImageStores))
+	for _, path := range repConfig.RootFSImageStores {
+		storePaths = append(storePaths, path)
+	}
+	sort.Strings(storePaths)
+	return diskquota.NewXFSGrower(xfsIOPath, xfsQuotaPath, storePaths, defaultDiskQuotaGrowthTimeout)
 }
 
-func repBaseHostName(advertiseDomain string) string {
-	return strings.Split(advertiseDomain, ".")[0]
+// capacityReservations returns nil when capacity_reservation_max_ttl is not
+// configured, in which case the cell rejects capacity reservations.
+func capacityReservations(repConfig config.RepConfig, clock clock.Clock) *auctioncellrep.CapacityReservations {
+	if repConfig.CapacityReservationMaxTTL <= 0 {
+		return nil
+	}
+	return auctioncellrep.NewCapacityReservations(clock, time.Duration(repConfig.CapacityReservationMaxTTL))
 }
 
-func repURL(config config.RepConfig) string {
-	port := strings.Split(config.ListenAddrSecurable, ":")[1]
-	return fmt.Sprintf("https://%s.%s:%s", repHost(config.CellID), config.AdvertiseDomain, port)
+// initializeCrashLoopDetector returns nil when no crash limit is configured,
+// so crashing instances are never quarantined.
+func initializeCrashLoopDetector(repConfig config.RepConfig, clock clock.Clock) crashloop.Detector {
+	if repConfig.CrashLoopMaxCrashes <= 0 {
+		return nil
+	}
+	return crashloop.New(
+		clock,
+		repConfig.CrashLoopMaxCrashes,
+		time.Duration(repConfig.CrashLoopWindow),
+		time.Duration(repConfig.CrashLoopQuarantineDuration),
+	)
 }
 
-func repAddress(logger lager.Logger, config config.RepConfig) string {
-	ip, err := localip.LocalIP()
-	if err != nil {
-		logger.Fatal("failed-to-fetch-ip", err)
+// placementPolicy returns nil unless a policy file is configured, in which
+// case the cell admits only the work the rules of the policy do not deny.
+func placementPolicy(repConfig config.RepConfig) (placementpolicy.Policy, error) {
+	if repConfig.PlacementPolicyPath == "" {
+		return nil, nil
 	}
 
-	listenAddress := config.ListenAddr
+	policy, err := placementpolicy.LoadRules(repConfig.PlacementPolicyPath)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+const defaultClockSkewTolerance = time.Second
+
+// clockSkewMonitor measures the deadlines callers give the cell, emitting the
+// skew between their clocks and the cell's when it exceeds the tolerance.
+func clockSkewMonitor(repConfig config.RepConfig, metronClient loggingclient.IngressClient, clock clock.Clock) *clockskew.Monitor {
+	tolerance := time.Duration(repConfig.ClockSkewTolerance)
+	if tolerance <= 0 {
+		tolerance = defaultClockSkewTolerance
+	}
+	return clockskew.NewMonitor(metronClient, clock, tolerance)
+}
+
+const defaultTaskCompletionFlushInterval = time.Second
+
+// initializeTaskCompleter completes each task on the BBS as soon as it
+// finishes unless a batch size is configured. Completions are then batched,
+// and the returned batcher has to run for them to reach the BBS.
+func initializeTaskCompleter(logger lager.Logger, repConfig config.RepConfig, bbsClient bbs.InternalClient, clock clock.Clock) (taskcompletion.Completer, *taskcompletion.Batcher) {
+	bbsCompleter := taskcompletion.NewBBSCompleter(bbsClient, repConfig.CellID)
+	if repConfig.TaskCompletionBatchSize <= 0 {
+		return bbsCompleter, nil
+	}
+
+	flushInterval := time.Duration(repConfig.TaskCompletionFlushInterval)
+	if flushInterval <= 0 {
+		flushInterval = defaultTaskCompletionFlushInterval
+	}
+
+	batcher := taskcompletion.NewBatcher(
+		logger,
+		bbsCompleter,
+		clock,
+		repConfig.TaskCompletionBatchSize,
+		flushInterval,
+		repConfig.TaskCompletionMaxAttempts,
+	)
+	return batcher, batcher
+}
+
+// logRateLimiter returns nil unless the cell limits the rate of the log
+// output of every container.
+func logRateLimiter(repConfig config.RepConfig, metronClient loggingclient.IngressClient, clock clock.Clock) *logratelimit.Limiter {
+	if repConfig.LogRateLimitBytesPerSecond <= 0 {
+		return nil
+	}
+	return logratelimit.NewLimiter(metronClient, repConfig.LogRateLimitBytesPerSecond, clock)
+}
+
+// saturationGauges returns the gauges of the queues the rep runs: the
+// operations waiting to be run on containers and those running, the
+// operations waiting while the cell evacuates, which are those of the
+// evacuation, and, when they are enabled, the performs waiting for their
+// turn and the task completions waiting to be reported. The operations of
+// containers run at once with no bound, so they have no utilization. Images
+// are pulled by garden as it creates each container, with no queue the rep
+// can observe, so there is no image pull queue gauge.
+func saturationGauges(operations *harmonizer.PendingQueue, evacuationReporter evacuation_context.EvacuationReporter, performQueue fairqueue.Queue, batcher *taskcompletion.Batcher) map[string]saturation.Gauge {
+	gauges := map[string]saturation.Gauge{
+		"OperationQueueDepth": operations.Pending,
+		"OperationsExecuting": operations.Executing,
+		"EvacuationQueueDepth": func() int {
+			if !evacuationReporter.Evacuating() {
+				return 0
+			}
+			return operations.Pending()
+		},
+	}
+	if performQueue != nil {
+		gauges["PerformQueueDepth"] = func() int { return performQueue.Stats().Waiting }
+		gauges["PerformQueueInFlight"] = func() int { return performQueue.Stats().InFlight }
+		gauges["PerformQueueUtilization"] = func() int {
+			stats := performQueue.Stats()
+			return saturation.Utilization(stats.InFlight, stats.MaxInFlight)
+		}
+	}
+	if batcher != nil {
+		gauges["TaskCompletionQueueDepth"] = batcher.Pending
+	}
+	return gauges
+}
+
+// checkPreloadedRootFSes checks that the preloaded rootfses of the cell and of
+// its executor backends are absolute and resolve within the preloaded rootfs
+// dir, when one is configured.
+func checkPreloadedRootFSes(repConfig config.RepConfig) error {
+	if repConfig.PreloadedRootFSDir == "" {
+		return nil
+	}
+
+	err := repConfig.PreloadedRootFS.CheckContainedIn(repConfig.PreloadedRootFSDir)
+	if err != nil {
+		return err
+	}
+	for _, backendConfig := range repConfig.ExecutorBackends {
+		err = backendConfig.PreloadedRootFS.CheckContainedIn(repConfig.PreloadedRootFSDir)
+		if err != nil {
+			return fmt.Errorf("executor backend %s: %w", backendConfig.Name, err)
+		}
+	}
+	return nil
+}
+
+const (
+	defaultCordonPollInterval = 10 * time.Second
+	defaultCordonFetchTimeout = 5 * time.Second
+)
+
+// initializeCordonWatcher returns nil unless a cordon locket key prefix is
+// configured. The cell is then cordoned while locket holds the resource whose
+// key is the prefix followed by the cell id.
+func initializeCordonWatcher(logger lager.Logger, repConfig config.RepConfig, clock clock.Clock) *cordon.Watcher {
+	if repConfig.CordonLocketKeyPrefix == "" {
+		return nil
+	}
+
+	locketClient, err := locket.NewClient(logger, repConfig.ClientLocketConfig)
+	if err != nil {
+		logger.Fatal("failed-to-construct-locket-client", err)
+	}
+
+	interval := time.Duration(repConfig.CordonPollInterval)
+	if interval <= 0 {
+		interval = defaultCordonPollInterval
+	}
+	timeout := time.Duration(repConfig.CordonFetchTimeout)
+	if timeout <= 0 {
+		timeout = defaultCordonFetchTimeout
+	}
+	return cordon.NewWatcher(logger, locketClient, repConfig.CordonLocketKeyPrefix+repConfig.CellID, clock, interval, timeout)
+}
+
+// cordonReporter keeps a nil watcher from becoming a non-nil reporter.
+func cordonReporter(watcher *cordon.Watcher) cordon.Reporter {
+	if watcher == nil {
+		return nil
+	}
+	return watcher
+}
+
+// maintenanceSchedule returns nil when no maintenance windows are configured.
+func maintenanceSchedule(repConfig config.RepConfig, clock clock.Clock) (*auctioncellrep.MaintenanceSchedule, error) {
+	if len(repConfig.MaintenanceWindows) == 0 {
+		return nil, nil
+	}
+
+	windows := make([]rep.MaintenanceWindow, 0, len(repConfig.MaintenanceWindows))
+	for _, window := range repConfig.MaintenanceWindows {
+		if window.Start.IsZero() || window.Duration <= 0 {
+			return nil, fmt.Errorf("maintenance window needs a start and a positive duration: %+v", window)
+		}
+		windows = append(windows, rep.MaintenanceWindow{
+			Start: window.Start,
+			End:   window.Start.Add(time.Duration(window.Duration)),
+		})
+	}
+
+	return auctioncellrep.NewMaintenanceSchedule(
+		clock,
+		windows,
+		time.Duration(repConfig.MaintenanceWindowLeadTime),
+		repConfig.MaintenanceScorePenalty,
+	), nil
+}
+
+// rootFSUsageReader returns nil when no image stores are configured, so the
+// cell does not report the disk usage of its rootfs providers.
+func rootFSUsageReader(stores map[string]imagecache.Store) imagecache.UsageReader {
+	if len(stores) == 0 {
+		return nil
+	}
+	return imagecache.NewUsageReader(stores)
+}
+
+const (
+	defaultImageSizeCheckTimeout  = 5 * time.Second
+	defaultImageSizeCheckCacheTTL = 10 * time.Minute
+)
+
+// imageSizeChecker returns nil unless image size checks are enabled and
+// image stores are configured to check the images against.
+func imageSizeChecker(repConfig config.RepConfig, stores map[string]imagecache.Store, clock clock.Clock) imagecache.SizeChecker {
+	if !repConfig.ImageSizeCheckEnabled || len(stores) == 0 {
+		return nil
+	}
+
+	timeout := time.Duration(repConfig.ImageSizeCheckTimeout)
+	if timeout == 0 {
+		timeout = defaultImageSizeCheckTimeout
+	}
+	cacheTTL := time.Duration(repConfig.ImageSizeCheckCacheTTL)
+	if cacheTTL == 0 {
+		cacheTTL = defaultImageSizeCheckCacheTTL
+	}
+
+	source := imagecache.NewRegistryLayerSource(timeout, repConfig.InsecureImageRegistries)
+	return imagecache.NewSizeChecker(stores, source, clock, timeout, cacheTTL)
+}
+
+// imageDigestPolicy returns nil unless the cell requires docker images to be
+// referenced by digest.
+func imageDigestPolicy(repConfig config.RepConfig, metronClient loggingclient.IngressClient) imagecache.DigestPolicy {
+	if !repConfig.RequireImageDigests {
+		return nil
+	}
+	return imagecache.NewDigestPolicy(metronClient)
+}
+
+const defaultPerformMaxQueuedPerCaller = 32
+
+// initializePerformQueue returns nil when perform_max_in_flight is not
+// configured, in which case the work of all callers is performed as it
+// arrives.
+func initializePerformQueue(repConfig config.RepConfig, metronClient loggingclient.IngressClient) fairqueue.Queue {
+	if repConfig.PerformMaxInFlight <= 0 {
+		return nil
+	}
+
+	maxQueuedPerCaller := repConfig.PerformMaxQueuedPerCaller
+	if maxQueuedPerCaller <= 0 {
+		maxQueuedPerCaller = defaultPerformMaxQueuedPerCaller
+	}
+	return fairqueue.New(repConfig.PerformMaxInFlight, maxQueuedPerCaller, metronClient)
+}
+
+// cellInfoReporter reports the feature flags the rep is currently running
+// with, so the info follows the flags as they are reloaded.
+func cellInfoReporter(repConfig config.RepConfig, featureFlags *featureflags.Flags) handlers.InfoReporter {
+	limits := rep.Limits{
+		MaxRequestBodyBytes: repConfig.MaxRequestBodyBytes,
+		MaxWorkBatchSize:    repConfig.MaxWorkBatchSize,
+	}
+	return handlers.InfoReporterFunc(func() rep.Info {
+		return rep.NewInfo(featureFlags.EnabledFlags(), limits)
+	})
+}
+
+// imageCachePruner returns nil when no image stores are configured, in which
+// case the prune endpoint reports that pruning is not configured.
+func imageCachePruner(repConfig config.RepConfig, stores map[string]imagecache.Store, metronClient loggingclient.IngressClient) imagecache.Pruner {
+	if len(stores) == 0 {
+		return nil
+	}
+
+	policy := imagecache.Policy{
+		MinFreeDiskMB: repConfig.ImageCacheMinFreeDiskMB,
+		PinnedVolumes: repConfig.ImageCachePinnedVolumes,
+	}
+	return imagecache.NewPruner(stores, policy, metronClient)
+}
+
+// downloadCacheTracker returns nil unless an interval to observe the executor
+// download cache at is configured, in which case the cache stats endpoint
+// reports that the statistics are not configured.
+func downloadCacheTracker(repConfig config.RepConfig, executorClient executor.Client, metronClient loggingclient.IngressClient) downloadcache.Tracker {
+	if repConfig.DownloadCacheStatsInterval <= 0 || repConfig.ExecutorConfig.CachePath == "" {
+		return nil
+	}
+	return downloadcache.NewTracker(downloadcache.NewDir(repConfig.ExecutorConfig.CachePath), executorClient, metronClient)
+}
+
+const defaultLoadBalancerDrainTimeout = 30 * time.Second
+
+// loadBalancerDeregisterers builds the hooks that remove the cell's LRP
+// instances from external load balancers before it evacuates.
+func loadBalancerDeregisterers(repConfig config.RepConfig, timeout time.Duration, clock clock.Clock) []loadbalancer.Deregisterer {
+	client := &http.Client{Timeout: timeout}
+
+	var deregisterers []loadbalancer.Deregisterer
+	if repConfig.LoadBalancerWebhookURL != "" {
+		deregisterers = append(deregisterers, loadbalancer.NewWebhookDeregisterer(client, repConfig.LoadBalancerWebhookURL, repConfig.CellID))
+	}
+	if len(repConfig.LoadBalancerAWSTargetGroups) > 0 {
+		deregisterers = append(deregisterers, loadbalancer.NewAWSTargetGroupDeregisterer(
+			client,
+			repConfig.LoadBalancerAWSEndpoint,
+			repConfig.LoadBalancerAWSRegion,
+			repConfig.LoadBalancerAWSTargetGroups,
+			loadbalancer.AWSCredentials{
+				AccessKeyID:     repConfig.LoadBalancerAWSAccessKeyID,
+				SecretAccessKey: repConfig.LoadBalancerAWSSecretKey,
+				SessionToken:    repConfig.LoadBalancerAWSSessionToken,
+			},
+			clock,
+		))
+	}
+	return deregisterers
+}
+
+// connectToLifecycleHintsNATS connects in the background, so that the rep
+// starts even when NATS is unavailable; hints are dropped until it connects.
+func connectToLifecycleHintsNATS(logger lager.Logger, repConfig config.RepConfig) (*nats.Conn, error) {
+	logger = logger.Session("lifecycle-hints-nats")
+
+	return nats.Connect(
+		strings.Join(repConfig.LifecycleHintsNATSAddresses, ","),
+		nats.UserInfo(repConfig.LifecycleHintsNATSUsername, repConfig.LifecycleHintsNATSPassword),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Error("disconnected", err)
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("reconnected", lager.Data{"url": conn.ConnectedUrl()})
+		}),
+	)
+}
+
+const defaultKubernetesNodeSyncInterval = 30 * time.Second
+
+// initializeNodeShim mirrors the cell state into the Node of the kubelet the
+// cell is co-located with, for platforms scheduling onto both.
+func initializeNodeShim(logger lager.Logger, repConfig config.RepConfig, stateReporter nodeshim.StateReporter, clock clock.Clock) (*nodeshim.Shim, error) {
+	if repConfig.KubernetesAPIURL == "" {
+		return nil, errors.New("kubernetes_api_url is required when kubernetes_node_name is set")
+	}
+
+	var tlsOptions []tlsconfig.ClientOption
+	if repConfig.KubernetesCACertFile != "" {
+		tlsOptions = append(tlsOptions, tlsconfig.WithAuthorityFromFile(repConfig.KubernetesCACertFile))
+	}
+	tlsConfig, err := tlsconfig.Build(tlsconfig.WithInternalServiceDefaults()).Client(tlsOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(repConfig.KubernetesNodeSyncInterval)
+	if interval == 0 {
+		interval = defaultKubernetesNodeSyncInterval
+	}
+
+	httpClient := &http.Client{
+		Timeout:   interval,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	client := nodeshim.NewAPIClient(httpClient, repConfig.KubernetesAPIURL, repConfig.KubernetesTokenFile)
+	return nodeshim.NewShim(logger, stateReporter, client, repConfig.KubernetesNodeName, clock, interval), nil
+}
+
+const (
+	defaultProxyReadinessCheckInterval = 500 * time.Millisecond
+	defaultProxyReadinessTimeout       = 30 * time.Second
+)
+
+// proxyReadinessWaiter returns nil unless a readiness endpoint or directory
+// is configured, in which case LRP instances are only reported as running
+// once their proxy is ready.
+func proxyReadinessWaiter(repConfig config.RepConfig, clock clock.Clock) proxyreadiness.Waiter {
+	interval := time.Duration(repConfig.ProxyReadinessCheckInterval)
+	if interval == 0 {
+		interval = defaultProxyReadinessCheckInterval
+	}
+	timeout := time.Duration(repConfig.ProxyReadinessTimeout)
+	if timeout == 0 {
+		timeout = defaultProxyReadinessTimeout
+	}
+
+	var checker proxyreadiness.Checker
+	switch {
+	case repConfig.ProxyReadinessCheckDir != "":
+		checker = proxyreadiness.NewFileChecker(repConfig.ProxyReadinessCheckDir)
+	case repConfig.ProxyReadinessCheckPort != 0:
+		checker = proxyreadiness.NewHTTPChecker(&http.Client{Timeout: interval}, repConfig.ProxyReadinessCheckPort, repConfig.ProxyReadinessCheckPath)
+	default:
+		return nil
+	}
+	return proxyreadiness.NewWaiter(checker, clock, interval, timeout)
+}
+
+const maxRecentLRPs = 1000
+
+const defaultUsageForecastHorizon = 5 * time.Minute
+
+const (
+	defaultSupervisorMinBackoff = time.Second
+	defaultSupervisorMaxBackoff = time.Minute
+)
+
+const defaultContainerEventsMaxContainers = 1000
+
+func recentLRPTracker(repConfig config.RepConfig, clock clock.Clock) *auctioncellrep.RecentLRPTracker {
+	if repConfig.RecentLRPRetention == 0 {
+		return nil
+	}
+	return auctioncellrep.NewRecentLRPTracker(clock, time.Duration(repConfig.RecentLRPRetention), maxRecentLRPs)
+}
+
+// placementHistory returns nil unless a capacity report retention is
+// configured.
+func placementHistory(repConfig config.RepConfig, clock clock.Clock) *auctioncellrep.PlacementHistory {
+	if repConfig.CapacityReportRetentionHours <= 0 {
+		return nil
+	}
+	return auctioncellrep.NewPlacementHistory(clock, repConfig.CapacityReportRetentionHours)
+}
+
+func usageForecaster(repConfig config.RepConfig, metricsProvider rep.ContainerMetricsProvider, clock clock.Clock) *auctioncellrep.UsageForecaster {
+	if repConfig.UsageForecastInterval == 0 {
+		return nil
+	}
+	horizon := time.Duration(repConfig.UsageForecastHorizon)
+	if horizon == 0 {
+		horizon = defaultUsageForecastHorizon
+	}
+	return auctioncellrep.NewUsageForecaster(metricsProvider, clock, time.Duration(repConfig.UsageForecastInterval), horizon)
+}
+
+// supervisorFor returns a func wrapping a runner in a supervisor that
+// restarts it when it panics.
+func supervisorFor(logger lager.Logger, repConfig config.RepConfig, metronClient loggingclient.IngressClient, clock clock.Clock) func(string, ifrit.Runner) ifrit.Runner {
+	minBackoff := time.Duration(repConfig.SupervisorMinBackoff)
+	if minBackoff == 0 {
+		minBackoff = defaultSupervisorMinBackoff
+	}
+	maxBackoff := time.Duration(repConfig.SupervisorMaxBackoff)
+	if maxBackoff == 0 {
+		maxBackoff = defaultSupervisorMaxBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+
+	return func(name string, runner ifrit.Runner) ifrit.Runner {
+		return supervisor.New(logger, name, runner, metronClient, clock, minBackoff, maxBackoff)
+	}
+}
+
+func tenantCaps(repConfig config.RepConfig) *rep.TenantCaps {
+	if repConfig.TenantMaxMemoryMB == 0 && repConfig.TenantMaxDiskMB == 0 && repConfig.TenantMaxContainers == 0 {
+		return nil
+	}
+	return &rep.TenantCaps{
+		MemoryMB:   repConfig.TenantMaxMemoryMB,
+		DiskMB:     repConfig.TenantMaxDiskMB,
+		Containers: repConfig.TenantMaxContainers,
+	}
+}
+
+const (
+	defaultCgroupRoot             = "/sys/fs/cgroup"
+	defaultCgroupContainersParent = "garden"
+)
+
+// cgroupInspector returns nil on cells without cgroups.
+func cgroupInspector(repConfig config.RepConfig, osFamily string) hostmetrics.CgroupInspector {
+	if osFamily == rep.OSFamilyWindows {
+		return nil
+	}
+	root := repConfig.CgroupRoot
+	if root == "" {
+		root = defaultCgroupRoot
+	}
+	parent := repConfig.CgroupContainersParent
+	if parent == "" {
+		parent = defaultCgroupContainersParent
+	}
+	return hostmetrics.NewCgroupInspector(root, parent)
+}
+
+// hostCgroups returns nil when the cgroups of the host cannot be detected,
+// so that the cell reports nothing rather than a wrong version.
+func hostCgroups(logger lager.Logger, inspector hostmetrics.CgroupInspector) *rep.CgroupInfo {
+	if inspector == nil {
+		return nil
+	}
+	info, err := inspector.Host(logger.Session("detect-cgroups"))
+	if err != nil {
+		return nil
+	}
+	logger.Info("detected-cgroups", lager.Data{"version": info.Version, "hybrid": info.Hybrid})
+	return &info
+}
+
+const defaultIOLimitsPollInterval = time.Second
+
+// initializeIOThrottler returns nil unless the cell is configured with the
+// device to throttle the disk I/O of its containers on and runs on cgroups.
+// The containers of every executor backend are throttled.
+func initializeIOThrottler(logger lager.Logger, repConfig config.RepConfig, cgroupInfo *rep.CgroupInfo, executorClient executor.Client, backends []auctioncellrep.Backend, clock clock.Clock) *iothrottle.Throttler {
+	if repConfig.IOLimitsDevice == "" || cgroupInfo == nil {
+		return nil
+	}
+	root := repConfig.CgroupRoot
+	if root == "" {
+		root = defaultCgroupRoot
+	}
+	parent := repConfig.CgroupContainersParent
+	if parent == "" {
+		parent = defaultCgroupContainersParent
+	}
+	pollInterval := time.Duration(repConfig.IOLimitsPollInterval)
+	if pollInterval <= 0 {
+		pollInterval = defaultIOLimitsPollInterval
+	}
+	executorClients := []executor.Client{executorClient}
+	for _, backend := range backends {
+		executorClients = append(executorClients, backend.Client)
+	}
+	applier := iothrottle.NewCgroupApplier(root, parent, repConfig.IOLimitsDevice, cgroupInfo.Version)
+	return iothrottle.NewThrottler(logger, executorClients, applier, clock, pollInterval)
+}
+
+const defaultRequestRecordingMaxBytes = 64 * 1024 * 1024
+
+// initializeRequestRecorder returns nil unless a request recording path is
+// configured, so that the requests of the auctioneer are only recorded on
+// cells where an operator asked for them.
+func initializeRequestRecorder(logger lager.Logger, repConfig config.RepConfig, clock clock.Clock) (*handlers.RequestRecorder, error) {
+	if repConfig.RequestRecordingPath == "" {
+		return nil, nil
+	}
+	maxBytes := repConfig.RequestRecordingMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultRequestRecordingMaxBytes
+	}
+	return handlers.NewRequestRecorder(logger, repConfig.RequestRecordingPath, maxBytes, clock)
+}
+
+// ioLimitReporter keeps a nil throttler from becoming a non-nil reporter.
+func ioLimitReporter(throttler *iothrottle.Throttler) auctioncellrep.IOLimitReporter {
+	if throttler == nil {
+		return nil
+	}
+	return throttler
+}
+
+const (
+	defaultSelfTestTimeout = time.Minute
+	selfTestPollInterval   = 100 * time.Millisecond
+)
+
+// selfTester returns nil unless the cell has a preloaded rootfs to run the
+// self test container with, the configured one or else the first one.
+func selfTester(logger lager.Logger, repConfig config.RepConfig, osFamily string, executorClient executor.Client, clock clock.Clock) handlers.SelfTester {
+	if osFamily == rep.OSFamilyWindows || len(repConfig.PreloadedRootFS) == 0 {
+		return nil
+	}
+
+	stack := repConfig.SelfTestRootFS
+	if stack == "" {
+		stack = repConfig.PreloadedRootFS[0].Name
+	}
+	rootFSPath, ok := repConfig.PreloadedRootFS.StackPathMap()[stack]
+	if !ok {
+		logger.Error("self-test-root-fs-not-preloaded", rep.ErrPreloadedRootFSNotFound, lager.Data{"stack": stack})
+		return nil
+	}
+
+	timeout := time.Duration(repConfig.SelfTestTimeout)
+	if timeout == 0 {
+		timeout = defaultSelfTestTimeout
+	}
+	return selftest.NewTester(executorClient, auctioncellrep.GenerateGuid, rootFSPath, clock, selfTestPollInterval, timeout)
+}
+
+// adaptivePollingInterval returns nil unless a minimum or maximum polling
+// interval is configured. A missing bound defaults to the polling interval.
+func adaptivePollingInterval(repConfig config.RepConfig, queue *harmonizer.PendingQueue) (*harmonizer.AdaptiveInterval, error) {
+	if repConfig.PollingMinInterval == 0 && repConfig.PollingMaxInterval == 0 {
+		return nil, nil
+	}
+
+	min := time.Duration(repConfig.PollingMinInterval)
+	if min == 0 {
+		min = time.Duration(repConfig.PollingInterval)
+	}
+	max := time.Duration(repConfig.PollingMaxInterval)
+	if max == 0 {
+		max = time.Duration(repConfig.PollingInterval)
+	}
+	return harmonizer.NewAdaptiveInterval(min, max, queue)
+}
+
+func containerEventHistory(repConfig config.RepConfig, clock clock.Clock) containerevents.History {
+	if repConfig.ContainerEventsPerContainer == 0 {
+		return nil
+	}
+	maxContainers := repConfig.ContainerEventsMaxContainers
+	if maxContainers == 0 {
+		maxContainers = defaultContainerEventsMaxContainers
+	}
+	return containerevents.NewHistory(clock, repConfig.ContainerEventsPerContainer, maxContainers)
+}
+
+func cellOSFamily(repConfig config.RepConfig) (string, error) {
+	switch repConfig.OSFamily {
+	case "", rep.OSFamilyLinux:
+		return rep.OSFamilyLinux, nil
+	case rep.OSFamilyWindows:
+		return rep.OSFamilyWindows, nil
+	}
+	return "", fmt.Errorf("unknown os family: %q", repConfig.OSFamily)
+}
+
+// initializeExecutorBackends initializes the executors of the cell's
+// additional backends. Events from each backend are harmonized with the BBS
+// through their own generator.
+func initializeExecutorBackends(
+	logger lager.Logger,
+	repConfig config.RepConfig,
+	bbsClient bbs.InternalClient,
+	metronClient loggingclient.IngressClient,
+	evacuationReporter evacuation_context.EvacuationReporter,
+	hintPublisher lifecyclehints.Publisher,
+	crashLoopDetector crashloop.Detector,
+	healthCheckRelaxer healthchecks.Relaxer,
+	workGroupHolds *rep.WorkGroupHolds,
+	taskCompleter taskcompletion.Completer,
+	featureFlags *featureflags.Flags,
+	clock clock.Clock,
+) ([]auctioncellrep.Backend, grouper.Members, error) {
+	if len(repConfig.ExecutorBackends) == 0 {
+		return nil, nil, nil
+	}
+
+	backends := []auctioncellrep.Backend{}
+	members := grouper.Members{}
+
+	for _, backendConfig := range repConfig.ExecutorBackends {
+		if backendConfig.Name == "" || backendConfig.Name == auctioncellrep.DefaultBackendName {
+			return nil, nil, fmt.Errorf("invalid executor backend name: %q", backendConfig.Name)
+		}
+
+		backendLogger := logger.Session("executor-backend", lager.Data{"backend": backendConfig.Name})
+		stackPathMap := backendConfig.PreloadedRootFS.StackPathMap()
+
+		client, metricsProvider, executorMembers, err := executorinit.Initialize(backendLogger, backendConfig.ExecutorConfig, repConfig.CellID, repConfig.Zone, stackPathMap, metronClient, clock)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		backends = append(backends, auctioncellrep.NewBackend(
+			backendConfig.Name,
+			client,
+			metricsProvider,
+			stackPathMap,
+			backendConfig.SupportedProviders,
+			auctioncellrep.NewContainerAllocator(auctioncellrep.GenerateGuid, stackPathMap, client),
+		))
+
+		for _, member := range executorMembers {
+			members = append(members, grouper.Member{Name: backendConfig.Name + "-" + member.Name, Runner: member.Runner})
+		}
+
+		backendGenerator := generator.New(
+			repConfig.CellID,
+			stackPathMap,
+			repConfig.LayeringMode,
+			bbsClient,
+			client,
+			metronClient,
+			evacuationReporter,
+			proxyReadinessWaiter(repConfig, clock),
+			hintPublisher,
+			crashLoopDetector,
+			healthCheckRelaxer,
+			workGroupHolds,
+			taskCompleter,
+			nil,
+			featureFlags,
+		)
+		members = append(members, grouper.Member{
+			Name:   backendConfig.Name + "-event-consumer",
+			Runner: harmonizer.NewEventConsumer(backendLogger, backendGenerator, operationq.NewSlidingQueue(1)),
+		})
+		members = append(members, grouper.Member{
+			Name:   backendConfig.Name + "-health-check-restorer",
+			Runner: healthchecks.NewRestorer(backendLogger, client, healthCheckRelaxer, clock, healthCheckRestoreInterval),
+		})
+	}
+
+	return backends, members, nil
+}
+
+func initializeCellPresence(
+	address string,
+	executorClient executor.Client,
+	logger lager.Logger,
+	repConfig config.RepConfig,
+	preloadedRootFSes []string,
+	repUrl string,
+	handoff *presence.Handoff,
+	status *presence.Status,
+	metronClient loggingclient.IngressClient,
+) ifrit.Runner {
+	locketClient, err := locket.NewClient(logger, repConfig.ClientLocketConfig)
+	if err != nil {
+		logger.Fatal("failed-to-construct-locket-client", err)
+	}
+
+	resources, err := executorClient.TotalResources(logger)
+	if err != nil {
+		logger.Fatal("failed-to-get-total-resources", err)
+	}
+	cellCapacity := models.NewCellCapacity(int32(resources.MemoryMB), int32(resources.DiskMB), int32(resources.Containers))
+	cellPresence := models.NewCellPresence(repConfig.CellID, address, repUrl,
+		repConfig.Zone, cellCapacity, repConfig.SupportedProviders,
+		preloadedRootFSes, cellPlacementTags(repConfig).Required(), repConfig.OptionalPlacementTags)
+
+	payload, err := json.Marshal(cellPresence)
+	if err != nil {
+		logger.Fatal("failed-to-encode-cell-presence", err)
+	}
+
+	lockPayload := &locketmodels.Resource{
+		Key:      repConfig.CellID,
+		Owner:    handoff.Owner(),
+		Value:    string(payload),
+		TypeCode: locketmodels.PRESENCE,
+		Type:     locketmodels.PresenceType,
+	}
+
+	logger.Debug("presence-payload", lager.Data{"payload": lockPayload})
+	renewalInterval := time.Duration(repConfig.LockRetryInterval)
+	if renewalInterval == 0 {
+		renewalInterval = locket.RetryInterval
+	}
+	renewalPolicy := presence.NewRenewalPolicy(
+		renewalInterval,
+		time.Duration(repConfig.LockMinRetryInterval),
+		time.Duration(repConfig.LockSlowRenewalThreshold),
+	)
+
+	presenceRunner := presence.NewLockRunner(
+		logger,
+		locketClient,
+		lockPayload,
+		int64(time.Duration(repConfig.LockTTL)/time.Second),
+		clock.NewClock(),
+		renewalPolicy,
+		metronClient,
+		status,
+	)
+	return presence.NewRunner(logger, presenceRunner, handoff)
+}
+
+func initializeServer(
+	logger lager.Logger,
+	routes rata.Routes,
+	routeHandlers rata.Handlers,
+	listenAddress string,
+	certFile string,
+	keyFile string,
+	caCertFile string,
+	requireLocalhostSAN bool,
+	listeners *standby.Listeners,
+	requestMetrics helpers.RequestMetrics,
+	clock clock.Clock,
+) ifrit.Runner {
+	router, err := rata.NewRouter(routes, handlers.TimeRequests(routeHandlers, requestMetrics, clock))
+	if err != nil {
+		logger.Fatal("failed-to-construct-router", err)
+	}
+
+	if requireLocalhostSAN {
+		err = verifyCertificate(certFile)
+		if err != nil {
+			logger.Fatal("tls-configuration-failed", err)
+		}
+	}
+
+	tlsConfig, err := tlsconfig.Build(
+		tlsconfig.WithInternalServiceDefaults(),
+		tlsconfig.WithIdentityFromFile(certFile, keyFile),
+	).Server(tlsconfig.WithClientAuthenticationFromFile(caCertFile))
+	if err != nil {
+		logger.Fatal("tls-configuration-failed", err)
+	}
+	return startTLSServer(listenAddress, router, tlsConfig, listeners, clock)
+}
+
+func startTLSServer(addr string, handler http.Handler, tlsConfig *tls.Config, listeners *standby.Listeners, clock clock.Clock) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		listener, err := listeners.Listen(addr)
+		if err != nil {
+			return err
+		}
+		listener = tls.NewListener(handlers.NewTimedListener(listener, clock), tlsConfig)
+		close(ready)
+		server := &http.Server{Handler: handler, ConnContext: handlers.RequestTimingContext}
+		go server.Serve(listener)
+		<-signals
+		return listener.Close()
+	})
+}
+
+// initializeGRPCServer serves the Rep service of server on the gRPC listen
+// address, with the mutual TLS of the secure server. Messages are bounded by
+// the maximum request body size, when one is configured.
+func initializeGRPCServer(logger lager.Logger, server *repgrpc.Server, repConfig config.RepConfig, listeners *standby.Listeners) ifrit.Runner {
+	tlsConfig, err := tlsconfig.Build(
+		tlsconfig.WithInternalServiceDefaults(),
+		tlsconfig.WithIdentityFromFile(repConfig.CertFile, repConfig.KeyFile),
+	).Server(tlsconfig.WithClientAuthenticationFromFile(repConfig.CaCertFile))
+	if err != nil {
+		logger.Fatal("tls-configuration-failed", err)
+	}
+
+	options := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}
+	if repConfig.MaxRequestBodyBytes > 0 {
+		options = append(options, grpc.MaxRecvMsgSize(int(repConfig.MaxRequestBodyBytes)))
+	}
+	grpcServer := grpc.NewServer(options...)
+	repgrpc.RegisterRepServer(grpcServer, server)
+
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		listener, err := listeners.Listen(repConfig.ListenAddrGRPC)
+		if err != nil {
+			return err
+		}
+		close(ready)
+		go grpcServer.Serve(listener)
+		<-signals
+		grpcServer.GracefulStop()
+		return nil
+	})
+}
+
+// inventorySaver returns nil when no warm standby inventory file is
+// configured, in which case no inventory is handed over with the listeners.
+func inventorySaver(logger lager.Logger, repConfig config.RepConfig, executorClient executor.Client) func() error {
+	if repConfig.WarmStandbyInventoryFile == "" {
+		return nil
+	}
+	return func() error {
+		return standby.SaveInventory(logger, executorClient, repConfig.WarmStandbyInventoryFile)
+	}
+}
+
+func initializeBBSClient(
+	logger lager.Logger,
+	repConfig config.RepConfig,
+) bbs.InternalClient {
+	bbsClient, err := bbs.NewClientWithConfig(bbs.ClientConfig{
+		URL:                    repConfig.BBSAddress,
+		IsTLS:                  true,
+		CAFile:                 repConfig.CaCertFile,
+		CertFile:               repConfig.CertFile,
+		KeyFile:                repConfig.KeyFile,
+		ClientSessionCacheSize: repConfig.BBSClientSessionCacheSize,
+		MaxIdleConnsPerHost:    repConfig.BBSMaxIdleConnsPerHost,
+		RequestTimeout:         time.Duration(repConfig.CommunicationTimeout),
+	})
+	if err != nil {
+		logger.Fatal("failed-to-configure-secure-BBS-client", err)
+	}
+	return bbsClient
+}
+
+func repHost(cellID string) string {
+	return strings.Replace(cellID, "_", "-", -1)
+}
+
+func repBaseHostName(advertiseDomain string) string {
+	return strings.Split(advertiseDomain, ".")[0]
+}
+
+func repURL(config config.RepConfig) string {
+	port := strings.Split(config.ListenAddrSecurable, ":")[1]
+	return fmt.Sprintf("https://%s.%s:%s", repHost(config.CellID), config.AdvertiseDomain, port)
+}
+
+func repAddress(logger lager.Logger, config config.RepConfig) string {
+	ip, err := localip.LocalIP()
+	if err != nil {
+		logger.Fatal("failed-to-fetch-ip", err)
+	}
+
+	listenAddress := config.ListenAddr
 	port := strings.Split(listenAddress, ":")[1]
 	return fmt.Sprintf("http://%s:%s", ip, port)
 }
 
+// initializeFeatureFlagsReloader reloads the feature flags from the config
+// file whenever the rep receives a SIGHUP.
+func initializeFeatureFlagsReloader(logger lager.Logger, featureFlags *featureflags.Flags, configHistory *config.ConfigHistory) ifrit.Runner {
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	return featureflags.NewReloader(logger, featureFlags, reload, func() (map[string]bool, error) {
+		reloaded, err := config.NewRepConfig(*configFilePath)
+		if err == nil {
+			err = featureflags.Validate(reloaded.FeatureFlags)
+		}
+		if err != nil {
+			configHistory.RecordFailure("reload", err)
+			return nil, err
+		}
+
+		configHistory.Update("reload", func(current *config.RepConfig) {
+			current.FeatureFlags = reloaded.FeatureFlags
+		})
+		return reloaded.FeatureFlags, nil
+	})
+}
+
+// initializePlacementTagsReloader reloads the placement tags and isolation
+// segment from the config file whenever the rep receives a SIGHUP.
+func initializePlacementTagsReloader(logger lager.Logger, auctionCellRep *auctioncellrep.AuctionCellRep, configHistory *config.ConfigHistory) ifrit.Runner {
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	return auctioncellrep.NewPlacementTagsReloader(logger, auctionCellRep, reload, func() (rep.CellPlacementTags, error) {
+		reloaded, err := config.NewRepConfig(*configFilePath)
+		if err != nil {
+			configHistory.RecordFailure("reload", err)
+			return rep.CellPlacementTags{}, err
+		}
+
+		configHistory.Update("reload", func(current *config.RepConfig) {
+			current.PlacementTags = reloaded.PlacementTags
+			current.OptionalPlacementTags = reloaded.OptionalPlacementTags
+			current.IsolationSegment = reloaded.IsolationSegment
+		})
+		return cellPlacementTags(reloaded), nil
+	})
+}
+
+func cellPlacementTags(repConfig config.RepConfig) rep.CellPlacementTags {
+	return rep.CellPlacementTags{
+		PlacementTags:         repConfig.PlacementTags,
+		OptionalPlacementTags: repConfig.OptionalPlacementTags,
+		IsolationSegment:      repConfig.IsolationSegment,
+	}
+}
+
+const defaultLifecycleBundleCheckTimeout = 5 * time.Second
+
+// initializeLifecycleCatalog returns nil when no lifecycle bundles are
+// configured, in which case the cell does not check the lifecycles work
+// requires. Otherwise the bundles are validated before the cell advertises
+// them.
+func initializeLifecycleCatalog(logger lager.Logger, repConfig config.RepConfig) lifecycles.Catalog {
+	if len(repConfig.LifecycleBundles) == 0 {
+		return nil
+	}
+
+	timeout := time.Duration(repConfig.LifecycleBundleCheckTimeout)
+	if timeout == 0 {
+		timeout = defaultLifecycleBundleCheckTimeout
+	}
+	catalog := lifecycles.NewCatalog(timeout)
+	catalog.Load(logger, lifecycleBundles(repConfig))
+	return catalog
+}
+
+// initializeLifecycleBundlesReloader validates the lifecycle bundles of the
+// config file again whenever the rep receives a SIGHUP.
+func initializeLifecycleBundlesReloader(logger lager.Logger, catalog lifecycles.Catalog, configHistory *config.ConfigHistory) ifrit.Runner {
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	return lifecycles.NewReloader(logger, catalog, reload, func() ([]lifecycles.Bundle, error) {
+		reloaded, err := config.NewRepConfig(*configFilePath)
+		if err != nil {
+			configHistory.RecordFailure("reload", err)
+			return nil, err
+		}
+
+		configHistory.Update("reload", func(current *config.RepConfig) {
+			current.LifecycleBundles = reloaded.LifecycleBundles
+		})
+		return lifecycleBundles(reloaded), nil
+	})
+}
+
+func lifecycleBundles(repConfig config.RepConfig) []lifecycles.Bundle {
+	bundles := make([]lifecycles.Bundle, 0, len(repConfig.LifecycleBundles))
+	for _, bundle := range repConfig.LifecycleBundles {
+		bundles = append(bundles, lifecycles.Bundle{
+			Name:     bundle.Name,
+			Version:  bundle.Version,
+			Location: bundle.Location,
+			Binaries: bundle.Binaries,
+		})
+	}
+	return bundles
+}
+
+const defaultIaaSMetadataTimeout = 5 * time.Second
+
+// discoverInstanceMetadata queries the configured IaaS metadata service.
+// Discovery is best effort: any failure is logged and an empty Metadata is
+// returned so that the statically configured values are used instead.
+func discoverInstanceMetadata(logger lager.Logger, repConfig config.RepConfig) iaasmetadata.Metadata {
+	if repConfig.IaaSMetadataProvider == "" {
+		return iaasmetadata.Metadata{}
+	}
+
+	logger = logger.Session("discover-instance-metadata", lager.Data{"provider": repConfig.IaaSMetadataProvider})
+
+	timeout := time.Duration(repConfig.IaaSMetadataTimeout)
+	if timeout == 0 {
+		timeout = defaultIaaSMetadataTimeout
+	}
+
+	provider, err := iaasmetadata.NewProvider(repConfig.IaaSMetadataProvider, &http.Client{Timeout: timeout}, repConfig.IaaSMetadataURL)
+	if err != nil {
+		logger.Error("invalid-provider", err)
+		return iaasmetadata.Metadata{}
+	}
+
+	metadata, err := provider.Discover(logger)
+	if err != nil {
+		logger.Error("failed-to-discover", err)
+		return iaasmetadata.Metadata{}
+	}
+
+	logger.Info("discovered", lager.Data{"metadata": metadata})
+	return metadata
+}
+
 func initializeMetron(logger lager.Logger, repConfig config.RepConfig) (loggingclient.IngressClient, error) {
 	client, err := loggingclient.NewIngressClient(repConfig.LoggregatorConfig)
 	if err != nil {
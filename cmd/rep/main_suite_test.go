@@ -42,6 +42,7 @@ var (
 	representativePath  string
 	serverPort          uint16
 	serverPortSecurable uint16
+	serverPortAdmin     uint16
 
 	bbsConfig        bbsconfig.BBSConfig
 	bbsBinPath       string
@@ -114,6 +115,8 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	Expect(err).NotTo(HaveOccurred())
 	serverPortSecurable, err = portAllocator.ClaimPorts(1)
 	Expect(err).NotTo(HaveOccurred())
+	serverPortAdmin, err = portAllocator.ClaimPorts(1)
+	Expect(err).NotTo(HaveOccurred())
 
 	dbName := fmt.Sprintf("diego_%d", GinkgoParallelProcess())
 
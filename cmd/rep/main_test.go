@@ -703,7 +703,7 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 								),
 							},
 						}
-						failed, err := repClient.Perform(logger, work)
+						failed, err := repClient.Perform(context.Background(), logger, work)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(failed.Tasks).To(HaveLen(0))
 					}
@@ -740,7 +740,7 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 
 			Context("State", func() {
 				It("returns the cell id and rep url in the state info", func() {
-					state, err := repClient.State(logger)
+					state, err := repClient.State(context.Background(), logger)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(state.CellID).To(Equal(cellID))
 					url := fmt.Sprintf("https://%s.cell.service.cf.internal:%d", cellID, serverPortSecurable)
@@ -769,13 +769,13 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 								),
 							},
 						}
-						failed, err := repClient.Perform(logger, work)
+						failed, err := repClient.Perform(context.Background(), logger, work)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(failed.LRPs).To(HaveLen(0))
 					})
 
 					It("returns the lrp info ", func() {
-						state, err := repClient.State(logger)
+						state, err := repClient.State(context.Background(), logger)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(state.LRPs).To(HaveLen(1))
 						lrp := state.LRPs[0]
@@ -790,7 +790,7 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 
 			Context("Capacity with a container", func() {
 				It("returns total capacity and state information", func() {
-					state, err := repClient.State(logger)
+					state, err := repClient.State(context.Background(), logger)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(state.TotalResources).To(Equal(rep.Resources{
 						MemoryMB:   1024,
@@ -807,7 +807,7 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 						fakeGarden.RouteToHandler("GET", "/containers/bulk_info", ghttp.RespondWithJSONEncoded(http.StatusOK, struct{}{}))
 
 						Eventually(func() rep.Resources {
-							state, err := repClient.State(logger)
+							state, err := repClient.State(context.Background(), logger)
 							Expect(err).NotTo(HaveOccurred())
 							return state.AvailableResources
 						}).Should(Equal(rep.Resources{
@@ -949,6 +949,36 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 			})
 		})
 
+		It("serves the admin routes on the locally accessible server", func() {
+			resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/debug/config", serverPort))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		Context("when an admin listen address is configured", func() {
+			BeforeEach(func() {
+				repConfig.ListenAddrAdmin = fmt.Sprintf("127.0.0.1:%d", serverPortAdmin)
+			})
+
+			It("serves the admin routes on the admin server", func() {
+				resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/debug/config", serverPortAdmin))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("does not serve the admin routes on the locally accessible server", func() {
+				resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/debug/config", serverPort))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+
+			It("does not serve the other routes on the admin server", func() {
+				resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/ping", serverPortAdmin))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
 		Context("ClientFactory", func() {
 			var (
 				addr          string
@@ -974,7 +1004,7 @@ dYbCU/DMZjsv+Pt9flhj7ELLo+WKHyI767hJSq9A7IT3GzFt8iGiEAt1qj2yS0DX
 			canConnectSuccessfully := func() {
 				client, err := clientFactory.CreateClient("", addr)
 				Expect(err).NotTo(HaveOccurred())
-				_, err = client.State(logger)
+				_, err = client.State(context.Background(), logger)
 				Expect(err).NotTo(HaveOccurred())
 			}
 
@@ -0,0 +1,44 @@
+package rep
+
+// The kinds of ConsistencyDivergence.
+const (
+	// DivergenceOrphanedContainer is an LRP container on the cell BBS has no
+	// actual LRP on the cell for.
+	DivergenceOrphanedContainer = "orphaned_container"
+	// DivergenceMissingContainer is an actual LRP BBS places on the cell that
+	// has no container on it.
+	DivergenceMissingContainer = "missing_container"
+)
+
+// ConsistencyDivergence is an LRP instance the cell and BBS disagree about.
+// State is that of the container for orphaned containers and that of the
+// actual LRP for missing ones.
+type ConsistencyDivergence struct {
+	Kind         string `json:"kind"`
+	ProcessGuid  string `json:"process_guid"`
+	Index        int32  `json:"index"`
+	InstanceGUID string `json:"instance_guid"`
+	State        string `json:"state"`
+	Repaired     bool   `json:"repaired,omitempty"`
+}
+
+// ConsistencyReport is the outcome of comparing the LRP containers of a cell
+// with the actual LRPs BBS places on it, at CheckedAt in unix nanoseconds.
+type ConsistencyReport struct {
+	CheckedAt   int64                   `json:"checked_at"`
+	Containers  int                     `json:"containers"`
+	ActualLRPs  int                     `json:"actual_lrps"`
+	Repair      bool                    `json:"repair"`
+	Divergences []ConsistencyDivergence `json:"divergences"`
+}
+
+// Count returns how many of the divergences are of kind.
+func (r ConsistencyReport) Count(kind string) int {
+	count := 0
+	for i := range r.Divergences {
+		if r.Divergences[i].Kind == kind {
+			count++
+		}
+	}
+	return count
+}
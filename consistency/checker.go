@@ -0,0 +1,262 @@
+package consistency
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/bbs"
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	orphanedContainersMetric = "ConsistencyOrphanedContainers"
+	missingContainersMetric  = "ConsistencyMissingContainers"
+
+	// MissingContainerReason is the crash reason reported to BBS for the
+	// running actual LRPs whose container is gone from the cell.
+	MissingContainerReason = "container is missing from the cell"
+)
+
+// Checker compares the LRP containers of the cell with the actual LRPs BBS
+// places on it every interval. Both views change while an instance starts or
+// stops, so an instance only diverges once the views disagree about it on
+// two checks in a row. When repair is set, orphaned containers are deleted
+// and the actual LRPs of missing containers are crashed or removed.
+type Checker struct {
+	logger         lager.Logger
+	cellID         string
+	executorClient executor.Client
+	bbsClient      bbs.InternalClient
+	metronClient   loggingclient.IngressClient
+	clock          clock.Clock
+	interval       time.Duration
+	repair         bool
+
+	lock     sync.Mutex
+	suspects map[string]struct{}
+	report   *rep.ConsistencyReport
+}
+
+func NewChecker(
+	logger lager.Logger,
+	cellID string,
+	executorClient executor.Client,
+	bbsClient bbs.InternalClient,
+	metronClient loggingclient.IngressClient,
+	clock clock.Clock,
+	interval time.Duration,
+	repair bool,
+) *Checker {
+	return &Checker{
+		logger:         logger.Session("consistency-checker"),
+		cellID:         cellID,
+		executorClient: executorClient,
+		bbsClient:      bbsClient,
+		metronClient:   metronClient,
+		clock:          clock,
+		interval:       interval,
+		repair:         repair,
+		suspects:       map[string]struct{}{},
+	}
+}
+
+func (c *Checker) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	ticker := c.clock.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.Check(c.logger)
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// Report returns the outcome of the last check, if any has completed.
+func (c *Checker) Report(logger lager.Logger) (rep.ConsistencyReport, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.report == nil {
+		return rep.ConsistencyReport{}, false
+	}
+	return *c.report, true
+}
+
+// Check compares the LRP containers of the cell with its actual LRPs in BBS,
+// repairs the divergences when configured to, and returns its report.
+func (c *Checker) Check(logger lager.Logger) (rep.ConsistencyReport, error) {
+	logger = logger.Session("check")
+
+	containers, err := c.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return rep.ConsistencyReport{}, err
+	}
+
+	actualLRPs, err := c.bbsClient.ActualLRPs(logger, models.ActualLRPFilter{CellID: c.cellID})
+	if err != nil {
+		logger.Error("failed-to-fetch-actual-lrps", err)
+		return rep.ConsistencyReport{}, err
+	}
+
+	residents := map[string]executor.Container{}
+	for _, container := range containers {
+		if container.Tags[rep.LifecycleTag] != rep.LRPLifecycle {
+			continue
+		}
+		residents[container.Tags[rep.InstanceGuidTag]] = container
+	}
+
+	placed := map[string]*models.ActualLRP{}
+	for _, actualLRP := range actualLRPs {
+		placed[actualLRP.InstanceGuid] = actualLRP
+	}
+
+	report := rep.ConsistencyReport{
+		CheckedAt:   c.clock.Now().UnixNano(),
+		Containers:  len(residents),
+		ActualLRPs:  len(placed),
+		Repair:      c.repair,
+		Divergences: []rep.ConsistencyDivergence{},
+	}
+
+	suspects := map[string]struct{}{}
+	for instanceGuid, container := range residents {
+		if _, ok := placed[instanceGuid]; ok || !settled(container) {
+			continue
+		}
+		if c.suspected(suspects, rep.DivergenceOrphanedContainer, instanceGuid) {
+			report.Divergences = append(report.Divergences, c.orphaned(logger, container))
+		}
+	}
+	for instanceGuid, actualLRP := range placed {
+		if _, ok := residents[instanceGuid]; ok || !claimed(actualLRP) {
+			continue
+		}
+		if c.suspected(suspects, rep.DivergenceMissingContainer, instanceGuid) {
+			report.Divergences = append(report.Divergences, c.missing(logger, actualLRP))
+		}
+	}
+
+	sort.Slice(report.Divergences, func(i, j int) bool {
+		if report.Divergences[i].Kind == report.Divergences[j].Kind {
+			return report.Divergences[i].InstanceGUID < report.Divergences[j].InstanceGUID
+		}
+		return report.Divergences[i].Kind < report.Divergences[j].Kind
+	})
+
+	c.lock.Lock()
+	c.suspects = suspects
+	c.report = &report
+	c.lock.Unlock()
+
+	c.emit(logger, report)
+	if len(report.Divergences) > 0 {
+		logger.Info("found-divergences", lager.Data{"divergences": report.Divergences})
+	}
+	return report, nil
+}
+
+// suspected records that the views disagree about instanceGuid, and reports
+// whether they already did on the previous check.
+func (c *Checker) suspected(suspects map[string]struct{}, kind, instanceGuid string) bool {
+	key := kind + "/" + instanceGuid
+	suspects[key] = struct{}{}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, ok := c.suspects[key]
+	return ok
+}
+
+func (c *Checker) orphaned(logger lager.Logger, container executor.Container) rep.ConsistencyDivergence {
+	divergence := rep.ConsistencyDivergence{
+		Kind:         rep.DivergenceOrphanedContainer,
+		InstanceGUID: container.Tags[rep.InstanceGuidTag],
+		State:        string(container.State),
+	}
+	if key, err := rep.ActualLRPKeyFromTags(container.Tags); err == nil {
+		divergence.ProcessGuid = key.ProcessGuid
+		divergence.Index = key.Index
+	}
+
+	if !c.repair {
+		return divergence
+	}
+
+	err := c.executorClient.DeleteContainer(logger, container.Guid)
+	if err != nil {
+		logger.Error("failed-to-delete-orphaned-container", err, lager.Data{"container-guid": container.Guid})
+		return divergence
+	}
+	divergence.Repaired = true
+	return divergence
+}
+
+func (c *Checker) missing(logger lager.Logger, actualLRP *models.ActualLRP) rep.ConsistencyDivergence {
+	divergence := rep.ConsistencyDivergence{
+		Kind:         rep.DivergenceMissingContainer,
+		ProcessGuid:  actualLRP.ProcessGuid,
+		Index:        actualLRP.Index,
+		InstanceGUID: actualLRP.InstanceGuid,
+		State:        actualLRP.State,
+	}
+
+	if !c.repair {
+		return divergence
+	}
+
+	logger = logger.WithData(lager.Data{"process-guid": actualLRP.ProcessGuid, "index": actualLRP.Index})
+
+	var err error
+	switch {
+	case actualLRP.GetPresence() == models.ActualLRP_Evacuating:
+		err = c.bbsClient.RemoveEvacuatingActualLRP(logger, &actualLRP.ActualLRPKey, &actualLRP.ActualLRPInstanceKey)
+	case actualLRP.State == models.ActualLRPStateRunning:
+		err = c.bbsClient.CrashActualLRP(logger, &actualLRP.ActualLRPKey, &actualLRP.ActualLRPInstanceKey, MissingContainerReason)
+	default:
+		err = c.bbsClient.RemoveActualLRP(logger, &actualLRP.ActualLRPKey, &actualLRP.ActualLRPInstanceKey)
+	}
+	if err != nil {
+		logger.Error("failed-to-repair-missing-container", err)
+		return divergence
+	}
+	divergence.Repaired = true
+	return divergence
+}
+
+func (c *Checker) emit(logger lager.Logger, report rep.ConsistencyReport) {
+	err := c.metronClient.SendMetric(orphanedContainersMetric, report.Count(rep.DivergenceOrphanedContainer))
+	if err != nil {
+		logger.Error("failed-to-send-orphaned-containers-metric", err)
+	}
+
+	err = c.metronClient.SendMetric(missingContainersMetric, report.Count(rep.DivergenceMissingContainer))
+	if err != nil {
+		logger.Error("failed-to-send-missing-containers-metric", err)
+	}
+}
+
+// settled reports whether the container is past the reservation BBS learns
+// about once the instance is claimed.
+func settled(container executor.Container) bool {
+	return container.State == executor.StateCreated || container.State == executor.StateRunning
+}
+
+// claimed reports whether BBS expects a container on the cell for the actual
+// LRP.
+func claimed(actualLRP *models.ActualLRP) bool {
+	return actualLRP.State == models.ActualLRPStateClaimed || actualLRP.State == models.ActualLRPStateRunning
+}
@@ -0,0 +1,229 @@
+package consistency_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/bbs/fake_bbs"
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/consistency"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit/ginkgomon"
+)
+
+var _ = Describe("Checker", func() {
+	const cellID = "cell-id"
+
+	var (
+		logger             *lagertest.TestLogger
+		fakeExecutorClient *fakes.FakeClient
+		fakeBBSClient      *fake_bbs.FakeInternalClient
+		fakeMetronClient   *mfakes.FakeIngressClient
+		fakeClock          *fakeclock.FakeClock
+		repair             bool
+		checker            *consistency.Checker
+
+		matched, orphaned executor.Container
+		placed, missing   *models.ActualLRP
+	)
+
+	lrpContainer := func(instanceGuid string, index string) executor.Container {
+		return executor.Container{
+			Guid:  instanceGuid + "-container",
+			State: executor.StateRunning,
+			Tags: executor.Tags{
+				rep.LifecycleTag:    rep.LRPLifecycle,
+				rep.DomainTag:       "domain",
+				rep.ProcessGuidTag:  "process-guid",
+				rep.ProcessIndexTag: index,
+				rep.InstanceGuidTag: instanceGuid,
+			},
+		}
+	}
+
+	actualLRP := func(instanceGuid string, index int32, state string) *models.ActualLRP {
+		return &models.ActualLRP{
+			ActualLRPKey:         models.NewActualLRPKey("process-guid", index, "domain"),
+			ActualLRPInstanceKey: models.NewActualLRPInstanceKey(instanceGuid, cellID),
+			State:                state,
+		}
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeExecutorClient = new(fakes.FakeClient)
+		fakeBBSClient = new(fake_bbs.FakeInternalClient)
+		fakeMetronClient = new(mfakes.FakeIngressClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		repair = false
+
+		matched = lrpContainer("matched", "0")
+		orphaned = lrpContainer("orphaned", "1")
+		placed = actualLRP("matched", 0, models.ActualLRPStateRunning)
+		missing = actualLRP("missing", 2, models.ActualLRPStateRunning)
+
+		fakeExecutorClient.ListContainersReturns([]executor.Container{matched, orphaned}, nil)
+		fakeBBSClient.ActualLRPsReturns([]*models.ActualLRP{placed, missing}, nil)
+	})
+
+	JustBeforeEach(func() {
+		checker = consistency.NewChecker(logger, cellID, fakeExecutorClient, fakeBBSClient, fakeMetronClient, fakeClock, time.Minute, repair)
+	})
+
+	It("lists the actual LRPs BBS places on the cell", func() {
+		_, err := checker.Check(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeBBSClient.ActualLRPsCallCount()).To(Equal(1))
+		_, filter := fakeBBSClient.ActualLRPsArgsForCall(0)
+		Expect(filter).To(Equal(models.ActualLRPFilter{CellID: cellID}))
+	})
+
+	It("does not report a divergence seen only once", func() {
+		report, err := checker.Check(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Containers).To(Equal(2))
+		Expect(report.ActualLRPs).To(Equal(2))
+		Expect(report.Divergences).To(BeEmpty())
+	})
+
+	It("reports the divergences seen on two checks in a row", func() {
+		checker.Check(logger)
+		report, err := checker.Check(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(report.Divergences).To(Equal([]rep.ConsistencyDivergence{
+			{Kind: rep.DivergenceMissingContainer, ProcessGuid: "process-guid", Index: 2, InstanceGUID: "missing", State: models.ActualLRPStateRunning},
+			{Kind: rep.DivergenceOrphanedContainer, ProcessGuid: "process-guid", Index: 1, InstanceGUID: "orphaned", State: string(executor.StateRunning)},
+		}))
+		Expect(fakeExecutorClient.DeleteContainerCallCount()).To(BeZero())
+		Expect(fakeBBSClient.CrashActualLRPCallCount()).To(BeZero())
+
+		last, ok := checker.Report(logger)
+		Expect(ok).To(BeTrue())
+		Expect(last).To(Equal(report))
+	})
+
+	It("forgets divergences that resolved themselves", func() {
+		checker.Check(logger)
+		fakeExecutorClient.ListContainersReturns([]executor.Container{matched}, nil)
+		fakeBBSClient.ActualLRPsReturns([]*models.ActualLRP{placed}, nil)
+		checker.Check(logger)
+
+		fakeExecutorClient.ListContainersReturns([]executor.Container{matched, orphaned}, nil)
+		report, err := checker.Check(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Divergences).To(BeEmpty())
+	})
+
+	It("ignores containers that are still reserved and actual LRPs that are not claimed", func() {
+		orphaned.State = executor.StateReserved
+		missing.State = models.ActualLRPStateUnclaimed
+		fakeExecutorClient.ListContainersReturns([]executor.Container{matched, orphaned}, nil)
+
+		checker.Check(logger)
+		report, err := checker.Check(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Divergences).To(BeEmpty())
+	})
+
+	It("emits the divergence counts", func() {
+		checker.Check(logger)
+		checker.Check(logger)
+
+		Expect(fakeMetronClient.SendMetricCallCount()).To(Equal(4))
+		name, value, _ := fakeMetronClient.SendMetricArgsForCall(2)
+		Expect(name).To(Equal("ConsistencyOrphanedContainers"))
+		Expect(value).To(Equal(1))
+		name, value, _ = fakeMetronClient.SendMetricArgsForCall(3)
+		Expect(name).To(Equal("ConsistencyMissingContainers"))
+		Expect(value).To(Equal(1))
+	})
+
+	Context("when repairing divergences", func() {
+		BeforeEach(func() {
+			repair = true
+		})
+
+		It("deletes orphaned containers and crashes the running actual LRPs of missing containers", func() {
+			checker.Check(logger)
+			report, err := checker.Check(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Divergences).To(HaveLen(2))
+			for _, divergence := range report.Divergences {
+				Expect(divergence.Repaired).To(BeTrue())
+			}
+
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(1))
+			_, guid := fakeExecutorClient.DeleteContainerArgsForCall(0)
+			Expect(guid).To(Equal("orphaned-container"))
+
+			Expect(fakeBBSClient.CrashActualLRPCallCount()).To(Equal(1))
+			_, key, instanceKey, reason := fakeBBSClient.CrashActualLRPArgsForCall(0)
+			Expect(*key).To(Equal(missing.ActualLRPKey))
+			Expect(*instanceKey).To(Equal(missing.ActualLRPInstanceKey))
+			Expect(reason).To(Equal(consistency.MissingContainerReason))
+		})
+
+		It("removes the claimed actual LRPs of missing containers", func() {
+			missing.State = models.ActualLRPStateClaimed
+
+			checker.Check(logger)
+			checker.Check(logger)
+
+			Expect(fakeBBSClient.RemoveActualLRPCallCount()).To(Equal(1))
+			_, key, _ := fakeBBSClient.RemoveActualLRPArgsForCall(0)
+			Expect(*key).To(Equal(missing.ActualLRPKey))
+		})
+
+		Context("when a repair fails", func() {
+			BeforeEach(func() {
+				fakeExecutorClient.DeleteContainerReturns(errors.New("boom"))
+			})
+
+			It("reports the divergence as not repaired", func() {
+				checker.Check(logger)
+				report, _ := checker.Check(logger)
+				Expect(report.Divergences[1].Kind).To(Equal(rep.DivergenceOrphanedContainer))
+				Expect(report.Divergences[1].Repaired).To(BeFalse())
+			})
+		})
+	})
+
+	Context("when BBS cannot be reached", func() {
+		BeforeEach(func() {
+			fakeBBSClient.ActualLRPsReturns(nil, errors.New("boom"))
+		})
+
+		It("fails without a report", func() {
+			_, err := checker.Check(logger)
+			Expect(err).To(MatchError("boom"))
+
+			_, ok := checker.Report(logger)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	It("checks every interval until signalled", func() {
+		process := ginkgomon.Invoke(checker)
+		defer ginkgomon.Interrupt(process)
+
+		Eventually(fakeClock.WatcherCount).Should(Equal(1))
+		fakeClock.Increment(time.Minute)
+		Eventually(fakeExecutorClient.ListContainersCallCount).Should(Equal(1))
+	})
+
+	It("stops when signalled", func() {
+		process := ginkgomon.Invoke(checker)
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1 @@
+package consistency // import "code.cloudfoundry.org/rep/consistency"
@@ -0,0 +1,13 @@
+package contacts_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestContacts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Contacts Suite")
+}
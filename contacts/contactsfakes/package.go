@@ -0,0 +1 @@
+package contactsfakes // import "code.cloudfoundry.org/rep/contacts/contactsfakes"
@@ -0,0 +1 @@
+package contacts // import "code.cloudfoundry.org/rep/contacts"
@@ -0,0 +1,55 @@
+package contacts
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o contactsfakes/fake_recorder.go . Recorder
+
+// Recorder records that the rep reached the BBS or Garden.
+type Recorder interface {
+	RecordBBSContact()
+	RecordGardenContact()
+}
+
+//go:generate counterfeiter -o contactsfakes/fake_reporter.go . Reporter
+
+// Reporter reports when the rep last reached the BBS and Garden.
+type Reporter interface {
+	LastContacts() rep.LastContacts
+}
+
+// Tracker keeps the last time the rep reached the BBS and Garden, so that
+// the cell can tell the auctioneer how fresh its state is even while its
+// HTTP API keeps responding.
+type Tracker struct {
+	clock clock.Clock
+
+	lock     sync.RWMutex
+	contacts rep.LastContacts
+}
+
+func NewTracker(clock clock.Clock) *Tracker {
+	return &Tracker{clock: clock}
+}
+
+func (t *Tracker) RecordBBSContact() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.contacts.LastBBSContact = t.clock.Now().UnixNano()
+}
+
+func (t *Tracker) RecordGardenContact() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.contacts.LastGardenContact = t.clock.Now().UnixNano()
+}
+
+func (t *Tracker) LastContacts() rep.LastContacts {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.contacts
+}
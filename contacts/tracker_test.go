@@ -0,0 +1,43 @@
+package contacts_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/contacts"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracker", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		tracker   *contacts.Tracker
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(1700000000, 0))
+		tracker = contacts.NewTracker(fakeClock)
+	})
+
+	It("reports no contacts before any was recorded", func() {
+		Expect(tracker.LastContacts()).To(Equal(rep.LastContacts{}))
+	})
+
+	It("reports the last time each contact was recorded", func() {
+		tracker.RecordBBSContact()
+		fakeClock.Increment(time.Second)
+		tracker.RecordGardenContact()
+
+		Expect(tracker.LastContacts()).To(Equal(rep.LastContacts{
+			LastBBSContact:    time.Unix(1700000000, 0).UnixNano(),
+			LastGardenContact: time.Unix(1700000001, 0).UnixNano(),
+		}))
+
+		fakeClock.Increment(time.Second)
+		tracker.RecordBBSContact()
+		Expect(tracker.LastContacts().LastBBSContact).To(Equal(time.Unix(1700000002, 0).UnixNano()))
+	})
+})
@@ -0,0 +1,22 @@
+package rep
+
+// The types of the events in the life of a container.
+const (
+	ContainerEventReserved    = "reserved"
+	ContainerEventCreated     = "created"
+	ContainerEventHealthy     = "healthy"
+	ContainerEventCrashed     = "crashed"
+	ContainerEventOutOfMemory = "out-of-memory"
+	ContainerEventCompleted   = "completed"
+	ContainerEventStopped     = "stopped"
+	ContainerEventRemoved     = "removed"
+)
+
+// ContainerEvent is an event in the life of a container on a cell, as served
+// by ContainerEventsRoute. Time is in unix nanoseconds, and Message holds the
+// failure reason of a container that crashed.
+type ContainerEvent struct {
+	Type    string `json:"type"`
+	Time    int64  `json:"time"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,101 @@
+package rep
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/executor/containermetrics"
+)
+
+// The fields a ContainerMetricsBatch can be asked for.
+const (
+	ContainerMetricsFieldMemory = "memory"
+	ContainerMetricsFieldCPU    = "cpu"
+	ContainerMetricsFieldDisk   = "disk"
+)
+
+// ContainerMetricsFields selects the metrics of a ContainerMetricsBatch.
+type ContainerMetricsFields struct {
+	Memory bool
+	CPU    bool
+	Disk   bool
+}
+
+// AllContainerMetricsFields selects every metric.
+var AllContainerMetricsFields = ContainerMetricsFields{Memory: true, CPU: true, Disk: true}
+
+// ParseContainerMetricsFields parses a comma separated list of fields such
+// as memory,cpu. An empty list selects every field.
+func ParseContainerMetricsFields(fields string) (ContainerMetricsFields, error) {
+	if fields == "" {
+		return AllContainerMetricsFields, nil
+	}
+
+	selected := ContainerMetricsFields{}
+	for _, field := range strings.Split(fields, ",") {
+		switch strings.TrimSpace(field) {
+		case ContainerMetricsFieldMemory:
+			selected.Memory = true
+		case ContainerMetricsFieldCPU:
+			selected.CPU = true
+		case ContainerMetricsFieldDisk:
+			selected.Disk = true
+		default:
+			return ContainerMetricsFields{}, fmt.Errorf("unknown container metrics field %q", field)
+		}
+	}
+	return selected, nil
+}
+
+// ContainerMetricsBatch holds the current metrics of every container on a
+// cell. Unlike a ContainerMetricsCollection it is built from the metrics the
+// executor caches alone, without listing the containers, and only carries
+// the selected fields.
+type ContainerMetricsBatch struct {
+	CellID     string                   `json:"cell_id"`
+	Containers []ContainerMetricsSample `json:"containers"`
+}
+
+// ContainerMetricsSample holds the selected metrics of one container. The
+// fields that were not selected are left out.
+type ContainerMetricsSample struct {
+	Guid             string   `json:"guid"`
+	MetricGUID       string   `json:"metric_guid,omitempty"`
+	MemoryUsageBytes *uint64  `json:"memory_usage_bytes,omitempty"`
+	MemoryQuotaBytes *uint64  `json:"memory_quota_bytes,omitempty"`
+	CPUUsageFraction *float64 `json:"cpu_usage_fraction,omitempty"`
+	DiskUsageBytes   *uint64  `json:"disk_usage_bytes,omitempty"`
+	DiskQuotaBytes   *uint64  `json:"disk_quota_bytes,omitempty"`
+}
+
+// NewContainerMetricsBatch returns the batch of the selected fields of
+// metrics, which are keyed by container guid, ordered by container guid.
+func NewContainerMetricsBatch(cellID string, metrics map[string]*containermetrics.CachedContainerMetrics, fields ContainerMetricsFields) ContainerMetricsBatch {
+	batch := ContainerMetricsBatch{CellID: cellID, Containers: make([]ContainerMetricsSample, 0, len(metrics))}
+
+	for guid, m := range metrics {
+		if m == nil {
+			continue
+		}
+		m := *m
+		sample := ContainerMetricsSample{Guid: guid, MetricGUID: m.MetricGUID}
+		if fields.Memory {
+			sample.MemoryUsageBytes = &m.MemoryUsageBytes
+			sample.MemoryQuotaBytes = &m.MemoryQuotaBytes
+		}
+		if fields.CPU {
+			sample.CPUUsageFraction = &m.CPUUsageFraction
+		}
+		if fields.Disk {
+			sample.DiskUsageBytes = &m.DiskUsageBytes
+			sample.DiskQuotaBytes = &m.DiskQuotaBytes
+		}
+		batch.Containers = append(batch.Containers, sample)
+	}
+
+	sort.Slice(batch.Containers, func(i, j int) bool {
+		return batch.Containers[i].Guid < batch.Containers[j].Guid
+	})
+	return batch
+}
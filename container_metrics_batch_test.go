@@ -0,0 +1,66 @@
+package rep_test
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContainerMetricsBatch", func() {
+	Describe("ParseContainerMetricsFields", func() {
+		It("selects the given fields", func() {
+			fields, err := rep.ParseContainerMetricsFields("memory, disk")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fields).To(Equal(rep.ContainerMetricsFields{Memory: true, Disk: true}))
+		})
+
+		It("selects every field when none is given", func() {
+			fields, err := rep.ParseContainerMetricsFields("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fields).To(Equal(rep.AllContainerMetricsFields))
+		})
+
+		It("rejects unknown fields", func() {
+			_, err := rep.ParseContainerMetricsFields("memory,network")
+			Expect(err).To(MatchError(`unknown container metrics field "network"`))
+		})
+	})
+
+	Describe("NewContainerMetricsBatch", func() {
+		var metrics map[string]*containermetrics.CachedContainerMetrics
+
+		BeforeEach(func() {
+			metrics = map[string]*containermetrics.CachedContainerMetrics{
+				"container-b": {MetricGUID: "metric-guid-b", CPUUsageFraction: 0.5, MemoryUsageBytes: 10, MemoryQuotaBytes: 20},
+				"container-a": {MetricGUID: "metric-guid-a", DiskUsageBytes: 30, DiskQuotaBytes: 40},
+				"container-c": nil,
+			}
+		})
+
+		It("only encodes the selected fields of each container, ordered by guid", func() {
+			batch := rep.NewContainerMetricsBatch("cell-id", metrics, rep.ContainerMetricsFields{CPU: true})
+
+			encoded, err := json.Marshal(batch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoded).To(MatchJSON(`{
+				"cell_id": "cell-id",
+				"containers": [
+					{"guid": "container-a", "metric_guid": "metric-guid-a", "cpu_usage_fraction": 0},
+					{"guid": "container-b", "metric_guid": "metric-guid-b", "cpu_usage_fraction": 0.5}
+				]
+			}`))
+		})
+
+		It("encodes every field when all are selected", func() {
+			batch := rep.NewContainerMetricsBatch("cell-id", metrics, rep.AllContainerMetricsFields)
+
+			Expect(batch.Containers).To(HaveLen(2))
+			Expect(*batch.Containers[1].MemoryQuotaBytes).To(Equal(uint64(20)))
+			Expect(*batch.Containers[0].DiskQuotaBytes).To(Equal(uint64(40)))
+		})
+	})
+})
@@ -0,0 +1,144 @@
+package containerd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Stats is the resource usage of a container's task as reported by
+// containerd.
+type Stats struct {
+	CPUUsageNanos    uint64
+	MemoryUsageBytes uint64
+	MemoryLimitBytes uint64
+}
+
+//go:generate counterfeiter -o containerdfakes/fake_client.go . Client
+
+// Client queries containerd directly, bypassing garden. Containers are
+// identified by their garden handle.
+type Client interface {
+	ListContainers(logger lager.Logger) ([]string, error)
+	Stats(logger lager.Logger, handle string) (Stats, error)
+}
+
+type ctrClient struct {
+	ctrPath   string
+	address   string
+	namespace string
+	timeout   time.Duration
+}
+
+// NewCtrClient returns a Client that queries the containerd listening on
+// address through the ctr binary at ctrPath.
+func NewCtrClient(ctrPath, address, namespace string, timeout time.Duration) Client {
+	return &ctrClient{
+		ctrPath:   ctrPath,
+		address:   address,
+		namespace: namespace,
+		timeout:   timeout,
+	}
+}
+
+func (c *ctrClient) ListContainers(logger lager.Logger) ([]string, error) {
+	output, err := c.run("containers", "list", "--quiet")
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return nil, err
+	}
+
+	handles := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		handle := strings.TrimSpace(scanner.Text())
+		if handle != "" {
+			handles = append(handles, handle)
+		}
+	}
+	return handles, scanner.Err()
+}
+
+// taskMetrics covers the JSON output of ctr for both cgroup v1 and cgroup v2
+// hosts.
+type taskMetrics struct {
+	CPU struct {
+		Usage struct {
+			Total uint64 `json:"total"`
+		} `json:"usage"`
+		UsageUsec uint64 `json:"usage_usec"`
+	} `json:"cpu"`
+	Memory struct {
+		Usage json.RawMessage `json:"usage"`
+		Limit uint64          `json:"usage_limit"`
+	} `json:"memory"`
+}
+
+type memoryEntry struct {
+	Usage uint64 `json:"usage"`
+	Limit uint64 `json:"limit"`
+}
+
+func (c *ctrClient) Stats(logger lager.Logger, handle string) (Stats, error) {
+	output, err := c.run("tasks", "metrics", "--format", "json", handle)
+	if err != nil {
+		logger.Error("failed-to-fetch-task-metrics", err, lager.Data{"handle": handle})
+		return Stats{}, err
+	}
+
+	var metrics taskMetrics
+	err = json.Unmarshal(output, &metrics)
+	if err != nil {
+		logger.Error("failed-to-parse-task-metrics", err, lager.Data{"handle": handle})
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		CPUUsageNanos:    metrics.CPU.Usage.Total,
+		MemoryLimitBytes: metrics.Memory.Limit,
+	}
+	if metrics.CPU.UsageUsec != 0 {
+		stats.CPUUsageNanos = metrics.CPU.UsageUsec * uint64(time.Microsecond)
+	}
+
+	// cgroup v1 reports memory usage as an entry with its own limit, cgroup v2
+	// as a plain number.
+	if len(metrics.Memory.Usage) == 0 {
+		return stats, nil
+	}
+
+	var entry memoryEntry
+	if err := json.Unmarshal(metrics.Memory.Usage, &entry); err == nil {
+		stats.MemoryUsageBytes = entry.Usage
+		stats.MemoryLimitBytes = entry.Limit
+	} else if err := json.Unmarshal(metrics.Memory.Usage, &stats.MemoryUsageBytes); err != nil {
+		logger.Error("failed-to-parse-memory-usage", err, lager.Data{"handle": handle})
+		return Stats{}, err
+	}
+
+	return stats, nil
+}
+
+func (c *ctrClient) run(args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	args = append([]string{"--address", c.address, "--namespace", c.namespace}, args...)
+	cmd := exec.CommandContext(ctx, c.ctrPath, args...)
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ctr %s: %s: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
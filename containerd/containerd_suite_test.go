@@ -0,0 +1,13 @@
+package containerd_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestContainerd(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Containerd Suite")
+}
@@ -0,0 +1 @@
+package containerdfakes // import "code.cloudfoundry.org/rep/containerd/containerdfakes"
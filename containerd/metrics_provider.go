@@ -0,0 +1,185 @@
+package containerd
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const containerdMetricsCollectionDuration = "ContainerdMetricsCollectionDuration"
+
+type sample struct {
+	cpuUsageNanos uint64
+	time          time.Time
+}
+
+// MetricsProvider serves container metrics collected from containerd. The
+// CPU and memory usage of every container containerd reports on is collected
+// directly, while the rest of its metrics, and the metrics of containers
+// containerd does not report on, come from the fallback provider.
+type MetricsProvider struct {
+	logger       lager.Logger
+	client       Client
+	fallback     rep.ContainerMetricsProvider
+	clock        clock.Clock
+	interval     time.Duration
+	maxInFlight  int
+	metronClient loggingclient.IngressClient
+
+	lock    sync.RWMutex
+	stats   map[string]Stats
+	samples map[string]sample
+	cpu     map[string]float64
+}
+
+func NewMetricsProvider(
+	logger lager.Logger,
+	client Client,
+	fallback rep.ContainerMetricsProvider,
+	clock clock.Clock,
+	interval time.Duration,
+	maxInFlight int,
+	metronClient loggingclient.IngressClient,
+) *MetricsProvider {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+
+	return &MetricsProvider{
+		logger:       logger.Session("containerd-metrics"),
+		client:       client,
+		fallback:     fallback,
+		clock:        clock,
+		interval:     interval,
+		maxInFlight:  maxInFlight,
+		metronClient: metronClient,
+		stats:        map[string]Stats{},
+		samples:      map[string]sample{},
+		cpu:          map[string]float64{},
+	}
+}
+
+func (p *MetricsProvider) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	p.logger.Info("starting")
+	defer p.logger.Info("finished")
+
+	p.collect()
+	close(ready)
+
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			p.collect()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (p *MetricsProvider) Metrics() map[string]*containermetrics.CachedContainerMetrics {
+	metrics := map[string]*containermetrics.CachedContainerMetrics{}
+	for handle, fallback := range p.fallback.Metrics() {
+		metrics[handle] = fallback
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	for handle, stats := range p.stats {
+		containerMetrics := containermetrics.CachedContainerMetrics{}
+		if fallback, ok := metrics[handle]; ok && fallback != nil {
+			containerMetrics = *fallback
+		}
+
+		containerMetrics.MemoryUsageBytes = stats.MemoryUsageBytes
+		if stats.MemoryLimitBytes != 0 {
+			containerMetrics.MemoryQuotaBytes = stats.MemoryLimitBytes
+		}
+		if cpu, ok := p.cpu[handle]; ok {
+			containerMetrics.CPUUsageFraction = cpu
+		}
+
+		metrics[handle] = &containerMetrics
+	}
+
+	return metrics
+}
+
+func (p *MetricsProvider) collect() {
+	logger := p.logger.Session("collect")
+	start := p.clock.Now()
+
+	handles, err := p.client.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return
+	}
+
+	stats := map[string]Stats{}
+	statsLock := sync.Mutex{}
+	inFlight := make(chan struct{}, p.maxInFlight)
+	wg := sync.WaitGroup{}
+
+	for _, handle := range handles {
+		handle := handle
+		inFlight <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-inFlight
+				wg.Done()
+			}()
+
+			containerStats, err := p.client.Stats(logger, handle)
+			if err != nil {
+				return
+			}
+
+			statsLock.Lock()
+			stats[handle] = containerStats
+			statsLock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	now := p.clock.Now()
+	samples := map[string]sample{}
+	cpu := map[string]float64{}
+
+	p.lock.Lock()
+	for handle, containerStats := range stats {
+		samples[handle] = sample{cpuUsageNanos: containerStats.CPUUsageNanos, time: now}
+
+		previous, ok := p.samples[handle]
+		if !ok || containerStats.CPUUsageNanos < previous.cpuUsageNanos {
+			continue
+		}
+
+		elapsed := now.Sub(previous.time)
+		if elapsed > 0 {
+			cpu[handle] = float64(containerStats.CPUUsageNanos-previous.cpuUsageNanos) / float64(elapsed)
+		}
+	}
+	p.stats = stats
+	p.samples = samples
+	p.cpu = cpu
+	p.lock.Unlock()
+
+	duration := p.clock.Since(start)
+	err = p.metronClient.SendDuration(containerdMetricsCollectionDuration, duration)
+	if err != nil {
+		logger.Error("failed-to-send-collection-duration", err)
+	}
+
+	logger.Debug("collected", lager.Data{"containers": len(stats), "duration": duration})
+}
@@ -0,0 +1,143 @@
+package containerd_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
+	"code.cloudfoundry.org/rep/containerd"
+	"code.cloudfoundry.org/rep/containerd/containerdfakes"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MetricsProvider", func() {
+	const interval = 10 * time.Second
+
+	var (
+		fakeClient       *containerdfakes.FakeClient
+		fakeFallback     *auctioncellrepfakes.FakeContainerMetricsProvider
+		fakeClock        *fakeclock.FakeClock
+		fakeMetronClient *mfakes.FakeIngressClient
+		provider         *containerd.MetricsProvider
+		process          ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeClient = new(containerdfakes.FakeClient)
+		fakeFallback = new(auctioncellrepfakes.FakeContainerMetricsProvider)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		fakeMetronClient = new(mfakes.FakeIngressClient)
+
+		fakeClient.ListContainersReturns([]string{"container-1"}, nil)
+		fakeClient.StatsReturns(containerd.Stats{
+			CPUUsageNanos:    uint64(time.Second),
+			MemoryUsageBytes: 100,
+			MemoryLimitBytes: 1000,
+		}, nil)
+		fakeFallback.MetricsReturns(map[string]*containermetrics.CachedContainerMetrics{
+			"container-1": {
+				MetricGUID:       "metric-guid-1",
+				CPUUsageFraction: 0.1,
+				DiskUsageBytes:   10,
+				DiskQuotaBytes:   20,
+				MemoryUsageBytes: 5,
+				MemoryQuotaBytes: 10,
+			},
+			"container-2": {
+				MetricGUID: "metric-guid-2",
+			},
+		})
+	})
+
+	JustBeforeEach(func() {
+		provider = containerd.NewMetricsProvider(lagertest.NewTestLogger("test"), fakeClient, fakeFallback, fakeClock, interval, 2, fakeMetronClient)
+		process = ifrit.Background(provider)
+		Eventually(process.Ready()).Should(BeClosed())
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("collects the stats of every container containerd lists before becoming ready", func() {
+		Expect(fakeClient.ListContainersCallCount()).To(Equal(1))
+		Expect(fakeClient.StatsCallCount()).To(Equal(1))
+		_, handle := fakeClient.StatsArgsForCall(0)
+		Expect(handle).To(Equal("container-1"))
+	})
+
+	It("overrides the memory usage reported by the fallback provider", func() {
+		metrics := provider.Metrics()
+		Expect(metrics["container-1"].MetricGUID).To(Equal("metric-guid-1"))
+		Expect(metrics["container-1"].MemoryUsageBytes).To(BeEquivalentTo(100))
+		Expect(metrics["container-1"].MemoryQuotaBytes).To(BeEquivalentTo(1000))
+		Expect(metrics["container-1"].DiskUsageBytes).To(BeEquivalentTo(10))
+	})
+
+	It("keeps the fallback CPU usage until it has two samples", func() {
+		Expect(provider.Metrics()["container-1"].CPUUsageFraction).To(Equal(0.1))
+	})
+
+	It("keeps the fallback metrics of containers containerd does not report on", func() {
+		Expect(provider.Metrics()["container-2"].MetricGUID).To(Equal("metric-guid-2"))
+	})
+
+	It("emits the collection duration", func() {
+		Expect(fakeMetronClient.SendDurationCallCount()).To(Equal(1))
+		name, _, _ := fakeMetronClient.SendDurationArgsForCall(0)
+		Expect(name).To(Equal("ContainerdMetricsCollectionDuration"))
+	})
+
+	Context("when the interval elapses", func() {
+		JustBeforeEach(func() {
+			fakeClient.StatsReturns(containerd.Stats{
+				CPUUsageNanos:    uint64(6 * time.Second),
+				MemoryUsageBytes: 200,
+			}, nil)
+			fakeClock.WaitForWatcherAndIncrement(interval)
+			Eventually(fakeClient.StatsCallCount).Should(Equal(2))
+		})
+
+		It("computes the CPU usage from the last two samples", func() {
+			Eventually(func() float64 {
+				return provider.Metrics()["container-1"].CPUUsageFraction
+			}).Should(Equal(0.5))
+		})
+
+		It("keeps the fallback memory quota when containerd does not report a limit", func() {
+			Eventually(func() uint64 {
+				return uint64(provider.Metrics()["container-1"].MemoryUsageBytes)
+			}).Should(BeEquivalentTo(200))
+			Expect(provider.Metrics()["container-1"].MemoryQuotaBytes).To(BeEquivalentTo(10))
+		})
+	})
+
+	Context("when listing the containers fails", func() {
+		BeforeEach(func() {
+			fakeClient.ListContainersReturns(nil, errors.New("boom"))
+		})
+
+		It("serves the fallback metrics", func() {
+			Expect(provider.Metrics()["container-1"].MemoryUsageBytes).To(BeEquivalentTo(5))
+		})
+	})
+
+	Context("when fetching the stats of a container fails", func() {
+		BeforeEach(func() {
+			fakeClient.StatsReturns(containerd.Stats{}, errors.New("boom"))
+		})
+
+		It("serves the fallback metrics for it", func() {
+			Expect(provider.Metrics()["container-1"].MemoryUsageBytes).To(BeEquivalentTo(5))
+		})
+	})
+})
@@ -0,0 +1 @@
+package containerd // import "code.cloudfoundry.org/rep/containerd"
@@ -0,0 +1,34 @@
+package containerevents
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// recordingClient records the containers the rep creates and removes, which
+// the executor emits no events for.
+type recordingClient struct {
+	executor.Client
+	history History
+}
+
+func NewRecordingClient(client executor.Client, history History) executor.Client {
+	return &recordingClient{Client: client, history: history}
+}
+
+func (c *recordingClient) RunContainer(logger lager.Logger, request *executor.RunRequest) error {
+	err := c.Client.RunContainer(logger, request)
+	if err == nil {
+		c.history.Record(request.Guid, rep.ContainerEventCreated, "")
+	}
+	return err
+}
+
+func (c *recordingClient) DeleteContainer(logger lager.Logger, guid string) error {
+	err := c.Client.DeleteContainer(logger, guid)
+	if err == nil {
+		c.history.Record(guid, rep.ContainerEventRemoved, "")
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+package containerevents_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestContainerEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Container Events Suite")
+}
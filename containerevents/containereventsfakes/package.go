@@ -0,0 +1 @@
+package containereventsfakes // import "code.cloudfoundry.org/rep/containerevents/containereventsfakes"
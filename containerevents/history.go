@@ -0,0 +1,84 @@
+package containerevents
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o containereventsfakes/fake_history.go . History
+
+// History keeps the recent events in the life of the containers on the cell,
+// including the containers that are gone, so that the life of an instance can
+// be reconstructed without correlating the logs of the rep, the executor and
+// garden.
+type History interface {
+	Record(guid, eventType, message string)
+
+	// Events returns the events of the container, oldest first, and false
+	// when no event of the container was recorded.
+	Events(guid string) ([]rep.ContainerEvent, bool)
+}
+
+type history struct {
+	clock         clock.Clock
+	maxEvents     int
+	maxContainers int
+
+	lock       sync.Mutex
+	containers map[string][]rep.ContainerEvent
+}
+
+// NewHistory returns a History that keeps the last maxEvents events of each
+// container. Once it holds the events of maxContainers containers, it forgets
+// the container whose last event is the oldest to make room for another.
+func NewHistory(clock clock.Clock, maxEvents, maxContainers int) History {
+	return &history{
+		clock:         clock,
+		maxEvents:     maxEvents,
+		maxContainers: maxContainers,
+		containers:    map[string][]rep.ContainerEvent{},
+	}
+}
+
+func (h *history) Record(guid, eventType, message string) {
+	event := rep.ContainerEvent{Type: eventType, Time: h.clock.Now().UnixNano(), Message: message}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	events, ok := h.containers[guid]
+	if !ok && len(h.containers) >= h.maxContainers {
+		h.forgetOldest()
+	}
+
+	events = append(events, event)
+	if len(events) > h.maxEvents {
+		events = append(events[:0:0], events[len(events)-h.maxEvents:]...)
+	}
+	h.containers[guid] = events
+}
+
+func (h *history) Events(guid string) ([]rep.ContainerEvent, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	events, ok := h.containers[guid]
+	if !ok {
+		return nil, false
+	}
+	return append([]rep.ContainerEvent{}, events...), true
+}
+
+func (h *history) forgetOldest() {
+	var oldestGuid string
+	var oldest int64
+	for guid, events := range h.containers {
+		last := events[len(events)-1].Time
+		if oldestGuid == "" || last < oldest {
+			oldestGuid, oldest = guid, last
+		}
+	}
+	delete(h.containers, oldestGuid)
+}
@@ -0,0 +1,72 @@
+package containerevents_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/containerevents"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("History", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		start     time.Time
+		history   containerevents.History
+	)
+
+	BeforeEach(func() {
+		start = time.Now()
+		fakeClock = fakeclock.NewFakeClock(start)
+		history = containerevents.NewHistory(fakeClock, 3, 2)
+	})
+
+	It("returns the events of a container oldest first", func() {
+		history.Record("guid-1", rep.ContainerEventReserved, "")
+		fakeClock.Increment(time.Second)
+		history.Record("guid-1", rep.ContainerEventCrashed, "exited with status 1")
+
+		events, ok := history.Events("guid-1")
+		Expect(ok).To(BeTrue())
+		Expect(events).To(Equal([]rep.ContainerEvent{
+			{Type: rep.ContainerEventReserved, Time: start.UnixNano()},
+			{Type: rep.ContainerEventCrashed, Time: start.Add(time.Second).UnixNano(), Message: "exited with status 1"},
+		}))
+	})
+
+	It("reports containers it recorded no event of", func() {
+		_, ok := history.Events("guid-1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("keeps only the most recent events of a container", func() {
+		for _, eventType := range []string{rep.ContainerEventReserved, rep.ContainerEventCreated, rep.ContainerEventHealthy, rep.ContainerEventStopped} {
+			history.Record("guid-1", eventType, "")
+		}
+
+		events, _ := history.Events("guid-1")
+		Expect(events).To(HaveLen(3))
+		Expect(events[0].Type).To(Equal(rep.ContainerEventCreated))
+		Expect(events[2].Type).To(Equal(rep.ContainerEventStopped))
+	})
+
+	It("forgets the container whose last event is the oldest to make room for another", func() {
+		history.Record("guid-1", rep.ContainerEventReserved, "")
+		fakeClock.Increment(time.Second)
+		history.Record("guid-2", rep.ContainerEventReserved, "")
+		fakeClock.Increment(time.Second)
+		history.Record("guid-1", rep.ContainerEventCreated, "")
+		fakeClock.Increment(time.Second)
+		history.Record("guid-3", rep.ContainerEventReserved, "")
+
+		_, ok := history.Events("guid-2")
+		Expect(ok).To(BeFalse())
+		_, ok = history.Events("guid-1")
+		Expect(ok).To(BeTrue())
+		_, ok = history.Events("guid-3")
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -0,0 +1 @@
+package containerevents // import "code.cloudfoundry.org/rep/containerevents"
@@ -0,0 +1,106 @@
+package containerevents
+
+import (
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// outOfMemoryReason is part of the failure reason the executor reports for a
+// container whose process was killed for running out of memory.
+const outOfMemoryReason = "out of memory"
+
+// Recorder records the lifecycle events the executor emits for the containers
+// on the cell in a History.
+type Recorder struct {
+	logger         lager.Logger
+	executorClient executor.Client
+	history        History
+}
+
+func NewRecorder(logger lager.Logger, executorClient executor.Client, history History) *Recorder {
+	return &Recorder{
+		logger:         logger.Session("container-event-recorder"),
+		executorClient: executorClient,
+		history:        history,
+	}
+}
+
+func (r *Recorder) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	events, err := r.executorClient.SubscribeToEvents(r.logger)
+	if err != nil {
+		r.logger.Error("failed-subscribing-to-events", err)
+		return err
+	}
+	defer events.Close()
+
+	received := make(chan executor.Event)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(received)
+		for {
+			event, err := events.Next()
+			if err != nil {
+				return
+			}
+			select {
+			case received <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	close(ready)
+
+	for {
+		select {
+		case event, ok := <-received:
+			if !ok {
+				r.logger.Info("event-stream-closed")
+				return nil
+			}
+			r.record(event)
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (r *Recorder) record(event executor.Event) {
+	lifecycle, ok := event.(executor.LifecycleEvent)
+	if !ok {
+		return
+	}
+	container := lifecycle.Container()
+
+	switch event.EventType() {
+	case executor.EventTypeContainerReserved:
+		r.history.Record(container.Guid, rep.ContainerEventReserved, "")
+	case executor.EventTypeContainerRunning:
+		r.history.Record(container.Guid, rep.ContainerEventHealthy, "")
+	case executor.EventTypeContainerComplete:
+		eventType, message := completion(container.RunResult)
+		r.history.Record(container.Guid, eventType, message)
+	}
+}
+
+// completion returns the type of the event of a container that completed
+// with result, and the message of the event.
+func completion(result executor.ContainerRunResult) (string, string) {
+	switch {
+	case result.Stopped:
+		return rep.ContainerEventStopped, ""
+	case !result.Failed:
+		return rep.ContainerEventCompleted, ""
+	case strings.Contains(result.FailureReason, outOfMemoryReason):
+		return rep.ContainerEventOutOfMemory, result.FailureReason
+	default:
+		return rep.ContainerEventCrashed, result.FailureReason
+	}
+}
@@ -0,0 +1,132 @@
+package containerevents_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/executor"
+	efakes "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/containerevents"
+	"code.cloudfoundry.org/rep/containerevents/containereventsfakes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		logger         *lagertest.TestLogger
+		executorClient *efakes.FakeClient
+		history        *containereventsfakes.FakeHistory
+		events         chan executor.Event
+		process        ifrit.Process
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(efakes.FakeClient)
+		history = new(containereventsfakes.FakeHistory)
+
+		events = make(chan executor.Event)
+		eventSource := new(efakes.FakeEventSource)
+		eventSource.NextStub = func() (executor.Event, error) {
+			event, ok := <-events
+			if !ok {
+				return nil, errors.New("closed")
+			}
+			return event, nil
+		}
+		executorClient.SubscribeToEventsReturns(eventSource, nil)
+	})
+
+	JustBeforeEach(func() {
+		process = ginkgomon.Invoke(containerevents.NewRecorder(logger, executorClient, history))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+	})
+
+	recorded := func(i int) []string {
+		guid, eventType, message := history.RecordArgsForCall(i)
+		return []string{guid, eventType, message}
+	}
+
+	It("records reservations and containers passing their health check", func() {
+		container := executor.Container{Guid: "guid-1"}
+		events <- executor.NewContainerReservedEvent(container)
+		events <- executor.NewContainerRunningEvent(container)
+
+		Eventually(history.RecordCallCount).Should(Equal(2))
+		Expect(recorded(0)).To(Equal([]string{"guid-1", rep.ContainerEventReserved, ""}))
+		Expect(recorded(1)).To(Equal([]string{"guid-1", rep.ContainerEventHealthy, ""}))
+	})
+
+	It("tells crashes, out of memory kills, completions and stops apart", func() {
+		events <- executor.NewContainerCompleteEvent(executor.Container{
+			Guid:      "guid-1",
+			RunResult: executor.ContainerRunResult{Failed: true, FailureReason: "exited with status 1"},
+		})
+		events <- executor.NewContainerCompleteEvent(executor.Container{
+			Guid:      "guid-2",
+			RunResult: executor.ContainerRunResult{Failed: true, FailureReason: "Exited with status 137 (out of memory)"},
+		})
+		events <- executor.NewContainerCompleteEvent(executor.Container{Guid: "guid-3"})
+		events <- executor.NewContainerCompleteEvent(executor.Container{
+			Guid:      "guid-4",
+			RunResult: executor.ContainerRunResult{Stopped: true},
+		})
+
+		Eventually(history.RecordCallCount).Should(Equal(4))
+		Expect(recorded(0)).To(Equal([]string{"guid-1", rep.ContainerEventCrashed, "exited with status 1"}))
+		Expect(recorded(1)).To(Equal([]string{"guid-2", rep.ContainerEventOutOfMemory, "Exited with status 137 (out of memory)"}))
+		Expect(recorded(2)).To(Equal([]string{"guid-3", rep.ContainerEventCompleted, ""}))
+		Expect(recorded(3)).To(Equal([]string{"guid-4", rep.ContainerEventStopped, ""}))
+	})
+
+	Context("when the event stream closes", func() {
+		It("exits", func() {
+			close(events)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+})
+
+var _ = Describe("RecordingClient", func() {
+	var (
+		logger         *lagertest.TestLogger
+		executorClient *efakes.FakeClient
+		history        *containereventsfakes.FakeHistory
+		client         executor.Client
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(efakes.FakeClient)
+		history = new(containereventsfakes.FakeHistory)
+		client = containerevents.NewRecordingClient(executorClient, history)
+	})
+
+	It("records the containers it runs and deletes", func() {
+		Expect(client.RunContainer(logger, &executor.RunRequest{Guid: "guid-1"})).To(Succeed())
+		Expect(client.DeleteContainer(logger, "guid-1")).To(Succeed())
+
+		Expect(executorClient.RunContainerCallCount()).To(Equal(1))
+		Expect(executorClient.DeleteContainerCallCount()).To(Equal(1))
+		Expect(history.RecordCallCount()).To(Equal(2))
+		guid, eventType, _ := history.RecordArgsForCall(0)
+		Expect(guid).To(Equal("guid-1"))
+		Expect(eventType).To(Equal(rep.ContainerEventCreated))
+		_, eventType, _ = history.RecordArgsForCall(1)
+		Expect(eventType).To(Equal(rep.ContainerEventRemoved))
+	})
+
+	It("does not record containers the executor fails to run", func() {
+		executorClient.RunContainerReturns(errors.New("boom"))
+		Expect(client.RunContainer(logger, &executor.RunRequest{Guid: "guid-1"})).NotTo(Succeed())
+		Expect(history.RecordCallCount()).To(BeZero())
+	})
+})
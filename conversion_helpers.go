@@ -20,8 +20,9 @@ const (
 	ResultFileTag = "result-file"
 	DomainTag     = "domain"
 
-	TaskLifecycle = "task"
-	LRPLifecycle  = "lrp"
+	TaskLifecycle     = "task"
+	LRPLifecycle      = "lrp"
+	SelfTestLifecycle = "self-test"
 
 	ProcessGuidTag  = "process-guid"
 	InstanceGuidTag = "instance-guid"
@@ -29,6 +30,8 @@ const (
 
 	VolumeDriversTag = "volume-drivers"
 	PlacementTagsTag = "placement-tags"
+
+	CPUEntitlementTag = "cpu-entitlement"
 )
 
 var (
@@ -78,6 +81,27 @@ func ActualLRPInstanceKeyFromContainer(container executor.Container, cellID stri
 	return &actualLRPInstanceKey, nil
 }
 
+// ContainerNetworkFromContainer returns the network assignment of the
+// container, or nil when the container has not been assigned an address yet.
+func ContainerNetworkFromContainer(container executor.Container) *ContainerNetwork {
+	if container.InternalIP == "" && container.ExternalIP == "" {
+		return nil
+	}
+
+	network := &ContainerNetwork{
+		InstanceAddress: container.InternalIP,
+		HostAddress:     container.ExternalIP,
+		Ports:           container.Ports,
+	}
+
+	if container.Network != nil {
+		network.NetworkName = container.Network.Properties[NetworkNameProperty]
+		network.PolicyGroupID = container.Network.Properties[PolicyGroupIDProperty]
+	}
+
+	return network
+}
+
 func ActualLRPNetInfoFromContainer(container executor.Container) (*models.ActualLRPNetInfo, error) {
 	ports := []*models.PortMapping{}
 
@@ -340,6 +364,26 @@ func convertImageLayers(t *models.TaskDefinition) ([]*models.CachedDependency, *
 	return cachedDependencies, action
 }
 
+// PullCredentials returns registry with the username and password that the
+// run requests of an LRP or task with the rootfs at rootFSPath pull it with,
+// so that the credentials checked before the work is placed are those of the
+// pull. It returns nil when registry is nil.
+func (rrch RunRequestConversionHelper) PullCredentials(rootFSPath string, registry *RegistryCredentials) (*RegistryCredentials, error) {
+	if registry == nil {
+		return nil, nil
+	}
+
+	username, password, err := rrch.convertCredentials(rootFSPath, registry.Username, registry.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	pull := *registry
+	pull.Username = username
+	pull.Password = password
+	return &pull, nil
+}
+
 func (rrch RunRequestConversionHelper) convertCredentials(rootFS string, username string, password string) (string, string, error) {
 	isECRRepo, err := rrch.ECRHelper.IsECRRepo(rootFS)
 	if err != nil {
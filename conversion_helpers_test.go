@@ -257,6 +257,72 @@ var _ = Describe("Resources", func() {
 		})
 	})
 
+	Describe("ContainerNetworkFromContainer", func() {
+		var container executor.Container
+
+		BeforeEach(func() {
+			container = executor.Container{
+				Guid:       "some-instance-guid",
+				ExternalIP: "some-external-ip",
+				InternalIP: "container-ip",
+				RunInfo: executor.RunInfo{
+					Ports: []executor.PortMapping{
+						{
+							ContainerPort: 1234,
+							HostPort:      6789,
+						},
+					},
+					Network: &executor.Network{
+						Properties: map[string]string{
+							rep.NetworkNameProperty:   "overlay",
+							rep.PolicyGroupIDProperty: "some-app-guid",
+							"space_id":                "some-space-guid",
+						},
+					},
+				},
+			}
+		})
+
+		It("returns the network assignment of the container", func() {
+			Expect(rep.ContainerNetworkFromContainer(container)).To(Equal(&rep.ContainerNetwork{
+				InstanceAddress: "container-ip",
+				HostAddress:     "some-external-ip",
+				NetworkName:     "overlay",
+				PolicyGroupID:   "some-app-guid",
+				Ports: []executor.PortMapping{
+					{
+						ContainerPort: 1234,
+						HostPort:      6789,
+					},
+				},
+			}))
+		})
+
+		Context("when the container has no network properties", func() {
+			BeforeEach(func() {
+				container.Network = nil
+			})
+
+			It("returns the addresses and ports only", func() {
+				network := rep.ContainerNetworkFromContainer(container)
+				Expect(network.InstanceAddress).To(Equal("container-ip"))
+				Expect(network.NetworkName).To(BeEmpty())
+				Expect(network.PolicyGroupID).To(BeEmpty())
+			})
+		})
+
+		Context("when the container has not been assigned an address", func() {
+			BeforeEach(func() {
+				container.ExternalIP = ""
+				container.InternalIP = ""
+			})
+
+			It("returns nil", func() {
+				Expect(rep.ContainerNetworkFromContainer(container)).To(BeNil())
+			})
+		})
+	})
+
 	Describe("RunRequestConversionHelper", func() {
 		var (
 			runRequestConversionHelper rep.RunRequestConversionHelper
@@ -270,6 +336,50 @@ var _ = Describe("Resources", func() {
 			}
 		})
 
+		Describe("PullCredentials", func() {
+			var registry *rep.RegistryCredentials
+
+			BeforeEach(func() {
+				registry = &rep.RegistryCredentials{Username: "user", Password: "password", CACertificates: "some-ca"}
+			})
+
+			It("returns the credentials unchanged when the rootfs is not an ECR repo", func() {
+				fakeECRHelper.IsECRRepoReturns(false, nil)
+				pull, err := runRequestConversionHelper.PullCredentials("docker:///some/image", registry)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pull).To(Equal(registry))
+			})
+
+			It("returns the ECR provided username and password for an ECR repo", func() {
+				fakeECRHelper.IsECRRepoReturns(true, nil)
+				fakeECRHelper.GetECRCredentialsReturns("ecr-username", "ecr-password", nil)
+
+				pull, err := runRequestConversionHelper.PullCredentials("docker://some.ecr.repo/image", registry)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pull).To(Equal(&rep.RegistryCredentials{Username: "ecr-username", Password: "ecr-password", CACertificates: "some-ca"}))
+				Expect(registry.Password).To(Equal("password"))
+
+				rootFS, username, password := fakeECRHelper.GetECRCredentialsArgsForCall(0)
+				Expect(rootFS).To(Equal("docker://some.ecr.repo/image"))
+				Expect(username).To(Equal("user"))
+				Expect(password).To(Equal("password"))
+			})
+
+			It("returns an error when the ECR credentials cannot be got", func() {
+				fakeECRHelper.IsECRRepoReturns(true, nil)
+				fakeECRHelper.GetECRCredentialsReturns("", "", errors.New("disaster"))
+
+				_, err := runRequestConversionHelper.PullCredentials("docker://some.ecr.repo/image", registry)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("returns nil without a registry", func() {
+				pull, err := runRequestConversionHelper.PullCredentials("docker:///some/image", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pull).To(BeNil())
+			})
+		})
+
 		Describe("NewRunRequestFromDesiredLRP", func() {
 			var (
 				containerGuid string
@@ -0,0 +1 @@
+package cordonfakes // import "code.cloudfoundry.org/rep/cordon/cordonfakes"
@@ -0,0 +1,101 @@
+package cordon
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	locketmodels "code.cloudfoundry.org/locket/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate counterfeiter -o cordonfakes/fake_reporter.go . Reporter
+
+// Reporter reports whether the cell is cordoned. A cordoned cell refuses new
+// work but keeps running the work it already has.
+type Reporter interface {
+	Cordoned() bool
+}
+
+// Watcher observes the cordon of the cell declared centrally in locket: the
+// cell is cordoned while a resource with its cordon key exists, so that
+// operators can cordon and uncordon cells across the deployment without
+// calling the admin API of every cell. It fetches the resource when it
+// starts and every interval after that, and keeps what it observed last when
+// locket cannot be reached or does not answer within timeout.
+type Watcher struct {
+	logger   lager.Logger
+	locker   locketmodels.LocketClient
+	key      string
+	clock    clock.Clock
+	interval time.Duration
+	timeout  time.Duration
+
+	lock     sync.RWMutex
+	cordoned bool
+}
+
+func NewWatcher(logger lager.Logger, locker locketmodels.LocketClient, key string, clock clock.Clock, interval, timeout time.Duration) *Watcher {
+	return &Watcher{
+		logger:   logger.Session("cordon-watcher", lager.Data{"key": key}),
+		locker:   locker,
+		key:      key,
+		clock:    clock,
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+func (w *Watcher) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	w.observe()
+	close(ready)
+
+	ticker := w.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			w.observe()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (w *Watcher) Cordoned() bool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.cordoned
+}
+
+func (w *Watcher) observe() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	response, err := w.locker.Fetch(ctx, &locketmodels.FetchRequest{Key: w.key})
+	if err != nil && status.Code(err) != codes.NotFound {
+		w.logger.Error("failed-to-fetch-cordon", err)
+		return
+	}
+	cordoned := err == nil
+
+	w.lock.Lock()
+	changed := w.cordoned != cordoned
+	w.cordoned = cordoned
+	w.lock.Unlock()
+
+	if !changed {
+		return
+	}
+	if cordoned {
+		w.logger.Info("cordoned", lager.Data{"reason": response.GetResource().GetValue()})
+	} else {
+		w.logger.Info("uncordoned")
+	}
+}
@@ -0,0 +1,86 @@
+package cordon_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	locketmodels "code.cloudfoundry.org/locket/models"
+	"code.cloudfoundry.org/locket/models/modelsfakes"
+	"code.cloudfoundry.org/rep/cordon"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		fakeLocker *modelsfakes.FakeLocketClient
+		fakeClock  *fakeclock.FakeClock
+		logger     *lagertest.TestLogger
+		watcher    *cordon.Watcher
+		process    ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeLocker = new(modelsfakes.FakeLocketClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+		fakeLocker.FetchReturns(nil, locketmodels.ErrResourceNotFound)
+	})
+
+	JustBeforeEach(func() {
+		watcher = cordon.NewWatcher(logger, fakeLocker, "cordon-cell-id", fakeClock, 10*time.Second, 5*time.Second)
+		process = ifrit.Invoke(watcher)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("fetches the cordon key of the cell before becoming ready", func() {
+		Expect(fakeLocker.FetchCallCount()).To(Equal(1))
+		_, request, _ := fakeLocker.FetchArgsForCall(0)
+		Expect(request.Key).To(Equal("cordon-cell-id"))
+		Expect(watcher.Cordoned()).To(BeFalse())
+	})
+
+	It("fetches the cordon key within the timeout", func() {
+		ctx, _, _ := fakeLocker.FetchArgsForCall(0)
+		deadline, ok := ctx.Deadline()
+		Expect(ok).To(BeTrue())
+		Expect(deadline).To(BeTemporally("~", time.Now().Add(5*time.Second), time.Second))
+	})
+
+	It("cordons the cell while the cordon resource exists", func() {
+		fakeLocker.FetchReturns(&locketmodels.FetchResponse{Resource: &locketmodels.Resource{Key: "cordon-cell-id", Value: "kernel upgrade"}}, nil)
+		fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+		Eventually(watcher.Cordoned).Should(BeTrue())
+		Expect(logger).To(gbytes.Say("cordoned.*kernel upgrade"))
+
+		fakeLocker.FetchReturns(nil, locketmodels.ErrResourceNotFound)
+		fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+		Eventually(watcher.Cordoned).Should(BeFalse())
+		Expect(logger).To(gbytes.Say("uncordoned"))
+	})
+
+	Context("when locket cannot be reached", func() {
+		BeforeEach(func() {
+			fakeLocker.FetchReturns(&locketmodels.FetchResponse{Resource: &locketmodels.Resource{Key: "cordon-cell-id"}}, nil)
+		})
+
+		It("keeps the cordon it observed last", func() {
+			Expect(watcher.Cordoned()).To(BeTrue())
+
+			fakeLocker.FetchReturns(nil, errors.New("unavailable"))
+			fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+			Eventually(logger).Should(gbytes.Say("failed-to-fetch-cordon"))
+			Expect(watcher.Cordoned()).To(BeTrue())
+		})
+	})
+})
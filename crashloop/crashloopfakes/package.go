@@ -0,0 +1 @@
+package crashloopfakes // import "code.cloudfoundry.org/rep/crashloop/crashloopfakes"
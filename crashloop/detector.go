@@ -0,0 +1,154 @@
+package crashloop
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// QuarantineReasonPrefix starts the crash reason reported to the BBS for an
+// instance the cell quarantined.
+const QuarantineReasonPrefix = "quarantined on cell after repeated crashes"
+
+//go:generate counterfeiter -o crashloopfakes/fake_detector.go . Detector
+
+// Detector notices LRP instances that keep crashing on the cell and
+// quarantines them, so that the cell stops accepting them and does not spend
+// its CPU restarting them over and over.
+type Detector interface {
+	// RecordCrash records a crash of the instance and reports whether it put
+	// the instance in quarantine.
+	RecordCrash(logger lager.Logger, processGuid string, index int32) bool
+
+	// Quarantined reports whether the instance is in quarantine.
+	Quarantined(processGuid string, index int32) bool
+
+	// Quarantines returns the instances in quarantine.
+	Quarantines() []rep.QuarantinedLRP
+}
+
+// QuarantineReason returns the crash reason reported to the BBS for an
+// instance quarantined after crashing with failureReason.
+func QuarantineReason(failureReason string) string {
+	if failureReason == "" {
+		return QuarantineReasonPrefix
+	}
+	return fmt.Sprintf("%s: %s", QuarantineReasonPrefix, failureReason)
+}
+
+type instance struct {
+	processGuid string
+	index       int32
+}
+
+type detector struct {
+	clock      clock.Clock
+	maxCrashes int
+	window     time.Duration
+	quarantine time.Duration
+
+	lock        sync.Mutex
+	crashes     map[instance][]time.Time
+	quarantined map[instance]time.Time
+}
+
+// New returns a Detector that quarantines an instance for quarantine once it
+// crashed more than maxCrashes times within window.
+func New(clock clock.Clock, maxCrashes int, window, quarantine time.Duration) Detector {
+	return &detector{
+		clock:       clock,
+		maxCrashes:  maxCrashes,
+		window:      window,
+		quarantine:  quarantine,
+		crashes:     map[instance][]time.Time{},
+		quarantined: map[instance]time.Time{},
+	}
+}
+
+func (d *detector) RecordCrash(logger lager.Logger, processGuid string, index int32) bool {
+	now := d.clock.Now()
+	key := instance{processGuid: processGuid, index: index}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.forgetExpired(now)
+
+	crashes := append(d.crashes[key], now)
+	if len(crashes) <= d.maxCrashes {
+		d.crashes[key] = crashes
+		return false
+	}
+
+	delete(d.crashes, key)
+	until := now.Add(d.quarantine)
+	d.quarantined[key] = until
+	logger.Info("quarantined-crashing-instance", lager.Data{
+		"process-guid": processGuid,
+		"index":        index,
+		"crashes":      len(crashes),
+		"until":        until,
+	})
+	return true
+}
+
+func (d *detector) Quarantined(processGuid string, index int32) bool {
+	now := d.clock.Now()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	until, ok := d.quarantined[instance{processGuid: processGuid, index: index}]
+	return ok && now.Before(until)
+}
+
+func (d *detector) Quarantines() []rep.QuarantinedLRP {
+	now := d.clock.Now()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.forgetExpired(now)
+
+	quarantines := make([]rep.QuarantinedLRP, 0, len(d.quarantined))
+	for key, until := range d.quarantined {
+		quarantines = append(quarantines, rep.QuarantinedLRP{ProcessGUID: key.processGuid, Index: key.index, Until: until.UnixNano()})
+	}
+	sort.Slice(quarantines, func(i, j int) bool {
+		if quarantines[i].ProcessGUID != quarantines[j].ProcessGUID {
+			return quarantines[i].ProcessGUID < quarantines[j].ProcessGUID
+		}
+		return quarantines[i].Index < quarantines[j].Index
+	})
+	return quarantines
+}
+
+// forgetExpired drops the crashes that happened before the window and the
+// quarantines that are over. It must be called with the lock held.
+func (d *detector) forgetExpired(now time.Time) {
+	cutoff := now.Add(-d.window)
+	for key, crashes := range d.crashes {
+		recent := crashes[:0]
+		for _, crashed := range crashes {
+			if crashed.After(cutoff) {
+				recent = append(recent, crashed)
+			}
+		}
+		if len(recent) == 0 {
+			delete(d.crashes, key)
+		} else {
+			d.crashes[key] = recent
+		}
+	}
+
+	for key, until := range d.quarantined {
+		if !now.Before(until) {
+			delete(d.quarantined, key)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package crashloop_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/crashloop"
+	"github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Detector", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		logger    *lagertest.TestLogger
+		detector  crashloop.Detector
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+		detector = crashloop.New(fakeClock, 2, 10*time.Minute, time.Hour)
+	})
+
+	crash := func(times int) bool {
+		quarantined := false
+		for i := 0; i < times; i++ {
+			quarantined = detector.RecordCrash(logger, "pg-1", 0)
+			fakeClock.Increment(time.Minute)
+		}
+		return quarantined
+	}
+
+	It("does not quarantine an instance that crashed at most the max crashes within the window", func() {
+		Expect(crash(2)).To(BeFalse())
+		Expect(detector.Quarantined("pg-1", 0)).To(BeFalse())
+		Expect(detector.Quarantines()).To(BeEmpty())
+	})
+
+	It("quarantines an instance that crashed more often within the window", func() {
+		start := fakeClock.Now()
+		Expect(crash(3)).To(BeTrue())
+
+		Expect(detector.Quarantined("pg-1", 0)).To(BeTrue())
+		Expect(detector.Quarantined("pg-1", 1)).To(BeFalse())
+		Expect(detector.Quarantines()).To(Equal([]rep.QuarantinedLRP{
+			{ProcessGUID: "pg-1", Index: 0, Until: start.Add(2*time.Minute + time.Hour).UnixNano()},
+		}))
+		Expect(logger).To(gbytes.Say("quarantined-crashing-instance"))
+	})
+
+	It("forgets crashes that happened before the window", func() {
+		Expect(crash(2)).To(BeFalse())
+		fakeClock.Increment(10 * time.Minute)
+		Expect(crash(1)).To(BeFalse())
+	})
+
+	It("releases the instance once the quarantine is over", func() {
+		Expect(crash(3)).To(BeTrue())
+		fakeClock.Increment(time.Hour)
+
+		Expect(detector.Quarantined("pg-1", 0)).To(BeFalse())
+		Expect(detector.Quarantines()).To(BeEmpty())
+	})
+
+	Describe("QuarantineReason", func() {
+		It("prefixes the failure reason", func() {
+			Expect(crashloop.QuarantineReason("exit status 1")).To(Equal(crashloop.QuarantineReasonPrefix + ": exit status 1"))
+			Expect(crashloop.QuarantineReason("")).To(Equal(crashloop.QuarantineReasonPrefix))
+		})
+	})
+})
@@ -0,0 +1 @@
+package crashloop // import "code.cloudfoundry.org/rep/crashloop"
@@ -0,0 +1,40 @@
+package rep
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrDeadlinePassed = errors.New("the deadline has already passed")
+
+// Deadline is an absolute deadline a caller gives a cell: ExpiresAt, in unix
+// nanoseconds on the clock of the caller, which read SentAt when it sent the
+// request. SentAt is optional, but without it the cell cannot tell how far
+// its clock is from the caller's.
+type Deadline struct {
+	ExpiresAt int64 `json:"expires_at"`
+	SentAt    int64 `json:"sent_at,omitempty"`
+}
+
+// NewDeadline returns the Deadline for expiresAt, sent at now.
+func NewDeadline(expiresAt, now time.Time) Deadline {
+	return Deadline{ExpiresAt: expiresAt.UnixNano(), SentAt: now.UnixNano()}
+}
+
+// Remaining returns how long remains until the deadline when the cell reads
+// now, and the skew between the clock of the cell and the caller's, which
+// includes the time the request took to arrive. As long as the skew is within
+// tolerance the remaining time is measured on the cell's clock; beyond it the
+// remaining time is measured on the caller's clock, from SentAt, so that a
+// clock running ahead or behind neither expires nor stretches the deadline.
+func (d Deadline) Remaining(now time.Time, tolerance time.Duration) (time.Duration, time.Duration) {
+	if d.SentAt == 0 {
+		return time.Duration(d.ExpiresAt - now.UnixNano()), 0
+	}
+
+	skew := time.Duration(now.UnixNano() - d.SentAt)
+	if skew <= tolerance && skew >= -tolerance {
+		return time.Duration(d.ExpiresAt - now.UnixNano()), skew
+	}
+	return time.Duration(d.ExpiresAt - d.SentAt), skew
+}
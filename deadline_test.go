@@ -0,0 +1,50 @@
+package rep_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deadline", func() {
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Unix(1700000000, 0)
+	})
+
+	It("measures a deadline without a send time on the clock of the cell", func() {
+		remaining, skew := rep.Deadline{ExpiresAt: now.Add(time.Minute).UnixNano()}.Remaining(now, time.Second)
+		Expect(remaining).To(Equal(time.Minute))
+		Expect(skew).To(BeZero())
+	})
+
+	It("measures the deadline on the clock of the cell while the skew is within tolerance", func() {
+		deadline := rep.NewDeadline(now.Add(time.Minute), now.Add(-500*time.Millisecond))
+
+		remaining, skew := deadline.Remaining(now, time.Second)
+		Expect(remaining).To(Equal(time.Minute))
+		Expect(skew).To(Equal(500 * time.Millisecond))
+	})
+
+	It("measures the deadline on the clock of the caller when the caller runs ahead", func() {
+		callerNow := now.Add(time.Hour)
+		deadline := rep.NewDeadline(callerNow.Add(time.Minute), callerNow)
+
+		remaining, skew := deadline.Remaining(now, time.Second)
+		Expect(remaining).To(Equal(time.Minute))
+		Expect(skew).To(Equal(-time.Hour))
+	})
+
+	It("measures the deadline on the clock of the caller when the caller runs behind", func() {
+		callerNow := now.Add(-time.Hour)
+		deadline := rep.NewDeadline(callerNow.Add(time.Minute), callerNow)
+
+		remaining, skew := deadline.Remaining(now, time.Second)
+		Expect(remaining).To(Equal(time.Minute))
+		Expect(skew).To(Equal(time.Hour))
+	})
+})
@@ -0,0 +1,65 @@
+package rep
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/executor"
+)
+
+// DirectedByTag and DirectedReasonTag record on its container who directed
+// an LRP instance or task to the cell, and why.
+const (
+	DirectedByTag     = "directed-by"
+	DirectedReasonTag = "directed-reason"
+)
+
+var (
+	ErrDirectedPlacementUnattributed = errors.New("a directed placement must be requested with a client certificate")
+	ErrDirectedToAnotherCell         = errors.New("the work is directed to another cell")
+)
+
+// DirectedPlacement marks an LRP instance or task that an operator pinned to
+// a cell instead of letting the auction score the cells for it. The cell
+// still checks that the work fits, as no scoring did, and records the
+// placement on the container so that it can be audited. The cell sets
+// RequestedBy to the common name of the client certificate the placement was
+// requested with, so that the audit record does not rest on what the caller
+// claims, and rejects placements requested without one.
+type DirectedPlacement struct {
+	CellID      string `json:"cell_id"`
+	RequestedBy string `json:"requested_by"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Validate returns an error unless the placement names who requested it and
+// is directed to cellID.
+func (d *DirectedPlacement) Validate(cellID string) error {
+	if d.RequestedBy == "" {
+		return ErrDirectedPlacementUnattributed
+	}
+	if d.CellID != cellID {
+		return ErrDirectedToAnotherCell
+	}
+	return nil
+}
+
+// AddDirectedPlacementTags records directed on the tags of a container. It
+// does nothing when directed is nil.
+func AddDirectedPlacementTags(tags executor.Tags, directed *DirectedPlacement) {
+	if directed == nil {
+		return
+	}
+	tags[DirectedByTag] = directed.RequestedBy
+	if directed.Reason != "" {
+		tags[DirectedReasonTag] = directed.Reason
+	}
+}
+
+// DirectedPlacementFailure records the directed LRP instance or task of a
+// Work that was rejected because it does not fit on the cell, or is not
+// directed to it.
+type DirectedPlacementFailure struct {
+	InstanceGUID string `json:"instance_guid,omitempty"`
+	TaskGuid     string `json:"task_guid,omitempty"`
+	Error        string `json:"error"`
+}
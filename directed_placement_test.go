@@ -0,0 +1,46 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirectedPlacement", func() {
+	var directed *rep.DirectedPlacement
+
+	BeforeEach(func() {
+		directed = &rep.DirectedPlacement{CellID: "cell-id", RequestedBy: "ops-pinning-tool", Reason: "hardware canary"}
+	})
+
+	Describe("Validate", func() {
+		It("accepts a placement directed to the cell by someone", func() {
+			Expect(directed.Validate("cell-id")).To(Succeed())
+		})
+
+		It("rejects a placement directed to another cell", func() {
+			Expect(directed.Validate("other-cell-id")).To(Equal(rep.ErrDirectedToAnotherCell))
+		})
+
+		It("rejects a placement that does not say who requested it", func() {
+			directed.RequestedBy = ""
+			Expect(directed.Validate("cell-id")).To(Equal(rep.ErrDirectedPlacementUnattributed))
+		})
+	})
+
+	Describe("AddDirectedPlacementTags", func() {
+		It("records who directed the work and why", func() {
+			tags := executor.Tags{}
+			rep.AddDirectedPlacementTags(tags, directed)
+			Expect(tags).To(Equal(executor.Tags{rep.DirectedByTag: "ops-pinning-tool", rep.DirectedReasonTag: "hardware canary"}))
+		})
+
+		It("adds nothing for work that is not directed", func() {
+			tags := executor.Tags{}
+			rep.AddDirectedPlacementTags(tags, nil)
+			Expect(tags).To(BeEmpty())
+		})
+	})
+})
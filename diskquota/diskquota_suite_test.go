@@ -0,0 +1,13 @@
+package diskquota_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestDiskquota(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diskquota Suite")
+}
@@ -0,0 +1 @@
+package diskquota // import "code.cloudfoundry.org/rep/diskquota"
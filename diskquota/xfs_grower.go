@@ -0,0 +1,129 @@
+package diskquota
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+var (
+	ErrImageNotFound  = errors.New("no image store holds the image of the container")
+	ErrNoProjectQuota = errors.New("the image of the container has no project quota")
+)
+
+// XFSGrower grows the disk quota of running containers by raising the XFS
+// project quota garden applies to their image. GrootFS keeps the image of a
+// container under the images directory of its store, named after the
+// container handle, and limits its disk through a project quota on that
+// directory, so the limit can be raised without restarting the container.
+type XFSGrower struct {
+	xfsIOPath    string
+	xfsQuotaPath string
+	storePaths   []string
+	timeout      time.Duration
+}
+
+// NewXFSGrower returns an XFSGrower for the images of the GrootFS stores at
+// storePaths, which reads the project of an image with the xfs_io binary at
+// xfsIOPath and sets its limit with the xfs_quota binary at xfsQuotaPath.
+func NewXFSGrower(xfsIOPath, xfsQuotaPath string, storePaths []string, timeout time.Duration) *XFSGrower {
+	return &XFSGrower{
+		xfsIOPath:    xfsIOPath,
+		xfsQuotaPath: xfsQuotaPath,
+		storePaths:   storePaths,
+		timeout:      timeout,
+	}
+}
+
+// GrowDiskQuota sets the hard block limit of the project of the image of the
+// container with guid to diskMB.
+func (g *XFSGrower) GrowDiskQuota(logger lager.Logger, guid string, diskMB int) error {
+	logger = logger.Session("xfs-grow-disk-quota", lager.Data{"container-guid": guid, "disk-mb": diskMB})
+
+	storePath, imagePath, err := g.image(guid)
+	if err != nil {
+		logger.Error("failed-to-find-image", err)
+		return err
+	}
+
+	output, err := g.run(g.xfsIOPath, "-r", "-c", "lsproj", imagePath)
+	if err != nil {
+		logger.Error("failed-to-read-project", err, lager.Data{"image": imagePath})
+		return err
+	}
+	projectID, err := parseProjectID(output)
+	if err != nil {
+		logger.Error("failed-to-parse-project", err, lager.Data{"image": imagePath})
+		return err
+	}
+
+	_, err = g.run(g.xfsQuotaPath, "-x", "-c", fmt.Sprintf("limit -p bhard=%dm %d", diskMB, projectID), storePath)
+	if err != nil {
+		logger.Error("failed-to-set-project-limit", err, lager.Data{"project-id": projectID})
+		return err
+	}
+
+	logger.Info("grew-project-limit", lager.Data{"project-id": projectID})
+	return nil
+}
+
+func (g *XFSGrower) image(guid string) (string, string, error) {
+	if guid == "" || strings.ContainsAny(guid, `/\`) || guid == "." || guid == ".." {
+		return "", "", ErrImageNotFound
+	}
+
+	for _, storePath := range g.storePaths {
+		imagePath := filepath.Join(storePath, "images", guid)
+		info, err := os.Stat(imagePath)
+		if err == nil && info.IsDir() {
+			return storePath, imagePath, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", "", err
+		}
+	}
+	return "", "", ErrImageNotFound
+}
+
+// parseProjectID parses the output of xfs_io lsproj, such as
+// "projid = 42".
+func parseProjectID(output []byte) (uint32, error) {
+	fields := strings.Fields(string(output))
+	if len(fields) != 3 || fields[0] != "projid" || fields[1] != "=" {
+		return 0, fmt.Errorf("unexpected lsproj output: %q", strings.TrimSpace(string(output)))
+	}
+
+	projectID, err := strconv.ParseUint(fields[2], 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if projectID == 0 {
+		return 0, ErrNoProjectQuota
+	}
+	return uint32(projectID), nil
+}
+
+func (g *XFSGrower) run(path string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, args...)
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %s: %s", filepath.Base(path), strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
@@ -0,0 +1,95 @@
+package diskquota_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/diskquota"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("XFSGrower", func() {
+	var (
+		logger       *lagertest.TestLogger
+		tmpDir       string
+		storePath    string
+		xfsIOPath    string
+		xfsQuotaPath string
+		callsPath    string
+		grower       *diskquota.XFSGrower
+	)
+
+	writeScript := func(path, script string) {
+		Expect(ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "xfs-grower")
+		Expect(err).NotTo(HaveOccurred())
+
+		storePath = filepath.Join(tmpDir, "store")
+		Expect(os.MkdirAll(filepath.Join(storePath, "images", "container-guid"), 0755)).To(Succeed())
+
+		callsPath = filepath.Join(tmpDir, "calls")
+		xfsIOPath = filepath.Join(tmpDir, "xfs_io")
+		xfsQuotaPath = filepath.Join(tmpDir, "xfs_quota")
+		writeScript(xfsIOPath, `echo "xfs_io $@" >> `+callsPath+`
+echo "projid = 42"
+`)
+		writeScript(xfsQuotaPath, `echo "xfs_quota $@" >> `+callsPath+"\n")
+
+		grower = diskquota.NewXFSGrower(xfsIOPath, xfsQuotaPath, []string{filepath.Join(tmpDir, "other-store"), storePath}, time.Second)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	calls := func() string {
+		contents, err := ioutil.ReadFile(callsPath)
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	It("raises the project limit of the image of the container", func() {
+		Expect(grower.GrowDiskQuota(logger, "container-guid", 2048)).To(Succeed())
+
+		imagePath := filepath.Join(storePath, "images", "container-guid")
+		Expect(calls()).To(Equal(
+			"xfs_io -r -c lsproj " + imagePath + "\n" +
+				"xfs_quota -x -c limit -p bhard=2048m 42 " + storePath + "\n",
+		))
+	})
+
+	It("fails when no store holds the image of the container", func() {
+		Expect(grower.GrowDiskQuota(logger, "missing-guid", 2048)).To(MatchError(diskquota.ErrImageNotFound))
+		Expect(grower.GrowDiskQuota(logger, "../store", 2048)).To(MatchError(diskquota.ErrImageNotFound))
+		_, err := os.Stat(callsPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("fails when the image has no project", func() {
+		writeScript(xfsIOPath, `echo "projid = 0"`+"\n")
+		Expect(grower.GrowDiskQuota(logger, "container-guid", 2048)).To(MatchError(diskquota.ErrNoProjectQuota))
+	})
+
+	It("fails when the project cannot be read", func() {
+		writeScript(xfsIOPath, "echo 'not an xfs filesystem' >&2\nexit 1\n")
+		err := grower.GrowDiskQuota(logger, "container-guid", 2048)
+		Expect(err).To(MatchError(ContainSubstring("not an xfs filesystem")))
+	})
+
+	It("fails when the limit cannot be set", func() {
+		writeScript(xfsQuotaPath, "echo 'permission denied' >&2\nexit 1\n")
+		err := grower.GrowDiskQuota(logger, "container-guid", 2048)
+		Expect(err).To(MatchError(ContainSubstring("permission denied")))
+	})
+})
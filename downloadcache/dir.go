@@ -0,0 +1,86 @@
+package downloadcache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Entry is a download cached by the executor. The executor names an entry
+// after the hex MD5 of its cache key, followed by a dash and a suffix that
+// tells successive downloads of the same key apart.
+type Entry struct {
+	Name      string
+	SizeBytes int64
+	ModTime   time.Time
+}
+
+//go:generate counterfeiter -o downloadcachefakes/fake_dir.go . Dir
+
+// Dir lists the downloads cached by the executor.
+type Dir interface {
+	Entries(logger lager.Logger) ([]Entry, error)
+}
+
+type dir struct {
+	path string
+}
+
+// NewDir returns a Dir for the executor cache at path. Files that are not
+// named like cached downloads, such as the saved state of the cache, are
+// skipped, and the size of an extracted download is that of its contents.
+func NewDir(path string) Dir {
+	return &dir{path: path}
+}
+
+func (d *dir) Entries(logger lager.Logger) ([]Entry, error) {
+	logger = logger.Session("download-cache-dir", lager.Data{"path": d.path})
+
+	infos, err := ioutil.ReadDir(d.path)
+	if err != nil {
+		logger.Error("failed-to-list-entries", err)
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(infos))
+	for _, info := range infos {
+		if !strings.Contains(info.Name(), "-") {
+			continue
+		}
+
+		size := info.Size()
+		if info.IsDir() {
+			size, err = dirSize(filepath.Join(d.path, info.Name()))
+			if err != nil {
+				logger.Error("failed-to-size-entry", err, lager.Data{"entry": info.Name()})
+				continue
+			}
+		}
+
+		entries = append(entries, Entry{
+			Name:      info.Name(),
+			SizeBytes: size,
+			ModTime:   info.ModTime(),
+		})
+	}
+
+	return entries, nil
+}
+
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
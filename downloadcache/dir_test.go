@@ -0,0 +1,61 @@
+package downloadcache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/downloadcache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dir", func() {
+	var (
+		cachePath string
+		logger    *lagertest.TestLogger
+	)
+
+	writeFile := func(name string, size int) {
+		path := filepath.Join(cachePath, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, make([]byte, size), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		cachePath, err = ioutil.TempDir("", "download-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(cachePath)
+	})
+
+	It("lists the cached downloads and their sizes", func() {
+		writeFile("abc-1-1", 100)
+		writeFile("def-2-2/bin/app", 30)
+		writeFile("def-2-2/lib/lib.so", 20)
+		writeFile("saved_cache.json", 10)
+
+		entries, err := downloadcache.NewDir(cachePath).Entries(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Name).To(Equal("abc-1-1"))
+		Expect(entries[0].SizeBytes).To(BeEquivalentTo(100))
+		Expect(entries[1].Name).To(Equal("def-2-2"))
+		Expect(entries[1].SizeBytes).To(BeEquivalentTo(50))
+	})
+
+	Context("when the cache does not exist", func() {
+		It("returns the error", func() {
+			_, err := downloadcache.NewDir(filepath.Join(cachePath, "missing")).Entries(logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package downloadcache_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestDownloadCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Download Cache Suite")
+}
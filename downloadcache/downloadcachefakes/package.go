@@ -0,0 +1 @@
+package downloadcachefakes // import "code.cloudfoundry.org/rep/downloadcache/downloadcachefakes"
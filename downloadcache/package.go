@@ -0,0 +1 @@
+package downloadcache // import "code.cloudfoundry.org/rep/downloadcache"
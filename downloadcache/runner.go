@@ -0,0 +1,44 @@
+package downloadcache
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+// Runner observes the download cache when it starts, to record what is
+// already cached, and every interval after that.
+type Runner struct {
+	logger   lager.Logger
+	tracker  Tracker
+	clock    clock.Clock
+	interval time.Duration
+}
+
+func NewRunner(logger lager.Logger, tracker Tracker, clock clock.Clock, interval time.Duration) *Runner {
+	return &Runner{
+		logger:   logger.Session("download-cache-tracker"),
+		tracker:  tracker,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+func (r *Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	r.tracker.Observe(r.logger)
+	close(ready)
+
+	ticker := r.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			r.tracker.Observe(r.logger)
+		case <-signals:
+			return nil
+		}
+	}
+}
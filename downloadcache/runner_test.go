@@ -0,0 +1,45 @@
+package downloadcache_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/downloadcache"
+	"code.cloudfoundry.org/rep/downloadcache/downloadcachefakes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		tracker   *downloadcachefakes.FakeTracker
+		fakeClock *fakeclock.FakeClock
+		process   ifrit.Process
+	)
+
+	BeforeEach(func() {
+		tracker = new(downloadcachefakes.FakeTracker)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		runner := downloadcache.NewRunner(lagertest.NewTestLogger("test"), tracker, fakeClock, time.Minute)
+		process = ginkgomon.Invoke(runner)
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+	})
+
+	It("observes the cache when it starts and every interval after that", func() {
+		Expect(tracker.ObserveCallCount()).To(Equal(1))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(tracker.ObserveCallCount).Should(Equal(2))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(tracker.ObserveCallCount).Should(Equal(3))
+	})
+})
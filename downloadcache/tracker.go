@@ -0,0 +1,290 @@
+package downloadcache
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+)
+
+// The kinds of cached downloads, told apart by their cache keys.
+const (
+	KindDroplet   = "droplet"
+	KindBuildpack = "buildpack"
+	KindOther     = "other"
+)
+
+const bytesPerMB = 1024 * 1024
+
+var kinds = []string{KindDroplet, KindBuildpack, KindOther}
+
+var metricPrefixes = map[string]string{
+	KindDroplet:   "DropletCache",
+	KindBuildpack: "BuildpackCache",
+	KindOther:     "DependencyCache",
+}
+
+// KindOf classifies a cache key by the names the Cloud Controller gives the
+// cache keys of droplets and buildpacks.
+func KindOf(cacheKey string) string {
+	switch {
+	case strings.HasPrefix(cacheKey, "droplets-"):
+		return KindDroplet
+	case strings.Contains(cacheKey, "buildpack"):
+		return KindBuildpack
+	default:
+		return KindOther
+	}
+}
+
+// KindStats counts, since the rep started, the downloads of a kind that were
+// served from the cache and those that were not, and the cached downloads of
+// the kind the executor evicted.
+type KindStats struct {
+	Hits         int   `json:"hits"`
+	Misses       int   `json:"misses"`
+	BytesSaved   int64 `json:"bytes_saved"`
+	Evictions    int   `json:"evictions"`
+	EvictedBytes int64 `json:"evicted_bytes"`
+}
+
+// Stats are the cache statistics of every kind of download, along with what
+// the cache held when it was last observed.
+type Stats struct {
+	Kinds         map[string]KindStats `json:"kinds"`
+	CachedEntries int                  `json:"cached_entries"`
+	CachedBytes   int64                `json:"cached_bytes"`
+}
+
+//go:generate counterfeiter -o downloadcachefakes/fake_tracker.go . Tracker
+
+// Tracker attributes the downloads of the containers on the cell to the
+// executor download cache.
+type Tracker interface {
+	Observe(logger lager.Logger) error
+	Stats() Stats
+}
+
+type tracker struct {
+	dir            Dir
+	executorClient executor.Client
+	metronClient   loggingclient.IngressClient
+
+	lock       sync.Mutex
+	observed   bool
+	containers map[string]struct{}
+	entries    map[string]Entry
+	keyKinds   map[string]string
+	stats      map[string]KindStats
+}
+
+func NewTracker(dir Dir, executorClient executor.Client, metronClient loggingclient.IngressClient) Tracker {
+	stats := make(map[string]KindStats, len(kinds))
+	for _, kind := range kinds {
+		stats[kind] = KindStats{}
+	}
+
+	return &tracker{
+		dir:            dir,
+		executorClient: executorClient,
+		metronClient:   metronClient,
+		containers:     map[string]struct{}{},
+		entries:        map[string]Entry{},
+		keyKinds:       map[string]string{},
+		stats:          stats,
+	}
+}
+
+// Observe compares the cache and the containers with what was observed
+// before. A cached download of a container that is new since then is a hit
+// when the cache held it before the container was allocated and a miss
+// otherwise, and an entry that has gone from the cache was evicted. The first
+// observation only records what is already there.
+func (t *tracker) Observe(logger lager.Logger) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	logger = logger.Session("observe-download-cache")
+
+	entries, err := t.dir.Entries(logger)
+	if err != nil {
+		return err
+	}
+
+	containers, err := t.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return err
+	}
+
+	current := make(map[string]Entry, len(entries))
+	oldest := map[string]Entry{}
+	for _, entry := range entries {
+		current[entry.Name] = entry
+		hash := entryKeyHash(entry.Name)
+		if cached, ok := oldest[hash]; !ok || entry.ModTime.Before(cached.ModTime) {
+			oldest[hash] = entry
+		}
+	}
+
+	seen := make(map[string]struct{}, len(containers))
+	keyKinds := map[string]string{}
+	for i := range containers {
+		container := &containers[i]
+		if container.State == executor.StateReserved {
+			continue
+		}
+		seen[container.Guid] = struct{}{}
+
+		_, known := t.containers[container.Guid]
+		allocatedAt := time.Unix(0, container.AllocatedAt)
+		for _, cacheKey := range cacheKeys(container) {
+			hash := keyHash(cacheKey)
+			kind := KindOf(cacheKey)
+			keyKinds[hash] = kind
+			if known || !t.observed {
+				continue
+			}
+
+			stats := t.stats[kind]
+			if entry, ok := oldest[hash]; ok && entry.ModTime.Before(allocatedAt) {
+				stats.Hits++
+				stats.BytesSaved += entry.SizeBytes
+			} else {
+				stats.Misses++
+			}
+			t.stats[kind] = stats
+		}
+	}
+
+	for name, entry := range t.entries {
+		if _, ok := current[name]; ok {
+			continue
+		}
+
+		kind, ok := t.keyKinds[entryKeyHash(name)]
+		if !ok {
+			kind = KindOther
+		}
+		stats := t.stats[kind]
+		stats.Evictions++
+		stats.EvictedBytes += entry.SizeBytes
+		t.stats[kind] = stats
+	}
+
+	for hash, kind := range t.keyKinds {
+		if _, ok := keyKinds[hash]; !ok {
+			if _, cached := oldest[hash]; cached {
+				keyKinds[hash] = kind
+			}
+		}
+	}
+
+	t.observed = true
+	t.containers = seen
+	t.entries = current
+	t.keyKinds = keyKinds
+
+	t.emitMetrics(logger)
+	return nil
+}
+
+func (t *tracker) Stats() Stats {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stats := Stats{Kinds: make(map[string]KindStats, len(t.stats))}
+	for kind, kindStats := range t.stats {
+		stats.Kinds[kind] = kindStats
+	}
+	for _, entry := range t.entries {
+		stats.CachedEntries++
+		stats.CachedBytes += entry.SizeBytes
+	}
+	return stats
+}
+
+func (t *tracker) emitMetrics(logger lager.Logger) {
+	for _, kind := range kinds {
+		prefix := metricPrefixes[kind]
+		stats := t.stats[kind]
+
+		err := t.metronClient.SendMetric(prefix+"Hits", stats.Hits)
+		if err != nil {
+			logger.Error("failed-to-send-cache-hits-metric", err, lager.Data{"kind": kind})
+		}
+
+		err = t.metronClient.SendMetric(prefix+"Misses", stats.Misses)
+		if err != nil {
+			logger.Error("failed-to-send-cache-misses-metric", err, lager.Data{"kind": kind})
+		}
+
+		err = t.metronClient.SendMetric(prefix+"Evictions", stats.Evictions)
+		if err != nil {
+			logger.Error("failed-to-send-cache-evictions-metric", err, lager.Data{"kind": kind})
+		}
+
+		err = t.metronClient.SendMebiBytes(prefix+"BytesSaved", int(stats.BytesSaved/bytesPerMB))
+		if err != nil {
+			logger.Error("failed-to-send-cache-bytes-saved-metric", err, lager.Data{"kind": kind})
+		}
+	}
+}
+
+func keyHash(cacheKey string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(cacheKey)))
+}
+
+func entryKeyHash(name string) string {
+	return strings.SplitN(name, "-", 2)[0]
+}
+
+// cacheKeys returns the cache keys of the cached dependencies of container
+// and of the downloads of its setup action.
+func cacheKeys(container *executor.Container) []string {
+	keys := []string{}
+	for _, dependency := range container.RunInfo.CachedDependencies {
+		if dependency.CacheKey != "" {
+			keys = append(keys, dependency.CacheKey)
+		}
+	}
+	return appendDownloadCacheKeys(keys, container.RunInfo.Setup)
+}
+
+func appendDownloadCacheKeys(keys []string, action *models.Action) []string {
+	if action == nil {
+		return keys
+	}
+
+	switch {
+	case action.DownloadAction != nil:
+		if action.DownloadAction.CacheKey != "" {
+			keys = append(keys, action.DownloadAction.CacheKey)
+		}
+	case action.SerialAction != nil:
+		for _, child := range action.SerialAction.Actions {
+			keys = appendDownloadCacheKeys(keys, child)
+		}
+	case action.ParallelAction != nil:
+		for _, child := range action.ParallelAction.Actions {
+			keys = appendDownloadCacheKeys(keys, child)
+		}
+	case action.CodependentAction != nil:
+		for _, child := range action.CodependentAction.Actions {
+			keys = appendDownloadCacheKeys(keys, child)
+		}
+	case action.TimeoutAction != nil:
+		keys = appendDownloadCacheKeys(keys, action.TimeoutAction.Action)
+	case action.TryAction != nil:
+		keys = appendDownloadCacheKeys(keys, action.TryAction.Action)
+	case action.EmitProgressAction != nil:
+		keys = appendDownloadCacheKeys(keys, action.EmitProgressAction.Action)
+	}
+	return keys
+}
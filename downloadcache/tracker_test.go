@@ -0,0 +1,183 @@
+package downloadcache_test
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/downloadcache"
+	"code.cloudfoundry.org/rep/downloadcache/downloadcachefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracker", func() {
+	const (
+		mb           = 1024 * 1024
+		dropletKey   = "droplets-pg-1"
+		buildpackKey = "ruby_buildpack-cflinuxfs4"
+	)
+
+	var (
+		dir            *downloadcachefakes.FakeDir
+		executorClient *fakes.FakeClient
+		metronClient   *mfakes.FakeIngressClient
+		tracker        downloadcache.Tracker
+		logger         *lagertest.TestLogger
+		now            time.Time
+		dropletEntry   downloadcache.Entry
+	)
+
+	entryFor := func(cacheKey string, size int64, modTime time.Time) downloadcache.Entry {
+		return downloadcache.Entry{
+			Name:      fmt.Sprintf("%x-%d-1", md5.Sum([]byte(cacheKey)), modTime.UnixNano()),
+			SizeBytes: size,
+			ModTime:   modTime,
+		}
+	}
+
+	containerWith := func(guid string, state executor.State, allocatedAt time.Time) executor.Container {
+		return executor.Container{
+			Guid:        guid,
+			State:       state,
+			AllocatedAt: allocatedAt.UnixNano(),
+			RunInfo: executor.RunInfo{
+				CachedDependencies: []executor.CachedDependency{{CacheKey: buildpackKey}},
+				Setup: models.WrapAction(models.Serial(
+					models.Timeout(&models.DownloadAction{CacheKey: dropletKey}, time.Minute),
+					&models.DownloadAction{From: "http://example.com/uncached"},
+				)),
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		now = time.Now()
+
+		dir = new(downloadcachefakes.FakeDir)
+		dropletEntry = entryFor(dropletKey, 10*mb, now.Add(-time.Hour))
+		dir.EntriesReturns([]downloadcache.Entry{dropletEntry}, nil)
+
+		executorClient = new(fakes.FakeClient)
+		executorClient.ListContainersReturns([]executor.Container{
+			containerWith("existing", executor.StateRunning, now.Add(-time.Hour)),
+		}, nil)
+
+		metronClient = new(mfakes.FakeIngressClient)
+		tracker = downloadcache.NewTracker(dir, executorClient, metronClient)
+	})
+
+	It("only records what is already there when first observing", func() {
+		Expect(tracker.Observe(logger)).To(Succeed())
+
+		stats := tracker.Stats()
+		Expect(stats.Kinds).To(Equal(map[string]downloadcache.KindStats{
+			downloadcache.KindDroplet:   {},
+			downloadcache.KindBuildpack: {},
+			downloadcache.KindOther:     {},
+		}))
+		Expect(stats.CachedEntries).To(Equal(1))
+		Expect(stats.CachedBytes).To(BeEquivalentTo(10 * mb))
+	})
+
+	Context("when new containers download through the cache", func() {
+		BeforeEach(func() {
+			Expect(tracker.Observe(logger)).To(Succeed())
+
+			executorClient.ListContainersReturns([]executor.Container{
+				containerWith("existing", executor.StateRunning, now.Add(-time.Hour)),
+				containerWith("new", executor.StateInitializing, now),
+				containerWith("reserved", executor.StateReserved, now),
+			}, nil)
+			dir.EntriesReturns([]downloadcache.Entry{
+				dropletEntry,
+				entryFor(buildpackKey, 5*mb, now.Add(time.Second)),
+			}, nil)
+			Expect(tracker.Observe(logger)).To(Succeed())
+		})
+
+		It("counts the downloads cached before the container was allocated as hits", func() {
+			stats := tracker.Stats().Kinds[downloadcache.KindDroplet]
+			Expect(stats).To(Equal(downloadcache.KindStats{Hits: 1, BytesSaved: 10 * mb}))
+		})
+
+		It("counts the downloads cached since the container was allocated as misses", func() {
+			stats := tracker.Stats().Kinds[downloadcache.KindBuildpack]
+			Expect(stats).To(Equal(downloadcache.KindStats{Misses: 1}))
+		})
+
+		It("counts the downloads of a container once", func() {
+			Expect(tracker.Observe(logger)).To(Succeed())
+			Expect(tracker.Stats().Kinds[downloadcache.KindDroplet].Hits).To(Equal(1))
+		})
+
+		It("emits the cache metrics", func() {
+			metrics := map[string]int{}
+			for i := 0; i < metronClient.SendMetricCallCount(); i++ {
+				name, value, _ := metronClient.SendMetricArgsForCall(i)
+				metrics[name] = value
+			}
+			Expect(metrics).To(HaveKeyWithValue("DropletCacheHits", 1))
+			Expect(metrics).To(HaveKeyWithValue("BuildpackCacheMisses", 1))
+
+			mebibytes := map[string]int{}
+			for i := 0; i < metronClient.SendMebiBytesCallCount(); i++ {
+				name, value, _ := metronClient.SendMebiBytesArgsForCall(i)
+				mebibytes[name] = value
+			}
+			Expect(mebibytes).To(HaveKeyWithValue("DropletCacheBytesSaved", 10))
+		})
+
+		Context("when a cached download is evicted", func() {
+			BeforeEach(func() {
+				dir.EntriesReturns([]downloadcache.Entry{entryFor(buildpackKey, 5*mb, now.Add(time.Second))}, nil)
+				Expect(tracker.Observe(logger)).To(Succeed())
+			})
+
+			It("counts the eviction against the kind of the download", func() {
+				stats := tracker.Stats()
+				Expect(stats.Kinds[downloadcache.KindDroplet].Evictions).To(Equal(1))
+				Expect(stats.Kinds[downloadcache.KindDroplet].EvictedBytes).To(BeEquivalentTo(10 * mb))
+				Expect(stats.CachedEntries).To(Equal(1))
+				Expect(stats.CachedBytes).To(BeEquivalentTo(5 * mb))
+			})
+		})
+	})
+
+	Context("when the cache cannot be listed", func() {
+		BeforeEach(func() {
+			dir.EntriesReturns(nil, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(tracker.Observe(logger)).To(MatchError("boom"))
+		})
+	})
+
+	Context("when the containers cannot be listed", func() {
+		BeforeEach(func() {
+			executorClient.ListContainersReturns(nil, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(tracker.Observe(logger)).To(MatchError("boom"))
+		})
+	})
+})
+
+var _ = Describe("KindOf", func() {
+	It("tells droplets, buildpacks and other dependencies apart", func() {
+		Expect(downloadcache.KindOf("droplets-pg-1")).To(Equal(downloadcache.KindDroplet))
+		Expect(downloadcache.KindOf("ruby_buildpack-cflinuxfs4")).To(Equal(downloadcache.KindBuildpack))
+		Expect(downloadcache.KindOf("lifecycle-buildpacks")).To(Equal(downloadcache.KindBuildpack))
+		Expect(downloadcache.KindOf("healthcheck")).To(Equal(downloadcache.KindOther))
+	})
+})
@@ -0,0 +1,64 @@
+package rep
+
+import "sort"
+
+// FailureDomainsOf returns the failure domains of graph that cellID belongs
+// to, sorted. The graph is supplied by the operator and lists the cells of
+// each domain, such as those sharing a hypervisor, a rack or a storage array,
+// which are likely to fail together.
+func FailureDomainsOf(graph map[string][]string, cellID string) []string {
+	domains := []string{}
+	for domain, cellIDs := range graph {
+		for _, id := range cellIDs {
+			if id == cellID {
+				domains = append(domains, domain)
+				break
+			}
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// SharesFailureDomain reports whether the cell belongs to one of the failure
+// domains of other.
+func (c *CellState) SharesFailureDomain(other *CellState) bool {
+	for _, domain := range c.FailureDomains {
+		for _, otherDomain := range other.FailureDomains {
+			if domain == otherDomain {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CorrelatedFailurePenalty returns the FailureDomainPenalty of the cell when
+// one of cells sharing a failure domain with it, the cell itself included,
+// runs another instance of the process of lrp, and 0 otherwise. Placing the
+// instance elsewhere keeps a single failure from taking down several
+// instances of the process.
+func (c *CellState) CorrelatedFailurePenalty(lrp *LRP, cells []CellState) float64 {
+	if c.FailureDomainPenalty == 0 || len(c.FailureDomains) == 0 {
+		return 0
+	}
+	for i := range cells {
+		if !c.SharesFailureDomain(&cells[i]) {
+			continue
+		}
+		for j := range cells[i].LRPs {
+			other := &cells[i].LRPs[j]
+			if other.ProcessGuid == lrp.ProcessGuid && other.Index != lrp.Index {
+				return c.FailureDomainPenalty
+			}
+		}
+	}
+	return 0
+}
+
+// ComputeLRPScoreAmong scores the cell for lrp like ComputeLRPScore, raising
+// the score by its CorrelatedFailurePenalty among cells, the states of all the
+// cells the instance may be placed on.
+func (c CellState) ComputeLRPScoreAmong(lrp *LRP, startingContainerWeight float64, strategy ScoringStrategy, cells []CellState) float64 {
+	return c.ComputeLRPScore(lrp, startingContainerWeight, strategy) + c.CorrelatedFailurePenalty(lrp, cells)
+}
@@ -0,0 +1,67 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FailureDomains", func() {
+	instance := func(processGuid string, index int32) rep.LRP {
+		return rep.LRP{InstanceGUID: "ig", ActualLRPKey: models.NewActualLRPKey(processGuid, index, "domain")}
+	}
+
+	It("returns the sorted failure domains a cell belongs to", func() {
+		graph := map[string][]string{
+			"rack-r1":    {"cell-1", "cell-2"},
+			"array-a":    {"cell-2", "cell-3"},
+			"hypervisor": {"cell-3"},
+		}
+		Expect(rep.FailureDomainsOf(graph, "cell-2")).To(Equal([]string{"array-a", "rack-r1"}))
+		Expect(rep.FailureDomainsOf(graph, "cell-4")).To(BeEmpty())
+		Expect(rep.FailureDomainsOf(nil, "cell-1")).To(BeEmpty())
+	})
+
+	Describe("CorrelatedFailurePenalty", func() {
+		var (
+			cell, sameRack, otherRack rep.CellState
+			lrp                       rep.LRP
+		)
+
+		BeforeEach(func() {
+			cell = rep.CellState{CellID: "cell-1", FailureDomains: []string{"rack-r1"}, FailureDomainPenalty: 0.2}
+			sameRack = rep.CellState{CellID: "cell-2", FailureDomains: []string{"array-a", "rack-r1"}}
+			otherRack = rep.CellState{CellID: "cell-3", FailureDomains: []string{"rack-r2"}}
+			lrp = instance("pg-1", 0)
+		})
+
+		It("penalizes the cell when a cell of its domain runs another instance of the process", func() {
+			sameRack.LRPs = []rep.LRP{instance("pg-1", 1)}
+			cells := []rep.CellState{cell, sameRack, otherRack}
+
+			Expect(cell.CorrelatedFailurePenalty(&lrp, cells)).To(Equal(0.2))
+			Expect(cell.ComputeLRPScoreAmong(&lrp, 0.25, nil, cells)).To(BeNumerically("~", cell.ComputeLRPScore(&lrp, 0.25, nil)+0.2, 0.0001))
+		})
+
+		It("penalizes the cell when it runs another instance of the process itself", func() {
+			cell.LRPs = []rep.LRP{instance("pg-1", 1)}
+			Expect(cell.CorrelatedFailurePenalty(&lrp, []rep.CellState{cell})).To(Equal(0.2))
+		})
+
+		It("does not penalize the cell for instances outside its domains or of other processes", func() {
+			otherRack.LRPs = []rep.LRP{instance("pg-1", 1)}
+			sameRack.LRPs = []rep.LRP{instance("pg-2", 1), instance("pg-1", 0)}
+
+			Expect(cell.CorrelatedFailurePenalty(&lrp, []rep.CellState{cell, sameRack, otherRack})).To(BeZero())
+		})
+
+		It("does not penalize a cell without failure domains", func() {
+			cell.FailureDomains = nil
+			sameRack.LRPs = []rep.LRP{instance("pg-1", 1)}
+
+			Expect(cell.CorrelatedFailurePenalty(&lrp, []rep.CellState{sameRack})).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1 @@
+package fairqueuefakes // import "code.cloudfoundry.org/rep/fairqueue/fairqueuefakes"
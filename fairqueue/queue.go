@@ -0,0 +1,209 @@
+package fairqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	acceptedRequestsMetric = "FairQueueAcceptedRequests"
+	rejectedRequestsMetric = "FairQueueRejectedRequests"
+)
+
+var ErrQueueFull = errors.New("too many requests are queued for the caller")
+
+//go:generate counterfeiter -o fairqueuefakes/fake_queue.go . Queue
+
+// Queue admits requests from competing callers. At most maxInFlight requests
+// proceed at once; the others wait in a queue per caller, and the queues are
+// served round robin so that a burst from one caller cannot starve the
+// others. The requests accepted and rejected are counted across callers, and
+// logged with the running count of their caller.
+type Queue interface {
+	// Admit blocks until the caller's request may proceed and returns the
+	// function that must be called once the request is done. It returns
+	// ErrQueueFull when the caller already has maxQueuedPerCaller requests
+	// waiting, and the context's error when it is done before the request is
+	// admitted.
+	Admit(ctx context.Context, logger lager.Logger, caller string) (func(), error)
+
+	// Waiting returns the number of requests of the caller waiting to be
+	// admitted.
+	Waiting(caller string) int
+
+	// Stats returns how many requests are in flight and waiting across all
+	// callers.
+	Stats() Stats
+}
+
+// Stats are the requests in flight and waiting in a Queue, against the
+// maximum number of requests in flight.
+type Stats struct {
+	InFlight    int
+	MaxInFlight int
+	Waiting     int
+}
+
+type waiter struct {
+	admitted chan struct{}
+}
+
+type queue struct {
+	maxInFlight        int
+	maxQueuedPerCaller int
+	metronClient       loggingclient.IngressClient
+
+	mu       sync.Mutex
+	inFlight int
+	waiting  map[string][]*waiter
+	// callers are the callers with waiting requests, in the order they are
+	// served.
+	callers  []string
+	accepted map[string]int
+	rejected map[string]int
+}
+
+func New(maxInFlight, maxQueuedPerCaller int, metronClient loggingclient.IngressClient) Queue {
+	return &queue{
+		maxInFlight:        maxInFlight,
+		maxQueuedPerCaller: maxQueuedPerCaller,
+		metronClient:       metronClient,
+		waiting:            map[string][]*waiter{},
+		accepted:           map[string]int{},
+		rejected:           map[string]int{},
+	}
+}
+
+func (q *queue) Admit(ctx context.Context, logger lager.Logger, caller string) (func(), error) {
+	logger = logger.Session("fair-queue-admit", lager.Data{"caller": caller})
+
+	q.mu.Lock()
+	if q.inFlight < q.maxInFlight && len(q.callers) == 0 {
+		q.inFlight++
+		accepted := q.accept(caller)
+		q.mu.Unlock()
+		logger.Debug("admitted", lager.Data{"accepted": accepted})
+		q.emit(logger, acceptedRequestsMetric)
+		return q.release, nil
+	}
+
+	if len(q.waiting[caller]) >= q.maxQueuedPerCaller {
+		q.rejected[caller]++
+		rejected := q.rejected[caller]
+		q.mu.Unlock()
+		logger.Info("queue-full", lager.Data{"max-queued": q.maxQueuedPerCaller, "rejected": rejected})
+		q.emit(logger, rejectedRequestsMetric)
+		return nil, ErrQueueFull
+	}
+
+	w := &waiter{admitted: make(chan struct{})}
+	if len(q.waiting[caller]) == 0 {
+		q.callers = append(q.callers, caller)
+	}
+	q.waiting[caller] = append(q.waiting[caller], w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.admitted:
+		q.mu.Lock()
+		accepted := q.accept(caller)
+		q.mu.Unlock()
+		logger.Debug("admitted", lager.Data{"accepted": accepted})
+		q.emit(logger, acceptedRequestsMetric)
+		return q.release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		removed := q.remove(caller, w)
+		q.mu.Unlock()
+		if !removed {
+			// the request was admitted while the context was done, hand its
+			// slot to the next waiting request
+			q.release()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (q *queue) Waiting(caller string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting[caller])
+}
+
+func (q *queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := Stats{InFlight: q.inFlight, MaxInFlight: q.maxInFlight}
+	for _, waiters := range q.waiting {
+		stats.Waiting += len(waiters)
+	}
+	return stats
+}
+
+func (q *queue) accept(caller string) int {
+	q.accepted[caller]++
+	return q.accepted[caller]
+}
+
+// release hands the slot of a finished request to the waiting request of the
+// next caller, or frees it when no request is waiting.
+func (q *queue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.callers) == 0 {
+		q.inFlight--
+		return
+	}
+
+	caller := q.callers[0]
+	waiters := q.waiting[caller]
+	next := waiters[0]
+	q.callers = q.callers[1:]
+	if len(waiters) == 1 {
+		delete(q.waiting, caller)
+	} else {
+		q.waiting[caller] = waiters[1:]
+		q.callers = append(q.callers, caller)
+	}
+	close(next.admitted)
+}
+
+// remove removes w from the caller's queue and reports whether it was still
+// waiting.
+func (q *queue) remove(caller string, w *waiter) bool {
+	waiters := q.waiting[caller]
+	for i := range waiters {
+		if waiters[i] != w {
+			continue
+		}
+
+		waiters = append(waiters[:i:i], waiters[i+1:]...)
+		if len(waiters) > 0 {
+			q.waiting[caller] = waiters
+			return true
+		}
+
+		delete(q.waiting, caller)
+		for j := range q.callers {
+			if q.callers[j] == caller {
+				q.callers = append(q.callers[:j:j], q.callers[j+1:]...)
+				break
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (q *queue) emit(logger lager.Logger, name string) {
+	err := q.metronClient.IncrementCounter(name)
+	if err != nil {
+		logger.Error("failed-to-send-metric", err, lager.Data{"metric": name})
+	}
+}
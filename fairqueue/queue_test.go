@@ -0,0 +1,149 @@
+package fairqueue_test
+
+import (
+	"context"
+	"sync"
+
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/fairqueue"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue", func() {
+	var (
+		logger       *lagertest.TestLogger
+		metronClient *mfakes.FakeIngressClient
+		queue        fairqueue.Queue
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		metronClient = new(mfakes.FakeIngressClient)
+		queue = fairqueue.New(1, 2, metronClient)
+	})
+
+	admitInBackground := func(caller string, admitted chan<- string) {
+		go func() {
+			defer GinkgoRecover()
+			release, err := queue.Admit(context.Background(), logger, caller)
+			Expect(err).NotTo(HaveOccurred())
+			admitted <- caller
+			release()
+		}()
+	}
+
+	It("admits requests while there is capacity", func() {
+		release, err := queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+		release()
+
+		release, err = queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+		release()
+	})
+
+	It("counts the accepted requests", func() {
+		release, err := queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+		release()
+
+		Expect(metronClient.IncrementCounterCallCount()).To(Equal(1))
+		Expect(metronClient.IncrementCounterArgsForCall(0)).To(Equal("FairQueueAcceptedRequests"))
+	})
+
+	It("serves the queued requests of the callers round robin", func() {
+		release, err := queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+
+		// hold the requests in the order they are admitted until all of
+		// them are queued
+		admitted := make(chan string)
+		var order []string
+		var mu sync.Mutex
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < 3; i++ {
+				caller := <-admitted
+				mu.Lock()
+				order = append(order, caller)
+				mu.Unlock()
+			}
+			close(done)
+		}()
+
+		admitInBackground("auctioneer-a", admitted)
+		Eventually(func() int { return queue.Waiting("auctioneer-a") }).Should(Equal(1))
+		admitInBackground("auctioneer-a", admitted)
+		Eventually(func() int { return queue.Waiting("auctioneer-a") }).Should(Equal(2))
+		admitInBackground("auctioneer-b", admitted)
+		Eventually(func() int { return queue.Waiting("auctioneer-b") }).Should(Equal(1))
+
+		release()
+		Eventually(done).Should(BeClosed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(order).To(Equal([]string{"auctioneer-a", "auctioneer-b", "auctioneer-a"}))
+	})
+
+	It("rejects requests once the queue of the caller is full", func() {
+		release, err := queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+		defer release()
+
+		admitted := make(chan string, 3)
+		admitInBackground("auctioneer-a", admitted)
+		admitInBackground("auctioneer-a", admitted)
+		Eventually(func() int { return queue.Waiting("auctioneer-a") }).Should(Equal(2))
+
+		_, err = queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).To(Equal(fairqueue.ErrQueueFull))
+		Expect(metronClient.IncrementCounterArgsForCall(metronClient.IncrementCounterCallCount() - 1)).To(Equal("FairQueueRejectedRequests"))
+
+		By("still queueing the requests of other callers")
+		admitInBackground("auctioneer-b", admitted)
+		Eventually(func() int { return queue.Waiting("auctioneer-b") }).Should(Equal(1))
+	})
+
+	It("reports the requests in flight and waiting across callers", func() {
+		Expect(queue.Stats()).To(Equal(fairqueue.Stats{MaxInFlight: 1}))
+
+		release, err := queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+
+		admitted := make(chan string, 2)
+		admitInBackground("auctioneer-a", admitted)
+		admitInBackground("auctioneer-b", admitted)
+		Eventually(queue.Stats).Should(Equal(fairqueue.Stats{InFlight: 1, MaxInFlight: 1, Waiting: 2}))
+
+		release()
+		Eventually(admitted).Should(Receive())
+		Eventually(admitted).Should(Receive())
+		Eventually(queue.Stats).Should(Equal(fairqueue.Stats{MaxInFlight: 1}))
+	})
+
+	It("stops waiting when the context is done", func() {
+		release, err := queue.Admit(context.Background(), logger, "auctioneer-a")
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		go func() {
+			_, err := queue.Admit(ctx, logger, "auctioneer-b")
+			errs <- err
+		}()
+		Eventually(func() int { return queue.Waiting("auctioneer-b") }).Should(Equal(1))
+
+		cancel()
+		Eventually(errs).Should(Receive(Equal(context.Canceled)))
+		Expect(queue.Waiting("auctioneer-b")).To(Equal(0))
+
+		release()
+		release, err = queue.Admit(context.Background(), logger, "auctioneer-b")
+		Expect(err).NotTo(HaveOccurred())
+		release()
+	})
+})
@@ -0,0 +1,109 @@
+package featureflags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// StateV2Schema serves the cell state as a delta against a state the
+	// client already has, when it asks for one. Without it the full state is
+	// always served.
+	StateV2Schema = "state_v2_schema"
+	// ProxyOverhead accounts for the envoy proxy's memory when reporting
+	// capacity and allocating containers.
+	ProxyOverhead = "proxy_overhead"
+	// LocalRestart restarts crashed LRP instances on the cell instead of
+	// handing them back to the BBS, unless they crash in a loop.
+	LocalRestart = "local_restart"
+)
+
+// defaults holds the value of every known flag that is not explicitly
+// configured.
+var defaults = map[string]bool{
+	StateV2Schema: false,
+	ProxyOverhead: true,
+	LocalRestart:  false,
+}
+
+// UnknownFlagsError is returned for configured flags that are not known.
+type UnknownFlagsError struct {
+	Names []string
+}
+
+func (e UnknownFlagsError) Error() string {
+	return fmt.Sprintf("unknown feature flags: %s", strings.Join(e.Names, ", "))
+}
+
+// Validate returns an UnknownFlagsError when configured holds flags that are
+// not known, so that a misspelt flag is not silently left at its default.
+func Validate(configured map[string]bool) error {
+	unknown := []string{}
+	for name := range configured {
+		if _, ok := defaults[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return UnknownFlagsError{Names: unknown}
+}
+
+// Flags is the set of feature flags the rep is currently running with. It is
+// safe for concurrent use and may be updated while the rep is running.
+type Flags struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+}
+
+func New(configured map[string]bool) *Flags {
+	flags := &Flags{}
+	flags.Update(configured)
+	return flags
+}
+
+// Update replaces the configured flags. Flags missing from configured revert
+// to their default, and flags that are not known are ignored.
+func (f *Flags) Update(configured map[string]bool) {
+	enabled := make(map[string]bool, len(defaults))
+	for name, value := range defaults {
+		enabled[name] = value
+	}
+	for name, value := range configured {
+		if _, ok := defaults[name]; ok {
+			enabled[name] = value
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.enabled = enabled
+}
+
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.enabled[name]
+}
+
+// EnabledFlags returns the sorted names of all enabled flags.
+func (f *Flags) EnabledFlags() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	names := []string{}
+	for name, value := range f.enabled {
+		if value {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
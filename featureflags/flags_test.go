@@ -0,0 +1,74 @@
+package featureflags_test
+
+import (
+	"code.cloudfoundry.org/rep/featureflags"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flags", func() {
+	It("uses the defaults for flags that are not configured", func() {
+		flags := featureflags.New(nil)
+		Expect(flags.Enabled(featureflags.ProxyOverhead)).To(BeTrue())
+		Expect(flags.Enabled(featureflags.StateV2Schema)).To(BeFalse())
+		Expect(flags.Enabled(featureflags.LocalRestart)).To(BeFalse())
+	})
+
+	It("overrides the defaults with the configured flags", func() {
+		flags := featureflags.New(map[string]bool{
+			featureflags.ProxyOverhead: false,
+			featureflags.LocalRestart:  true,
+		})
+		Expect(flags.Enabled(featureflags.ProxyOverhead)).To(BeFalse())
+		Expect(flags.Enabled(featureflags.LocalRestart)).To(BeTrue())
+	})
+
+	It("does not enable unknown flags", func() {
+		flags := featureflags.New(map[string]bool{"unknown": true})
+		Expect(flags.Enabled("unknown")).To(BeFalse())
+		Expect(flags.EnabledFlags()).To(Equal([]string{featureflags.ProxyOverhead}))
+	})
+
+	Describe("Validate", func() {
+		It("accepts the known flags", func() {
+			Expect(featureflags.Validate(map[string]bool{
+				featureflags.StateV2Schema: true,
+				featureflags.ProxyOverhead: false,
+				featureflags.LocalRestart:  true,
+			})).To(Succeed())
+			Expect(featureflags.Validate(nil)).To(Succeed())
+		})
+
+		It("rejects unknown flags", func() {
+			err := featureflags.Validate(map[string]bool{
+				featureflags.LocalRestart: true,
+				"local_restarts":          true,
+				"proxy_overheads":         false,
+			})
+			Expect(err).To(MatchError(featureflags.UnknownFlagsError{Names: []string{"local_restarts", "proxy_overheads"}}))
+			Expect(err).To(MatchError("unknown feature flags: local_restarts, proxy_overheads"))
+		})
+	})
+
+	It("returns the sorted names of the enabled flags", func() {
+		flags := featureflags.New(map[string]bool{
+			featureflags.StateV2Schema: true,
+			featureflags.LocalRestart:  true,
+		})
+		Expect(flags.EnabledFlags()).To(Equal([]string{
+			featureflags.LocalRestart,
+			featureflags.ProxyOverhead,
+			featureflags.StateV2Schema,
+		}))
+	})
+
+	Describe("Update", func() {
+		It("reverts flags that are no longer configured to their default", func() {
+			flags := featureflags.New(map[string]bool{featureflags.ProxyOverhead: false})
+			flags.Update(map[string]bool{featureflags.LocalRestart: true})
+
+			Expect(flags.Enabled(featureflags.ProxyOverhead)).To(BeTrue())
+			Expect(flags.Enabled(featureflags.LocalRestart)).To(BeTrue())
+		})
+	})
+})
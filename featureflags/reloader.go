@@ -0,0 +1,49 @@
+package featureflags
+
+import (
+	"os"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+// LoadFunc returns the currently configured feature flags.
+type LoadFunc func() (map[string]bool, error)
+
+type reloader struct {
+	logger lager.Logger
+	flags  *Flags
+	reload <-chan os.Signal
+	load   LoadFunc
+}
+
+// NewReloader returns a runner that reloads the flags with load every time a
+// signal is received on reload. A failed load leaves the flags untouched.
+func NewReloader(logger lager.Logger, flags *Flags, reload <-chan os.Signal, load LoadFunc) ifrit.Runner {
+	return &reloader{
+		logger: logger.Session("feature-flags-reloader"),
+		flags:  flags,
+		reload: reload,
+		load:   load,
+	}
+}
+
+func (r *reloader) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	for {
+		select {
+		case <-signals:
+			return nil
+		case <-r.reload:
+			configured, err := r.load()
+			if err != nil {
+				r.logger.Error("failed-to-reload", err)
+				continue
+			}
+
+			r.flags.Update(configured)
+			r.logger.Info("reloaded", lager.Data{"enabled": r.flags.EnabledFlags()})
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package featureflags_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/featureflags"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Reloader", func() {
+	var (
+		logger     *lagertest.TestLogger
+		flags      *featureflags.Flags
+		reload     chan os.Signal
+		configured map[string]bool
+		loadErr    error
+		process    ifrit.Process
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		flags = featureflags.New(nil)
+		reload = make(chan os.Signal)
+		configured = map[string]bool{featureflags.LocalRestart: true}
+		loadErr = nil
+	})
+
+	JustBeforeEach(func() {
+		load := func() (map[string]bool, error) {
+			return configured, loadErr
+		}
+		process = ifrit.Invoke(featureflags.NewReloader(logger, flags, reload, load))
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("reloads the flags when signalled", func() {
+		Expect(flags.Enabled(featureflags.LocalRestart)).To(BeFalse())
+		reload <- syscall.SIGHUP
+		Eventually(func() bool { return flags.Enabled(featureflags.LocalRestart) }).Should(BeTrue())
+	})
+
+	Context("when loading the flags fails", func() {
+		BeforeEach(func() {
+			loadErr = errors.New("boom")
+		})
+
+		It("keeps the current flags", func() {
+			reload <- syscall.SIGHUP
+			Eventually(logger.Buffer()).Should(gbytes.Say("failed-to-reload"))
+			Expect(flags.Enabled(featureflags.LocalRestart)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package rep
+
+import "sort"
+
+// FragmentationPool is the free capacity of one backend of a cell and the
+// LRP instances running on it. An instance has to fit in a single pool.
+type FragmentationPool struct {
+	Backend   string
+	Available Resources
+	LRPs      []LRP
+}
+
+// FragmentationReport compares the largest instance a cell can still place
+// with the resources it has free. A score of 0 means a single instance could
+// use all of the free memory or disk; the closer to 1, the more of it is
+// stranded in pools, or behind exhausted container slots, no instance fits.
+type FragmentationReport struct {
+	FreeMemoryMB             int32        `json:"free_memory_mb"`
+	FreeDiskMB               int32        `json:"free_disk_mb"`
+	LargestPlaceableMemoryMB int32        `json:"largest_placeable_memory_mb"`
+	LargestPlaceableDiskMB   int32        `json:"largest_placeable_disk_mb"`
+	MemoryScore              float64      `json:"memory_score"`
+	DiskScore                float64      `json:"disk_score"`
+	Relocations              []Relocation `json:"relocations,omitempty"`
+}
+
+// Relocation suggests moving an LRP instance off the cell, with the largest
+// instance the cell could place and its scores once the instance has moved.
+type Relocation struct {
+	InstanceGUID             string  `json:"instance_guid"`
+	ProcessGuid              string  `json:"process_guid"`
+	Index                    int32   `json:"index"`
+	Backend                  string  `json:"backend"`
+	LargestPlaceableMemoryMB int32   `json:"largest_placeable_memory_mb"`
+	LargestPlaceableDiskMB   int32   `json:"largest_placeable_disk_mb"`
+	MemoryScore              float64 `json:"memory_score"`
+	DiskScore                float64 `json:"disk_score"`
+}
+
+// AnalyzeFragmentation scores the fragmentation of pools and suggests the
+// relocations that would lower it, least fragmented outcome first. The
+// largest placeable instance is that of the pool with the most placeable
+// memory, capped by maxContainer where it is set.
+func AnalyzeFragmentation(pools []FragmentationPool, maxContainer Resource) FragmentationReport {
+	report := fragmentation(pools, maxContainer)
+
+	for i := range pools {
+		for _, lrp := range pools[i].LRPs {
+			relocated := make([]FragmentationPool, len(pools))
+			copy(relocated, pools)
+			relocated[i].Available.MemoryMB += lrp.MemoryMB
+			relocated[i].Available.DiskMB += lrp.DiskMB
+			relocated[i].Available.Containers++
+
+			after := fragmentation(relocated, maxContainer)
+			if !lessFragmented(after, report) {
+				continue
+			}
+
+			report.Relocations = append(report.Relocations, Relocation{
+				InstanceGUID:             lrp.InstanceGUID,
+				ProcessGuid:              lrp.ProcessGuid,
+				Index:                    lrp.Index,
+				Backend:                  pools[i].Backend,
+				LargestPlaceableMemoryMB: after.LargestPlaceableMemoryMB,
+				LargestPlaceableDiskMB:   after.LargestPlaceableDiskMB,
+				MemoryScore:              after.MemoryScore,
+				DiskScore:                after.DiskScore,
+			})
+		}
+	}
+
+	sort.Slice(report.Relocations, func(i, j int) bool {
+		a, b := report.Relocations[i], report.Relocations[j]
+		if a.MemoryScore != b.MemoryScore {
+			return a.MemoryScore < b.MemoryScore
+		}
+		if a.DiskScore != b.DiskScore {
+			return a.DiskScore < b.DiskScore
+		}
+		return a.InstanceGUID < b.InstanceGUID
+	})
+
+	return report
+}
+
+func fragmentation(pools []FragmentationPool, maxContainer Resource) FragmentationReport {
+	report := FragmentationReport{}
+
+	for i := range pools {
+		available := pools[i].Available
+		report.FreeMemoryMB += nonNegative(available.MemoryMB)
+		report.FreeDiskMB += nonNegative(available.DiskMB)
+
+		if available.Containers < 1 {
+			continue
+		}
+
+		memoryMB := capped(nonNegative(available.MemoryMB), maxContainer.MemoryMB)
+		diskMB := capped(nonNegative(available.DiskMB), maxContainer.DiskMB)
+		if memoryMB > report.LargestPlaceableMemoryMB ||
+			(memoryMB == report.LargestPlaceableMemoryMB && diskMB > report.LargestPlaceableDiskMB) {
+			report.LargestPlaceableMemoryMB = memoryMB
+			report.LargestPlaceableDiskMB = diskMB
+		}
+	}
+
+	report.MemoryScore = fragmentationScore(report.LargestPlaceableMemoryMB, report.FreeMemoryMB)
+	report.DiskScore = fragmentationScore(report.LargestPlaceableDiskMB, report.FreeDiskMB)
+	return report
+}
+
+func lessFragmented(report, than FragmentationReport) bool {
+	if report.MemoryScore != than.MemoryScore {
+		return report.MemoryScore < than.MemoryScore
+	}
+	return report.DiskScore < than.DiskScore
+}
+
+func fragmentationScore(largest, free int32) float64 {
+	if free <= 0 {
+		return 0
+	}
+	return 1 - float64(largest)/float64(free)
+}
+
+func nonNegative(value int32) int32 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+func capped(value, limit int32) int32 {
+	if limit > 0 && value > limit {
+		return limit
+	}
+	return value
+}
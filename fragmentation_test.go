@@ -0,0 +1,122 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AnalyzeFragmentation", func() {
+	var (
+		lrpA, lrpB rep.LRP
+		pools      []rep.FragmentationPool
+	)
+
+	BeforeEach(func() {
+		lrpA = rep.NewLRP("ig-a", models.NewActualLRPKey("pg-a", 0, "domain"), rep.NewResource(200, 200, 10), rep.PlacementConstraint{})
+		lrpB = rep.NewLRP("ig-b", models.NewActualLRPKey("pg-b", 1, "domain"), rep.NewResource(500, 500, 10), rep.PlacementConstraint{})
+	})
+
+	Context("when all the free resources are in one pool", func() {
+		BeforeEach(func() {
+			pools = []rep.FragmentationPool{
+				{Backend: "linux", Available: rep.NewResources(1000, 2000, 4), LRPs: []rep.LRP{lrpA, lrpB}},
+			}
+		})
+
+		It("reports no fragmentation and suggests no relocations", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{})
+			Expect(report).To(Equal(rep.FragmentationReport{
+				FreeMemoryMB:             1000,
+				FreeDiskMB:               2000,
+				LargestPlaceableMemoryMB: 1000,
+				LargestPlaceableDiskMB:   2000,
+			}))
+		})
+	})
+
+	Context("when the free resources are split across pools", func() {
+		BeforeEach(func() {
+			pools = []rep.FragmentationPool{
+				{Backend: "linux", Available: rep.NewResources(1000, 1000, 2), LRPs: []rep.LRP{lrpA}},
+				{Backend: "windows", Available: rep.NewResources(600, 600, 2), LRPs: []rep.LRP{lrpB}},
+			}
+		})
+
+		It("scores the largest placeable instance against the total free", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{})
+			Expect(report.FreeMemoryMB).To(BeEquivalentTo(1600))
+			Expect(report.LargestPlaceableMemoryMB).To(BeEquivalentTo(1000))
+			Expect(report.LargestPlaceableDiskMB).To(BeEquivalentTo(1000))
+			Expect(report.MemoryScore).To(Equal(0.375))
+			Expect(report.DiskScore).To(Equal(0.375))
+		})
+
+		It("suggests only the relocations that lower the score", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{})
+			Expect(report.Relocations).To(HaveLen(1))
+
+			relocation := report.Relocations[0]
+			Expect(relocation.InstanceGUID).To(Equal("ig-a"))
+			Expect(relocation.ProcessGuid).To(Equal("pg-a"))
+			Expect(relocation.Backend).To(Equal("linux"))
+			Expect(relocation.LargestPlaceableMemoryMB).To(BeEquivalentTo(1200))
+			Expect(relocation.MemoryScore).To(BeNumerically("~", 1.0/3, 0.001))
+		})
+	})
+
+	Context("when the pool has no container slots left", func() {
+		BeforeEach(func() {
+			pools = []rep.FragmentationPool{
+				{Backend: "linux", Available: rep.NewResources(1000, 1000, 0), LRPs: []rep.LRP{lrpB, lrpA}},
+			}
+		})
+
+		It("considers all the free resources stranded", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{})
+			Expect(report.LargestPlaceableMemoryMB).To(BeZero())
+			Expect(report.MemoryScore).To(Equal(1.0))
+			Expect(report.DiskScore).To(Equal(1.0))
+		})
+
+		It("suggests relocating any instance, least fragmented outcome first", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{})
+			Expect(report.Relocations).To(HaveLen(2))
+			Expect(report.Relocations[0].InstanceGUID).To(Equal("ig-a"))
+			Expect(report.Relocations[0].MemoryScore).To(BeZero())
+			Expect(report.Relocations[1].InstanceGUID).To(Equal("ig-b"))
+		})
+	})
+
+	Context("when the largest container is capped", func() {
+		BeforeEach(func() {
+			pools = []rep.FragmentationPool{
+				{Backend: "linux", Available: rep.NewResources(8000, 8000, 4), LRPs: []rep.LRP{lrpA}},
+			}
+		})
+
+		It("caps the largest placeable instance without suggesting relocations", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{MemoryMB: 4000, DiskMB: 2000})
+			Expect(report.LargestPlaceableMemoryMB).To(BeEquivalentTo(4000))
+			Expect(report.LargestPlaceableDiskMB).To(BeEquivalentTo(2000))
+			Expect(report.MemoryScore).To(Equal(0.5))
+			Expect(report.DiskScore).To(Equal(0.75))
+			Expect(report.Relocations).To(BeEmpty())
+		})
+	})
+
+	Context("when nothing is free", func() {
+		BeforeEach(func() {
+			pools = []rep.FragmentationPool{
+				{Backend: "linux", Available: rep.NewResources(0, 0, 0), LRPs: []rep.LRP{lrpA}},
+			}
+		})
+
+		It("reports no fragmentation", func() {
+			report := rep.AnalyzeFragmentation(pools, rep.Resource{})
+			Expect(report.MemoryScore).To(BeZero())
+			Expect(report.DiskScore).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package rep_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+)
+
+// The fuzz targets decode the payloads misconfigured clients could send and
+// check that whatever decodes encodes again into a payload that decodes to
+// the same work or state. Run them with go test -fuzz; without -fuzz they
+// only run their seeds.
+
+func FuzzWorkUnmarshalJSON(f *testing.F) {
+	addFixture(f, "fixtures/work.json")
+	f.Add([]byte(`{"LRPs": [{"process_guid": "pg", "memory": "1.5G", "disk": "512MB"}], "cell_id": "cell"}`))
+	f.Add([]byte(`{"Tasks": [{"TaskGuid": "tg", "MemoryMB": 256, "DiskMB": "1G"}]}`))
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		var work rep.Work
+		if json.Unmarshal(payload, &work) != nil {
+			return
+		}
+
+		var decoded rep.Work
+		reencode(t, work, &decoded)
+		if decoded.BatchSize() != work.BatchSize() {
+			t.Fatalf("re-encoded work has %d LRPs and tasks rather than %d", decoded.BatchSize(), work.BatchSize())
+		}
+		for i := range work.LRPs {
+			if decoded.LRPs[i].Resource.MemoryMB != work.LRPs[i].Resource.MemoryMB || decoded.LRPs[i].Resource.DiskMB != work.LRPs[i].Resource.DiskMB {
+				t.Fatalf("re-encoded LRP %d has resource %+v rather than %+v", i, decoded.LRPs[i].Resource, work.LRPs[i].Resource)
+			}
+		}
+	})
+}
+
+func FuzzCellStateUnmarshalJSON(f *testing.F) {
+	addFixture(f, "fixtures/cell_state.json")
+	f.Add([]byte(`{"cell_id": "cell", "RootFSProviders": {"preloaded": {"type": "fixed_set", "set": {"cflinuxfs4": {}}}, "docker": {"type": "unknown"}}}`))
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		var state rep.CellState
+		if json.Unmarshal(payload, &state) != nil {
+			return
+		}
+
+		valid := state.Validate() == nil
+		var decoded rep.CellState
+		reencode(t, state, &decoded)
+		if (decoded.Validate() == nil) != valid {
+			t.Fatalf("re-encoded state is valid: %t, decoded state is valid: %t", !valid, valid)
+		}
+	})
+}
+
+func FuzzRootFSMatch(f *testing.F) {
+	for _, rootfs := range []string{
+		"",
+		models.PreloadedRootFS("cflinuxfs4"),
+		models.PreloadedOCIRootFSScheme + ":cflinuxfs4?layer=abc",
+		"docker:///cloudfoundry/grace",
+		"preloaded://cflinuxfs4",
+		"%zz",
+	} {
+		f.Add(rootfs)
+	}
+
+	state := rep.CellState{
+		RootFSProviders: rep.RootFSProviders{
+			models.PreloadedRootFSScheme:    rep.NewFixedSetRootFSProvider("cflinuxfs4"),
+			models.PreloadedOCIRootFSScheme: rep.NewFixedSetRootFSProvider("cflinuxfs4"),
+			"docker":                        rep.ArbitraryRootFSProvider{},
+		},
+	}
+	stackPathMap := rep.StackPathMap{"cflinuxfs4": "/var/vcap/packages/cflinuxfs4/rootfs.tar"}
+
+	f.Fuzz(func(t *testing.T, rootfs string) {
+		matched := state.MatchRootFS(rootfs)
+		state.RootFSOverhead(rootfs)
+		path, err := stackPathMap.PathForRootFS(rootfs)
+
+		rootFSURL, parseErr := url.Parse(rootfs)
+		if parseErr != nil || !matched {
+			return
+		}
+		preloaded := rootFSURL.Scheme == models.PreloadedRootFSScheme || rootFSURL.Scheme == models.PreloadedOCIRootFSScheme
+		if preloaded && err != nil {
+			t.Fatalf("%q matches the cell but resolves to no path: %s", rootfs, err)
+		}
+		if preloaded && path == rootfs {
+			t.Fatalf("%q matches the cell but is not resolved", rootfs)
+		}
+	})
+}
+
+func addFixture(f *testing.F, path string) {
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		f.Fatalf("failed to read %s: %s", path, err)
+	}
+	f.Add(payload)
+}
+
+func reencode(t *testing.T, value interface{}, decoded interface{}) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to encode the decoded payload: %s", err)
+	}
+	err = json.Unmarshal(payload, decoded)
+	if err != nil {
+		t.Fatalf("failed to decode the re-encoded payload %s: %s", payload, err)
+	}
+}
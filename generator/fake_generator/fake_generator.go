@@ -4,24 +4,27 @@ package fake_generator
 import (
 	"sync"
 
+	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/operationq"
 	"code.cloudfoundry.org/rep/generator"
 )
 
 type FakeGenerator struct {
-	BatchOperationsStub        func(lager.Logger) (map[string]operationq.Operation, error)
+	BatchOperationsStub        func(lager.Logger) (map[string]operationq.Operation, []executor.Container, error)
 	batchOperationsMutex       sync.RWMutex
 	batchOperationsArgsForCall []struct {
 		arg1 lager.Logger
 	}
 	batchOperationsReturns struct {
 		result1 map[string]operationq.Operation
-		result2 error
+		result2 []executor.Container
+		result3 error
 	}
 	batchOperationsReturnsOnCall map[int]struct {
 		result1 map[string]operationq.Operation
-		result2 error
+		result2 []executor.Container
+		result3 error
 	}
 	OperationStreamStub        func(lager.Logger) (<-chan operationq.Operation, error)
 	operationStreamMutex       sync.RWMutex
@@ -40,7 +43,7 @@ type FakeGenerator struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeGenerator) BatchOperations(arg1 lager.Logger) (map[string]operationq.Operation, error) {
+func (fake *FakeGenerator) BatchOperations(arg1 lager.Logger) (map[string]operationq.Operation, []executor.Container, error) {
 	fake.batchOperationsMutex.Lock()
 	ret, specificReturn := fake.batchOperationsReturnsOnCall[len(fake.batchOperationsArgsForCall)]
 	fake.batchOperationsArgsForCall = append(fake.batchOperationsArgsForCall, struct {
@@ -54,9 +57,9 @@ func (fake *FakeGenerator) BatchOperations(arg1 lager.Logger) (map[string]operat
 		return stub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeGenerator) BatchOperationsCallCount() int {
@@ -65,7 +68,7 @@ func (fake *FakeGenerator) BatchOperationsCallCount() int {
 	return len(fake.batchOperationsArgsForCall)
 }
 
-func (fake *FakeGenerator) BatchOperationsCalls(stub func(lager.Logger) (map[string]operationq.Operation, error)) {
+func (fake *FakeGenerator) BatchOperationsCalls(stub func(lager.Logger) (map[string]operationq.Operation, []executor.Container, error)) {
 	fake.batchOperationsMutex.Lock()
 	defer fake.batchOperationsMutex.Unlock()
 	fake.BatchOperationsStub = stub
@@ -78,17 +81,18 @@ func (fake *FakeGenerator) BatchOperationsArgsForCall(i int) lager.Logger {
 	return argsForCall.arg1
 }
 
-func (fake *FakeGenerator) BatchOperationsReturns(result1 map[string]operationq.Operation, result2 error) {
+func (fake *FakeGenerator) BatchOperationsReturns(result1 map[string]operationq.Operation, result2 []executor.Container, result3 error) {
 	fake.batchOperationsMutex.Lock()
 	defer fake.batchOperationsMutex.Unlock()
 	fake.BatchOperationsStub = nil
 	fake.batchOperationsReturns = struct {
 		result1 map[string]operationq.Operation
-		result2 error
-	}{result1, result2}
+		result2 []executor.Container
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeGenerator) BatchOperationsReturnsOnCall(i int, result1 map[string]operationq.Operation, result2 error) {
+func (fake *FakeGenerator) BatchOperationsReturnsOnCall(i int, result1 map[string]operationq.Operation, result2 []executor.Container, result3 error) {
 	fake.batchOperationsMutex.Lock()
 	defer fake.batchOperationsMutex.Unlock()
 	fake.BatchOperationsStub = nil
@@ -100,8 +104,9 @@ func (fake *FakeGenerator) BatchOperationsReturnsOnCall(i int, result1 map[strin
 	}
 	fake.batchOperationsReturnsOnCall[i] = struct {
 		result1 map[string]operationq.Operation
-		result2 error
-	}{result1, result2}
+		result2 []executor.Container
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeGenerator) OperationStream(arg1 lager.Logger) (<-chan operationq.Operation, error) {
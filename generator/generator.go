@@ -11,8 +11,15 @@ import (
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/operationq"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/contacts"
+	"code.cloudfoundry.org/rep/crashloop"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+	"code.cloudfoundry.org/rep/featureflags"
 	"code.cloudfoundry.org/rep/generator/internal"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"code.cloudfoundry.org/rep/lifecyclehints"
+	"code.cloudfoundry.org/rep/proxyreadiness"
+	"code.cloudfoundry.org/rep/taskcompletion"
 	multierror "github.com/hashicorp/go-multierror"
 )
 
@@ -20,8 +27,9 @@ import (
 
 // Generator encapsulates operation creation in the Rep.
 type Generator interface {
-	// BatchOperations creates a set of operations across all containers the Rep is managing.
-	BatchOperations(lager.Logger) (map[string]operationq.Operation, error)
+	// BatchOperations creates a set of operations across all containers the
+	// Rep is managing, and returns the containers it listed to do so.
+	BatchOperations(lager.Logger) (map[string]operationq.Operation, []executor.Container, error)
 
 	// OperationStream creates an operation every time a container lifecycle event is observed.
 	OperationStream(lager.Logger) (<-chan operationq.Operation, error)
@@ -34,6 +42,7 @@ type generator struct {
 	lrpProcessor      internal.LRPProcessor
 	taskProcessor     internal.TaskProcessor
 	containerDelegate internal.ContainerDelegate
+	contactRecorder   contacts.Recorder
 }
 
 func New(
@@ -44,10 +53,18 @@ func New(
 	executorClient executor.Client,
 	metronClient loggingclient.IngressClient,
 	evacuationReporter evacuation_context.EvacuationReporter,
+	proxyReadinessWaiter proxyreadiness.Waiter,
+	hintPublisher lifecyclehints.Publisher,
+	crashLoopDetector crashloop.Detector,
+	healthCheckRelaxer healthchecks.Relaxer,
+	workGroupHolds *rep.WorkGroupHolds,
+	taskCompleter taskcompletion.Completer,
+	contactRecorder contacts.Recorder,
+	featureFlags *featureflags.Flags,
 ) Generator {
 	containerDelegate := internal.NewContainerDelegate(executorClient)
-	lrpProcessor := internal.NewLRPProcessor(bbs, containerDelegate, metronClient, cellID, stackPathMap, layeringMode, evacuationReporter)
-	taskProcessor := internal.NewTaskProcessor(bbs, containerDelegate, cellID, stackPathMap, layeringMode)
+	lrpProcessor := internal.NewLRPProcessor(bbs, containerDelegate, metronClient, cellID, stackPathMap, layeringMode, evacuationReporter, proxyReadinessWaiter, hintPublisher, crashLoopDetector, healthCheckRelaxer, workGroupHolds, featureFlags)
+	taskProcessor := internal.NewTaskProcessor(bbs, containerDelegate, taskCompleter, cellID, stackPathMap, layeringMode, workGroupHolds)
 
 	return &generator{
 		cellID:            cellID,
@@ -56,14 +73,16 @@ func New(
 		lrpProcessor:      lrpProcessor,
 		taskProcessor:     taskProcessor,
 		containerDelegate: containerDelegate,
+		contactRecorder:   contactRecorder,
 	}
 }
 
-func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.Operation, error) {
+func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.Operation, []executor.Container, error) {
 	logger = logger.Session("batch-operations")
 	logger.Info("started")
 
 	containers := make(map[string]executor.Container)
+	var listedContainers []executor.Container
 	instanceLRPs := make(map[string]models.ActualLRP)
 	evacuatingLRPs := make(map[string]models.ActualLRP)
 	tasks := make(map[string]*models.Task)
@@ -76,11 +95,14 @@ func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.
 		if err != nil {
 			logger.Error("failed-to-list-containers", err)
 			err = fmt.Errorf("failed to list containers: %s", err.Error())
+		} else if g.contactRecorder != nil && g.executorClient.Healthy(logger) {
+			g.contactRecorder.RecordGardenContact()
 		}
 
 		for _, c := range foundContainers {
 			containers[c.Guid] = c
 		}
+		listedContainers = foundContainers
 
 		errChan <- err
 	}()
@@ -90,6 +112,8 @@ func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.
 		if err != nil {
 			logger.Error("failed-to-retrieve-lrps", err)
 			err = fmt.Errorf("failed to retrieve lrps: %s", err.Error())
+		} else {
+			g.recordBBSContact()
 		}
 
 		for _, lrp := range lrps {
@@ -107,6 +131,8 @@ func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.
 		if err != nil {
 			logger.Error("failed-to-retrieve-tasks", err)
 			err = fmt.Errorf("failed to retrieve tasks: %s", err.Error())
+		} else {
+			g.recordBBSContact()
 		}
 
 		for _, task := range foundTasks {
@@ -125,7 +151,7 @@ func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.
 
 	if err != nil {
 		logger.Error("failed-getting-containers-lrps-and-tasks", err)
-		return nil, err
+		return nil, nil, err
 	}
 	logger.Info("succeeded-getting-containers-lrps-and-tasks")
 
@@ -165,7 +191,13 @@ func (g *generator) BatchOperations(logger lager.Logger) (map[string]operationq.
 	}
 
 	logger.Info("succeeded", lager.Data{"batch-size": len(batch)})
-	return batch, nil
+	return batch, listedContainers, nil
+}
+
+func (g *generator) recordBBSContact() {
+	if g.contactRecorder != nil {
+		g.contactRecorder.RecordBBSContact()
+	}
 }
 
 func (g *generator) OperationStream(logger lager.Logger) (<-chan operationq.Operation, error) {
@@ -8,8 +8,10 @@ import (
 	efakes "code.cloudfoundry.org/executor/fakes"
 	"code.cloudfoundry.org/operationq"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/contacts/contactsfakes"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context/fake_evacuation_context"
 	"code.cloudfoundry.org/rep/generator"
+	"code.cloudfoundry.org/rep/taskcompletion"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
@@ -25,6 +27,7 @@ var _ = Describe("Generator", func() {
 	var (
 		cellID             string
 		fakeExecutorClient *efakes.FakeClient
+		fakeContacts       *contactsfakes.FakeRecorder
 
 		opGenerator generator.Generator
 	)
@@ -32,20 +35,22 @@ var _ = Describe("Generator", func() {
 	BeforeEach(func() {
 		cellID = "some-cell-id"
 		fakeExecutorClient = new(efakes.FakeClient)
+		fakeContacts = new(contactsfakes.FakeRecorder)
 		fakeEvacuationReporter := &fake_evacuation_context.FakeEvacuationReporter{}
-		opGenerator = generator.New(cellID, rep.StackPathMap{}, "", fakeBBS, fakeExecutorClient, nil, fakeEvacuationReporter)
+		opGenerator = generator.New(cellID, rep.StackPathMap{}, "", fakeBBS, fakeExecutorClient, nil, fakeEvacuationReporter, nil, nil, nil, nil, nil, taskcompletion.NewBBSCompleter(fakeBBS, cellID), fakeContacts, nil)
 	})
 
 	Describe("BatchOperations", func() {
 		const sessionName = "test.batch-operations"
 
 		var (
-			batch    map[string]operationq.Operation
-			batchErr error
+			batch      map[string]operationq.Operation
+			containers []executor.Container
+			batchErr   error
 		)
 
 		JustBeforeEach(func() {
-			batch, batchErr = opGenerator.BatchOperations(logger)
+			batch, containers, batchErr = opGenerator.BatchOperations(logger)
 		})
 
 		It("logs its lifecycle", func() {
@@ -133,6 +138,26 @@ var _ = Describe("Generator", func() {
 				Expect(batch).To(HaveLen(8))
 			})
 
+			It("returns the containers it listed", func() {
+				Expect(containers).To(HaveLen(4))
+				Expect(containers[3].Guid).To(Equal(guidContainerForTask))
+			})
+
+			It("records the contact with the BBS but not with an unhealthy Garden", func() {
+				Expect(fakeContacts.RecordBBSContactCallCount()).To(Equal(2))
+				Expect(fakeContacts.RecordGardenContactCallCount()).To(Equal(0))
+			})
+
+			Context("when Garden is healthy", func() {
+				BeforeEach(func() {
+					fakeExecutorClient.HealthyReturns(true)
+				})
+
+				It("records the contact with Garden", func() {
+					Expect(fakeContacts.RecordGardenContactCallCount()).To(Equal(1))
+				})
+			})
+
 			batchHasAContainerOperationForGuid := func(guid string, batch map[string]operationq.Operation) {
 				Expect(batch).To(HaveKey(guid))
 				Expect(batch[guid]).To(BeAssignableToTypeOf(new(generator.ContainerOperation)))
@@ -194,6 +219,10 @@ var _ = Describe("Generator", func() {
 				It("logs the failure", func() {
 					Expect(logger).To(Say(sessionName + ".failed-to-list-containers"))
 				})
+
+				It("does not record the contact with Garden", func() {
+					Expect(fakeContacts.RecordGardenContactCallCount()).To(Equal(0))
+				})
 			})
 
 			Context("when retrieving the tasks fails", func() {
@@ -224,6 +253,10 @@ var _ = Describe("Generator", func() {
 				It("logs the failure", func() {
 					Expect(logger).To(Say(sessionName + ".failed-to-retrieve-lrps"))
 				})
+
+				It("records only the contact of the request that succeeded", func() {
+					Expect(fakeContacts.RecordBBSContactCallCount()).To(Equal(1))
+				})
 			})
 		})
 	})
@@ -23,6 +23,7 @@ type ContainerDelegate interface {
 	RunContainer(logger lager.Logger, req *executor.RunRequest) bool
 	StopContainer(logger lager.Logger, guid string) bool
 	DeleteContainer(logger lager.Logger, guid string) bool
+	ReallocateContainer(logger lager.Logger, container executor.Container) bool
 	FetchContainerResultFile(logger lager.Logger, guid string, filename string) (string, error)
 }
 
@@ -81,6 +82,28 @@ func (d *containerDelegate) DeleteContainer(logger lager.Logger, guid string) bo
 	return true
 }
 
+// ReallocateContainer deletes the container and reserves a new one with its
+// guid, resources and tags, so that the work it ran is run on the cell again.
+func (d *containerDelegate) ReallocateContainer(logger lager.Logger, container executor.Container) bool {
+	logger.Info("reallocating-container")
+	err := d.client.DeleteContainer(logger, container.Guid)
+	if err != nil {
+		logInfoOrError(logger, "failed-deleting-container", err)
+		return false
+	}
+
+	resource := container.Resource
+	failures := d.client.AllocateContainers(logger, []executor.AllocationRequest{
+		executor.NewAllocationRequest(container.Guid, &resource, container.Tags),
+	})
+	if len(failures) > 0 {
+		logger.Error("failed-reallocating-container", &failures[0])
+		return false
+	}
+	logger.Info("succeeded-reallocating-container")
+	return true
+}
+
 func (d *containerDelegate) FetchContainerResultFile(logger lager.Logger, guid string, filename string) (string, error) {
 	logger.Info("fetching-container-result")
 	stream, err := d.client.GetFiles(logger, guid, filename)
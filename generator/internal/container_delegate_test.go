@@ -172,6 +172,63 @@ var _ = Describe("ContainerDelegate", func() {
 		})
 	})
 
+	Describe("ReallocateContainer", func() {
+		var (
+			container executor.Container
+			result    bool
+		)
+
+		BeforeEach(func() {
+			container = executor.Container{
+				Guid:     expectedGuid,
+				State:    executor.StateCompleted,
+				Resource: executor.Resource{MemoryMB: 128, DiskMB: 256},
+				Tags:     executor.Tags{"lifecycle": "lrp"},
+			}
+		})
+
+		JustBeforeEach(func() {
+			result = containerDelegate.ReallocateContainer(logger, container)
+		})
+
+		It("deletes the container and reserves a new one like it", func() {
+			Expect(result).To(BeTrue())
+
+			Expect(executorClient.DeleteContainerCallCount()).To(Equal(1))
+			_, containerGuid := executorClient.DeleteContainerArgsForCall(0)
+			Expect(containerGuid).To(Equal(expectedGuid))
+
+			Expect(executorClient.AllocateContainersCallCount()).To(Equal(1))
+			_, requests := executorClient.AllocateContainersArgsForCall(0)
+			resource := container.Resource
+			Expect(requests).To(Equal([]executor.AllocationRequest{executor.NewAllocationRequest(expectedGuid, &resource, container.Tags)}))
+		})
+
+		Context("when deleting fails", func() {
+			BeforeEach(func() {
+				executorClient.DeleteContainerReturns(errors.New("ka-boom"))
+			})
+
+			It("does not reserve a new container", func() {
+				Expect(result).To(BeFalse())
+				Expect(executorClient.AllocateContainersCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when reserving fails", func() {
+			BeforeEach(func() {
+				resource := container.Resource
+				request := executor.NewAllocationRequest(expectedGuid, &resource, container.Tags)
+				executorClient.AllocateContainersReturns([]executor.AllocationFailure{executor.NewAllocationFailure(&request, "no room")})
+			})
+
+			It("returns false", func() {
+				Expect(result).To(BeFalse())
+				Expect(logger).To(gbytes.Say(sessionPrefix + ".failed-reallocating-container"))
+			})
+		})
+	})
+
 	Describe("FetchContainerResultFile", func() {
 		var (
 			filename string
@@ -57,7 +57,7 @@ var _ = Describe("EvacuationLrpProcessor", func() {
 
 			fakeMetronClient = new(mfakes.FakeIngressClient)
 
-			lrpProcessor = internal.NewLRPProcessor(fakeBBS, fakeContainerDelegate, fakeMetronClient, localCellID, rep.StackPathMap{}, "", fakeEvacuationReporter)
+			lrpProcessor = internal.NewLRPProcessor(fakeBBS, fakeContainerDelegate, fakeMetronClient, localCellID, rep.StackPathMap{}, "", fakeEvacuationReporter, nil, nil, nil, nil, nil, nil)
 
 			processGuid = "process-guid"
 			desiredLRP = models.DesiredLRP{
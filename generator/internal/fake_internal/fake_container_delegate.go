@@ -51,6 +51,18 @@ type FakeContainerDelegate struct {
 		result1 executor.Container
 		result2 bool
 	}
+	ReallocateContainerStub        func(lager.Logger, executor.Container) bool
+	reallocateContainerMutex       sync.RWMutex
+	reallocateContainerArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 executor.Container
+	}
+	reallocateContainerReturns struct {
+		result1 bool
+	}
+	reallocateContainerReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	RunContainerStub        func(lager.Logger, *executor.RunRequest) bool
 	runContainerMutex       sync.RWMutex
 	runContainerArgsForCall []struct {
@@ -272,6 +284,68 @@ func (fake *FakeContainerDelegate) GetContainerReturnsOnCall(i int, result1 exec
 	}{result1, result2}
 }
 
+func (fake *FakeContainerDelegate) ReallocateContainer(arg1 lager.Logger, arg2 executor.Container) bool {
+	fake.reallocateContainerMutex.Lock()
+	ret, specificReturn := fake.reallocateContainerReturnsOnCall[len(fake.reallocateContainerArgsForCall)]
+	fake.reallocateContainerArgsForCall = append(fake.reallocateContainerArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 executor.Container
+	}{arg1, arg2})
+	stub := fake.ReallocateContainerStub
+	fakeReturns := fake.reallocateContainerReturns
+	fake.recordInvocation("ReallocateContainer", []interface{}{arg1, arg2})
+	fake.reallocateContainerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeContainerDelegate) ReallocateContainerCallCount() int {
+	fake.reallocateContainerMutex.RLock()
+	defer fake.reallocateContainerMutex.RUnlock()
+	return len(fake.reallocateContainerArgsForCall)
+}
+
+func (fake *FakeContainerDelegate) ReallocateContainerCalls(stub func(lager.Logger, executor.Container) bool) {
+	fake.reallocateContainerMutex.Lock()
+	defer fake.reallocateContainerMutex.Unlock()
+	fake.ReallocateContainerStub = stub
+}
+
+func (fake *FakeContainerDelegate) ReallocateContainerArgsForCall(i int) (lager.Logger, executor.Container) {
+	fake.reallocateContainerMutex.RLock()
+	defer fake.reallocateContainerMutex.RUnlock()
+	argsForCall := fake.reallocateContainerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeContainerDelegate) ReallocateContainerReturns(result1 bool) {
+	fake.reallocateContainerMutex.Lock()
+	defer fake.reallocateContainerMutex.Unlock()
+	fake.ReallocateContainerStub = nil
+	fake.reallocateContainerReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeContainerDelegate) ReallocateContainerReturnsOnCall(i int, result1 bool) {
+	fake.reallocateContainerMutex.Lock()
+	defer fake.reallocateContainerMutex.Unlock()
+	fake.ReallocateContainerStub = nil
+	if fake.reallocateContainerReturnsOnCall == nil {
+		fake.reallocateContainerReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.reallocateContainerReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeContainerDelegate) RunContainer(arg1 lager.Logger, arg2 *executor.RunRequest) bool {
 	fake.runContainerMutex.Lock()
 	ret, specificReturn := fake.runContainerReturnsOnCall[len(fake.runContainerArgsForCall)]
@@ -405,6 +479,8 @@ func (fake *FakeContainerDelegate) Invocations() map[string][][]interface{} {
 	defer fake.fetchContainerResultFileMutex.RUnlock()
 	fake.getContainerMutex.RLock()
 	defer fake.getContainerMutex.RUnlock()
+	fake.reallocateContainerMutex.RLock()
+	defer fake.reallocateContainerMutex.RUnlock()
 	fake.runContainerMutex.RLock()
 	defer fake.runContainerMutex.RUnlock()
 	fake.stopContainerMutex.RLock()
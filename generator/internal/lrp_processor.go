@@ -7,7 +7,12 @@ import (
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/crashloop"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+	"code.cloudfoundry.org/rep/featureflags"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"code.cloudfoundry.org/rep/lifecyclehints"
+	"code.cloudfoundry.org/rep/proxyreadiness"
 )
 
 type lrpContainer struct {
@@ -44,8 +49,14 @@ func NewLRPProcessor(
 	stackPathMap rep.StackPathMap,
 	layeringMode string,
 	evacuationReporter evacuation_context.EvacuationReporter,
+	proxyReadinessWaiter proxyreadiness.Waiter,
+	hintPublisher lifecyclehints.Publisher,
+	crashLoopDetector crashloop.Detector,
+	healthCheckRelaxer healthchecks.Relaxer,
+	workGroupHolds *rep.WorkGroupHolds,
+	featureFlags *featureflags.Flags,
 ) LRPProcessor {
-	ordinaryProcessor := newOrdinaryLRPProcessor(bbsClient, containerDelegate, cellID, stackPathMap, layeringMode)
+	ordinaryProcessor := newOrdinaryLRPProcessor(bbsClient, containerDelegate, cellID, stackPathMap, layeringMode, proxyReadinessWaiter, hintPublisher, crashLoopDetector, healthCheckRelaxer, workGroupHolds, featureFlags)
 	evacuationProcessor := newEvacuationLRPProcessor(bbsClient, containerDelegate, metronClient, cellID)
 	return &lrpProcessor{
 		evacuationReporter:  evacuationReporter,
@@ -7,6 +7,11 @@ import (
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/crashloop"
+	"code.cloudfoundry.org/rep/featureflags"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"code.cloudfoundry.org/rep/lifecyclehints"
+	"code.cloudfoundry.org/rep/proxyreadiness"
 )
 
 type ordinaryLRPProcessor struct {
@@ -16,6 +21,12 @@ type ordinaryLRPProcessor struct {
 	stackPathMap               rep.StackPathMap
 	layeringMode               string
 	runRequestConversionHelper rep.RunRequestConversionHelper
+	proxyReadinessWaiter       proxyreadiness.Waiter
+	hintPublisher              lifecyclehints.Publisher
+	crashLoopDetector          crashloop.Detector
+	healthCheckRelaxer         healthchecks.Relaxer
+	workGroupHolds             *rep.WorkGroupHolds
+	featureFlags               *featureflags.Flags
 }
 
 func newOrdinaryLRPProcessor(
@@ -24,6 +35,12 @@ func newOrdinaryLRPProcessor(
 	cellID string,
 	stackPathMap rep.StackPathMap,
 	layeringMode string,
+	proxyReadinessWaiter proxyreadiness.Waiter,
+	hintPublisher lifecyclehints.Publisher,
+	crashLoopDetector crashloop.Detector,
+	healthCheckRelaxer healthchecks.Relaxer,
+	workGroupHolds *rep.WorkGroupHolds,
+	featureFlags *featureflags.Flags,
 ) LRPProcessor {
 	runRequestConversionHelper := rep.RunRequestConversionHelper{ECRHelper: ecrhelper.NewECRHelper()}
 
@@ -34,6 +51,12 @@ func newOrdinaryLRPProcessor(
 		stackPathMap:               stackPathMap,
 		layeringMode:               layeringMode,
 		runRequestConversionHelper: runRequestConversionHelper,
+		proxyReadinessWaiter:       proxyReadinessWaiter,
+		hintPublisher:              hintPublisher,
+		crashLoopDetector:          crashLoopDetector,
+		healthCheckRelaxer:         healthCheckRelaxer,
+		workGroupHolds:             workGroupHolds,
+		featureFlags:               featureFlags,
 	}
 }
 
@@ -77,7 +100,11 @@ func (p *ordinaryLRPProcessor) Process(logger lager.Logger, container executor.C
 }
 
 func (p *ordinaryLRPProcessor) processReservedContainer(logger lager.Logger, lrpContainer *lrpContainer) {
-	logger = logger.Session("process-reserved-container")
+	logger = rep.WithTraceID(logger.Session("process-reserved-container"), lrpContainer.Tags)
+	logger = rep.WithProvenance(logger, rep.ProvenanceFromTags(lrpContainer.Tags))
+	if !waitForWorkGroup(logger, p.workGroupHolds, p.containerDelegate, lrpContainer.Container) {
+		return
+	}
 	ok := p.claimLRPContainer(logger, lrpContainer)
 	if !ok {
 		return
@@ -94,6 +121,26 @@ func (p *ordinaryLRPProcessor) processReservedContainer(logger lager.Logger, lrp
 		logger.Error("failed-to-construct-run-request", err)
 		return
 	}
+
+	initSteps, err := rep.InitStepsFromTags(lrpContainer.Tags)
+	if err != nil {
+		logger.Error("failed-to-decode-init-steps", err)
+		return
+	}
+	rep.AddInitSteps(&runReq.RunInfo, initSteps)
+	staticHostPorts, err := rep.StaticHostPortsFromTags(lrpContainer.Tags)
+	if err != nil {
+		logger.Error("failed-to-decode-static-host-ports", err)
+		return
+	}
+	rep.MapStaticHostPorts(&runReq.RunInfo, staticHostPorts)
+	if p.healthCheckRelaxer != nil {
+		if relaxation, ok := p.healthCheckRelaxer.Relaxation(lrpContainer.ProcessGuid, lrpContainer.Index); ok {
+			logger.Info("relaxing-health-checks", lager.Data{"suspend": relaxation.Suspend, "expires-at": relaxation.ExpiresAt})
+			relaxation.Apply(&runReq.RunInfo)
+			rep.AddHealthCheckRelaxationTag(runReq.Tags, relaxation)
+		}
+	}
 	ok = p.containerDelegate.RunContainer(logger, &runReq)
 	if !ok {
 		p.bbsClient.RemoveActualLRP(logger, lrpContainer.ActualLRPKey, lrpContainer.ActualLRPInstanceKey)
@@ -122,6 +169,13 @@ func (p *ordinaryLRPProcessor) processRunningContainer(logger lager.Logger, lrpC
 	}
 	logger.Debug("succeeded-extracting-net-info-from-container")
 
+	if p.proxyReadinessWaiter != nil {
+		err = p.proxyReadinessWaiter.Wait(logger, lrpContainer.Container)
+		if err != nil {
+			logger.Error("failed-waiting-for-proxy-readiness", err)
+		}
+	}
+
 	logger.Info("bbs-start-actual-lrp", lager.Data{"net_info": netInfo})
 	internalRoutes := []*models.ActualLRPInternalRoute{}
 	for _, internalRoute := range lrpContainer.InternalRoutes {
@@ -132,6 +186,9 @@ func (p *ordinaryLRPProcessor) processRunningContainer(logger lager.Logger, lrpC
 	if bbsErr != nil && bbsErr.Type == models.Error_ActualLRPCannotBeStarted {
 		p.containerDelegate.StopContainer(logger, lrpContainer.Guid)
 	}
+	if err == nil && p.hintPublisher != nil {
+		p.hintPublisher.ContainerStarted(logger, lrpContainer.Container)
+	}
 }
 
 func (p *ordinaryLRPProcessor) processCompletedContainer(logger lager.Logger, lrpContainer *lrpContainer) {
@@ -143,15 +200,44 @@ func (p *ordinaryLRPProcessor) processCompletedContainer(logger lager.Logger, lr
 			logger.Info("failed-to-remove-actual-lrp", lager.Data{"error": err})
 		}
 	} else {
-		err := p.bbsClient.CrashActualLRP(logger, lrpContainer.ActualLRPKey, lrpContainer.ActualLRPInstanceKey, lrpContainer.RunResult.FailureReason)
+		reason := lrpContainer.RunResult.FailureReason
+		crashLooping := p.crashLoopDetector != nil && p.crashLoopDetector.RecordCrash(logger, lrpContainer.ProcessGuid, lrpContainer.Index)
+		if crashLooping {
+			reason = crashloop.QuarantineReason(reason)
+		}
+
+		if !crashLooping && p.restartsLocally() {
+			logger.Info("restarting-crashed-instance-locally", lager.Data{"reason": reason})
+			if p.containerDelegate.ReallocateContainer(logger, lrpContainer.Container) {
+				if p.hintPublisher != nil {
+					p.hintPublisher.ContainerStopped(logger, lrpContainer.Container)
+				}
+				return
+			}
+		}
+
+		err := p.bbsClient.CrashActualLRP(logger, lrpContainer.ActualLRPKey, lrpContainer.ActualLRPInstanceKey, reason)
 		if err != nil {
 			logger.Info("failed-to-crash-actual-lrp", lager.Data{"error": err})
 		}
 	}
 
+	if p.hintPublisher != nil {
+		p.hintPublisher.ContainerStopped(logger, lrpContainer.Container)
+	}
+
 	p.containerDelegate.DeleteContainer(logger, lrpContainer.Guid)
 }
 
+// restartsLocally reports whether crashed instances are restarted on the cell,
+// their container reserved again to be claimed and run as a new one, rather
+// than handed back to the BBS. It needs a crash loop detector, which hands
+// an instance crashing in a loop back to the BBS, so that it is not restarted
+// on the cell forever.
+func (p *ordinaryLRPProcessor) restartsLocally() bool {
+	return p.crashLoopDetector != nil && p.featureFlags != nil && p.featureFlags.Enabled(featureflags.LocalRestart)
+}
+
 func (p *ordinaryLRPProcessor) processInvalidContainer(logger lager.Logger, lrpContainer *lrpContainer) {
 	logger = logger.Session("process-invalid-container")
 	logger.Error("not-processing-container-in-invalid-state", nil)
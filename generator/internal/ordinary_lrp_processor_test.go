@@ -10,11 +10,18 @@ import (
 	"code.cloudfoundry.org/bbs/models/test/model_helpers"
 	fakeecrhelper "code.cloudfoundry.org/ecrhelper/fakes"
 	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/crashloop"
+	"code.cloudfoundry.org/rep/crashloop/crashloopfakes"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context/fake_evacuation_context"
+	"code.cloudfoundry.org/rep/featureflags"
 	"code.cloudfoundry.org/rep/generator/internal"
 	"code.cloudfoundry.org/rep/generator/internal/fake_internal"
+	"code.cloudfoundry.org/rep/healthchecks/healthchecksfakes"
+	"code.cloudfoundry.org/rep/lifecyclehints/lifecyclehintsfakes"
+	"code.cloudfoundry.org/rep/proxyreadiness/proxyreadinessfakes"
 	"code.cloudfoundry.org/routing-info/internalroutes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -25,11 +32,17 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 	const expectedCellID = "cell-id"
 
 	var (
-		processor          internal.LRPProcessor
-		logger             *lagertest.TestLogger
-		bbsClient          *fake_bbs.FakeInternalClient
-		containerDelegate  *fake_internal.FakeContainerDelegate
-		evacuationReporter *fake_evacuation_context.FakeEvacuationReporter
+		processor            internal.LRPProcessor
+		logger               *lagertest.TestLogger
+		bbsClient            *fake_bbs.FakeInternalClient
+		containerDelegate    *fake_internal.FakeContainerDelegate
+		evacuationReporter   *fake_evacuation_context.FakeEvacuationReporter
+		proxyReadinessWaiter *proxyreadinessfakes.FakeWaiter
+		hintPublisher        *lifecyclehintsfakes.FakePublisher
+		crashLoopDetector    *crashloopfakes.FakeDetector
+		healthCheckRelaxer   *healthchecksfakes.FakeRelaxer
+		workGroupHolds       *rep.WorkGroupHolds
+		featureFlags         *featureflags.Flags
 	)
 
 	BeforeEach(func() {
@@ -37,7 +50,13 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 		containerDelegate = new(fake_internal.FakeContainerDelegate)
 		evacuationReporter = &fake_evacuation_context.FakeEvacuationReporter{}
 		evacuationReporter.EvacuatingReturns(false)
-		processor = internal.NewLRPProcessor(bbsClient, containerDelegate, nil, expectedCellID, rep.StackPathMap{}, "", evacuationReporter)
+		proxyReadinessWaiter = new(proxyreadinessfakes.FakeWaiter)
+		hintPublisher = new(lifecyclehintsfakes.FakePublisher)
+		crashLoopDetector = new(crashloopfakes.FakeDetector)
+		healthCheckRelaxer = new(healthchecksfakes.FakeRelaxer)
+		workGroupHolds = rep.NewWorkGroupHolds()
+		featureFlags = featureflags.New(nil)
+		processor = internal.NewLRPProcessor(bbsClient, containerDelegate, nil, expectedCellID, rep.StackPathMap{}, "", evacuationReporter, proxyReadinessWaiter, hintPublisher, crashLoopDetector, healthCheckRelaxer, workGroupHolds, featureFlags)
 		logger = lagertest.NewTestLogger("test")
 	})
 
@@ -130,6 +149,33 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 					})
 				})
 
+				Context("when the claims of its work group are held", func() {
+					BeforeEach(func() {
+						container.Tags[rep.WorkGroupTag] = "web-with-migration"
+						containerDelegate.GetContainerReturns(container, true)
+						workGroupHolds.Hold("web-with-migration")
+						go func() {
+							defer GinkgoRecover()
+							Consistently(bbsClient.ClaimActualLRPCallCount).Should(Equal(0))
+							workGroupHolds.Release("web-with-migration")
+						}()
+					})
+
+					It("claims the actualLRP once they are released", func() {
+						Expect(bbsClient.ClaimActualLRPCallCount()).To(Equal(1))
+					})
+
+					Context("when the container is released with the group", func() {
+						BeforeEach(func() {
+							containerDelegate.GetContainerReturns(executor.Container{}, false)
+						})
+
+						It("does not claim the actualLRP", func() {
+							Expect(bbsClient.ClaimActualLRPCallCount()).To(Equal(0))
+						})
+					})
+				})
+
 				Context("when claiming succeeds", func() {
 					It("runs the container", func() {
 						Expect(containerDelegate.RunContainerCallCount()).To(Equal(1))
@@ -143,6 +189,96 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 						Expect(delegateLogger.SessionName()).To(Equal(expectedSessionName))
 					})
 
+					Context("when the instance has init steps", func() {
+						var initSteps []rep.InitStep
+
+						BeforeEach(func() {
+							initSteps = []rep.InitStep{{Name: "migrate", Path: "/bin/migrate", TimeoutMs: 60000}}
+							rep.AddInitStepsTag(container.Tags, initSteps)
+						})
+
+						It("runs the init steps after the setup of the container", func() {
+							Expect(containerDelegate.RunContainerCallCount()).To(Equal(1))
+
+							runRequestConversionHelper := rep.RunRequestConversionHelper{ECRHelper: &fakeecrhelper.FakeECRHelper{}}
+							expectedRunRequest, err := runRequestConversionHelper.NewRunRequestFromDesiredLRP(container.Guid, desiredLRP, &expectedLrpKey, &expectedInstanceKey, rep.StackPathMap{}, "")
+							Expect(err).NotTo(HaveOccurred())
+							rep.AddInitSteps(&expectedRunRequest.RunInfo, initSteps)
+
+							_, runRequest := containerDelegate.RunContainerArgsForCall(0)
+							Expect(*runRequest).To(Equal(expectedRunRequest))
+						})
+					})
+
+					Context("when the instance requests static host ports", func() {
+						BeforeEach(func() {
+							rep.AddStaticHostPortsTag(container.Tags, []uint16{61001, 61002})
+						})
+
+						It("requests the static host ports from the executor", func() {
+							Expect(containerDelegate.RunContainerCallCount()).To(Equal(1))
+
+							runRequestConversionHelper := rep.RunRequestConversionHelper{ECRHelper: &fakeecrhelper.FakeECRHelper{}}
+							expectedRunRequest, err := runRequestConversionHelper.NewRunRequestFromDesiredLRP(container.Guid, desiredLRP, &expectedLrpKey, &expectedInstanceKey, rep.StackPathMap{}, "")
+							Expect(err).NotTo(HaveOccurred())
+							rep.MapStaticHostPorts(&expectedRunRequest.RunInfo, []uint16{61001, 61002})
+
+							_, runRequest := containerDelegate.RunContainerArgsForCall(0)
+							Expect(*runRequest).To(Equal(expectedRunRequest))
+							Expect(runRequest.RunInfo.Ports[0].HostPort).To(BeEquivalentTo(61001))
+						})
+					})
+
+					Context("when the static host ports of the instance cannot be decoded", func() {
+						BeforeEach(func() {
+							container.Tags[rep.StaticHostPortsTag] = "not-json"
+						})
+
+						It("does not run the container", func() {
+							Expect(containerDelegate.RunContainerCallCount()).To(Equal(0))
+							Expect(logger).To(Say("failed-to-decode-static-host-ports"))
+						})
+					})
+
+					Context("when the health checks of the instance are relaxed", func() {
+						var relaxation rep.HealthCheckRelaxation
+
+						BeforeEach(func() {
+							relaxation = rep.HealthCheckRelaxation{ProcessGuid: "process-guid", Index: 2, Suspend: true}
+							healthCheckRelaxer.RelaxationReturns(relaxation, true)
+						})
+
+						It("runs the container with the relaxed health checks", func() {
+							Expect(healthCheckRelaxer.RelaxationCallCount()).To(Equal(1))
+							processGuid, index := healthCheckRelaxer.RelaxationArgsForCall(0)
+							Expect(processGuid).To(Equal("process-guid"))
+							Expect(index).To(BeEquivalentTo(2))
+
+							runRequestConversionHelper := rep.RunRequestConversionHelper{ECRHelper: &fakeecrhelper.FakeECRHelper{}}
+							expectedRunRequest, err := runRequestConversionHelper.NewRunRequestFromDesiredLRP(container.Guid, desiredLRP, &expectedLrpKey, &expectedInstanceKey, rep.StackPathMap{}, "")
+							Expect(err).NotTo(HaveOccurred())
+							relaxation.Apply(&expectedRunRequest.RunInfo)
+							rep.AddHealthCheckRelaxationTag(expectedRunRequest.Tags, relaxation)
+
+							_, runRequest := containerDelegate.RunContainerArgsForCall(0)
+							Expect(*runRequest).To(Equal(expectedRunRequest))
+							Expect(runRequest.RunInfo.Monitor).To(BeNil())
+							Expect(runRequest.RunInfo.CheckDefinition).To(BeNil())
+							Expect(runRequest.Tags).To(HaveKey(rep.HealthCheckRelaxationTag))
+						})
+					})
+
+					Context("when the init steps of the instance cannot be decoded", func() {
+						BeforeEach(func() {
+							container.Tags[rep.InitStepsTag] = "not-json"
+						})
+
+						It("does not run the container", func() {
+							Expect(containerDelegate.RunContainerCallCount()).To(Equal(0))
+							Expect(logger).To(Say("failed-to-decode-init-steps"))
+						})
+					})
+
 					Context("when running fails", func() {
 						BeforeEach(func() {
 							containerDelegate.RunContainerReturns(false)
@@ -241,11 +377,40 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 						))
 					})
 
+					It("waits for the proxy to be ready before starting the lrp", func() {
+						Expect(proxyReadinessWaiter.WaitCallCount()).To(Equal(1))
+						_, waitedContainer := proxyReadinessWaiter.WaitArgsForCall(0)
+						Expect(waitedContainer).To(Equal(container))
+					})
+
+					Context("when waiting for the proxy fails", func() {
+						BeforeEach(func() {
+							proxyReadinessWaiter.WaitStub = func(lager.Logger, executor.Container) error {
+								Expect(bbsClient.StartActualLRPCallCount()).To(Equal(0))
+								return errors.New("timed out")
+							}
+						})
+
+						It("starts the lrp anyway", func() {
+							Expect(bbsClient.StartActualLRPCallCount()).To(Equal(1))
+						})
+					})
+
+					It("publishes a started hint", func() {
+						Expect(hintPublisher.ContainerStartedCallCount()).To(Equal(1))
+						_, hintedContainer := hintPublisher.ContainerStartedArgsForCall(0)
+						Expect(hintedContainer).To(Equal(container))
+					})
+
 					Context("when starting fails because ErrActualLRPCannotBeStarted", func() {
 						BeforeEach(func() {
 							bbsClient.StartActualLRPReturns(models.NewError(models.Error_ActualLRPCannotBeStarted, "foobar").ToError())
 						})
 
+						It("does not publish a started hint", func() {
+							Expect(hintPublisher.ContainerStartedCallCount()).To(Equal(0))
+						})
+
 						It("stops the container", func() {
 							Expect(containerDelegate.StopContainerCallCount()).To(Equal(1))
 							delegateLogger, containerGuid := containerDelegate.StopContainerArgsForCall(0)
@@ -272,6 +437,12 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 						container.State = executor.StateCompleted
 					})
 
+					It("publishes a stopped hint", func() {
+						Expect(hintPublisher.ContainerStoppedCallCount()).To(Equal(1))
+						_, hintedContainer := hintPublisher.ContainerStoppedArgsForCall(0)
+						Expect(hintedContainer).To(Equal(container))
+					})
+
 					Context("and the container was requested to stop", func() {
 						BeforeEach(func() {
 							container.RunResult.Stopped = true
@@ -322,12 +493,73 @@ var _ = Describe("OrdinaryLRPProcessor", func() {
 							Expect(reason).To(Equal("crashed"))
 						})
 
+						It("records the crash", func() {
+							Expect(crashLoopDetector.RecordCrashCallCount()).To(Equal(1))
+							_, processGuid, index := crashLoopDetector.RecordCrashArgsForCall(0)
+							Expect(processGuid).To(Equal(expectedLrpKey.ProcessGuid))
+							Expect(index).To(Equal(expectedLrpKey.Index))
+						})
+
 						It("deletes the container", func() {
 							Expect(containerDelegate.DeleteContainerCallCount()).To(Equal(1))
 							delegateLogger, containerGuid := containerDelegate.DeleteContainerArgsForCall(0)
 							Expect(containerGuid).To(Equal(container.Guid))
 							Expect(delegateLogger.SessionName()).To(Equal(expectedSessionName))
 						})
+
+						Context("when the crash quarantines the instance", func() {
+							BeforeEach(func() {
+								crashLoopDetector.RecordCrashReturns(true)
+							})
+
+							It("crashes the actual LRP with the quarantine reason", func() {
+								Expect(bbsClient.CrashActualLRPCallCount()).To(Equal(1))
+								_, _, _, reason := bbsClient.CrashActualLRPArgsForCall(0)
+								Expect(reason).To(Equal(crashloop.QuarantineReason("crashed")))
+							})
+						})
+
+						It("does not restart the instance on the cell", func() {
+							Expect(containerDelegate.ReallocateContainerCallCount()).To(Equal(0))
+						})
+
+						Context("when local restarts are enabled", func() {
+							BeforeEach(func() {
+								featureFlags.Update(map[string]bool{featureflags.LocalRestart: true})
+								containerDelegate.ReallocateContainerReturns(true)
+							})
+
+							It("reserves the container again instead of crashing the actual LRP", func() {
+								Expect(containerDelegate.ReallocateContainerCallCount()).To(Equal(1))
+								_, reallocated := containerDelegate.ReallocateContainerArgsForCall(0)
+								Expect(reallocated).To(Equal(container))
+
+								Expect(bbsClient.CrashActualLRPCallCount()).To(Equal(0))
+								Expect(containerDelegate.DeleteContainerCallCount()).To(Equal(0))
+								Expect(hintPublisher.ContainerStoppedCallCount()).To(Equal(1))
+							})
+
+							Context("when the container cannot be reserved again", func() {
+								BeforeEach(func() {
+									containerDelegate.ReallocateContainerReturns(false)
+								})
+
+								It("crashes the actual LRP", func() {
+									Expect(bbsClient.CrashActualLRPCallCount()).To(Equal(1))
+								})
+							})
+
+							Context("when the crash quarantines the instance", func() {
+								BeforeEach(func() {
+									crashLoopDetector.RecordCrashReturns(true)
+								})
+
+								It("hands the instance back to the BBS", func() {
+									Expect(containerDelegate.ReallocateContainerCallCount()).To(Equal(0))
+									Expect(bbsClient.CrashActualLRPCallCount()).To(Equal(1))
+								})
+							})
+						})
 					})
 				})
 
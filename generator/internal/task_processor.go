@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/taskcompletion"
 )
 
 const TaskCompletionReasonMissingContainer = "task container does not exist"
@@ -23,22 +24,26 @@ type TaskProcessor interface {
 type taskProcessor struct {
 	bbsClient                  bbs.InternalClient
 	containerDelegate          ContainerDelegate
+	completer                  taskcompletion.Completer
 	cellID                     string
 	stackPathMap               rep.StackPathMap
 	layeringMode               string
 	runRequestConversionHelper rep.RunRequestConversionHelper
+	workGroupHolds             *rep.WorkGroupHolds
 }
 
-func NewTaskProcessor(bbs bbs.InternalClient, containerDelegate ContainerDelegate, cellID string, stackPathMap rep.StackPathMap, layeringMode string) TaskProcessor {
+func NewTaskProcessor(bbs bbs.InternalClient, containerDelegate ContainerDelegate, completer taskcompletion.Completer, cellID string, stackPathMap rep.StackPathMap, layeringMode string, workGroupHolds *rep.WorkGroupHolds) TaskProcessor {
 	runRequestConversionHelper := rep.RunRequestConversionHelper{ECRHelper: ecrhelper.NewECRHelper()}
 
 	return &taskProcessor{
 		bbsClient:                  bbs,
 		containerDelegate:          containerDelegate,
+		completer:                  completer,
 		cellID:                     cellID,
 		stackPathMap:               stackPathMap,
 		layeringMode:               layeringMode,
 		runRequestConversionHelper: runRequestConversionHelper,
+		workGroupHolds:             workGroupHolds,
 	}
 }
 
@@ -54,6 +59,9 @@ func (p *taskProcessor) Process(logger lager.Logger, container executor.Containe
 	switch container.State {
 	case executor.StateReserved:
 		logger.Debug("processing-reserved-container")
+		if !waitForWorkGroup(logger, p.workGroupHolds, p.containerDelegate, container) {
+			return
+		}
 		p.processActiveContainer(logger, container)
 	case executor.StateInitializing:
 		logger.Debug("processing-initializing-container")
@@ -71,6 +79,7 @@ func (p *taskProcessor) Process(logger lager.Logger, container executor.Containe
 }
 
 func (p *taskProcessor) processActiveContainer(logger lager.Logger, container executor.Container) {
+	logger = rep.WithProvenance(rep.WithTraceID(logger, container.Tags), rep.ProvenanceFromTags(container.Tags))
 	ok := p.startTask(logger, container.Guid)
 	if !ok {
 		return
@@ -87,10 +96,16 @@ func (p *taskProcessor) processActiveContainer(logger lager.Logger, container ex
 		logger.Error("failed-to-construct-run-request", err)
 		return
 	}
+	staticHostPorts, err := rep.StaticHostPortsFromTags(container.Tags)
+	if err != nil {
+		logger.Error("failed-to-decode-static-host-ports", err)
+		return
+	}
+	rep.MapStaticHostPorts(&runReq.RunInfo, staticHostPorts)
 
 	ok = p.containerDelegate.RunContainer(logger, &runReq)
 	if !ok {
-		err = p.bbsClient.CompleteTask(logger, container.Guid, p.cellID, true, TaskCompletionReasonFailedToRunContainer, "")
+		err = p.completer.Complete(logger, taskcompletion.Completion{TaskGuid: container.Guid, Failed: true, FailureReason: TaskCompletionReasonFailedToRunContainer, Provenance: rep.ProvenanceFromTags(container.Tags)})
 		if err != nil {
 			logger.Error("failed-completing-task", err)
 		}
@@ -130,6 +145,7 @@ func (p *taskProcessor) startTask(logger lager.Logger, guid string) bool {
 func (p *taskProcessor) completeTask(logger lager.Logger, container executor.Container) {
 	var result string
 	var err error
+	provenance := rep.ProvenanceFromTags(container.Tags)
 
 	if container.RunResult.Failed && container.RunResult.Retryable {
 		logger.Info("rejecting-task")
@@ -144,7 +160,7 @@ func (p *taskProcessor) completeTask(logger lager.Logger, container executor.Con
 	if !container.RunResult.Failed && resultFile != "" {
 		result, err = p.containerDelegate.FetchContainerResultFile(logger, container.Guid, resultFile)
 		if err != nil {
-			err = p.bbsClient.CompleteTask(logger, container.Guid, p.cellID, true, TaskCompletionReasonFailedToFetchResult, "")
+			err = p.completer.Complete(logger, taskcompletion.Completion{TaskGuid: container.Guid, Failed: true, FailureReason: TaskCompletionReasonFailedToFetchResult, Provenance: provenance})
 			if err != nil {
 				logger.Error("failed-completing-task", err)
 			}
@@ -153,13 +169,19 @@ func (p *taskProcessor) completeTask(logger lager.Logger, container executor.Con
 	}
 
 	logger.Info("completing-task")
-	err = p.bbsClient.CompleteTask(logger, container.Guid, p.cellID, container.RunResult.Failed, container.RunResult.FailureReason, result)
+	err = p.completer.Complete(logger, taskcompletion.Completion{
+		TaskGuid:      container.Guid,
+		Failed:        container.RunResult.Failed,
+		FailureReason: container.RunResult.FailureReason,
+		Result:        result,
+		Provenance:    provenance,
+	})
 	if err != nil {
 		logger.Error("failed-completing-task", err)
 
 		bbsErr := models.ConvertError(err)
 		if bbsErr.Type == models.Error_InvalidStateTransition {
-			err = p.bbsClient.CompleteTask(logger, container.Guid, p.cellID, true, TaskCompletionReasonInvalidTransition, "")
+			err = p.completer.Complete(logger, taskcompletion.Completion{TaskGuid: container.Guid, Failed: true, FailureReason: TaskCompletionReasonInvalidTransition, Provenance: provenance})
 			if err != nil {
 				logger.Error("failed-completing-task", err)
 			}
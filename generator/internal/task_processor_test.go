@@ -14,6 +14,7 @@ import (
 	"code.cloudfoundry.org/rep/generator/internal/fake_internal"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 )
 
 var processor internal.TaskProcessor
@@ -27,6 +28,7 @@ var _ = Describe("TaskProcessor", func() {
 		task                     *models.Task
 		expectedRunRequest       executor.RunRequest
 		container                executor.Container
+		workGroupHolds           *rep.WorkGroupHolds
 	)
 
 	BeforeEach(func() {
@@ -38,8 +40,9 @@ var _ = Describe("TaskProcessor", func() {
 
 		expectedCellID = "the-cell"
 		taskGuid = "the-guid"
+		workGroupHolds = rep.NewWorkGroupHolds()
 
-		processor = internal.NewTaskProcessor(bbsClient, containerDelegate, expectedCellID, rep.StackPathMap{}, "")
+		processor = internal.NewTaskProcessor(bbsClient, containerDelegate, taskcompletion.NewBBSCompleter(bbsClient, expectedCellID), expectedCellID, rep.StackPathMap{}, "", workGroupHolds)
 
 		task = model_helpers.NewValidTask(taskGuid)
 		runRequestConversionHelper := rep.RunRequestConversionHelper{ECRHelper: &fakeecrhelper.FakeECRHelper{}}
@@ -71,6 +74,19 @@ var _ = Describe("TaskProcessor", func() {
 			Expect(runReq).To(Equal(&expectedRunRequest))
 		})
 
+		Context("when the task requests static host ports", func() {
+			BeforeEach(func() {
+				container.Tags = executor.Tags{}
+				rep.AddStaticHostPortsTag(container.Tags, []uint16{9000})
+			})
+
+			It("requests the static host ports from the executor", func() {
+				Expect(containerDelegate.RunContainerCallCount()).To(Equal(1))
+				_, runReq := containerDelegate.RunContainerArgsForCall(0)
+				Expect(runReq.RunInfo.Ports).To(Equal([]executor.PortMapping{{ContainerPort: 9000, HostPort: 9000}}))
+			})
+		})
+
 		Context("when the task hasn't changed", func() {
 			BeforeEach(func() {
 				bbsClient.StartTaskReturns(false, nil)
@@ -207,6 +223,23 @@ var _ = Describe("TaskProcessor", func() {
 		})
 
 		itProcessesAnActiveContainer()
+
+		Context("when its container is released with its work group", func() {
+			BeforeEach(func() {
+				container.Tags = executor.Tags{rep.WorkGroupTag: "web-with-migration"}
+				containerDelegate.GetContainerReturns(executor.Container{}, false)
+				workGroupHolds.Hold("web-with-migration")
+				go func() {
+					defer GinkgoRecover()
+					Consistently(bbsClient.StartTaskCallCount).Should(Equal(0))
+					workGroupHolds.Release("web-with-migration")
+				}()
+			})
+
+			It("does not start the task", func() {
+				Expect(bbsClient.StartTaskCallCount()).To(Equal(0))
+			})
+		})
 	})
 
 	Context("when the container is completed", func() {
@@ -318,6 +351,19 @@ var _ = Describe("TaskProcessor", func() {
 				})
 			})
 
+			Context("and the container records the provenance of the task", func() {
+				BeforeEach(func() {
+					container.Tags[rep.ProvenanceTag] = `{"auction_id":"auction-1","auctioneer_id":"auctioneer-0","attempt":2}`
+				})
+
+				It("completes the task with a logger logging the provenance", func() {
+					Expect(bbsClient.CompleteTaskCallCount()).To(Equal(1))
+					completeLogger, _, _, _, _, _ := bbsClient.CompleteTaskArgsForCall(0)
+					completeLogger.Info("complete-task")
+					Expect(logger).To(gbytes.Say(`"auction-id":"auction-1"`))
+				})
+			})
+
 			Context("and fetching the container result fails", func() {
 				BeforeEach(func() {
 					containerDelegate.FetchContainerResultFileReturns("", errors.New("get outta here"))
@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// waitForWorkGroup waits while the claim of a reserved container is held for
+// its work group, and returns whether the container is still there to be
+// claimed, as the containers of a group that turns out incomplete are
+// released in the meantime.
+func waitForWorkGroup(logger lager.Logger, holds *rep.WorkGroupHolds, containerDelegate ContainerDelegate, container executor.Container) bool {
+	group := container.Tags[rep.WorkGroupTag]
+	if holds == nil || group == "" {
+		return true
+	}
+
+	if !holds.Wait(group) {
+		return true
+	}
+	if _, ok := containerDelegate.GetContainer(logger, container.Guid); !ok {
+		logger.Info("container-of-work-group-released", lager.Data{"group": group})
+		return false
+	}
+	return true
+}
@@ -434,6 +434,23 @@ var _ = Describe("Operation", func() {
 					})
 				})
 
+				Context("when the container is a self test container", func() {
+					BeforeEach(func() {
+						container = executor.Container{
+							Tags: executor.Tags{
+								rep.LifecycleTag: rep.SelfTestLifecycle,
+							},
+						}
+						containerDelegate.GetContainerReturns(container, true)
+					})
+
+					It("leaves the container to the self test", func() {
+						Expect(lrpProcessor.ProcessCallCount()).To(Equal(0))
+						Expect(taskProcessor.ProcessCallCount()).To(Equal(0))
+						Expect(logger).NotTo(Say("failed-to-process-container-with-unknown-lifecycle"))
+					})
+				})
+
 				Context("when the container has an unknown lifecycle tag", func() {
 					BeforeEach(func() {
 						container = executor.Container{
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . AuctionRoutesCloser
+type AuctionRoutesCloser interface {
+	CloseAuctionRoutes(logger lager.Logger) rep.AuctionRoutesStatus
+	OpenAuctionRoutes(logger lager.Logger) rep.AuctionRoutesStatus
+	AuctionRoutesStatus() rep.AuctionRoutesStatus
+}
+
+// closableAuctionRoute serves the requests of an auction route with handler
+// unless the auction routes are closed, in which case it turns them away as
+// it would were the cell unavailable.
+func closableAuctionRoute(closer AuctionRoutesCloser, handler func(http.ResponseWriter, *http.Request, lager.Logger)) func(http.ResponseWriter, *http.Request, lager.Logger) {
+	return func(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+		if closer.AuctionRoutesStatus().Closed {
+			logger.Info("auction-routes-closed")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, r, logger)
+	}
+}
+
+type auctionRoutesHandler struct {
+	closer  AuctionRoutesCloser
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Auction Routes Handler tells whether the auction routes of the cell are
+// closed
+func newAuctionRoutesHandler(closer AuctionRoutesCloser, metrics helpers.RequestMetrics, clock clock.Clock) *auctionRoutesHandler {
+	return &auctionRoutesHandler{
+		closer:  closer,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *auctionRoutesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "AuctionRoutes"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-auction-routes")
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.closer.AuctionRoutesStatus())
+	if deferErr != nil {
+		logger.Error("failed-to-encode-auction-routes-status", deferErr)
+	}
+}
+
+type closeAuctionRoutesHandler struct {
+	closer  AuctionRoutesCloser
+	close   bool
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Close Auction Routes Handler closes the routes the auctioneer uses to see
+// and place work on the cell, freezing the scheduling of work onto it, or
+// opens them again. The admin routes and those managing the containers of the
+// cell stay open. The routes are open again once the rep restarts
+func newCloseAuctionRoutesHandler(closer AuctionRoutesCloser, close bool, metrics helpers.RequestMetrics, clock clock.Clock) *closeAuctionRoutesHandler {
+	return &closeAuctionRoutesHandler{
+		closer:  closer,
+		close:   close,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *closeAuctionRoutesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "OpenAuctionRoutes"
+	if h.close {
+		requestType = "CloseAuctionRoutes"
+	}
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-close-auction-routes", lager.Data{"close": h.close})
+
+	var status rep.AuctionRoutesStatus
+	if h.close {
+		status = h.closer.CloseAuctionRoutes(logger)
+	} else {
+		status = h.closer.OpenAuctionRoutes(logger)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(status)
+	if deferErr != nil {
+		logger.Error("failed-to-encode-auction-routes-status", deferErr)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuctionRoutes", func() {
+	closed := rep.AuctionRoutesStatus{Closed: true, ClosedAt: 1700000000}
+
+	It("returns whether the auction routes are closed", func() {
+		fakeAuctionRoutesCloser.AuctionRoutesStatusReturns(closed)
+
+		status, body := Request(rep.AuctionRoutesRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(closed)))
+	})
+
+	It("closes the auction routes", func() {
+		fakeAuctionRoutesCloser.CloseAuctionRoutesReturns(closed)
+
+		status, body := Request(rep.CloseAuctionRoutesRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(closed)))
+		Expect(fakeAuctionRoutesCloser.CloseAuctionRoutesCallCount()).To(Equal(1))
+		Expect(fakeAuctionRoutesCloser.OpenAuctionRoutesCallCount()).To(Equal(0))
+	})
+
+	It("opens the auction routes", func() {
+		status, body := Request(rep.OpenAuctionRoutesRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(rep.AuctionRoutesStatus{})))
+		Expect(fakeAuctionRoutesCloser.OpenAuctionRoutesCallCount()).To(Equal(1))
+	})
+
+	Context("when the auction routes are closed", func() {
+		BeforeEach(func() {
+			fakeAuctionRoutesCloser.AuctionRoutesStatusReturns(closed)
+		})
+
+		It("turns away the requests of the auctioneer", func() {
+			status, _ := Request(rep.StateRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			status, _ = Request(rep.CapacitySummaryRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			status, _ = Request(rep.PerformRoute, nil, JSONReaderFor(rep.Work{}))
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			status, _ = Request(rep.CanPlaceRoute, nil, JSONReaderFor(rep.Work{}))
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			status, _ = Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(rep.CapacityReservationRequest{}))
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			status, _ = Request(rep.ReleaseCapacityRoute, rata.Params{"reservation_id": "some-reservation"}, nil)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+
+			Expect(fakeLocalRep.StateCallCount()).To(Equal(0))
+			Expect(fakeLocalRep.CapacitySummaryCallCount()).To(Equal(0))
+			Expect(fakeLocalRep.PerformCallCount()).To(Equal(0))
+			Expect(fakeCapacityReserver.ReserveCapacityCallCount()).To(Equal(0))
+			Expect(fakeCapacityReserver.ReleaseCapacityCallCount()).To(Equal(0))
+		})
+
+		It("keeps serving the other routes", func() {
+			status, _ := Request(rep.ContainersRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusOK))
+			status, _ = Request(rep.PlacementTagsRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep/downloadcache"
+)
+
+//go:generate counterfeiter . CacheStatsReporter
+type CacheStatsReporter interface {
+	Stats() downloadcache.Stats
+}
+
+type cacheStatsHandler struct {
+	reporter CacheStatsReporter
+	metrics  helpers.RequestMetrics
+	clock    clock.Clock
+}
+
+// Cache Stats Handler serves how often the downloads of the containers on the
+// cell were served from the executor download cache
+func newCacheStatsHandler(reporter CacheStatsReporter, metrics helpers.RequestMetrics, clock clock.Clock) *cacheStatsHandler {
+	return &cacheStatsHandler{
+		reporter: reporter,
+		metrics:  metrics,
+		clock:    clock,
+	}
+}
+
+func (h *cacheStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "CacheStats"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-cache-stats")
+
+	if h.reporter == nil {
+		logger.Info("download-cache-stats-not-configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.reporter.Stats())
+}
@@ -0,0 +1,55 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/downloadcache"
+	"code.cloudfoundry.org/rep/handlers"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CacheStats", func() {
+	It("serves the download cache statistics", func() {
+		stats := downloadcache.Stats{
+			Kinds: map[string]downloadcache.KindStats{
+				downloadcache.KindDroplet:   {Hits: 3, Misses: 1, BytesSaved: 3072},
+				downloadcache.KindBuildpack: {Misses: 2, Evictions: 1, EvictedBytes: 1024},
+			},
+			CachedEntries: 4,
+			CachedBytes:   4096,
+		}
+		fakeCacheStatsReporter.StatsReturns(stats)
+
+		status, body := Request(rep.CacheStatsRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(stats)))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.CacheStatsRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("CacheStats"))
+	})
+
+	Context("when download cache statistics are not configured", func() {
+		It("responds with 501 Not Implemented", func() {
+			adminHandlers := handlers.NewAdmin(fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, nil, fakeSelfTester, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeRequestMetrics, fakeClock, logger)
+			router, err := rata.NewRouter(rep.RoutesAdmin, adminHandlers)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := rata.NewRequestGenerator("", rep.RoutesAdmin).CreateRequest(rep.CacheStatsRoute, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+type canPlaceHandler struct {
+	rep          auctioncellrep.StateReporter
+	infoReporter InfoReporter
+	metrics      helpers.RequestMetrics
+	clock        clock.Clock
+}
+
+// Can Place Handler tells whether the cell would accept the work, and how the
+// auctioneer would score it by the strategy it names, without allocating or
+// reserving anything
+func newCanPlaceHandler(rep auctioncellrep.StateReporter, infoReporter InfoReporter, metrics helpers.RequestMetrics, clock clock.Clock) *canPlaceHandler {
+	return &canPlaceHandler{rep: rep, infoReporter: infoReporter, metrics: metrics, clock: clock}
+}
+
+func (h *canPlaceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "CanPlace"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("auction-check-placement")
+	if limit := h.infoReporter.Info().Limits.MaxRequestBodyBytes; limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+	}
+
+	weight := 0.0
+	if value := r.URL.Query().Get("starting_container_weight"); value != "" {
+		weight, deferErr = strconv.ParseFloat(value, 64)
+		if deferErr != nil {
+			logger.Error("failed-to-parse-starting-container-weight", deferErr, lager.Data{"starting-container-weight": value})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	var strategy rep.ScoringStrategy = rep.SpreadStrategy{}
+	if name := r.URL.Query().Get("scoring_strategy"); name != "" {
+		var ok bool
+		strategy, ok = rep.ScoringStrategyNamed(name)
+		if !ok {
+			deferErr = fmt.Errorf("unknown scoring strategy: %q", name)
+			logger.Error("failed-to-parse-scoring-strategy", deferErr)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	var work rep.Work
+	deferErr = json.NewDecoder(r.Body).Decode(&work)
+	if deferErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		logger.Error("failed-to-unmarshal", deferErr)
+		return
+	}
+
+	var state rep.CellState
+	state, _, deferErr = h.rep.State(r.Context(), logger)
+	if deferErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		logger.Error("failed-to-fetch-state", deferErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rep.CheckPlacement(state, work, weight, strategy))
+}
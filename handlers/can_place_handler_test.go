@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CanPlace", func() {
+	var (
+		repState rep.CellState
+		work     rep.Work
+	)
+
+	BeforeEach(func() {
+		repState = rep.CellState{
+			CellID:             "cell-id",
+			RootFSProviders:    rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+			AvailableResources: rep.NewResources(512, 1024, 3),
+			TotalResources:     rep.NewResources(1024, 2048, 10),
+		}
+		fakeLocalRep.StateReturns(repState, true, nil)
+
+		constraint := rep.NewPlacementConstraint("docker:///busybox", nil, nil)
+		work = rep.Work{LRPs: []rep.LRP{
+			rep.NewLRP("ig-1", models.NewActualLRPKey("pg", 0, "domain"), rep.NewResource(256, 10, 10), constraint),
+			rep.NewLRP("ig-2", models.NewActualLRPKey("pg", 1, "domain"), rep.NewResource(1024, 10, 10), constraint),
+		}}
+	})
+
+	It("returns whether the cell would accept each item without performing it", func() {
+		status, body := Request(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(rep.CheckPlacement(repState, work, 0, rep.SpreadStrategy{}))))
+
+		var checks rep.PlacementChecks
+		Expect(json.Unmarshal(body, &checks)).To(Succeed())
+		Expect(checks.LRPs[0].Placeable).To(BeTrue())
+		Expect(checks.LRPs[1].Reason).To(Equal(rep.PlacementReasonInsufficientResources))
+
+		Expect(fakeLocalRep.PerformCallCount()).To(BeZero())
+	})
+
+	It("scores the cell with the starting container weight", func() {
+		request, err := requestGenerator.CreateRequest(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+		Expect(err).NotTo(HaveOccurred())
+		request.URL.RawQuery = "starting_container_weight=0.25"
+
+		response, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		var checks rep.PlacementChecks
+		Expect(json.NewDecoder(response.Body).Decode(&checks)).To(Succeed())
+		Expect(checks).To(Equal(rep.CheckPlacement(repState, work, 0.25, rep.SpreadStrategy{})))
+	})
+
+	It("scores the cell by the strategy the caller names", func() {
+		request, err := requestGenerator.CreateRequest(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+		Expect(err).NotTo(HaveOccurred())
+		request.URL.RawQuery = "scoring_strategy=bin-pack"
+
+		response, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		var checks rep.PlacementChecks
+		Expect(json.NewDecoder(response.Body).Decode(&checks)).To(Succeed())
+		Expect(checks).To(Equal(rep.CheckPlacement(repState, work, 0, rep.BinPackStrategy{})))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("CanPlace"))
+	})
+
+	Context("when the starting container weight is not a number", func() {
+		It("returns a StatusBadRequest", func() {
+			request, err := requestGenerator.CreateRequest(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+			Expect(err).NotTo(HaveOccurred())
+			request.URL.RawQuery = "starting_container_weight=heavy"
+
+			response, err := client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when the scoring strategy is unknown", func() {
+		It("returns a StatusBadRequest", func() {
+			request, err := requestGenerator.CreateRequest(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+			Expect(err).NotTo(HaveOccurred())
+			request.URL.RawQuery = "scoring_strategy=random"
+
+			response, err := client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when the work cannot be decoded", func() {
+		It("returns a StatusBadRequest", func() {
+			status, _ := Request(rep.CanPlaceRoute, nil, JSONReaderFor("garbage"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when the state call fails", func() {
+		BeforeEach(func() {
+			fakeLocalRep.StateReturns(rep.CellState{}, false, errors.New("boom"))
+		})
+
+		It("fails", func() {
+			status, body := Request(rep.CanPlaceRoute, nil, JSONReaderFor(work))
+			Expect(status).To(Equal(http.StatusInternalServerError))
+			Expect(body).To(BeEmpty())
+		})
+	})
+})
@@ -2,8 +2,8 @@ package handlers
 
 import (
 	"net/http"
-	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
@@ -12,22 +12,24 @@ import (
 type cancelTaskHandler struct {
 	executorClient executor.Client
 	metrics        helpers.RequestMetrics
+	clock          clock.Clock
 }
 
-func newCancelTaskHandler(executorClient executor.Client, requestMetrics helpers.RequestMetrics) *cancelTaskHandler {
+func newCancelTaskHandler(executorClient executor.Client, requestMetrics helpers.RequestMetrics, clock clock.Clock) *cancelTaskHandler {
 	return &cancelTaskHandler{
 		executorClient: executorClient,
 		metrics:        requestMetrics,
+		clock:          clock,
 	}
 }
 
 func (h *cancelTaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "CancelTask"
 	startMetrics(h.metrics, requestType)
 	defer func() {
 		h.metrics.DecrementRequestsInFlightCounter(requestType, 1)
-		h.metrics.UpdateLatency(requestType, time.Since(start))
+		h.metrics.UpdateLatency(requestType, h.clock.Since(start))
 	}()
 
 	taskGuid := r.FormValue(":task_guid")
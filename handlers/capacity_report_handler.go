@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . CapacityReporter
+type CapacityReporter interface {
+	CapacityReport(hours int) rep.CapacityReport
+}
+
+type capacityReportHandler struct {
+	reporter CapacityReporter
+	metrics  helpers.RequestMetrics
+	clock    clock.Clock
+}
+
+// Capacity Report Handler serves the placements the cell accepted and
+// rejected over the hours given in the hours query parameter, the whole
+// retention of the placement history by default
+func newCapacityReportHandler(reporter CapacityReporter, metrics helpers.RequestMetrics, clock clock.Clock) *capacityReportHandler {
+	return &capacityReportHandler{
+		reporter: reporter,
+		metrics:  metrics,
+		clock:    clock,
+	}
+}
+
+func (h *capacityReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "CapacityReport"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-capacity-report")
+
+	if h.reporter == nil {
+		logger.Info("placement-history-not-configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	hours := 0
+	if value := r.URL.Query().Get("hours"); value != "" {
+		var err error
+		hours, err = strconv.Atoi(value)
+		if err != nil {
+			logger.Error("failed-to-parse-hours", err, lager.Data{"hours": value})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.reporter.CapacityReport(hours))
+	if deferErr != nil {
+		logger.Error("failed-to-encode-report", deferErr)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CapacityReport", func() {
+	var report rep.CapacityReport
+
+	requestReport := func(hours string) (int, []byte) {
+		request, err := requestGenerator.CreateRequest(rep.CapacityReportRoute, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if hours != "" {
+			request.URL.RawQuery = url.Values{"hours": []string{hours}}.Encode()
+		}
+
+		response, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return response.StatusCode, body
+	}
+
+	BeforeEach(func() {
+		report = rep.CapacityReport{
+			Since: time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC),
+			Hours: 6,
+			Placements: []rep.PlacementSummary{
+				{Kind: rep.LRPLifecycle, Shape: "512MB/1024MB", Accepted: 40, Rejected: map[string]int{rep.PlacementReasonInsufficientResources: 3}},
+			},
+			PeakUtilization: rep.Utilization{Memory: 0.9, Disk: 0.4, Containers: 0.2},
+		}
+		fakeCapacityReporter.CapacityReportReturns(report)
+	})
+
+	It("serves the report for the requested hours", func() {
+		status, body := requestReport("6")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(report)))
+		Expect(fakeCapacityReporter.CapacityReportArgsForCall(0)).To(Equal(6))
+	})
+
+	It("covers the whole retention without hours", func() {
+		status, _ := requestReport("")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(fakeCapacityReporter.CapacityReportArgsForCall(0)).To(Equal(0))
+	})
+
+	It("emits the request metrics", func() {
+		requestReport("")
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("CapacityReport"))
+	})
+
+	Context("when hours is not a number", func() {
+		It("responds with 400 Bad Request", func() {
+			status, _ := requestReport("a-day")
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeCapacityReporter.CapacityReportCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when placement history is not configured", func() {
+		It("responds with 501 Not Implemented", func() {
+			adminHandlers := handlers.NewAdmin(fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, fakeCacheStatsReporter, fakeSelfTester, nil, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeRequestMetrics, fakeClock, logger)
+			router, err := rata.NewRouter(rep.RoutesAdmin, adminHandlers)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := rata.NewRequestGenerator("", rep.RoutesAdmin).CreateRequest(rep.CapacityReportRoute, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+//go:generate counterfeiter . CapacityReserver
+type CapacityReserver interface {
+	ReserveCapacity(logger lager.Logger, request rep.CapacityReservationRequest) (rep.CapacityReservation, error)
+	ReleaseCapacity(logger lager.Logger, reservationID string) error
+}
+
+type reserveCapacityHandler struct {
+	reserver CapacityReserver
+	metrics  helpers.RequestMetrics
+	clock    clock.Clock
+}
+
+// Reserve Capacity Handler holds capacity on the cell for the instances of a
+// process ahead of them being auctioned
+func newReserveCapacityHandler(reserver CapacityReserver, metrics helpers.RequestMetrics, clock clock.Clock) *reserveCapacityHandler {
+	return &reserveCapacityHandler{
+		reserver: reserver,
+		metrics:  metrics,
+		clock:    clock,
+	}
+}
+
+func (h *reserveCapacityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "ReserveCapacity"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-reserve-capacity")
+
+	var request rep.CapacityReservationRequest
+	deferErr = json.NewDecoder(r.Body).Decode(&request)
+	if deferErr != nil {
+		logger.Error("failed-to-unmarshal", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var reservation rep.CapacityReservation
+	reservation, deferErr = h.reserver.ReserveCapacity(logger, request)
+	switch deferErr.(type) {
+	case nil:
+	case rep.InsufficientResourcesError:
+		logger.Info("insufficient-capacity", lager.Data{"error": deferErr.Error()})
+		w.WriteHeader(http.StatusConflict)
+		return
+	default:
+		switch deferErr {
+		case rep.ErrInvalidCapacityReservation, rep.ErrDeadlinePassed:
+			w.WriteHeader(http.StatusBadRequest)
+		case auctioncellrep.ErrCapacityReservationsDisabled:
+			w.WriteHeader(http.StatusNotImplemented)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		logger.Error("failed-to-reserve-capacity", deferErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+type releaseCapacityHandler struct {
+	reserver CapacityReserver
+	metrics  helpers.RequestMetrics
+	clock    clock.Clock
+}
+
+// Release Capacity Handler releases a capacity reservation before it expires
+func newReleaseCapacityHandler(reserver CapacityReserver, metrics helpers.RequestMetrics, clock clock.Clock) *releaseCapacityHandler {
+	return &releaseCapacityHandler{
+		reserver: reserver,
+		metrics:  metrics,
+		clock:    clock,
+	}
+}
+
+func (h *releaseCapacityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "ReleaseCapacity"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	reservationID := r.FormValue(":reservation_id")
+	logger = logger.Session("handling-release-capacity", lager.Data{"reservation-id": reservationID})
+
+	deferErr = h.reserver.ReleaseCapacity(logger, reservationID)
+	switch deferErr {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case auctioncellrep.ErrCapacityReservationNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case auctioncellrep.ErrCapacityReservationsDisabled:
+		w.WriteHeader(http.StatusNotImplemented)
+	default:
+		logger.Error("failed-to-release-capacity", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
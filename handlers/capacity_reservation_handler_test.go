@@ -0,0 +1,128 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReserveCapacity", func() {
+	var request rep.CapacityReservationRequest
+
+	BeforeEach(func() {
+		request = rep.NewCapacityReservationRequest("pg-new", rep.NewResource(256, 512, 10), 3, 300)
+	})
+
+	It("reserves the capacity and returns the reservation", func() {
+		reservation := rep.CapacityReservation{ID: "reservation-id", ProcessGuid: "pg-new", Resource: request.Resource, Instances: 3, ExpiresAt: 1234}
+		fakeCapacityReserver.ReserveCapacityReturns(reservation, nil)
+
+		status, body := Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(request))
+		Expect(status).To(Equal(http.StatusCreated))
+		Expect(body).To(MatchJSON(JSONFor(reservation)))
+
+		Expect(fakeCapacityReserver.ReserveCapacityCallCount()).To(Equal(1))
+		_, actualRequest := fakeCapacityReserver.ReserveCapacityArgsForCall(0)
+		Expect(actualRequest).To(Equal(request))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(request))
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("ReserveCapacity"))
+	})
+
+	Context("when the request cannot be decoded", func() {
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.ReserveCapacityRoute, nil, JSONReaderFor("not-a-request"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeCapacityReserver.ReserveCapacityCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the cell does not have the capacity", func() {
+		BeforeEach(func() {
+			fakeCapacityReserver.ReserveCapacityReturns(rep.CapacityReservation{}, rep.InsufficientResourcesError{Problems: map[string]struct{}{"memory": {}}})
+		})
+
+		It("responds with a conflict", func() {
+			status, _ := Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusConflict))
+			Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the reservation is invalid", func() {
+		BeforeEach(func() {
+			fakeCapacityReserver.ReserveCapacityReturns(rep.CapacityReservation{}, rep.ErrInvalidCapacityReservation)
+		})
+
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when capacity reservations are not enabled", func() {
+		BeforeEach(func() {
+			fakeCapacityReserver.ReserveCapacityReturns(rep.CapacityReservation{}, auctioncellrep.ErrCapacityReservationsDisabled)
+		})
+
+		It("responds with not implemented", func() {
+			status, _ := Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusNotImplemented))
+		})
+	})
+
+	Context("when reserving the capacity fails", func() {
+		BeforeEach(func() {
+			fakeCapacityReserver.ReserveCapacityReturns(rep.CapacityReservation{}, errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			status, _ := Request(rep.ReserveCapacityRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
+
+var _ = Describe("ReleaseCapacity", func() {
+	It("releases the reservation", func() {
+		status, _ := Request(rep.ReleaseCapacityRoute, rata.Params{"reservation_id": "reservation-id"}, nil)
+		Expect(status).To(Equal(http.StatusNoContent))
+
+		Expect(fakeCapacityReserver.ReleaseCapacityCallCount()).To(Equal(1))
+		_, reservationID := fakeCapacityReserver.ReleaseCapacityArgsForCall(0)
+		Expect(reservationID).To(Equal("reservation-id"))
+	})
+
+	Context("when the reservation does not exist", func() {
+		BeforeEach(func() {
+			fakeCapacityReserver.ReleaseCapacityReturns(auctioncellrep.ErrCapacityReservationNotFound)
+		})
+
+		It("responds with not found", func() {
+			status, _ := Request(rep.ReleaseCapacityRoute, rata.Params{"reservation_id": "reservation-id"}, nil)
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when capacity reservations are not enabled", func() {
+		BeforeEach(func() {
+			fakeCapacityReserver.ReleaseCapacityReturns(auctioncellrep.ErrCapacityReservationsDisabled)
+		})
+
+		It("responds with not implemented", func() {
+			status, _ := Request(rep.ReleaseCapacityRoute, rata.Params{"reservation_id": "reservation-id"}, nil)
+			Expect(status).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
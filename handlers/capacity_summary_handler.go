@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+type capacitySummaryHandler struct {
+	rep     auctioncellrep.CapacitySummarizer
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Capacity Summary Handler returns the aggregate capacity of the cell without
+// its LRP instances and tasks, for auctioneers that poll it often
+func newCapacitySummaryHandler(rep auctioncellrep.CapacitySummarizer, metrics helpers.RequestMetrics, clock clock.Clock) *capacitySummaryHandler {
+	return &capacitySummaryHandler{rep: rep, metrics: metrics, clock: clock}
+}
+
+func (h *capacitySummaryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "CapacitySummary"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("auction-fetch-capacity")
+
+	var summary rep.CapacitySummary
+	var healthy bool
+	summary, healthy, deferErr = h.rep.CapacitySummary(r.Context(), logger)
+	if deferErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		logger.Error("failed-to-fetch-capacity", deferErr)
+		return
+	}
+
+	if !healthy {
+		logger.Info("cell-not-healthy")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(summary)
+}
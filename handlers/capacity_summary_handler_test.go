@@ -0,0 +1,66 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CapacitySummary", func() {
+	var summary rep.CapacitySummary
+
+	BeforeEach(func() {
+		summary = rep.CapacitySummary{
+			CellID:             "cell-id",
+			Zone:               "z1",
+			RootFSProviders:    rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+			AvailableResources: rep.NewResources(512, 1024, 3),
+			TotalResources:     rep.NewResources(1024, 2048, 10),
+			PlacementTags:      []string{"gpu"},
+		}
+		fakeLocalRep.CapacitySummaryReturns(summary, true, nil)
+	})
+
+	It("returns the capacity of the cell without fetching its state", func() {
+		status, body := Request(rep.CapacitySummaryRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(summary)))
+		Expect(fakeLocalRep.StateCallCount()).To(BeZero())
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.CapacitySummaryRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("CapacitySummary"))
+	})
+
+	Context("when the cell is not healthy", func() {
+		BeforeEach(func() {
+			fakeLocalRep.CapacitySummaryReturns(summary, false, nil)
+		})
+
+		It("returns a StatusServiceUnavailable with the capacity", func() {
+			status, body := Request(rep.CapacitySummaryRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			Expect(body).To(MatchJSON(JSONFor(summary)))
+		})
+	})
+
+	Context("when summarizing the capacity fails", func() {
+		BeforeEach(func() {
+			fakeLocalRep.CapacitySummaryReturns(rep.CapacitySummary{}, false, errors.New("boom"))
+		})
+
+		It("fails", func() {
+			status, body := Request(rep.CapacitySummaryRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+			Expect(body).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . ConsistencyReporter
+type ConsistencyReporter interface {
+	Report(logger lager.Logger) (rep.ConsistencyReport, bool)
+}
+
+type consistencyHandler struct {
+	reporter ConsistencyReporter
+	metrics  helpers.RequestMetrics
+	clock    clock.Clock
+}
+
+// Consistency Handler serves the outcome of the last comparison of the LRP
+// containers of the cell with the actual LRPs BBS places on it
+func newConsistencyHandler(reporter ConsistencyReporter, metrics helpers.RequestMetrics, clock clock.Clock) *consistencyHandler {
+	return &consistencyHandler{
+		reporter: reporter,
+		metrics:  metrics,
+		clock:    clock,
+	}
+}
+
+func (h *consistencyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "Consistency"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-consistency")
+
+	if h.reporter == nil {
+		logger.Info("consistency-checker-not-configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	report, ok := h.reporter.Report(logger)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
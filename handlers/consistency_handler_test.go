@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Consistency", func() {
+	It("serves the last consistency report", func() {
+		report := rep.ConsistencyReport{
+			CheckedAt:  1234,
+			Containers: 2,
+			ActualLRPs: 2,
+			Divergences: []rep.ConsistencyDivergence{
+				{Kind: rep.DivergenceOrphanedContainer, ProcessGuid: "pg", Index: 1, InstanceGUID: "ig-1", State: "running"},
+			},
+		}
+		fakeConsistencyReporter.ReportReturns(report, true)
+
+		status, body := Request(rep.ConsistencyRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(report)))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.ConsistencyRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("Consistency"))
+	})
+
+	Context("when no check has completed yet", func() {
+		It("responds with 404 Not Found", func() {
+			status, _ := Request(rep.ConsistencyRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the consistency checker is not configured", func() {
+		It("responds with 501 Not Implemented", func() {
+			adminHandlers := handlers.NewAdmin(fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, nil, fakeCacheStatsReporter, fakeSelfTester, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeRequestMetrics, fakeClock, logger)
+			router, err := rata.NewRouter(rep.RoutesAdmin, adminHandlers)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := rata.NewRequestGenerator("", rep.RoutesAdmin).CreateRequest(rep.ConsistencyRoute, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . ContainerEventHistory
+type ContainerEventHistory interface {
+	Events(guid string) ([]rep.ContainerEvent, bool)
+}
+
+type containerEventsHandler struct {
+	history ContainerEventHistory
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Container Events Handler serves the recent lifecycle events of a container
+// on the cell, including one that is gone
+func newContainerEventsHandler(history ContainerEventHistory, metrics helpers.RequestMetrics, clock clock.Clock) *containerEventsHandler {
+	return &containerEventsHandler{
+		history: history,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *containerEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "ContainerEvents"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	containerGuid := r.FormValue(":container_guid")
+	logger = logger.Session("handling-container-events", lager.Data{"container-guid": containerGuid})
+
+	if h.history == nil {
+		logger.Info("container-event-history-not-configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	events, ok := h.history.Events(containerGuid)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(events)
+	if deferErr != nil {
+		logger.Error("failed-to-encode-container-events", deferErr)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContainerEvents", func() {
+	var params rata.Params
+
+	BeforeEach(func() {
+		params = rata.Params{"container_guid": "container-guid"}
+	})
+
+	It("serves the events of the container", func() {
+		events := []rep.ContainerEvent{
+			{Type: rep.ContainerEventReserved, Time: 1},
+			{Type: rep.ContainerEventCrashed, Time: 2, Message: "exited with status 1"},
+		}
+		fakeContainerEventHistory.EventsReturns(events, true)
+
+		status, body := Request(rep.ContainerEventsRoute, params, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(events)))
+
+		Expect(fakeContainerEventHistory.EventsCallCount()).To(Equal(1))
+		Expect(fakeContainerEventHistory.EventsArgsForCall(0)).To(Equal("container-guid"))
+	})
+
+	It("emits the request metrics", func() {
+		fakeContainerEventHistory.EventsReturns(nil, true)
+		Request(rep.ContainerEventsRoute, params, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("ContainerEvents"))
+	})
+
+	Context("when no event of the container was recorded", func() {
+		It("responds with 404 Not Found", func() {
+			status, _ := Request(rep.ContainerEventsRoute, params, nil)
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the container event history is not configured", func() {
+		It("responds with 501 Not Implemented", func() {
+			secureHandlers := handlers.New(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeMaintainable, fakePlannedRestarter, fakeInfoReporter, fakePerformQueue, fakeCapacityReserver, fakeDiskQuotaGrower, nil, fakeCgroupReader, fakeLogRateLimitReporter, fakeHealthCheckReporter, fakeAuctionRoutesCloser, fakeContactReporter, fakeRequestMetrics, fakeClock, logger, true)
+			router, err := rata.NewRouter(rep.RoutesNetworkAccessible, secureHandlers)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := rata.NewRequestGenerator("", rep.RoutesNetworkAccessible).CreateRequest(rep.ContainerEventsRoute, params, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
@@ -3,8 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 	"code.cloudfoundry.org/rep"
@@ -13,24 +13,26 @@ import (
 //go:generate counterfeiter . MetricCollector
 type MetricCollector interface {
 	Metrics(logger lager.Logger) (*rep.ContainerMetricsCollection, error)
+	MetricsBatch(logger lager.Logger, fields rep.ContainerMetricsFields) *rep.ContainerMetricsBatch
 }
 
 type containerMetrics struct {
 	rep     MetricCollector
 	metrics helpers.RequestMetrics
+	clock   clock.Clock
 }
 
-func newContainerMetricsHandler(rep MetricCollector, metrics helpers.RequestMetrics) *containerMetrics {
-	return &containerMetrics{rep: rep, metrics: metrics}
+func newContainerMetricsHandler(rep MetricCollector, metrics helpers.RequestMetrics, clock clock.Clock) *containerMetrics {
+	return &containerMetrics{rep: rep, metrics: metrics, clock: clock}
 }
 
 func (h *containerMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
 	var deferErr error
 
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "ContainerMetrics"
 	startMetrics(h.metrics, requestType)
-	defer stopMetrics(h.metrics, requestType, start, &deferErr)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
 
 	logger = logger.Session("container-metrics-handler")
 
@@ -45,3 +47,40 @@ func (h *containerMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request, log
 
 	json.NewEncoder(w).Encode(metricsCollector)
 }
+
+type containerMetricsBatch struct {
+	rep     MetricCollector
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// newContainerMetricsBatchHandler serves the metrics of every container on
+// the cell in one compact response, limited to the fields given in the fields
+// query parameter.
+func newContainerMetricsBatchHandler(rep MetricCollector, metrics helpers.RequestMetrics, clock clock.Clock) *containerMetricsBatch {
+	return &containerMetricsBatch{rep: rep, metrics: metrics, clock: clock}
+}
+
+func (h *containerMetricsBatch) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "ContainerMetricsBatch"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("container-metrics-batch-handler")
+
+	fields, err := rep.ParseContainerMetricsFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		logger.Error("failed-to-parse-fields", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.rep.MetricsBatch(logger, fields))
+	if deferErr != nil {
+		logger.Error("failed-to-encode-container-metrics", deferErr)
+	}
+}
@@ -2,7 +2,9 @@ package handlers_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -36,7 +38,7 @@ var _ = Describe("ContainerMetrics", func() {
 		requestLatency = 50 * time.Millisecond
 
 		fakeMetricCollector.MetricsStub = func(logger lager.Logger) (*rep.ContainerMetricsCollection, error) {
-			time.Sleep(requestLatency)
+			fakeClock.Increment(requestLatency)
 			return containerMetrics, nil
 		}
 	})
@@ -90,7 +92,7 @@ var _ = Describe("ContainerMetrics", func() {
 		Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
 		calledRequestType, calledLatency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
 		Expect(calledRequestType).To(Equal("ContainerMetrics"))
-		Expect(calledLatency).To(BeNumerically("~", requestLatency, 25*time.Millisecond))
+		Expect(calledLatency).To(Equal(requestLatency))
 
 		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
 		calledRequestType, delta = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
@@ -126,3 +128,65 @@ var _ = Describe("ContainerMetrics", func() {
 		})
 	})
 })
+
+var _ = Describe("ContainerMetricsBatch", func() {
+	var batch *rep.ContainerMetricsBatch
+
+	requestBatch := func(fields string) (int, []byte) {
+		request, err := requestGenerator.CreateRequest(rep.ContainerMetricsBatchRoute, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if fields != "" {
+			request.URL.RawQuery = url.Values{"fields": []string{fields}}.Encode()
+		}
+
+		response, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return response.StatusCode, body
+	}
+
+	BeforeEach(func() {
+		batch = &rep.ContainerMetricsBatch{
+			CellID:     "some-cell-id",
+			Containers: []rep.ContainerMetricsSample{{Guid: "container-1", MetricGUID: "metric-guid-1"}},
+		}
+		fakeMetricCollector.MetricsBatchReturns(batch)
+	})
+
+	It("returns the batch of all fields without a field selection", func() {
+		status, body := requestBatch("")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(batch)))
+
+		Expect(fakeMetricCollector.MetricsBatchCallCount()).To(Equal(1))
+		_, fields := fakeMetricCollector.MetricsBatchArgsForCall(0)
+		Expect(fields).To(Equal(rep.AllContainerMetricsFields))
+		Expect(fakeMetricCollector.MetricsCallCount()).To(BeZero())
+	})
+
+	It("returns the batch of the selected fields", func() {
+		status, _ := requestBatch("memory,cpu")
+		Expect(status).To(Equal(http.StatusOK))
+
+		_, fields := fakeMetricCollector.MetricsBatchArgsForCall(0)
+		Expect(fields).To(Equal(rep.ContainerMetricsFields{Memory: true, CPU: true}))
+	})
+
+	It("emits the request metrics", func() {
+		requestBatch("")
+
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("ContainerMetricsBatch"))
+	})
+
+	Context("when a field is unknown", func() {
+		It("returns a bad request", func() {
+			status, _ := requestBatch("memory,network")
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeMetricCollector.MetricsBatchCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+//go:generate counterfeiter . CgroupReader
+type CgroupReader interface {
+	Container(logger lager.Logger, guid string) (rep.ContainerCgroup, error)
+}
+
+//go:generate counterfeiter . LogRateLimitReporter
+type LogRateLimitReporter interface {
+	Statuses(logger lager.Logger) (map[string]rep.LogRateLimitStatus, error)
+}
+
+//go:generate counterfeiter . HealthCheckReporter
+type HealthCheckReporter interface {
+	HealthChecks(logger lager.Logger) (map[string]rep.ContainerHealthCheck, error)
+}
+
+type containersHandler struct {
+	rep           auctioncellrep.StateReporter
+	cgroups       CgroupReader
+	logRateLimits LogRateLimitReporter
+	healthChecks  HealthCheckReporter
+	metrics       helpers.RequestMetrics
+	clock         clock.Clock
+}
+
+// Containers Handler lists the LRP instances and tasks on the cell, optionally
+// filtered by the label selector given in the selector query parameter, along
+// with the cgroups of their containers when cgroups is not nil, their log
+// rate limits when logRateLimits is not nil, and the health checks of the LRP
+// instances when healthChecks is not nil
+func newContainersHandler(rep auctioncellrep.StateReporter, cgroups CgroupReader, logRateLimits LogRateLimitReporter, healthChecks HealthCheckReporter, metrics helpers.RequestMetrics, clock clock.Clock) *containersHandler {
+	return &containersHandler{rep: rep, cgroups: cgroups, logRateLimits: logRateLimits, healthChecks: healthChecks, metrics: metrics, clock: clock}
+}
+
+func (h *containersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "Containers"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("list-containers")
+
+	selector, err := rep.ParseLabelSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		logger.Error("failed-to-parse-selector", err, lager.Data{"selector": r.URL.Query().Get("selector")})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var state rep.CellState
+	state, _, deferErr = h.rep.State(r.Context(), logger)
+	if deferErr != nil {
+		logger.Error("failed-to-fetch-state", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	inventory := rep.SelectContainers(state, selector)
+	if h.cgroups != nil {
+		inventory.Cgroups = h.containerCgroups(logger, inventory)
+	}
+	if h.logRateLimits != nil {
+		inventory.LogRateLimits = h.containerLogRateLimits(logger, inventory)
+	}
+	if h.healthChecks != nil {
+		inventory.HealthChecks = h.containerHealthChecks(logger, inventory)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(inventory)
+	if deferErr != nil {
+		logger.Error("failed-to-encode-containers", deferErr)
+	}
+}
+
+// containerCgroups returns the cgroups of the containers of inventory by
+// container guid, leaving out the containers whose cgroup cannot be read,
+// such as those still being created.
+func (h *containersHandler) containerCgroups(logger lager.Logger, inventory rep.ContainerInventory) map[string]rep.ContainerCgroup {
+	cgroups := map[string]rep.ContainerCgroup{}
+	for _, guid := range inventoryGuids(inventory) {
+		cgroup, err := h.cgroups.Container(logger, guid)
+		if err != nil {
+			logger.Debug("failed-to-read-container-cgroup", lager.Data{"guid": guid, "error": err.Error()})
+			continue
+		}
+		cgroups[guid] = cgroup
+	}
+	return cgroups
+}
+
+// containerLogRateLimits returns the log rate limits of the containers of
+// inventory by container guid, or none when they cannot be read.
+func (h *containersHandler) containerLogRateLimits(logger lager.Logger, inventory rep.ContainerInventory) map[string]rep.LogRateLimitStatus {
+	statuses, err := h.logRateLimits.Statuses(logger)
+	if err != nil {
+		logger.Error("failed-to-read-log-rate-limits", err)
+		return nil
+	}
+
+	limits := map[string]rep.LogRateLimitStatus{}
+	for _, guid := range inventoryGuids(inventory) {
+		if status, ok := statuses[guid]; ok {
+			limits[guid] = status
+		}
+	}
+	return limits
+}
+
+// containerHealthChecks returns the health checks of the LRP instances of
+// inventory by instance guid, or none when they cannot be read.
+func (h *containersHandler) containerHealthChecks(logger lager.Logger, inventory rep.ContainerInventory) map[string]rep.ContainerHealthCheck {
+	healthChecks, err := h.healthChecks.HealthChecks(logger)
+	if err != nil {
+		logger.Error("failed-to-read-health-checks", err)
+		return nil
+	}
+
+	checks := map[string]rep.ContainerHealthCheck{}
+	for i := range inventory.LRPs {
+		if healthCheck, ok := healthChecks[inventory.LRPs[i].InstanceGUID]; ok {
+			checks[inventory.LRPs[i].InstanceGUID] = healthCheck
+		}
+	}
+	return checks
+}
+
+func inventoryGuids(inventory rep.ContainerInventory) []string {
+	guids := make([]string, 0, len(inventory.LRPs)+len(inventory.Tasks))
+	for i := range inventory.LRPs {
+		guids = append(guids, inventory.LRPs[i].InstanceGUID)
+	}
+	for i := range inventory.Tasks {
+		guids = append(guids, inventory.Tasks[i].TaskGuid)
+	}
+	return guids
+}
@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Containers", func() {
+	var (
+		web, worker rep.LRP
+		task        rep.Task
+	)
+
+	listContainers := func(selector string) (int, []byte) {
+		request, err := requestGenerator.CreateRequest(rep.ContainersRoute, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		request.URL.RawQuery = url.Values{"selector": []string{selector}}.Encode()
+
+		response, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return response.StatusCode, body
+	}
+
+	BeforeEach(func() {
+		web = rep.LRP{InstanceGUID: "ig-1", Labels: map[string]string{"team": "payments", "tier": "web"}}
+		worker = rep.LRP{InstanceGUID: "ig-2", Labels: map[string]string{"team": "payments", "tier": "worker"}}
+		task = rep.Task{TaskGuid: "tg-1", Labels: map[string]string{"team": "search"}}
+		fakeLocalRep.StateReturns(rep.CellState{LRPs: []rep.LRP{web, worker}, Tasks: []rep.Task{task}}, true, nil)
+	})
+
+	It("lists all containers without a selector", func() {
+		status, body := listContainers("")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(rep.ContainerInventory{LRPs: []rep.LRP{web, worker}, Tasks: []rep.Task{task}})))
+	})
+
+	It("lists the containers matching the selector", func() {
+		status, body := listContainers("team=payments,tier=web")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(rep.ContainerInventory{LRPs: []rep.LRP{web}, Tasks: []rep.Task{}})))
+	})
+
+	It("emits the request metrics", func() {
+		listContainers("")
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("Containers"))
+	})
+
+	Context("when the cgroups of the containers can be read", func() {
+		BeforeEach(func() {
+			fakeCgroupReader.ContainerStub = func(_ lager.Logger, guid string) (rep.ContainerCgroup, error) {
+				if guid == "ig-1" {
+					return rep.ContainerCgroup{Path: "/garden/ig-1", Controllers: []string{"cpu", "memory"}}, nil
+				}
+				return rep.ContainerCgroup{}, os.ErrNotExist
+			}
+		})
+
+		It("includes the cgroups that could be read", func() {
+			status, body := listContainers("tier=web")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(JSONFor(rep.ContainerInventory{
+				LRPs:    []rep.LRP{web},
+				Tasks:   []rep.Task{},
+				Cgroups: map[string]rep.ContainerCgroup{"ig-1": {Path: "/garden/ig-1", Controllers: []string{"cpu", "memory"}}},
+			})))
+			Expect(fakeCgroupReader.ContainerCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the cell rate limits the logs of containers", func() {
+		BeforeEach(func() {
+			fakeLogRateLimitReporter.StatusesReturns(map[string]rep.LogRateLimitStatus{
+				"ig-1": {BytesPerSecond: 16384, DroppedBytes: 2048},
+				"ig-2": {BytesPerSecond: -1},
+			}, nil)
+		})
+
+		It("includes the log rate limits of the listed containers", func() {
+			status, body := listContainers("tier=web")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(JSONFor(rep.ContainerInventory{
+				LRPs:          []rep.LRP{web},
+				Tasks:         []rep.Task{},
+				LogRateLimits: map[string]rep.LogRateLimitStatus{"ig-1": {BytesPerSecond: 16384, DroppedBytes: 2048}},
+			})))
+		})
+	})
+
+	Context("when the health checks of the LRP instances can be read", func() {
+		var relaxed rep.ContainerHealthCheck
+
+		BeforeEach(func() {
+			relaxed = rep.ContainerHealthCheck{
+				Checks:     []rep.HealthCheck{{Type: rep.HealthCheckTypeHTTP, Port: 8080, Path: "/healthz", TimeoutMs: 5000}},
+				Relaxation: &rep.HealthCheckRelaxation{ProcessGuid: "pg-1", TimeoutMs: 5000, ExpiresAt: 1234},
+			}
+			fakeHealthCheckReporter.HealthChecksReturns(map[string]rep.ContainerHealthCheck{
+				"ig-1": relaxed,
+				"ig-2": {Checks: []rep.HealthCheck{{Type: rep.HealthCheckTypeMonitor}}},
+			}, nil)
+		})
+
+		It("includes the health checks of the listed LRP instances", func() {
+			status, body := listContainers("tier=web")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(JSONFor(rep.ContainerInventory{
+				LRPs:         []rep.LRP{web},
+				Tasks:        []rep.Task{},
+				HealthChecks: map[string]rep.ContainerHealthCheck{"ig-1": relaxed},
+			})))
+		})
+	})
+
+	Context("when the selector is invalid", func() {
+		It("fails with a 400 without fetching the state", func() {
+			status, _ := listContainers("team==payments")
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeLocalRep.StateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the state cannot be fetched", func() {
+		BeforeEach(func() {
+			fakeLocalRep.StateReturns(rep.CellState{}, false, errors.New("boom"))
+		})
+
+		It("fails with a 500", func() {
+			status, _ := listContainers("")
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+)
+
+//go:generate counterfeiter . ConfigReporter
+type ConfigReporter interface {
+	EffectiveConfig() (interface{}, error)
+}
+
+type debugConfigHandler struct {
+	configReporter ConfigReporter
+	metrics        helpers.RequestMetrics
+	clock          clock.Clock
+}
+
+// Debug Config Handler serves the configuration the rep is running with,
+// with secrets redacted
+func newDebugConfigHandler(configReporter ConfigReporter, metrics helpers.RequestMetrics, clock clock.Clock) *debugConfigHandler {
+	return &debugConfigHandler{
+		configReporter: configReporter,
+		metrics:        metrics,
+		clock:          clock,
+	}
+}
+
+func (h *debugConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "DebugConfig"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("debug-config")
+
+	var effectiveConfig interface{}
+	effectiveConfig, deferErr = h.configReporter.EffectiveConfig()
+	if deferErr != nil {
+		logger.Error("failed-to-fetch-effective-config", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(effectiveConfig)
+}
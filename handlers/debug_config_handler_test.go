@@ -0,0 +1,53 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DebugConfigHandler", func() {
+	Context("when the effective config is available", func() {
+		BeforeEach(func() {
+			fakeConfigReporter.EffectiveConfigReturns(map[string]interface{}{
+				"cell_id":  "cell-id",
+				"key_file": "[REDACTED]",
+			}, nil)
+		})
+
+		It("responds with 200 OK and the effective config", func() {
+			status, body := Request(rep.DebugConfigRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`{"cell_id": "cell-id", "key_file": "[REDACTED]"}`))
+		})
+
+		It("emits the request metrics", func() {
+			Request(rep.DebugConfigRoute, nil, nil)
+
+			Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+			calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+			Expect(calledRequestType).To(Equal("DebugConfig"))
+
+			Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when fetching the effective config fails", func() {
+		BeforeEach(func() {
+			fakeConfigReporter.EffectiveConfigReturns(nil, errors.New("boom"))
+		})
+
+		It("responds with 500 Internal Server Error", func() {
+			status, _ := Request(rep.DebugConfigRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+
+		It("emits a failed request metric", func() {
+			Request(rep.DebugConfigRoute, nil, nil)
+			Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+//go:generate counterfeiter . DiskQuotaGrower
+type DiskQuotaGrower interface {
+	GrowDiskQuota(logger lager.Logger, guid string, diskMB int32) error
+}
+
+type growDiskQuotaHandler struct {
+	grower  DiskQuotaGrower
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Grow Disk Quota Handler grows the disk quota of a running container without
+// restarting it, for work that finds it needs more scratch space
+func newGrowDiskQuotaHandler(grower DiskQuotaGrower, metrics helpers.RequestMetrics, clock clock.Clock) *growDiskQuotaHandler {
+	return &growDiskQuotaHandler{
+		grower:  grower,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *growDiskQuotaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "GrowDiskQuota"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	containerGuid := r.FormValue(":container_guid")
+	logger = logger.Session("handling-grow-disk-quota", lager.Data{"container-guid": containerGuid})
+
+	var request rep.DiskQuotaGrowthRequest
+	deferErr = json.NewDecoder(r.Body).Decode(&request)
+	if deferErr != nil {
+		logger.Error("failed-to-unmarshal", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	deferErr = h.grower.GrowDiskQuota(logger, containerGuid, request.DiskMB)
+	switch deferErr.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case rep.InsufficientResourcesError:
+		logger.Info("insufficient-disk", lager.Data{"error": deferErr.Error()})
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	switch deferErr {
+	case rep.ErrInvalidDiskQuotaGrowth:
+		w.WriteHeader(http.StatusBadRequest)
+	case executor.ErrContainerNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case auctioncellrep.ErrContainerNotRunning:
+		w.WriteHeader(http.StatusConflict)
+	case auctioncellrep.ErrDiskQuotaGrowthUnsupported:
+		w.WriteHeader(http.StatusNotImplemented)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	logger.Error("failed-to-grow-disk-quota", deferErr)
+}
@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GrowDiskQuota", func() {
+	var (
+		params  rata.Params
+		request rep.DiskQuotaGrowthRequest
+	)
+
+	BeforeEach(func() {
+		params = rata.Params{"container_guid": "task-guid"}
+		request = rep.DiskQuotaGrowthRequest{DiskMB: 2048}
+	})
+
+	It("grows the disk quota of the container", func() {
+		status, _ := Request(rep.GrowDiskQuotaRoute, params, JSONReaderFor(request))
+		Expect(status).To(Equal(http.StatusNoContent))
+
+		Expect(fakeDiskQuotaGrower.GrowDiskQuotaCallCount()).To(Equal(1))
+		_, guid, diskMB := fakeDiskQuotaGrower.GrowDiskQuotaArgsForCall(0)
+		Expect(guid).To(Equal("task-guid"))
+		Expect(diskMB).To(BeEquivalentTo(2048))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.GrowDiskQuotaRoute, params, JSONReaderFor(request))
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("GrowDiskQuota"))
+	})
+
+	Context("when the request cannot be decoded", func() {
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.GrowDiskQuotaRoute, params, JSONReaderFor("not-a-request"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeDiskQuotaGrower.GrowDiskQuotaCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when growing the disk quota fails", func() {
+		expectStatus := func(err error, status int) {
+			fakeDiskQuotaGrower.GrowDiskQuotaReturns(err)
+			actual, _ := Request(rep.GrowDiskQuotaRoute, params, JSONReaderFor(request))
+			Expect(actual).To(Equal(status), err.Error())
+		}
+
+		It("maps the error to the status", func() {
+			expectStatus(rep.ErrInvalidDiskQuotaGrowth, http.StatusBadRequest)
+			expectStatus(executor.ErrContainerNotFound, http.StatusNotFound)
+			expectStatus(auctioncellrep.ErrContainerNotRunning, http.StatusConflict)
+			expectStatus(rep.InsufficientResourcesError{Problems: map[string]struct{}{"disk": {}}}, http.StatusConflict)
+			expectStatus(auctioncellrep.ErrDiskQuotaGrowthUnsupported, http.StatusNotImplemented)
+			expectStatus(errors.New("boom"), http.StatusInternalServerError)
+		})
+	})
+})
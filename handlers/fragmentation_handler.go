@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . FragmentationAnalyzer
+type FragmentationAnalyzer interface {
+	Fragmentation(logger lager.Logger) (rep.FragmentationReport, error)
+}
+
+type fragmentationHandler struct {
+	analyzer FragmentationAnalyzer
+	metrics  helpers.RequestMetrics
+	clock    clock.Clock
+}
+
+// Fragmentation Handler serves how fragmented the free resources of the cell
+// are, and the relocations that would defragment them
+func newFragmentationHandler(analyzer FragmentationAnalyzer, metrics helpers.RequestMetrics, clock clock.Clock) *fragmentationHandler {
+	return &fragmentationHandler{
+		analyzer: analyzer,
+		metrics:  metrics,
+		clock:    clock,
+	}
+}
+
+func (h *fragmentationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "Fragmentation"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-fragmentation")
+
+	var report rep.FragmentationReport
+	report, deferErr = h.analyzer.Fragmentation(logger)
+	if deferErr != nil {
+		logger.Error("failed-to-analyze-fragmentation", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,47 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fragmentation", func() {
+	It("serves the fragmentation report", func() {
+		report := rep.FragmentationReport{
+			FreeMemoryMB:             1600,
+			LargestPlaceableMemoryMB: 1000,
+			MemoryScore:              0.375,
+			Relocations:              []rep.Relocation{{InstanceGUID: "ig-1", ProcessGuid: "pg-1", Backend: "linux"}},
+		}
+		fakeFragmentationAnalyzer.FragmentationReturns(report, nil)
+
+		status, body := Request(rep.FragmentationRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(report)))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.FragmentationRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("Fragmentation"))
+	})
+
+	Context("when the fragmentation cannot be analyzed", func() {
+		BeforeEach(func() {
+			fakeFragmentationAnalyzer.FragmentationReturns(rep.FragmentationReport{}, errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			status, _ := Request(rep.FragmentationRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+			Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -3,12 +3,17 @@ package handlers
 import (
 	"net/http"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 	"code.cloudfoundry.org/rep"
 	"code.cloudfoundry.org/rep/auctioncellrep"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+	"code.cloudfoundry.org/rep/fairqueue"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/maintenance"
+	"code.cloudfoundry.org/rep/presence"
 	"github.com/tedsuo/rata"
 )
 
@@ -17,41 +22,140 @@ func New(
 	localMetricCollector MetricCollector,
 	executorClient executor.Client,
 	evacuatable evacuation_context.Evacuatable,
+	maintainable maintenance.Maintainable,
+	plannedRestarter presence.PlannedRestarter,
+	infoReporter InfoReporter,
+	performQueue fairqueue.Queue,
+	capacityReserver CapacityReserver,
+	diskQuotaGrower DiskQuotaGrower,
+	containerEvents ContainerEventHistory,
+	cgroups CgroupReader,
+	logRateLimits LogRateLimitReporter,
+	healthChecks HealthCheckReporter,
+	auctionRoutes AuctionRoutesCloser,
+	contacts ContactReporter,
 	requestMetrics helpers.RequestMetrics,
+	clock clock.Clock,
 	logger lager.Logger,
 	secure bool,
 ) rata.Handlers {
 
 	handlers := rata.Handlers{}
 	if secure {
-		stateHandler := newStateHandler(localCellClient, requestMetrics)
-		containerMetricsHandler := newContainerMetricsHandler(localMetricCollector, requestMetrics)
-		performHandler := newPerformHandler(localCellClient, requestMetrics)
-		resetHandler := newResetHandler(localCellClient, requestMetrics)
-		updateLrpHandler := NewUpdateLRPInstanceHandler(executorClient, requestMetrics)
-		stopLrpHandler := NewStopLRPInstanceHandler(executorClient, requestMetrics)
-		cancelTaskHandler := newCancelTaskHandler(executorClient, requestMetrics)
+		stateHandler := newStateHandler(localCellClient, requestMetrics, clock)
+		capacitySummaryHandler := newCapacitySummaryHandler(localCellClient, requestMetrics, clock)
+		containerMetricsHandler := newContainerMetricsHandler(localMetricCollector, requestMetrics, clock)
+		containerMetricsBatchHandler := newContainerMetricsBatchHandler(localMetricCollector, requestMetrics, clock)
+		canPlaceHandler := newCanPlaceHandler(localCellClient, infoReporter, requestMetrics, clock)
+		performHandler := newPerformHandler(localCellClient, infoReporter, performQueue, requestMetrics, clock)
+		infoHandler := newInfoHandler(infoReporter, requestMetrics, clock)
+		containersHandler := newContainersHandler(localCellClient, cgroups, logRateLimits, healthChecks, requestMetrics, clock)
+		containerEventsHandler := newContainerEventsHandler(containerEvents, requestMetrics, clock)
+		resetHandler := newResetHandler(localCellClient, requestMetrics, clock)
+		updateLrpHandler := NewUpdateLRPInstanceHandler(executorClient, requestMetrics, clock)
+		stopLrpHandler := NewStopLRPInstanceHandler(executorClient, requestMetrics, clock)
+		stopLrpsHandler := newStopLRPInstancesHandler(executorClient, requestMetrics, clock)
+		cancelTaskHandler := newCancelTaskHandler(executorClient, requestMetrics, clock)
+		reserveCapacityHandler := newReserveCapacityHandler(capacityReserver, requestMetrics, clock)
+		releaseCapacityHandler := newReleaseCapacityHandler(capacityReserver, requestMetrics, clock)
+		growDiskQuotaHandler := newGrowDiskQuotaHandler(diskQuotaGrower, requestMetrics, clock)
 
-		handlers[rep.StateRoute] = logWrap(stateHandler.ServeHTTP, logger)
+		handlers[rep.StateRoute] = logWrap(closableAuctionRoute(auctionRoutes, stateHandler.ServeHTTP), logger)
+		handlers[rep.CapacitySummaryRoute] = logWrap(closableAuctionRoute(auctionRoutes, capacitySummaryHandler.ServeHTTP), logger)
 		handlers[rep.ContainerMetricsRoute] = logWrap(containerMetricsHandler.ServeHTTP, logger)
-		handlers[rep.PerformRoute] = logWrap(performHandler.ServeHTTP, logger)
+		handlers[rep.ContainerMetricsBatchRoute] = logWrap(containerMetricsBatchHandler.ServeHTTP, logger)
+		handlers[rep.PerformRoute] = logWrap(closableAuctionRoute(auctionRoutes, performHandler.ServeHTTP), logger)
+		handlers[rep.CanPlaceRoute] = logWrap(closableAuctionRoute(auctionRoutes, canPlaceHandler.ServeHTTP), logger)
+		handlers[rep.InfoRoute] = logWrap(infoHandler.ServeHTTP, logger)
+		handlers[rep.ContainersRoute] = logWrap(containersHandler.ServeHTTP, logger)
+		handlers[rep.ContainerEventsRoute] = logWrap(containerEventsHandler.ServeHTTP, logger)
 		handlers[rep.SimResetRoute] = logWrap(resetHandler.ServeHTTP, logger)
 
 		handlers[rep.StopLRPInstanceRoute] = logWrap(stopLrpHandler.ServeHTTP, logger)
+		handlers[rep.StopLRPInstancesRoute] = logWrap(stopLrpsHandler.ServeHTTP, logger)
 		handlers[rep.UpdateLRPInstanceRoute] = logWrap(updateLrpHandler.ServeHTTP, logger)
 		handlers[rep.UpdateLRPInstanceRoute_r0] = logWrap(updateLrpHandler.ServeHTTP, logger)
 		handlers[rep.CancelTaskRoute] = logWrap(cancelTaskHandler.ServeHTTP, logger)
+		handlers[rep.ReserveCapacityRoute] = logWrap(closableAuctionRoute(auctionRoutes, reserveCapacityHandler.ServeHTTP), logger)
+		handlers[rep.ReleaseCapacityRoute] = logWrap(closableAuctionRoute(auctionRoutes, releaseCapacityHandler.ServeHTTP), logger)
+		handlers[rep.GrowDiskQuotaRoute] = logWrap(growDiskQuotaHandler.ServeHTTP, logger)
 	} else {
-		pingHandler := newPingHandler(requestMetrics)
+		pingHandler := newPingHandler(contacts, requestMetrics)
 		evacuationHandler := newEvacuationHandler(evacuatable, requestMetrics)
+		startMaintenanceHandler := newMaintenanceHandler(maintainable, true, requestMetrics)
+		stopMaintenanceHandler := newMaintenanceHandler(maintainable, false, requestMetrics)
+		plannedRestartHandler := newPlannedRestartHandler(plannedRestarter, requestMetrics)
 
 		handlers[rep.PingRoute] = logWrap(pingHandler.ServeHTTP, logger)
 		handlers[rep.EvacuateRoute] = logWrap(evacuationHandler.ServeHTTP, logger)
+		handlers[rep.StartMaintenanceRoute] = logWrap(startMaintenanceHandler.ServeHTTP, logger)
+		handlers[rep.StopMaintenanceRoute] = logWrap(stopMaintenanceHandler.ServeHTTP, logger)
+		handlers[rep.PlannedRestartRoute] = logWrap(plannedRestartHandler.ServeHTTP, logger)
 	}
 
 	return handlers
 }
 
+// NewAdmin returns the handlers for rep.RoutesAdmin
+func NewAdmin(
+	configReporter ConfigReporter,
+	imageCachePruner imagecache.Pruner,
+	placementBlocker PlacementBlocker,
+	placementTagsUpdater PlacementTagsUpdater,
+	fragmentationAnalyzer FragmentationAnalyzer,
+	consistencyReporter ConsistencyReporter,
+	cacheStatsReporter CacheStatsReporter,
+	selfTester SelfTester,
+	capacityReporter CapacityReporter,
+	auctionRoutes AuctionRoutesCloser,
+	healthCheckRelaxer HealthCheckRelaxer,
+	requestMetrics helpers.RequestMetrics,
+	clock clock.Clock,
+	logger lager.Logger,
+) rata.Handlers {
+	debugConfigHandler := newDebugConfigHandler(configReporter, requestMetrics, clock)
+	openAPIHandler := newOpenAPIHandler(requestMetrics, clock)
+	imageCachePruneHandler := newImageCachePruneHandler(imageCachePruner, requestMetrics, clock)
+	blockPlacementHandler := newBlockPlacementHandler(placementBlocker, requestMetrics, clock)
+	unblockPlacementHandler := newUnblockPlacementHandler(placementBlocker, requestMetrics, clock)
+	placementBlocksHandler := newPlacementBlocksHandler(placementBlocker, requestMetrics, clock)
+	placementTagsHandler := newPlacementTagsHandler(placementTagsUpdater, requestMetrics, clock)
+	updatePlacementTagsHandler := newUpdatePlacementTagsHandler(placementTagsUpdater, requestMetrics, clock)
+	fragmentationHandler := newFragmentationHandler(fragmentationAnalyzer, requestMetrics, clock)
+	consistencyHandler := newConsistencyHandler(consistencyReporter, requestMetrics, clock)
+	cacheStatsHandler := newCacheStatsHandler(cacheStatsReporter, requestMetrics, clock)
+	selfTestHandler := newSelfTestHandler(selfTester, requestMetrics, clock)
+	capacityReportHandler := newCapacityReportHandler(capacityReporter, requestMetrics, clock)
+	auctionRoutesHandler := newAuctionRoutesHandler(auctionRoutes, requestMetrics, clock)
+	closeAuctionRoutesHandler := newCloseAuctionRoutesHandler(auctionRoutes, true, requestMetrics, clock)
+	openAuctionRoutesHandler := newCloseAuctionRoutesHandler(auctionRoutes, false, requestMetrics, clock)
+	relaxHealthCheckHandler := newRelaxHealthCheckHandler(healthCheckRelaxer, requestMetrics, clock)
+	restoreHealthCheckHandler := newRestoreHealthCheckHandler(healthCheckRelaxer, requestMetrics, clock)
+	healthCheckRelaxationsHandler := newHealthCheckRelaxationsHandler(healthCheckRelaxer, requestMetrics, clock)
+
+	return rata.Handlers{
+		rep.DebugConfigRoute:            logWrap(debugConfigHandler.ServeHTTP, logger),
+		rep.OpenAPIRoute:                logWrap(openAPIHandler.ServeHTTP, logger),
+		rep.ImageCachePruneRoute:        logWrap(imageCachePruneHandler.ServeHTTP, logger),
+		rep.BlockPlacementRoute:         logWrap(blockPlacementHandler.ServeHTTP, logger),
+		rep.UnblockPlacementRoute:       logWrap(unblockPlacementHandler.ServeHTTP, logger),
+		rep.PlacementBlocksRoute:        logWrap(placementBlocksHandler.ServeHTTP, logger),
+		rep.PlacementTagsRoute:          logWrap(placementTagsHandler.ServeHTTP, logger),
+		rep.UpdatePlacementTagsRoute:    logWrap(updatePlacementTagsHandler.ServeHTTP, logger),
+		rep.FragmentationRoute:          logWrap(fragmentationHandler.ServeHTTP, logger),
+		rep.ConsistencyRoute:            logWrap(consistencyHandler.ServeHTTP, logger),
+		rep.CacheStatsRoute:             logWrap(cacheStatsHandler.ServeHTTP, logger),
+		rep.SelfTestRoute:               logWrap(selfTestHandler.ServeHTTP, logger),
+		rep.CapacityReportRoute:         logWrap(capacityReportHandler.ServeHTTP, logger),
+		rep.AuctionRoutesRoute:          logWrap(auctionRoutesHandler.ServeHTTP, logger),
+		rep.CloseAuctionRoutesRoute:     logWrap(closeAuctionRoutesHandler.ServeHTTP, logger),
+		rep.OpenAuctionRoutesRoute:      logWrap(openAuctionRoutesHandler.ServeHTTP, logger),
+		rep.RelaxHealthCheckRoute:       logWrap(relaxHealthCheckHandler.ServeHTTP, logger),
+		rep.RestoreHealthCheckRoute:     logWrap(restoreHealthCheckHandler.ServeHTTP, logger),
+		rep.HealthCheckRelaxationsRoute: logWrap(healthCheckRelaxationsHandler.ServeHTTP, logger),
+	}
+}
+
 // this isn't being used in the Rep anymore. It is used in tests that run a
 // fake cell. Without this function those tests will have to replicate the code
 // below. Those places are auctioneer fake_cell_test.go and rep's
@@ -61,14 +165,41 @@ func NewLegacy(
 	localMetricCollector MetricCollector,
 	executorClient executor.Client,
 	evacuatable evacuation_context.Evacuatable,
+	maintainable maintenance.Maintainable,
+	plannedRestarter presence.PlannedRestarter,
+	infoReporter InfoReporter,
+	performQueue fairqueue.Queue,
+	capacityReserver CapacityReserver,
+	diskQuotaGrower DiskQuotaGrower,
+	containerEvents ContainerEventHistory,
+	cgroups CgroupReader,
+	logRateLimits LogRateLimitReporter,
+	healthChecks HealthCheckReporter,
+	configReporter ConfigReporter,
+	imageCachePruner imagecache.Pruner,
+	placementBlocker PlacementBlocker,
+	placementTagsUpdater PlacementTagsUpdater,
+	fragmentationAnalyzer FragmentationAnalyzer,
+	consistencyReporter ConsistencyReporter,
+	cacheStatsReporter CacheStatsReporter,
+	selfTester SelfTester,
+	capacityReporter CapacityReporter,
+	auctionRoutes AuctionRoutesCloser,
+	healthCheckRelaxer HealthCheckRelaxer,
+	contacts ContactReporter,
 	requestMetrics helpers.RequestMetrics,
+	clock clock.Clock,
 	logger lager.Logger,
 ) rata.Handlers {
-	insecureHandlers := New(localCellClient, localMetricCollector, executorClient, evacuatable, requestMetrics, logger, false)
-	secureHandlers := New(localCellClient, localMetricCollector, executorClient, evacuatable, requestMetrics, logger, true)
+	insecureHandlers := New(localCellClient, localMetricCollector, executorClient, evacuatable, maintainable, plannedRestarter, infoReporter, performQueue, capacityReserver, diskQuotaGrower, containerEvents, cgroups, logRateLimits, healthChecks, auctionRoutes, contacts, requestMetrics, clock, logger, false)
+	secureHandlers := New(localCellClient, localMetricCollector, executorClient, evacuatable, maintainable, plannedRestarter, infoReporter, performQueue, capacityReserver, diskQuotaGrower, containerEvents, cgroups, logRateLimits, healthChecks, auctionRoutes, contacts, requestMetrics, clock, logger, true)
+	adminHandlers := NewAdmin(configReporter, imageCachePruner, placementBlocker, placementTagsUpdater, fragmentationAnalyzer, consistencyReporter, cacheStatsReporter, selfTester, capacityReporter, auctionRoutes, healthCheckRelaxer, requestMetrics, clock, logger)
 	for name, handler := range secureHandlers {
 		insecureHandlers[name] = handler
 	}
+	for name, handler := range adminHandlers {
+		insecureHandlers[name] = handler
+	}
 	return insecureHandlers
 }
 
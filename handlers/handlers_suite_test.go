@@ -7,16 +7,23 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	executorfakes "code.cloudfoundry.org/executor/fakes"
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/locket/metrics/helpers/helpersfakes"
 	"code.cloudfoundry.org/rep"
 	"code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context/fake_evacuation_context"
+	"code.cloudfoundry.org/rep/fairqueue/fairqueuefakes"
 	"code.cloudfoundry.org/rep/handlers"
 	"code.cloudfoundry.org/rep/handlers/handlersfakes"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+	"code.cloudfoundry.org/rep/maintenance/fake_maintenance"
+	"code.cloudfoundry.org/rep/presence/fake_presence"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/tedsuo/rata"
@@ -28,15 +35,38 @@ func TestAuctionHttpHandlers(t *testing.T) {
 }
 
 var (
-	server              *httptest.Server
-	requestGenerator    *rata.RequestGenerator
-	client              *http.Client
-	fakeLocalRep        *auctioncellrepfakes.FakeAuctionCellClient
-	fakeMetricCollector *handlersfakes.FakeMetricCollector
-	fakeExecutorClient  *executorfakes.FakeClient
-	fakeEvacuatable     *fake_evacuation_context.FakeEvacuatable
-	fakeRequestMetrics  *helpersfakes.FakeRequestMetrics
-	logger              *lagertest.TestLogger
+	server                    *httptest.Server
+	requestGenerator          *rata.RequestGenerator
+	client                    *http.Client
+	fakeLocalRep              *auctioncellrepfakes.FakeAuctionCellClient
+	fakeMetricCollector       *handlersfakes.FakeMetricCollector
+	fakeExecutorClient        *executorfakes.FakeClient
+	fakeEvacuatable           *fake_evacuation_context.FakeEvacuatable
+	fakeMaintainable          *fake_maintenance.FakeMaintainable
+	fakePlannedRestarter      *fake_presence.FakePlannedRestarter
+	fakeInfoReporter          *handlersfakes.FakeInfoReporter
+	fakePerformQueue          *fairqueuefakes.FakeQueue
+	fakeCapacityReserver      *handlersfakes.FakeCapacityReserver
+	fakeDiskQuotaGrower       *handlersfakes.FakeDiskQuotaGrower
+	fakeContainerEventHistory *handlersfakes.FakeContainerEventHistory
+	fakeCgroupReader          *handlersfakes.FakeCgroupReader
+	fakeLogRateLimitReporter  *handlersfakes.FakeLogRateLimitReporter
+	fakeConfigReporter        *handlersfakes.FakeConfigReporter
+	fakeImageCachePruner      *imagecachefakes.FakePruner
+	fakePlacementBlocker      *handlersfakes.FakePlacementBlocker
+	fakePlacementTagsUpdater  *handlersfakes.FakePlacementTagsUpdater
+	fakeConsistencyReporter   *handlersfakes.FakeConsistencyReporter
+	fakeFragmentationAnalyzer *handlersfakes.FakeFragmentationAnalyzer
+	fakeCacheStatsReporter    *handlersfakes.FakeCacheStatsReporter
+	fakeSelfTester            *handlersfakes.FakeSelfTester
+	fakeCapacityReporter      *handlersfakes.FakeCapacityReporter
+	fakeAuctionRoutesCloser   *handlersfakes.FakeAuctionRoutesCloser
+	fakeHealthCheckReporter   *handlersfakes.FakeHealthCheckReporter
+	fakeHealthCheckRelaxer    *handlersfakes.FakeHealthCheckRelaxer
+	fakeContactReporter       *handlersfakes.FakeContactReporter
+	fakeRequestMetrics        *helpersfakes.FakeRequestMetrics
+	fakeClock                 *fakeclock.FakeClock
+	logger                    *lagertest.TestLogger
 )
 
 var _ = BeforeEach(func() {
@@ -46,9 +76,34 @@ var _ = BeforeEach(func() {
 	fakeMetricCollector = new(handlersfakes.FakeMetricCollector)
 	fakeExecutorClient = new(executorfakes.FakeClient)
 	fakeEvacuatable = new(fake_evacuation_context.FakeEvacuatable)
+	fakeMaintainable = new(fake_maintenance.FakeMaintainable)
+	fakePlannedRestarter = new(fake_presence.FakePlannedRestarter)
+	fakeInfoReporter = new(handlersfakes.FakeInfoReporter)
+	fakePerformQueue = new(fairqueuefakes.FakeQueue)
+	fakePerformQueue.AdmitReturns(func() {}, nil)
+	fakeCapacityReserver = new(handlersfakes.FakeCapacityReserver)
+	fakeDiskQuotaGrower = new(handlersfakes.FakeDiskQuotaGrower)
+	fakeContainerEventHistory = new(handlersfakes.FakeContainerEventHistory)
+	fakeCgroupReader = new(handlersfakes.FakeCgroupReader)
+	fakeCgroupReader.ContainerReturns(rep.ContainerCgroup{}, os.ErrNotExist)
+	fakeLogRateLimitReporter = new(handlersfakes.FakeLogRateLimitReporter)
+	fakeConfigReporter = new(handlersfakes.FakeConfigReporter)
+	fakeImageCachePruner = new(imagecachefakes.FakePruner)
+	fakePlacementBlocker = new(handlersfakes.FakePlacementBlocker)
+	fakePlacementTagsUpdater = new(handlersfakes.FakePlacementTagsUpdater)
+	fakeConsistencyReporter = new(handlersfakes.FakeConsistencyReporter)
+	fakeFragmentationAnalyzer = new(handlersfakes.FakeFragmentationAnalyzer)
+	fakeCacheStatsReporter = new(handlersfakes.FakeCacheStatsReporter)
+	fakeSelfTester = new(handlersfakes.FakeSelfTester)
+	fakeCapacityReporter = new(handlersfakes.FakeCapacityReporter)
+	fakeAuctionRoutesCloser = new(handlersfakes.FakeAuctionRoutesCloser)
+	fakeHealthCheckReporter = new(handlersfakes.FakeHealthCheckReporter)
+	fakeHealthCheckRelaxer = new(handlersfakes.FakeHealthCheckRelaxer)
+	fakeContactReporter = new(handlersfakes.FakeContactReporter)
 	fakeRequestMetrics = new(helpersfakes.FakeRequestMetrics)
+	fakeClock = fakeclock.NewFakeClock(time.Now())
 
-	handler, err := rata.NewRouter(rep.Routes, handlers.NewLegacy(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeRequestMetrics, logger))
+	handler, err := rata.NewRouter(rep.Routes, handlers.NewLegacy(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeMaintainable, fakePlannedRestarter, fakeInfoReporter, fakePerformQueue, fakeCapacityReserver, fakeDiskQuotaGrower, fakeContainerEventHistory, fakeCgroupReader, fakeLogRateLimitReporter, fakeHealthCheckReporter, fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, fakeCacheStatsReporter, fakeSelfTester, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeContactReporter, fakeRequestMetrics, fakeClock, logger))
 	Expect(err).NotTo(HaveOccurred())
 
 	server = httptest.NewServer(handler)
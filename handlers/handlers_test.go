@@ -21,7 +21,7 @@ var _ = Describe("New", func() {
 			fakeExecutorClient := new(executorfakes.FakeClient)
 			fakeEvacuatable := new(fake_evacuation_context.FakeEvacuatable)
 			fakeRequestMetrics := new(helpersfakes.FakeRequestMetrics)
-			test_handlers = handlers.New(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeRequestMetrics, logger, false)
+			test_handlers = handlers.New(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeMaintainable, fakePlannedRestarter, fakeInfoReporter, fakePerformQueue, fakeCapacityReserver, fakeDiskQuotaGrower, fakeContainerEventHistory, fakeCgroupReader, fakeLogRateLimitReporter, fakeHealthCheckReporter, fakeAuctionRoutesCloser, fakeContactReporter, fakeRequestMetrics, fakeClock, logger, false)
 		})
 
 		It("has no secure routes", func() {
@@ -42,7 +42,7 @@ var _ = Describe("New", func() {
 			fakeExecutorClient := new(executorfakes.FakeClient)
 			fakeEvacuatable := new(fake_evacuation_context.FakeEvacuatable)
 			fakeRequestMetrics := new(helpersfakes.FakeRequestMetrics)
-			test_handlers = handlers.New(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeRequestMetrics, logger, true)
+			test_handlers = handlers.New(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeMaintainable, fakePlannedRestarter, fakeInfoReporter, fakePerformQueue, fakeCapacityReserver, fakeDiskQuotaGrower, fakeContainerEventHistory, fakeCgroupReader, fakeLogRateLimitReporter, fakeHealthCheckReporter, fakeAuctionRoutesCloser, fakeContactReporter, fakeRequestMetrics, fakeClock, logger, true)
 		})
 
 		It("has all the secure routes", func() {
@@ -56,5 +56,33 @@ var _ = Describe("New", func() {
 				Expect(test_handlers[route.Name]).To(BeNil())
 			}
 		})
+
+		It("has no admin routes", func() {
+			for _, route := range rep.RoutesAdmin {
+				Expect(test_handlers[route.Name]).To(BeNil())
+			}
+		})
+	})
+
+	Context("an admin server", func() {
+		BeforeEach(func() {
+			fakeRequestMetrics := new(helpersfakes.FakeRequestMetrics)
+			test_handlers = handlers.NewAdmin(fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, fakeCacheStatsReporter, fakeSelfTester, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeRequestMetrics, fakeClock, logger)
+		})
+
+		It("has all the admin routes", func() {
+			for _, route := range rep.RoutesAdmin {
+				Expect(test_handlers[route.Name]).NotTo(BeNil())
+			}
+		})
+
+		It("has no unsecure or secure routes", func() {
+			for _, route := range rep.RoutesLocalhostOnly {
+				Expect(test_handlers[route.Name]).To(BeNil())
+			}
+			for _, route := range rep.RoutesNetworkAccessible {
+				Expect(test_handlers[route.Name]).To(BeNil())
+			}
+		})
 	})
 })
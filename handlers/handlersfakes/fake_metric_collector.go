@@ -23,6 +23,18 @@ type FakeMetricCollector struct {
 		result1 *rep.ContainerMetricsCollection
 		result2 error
 	}
+	MetricsBatchStub        func(lager.Logger, rep.ContainerMetricsFields) *rep.ContainerMetricsBatch
+	metricsBatchMutex       sync.RWMutex
+	metricsBatchArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 rep.ContainerMetricsFields
+	}
+	metricsBatchReturns struct {
+		result1 *rep.ContainerMetricsBatch
+	}
+	metricsBatchReturnsOnCall map[int]struct {
+		result1 *rep.ContainerMetricsBatch
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -91,11 +103,75 @@ func (fake *FakeMetricCollector) MetricsReturnsOnCall(i int, result1 *rep.Contai
 	}{result1, result2}
 }
 
+func (fake *FakeMetricCollector) MetricsBatch(arg1 lager.Logger, arg2 rep.ContainerMetricsFields) *rep.ContainerMetricsBatch {
+	fake.metricsBatchMutex.Lock()
+	ret, specificReturn := fake.metricsBatchReturnsOnCall[len(fake.metricsBatchArgsForCall)]
+	fake.metricsBatchArgsForCall = append(fake.metricsBatchArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 rep.ContainerMetricsFields
+	}{arg1, arg2})
+	stub := fake.MetricsBatchStub
+	fakeReturns := fake.metricsBatchReturns
+	fake.recordInvocation("MetricsBatch", []interface{}{arg1, arg2})
+	fake.metricsBatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMetricCollector) MetricsBatchCallCount() int {
+	fake.metricsBatchMutex.RLock()
+	defer fake.metricsBatchMutex.RUnlock()
+	return len(fake.metricsBatchArgsForCall)
+}
+
+func (fake *FakeMetricCollector) MetricsBatchCalls(stub func(lager.Logger, rep.ContainerMetricsFields) *rep.ContainerMetricsBatch) {
+	fake.metricsBatchMutex.Lock()
+	defer fake.metricsBatchMutex.Unlock()
+	fake.MetricsBatchStub = stub
+}
+
+func (fake *FakeMetricCollector) MetricsBatchArgsForCall(i int) (lager.Logger, rep.ContainerMetricsFields) {
+	fake.metricsBatchMutex.RLock()
+	defer fake.metricsBatchMutex.RUnlock()
+	argsForCall := fake.metricsBatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMetricCollector) MetricsBatchReturns(result1 *rep.ContainerMetricsBatch) {
+	fake.metricsBatchMutex.Lock()
+	defer fake.metricsBatchMutex.Unlock()
+	fake.MetricsBatchStub = nil
+	fake.metricsBatchReturns = struct {
+		result1 *rep.ContainerMetricsBatch
+	}{result1}
+}
+
+func (fake *FakeMetricCollector) MetricsBatchReturnsOnCall(i int, result1 *rep.ContainerMetricsBatch) {
+	fake.metricsBatchMutex.Lock()
+	defer fake.metricsBatchMutex.Unlock()
+	fake.MetricsBatchStub = nil
+	if fake.metricsBatchReturnsOnCall == nil {
+		fake.metricsBatchReturnsOnCall = make(map[int]struct {
+			result1 *rep.ContainerMetricsBatch
+		})
+	}
+	fake.metricsBatchReturnsOnCall[i] = struct {
+		result1 *rep.ContainerMetricsBatch
+	}{result1}
+}
+
 func (fake *FakeMetricCollector) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.metricsMutex.RLock()
 	defer fake.metricsMutex.RUnlock()
+	fake.metricsBatchMutex.RLock()
+	defer fake.metricsBatchMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
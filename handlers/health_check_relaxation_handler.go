@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/healthchecks"
+)
+
+//go:generate counterfeiter . HealthCheckRelaxer
+type HealthCheckRelaxer interface {
+	Relax(logger lager.Logger, request rep.HealthCheckRelaxationRequest) (rep.HealthCheckRelaxation, error)
+	Restore(logger lager.Logger, processGuid string, index int32) error
+	Relaxations() []rep.HealthCheckRelaxation
+}
+
+type relaxHealthCheckHandler struct {
+	relaxer HealthCheckRelaxer
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Relax Health Check Handler relaxes the health checks of the containers the
+// cell runs for an LRP instance until the relaxation expires
+func newRelaxHealthCheckHandler(relaxer HealthCheckRelaxer, metrics helpers.RequestMetrics, clock clock.Clock) *relaxHealthCheckHandler {
+	return &relaxHealthCheckHandler{
+		relaxer: relaxer,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *relaxHealthCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "RelaxHealthCheck"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-relax-health-check")
+
+	var request rep.HealthCheckRelaxationRequest
+	deferErr = json.NewDecoder(r.Body).Decode(&request)
+	if deferErr != nil {
+		logger.Error("failed-to-unmarshal", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var relaxation rep.HealthCheckRelaxation
+	relaxation, deferErr = h.relaxer.Relax(logger, request)
+	switch deferErr {
+	case nil:
+	case rep.ErrInvalidHealthCheckRelaxation:
+		logger.Error("invalid-health-check-relaxation", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	default:
+		logger.Error("failed-to-relax-health-check", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(relaxation)
+}
+
+type restoreHealthCheckHandler struct {
+	relaxer HealthCheckRelaxer
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Restore Health Check Handler drops the health check relaxation of an LRP
+// instance before it expires
+func newRestoreHealthCheckHandler(relaxer HealthCheckRelaxer, metrics helpers.RequestMetrics, clock clock.Clock) *restoreHealthCheckHandler {
+	return &restoreHealthCheckHandler{
+		relaxer: relaxer,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *restoreHealthCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "RestoreHealthCheck"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	processGuid := r.FormValue(":process_guid")
+	logger = logger.Session("handling-restore-health-check", lager.Data{"process-guid": processGuid, "index": r.FormValue(":index")})
+
+	var index int64
+	index, deferErr = strconv.ParseInt(r.FormValue(":index"), 10, 32)
+	if deferErr != nil {
+		logger.Error("failed-to-parse-index", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	deferErr = h.relaxer.Restore(logger, processGuid, int32(index))
+	switch deferErr {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case healthchecks.ErrRelaxationNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		logger.Error("failed-to-restore-health-check", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+type healthCheckRelaxationsHandler struct {
+	relaxer HealthCheckRelaxer
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Health Check Relaxations Handler lists the health check relaxations that
+// have not expired
+func newHealthCheckRelaxationsHandler(relaxer HealthCheckRelaxer, metrics helpers.RequestMetrics, clock clock.Clock) *healthCheckRelaxationsHandler {
+	return &healthCheckRelaxationsHandler{
+		relaxer: relaxer,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *healthCheckRelaxationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "HealthCheckRelaxations"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-health-check-relaxations")
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.relaxer.Relaxations())
+	if deferErr != nil {
+		logger.Error("failed-to-encode-health-check-relaxations", deferErr)
+	}
+}
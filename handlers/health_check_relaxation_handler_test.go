@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HealthCheckRelaxation", func() {
+	relaxation := rep.HealthCheckRelaxation{ProcessGuid: "pg-1", Index: 2, Suspend: true, ExpiresAt: 1700000000}
+
+	Describe("relaxing the health checks of an instance", func() {
+		request := rep.HealthCheckRelaxationRequest{ProcessGuid: "pg-1", Index: 2, Suspend: true, TTLSeconds: 600}
+
+		It("relaxes them and returns the relaxation", func() {
+			fakeHealthCheckRelaxer.RelaxReturns(relaxation, nil)
+
+			status, body := Request(rep.RelaxHealthCheckRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusCreated))
+			Expect(body).To(MatchJSON(JSONFor(relaxation)))
+
+			Expect(fakeHealthCheckRelaxer.RelaxCallCount()).To(Equal(1))
+			_, relaxed := fakeHealthCheckRelaxer.RelaxArgsForCall(0)
+			Expect(relaxed).To(Equal(request))
+		})
+
+		It("fails with a 400 when the relaxation is invalid", func() {
+			fakeHealthCheckRelaxer.RelaxReturns(rep.HealthCheckRelaxation{}, rep.ErrInvalidHealthCheckRelaxation)
+
+			status, _ := Request(rep.RelaxHealthCheckRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+
+		It("fails with a 400 when the request cannot be unmarshalled", func() {
+			status, _ := Request(rep.RelaxHealthCheckRoute, nil, JSONReaderFor("nope"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeHealthCheckRelaxer.RelaxCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("restoring the health checks of an instance", func() {
+		It("drops the relaxation", func() {
+			status, _ := Request(rep.RestoreHealthCheckRoute, rata.Params{"process_guid": "pg-1", "index": "2"}, nil)
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			Expect(fakeHealthCheckRelaxer.RestoreCallCount()).To(Equal(1))
+			_, processGuid, index := fakeHealthCheckRelaxer.RestoreArgsForCall(0)
+			Expect(processGuid).To(Equal("pg-1"))
+			Expect(index).To(BeEquivalentTo(2))
+		})
+
+		It("fails with a 404 when the instance has no relaxation", func() {
+			fakeHealthCheckRelaxer.RestoreReturns(healthchecks.ErrRelaxationNotFound)
+
+			status, _ := Request(rep.RestoreHealthCheckRoute, rata.Params{"process_guid": "pg-1", "index": "2"}, nil)
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+
+		It("fails with a 400 when the index is not a number", func() {
+			status, _ := Request(rep.RestoreHealthCheckRoute, rata.Params{"process_guid": "pg-1", "index": "two"}, nil)
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeHealthCheckRelaxer.RestoreCallCount()).To(Equal(0))
+		})
+
+		It("fails with a 500 when restoring fails", func() {
+			fakeHealthCheckRelaxer.RestoreReturns(errors.New("boom"))
+
+			status, _ := Request(rep.RestoreHealthCheckRoute, rata.Params{"process_guid": "pg-1", "index": "2"}, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	It("lists the relaxations", func() {
+		fakeHealthCheckRelaxer.RelaxationsReturns([]rep.HealthCheckRelaxation{relaxation})
+
+		status, body := Request(rep.HealthCheckRelaxationsRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor([]rep.HealthCheckRelaxation{relaxation})))
+	})
+})
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep/imagecache"
+)
+
+type imageCachePruneHandler struct {
+	pruner  imagecache.Pruner
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Image Cache Prune Handler evicts the cached image layers the pruning
+// policy allows, and responds with what it evicted
+func newImageCachePruneHandler(pruner imagecache.Pruner, metrics helpers.RequestMetrics, clock clock.Clock) *imageCachePruneHandler {
+	return &imageCachePruneHandler{
+		pruner:  pruner,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *imageCachePruneHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "ImageCachePrune"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("image-cache-prune")
+
+	if h.pruner == nil {
+		logger.Info("image-cache-pruning-not-configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	var result imagecache.PruneResult
+	result, deferErr = h.pruner.Prune(logger)
+
+	w.Header().Set("Content-Type", "application/json")
+	if deferErr != nil {
+		logger.Error("failed-to-prune-image-cache", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,87 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+	"code.cloudfoundry.org/rep/imagecache"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/rata"
+)
+
+var _ = Describe("ImageCachePruneHandler", func() {
+	var result imagecache.PruneResult
+
+	BeforeEach(func() {
+		result = imagecache.PruneResult{
+			EvictedVolumes:  []imagecache.EvictedVolume{{Provider: "docker", ID: "layer-1", SizeBytes: 2097152}},
+			ReclaimedDiskMB: 2,
+		}
+	})
+
+	Context("when pruning succeeds", func() {
+		BeforeEach(func() {
+			fakeImageCachePruner.PruneReturns(result, nil)
+		})
+
+		It("responds with 200 OK and the evicted volumes", func() {
+			status, body := Request(rep.ImageCachePruneRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(fakeImageCachePruner.PruneCallCount()).To(Equal(1))
+
+			var response imagecache.PruneResult
+			Expect(json.Unmarshal(body, &response)).To(Succeed())
+			Expect(response).To(Equal(result))
+		})
+
+		It("emits the request metrics", func() {
+			Request(rep.ImageCachePruneRoute, nil, nil)
+
+			Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+			calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+			Expect(calledRequestType).To(Equal("ImageCachePrune"))
+
+			Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when pruning fails", func() {
+		BeforeEach(func() {
+			fakeImageCachePruner.PruneReturns(result, errors.New("boom"))
+		})
+
+		It("responds with 500 Internal Server Error and what was evicted", func() {
+			status, body := Request(rep.ImageCachePruneRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+
+			var response imagecache.PruneResult
+			Expect(json.Unmarshal(body, &response)).To(Succeed())
+			Expect(response).To(Equal(result))
+		})
+
+		It("emits a failed request metric", func() {
+			Request(rep.ImageCachePruneRoute, nil, nil)
+			Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when image cache pruning is not configured", func() {
+		It("responds with 501 Not Implemented", func() {
+			adminHandlers := handlers.NewAdmin(fakeConfigReporter, nil, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, fakeCacheStatsReporter, fakeSelfTester, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeRequestMetrics, fakeClock, logger)
+			router, err := rata.NewRouter(rep.RoutesAdmin, adminHandlers)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := rata.NewRequestGenerator("", rep.RoutesAdmin).CreateRequest(rep.ImageCachePruneRoute, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
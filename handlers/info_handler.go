@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . InfoReporter
+type InfoReporter interface {
+	Info() rep.Info
+}
+
+// InfoReporterFunc adapts a function to the InfoReporter interface.
+type InfoReporterFunc func() rep.Info
+
+func (f InfoReporterFunc) Info() rep.Info {
+	return f()
+}
+
+type infoHandler struct {
+	infoReporter InfoReporter
+	metrics      helpers.RequestMetrics
+	clock        clock.Clock
+}
+
+// Info Handler serves the version of the rep, the API versions and feature
+// flags it supports and the limits it enforces on requests
+func newInfoHandler(infoReporter InfoReporter, metrics helpers.RequestMetrics, clock clock.Clock) *infoHandler {
+	return &infoHandler{
+		infoReporter: infoReporter,
+		metrics:      metrics,
+		clock:        clock,
+	}
+}
+
+func (h *infoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "Info"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.infoReporter.Info())
+	if deferErr != nil {
+		logger.Session("info").Error("failed-to-encode-info", deferErr)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Info", func() {
+	var info rep.Info
+
+	BeforeEach(func() {
+		info = rep.Info{
+			Version:      "1.2.3",
+			APIVersions:  []string{"v1", "v2"},
+			FeatureFlags: []string{"local_restart"},
+			Limits:       rep.Limits{MaxRequestBodyBytes: 1024, MaxWorkBatchSize: 10},
+		}
+		fakeInfoReporter.InfoReturns(info)
+	})
+
+	It("returns the info of the rep", func() {
+		status, body := Request(rep.InfoRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(info)))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.InfoRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("Info"))
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("Info"))
+	})
+})
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep/maintenance"
+)
+
+type maintenanceHandler struct {
+	maintainable maintenance.Maintainable
+	enable       bool
+	metrics      helpers.RequestMetrics
+}
+
+// Maintenance Handler serves the routes an operator uses to put the cell in
+// and out of maintenance mode
+func newMaintenanceHandler(maintainable maintenance.Maintainable, enable bool, requestMetrics helpers.RequestMetrics) *maintenanceHandler {
+	return &maintenanceHandler{
+		maintainable: maintainable,
+		enable:       enable,
+		metrics:      requestMetrics,
+	}
+}
+
+func (h *maintenanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	logger = logger.Session("handling-maintenance", lager.Data{"enable": h.enable})
+
+	if h.enable {
+		h.maintainable.StartMaintenance()
+	} else {
+		h.maintainable.StopMaintenance()
+	}
+
+	logger.Info("updated")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package handlers_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaintenanceHandler", func() {
+	Context("when starting maintenance", func() {
+		It("puts the cell in maintenance", func() {
+			Request(rep.StartMaintenanceRoute, nil, nil)
+			Expect(fakeMaintainable.StartMaintenanceCallCount()).To(Equal(1))
+			Expect(fakeMaintainable.StopMaintenanceCallCount()).To(Equal(0))
+		})
+
+		It("responds with 204 NO CONTENT", func() {
+			status, _ := Request(rep.StartMaintenanceRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	Context("when stopping maintenance", func() {
+		It("takes the cell out of maintenance", func() {
+			Request(rep.StopMaintenanceRoute, nil, nil)
+			Expect(fakeMaintainable.StopMaintenanceCallCount()).To(Equal(1))
+			Expect(fakeMaintainable.StartMaintenanceCallCount()).To(Equal(0))
+		})
+
+		It("responds with 204 NO CONTENT", func() {
+			status, _ := Request(rep.StopMaintenanceRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+})
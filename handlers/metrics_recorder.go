@@ -3,6 +3,7 @@ package handlers
 import (
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 )
 
@@ -11,9 +12,9 @@ func startMetrics(metrics helpers.RequestMetrics, requestType string) {
 	metrics.IncrementRequestsInFlightCounter(requestType, 1)
 }
 
-func stopMetrics(metrics helpers.RequestMetrics, requestType string, start time.Time, deferErr *error) {
+func stopMetrics(metrics helpers.RequestMetrics, clock clock.Clock, requestType string, start time.Time, deferErr *error) {
 	metrics.DecrementRequestsInFlightCounter(requestType, 1)
-	metrics.UpdateLatency(requestType, time.Since(start))
+	metrics.UpdateLatency(requestType, clock.Since(start))
 
 	if deferErr == nil || *deferErr == nil {
 		metrics.IncrementRequestsSucceededCounter(requestType, 1)
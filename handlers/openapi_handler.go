@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep/openapi"
+)
+
+type openAPIHandler struct {
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+
+	once     sync.Once
+	document []byte
+	err      error
+}
+
+// OpenAPI Handler serves the OpenAPI document describing the routes of the
+// rep. The document is generated on the first request.
+func newOpenAPIHandler(metrics helpers.RequestMetrics, clock clock.Clock) *openAPIHandler {
+	return &openAPIHandler{
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *openAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "OpenAPI"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("openapi")
+
+	h.once.Do(func() {
+		var document *openapi.Document
+		document, h.err = openapi.RepDocument()
+		if h.err == nil {
+			h.document, h.err = json.Marshal(document)
+		}
+	})
+
+	deferErr = h.err
+	if deferErr != nil {
+		logger.Error("failed-to-generate-openapi-document", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.document)
+}
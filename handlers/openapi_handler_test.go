@@ -0,0 +1,32 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPIHandler", func() {
+	It("responds with 200 OK and the OpenAPI document", func() {
+		status, body := Request(rep.OpenAPIRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+
+		var document map[string]interface{}
+		Expect(json.Unmarshal(body, &document)).To(Succeed())
+		Expect(document["openapi"]).To(Equal("3.0.3"))
+		Expect(document["paths"]).To(HaveKey("/state"))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.OpenAPIRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("OpenAPI"))
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+	})
+})
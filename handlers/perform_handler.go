@@ -2,43 +2,80 @@ package handlers
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
-	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 	"code.cloudfoundry.org/rep"
 	"code.cloudfoundry.org/rep/auctioncellrep"
+	"code.cloudfoundry.org/rep/fairqueue"
 )
 
 type perform struct {
-	rep     auctioncellrep.AuctionCellClient
-	metrics helpers.RequestMetrics
+	rep          auctioncellrep.WorkPerformer
+	infoReporter InfoReporter
+	queue        fairqueue.Queue
+	metrics      helpers.RequestMetrics
+	clock        clock.Clock
 }
 
-func newPerformHandler(rep auctioncellrep.AuctionCellClient, metrics helpers.RequestMetrics) *perform {
-	return &perform{rep: rep, metrics: metrics}
+// Perform Handler allocates the work on the cell. When queue is not nil, the
+// work of competing callers is admitted through it.
+func newPerformHandler(rep auctioncellrep.WorkPerformer, infoReporter InfoReporter, queue fairqueue.Queue, metrics helpers.RequestMetrics, clock clock.Clock) *perform {
+	return &perform{rep: rep, infoReporter: infoReporter, queue: queue, metrics: metrics, clock: clock}
 }
 
 func (h *perform) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
 	var deferErr error
 
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "Perform"
 	startMetrics(h.metrics, requestType)
-	defer stopMetrics(h.metrics, requestType, start, &deferErr)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
 
 	logger = logger.Session("auction-perform-work")
+	limits := h.infoReporter.Info().Limits
+	if limits.MaxRequestBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limits.MaxRequestBodyBytes)
+	}
+
 	var work rep.Work
-	deferErr = json.NewDecoder(r.Body).Decode(&work)
+	work, deferErr = rep.DecodeWork(r.Body, limits.MaxWorkBatchSize)
+	if tooLarge, ok := deferErr.(rep.WorkBatchTooLargeError); ok {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		logger.Error("work-batch-too-large", deferErr)
+		json.NewEncoder(w).Encode(tooLarge)
+		return
+	}
 	if deferErr != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		logger.Error("failed-to-unmarshal", deferErr)
 		return
 	}
 
+	attributeDirectedPlacements(&work, certificateIdentity(r))
+
+	if h.queue != nil {
+		var release func()
+		release, deferErr = h.queue.Admit(r.Context(), logger, callerIdentity(r))
+		if deferErr != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			logger.Error("failed-to-admit-work", deferErr)
+			return
+		}
+		defer release()
+	}
+
+	ctx := r.Context()
+	if trace := rep.TraceContextFromHeader(r.Header); trace != nil {
+		ctx = rep.WithTraceContext(ctx, trace)
+		logger = logger.WithData(lager.Data{"trace-id": trace.TraceID()})
+	}
+
 	var failedWork rep.Work
-	failedWork, deferErr = h.rep.Perform(logger, work)
+	failedWork, deferErr = h.rep.Perform(ctx, logger, work)
 	if deferErr != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		logger.Error("failed-to-perform-work", deferErr)
@@ -47,3 +84,43 @@ func (h *perform) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager
 
 	json.NewEncoder(w).Encode(failedWork)
 }
+
+// attributeDirectedPlacements records requestedBy as who requested each
+// directed placement of work, overriding whatever the caller claimed.
+func attributeDirectedPlacements(work *rep.Work, requestedBy string) {
+	for i := range work.LRPs {
+		if work.LRPs[i].Directed != nil {
+			work.LRPs[i].Directed.RequestedBy = requestedBy
+		}
+	}
+	for i := range work.Tasks {
+		if work.Tasks[i].Directed != nil {
+			work.Tasks[i].Directed.RequestedBy = requestedBy
+		}
+	}
+}
+
+// certificateIdentity is the common name of the client certificate of a
+// request, or empty when it was made without one.
+func certificateIdentity(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return ""
+}
+
+// callerIdentity identifies the caller of a request by the host it connects
+// from, qualified by the common name of its client certificate. Auctioneers
+// usually share a certificate, so the common name alone does not tell them
+// apart.
+func callerIdentity(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if identity := certificateIdentity(r); identity != "" {
+		return identity + "@" + host
+	}
+	return host
+}
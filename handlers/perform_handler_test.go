@@ -2,12 +2,18 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/fairqueue"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -46,8 +52,8 @@ var _ = Describe("Perform", func() {
 
 		Context("and no perform error", func() {
 			BeforeEach(func() {
-				fakeLocalRep.PerformStub = func(logger lager.Logger, work rep.Work) (rep.Work, error) {
-					time.Sleep(requestLatency)
+				fakeLocalRep.PerformStub = func(ctx context.Context, logger lager.Logger, work rep.Work) (rep.Work, error) {
+					fakeClock.Increment(requestLatency)
 					return failedWork, nil
 				}
 			})
@@ -58,7 +64,7 @@ var _ = Describe("Perform", func() {
 				Expect(body).To(MatchJSON(JSONFor(failedWork)))
 
 				Expect(fakeLocalRep.PerformCallCount()).To(Equal(1))
-				_, actualWork := fakeLocalRep.PerformArgsForCall(0)
+				_, _, actualWork := fakeLocalRep.PerformArgsForCall(0)
 				Expect(actualWork).To(Equal(requestedWork))
 			})
 
@@ -83,7 +89,7 @@ var _ = Describe("Perform", func() {
 				Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
 				calledRequestType, calledLatency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
 				Expect(calledRequestType).To(Equal("Perform"))
-				Expect(calledLatency).To(BeNumerically("~", requestLatency, 2*requestLatency))
+				Expect(calledLatency).To(Equal(requestLatency))
 
 				Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
 				calledRequestType, delta = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
@@ -105,7 +111,7 @@ var _ = Describe("Perform", func() {
 				Expect(body).To(BeEmpty())
 
 				Expect(fakeLocalRep.PerformCallCount()).To(Equal(1))
-				_, actualWork := fakeLocalRep.PerformArgsForCall(0)
+				_, _, actualWork := fakeLocalRep.PerformArgsForCall(0)
 				Expect(actualWork).To(Equal(requestedWork))
 			})
 
@@ -122,6 +128,163 @@ var _ = Describe("Perform", func() {
 		})
 	})
 
+	Context("with limits", func() {
+		var requestedWork rep.Work
+
+		BeforeEach(func() {
+			requestedWork = rep.Work{
+				Tasks: []rep.Task{
+					rep.NewTask("a", "domain", rep.NewResource(128, 256, 256), rep.NewPlacementConstraint("some-rootfs", nil, nil)),
+					rep.NewTask("b", "domain", rep.NewResource(128, 256, 256), rep.NewPlacementConstraint("some-rootfs", nil, nil)),
+				},
+			}
+		})
+
+		Context("when the work exceeds the max batch size", func() {
+			BeforeEach(func() {
+				fakeInfoReporter.InfoReturns(rep.Info{Limits: rep.Limits{MaxWorkBatchSize: 1}})
+			})
+
+			It("rejects the work with the max batch size", func() {
+				status, body := Request(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+				Expect(status).To(Equal(http.StatusRequestEntityTooLarge))
+				Expect(body).To(MatchJSON(JSONFor(rep.NewWorkBatchTooLargeError(1))))
+				Expect(fakeLocalRep.PerformCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the body exceeds the max body size", func() {
+			BeforeEach(func() {
+				fakeInfoReporter.InfoReturns(rep.Info{Limits: rep.Limits{MaxRequestBodyBytes: 16}})
+			})
+
+			It("rejects the work", func() {
+				status, _ := Request(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+				Expect(status).To(Equal(http.StatusBadRequest))
+				Expect(fakeLocalRep.PerformCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the work is within the limits", func() {
+			BeforeEach(func() {
+				fakeInfoReporter.InfoReturns(rep.Info{Limits: rep.Limits{MaxWorkBatchSize: 2, MaxRequestBodyBytes: 1024 * 1024}})
+			})
+
+			It("performs the work", func() {
+				status, _ := Request(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+				Expect(status).To(Equal(http.StatusOK))
+				Expect(fakeLocalRep.PerformCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("with a fair queue", func() {
+		var requestedWork rep.Work
+
+		BeforeEach(func() {
+			requestedWork = rep.Work{
+				Tasks: []rep.Task{
+					rep.NewTask("a", "domain", rep.NewResource(128, 256, 256), rep.NewPlacementConstraint("some-rootfs", nil, nil)),
+				},
+			}
+		})
+
+		It("admits the work of the caller through the queue", func() {
+			released := false
+			fakePerformQueue.AdmitReturns(func() { released = true }, nil)
+
+			status, _ := Request(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(fakePerformQueue.AdmitCallCount()).To(Equal(1))
+			_, _, caller := fakePerformQueue.AdmitArgsForCall(0)
+			Expect(caller).To(Equal("127.0.0.1"))
+			Expect(fakeLocalRep.PerformCallCount()).To(Equal(1))
+			Expect(released).To(BeTrue())
+		})
+
+		It("tells apart callers sharing a client certificate by their host", func() {
+			request, err := requestGenerator.CreateRequest(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+			Expect(err).NotTo(HaveOccurred())
+			request.RemoteAddr = "10.0.0.1:4567"
+			request.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "auctioneer"}}},
+			}
+
+			recorder := httptest.NewRecorder()
+			server.Config.Handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			_, _, caller := fakePerformQueue.AdmitArgsForCall(0)
+			Expect(caller).To(Equal("auctioneer@10.0.0.1"))
+		})
+
+		Context("when the work is not admitted", func() {
+			BeforeEach(func() {
+				fakePerformQueue.AdmitReturns(nil, fairqueue.ErrQueueFull)
+			})
+
+			It("rejects the work", func() {
+				status, _ := Request(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+				Expect(status).To(Equal(http.StatusServiceUnavailable))
+				Expect(fakeLocalRep.PerformCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("with directed work", func() {
+		var requestedWork rep.Work
+
+		BeforeEach(func() {
+			task := rep.NewTask("a", "domain", rep.NewResource(128, 256, 256), rep.NewPlacementConstraint("some-rootfs", nil, nil))
+			task.Directed = &rep.DirectedPlacement{CellID: "cell-id", RequestedBy: "someone-else", Reason: "pin"}
+			requestedWork = rep.Work{Tasks: []rep.Task{task}}
+		})
+
+		It("attributes the placements to the client certificate of the request", func() {
+			request, err := requestGenerator.CreateRequest(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+			Expect(err).NotTo(HaveOccurred())
+			request.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "ops-pinning-tool"}}},
+			}
+
+			recorder := httptest.NewRecorder()
+			server.Config.Handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			_, _, actualWork := fakeLocalRep.PerformArgsForCall(0)
+			Expect(actualWork.Tasks[0].Directed).To(Equal(&rep.DirectedPlacement{CellID: "cell-id", RequestedBy: "ops-pinning-tool", Reason: "pin"}))
+		})
+
+		It("leaves the placements unattributed without a client certificate", func() {
+			status, _ := Request(rep.PerformRoute, nil, JSONReaderFor(requestedWork))
+			Expect(status).To(Equal(http.StatusOK))
+
+			_, _, actualWork := fakeLocalRep.PerformArgsForCall(0)
+			Expect(actualWork.Tasks[0].Directed.RequestedBy).To(BeEmpty())
+		})
+	})
+
+	Context("with a trace context", func() {
+		const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+		It("performs the work with the trace context of the request", func() {
+			request, err := requestGenerator.CreateRequest(rep.PerformRoute, nil, JSONReaderFor(rep.Work{}))
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Set(rep.TraceParentHeader, traceParent)
+			request.Header.Set(rep.BaggageHeader, "auction=1")
+
+			response, err := client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(fakeLocalRep.PerformCallCount()).To(Equal(1))
+			ctx, _, _ := fakeLocalRep.PerformArgsForCall(0)
+			Expect(rep.TraceContextFromContext(ctx)).To(Equal(&rep.TraceContext{TraceParent: traceParent, Baggage: "auction=1"}))
+		})
+	})
+
 	Context("with invalid JSON", func() {
 		It("fails", func() {
 			status, body := Request(rep.PerformRoute, nil, bytes.NewBufferString("∆"))
@@ -1,23 +1,44 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
 )
 
+//go:generate counterfeiter . ContactReporter
+type ContactReporter interface {
+	LastContacts() rep.LastContacts
+}
+
 type pingHandler struct {
-	metrics helpers.RequestMetrics
+	contacts ContactReporter
+	metrics  helpers.RequestMetrics
 }
 
-// Ping Handler serves a route that is called by the rep ctl script
-func newPingHandler(metrics helpers.RequestMetrics) *pingHandler {
+// Ping Handler serves a route that is called by the rep ctl script. It
+// responds with when the rep last reached the BBS and Garden, so that a
+// partitioned cell can be told apart from a ready one.
+func newPingHandler(contacts ContactReporter, metrics helpers.RequestMetrics) *pingHandler {
 	return &pingHandler{
-		metrics: metrics,
+		contacts: contacts,
+		metrics:  metrics,
 	}
 }
 
 func (h *pingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	if h.contacts == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(h.contacts.LastContacts())
+	if err != nil {
+		logger.Session("ping").Error("failed-to-encode-last-contacts", err)
+	}
 }
@@ -13,4 +13,11 @@ var _ = Describe("PingHandler", func() {
 		status, _ := Request(rep.PingRoute, nil, nil)
 		Expect(status).To(Equal(http.StatusOK))
 	})
+
+	It("responds with when the rep last reached the BBS and Garden", func() {
+		fakeContactReporter.LastContactsReturns(rep.LastContacts{LastBBSContact: 1000, LastGardenContact: 2000})
+
+		_, body := Request(rep.PingRoute, nil, nil)
+		Expect(body).To(MatchJSON(`{"last_bbs_contact": 1000, "last_garden_contact": 2000}`))
+	})
 })
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+//go:generate counterfeiter . PlacementBlocker
+type PlacementBlocker interface {
+	BlockPlacement(logger lager.Logger, request rep.PlacementBlockRequest) (rep.PlacementBlock, error)
+	UnblockPlacement(logger lager.Logger, blockID string) error
+	PlacementBlocks(logger lager.Logger) []rep.PlacementBlock
+}
+
+type blockPlacementHandler struct {
+	blocker PlacementBlocker
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Block Placement Handler keeps the work of a process guid or a domain off
+// the cell until the block expires
+func newBlockPlacementHandler(blocker PlacementBlocker, metrics helpers.RequestMetrics, clock clock.Clock) *blockPlacementHandler {
+	return &blockPlacementHandler{
+		blocker: blocker,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *blockPlacementHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "BlockPlacement"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-block-placement")
+
+	var request rep.PlacementBlockRequest
+	deferErr = json.NewDecoder(r.Body).Decode(&request)
+	if deferErr != nil {
+		logger.Error("failed-to-unmarshal", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var block rep.PlacementBlock
+	block, deferErr = h.blocker.BlockPlacement(logger, request)
+	switch deferErr {
+	case nil:
+	case rep.ErrInvalidPlacementBlock, rep.ErrDeadlinePassed:
+		logger.Error("invalid-placement-block", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	default:
+		logger.Error("failed-to-block-placement", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(block)
+}
+
+type unblockPlacementHandler struct {
+	blocker PlacementBlocker
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Unblock Placement Handler lifts a placement block before it expires
+func newUnblockPlacementHandler(blocker PlacementBlocker, metrics helpers.RequestMetrics, clock clock.Clock) *unblockPlacementHandler {
+	return &unblockPlacementHandler{
+		blocker: blocker,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *unblockPlacementHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "UnblockPlacement"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	blockID := r.FormValue(":block_id")
+	logger = logger.Session("handling-unblock-placement", lager.Data{"block-id": blockID})
+
+	deferErr = h.blocker.UnblockPlacement(logger, blockID)
+	switch deferErr {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case auctioncellrep.ErrPlacementBlockNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		logger.Error("failed-to-unblock-placement", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+type placementBlocksHandler struct {
+	blocker PlacementBlocker
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Placement Blocks Handler lists the placement blocks that have not expired
+func newPlacementBlocksHandler(blocker PlacementBlocker, metrics helpers.RequestMetrics, clock clock.Clock) *placementBlocksHandler {
+	return &placementBlocksHandler{
+		blocker: blocker,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *placementBlocksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "PlacementBlocks"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-placement-blocks")
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.blocker.PlacementBlocks(logger))
+	if deferErr != nil {
+		logger.Error("failed-to-encode-placement-blocks", deferErr)
+	}
+}
@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BlockPlacement", func() {
+	var request rep.PlacementBlockRequest
+
+	BeforeEach(func() {
+		request = rep.PlacementBlockRequest{ProcessGuid: "noisy-pg", Reason: "noisy neighbour", TTLSeconds: 3600}
+	})
+
+	It("blocks the placement and returns the block", func() {
+		block := rep.PlacementBlock{ID: "block-id", ProcessGuid: "noisy-pg", Reason: "noisy neighbour", ExpiresAt: 1234}
+		fakePlacementBlocker.BlockPlacementReturns(block, nil)
+
+		status, body := Request(rep.BlockPlacementRoute, nil, JSONReaderFor(request))
+		Expect(status).To(Equal(http.StatusCreated))
+		Expect(body).To(MatchJSON(JSONFor(block)))
+
+		Expect(fakePlacementBlocker.BlockPlacementCallCount()).To(Equal(1))
+		_, actualRequest := fakePlacementBlocker.BlockPlacementArgsForCall(0)
+		Expect(actualRequest).To(Equal(request))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.BlockPlacementRoute, nil, JSONReaderFor(request))
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("BlockPlacement"))
+	})
+
+	Context("when the request cannot be decoded", func() {
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.BlockPlacementRoute, nil, JSONReaderFor("not-a-request"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakePlacementBlocker.BlockPlacementCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the block is invalid", func() {
+		BeforeEach(func() {
+			fakePlacementBlocker.BlockPlacementReturns(rep.PlacementBlock{}, rep.ErrInvalidPlacementBlock)
+		})
+
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.BlockPlacementRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when blocking the placement fails", func() {
+		BeforeEach(func() {
+			fakePlacementBlocker.BlockPlacementReturns(rep.PlacementBlock{}, errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			status, _ := Request(rep.BlockPlacementRoute, nil, JSONReaderFor(request))
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
+
+var _ = Describe("UnblockPlacement", func() {
+	It("lifts the block", func() {
+		status, _ := Request(rep.UnblockPlacementRoute, rata.Params{"block_id": "block-id"}, nil)
+		Expect(status).To(Equal(http.StatusNoContent))
+
+		Expect(fakePlacementBlocker.UnblockPlacementCallCount()).To(Equal(1))
+		_, blockID := fakePlacementBlocker.UnblockPlacementArgsForCall(0)
+		Expect(blockID).To(Equal("block-id"))
+	})
+
+	Context("when the block does not exist", func() {
+		BeforeEach(func() {
+			fakePlacementBlocker.UnblockPlacementReturns(auctioncellrep.ErrPlacementBlockNotFound)
+		})
+
+		It("responds with not found", func() {
+			status, _ := Request(rep.UnblockPlacementRoute, rata.Params{"block_id": "block-id"}, nil)
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when lifting the block fails", func() {
+		BeforeEach(func() {
+			fakePlacementBlocker.UnblockPlacementReturns(errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			status, _ := Request(rep.UnblockPlacementRoute, rata.Params{"block_id": "block-id"}, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
+
+var _ = Describe("PlacementBlocks", func() {
+	It("lists the placement blocks", func() {
+		blocks := []rep.PlacementBlock{
+			{ID: "block-1", ProcessGuid: "noisy-pg", ExpiresAt: 1234},
+			{ID: "block-2", Domain: "noisy-domain", ExpiresAt: 5678},
+		}
+		fakePlacementBlocker.PlacementBlocksReturns(blocks)
+
+		status, body := Request(rep.PlacementBlocksRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(blocks)))
+	})
+})
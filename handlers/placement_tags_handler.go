@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter . PlacementTagsUpdater
+type PlacementTagsUpdater interface {
+	PlacementTags(logger lager.Logger) rep.CellPlacementTags
+	UpdatePlacementTags(ctx context.Context, logger lager.Logger, tags rep.CellPlacementTags, validate bool) (rep.PlacementTagsUpdate, error)
+}
+
+type placementTagsHandler struct {
+	updater PlacementTagsUpdater
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Placement Tags Handler returns the placement tags the cell currently
+// advertises
+func newPlacementTagsHandler(updater PlacementTagsUpdater, metrics helpers.RequestMetrics, clock clock.Clock) *placementTagsHandler {
+	return &placementTagsHandler{
+		updater: updater,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *placementTagsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "PlacementTags"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-placement-tags")
+
+	w.Header().Set("Content-Type", "application/json")
+	deferErr = json.NewEncoder(w).Encode(h.updater.PlacementTags(logger))
+	if deferErr != nil {
+		logger.Error("failed-to-encode-placement-tags", deferErr)
+	}
+}
+
+type updatePlacementTagsHandler struct {
+	updater PlacementTagsUpdater
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Update Placement Tags Handler changes the placement tags and isolation
+// segment the cell advertises in its subsequent states. With validate=true it
+// also returns the resident work the new tags no longer match
+func newUpdatePlacementTagsHandler(updater PlacementTagsUpdater, metrics helpers.RequestMetrics, clock clock.Clock) *updatePlacementTagsHandler {
+	return &updatePlacementTagsHandler{
+		updater: updater,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *updatePlacementTagsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "UpdatePlacementTags"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-update-placement-tags")
+
+	var tags rep.CellPlacementTags
+	deferErr = json.NewDecoder(r.Body).Decode(&tags)
+	if deferErr != nil {
+		logger.Error("failed-to-unmarshal", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var update rep.PlacementTagsUpdate
+	update, deferErr = h.updater.UpdatePlacementTags(r.Context(), logger, tags, r.URL.Query().Get("validate") == "true")
+	switch deferErr {
+	case nil:
+	case rep.ErrInvalidPlacementTags:
+		logger.Error("invalid-placement-tags", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	default:
+		logger.Error("failed-to-update-placement-tags", deferErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(update)
+}
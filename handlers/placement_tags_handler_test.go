@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlacementTags", func() {
+	It("returns the placement tags of the cell", func() {
+		tags := rep.CellPlacementTags{PlacementTags: []string{"gpu"}, OptionalPlacementTags: []string{"ssd"}, IsolationSegment: "payments"}
+		fakePlacementTagsUpdater.PlacementTagsReturns(tags)
+
+		status, body := Request(rep.PlacementTagsRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(tags)))
+	})
+})
+
+var _ = Describe("UpdatePlacementTags", func() {
+	var tags rep.CellPlacementTags
+
+	BeforeEach(func() {
+		tags = rep.CellPlacementTags{PlacementTags: []string{"gpu"}, IsolationSegment: "payments"}
+	})
+
+	It("updates the placement tags and returns the update", func() {
+		update := rep.PlacementTagsUpdate{CellPlacementTags: tags}
+		fakePlacementTagsUpdater.UpdatePlacementTagsReturns(update, nil)
+
+		status, body := Request(rep.UpdatePlacementTagsRoute, nil, JSONReaderFor(tags))
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(update)))
+
+		Expect(fakePlacementTagsUpdater.UpdatePlacementTagsCallCount()).To(Equal(1))
+		_, _, actualTags, validate := fakePlacementTagsUpdater.UpdatePlacementTagsArgsForCall(0)
+		Expect(actualTags).To(Equal(tags))
+		Expect(validate).To(BeFalse())
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.UpdatePlacementTagsRoute, nil, JSONReaderFor(tags))
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("UpdatePlacementTags"))
+	})
+
+	Context("when asked to validate the resident work", func() {
+		It("returns the work the tags no longer match", func() {
+			update := rep.PlacementTagsUpdate{
+				CellPlacementTags: tags,
+				Mismatches:        []rep.PlacementTagsMismatch{{InstanceGUID: "ig-1", PlacementTags: []string{"gpu"}}},
+			}
+			fakePlacementTagsUpdater.UpdatePlacementTagsReturns(update, nil)
+
+			request, err := requestGenerator.CreateRequest(rep.UpdatePlacementTagsRoute, nil, JSONReaderFor(tags))
+			Expect(err).NotTo(HaveOccurred())
+			request.URL.RawQuery = "validate=true"
+
+			response, err := client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			_, _, _, validate := fakePlacementTagsUpdater.UpdatePlacementTagsArgsForCall(0)
+			Expect(validate).To(BeTrue())
+		})
+	})
+
+	Context("when the request cannot be decoded", func() {
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.UpdatePlacementTagsRoute, nil, JSONReaderFor("not-tags"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakePlacementTagsUpdater.UpdatePlacementTagsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the tags are invalid", func() {
+		BeforeEach(func() {
+			fakePlacementTagsUpdater.UpdatePlacementTagsReturns(rep.PlacementTagsUpdate{}, rep.ErrInvalidPlacementTags)
+		})
+
+		It("responds with a bad request", func() {
+			status, _ := Request(rep.UpdatePlacementTagsRoute, nil, JSONReaderFor(tags))
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when the resident work cannot be validated", func() {
+		BeforeEach(func() {
+			fakePlacementTagsUpdater.UpdatePlacementTagsReturns(rep.PlacementTagsUpdate{}, errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			status, _ := Request(rep.UpdatePlacementTagsRoute, nil, JSONReaderFor(tags))
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
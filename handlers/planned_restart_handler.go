@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep/presence"
+)
+
+type plannedRestartHandler struct {
+	plannedRestarter presence.PlannedRestarter
+	metrics          helpers.RequestMetrics
+}
+
+// Planned Restart Handler serves a route that is called before the rep is
+// restarted on purpose, e.g. during an upgrade
+func newPlannedRestartHandler(plannedRestarter presence.PlannedRestarter, requestMetrics helpers.RequestMetrics) *plannedRestartHandler {
+	return &plannedRestartHandler{
+		plannedRestarter: plannedRestarter,
+		metrics:          requestMetrics,
+	}
+}
+
+func (h *plannedRestartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	logger = logger.Session("handling-planned-restart")
+
+	err := h.plannedRestarter.MarkPlannedRestart()
+	if err != nil {
+		logger.Error("failed-to-mark-planned-restart", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("marked-planned-restart")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,35 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlannedRestartHandler", func() {
+	Context("when the planned restart is marked", func() {
+		It("marks the planned restart", func() {
+			Request(rep.PlannedRestartRoute, nil, nil)
+			Expect(fakePlannedRestarter.MarkPlannedRestartCallCount()).To(Equal(1))
+		})
+
+		It("responds with 204 NO CONTENT", func() {
+			status, _ := Request(rep.PlannedRestartRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	Context("when marking the planned restart fails", func() {
+		BeforeEach(func() {
+			fakePlannedRestarter.MarkPlannedRestartReturns(errors.New("boom"))
+		})
+
+		It("responds with 500 INTERNAL SERVER ERROR", func() {
+			status, _ := Request(rep.PlannedRestartRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
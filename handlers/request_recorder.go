@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+	"github.com/tedsuo/rata"
+)
+
+const redacted = "[REDACTED]"
+
+var ErrRequestRecordingUnbounded = errors.New("the max bytes of a request recording must be positive")
+
+// recordedRoutes are the routes whose requests are recorded. They are the
+// routes the auctioneer drives the cell through.
+var recordedRoutes = []string{rep.StateRoute, rep.PerformRoute}
+
+// sensitiveHeaders are left out of the recorded requests.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// RecordedExchange is a request to the rep and the response it got, as a
+// RequestRecorder writes them. Passwords in the bodies, such as those of the
+// registry credentials of work, are redacted.
+type RecordedExchange struct {
+	Time           time.Time   `json:"time"`
+	Route          string      `json:"route"`
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	StatusCode     int         `json:"status_code"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+}
+
+// RequestRecorder writes the exchanges of the recorded routes to a file, one
+// JSON encoded RecordedExchange per line, so that the auctions of a cell can
+// be replayed against a fake cell with Replay. Once the file would grow past
+// maxBytes it is rotated: it replaces the file of the same path suffixed with
+// ".1", and recording starts over in a new file, so that the recording never
+// takes more than twice maxBytes on disk.
+type RequestRecorder struct {
+	logger   lager.Logger
+	clock    clock.Clock
+	path     string
+	maxBytes int64
+
+	lock    sync.Mutex
+	file    *os.File
+	written int64
+}
+
+// NewRequestRecorder appends the exchanges it records to the file at path.
+// maxBytes must be positive.
+func NewRequestRecorder(logger lager.Logger, path string, maxBytes int64, clock clock.Clock) (*RequestRecorder, error) {
+	if maxBytes <= 0 {
+		return nil, ErrRequestRecordingUnbounded
+	}
+	file, written, err := openRecording(path)
+	if err != nil {
+		return nil, err
+	}
+	return &RequestRecorder{
+		logger:   logger.Session("request-recorder", lager.Data{"path": path}),
+		clock:    clock,
+		path:     path,
+		maxBytes: maxBytes,
+		file:     file,
+		written:  written,
+	}, nil
+}
+
+func openRecording(path string) (*os.File, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// RecordRequests wraps the handlers of the recorded routes so that recorder
+// records their exchanges. Handlers are returned as they are when recorder is
+// nil.
+func RecordRequests(handlers rata.Handlers, recorder *RequestRecorder) rata.Handlers {
+	if recorder == nil {
+		return handlers
+	}
+	for _, route := range recordedRoutes {
+		if handler, ok := handlers[route]; ok {
+			handlers[route] = recorder.wrap(route, handler)
+		}
+	}
+	return handlers
+}
+
+// Close closes the recording. The exchanges recorded after it is closed are
+// dropped.
+func (r *RequestRecorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RequestRecorder) wrap(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestBody, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+
+		recording := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		handler.ServeHTTP(recording, req)
+
+		r.record(RecordedExchange{
+			Time:           r.clock.Now(),
+			Route:          route,
+			Method:         req.Method,
+			URL:            req.URL.RequestURI(),
+			RequestHeader:  sanitizeHeader(req.Header),
+			RequestBody:    string(sanitizeBody(requestBody)),
+			StatusCode:     recording.statusCode,
+			ResponseHeader: recording.Header().Clone(),
+			ResponseBody:   string(sanitizeBody(recording.body.Bytes())),
+		})
+	})
+}
+
+func (r *RequestRecorder) record(exchange RecordedExchange) {
+	encoded, err := json.Marshal(exchange)
+	if err != nil {
+		r.logger.Error("failed-to-encode-exchange", err)
+		return
+	}
+	encoded = append(encoded, '\n')
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.file == nil {
+		return
+	}
+	if int64(len(encoded)) > r.maxBytes {
+		r.logger.Info("exchange-exceeds-max-bytes", lager.Data{"route": exchange.Route, "bytes": len(encoded), "max-bytes": r.maxBytes})
+		return
+	}
+	if r.written+int64(len(encoded)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			r.logger.Error("failed-to-rotate-recording", err)
+			return
+		}
+	}
+	n, err := r.file.Write(encoded)
+	r.written += int64(n)
+	if err != nil {
+		r.logger.Error("failed-to-write-exchange", err)
+	}
+}
+
+// rotate moves the recording aside and starts a new one. It is called with
+// the lock held.
+func (r *RequestRecorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	file, written, err := openRecording(r.path)
+	if err != nil {
+		return err
+	}
+	r.file, r.written = file, written
+	r.logger.Info("rotated-recording", lager.Data{"max-bytes": r.maxBytes})
+	return nil
+}
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingResponseWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func sanitizeHeader(header http.Header) http.Header {
+	sanitized := header.Clone()
+	for _, name := range sensitiveHeaders {
+		sanitized.Del(name)
+	}
+	return sanitized
+}
+
+// sanitizeBody redacts the values of the password fields of a JSON body.
+// Bodies that are not JSON are kept as they are.
+func sanitizeBody(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	sanitized, err := json.Marshal(redactPasswords(value))
+	if err != nil {
+		return body
+	}
+	return sanitized
+}
+
+func redactPasswords(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if strings.EqualFold(key, "password") {
+				v[key] = redacted
+			} else {
+				v[key] = redactPasswords(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactPasswords(v[i])
+		}
+	}
+	return value
+}
+
+// ReplayResult is the response a recorded request got when it was replayed,
+// and whether it matched the recorded response.
+type ReplayResult struct {
+	Exchange     RecordedExchange
+	StatusCode   int
+	ResponseBody string
+	Matched      bool
+}
+
+// Replay serves the exchanges recorded in recording to handler, such as the
+// handlers of a cell backed by a fake LocalRep, in the order they were
+// recorded, and returns the result of each. Responses match when their status
+// codes and their sanitized bodies are equal.
+func Replay(recording io.Reader, handler http.Handler) ([]ReplayResult, error) {
+	results := []ReplayResult{}
+	scanner := bufio.NewScanner(recording)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var exchange RecordedExchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return nil, err
+		}
+
+		req := httptest.NewRequest(exchange.Method, exchange.URL, strings.NewReader(exchange.RequestBody))
+		for name, values := range exchange.RequestHeader {
+			req.Header[name] = values
+		}
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, req)
+
+		body := response.Body.String()
+		results = append(results, ReplayResult{
+			Exchange:     exchange,
+			StatusCode:   response.Code,
+			ResponseBody: body,
+			Matched:      response.Code == exchange.StatusCode && string(sanitizeBody([]byte(body))) == exchange.ResponseBody,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
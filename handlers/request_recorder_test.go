@@ -0,0 +1,233 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/rata"
+)
+
+var _ = Describe("RequestRecorder", func() {
+	var (
+		tmpDir        string
+		recordingPath string
+		maxBytes      int64
+		recorder      *handlers.RequestRecorder
+		router        http.Handler
+		requestedWork rep.Work
+		failedWork    rep.Work
+	)
+
+	newRouter := func(recorder *handlers.RequestRecorder) http.Handler {
+		legacy := handlers.NewLegacy(fakeLocalRep, fakeMetricCollector, fakeExecutorClient, fakeEvacuatable, fakeMaintainable, fakePlannedRestarter, fakeInfoReporter, fakePerformQueue, fakeCapacityReserver, fakeDiskQuotaGrower, fakeContainerEventHistory, fakeCgroupReader, fakeLogRateLimitReporter, fakeHealthCheckReporter, fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, fakeCacheStatsReporter, fakeSelfTester, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeContactReporter, fakeRequestMetrics, fakeClock, logger)
+		handler, err := rata.NewRouter(rep.Routes, handlers.RecordRequests(legacy, recorder))
+		Expect(err).NotTo(HaveOccurred())
+		return handler
+	}
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewReader(body))
+		request.Header.Set("Authorization", "Bearer secret")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	readExchanges := func() []handlers.RecordedExchange {
+		contents, err := ioutil.ReadFile(recordingPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		exchanges := []handlers.RecordedExchange{}
+		for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+			if line == "" {
+				continue
+			}
+			var exchange handlers.RecordedExchange
+			Expect(json.Unmarshal([]byte(line), &exchange)).To(Succeed())
+			exchanges = append(exchanges, exchange)
+		}
+		return exchanges
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "request-recorder")
+		Expect(err).NotTo(HaveOccurred())
+		recordingPath = filepath.Join(tmpDir, "requests.jsonl")
+		maxBytes = 1024 * 1024
+
+		lrp := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.NewResource(128, 256, 256), rep.NewPlacementConstraint("some-rootfs", nil, nil))
+		lrp.Registry = &rep.RegistryCredentials{Username: "user", Password: "hunter2"}
+		requestedWork = rep.Work{LRPs: []rep.LRP{lrp}}
+		failedWork = rep.Work{LRPs: []rep.LRP{lrp}}
+
+		fakeLocalRep.PerformStub = func(ctx context.Context, logger lager.Logger, work rep.Work) (rep.Work, error) {
+			return failedWork, nil
+		}
+		fakeLocalRep.StateReturns(rep.CellState{CellID: "cell-1"}, true, nil)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		recorder, err = handlers.NewRequestRecorder(logger, recordingPath, maxBytes, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+		router = newRouter(recorder)
+	})
+
+	AfterEach(func() {
+		Expect(recorder.Close()).To(Succeed())
+		os.RemoveAll(tmpDir)
+	})
+
+	It("records the state requests and their responses", func() {
+		response := serve("GET", "/state", nil)
+		Expect(response.Code).To(Equal(http.StatusOK))
+
+		exchanges := readExchanges()
+		Expect(exchanges).To(HaveLen(1))
+		Expect(exchanges[0].Route).To(Equal(rep.StateRoute))
+		Expect(exchanges[0].Method).To(Equal("GET"))
+		Expect(exchanges[0].URL).To(Equal("/state"))
+		Expect(exchanges[0].StatusCode).To(Equal(http.StatusOK))
+		Expect(exchanges[0].ResponseBody).To(MatchJSON(response.Body.String()))
+		Expect(exchanges[0].Time).To(BeTemporally("==", fakeClock.Now()))
+	})
+
+	It("records the perform requests with the registry passwords redacted", func() {
+		response := serve("POST", "/work", []byte(JSONFor(requestedWork)))
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Body.String()).To(ContainSubstring("hunter2"))
+
+		exchanges := readExchanges()
+		Expect(exchanges).To(HaveLen(1))
+		Expect(exchanges[0].Route).To(Equal(rep.PerformRoute))
+		Expect(exchanges[0].RequestBody).To(ContainSubstring(`"username":"user"`))
+		Expect(exchanges[0].RequestBody).NotTo(ContainSubstring("hunter2"))
+		Expect(exchanges[0].ResponseBody).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("passes the request body on to the handler", func() {
+		serve("POST", "/work", []byte(JSONFor(requestedWork)))
+
+		Expect(fakeLocalRep.PerformCallCount()).To(Equal(1))
+		_, _, work := fakeLocalRep.PerformArgsForCall(0)
+		Expect(work.LRPs[0].Registry.Password).To(Equal("hunter2"))
+	})
+
+	It("leaves the authorization headers out", func() {
+		serve("GET", "/state", nil)
+
+		exchanges := readExchanges()
+		Expect(exchanges).To(HaveLen(1))
+		Expect(exchanges[0].RequestHeader.Get("Authorization")).To(BeEmpty())
+	})
+
+	It("does not record the other routes", func() {
+		serve("GET", "/ping", nil)
+
+		Expect(readExchanges()).To(BeEmpty())
+	})
+
+	It("requires a positive max bytes", func() {
+		_, err := handlers.NewRequestRecorder(logger, filepath.Join(tmpDir, "unbounded.jsonl"), 0, fakeClock)
+		Expect(err).To(Equal(handlers.ErrRequestRecordingUnbounded))
+	})
+
+	It("stops recording once closed", func() {
+		Expect(recorder.Close()).To(Succeed())
+		serve("GET", "/state", nil)
+
+		Expect(readExchanges()).To(BeEmpty())
+	})
+
+	Context("when the recording reaches its max bytes", func() {
+		JustBeforeEach(func() {
+			serve("GET", "/state", nil)
+			info, err := os.Stat(recordingPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Close()).To(Succeed())
+			Expect(os.Remove(recordingPath)).To(Succeed())
+
+			recorder, err = handlers.NewRequestRecorder(logger, recordingPath, info.Size()*3/2, fakeClock)
+			Expect(err).NotTo(HaveOccurred())
+			router = newRouter(recorder)
+		})
+
+		It("rotates the recording", func() {
+			serve("GET", "/state", nil)
+			serve("GET", "/state", nil)
+
+			Expect(readExchanges()).To(HaveLen(1))
+			rotated, err := ioutil.ReadFile(recordingPath + ".1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Count(string(rotated), "\n")).To(Equal(1))
+			Expect(logger.Buffer()).To(gbytes.Say("rotated-recording"))
+		})
+	})
+
+	Context("when an exchange is larger than the max bytes", func() {
+		BeforeEach(func() {
+			maxBytes = 1
+		})
+
+		It("drops it", func() {
+			serve("GET", "/state", nil)
+
+			Expect(readExchanges()).To(BeEmpty())
+			Expect(logger.Buffer()).To(gbytes.Say("exchange-exceeds-max-bytes"))
+		})
+	})
+
+	Describe("Replay", func() {
+		It("replays the recorded requests against the handler and matches their responses", func() {
+			serve("GET", "/state", nil)
+			serve("POST", "/work", []byte(JSONFor(requestedWork)))
+
+			recording, err := os.Open(recordingPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer recording.Close()
+
+			results, err := handlers.Replay(recording, newRouter(nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Matched).To(BeTrue())
+			Expect(results[1].Matched).To(BeTrue())
+			Expect(fakeLocalRep.PerformCallCount()).To(Equal(2))
+		})
+
+		It("reports the responses that differ from the recorded ones", func() {
+			serve("GET", "/state", nil)
+			fakeLocalRep.StateReturns(rep.CellState{CellID: "cell-2"}, true, nil)
+
+			recording, err := os.Open(recordingPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer recording.Close()
+
+			results, err := handlers.Replay(recording, newRouter(nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Matched).To(BeFalse())
+			Expect(results[0].ResponseBody).To(ContainSubstring("cell-2"))
+		})
+
+		It("errors on a recording that cannot be decoded", func() {
+			_, err := handlers.Replay(strings.NewReader("not json\n"), newRouter(nil))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
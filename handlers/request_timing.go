@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"github.com/tedsuo/rata"
+)
+
+// routeRequestTypes are the request types of the routes whose names are not
+// the request types their handlers report metrics under.
+var routeRequestTypes = map[string]string{
+	rep.StateRoute:    "State",
+	rep.PerformRoute:  "Perform",
+	rep.SimResetRoute: "Reset",
+}
+
+type timedConnKey struct{}
+
+// NewTimedListener returns a listener that notes when it accepts each
+// connection, for TimeRequests to measure how long the first request of the
+// connection queued before a handler started on it. It is to wrap the
+// listener beneath any TLS listener, so that the handshake of a new
+// connection counts towards the queueing of its first request.
+func NewTimedListener(listener net.Listener, clock clock.Clock) net.Listener {
+	return &timedListener{Listener: listener, clock: clock}
+}
+
+// RequestTimingContext is the http.Server ConnContext that passes the
+// connections of a NewTimedListener on to TimeRequests.
+func RequestTimingContext(ctx context.Context, conn net.Conn) context.Context {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	if timed, ok := conn.(*timedConn); ok {
+		return context.WithValue(ctx, timedConnKey{}, timed)
+	}
+	return ctx
+}
+
+// QueueRequestType is the request type TimeRequests updates the latency of
+// with the queue time of the requests to route. The request metrics notifier
+// is to be created with the queue request types of the routes it times.
+func QueueRequestType(route string) string {
+	if requestType, ok := routeRequestTypes[route]; ok {
+		return requestType + "Queue"
+	}
+	return route + "Queue"
+}
+
+// TimeRequests wraps the handlers to report the rep.RequestTiming of each
+// request in its Server-Timing header and to update the latency of the
+// QueueRequestType of its route with its queue time, next to the latency its
+// handler reports. The queue time runs from when the listener accepted the
+// connection of the request, as the backlog of connections not yet accepted
+// cannot be observed, so only the first request of a connection queues: the
+// requests after it on a kept alive connection, and those that did not arrive
+// through a NewTimedListener, are reported with no queue time.
+func TimeRequests(handlers rata.Handlers, metrics helpers.RequestMetrics, clock clock.Clock) rata.Handlers {
+	timed := rata.Handlers{}
+	for route, handler := range handlers {
+		timed[route] = timeRequests(handler, QueueRequestType(route), metrics, clock)
+	}
+	return timed
+}
+
+func timeRequests(handler http.Handler, queueRequestType string, metrics helpers.RequestMetrics, clock clock.Clock) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := clock.Now()
+
+		var queueTime time.Duration
+		if conn, ok := r.Context().Value(timedConnKey{}).(*timedConn); ok {
+			if accepted, ok := conn.firstRequest(); ok && start.After(accepted) {
+				queueTime = start.Sub(accepted)
+			}
+		}
+		metrics.UpdateLatency(queueRequestType, queueTime)
+
+		timed := &timingResponseWriter{ResponseWriter: w, clock: clock, start: start, queueTime: queueTime}
+		handler.ServeHTTP(timed, r)
+		if !timed.written {
+			timed.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+type timedListener struct {
+	net.Listener
+	clock clock.Clock
+}
+
+func (l *timedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{Conn: conn, accepted: l.clock.Now()}, nil
+}
+
+// timedConn is a connection with the time it was accepted.
+type timedConn struct {
+	net.Conn
+	accepted time.Time
+
+	lock   sync.Mutex
+	served bool
+}
+
+// firstRequest returns when the connection was accepted, unless a request of
+// the connection was already served.
+func (c *timedConn) firstRequest() (time.Time, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.served {
+		return time.Time{}, false
+	}
+	c.served = true
+	return c.accepted, true
+}
+
+type timingResponseWriter struct {
+	http.ResponseWriter
+	clock     clock.Clock
+	start     time.Time
+	queueTime time.Duration
+	written   bool
+}
+
+func (w *timingResponseWriter) WriteHeader(statusCode int) {
+	if !w.written {
+		w.written = true
+		timing := rep.RequestTiming{Queue: w.queueTime, Handler: w.clock.Since(w.start)}
+		w.Header().Set(rep.ServerTimingHeader, timing.Header())
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *timingResponseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
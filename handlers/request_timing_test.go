@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// acceptSignalingListener signals on accepts whenever it accepted a
+// connection.
+type acceptSignalingListener struct {
+	net.Listener
+	accepts chan struct{}
+}
+
+func (l *acceptSignalingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepts <- struct{}{}
+	}
+	return conn, err
+}
+
+var _ = Describe("RequestTiming", func() {
+	var (
+		listener net.Listener
+		accepts  chan struct{}
+		conn     net.Conn
+	)
+
+	BeforeEach(func() {
+		accepts = make(chan struct{}, 100)
+
+		tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		listener = &acceptSignalingListener{Listener: handlers.NewTimedListener(tcpListener, fakeClock), accepts: accepts}
+
+		routeHandlers := rata.Handlers{
+			rep.StateRoute: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fakeClock.Increment(2 * time.Second)
+				w.Write([]byte("ok"))
+			}),
+		}
+		router, err := rata.NewRouter(rata.Routes{rata.Route{Path: "/state", Method: "GET", Name: rep.StateRoute}}, handlers.TimeRequests(routeHandlers, fakeRequestMetrics, fakeClock))
+		Expect(err).NotTo(HaveOccurred())
+		server := &http.Server{Handler: router, ConnContext: handlers.RequestTimingContext}
+		go server.Serve(listener)
+
+		conn, err = net.Dial("tcp", listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(accepts).Should(Receive())
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		listener.Close()
+	})
+
+	readResponse := func(reader *bufio.Reader) rep.RequestTiming {
+		resp, err := http.ReadResponse(reader, nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		timing, ok := rep.RequestTimingFromHeader(resp.Header)
+		Expect(ok).To(BeTrue())
+		return timing
+	}
+
+	It("reports the time a request queued since its connection was accepted apart from the time its handler took", func() {
+		fakeClock.Increment(3 * time.Second)
+		_, err := conn.Write([]byte("GET /state HTTP/1.1\r\nHost: cell\r\n\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		timing := readResponse(bufio.NewReader(conn))
+		Expect(timing).To(Equal(rep.RequestTiming{Queue: 3 * time.Second, Handler: 2 * time.Second}))
+
+		Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
+		requestType, latency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
+		Expect(requestType).To(Equal("StateQueue"))
+		Expect(latency).To(Equal(3 * time.Second))
+	})
+
+	It("reports no queue time for the later requests of a kept alive connection", func() {
+		reader := bufio.NewReader(conn)
+		_, err := conn.Write([]byte("GET /state HTTP/1.1\r\nHost: cell\r\n\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+		readResponse(reader)
+
+		fakeClock.Increment(time.Minute)
+		_, err = conn.Write([]byte("GET /state HTTP/1.1\r\nHost: cell\r\n\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		timing := readResponse(reader)
+		Expect(timing.Queue).To(BeZero())
+		Expect(timing.Handler).To(Equal(2 * time.Second))
+
+		Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(2))
+		_, latency := fakeRequestMetrics.UpdateLatencyArgsForCall(1)
+		Expect(latency).To(BeZero())
+	})
+
+	It("names the queue request types after the request types of the routes", func() {
+		Expect(handlers.QueueRequestType(rep.PerformRoute)).To(Equal("PerformQueue"))
+		Expect(handlers.QueueRequestType(rep.CancelTaskRoute)).To(Equal("CancelTaskQueue"))
+	})
+})
@@ -2,33 +2,34 @@ package handlers
 
 import (
 	"net/http"
-	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 	"code.cloudfoundry.org/rep/auctioncellrep"
 )
 
 type reset struct {
-	rep     auctioncellrep.AuctionCellClient
+	rep     auctioncellrep.Resetter
 	metrics helpers.RequestMetrics
+	clock   clock.Clock
 }
 
-func newResetHandler(rep auctioncellrep.AuctionCellClient, metrics helpers.RequestMetrics) *reset {
-	return &reset{rep: rep, metrics: metrics}
+func newResetHandler(rep auctioncellrep.Resetter, metrics helpers.RequestMetrics, clock clock.Clock) *reset {
+	return &reset{rep: rep, metrics: metrics, clock: clock}
 }
 
 func (h *reset) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
 	var deferErr error
 
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "Reset"
 	startMetrics(h.metrics, requestType)
-	defer stopMetrics(h.metrics, requestType, start, &deferErr)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
 
 	logger = logger.Session("sim-reset")
 
-	deferErr = h.rep.Reset()
+	deferErr = h.rep.Reset(r.Context())
 	if deferErr != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		logger.Error("failed-to-reset", deferErr)
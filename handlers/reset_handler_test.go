@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -16,8 +17,8 @@ var _ = Describe("Reset", func() {
 
 		BeforeEach(func() {
 			requestLatency = 50 * time.Millisecond
-			fakeLocalRep.ResetStub = func() error {
-				time.Sleep(requestLatency)
+			fakeLocalRep.ResetStub = func(ctx context.Context) error {
+				fakeClock.Increment(requestLatency)
 				return nil
 			}
 		})
@@ -51,7 +52,7 @@ var _ = Describe("Reset", func() {
 			Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
 			calledRequestType, calledLatency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
 			Expect(calledRequestType).To(Equal("Reset"))
-			Expect(calledLatency).To(BeNumerically("~", requestLatency, 25*time.Millisecond))
+			Expect(calledLatency).To(Equal(requestLatency))
 
 			Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
 			calledRequestType, delta = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep/selftest"
+)
+
+//go:generate counterfeiter . SelfTester
+type SelfTester interface {
+	Run(logger lager.Logger) selftest.Report
+}
+
+type selfTestHandler struct {
+	tester  SelfTester
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Self Test Handler runs a throwaway container on the cell and serves the
+// timings of every stage, with a 503 when one of them failed
+func newSelfTestHandler(tester SelfTester, metrics helpers.RequestMetrics, clock clock.Clock) *selfTestHandler {
+	return &selfTestHandler{
+		tester:  tester,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *selfTestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "SelfTest"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-self-test")
+
+	if h.tester == nil {
+		logger.Info("self-test-not-configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	report := h.tester.Run(logger)
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Succeeded {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	deferErr = json.NewEncoder(w).Encode(report)
+	if deferErr != nil {
+		logger.Error("failed-to-encode-report", deferErr)
+	}
+}
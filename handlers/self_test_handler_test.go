@@ -0,0 +1,78 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/handlers"
+	"code.cloudfoundry.org/rep/selftest"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelfTest", func() {
+	var report selftest.Report
+
+	BeforeEach(func() {
+		report = selftest.Report{
+			ContainerGuid: "self-test-some-guid",
+			Succeeded:     true,
+			Duration:      3 * time.Second,
+			Stages: []selftest.Stage{
+				{Name: selftest.StageAllocate, Duration: 100 * time.Millisecond},
+				{Name: selftest.StageRun, Duration: 2 * time.Second},
+				{Name: selftest.StageComplete, Duration: 800 * time.Millisecond},
+				{Name: selftest.StageDestroy, Duration: 100 * time.Millisecond},
+			},
+		}
+		fakeSelfTester.RunReturns(report)
+	})
+
+	It("runs the self test and serves its report", func() {
+		status, body := Request(rep.SelfTestRoute, nil, nil)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(JSONFor(report)))
+		Expect(fakeSelfTester.RunCallCount()).To(Equal(1))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.SelfTestRoute, nil, nil)
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("SelfTest"))
+	})
+
+	Context("when a stage fails", func() {
+		BeforeEach(func() {
+			report.Succeeded = false
+			report.Stages[2].Error = selftest.ErrTimedOut.Error()
+			fakeSelfTester.RunReturns(report)
+		})
+
+		It("responds with 503 Service Unavailable and the report", func() {
+			status, body := Request(rep.SelfTestRoute, nil, nil)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			Expect(body).To(MatchJSON(JSONFor(report)))
+		})
+	})
+
+	Context("when the self test is not configured", func() {
+		It("responds with 501 Not Implemented", func() {
+			adminHandlers := handlers.NewAdmin(fakeConfigReporter, fakeImageCachePruner, fakePlacementBlocker, fakePlacementTagsUpdater, fakeFragmentationAnalyzer, fakeConsistencyReporter, fakeCacheStatsReporter, nil, fakeCapacityReporter, fakeAuctionRoutesCloser, fakeHealthCheckRelaxer, fakeRequestMetrics, fakeClock, logger)
+			router, err := rata.NewRouter(rep.RoutesAdmin, adminHandlers)
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := rata.NewRequestGenerator("", rep.RoutesAdmin).CreateRequest(rep.SelfTestRoute, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+		})
+	})
+})
@@ -3,46 +3,116 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"time"
+	"strings"
+	"sync"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
 	"code.cloudfoundry.org/rep"
 	"code.cloudfoundry.org/rep/auctioncellrep"
+	"code.cloudfoundry.org/rep/featureflags"
 )
 
+// stateSnapshotHistory is how many of the most recent states are remembered
+// to compute deltas from. A client that polls less often than the state
+// changes falls back to the full state.
+const stateSnapshotHistory = 8
+
+type stateSnapshot struct {
+	etag  string
+	state rep.CellState
+}
+
 type state struct {
-	rep     auctioncellrep.AuctionCellClient
+	rep     auctioncellrep.StateReporter
 	metrics helpers.RequestMetrics
+	clock   clock.Clock
+
+	snapshotsLock sync.Mutex
+	snapshots     []stateSnapshot
 }
 
-func newStateHandler(rep auctioncellrep.AuctionCellClient, metrics helpers.RequestMetrics) *state {
-	return &state{rep: rep, metrics: metrics}
+func newStateHandler(rep auctioncellrep.StateReporter, metrics helpers.RequestMetrics, clock clock.Clock) *state {
+	return &state{rep: rep, metrics: metrics, clock: clock}
 }
 
 func (h *state) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
 	var deferErr error
 
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "State"
 	startMetrics(h.metrics, requestType)
-	defer stopMetrics(h.metrics, requestType, start, &deferErr)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
 
 	logger = logger.Session("auction-fetch-state")
 
 	var state rep.CellState
 	var healthy bool
-	state, healthy, deferErr = h.rep.State(logger)
+	state, healthy, deferErr = h.rep.State(r.Context(), logger)
 	if deferErr != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		logger.Error("failed-to-fetch-state", deferErr)
 		return
 	}
 
+	var response interface{} = state
+	etag, err := rep.StateETag(state)
+	if err != nil {
+		logger.Error("failed-to-compute-state-etag", err)
+	} else {
+		w.Header().Set("ETag", `"`+etag+`"`)
+
+		since := strings.Trim(r.URL.Query().Get("since"), `"`)
+		if base, ok := h.remember(etag, state, since); ok && servesDeltas(state) {
+			w.Header().Set(rep.StateDeltaHeader, "true")
+			response = rep.NewCellStateDelta(since, base, state)
+		}
+	}
+
 	if !healthy {
-		logger.Info("cell-not-healthy")
+		logger.Info("cell-not-healthy", lager.Data{"reasons": state.UnhealthyReasons})
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 
-	json.NewEncoder(w).Encode(state)
+	json.NewEncoder(w).Encode(response)
+}
+
+// servesDeltas reports whether the cell serves its state as deltas, which it
+// does while it reports the v2 state schema feature flag enabled.
+func servesDeltas(state rep.CellState) bool {
+	for _, flag := range state.FeatureFlags {
+		if flag == featureflags.StateV2Schema {
+			return true
+		}
+	}
+	return false
+}
+
+// remember records the current state and returns the snapshot identified by
+// since, if it is still remembered.
+func (h *state) remember(etag string, current rep.CellState, since string) (rep.CellState, bool) {
+	h.snapshotsLock.Lock()
+	defer h.snapshotsLock.Unlock()
+
+	var base rep.CellState
+	found := false
+	known := false
+	for _, snapshot := range h.snapshots {
+		if since != "" && snapshot.etag == since {
+			base, found = snapshot.state, true
+		}
+		if snapshot.etag == etag {
+			known = true
+		}
+	}
+
+	if !known {
+		h.snapshots = append(h.snapshots, stateSnapshot{etag: etag, state: current})
+		if len(h.snapshots) > stateSnapshotHistory {
+			h.snapshots = h.snapshots[len(h.snapshots)-stateSnapshotHistory:]
+		}
+	}
+
+	return base, found
 }
@@ -1,12 +1,18 @@
 package handlers_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/featureflags"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -23,8 +29,8 @@ var _ = Describe("State", func() {
 			RootFSProviders: rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
 		}
 		requestLatency = 50 * time.Millisecond
-		fakeLocalRep.StateStub = func(logger lager.Logger) (rep.CellState, bool, error) {
-			time.Sleep(requestLatency)
+		fakeLocalRep.StateStub = func(ctx context.Context, logger lager.Logger) (rep.CellState, bool, error) {
+			fakeClock.Increment(requestLatency)
 			return repState, true, nil
 		}
 	})
@@ -57,7 +63,7 @@ var _ = Describe("State", func() {
 		Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
 		calledRequestType, calledLatency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
 		Expect(calledRequestType).To(Equal("State"))
-		Expect(calledLatency).To(BeNumerically("~", requestLatency, 25*time.Millisecond))
+		Expect(calledLatency).To(Equal(requestLatency))
 
 		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
 		calledRequestType, delta = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
@@ -67,15 +73,84 @@ var _ = Describe("State", func() {
 		Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(0))
 	})
 
+	Describe("delta-encoded responses", func() {
+		fetchState := func(since string) (*http.Response, []byte) {
+			request, err := requestGenerator.CreateRequest(rep.StateRoute, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			if since != "" {
+				request.URL.RawQuery = url.Values{"since": []string{since}}.Encode()
+			}
+
+			response, err := client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+			return response, body
+		}
+
+		BeforeEach(func() {
+			repState.FeatureFlags = []string{featureflags.StateV2Schema}
+		})
+
+		It("identifies the state with an etag", func() {
+			response, _ := fetchState("")
+			etag, err := rep.StateETag(repState)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Header.Get("ETag")).To(Equal(`"` + etag + `"`))
+		})
+
+		It("returns the change since a remembered snapshot", func() {
+			response, _ := fetchState("")
+			since := response.Header.Get("ETag")
+			base := repState
+
+			repState = rep.CellState{
+				RootFSProviders: rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+				Tasks:           []rep.Task{rep.NewTask("task-guid", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{})},
+				FeatureFlags:    []string{featureflags.StateV2Schema},
+			}
+
+			response, body := fetchState(since)
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get(rep.StateDeltaHeader)).To(Equal("true"))
+
+			var delta rep.CellStateDelta
+			Expect(json.Unmarshal(body, &delta)).To(Succeed())
+			Expect(delta.Since).To(Equal(strings.Trim(since, `"`)))
+			Expect(delta.Tasks).To(HaveLen(1))
+			Expect(delta.Apply(base).Tasks).To(HaveLen(1))
+		})
+
+		It("returns the full state unless the v2 state schema is enabled", func() {
+			repState.FeatureFlags = nil
+			response, _ := fetchState("")
+			since := response.Header.Get("ETag")
+
+			response, body := fetchState(since)
+			Expect(response.Header.Get(rep.StateDeltaHeader)).To(BeEmpty())
+			Expect(body).To(MatchJSON(JSONFor(repState)))
+		})
+
+		It("returns the full state when the snapshot is not remembered", func() {
+			response, body := fetchState("unknown-etag")
+			Expect(response.Header.Get(rep.StateDeltaHeader)).To(BeEmpty())
+			Expect(body).To(MatchJSON(JSONFor(repState)))
+		})
+	})
+
 	Context("when the state call is not healthy", func() {
 		BeforeEach(func() {
+			repState.UnhealthyReasons = []string{rep.UnhealthyReasonGardenUnresponsive}
 			fakeLocalRep.StateReturns(repState, false, nil)
 		})
 
-		It("returns a StatusServiceUnavailable", func() {
+		It("returns a StatusServiceUnavailable with the reasons in the state", func() {
 			status, body := Request(rep.StateRoute, nil, nil)
 			Expect(status).To(Equal(http.StatusServiceUnavailable))
 			Expect(body).To(MatchJSON(JSONFor(repState)))
+			Expect(string(body)).To(ContainSubstring(`"UnhealthyReasons":["garden unresponsive"]`))
 			Expect(fakeLocalRep.StateCallCount()).To(Equal(1))
 		})
 	})
@@ -3,8 +3,8 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
@@ -15,23 +15,25 @@ import (
 type StopLRPInstanceHandler struct {
 	client  executor.Client
 	metrics helpers.RequestMetrics
+	clock   clock.Clock
 }
 
 // This is public for testing purpose
-func NewStopLRPInstanceHandler(client executor.Client, metrics helpers.RequestMetrics) *StopLRPInstanceHandler {
+func NewStopLRPInstanceHandler(client executor.Client, metrics helpers.RequestMetrics, clock clock.Clock) *StopLRPInstanceHandler {
 	return &StopLRPInstanceHandler{
 		client:  client,
 		metrics: metrics,
+		clock:   clock,
 	}
 }
 
 func (h *StopLRPInstanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
 	var deferErr error
 
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "StopLRPInstance"
 	startMetrics(h.metrics, requestType)
-	defer stopMetrics(h.metrics, requestType, start, &deferErr)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
 
 	processGuid := r.FormValue(":process_guid")
 	instanceGuid := r.FormValue(":instance_guid")
@@ -35,7 +35,7 @@ var _ = Describe("StopLRPInstanceHandler", func() {
 		logger = lagertest.NewTestLogger("test")
 		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
 
-		stopInstanceHandler = handlers.NewStopLRPInstanceHandler(fakeClient, fakeRequestMetrics)
+		stopInstanceHandler = handlers.NewStopLRPInstanceHandler(fakeClient, fakeRequestMetrics, fakeClock)
 
 		resp = httptest.NewRecorder()
 
@@ -66,7 +66,7 @@ var _ = Describe("StopLRPInstanceHandler", func() {
 			BeforeEach(func() {
 				requestLatency = 50 * time.Millisecond
 				fakeClient.StopContainerStub = func(logger lager.Logger, guid string) error {
-					time.Sleep(requestLatency)
+					fakeClock.Increment(requestLatency)
 					return nil
 				}
 			})
@@ -102,7 +102,7 @@ var _ = Describe("StopLRPInstanceHandler", func() {
 				Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
 				calledRequestType, calledLatency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
 				Expect(calledRequestType).To(Equal("StopLRPInstance"))
-				Expect(calledLatency).To(BeNumerically("~", requestLatency, 25*time.Millisecond))
+				Expect(calledLatency).To(Equal(requestLatency))
 
 				Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
 				calledRequestType, delta = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+// maxConcurrentStops bounds how many containers a batch stops at once, so a
+// large batch does not flood garden with concurrent requests.
+const maxConcurrentStops = 20
+
+type stopLRPInstancesHandler struct {
+	client  executor.Client
+	metrics helpers.RequestMetrics
+	clock   clock.Clock
+}
+
+// Stop LRP Instances Handler stops a batch of LRP instances and responds with
+// the outcome for each of them
+func newStopLRPInstancesHandler(client executor.Client, metrics helpers.RequestMetrics, clock clock.Clock) *stopLRPInstancesHandler {
+	return &stopLRPInstancesHandler{
+		client:  client,
+		metrics: metrics,
+		clock:   clock,
+	}
+}
+
+func (h *stopLRPInstancesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
+	var deferErr error
+
+	start := h.clock.Now()
+	requestType := "StopLRPInstances"
+	startMetrics(h.metrics, requestType)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
+
+	logger = logger.Session("handling-stop-lrp-instances")
+
+	var instances []rep.StopLRPInstanceRequest
+	deferErr = json.NewDecoder(r.Body).Decode(&instances)
+	if deferErr != nil {
+		logger.Error("failed-to-unmarshal", deferErr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	logger.Info("stopping", lager.Data{"num-instances": len(instances)})
+
+	results := make([]rep.StopLRPInstanceResult, len(instances))
+	semaphore := make(chan struct{}, maxConcurrentStops)
+	wg := sync.WaitGroup{}
+	for i := range instances {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i] = h.stop(logger, instances[i])
+		}(i)
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func (h *stopLRPInstancesHandler) stop(logger lager.Logger, instance rep.StopLRPInstanceRequest) rep.StopLRPInstanceResult {
+	result := rep.StopLRPInstanceResult{
+		ProcessGuid:  instance.ProcessGuid,
+		InstanceGuid: instance.InstanceGuid,
+	}
+
+	if instance.ProcessGuid == "" {
+		result.Error = "process_guid missing from request"
+		return result
+	}
+	if instance.InstanceGuid == "" {
+		result.Error = "instance_guid missing from request"
+		return result
+	}
+
+	err := h.client.StopContainer(logger, rep.LRPContainerGuid(instance.ProcessGuid, instance.InstanceGuid))
+	if err != nil {
+		logger.Error("failed-to-stop-container", err, lager.Data{
+			"process-guid":  instance.ProcessGuid,
+			"instance-guid": instance.InstanceGuid,
+		})
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StopLRPInstancesHandler", func() {
+	var instances []rep.StopLRPInstanceRequest
+
+	BeforeEach(func() {
+		instances = []rep.StopLRPInstanceRequest{
+			rep.NewStopLRPInstanceRequest(models.NewActualLRPKey("pg-1", 0, "domain"), models.NewActualLRPInstanceKey("ig-1", "cell-id")),
+			rep.NewStopLRPInstanceRequest(models.NewActualLRPKey("pg-1", 1, "domain"), models.NewActualLRPInstanceKey("ig-2", "cell-id")),
+			rep.NewStopLRPInstanceRequest(models.NewActualLRPKey("pg-2", 0, "domain"), models.NewActualLRPInstanceKey("", "cell-id")),
+		}
+
+		fakeExecutorClient.StopContainerStub = func(_ lager.Logger, guid string) error {
+			if guid == rep.LRPContainerGuid("pg-1", "ig-2") {
+				return errors.New("boom")
+			}
+			return nil
+		}
+	})
+
+	It("stops every instance and responds with the outcome for each of them", func() {
+		status, body := Request(rep.StopLRPInstancesRoute, nil, bytes.NewBufferString(JSONFor(instances)))
+		Expect(status).To(Equal(http.StatusOK))
+
+		var results []rep.StopLRPInstanceResult
+		Expect(json.Unmarshal(body, &results)).To(Succeed())
+		Expect(results).To(Equal([]rep.StopLRPInstanceResult{
+			{ProcessGuid: "pg-1", InstanceGuid: "ig-1"},
+			{ProcessGuid: "pg-1", InstanceGuid: "ig-2", Error: "boom"},
+			{ProcessGuid: "pg-2", InstanceGuid: "", Error: "instance_guid missing from request"},
+		}))
+
+		Expect(fakeExecutorClient.StopContainerCallCount()).To(Equal(2))
+		stopped := []string{}
+		for i := 0; i < fakeExecutorClient.StopContainerCallCount(); i++ {
+			_, guid := fakeExecutorClient.StopContainerArgsForCall(i)
+			stopped = append(stopped, guid)
+		}
+		Expect(stopped).To(ConsistOf(rep.LRPContainerGuid("pg-1", "ig-1"), rep.LRPContainerGuid("pg-1", "ig-2")))
+	})
+
+	It("emits the request metrics", func() {
+		Request(rep.StopLRPInstancesRoute, nil, bytes.NewBufferString(JSONFor(instances)))
+
+		Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+		calledRequestType, _ := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+		Expect(calledRequestType).To(Equal("StopLRPInstances"))
+
+		Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+	})
+
+	Context("when the request cannot be decoded", func() {
+		It("responds with 400 Bad Request", func() {
+			status, _ := Request(rep.StopLRPInstancesRoute, nil, bytes.NewBufferString("garbage"))
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(fakeExecutorClient.StopContainerCallCount()).To(BeZero())
+		})
+	})
+})
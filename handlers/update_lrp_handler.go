@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/locket/metrics/helpers"
@@ -16,23 +16,25 @@ import (
 type UpdateLRPInstanceHandler struct {
 	client  executor.Client
 	metrics helpers.RequestMetrics
+	clock   clock.Clock
 }
 
 // This is public for testing purpose
-func NewUpdateLRPInstanceHandler(client executor.Client, metrics helpers.RequestMetrics) *UpdateLRPInstanceHandler {
+func NewUpdateLRPInstanceHandler(client executor.Client, metrics helpers.RequestMetrics, clock clock.Clock) *UpdateLRPInstanceHandler {
 	return &UpdateLRPInstanceHandler{
 		client:  client,
 		metrics: metrics,
+		clock:   clock,
 	}
 }
 
 func (h *UpdateLRPInstanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request, logger lager.Logger) {
 	var deferErr error
 
-	start := time.Now()
+	start := h.clock.Now()
 	requestType := "UpdateLRPInstance"
 	startMetrics(h.metrics, requestType)
-	defer stopMetrics(h.metrics, requestType, start, &deferErr)
+	defer stopMetrics(h.metrics, h.clock, requestType, start, &deferErr)
 
 	processGuid := r.FormValue(":process_guid")
 	instanceGuid := r.FormValue(":instance_guid")
@@ -43,7 +43,7 @@ var _ = Describe("UpdateLRPInstanceHandler", func() {
 		logger = lagertest.NewTestLogger("test")
 		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
 
-		updateInstanceHandler = handlers.NewUpdateLRPInstanceHandler(fakeClient, fakeRequestMetrics)
+		updateInstanceHandler = handlers.NewUpdateLRPInstanceHandler(fakeClient, fakeRequestMetrics, fakeClock)
 
 		resp = httptest.NewRecorder()
 
@@ -78,7 +78,7 @@ var _ = Describe("UpdateLRPInstanceHandler", func() {
 			BeforeEach(func() {
 				requestLatency = 50 * time.Millisecond
 				fakeClient.UpdateContainerStub = func(logger lager.Logger, updateReq *executor.UpdateRequest) error {
-					time.Sleep(requestLatency)
+					fakeClock.Increment(requestLatency)
 					return nil
 				}
 			})
@@ -115,7 +115,7 @@ var _ = Describe("UpdateLRPInstanceHandler", func() {
 				Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
 				calledRequestType, calledLatency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
 				Expect(calledRequestType).To(Equal("UpdateLRPInstance"))
-				Expect(calledLatency).To(BeNumerically("~", requestLatency, 25*time.Millisecond))
+				Expect(calledLatency).To(Equal(requestLatency))
 
 				Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
 				calledRequestType, delta = fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
@@ -0,0 +1,61 @@
+package harmonizer
+
+import (
+	"fmt"
+	"time"
+)
+
+// busySyncFraction is the fraction of the interval a sync may take before
+// the BBS is considered slow.
+const busySyncFraction = 10
+
+// AdaptiveInterval adjusts the interval between bulk syncs to the load of
+// the cell. The interval halves, down to min, after a quick sync that found
+// work, so that changes to the work of the cell are reconciled sooner, and
+// doubles, up to max, after any other sync: one that found nothing to do, so
+// that a settled cell polls the BBS less, and one that found operations of
+// the previous sync still pending or took longer than a tenth of the
+// interval, so that a busy cell or BBS is not polled harder.
+type AdaptiveInterval struct {
+	min   time.Duration
+	max   time.Duration
+	queue *PendingQueue
+}
+
+// NewAdaptiveInterval returns an error when min is above max.
+func NewAdaptiveInterval(min, max time.Duration, queue *PendingQueue) (*AdaptiveInterval, error) {
+	if min > max {
+		return nil, fmt.Errorf("min interval %s is above max interval %s", min, max)
+	}
+	return &AdaptiveInterval{
+		min:   min,
+		max:   max,
+		queue: queue,
+	}, nil
+}
+
+// Backlog returns the number of containers with an operation still pending.
+func (a *AdaptiveInterval) Backlog() int {
+	return a.queue.Pending()
+}
+
+// Clamp returns interval bounded by min and max.
+func (a *AdaptiveInterval) Clamp(interval time.Duration) time.Duration {
+	if interval < a.min {
+		return a.min
+	}
+	if interval > a.max {
+		return a.max
+	}
+	return interval
+}
+
+// Next returns the interval to wait after a sync that took syncDuration,
+// started with backlog operations pending and found work for the cell, given
+// the current interval.
+func (a *AdaptiveInterval) Next(current, syncDuration time.Duration, backlog, work int) time.Duration {
+	if backlog > 0 || syncDuration > current/busySyncFraction || work == 0 {
+		return a.Clamp(current * 2)
+	}
+	return a.Clamp(current / 2)
+}
@@ -0,0 +1,54 @@
+package harmonizer_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/operationq/fake_operationq"
+	"code.cloudfoundry.org/rep/harmonizer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AdaptiveInterval", func() {
+	var interval *harmonizer.AdaptiveInterval
+
+	BeforeEach(func() {
+		var err error
+		interval, err = harmonizer.NewAdaptiveInterval(10*time.Second, time.Minute, harmonizer.NewPendingQueue(new(fake_operationq.FakeQueue)))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects a min above its max", func() {
+		_, err := harmonizer.NewAdaptiveInterval(time.Minute, 10*time.Second, harmonizer.NewPendingQueue(new(fake_operationq.FakeQueue)))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("halves the interval after a quick sync that found work with nothing pending", func() {
+		Expect(interval.Next(40*time.Second, time.Second, 0, 1)).To(Equal(20 * time.Second))
+		Expect(interval.Next(15*time.Second, time.Second/2, 0, 2)).To(Equal(10 * time.Second))
+	})
+
+	It("doubles the interval after a quick sync that found no work", func() {
+		Expect(interval.Next(20*time.Second, time.Second, 0, 0)).To(Equal(40 * time.Second))
+		Expect(interval.Next(40*time.Second, time.Second, 0, 0)).To(Equal(time.Minute))
+	})
+
+	It("doubles the interval after a sync that took over a tenth of it", func() {
+		Expect(interval.Next(20*time.Second, 3*time.Second, 0, 1)).To(Equal(40 * time.Second))
+		Expect(interval.Next(40*time.Second, 5*time.Second, 0, 1)).To(Equal(time.Minute))
+	})
+
+	It("doubles the interval when operations are still pending", func() {
+		Expect(interval.Next(20*time.Second, 0, 3, 1)).To(Equal(40 * time.Second))
+	})
+
+	It("clamps intervals to its bounds", func() {
+		Expect(interval.Clamp(time.Second)).To(Equal(10 * time.Second))
+		Expect(interval.Clamp(30 * time.Second)).To(Equal(30 * time.Second))
+		Expect(interval.Clamp(time.Hour)).To(Equal(time.Minute))
+	})
+
+	It("reports the operations pending on its queue", func() {
+		Expect(interval.Backlog()).To(BeZero())
+	})
+})
@@ -6,46 +6,61 @@ import (
 
 	"code.cloudfoundry.org/clock"
 	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/operationq"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
 	"code.cloudfoundry.org/rep/generator"
 )
 
-const repBulkSyncDuration = "RepBulkSyncDuration"
+const (
+	repBulkSyncDuration = "RepBulkSyncDuration"
+	repBulkSyncInterval = "RepBulkSyncInterval"
+)
 
 type Bulker struct {
 	logger lager.Logger
 
 	pollInterval           time.Duration
 	evacuationPollInterval time.Duration
+	adaptiveInterval       *AdaptiveInterval
 	evacuationNotifier     evacuation_context.EvacuationNotifier
 	clock                  clock.Clock
 	generator              generator.Generator
 	queue                  operationq.Queue
 	metronClient           loggingclient.IngressClient
+	stateConverter         auctioncellrep.ContainerStateConverter
+
+	lastState      *rep.CellState
+	lastOperations map[string]struct{}
 }
 
 func NewBulker(
 	logger lager.Logger,
 	pollInterval time.Duration,
 	evacuationPollInterval time.Duration,
+	adaptiveInterval *AdaptiveInterval,
 	evacuationNotifier evacuation_context.EvacuationNotifier,
 	clock clock.Clock,
 	generator generator.Generator,
 	queue operationq.Queue,
 	metronClient loggingclient.IngressClient,
+	stateConverter auctioncellrep.ContainerStateConverter,
 ) *Bulker {
 	return &Bulker{
 		logger: logger,
 
 		pollInterval:           pollInterval,
 		evacuationPollInterval: evacuationPollInterval,
+		adaptiveInterval:       adaptiveInterval,
 		evacuationNotifier:     evacuationNotifier,
 		clock:                  clock,
 		generator:              generator,
 		queue:                  queue,
 		metronClient:           metronClient,
+		stateConverter:         stateConverter,
 	}
 }
 
@@ -55,13 +70,16 @@ func (b *Bulker) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 
 	logger := b.logger.Session("running-bulker")
 
+	interval := b.pollInterval
+	if b.adaptiveInterval != nil {
+		interval = b.adaptiveInterval.Clamp(interval)
+	}
+
 	logger.Info("starting", lager.Data{
-		"interval": b.pollInterval.String(),
+		"interval": interval.String(),
 	})
 	defer logger.Info("finished")
 
-	interval := b.pollInterval
-
 	timer := b.clock.NewTimer(interval)
 	defer timer.Stop()
 
@@ -81,12 +99,44 @@ func (b *Bulker) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 			return nil
 		}
 
-		b.sync(logger)
+		backlog := 0
+		if b.adaptiveInterval != nil {
+			backlog = b.adaptiveInterval.Backlog()
+		}
+
+		syncDuration, work := b.sync(logger)
+
+		// the evacuation poll interval stays fixed so that evacuation is not
+		// slowed down by a busy cell
+		if b.adaptiveInterval != nil && evacuateNotify != nil {
+			interval = b.adapt(logger, interval, syncDuration, backlog, work)
+		}
 		timer.Reset(interval)
 	}
 }
 
-func (b *Bulker) sync(logger lager.Logger) {
+func (b *Bulker) adapt(logger lager.Logger, interval, syncDuration time.Duration, backlog, work int) time.Duration {
+	next := b.adaptiveInterval.Next(interval, syncDuration, backlog, work)
+	if next != interval {
+		logger.Info("adjusted-interval", lager.Data{
+			"interval":      next.String(),
+			"sync-duration": syncDuration.String(),
+			"backlog":       backlog,
+			"work":          work,
+		})
+	}
+
+	err := b.metronClient.SendDuration(repBulkSyncInterval, next)
+	if err != nil {
+		logger.Error("failed-to-send-rep-bulk-sync-interval-metric", err)
+	}
+	return next
+}
+
+// sync returns how long the sync took and the work it found: the containers,
+// LRPs and tasks it generated operations for that the previous sync did not,
+// or the other way around. A failed sync finds no work.
+func (b *Bulker) sync(logger lager.Logger) (time.Duration, int) {
 	logger = logger.Session("sync")
 
 	logger.Info("starting")
@@ -94,21 +144,56 @@ func (b *Bulker) sync(logger lager.Logger) {
 
 	startTime := b.clock.Now()
 
-	ops, batchError := b.generator.BatchOperations(logger)
+	ops, containers, batchError := b.generator.BatchOperations(logger)
 
 	endTime := b.clock.Now()
+	duration := endTime.Sub(startTime)
 
-	sendError := b.metronClient.SendDuration(repBulkSyncDuration, endTime.Sub(startTime))
+	sendError := b.metronClient.SendDuration(repBulkSyncDuration, duration)
 	if sendError != nil {
 		logger.Error("failed-to-send-rep-bulk-sync-duration-metric", sendError)
 	}
 
 	if batchError != nil {
 		logger.Error("failed-to-generate-operations", batchError)
-		return
+		return duration, 0
 	}
 
-	for _, operation := range ops {
+	keys := make(map[string]struct{}, len(ops))
+	work := 0
+	for key, operation := range ops {
 		b.queue.Push(operation)
+		keys[key] = struct{}{}
+		if _, ok := b.lastOperations[key]; !ok {
+			work++
+		}
+	}
+	for key := range b.lastOperations {
+		if _, ok := keys[key]; !ok {
+			work++
+		}
+	}
+	b.lastOperations = keys
+
+	if b.stateConverter != nil {
+		b.logStateChanges(logger, containers)
+	}
+	return duration, work
+}
+
+// logStateChanges logs how the LRPs and tasks of the cell changed since the
+// previous sync instead of the whole state, so that the drift of the state
+// can be followed without logging every LRP instance and task at every sync.
+// The state is converted from the containers the sync listed, so working it
+// out costs the executor nothing.
+func (b *Bulker) logStateChanges(logger lager.Logger, containers []executor.Container) {
+	state := b.stateConverter.ContainerState(logger, containers)
+
+	if b.lastState != nil {
+		changes := rep.NewCellStateDelta("", *b.lastState, state).Changes(*b.lastState)
+		if !changes.Empty() {
+			logger.Info("state-changed", lager.Data{"changes": changes})
+		}
 	}
+	b.lastState = &state
 }
@@ -7,10 +7,13 @@ import (
 
 	"code.cloudfoundry.org/clock/fakeclock"
 	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"code.cloudfoundry.org/operationq"
 	"code.cloudfoundry.org/operationq/fake_operationq"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
 	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
 	"code.cloudfoundry.org/rep/generator/fake_generator"
 	"code.cloudfoundry.org/rep/harmonizer"
@@ -28,9 +31,12 @@ var _ = Describe("Bulker", func() {
 		fakeClock              *fakeclock.FakeClock
 		fakeGenerator          *fake_generator.FakeGenerator
 		fakeQueue              *fake_operationq.FakeQueue
+		queue                  operationq.Queue
+		adaptiveInterval       *harmonizer.AdaptiveInterval
 		evacuatable            evacuation_context.Evacuatable
 		evacuationNotifier     evacuation_context.EvacuationNotifier
 		fakeMetronClient       *mfakes.FakeIngressClient
+		stateConverter         auctioncellrep.ContainerStateConverter
 
 		bulker  *harmonizer.Bulker
 		process ifrit.Process
@@ -44,22 +50,26 @@ var _ = Describe("Bulker", func() {
 		fakeGenerator = new(fake_generator.FakeGenerator)
 		fakeQueue = new(fake_operationq.FakeQueue)
 		fakeMetronClient = new(mfakes.FakeIngressClient)
+		queue = fakeQueue
+		adaptiveInterval = nil
+		stateConverter = nil
 
 		evacuatable, _, evacuationNotifier = evacuation_context.New()
+	})
 
+	JustBeforeEach(func() {
 		bulker = harmonizer.NewBulker(
 			logger,
 			pollInterval,
 			evacuationPollInterval,
+			adaptiveInterval,
 			evacuationNotifier,
 			fakeClock,
 			fakeGenerator,
-			fakeQueue,
+			queue,
 			fakeMetronClient,
+			stateConverter,
 		)
-	})
-
-	JustBeforeEach(func() {
 		process = ifrit.Invoke(bulker)
 		Eventually(fakeClock.WatcherCount).Should(Equal(1))
 	})
@@ -80,9 +90,9 @@ var _ = Describe("Bulker", func() {
 				operation1 = new(fake_operationq.FakeOperation)
 				operation2 = new(fake_operationq.FakeOperation)
 
-				fakeGenerator.BatchOperationsStub = func(lager.Logger) (map[string]operationq.Operation, error) {
+				fakeGenerator.BatchOperationsStub = func(lager.Logger) (map[string]operationq.Operation, []executor.Container, error) {
 					fakeClock.Increment(10 * time.Second)
-					return map[string]operationq.Operation{"guid1": operation1, "guid2": operation2}, nil
+					return map[string]operationq.Operation{"guid1": operation1, "guid2": operation2}, nil, nil
 				}
 			})
 
@@ -109,7 +119,7 @@ var _ = Describe("Bulker", func() {
 			disaster := errors.New("nope")
 
 			BeforeEach(func() {
-				fakeGenerator.BatchOperationsReturns(nil, disaster)
+				fakeGenerator.BatchOperationsReturns(nil, nil, disaster)
 			})
 
 			It("logs the error", func() {
@@ -145,6 +155,111 @@ var _ = Describe("Bulker", func() {
 		})
 	})
 
+	Context("with an adaptive interval", func() {
+		var operation *fake_operationq.FakeOperation
+
+		syncInterval := func(call int) time.Duration {
+			name, value, _ := fakeMetronClient.SendDurationArgsForCall(call)
+			Expect(name).To(Equal("RepBulkSyncInterval"))
+			return value
+		}
+
+		BeforeEach(func() {
+			pendingQueue := harmonizer.NewPendingQueue(fakeQueue)
+			queue = pendingQueue
+			var err error
+			adaptiveInterval, err = harmonizer.NewAdaptiveInterval(10*time.Second, 2*time.Minute, pendingQueue)
+			Expect(err).NotTo(HaveOccurred())
+
+			operation = new(fake_operationq.FakeOperation)
+			operation.KeyReturns("guid1")
+			fakeGenerator.BatchOperationsReturns(map[string]operationq.Operation{}, nil, nil)
+		})
+
+		JustBeforeEach(func() {
+			fakeClock.WaitForWatcherAndIncrement(pollInterval)
+		})
+
+		It("doubles the interval after a quick sync that found nothing to do", func() {
+			Eventually(fakeMetronClient.SendDurationCallCount).Should(Equal(2))
+			Expect(syncInterval(1)).To(Equal(time.Minute))
+
+			fakeClock.WaitForWatcherAndIncrement(time.Minute)
+			Eventually(fakeGenerator.BatchOperationsCallCount).Should(Equal(2))
+		})
+
+		Context("when the sync finds work", func() {
+			BeforeEach(func() {
+				fakeGenerator.BatchOperationsReturnsOnCall(0, map[string]operationq.Operation{"guid1": operation}, nil, nil)
+			})
+
+			It("halves the interval", func() {
+				Eventually(fakeMetronClient.SendDurationCallCount).Should(Equal(2))
+				Expect(syncInterval(1)).To(Equal(15 * time.Second))
+
+				fakeClock.WaitForWatcherAndIncrement(15 * time.Second)
+				Eventually(fakeGenerator.BatchOperationsCallCount).Should(Equal(2))
+			})
+		})
+
+		Context("when the sync is slow", func() {
+			BeforeEach(func() {
+				fakeGenerator.BatchOperationsStub = func(lager.Logger) (map[string]operationq.Operation, []executor.Container, error) {
+					fakeClock.Increment(10 * time.Second)
+					return map[string]operationq.Operation{}, nil, nil
+				}
+			})
+
+			It("doubles the interval", func() {
+				Eventually(fakeMetronClient.SendDurationCallCount).Should(Equal(2))
+				Expect(syncInterval(1)).To(Equal(time.Minute))
+				Eventually(logger).Should(gbytes.Say("adjusted-interval"))
+			})
+		})
+
+		Context("when the operations of the previous sync are still pending", func() {
+			BeforeEach(func() {
+				fakeGenerator.BatchOperationsReturns(map[string]operationq.Operation{"guid1": operation}, nil, nil)
+			})
+
+			It("doubles the interval", func() {
+				Eventually(fakeMetronClient.SendDurationCallCount).Should(Equal(2))
+				Expect(syncInterval(1)).To(Equal(15 * time.Second))
+
+				fakeClock.WaitForWatcherAndIncrement(15 * time.Second)
+				Eventually(fakeMetronClient.SendDurationCallCount).Should(Equal(4))
+				Expect(syncInterval(3)).To(Equal(30 * time.Second))
+			})
+		})
+	})
+
+	Context("with a state converter", func() {
+		BeforeEach(func() {
+			stateConverter = containerStateConverter(func(_ lager.Logger, containers []executor.Container) rep.CellState {
+				state := rep.CellState{CellID: "cell-id"}
+				for _, container := range containers {
+					state.LRPs = append(state.LRPs, rep.LRP{InstanceGUID: container.Guid})
+				}
+				return state
+			})
+			fakeGenerator.BatchOperationsReturnsOnCall(0, map[string]operationq.Operation{}, []executor.Container{{Guid: "ig-1"}}, nil)
+			fakeGenerator.BatchOperationsReturnsOnCall(1, map[string]operationq.Operation{}, []executor.Container{{Guid: "ig-1"}}, nil)
+			fakeGenerator.BatchOperationsReturnsOnCall(2, map[string]operationq.Operation{}, []executor.Container{{Guid: "ig-1"}, {Guid: "ig-2"}}, nil)
+		})
+
+		It("logs how the state of the listed containers changed since the previous sync", func() {
+			fakeClock.WaitForWatcherAndIncrement(pollInterval)
+			Eventually(fakeGenerator.BatchOperationsCallCount).Should(Equal(1))
+
+			fakeClock.WaitForWatcherAndIncrement(pollInterval)
+			Eventually(fakeGenerator.BatchOperationsCallCount).Should(Equal(2))
+			Consistently(logger).ShouldNot(gbytes.Say("state-changed"))
+
+			fakeClock.WaitForWatcherAndIncrement(pollInterval)
+			Eventually(logger).Should(gbytes.Say(`state-changed.*"added_lrps":\["ig-2"\]`))
+		})
+	})
+
 	Context("when evacuation starts", func() {
 		BeforeEach(func() {
 			evacuatable.Evacuate()
@@ -167,3 +282,9 @@ var _ = Describe("Bulker", func() {
 		})
 	})
 })
+
+type containerStateConverter func(logger lager.Logger, containers []executor.Container) rep.CellState
+
+func (f containerStateConverter) ContainerState(logger lager.Logger, containers []executor.Container) rep.CellState {
+	return f(logger, containers)
+}
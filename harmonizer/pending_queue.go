@@ -0,0 +1,78 @@
+package harmonizer
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/operationq"
+)
+
+// PendingQueue is an operationq.Queue that counts the containers with an
+// operation waiting to be executed, and the operations executing. An
+// operation replaced by a newer one for the same container before it ran is
+// not counted twice.
+type PendingQueue struct {
+	queue operationq.Queue
+
+	lock      sync.Mutex
+	pending   map[string]*pendingOperation
+	executing int
+}
+
+func NewPendingQueue(queue operationq.Queue) *PendingQueue {
+	return &PendingQueue{
+		queue:   queue,
+		pending: map[string]*pendingOperation{},
+	}
+}
+
+func (q *PendingQueue) Push(operation operationq.Operation) {
+	op := &pendingOperation{Operation: operation, queue: q}
+
+	q.lock.Lock()
+	q.pending[operation.Key()] = op
+	q.lock.Unlock()
+
+	q.queue.Push(op)
+}
+
+// Pending returns the number of containers with an operation waiting to be
+// executed.
+func (q *PendingQueue) Pending() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.pending)
+}
+
+// Executing returns the number of operations executing. The operations of
+// different containers execute at once, with no bound on how many.
+func (q *PendingQueue) Executing() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.executing
+}
+
+func (q *PendingQueue) started(op *pendingOperation) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.pending[op.Key()] == op {
+		delete(q.pending, op.Key())
+	}
+	q.executing++
+}
+
+func (q *PendingQueue) finished() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.executing--
+}
+
+type pendingOperation struct {
+	operationq.Operation
+	queue *PendingQueue
+}
+
+func (o *pendingOperation) Execute() {
+	o.queue.started(o)
+	defer o.queue.finished()
+	o.Operation.Execute()
+}
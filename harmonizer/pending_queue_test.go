@@ -0,0 +1,71 @@
+package harmonizer_test
+
+import (
+	"code.cloudfoundry.org/operationq/fake_operationq"
+	"code.cloudfoundry.org/rep/harmonizer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PendingQueue", func() {
+	var (
+		fakeQueue *fake_operationq.FakeQueue
+		queue     *harmonizer.PendingQueue
+	)
+
+	newOperation := func(key string) *fake_operationq.FakeOperation {
+		operation := new(fake_operationq.FakeOperation)
+		operation.KeyReturns(key)
+		return operation
+	}
+
+	BeforeEach(func() {
+		fakeQueue = new(fake_operationq.FakeQueue)
+		queue = harmonizer.NewPendingQueue(fakeQueue)
+	})
+
+	It("counts the containers with an operation waiting to be executed", func() {
+		queue.Push(newOperation("guid1"))
+		queue.Push(newOperation("guid2"))
+		Expect(queue.Pending()).To(Equal(2))
+		Expect(fakeQueue.PushCallCount()).To(Equal(2))
+
+		fakeQueue.PushArgsForCall(0).Execute()
+		Expect(queue.Pending()).To(Equal(1))
+	})
+
+	It("executes the pushed operation", func() {
+		operation := newOperation("guid1")
+		queue.Push(operation)
+
+		fakeQueue.PushArgsForCall(0).Execute()
+		Expect(operation.ExecuteCallCount()).To(Equal(1))
+	})
+
+	It("counts the operations executing", func() {
+		operation := newOperation("guid1")
+		operation.ExecuteStub = func() {
+			Expect(queue.Executing()).To(Equal(1))
+		}
+		queue.Push(operation)
+		Expect(queue.Executing()).To(BeZero())
+
+		fakeQueue.PushArgsForCall(0).Execute()
+		Expect(operation.ExecuteCallCount()).To(Equal(1))
+		Expect(queue.Executing()).To(BeZero())
+	})
+
+	Context("when an operation is replaced before it runs", func() {
+		It("counts the container once until the newest operation runs", func() {
+			queue.Push(newOperation("guid1"))
+			queue.Push(newOperation("guid1"))
+			Expect(queue.Pending()).To(Equal(1))
+
+			fakeQueue.PushArgsForCall(0).Execute()
+			Expect(queue.Pending()).To(Equal(1))
+
+			fakeQueue.PushArgsForCall(1).Execute()
+			Expect(queue.Pending()).To(BeZero())
+		})
+	})
+})
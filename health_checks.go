@@ -0,0 +1,177 @@
+package rep
+
+import (
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+)
+
+// HealthCheckRelaxationTag records on the container of an LRP instance the
+// relaxation its health checks run with.
+const HealthCheckRelaxationTag = "health-check-relaxation"
+
+var ErrInvalidHealthCheckRelaxation = errors.New("a health check relaxation needs a process guid, a positive ttl, and either to suspend the checks or a timeout or interval to relax them to")
+
+// The types of HealthCheck. A monitor check is the action the LRP declared
+// for the checks of its instances before it declared them as a check
+// definition, whose port and timings the cell does not know.
+const (
+	HealthCheckTypeTCP     = "tcp"
+	HealthCheckTypeHTTP    = "http"
+	HealthCheckTypeMonitor = "monitor"
+)
+
+// HealthCheck is one of the checks the executor runs against an LRP instance
+// to tell whether it is healthy.
+type HealthCheck struct {
+	Type       string `json:"type"`
+	Port       uint32 `json:"port,omitempty"`
+	Path       string `json:"path,omitempty"`
+	IntervalMs uint64 `json:"interval_ms,omitempty"`
+	TimeoutMs  uint64 `json:"timeout_ms,omitempty"`
+}
+
+// ContainerHealthCheck is how the container of an LRP instance is checked,
+// along with the relaxation, if any, that applies to the instance.
+type ContainerHealthCheck struct {
+	Checks     []HealthCheck          `json:"checks"`
+	Relaxation *HealthCheckRelaxation `json:"relaxation,omitempty"`
+}
+
+// HealthChecksOf returns the checks runInfo has the executor run: those of
+// its check definition, or its monitor action when it has no check
+// definition.
+func HealthChecksOf(runInfo executor.RunInfo) []HealthCheck {
+	checks := []HealthCheck{}
+	if runInfo.CheckDefinition != nil && len(runInfo.CheckDefinition.Checks) > 0 {
+		for _, check := range runInfo.CheckDefinition.Checks {
+			switch {
+			case check.TcpCheck != nil:
+				checks = append(checks, HealthCheck{
+					Type:       HealthCheckTypeTCP,
+					Port:       check.TcpCheck.Port,
+					IntervalMs: check.TcpCheck.IntervalMs,
+					TimeoutMs:  check.TcpCheck.ConnectTimeoutMs,
+				})
+			case check.HttpCheck != nil:
+				checks = append(checks, HealthCheck{
+					Type:       HealthCheckTypeHTTP,
+					Port:       check.HttpCheck.Port,
+					Path:       check.HttpCheck.Path,
+					IntervalMs: check.HttpCheck.IntervalMs,
+					TimeoutMs:  check.HttpCheck.RequestTimeoutMs,
+				})
+			}
+		}
+		return checks
+	}
+	if runInfo.Monitor != nil {
+		checks = append(checks, HealthCheck{Type: HealthCheckTypeMonitor})
+	}
+	return checks
+}
+
+// HealthCheckRelaxationRequest asks a cell to relax the health checks of the
+// instance at Index of ProcessGuid for TTLSeconds, either suspending them or
+// relaxing the timeout and interval of those of its check definition to
+// TimeoutMs and IntervalMs, where they are given and larger.
+type HealthCheckRelaxationRequest struct {
+	ProcessGuid string `json:"process_guid"`
+	Index       int32  `json:"index"`
+	Suspend     bool   `json:"suspend,omitempty"`
+	TimeoutMs   uint64 `json:"timeout_ms,omitempty"`
+	IntervalMs  uint64 `json:"interval_ms,omitempty"`
+	TTLSeconds  int64  `json:"ttl_seconds"`
+}
+
+func (r HealthCheckRelaxationRequest) Validate() error {
+	if r.ProcessGuid == "" || r.Index < 0 || r.TTLSeconds < 1 || (!r.Suspend && r.TimeoutMs == 0 && r.IntervalMs == 0) {
+		return ErrInvalidHealthCheckRelaxation
+	}
+	return nil
+}
+
+// HealthCheckRelaxation relaxes the health checks of the containers a cell
+// runs for the instance at Index of ProcessGuid until ExpiresAt, in unix
+// nanoseconds. The executor cannot change the checks of a container once it
+// runs, so the relaxation applies to the containers the cell starts for the
+// instance while it holds, and those are stopped once it no longer does, for
+// the instance to be placed again with its own checks.
+type HealthCheckRelaxation struct {
+	ProcessGuid string `json:"process_guid"`
+	Index       int32  `json:"index"`
+	Suspend     bool   `json:"suspend,omitempty"`
+	TimeoutMs   uint64 `json:"timeout_ms,omitempty"`
+	IntervalMs  uint64 `json:"interval_ms,omitempty"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+// Apply relaxes the health checks of runInfo. Suspending them drops both its
+// check definition and its monitor action, so the executor considers the
+// instance healthy once it starts.
+func (r HealthCheckRelaxation) Apply(runInfo *executor.RunInfo) {
+	if r.Suspend {
+		runInfo.CheckDefinition = nil
+		runInfo.Monitor = nil
+		return
+	}
+	if runInfo.CheckDefinition == nil {
+		return
+	}
+
+	definition := *runInfo.CheckDefinition
+	definition.Checks = make([]*models.Check, 0, len(runInfo.CheckDefinition.Checks))
+	for _, check := range runInfo.CheckDefinition.Checks {
+		relaxed := &models.Check{}
+		if check.TcpCheck != nil {
+			tcpCheck := *check.TcpCheck
+			tcpCheck.ConnectTimeoutMs = relax(tcpCheck.ConnectTimeoutMs, r.TimeoutMs)
+			tcpCheck.IntervalMs = relax(tcpCheck.IntervalMs, r.IntervalMs)
+			relaxed.TcpCheck = &tcpCheck
+		}
+		if check.HttpCheck != nil {
+			httpCheck := *check.HttpCheck
+			httpCheck.RequestTimeoutMs = relax(httpCheck.RequestTimeoutMs, r.TimeoutMs)
+			httpCheck.IntervalMs = relax(httpCheck.IntervalMs, r.IntervalMs)
+			relaxed.HttpCheck = &httpCheck
+		}
+		definition.Checks = append(definition.Checks, relaxed)
+	}
+	runInfo.CheckDefinition = &definition
+}
+
+// relax returns relaxed when it is larger than value, so that a relaxation
+// never tightens a check.
+func relax(value, relaxed uint64) uint64 {
+	if relaxed > value {
+		return relaxed
+	}
+	return value
+}
+
+// AddHealthCheckRelaxationTag records relaxation in tags.
+func AddHealthCheckRelaxationTag(tags executor.Tags, relaxation HealthCheckRelaxation) {
+	payload, err := json.Marshal(relaxation)
+	if err != nil {
+		return
+	}
+	tags[HealthCheckRelaxationTag] = string(payload)
+}
+
+// HealthCheckRelaxationFromTags returns the relaxation recorded in tags, or nil
+// when there is none.
+func HealthCheckRelaxationFromTags(tags executor.Tags) (*HealthCheckRelaxation, error) {
+	payload, ok := tags[HealthCheckRelaxationTag]
+	if !ok {
+		return nil, nil
+	}
+
+	var relaxation HealthCheckRelaxation
+	err := json.Unmarshal([]byte(payload), &relaxation)
+	if err != nil {
+		return nil, err
+	}
+	return &relaxation, nil
+}
@@ -0,0 +1,89 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HealthChecks", func() {
+	var runInfo executor.RunInfo
+
+	BeforeEach(func() {
+		runInfo = executor.RunInfo{
+			Monitor: models.WrapAction(&models.RunAction{Path: "/bin/healthcheck", User: "vcap"}),
+			CheckDefinition: &models.CheckDefinition{
+				Checks: []*models.Check{
+					{TcpCheck: &models.TCPCheck{Port: 8080, ConnectTimeoutMs: 1000, IntervalMs: 500}},
+					{HttpCheck: &models.HTTPCheck{Port: 8081, Path: "/healthz", RequestTimeoutMs: 10000, IntervalMs: 2000}},
+				},
+			},
+		}
+	})
+
+	It("returns the checks of the check definition", func() {
+		Expect(rep.HealthChecksOf(runInfo)).To(Equal([]rep.HealthCheck{
+			{Type: rep.HealthCheckTypeTCP, Port: 8080, TimeoutMs: 1000, IntervalMs: 500},
+			{Type: rep.HealthCheckTypeHTTP, Port: 8081, Path: "/healthz", TimeoutMs: 10000, IntervalMs: 2000},
+		}))
+	})
+
+	It("returns the monitor action without a check definition", func() {
+		runInfo.CheckDefinition = nil
+		Expect(rep.HealthChecksOf(runInfo)).To(Equal([]rep.HealthCheck{{Type: rep.HealthCheckTypeMonitor}}))
+	})
+
+	It("returns no checks for an instance that is not checked", func() {
+		Expect(rep.HealthChecksOf(executor.RunInfo{})).To(BeEmpty())
+	})
+
+	Describe("HealthCheckRelaxation", func() {
+		It("drops every check when suspending them", func() {
+			rep.HealthCheckRelaxation{Suspend: true}.Apply(&runInfo)
+			Expect(runInfo.CheckDefinition).To(BeNil())
+			Expect(runInfo.Monitor).To(BeNil())
+		})
+
+		It("relaxes the timeouts and intervals without tightening them", func() {
+			definition := runInfo.CheckDefinition
+			rep.HealthCheckRelaxation{TimeoutMs: 5000, IntervalMs: 1000}.Apply(&runInfo)
+
+			Expect(rep.HealthChecksOf(runInfo)).To(Equal([]rep.HealthCheck{
+				{Type: rep.HealthCheckTypeTCP, Port: 8080, TimeoutMs: 5000, IntervalMs: 1000},
+				{Type: rep.HealthCheckTypeHTTP, Port: 8081, Path: "/healthz", TimeoutMs: 10000, IntervalMs: 2000},
+			}))
+			Expect(definition.Checks[0].TcpCheck.ConnectTimeoutMs).To(BeEquivalentTo(1000))
+		})
+
+		It("round trips through the container tags", func() {
+			relaxation := rep.HealthCheckRelaxation{ProcessGuid: "pg-1", Index: 1, Suspend: true, ExpiresAt: 1234}
+			tags := executor.Tags{}
+			rep.AddHealthCheckRelaxationTag(tags, relaxation)
+
+			decoded, err := rep.HealthCheckRelaxationFromTags(tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(&relaxation))
+
+			decoded, err = rep.HealthCheckRelaxationFromTags(executor.Tags{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(BeNil())
+
+			_, err = rep.HealthCheckRelaxationFromTags(executor.Tags{rep.HealthCheckRelaxationTag: "not-json"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("HealthCheckRelaxationRequest", func() {
+		It("needs a process guid, a ttl and something to relax", func() {
+			Expect(rep.HealthCheckRelaxationRequest{ProcessGuid: "pg", Suspend: true, TTLSeconds: 60}.Validate()).To(Succeed())
+			Expect(rep.HealthCheckRelaxationRequest{ProcessGuid: "pg", TimeoutMs: 5000, TTLSeconds: 60}.Validate()).To(Succeed())
+
+			Expect(rep.HealthCheckRelaxationRequest{Suspend: true, TTLSeconds: 60}.Validate()).To(Equal(rep.ErrInvalidHealthCheckRelaxation))
+			Expect(rep.HealthCheckRelaxationRequest{ProcessGuid: "pg", Suspend: true}.Validate()).To(Equal(rep.ErrInvalidHealthCheckRelaxation))
+			Expect(rep.HealthCheckRelaxationRequest{ProcessGuid: "pg", TTLSeconds: 60}.Validate()).To(Equal(rep.ErrInvalidHealthCheckRelaxation))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package healthchecks_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestHealthChecks(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Health Checks Suite")
+}
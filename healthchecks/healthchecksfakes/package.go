@@ -0,0 +1 @@
+package healthchecksfakes // import "code.cloudfoundry.org/rep/healthchecks/healthchecksfakes"
@@ -0,0 +1 @@
+package healthchecks // import "code.cloudfoundry.org/rep/healthchecks"
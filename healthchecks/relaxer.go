@@ -0,0 +1,149 @@
+package healthchecks
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+var ErrRelaxationNotFound = errors.New("health check relaxation not found")
+
+//go:generate counterfeiter -o healthchecksfakes/fake_relaxer.go . Relaxer
+
+// Relaxer relaxes the health checks of LRP instances for a while, so that an
+// operator can debug an instance that fails them without the executor
+// restarting it. The relaxations expire on their own after their ttl.
+type Relaxer interface {
+	// Relax records the relaxation request asks for, replacing the one the
+	// instance already had.
+	Relax(logger lager.Logger, request rep.HealthCheckRelaxationRequest) (rep.HealthCheckRelaxation, error)
+
+	// Restore drops the relaxation of the instance before it expires.
+	Restore(logger lager.Logger, processGuid string, index int32) error
+
+	// Relaxation returns the relaxation of the instance, if it has one.
+	Relaxation(processGuid string, index int32) (rep.HealthCheckRelaxation, bool)
+
+	// Relaxations returns the relaxations that have not expired.
+	Relaxations() []rep.HealthCheckRelaxation
+}
+
+type instance struct {
+	processGuid string
+	index       int32
+}
+
+// heldRelaxation is a relaxation along with when it expires on the monotonic
+// clock, so that the wall clock of the cell being stepped neither restores
+// the checks early nor keeps them relaxed longer.
+type heldRelaxation struct {
+	rep.HealthCheckRelaxation
+	expires time.Time
+}
+
+type relaxer struct {
+	clock clock.Clock
+
+	lock        sync.Mutex
+	relaxations map[instance]heldRelaxation
+}
+
+func NewRelaxer(clock clock.Clock) Relaxer {
+	return &relaxer{
+		clock:       clock,
+		relaxations: map[instance]heldRelaxation{},
+	}
+}
+
+func (r *relaxer) Relax(logger lager.Logger, request rep.HealthCheckRelaxationRequest) (rep.HealthCheckRelaxation, error) {
+	logger = logger.Session("relax-health-checks", lager.Data{"process-guid": request.ProcessGuid, "index": request.Index})
+
+	err := request.Validate()
+	if err != nil {
+		logger.Error("invalid-relaxation", err)
+		return rep.HealthCheckRelaxation{}, err
+	}
+
+	expires := r.clock.Now().Add(time.Duration(request.TTLSeconds) * time.Second)
+	relaxation := rep.HealthCheckRelaxation{
+		ProcessGuid: request.ProcessGuid,
+		Index:       request.Index,
+		Suspend:     request.Suspend,
+		TimeoutMs:   request.TimeoutMs,
+		IntervalMs:  request.IntervalMs,
+		ExpiresAt:   expires.UnixNano(),
+	}
+
+	r.lock.Lock()
+	r.relaxations[instance{processGuid: request.ProcessGuid, index: request.Index}] = heldRelaxation{HealthCheckRelaxation: relaxation, expires: expires}
+	r.lock.Unlock()
+
+	logger.Info("relaxed", lager.Data{"suspend": request.Suspend, "expires-at": relaxation.ExpiresAt})
+	return relaxation, nil
+}
+
+func (r *relaxer) Restore(logger lager.Logger, processGuid string, index int32) error {
+	now := r.clock.Now()
+	key := instance{processGuid: processGuid, index: index}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.forgetExpired(now)
+	if _, ok := r.relaxations[key]; !ok {
+		return ErrRelaxationNotFound
+	}
+	delete(r.relaxations, key)
+
+	logger.Info("restored-health-checks", lager.Data{"process-guid": processGuid, "index": index})
+	return nil
+}
+
+func (r *relaxer) Relaxation(processGuid string, index int32) (rep.HealthCheckRelaxation, bool) {
+	now := r.clock.Now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	held, ok := r.relaxations[instance{processGuid: processGuid, index: index}]
+	if !ok || !now.Before(held.expires) {
+		return rep.HealthCheckRelaxation{}, false
+	}
+	return held.HealthCheckRelaxation, true
+}
+
+func (r *relaxer) Relaxations() []rep.HealthCheckRelaxation {
+	now := r.clock.Now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.forgetExpired(now)
+
+	relaxations := make([]rep.HealthCheckRelaxation, 0, len(r.relaxations))
+	for _, held := range r.relaxations {
+		relaxations = append(relaxations, held.HealthCheckRelaxation)
+	}
+	sort.Slice(relaxations, func(i, j int) bool {
+		if relaxations[i].ProcessGuid != relaxations[j].ProcessGuid {
+			return relaxations[i].ProcessGuid < relaxations[j].ProcessGuid
+		}
+		return relaxations[i].Index < relaxations[j].Index
+	})
+	return relaxations
+}
+
+// forgetExpired drops the relaxations that are over. It must be called with
+// the lock held.
+func (r *relaxer) forgetExpired(now time.Time) {
+	for key, held := range r.relaxations {
+		if !now.Before(held.expires) {
+			delete(r.relaxations, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package healthchecks_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/healthchecks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Relaxer", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		logger    *lagertest.TestLogger
+		relaxer   healthchecks.Relaxer
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+		relaxer = healthchecks.NewRelaxer(fakeClock)
+	})
+
+	It("relaxes the health checks of an instance until the ttl is over", func() {
+		relaxation, err := relaxer.Relax(logger, rep.HealthCheckRelaxationRequest{ProcessGuid: "pg-1", Index: 1, TimeoutMs: 5000, TTLSeconds: 60})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(relaxation).To(Equal(rep.HealthCheckRelaxation{
+			ProcessGuid: "pg-1",
+			Index:       1,
+			TimeoutMs:   5000,
+			ExpiresAt:   fakeClock.Now().Add(time.Minute).UnixNano(),
+		}))
+
+		held, ok := relaxer.Relaxation("pg-1", 1)
+		Expect(ok).To(BeTrue())
+		Expect(held).To(Equal(relaxation))
+		_, ok = relaxer.Relaxation("pg-1", 0)
+		Expect(ok).To(BeFalse())
+
+		fakeClock.Increment(time.Minute)
+		_, ok = relaxer.Relaxation("pg-1", 1)
+		Expect(ok).To(BeFalse())
+		Expect(relaxer.Relaxations()).To(BeEmpty())
+	})
+
+	It("rejects an invalid relaxation", func() {
+		_, err := relaxer.Relax(logger, rep.HealthCheckRelaxationRequest{ProcessGuid: "pg-1", TTLSeconds: 60})
+		Expect(err).To(Equal(rep.ErrInvalidHealthCheckRelaxation))
+		Expect(relaxer.Relaxations()).To(BeEmpty())
+	})
+
+	It("restores the health checks of an instance before the relaxation expires", func() {
+		_, err := relaxer.Relax(logger, rep.HealthCheckRelaxationRequest{ProcessGuid: "pg-1", Suspend: true, TTLSeconds: 60})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(relaxer.Restore(logger, "pg-1", 0)).To(Succeed())
+		_, ok := relaxer.Relaxation("pg-1", 0)
+		Expect(ok).To(BeFalse())
+
+		Expect(relaxer.Restore(logger, "pg-1", 0)).To(Equal(healthchecks.ErrRelaxationNotFound))
+	})
+
+	It("lists the relaxations by process guid and index", func() {
+		for _, request := range []rep.HealthCheckRelaxationRequest{
+			{ProcessGuid: "pg-2", Index: 0, Suspend: true, TTLSeconds: 60},
+			{ProcessGuid: "pg-1", Index: 1, Suspend: true, TTLSeconds: 60},
+			{ProcessGuid: "pg-1", Index: 0, Suspend: true, TTLSeconds: 600},
+		} {
+			_, err := relaxer.Relax(logger, request)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		relaxations := relaxer.Relaxations()
+		Expect(relaxations).To(HaveLen(3))
+		Expect(relaxations[0].ProcessGuid).To(Equal("pg-1"))
+		Expect(relaxations[0].Index).To(BeEquivalentTo(0))
+		Expect(relaxations[1].Index).To(BeEquivalentTo(1))
+		Expect(relaxations[2].ProcessGuid).To(Equal("pg-2"))
+
+		fakeClock.Increment(time.Minute)
+		relaxations = relaxer.Relaxations()
+		Expect(relaxations).To(HaveLen(1))
+		Expect(relaxations[0].ProcessGuid).To(Equal("pg-1"))
+	})
+})
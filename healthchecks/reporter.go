@@ -0,0 +1,45 @@
+package healthchecks
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Reporter reports how the executor checks the LRP instances of a cell, and
+// the relaxations that apply to them.
+type Reporter struct {
+	executorClient executor.Client
+	relaxer        Relaxer
+}
+
+func NewReporter(executorClient executor.Client, relaxer Relaxer) *Reporter {
+	return &Reporter{executorClient: executorClient, relaxer: relaxer}
+}
+
+// HealthChecks returns the health checks of every LRP instance on the cell,
+// by instance guid.
+func (r *Reporter) HealthChecks(logger lager.Logger) (map[string]rep.ContainerHealthCheck, error) {
+	containers, err := r.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return nil, err
+	}
+
+	healthChecks := map[string]rep.ContainerHealthCheck{}
+	for i := range containers {
+		container := &containers[i]
+		if container.Tags[rep.LifecycleTag] != rep.LRPLifecycle {
+			continue
+		}
+
+		healthCheck := rep.ContainerHealthCheck{Checks: rep.HealthChecksOf(container.RunInfo)}
+		if key, err := rep.ActualLRPKeyFromTags(container.Tags); err == nil {
+			if relaxation, ok := r.relaxer.Relaxation(key.ProcessGuid, key.Index); ok {
+				healthCheck.Relaxation = &relaxation
+			}
+		}
+		healthChecks[container.Tags[rep.InstanceGuidTag]] = healthCheck
+	}
+	return healthChecks, nil
+}
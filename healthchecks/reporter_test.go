@@ -0,0 +1,81 @@
+package healthchecks_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+	fake_client "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"code.cloudfoundry.org/rep/healthchecks/healthchecksfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reporter", func() {
+	var (
+		logger         *lagertest.TestLogger
+		executorClient *fake_client.FakeClient
+		relaxer        *healthchecksfakes.FakeRelaxer
+		reporter       *healthchecks.Reporter
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(fake_client.FakeClient)
+		relaxer = new(healthchecksfakes.FakeRelaxer)
+		reporter = healthchecks.NewReporter(executorClient, relaxer)
+
+		lrpContainer := executor.Container{
+			Guid: "lrp-container",
+			Tags: executor.Tags{
+				rep.LifecycleTag:    rep.LRPLifecycle,
+				rep.DomainTag:       "domain",
+				rep.ProcessGuidTag:  "process-guid",
+				rep.ProcessIndexTag: "2",
+				rep.InstanceGuidTag: "instance-guid",
+			},
+		}
+		lrpContainer.CheckDefinition = &models.CheckDefinition{
+			Checks: []*models.Check{{TcpCheck: &models.TCPCheck{Port: 8080}}},
+		}
+
+		taskContainer := executor.Container{
+			Guid: "task-guid",
+			Tags: executor.Tags{rep.LifecycleTag: rep.TaskLifecycle},
+		}
+
+		executorClient.ListContainersReturns([]executor.Container{lrpContainer, taskContainer}, nil)
+	})
+
+	It("reports the health checks of every LRP instance by instance guid", func() {
+		healthChecks, err := reporter.HealthChecks(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(healthChecks).To(Equal(map[string]rep.ContainerHealthCheck{
+			"instance-guid": {Checks: []rep.HealthCheck{{Type: rep.HealthCheckTypeTCP, Port: 8080}}},
+		}))
+	})
+
+	It("attaches the relaxation of an instance", func() {
+		relaxation := rep.HealthCheckRelaxation{ProcessGuid: "process-guid", Index: 2, Suspend: true, ExpiresAt: 1234}
+		relaxer.RelaxationReturns(relaxation, true)
+
+		healthChecks, err := reporter.HealthChecks(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(healthChecks["instance-guid"].Relaxation).To(Equal(&relaxation))
+
+		Expect(relaxer.RelaxationCallCount()).To(Equal(1))
+		processGuid, index := relaxer.RelaxationArgsForCall(0)
+		Expect(processGuid).To(Equal("process-guid"))
+		Expect(index).To(BeEquivalentTo(2))
+	})
+
+	It("returns the error of listing the containers", func() {
+		executorClient.ListContainersReturns(nil, errors.New("boom"))
+		_, err := reporter.HealthChecks(logger)
+		Expect(err).To(MatchError("boom"))
+	})
+})
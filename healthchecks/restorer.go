@@ -0,0 +1,96 @@
+package healthchecks
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Restorer restores the health checks of the LRP instances of the cell once
+// their relaxation is over. The executor cannot change the checks of a
+// container once it runs, so every interval the containers that run with a
+// relaxation that has expired or been restored are stopped, for the BBS to
+// place their instances again with their own checks. A container run with a
+// relaxation that has since been replaced keeps running until no relaxation
+// holds for its instance.
+type Restorer struct {
+	logger         lager.Logger
+	executorClient executor.Client
+	relaxer        Relaxer
+	clock          clock.Clock
+	interval       time.Duration
+}
+
+func NewRestorer(logger lager.Logger, executorClient executor.Client, relaxer Relaxer, clock clock.Clock, interval time.Duration) *Restorer {
+	return &Restorer{
+		logger:         logger.Session("health-check-restorer"),
+		executorClient: executorClient,
+		relaxer:        relaxer,
+		clock:          clock,
+		interval:       interval,
+	}
+}
+
+func (r *Restorer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	ticker := r.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			r.Restore(r.logger)
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// Restore stops the containers whose relaxation is over and returns how many
+// it stopped.
+func (r *Restorer) Restore(logger lager.Logger) int {
+	logger = logger.Session("restore")
+
+	containers, err := r.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return 0
+	}
+
+	stopped := 0
+	for i := range containers {
+		container := &containers[i]
+		if container.Tags[rep.LifecycleTag] != rep.LRPLifecycle {
+			continue
+		}
+		if container.State != executor.StateCreated && container.State != executor.StateRunning {
+			continue
+		}
+
+		applied, err := rep.HealthCheckRelaxationFromTags(container.Tags)
+		if err != nil {
+			logger.Error("failed-to-decode-relaxation", err, lager.Data{"container-guid": container.Guid})
+			continue
+		}
+		if applied == nil {
+			continue
+		}
+		if _, ok := r.relaxer.Relaxation(applied.ProcessGuid, applied.Index); ok {
+			continue
+		}
+
+		logger.Info("stopping-relaxed-container", lager.Data{"container-guid": container.Guid, "process-guid": applied.ProcessGuid, "index": applied.Index})
+		err = r.executorClient.StopContainer(logger, container.Guid)
+		if err != nil {
+			logger.Error("failed-to-stop-container", err, lager.Data{"container-guid": container.Guid})
+			continue
+		}
+		stopped++
+	}
+	return stopped
+}
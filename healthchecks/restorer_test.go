@@ -0,0 +1,125 @@
+package healthchecks_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	fake_client "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/healthchecks"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Restorer", func() {
+	var (
+		fakeClock      *fakeclock.FakeClock
+		logger         *lagertest.TestLogger
+		executorClient *fake_client.FakeClient
+		relaxer        healthchecks.Relaxer
+		restorer       *healthchecks.Restorer
+
+		relaxedContainer executor.Container
+	)
+
+	lrpContainer := func(guid string, state executor.State, index string) executor.Container {
+		return executor.Container{
+			Guid:  guid,
+			State: state,
+			Tags: executor.Tags{
+				rep.LifecycleTag:    rep.LRPLifecycle,
+				rep.ProcessGuidTag:  "process-guid",
+				rep.ProcessIndexTag: index,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(fake_client.FakeClient)
+		relaxer = healthchecks.NewRelaxer(fakeClock)
+		restorer = healthchecks.NewRestorer(logger, executorClient, relaxer, fakeClock, time.Second)
+
+		relaxation, err := relaxer.Relax(logger, rep.HealthCheckRelaxationRequest{ProcessGuid: "process-guid", Index: 1, Suspend: true, TTLSeconds: 60})
+		Expect(err).NotTo(HaveOccurred())
+
+		relaxedContainer = lrpContainer("relaxed", executor.StateRunning, "1")
+		rep.AddHealthCheckRelaxationTag(relaxedContainer.Tags, relaxation)
+
+		executorClient.ListContainersReturns([]executor.Container{
+			relaxedContainer,
+			lrpContainer("unrelaxed", executor.StateRunning, "2"),
+		}, nil)
+	})
+
+	It("leaves the containers alone while their relaxation holds", func() {
+		Expect(restorer.Restore(logger)).To(Equal(0))
+		Expect(executorClient.StopContainerCallCount()).To(Equal(0))
+	})
+
+	It("stops the containers run with a relaxation that has expired", func() {
+		fakeClock.Increment(time.Minute)
+
+		Expect(restorer.Restore(logger)).To(Equal(1))
+		Expect(executorClient.StopContainerCallCount()).To(Equal(1))
+		_, guid := executorClient.StopContainerArgsForCall(0)
+		Expect(guid).To(Equal("relaxed"))
+	})
+
+	It("stops the containers run with a relaxation that has been restored", func() {
+		Expect(relaxer.Restore(logger, "process-guid", 1)).To(Succeed())
+
+		Expect(restorer.Restore(logger)).To(Equal(1))
+		_, guid := executorClient.StopContainerArgsForCall(0)
+		Expect(guid).To(Equal("relaxed"))
+	})
+
+	It("keeps the containers run with a relaxation that has been replaced", func() {
+		_, err := relaxer.Relax(logger, rep.HealthCheckRelaxationRequest{ProcessGuid: "process-guid", Index: 1, TimeoutMs: 5000, TTLSeconds: 120})
+		Expect(err).NotTo(HaveOccurred())
+		fakeClock.Increment(time.Minute)
+
+		Expect(restorer.Restore(logger)).To(Equal(0))
+	})
+
+	It("only stops containers that have been run", func() {
+		relaxedContainer.State = executor.StateCompleted
+		executorClient.ListContainersReturns([]executor.Container{relaxedContainer}, nil)
+		fakeClock.Increment(time.Minute)
+
+		Expect(restorer.Restore(logger)).To(Equal(0))
+	})
+
+	It("keeps going when a container cannot be stopped", func() {
+		other := lrpContainer("other-relaxed", executor.StateCreated, "1")
+		other.Tags[rep.HealthCheckRelaxationTag] = relaxedContainer.Tags[rep.HealthCheckRelaxationTag]
+		executorClient.ListContainersReturns([]executor.Container{relaxedContainer, other}, nil)
+		executorClient.StopContainerReturnsOnCall(0, errors.New("boom"))
+		fakeClock.Increment(time.Minute)
+
+		Expect(restorer.Restore(logger)).To(Equal(1))
+		Expect(executorClient.StopContainerCallCount()).To(Equal(2))
+		Expect(logger).To(gbytes.Say("failed-to-stop-container"))
+	})
+
+	It("restores every interval while running", func() {
+		fakeClock.Increment(time.Minute)
+		process := ifrit.Invoke(restorer)
+		defer func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+		}()
+
+		Eventually(fakeClock.WatcherCount).Should(Equal(1))
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+		Eventually(executorClient.StopContainerCallCount).Should(Equal(1))
+	})
+})
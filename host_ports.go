@@ -0,0 +1,135 @@
+package rep
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/executor"
+)
+
+// StaticHostPortsTag holds the JSON encoded static host ports an LRP
+// instance or task requested on its container, so that the cell counts them
+// as taken while the container is reserved and does not map them yet.
+const StaticHostPortsTag = "static-host-ports"
+
+// HostPortConflictError is returned for work requesting static host ports
+// that another container on the cell already holds.
+type HostPortConflictError struct {
+	Ports []uint16 `json:"ports"`
+}
+
+func (e HostPortConflictError) Error() string {
+	ports := make([]string, len(e.Ports))
+	for i, port := range e.Ports {
+		ports[i] = fmt.Sprint(port)
+	}
+	return fmt.Sprintf("the host ports %s are already allocated on the cell", strings.Join(ports, ", "))
+}
+
+// HostPortConflictFailure records the LRP instance or task of a Work that was
+// rejected because it requests host ports already allocated on the cell.
+type HostPortConflictFailure struct {
+	InstanceGUID string                `json:"instance_guid,omitempty"`
+	TaskGuid     string                `json:"task_guid,omitempty"`
+	Error        HostPortConflictError `json:"error"`
+}
+
+// HostPortsMatch returns a HostPortConflictError when res requests static
+// host ports that the LRPs or tasks of the cell hold, or the same port twice.
+func (c *CellState) HostPortsMatch(res *Resource) error {
+	if len(res.StaticHostPorts) == 0 {
+		return nil
+	}
+
+	held := map[uint16]struct{}{}
+	for i := range c.LRPs {
+		for _, port := range c.LRPs[i].StaticHostPorts {
+			held[port] = struct{}{}
+		}
+	}
+	for i := range c.Tasks {
+		for _, port := range c.Tasks[i].StaticHostPorts {
+			held[port] = struct{}{}
+		}
+	}
+
+	conflicts := []uint16{}
+	for _, port := range res.StaticHostPorts {
+		if _, ok := held[port]; ok {
+			conflicts = append(conflicts, port)
+		}
+		held[port] = struct{}{}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i] < conflicts[j] })
+	return HostPortConflictError{Ports: conflicts}
+}
+
+// AddStaticHostPortsTag records the static host ports work requested on the
+// tags of its container.
+func AddStaticHostPortsTag(tags executor.Tags, ports []uint16) {
+	if len(ports) == 0 {
+		return
+	}
+	encoded, _ := json.Marshal(ports)
+	tags[StaticHostPortsTag] = string(encoded)
+}
+
+// StaticHostPortsFromTags returns the static host ports recorded on the tags
+// of a container.
+func StaticHostPortsFromTags(tags executor.Tags) ([]uint16, error) {
+	encoded, ok := tags[StaticHostPortsTag]
+	if !ok {
+		return nil, nil
+	}
+	var ports []uint16
+	err := json.Unmarshal([]byte(encoded), &ports)
+	return ports, err
+}
+
+// MapStaticHostPorts requests the static host ports of work from the
+// executor. The ports are mapped in order to the ports of the container, and
+// those beyond its ports to the container port of the same number.
+func MapStaticHostPorts(runInfo *executor.RunInfo, ports []uint16) {
+	for i, port := range ports {
+		if i < len(runInfo.Ports) {
+			runInfo.Ports[i].HostPort = port
+			continue
+		}
+		runInfo.Ports = append(runInfo.Ports, executor.PortMapping{ContainerPort: port, HostPort: port})
+	}
+}
+
+// HostPortsOfContainer returns the host ports container holds: those its
+// port mappings map, including the ports of its TLS proxy, and those the work
+// it runs requested before it maps them.
+func HostPortsOfContainer(container executor.Container) ([]uint16, error) {
+	ports := []uint16{}
+	seen := map[uint16]struct{}{}
+	add := func(port uint16) {
+		if _, ok := seen[port]; port != 0 && !ok {
+			seen[port] = struct{}{}
+			ports = append(ports, port)
+		}
+	}
+
+	for _, mapping := range container.Ports {
+		add(mapping.HostPort)
+		add(mapping.HostTLSProxyPort)
+	}
+
+	requested, err := StaticHostPortsFromTags(container.Tags)
+	for _, port := range requested {
+		add(port)
+	}
+
+	if len(ports) == 0 {
+		return nil, err
+	}
+	return ports, err
+}
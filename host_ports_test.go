@@ -0,0 +1,111 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HostPorts", func() {
+	Describe("HostPortsMatch", func() {
+		var state rep.CellState
+
+		BeforeEach(func() {
+			lrp := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: "preloaded:cflinuxfs4"})
+			lrp.StaticHostPorts = []uint16{61001, 61002}
+			task := rep.NewTask("tg-1", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: "preloaded:cflinuxfs4"})
+			task.StaticHostPorts = []uint16{9000}
+
+			state = rep.NewCellState(
+				"cell-id", 0, "https://cell-id.cell.service.cf.internal",
+				rep.RootFSProviders{"preloaded": rep.NewFixedSetRootFSProvider("cflinuxfs4")},
+				rep.NewResources(1000, 1000, 10), rep.NewResources(1000, 1000, 10),
+				[]rep.LRP{lrp}, []rep.Task{task},
+				"z1", 0, false, nil, nil, nil, 0,
+			)
+		})
+
+		It("accepts work requesting no host ports, or free ones", func() {
+			Expect(state.HostPortsMatch(&rep.Resource{})).To(Succeed())
+			Expect(state.HostPortsMatch(&rep.Resource{StaticHostPorts: []uint16{8080, 8081}})).To(Succeed())
+		})
+
+		It("returns the host ports the containers of the cell hold, in order", func() {
+			err := state.HostPortsMatch(&rep.Resource{StaticHostPorts: []uint16{9000, 8080, 61001}})
+			Expect(err).To(Equal(rep.HostPortConflictError{Ports: []uint16{9000, 61001}}))
+			Expect(err.Error()).To(Equal("the host ports 9000, 61001 are already allocated on the cell"))
+		})
+
+		It("returns a host port requested twice", func() {
+			Expect(state.HostPortsMatch(&rep.Resource{StaticHostPorts: []uint16{8080, 8080}})).To(Equal(rep.HostPortConflictError{Ports: []uint16{8080}}))
+		})
+
+		It("rejects the LRPs and tasks whose host ports conflict, counting the work added before them", func() {
+			lrp := rep.NewLRP("ig-2", models.NewActualLRPKey("pg-2", 0, "domain"), rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: "preloaded:cflinuxfs4"})
+			lrp.StaticHostPorts = []uint16{8080}
+			Expect(state.LRPResourceMatch(&lrp)).To(Succeed())
+			state.AddLRP(&lrp)
+
+			task := rep.NewTask("tg-2", "domain", rep.NewResource(10, 10, 10), rep.PlacementConstraint{RootFs: "preloaded:cflinuxfs4"})
+			task.StaticHostPorts = []uint16{8080}
+			Expect(state.TaskResourceMatch(&task)).To(Equal(rep.HostPortConflictError{Ports: []uint16{8080}}))
+		})
+	})
+
+	Describe("HostPortsOfContainer", func() {
+		It("returns the mapped host ports and the requested static host ports", func() {
+			tags := executor.Tags{}
+			rep.AddStaticHostPortsTag(tags, []uint16{9000, 61001})
+
+			ports, err := rep.HostPortsOfContainer(executor.Container{
+				Ports: []executor.PortMapping{
+					{ContainerPort: 8080, HostPort: 61001, ContainerTLSProxyPort: 61443, HostTLSProxyPort: 61002},
+					{ContainerPort: 2222},
+				},
+				Tags: tags,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ports).To(Equal([]uint16{61001, 61002, 9000}))
+		})
+
+		It("returns no ports for a container holding none", func() {
+			ports, err := rep.HostPortsOfContainer(executor.Container{Ports: []executor.PortMapping{{ContainerPort: 8080}}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ports).To(BeNil())
+		})
+
+		It("returns an error for an unreadable tag", func() {
+			_, err := rep.HostPortsOfContainer(executor.Container{Tags: executor.Tags{rep.StaticHostPortsTag: "not-json"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("MapStaticHostPorts", func() {
+		It("maps the static host ports to the ports of the container in order", func() {
+			tags := executor.Tags{}
+			rep.AddStaticHostPortsTag(tags, []uint16{61001, 61002, 9000})
+			ports, err := rep.StaticHostPortsFromTags(tags)
+			Expect(err).NotTo(HaveOccurred())
+
+			runInfo := executor.RunInfo{Ports: []executor.PortMapping{{ContainerPort: 8080}, {ContainerPort: 2222}}}
+			rep.MapStaticHostPorts(&runInfo, ports)
+			Expect(runInfo.Ports).To(Equal([]executor.PortMapping{
+				{ContainerPort: 8080, HostPort: 61001},
+				{ContainerPort: 2222, HostPort: 61002},
+				{ContainerPort: 9000, HostPort: 9000},
+			}))
+		})
+
+		It("leaves the ports of work without static host ports to the executor", func() {
+			ports, err := rep.StaticHostPortsFromTags(executor.Tags{})
+			Expect(err).NotTo(HaveOccurred())
+
+			runInfo := executor.RunInfo{Ports: []executor.PortMapping{{ContainerPort: 8080}}}
+			rep.MapStaticHostPorts(&runInfo, ports)
+			Expect(runInfo.Ports).To(Equal([]executor.PortMapping{{ContainerPort: 8080}}))
+		})
+	})
+})
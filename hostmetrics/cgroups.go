@@ -0,0 +1,131 @@
+package hostmetrics
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const cgroupControllersFile = "cgroup.controllers"
+
+//go:generate counterfeiter -o hostmetricsfakes/fake_cgroup_inspector.go . CgroupInspector
+
+// CgroupInspector reads the cgroup hierarchy of the host and the cgroups of
+// its containers.
+type CgroupInspector interface {
+	Host(logger lager.Logger) (rep.CgroupInfo, error)
+	Container(logger lager.Logger, guid string) (rep.ContainerCgroup, error)
+}
+
+type cgroupInspector struct {
+	root             string
+	containersParent string
+}
+
+// NewCgroupInspector returns a CgroupInspector for the hierarchy mounted at
+// root, such as /sys/fs/cgroup, whose containers have their cgroups under
+// containersParent. A hierarchy with a cgroup.controllers file at its root is
+// the unified version 2 hierarchy, and any other is a version 1 hierarchy
+// with a directory per controller.
+func NewCgroupInspector(root, containersParent string) CgroupInspector {
+	return &cgroupInspector{root: root, containersParent: containersParent}
+}
+
+func (i *cgroupInspector) Host(logger lager.Logger) (rep.CgroupInfo, error) {
+	controllers, err := readControllers(filepath.Join(i.root, cgroupControllersFile))
+	if err == nil {
+		return rep.CgroupInfo{Version: 2, Controllers: controllers}, nil
+	}
+	if !os.IsNotExist(err) {
+		logger.Error("failed-to-read-cgroup-controllers", err)
+		return rep.CgroupInfo{}, err
+	}
+
+	hierarchies, err := i.v1Hierarchies()
+	if err != nil {
+		logger.Error("failed-to-read-cgroup-hierarchies", err, lager.Data{"root": i.root})
+		return rep.CgroupInfo{}, err
+	}
+
+	info := rep.CgroupInfo{Version: 1}
+	if _, err := os.Stat(filepath.Join(i.root, "unified")); err == nil {
+		info.Hybrid = true
+	}
+	for _, hierarchy := range hierarchies {
+		info.Controllers = append(info.Controllers, strings.Split(hierarchy, ",")...)
+	}
+	info.Controllers = sortedUnique(info.Controllers)
+	return info, nil
+}
+
+func (i *cgroupInspector) Container(logger lager.Logger, guid string) (rep.ContainerCgroup, error) {
+	cgroup := rep.ContainerCgroup{Path: path.Join("/", i.containersParent, guid), Controllers: []string{}}
+
+	controllers, err := readControllers(filepath.Join(i.root, i.containersParent, guid, cgroupControllersFile))
+	if err == nil {
+		cgroup.Controllers = controllers
+		return cgroup, nil
+	}
+	if _, statErr := os.Stat(filepath.Join(i.root, cgroupControllersFile)); statErr == nil {
+		return rep.ContainerCgroup{}, err
+	}
+
+	hierarchies, err := i.v1Hierarchies()
+	if err != nil {
+		return rep.ContainerCgroup{}, err
+	}
+	for _, hierarchy := range hierarchies {
+		if _, err := os.Stat(filepath.Join(i.root, hierarchy, i.containersParent, guid)); err == nil {
+			cgroup.Controllers = append(cgroup.Controllers, strings.Split(hierarchy, ",")...)
+		}
+	}
+	if len(cgroup.Controllers) == 0 {
+		return rep.ContainerCgroup{}, os.ErrNotExist
+	}
+	cgroup.Controllers = sortedUnique(cgroup.Controllers)
+	return cgroup, nil
+}
+
+// v1Hierarchies returns the directories of the controller hierarchies under
+// root, such as cpu,cpuacct and memory, leaving out the named systemd and
+// unified hierarchies.
+func (i *cgroupInspector) v1Hierarchies() ([]string, error) {
+	entries, err := ioutil.ReadDir(i.root)
+	if err != nil {
+		return nil, err
+	}
+
+	hierarchies := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "systemd" || entry.Name() == "unified" {
+			continue
+		}
+		hierarchies = append(hierarchies, entry.Name())
+	}
+	return hierarchies, nil
+}
+
+func readControllers(path string) ([]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return sortedUnique(strings.Fields(string(contents))), nil
+}
+
+func sortedUnique(values []string) []string {
+	sort.Strings(values)
+	unique := []string{}
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
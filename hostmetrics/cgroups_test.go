@@ -0,0 +1,120 @@
+package hostmetrics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/hostmetrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CgroupInspector", func() {
+	var (
+		root      string
+		inspector hostmetrics.CgroupInspector
+		logger    *lagertest.TestLogger
+	)
+
+	writeCgroupFile := func(name, contents string) {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	makeCgroupDir := func(name string) {
+		Expect(os.MkdirAll(filepath.Join(root, name), 0755)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "cgroup")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		inspector = hostmetrics.NewCgroupInspector(root, "garden")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	Context("with the unified hierarchy", func() {
+		BeforeEach(func() {
+			writeCgroupFile("cgroup.controllers", "memory cpu io pids\n")
+			writeCgroupFile("garden/container-guid/cgroup.controllers", "cpu memory\n")
+		})
+
+		It("reports version 2 with the controllers of the root", func() {
+			info, err := inspector.Host(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(rep.CgroupInfo{Version: 2, Controllers: []string{"cpu", "io", "memory", "pids"}}))
+		})
+
+		It("reports the controllers enabled for a container", func() {
+			cgroup, err := inspector.Container(logger, "container-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cgroup).To(Equal(rep.ContainerCgroup{Path: "/garden/container-guid", Controllers: []string{"cpu", "memory"}}))
+		})
+
+		It("fails for a container without a cgroup", func() {
+			_, err := inspector.Container(logger, "missing-guid")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+
+	Context("with controller hierarchies", func() {
+		BeforeEach(func() {
+			makeCgroupDir("cpu,cpuacct/garden/container-guid")
+			makeCgroupDir("memory/garden/container-guid")
+			makeCgroupDir("pids")
+			makeCgroupDir("systemd")
+		})
+
+		It("reports version 1 with the controllers of every hierarchy", func() {
+			info, err := inspector.Host(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(rep.CgroupInfo{Version: 1, Controllers: []string{"cpu", "cpuacct", "memory", "pids"}}))
+		})
+
+		It("reports the hierarchies a container has a cgroup in", func() {
+			cgroup, err := inspector.Container(logger, "container-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cgroup).To(Equal(rep.ContainerCgroup{Path: "/garden/container-guid", Controllers: []string{"cpu", "cpuacct", "memory"}}))
+		})
+
+		It("fails for a container without a cgroup", func() {
+			_, err := inspector.Container(logger, "missing-guid")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		Context("when the unified hierarchy is also mounted", func() {
+			BeforeEach(func() {
+				makeCgroupDir("unified")
+			})
+
+			It("reports a hybrid hierarchy", func() {
+				info, err := inspector.Host(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Version).To(Equal(1))
+				Expect(info.Hybrid).To(BeTrue())
+				Expect(info.Controllers).NotTo(ContainElement("unified"))
+			})
+		})
+	})
+
+	Context("when the hierarchy is not mounted", func() {
+		BeforeEach(func() {
+			inspector = hostmetrics.NewCgroupInspector(filepath.Join(root, "missing"), "garden")
+		})
+
+		It("fails", func() {
+			_, err := inspector.Host(logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package hostmetrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestHostmetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Hostmetrics Suite")
+}
@@ -0,0 +1 @@
+package hostmetricsfakes // import "code.cloudfoundry.org/rep/hostmetrics/hostmetricsfakes"
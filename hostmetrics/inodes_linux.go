@@ -0,0 +1,20 @@
+//go:build linux
+// +build linux
+
+package hostmetrics
+
+import "syscall"
+
+func inodesUsedFraction(path string) (float64, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, err
+	}
+
+	if stat.Files == 0 {
+		return 0, nil
+	}
+
+	return float64(stat.Files-stat.Ffree) / float64(stat.Files), nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package hostmetrics
+
+// Inode usage is only read on Linux hosts.
+func inodesUsedFraction(path string) (float64, error) {
+	return 0, nil
+}
@@ -0,0 +1 @@
+package hostmetrics // import "code.cloudfoundry.org/rep/hostmetrics"
@@ -0,0 +1,136 @@
+package hostmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o hostmetricsfakes/fake_reader.go . Reader
+
+// Reader reads the pressure signals of the host the cell runs on.
+type Reader interface {
+	Read(logger lager.Logger) (rep.HostPressure, error)
+}
+
+type reader struct {
+	procPath  string
+	inodePath string
+}
+
+// NewReader returns a Reader that reads pressure stall information and the
+// load average from the proc filesystem mounted at procPath, and the inode
+// usage of the filesystem containing inodePath. Pressure stall information
+// is left empty on kernels that do not provide it.
+func NewReader(procPath, inodePath string) Reader {
+	return &reader{
+		procPath:  procPath,
+		inodePath: inodePath,
+	}
+}
+
+func (r *reader) Read(logger lager.Logger) (rep.HostPressure, error) {
+	pressure := rep.HostPressure{}
+
+	cpuSome, _, err := readPressure(filepath.Join(r.procPath, "pressure", "cpu"))
+	if err != nil {
+		logger.Error("failed-to-read-cpu-pressure", err)
+		return rep.HostPressure{}, err
+	}
+	memorySome, memoryFull, err := readPressure(filepath.Join(r.procPath, "pressure", "memory"))
+	if err != nil {
+		logger.Error("failed-to-read-memory-pressure", err)
+		return rep.HostPressure{}, err
+	}
+	ioSome, ioFull, err := readPressure(filepath.Join(r.procPath, "pressure", "io"))
+	if err != nil {
+		logger.Error("failed-to-read-io-pressure", err)
+		return rep.HostPressure{}, err
+	}
+
+	pressure.CPUSomeAvg10 = cpuSome
+	pressure.MemorySomeAvg10 = memorySome
+	pressure.MemoryFullAvg10 = memoryFull
+	pressure.IOSomeAvg10 = ioSome
+	pressure.IOFullAvg10 = ioFull
+
+	pressure.LoadAverage1, pressure.LoadAverage5, pressure.LoadAverage15, err = readLoadAverage(filepath.Join(r.procPath, "loadavg"))
+	if err != nil {
+		logger.Error("failed-to-read-load-average", err)
+		return rep.HostPressure{}, err
+	}
+
+	if r.inodePath != "" {
+		pressure.InodesUsedFraction, err = inodesUsedFraction(r.inodePath)
+		if err != nil {
+			logger.Error("failed-to-read-inode-usage", err, lager.Data{"path": r.inodePath})
+			return rep.HostPressure{}, err
+		}
+	}
+
+	return pressure, nil
+}
+
+// readPressure returns the avg10 values of the "some" and "full" lines of a
+// pressure stall information file.
+func readPressure(path string) (some, full float64, err error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "avg10=") {
+			continue
+		}
+
+		avg10, err := strconv.ParseFloat(strings.TrimPrefix(fields[1], "avg10="), 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("malformed pressure line in %s: %s", path, scanner.Text())
+		}
+
+		switch fields[0] {
+		case "some":
+			some = avg10
+		case "full":
+			full = avg10
+		}
+	}
+
+	return some, full, scanner.Err()
+}
+
+func readLoadAverage(path string) (load1, load5, load15 float64, err error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) < 3 {
+		return 0, 0, 0, fmt.Errorf("malformed load average: %q", string(contents))
+	}
+
+	loads := make([]float64, 3)
+	for i := range loads {
+		loads[i], err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("malformed load average: %q", string(contents))
+		}
+	}
+
+	return loads[0], loads[1], loads[2], nil
+}
@@ -0,0 +1,111 @@
+package hostmetrics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/hostmetrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reader", func() {
+	var (
+		procPath string
+		reader   hostmetrics.Reader
+		logger   *lagertest.TestLogger
+	)
+
+	writeProcFile := func(name, contents string) {
+		path := filepath.Join(procPath, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		procPath, err = ioutil.TempDir("", "proc")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+
+		writeProcFile("pressure/cpu", "some avg10=1.50 avg60=1.00 avg300=0.50 total=12345\n")
+		writeProcFile("pressure/memory", "some avg10=12.25 avg60=3.00 avg300=1.00 total=54321\nfull avg10=4.75 avg60=1.00 avg300=0.25 total=1234\n")
+		writeProcFile("pressure/io", "some avg10=6.00 avg60=2.00 avg300=1.00 total=999\nfull avg10=2.50 avg60=1.00 avg300=0.50 total=99\n")
+		writeProcFile("loadavg", "0.52 0.58 0.59 1/1254 12345\n")
+	})
+
+	JustBeforeEach(func() {
+		reader = hostmetrics.NewReader(procPath, procPath)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(procPath)
+	})
+
+	It("reads the pressure stall information and load average", func() {
+		pressure, err := reader.Read(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pressure.CPUSomeAvg10).To(Equal(1.5))
+		Expect(pressure.MemorySomeAvg10).To(Equal(12.25))
+		Expect(pressure.MemoryFullAvg10).To(Equal(4.75))
+		Expect(pressure.IOSomeAvg10).To(Equal(6.0))
+		Expect(pressure.IOFullAvg10).To(Equal(2.5))
+		Expect(pressure.LoadAverage1).To(Equal(0.52))
+		Expect(pressure.LoadAverage5).To(Equal(0.58))
+		Expect(pressure.LoadAverage15).To(Equal(0.59))
+	})
+
+	It("reads the inode usage of the filesystem", func() {
+		pressure, err := reader.Read(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pressure.InodesUsedFraction).To(BeNumerically(">", 0))
+		Expect(pressure.InodesUsedFraction).To(BeNumerically("<", 1))
+	})
+
+	Context("when the kernel does not provide pressure stall information", func() {
+		BeforeEach(func() {
+			Expect(os.RemoveAll(filepath.Join(procPath, "pressure"))).To(Succeed())
+		})
+
+		It("reports the load average only", func() {
+			pressure, err := reader.Read(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pressure).To(Equal(rep.HostPressure{
+				LoadAverage1:       0.52,
+				LoadAverage5:       0.58,
+				LoadAverage15:      0.59,
+				InodesUsedFraction: pressure.InodesUsedFraction,
+			}))
+		})
+	})
+
+	Context("when a pressure file is malformed", func() {
+		BeforeEach(func() {
+			writeProcFile("pressure/memory", "some avg10=lots avg60=3.00 avg300=1.00 total=54321\n")
+		})
+
+		It("returns an error", func() {
+			_, err := reader.Read(logger)
+			Expect(err).To(MatchError(ContainSubstring("malformed pressure line")))
+		})
+	})
+
+	Context("when the load average cannot be read", func() {
+		BeforeEach(func() {
+			Expect(os.Remove(filepath.Join(procPath, "loadavg"))).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			_, err := reader.Read(logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
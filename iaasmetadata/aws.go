@@ -0,0 +1,76 @@
+package iaasmetadata
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	awsTokenHeader    = "X-aws-ec2-metadata-token"
+	awsTokenTTLHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+	awsTokenTTL       = "300"
+)
+
+type awsProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewAWSProvider queries the EC2 instance metadata service using IMDSv2
+// session tokens.
+func NewAWSProvider(client *http.Client, baseURL string) Provider {
+	return &awsProvider{client: client, baseURL: baseURL}
+}
+
+func (p *awsProvider) Name() string { return ProviderAWS }
+
+func (p *awsProvider) Discover(logger lager.Logger) (Metadata, error) {
+	logger = logger.Session("aws-metadata")
+
+	req, err := http.NewRequest("PUT", p.baseURL+"/latest/api/token", nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	req.Header.Set(awsTokenTTLHeader, awsTokenTTL)
+
+	token, err := do(p.client, req)
+	if err != nil {
+		logger.Error("failed-to-fetch-token", err)
+		return Metadata{}, err
+	}
+
+	zone, err := p.get(string(token), "placement/availability-zone")
+	if err != nil {
+		logger.Error("failed-to-fetch-zone", err)
+		return Metadata{}, err
+	}
+
+	instanceType, err := p.get(string(token), "instance-type")
+	if err != nil {
+		logger.Error("failed-to-fetch-instance-type", err)
+		return Metadata{}, err
+	}
+
+	instanceID, err := p.get(string(token), "instance-id")
+	if err != nil {
+		logger.Error("failed-to-fetch-instance-id", err)
+		return Metadata{}, err
+	}
+
+	return Metadata{Zone: zone, InstanceType: instanceType, InstanceID: instanceID}, nil
+}
+
+func (p *awsProvider) get(token, path string) (string, error) {
+	req, err := http.NewRequest("GET", p.baseURL+"/latest/meta-data/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(awsTokenHeader, token)
+
+	value, err := do(p.client, req)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
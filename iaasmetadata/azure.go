@@ -0,0 +1,59 @@
+package iaasmetadata
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+const azureAPIVersion = "2021-02-01"
+
+type azureProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewAzureProvider(client *http.Client, baseURL string) Provider {
+	return &azureProvider{client: client, baseURL: baseURL}
+}
+
+func (p *azureProvider) Name() string { return ProviderAzure }
+
+type azureCompute struct {
+	Location string `json:"location"`
+	Zone     string `json:"zone"`
+	VMID     string `json:"vmId"`
+	VMSize   string `json:"vmSize"`
+}
+
+func (p *azureProvider) Discover(logger lager.Logger) (Metadata, error) {
+	logger = logger.Session("azure-metadata")
+
+	req, err := http.NewRequest("GET", p.baseURL+"/metadata/instance/compute?format=json&api-version="+azureAPIVersion, nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	payload, err := do(p.client, req)
+	if err != nil {
+		logger.Error("failed-to-fetch-compute-metadata", err)
+		return Metadata{}, err
+	}
+
+	var compute azureCompute
+	err = json.Unmarshal(payload, &compute)
+	if err != nil {
+		logger.Error("failed-to-unmarshal-compute-metadata", err)
+		return Metadata{}, err
+	}
+
+	// VMs outside of an availability zone only report their region
+	zone := compute.Zone
+	if zone == "" {
+		zone = compute.Location
+	}
+
+	return Metadata{Zone: zone, InstanceType: compute.VMSize, InstanceID: compute.VMID}, nil
+}
@@ -0,0 +1,67 @@
+package iaasmetadata
+
+import (
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+type gcpProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewGCPProvider(client *http.Client, baseURL string) Provider {
+	return &gcpProvider{client: client, baseURL: baseURL}
+}
+
+func (p *gcpProvider) Name() string { return ProviderGCP }
+
+func (p *gcpProvider) Discover(logger lager.Logger) (Metadata, error) {
+	logger = logger.Session("gcp-metadata")
+
+	zone, err := p.get("zone")
+	if err != nil {
+		logger.Error("failed-to-fetch-zone", err)
+		return Metadata{}, err
+	}
+
+	machineType, err := p.get("machine-type")
+	if err != nil {
+		logger.Error("failed-to-fetch-machine-type", err)
+		return Metadata{}, err
+	}
+
+	instanceID, err := p.get("id")
+	if err != nil {
+		logger.Error("failed-to-fetch-instance-id", err)
+		return Metadata{}, err
+	}
+
+	// zone and machine-type are returned as fully qualified resource names,
+	// e.g. projects/1234/zones/us-central1-a
+	return Metadata{
+		Zone:         lastPathSegment(zone),
+		InstanceType: lastPathSegment(machineType),
+		InstanceID:   instanceID,
+	}, nil
+}
+
+func (p *gcpProvider) get(path string) (string, error) {
+	req, err := http.NewRequest("GET", p.baseURL+"/computeMetadata/v1/instance/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	value, err := do(p.client, req)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func lastPathSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
@@ -0,0 +1,13 @@
+package iaasmetadata_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestIaaSMetadata(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IaaS Metadata Suite")
+}
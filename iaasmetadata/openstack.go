@@ -0,0 +1,51 @@
+package iaasmetadata
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+type openStackProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewOpenStackProvider queries the OpenStack metadata service. OpenStack
+// does not report the flavor of the instance, so InstanceType is always
+// empty.
+func NewOpenStackProvider(client *http.Client, baseURL string) Provider {
+	return &openStackProvider{client: client, baseURL: baseURL}
+}
+
+func (p *openStackProvider) Name() string { return ProviderOpenStack }
+
+type openStackMetadata struct {
+	UUID             string `json:"uuid"`
+	AvailabilityZone string `json:"availability_zone"`
+}
+
+func (p *openStackProvider) Discover(logger lager.Logger) (Metadata, error) {
+	logger = logger.Session("openstack-metadata")
+
+	req, err := http.NewRequest("GET", p.baseURL+"/openstack/latest/meta_data.json", nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	payload, err := do(p.client, req)
+	if err != nil {
+		logger.Error("failed-to-fetch-metadata", err)
+		return Metadata{}, err
+	}
+
+	var metadata openStackMetadata
+	err = json.Unmarshal(payload, &metadata)
+	if err != nil {
+		logger.Error("failed-to-unmarshal-metadata", err)
+		return Metadata{}, err
+	}
+
+	return Metadata{Zone: metadata.AvailabilityZone, InstanceID: metadata.UUID}, nil
+}
@@ -0,0 +1 @@
+package iaasmetadata // import "code.cloudfoundry.org/rep/iaasmetadata"
@@ -0,0 +1,76 @@
+// iaasmetadata discovers details about the VM the rep is running on from the
+// metadata service of the underlying IaaS
+package iaasmetadata
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	ProviderAWS       = "aws"
+	ProviderGCP       = "gcp"
+	ProviderAzure     = "azure"
+	ProviderOpenStack = "openstack"
+)
+
+var ErrUnknownProvider = errors.New("unknown iaas metadata provider")
+
+// Metadata holds the details of the VM that are relevant to placement.
+type Metadata struct {
+	Zone         string `json:"zone,omitempty"`
+	InstanceType string `json:"instance_type,omitempty"`
+	InstanceID   string `json:"instance_id,omitempty"`
+}
+
+type Provider interface {
+	Name() string
+	Discover(logger lager.Logger) (Metadata, error)
+}
+
+// NewProvider returns the provider with the given name. When baseURL is
+// empty the well known address of that IaaS's metadata service is used.
+func NewProvider(name string, client *http.Client, baseURL string) (Provider, error) {
+	switch name {
+	case ProviderAWS:
+		return NewAWSProvider(client, baseURLOrDefault(baseURL, defaultLinkLocalURL)), nil
+	case ProviderGCP:
+		return NewGCPProvider(client, baseURLOrDefault(baseURL, defaultGCPURL)), nil
+	case ProviderAzure:
+		return NewAzureProvider(client, baseURLOrDefault(baseURL, defaultLinkLocalURL)), nil
+	case ProviderOpenStack:
+		return NewOpenStackProvider(client, baseURLOrDefault(baseURL, defaultLinkLocalURL)), nil
+	}
+
+	return nil, ErrUnknownProvider
+}
+
+const (
+	defaultLinkLocalURL = "http://169.254.169.254"
+	defaultGCPURL       = "http://metadata.google.internal"
+)
+
+func baseURLOrDefault(baseURL, defaultURL string) string {
+	if baseURL == "" {
+		return defaultURL
+	}
+	return baseURL
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,246 @@
+package iaasmetadata_test
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/iaasmetadata"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Provider", func() {
+	var (
+		fakeServer *ghttp.Server
+		logger     *lagertest.TestLogger
+		provider   iaasmetadata.Provider
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	Describe("NewProvider", func() {
+		It("returns the provider with the given name", func() {
+			for _, name := range []string{
+				iaasmetadata.ProviderAWS,
+				iaasmetadata.ProviderGCP,
+				iaasmetadata.ProviderAzure,
+				iaasmetadata.ProviderOpenStack,
+			} {
+				provider, err := iaasmetadata.NewProvider(name, http.DefaultClient, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(provider.Name()).To(Equal(name))
+			}
+		})
+
+		It("errors for an unknown provider", func() {
+			_, err := iaasmetadata.NewProvider("vsphere", http.DefaultClient, "")
+			Expect(err).To(MatchError(iaasmetadata.ErrUnknownProvider))
+		})
+	})
+
+	Describe("AWS", func() {
+		BeforeEach(func() {
+			provider = iaasmetadata.NewAWSProvider(http.DefaultClient, fakeServer.URL())
+		})
+
+		Context("when the metadata service responds", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/latest/api/token"),
+						ghttp.VerifyHeaderKV("X-aws-ec2-metadata-token-ttl-seconds", "300"),
+						ghttp.RespondWith(http.StatusOK, "some-token"),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/latest/meta-data/placement/availability-zone"),
+						ghttp.VerifyHeaderKV("X-aws-ec2-metadata-token", "some-token"),
+						ghttp.RespondWith(http.StatusOK, "us-east-1a"),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/latest/meta-data/instance-type"),
+						ghttp.VerifyHeaderKV("X-aws-ec2-metadata-token", "some-token"),
+						ghttp.RespondWith(http.StatusOK, "m5.large"),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/latest/meta-data/instance-id"),
+						ghttp.VerifyHeaderKV("X-aws-ec2-metadata-token", "some-token"),
+						ghttp.RespondWith(http.StatusOK, "i-0123456789"),
+					),
+				)
+			})
+
+			It("returns the zone, instance type and instance id", func() {
+				metadata, err := provider.Discover(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata).To(Equal(iaasmetadata.Metadata{
+					Zone:         "us-east-1a",
+					InstanceType: "m5.large",
+					InstanceID:   "i-0123456789",
+				}))
+			})
+		})
+
+		Context("when fetching the session token fails", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("PUT", "/latest/api/token"),
+						ghttp.RespondWith(http.StatusForbidden, nil),
+					),
+				)
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Discover(logger)
+				Expect(err).To(MatchError("unexpected status code: 403"))
+			})
+		})
+	})
+
+	Describe("GCP", func() {
+		BeforeEach(func() {
+			provider = iaasmetadata.NewGCPProvider(http.DefaultClient, fakeServer.URL())
+		})
+
+		Context("when the metadata service responds", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/computeMetadata/v1/instance/zone"),
+						ghttp.VerifyHeaderKV("Metadata-Flavor", "Google"),
+						ghttp.RespondWith(http.StatusOK, "projects/1234/zones/us-central1-a"),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/computeMetadata/v1/instance/machine-type"),
+						ghttp.VerifyHeaderKV("Metadata-Flavor", "Google"),
+						ghttp.RespondWith(http.StatusOK, "projects/1234/machineTypes/n1-standard-4"),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/computeMetadata/v1/instance/id"),
+						ghttp.VerifyHeaderKV("Metadata-Flavor", "Google"),
+						ghttp.RespondWith(http.StatusOK, "4567"),
+					),
+				)
+			})
+
+			It("returns the unqualified zone and machine type along with the instance id", func() {
+				metadata, err := provider.Discover(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata).To(Equal(iaasmetadata.Metadata{
+					Zone:         "us-central1-a",
+					InstanceType: "n1-standard-4",
+					InstanceID:   "4567",
+				}))
+			})
+		})
+
+		Context("when the metadata service fails", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Discover(logger)
+				Expect(err).To(MatchError("unexpected status code: 500"))
+			})
+		})
+	})
+
+	Describe("Azure", func() {
+		BeforeEach(func() {
+			provider = iaasmetadata.NewAzureProvider(http.DefaultClient, fakeServer.URL())
+		})
+
+		Context("when the vm is in an availability zone", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/metadata/instance/compute", "format=json&api-version=2021-02-01"),
+						ghttp.VerifyHeaderKV("Metadata", "true"),
+						ghttp.RespondWith(http.StatusOK, `{"location": "westeurope", "zone": "2", "vmId": "some-vm-id", "vmSize": "Standard_D2s_v3"}`),
+					),
+				)
+			})
+
+			It("returns the zone, vm size and vm id", func() {
+				metadata, err := provider.Discover(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata).To(Equal(iaasmetadata.Metadata{
+					Zone:         "2",
+					InstanceType: "Standard_D2s_v3",
+					InstanceID:   "some-vm-id",
+				}))
+			})
+		})
+
+		Context("when the vm is not in an availability zone", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{"location": "westeurope", "zone": "", "vmId": "some-vm-id", "vmSize": "Standard_D2s_v3"}`),
+				)
+			})
+
+			It("uses the location as the zone", func() {
+				metadata, err := provider.Discover(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata.Zone).To(Equal("westeurope"))
+			})
+		})
+
+		Context("when the response is not valid json", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{`))
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Discover(logger)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("OpenStack", func() {
+		BeforeEach(func() {
+			provider = iaasmetadata.NewOpenStackProvider(http.DefaultClient, fakeServer.URL())
+		})
+
+		Context("when the metadata service responds", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/openstack/latest/meta_data.json"),
+						ghttp.RespondWith(http.StatusOK, `{"uuid": "some-uuid", "availability_zone": "nova"}`),
+					),
+				)
+			})
+
+			It("returns the availability zone and uuid", func() {
+				metadata, err := provider.Discover(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata).To(Equal(iaasmetadata.Metadata{
+					Zone:       "nova",
+					InstanceID: "some-uuid",
+				}))
+			})
+		})
+
+		Context("when the metadata service fails", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, nil))
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Discover(logger)
+				Expect(err).To(MatchError("unexpected status code: 404"))
+			})
+		})
+	})
+})
@@ -0,0 +1,25 @@
+package rep
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var ErrImageDigestRequired = errors.New("the docker image must be referenced by digest, as in docker:///cloudfoundry/grace@sha256:<digest>, rather than by tag")
+
+// ImageDigestFailure records the LRP instance or task of a Work that was
+// rejected because its docker image is not referenced by digest.
+type ImageDigestFailure struct {
+	InstanceGUID string `json:"instance_guid,omitempty"`
+	TaskGuid     string `json:"task_guid,omitempty"`
+	Error        string `json:"error"`
+}
+
+// DockerRootFSHasDigest reports whether a docker rootfs references its image
+// by digest, such as docker:///cloudfoundry/grace@sha256:<digest>, rather
+// than by a tag that may be moved to another image.
+func DockerRootFSHasDigest(rootFS url.URL) bool {
+	i := strings.Index(rootFS.Path, "@")
+	return i >= 0 && strings.Contains(rootFS.Path[i+1:], ":")
+}
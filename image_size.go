@@ -0,0 +1,23 @@
+package rep
+
+import "fmt"
+
+// ImageTooLargeError is returned for work whose rootfs image cannot fit in
+// the image store of the cell, even once every cached layer no container
+// uses is reclaimed.
+type ImageTooLargeError struct {
+	RequiredMB  int64 `json:"required_mb"`
+	AvailableMB int64 `json:"available_mb"`
+}
+
+func (e ImageTooLargeError) Error() string {
+	return fmt.Sprintf("the image needs at least %d MB of disk but the cell has %d MB", e.RequiredMB, e.AvailableMB)
+}
+
+// ImageSizeFailure records the LRP instance or task of a Work that was
+// rejected because its rootfs image cannot fit on the cell.
+type ImageSizeFailure struct {
+	InstanceGUID string             `json:"instance_guid,omitempty"`
+	TaskGuid     string             `json:"task_guid,omitempty"`
+	Error        ImageTooLargeError `json:"error"`
+}
@@ -0,0 +1,41 @@
+package imagecache
+
+import (
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const undigestedImageRejectionsMetric = "UndigestedImageRejections"
+
+//go:generate counterfeiter -o imagecachefakes/fake_digest_policy.go . DigestPolicy
+
+// DigestPolicy refuses the docker images that are not referenced by digest,
+// so that a cell only runs immutable images.
+type DigestPolicy interface {
+	Check(logger lager.Logger, rootfs string) error
+}
+
+type digestPolicy struct {
+	metronClient loggingclient.IngressClient
+}
+
+// NewDigestPolicy returns a DigestPolicy that counts every image it refuses.
+func NewDigestPolicy(metronClient loggingclient.IngressClient) DigestPolicy {
+	return &digestPolicy{metronClient: metronClient}
+}
+
+// Check returns rep.ErrImageDigestRequired for a docker rootfs referenced by
+// tag, or by neither tag nor digest, and nil for any other rootfs.
+func (p *digestPolicy) Check(logger lager.Logger, rootfs string) error {
+	rootFSURL, err := rep.ParseRootFS(rootfs)
+	if err != nil || rootFSURL.Scheme != "docker" || rep.DockerRootFSHasDigest(*rootFSURL) {
+		return nil
+	}
+
+	err = p.metronClient.IncrementCounter(undigestedImageRejectionsMetric)
+	if err != nil {
+		logger.Error("failed-to-send-undigested-image-rejections-metric", err)
+	}
+	return rep.ErrImageDigestRequired
+}
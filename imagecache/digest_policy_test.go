@@ -0,0 +1,39 @@
+package imagecache_test
+
+import (
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/imagecache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DigestPolicy", func() {
+	var (
+		logger       *lagertest.TestLogger
+		metronClient *mfakes.FakeIngressClient
+		policy       imagecache.DigestPolicy
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		metronClient = new(mfakes.FakeIngressClient)
+		policy = imagecache.NewDigestPolicy(metronClient)
+	})
+
+	It("accepts docker images referenced by digest and other rootfses", func() {
+		Expect(policy.Check(logger, "docker:///cloudfoundry/grace@sha256:2ae1b0f6a3d4")).To(Succeed())
+		Expect(policy.Check(logger, "preloaded:cflinuxfs3")).To(Succeed())
+		Expect(metronClient.IncrementCounterCallCount()).To(BeZero())
+	})
+
+	It("refuses docker images referenced by tag, counting every refusal", func() {
+		Expect(policy.Check(logger, "docker:///cloudfoundry/grace#v1")).To(MatchError(rep.ErrImageDigestRequired))
+		Expect(policy.Check(logger, "docker://registry.example.com/cloudfoundry/grace")).To(MatchError(rep.ErrImageDigestRequired))
+
+		Expect(metronClient.IncrementCounterCallCount()).To(Equal(2))
+		Expect(metronClient.IncrementCounterArgsForCall(1)).To(Equal("UndigestedImageRejections"))
+	})
+})
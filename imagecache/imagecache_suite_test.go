@@ -0,0 +1,13 @@
+package imagecache_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestImageCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Image Cache Suite")
+}
@@ -0,0 +1 @@
+package imagecachefakes // import "code.cloudfoundry.org/rep/imagecache/imagecachefakes"
@@ -0,0 +1 @@
+package imagecache // import "code.cloudfoundry.org/rep/imagecache"
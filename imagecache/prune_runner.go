@@ -0,0 +1,42 @@
+package imagecache
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+// PruneRunner prunes the image cache every interval.
+type PruneRunner struct {
+	logger   lager.Logger
+	pruner   Pruner
+	clock    clock.Clock
+	interval time.Duration
+}
+
+func NewPruneRunner(logger lager.Logger, pruner Pruner, clock clock.Clock, interval time.Duration) *PruneRunner {
+	return &PruneRunner{
+		logger:   logger.Session("image-cache-pruner"),
+		pruner:   pruner,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+func (r *PruneRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	ticker := r.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			r.pruner.Prune(r.logger)
+		case <-signals:
+			return nil
+		}
+	}
+}
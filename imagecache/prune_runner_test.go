@@ -0,0 +1,45 @@
+package imagecache_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PruneRunner", func() {
+	var (
+		pruner    *imagecachefakes.FakePruner
+		fakeClock *fakeclock.FakeClock
+		process   ifrit.Process
+	)
+
+	BeforeEach(func() {
+		pruner = new(imagecachefakes.FakePruner)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		runner := imagecache.NewPruneRunner(lagertest.NewTestLogger("test"), pruner, fakeClock, time.Hour)
+		process = ginkgomon.Invoke(runner)
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+	})
+
+	It("prunes the image cache every interval", func() {
+		Consistently(pruner.PruneCallCount).Should(Equal(0))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Hour)
+		Eventually(pruner.PruneCallCount).Should(Equal(1))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Hour)
+		Eventually(pruner.PruneCallCount).Should(Equal(2))
+	})
+})
@@ -0,0 +1,163 @@
+package imagecache
+
+import (
+	"sort"
+	"sync"
+
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	imageCacheEvictedVolumesMetric = "ImageCacheEvictedVolumes"
+	imageCacheReclaimedDiskMetric  = "ImageCacheReclaimedDisk"
+)
+
+// Policy decides which unused volumes are evicted when the cache is pruned.
+// Volumes are evicted least recently used first until each store has at
+// least MinFreeDiskMB available, or all of them when MinFreeDiskMB is zero.
+// Pinned volumes are never evicted.
+type Policy struct {
+	MinFreeDiskMB int64
+	PinnedVolumes []string
+}
+
+// EvictedVolume is a volume that was removed when the cache was pruned.
+type EvictedVolume struct {
+	Provider  string `json:"provider"`
+	ID        string `json:"id"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// PruneResult lists the volumes a prune evicted and the disk it reclaimed.
+type PruneResult struct {
+	EvictedVolumes  []EvictedVolume `json:"evicted_volumes"`
+	ReclaimedDiskMB int64           `json:"reclaimed_disk_mb"`
+}
+
+//go:generate counterfeiter -o imagecachefakes/fake_pruner.go . Pruner
+
+// Pruner evicts cached image layers no container depends on.
+type Pruner interface {
+	Prune(logger lager.Logger) (PruneResult, error)
+}
+
+type pruner struct {
+	stores       map[string]Store
+	policy       Policy
+	pinned       map[string]bool
+	metronClient loggingclient.IngressClient
+
+	lock sync.Mutex
+}
+
+func NewPruner(stores map[string]Store, policy Policy, metronClient loggingclient.IngressClient) Pruner {
+	pinned := map[string]bool{}
+	for _, id := range policy.PinnedVolumes {
+		pinned[id] = true
+	}
+
+	return &pruner{
+		stores:       stores,
+		policy:       policy,
+		pinned:       pinned,
+		metronClient: metronClient,
+	}
+}
+
+// Prune prunes the store of every provider. A store that cannot be pruned
+// does not keep the others from being pruned, and the first error is
+// returned along with what was evicted.
+func (p *pruner) Prune(logger lager.Logger) (PruneResult, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	logger = logger.Session("prune-image-cache")
+	logger.Info("starting")
+	defer logger.Info("finished")
+
+	providers := make([]string, 0, len(p.stores))
+	for provider := range p.stores {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	result := PruneResult{EvictedVolumes: []EvictedVolume{}}
+	var reclaimed int64
+	var firstErr error
+	for _, provider := range providers {
+		evicted, err := p.pruneStore(logger.WithData(lager.Data{"provider": provider}), provider, p.stores[provider])
+		for _, volume := range evicted {
+			reclaimed += volume.SizeBytes
+		}
+		result.EvictedVolumes = append(result.EvictedVolumes, evicted...)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	result.ReclaimedDiskMB = reclaimed / bytesPerMB
+
+	p.emitMetrics(logger, result)
+	logger.Info("pruned", lager.Data{"evicted-volumes": len(result.EvictedVolumes), "reclaimed-disk-mb": result.ReclaimedDiskMB})
+	return result, firstErr
+}
+
+func (p *pruner) pruneStore(logger lager.Logger, provider string, store Store) ([]EvictedVolume, error) {
+	volumes, err := store.Volumes(logger)
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return nil, err
+	}
+
+	candidates := []Volume{}
+	for _, volume := range volumes {
+		if !volume.InUse && !p.pinned[volume.ID] {
+			candidates = append(candidates, volume)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastUsed.Before(candidates[j].LastUsed)
+	})
+
+	var available int64
+	target := p.policy.MinFreeDiskMB * bytesPerMB
+	if target > 0 {
+		available, err = store.AvailableBytes(logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	evicted := []EvictedVolume{}
+	for _, volume := range candidates {
+		if target > 0 && available >= target {
+			break
+		}
+
+		err := store.DeleteVolume(logger, volume.ID)
+		if err == ErrVolumeInUse {
+			continue
+		}
+		if err != nil {
+			logger.Error("failed-to-evict-volume", err, lager.Data{"volume": volume.ID})
+			return evicted, err
+		}
+
+		available += volume.SizeBytes
+		evicted = append(evicted, EvictedVolume{Provider: provider, ID: volume.ID, SizeBytes: volume.SizeBytes})
+	}
+
+	return evicted, nil
+}
+
+func (p *pruner) emitMetrics(logger lager.Logger, result PruneResult) {
+	err := p.metronClient.SendMetric(imageCacheEvictedVolumesMetric, len(result.EvictedVolumes))
+	if err != nil {
+		logger.Error("failed-to-send-evicted-volumes-metric", err)
+	}
+
+	err = p.metronClient.SendMebiBytes(imageCacheReclaimedDiskMetric, int(result.ReclaimedDiskMB))
+	if err != nil {
+		logger.Error("failed-to-send-reclaimed-disk-metric", err)
+	}
+}
@@ -0,0 +1,170 @@
+package imagecache_test
+
+import (
+	"errors"
+	"time"
+
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pruner", func() {
+	const mb = 1024 * 1024
+
+	var (
+		store        *imagecachefakes.FakeStore
+		metronClient *mfakes.FakeIngressClient
+		policy       imagecache.Policy
+		pruner       imagecache.Pruner
+		logger       *lagertest.TestLogger
+		now          time.Time
+	)
+
+	deletedVolumes := func() []string {
+		ids := []string{}
+		for i := 0; i < store.DeleteVolumeCallCount(); i++ {
+			_, id := store.DeleteVolumeArgsForCall(i)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		metronClient = new(mfakes.FakeIngressClient)
+		now = time.Now()
+
+		store = new(imagecachefakes.FakeStore)
+		store.VolumesReturns([]imagecache.Volume{
+			{ID: "in-use", SizeBytes: 100 * mb, LastUsed: now.Add(-4 * time.Hour), InUse: true},
+			{ID: "newest", SizeBytes: 100 * mb, LastUsed: now.Add(-1 * time.Hour)},
+			{ID: "oldest", SizeBytes: 100 * mb, LastUsed: now.Add(-3 * time.Hour)},
+			{ID: "older", SizeBytes: 200 * mb, LastUsed: now.Add(-2 * time.Hour)},
+		}, nil)
+		store.AvailableBytesReturns(50*mb, nil)
+
+		policy = imagecache.Policy{}
+	})
+
+	JustBeforeEach(func() {
+		pruner = imagecache.NewPruner(map[string]imagecache.Store{"docker": store}, policy, metronClient)
+	})
+
+	Context("without a free disk target", func() {
+		It("evicts every unused volume, least recently used first", func() {
+			result, err := pruner.Prune(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deletedVolumes()).To(Equal([]string{"oldest", "older", "newest"}))
+			Expect(result.ReclaimedDiskMB).To(BeEquivalentTo(400))
+			Expect(result.EvictedVolumes).To(Equal([]imagecache.EvictedVolume{
+				{Provider: "docker", ID: "oldest", SizeBytes: 100 * mb},
+				{Provider: "docker", ID: "older", SizeBytes: 200 * mb},
+				{Provider: "docker", ID: "newest", SizeBytes: 100 * mb},
+			}))
+		})
+
+		It("emits the eviction metrics", func() {
+			_, err := pruner.Prune(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(metronClient.SendMetricCallCount()).To(Equal(1))
+			name, value, _ := metronClient.SendMetricArgsForCall(0)
+			Expect(name).To(Equal("ImageCacheEvictedVolumes"))
+			Expect(value).To(Equal(3))
+
+			Expect(metronClient.SendMebiBytesCallCount()).To(Equal(1))
+			name, value, _ = metronClient.SendMebiBytesArgsForCall(0)
+			Expect(name).To(Equal("ImageCacheReclaimedDisk"))
+			Expect(value).To(Equal(400))
+		})
+	})
+
+	Context("with a free disk target", func() {
+		BeforeEach(func() {
+			policy.MinFreeDiskMB = 300
+		})
+
+		It("stops evicting once the target is met", func() {
+			result, err := pruner.Prune(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deletedVolumes()).To(Equal([]string{"oldest", "older"}))
+			Expect(result.ReclaimedDiskMB).To(BeEquivalentTo(300))
+		})
+
+		Context("when the target is already met", func() {
+			BeforeEach(func() {
+				store.AvailableBytesReturns(500*mb, nil)
+			})
+
+			It("does not evict anything", func() {
+				result, err := pruner.Prune(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(store.DeleteVolumeCallCount()).To(Equal(0))
+				Expect(result.EvictedVolumes).To(BeEmpty())
+			})
+		})
+
+		Context("when the available disk cannot be read", func() {
+			BeforeEach(func() {
+				store.AvailableBytesReturns(0, errors.New("boom"))
+			})
+
+			It("does not evict anything and returns the error", func() {
+				_, err := pruner.Prune(logger)
+				Expect(err).To(MatchError("boom"))
+				Expect(store.DeleteVolumeCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("with pinned volumes", func() {
+		BeforeEach(func() {
+			policy.PinnedVolumes = []string{"oldest"}
+		})
+
+		It("never evicts them", func() {
+			_, err := pruner.Prune(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedVolumes()).To(Equal([]string{"older", "newest"}))
+		})
+	})
+
+	Context("when a volume came into use since it was listed", func() {
+		BeforeEach(func() {
+			store.DeleteVolumeStub = func(_ lager.Logger, id string) error {
+				if id == "older" {
+					return imagecache.ErrVolumeInUse
+				}
+				return nil
+			}
+		})
+
+		It("skips it", func() {
+			result, err := pruner.Prune(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ReclaimedDiskMB).To(BeEquivalentTo(200))
+		})
+	})
+
+	Context("when evicting a volume fails", func() {
+		BeforeEach(func() {
+			store.DeleteVolumeReturnsOnCall(1, errors.New("boom"))
+		})
+
+		It("returns the error along with what was evicted", func() {
+			result, err := pruner.Prune(logger)
+			Expect(err).To(MatchError("boom"))
+			Expect(result.EvictedVolumes).To(Equal([]imagecache.EvictedVolume{
+				{Provider: "docker", ID: "oldest", SizeBytes: 100 * mb},
+			}))
+		})
+	})
+})
@@ -0,0 +1,62 @@
+package imagecache
+
+import (
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const bytesPerMB = 1024 * 1024
+
+//go:generate counterfeiter -o imagecachefakes/fake_usage_reader.go . UsageReader
+
+// UsageReader attributes the disk used by cached image layers to the rootfs
+// providers of the cell.
+type UsageReader interface {
+	Read(logger lager.Logger) (map[string]rep.RootFSDiskUsage, error)
+}
+
+type usageReader struct {
+	stores map[string]Store
+}
+
+// NewUsageReader returns a UsageReader that reports the disk usage of the
+// store of each rootfs provider in stores.
+func NewUsageReader(stores map[string]Store) UsageReader {
+	return &usageReader{stores: stores}
+}
+
+func (r *usageReader) Read(logger lager.Logger) (map[string]rep.RootFSDiskUsage, error) {
+	logger = logger.Session("read-rootfs-disk-usage")
+
+	providers := make([]string, 0, len(r.stores))
+	for provider := range r.stores {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	usage := make(map[string]rep.RootFSDiskUsage, len(providers))
+	for _, provider := range providers {
+		volumes, err := r.stores[provider].Volumes(logger)
+		if err != nil {
+			logger.Error("failed-to-list-volumes", err, lager.Data{"provider": provider})
+			return nil, err
+		}
+
+		var used, reclaimable int64
+		for _, volume := range volumes {
+			used += volume.SizeBytes
+			if !volume.InUse {
+				reclaimable += volume.SizeBytes
+			}
+		}
+
+		usage[provider] = rep.RootFSDiskUsage{
+			DiskMB:            int32(used / bytesPerMB),
+			ReclaimableDiskMB: int32(reclaimable / bytesPerMB),
+		}
+	}
+
+	return usage, nil
+}
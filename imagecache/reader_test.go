@@ -0,0 +1,64 @@
+package imagecache_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UsageReader", func() {
+	var (
+		dockerStore    *imagecachefakes.FakeStore
+		preloadedStore *imagecachefakes.FakeStore
+		reader         imagecache.UsageReader
+		logger         *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+
+		dockerStore = new(imagecachefakes.FakeStore)
+		dockerStore.VolumesReturns([]imagecache.Volume{
+			{ID: "layer-1", SizeBytes: 300 * 1024 * 1024, InUse: true},
+			{ID: "layer-2", SizeBytes: 200 * 1024 * 1024},
+			{ID: "layer-3", SizeBytes: 100 * 1024 * 1024},
+		}, nil)
+
+		preloadedStore = new(imagecachefakes.FakeStore)
+		preloadedStore.VolumesReturns([]imagecache.Volume{
+			{ID: "cflinuxfs3", SizeBytes: 1024 * 1024 * 1024, InUse: true},
+		}, nil)
+
+		reader = imagecache.NewUsageReader(map[string]imagecache.Store{
+			"docker":    dockerStore,
+			"preloaded": preloadedStore,
+		})
+	})
+
+	It("reports the used and reclaimable disk of each provider", func() {
+		usage, err := reader.Read(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(usage).To(Equal(map[string]rep.RootFSDiskUsage{
+			"docker":    {DiskMB: 600, ReclaimableDiskMB: 300},
+			"preloaded": {DiskMB: 1024, ReclaimableDiskMB: 0},
+		}))
+	})
+
+	Context("when listing the volumes of a store fails", func() {
+		BeforeEach(func() {
+			dockerStore.VolumesReturns(nil, errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			_, err := reader.Read(logger)
+			Expect(err).To(MatchError("boom"))
+		})
+	})
+})
@@ -0,0 +1,377 @@
+package imagecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	defaultReference  = "latest"
+
+	manifestMediaType     = "application/vnd.docker.distribution.manifest.v2+json"
+	manifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ociManifestMediaType  = "application/vnd.oci.image.manifest.v1+json"
+	ociIndexMediaType     = "application/vnd.oci.image.index.v1+json"
+
+	// maxManifestBytes bounds the manifests and image configs that are read,
+	// which are a few kilobytes for any real image.
+	maxManifestBytes = 4 * 1024 * 1024
+)
+
+var (
+	ErrInvalidManifest    = errors.New("the image manifest does not match its config")
+	ErrNoPlatformManifest = errors.New("the image has no manifest for linux/amd64")
+)
+
+// Image is an image in a registry, named by a tag or a digest.
+type Image struct {
+	Registry   string
+	Repository string
+	Reference  string
+}
+
+// ParseDockerRootFS returns the image of a docker rootfs, such as
+// docker:///cloudfoundry/grace#v1, and false for any other rootfs.
+func ParseDockerRootFS(rootfs string) (Image, bool) {
+	rootFSURL, err := rep.ParseRootFS(rootfs)
+	if err != nil || rootFSURL.Scheme != "docker" {
+		return Image{}, false
+	}
+
+	image := Image{
+		Registry:   rootFSURL.Host,
+		Repository: strings.TrimPrefix(rootFSURL.Path, "/"),
+		Reference:  rootFSURL.Fragment,
+	}
+	if i := strings.Index(image.Repository, "@"); i >= 0 {
+		image.Reference = image.Repository[i+1:]
+		image.Repository = image.Repository[:i]
+	}
+	if image.Repository == "" {
+		return Image{}, false
+	}
+	if image.Reference == "" {
+		image.Reference = defaultReference
+	}
+	if image.Registry == "" || image.Registry == "docker.io" || image.Registry == "index.docker.io" {
+		image.Registry = dockerHubRegistry
+		if !strings.Contains(image.Repository, "/") {
+			image.Repository = "library/" + image.Repository
+		}
+	}
+	return image, true
+}
+
+func (i Image) String() string {
+	return fmt.Sprintf("%s/%s#%s", i.Registry, i.Repository, i.Reference)
+}
+
+// Layer is a layer of an image. Its ChainID names the volume a GrootFS
+// store caches the layer in.
+type Layer struct {
+	ChainID   string
+	SizeBytes int64
+}
+
+//go:generate counterfeiter -o imagecachefakes/fake_layer_source.go . LayerSource
+
+// LayerSource lists the layers of an image.
+type LayerSource interface {
+	Layers(ctx context.Context, logger lager.Logger, image Image, registry *rep.RegistryCredentials) ([]Layer, error)
+}
+
+type registryLayerSource struct {
+	timeout            time.Duration
+	insecureRegistries map[string]bool
+}
+
+// NewRegistryLayerSource returns a LayerSource that reads the layers of an
+// image from the manifest and config of the image in its registry. The size
+// of a layer is its compressed size, which is less than what the layer takes
+// up once it is extracted. Registries in insecureRegistries are reached over
+// plain HTTP.
+func NewRegistryLayerSource(timeout time.Duration, insecureRegistries []string) LayerSource {
+	insecure := make(map[string]bool, len(insecureRegistries))
+	for _, registry := range insecureRegistries {
+		insecure[registry] = true
+	}
+	return &registryLayerSource{timeout: timeout, insecureRegistries: insecure}
+}
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Config    descriptor   `json:"config"`
+	Layers    []descriptor `json:"layers"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+type imageConfig struct {
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+func (s *registryLayerSource) Layers(ctx context.Context, logger lager.Logger, image Image, registry *rep.RegistryCredentials) ([]Layer, error) {
+	logger = logger.Session("read-image-layers", lager.Data{"image": image.String()})
+
+	session, err := s.newSession(ctx, image, registry)
+	if err != nil {
+		logger.Error("failed-to-configure-registry-client", err)
+		return nil, err
+	}
+
+	var m manifest
+	err = session.getJSON("manifests/"+image.Reference, &m)
+	if err != nil {
+		logger.Error("failed-to-fetch-manifest", err)
+		return nil, err
+	}
+
+	if m.MediaType == manifestListMediaType || m.MediaType == ociIndexMediaType || len(m.Manifests) > 0 {
+		digest, err := platformManifest(m.Manifests)
+		if err != nil {
+			logger.Error("failed-to-select-manifest", err)
+			return nil, err
+		}
+		m = manifest{}
+		err = session.getJSON("manifests/"+digest, &m)
+		if err != nil {
+			logger.Error("failed-to-fetch-platform-manifest", err)
+			return nil, err
+		}
+	}
+
+	var config imageConfig
+	err = session.getJSON("blobs/"+m.Config.Digest, &config)
+	if err != nil {
+		logger.Error("failed-to-fetch-image-config", err)
+		return nil, err
+	}
+
+	if len(config.RootFS.DiffIDs) != len(m.Layers) {
+		logger.Error("failed-to-match-layers", ErrInvalidManifest, lager.Data{"layers": len(m.Layers), "diff-ids": len(config.RootFS.DiffIDs)})
+		return nil, ErrInvalidManifest
+	}
+
+	layers := make([]Layer, len(m.Layers))
+	chainID := ""
+	for i := range m.Layers {
+		chainID = nextChainID(chainID, config.RootFS.DiffIDs[i])
+		layers[i] = Layer{ChainID: chainID, SizeBytes: m.Layers[i].Size}
+	}
+	return layers, nil
+}
+
+// platformManifest returns the digest of the linux/amd64 manifest of a
+// manifest list.
+func platformManifest(manifests []descriptor) (string, error) {
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+			return m.Digest, nil
+		}
+	}
+	return "", ErrNoPlatformManifest
+}
+
+// nextChainID returns the chain ID of a layer with diffID on top of the
+// layers with parentChainID, without the algorithm prefix GrootFS leaves off
+// the names of its volumes.
+func nextChainID(parentChainID, diffID string) string {
+	diffID = strings.TrimPrefix(diffID, "sha256:")
+	if parentChainID == "" {
+		return diffID
+	}
+	sum := sha256.Sum256([]byte("sha256:" + parentChainID + " sha256:" + diffID))
+	return hex.EncodeToString(sum[:])
+}
+
+type registrySession struct {
+	ctx         context.Context
+	client      *http.Client
+	baseURL     string
+	repository  string
+	credentials *rep.RegistryCredentials
+	token       string
+}
+
+func (s *registryLayerSource) newSession(ctx context.Context, image Image, registry *rep.RegistryCredentials) (*registrySession, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if registry != nil && registry.CACertificates != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(registry.CACertificates)) {
+			return nil, errors.New("the certificate authorities are not PEM encoded")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	scheme := "https"
+	if s.insecureRegistries[image.Registry] {
+		scheme = "http"
+	}
+
+	return &registrySession{
+		ctx:         ctx,
+		client:      &http.Client{Timeout: s.timeout, Transport: transport},
+		baseURL:     fmt.Sprintf("%s://%s/v2/%s/", scheme, image.Registry, image.Repository),
+		repository:  image.Repository,
+		credentials: registry,
+	}, nil
+}
+
+// getJSON decodes the registry resource at path. A bearer token is fetched
+// the first time the registry challenges the session for one.
+func (s *registrySession) getJSON(path string, into interface{}) error {
+	resp, err := s.get(path)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && s.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		err = s.authenticate(challenge)
+		if err != nil {
+			return err
+		}
+		resp, err = s.get(path)
+		if err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code fetching %s: %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxManifestBytes)).Decode(into)
+}
+
+func (s *registrySession) get(path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(s.ctx, "GET", s.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join([]string{manifestMediaType, manifestListMediaType, ociManifestMediaType, ociIndexMediaType}, ", "))
+	switch {
+	case s.token != "":
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	case s.credentials != nil && s.credentials.Username != "":
+		req.SetBasicAuth(s.credentials.Username, s.credentials.Password)
+	}
+	return s.client.Do(req)
+}
+
+// authenticate fetches a bearer token from the realm of a challenge such as
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io".
+func (s *registrySession) authenticate(challenge string) error {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return errors.New("the registry refused the credentials")
+	}
+	params := challengeParams(strings.TrimPrefix(challenge, "Bearer "))
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("invalid registry auth challenge: %q", challenge)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", s.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(s.ctx, "GET", realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if s.credentials != nil && s.credentials.Username != "" {
+		req.SetBasicAuth(s.credentials.Username, s.credentials.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return fmt.Errorf("unexpected status code fetching a registry token: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxManifestBytes)).Decode(&token)
+	if err != nil {
+		return err
+	}
+	s.token = token.Token
+	if s.token == "" {
+		s.token = token.AccessToken
+	}
+	if s.token == "" {
+		return errors.New("the registry returned no token")
+	}
+	return nil
+}
+
+// challengeParams parses the comma separated key="value" pairs of a
+// challenge. Values may contain commas, as in scope="repository:app:pull,push".
+func challengeParams(challenge string) map[string]string {
+	params := map[string]string{}
+	for challenge != "" {
+		eq := strings.Index(challenge, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.TrimSpace(strings.TrimLeft(challenge[:eq], ", "))
+		rest := challenge[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else if comma := strings.Index(rest, ","); comma >= 0 {
+			value, rest = rest[:comma], rest[comma:]
+		} else {
+			value, rest = rest, ""
+		}
+
+		params[key] = value
+		challenge = rest
+	}
+	return params
+}
@@ -0,0 +1,172 @@
+package imagecache_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/imagecache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("ParseDockerRootFS", func() {
+	It("parses images on docker hub and other registries", func() {
+		image, ok := imagecache.ParseDockerRootFS("docker:///ubuntu")
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal(imagecache.Image{Registry: "registry-1.docker.io", Repository: "library/ubuntu", Reference: "latest"}))
+
+		image, ok = imagecache.ParseDockerRootFS("docker://registry.example.com:5000/team/app#v2")
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal(imagecache.Image{Registry: "registry.example.com:5000", Repository: "team/app", Reference: "v2"}))
+	})
+
+	It("parses images with characters url.Parse rejects", func() {
+		image, ok := imagecache.ParseDockerRootFS("docker:///cloudfoundry/grace#v1%")
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal(imagecache.Image{Registry: "registry-1.docker.io", Repository: "cloudfoundry/grace", Reference: "v1%"}))
+	})
+
+	It("does not parse other rootfses", func() {
+		_, ok := imagecache.ParseDockerRootFS("preloaded:cflinuxfs3")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("RegistryLayerSource", func() {
+	const (
+		configDigest = "sha256:config"
+		diffID1      = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		diffID2      = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	var (
+		logger   *lagertest.TestLogger
+		registry *ghttp.Server
+		image    imagecache.Image
+		source   imagecache.LayerSource
+	)
+
+	manifest := map[string]interface{}{
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config":    map[string]interface{}{"digest": configDigest, "size": 1000},
+		"layers": []map[string]interface{}{
+			{"digest": "sha256:layer1", "size": 300},
+			{"digest": "sha256:layer2", "size": 200},
+		},
+	}
+	config := map[string]interface{}{
+		"rootfs": map[string]interface{}{"diff_ids": []string{diffID1, diffID2}},
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		registry = ghttp.NewServer()
+		registryHost, err := url.Parse(registry.URL())
+		Expect(err).NotTo(HaveOccurred())
+
+		image = imagecache.Image{Registry: registryHost.Host, Repository: "team/app", Reference: "v2"}
+		source = imagecache.NewRegistryLayerSource(time.Second, []string{registryHost.Host})
+	})
+
+	AfterEach(func() {
+		registry.Close()
+	})
+
+	It("returns the compressed size and chain id of every layer", func() {
+		registry.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/team/app/manifests/v2"),
+				ghttp.VerifyBasicAuth("user", "pass"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, manifest),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/team/app/blobs/"+configDigest),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, config),
+			),
+		)
+
+		layers, err := source.Layers(context.Background(), logger, image, &rep.RegistryCredentials{Username: "user", Password: "pass"})
+		Expect(err).NotTo(HaveOccurred())
+
+		sum := sha256.Sum256([]byte(diffID1 + " " + diffID2))
+		Expect(layers).To(Equal([]imagecache.Layer{
+			{ChainID: "1111111111111111111111111111111111111111111111111111111111111111", SizeBytes: 300},
+			{ChainID: hex.EncodeToString(sum[:]), SizeBytes: 200},
+		}))
+	})
+
+	It("selects the linux/amd64 manifest of a manifest list", func() {
+		registry.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+				"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+				"manifests": []map[string]interface{}{
+					{"digest": "sha256:arm", "platform": map[string]string{"os": "linux", "architecture": "arm64"}},
+					{"digest": "sha256:amd", "platform": map[string]string{"os": "linux", "architecture": "amd64"}},
+				},
+			}),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v2/team/app/manifests/sha256:amd"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, manifest),
+			),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, config),
+		)
+
+		layers, err := source.Layers(context.Background(), logger, image, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(layers).To(HaveLen(2))
+	})
+
+	It("fetches a bearer token when the registry asks for one", func() {
+		registry.AppendHandlers(
+			ghttp.RespondWith(http.StatusUnauthorized, "", http.Header{
+				"WWW-Authenticate": []string{`Bearer realm="` + registry.URL() + `/token",service="registry",scope="repository:team/app:pull"`},
+			}),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/token", "scope=repository%3Ateam%2Fapp%3Apull&service=registry"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{"token": "the-token"}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer the-token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, manifest),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer the-token"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, config),
+			),
+		)
+
+		_, err := source.Layers(context.Background(), logger, image, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when the config does not match the layers", func() {
+		It("returns an error", func() {
+			registry.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, manifest),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+					"rootfs": map[string]interface{}{"diff_ids": []string{diffID1}},
+				}),
+			)
+
+			_, err := source.Layers(context.Background(), logger, image, nil)
+			Expect(err).To(Equal(imagecache.ErrInvalidManifest))
+		})
+	})
+
+	Context("when the image does not exist", func() {
+		It("returns an error", func() {
+			registry.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+
+			_, err := source.Layers(context.Background(), logger, image, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
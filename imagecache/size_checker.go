@@ -0,0 +1,253 @@
+package imagecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o imagecachefakes/fake_size_checker.go . SizeChecker
+
+// SizeChecker tells whether the images of rootfses can fit in the image
+// stores of the cell before containers pull them.
+type SizeChecker interface {
+	// Check returns, for each of images, why it cannot fit once the images
+	// before it that fit are pulled too, or nil when it fits, is not an image
+	// with a store to check it against, or could not be checked in time.
+	Check(ctx context.Context, logger lager.Logger, images []ImageCheck) []*rep.ImageTooLargeError
+}
+
+// ImageCheck is the rootfs of a container with the credentials its image is
+// pulled with.
+type ImageCheck struct {
+	RootFS   string
+	Registry *rep.RegistryCredentials
+}
+
+type cachedLayers struct {
+	layers  []Layer
+	fetched time.Time
+}
+
+type sizeChecker struct {
+	stores   map[string]Store
+	source   LayerSource
+	clock    clock.Clock
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mutex  sync.Mutex
+	layers map[string]cachedLayers
+}
+
+// NewSizeChecker returns a SizeChecker for the images of the rootfs
+// providers in stores. The layers of an image already cached in its store
+// take no more disk, and the layers no container uses count as available, as
+// they are reclaimed once the store runs out of space. As the layers source
+// reports compressed sizes, an image that does not fit cannot possibly be
+// pulled, while one that fits may still not. The layers of the images of a
+// check are looked up at once, and the images not looked up within timeout
+// are taken to fit. The layers of an image are looked up again once cacheTTL
+// has passed, so that a moved tag is noticed, and separately for each set of
+// credentials, so that what one tenant may pull never answers for another.
+func NewSizeChecker(stores map[string]Store, source LayerSource, clock clock.Clock, timeout, cacheTTL time.Duration) SizeChecker {
+	return &sizeChecker{
+		stores:   stores,
+		source:   source,
+		clock:    clock,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		layers:   map[string]cachedLayers{},
+	}
+}
+
+// layersLookup is the lookup of the layers of an image, done once it is
+// closed.
+type layersLookup struct {
+	done   chan struct{}
+	layers []Layer
+	err    error
+}
+
+// wait waits for the lookup until ctx is done and reports whether the lookup
+// is done.
+func (l *layersLookup) wait(ctx context.Context) bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+	}
+
+	select {
+	case <-l.done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// storeSpace is the space left in a store as the images of a check are
+// accepted, and the layers the store holds or the accepted images pull.
+type storeSpace struct {
+	available int64
+	cached    map[string]bool
+	err       error
+}
+
+func (c *sizeChecker) Check(ctx context.Context, logger lager.Logger, images []ImageCheck) []*rep.ImageTooLargeError {
+	logger = logger.Session("check-image-sizes")
+	results := make([]*rep.ImageTooLargeError, len(images))
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	schemes := make([]string, len(images))
+	parsed := make([]Image, len(images))
+	lookups := make([]*layersLookup, len(images))
+	started := map[string]*layersLookup{}
+	for i := range images {
+		rootFSURL, err := rep.ParseRootFS(images[i].RootFS)
+		if err != nil {
+			continue
+		}
+		if _, ok := c.stores[rootFSURL.Scheme]; !ok {
+			continue
+		}
+		image, ok := ParseDockerRootFS(images[i].RootFS)
+		if !ok {
+			continue
+		}
+
+		key := cacheKey(image, images[i].Registry)
+		lookup, ok := started[key]
+		if !ok {
+			lookup = c.lookupLayers(ctx, logger, key, image, images[i].Registry)
+			started[key] = lookup
+		}
+		schemes[i], parsed[i], lookups[i] = rootFSURL.Scheme, image, lookup
+	}
+
+	spaces := map[string]*storeSpace{}
+	for i := range images {
+		if lookups[i] == nil {
+			continue
+		}
+		imageLogger := logger.WithData(lager.Data{"image": parsed[i].String()})
+
+		if !lookups[i].wait(ctx) {
+			imageLogger.Info("image-size-check-timed-out")
+			continue
+		}
+		if lookups[i].err != nil {
+			continue
+		}
+
+		space, ok := spaces[schemes[i]]
+		if !ok {
+			space = c.storeSpace(imageLogger, c.stores[schemes[i]])
+			spaces[schemes[i]] = space
+		}
+		if space.err != nil {
+			continue
+		}
+
+		var required int64
+		var missing []string
+		for _, layer := range lookups[i].layers {
+			if !space.cached[layer.ChainID] {
+				required += layer.SizeBytes
+				missing = append(missing, layer.ChainID)
+			}
+		}
+
+		if required > space.available {
+			results[i] = &rep.ImageTooLargeError{RequiredMB: required / bytesPerMB, AvailableMB: space.available / bytesPerMB}
+			imageLogger.Info("image-too-large", lager.Data{"required-mb": results[i].RequiredMB, "available-mb": results[i].AvailableMB})
+			continue
+		}
+
+		space.available -= required
+		for _, chainID := range missing {
+			space.cached[chainID] = true
+		}
+	}
+
+	return results
+}
+
+// lookupLayers looks up the layers of image in the cache, or in the source
+// until ctx is done.
+func (c *sizeChecker) lookupLayers(ctx context.Context, logger lager.Logger, key string, image Image, registry *rep.RegistryCredentials) *layersLookup {
+	lookup := &layersLookup{done: make(chan struct{})}
+	now := c.clock.Now()
+
+	c.mutex.Lock()
+	entry, ok := c.layers[key]
+	c.mutex.Unlock()
+	if ok && now.Sub(entry.fetched) < c.cacheTTL {
+		lookup.layers = entry.layers
+		close(lookup.done)
+		return lookup
+	}
+
+	go func() {
+		defer close(lookup.done)
+
+		layers, err := c.source.Layers(ctx, logger, image, registry)
+		if err != nil {
+			logger.Error("failed-to-look-up-image-layers", err, lager.Data{"image": image.String()})
+			lookup.err = err
+			return
+		}
+		lookup.layers = layers
+
+		c.mutex.Lock()
+		c.layers[key] = cachedLayers{layers: layers, fetched: now}
+		for cachedKey, cachedEntry := range c.layers {
+			if now.Sub(cachedEntry.fetched) >= c.cacheTTL {
+				delete(c.layers, cachedKey)
+			}
+		}
+		c.mutex.Unlock()
+	}()
+
+	return lookup
+}
+
+func (c *sizeChecker) storeSpace(logger lager.Logger, store Store) *storeSpace {
+	volumes, err := store.Volumes(logger)
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return &storeSpace{err: err}
+	}
+	available, err := store.AvailableBytes(logger)
+	if err != nil {
+		logger.Error("failed-to-get-available-bytes", err)
+		return &storeSpace{err: err}
+	}
+
+	space := &storeSpace{available: available, cached: make(map[string]bool, len(volumes))}
+	for _, volume := range volumes {
+		space.cached[volume.ID] = true
+		if !volume.InUse {
+			space.available += volume.SizeBytes
+		}
+	}
+	return space
+}
+
+// cacheKey identifies the layers of image as looked up with registry,
+// without keeping the password of registry in memory.
+func cacheKey(image Image, registry *rep.RegistryCredentials) string {
+	if registry == nil {
+		return image.String()
+	}
+	sum := sha256.Sum256([]byte(registry.Username + "\x00" + registry.Password + "\x00" + registry.CACertificates))
+	return image.String() + "@" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,144 @@
+package imagecache_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("SizeChecker", func() {
+	const mb = 1024 * 1024
+
+	var (
+		logger      *lagertest.TestLogger
+		fakeClock   *fakeclock.FakeClock
+		dockerStore *imagecachefakes.FakeStore
+		source      *imagecachefakes.FakeLayerSource
+		checker     imagecache.SizeChecker
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		dockerStore = new(imagecachefakes.FakeStore)
+		dockerStore.AvailableBytesReturns(1000*mb, nil)
+		dockerStore.VolumesReturns([]imagecache.Volume{
+			{ID: "chain-1", SizeBytes: 600 * mb, InUse: true},
+			{ID: "unused", SizeBytes: 400 * mb},
+		}, nil)
+
+		source = new(imagecachefakes.FakeLayerSource)
+		source.LayersReturns([]imagecache.Layer{
+			{ChainID: "chain-1", SizeBytes: 600 * mb},
+			{ChainID: "chain-2", SizeBytes: 1000 * mb},
+		}, nil)
+
+		checker = imagecache.NewSizeChecker(map[string]imagecache.Store{"docker": dockerStore}, source, fakeClock, time.Minute, time.Minute)
+	})
+
+	check := func(images ...imagecache.ImageCheck) []*rep.ImageTooLargeError {
+		return checker.Check(context.Background(), logger, images)
+	}
+
+	It("counts cached layers as pulled and unused layers as available", func() {
+		Expect(check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1"})).To(Equal([]*rep.ImageTooLargeError{nil}))
+	})
+
+	It("rejects images whose missing layers exceed the available disk", func() {
+		source.LayersReturns([]imagecache.Layer{{ChainID: "chain-3", SizeBytes: 2000 * mb}}, nil)
+
+		tooLarge := check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1", Registry: &rep.RegistryCredentials{Username: "user", Password: "pass"}})
+		Expect(tooLarge).To(Equal([]*rep.ImageTooLargeError{{RequiredMB: 2000, AvailableMB: 1400}}))
+
+		_, _, image, registry := source.LayersArgsForCall(0)
+		Expect(image).To(Equal(imagecache.Image{Registry: "registry-1.docker.io", Repository: "team/app", Reference: "v1"}))
+		Expect(registry).To(Equal(&rep.RegistryCredentials{Username: "user", Password: "pass"}))
+	})
+
+	It("subtracts each image that fits from the space left for the images after it", func() {
+		source.LayersStub = func(_ context.Context, _ lager.Logger, image imagecache.Image, _ *rep.RegistryCredentials) ([]imagecache.Layer, error) {
+			return []imagecache.Layer{{ChainID: image.Repository, SizeBytes: 1000 * mb}}, nil
+		}
+
+		tooLarge := check(
+			imagecache.ImageCheck{RootFS: "docker:///team/app#v1"},
+			imagecache.ImageCheck{RootFS: "docker:///team/app#v1"},
+			imagecache.ImageCheck{RootFS: "docker:///team/other#v1"},
+		)
+		Expect(tooLarge).To(HaveLen(3))
+		Expect(tooLarge[0]).To(BeNil())
+		Expect(tooLarge[1]).To(BeNil())
+		Expect(tooLarge[2]).To(Equal(&rep.ImageTooLargeError{RequiredMB: 1000, AvailableMB: 400}))
+		Expect(source.LayersCallCount()).To(Equal(2))
+	})
+
+	It("looks up the layers of an image again once the cache ttl passed", func() {
+		check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1"})
+		check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1"})
+		Expect(source.LayersCallCount()).To(Equal(1))
+
+		fakeClock.Increment(time.Minute)
+		check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1"})
+		Expect(source.LayersCallCount()).To(Equal(2))
+	})
+
+	It("looks up the layers of an image separately for each set of credentials", func() {
+		check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1", Registry: &rep.RegistryCredentials{Username: "tenant-a", Password: "pass"}})
+		check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1", Registry: &rep.RegistryCredentials{Username: "tenant-b", Password: "pass"}})
+		check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1", Registry: &rep.RegistryCredentials{Username: "tenant-a", Password: "pass"}})
+		Expect(source.LayersCallCount()).To(Equal(2))
+
+		_, _, _, registry := source.LayersArgsForCall(1)
+		Expect(registry).To(Equal(&rep.RegistryCredentials{Username: "tenant-b", Password: "pass"}))
+	})
+
+	It("does not check rootfses without a store", func() {
+		Expect(check(imagecache.ImageCheck{RootFS: "preloaded:cflinuxfs3"})).To(Equal([]*rep.ImageTooLargeError{nil}))
+		Expect(source.LayersCallCount()).To(BeZero())
+	})
+
+	Context("when the layers cannot be listed", func() {
+		BeforeEach(func() {
+			source.LayersReturns(nil, errors.New("registry unavailable"))
+		})
+
+		It("takes the image to fit", func() {
+			Expect(check(imagecache.ImageCheck{RootFS: "docker:///team/app#v1"})).To(Equal([]*rep.ImageTooLargeError{nil}))
+			Expect(logger).To(Say("failed-to-look-up-image-layers"))
+		})
+	})
+
+	Context("when the layers are not looked up within the timeout", func() {
+		BeforeEach(func() {
+			source.LayersStub = func(ctx context.Context, _ lager.Logger, image imagecache.Image, _ *rep.RegistryCredentials) ([]imagecache.Layer, error) {
+				if image.Repository == "team/slow" {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return []imagecache.Layer{{ChainID: "chain-3", SizeBytes: 2000 * mb}}, nil
+			}
+			checker = imagecache.NewSizeChecker(map[string]imagecache.Store{"docker": dockerStore}, source, fakeClock, 10*time.Millisecond, time.Minute)
+		})
+
+		It("takes the images not looked up to fit and checks the others", func() {
+			tooLarge := check(
+				imagecache.ImageCheck{RootFS: "docker:///team/slow#v1"},
+				imagecache.ImageCheck{RootFS: "docker:///team/app#v1"},
+			)
+			Expect(tooLarge).To(Equal([]*rep.ImageTooLargeError{nil, {RequiredMB: 2000, AvailableMB: 1400}}))
+			Expect(logger).To(Say("image-size-check-timed-out"))
+		})
+	})
+})
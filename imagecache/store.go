@@ -0,0 +1,203 @@
+package imagecache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/grootfs/groot"
+	"code.cloudfoundry.org/grootfs/store/locksmith"
+	"code.cloudfoundry.org/lager"
+)
+
+var (
+	ErrInvalidVolumeID = errors.New("invalid volume id")
+	ErrVolumeInUse     = errors.New("volume is in use by an image")
+)
+
+// Volume is an image layer cached in a store. A volume is in use while an
+// image of a container depends on it, and can be reclaimed otherwise.
+type Volume struct {
+	ID        string
+	SizeBytes int64
+	LastUsed  time.Time
+	InUse     bool
+}
+
+//go:generate counterfeiter -o imagecachefakes/fake_store.go . Store
+
+// Store lists and deletes the image layers cached on the cell.
+type Store interface {
+	Volumes(logger lager.Logger) ([]Volume, error)
+	AvailableBytes(logger lager.Logger) (int64, error)
+	DeleteVolume(logger lager.Logger, id string) error
+}
+
+//go:generate counterfeiter -o imagecachefakes/fake_volume_driver.go . VolumeDriver
+
+// VolumeDriver is the GrootFS filesystem driver of a store, such as its
+// overlay-xfs driver, which destroys the volumes of the store along with
+// what the store accounts for them.
+type VolumeDriver interface {
+	DestroyVolume(logger lager.Logger, id string) error
+}
+
+type grootFSStore struct {
+	path      string
+	driver    VolumeDriver
+	locksmith *locksmith.FileSystem
+}
+
+// NewGrootFSStore returns a Store for the GrootFS store at path. The size of
+// a volume is read from its metadata rather than by walking the layer, and
+// the volumes an image depends on are read from the dependencies of the
+// image. Volumes are deleted through driver while holding the global lock of
+// the store, as GrootFS deletes them when it cleans the store, so that no
+// image is created on a volume as it is deleted.
+func NewGrootFSStore(path string, driver VolumeDriver) Store {
+	return &grootFSStore{
+		path:      path,
+		driver:    driver,
+		locksmith: locksmith.NewExclusiveFileSystem(path),
+	}
+}
+
+type volumeMetadata struct {
+	Size int64 `json:"Size"`
+}
+
+func (s *grootFSStore) Volumes(logger lager.Logger) ([]Volume, error) {
+	logger = logger.Session("grootfs-store", lager.Data{"path": s.path})
+
+	inUse, err := s.imageDependencies()
+	if err != nil {
+		logger.Error("failed-to-read-image-dependencies", err)
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(s.path, "volumes"))
+	if err != nil {
+		logger.Error("failed-to-list-volumes", err)
+		return nil, err
+	}
+
+	volumes := make([]Volume, 0, len(entries))
+	for _, entry := range entries {
+		id := entry.Name()
+		size, err := s.volumeSize(id)
+		if err != nil {
+			logger.Error("failed-to-read-volume-metadata", err, lager.Data{"volume": id})
+			continue
+		}
+
+		volumes = append(volumes, Volume{
+			ID:        id,
+			SizeBytes: size,
+			LastUsed:  entry.ModTime(),
+			InUse:     inUse[id],
+		})
+	}
+
+	return volumes, nil
+}
+
+func (s *grootFSStore) AvailableBytes(logger lager.Logger) (int64, error) {
+	available, err := availableBytes(s.path)
+	if err != nil {
+		logger.Error("failed-to-read-available-disk", err, lager.Data{"path": s.path})
+		return 0, err
+	}
+	return available, nil
+}
+
+// DeleteVolume destroys a volume. Volumes an image depends on are never
+// deleted.
+func (s *grootFSStore) DeleteVolume(logger lager.Logger, id string) error {
+	logger = logger.Session("delete-volume", lager.Data{"path": s.path, "volume": id})
+
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return ErrInvalidVolumeID
+	}
+
+	lockFile, err := s.locksmith.Lock(groot.GlobalLockKey)
+	if err != nil {
+		logger.Error("failed-to-lock-store", err)
+		return err
+	}
+	defer func() {
+		if err := s.locksmith.Unlock(lockFile); err != nil {
+			logger.Error("failed-to-unlock-store", err)
+		}
+	}()
+
+	inUse, err := s.imageDependencies()
+	if err != nil {
+		logger.Error("failed-to-read-image-dependencies", err)
+		return err
+	}
+	if inUse[id] {
+		return ErrVolumeInUse
+	}
+
+	err = s.driver.DestroyVolume(logger, id)
+	if err != nil {
+		logger.Error("failed-to-destroy-volume", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *grootFSStore) imageDependencies() (map[string]bool, error) {
+	inUse := map[string]bool{}
+
+	dependenciesPath := filepath.Join(s.path, "meta", "dependencies")
+	entries, err := ioutil.ReadDir(dependenciesPath)
+	if os.IsNotExist(err) {
+		return inUse, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "image:") {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(dependenciesPath, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var volumeIDs []string
+		if err := json.Unmarshal(contents, &volumeIDs); err != nil {
+			return nil, err
+		}
+		for _, id := range volumeIDs {
+			inUse[id] = true
+		}
+	}
+
+	return inUse, nil
+}
+
+func (s *grootFSStore) volumeSize(id string) (int64, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(s.path, "meta", "volume-"+id))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var metadata volumeMetadata
+	if err := json.Unmarshal(contents, &metadata); err != nil {
+		return 0, err
+	}
+	return metadata.Size, nil
+}
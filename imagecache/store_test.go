@@ -0,0 +1,157 @@
+package imagecache_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/imagecache/imagecachefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GrootFSStore", func() {
+	var (
+		storePath string
+		driver    *imagecachefakes.FakeVolumeDriver
+		store     imagecache.Store
+		logger    *lagertest.TestLogger
+	)
+
+	writeStoreFile := func(name, contents string) {
+		path := filepath.Join(storePath, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	createVolume := func(id string, size string, lastUsed time.Time) {
+		path := filepath.Join(storePath, "volumes", id)
+		Expect(os.MkdirAll(path, 0755)).To(Succeed())
+		Expect(os.Chtimes(path, lastUsed, lastUsed)).To(Succeed())
+		if size != "" {
+			writeStoreFile("meta/volume-"+id, `{"Size": `+size+`}`)
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		storePath, err = ioutil.TempDir("", "grootfs-store")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		driver = new(imagecachefakes.FakeVolumeDriver)
+		store = imagecache.NewGrootFSStore(storePath, driver)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(storePath)
+	})
+
+	Context("when images depend on some of the volumes", func() {
+		var lastUsed time.Time
+
+		BeforeEach(func() {
+			lastUsed = time.Now().Add(-time.Hour).Truncate(time.Second)
+			createVolume("layer-1", "1048576", lastUsed)
+			createVolume("layer-2", "2097152", lastUsed)
+			createVolume("layer-3", "", lastUsed)
+			writeStoreFile("meta/dependencies/image:container-1.json", `["layer-1"]`)
+			writeStoreFile("meta/dependencies/image:container-2.json", `["layer-1", "layer-3"]`)
+		})
+
+		It("lists the volumes with their size and whether they are in use", func() {
+			volumes, err := store.Volumes(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumes).To(ConsistOf(
+				imagecache.Volume{ID: "layer-1", SizeBytes: 1048576, LastUsed: lastUsed, InUse: true},
+				imagecache.Volume{ID: "layer-2", SizeBytes: 2097152, LastUsed: lastUsed, InUse: false},
+				imagecache.Volume{ID: "layer-3", SizeBytes: 0, LastUsed: lastUsed, InUse: true},
+			))
+		})
+	})
+
+	Context("when there are no image dependencies", func() {
+		BeforeEach(func() {
+			createVolume("layer-1", "1048576", time.Now())
+		})
+
+		It("reports every volume as not in use", func() {
+			volumes, err := store.Volumes(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].InUse).To(BeFalse())
+		})
+	})
+
+	Context("when the metadata of a volume is malformed", func() {
+		BeforeEach(func() {
+			createVolume("layer-1", "1048576", time.Now())
+			createVolume("layer-2", "", time.Now())
+			writeStoreFile("meta/volume-layer-2", "garbage")
+		})
+
+		It("skips the volume", func() {
+			volumes, err := store.Volumes(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].ID).To(Equal("layer-1"))
+		})
+	})
+
+	Context("when the image dependencies are malformed", func() {
+		BeforeEach(func() {
+			createVolume("layer-1", "1048576", time.Now())
+			writeStoreFile("meta/dependencies/image:container-1.json", "garbage")
+		})
+
+		It("returns an error", func() {
+			_, err := store.Volumes(logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the store has no volumes directory", func() {
+		It("returns an error", func() {
+			_, err := store.Volumes(logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("DeleteVolume", func() {
+		BeforeEach(func() {
+			createVolume("layer-1", "1048576", time.Now())
+			createVolume("layer-2", "2097152", time.Now())
+			writeStoreFile("meta/dependencies/image:container-1.json", `["layer-1"]`)
+		})
+
+		It("destroys the volume through the driver of the store", func() {
+			Expect(store.DeleteVolume(logger, "layer-2")).To(Succeed())
+
+			Expect(driver.DestroyVolumeCallCount()).To(Equal(1))
+			_, id := driver.DestroyVolumeArgsForCall(0)
+			Expect(id).To(Equal("layer-2"))
+		})
+
+		It("returns the error of the driver", func() {
+			driver.DestroyVolumeReturns(errors.New("device busy"))
+			Expect(store.DeleteVolume(logger, "layer-2")).To(MatchError("device busy"))
+		})
+
+		It("refuses to remove a volume an image depends on", func() {
+			Expect(store.DeleteVolume(logger, "layer-1")).To(MatchError(imagecache.ErrVolumeInUse))
+			Expect(driver.DestroyVolumeCallCount()).To(BeZero())
+		})
+
+		It("refuses volume ids outside of the store", func() {
+			Expect(store.DeleteVolume(logger, "../meta")).To(MatchError(imagecache.ErrInvalidVolumeID))
+			Expect(store.DeleteVolume(logger, "..")).To(MatchError(imagecache.ErrInvalidVolumeID))
+			Expect(driver.DestroyVolumeCallCount()).To(BeZero())
+		})
+	})
+})
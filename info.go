@@ -0,0 +1,34 @@
+package rep
+
+// Version is the version of this rep build. It is overridden at build time
+// with -ldflags "-X code.cloudfoundry.org/rep.Version=<version>".
+var Version = "dev"
+
+// SupportedAPIVersions are the versions of the HTTP API this rep serves,
+// oldest first.
+var SupportedAPIVersions = []string{"v1", "v2"}
+
+// Info describes a rep build and the features it supports, so that callers
+// can adapt to each cell during rolling upgrades.
+type Info struct {
+	Version      string   `json:"version"`
+	APIVersions  []string `json:"api_versions"`
+	FeatureFlags []string `json:"feature_flags"`
+	Limits       Limits   `json:"limits"`
+}
+
+// Limits are the bounds the rep enforces on the requests it serves. A zero
+// value means the limit is not enforced.
+type Limits struct {
+	MaxRequestBodyBytes int64 `json:"max_request_body_bytes"`
+	MaxWorkBatchSize    int   `json:"max_work_batch_size"`
+}
+
+func NewInfo(featureFlags []string, limits Limits) Info {
+	return Info{
+		Version:      Version,
+		APIVersions:  SupportedAPIVersions,
+		FeatureFlags: featureFlags,
+		Limits:       limits,
+	}
+}
@@ -0,0 +1,193 @@
+package rep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+)
+
+// InitStepsTag holds the JSON encoded init steps of an LRP instance on its
+// container.
+const InitStepsTag = "init-steps"
+
+// The statuses of the init steps of an LRP instance.
+const (
+	InitStepPending   = "pending"
+	InitStepRunning   = "running"
+	InitStepSucceeded = "succeeded"
+)
+
+const defaultInitStepUser = "vcap"
+
+var (
+	ErrInvalidInitStep        = errors.New("an init step needs a name, a clean absolute path and a non-negative timeout")
+	ErrInitStepPathNotAllowed = errors.New("the path of the init step is not allowed on this cell")
+)
+
+// InitStep is a command an LRP instance runs to completion in its container
+// before its main process starts, such as a schema migration or fetching its
+// configuration. A step that fails, or that is still running after TimeoutMs,
+// fails the instance. A step without a timeout may run for as long as the
+// start timeout of the instance allows. Steps run as the user the process of
+// the instance runs as, never as one of their own.
+type InitStep struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	Args      []string `json:"args,omitempty"`
+	TimeoutMs int64    `json:"timeout_ms,omitempty"`
+}
+
+func (s *InitStep) Validate() error {
+	if s.Name == "" || !path.IsAbs(s.Path) || path.Clean(s.Path) != s.Path || s.TimeoutMs < 0 {
+		return ErrInvalidInitStep
+	}
+	return nil
+}
+
+// ValidateAllowed returns ErrInitStepPathNotAllowed unless the path of the
+// step is one of allowed, or inside one of the directories among them, given
+// with a trailing slash.
+func (s *InitStep) ValidateAllowed(allowed []string) error {
+	for _, entry := range allowed {
+		if entry == s.Path || (strings.HasSuffix(entry, "/") && strings.HasPrefix(s.Path, entry)) {
+			return nil
+		}
+	}
+	return ErrInitStepPathNotAllowed
+}
+
+// AddInitStepsTag records steps on the tags of the container of an LRP
+// instance, so that they can be added to its run request once it is claimed.
+func AddInitStepsTag(tags executor.Tags, steps []InitStep) {
+	if len(steps) == 0 {
+		return
+	}
+	encoded, _ := json.Marshal(steps)
+	tags[InitStepsTag] = string(encoded)
+}
+
+// InitStepsFromTags returns the init steps recorded on the tags of a
+// container, or nil when it has none.
+func InitStepsFromTags(tags executor.Tags) ([]InitStep, error) {
+	encoded, ok := tags[InitStepsTag]
+	if !ok {
+		return nil, nil
+	}
+
+	var steps []InitStep
+	err := json.Unmarshal([]byte(encoded), &steps)
+	if err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// AddInitSteps runs steps in order once the setup of runInfo is done, and
+// before its action starts. The steps run as the user of the first process of
+// the action.
+func AddInitSteps(runInfo *executor.RunInfo, steps []InitStep) {
+	if len(steps) == 0 {
+		return
+	}
+
+	user := actionUser(runInfo.Action)
+	if user == "" {
+		user = defaultInitStepUser
+	}
+
+	actions := []models.ActionInterface{}
+	if runInfo.Setup != nil {
+		actions = append(actions, runInfo.Setup.GetValue().(models.ActionInterface))
+	}
+	for i := range steps {
+		actions = append(actions, initStepAction(&steps[i], user))
+	}
+	runInfo.Setup = models.WrapAction(models.Serial(actions...))
+}
+
+// actionUser returns the user the first process of action runs as, or an
+// empty string when it runs none.
+func actionUser(action *models.Action) string {
+	if action == nil {
+		return ""
+	}
+
+	var nested []*models.Action
+	switch a := action.GetValue().(type) {
+	case *models.RunAction:
+		return a.User
+	case *models.TimeoutAction:
+		nested = []*models.Action{a.Action}
+	case *models.TryAction:
+		nested = []*models.Action{a.Action}
+	case *models.EmitProgressAction:
+		nested = []*models.Action{a.Action}
+	case *models.SerialAction:
+		nested = a.Actions
+	case *models.ParallelAction:
+		nested = a.Actions
+	case *models.CodependentAction:
+		nested = a.Actions
+	}
+	for _, action := range nested {
+		if user := actionUser(action); user != "" {
+			return user
+		}
+	}
+	return ""
+}
+
+func initStepAction(step *InitStep, user string) models.ActionInterface {
+	var action models.ActionInterface = &models.RunAction{
+		Path: step.Path,
+		Args: step.Args,
+		User: user,
+	}
+	if step.TimeoutMs > 0 {
+		action = models.Timeout(action, time.Duration(step.TimeoutMs)*time.Millisecond)
+	}
+	return models.EmitProgressFor(
+		action,
+		fmt.Sprintf("Running init step %s", step.Name),
+		fmt.Sprintf("Init step %s succeeded", step.Name),
+		fmt.Sprintf("Init step %s failed", step.Name),
+	)
+}
+
+// InitStepStatus is the status of one of the init steps of an LRP instance.
+type InitStepStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// InitStepStatuses returns the status of each of steps, in order, for the LRP
+// instance running in container. The executor reports the progress of the
+// setup of a container as a whole, so the steps are pending until the setup
+// starts, running while it does, and succeeded once the instance runs. It is
+// nil once the container has completed, as the executor does not report
+// whether the container failed in its setup or later.
+func InitStepStatuses(container *executor.Container, steps []InitStep) []InitStepStatus {
+	var status string
+	switch container.State {
+	case executor.StateReserved, executor.StateInitializing:
+		status = InitStepPending
+	case executor.StateCreated:
+		status = InitStepRunning
+	case executor.StateRunning:
+		status = InitStepSucceeded
+	default:
+		return nil
+	}
+
+	var statuses []InitStepStatus
+	for i := range steps {
+		statuses = append(statuses, InitStepStatus{Name: steps[i].Name, Status: status})
+	}
+	return statuses
+}
@@ -0,0 +1,157 @@
+package rep_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InitSteps", func() {
+	var steps []rep.InitStep
+
+	BeforeEach(func() {
+		steps = []rep.InitStep{
+			{Name: "migrate", Path: "/bin/migrate", Args: []string{"up"}, TimeoutMs: 30000},
+			{Name: "fetch-config", Path: "/bin/fetch"},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("requires a name, a clean absolute path and a non-negative timeout", func() {
+			Expect(steps[0].Validate()).To(Succeed())
+			Expect((&rep.InitStep{Path: "/bin/migrate"}).Validate()).To(Equal(rep.ErrInvalidInitStep))
+			Expect((&rep.InitStep{Name: "migrate"}).Validate()).To(Equal(rep.ErrInvalidInitStep))
+			Expect((&rep.InitStep{Name: "migrate", Path: "bin/migrate"}).Validate()).To(Equal(rep.ErrInvalidInitStep))
+			Expect((&rep.InitStep{Name: "migrate", Path: "/app/bin/../../bin/sh"}).Validate()).To(Equal(rep.ErrInvalidInitStep))
+			Expect((&rep.InitStep{Name: "migrate", Path: "/bin/migrate", TimeoutMs: -1}).Validate()).To(Equal(rep.ErrInvalidInitStep))
+		})
+	})
+
+	Describe("ValidateAllowed", func() {
+		var allowed []string
+
+		BeforeEach(func() {
+			allowed = []string{"/bin/migrate", "/home/vcap/app/bin/"}
+		})
+
+		It("allows the listed paths and the paths inside the listed directories", func() {
+			Expect((&rep.InitStep{Path: "/bin/migrate"}).ValidateAllowed(allowed)).To(Succeed())
+			Expect((&rep.InitStep{Path: "/home/vcap/app/bin/warm"}).ValidateAllowed(allowed)).To(Succeed())
+		})
+
+		It("rejects any other path", func() {
+			Expect((&rep.InitStep{Path: "/bin/migrate-all"}).ValidateAllowed(allowed)).To(Equal(rep.ErrInitStepPathNotAllowed))
+			Expect((&rep.InitStep{Path: "/home/vcap/app/binary"}).ValidateAllowed(allowed)).To(Equal(rep.ErrInitStepPathNotAllowed))
+			Expect((&rep.InitStep{Path: "/bin/sh"}).ValidateAllowed(allowed)).To(Equal(rep.ErrInitStepPathNotAllowed))
+		})
+
+		It("rejects every path when nothing is allowed", func() {
+			Expect((&rep.InitStep{Path: "/bin/migrate"}).ValidateAllowed(nil)).To(Equal(rep.ErrInitStepPathNotAllowed))
+		})
+	})
+
+	Describe("tags", func() {
+		It("round trips the steps through the container tags", func() {
+			tags := executor.Tags{}
+			rep.AddInitStepsTag(tags, steps)
+			Expect(tags).To(HaveKey(rep.InitStepsTag))
+
+			decoded, err := rep.InitStepsFromTags(tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(steps))
+		})
+
+		It("does not tag containers without steps", func() {
+			tags := executor.Tags{}
+			rep.AddInitStepsTag(tags, nil)
+			Expect(tags).To(BeEmpty())
+
+			decoded, err := rep.InitStepsFromTags(tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(BeNil())
+		})
+
+		It("fails on a malformed tag", func() {
+			_, err := rep.InitStepsFromTags(executor.Tags{rep.InitStepsTag: "not-json"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("AddInitSteps", func() {
+		It("runs the steps in order after the existing setup as the user of the instance", func() {
+			setup := &models.DownloadAction{From: "http://example.com/droplet", To: "/app", User: "vcap"}
+			action := models.Codependent(
+				models.Timeout(&models.RunAction{Path: "/app/start", User: "app-user"}, time.Minute),
+				&models.RunAction{Path: "/etc/cf-assets/envoy", User: "root"},
+			)
+			runInfo := executor.RunInfo{Setup: models.WrapAction(setup), Action: models.WrapAction(action)}
+
+			rep.AddInitSteps(&runInfo, steps)
+
+			Expect(runInfo.Setup).To(Equal(models.WrapAction(models.Serial(
+				setup,
+				models.EmitProgressFor(
+					models.Timeout(&models.RunAction{Path: "/bin/migrate", Args: []string{"up"}, User: "app-user"}, 30*time.Second),
+					"Running init step migrate", "Init step migrate succeeded", "Init step migrate failed",
+				),
+				models.EmitProgressFor(
+					&models.RunAction{Path: "/bin/fetch", User: "app-user"},
+					"Running init step fetch-config", "Init step fetch-config succeeded", "Init step fetch-config failed",
+				),
+			))))
+		})
+
+		It("runs the steps as vcap when the instance runs no process", func() {
+			runInfo := executor.RunInfo{}
+
+			rep.AddInitSteps(&runInfo, steps[1:])
+
+			Expect(runInfo.Setup).To(Equal(models.WrapAction(models.Serial(
+				models.EmitProgressFor(
+					&models.RunAction{Path: "/bin/fetch", User: "vcap"},
+					"Running init step fetch-config", "Init step fetch-config succeeded", "Init step fetch-config failed",
+				),
+			))))
+		})
+
+		It("leaves the run info alone without steps", func() {
+			runInfo := executor.RunInfo{}
+			rep.AddInitSteps(&runInfo, nil)
+			Expect(runInfo.Setup).To(BeNil())
+		})
+	})
+
+	Describe("InitStepStatuses", func() {
+		var container executor.Container
+
+		statuses := func(status string) []rep.InitStepStatus {
+			return []rep.InitStepStatus{
+				{Name: "migrate", Status: status},
+				{Name: "fetch-config", Status: status},
+			}
+		}
+
+		It("reports each step, following the state of the container", func() {
+			container.State = executor.StateReserved
+			Expect(rep.InitStepStatuses(&container, steps)).To(Equal(statuses(rep.InitStepPending)))
+			container.State = executor.StateInitializing
+			Expect(rep.InitStepStatuses(&container, steps)).To(Equal(statuses(rep.InitStepPending)))
+			container.State = executor.StateCreated
+			Expect(rep.InitStepStatuses(&container, steps)).To(Equal(statuses(rep.InitStepRunning)))
+			container.State = executor.StateRunning
+			Expect(rep.InitStepStatuses(&container, steps)).To(Equal(statuses(rep.InitStepSucceeded)))
+			container.State = executor.StateCompleted
+			Expect(rep.InitStepStatuses(&container, steps)).To(BeNil())
+		})
+
+		It("is empty for containers without steps", func() {
+			container.State = executor.StateRunning
+			Expect(rep.InitStepStatuses(&container, nil)).To(BeNil())
+		})
+	})
+})
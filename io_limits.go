@@ -0,0 +1,79 @@
+package rep
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/executor"
+)
+
+// IOLimitsTag holds the JSON encoded I/O limits of work on its container.
+const IOLimitsTag = "io-limits"
+
+// The kinds of I/O limits a cell may apply to its containers.
+const (
+	IOLimitKindDisk    = "disk"
+	IOLimitKindNetwork = "network"
+)
+
+// IOLimits throttle the disk and network I/O of a container, so that work
+// that is heavy on disk or network cannot starve the other containers of the
+// cell. A zero limit leaves that I/O unthrottled.
+type IOLimits struct {
+	ReadIOPS            uint64 `json:"read_iops,omitempty"`
+	WriteIOPS           uint64 `json:"write_iops,omitempty"`
+	ReadBytesPerSecond  uint64 `json:"read_bytes_per_second,omitempty"`
+	WriteBytesPerSecond uint64 `json:"write_bytes_per_second,omitempty"`
+	// The network limits apply to the traffic into and out of the
+	// container.
+	IngressBytesPerSecond uint64 `json:"ingress_bytes_per_second,omitempty"`
+	EgressBytesPerSecond  uint64 `json:"egress_bytes_per_second,omitempty"`
+}
+
+// Disk returns the disk limits of l alone.
+func (l IOLimits) Disk() IOLimits {
+	return IOLimits{
+		ReadIOPS:            l.ReadIOPS,
+		WriteIOPS:           l.WriteIOPS,
+		ReadBytesPerSecond:  l.ReadBytesPerSecond,
+		WriteBytesPerSecond: l.WriteBytesPerSecond,
+	}
+}
+
+// Network returns the network limits of l alone.
+func (l IOLimits) Network() IOLimits {
+	return IOLimits{
+		IngressBytesPerSecond: l.IngressBytesPerSecond,
+		EgressBytesPerSecond:  l.EgressBytesPerSecond,
+	}
+}
+
+// Empty reports whether l leaves all I/O unthrottled.
+func (l IOLimits) Empty() bool {
+	return l == IOLimits{}
+}
+
+// AddIOLimitsTag records the I/O limits of work on the tags of its
+// container, so that the cell can apply them once the container is created.
+func AddIOLimitsTag(tags executor.Tags, limits *IOLimits) {
+	if limits == nil || limits.Empty() {
+		return
+	}
+	encoded, _ := json.Marshal(limits)
+	tags[IOLimitsTag] = string(encoded)
+}
+
+// IOLimitsFromTags returns the I/O limits recorded on the tags of a
+// container, or nil when it has none.
+func IOLimitsFromTags(tags executor.Tags) (*IOLimits, error) {
+	encoded, ok := tags[IOLimitsTag]
+	if !ok {
+		return nil, nil
+	}
+
+	limits := &IOLimits{}
+	err := json.Unmarshal([]byte(encoded), limits)
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
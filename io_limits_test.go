@@ -0,0 +1,45 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IOLimits", func() {
+	It("splits into its disk and network limits", func() {
+		limits := rep.IOLimits{ReadIOPS: 1, WriteIOPS: 2, ReadBytesPerSecond: 3, WriteBytesPerSecond: 4, IngressBytesPerSecond: 5, EgressBytesPerSecond: 6}
+		Expect(limits.Disk()).To(Equal(rep.IOLimits{ReadIOPS: 1, WriteIOPS: 2, ReadBytesPerSecond: 3, WriteBytesPerSecond: 4}))
+		Expect(limits.Network()).To(Equal(rep.IOLimits{IngressBytesPerSecond: 5, EgressBytesPerSecond: 6}))
+		Expect(limits.Empty()).To(BeFalse())
+		Expect(rep.IOLimits{}.Empty()).To(BeTrue())
+	})
+
+	It("round trips through the tags of a container", func() {
+		tags := executor.Tags{}
+		rep.AddIOLimitsTag(tags, &rep.IOLimits{ReadIOPS: 100, EgressBytesPerSecond: 1024})
+		Expect(tags[rep.IOLimitsTag]).To(MatchJSON(`{"read_iops":100,"egress_bytes_per_second":1024}`))
+
+		limits, err := rep.IOLimitsFromTags(tags)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(Equal(&rep.IOLimits{ReadIOPS: 100, EgressBytesPerSecond: 1024}))
+	})
+
+	It("leaves the tags alone when the work has no limits", func() {
+		tags := executor.Tags{}
+		rep.AddIOLimitsTag(tags, nil)
+		rep.AddIOLimitsTag(tags, &rep.IOLimits{})
+		Expect(tags).To(BeEmpty())
+
+		limits, err := rep.IOLimitsFromTags(tags)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(BeNil())
+	})
+
+	It("fails on malformed limits", func() {
+		_, err := rep.IOLimitsFromTags(executor.Tags{rep.IOLimitsTag: "{"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,108 @@
+package iothrottle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o iothrottlefakes/fake_applier.go . Applier
+
+// Applier applies I/O limits to the containers of the cell.
+type Applier interface {
+	// Supported returns the kinds of I/O limits the applier applies.
+	Supported() []string
+	// Apply applies the limits it supports to the container and returns the
+	// limits it applied. The others are left unthrottled.
+	Apply(logger lager.Logger, guid string, limits rep.IOLimits) (rep.IOLimits, error)
+}
+
+type cgroupApplier struct {
+	root             string
+	containersParent string
+	device           string
+	version          int
+}
+
+// NewCgroupApplier returns an Applier that throttles the disk I/O of
+// containers through their cgroups, on the block device given by its
+// MAJ:MIN numbers. Version 2 hierarchies take the limits in the io.max file
+// of the container, and version 1 hierarchies in the throttle files of its
+// blkio cgroup. Network limits are not applied.
+func NewCgroupApplier(root, containersParent, device string, version int) Applier {
+	return &cgroupApplier{
+		root:             root,
+		containersParent: containersParent,
+		device:           device,
+		version:          version,
+	}
+}
+
+func (a *cgroupApplier) Supported() []string {
+	return []string{rep.IOLimitKindDisk}
+}
+
+func (a *cgroupApplier) Apply(logger lager.Logger, guid string, limits rep.IOLimits) (rep.IOLimits, error) {
+	disk := limits.Disk()
+	if disk.Empty() {
+		return rep.IOLimits{}, nil
+	}
+
+	var err error
+	if a.version == 2 {
+		err = a.applyV2(guid, disk)
+	} else {
+		err = a.applyV1(guid, disk)
+	}
+	if err != nil {
+		logger.Error("failed-to-apply-io-limits", err, lager.Data{"guid": guid})
+		return rep.IOLimits{}, err
+	}
+	return disk, nil
+}
+
+func (a *cgroupApplier) applyV2(guid string, limits rep.IOLimits) error {
+	line := fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s",
+		a.device,
+		ioMax(limits.ReadBytesPerSecond),
+		ioMax(limits.WriteBytesPerSecond),
+		ioMax(limits.ReadIOPS),
+		ioMax(limits.WriteIOPS),
+	)
+	return ioutil.WriteFile(filepath.Join(a.root, a.containersParent, guid, "io.max"), []byte(line), 0644)
+}
+
+func (a *cgroupApplier) applyV1(guid string, limits rep.IOLimits) error {
+	dir := filepath.Join(a.root, "blkio", a.containersParent, guid)
+	files := []struct {
+		name  string
+		value uint64
+	}{
+		{"blkio.throttle.read_bps_device", limits.ReadBytesPerSecond},
+		{"blkio.throttle.write_bps_device", limits.WriteBytesPerSecond},
+		{"blkio.throttle.read_iops_device", limits.ReadIOPS},
+		{"blkio.throttle.write_iops_device", limits.WriteIOPS},
+	}
+	for _, file := range files {
+		if file.value == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%s %d", a.device, file.value)
+		if err := ioutil.WriteFile(filepath.Join(dir, file.name), []byte(line), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ioMax formats a limit for io.max, where max leaves the I/O unthrottled.
+func ioMax(value uint64) string {
+	if value == 0 {
+		return "max"
+	}
+	return strconv.FormatUint(value, 10)
+}
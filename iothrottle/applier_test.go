@@ -0,0 +1,87 @@
+package iothrottle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/iothrottle"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CgroupApplier", func() {
+	var (
+		logger *lagertest.TestLogger
+		root   string
+		limits rep.IOLimits
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		var err error
+		root, err = ioutil.TempDir("", "cgroups")
+		Expect(err).NotTo(HaveOccurred())
+		limits = rep.IOLimits{ReadIOPS: 100, WriteBytesPerSecond: 1024, IngressBytesPerSecond: 2048}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(root)).To(Succeed())
+	})
+
+	readFile := func(elem ...string) string {
+		contents, err := ioutil.ReadFile(filepath.Join(append([]string{root}, elem...)...))
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	It("supports disk limits only", func() {
+		applier := iothrottle.NewCgroupApplier(root, "garden", "8:0", 2)
+		Expect(applier.Supported()).To(Equal([]string{rep.IOLimitKindDisk}))
+	})
+
+	Context("on a version 2 hierarchy", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(root, "garden", "guid-1"), 0755)).To(Succeed())
+		})
+
+		It("writes the disk limits to io.max and returns them", func() {
+			applier := iothrottle.NewCgroupApplier(root, "garden", "8:0", 2)
+			applied, err := applier.Apply(logger, "guid-1", limits)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied).To(Equal(rep.IOLimits{ReadIOPS: 100, WriteBytesPerSecond: 1024}))
+			Expect(readFile("garden", "guid-1", "io.max")).To(Equal("8:0 rbps=max wbps=1024 riops=100 wiops=max"))
+		})
+	})
+
+	Context("on a version 1 hierarchy", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(root, "blkio", "garden", "guid-1"), 0755)).To(Succeed())
+		})
+
+		It("writes the disk limits to the blkio throttle files", func() {
+			applier := iothrottle.NewCgroupApplier(root, "garden", "8:0", 1)
+			_, err := applier.Apply(logger, "guid-1", limits)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readFile("blkio", "garden", "guid-1", "blkio.throttle.read_iops_device")).To(Equal("8:0 100"))
+			Expect(readFile("blkio", "garden", "guid-1", "blkio.throttle.write_bps_device")).To(Equal("8:0 1024"))
+			Expect(filepath.Join(root, "blkio", "garden", "guid-1", "blkio.throttle.read_bps_device")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	It("applies nothing when the work has no disk limits", func() {
+		applier := iothrottle.NewCgroupApplier(root, "garden", "8:0", 2)
+		applied, err := applier.Apply(logger, "guid-1", rep.IOLimits{EgressBytesPerSecond: 1024})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied.Empty()).To(BeTrue())
+	})
+
+	It("fails when the container has no cgroup", func() {
+		applier := iothrottle.NewCgroupApplier(root, "garden", "8:0", 2)
+		_, err := applier.Apply(logger, "missing-guid", limits)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,13 @@
+package iothrottle_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestIOThrottle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IO Throttle Suite")
+}
@@ -0,0 +1 @@
+package iothrottlefakes // import "code.cloudfoundry.org/rep/iothrottle/iothrottlefakes"
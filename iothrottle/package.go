@@ -0,0 +1 @@
+package iothrottle // import "code.cloudfoundry.org/rep/iothrottle"
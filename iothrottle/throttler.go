@@ -0,0 +1,201 @@
+package iothrottle
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Throttler applies the I/O limits recorded on the tags of the containers of
+// every executor of the cell once they are created, and keeps the limits it
+// applied until they complete. The executors emit no event when they create
+// a container, so the throttler looks for the containers created since it
+// last looked every poll interval, rather than waiting until they are
+// healthy and running to throttle them.
+type Throttler struct {
+	logger          lager.Logger
+	executorClients []executor.Client
+	applier         Applier
+	clock           clock.Clock
+	pollInterval    time.Duration
+
+	lock    sync.RWMutex
+	applied map[string]rep.IOLimits
+	// throttled holds the containers whose limits were applied or failed to
+	// apply, so that each container is throttled once. Only Run uses it.
+	throttled map[string]struct{}
+}
+
+func NewThrottler(logger lager.Logger, executorClients []executor.Client, applier Applier, clock clock.Clock, pollInterval time.Duration) *Throttler {
+	return &Throttler{
+		logger:          logger.Session("io-throttler"),
+		executorClients: executorClients,
+		applier:         applier,
+		clock:           clock,
+		pollInterval:    pollInterval,
+		applied:         map[string]rep.IOLimits{},
+		throttled:       map[string]struct{}{},
+	}
+}
+
+func (t *Throttler) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	sources := make([]executor.EventSource, 0, len(t.executorClients))
+	for _, executorClient := range t.executorClients {
+		source, err := executorClient.SubscribeToEvents(t.logger)
+		if err != nil {
+			t.logger.Error("failed-subscribing-to-events", err)
+			for _, source := range sources {
+				source.Close()
+			}
+			return err
+		}
+		sources = append(sources, source)
+	}
+
+	received := make(chan executor.Event)
+	closed := make(chan struct{}, len(sources))
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		for _, source := range sources {
+			source.Close()
+		}
+	}()
+
+	for _, source := range sources {
+		go func(source executor.EventSource) {
+			for {
+				event, err := source.Next()
+				if err != nil {
+					closed <- struct{}{}
+					return
+				}
+				select {
+				case received <- event:
+				case <-done:
+					return
+				}
+			}
+		}(source)
+	}
+
+	// Containers that were already created when the rep started get their
+	// limits applied again, so that the rep reports them after a restart.
+	t.throttleCreated()
+
+	close(ready)
+
+	ticker := t.clock.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-received:
+			t.handle(event)
+		case <-ticker.C():
+			t.throttleCreated()
+		case <-closed:
+			t.logger.Info("event-stream-closed")
+			return nil
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// SupportedIOLimits returns the kinds of I/O limits the cell applies.
+func (t *Throttler) SupportedIOLimits() []string {
+	return t.applier.Supported()
+}
+
+// AppliedIOLimits returns the limits applied to the container with guid.
+func (t *Throttler) AppliedIOLimits(guid string) (rep.IOLimits, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	limits, ok := t.applied[guid]
+	return limits, ok
+}
+
+func (t *Throttler) handle(event executor.Event) {
+	lifecycle, ok := event.(executor.LifecycleEvent)
+	if !ok {
+		return
+	}
+	container := lifecycle.Container()
+
+	switch event.EventType() {
+	case executor.EventTypeContainerRunning:
+		t.throttle(container)
+	case executor.EventTypeContainerComplete:
+		t.forget(container.Guid)
+	}
+}
+
+// throttleCreated throttles the containers of every executor that have been
+// created since it last ran, and forgets the containers that are gone.
+func (t *Throttler) throttleCreated() {
+	listed := map[string]struct{}{}
+	listedAll := true
+	for _, executorClient := range t.executorClients {
+		containers, err := executorClient.ListContainers(t.logger)
+		if err != nil {
+			t.logger.Error("failed-to-list-containers", err)
+			listedAll = false
+			continue
+		}
+		for _, container := range containers {
+			listed[container.Guid] = struct{}{}
+			if container.State == executor.StateCreated || container.State == executor.StateRunning {
+				t.throttle(container)
+			}
+		}
+	}
+
+	if !listedAll {
+		return
+	}
+	for guid := range t.throttled {
+		if _, ok := listed[guid]; !ok {
+			t.forget(guid)
+		}
+	}
+}
+
+func (t *Throttler) forget(guid string) {
+	delete(t.throttled, guid)
+	t.lock.Lock()
+	delete(t.applied, guid)
+	t.lock.Unlock()
+}
+
+func (t *Throttler) throttle(container executor.Container) {
+	if _, ok := t.throttled[container.Guid]; ok {
+		return
+	}
+	t.throttled[container.Guid] = struct{}{}
+
+	logger := t.logger.Session("throttle", lager.Data{"guid": container.Guid})
+
+	limits, err := rep.IOLimitsFromTags(container.Tags)
+	if err != nil {
+		logger.Error("cannot-unmarshal-io-limits", err)
+		return
+	}
+	if limits == nil {
+		return
+	}
+
+	applied, err := t.applier.Apply(logger, container.Guid, *limits)
+	if err != nil || applied.Empty() {
+		return
+	}
+
+	t.lock.Lock()
+	t.applied[container.Guid] = applied
+	t.lock.Unlock()
+}
@@ -0,0 +1,182 @@
+package iothrottle_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	efakes "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/iothrottle"
+	"code.cloudfoundry.org/rep/iothrottle/iothrottlefakes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Throttler", func() {
+	var (
+		logger         *lagertest.TestLogger
+		executorClient *efakes.FakeClient
+		backendClient  *efakes.FakeClient
+		fakeClock      *fakeclock.FakeClock
+		applier        *iothrottlefakes.FakeApplier
+		events         chan executor.Event
+		backendEvents  chan struct{}
+		throttler      *iothrottle.Throttler
+		process        ifrit.Process
+		limits         rep.IOLimits
+		container      executor.Container
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(efakes.FakeClient)
+		backendClient = new(efakes.FakeClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		applier = new(iothrottlefakes.FakeApplier)
+		applier.SupportedReturns([]string{rep.IOLimitKindDisk})
+		applier.ApplyStub = func(_ lager.Logger, _ string, limits rep.IOLimits) (rep.IOLimits, error) {
+			return limits.Disk(), nil
+		}
+
+		events = make(chan executor.Event)
+		backendEvents = make(chan struct{})
+		eventSource := new(efakes.FakeEventSource)
+		eventSource.NextStub = func() (executor.Event, error) {
+			event, ok := <-events
+			if !ok {
+				return nil, errors.New("closed")
+			}
+			return event, nil
+		}
+		executorClient.SubscribeToEventsReturns(eventSource, nil)
+		backendSource := new(efakes.FakeEventSource)
+		backendSource.NextStub = func() (executor.Event, error) {
+			<-backendEvents
+			return nil, errors.New("closed")
+		}
+		backendClient.SubscribeToEventsReturns(backendSource, nil)
+
+		limits = rep.IOLimits{ReadIOPS: 100, EgressBytesPerSecond: 1024}
+		container = executor.Container{Guid: "guid-1", State: executor.StateRunning, Tags: executor.Tags{}}
+		rep.AddIOLimitsTag(container.Tags, &limits)
+	})
+
+	JustBeforeEach(func() {
+		throttler = iothrottle.NewThrottler(logger, []executor.Client{executorClient, backendClient}, applier, fakeClock, time.Second)
+		process = ginkgomon.Invoke(throttler)
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+		close(backendEvents)
+	})
+
+	It("reports the kinds of limits its applier supports", func() {
+		Expect(throttler.SupportedIOLimits()).To(Equal([]string{rep.IOLimitKindDisk}))
+	})
+
+	It("applies the limits of containers once they are running, until they complete", func() {
+		events <- executor.NewContainerRunningEvent(container)
+
+		Eventually(applier.ApplyCallCount).Should(Equal(1))
+		_, guid, applied := applier.ApplyArgsForCall(0)
+		Expect(guid).To(Equal("guid-1"))
+		Expect(applied).To(Equal(limits))
+		Eventually(func() bool {
+			_, ok := throttler.AppliedIOLimits("guid-1")
+			return ok
+		}).Should(BeTrue())
+		appliedLimits, _ := throttler.AppliedIOLimits("guid-1")
+		Expect(appliedLimits).To(Equal(rep.IOLimits{ReadIOPS: 100}))
+
+		events <- executor.NewContainerCompleteEvent(container)
+		Eventually(func() bool {
+			_, ok := throttler.AppliedIOLimits("guid-1")
+			return ok
+		}).Should(BeFalse())
+	})
+
+	It("leaves containers without limits alone", func() {
+		events <- executor.NewContainerRunningEvent(executor.Container{Guid: "guid-2"})
+		events <- executor.NewContainerRunningEvent(container)
+
+		Eventually(applier.ApplyCallCount).Should(Equal(1))
+		_, guid, _ := applier.ApplyArgsForCall(0)
+		Expect(guid).To(Equal("guid-1"))
+	})
+
+	Context("when containers are already running", func() {
+		BeforeEach(func() {
+			executorClient.ListContainersReturns([]executor.Container{container}, nil)
+		})
+
+		It("applies their limits before becoming ready", func() {
+			Expect(applier.ApplyCallCount()).To(Equal(1))
+			_, ok := throttler.AppliedIOLimits("guid-1")
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	It("applies the limits of containers once they are created, on every executor", func() {
+		created := container
+		created.State = executor.StateCreated
+		backendClient.ListContainersReturns([]executor.Container{created}, nil)
+
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+
+		Eventually(applier.ApplyCallCount).Should(Equal(1))
+		_, guid, _ := applier.ApplyArgsForCall(0)
+		Expect(guid).To(Equal("guid-1"))
+		Eventually(func() bool {
+			_, ok := throttler.AppliedIOLimits("guid-1")
+			return ok
+		}).Should(BeTrue())
+
+		events <- executor.NewContainerRunningEvent(container)
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+		Consistently(applier.ApplyCallCount).Should(Equal(1))
+	})
+
+	It("forgets the containers that are gone", func() {
+		created := container
+		created.State = executor.StateCreated
+		backendClient.ListContainersReturns([]executor.Container{created}, nil)
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+		Eventually(applier.ApplyCallCount).Should(Equal(1))
+
+		backendClient.ListContainersReturns(nil, nil)
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+		Eventually(func() bool {
+			_, ok := throttler.AppliedIOLimits("guid-1")
+			return ok
+		}).Should(BeFalse())
+	})
+
+	Context("when the limits cannot be applied", func() {
+		BeforeEach(func() {
+			applier.ApplyStub = nil
+			applier.ApplyReturns(rep.IOLimits{}, errors.New("boom"))
+			executorClient.ListContainersReturns([]executor.Container{container}, nil)
+		})
+
+		It("does not report them as applied", func() {
+			Expect(applier.ApplyCallCount()).To(Equal(1))
+			_, ok := throttler.AppliedIOLimits("guid-1")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when the event stream closes", func() {
+		It("exits", func() {
+			close(events)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package rep
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/executor"
+)
+
+// LabelTagPrefix prefixes the container tags holding the labels of the work
+// the container runs.
+const LabelTagPrefix = "label:"
+
+var ErrInvalidLabelSelector = errors.New("invalid label selector")
+
+// AddLabelTags adds labels to the tags of a container.
+func AddLabelTags(tags executor.Tags, labels map[string]string) {
+	for key, value := range labels {
+		tags[LabelTagPrefix+key] = value
+	}
+}
+
+// LabelsFromTags returns the labels held in the tags of a container, or nil
+// when it has none.
+func LabelsFromTags(tags executor.Tags) map[string]string {
+	var labels map[string]string
+	for tag, value := range tags {
+		if !strings.HasPrefix(tag, LabelTagPrefix) {
+			continue
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[strings.TrimPrefix(tag, LabelTagPrefix)] = value
+	}
+	return labels
+}
+
+type labelOperator int
+
+const (
+	labelExists labelOperator = iota
+	labelDoesNotExist
+	labelEquals
+	labelNotEquals
+)
+
+type labelRequirement struct {
+	key      string
+	operator labelOperator
+	value    string
+}
+
+// LabelSelector selects work by its labels. It is parsed from a comma
+// separated list of requirements, all of which must hold: "key=value" and
+// "key!=value" compare the value of a label, "key" requires the label to be
+// set and "!key" requires it not to be. The empty selector selects all work.
+type LabelSelector struct {
+	requirements []labelRequirement
+}
+
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	parsed := LabelSelector{}
+	if strings.TrimSpace(selector) == "" {
+		return parsed, nil
+	}
+
+	for _, requirement := range strings.Split(selector, ",") {
+		requirement = strings.TrimSpace(requirement)
+
+		var r labelRequirement
+		switch {
+		case strings.Contains(requirement, "!="):
+			parts := strings.SplitN(requirement, "!=", 2)
+			r = labelRequirement{key: parts[0], operator: labelNotEquals, value: parts[1]}
+		case strings.Contains(requirement, "="):
+			parts := strings.SplitN(requirement, "=", 2)
+			r = labelRequirement{key: parts[0], operator: labelEquals, value: parts[1]}
+		case strings.HasPrefix(requirement, "!"):
+			r = labelRequirement{key: strings.TrimPrefix(requirement, "!"), operator: labelDoesNotExist}
+		default:
+			r = labelRequirement{key: requirement, operator: labelExists}
+		}
+
+		r.key = strings.TrimSpace(r.key)
+		r.value = strings.TrimSpace(r.value)
+		if r.key == "" || strings.ContainsAny(r.key, "!=") || strings.Contains(r.value, "=") {
+			return LabelSelector{}, ErrInvalidLabelSelector
+		}
+		parsed.requirements = append(parsed.requirements, r)
+	}
+
+	return parsed, nil
+}
+
+// Matches reports whether labels satisfy every requirement of the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		value, ok := labels[r.key]
+		switch r.operator {
+		case labelExists:
+			if !ok {
+				return false
+			}
+		case labelDoesNotExist:
+			if ok {
+				return false
+			}
+		case labelEquals:
+			if !ok || value != r.value {
+				return false
+			}
+		case labelNotEquals:
+			if ok && value == r.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s LabelSelector) String() string {
+	requirements := make([]string, 0, len(s.requirements))
+	for _, r := range s.requirements {
+		switch r.operator {
+		case labelExists:
+			requirements = append(requirements, r.key)
+		case labelDoesNotExist:
+			requirements = append(requirements, "!"+r.key)
+		case labelEquals:
+			requirements = append(requirements, r.key+"="+r.value)
+		case labelNotEquals:
+			requirements = append(requirements, r.key+"!="+r.value)
+		}
+	}
+	sort.Strings(requirements)
+	return strings.Join(requirements, ",")
+}
+
+// ContainerInventory lists the LRP instances and tasks on a cell. Cgroups,
+// LogRateLimits and HealthChecks hold the cgroups, log rate limits and health
+// checks of their containers by container guid, on cells that report them.
+type ContainerInventory struct {
+	LRPs          []LRP                           `json:"lrps"`
+	Tasks         []Task                          `json:"tasks"`
+	Cgroups       map[string]ContainerCgroup      `json:"cgroups,omitempty"`
+	LogRateLimits map[string]LogRateLimitStatus   `json:"log_rate_limits,omitempty"`
+	HealthChecks  map[string]ContainerHealthCheck `json:"health_checks,omitempty"`
+}
+
+// SelectContainers returns the LRP instances and tasks of state whose labels
+// match selector.
+func SelectContainers(state CellState, selector LabelSelector) ContainerInventory {
+	inventory := ContainerInventory{LRPs: []LRP{}, Tasks: []Task{}}
+	for i := range state.LRPs {
+		if selector.Matches(state.LRPs[i].Labels) {
+			inventory.LRPs = append(inventory.LRPs, state.LRPs[i])
+		}
+	}
+	for i := range state.Tasks {
+		if selector.Matches(state.Tasks[i].Labels) {
+			inventory.Tasks = append(inventory.Tasks, state.Tasks[i])
+		}
+	}
+	return inventory
+}
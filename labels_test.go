@@ -0,0 +1,83 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Labels", func() {
+	Describe("label tags", func() {
+		It("round trips labels through container tags", func() {
+			tags := executor.Tags{rep.ProcessGuidTag: "pg-1"}
+			rep.AddLabelTags(tags, map[string]string{"team": "payments", "tier": "web"})
+
+			Expect(tags).To(HaveKeyWithValue("label:team", "payments"))
+			Expect(rep.LabelsFromTags(tags)).To(Equal(map[string]string{"team": "payments", "tier": "web"}))
+		})
+
+		It("returns nil when the tags hold no labels", func() {
+			Expect(rep.LabelsFromTags(executor.Tags{rep.ProcessGuidTag: "pg-1"})).To(BeNil())
+		})
+	})
+
+	Describe("LabelSelector", func() {
+		labels := map[string]string{"team": "payments", "tier": "web"}
+
+		matches := func(selector string) bool {
+			parsed, err := rep.ParseLabelSelector(selector)
+			Expect(err).NotTo(HaveOccurred())
+			return parsed.Matches(labels)
+		}
+
+		It("matches everything when empty", func() {
+			Expect(matches("")).To(BeTrue())
+		})
+
+		It("supports equality and inequality", func() {
+			Expect(matches("team=payments")).To(BeTrue())
+			Expect(matches("team=search")).To(BeFalse())
+			Expect(matches("team!=search")).To(BeTrue())
+			Expect(matches("team!=payments")).To(BeFalse())
+			Expect(matches("owner!=anyone")).To(BeTrue())
+		})
+
+		It("supports existence", func() {
+			Expect(matches("tier")).To(BeTrue())
+			Expect(matches("owner")).To(BeFalse())
+			Expect(matches("!owner")).To(BeTrue())
+			Expect(matches("!tier")).To(BeFalse())
+		})
+
+		It("requires every requirement to hold", func() {
+			Expect(matches("team=payments, tier=web")).To(BeTrue())
+			Expect(matches("team=payments,tier=worker")).To(BeFalse())
+		})
+
+		It("rejects malformed selectors", func() {
+			for _, selector := range []string{"=web", "team==payments", "team=payments,", "!"} {
+				_, err := rep.ParseLabelSelector(selector)
+				Expect(err).To(MatchError(rep.ErrInvalidLabelSelector), selector)
+			}
+		})
+	})
+
+	Describe("SelectContainers", func() {
+		It("returns the LRPs and tasks whose labels match", func() {
+			web := rep.LRP{InstanceGUID: "ig-1", Labels: map[string]string{"tier": "web"}}
+			worker := rep.LRP{InstanceGUID: "ig-2", Labels: map[string]string{"tier": "worker"}}
+			task := rep.Task{TaskGuid: "tg-1"}
+			state := rep.CellState{LRPs: []rep.LRP{web, worker}, Tasks: []rep.Task{task}}
+
+			selector, err := rep.ParseLabelSelector("tier!=worker")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rep.SelectContainers(state, selector)).To(Equal(rep.ContainerInventory{
+				LRPs:  []rep.LRP{web},
+				Tasks: []rep.Task{task},
+			}))
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package rep
+
+import "time"
+
+// LastContacts are the last times, in unix nanoseconds, the rep synced with
+// the BBS and found Garden healthy. A time is zero until the rep first made
+// that contact.
+type LastContacts struct {
+	LastBBSContact    int64 `json:"last_bbs_contact,omitempty"`
+	LastGardenContact int64 `json:"last_garden_contact,omitempty"`
+}
+
+// ContactsOlderThan reports whether the cell last reached the BBS or Garden
+// more than maxAge before now. Such a cell still answers the auction but is
+// partitioned from the control plane, so its state may no longer reflect
+// the work it runs. Contacts the cell does not report, as reps that predate
+// them do not, are never considered stale.
+func (c *CellState) ContactsOlderThan(now time.Time, maxAge time.Duration) bool {
+	oldest := now.Add(-maxAge).UnixNano()
+	return (c.LastBBSContact != 0 && c.LastBBSContact < oldest) ||
+		(c.LastGardenContact != 0 && c.LastGardenContact < oldest)
+}
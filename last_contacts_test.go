@@ -0,0 +1,33 @@
+package rep_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LastContacts", func() {
+	Describe("ContactsOlderThan", func() {
+		now := time.Unix(1700000000, 0)
+
+		It("reports a cell that has not reached the BBS or Garden for too long", func() {
+			state := rep.CellState{LastBBSContact: now.Add(-time.Minute).UnixNano(), LastGardenContact: now.UnixNano()}
+			Expect(state.ContactsOlderThan(now, 30*time.Second)).To(BeTrue())
+
+			state = rep.CellState{LastBBSContact: now.UnixNano(), LastGardenContact: now.Add(-time.Minute).UnixNano()}
+			Expect(state.ContactsOlderThan(now, 30*time.Second)).To(BeTrue())
+		})
+
+		It("does not report a cell with recent contacts", func() {
+			state := rep.CellState{LastBBSContact: now.Add(-10 * time.Second).UnixNano(), LastGardenContact: now.UnixNano()}
+			Expect(state.ContactsOlderThan(now, 30*time.Second)).To(BeFalse())
+		})
+
+		It("does not report a cell that does not report its contacts", func() {
+			Expect((&rep.CellState{}).ContactsOlderThan(now, 30*time.Second)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package lifecyclehints
+
+import (
+	nats "github.com/nats-io/nats.go"
+)
+
+//go:generate counterfeiter -o lifecyclehintsfakes/fake_bus.go . Bus
+
+// Bus is the local message bus hints are published on.
+type Bus interface {
+	Publish(subject string, payload []byte) error
+}
+
+type natsBus struct {
+	conn *nats.Conn
+}
+
+func NewNATSBus(conn *nats.Conn) Bus {
+	return &natsBus{conn: conn}
+}
+
+func (b *natsBus) Publish(subject string, payload []byte) error {
+	return b.conn.Publish(subject, payload)
+}
@@ -0,0 +1,13 @@
+package lifecyclehints_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLifecyclehints(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Lifecyclehints Suite")
+}
@@ -0,0 +1 @@
+package lifecyclehintsfakes // import "code.cloudfoundry.org/rep/lifecyclehints/lifecyclehintsfakes"
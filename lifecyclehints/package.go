@@ -0,0 +1 @@
+package lifecyclehints // import "code.cloudfoundry.org/rep/lifecyclehints"
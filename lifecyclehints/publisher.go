@@ -0,0 +1,104 @@
+package lifecyclehints
+
+import (
+	"encoding/json"
+	"sync"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	ContainerStartedSubject = "rep.container.started"
+	ContainerStoppedSubject = "rep.container.stopped"
+)
+
+// Hint tells subscribers, such as a co-located route emitter, that an LRP
+// instance changed state so that they can resync it without waiting for
+// their next sync with the BBS. It carries just enough to identify the
+// instance; the BBS remains the source of truth.
+type Hint struct {
+	CellID       string                 `json:"cell_id"`
+	ProcessGUID  string                 `json:"process_guid"`
+	InstanceGUID string                 `json:"instance_guid"`
+	Index        int32                  `json:"index"`
+	Address      string                 `json:"address,omitempty"`
+	Ports        []executor.PortMapping `json:"ports,omitempty"`
+}
+
+//go:generate counterfeiter -o lifecyclehintsfakes/fake_publisher.go . Publisher
+
+type Publisher interface {
+	ContainerStarted(logger lager.Logger, container executor.Container)
+	ContainerStopped(logger lager.Logger, container executor.Container)
+}
+
+type publisher struct {
+	bus    Bus
+	cellID string
+
+	lock    sync.Mutex
+	started map[string]struct{}
+}
+
+// NewPublisher returns a Publisher that publishes hints on bus. Containers
+// are reprocessed on every bulk sync, so a started hint is only published
+// the first time a container is seen running.
+func NewPublisher(bus Bus, cellID string) Publisher {
+	return &publisher{
+		bus:     bus,
+		cellID:  cellID,
+		started: map[string]struct{}{},
+	}
+}
+
+func (p *publisher) ContainerStarted(logger lager.Logger, container executor.Container) {
+	p.lock.Lock()
+	_, published := p.started[container.Guid]
+	p.started[container.Guid] = struct{}{}
+	p.lock.Unlock()
+
+	if published {
+		return
+	}
+	p.publish(logger, ContainerStartedSubject, container)
+}
+
+func (p *publisher) ContainerStopped(logger lager.Logger, container executor.Container) {
+	p.lock.Lock()
+	delete(p.started, container.Guid)
+	p.lock.Unlock()
+
+	p.publish(logger, ContainerStoppedSubject, container)
+}
+
+func (p *publisher) publish(logger lager.Logger, subject string, container executor.Container) {
+	logger = logger.Session("publish-lifecycle-hint", lager.Data{"subject": subject})
+
+	lrpKey, err := rep.ActualLRPKeyFromTags(container.Tags)
+	if err != nil {
+		logger.Error("failed-to-generate-lrp-key", err)
+		return
+	}
+
+	hint := Hint{
+		CellID:       p.cellID,
+		ProcessGUID:  lrpKey.ProcessGuid,
+		InstanceGUID: container.Tags[rep.InstanceGuidTag],
+		Index:        lrpKey.Index,
+		Address:      container.ExternalIP,
+		Ports:        container.Ports,
+	}
+
+	payload, err := json.Marshal(hint)
+	if err != nil {
+		logger.Error("failed-to-marshal-hint", err)
+		return
+	}
+
+	err = p.bus.Publish(subject, payload)
+	if err != nil {
+		logger.Error("failed-to-publish-hint", err)
+	}
+}
@@ -0,0 +1,102 @@
+package lifecyclehints_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/lifecyclehints"
+	"code.cloudfoundry.org/rep/lifecyclehints/lifecyclehintsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Publisher", func() {
+	var (
+		logger    *lagertest.TestLogger
+		fakeBus   *lifecyclehintsfakes.FakeBus
+		container executor.Container
+		publisher lifecyclehints.Publisher
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeBus = new(lifecyclehintsfakes.FakeBus)
+		container = executor.Container{
+			Guid:       "container-guid",
+			ExternalIP: "10.0.16.4",
+			Tags: executor.Tags{
+				rep.LifecycleTag:    rep.LRPLifecycle,
+				rep.DomainTag:       "domain",
+				rep.ProcessGuidTag:  "process-guid",
+				rep.ProcessIndexTag: "2",
+				rep.InstanceGuidTag: "instance-guid",
+			},
+		}
+		container.Ports = []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001}}
+		publisher = lifecyclehints.NewPublisher(fakeBus, "cell-id")
+	})
+
+	Describe("ContainerStarted", func() {
+		It("publishes a started hint", func() {
+			publisher.ContainerStarted(logger, container)
+
+			Expect(fakeBus.PublishCallCount()).To(Equal(1))
+			subject, payload := fakeBus.PublishArgsForCall(0)
+			Expect(subject).To(Equal(lifecyclehints.ContainerStartedSubject))
+
+			var hint lifecyclehints.Hint
+			Expect(json.Unmarshal(payload, &hint)).To(Succeed())
+			Expect(hint).To(Equal(lifecyclehints.Hint{
+				CellID:       "cell-id",
+				ProcessGUID:  "process-guid",
+				InstanceGUID: "instance-guid",
+				Index:        2,
+				Address:      "10.0.16.4",
+				Ports:        []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001}},
+			}))
+		})
+
+		It("publishes the hint once per container", func() {
+			publisher.ContainerStarted(logger, container)
+			publisher.ContainerStarted(logger, container)
+
+			Expect(fakeBus.PublishCallCount()).To(Equal(1))
+		})
+
+		It("publishes the hint again once the container stopped", func() {
+			publisher.ContainerStarted(logger, container)
+			publisher.ContainerStopped(logger, container)
+			publisher.ContainerStarted(logger, container)
+
+			Expect(fakeBus.PublishCallCount()).To(Equal(3))
+		})
+
+		It("does not publish hints for containers without an lrp key", func() {
+			container.Tags = executor.Tags{}
+			publisher.ContainerStarted(logger, container)
+
+			Expect(fakeBus.PublishCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("ContainerStopped", func() {
+		It("publishes a stopped hint", func() {
+			publisher.ContainerStopped(logger, container)
+
+			Expect(fakeBus.PublishCallCount()).To(Equal(1))
+			subject, _ := fakeBus.PublishArgsForCall(0)
+			Expect(subject).To(Equal(lifecyclehints.ContainerStoppedSubject))
+		})
+
+		It("logs when publishing fails", func() {
+			fakeBus.PublishReturns(errors.New("nats: connection closed"))
+			publisher.ContainerStopped(logger, container)
+
+			Expect(logger).To(gbytes.Say("failed-to-publish-hint"))
+		})
+	})
+})
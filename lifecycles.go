@@ -0,0 +1,59 @@
+package rep
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lifecycle is a version of a lifecycle bundle, such as the buildpack app
+// lifecycle, that a cell has validated and can run work with.
+type Lifecycle struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+func (l Lifecycle) String() string {
+	return l.Name + "/" + l.Version
+}
+
+// LifecycleUnavailableError is returned for work requiring a lifecycle the
+// cell does not have.
+type LifecycleUnavailableError struct {
+	Lifecycle string `json:"lifecycle"`
+}
+
+func (e LifecycleUnavailableError) Error() string {
+	return fmt.Sprintf("the lifecycle %s is not available on the cell", e.Lifecycle)
+}
+
+// LifecycleFailure records the LRP instance or task of a Work that was
+// rejected because it requires a lifecycle the cell does not have.
+type LifecycleFailure struct {
+	InstanceGUID string                    `json:"instance_guid,omitempty"`
+	TaskGuid     string                    `json:"task_guid,omitempty"`
+	Error        LifecycleUnavailableError `json:"error"`
+}
+
+// MissingLifecycle returns an error for the first of required that is not
+// one of available. A requirement is either the name of a lifecycle, which
+// any version satisfies, or a name/version pair.
+func MissingLifecycle(available []Lifecycle, required []string) *LifecycleUnavailableError {
+	for _, requirement := range required {
+		name, version := requirement, ""
+		if i := strings.Index(requirement, "/"); i >= 0 {
+			name, version = requirement[:i], requirement[i+1:]
+		}
+
+		found := false
+		for _, lifecycle := range available {
+			if lifecycle.Name == name && (version == "" || lifecycle.Version == version) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &LifecycleUnavailableError{Lifecycle: requirement}
+		}
+	}
+	return nil
+}
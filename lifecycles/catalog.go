@@ -0,0 +1,135 @@
+package lifecycles
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+var ErrIncompleteBundle = errors.New("a lifecycle bundle needs a name, a version and a location")
+
+// Bundle is a lifecycle bundle the cell is configured with. Its Location is
+// either a directory on the cell or the http(s) URL it is downloaded from.
+// Binaries are the paths, relative to the directory, of the executables the
+// bundle must contain, such as its healthcheck and launcher. They are not
+// checked for bundles that are downloaded.
+type Bundle struct {
+	Name     string
+	Version  string
+	Location string
+	Binaries []string
+}
+
+//go:generate counterfeiter -o lifecyclesfakes/fake_catalog.go . Catalog
+
+// Catalog holds the lifecycles the cell has validated.
+type Catalog interface {
+	// Load validates bundles and replaces the available lifecycles with the
+	// valid ones.
+	Load(logger lager.Logger, bundles []Bundle)
+	Available() []rep.Lifecycle
+}
+
+type catalog struct {
+	client *http.Client
+
+	mutex     sync.RWMutex
+	available []rep.Lifecycle
+}
+
+// NewCatalog returns a Catalog that gives up reaching the location of a
+// bundle that is downloaded after timeout.
+func NewCatalog(timeout time.Duration) Catalog {
+	return &catalog{client: &http.Client{Timeout: timeout}}
+}
+
+// Load leaves every invalid bundle out of the available lifecycles, so that
+// work requiring it is refused rather than failing once its container starts.
+func (c *catalog) Load(logger lager.Logger, bundles []Bundle) {
+	logger = logger.Session("load-lifecycle-bundles")
+
+	available := []rep.Lifecycle{}
+	for _, bundle := range bundles {
+		lifecycle := rep.Lifecycle{Name: bundle.Name, Version: bundle.Version}
+		err := c.validate(bundle)
+		if err != nil {
+			logger.Error("invalid-lifecycle-bundle", err, lager.Data{"lifecycle": lifecycle.String(), "location": bundle.Location})
+			continue
+		}
+		available = append(available, lifecycle)
+	}
+
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].String() < available[j].String()
+	})
+	logger.Info("loaded", lager.Data{"available": len(available), "configured": len(bundles)})
+
+	c.mutex.Lock()
+	c.available = available
+	c.mutex.Unlock()
+}
+
+func (c *catalog) Available() []rep.Lifecycle {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.available
+}
+
+func (c *catalog) validate(bundle Bundle) error {
+	if bundle.Name == "" || bundle.Version == "" || bundle.Location == "" {
+		return ErrIncompleteBundle
+	}
+
+	location, err := url.Parse(bundle.Location)
+	if err == nil && (location.Scheme == "http" || location.Scheme == "https") {
+		return c.validateURL(bundle.Location)
+	}
+	return validateDirectory(bundle.Location, bundle.Binaries)
+}
+
+func (c *catalog) validateURL(location string) error {
+	resp, err := c.client.Head(location)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code fetching %s: %d", location, resp.StatusCode)
+	}
+	return nil
+}
+
+func validateDirectory(dir string, binaries []string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	for _, binary := range binaries {
+		info, err := os.Stat(filepath.Join(dir, binary))
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+			return fmt.Errorf("%s is not executable", filepath.Join(dir, binary))
+		}
+	}
+	return nil
+}
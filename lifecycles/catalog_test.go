@@ -0,0 +1,89 @@
+package lifecycles_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/lifecycles"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Catalog", func() {
+	var (
+		logger     *lagertest.TestLogger
+		bundleDir  string
+		blobServer *ghttp.Server
+		catalog    lifecycles.Catalog
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+
+		var err error
+		bundleDir, err = ioutil.TempDir("", "lifecycle-bundle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(bundleDir, "healthcheck"), []byte("#!/bin/sh"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bundleDir, "launcher"), []byte("#!/bin/sh"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bundleDir, "README"), []byte("docs"), 0644)).To(Succeed())
+
+		blobServer = ghttp.NewServer()
+		catalog = lifecycles.NewCatalog(time.Second)
+	})
+
+	AfterEach(func() {
+		blobServer.Close()
+		os.RemoveAll(bundleDir)
+	})
+
+	It("makes the bundles whose binaries are executable available, in order", func() {
+		catalog.Load(logger, []lifecycles.Bundle{
+			{Name: "docker", Version: "2.0.0", Location: bundleDir, Binaries: []string{"launcher"}},
+			{Name: "buildpack", Version: "1.2.3", Location: bundleDir, Binaries: []string{"healthcheck", "launcher"}},
+		})
+
+		Expect(catalog.Available()).To(Equal([]rep.Lifecycle{
+			{Name: "buildpack", Version: "1.2.3"},
+			{Name: "docker", Version: "2.0.0"},
+		}))
+	})
+
+	It("leaves out bundles that are incomplete, missing or not executable", func() {
+		catalog.Load(logger, []lifecycles.Bundle{
+			{Name: "no-version", Location: bundleDir},
+			{Name: "missing", Version: "1.0.0", Location: filepath.Join(bundleDir, "missing")},
+			{Name: "missing-binary", Version: "1.0.0", Location: bundleDir, Binaries: []string{"diego-sshd"}},
+			{Name: "not-executable", Version: "1.0.0", Location: bundleDir, Binaries: []string{"README"}},
+		})
+
+		Expect(catalog.Available()).To(BeEmpty())
+		Expect(logger).To(gbytes.Say("invalid-lifecycle-bundle"))
+	})
+
+	It("checks that bundles that are downloaded can be reached", func() {
+		blobServer.RouteToHandler("HEAD", "/buildpack.tgz", ghttp.RespondWith(http.StatusOK, nil))
+		blobServer.RouteToHandler("HEAD", "/docker.tgz", ghttp.RespondWith(http.StatusNotFound, nil))
+
+		catalog.Load(logger, []lifecycles.Bundle{
+			{Name: "buildpack", Version: "1.2.3", Location: blobServer.URL() + "/buildpack.tgz"},
+			{Name: "docker", Version: "2.0.0", Location: blobServer.URL() + "/docker.tgz"},
+		})
+
+		Expect(catalog.Available()).To(Equal([]rep.Lifecycle{{Name: "buildpack", Version: "1.2.3"}}))
+	})
+
+	It("replaces the available lifecycles on every load", func() {
+		catalog.Load(logger, []lifecycles.Bundle{{Name: "buildpack", Version: "1.2.3", Location: bundleDir}})
+		catalog.Load(logger, []lifecycles.Bundle{{Name: "buildpack", Version: "1.3.0", Location: bundleDir}})
+
+		Expect(catalog.Available()).To(Equal([]rep.Lifecycle{{Name: "buildpack", Version: "1.3.0"}}))
+	})
+})
@@ -0,0 +1,13 @@
+package lifecycles_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLifecycles(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Lifecycles Suite")
+}
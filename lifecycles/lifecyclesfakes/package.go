@@ -0,0 +1 @@
+package lifecyclesfakes // import "code.cloudfoundry.org/rep/lifecycles/lifecyclesfakes"
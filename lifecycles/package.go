@@ -0,0 +1 @@
+package lifecycles // import "code.cloudfoundry.org/rep/lifecycles"
@@ -0,0 +1,49 @@
+package lifecycles
+
+import (
+	"os"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+// LoadFunc returns the currently configured lifecycle bundles.
+type LoadFunc func() ([]Bundle, error)
+
+type reloader struct {
+	logger  lager.Logger
+	catalog Catalog
+	reload  <-chan os.Signal
+	load    LoadFunc
+}
+
+// NewReloader returns a runner that validates the bundles returned by load
+// into catalog every time a signal is received on reload. A failed load
+// leaves the catalog untouched.
+func NewReloader(logger lager.Logger, catalog Catalog, reload <-chan os.Signal, load LoadFunc) ifrit.Runner {
+	return &reloader{
+		logger:  logger.Session("lifecycle-bundles-reloader"),
+		catalog: catalog,
+		reload:  reload,
+		load:    load,
+	}
+}
+
+func (r *reloader) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	for {
+		select {
+		case <-signals:
+			return nil
+		case <-r.reload:
+			bundles, err := r.load()
+			if err != nil {
+				r.logger.Error("failed-to-reload", err)
+				continue
+			}
+
+			r.catalog.Load(r.logger, bundles)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package lifecycles_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/lifecycles"
+	"code.cloudfoundry.org/rep/lifecycles/lifecyclesfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Reloader", func() {
+	var (
+		logger     *lagertest.TestLogger
+		catalog    *lifecyclesfakes.FakeCatalog
+		reload     chan os.Signal
+		configured []lifecycles.Bundle
+		loadErr    error
+		process    ifrit.Process
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		catalog = new(lifecyclesfakes.FakeCatalog)
+		reload = make(chan os.Signal)
+		configured = []lifecycles.Bundle{{Name: "buildpack", Version: "1.2.3", Location: "/var/vcap/packages/buildpack_app_lifecycle"}}
+		loadErr = nil
+	})
+
+	JustBeforeEach(func() {
+		load := func() ([]lifecycles.Bundle, error) {
+			return configured, loadErr
+		}
+		process = ifrit.Invoke(lifecycles.NewReloader(logger, catalog, reload, load))
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+	})
+
+	It("loads the bundles into the catalog when signalled", func() {
+		Expect(catalog.LoadCallCount()).To(BeZero())
+		reload <- syscall.SIGHUP
+		Eventually(catalog.LoadCallCount).Should(Equal(1))
+
+		_, bundles := catalog.LoadArgsForCall(0)
+		Expect(bundles).To(Equal(configured))
+	})
+
+	Context("when loading the bundles fails", func() {
+		BeforeEach(func() {
+			loadErr = errors.New("boom")
+		})
+
+		It("keeps the current catalog", func() {
+			reload <- syscall.SIGHUP
+			Eventually(logger.Buffer()).Should(gbytes.Say("failed-to-reload"))
+			Expect(catalog.LoadCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MissingLifecycle", func() {
+	available := []rep.Lifecycle{{Name: "buildpack", Version: "1.2.3"}, {Name: "docker", Version: "2.0.0"}}
+
+	It("accepts requirements on any version or on a version the cell has", func() {
+		Expect(rep.MissingLifecycle(available, []string{"buildpack", "docker/2.0.0"})).To(BeNil())
+		Expect(rep.MissingLifecycle(available, nil)).To(BeNil())
+	})
+
+	It("returns the first requirement the cell does not have", func() {
+		Expect(rep.MissingLifecycle(available, []string{"buildpack", "docker/1.0.0", "windows"})).To(Equal(&rep.LifecycleUnavailableError{Lifecycle: "docker/1.0.0"}))
+		Expect(rep.MissingLifecycle(nil, []string{"buildpack"})).To(Equal(&rep.LifecycleUnavailableError{Lifecycle: "buildpack"}))
+	})
+})
@@ -0,0 +1,191 @@
+package loadbalancer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	awsService           = "elasticloadbalancing"
+	awsAPIVersion        = "2015-12-01"
+	awsSigningAlgorithm  = "AWS4-HMAC-SHA256"
+	awsRequestTimeFormat = "20060102T150405Z"
+	awsRequestDateFormat = "20060102"
+	awsFormContentType   = "application/x-www-form-urlencoded; charset=utf-8"
+)
+
+// AWSCredentials are used to sign requests to the Elastic Load Balancing
+// API.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+type awsTargetGroupDeregisterer struct {
+	client          *http.Client
+	endpoint        string
+	region          string
+	targetGroupARNs []string
+	credentials     AWSCredentials
+	clock           clock.Clock
+}
+
+// NewAWSTargetGroupDeregisterer returns a Deregisterer that removes the
+// targets from the given IP target groups of an AWS application or network
+// load balancer. The regional API endpoint is used when endpoint is empty.
+func NewAWSTargetGroupDeregisterer(
+	client *http.Client,
+	endpoint string,
+	region string,
+	targetGroupARNs []string,
+	credentials AWSCredentials,
+	clock clock.Clock,
+) Deregisterer {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsService, region)
+	}
+
+	return &awsTargetGroupDeregisterer{
+		client:          client,
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		region:          region,
+		targetGroupARNs: targetGroupARNs,
+		credentials:     credentials,
+		clock:           clock,
+	}
+}
+
+func (a *awsTargetGroupDeregisterer) Name() string {
+	return "aws-target-group"
+}
+
+func (a *awsTargetGroupDeregisterer) Deregister(logger lager.Logger, targets []Target) error {
+	for _, targetGroupARN := range a.targetGroupARNs {
+		logger := logger.WithData(lager.Data{"target-group-arn": targetGroupARN})
+
+		err := a.deregisterTargets(targetGroupARN, targets)
+		if err != nil {
+			logger.Error("failed-to-deregister-targets", err)
+			return err
+		}
+		logger.Info("deregistered-targets", lager.Data{"targets": len(targets)})
+	}
+
+	return nil
+}
+
+func (a *awsTargetGroupDeregisterer) deregisterTargets(targetGroupARN string, targets []Target) error {
+	form := url.Values{}
+	form.Set("Action", "DeregisterTargets")
+	form.Set("Version", awsAPIVersion)
+	form.Set("TargetGroupArn", targetGroupARN)
+	for i, target := range targets {
+		member := fmt.Sprintf("Targets.member.%d.", i+1)
+		form.Set(member+"Id", target.Address)
+		form.Set(member+"Port", strconv.Itoa(int(target.Port)))
+	}
+	body := form.Encode()
+
+	req, err := http.NewRequest("POST", a.endpoint+"/", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", awsFormContentType)
+	a.sign(req, body)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		payload, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(payload)))
+	}
+
+	return nil
+}
+
+// sign adds a Signature Version 4 Authorization header to req.
+func (a *awsTargetGroupDeregisterer) sign(req *http.Request, body string) {
+	now := a.clock.Now().UTC()
+	requestTime := now.Format(awsRequestTimeFormat)
+	requestDate := now.Format(awsRequestDateFormat)
+
+	req.Header.Set("X-Amz-Date", requestTime)
+	if a.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.credentials.SessionToken)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date"}
+	values := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   requestTime,
+	}
+	if a.credentials.SessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+		values["x-amz-security-token"] = a.credentials.SessionToken
+	}
+
+	canonicalHeaders := ""
+	for _, header := range headers {
+		canonicalHeaders += header + ":" + values[header] + "\n"
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := strings.Join([]string{requestDate, a.region, awsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		awsSigningAlgorithm,
+		requestTime,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+a.credentials.SecretAccessKey), requestDate)
+	signingKey = hmacSHA256(signingKey, a.region)
+	signingKey = hmacSHA256(signingKey, awsService)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlgorithm,
+		a.credentials.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
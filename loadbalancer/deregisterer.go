@@ -0,0 +1,63 @@
+package loadbalancer
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Target is a routable port of an LRP instance running on the cell.
+type Target struct {
+	ProcessGUID  string `json:"process_guid"`
+	InstanceGUID string `json:"instance_guid"`
+	Index        int32  `json:"index"`
+	Address      string `json:"address"`
+	Port         uint16 `json:"port"`
+}
+
+//go:generate counterfeiter -o loadbalancerfakes/fake_deregisterer.go . Deregisterer
+
+// Deregisterer removes targets from an external load balancer.
+type Deregisterer interface {
+	Name() string
+	Deregister(logger lager.Logger, targets []Target) error
+}
+
+// TargetsFromContainers returns the host ports of the LRP containers that
+// external load balancers may be sending traffic to.
+func TargetsFromContainers(containers []executor.Container, cellID string) []Target {
+	targets := []Target{}
+
+	for _, container := range containers {
+		if container.Tags[rep.LifecycleTag] != rep.LRPLifecycle || container.ExternalIP == "" {
+			continue
+		}
+
+		key, err := rep.ActualLRPKeyFromTags(container.Tags)
+		if err != nil {
+			continue
+		}
+		instanceKey, err := rep.ActualLRPInstanceKeyFromContainer(container, cellID)
+		if err != nil {
+			continue
+		}
+
+		for _, port := range container.Ports {
+			for _, hostPort := range []uint16{port.HostPort, port.HostTLSProxyPort} {
+				if hostPort == 0 {
+					continue
+				}
+
+				targets = append(targets, Target{
+					ProcessGUID:  key.ProcessGuid,
+					InstanceGUID: instanceKey.InstanceGuid,
+					Index:        key.Index,
+					Address:      container.ExternalIP,
+					Port:         hostPort,
+				})
+			}
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,198 @@
+package loadbalancer_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/loadbalancer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+func lrpContainer(guid, externalIP string, ports ...executor.PortMapping) executor.Container {
+	container := executor.Container{
+		Guid:       guid,
+		ExternalIP: externalIP,
+		Tags: executor.Tags{
+			rep.LifecycleTag:    rep.LRPLifecycle,
+			rep.DomainTag:       "domain",
+			rep.ProcessGuidTag:  "process-guid",
+			rep.ProcessIndexTag: "2",
+			rep.InstanceGuidTag: guid + "-instance",
+		},
+	}
+	container.Ports = ports
+	return container
+}
+
+var _ = Describe("Deregisterer", func() {
+	var (
+		logger  *lagertest.TestLogger
+		targets []loadbalancer.Target
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		targets = []loadbalancer.Target{
+			{ProcessGUID: "process-guid", InstanceGUID: "instance-guid", Index: 2, Address: "10.0.16.4", Port: 61001},
+			{ProcessGUID: "process-guid", InstanceGUID: "instance-guid", Index: 2, Address: "10.0.16.4", Port: 61002},
+		}
+	})
+
+	Describe("TargetsFromContainers", func() {
+		It("returns the host ports of the LRP containers", func() {
+			containers := []executor.Container{
+				lrpContainer("container-1", "10.0.16.4", executor.PortMapping{ContainerPort: 8080, HostPort: 61001, ContainerTLSProxyPort: 61443, HostTLSProxyPort: 61002}),
+				lrpContainer("container-2", "10.0.16.4"),
+				{Guid: "task", ExternalIP: "10.0.16.4", Tags: executor.Tags{rep.LifecycleTag: rep.TaskLifecycle}},
+			}
+
+			Expect(loadbalancer.TargetsFromContainers(containers, "cell-id")).To(ConsistOf(
+				loadbalancer.Target{ProcessGUID: "process-guid", InstanceGUID: "container-1-instance", Index: 2, Address: "10.0.16.4", Port: 61001},
+				loadbalancer.Target{ProcessGUID: "process-guid", InstanceGUID: "container-1-instance", Index: 2, Address: "10.0.16.4", Port: 61002},
+			))
+		})
+
+		It("skips containers without a host address", func() {
+			containers := []executor.Container{
+				lrpContainer("container-1", "", executor.PortMapping{ContainerPort: 8080, HostPort: 61001}),
+			}
+
+			Expect(loadbalancer.TargetsFromContainers(containers, "cell-id")).To(BeEmpty())
+		})
+	})
+
+	Describe("Webhook", func() {
+		var (
+			fakeServer   *ghttp.Server
+			deregisterer loadbalancer.Deregisterer
+		)
+
+		BeforeEach(func() {
+			fakeServer = ghttp.NewServer()
+			deregisterer = loadbalancer.NewWebhookDeregisterer(http.DefaultClient, fakeServer.URL()+"/deregister", "cell-id")
+		})
+
+		AfterEach(func() {
+			fakeServer.Close()
+		})
+
+		It("posts the targets of the cell", func() {
+			payload, err := json.Marshal(map[string]interface{}{"cell_id": "cell-id", "targets": targets})
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/deregister"),
+				ghttp.VerifyContentType("application/json"),
+				ghttp.VerifyJSON(string(payload)),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			))
+
+			Expect(deregisterer.Deregister(logger, targets)).To(Succeed())
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("errors when the webhook does not succeed", func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+
+			err := deregisterer.Deregister(logger, targets)
+			Expect(err).To(MatchError("unexpected status code: 503"))
+		})
+	})
+
+	Describe("AWS target group", func() {
+		var (
+			fakeServer   *ghttp.Server
+			fakeClock    *fakeclock.FakeClock
+			credentials  loadbalancer.AWSCredentials
+			deregisterer loadbalancer.Deregisterer
+		)
+
+		BeforeEach(func() {
+			fakeServer = ghttp.NewServer()
+			fakeClock = fakeclock.NewFakeClock(time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC))
+			credentials = loadbalancer.AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+		})
+
+		JustBeforeEach(func() {
+			deregisterer = loadbalancer.NewAWSTargetGroupDeregisterer(
+				http.DefaultClient,
+				fakeServer.URL(),
+				"us-east-1",
+				[]string{"arn:tg-1", "arn:tg-2"},
+				credentials,
+				fakeClock,
+			)
+		})
+
+		AfterEach(func() {
+			fakeServer.Close()
+		})
+
+		verifyDeregisterTargets := func(targetGroupARN string) http.HandlerFunc {
+			return ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/"),
+				ghttp.VerifyHeaderKV("X-Amz-Date", "20260304T050607Z"),
+				func(w http.ResponseWriter, req *http.Request) {
+					Expect(req.Header.Get("Authorization")).To(MatchRegexp(
+						`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260304/us-east-1/elasticloadbalancing/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=[0-9a-f]{64}$`,
+					))
+
+					Expect(req.ParseForm()).To(Succeed())
+					Expect(req.PostForm).To(Equal(url.Values{
+						"Action":                {"DeregisterTargets"},
+						"Version":               {"2015-12-01"},
+						"TargetGroupArn":        {targetGroupARN},
+						"Targets.member.1.Id":   {"10.0.16.4"},
+						"Targets.member.1.Port": {"61001"},
+						"Targets.member.2.Id":   {"10.0.16.4"},
+						"Targets.member.2.Port": {"61002"},
+					}))
+				},
+				ghttp.RespondWith(http.StatusOK, "<DeregisterTargetsResponse/>"),
+			)
+		}
+
+		It("deregisters the targets from every target group", func() {
+			fakeServer.AppendHandlers(
+				verifyDeregisterTargets("arn:tg-1"),
+				verifyDeregisterTargets("arn:tg-2"),
+			)
+
+			Expect(deregisterer.Deregister(logger, targets)).To(Succeed())
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		Context("with a session token", func() {
+			BeforeEach(func() {
+				credentials.SessionToken = "session-token"
+			})
+
+			It("signs the session token", func() {
+				fakeServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("X-Amz-Security-Token", "session-token"),
+					func(w http.ResponseWriter, req *http.Request) {
+						Expect(req.Header.Get("Authorization")).To(ContainSubstring("SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,"))
+					},
+				), ghttp.RespondWith(http.StatusOK, nil))
+
+				Expect(deregisterer.Deregister(logger, targets)).To(Succeed())
+			})
+		})
+
+		It("errors when the API rejects the request", func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, "<Error>AccessDenied</Error>"))
+
+			err := deregisterer.Deregister(logger, targets)
+			Expect(err).To(MatchError("unexpected status code: 403: <Error>AccessDenied</Error>"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package loadbalancer
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+)
+
+type drainingEvacuatable struct {
+	logger         lager.Logger
+	evacuatable    evacuation_context.Evacuatable
+	executorClient executor.Client
+	deregisterers  []Deregisterer
+	cellID         string
+	timeout        time.Duration
+	clock          clock.Clock
+	once           sync.Once
+}
+
+// NewDrainingEvacuatable deregisters the cell's LRP instances from the
+// external load balancers before starting the evacuation, so that the load
+// balancers stop sending traffic to them before they are stopped. The
+// evacuation starts once every deregisterer has returned, or after timeout.
+func NewDrainingEvacuatable(
+	logger lager.Logger,
+	evacuatable evacuation_context.Evacuatable,
+	executorClient executor.Client,
+	deregisterers []Deregisterer,
+	cellID string,
+	timeout time.Duration,
+	clock clock.Clock,
+) evacuation_context.Evacuatable {
+	return &drainingEvacuatable{
+		logger:         logger,
+		evacuatable:    evacuatable,
+		executorClient: executorClient,
+		deregisterers:  deregisterers,
+		cellID:         cellID,
+		timeout:        timeout,
+		clock:          clock,
+	}
+}
+
+func (d *drainingEvacuatable) Evacuate() {
+	d.once.Do(d.deregister)
+	d.evacuatable.Evacuate()
+}
+
+func (d *drainingEvacuatable) deregister() {
+	logger := d.logger.Session("load-balancer-deregistration")
+	logger.Info("starting")
+	defer logger.Info("finished")
+
+	containers, err := d.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return
+	}
+
+	targets := TargetsFromContainers(containers, d.cellID)
+	if len(targets) == 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		wg := sync.WaitGroup{}
+		for _, deregisterer := range d.deregisterers {
+			deregisterer := deregisterer
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				logger := logger.WithData(lager.Data{"deregisterer": deregisterer.Name()})
+				err := deregisterer.Deregister(logger, targets)
+				if err != nil {
+					logger.Error("failed-to-deregister", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+
+	timer := d.clock.NewTimer(d.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		logger.Info("deregistered", lager.Data{"targets": len(targets)})
+	case <-timer.C():
+		logger.Error("timed-out", nil, lager.Data{"timeout": d.timeout})
+	}
+}
@@ -0,0 +1,129 @@
+package loadbalancer_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	executorfakes "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/evacuation/evacuation_context"
+	"code.cloudfoundry.org/rep/evacuation/evacuation_context/fake_evacuation_context"
+	"code.cloudfoundry.org/rep/loadbalancer"
+	"code.cloudfoundry.org/rep/loadbalancer/loadbalancerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DrainingEvacuatable", func() {
+	var (
+		fakeEvacuatable    *fake_evacuation_context.FakeEvacuatable
+		fakeExecutorClient *executorfakes.FakeClient
+		fakeDeregisterer   *loadbalancerfakes.FakeDeregisterer
+		fakeClock          *fakeclock.FakeClock
+		evacuatable        evacuation_context.Evacuatable
+	)
+
+	BeforeEach(func() {
+		fakeEvacuatable = new(fake_evacuation_context.FakeEvacuatable)
+		fakeExecutorClient = new(executorfakes.FakeClient)
+		fakeDeregisterer = new(loadbalancerfakes.FakeDeregisterer)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		fakeExecutorClient.ListContainersReturns([]executor.Container{
+			lrpContainer("container-1", "10.0.16.4", executor.PortMapping{ContainerPort: 8080, HostPort: 61001}),
+		}, nil)
+
+		evacuatable = loadbalancer.NewDrainingEvacuatable(
+			lagertest.NewTestLogger("test"),
+			fakeEvacuatable,
+			fakeExecutorClient,
+			[]loadbalancer.Deregisterer{fakeDeregisterer},
+			"cell-id",
+			10*time.Second,
+			fakeClock,
+		)
+	})
+
+	It("deregisters the targets before evacuating", func() {
+		fakeDeregisterer.DeregisterStub = func(lager.Logger, []loadbalancer.Target) error {
+			Expect(fakeEvacuatable.EvacuateCallCount()).To(Equal(0))
+			return nil
+		}
+
+		evacuatable.Evacuate()
+
+		Expect(fakeDeregisterer.DeregisterCallCount()).To(Equal(1))
+		_, targets := fakeDeregisterer.DeregisterArgsForCall(0)
+		Expect(targets).To(ConsistOf(loadbalancer.Target{
+			ProcessGUID:  "process-guid",
+			InstanceGUID: "container-1-instance",
+			Index:        2,
+			Address:      "10.0.16.4",
+			Port:         61001,
+		}))
+		Expect(fakeEvacuatable.EvacuateCallCount()).To(Equal(1))
+	})
+
+	It("only deregisters once", func() {
+		evacuatable.Evacuate()
+		evacuatable.Evacuate()
+
+		Expect(fakeDeregisterer.DeregisterCallCount()).To(Equal(1))
+		Expect(fakeEvacuatable.EvacuateCallCount()).To(Equal(2))
+	})
+
+	Context("when deregistration fails", func() {
+		BeforeEach(func() {
+			fakeDeregisterer.DeregisterReturns(errors.New("boom"))
+		})
+
+		It("evacuates anyway", func() {
+			evacuatable.Evacuate()
+			Expect(fakeEvacuatable.EvacuateCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when deregistration does not finish before the timeout", func() {
+		var release chan struct{}
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			fakeDeregisterer.DeregisterStub = func(lager.Logger, []loadbalancer.Target) error {
+				<-release
+				return nil
+			}
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("evacuates after the timeout", func() {
+			done := make(chan struct{})
+			go func() {
+				evacuatable.Evacuate()
+				close(done)
+			}()
+
+			Consistently(done).ShouldNot(BeClosed())
+			fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+			Eventually(done).Should(BeClosed())
+			Expect(fakeEvacuatable.EvacuateCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when there are no routable containers", func() {
+		BeforeEach(func() {
+			fakeExecutorClient.ListContainersReturns(nil, nil)
+		})
+
+		It("evacuates without calling the deregisterers", func() {
+			evacuatable.Evacuate()
+			Expect(fakeDeregisterer.DeregisterCallCount()).To(Equal(0))
+			Expect(fakeEvacuatable.EvacuateCallCount()).To(Equal(1))
+		})
+	})
+})
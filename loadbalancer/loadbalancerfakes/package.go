@@ -0,0 +1 @@
+package loadbalancerfakes // import "code.cloudfoundry.org/rep/loadbalancer/loadbalancerfakes"
@@ -0,0 +1 @@
+package loadbalancer // import "code.cloudfoundry.org/rep/loadbalancer"
@@ -0,0 +1,58 @@
+package loadbalancer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
+
+type webhookRequest struct {
+	CellID  string   `json:"cell_id"`
+	Targets []Target `json:"targets"`
+}
+
+type webhookDeregisterer struct {
+	client *http.Client
+	url    string
+	cellID string
+}
+
+// NewWebhookDeregisterer returns a Deregisterer that POSTs the targets of the
+// cell as JSON to url, and expects a 2xx response once the load balancer no
+// longer sends traffic to them.
+func NewWebhookDeregisterer(client *http.Client, url, cellID string) Deregisterer {
+	return &webhookDeregisterer{
+		client: client,
+		url:    url,
+		cellID: cellID,
+	}
+}
+
+func (w *webhookDeregisterer) Name() string {
+	return "webhook"
+}
+
+func (w *webhookDeregisterer) Deregister(logger lager.Logger, targets []Target) error {
+	payload, err := json.Marshal(webhookRequest{CellID: w.cellID, Targets: targets})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		logger.Error("failed-to-call-webhook", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		logger.Error("failed-to-call-webhook", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package rep
+
+// LogRateLimitStatus describes the rate limit applied to the log output of a
+// container, in bytes per second or -1 when it is unlimited, and the number
+// of bytes of output the cell dropped for exceeding the limit of the cell.
+type LogRateLimitStatus struct {
+	BytesPerSecond int64 `json:"bytes_per_second"`
+	DroppedBytes   int64 `json:"dropped_bytes"`
+}
+
+// AppliedLogRateLimit returns the rate limit applied to the log output of a
+// container requesting containerLimit bytes per second on a cell limiting
+// every container to cellLimit, where a negative container limit is
+// unlimited and a cell limit that is not positive imposes none.
+func AppliedLogRateLimit(containerLimit, cellLimit int64) int64 {
+	if cellLimit <= 0 {
+		return containerLimit
+	}
+	if containerLimit < 0 || containerLimit > cellLimit {
+		return cellLimit
+	}
+	return containerLimit
+}
@@ -0,0 +1,124 @@
+package logratelimit
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+)
+
+// idleBucketTTL is how long the limiter keeps track of a container that has
+// not logged, after which it is assumed to be gone.
+const idleBucketTTL = time.Hour
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	dropped int64
+}
+
+// Limiter is the IngressClient the executor sends the log output of
+// containers through. It drops the log lines of a container, identified by
+// the source_id and instance_id tags of its logs, once they exceed
+// bytesPerSecond over a burst of a second of output, and counts the bytes it
+// drops. Every other envelope is sent as it is.
+type Limiter struct {
+	loggingclient.IngressClient
+	bytesPerSecond int64
+	clock          clock.Clock
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+func NewLimiter(metronClient loggingclient.IngressClient, bytesPerSecond int64, clock clock.Clock) *Limiter {
+	return &Limiter{
+		IngressClient:  metronClient,
+		bytesPerSecond: bytesPerSecond,
+		clock:          clock,
+		buckets:        map[string]*bucket{},
+		lastPrune:      clock.Now(),
+	}
+}
+
+func (l *Limiter) SendAppLog(message, sourceType string, tags map[string]string) error {
+	if !l.allow(tags, len(message)) {
+		return nil
+	}
+	return l.IngressClient.SendAppLog(message, sourceType, tags)
+}
+
+func (l *Limiter) SendAppErrorLog(message, sourceType string, tags map[string]string) error {
+	if !l.allow(tags, len(message)) {
+		return nil
+	}
+	return l.IngressClient.SendAppErrorLog(message, sourceType, tags)
+}
+
+// BytesPerSecond returns the rate the limiter limits every container to.
+func (l *Limiter) BytesPerSecond() int64 {
+	return l.bytesPerSecond
+}
+
+// DroppedBytes returns the log output dropped for the container logging
+// under logGuid and index.
+func (l *Limiter) DroppedBytes(logGuid string, index int) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[bucketKey(logGuid, strconv.Itoa(index))]
+	if !ok {
+		return 0
+	}
+	return b.dropped
+}
+
+func (l *Limiter) allow(tags map[string]string, size int) bool {
+	now := l.clock.Now()
+	key := bucketKey(tags["source_id"], tags["instance_id"])
+	limit := float64(l.bytesPerSecond)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.updated).Seconds() * limit
+	if b.tokens > limit {
+		b.tokens = limit
+	}
+	b.updated = now
+
+	if b.tokens < float64(size) {
+		b.dropped += int64(size)
+		return false
+	}
+	b.tokens -= float64(size)
+	return true
+}
+
+// prune forgets the containers that have not logged for idleBucketTTL.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < idleBucketTTL {
+		return
+	}
+	l.lastPrune = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func bucketKey(sourceID, instanceID string) string {
+	return sourceID + "/" + instanceID
+}
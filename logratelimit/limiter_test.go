@@ -0,0 +1,63 @@
+package logratelimit_test
+
+import (
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/rep/logratelimit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter", func() {
+	var (
+		metronClient *mfakes.FakeIngressClient
+		fakeClock    *fakeclock.FakeClock
+		limiter      *logratelimit.Limiter
+		tags         map[string]string
+	)
+
+	BeforeEach(func() {
+		metronClient = new(mfakes.FakeIngressClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		limiter = logratelimit.NewLimiter(metronClient, 100, fakeClock)
+		tags = map[string]string{"source_id": "log-guid", "instance_id": "1"}
+	})
+
+	It("sends the logs of a container within its limit", func() {
+		Expect(limiter.SendAppLog(strings.Repeat("a", 60), "APP/PROC/WEB", tags)).To(Succeed())
+		Expect(limiter.SendAppErrorLog(strings.Repeat("b", 40), "APP/PROC/WEB", tags)).To(Succeed())
+
+		Expect(metronClient.SendAppLogCallCount()).To(Equal(1))
+		Expect(metronClient.SendAppErrorLogCallCount()).To(Equal(1))
+		Expect(limiter.DroppedBytes("log-guid", 1)).To(BeZero())
+	})
+
+	It("drops and counts the logs of a container beyond its limit", func() {
+		Expect(limiter.SendAppLog(strings.Repeat("a", 80), "APP/PROC/WEB", tags)).To(Succeed())
+		Expect(limiter.SendAppLog(strings.Repeat("b", 30), "APP/PROC/WEB", tags)).To(Succeed())
+
+		Expect(metronClient.SendAppLogCallCount()).To(Equal(1))
+		Expect(limiter.DroppedBytes("log-guid", 1)).To(Equal(int64(30)))
+
+		By("sending them again once the container is back within its limit")
+		fakeClock.Increment(time.Second)
+		Expect(limiter.SendAppLog(strings.Repeat("c", 30), "APP/PROC/WEB", tags)).To(Succeed())
+		Expect(metronClient.SendAppLogCallCount()).To(Equal(2))
+	})
+
+	It("limits every container on its own", func() {
+		Expect(limiter.SendAppLog(strings.Repeat("a", 100), "APP/PROC/WEB", tags)).To(Succeed())
+		Expect(limiter.SendAppLog(strings.Repeat("b", 100), "APP/PROC/WEB", map[string]string{"source_id": "log-guid", "instance_id": "2"})).To(Succeed())
+
+		Expect(metronClient.SendAppLogCallCount()).To(Equal(2))
+	})
+
+	It("passes every other envelope through", func() {
+		Expect(limiter.SendMetric("SomeMetric", 1)).To(Succeed())
+		Expect(metronClient.SendMetricCallCount()).To(Equal(1))
+	})
+})
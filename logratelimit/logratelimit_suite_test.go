@@ -0,0 +1,13 @@
+package logratelimit_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLogRateLimit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Log Rate Limit Suite")
+}
@@ -0,0 +1 @@
+package logratelimit // import "code.cloudfoundry.org/rep/logratelimit"
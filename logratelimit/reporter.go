@@ -0,0 +1,50 @@
+package logratelimit
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Reporter reports the log rate limits of the containers of a cell whose
+// logs go through a Limiter.
+type Reporter struct {
+	executorClient executor.Client
+	limiter        *Limiter
+}
+
+func NewReporter(executorClient executor.Client, limiter *Limiter) *Reporter {
+	return &Reporter{executorClient: executorClient, limiter: limiter}
+}
+
+// Statuses returns the log rate limit of every LRP instance and task on the
+// cell, by instance guid and task guid, as the lower of the limit of the
+// container and that of the limiter.
+func (r *Reporter) Statuses(logger lager.Logger) (map[string]rep.LogRateLimitStatus, error) {
+	containers, err := r.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return nil, err
+	}
+
+	statuses := map[string]rep.LogRateLimitStatus{}
+	for i := range containers {
+		container := &containers[i]
+
+		var guid string
+		switch container.Tags[rep.LifecycleTag] {
+		case rep.LRPLifecycle:
+			guid = container.Tags[rep.InstanceGuidTag]
+		case rep.TaskLifecycle:
+			guid = container.Guid
+		default:
+			continue
+		}
+
+		statuses[guid] = rep.LogRateLimitStatus{
+			BytesPerSecond: rep.AppliedLogRateLimit(container.LogRateLimitBytesPerSecond, r.limiter.BytesPerSecond()),
+			DroppedBytes:   r.limiter.DroppedBytes(container.LogConfig.Guid, container.LogConfig.Index),
+		}
+	}
+	return statuses, nil
+}
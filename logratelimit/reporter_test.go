@@ -0,0 +1,60 @@
+package logratelimit_test
+
+import (
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/executor"
+	fake_client "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/logratelimit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reporter", func() {
+	var (
+		logger         *lagertest.TestLogger
+		executorClient *fake_client.FakeClient
+		limiter        *logratelimit.Limiter
+		reporter       *logratelimit.Reporter
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(fake_client.FakeClient)
+		limiter = logratelimit.NewLimiter(new(mfakes.FakeIngressClient), 100, fakeclock.NewFakeClock(time.Now()))
+		reporter = logratelimit.NewReporter(executorClient, limiter)
+
+		lrpContainer := executor.Container{
+			Guid: "lrp-container",
+			Tags: executor.Tags{rep.LifecycleTag: rep.LRPLifecycle, rep.InstanceGuidTag: "instance-guid"},
+		}
+		lrpContainer.LogConfig = executor.LogConfig{Guid: "log-guid", Index: 1}
+		lrpContainer.LogRateLimitBytesPerSecond = -1
+
+		taskContainer := executor.Container{
+			Guid: "task-guid",
+			Tags: executor.Tags{rep.LifecycleTag: rep.TaskLifecycle},
+		}
+		taskContainer.LogConfig = executor.LogConfig{Guid: "task-log-guid"}
+		taskContainer.LogRateLimitBytesPerSecond = 50
+
+		executorClient.ListContainersReturns([]executor.Container{lrpContainer, taskContainer}, nil)
+	})
+
+	It("reports the applied limit and the dropped output of every container", func() {
+		Expect(limiter.SendAppLog(strings.Repeat("a", 120), "APP/PROC/WEB", map[string]string{"source_id": "log-guid", "instance_id": "1"})).To(Succeed())
+
+		statuses, err := reporter.Statuses(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(Equal(map[string]rep.LogRateLimitStatus{
+			"instance-guid": {BytesPerSecond: 100, DroppedBytes: 120},
+			"task-guid":     {BytesPerSecond: 50},
+		}))
+	})
+})
@@ -0,0 +1 @@
+package fake_maintenance // import "code.cloudfoundry.org/rep/maintenance/fake_maintenance"
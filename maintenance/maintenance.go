@@ -0,0 +1,51 @@
+package maintenance
+
+import "sync"
+
+//go:generate counterfeiter -o fake_maintenance/fake_maintainable.go . Maintainable
+type Maintainable interface {
+	StartMaintenance()
+	StopMaintenance()
+}
+
+//go:generate counterfeiter -o fake_maintenance/fake_maintenance_reporter.go . MaintenanceReporter
+type MaintenanceReporter interface {
+	InMaintenance() bool
+}
+
+// maintenanceContext tracks whether the cell is in maintenance mode. Unlike
+// evacuation, maintenance can be left again and does not affect the
+// containers already running on the cell.
+type maintenanceContext struct {
+	inMaintenance bool
+	mu            sync.RWMutex
+}
+
+func New(inMaintenance bool) (Maintainable, MaintenanceReporter) {
+	maintenanceContext := &maintenanceContext{
+		inMaintenance: inMaintenance,
+	}
+
+	return maintenanceContext, maintenanceContext
+}
+
+func (m *maintenanceContext) StartMaintenance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inMaintenance = true
+}
+
+func (m *maintenanceContext) StopMaintenance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inMaintenance = false
+}
+
+func (m *maintenanceContext) InMaintenance() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.inMaintenance
+}
@@ -0,0 +1,50 @@
+package maintenance_test
+
+import (
+	"code.cloudfoundry.org/rep/maintenance"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance", func() {
+	var (
+		maintainable        maintenance.Maintainable
+		maintenanceReporter maintenance.MaintenanceReporter
+		inMaintenance       bool
+	)
+
+	BeforeEach(func() {
+		inMaintenance = false
+	})
+
+	JustBeforeEach(func() {
+		maintainable, maintenanceReporter = maintenance.New(inMaintenance)
+	})
+
+	Context("when the cell does not start in maintenance", func() {
+		It("does not report maintenance", func() {
+			Expect(maintenanceReporter.InMaintenance()).To(BeFalse())
+		})
+
+		It("reports maintenance once StartMaintenance has been called", func() {
+			maintainable.StartMaintenance()
+			Expect(maintenanceReporter.InMaintenance()).To(BeTrue())
+		})
+	})
+
+	Context("when the cell starts in maintenance", func() {
+		BeforeEach(func() {
+			inMaintenance = true
+		})
+
+		It("reports maintenance", func() {
+			Expect(maintenanceReporter.InMaintenance()).To(BeTrue())
+		})
+
+		It("stops reporting maintenance once StopMaintenance has been called", func() {
+			maintainable.StopMaintenance()
+			Expect(maintenanceReporter.InMaintenance()).To(BeFalse())
+		})
+	})
+})
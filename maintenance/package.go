@@ -0,0 +1 @@
+package maintenance // import "code.cloudfoundry.org/rep/maintenance"
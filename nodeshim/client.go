@@ -0,0 +1,113 @@
+package nodeshim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	ConditionTrue    = "True"
+	ConditionFalse   = "False"
+	ConditionUnknown = "Unknown"
+)
+
+// Condition mirrors the NodeCondition type of the Kubernetes API.
+type Condition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastHeartbeatTime  time.Time `json:"lastHeartbeatTime"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+//go:generate counterfeiter -o nodeshimfakes/fake_client.go . Client
+
+// Client updates the Node object of the kubelet the cell is co-located with.
+type Client interface {
+	UpdateNode(logger lager.Logger, nodeName string, annotations map[string]string, conditions []Condition) error
+}
+
+type apiClient struct {
+	httpClient *http.Client
+	apiURL     string
+	tokenFile  string
+}
+
+// NewAPIClient returns a Client that patches nodes through the Kubernetes
+// API at apiURL, authenticating with the bearer token in tokenFile. The token
+// file is read on every update so that rotated tokens are picked up.
+func NewAPIClient(httpClient *http.Client, apiURL, tokenFile string) Client {
+	return &apiClient{
+		httpClient: httpClient,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		tokenFile:  tokenFile,
+	}
+}
+
+func (c *apiClient) UpdateNode(logger lager.Logger, nodeName string, annotations map[string]string, conditions []Condition) error {
+	nodeURL := c.apiURL + "/api/v1/nodes/" + url.PathEscape(nodeName)
+
+	// a null annotations field would remove every annotation of the node
+	if len(annotations) > 0 {
+		err := c.patch(nodeURL, "application/merge-patch+json", map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": annotations},
+		})
+		if err != nil {
+			logger.Error("failed-to-patch-node-annotations", err)
+			return err
+		}
+	}
+
+	// conditions are merged by type, leaving the ones owned by the kubelet alone
+	err := c.patch(nodeURL+"/status", "application/strategic-merge-patch+json", map[string]interface{}{
+		"status": map[string]interface{}{"conditions": conditions},
+	})
+	if err != nil {
+		logger.Error("failed-to-patch-node-conditions", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *apiClient) patch(url, contentType string, patch interface{}) error {
+	payload, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PATCH", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	if c.tokenFile != "" {
+		token, err := ioutil.ReadFile(c.tokenFile)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package nodeshim_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/nodeshim"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("APIClient", func() {
+	var (
+		logger     *lagertest.TestLogger
+		fakeServer *ghttp.Server
+		tokenFile  *os.File
+		client     nodeshim.Client
+		conditions []nodeshim.Condition
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeServer = ghttp.NewServer()
+
+		var err error
+		tokenFile, err = ioutil.TempFile("", "token")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = tokenFile.WriteString("some-token\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokenFile.Close()).To(Succeed())
+
+		client = nodeshim.NewAPIClient(http.DefaultClient, fakeServer.URL()+"/", tokenFile.Name())
+
+		now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+		conditions = []nodeshim.Condition{{
+			Type:               nodeshim.ConditionCellHealthy,
+			Status:             nodeshim.ConditionTrue,
+			Reason:             "CellHealthy",
+			LastHeartbeatTime:  now,
+			LastTransitionTime: now,
+		}}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+		Expect(os.Remove(tokenFile.Name())).To(Succeed())
+	})
+
+	It("patches the annotations and the conditions of the node", func() {
+		fakeServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", "/api/v1/nodes/some-node"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+				ghttp.VerifyContentType("application/merge-patch+json"),
+				ghttp.VerifyJSON(`{"metadata": {"annotations": {"diego.cloudfoundry.org/cell-id": "cell-id"}}}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", "/api/v1/nodes/some-node/status"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+				ghttp.VerifyContentType("application/strategic-merge-patch+json"),
+				ghttp.VerifyJSON(`{"status": {"conditions": [{
+					"type": "DiegoCellHealthy",
+					"status": "True",
+					"reason": "CellHealthy",
+					"lastHeartbeatTime": "2026-03-04T05:06:07Z",
+					"lastTransitionTime": "2026-03-04T05:06:07Z"
+				}]}}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+		)
+
+		err := client.UpdateNode(logger, "some-node", map[string]string{"diego.cloudfoundry.org/cell-id": "cell-id"}, conditions)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("does not patch the annotations when there are none", func() {
+		fakeServer.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PATCH", "/api/v1/nodes/some-node/status"),
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		))
+
+		Expect(client.UpdateNode(logger, "some-node", nil, conditions)).To(Succeed())
+		Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("errors when the API rejects the patch", func() {
+		fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, `{"reason": "Forbidden"}`))
+
+		err := client.UpdateNode(logger, "some-node", nil, conditions)
+		Expect(err).To(MatchError(`unexpected status code: 403: {"reason": "Forbidden"}`))
+	})
+})
@@ -0,0 +1,13 @@
+package nodeshim_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestNodeshim(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Nodeshim Suite")
+}
@@ -0,0 +1 @@
+package nodeshimfakes // import "code.cloudfoundry.org/rep/nodeshim/nodeshimfakes"
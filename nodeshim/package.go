@@ -0,0 +1 @@
+package nodeshim // import "code.cloudfoundry.org/rep/nodeshim"
@@ -0,0 +1,158 @@
+package nodeshim
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	AnnotationPrefix = "diego.cloudfoundry.org/"
+
+	ConditionCellHealthy     = "DiegoCellHealthy"
+	ConditionCellEvacuating  = "DiegoCellEvacuating"
+	ConditionCellMaintenance = "DiegoCellMaintenance"
+)
+
+//go:generate counterfeiter -o nodeshimfakes/fake_state_reporter.go . StateReporter
+
+// StateReporter is the part of the auction cell rep the shim mirrors.
+type StateReporter interface {
+	State(ctx context.Context, logger lager.Logger) (rep.CellState, bool, error)
+}
+
+// Shim periodically mirrors the capacity and health of the cell into the
+// annotations and conditions of the Kubernetes node it is co-located with.
+type Shim struct {
+	logger        lager.Logger
+	stateReporter StateReporter
+	client        Client
+	nodeName      string
+	clock         clock.Clock
+	interval      time.Duration
+
+	transitions map[string]transition
+}
+
+type transition struct {
+	status string
+	time   time.Time
+}
+
+func NewShim(
+	logger lager.Logger,
+	stateReporter StateReporter,
+	client Client,
+	nodeName string,
+	clock clock.Clock,
+	interval time.Duration,
+) *Shim {
+	return &Shim{
+		logger:        logger.Session("node-shim", lager.Data{"node-name": nodeName}),
+		stateReporter: stateReporter,
+		client:        client,
+		nodeName:      nodeName,
+		clock:         clock,
+		interval:      interval,
+		transitions:   map[string]transition{},
+	}
+}
+
+func (s *Shim) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	s.logger.Info("starting")
+	defer s.logger.Info("finished")
+
+	s.sync()
+	close(ready)
+
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			s.sync()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (s *Shim) sync() {
+	logger := s.logger.Session("sync")
+
+	now := s.clock.Now()
+	state, healthy, err := s.stateReporter.State(context.Background(), logger)
+	if err != nil {
+		logger.Error("failed-to-fetch-cell-state", err)
+		conditions := []Condition{s.condition(ConditionCellHealthy, ConditionUnknown, "StateUnavailable", err.Error(), now)}
+		s.update(logger, nil, conditions)
+		return
+	}
+
+	healthyStatus, healthyReason := ConditionTrue, "CellHealthy"
+	if !healthy {
+		healthyStatus, healthyReason = ConditionFalse, "CellUnhealthy"
+	}
+
+	conditions := []Condition{
+		s.condition(ConditionCellHealthy, healthyStatus, healthyReason, "", now),
+		s.condition(ConditionCellEvacuating, conditionStatus(state.Evacuating), "", "", now),
+		s.condition(ConditionCellMaintenance, conditionStatus(state.Maintenance), "", "", now),
+	}
+	s.update(logger, Annotations(state), conditions)
+}
+
+func (s *Shim) update(logger lager.Logger, annotations map[string]string, conditions []Condition) {
+	err := s.client.UpdateNode(logger, s.nodeName, annotations, conditions)
+	if err != nil {
+		logger.Error("failed-to-update-node", err)
+	}
+}
+
+// condition keeps the transition time of a condition until its status
+// changes, as Kubernetes expects.
+func (s *Shim) condition(conditionType, status, reason, message string, now time.Time) Condition {
+	last, ok := s.transitions[conditionType]
+	if !ok || last.status != status {
+		last = transition{status: status, time: now}
+		s.transitions[conditionType] = last
+	}
+
+	return Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: last.time,
+	}
+}
+
+func conditionStatus(b bool) string {
+	if b {
+		return ConditionTrue
+	}
+	return ConditionFalse
+}
+
+// Annotations describes the capacity of the cell as node annotations.
+func Annotations(state rep.CellState) map[string]string {
+	return map[string]string{
+		AnnotationPrefix + "cell-id":              state.CellID,
+		AnnotationPrefix + "zone":                 state.Zone,
+		AnnotationPrefix + "available-memory-mb":  strconv.Itoa(int(state.AvailableResources.MemoryMB)),
+		AnnotationPrefix + "available-disk-mb":    strconv.Itoa(int(state.AvailableResources.DiskMB)),
+		AnnotationPrefix + "available-containers": strconv.Itoa(state.AvailableResources.Containers),
+		AnnotationPrefix + "total-memory-mb":      strconv.Itoa(int(state.TotalResources.MemoryMB)),
+		AnnotationPrefix + "total-disk-mb":        strconv.Itoa(int(state.TotalResources.DiskMB)),
+		AnnotationPrefix + "total-containers":     strconv.Itoa(state.TotalResources.Containers),
+		AnnotationPrefix + "lrp-count":            strconv.Itoa(len(state.LRPs)),
+		AnnotationPrefix + "task-count":           strconv.Itoa(len(state.Tasks)),
+	}
+}
@@ -0,0 +1,122 @@
+package nodeshim_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/nodeshim"
+	"code.cloudfoundry.org/rep/nodeshim/nodeshimfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+)
+
+var _ = Describe("Shim", func() {
+	var (
+		fakeStateReporter *nodeshimfakes.FakeStateReporter
+		fakeClient        *nodeshimfakes.FakeClient
+		fakeClock         *fakeclock.FakeClock
+		startTime         time.Time
+		process           ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeStateReporter = new(nodeshimfakes.FakeStateReporter)
+		fakeClient = new(nodeshimfakes.FakeClient)
+		startTime = time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+		fakeClock = fakeclock.NewFakeClock(startTime)
+
+		fakeStateReporter.StateReturns(rep.CellState{
+			CellID:             "cell-id",
+			Zone:               "z1",
+			AvailableResources: rep.Resources{MemoryMB: 512, DiskMB: 1024, Containers: 3},
+			TotalResources:     rep.Resources{MemoryMB: 1024, DiskMB: 2048, Containers: 4},
+			LRPs:               []rep.LRP{{}},
+			Maintenance:        true,
+		}, true, nil)
+	})
+
+	JustBeforeEach(func() {
+		shim := nodeshim.NewShim(lagertest.NewTestLogger("test"), fakeStateReporter, fakeClient, "some-node", fakeClock, 10*time.Second)
+		process = ginkgomon.Invoke(shim)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("mirrors the cell state into the node", func() {
+		Expect(fakeClient.UpdateNodeCallCount()).To(Equal(1))
+		_, nodeName, annotations, conditions := fakeClient.UpdateNodeArgsForCall(0)
+		Expect(nodeName).To(Equal("some-node"))
+		Expect(annotations).To(Equal(map[string]string{
+			"diego.cloudfoundry.org/cell-id":              "cell-id",
+			"diego.cloudfoundry.org/zone":                 "z1",
+			"diego.cloudfoundry.org/available-memory-mb":  "512",
+			"diego.cloudfoundry.org/available-disk-mb":    "1024",
+			"diego.cloudfoundry.org/available-containers": "3",
+			"diego.cloudfoundry.org/total-memory-mb":      "1024",
+			"diego.cloudfoundry.org/total-disk-mb":        "2048",
+			"diego.cloudfoundry.org/total-containers":     "4",
+			"diego.cloudfoundry.org/lrp-count":            "1",
+			"diego.cloudfoundry.org/task-count":           "0",
+		}))
+		Expect(conditions).To(Equal([]nodeshim.Condition{
+			{Type: nodeshim.ConditionCellHealthy, Status: nodeshim.ConditionTrue, Reason: "CellHealthy", LastHeartbeatTime: startTime, LastTransitionTime: startTime},
+			{Type: nodeshim.ConditionCellEvacuating, Status: nodeshim.ConditionFalse, LastHeartbeatTime: startTime, LastTransitionTime: startTime},
+			{Type: nodeshim.ConditionCellMaintenance, Status: nodeshim.ConditionTrue, LastHeartbeatTime: startTime, LastTransitionTime: startTime},
+		}))
+	})
+
+	It("only moves the transition time of the conditions that changed", func() {
+		fakeStateReporter.StateReturns(rep.CellState{CellID: "cell-id", Evacuating: true, Maintenance: true}, true, nil)
+		fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+
+		Eventually(fakeClient.UpdateNodeCallCount).Should(Equal(2))
+		_, _, _, conditions := fakeClient.UpdateNodeArgsForCall(1)
+
+		now := startTime.Add(10 * time.Second)
+		Expect(conditions).To(Equal([]nodeshim.Condition{
+			{Type: nodeshim.ConditionCellHealthy, Status: nodeshim.ConditionTrue, Reason: "CellHealthy", LastHeartbeatTime: now, LastTransitionTime: startTime},
+			{Type: nodeshim.ConditionCellEvacuating, Status: nodeshim.ConditionTrue, LastHeartbeatTime: now, LastTransitionTime: now},
+			{Type: nodeshim.ConditionCellMaintenance, Status: nodeshim.ConditionTrue, LastHeartbeatTime: now, LastTransitionTime: startTime},
+		}))
+	})
+
+	Context("when the cell is unhealthy", func() {
+		BeforeEach(func() {
+			fakeStateReporter.StateReturns(rep.CellState{}, false, nil)
+		})
+
+		It("reports the cell as unhealthy", func() {
+			_, _, _, conditions := fakeClient.UpdateNodeArgsForCall(0)
+			Expect(conditions[0].Status).To(Equal(nodeshim.ConditionFalse))
+			Expect(conditions[0].Reason).To(Equal("CellUnhealthy"))
+		})
+	})
+
+	Context("when fetching the cell state fails", func() {
+		BeforeEach(func() {
+			fakeStateReporter.StateReturns(rep.CellState{}, false, errors.New("boom"))
+		})
+
+		It("reports the health of the cell as unknown and leaves the annotations alone", func() {
+			_, _, annotations, conditions := fakeClient.UpdateNodeArgsForCall(0)
+			Expect(annotations).To(BeNil())
+			Expect(conditions).To(Equal([]nodeshim.Condition{{
+				Type:               nodeshim.ConditionCellHealthy,
+				Status:             nodeshim.ConditionUnknown,
+				Reason:             "StateUnavailable",
+				Message:            "boom",
+				LastHeartbeatTime:  startTime,
+				LastTransitionTime: startTime,
+			}}))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tedsuo/rata"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps lower case HTTP methods to the operation serving them.
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary,omitempty"`
+	Parameters  []Parameter               `json:"parameters,omitempty"`
+	RequestBody *RequestBody              `json:"requestBody,omitempty"`
+	Responses   map[string]ResponseObject `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Operation describes what a route takes and returns. Request and the
+// response bodies are values of the Go types the rep encodes as JSON; their
+// schemas are derived from the types. Query names the optional query
+// parameters of the route.
+type Operation struct {
+	Summary   string
+	Query     []string
+	Request   interface{}
+	Responses map[int]Response
+}
+
+type Response struct {
+	Description string
+	Body        interface{}
+}
+
+var pathParameter = regexp.MustCompile(`:([a-z_]+)`)
+
+// Generate builds the document for routes, describing each route with the
+// operation registered under its name. Routes without an operation are left
+// out, so that undocumented routes are noticed.
+func Generate(title, version string, routes rata.Routes, operations map[string]Operation) (*Document, error) {
+	schemas := newSchemaRegistry("#/components/schemas/")
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]PathItem{},
+	}
+
+	for _, route := range routes {
+		operation, ok := operations[route.Name]
+		if !ok {
+			continue
+		}
+
+		path := pathParameter.ReplaceAllString(route.Path, "{$1}")
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+
+		method := strings.ToLower(route.Method)
+		if _, exists := item[method]; exists {
+			continue
+		}
+
+		object := &OperationObject{
+			OperationID: route.Name,
+			Summary:     operation.Summary,
+			Responses:   map[string]ResponseObject{},
+		}
+
+		for _, match := range pathParameter.FindAllStringSubmatch(route.Path, -1) {
+			object.Parameters = append(object.Parameters, Parameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+		for _, name := range operation.Query {
+			object.Parameters = append(object.Parameters, Parameter{
+				Name:   name,
+				In:     "query",
+				Schema: &Schema{Type: "string"},
+			})
+		}
+
+		if operation.Request != nil {
+			object.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(schemas.schemaFor(operation.Request)),
+			}
+		}
+
+		if len(operation.Responses) == 0 {
+			return nil, fmt.Errorf("operation %s has no responses", route.Name)
+		}
+		for statusCode, response := range operation.Responses {
+			responseObject := ResponseObject{Description: response.Description}
+			if response.Body != nil {
+				responseObject.Content = jsonContent(schemas.schemaFor(response.Body))
+			}
+			object.Responses[fmt.Sprintf("%d", statusCode)] = responseObject
+		}
+
+		item[method] = object
+	}
+
+	doc.Components.Schemas = schemas.schemas
+	return doc, nil
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
@@ -0,0 +1,143 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/openapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/rata"
+)
+
+type Embedded struct {
+	Zone string `json:"zone"`
+}
+
+type Thing struct {
+	Embedded
+	Name     string            `json:"name"`
+	Count    int32             `json:",omitempty"`
+	Created  time.Time         `json:"created"`
+	Labels   map[string]string `json:"labels"`
+	Children []*Thing          `json:"children"`
+	Ignored  string            `json:"-"`
+	private  string
+}
+
+var _ = Describe("Generate", func() {
+	var (
+		routes     rata.Routes
+		operations map[string]openapi.Operation
+	)
+
+	BeforeEach(func() {
+		routes = rata.Routes{
+			{Path: "/things/:thing_guid", Method: "PUT", Name: "UpdateThing"},
+			{Path: "/undocumented", Method: "GET", Name: "Undocumented"},
+		}
+		operations = map[string]openapi.Operation{
+			"UpdateThing": {
+				Summary: "Updates a thing",
+				Request: Thing{},
+				Responses: map[int]openapi.Response{
+					http.StatusOK:         {Description: "the updated thing", Body: Thing{}},
+					http.StatusBadRequest: {Description: "the thing is invalid"},
+				},
+			},
+		}
+	})
+
+	It("describes the documented routes", func() {
+		doc, err := openapi.Generate("Things", "1", routes, operations)
+		Expect(err).NotTo(HaveOccurred())
+
+		payload, err := json.Marshal(doc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(payload).To(MatchJSON(`{
+			"openapi": "3.0.3",
+			"info": {"title": "Things", "version": "1"},
+			"paths": {
+				"/things/{thing_guid}": {
+					"put": {
+						"operationId": "UpdateThing",
+						"summary": "Updates a thing",
+						"parameters": [{"name": "thing_guid", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"requestBody": {
+							"required": true,
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}
+						},
+						"responses": {
+							"200": {
+								"description": "the updated thing",
+								"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}
+							},
+							"400": {"description": "the thing is invalid"}
+						}
+					}
+				}
+			},
+			"components": {
+				"schemas": {
+					"Thing": {
+						"type": "object",
+						"properties": {
+							"zone": {"type": "string"},
+							"name": {"type": "string"},
+							"Count": {"type": "integer", "format": "int32"},
+							"created": {"type": "string", "format": "date-time"},
+							"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+							"children": {"type": "array", "items": {"$ref": "#/components/schemas/Thing"}}
+						}
+					}
+				}
+			}
+		}`))
+	})
+
+	It("describes the query parameters of an operation", func() {
+		operations["UpdateThing"] = openapi.Operation{
+			Summary:   "Updates a thing",
+			Query:     []string{"since"},
+			Responses: map[int]openapi.Response{http.StatusOK: {Description: "the updated thing"}},
+		}
+
+		doc, err := openapi.Generate("Things", "1", routes, operations)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(doc.Paths["/things/{thing_guid}"]["put"].Parameters).To(ConsistOf(
+			openapi.Parameter{Name: "thing_guid", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+			openapi.Parameter{Name: "since", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		))
+	})
+
+	It("errors when an operation has no responses", func() {
+		operations["UpdateThing"] = openapi.Operation{Summary: "Updates a thing"}
+
+		_, err := openapi.Generate("Things", "1", routes, operations)
+		Expect(err).To(MatchError("operation UpdateThing has no responses"))
+	})
+})
+
+var _ = Describe("RepDocument", func() {
+	It("describes every route of the rep", func() {
+		doc, err := openapi.RepDocument()
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, route := range rep.Routes {
+			Expect(operations(doc)).To(ContainElement(route.Name))
+		}
+	})
+})
+
+func operations(doc *openapi.Document) []string {
+	ids := []string{}
+	for _, item := range doc.Paths {
+		for _, operation := range item {
+			ids = append(ids, operation.OperationID)
+		}
+	}
+	return ids
+}
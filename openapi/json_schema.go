@@ -0,0 +1,38 @@
+package openapi
+
+import "code.cloudfoundry.org/rep"
+
+const JSONSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema is a standalone JSON schema of a type the rep encodes. The
+// structs it uses are described once in Definitions.
+type JSONSchema struct {
+	Schema      string             `json:"$schema"`
+	Ref         string             `json:"$ref"`
+	Definitions map[string]*Schema `json:"definitions"`
+}
+
+// ExportJSONSchema derives the schema of the JSON encoding of value the same
+// way Generate derives the schemas of request and response bodies. Encoding
+// it yields the same document for the same types, so that exported schemas
+// can be compared across rep versions.
+func ExportJSONSchema(value interface{}) *JSONSchema {
+	schemas := newSchemaRegistry("#/definitions/")
+	root := schemas.schemaFor(value)
+
+	return &JSONSchema{
+		Schema:      JSONSchemaVersion,
+		Ref:         root.Ref,
+		Definitions: schemas.schemas,
+	}
+}
+
+// CellStateSchema is the schema of the state cells send the auctioneer.
+func CellStateSchema() *JSONSchema {
+	return ExportJSONSchema(rep.CellState{})
+}
+
+// WorkSchema is the schema of the work the auctioneer sends cells.
+func WorkSchema() *JSONSchema {
+	return ExportJSONSchema(rep.Work{})
+}
@@ -0,0 +1,65 @@
+package openapi_test
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/rep/openapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExportJSONSchema", func() {
+	It("describes the type with its definitions", func() {
+		payload, err := json.Marshal(openapi.ExportJSONSchema(Thing{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(payload).To(MatchJSON(`{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"$ref": "#/definitions/Thing",
+			"definitions": {
+				"Thing": {
+					"type": "object",
+					"properties": {
+						"zone": {"type": "string"},
+						"name": {"type": "string"},
+						"Count": {"type": "integer", "format": "int32"},
+						"created": {"type": "string", "format": "date-time"},
+						"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+						"children": {"type": "array", "items": {"$ref": "#/definitions/Thing"}}
+					}
+				}
+			}
+		}`))
+	})
+
+	It("exports the same document every time", func() {
+		first, err := json.Marshal(openapi.CellStateSchema())
+		Expect(err).NotTo(HaveOccurred())
+		second, err := json.Marshal(openapi.CellStateSchema())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first).To(Equal(second))
+	})
+
+	Describe("CellStateSchema", func() {
+		It("describes the cell state and the work it holds", func() {
+			schema := openapi.CellStateSchema()
+			Expect(schema.Ref).To(Equal("#/definitions/CellState"))
+			Expect(schema.Definitions["CellState"].Properties).To(HaveKey("cell_id"))
+			Expect(schema.Definitions["CellState"].Properties["LRPs"]).To(Equal(&openapi.Schema{
+				Type:  "array",
+				Items: &openapi.Schema{Ref: "#/definitions/LRP"},
+			}))
+			Expect(schema.Definitions["LRP"].Properties).To(HaveKey("instance_guid"))
+			Expect(schema.Definitions["LRP"].Properties).To(HaveKey("process_guid"))
+			Expect(schema.Definitions["LRP"].Properties).To(HaveKeyWithValue("memory", &openapi.Schema{Type: "string"}))
+		})
+	})
+
+	Describe("WorkSchema", func() {
+		It("describes the work", func() {
+			schema := openapi.WorkSchema()
+			Expect(schema.Ref).To(Equal("#/definitions/Work"))
+			Expect(schema.Definitions["Work"].Properties).To(HaveKey("cell_id"))
+			Expect(schema.Definitions["Task"].Properties).To(HaveKey("TaskGuid"))
+		})
+	})
+})
@@ -0,0 +1,350 @@
+package openapi
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/downloadcache"
+	"code.cloudfoundry.org/rep/imagecache"
+	"code.cloudfoundry.org/rep/selftest"
+)
+
+const Title = "Diego Cell Rep"
+
+// Operations describes the routes of rep.Routes. Every route the rep serves
+// is expected to be described here.
+var Operations = map[string]Operation{
+	rep.StateRoute: {
+		Summary: "Returns the capacity and work of the cell, or its change since the snapshot named by since",
+		Query:   []string{"since"},
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the cell is healthy", Body: rep.CellState{}},
+			http.StatusServiceUnavailable:  {Description: "the cell is unhealthy, its state is still returned, unless the auction routes are closed", Body: rep.CellState{}},
+			http.StatusInternalServerError: {Description: "the state could not be fetched"},
+		},
+	},
+	rep.CapacitySummaryRoute: {
+		Summary: "Returns the aggregate capacity, zone, placement tags and rootfs providers of the cell, without its LRP instances and tasks",
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the cell is healthy", Body: rep.CapacitySummary{}},
+			http.StatusServiceUnavailable:  {Description: "the cell is unhealthy, its capacity is still returned", Body: rep.CapacitySummary{}},
+			http.StatusInternalServerError: {Description: "the state could not be fetched"},
+		},
+	},
+	rep.ContainerMetricsRoute: {
+		Summary: "Returns the metrics of the containers on the cell",
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the container metrics", Body: rep.ContainerMetricsCollection{}},
+			http.StatusInternalServerError: {Description: "the metrics could not be fetched"},
+		},
+	},
+	rep.PerformRoute: {
+		Summary: "Allocates LRP instances and tasks on the cell",
+		Request: rep.Work{},
+		Responses: map[int]Response{
+			http.StatusOK:                    {Description: "the work that could not be allocated", Body: rep.Work{}},
+			http.StatusBadRequest:            {Description: "the work could not be decoded or exceeds the max request body size"},
+			http.StatusRequestEntityTooLarge: {Description: "the work exceeds the max work batch size, which is returned", Body: rep.WorkBatchTooLargeError{}},
+			http.StatusInternalServerError:   {Description: "the work could not be performed"},
+			http.StatusServiceUnavailable:    {Description: "too much work of the caller is already queued, or the auction routes are closed"},
+		},
+	},
+	rep.CanPlaceRoute: {
+		Summary: "Tells whether the cell would accept each LRP instance and task, and its score once they are placed, without reserving anything",
+		Query:   []string{"starting_container_weight", "scoring_strategy"},
+		Request: rep.Work{},
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the outcome for each LRP instance and task", Body: rep.PlacementChecks{}},
+			http.StatusBadRequest:          {Description: "the work, starting_container_weight or scoring_strategy could not be decoded"},
+			http.StatusInternalServerError: {Description: "the state could not be fetched"},
+			http.StatusServiceUnavailable:  {Description: "the auction routes are closed"},
+		},
+	},
+	rep.InfoRoute: {
+		Summary: "Returns the version of the rep, its supported API versions and feature flags, and its request limits",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the info of the rep", Body: rep.Info{}},
+		},
+	},
+	rep.ContainersRoute: {
+		Summary: "Lists the LRP instances and tasks on the cell whose labels match selector",
+		Query:   []string{"selector"},
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the matching LRP instances and tasks", Body: rep.ContainerInventory{}},
+			http.StatusBadRequest:          {Description: "the selector is invalid"},
+			http.StatusInternalServerError: {Description: "the state could not be fetched"},
+		},
+	},
+	rep.ContainerMetricsBatchRoute: {
+		Summary: "Returns the selected metrics of every container on the cell in one compact batch",
+		Query:   []string{"fields"},
+		Responses: map[int]Response{
+			http.StatusOK:         {Description: "the container metrics", Body: rep.ContainerMetricsBatch{}},
+			http.StatusBadRequest: {Description: "a field is not one of memory, cpu or disk"},
+		},
+	},
+	rep.ContainerEventsRoute: {
+		Summary: "Lists the recent lifecycle events of a container on the cell, oldest first",
+		Responses: map[int]Response{
+			http.StatusOK:             {Description: "the events of the container", Body: []rep.ContainerEvent{}},
+			http.StatusNotFound:       {Description: "no event of the container was recorded"},
+			http.StatusNotImplemented: {Description: "the container event history is not configured"},
+		},
+	},
+	rep.UpdateLRPInstanceRoute: {
+		Summary: "Updates the internal routes and metric tags of an LRP instance",
+		Request: rep.LRPUpdate{},
+		Responses: map[int]Response{
+			http.StatusAccepted:            {Description: "the update was accepted"},
+			http.StatusBadRequest:          {Description: "the update is invalid"},
+			http.StatusInternalServerError: {Description: "the update failed"},
+		},
+	},
+	rep.UpdateLRPInstanceRoute_r0: {
+		Summary: "Updates the internal routes of an LRP instance (deprecated)",
+		Request: rep.LRPUpdate{},
+		Responses: map[int]Response{
+			http.StatusAccepted:            {Description: "the update was accepted"},
+			http.StatusBadRequest:          {Description: "the update is invalid"},
+			http.StatusInternalServerError: {Description: "the update failed"},
+		},
+	},
+	rep.StopLRPInstanceRoute: {
+		Summary: "Stops an LRP instance",
+		Responses: map[int]Response{
+			http.StatusAccepted:            {Description: "the instance is stopping"},
+			http.StatusBadRequest:          {Description: "the instance is not identified"},
+			http.StatusInternalServerError: {Description: "the instance could not be stopped"},
+		},
+	},
+	rep.StopLRPInstancesRoute: {
+		Summary: "Stops many LRP instances with a single request",
+		Request: []rep.StopLRPInstanceRequest{},
+		Responses: map[int]Response{
+			http.StatusOK:         {Description: "the outcome for each instance, in the order of the request", Body: []rep.StopLRPInstanceResult{}},
+			http.StatusBadRequest: {Description: "the instances could not be decoded"},
+		},
+	},
+	rep.CancelTaskRoute: {
+		Summary: "Cancels a task",
+		Responses: map[int]Response{
+			http.StatusAccepted: {Description: "the task is being cancelled"},
+		},
+	},
+	rep.ReserveCapacityRoute: {
+		Summary: "Holds capacity on the cell for instances of a process ahead of them being auctioned",
+		Request: rep.CapacityReservationRequest{},
+		Responses: map[int]Response{
+			http.StatusCreated:             {Description: "the capacity is reserved", Body: rep.CapacityReservation{}},
+			http.StatusBadRequest:          {Description: "the reservation request is invalid"},
+			http.StatusConflict:            {Description: "the cell does not have the capacity for the reservation"},
+			http.StatusNotImplemented:      {Description: "capacity reservations are not enabled on the cell"},
+			http.StatusInternalServerError: {Description: "the capacity could not be reserved"},
+		},
+	},
+	rep.ReleaseCapacityRoute: {
+		Summary: "Releases a capacity reservation before it expires",
+		Responses: map[int]Response{
+			http.StatusNoContent:           {Description: "the reservation was released"},
+			http.StatusNotFound:            {Description: "the reservation does not exist or has expired"},
+			http.StatusNotImplemented:      {Description: "capacity reservations are not enabled on the cell"},
+			http.StatusInternalServerError: {Description: "the reservation could not be released"},
+		},
+	},
+	rep.GrowDiskQuotaRoute: {
+		Summary: "Grows the disk quota of a running container in place",
+		Request: rep.DiskQuotaGrowthRequest{},
+		Responses: map[int]Response{
+			http.StatusNoContent:           {Description: "the disk quota was grown"},
+			http.StatusBadRequest:          {Description: "the request is invalid or does not grow the disk quota"},
+			http.StatusNotFound:            {Description: "the container does not exist"},
+			http.StatusConflict:            {Description: "the container is not running or the cell does not have the disk"},
+			http.StatusNotImplemented:      {Description: "the executor backend cannot grow disk quotas in place"},
+			http.StatusInternalServerError: {Description: "the disk quota could not be grown"},
+		},
+	},
+	rep.SimResetRoute: {
+		Summary: "Resets a simulated cell",
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the cell was reset"},
+			http.StatusInternalServerError: {Description: "the cell could not be reset"},
+		},
+	},
+	rep.PingRoute: {
+		Summary: "Reports that the rep is running",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the rep is running"},
+		},
+	},
+	rep.EvacuateRoute: {
+		Summary: "Starts evacuating the cell",
+		Responses: map[int]Response{
+			http.StatusAccepted:            {Description: "the cell is evacuating, poll the ping path until it fails", Body: map[string]string{}},
+			http.StatusInternalServerError: {Description: "the evacuation could not be started"},
+		},
+	},
+	rep.StartMaintenanceRoute: {
+		Summary: "Stops the cell from accepting new work",
+		Responses: map[int]Response{
+			http.StatusNoContent: {Description: "the cell is in maintenance"},
+		},
+	},
+	rep.StopMaintenanceRoute: {
+		Summary: "Lets the cell accept new work again",
+		Responses: map[int]Response{
+			http.StatusNoContent: {Description: "the cell left maintenance"},
+		},
+	},
+	rep.PlannedRestartRoute: {
+		Summary: "Hands the presence of the cell over to the rep replacing it",
+		Responses: map[int]Response{
+			http.StatusNoContent:           {Description: "the restart is planned"},
+			http.StatusInternalServerError: {Description: "the restart could not be planned"},
+		},
+	},
+	rep.DebugConfigRoute: {
+		Summary: "Returns the configuration of the rep with secrets redacted",
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the effective configuration", Body: map[string]interface{}{}},
+			http.StatusInternalServerError: {Description: "the configuration could not be fetched"},
+		},
+	},
+	rep.OpenAPIRoute: {
+		Summary: "Returns this document",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the OpenAPI document", Body: map[string]interface{}{}},
+		},
+	},
+	rep.ImageCachePruneRoute: {
+		Summary: "Evicts the cached image layers the pruning policy allows",
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the evicted image layers", Body: imagecache.PruneResult{}},
+			http.StatusNotImplemented:      {Description: "image cache pruning is not configured"},
+			http.StatusInternalServerError: {Description: "pruning failed, the layers evicted before the failure are returned", Body: imagecache.PruneResult{}},
+		},
+	},
+	rep.BlockPlacementRoute: {
+		Summary: "Keeps the LRP instances of a process guid, or the work of a domain, off the cell until the block expires",
+		Request: rep.PlacementBlockRequest{},
+		Responses: map[int]Response{
+			http.StatusCreated:             {Description: "the placement is blocked", Body: rep.PlacementBlock{}},
+			http.StatusBadRequest:          {Description: "the block request is invalid"},
+			http.StatusInternalServerError: {Description: "the placement could not be blocked"},
+		},
+	},
+	rep.UnblockPlacementRoute: {
+		Summary: "Lifts a placement block before it expires",
+		Responses: map[int]Response{
+			http.StatusNoContent:           {Description: "the block was lifted"},
+			http.StatusNotFound:            {Description: "the block does not exist or has expired"},
+			http.StatusInternalServerError: {Description: "the block could not be lifted"},
+		},
+	},
+	rep.PlacementBlocksRoute: {
+		Summary: "Lists the placement blocks that have not expired",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the placement blocks", Body: []rep.PlacementBlock{}},
+		},
+	},
+	rep.PlacementTagsRoute: {
+		Summary: "Returns the placement tags and isolation segment the cell advertises",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the placement tags", Body: rep.CellPlacementTags{}},
+		},
+	},
+	rep.UpdatePlacementTagsRoute: {
+		Summary: "Changes the placement tags and isolation segment the cell advertises, returning the resident work they no longer match when validate is true",
+		Query:   []string{"validate"},
+		Request: rep.CellPlacementTags{},
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the placement tags were changed", Body: rep.PlacementTagsUpdate{}},
+			http.StatusBadRequest:          {Description: "the placement tags could not be decoded or are blank"},
+			http.StatusInternalServerError: {Description: "the resident work could not be validated"},
+		},
+	},
+	rep.FragmentationRoute: {
+		Summary: "Scores how fragmented the free resources of the cell are and suggests the relocations that would defragment them",
+		Responses: map[int]Response{
+			http.StatusOK:                  {Description: "the fragmentation report", Body: rep.FragmentationReport{}},
+			http.StatusInternalServerError: {Description: "the cell state could not be fetched"},
+		},
+	},
+	rep.ConsistencyRoute: {
+		Summary: "Returns the divergences the last comparison of the LRP containers of the cell with the actual LRPs BBS places on it found",
+		Responses: map[int]Response{
+			http.StatusOK:             {Description: "the consistency report", Body: rep.ConsistencyReport{}},
+			http.StatusNotFound:       {Description: "no comparison has completed yet"},
+			http.StatusNotImplemented: {Description: "the consistency checker is not configured"},
+		},
+	},
+	rep.CacheStatsRoute: {
+		Summary: "Counts the downloads the executor download cache served and missed, and the cached downloads it evicted",
+		Responses: map[int]Response{
+			http.StatusOK:             {Description: "the download cache statistics", Body: downloadcache.Stats{}},
+			http.StatusNotImplemented: {Description: "download cache statistics are not configured"},
+		},
+	},
+	rep.SelfTestRoute: {
+		Summary: "Creates, runs and destroys a tiny container to verify the cell can run workloads, timing every stage",
+		Responses: map[int]Response{
+			http.StatusOK:                 {Description: "the self test passed", Body: selftest.Report{}},
+			http.StatusServiceUnavailable: {Description: "a stage of the self test failed", Body: selftest.Report{}},
+			http.StatusNotImplemented:     {Description: "the cell has no rootfs to run a self test with"},
+		},
+	},
+	rep.CapacityReportRoute: {
+		Summary: "Summarizes the placements accepted and rejected over the last hours by reason and resource shape, and the peak utilization of the cell",
+		Query:   []string{"hours"},
+		Responses: map[int]Response{
+			http.StatusOK:             {Description: "the capacity report", Body: rep.CapacityReport{}},
+			http.StatusBadRequest:     {Description: "hours is not a number"},
+			http.StatusNotImplemented: {Description: "placement history is not configured"},
+		},
+	},
+	rep.AuctionRoutesRoute: {
+		Summary: "Tells whether the auction routes of the cell are closed",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the auction routes status", Body: rep.AuctionRoutesStatus{}},
+		},
+	},
+	rep.CloseAuctionRoutesRoute: {
+		Summary: "Closes the state, capacity, perform, can place and capacity reservation routes of the cell, keeping the admin and container routes open, to freeze scheduling onto it until they are opened again or the rep restarts",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the auction routes are closed", Body: rep.AuctionRoutesStatus{}},
+		},
+	},
+	rep.OpenAuctionRoutesRoute: {
+		Summary: "Opens the auction routes of the cell again",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the auction routes are open", Body: rep.AuctionRoutesStatus{}},
+		},
+	},
+	rep.RelaxHealthCheckRoute: {
+		Summary: "Suspends or relaxes the health checks of the containers the cell runs for an LRP instance until the relaxation expires",
+		Request: rep.HealthCheckRelaxationRequest{},
+		Responses: map[int]Response{
+			http.StatusCreated:             {Description: "the health checks are relaxed", Body: rep.HealthCheckRelaxation{}},
+			http.StatusBadRequest:          {Description: "the relaxation request is invalid"},
+			http.StatusInternalServerError: {Description: "the health checks could not be relaxed"},
+		},
+	},
+	rep.RestoreHealthCheckRoute: {
+		Summary: "Drops the health check relaxation of an LRP instance before it expires",
+		Responses: map[int]Response{
+			http.StatusNoContent:  {Description: "the relaxation was dropped"},
+			http.StatusBadRequest: {Description: "the index is not a number"},
+			http.StatusNotFound:   {Description: "the instance has no relaxation or it has expired"},
+		},
+	},
+	rep.HealthCheckRelaxationsRoute: {
+		Summary: "Lists the health check relaxations that have not expired",
+		Responses: map[int]Response{
+			http.StatusOK: {Description: "the health check relaxations", Body: []rep.HealthCheckRelaxation{}},
+		},
+	},
+}
+
+// RepDocument describes every route of the rep.
+func RepDocument() (*Document, error) {
+	return Generate(Title, "1", rep.Routes, Operations)
+}
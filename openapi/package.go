@@ -0,0 +1 @@
+package openapi // import "code.cloudfoundry.org/rep/openapi"
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+)
+
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// extendedEncodings are the types that marshal themselves as their fields
+// plus the properties listed for them.
+var extendedEncodings = map[reflect.Type]map[string]*Schema{
+	reflect.TypeOf(rep.LRP{}):  typedSizeProperties,
+	reflect.TypeOf(rep.Task{}): typedSizeProperties,
+}
+
+var typedSizeProperties = map[string]*Schema{
+	"memory": {Type: "string"},
+	"disk":   {Type: "string"},
+}
+
+// schemaRegistry collects the schemas of named structs, which are referred
+// to by refPrefix followed by their name.
+type schemaRegistry struct {
+	refPrefix string
+	schemas   map[string]*Schema
+	names     map[reflect.Type]string
+}
+
+func newSchemaRegistry(refPrefix string) *schemaRegistry {
+	return &schemaRegistry{
+		refPrefix: refPrefix,
+		schemas:   map[string]*Schema{},
+		names:     map[reflect.Type]string{},
+	}
+}
+
+func (r *schemaRegistry) schemaFor(value interface{}) *Schema {
+	return r.schemaForType(reflect.TypeOf(value))
+}
+
+func (r *schemaRegistry) schemaForType(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	// the encoding of types marshaling themselves cannot be derived
+	_, extended := extendedEncodings[t]
+	if !extended && (t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return r.schemaForType(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaForType(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// structSchema registers named structs as components and refers to them, so
+// that types used in several places, or recursively, are described once.
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		r.addProperties(schema, t)
+		return schema
+	}
+
+	name, ok := r.names[t]
+	if !ok {
+		name = r.componentName(t)
+		r.names[t] = name
+
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		r.schemas[name] = schema
+		r.addProperties(schema, t)
+		for property, propertySchema := range extendedEncodings[t] {
+			schema.Properties[property] = propertySchema
+		}
+	}
+
+	return &Schema{Ref: r.refPrefix + name}
+}
+
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := r.schemas[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	return strings.Title(pkg) + name
+}
+
+func (r *schemaRegistry) addProperties(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// encoding/json promotes the fields of untagged embedded structs
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			r.addProperties(schema, fieldType)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = r.schemaForType(field.Type)
+	}
+}
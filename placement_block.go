@@ -0,0 +1,43 @@
+package rep
+
+import "errors"
+
+var ErrInvalidPlacementBlock = errors.New("a placement block needs either a process guid or a domain, and either a positive ttl or a deadline")
+var ErrPlacementBlocked = errors.New("placement is blocked on the cell")
+
+// PlacementBlockRequest asks a cell to stop accepting the LRP instances of
+// ProcessGuid, or the LRP instances and tasks of Domain, for TTLSeconds, or
+// until Deadline when it is given.
+type PlacementBlockRequest struct {
+	ProcessGuid string    `json:"process_guid,omitempty"`
+	Domain      string    `json:"domain,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	TTLSeconds  int64     `json:"ttl_seconds"`
+	Deadline    *Deadline `json:"deadline,omitempty"`
+}
+
+func (r PlacementBlockRequest) Validate() error {
+	if (r.ProcessGuid == "") == (r.Domain == "") || (r.TTLSeconds < 1 && r.Deadline == nil) {
+		return ErrInvalidPlacementBlock
+	}
+	return nil
+}
+
+// PlacementBlock keeps the work of ProcessGuid or Domain off a cell until
+// ExpiresAt, in unix nanoseconds.
+type PlacementBlock struct {
+	ID          string `json:"id"`
+	ProcessGuid string `json:"process_guid,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+// Blocks reports whether the block keeps work of processGuid in domain off
+// the cell. Tasks have no process guid.
+func (b *PlacementBlock) Blocks(processGuid, domain string) bool {
+	if b.ProcessGuid != "" {
+		return b.ProcessGuid == processGuid
+	}
+	return b.Domain == domain
+}
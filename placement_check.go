@@ -0,0 +1,107 @@
+package rep
+
+// PlacementCheck tells whether a cell would accept an LRP instance or task.
+// Score is the score the auctioneer would give the cell for it, and Reason
+// and Error why the cell would turn it down.
+type PlacementCheck struct {
+	InstanceGUID string  `json:"instance_guid,omitempty"`
+	TaskGuid     string  `json:"task_guid,omitempty"`
+	Placeable    bool    `json:"placeable"`
+	Score        float64 `json:"score,omitempty"`
+	Reason       string  `json:"reason,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// PlacementChecks tells whether a cell would accept each LRP instance and
+// task of a Work, and its Score once the placeable ones are placed.
+type PlacementChecks struct {
+	CellID string           `json:"cell_id"`
+	LRPs   []PlacementCheck `json:"lrps"`
+	Tasks  []PlacementCheck `json:"tasks"`
+	Score  float64          `json:"score"`
+}
+
+// CheckPlacement checks the LRP instances of work and then its tasks against
+// a copy of state, in order, each placeable one taking its resources from the
+// copy for those after it. The scores follow strategy, the one the
+// auctioneer scores every cell by. state itself is left untouched.
+func CheckPlacement(state CellState, work Work, startingContainerWeight float64, strategy ScoringStrategy) PlacementChecks {
+	cell := state.Copy()
+
+	checks := PlacementChecks{CellID: state.CellID, LRPs: []PlacementCheck{}, Tasks: []PlacementCheck{}}
+
+	for i := range work.LRPs {
+		lrp := &work.LRPs[i]
+		check := PlacementCheck{InstanceGUID: lrp.InstanceGUID}
+		if cell.Quarantined(lrp.ProcessGuid, lrp.Index) {
+			check.Reason = PlacementReasonQuarantined
+		} else {
+			check.Reason, check.Error = constraintMismatch(&cell, &lrp.PlacementConstraint)
+		}
+		if check.Reason == "" {
+			if err := cell.LRPResourceMatch(lrp); err != nil {
+				check.Reason, check.Error = resourceMismatch(err), err.Error()
+			}
+		}
+		if check.Reason == "" {
+			check.Placeable = true
+			check.Score = cell.ComputeLRPScore(lrp, startingContainerWeight, strategy)
+			cell.AddLRP(lrp)
+		}
+		checks.LRPs = append(checks.LRPs, check)
+	}
+
+	for i := range work.Tasks {
+		task := &work.Tasks[i]
+		check := PlacementCheck{TaskGuid: task.TaskGuid}
+		check.Reason, check.Error = constraintMismatch(&cell, &task.PlacementConstraint)
+		if check.Reason == "" {
+			if err := cell.TaskResourceMatch(task); err != nil {
+				check.Reason, check.Error = resourceMismatch(err), err.Error()
+			}
+		}
+		if check.Reason == "" {
+			check.Placeable = true
+			check.Score = cell.ComputeTaskScore(task, startingContainerWeight, strategy)
+			cell.AddTask(task)
+		}
+		checks.Tasks = append(checks.Tasks, check)
+	}
+
+	checks.Score = cell.ComputeScore(&Resource{}, startingContainerWeight, strategy)
+	return checks
+}
+
+// constraintMismatch returns why the cell cannot run work with constraint
+// regardless of its free resources, or an empty reason when it can, along
+// with the error of a rootfs that does not parse.
+func constraintMismatch(cell *CellState, constraint *PlacementConstraint) (string, string) {
+	switch {
+	case cell.Evacuating:
+		return PlacementReasonEvacuating, ""
+	case cell.Maintenance:
+		return PlacementReasonInMaintenance, ""
+	case cell.Cordoned:
+		return PlacementReasonCordoned, ""
+	case !cell.MatchRootFS(constraint.RootFs):
+		if _, err := ParseRootFS(constraint.RootFs); err != nil {
+			return PlacementReasonRootFSMismatch, err.Error()
+		}
+		return PlacementReasonRootFSMismatch, ""
+	case !cell.MatchPlacementTags(constraint.PlacementTags):
+		return PlacementReasonPlacementTagMismatch, ""
+	case !cell.MatchVolumeDrivers(constraint.VolumeDrivers):
+		return PlacementReasonVolumeDriverMismatch, ""
+	}
+	return "", ""
+}
+
+func resourceMismatch(err error) string {
+	if err == ErrPlacementBlocked {
+		return PlacementReasonBlocked
+	}
+	if _, ok := err.(HostPortConflictError); ok {
+		return PlacementReasonHostPortConflict
+	}
+	return PlacementReasonInsufficientResources
+}
@@ -0,0 +1,95 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckPlacement", func() {
+	var (
+		state      rep.CellState
+		lrp1, lrp2 rep.LRP
+		lrp3       rep.LRP
+		task       rep.Task
+	)
+
+	BeforeEach(func() {
+		state = rep.CellState{
+			CellID:              "cell-id",
+			RootFSProviders:     rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+			AvailableResources:  rep.NewResources(1024, 2048, 4),
+			TotalResources:      rep.NewResources(1024, 2048, 4),
+			StackContainersLeft: map[string]int{rep.StackOf("docker:///busybox"): 4},
+		}
+
+		constraint := rep.NewPlacementConstraint("docker:///busybox", nil, nil)
+		lrp1 = rep.NewLRP("ig-1", models.NewActualLRPKey("pg", 0, "domain"), rep.NewResource(512, 10, 10), constraint)
+		lrp2 = rep.NewLRP("ig-2", models.NewActualLRPKey("pg", 1, "domain"), rep.NewResource(512, 10, 10), constraint)
+		lrp3 = rep.NewLRP("ig-3", models.NewActualLRPKey("pg", 2, "domain"), rep.NewResource(512, 10, 10), constraint)
+		task = rep.NewTask("tg-1", "domain", rep.NewResource(10, 10, 10), rep.NewPlacementConstraint("docker:///busybox", []string{"gpu"}, nil))
+	})
+
+	It("places the work cumulatively on a copy of the cell", func() {
+		checks := rep.CheckPlacement(state, rep.Work{LRPs: []rep.LRP{lrp1, lrp2, lrp3}, Tasks: []rep.Task{task}}, 0, nil)
+
+		Expect(checks.CellID).To(Equal("cell-id"))
+		Expect(checks.LRPs).To(HaveLen(3))
+		Expect(checks.LRPs[0].Placeable).To(BeTrue())
+		Expect(checks.LRPs[0].Score).To(Equal(state.ComputeLRPScore(&lrp1, 0, nil)))
+		Expect(checks.LRPs[1].Placeable).To(BeTrue())
+		Expect(checks.LRPs[1].Score).To(BeNumerically(">", checks.LRPs[0].Score))
+
+		Expect(checks.LRPs[2].InstanceGUID).To(Equal("ig-3"))
+		Expect(checks.LRPs[2].Placeable).To(BeFalse())
+		Expect(checks.LRPs[2].Reason).To(Equal(rep.PlacementReasonInsufficientResources))
+		Expect(checks.LRPs[2].Error).To(ContainSubstring("memory"))
+
+		Expect(checks.Tasks).To(Equal([]rep.PlacementCheck{{TaskGuid: "tg-1", Reason: rep.PlacementReasonPlacementTagMismatch}}))
+		Expect(checks.Score).To(BeNumerically(">=", checks.LRPs[1].Score))
+
+		Expect(state.AvailableResources).To(Equal(rep.NewResources(1024, 2048, 4)))
+		Expect(state.LRPs).To(BeEmpty())
+		Expect(state.StackContainersLeft).To(Equal(map[string]int{rep.StackOf("docker:///busybox"): 4}))
+	})
+
+	It("turns down everything on an evacuating cell", func() {
+		state.Evacuating = true
+
+		checks := rep.CheckPlacement(state, rep.Work{LRPs: []rep.LRP{lrp1}}, 0, nil)
+		Expect(checks.LRPs).To(Equal([]rep.PlacementCheck{{InstanceGUID: "ig-1", Reason: rep.PlacementReasonEvacuating}}))
+	})
+
+	It("turns down everything on a cordoned cell", func() {
+		state.Cordoned = true
+
+		checks := rep.CheckPlacement(state, rep.Work{LRPs: []rep.LRP{lrp1}}, 0, nil)
+		Expect(checks.LRPs).To(Equal([]rep.PlacementCheck{{InstanceGUID: "ig-1", Reason: rep.PlacementReasonCordoned}}))
+	})
+
+	It("turns down quarantined instances", func() {
+		state.QuarantinedLRPs = []rep.QuarantinedLRP{{ProcessGUID: "pg", Index: 0}}
+
+		checks := rep.CheckPlacement(state, rep.Work{LRPs: []rep.LRP{lrp1}}, 0, nil)
+		Expect(checks.LRPs[0].Reason).To(Equal(rep.PlacementReasonQuarantined))
+	})
+
+	It("turns down work whose rootfs or volume drivers the cell does not provide", func() {
+		lrp1.RootFs = "preloaded:cflinuxfs4"
+		lrp2.VolumeDrivers = []string{"nfs"}
+
+		checks := rep.CheckPlacement(state, rep.Work{LRPs: []rep.LRP{lrp1, lrp2}}, 0, nil)
+		Expect(checks.LRPs[0].Reason).To(Equal(rep.PlacementReasonRootFSMismatch))
+		Expect(checks.LRPs[1].Reason).To(Equal(rep.PlacementReasonVolumeDriverMismatch))
+	})
+
+	It("surfaces why a rootfs does not parse", func() {
+		lrp1.RootFs = "cflinux%fs4"
+
+		checks := rep.CheckPlacement(state, rep.Work{LRPs: []rep.LRP{lrp1}}, 0, nil)
+		Expect(checks.LRPs[0].Reason).To(Equal(rep.PlacementReasonRootFSMismatch))
+		Expect(checks.LRPs[0].Error).To(ContainSubstring("missing scheme"))
+	})
+})
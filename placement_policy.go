@@ -0,0 +1,28 @@
+package rep
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolicyDeniedError is returned for work the placement policy of the cell
+// does not admit, with the reasons the policy gave.
+type PolicyDeniedError struct {
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+func (e PolicyDeniedError) Error() string {
+	if len(e.Reasons) == 0 {
+		return "denied by the placement policy of the cell"
+	}
+	return fmt.Sprintf("denied by the placement policy of the cell: %s", strings.Join(e.Reasons, "; "))
+}
+
+// PolicyFailure records the LRP instance or task of a Work that was rejected
+// because the placement policy of the cell does not admit it, or could not be
+// evaluated for it.
+type PolicyFailure struct {
+	InstanceGUID string `json:"instance_guid,omitempty"`
+	TaskGuid     string `json:"task_guid,omitempty"`
+	Error        string `json:"error"`
+}
@@ -0,0 +1,77 @@
+package rep
+
+import "errors"
+
+var ErrInvalidPlacementTags = errors.New("placement tags and the isolation segment must not be blank")
+
+// ErrPlacementTagsMismatch is returned for work whose placement tags do not
+// match those of the cell, so that work directed at a cell, which does not go
+// through the auction, cannot land on a cell dedicated to other work.
+var ErrPlacementTagsMismatch = errors.New("placement tags do not match the cell")
+
+// CellPlacementTags are the placement tags a cell advertises. A cell in an
+// isolation segment only accepts the work of the segment, so its name is one
+// more required placement tag.
+type CellPlacementTags struct {
+	PlacementTags         []string `json:"placement_tags"`
+	OptionalPlacementTags []string `json:"optional_placement_tags"`
+	IsolationSegment      string   `json:"isolation_segment,omitempty"`
+}
+
+func (t CellPlacementTags) Validate() error {
+	for _, tag := range append(t.PlacementTags, t.OptionalPlacementTags...) {
+		if tag == "" {
+			return ErrInvalidPlacementTags
+		}
+	}
+	return nil
+}
+
+// Required returns the required placement tags, including the isolation
+// segment.
+func (t CellPlacementTags) Required() []string {
+	required := append([]string{}, t.PlacementTags...)
+	if t.IsolationSegment == "" {
+		return required
+	}
+	for _, tag := range required {
+		if tag == t.IsolationSegment {
+			return required
+		}
+	}
+	return append(required, t.IsolationSegment)
+}
+
+// PlacementTagsMismatch names an LRP instance or task resident on a cell whose
+// placement tags no longer match those of the cell. It keeps running, but the
+// cell would not accept it again.
+type PlacementTagsMismatch struct {
+	InstanceGUID  string   `json:"instance_guid,omitempty"`
+	TaskGuid      string   `json:"task_guid,omitempty"`
+	PlacementTags []string `json:"placement_tags"`
+}
+
+// PlacementTagsUpdate holds the placement tags a cell changed to and, when it
+// was asked to validate them, the LRP instances and tasks they no longer
+// match.
+type PlacementTagsUpdate struct {
+	CellPlacementTags
+	Mismatches []PlacementTagsMismatch `json:"mismatches,omitempty"`
+}
+
+// MismatchedPlacementTags returns the LRP instances and tasks of state whose
+// placement tags do not match those of state.
+func MismatchedPlacementTags(state CellState) []PlacementTagsMismatch {
+	mismatches := []PlacementTagsMismatch{}
+	for i := range state.LRPs {
+		if !state.MatchPlacementTags(state.LRPs[i].PlacementTags) {
+			mismatches = append(mismatches, PlacementTagsMismatch{InstanceGUID: state.LRPs[i].InstanceGUID, PlacementTags: state.LRPs[i].PlacementTags})
+		}
+	}
+	for i := range state.Tasks {
+		if !state.MatchPlacementTags(state.Tasks[i].PlacementTags) {
+			mismatches = append(mismatches, PlacementTagsMismatch{TaskGuid: state.Tasks[i].TaskGuid, PlacementTags: state.Tasks[i].PlacementTags})
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,43 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CellPlacementTags", func() {
+	It("requires the isolation segment in addition to the placement tags", func() {
+		tags := rep.CellPlacementTags{PlacementTags: []string{"gpu"}, IsolationSegment: "payments"}
+		Expect(tags.Required()).To(Equal([]string{"gpu", "payments"}))
+
+		tags.PlacementTags = []string{"payments"}
+		Expect(tags.Required()).To(Equal([]string{"payments"}))
+	})
+
+	It("rejects blank tags", func() {
+		Expect(rep.CellPlacementTags{PlacementTags: []string{"gpu"}}.Validate()).To(Succeed())
+		Expect(rep.CellPlacementTags{OptionalPlacementTags: []string{""}}.Validate()).To(MatchError(rep.ErrInvalidPlacementTags))
+	})
+
+	Describe("MismatchedPlacementTags", func() {
+		It("returns the LRP instances and tasks whose tags no longer match the cell", func() {
+			state := rep.CellState{
+				PlacementTags: []string{"payments"},
+				LRPs: []rep.LRP{
+					{InstanceGUID: "ig-1", PlacementConstraint: rep.PlacementConstraint{PlacementTags: []string{"payments"}}},
+					{InstanceGUID: "ig-2", PlacementConstraint: rep.PlacementConstraint{PlacementTags: []string{}}},
+				},
+				Tasks: []rep.Task{
+					{TaskGuid: "tg-1", PlacementConstraint: rep.PlacementConstraint{PlacementTags: []string{"search"}}},
+				},
+			}
+
+			Expect(rep.MismatchedPlacementTags(state)).To(Equal([]rep.PlacementTagsMismatch{
+				{InstanceGUID: "ig-2", PlacementTags: []string{}},
+				{TaskGuid: "tg-1", PlacementTags: []string{"search"}},
+			}))
+		})
+	})
+})
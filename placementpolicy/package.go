@@ -0,0 +1 @@
+package placementpolicy // import "code.cloudfoundry.org/rep/placementpolicy"
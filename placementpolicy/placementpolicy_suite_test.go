@@ -0,0 +1,13 @@
+package placementpolicy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPlacementPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Placement Policy Suite")
+}
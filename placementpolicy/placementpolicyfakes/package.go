@@ -0,0 +1 @@
+package placementpolicyfakes // import "code.cloudfoundry.org/rep/placementpolicy/placementpolicyfakes"
@@ -0,0 +1,261 @@
+package placementpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+//go:generate counterfeiter -o placementpolicyfakes/fake_policy.go . Policy
+
+// Policy decides whether the cell admits an LRP instance or task, on top of
+// the checks the cell makes itself.
+type Policy interface {
+	// Admit returns a rep.PolicyDeniedError for work the policy does not
+	// admit, and any other error when the policy cannot be evaluated.
+	Admit(ctx context.Context, logger lager.Logger, input Input) error
+}
+
+// Input is the document a policy is evaluated against: the LRP instance or
+// the task being placed and the state of the cell it is placed on, including
+// the work already admitted from the same perform.
+type Input struct {
+	LRP  *rep.LRP      `json:"lrp,omitempty"`
+	Task *rep.Task     `json:"task,omitempty"`
+	Cell rep.CellState `json:"cell"`
+}
+
+// Rules is a Policy made of deny rules read from a JSON file. A rule denies
+// the work when all of its conditions hold, with its reason. The fields of
+// the conditions and the reasons are dotted paths into the JSON encoding of
+// the Input, such as "lrp.MemoryMB" or "cell.Zone".
+type Rules struct {
+	Deny []Rule `json:"deny"`
+}
+
+// Rule denies the work with Reason when every condition of When holds. The
+// paths in braces in Reason, such as "{lrp.process_guid}", are replaced with
+// their values.
+type Rule struct {
+	Reason string      `json:"reason"`
+	When   []Condition `json:"when"`
+}
+
+// Condition compares the value at Field with Value, or with the value at
+// Other scaled by Scale when it is set. A condition on a field the input
+// does not have never holds.
+type Condition struct {
+	Field string          `json:"field"`
+	Op    string          `json:"op"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Other string          `json:"other,omitempty"`
+	Scale float64         `json:"scale,omitempty"`
+}
+
+const (
+	OpEqual          = "=="
+	OpNotEqual       = "!="
+	OpLess           = "<"
+	OpLessOrEqual    = "<="
+	OpGreater        = ">"
+	OpGreaterOrEqual = ">="
+	OpContains       = "contains"
+)
+
+var ErrInvalidRule = errors.New("invalid placement policy rule")
+
+// LoadRules reads the rules at path and validates them.
+func LoadRules(path string) (*Rules, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &Rules{}
+	err = json.Unmarshal(contents, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rules.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate returns an error wrapping ErrInvalidRule for the first rule that
+// has no reason or conditions, or a condition that cannot be evaluated.
+func (r *Rules) Validate() error {
+	for i := range r.Deny {
+		rule := &r.Deny[i]
+		if rule.Reason == "" || len(rule.When) == 0 {
+			return fmt.Errorf("%w %d: a rule needs a reason and conditions", ErrInvalidRule, i)
+		}
+		for j := range rule.When {
+			if err := rule.When[j].validate(); err != nil {
+				return fmt.Errorf("%w %d, condition %d: %s", ErrInvalidRule, i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Condition) validate() error {
+	if c.Field == "" {
+		return errors.New("a condition needs a field")
+	}
+	if (len(c.Value) == 0) == (c.Other == "") {
+		return errors.New("a condition compares with either a value or another field")
+	}
+	if len(c.Value) > 0 {
+		var value interface{}
+		if err := json.Unmarshal(c.Value, &value); err != nil {
+			return err
+		}
+	}
+
+	switch c.Op {
+	case OpEqual, OpNotEqual, OpContains:
+		if c.Scale != 0 {
+			return fmt.Errorf("%s does not scale", c.Op)
+		}
+	case OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual:
+	default:
+		return fmt.Errorf("unknown op %q", c.Op)
+	}
+	return nil
+}
+
+// Admit denies the work with the reasons of the rules that deny it.
+func (r *Rules) Admit(ctx context.Context, logger lager.Logger, input Input) error {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	var document interface{}
+	err = json.Unmarshal(encoded, &document)
+	if err != nil {
+		return err
+	}
+
+	reasons := []string{}
+	for i := range r.Deny {
+		if r.Deny[i].denies(document) {
+			reasons = append(reasons, r.Deny[i].reason(document))
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	sort.Strings(reasons)
+	return rep.PolicyDeniedError{Reasons: reasons}
+}
+
+func (r *Rule) denies(document interface{}) bool {
+	for i := range r.When {
+		if !r.When[i].holds(document) {
+			return false
+		}
+	}
+	return true
+}
+
+var reasonPath = regexp.MustCompile(`\{([^{}]+)\}`)
+
+func (r *Rule) reason(document interface{}) string {
+	return reasonPath.ReplaceAllStringFunc(r.Reason, func(match string) string {
+		value, ok := lookup(document, match[1:len(match)-1])
+		if !ok {
+			return match
+		}
+		return fmt.Sprint(value)
+	})
+}
+
+func (c *Condition) holds(document interface{}) bool {
+	field, ok := lookup(document, c.Field)
+	if !ok {
+		return false
+	}
+
+	var other interface{}
+	if c.Other != "" {
+		other, ok = lookup(document, c.Other)
+		if !ok {
+			return false
+		}
+		if c.Scale != 0 {
+			number, ok := other.(float64)
+			if !ok {
+				return false
+			}
+			other = number * c.Scale
+		}
+	} else {
+		json.Unmarshal(c.Value, &other)
+	}
+
+	switch c.Op {
+	case OpEqual:
+		return reflect.DeepEqual(field, other)
+	case OpNotEqual:
+		return !reflect.DeepEqual(field, other)
+	case OpContains:
+		elements, ok := field.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, element := range elements {
+			if reflect.DeepEqual(element, other) {
+				return true
+			}
+		}
+		return false
+	}
+
+	left, ok := field.(float64)
+	if !ok {
+		return false
+	}
+	right, ok := other.(float64)
+	if !ok {
+		return false
+	}
+	switch c.Op {
+	case OpLess:
+		return left < right
+	case OpLessOrEqual:
+		return left <= right
+	case OpGreater:
+		return left > right
+	default:
+		return left >= right
+	}
+}
+
+// lookup returns the value at the dotted path in document.
+func lookup(document interface{}, path string) (interface{}, bool) {
+	value := document
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = object[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
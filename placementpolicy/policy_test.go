@@ -0,0 +1,143 @@
+package placementpolicy_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/placementpolicy"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rules", func() {
+	var (
+		logger     *lagertest.TestLogger
+		policyDir  string
+		policyPath string
+		policy     *placementpolicy.Rules
+		input      placementpolicy.Input
+	)
+
+	writePolicy := func(source string) {
+		err := ioutil.WriteFile(policyPath, []byte(source), 0600)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+
+		var err error
+		policyDir, err = ioutil.TempDir("", "placement-policy")
+		Expect(err).NotTo(HaveOccurred())
+		policyPath = filepath.Join(policyDir, "placement.json")
+
+		writePolicy(`{
+			"deny": [
+				{
+					"reason": "{lrp.process_guid} asks for more than half of the free memory",
+					"when": [{"field": "lrp.MemoryMB", "op": ">", "other": "cell.AvailableResources.MemoryMB", "scale": 0.5}]
+				},
+				{
+					"reason": "gpu tasks run in z-gpu",
+					"when": [
+						{"field": "task.PlacementTags", "op": "contains", "value": "gpu"},
+						{"field": "cell.Zone", "op": "!=", "value": "z-gpu"}
+					]
+				}
+			]
+		}`)
+
+		lrp := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.NewResource(512, 1024, 10), rep.PlacementConstraint{RootFs: "preloaded:cflinuxfs4"})
+		input = placementpolicy.Input{
+			LRP: &lrp,
+			Cell: rep.CellState{
+				Zone:               "z1",
+				AvailableResources: rep.Resources{MemoryMB: 4096},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		policy, err = placementpolicy.LoadRules(policyPath)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(policyDir)
+	})
+
+	It("admits work the policy does not deny", func() {
+		Expect(policy.Admit(context.Background(), logger, input)).To(Succeed())
+	})
+
+	It("denies an LRP with the reasons of the policy", func() {
+		input.Cell.AvailableResources.MemoryMB = 512
+
+		err := policy.Admit(context.Background(), logger, input)
+		Expect(err).To(Equal(rep.PolicyDeniedError{Reasons: []string{"pg-1 asks for more than half of the free memory"}}))
+	})
+
+	It("denies a task with the reasons of the policy", func() {
+		task := rep.NewTask("tg-1", "domain", rep.NewResource(256, 256, 10), rep.PlacementConstraint{PlacementTags: []string{"gpu"}})
+		input.LRP = nil
+		input.Task = &task
+
+		err := policy.Admit(context.Background(), logger, input)
+		Expect(err).To(Equal(rep.PolicyDeniedError{Reasons: []string{"gpu tasks run in z-gpu"}}))
+	})
+
+	It("does not deny work on fields it does not have", func() {
+		task := rep.NewTask("tg-1", "domain", rep.NewResource(256, 256, 10), rep.PlacementConstraint{})
+		input.LRP = nil
+		input.Task = &task
+		input.Cell.AvailableResources.MemoryMB = 1
+
+		Expect(policy.Admit(context.Background(), logger, input)).To(Succeed())
+	})
+
+	Context("when several rules deny the work", func() {
+		BeforeEach(func() {
+			writePolicy(`{
+				"deny": [
+					{"reason": "not in {cell.Zone}", "when": [{"field": "cell.Zone", "op": "==", "value": "z1"}]},
+					{"reason": "instance {lrp.index} is too small", "when": [{"field": "lrp.MemoryMB", "op": "<=", "value": 512}]}
+				]
+			}`)
+		})
+
+		It("denies it with every reason, in order", func() {
+			err := policy.Admit(context.Background(), logger, input)
+			Expect(err).To(Equal(rep.PolicyDeniedError{Reasons: []string{"instance 0 is too small", "not in z1"}}))
+		})
+	})
+
+	It("fails to load rules that cannot be parsed", func() {
+		writePolicy(`{"deny": [`)
+
+		_, err := placementpolicy.LoadRules(policyPath)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails to load invalid rules", func() {
+		for _, rule := range []string{
+			`{"when": [{"field": "cell.Zone", "op": "==", "value": "z1"}]}`,
+			`{"reason": "no"}`,
+			`{"reason": "no", "when": [{"field": "cell.Zone", "op": "~", "value": "z1"}]}`,
+			`{"reason": "no", "when": [{"field": "cell.Zone", "op": "==", "value": "z1", "other": "cell.cell_id"}]}`,
+			`{"reason": "no", "when": [{"field": "cell.Zone", "op": "=="}]}`,
+			`{"reason": "no", "when": [{"field": "cell.Zone", "op": "==", "other": "cell.cell_id", "scale": 2}]}`,
+		} {
+			writePolicy(`{"deny": [` + rule + `]}`)
+
+			_, err := placementpolicy.LoadRules(policyPath)
+			Expect(err).To(MatchError(ContainSubstring(placementpolicy.ErrInvalidRule.Error())), rule)
+		}
+	})
+})
@@ -0,0 +1 @@
+package fake_presence // import "code.cloudfoundry.org/rep/presence/fake_presence"
@@ -0,0 +1,143 @@
+package presence
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	uuid "github.com/nu7hatch/gouuid"
+)
+
+var (
+	ErrOwnerFileNotConfigured = errors.New("presence owner file is not configured")
+	ErrHandoffTimedOut        = errors.New("timed out waiting for a planned restart to hand over the presence")
+)
+
+//go:generate counterfeiter -o fake_presence/fake_planned_restarter.go . PlannedRestarter
+type PlannedRestarter interface {
+	MarkPlannedRestart() error
+}
+
+//go:generate counterfeiter -o fake_presence/fake_planned_restart_reporter.go . PlannedRestartReporter
+type PlannedRestartReporter interface {
+	PlannedRestart() bool
+}
+
+// Handoff lets a rep that is restarted on purpose hand its presence lock over
+// to the process that replaces it. Locket only lets the owner of a lock
+// refresh it, so the owner is persisted to ownerFile when a restart is
+// planned and picked up again by the next process.
+type Handoff struct {
+	ownerFile string
+	owner     string
+
+	mu      sync.RWMutex
+	planned bool
+}
+
+// NewHandoff reuses the owner left behind by a planned restart, if there is
+// one, and generates a new owner otherwise. The owner file is consumed so
+// that an unplanned restart later on does not reuse a stale owner.
+func NewHandoff(ownerFile string) (*Handoff, error) {
+	owner, err := consumeOwner(ownerFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == "" {
+		guid, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		owner = guid.String()
+	}
+
+	return &Handoff{ownerFile: ownerFile, owner: owner}, nil
+}
+
+func consumeOwner(ownerFile string) (string, error) {
+	if ownerFile == "" {
+		return "", nil
+	}
+
+	contents, err := ioutil.ReadFile(ownerFile)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Remove(ownerFile)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
+func (h *Handoff) Owner() string {
+	return h.owner
+}
+
+// MarkPlannedRestart persists the owner for the next process and makes the
+// presence runner leave the lock in place when it is stopped.
+func (h *Handoff) MarkPlannedRestart() error {
+	if h.ownerFile == "" {
+		return ErrOwnerFileNotConfigured
+	}
+
+	err := ioutil.WriteFile(h.ownerFile+".tmp", []byte(h.owner), 0600)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(h.ownerFile+".tmp", h.ownerFile)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.planned = true
+	return nil
+}
+
+func (h *Handoff) PlannedRestart() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.planned
+}
+
+// WaitForHandoff blocks a rep started as a warm standby until the rep it
+// replaces on the host marks a planned restart and leaves its owner in
+// ownerFile, checking every interval. The standby then takes the presence
+// lock over instead of competing for it. It gives up after timeout, unless
+// timeout is zero.
+func WaitForHandoff(ownerFile string, clock clock.Clock, interval, timeout time.Duration) error {
+	if ownerFile == "" {
+		return ErrOwnerFileNotConfigured
+	}
+
+	deadline := clock.Now().Add(timeout)
+	for {
+		_, err := os.Stat(ownerFile)
+		if err == nil {
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		if timeout > 0 && !clock.Now().Before(deadline) {
+			return ErrHandoffTimedOut
+		}
+		clock.Sleep(interval)
+	}
+}
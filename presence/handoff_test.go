@@ -0,0 +1,138 @@
+package presence_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/rep/presence"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handoff", func() {
+	var (
+		tmpDir    string
+		ownerFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "presence")
+		Expect(err).NotTo(HaveOccurred())
+		ownerFile = filepath.Join(tmpDir, "owner")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Context("when there is no owner left behind", func() {
+		It("generates a new owner", func() {
+			first, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first.Owner()).NotTo(BeEmpty())
+			Expect(first.Owner()).NotTo(Equal(second.Owner()))
+		})
+
+		It("does not report a planned restart", func() {
+			handoff, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handoff.PlannedRestart()).To(BeFalse())
+		})
+	})
+
+	Context("when a planned restart has been marked", func() {
+		var handoff *presence.Handoff
+
+		BeforeEach(func() {
+			var err error
+			handoff, err = presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handoff.MarkPlannedRestart()).To(Succeed())
+		})
+
+		It("reports the planned restart", func() {
+			Expect(handoff.PlannedRestart()).To(BeTrue())
+		})
+
+		It("hands the owner over to the next process", func() {
+			next, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next.Owner()).To(Equal(handoff.Owner()))
+			Expect(next.PlannedRestart()).To(BeFalse())
+		})
+
+		It("only hands the owner over once", func() {
+			_, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ownerFile).NotTo(BeAnExistingFile())
+
+			afterNext, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(afterNext.Owner()).NotTo(Equal(handoff.Owner()))
+		})
+	})
+
+	Context("when the owner file is not configured", func() {
+		It("fails to mark a planned restart", func() {
+			handoff, err := presence.NewHandoff("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handoff.MarkPlannedRestart()).To(MatchError(presence.ErrOwnerFileNotConfigured))
+			Expect(handoff.PlannedRestart()).To(BeFalse())
+		})
+	})
+
+	Describe("WaitForHandoff", func() {
+		var (
+			fakeClock *fakeclock.FakeClock
+			errCh     chan error
+		)
+
+		BeforeEach(func() {
+			fakeClock = fakeclock.NewFakeClock(time.Now())
+			errCh = make(chan error, 1)
+		})
+
+		wait := func(timeout time.Duration) {
+			go func() {
+				errCh <- presence.WaitForHandoff(ownerFile, fakeClock, time.Second, timeout)
+			}()
+		}
+
+		It("returns once a planned restart leaves the owner behind", func() {
+			wait(0)
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Consistently(errCh).ShouldNot(Receive())
+
+			handoff, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handoff.MarkPlannedRestart()).To(Succeed())
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(errCh).Should(Receive(BeNil()))
+
+			next, err := presence.NewHandoff(ownerFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next.Owner()).To(Equal(handoff.Owner()))
+		})
+
+		It("gives up after the timeout", func() {
+			wait(3 * time.Second)
+			for i := 0; i < 3; i++ {
+				fakeClock.WaitForWatcherAndIncrement(time.Second)
+			}
+			Eventually(errCh).Should(Receive(MatchError(presence.ErrHandoffTimedOut)))
+		})
+
+		It("requires the owner file", func() {
+			Expect(presence.WaitForHandoff("", fakeClock, time.Second, 0)).To(MatchError(presence.ErrOwnerFileNotConfigured))
+		})
+	})
+})
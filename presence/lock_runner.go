@@ -0,0 +1,133 @@
+package presence
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/lager"
+	locketmodels "code.cloudfoundry.org/locket/models"
+	"github.com/tedsuo/ifrit"
+)
+
+const (
+	presenceLockRenewalDuration = "PresenceLockRenewalDuration"
+	presenceLockRenewalInterval = "PresenceLockRenewalInterval"
+)
+
+type lockRunner struct {
+	logger       lager.Logger
+	locker       locketmodels.LocketClient
+	resource     *locketmodels.Resource
+	ttlInSeconds int64
+	clock        clock.Clock
+	policy       RenewalPolicy
+	metronClient loggingclient.IngressClient
+	status       *Status
+}
+
+// NewLockRunner maintains the cell's presence in locket. It behaves like the
+// locket presence runner, but renews the lock following policy and emits the
+// latency of every renewal. It records in status whether the cell lost its
+// presence.
+func NewLockRunner(
+	logger lager.Logger,
+	locker locketmodels.LocketClient,
+	resource *locketmodels.Resource,
+	ttlInSeconds int64,
+	clock clock.Clock,
+	policy RenewalPolicy,
+	metronClient loggingclient.IngressClient,
+	status *Status,
+) ifrit.Runner {
+	return &lockRunner{
+		logger:       logger,
+		locker:       locker,
+		resource:     resource,
+		ttlInSeconds: ttlInSeconds,
+		clock:        clock,
+		policy:       policy,
+		metronClient: metronClient,
+		status:       status,
+	}
+}
+
+func (r *lockRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	logger := r.logger.Session("presence-lock", lager.Data{"key": r.resource.Key, "owner": r.resource.Owner})
+	logger.Info("starting")
+	defer logger.Info("finished")
+
+	interval := r.policy.Interval
+	acquired := false
+
+	renew := func() {
+		latency, err := r.lock(logger)
+		if err != nil {
+			if acquired {
+				logger.Error("lost-lock", err)
+				acquired = false
+				r.status.setLost(true)
+			} else {
+				logger.Debug("failed-to-acquire-lock", lager.Data{"error": err.Error()})
+			}
+		} else if !acquired {
+			logger.Info("acquired-lock")
+			acquired = true
+			r.status.setLost(false)
+			if ready != nil {
+				close(ready)
+				ready = nil
+			}
+		}
+
+		next := r.policy.Next(interval, latency)
+		if next != interval {
+			logger.Info("adjusted-renewal-interval", lager.Data{"latency": latency.String(), "interval": next.String()})
+			interval = next
+		}
+		r.emit(logger, presenceLockRenewalInterval, interval)
+	}
+
+	renew()
+	timer := r.clock.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case sig := <-signals:
+			logger.Info("signalled", lager.Data{"signal": sig.String()})
+			_, err := r.locker.Release(context.Background(), &locketmodels.ReleaseRequest{Resource: r.resource})
+			if err != nil {
+				logger.Error("failed-to-release-lock", err)
+			}
+			return nil
+		case <-timer.C():
+			renew()
+			timer.Reset(interval)
+		}
+	}
+}
+
+func (r *lockRunner) lock(logger lager.Logger) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.ttlInSeconds)*time.Second)
+	defer cancel()
+
+	start := r.clock.Now()
+	_, err := r.locker.Lock(ctx, &locketmodels.LockRequest{
+		Resource:     r.resource,
+		TtlInSeconds: r.ttlInSeconds,
+	})
+	latency := r.clock.Since(start)
+
+	r.emit(logger, presenceLockRenewalDuration, latency)
+	return latency, err
+}
+
+func (r *lockRunner) emit(logger lager.Logger, name string, value time.Duration) {
+	err := r.metronClient.SendDuration(name, value)
+	if err != nil {
+		logger.Error("failed-to-send-metric", err, lager.Data{"metric": name})
+	}
+}
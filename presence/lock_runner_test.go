@@ -0,0 +1,139 @@
+package presence_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	locketmodels "code.cloudfoundry.org/locket/models"
+	"code.cloudfoundry.org/locket/models/modelsfakes"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/presence"
+	"github.com/tedsuo/ifrit"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LockRunner", func() {
+	var (
+		fakeLocker       *modelsfakes.FakeLocketClient
+		fakeClock        *fakeclock.FakeClock
+		fakeMetronClient *mfakes.FakeIngressClient
+		resource         *locketmodels.Resource
+		lockLatency      time.Duration
+		lockErr          error
+		status           *presence.Status
+		process          ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeLocker = new(modelsfakes.FakeLocketClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		fakeMetronClient = new(mfakes.FakeIngressClient)
+		resource = &locketmodels.Resource{Key: "cell-id", Owner: "owner"}
+		lockLatency = 100 * time.Millisecond
+		lockErr = nil
+		status = presence.NewStatus()
+
+		fakeLocker.LockStub = func(context.Context, *locketmodels.LockRequest, ...grpc.CallOption) (*locketmodels.LockResponse, error) {
+			fakeClock.Increment(lockLatency)
+			return &locketmodels.LockResponse{}, lockErr
+		}
+	})
+
+	JustBeforeEach(func() {
+		policy := presence.NewRenewalPolicy(8*time.Second, 0, 0)
+		runner := presence.NewLockRunner(lagertest.NewTestLogger("test"), fakeLocker, resource, 15, fakeClock, policy, fakeMetronClient, status)
+		process = ifrit.Background(runner)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("acquires the lock with the configured ttl before becoming ready", func() {
+		Eventually(process.Ready()).Should(BeClosed())
+
+		_, request, _ := fakeLocker.LockArgsForCall(0)
+		Expect(request.Resource).To(Equal(resource))
+		Expect(request.TtlInSeconds).To(BeEquivalentTo(15))
+	})
+
+	It("emits the latency of the renewal", func() {
+		Eventually(process.Ready()).Should(BeClosed())
+
+		name, value, _ := fakeMetronClient.SendDurationArgsForCall(0)
+		Expect(name).To(Equal("PresenceLockRenewalDuration"))
+		Expect(value).To(Equal(lockLatency))
+	})
+
+	It("renews the lock every interval", func() {
+		Eventually(process.Ready()).Should(BeClosed())
+		fakeClock.WaitForWatcherAndIncrement(8 * time.Second)
+		Eventually(fakeLocker.LockCallCount).Should(Equal(2))
+	})
+
+	It("reports the presence as lost until it is renewed again", func() {
+		Eventually(process.Ready()).Should(BeClosed())
+		Expect(status.Condition()).To(BeEmpty())
+
+		lockErr = errors.New("unavailable")
+		fakeClock.WaitForWatcherAndIncrement(8 * time.Second)
+		Eventually(status.Condition).Should(Equal(rep.CellConditionPresenceLost))
+
+		lockErr = nil
+		fakeClock.WaitForWatcherAndIncrement(8 * time.Second)
+		Eventually(status.Condition).Should(BeEmpty())
+	})
+
+	Context("when renewals are slow", func() {
+		BeforeEach(func() {
+			lockLatency = 5 * time.Second
+		})
+
+		It("renews the lock more often", func() {
+			Eventually(process.Ready()).Should(BeClosed())
+			fakeClock.WaitForWatcherAndIncrement(4 * time.Second)
+			Eventually(fakeLocker.LockCallCount).Should(Equal(2))
+		})
+
+		It("emits the adjusted interval", func() {
+			Eventually(process.Ready()).Should(BeClosed())
+
+			name, value, _ := fakeMetronClient.SendDurationArgsForCall(1)
+			Expect(name).To(Equal("PresenceLockRenewalInterval"))
+			Expect(value).To(Equal(4 * time.Second))
+		})
+	})
+
+	Context("when the lock cannot be acquired", func() {
+		BeforeEach(func() {
+			lockErr = errors.New("lock collision")
+		})
+
+		It("keeps retrying without becoming ready", func() {
+			Consistently(process.Ready()).ShouldNot(BeClosed())
+			fakeClock.WaitForWatcherAndIncrement(8 * time.Second)
+			Eventually(fakeLocker.LockCallCount).Should(Equal(2))
+		})
+	})
+
+	Context("when signalled", func() {
+		It("releases the lock", func() {
+			Eventually(process.Ready()).Should(BeClosed())
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+
+			Expect(fakeLocker.ReleaseCallCount()).To(Equal(1))
+			_, request, _ := fakeLocker.ReleaseArgsForCall(0)
+			Expect(request.Resource).To(Equal(resource))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package presence
+
+import "time"
+
+// RenewalPolicy decides how long to wait before the next renewal of the
+// presence lock. Renewals that take longer than SlowThreshold halve the
+// interval, down to MinInterval, so that a lock is less likely to expire
+// while locket is under load. Renewals that are fast again double it back up
+// to Interval.
+type RenewalPolicy struct {
+	Interval      time.Duration
+	MinInterval   time.Duration
+	SlowThreshold time.Duration
+}
+
+// NewRenewalPolicy returns a policy for the given interval. A zero
+// minInterval defaults to a quarter of the interval and a zero slowThreshold
+// to half of it.
+func NewRenewalPolicy(interval, minInterval, slowThreshold time.Duration) RenewalPolicy {
+	if minInterval == 0 || minInterval > interval {
+		minInterval = interval / 4
+	}
+	if slowThreshold == 0 {
+		slowThreshold = interval / 2
+	}
+
+	return RenewalPolicy{
+		Interval:      interval,
+		MinInterval:   minInterval,
+		SlowThreshold: slowThreshold,
+	}
+}
+
+// Next returns the interval to wait after a renewal that took latency, given
+// the interval that was used before it.
+func (p RenewalPolicy) Next(current, latency time.Duration) time.Duration {
+	if latency > p.SlowThreshold {
+		next := current / 2
+		if next < p.MinInterval {
+			return p.MinInterval
+		}
+		return next
+	}
+
+	next := current * 2
+	if next > p.Interval {
+		return p.Interval
+	}
+	return next
+}
@@ -0,0 +1,40 @@
+package presence_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/rep/presence"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RenewalPolicy", func() {
+	var policy presence.RenewalPolicy
+
+	BeforeEach(func() {
+		policy = presence.NewRenewalPolicy(8*time.Second, 0, 0)
+	})
+
+	It("defaults the minimum interval and the slow threshold", func() {
+		Expect(policy.MinInterval).To(Equal(2 * time.Second))
+		Expect(policy.SlowThreshold).To(Equal(4 * time.Second))
+	})
+
+	It("keeps the configured interval while renewals are fast", func() {
+		Expect(policy.Next(8*time.Second, time.Second)).To(Equal(8 * time.Second))
+	})
+
+	It("halves the interval after a slow renewal", func() {
+		Expect(policy.Next(8*time.Second, 5*time.Second)).To(Equal(4 * time.Second))
+	})
+
+	It("does not go below the minimum interval", func() {
+		Expect(policy.Next(3*time.Second, 5*time.Second)).To(Equal(2 * time.Second))
+	})
+
+	It("recovers towards the configured interval once renewals are fast again", func() {
+		Expect(policy.Next(2*time.Second, time.Second)).To(Equal(4 * time.Second))
+		Expect(policy.Next(4*time.Second, time.Second)).To(Equal(8 * time.Second))
+	})
+})
@@ -0,0 +1,52 @@
+package presence
+
+import (
+	"os"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+type runner struct {
+	logger         lager.Logger
+	presenceRunner ifrit.Runner
+	reporter       PlannedRestartReporter
+}
+
+// NewRunner wraps the locket presence runner. When the rep is stopped after a
+// planned restart has been marked, the presence runner is not signalled so
+// the lock is not released and the next process can take it over without
+// the cell disappearing. The lock expires on its own after its TTL if no
+// process takes it over.
+func NewRunner(logger lager.Logger, presenceRunner ifrit.Runner, reporter PlannedRestartReporter) ifrit.Runner {
+	return &runner{
+		logger:         logger.Session("presence"),
+		presenceRunner: presenceRunner,
+		reporter:       reporter,
+	}
+}
+
+func (r *runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	process := ifrit.Background(r.presenceRunner)
+
+	select {
+	case <-process.Ready():
+	case err := <-process.Wait():
+		return err
+	}
+
+	close(ready)
+
+	select {
+	case signal := <-signals:
+		if r.reporter.PlannedRestart() {
+			r.logger.Info("keeping-presence-for-planned-restart")
+			return nil
+		}
+
+		process.Signal(signal)
+		return <-process.Wait()
+	case err := <-process.Wait():
+		return err
+	}
+}
@@ -0,0 +1,56 @@
+package presence_test
+
+import (
+	"os"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/presence"
+	"code.cloudfoundry.org/rep/presence/fake_presence"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		reporter        *fake_presence.FakePlannedRestartReporter
+		presenceSignals chan os.Signal
+		process         ifrit.Process
+	)
+
+	BeforeEach(func() {
+		reporter = new(fake_presence.FakePlannedRestartReporter)
+		presenceSignals = make(chan os.Signal, 1)
+	})
+
+	JustBeforeEach(func() {
+		presenceRunner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			presenceSignals <- <-signals
+			return nil
+		})
+
+		process = ifrit.Invoke(presence.NewRunner(lagertest.NewTestLogger("test"), presenceRunner, reporter))
+	})
+
+	Context("when the restart is not planned", func() {
+		It("stops the presence runner so the lock is released", func() {
+			process.Signal(os.Interrupt)
+			Eventually(presenceSignals).Should(Receive(Equal(os.Interrupt)))
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when the restart is planned", func() {
+		BeforeEach(func() {
+			reporter.PlannedRestartReturns(true)
+		})
+
+		It("exits without stopping the presence runner", func() {
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+			Consistently(presenceSignals).ShouldNot(Receive())
+		})
+	})
+})
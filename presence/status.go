@@ -0,0 +1,33 @@
+package presence
+
+import (
+	"sync/atomic"
+
+	"code.cloudfoundry.org/rep"
+)
+
+// Status records whether the cell lost the presence it held in locket. A cell
+// that lost its presence is unknown to the auctioneer until it is renewed.
+type Status struct {
+	lost int32
+}
+
+func NewStatus() *Status {
+	return &Status{}
+}
+
+// Condition reports the presence as lost until it is acquired again.
+func (s *Status) Condition() string {
+	if atomic.LoadInt32(&s.lost) == 1 {
+		return rep.CellConditionPresenceLost
+	}
+	return ""
+}
+
+func (s *Status) setLost(lost bool) {
+	var value int32
+	if lost {
+		value = 1
+	}
+	atomic.StoreInt32(&s.lost, value)
+}
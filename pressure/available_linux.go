@@ -0,0 +1,16 @@
+//go:build linux
+// +build linux
+
+package pressure
+
+import "syscall"
+
+func availableBytes(path string) (int64, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
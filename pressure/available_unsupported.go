@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package pressure
+
+import "errors"
+
+// Disk pressure is only read on Linux hosts.
+func availableBytes(path string) (int64, error) {
+	return 0, errors.New("reading the available disk is not supported on this platform")
+}
@@ -0,0 +1,205 @@
+package pressure
+
+import (
+	"os"
+	"sync/atomic"
+	"time"
+
+	"code.cloudfoundry.org/bbs"
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const pressureEvictionsMetric = "PressureEvictions"
+
+// EvictionReason is the failure reason reported to BBS for the LRP instances
+// and tasks destroyed to relieve pressure on the cell.
+func EvictionReason(shortfall Shortfall) string {
+	switch {
+	case shortfall.MemoryMB > 0 && shortfall.DiskMB > 0:
+		return "destroyed to relieve memory and disk pressure on the cell"
+	case shortfall.DiskMB > 0:
+		return "destroyed to relieve disk pressure on the cell"
+	default:
+		return "destroyed to relieve memory pressure on the cell"
+	}
+}
+
+// Evictor checks the host for pressure every interval and destroys the
+// containers SelectVictims picks when less than minAvailable memory or disk
+// is left, so that the cell rather than the kernel chooses what to kill. No
+// more containers are destroyed until cooldown has passed since the last
+// were, as the memory and disk they held take a while to be released.
+type Evictor struct {
+	logger          lager.Logger
+	cellID          string
+	reader          Reader
+	executorClient  executor.Client
+	metricsProvider rep.ContainerMetricsProvider
+	bbsClient       bbs.InternalClient
+	metronClient    loggingclient.IngressClient
+	clock           clock.Clock
+	interval        time.Duration
+	cooldown        time.Duration
+	minAvailable    Available
+
+	diskPressure int32
+	lastEviction time.Time
+}
+
+func NewEvictor(
+	logger lager.Logger,
+	cellID string,
+	reader Reader,
+	executorClient executor.Client,
+	metricsProvider rep.ContainerMetricsProvider,
+	bbsClient bbs.InternalClient,
+	metronClient loggingclient.IngressClient,
+	clock clock.Clock,
+	interval time.Duration,
+	cooldown time.Duration,
+	minAvailable Available,
+) *Evictor {
+	return &Evictor{
+		logger:          logger.Session("pressure-evictor"),
+		cellID:          cellID,
+		reader:          reader,
+		executorClient:  executorClient,
+		metricsProvider: metricsProvider,
+		bbsClient:       bbsClient,
+		metronClient:    metronClient,
+		clock:           clock,
+		interval:        interval,
+		cooldown:        cooldown,
+		minAvailable:    minAvailable,
+	}
+}
+
+func (e *Evictor) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			e.Evict(e.logger)
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// Evict destroys containers until the shortfall of the host is covered, and
+// returns the containers it destroyed.
+func (e *Evictor) Evict(logger lager.Logger) []executor.Container {
+	logger = logger.Session("evict")
+
+	available, err := e.reader.Read(logger)
+	if err != nil {
+		return nil
+	}
+
+	shortfall := e.shortfall(available)
+	e.setDiskPressure(shortfall.DiskMB > 0)
+	if shortfall.relieved() {
+		return nil
+	}
+
+	now := e.clock.Now()
+	if !e.lastEviction.IsZero() && now.Sub(e.lastEviction) < e.cooldown {
+		logger.Info("cooling-down", lager.Data{"shortfall": shortfall, "last-eviction": e.lastEviction})
+		return nil
+	}
+
+	containers, err := e.executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return nil
+	}
+
+	victims := SelectVictims(containers, e.metricsProvider.Metrics(), shortfall)
+	logger.Info("relieving-pressure", lager.Data{"shortfall": shortfall, "victims": len(victims)})
+	if len(victims) > 0 {
+		e.lastEviction = now
+	}
+
+	reason := EvictionReason(shortfall)
+	for _, victim := range victims {
+		e.report(logger, victim, reason)
+
+		err := e.executorClient.DeleteContainer(logger, victim.Guid)
+		if err != nil {
+			logger.Error("failed-to-delete-container", err, lager.Data{"container-guid": victim.Guid})
+		}
+	}
+
+	err = e.metronClient.SendMetric(pressureEvictionsMetric, len(victims))
+	if err != nil {
+		logger.Error("failed-to-send-pressure-evictions-metric", err)
+	}
+
+	return victims
+}
+
+// Condition reports disk pressure while the last check found less disk
+// available than the minimum, as work placed on the cell may be evicted
+// until the pressure is relieved.
+func (e *Evictor) Condition() string {
+	if atomic.LoadInt32(&e.diskPressure) == 1 {
+		return rep.CellConditionDiskPressure
+	}
+	return ""
+}
+
+func (e *Evictor) setDiskPressure(pressure bool) {
+	var value int32
+	if pressure {
+		value = 1
+	}
+	atomic.StoreInt32(&e.diskPressure, value)
+}
+
+func (e *Evictor) shortfall(available Available) Shortfall {
+	shortfall := Shortfall{}
+	if available.MemoryMB != Unknown {
+		shortfall.MemoryMB = e.minAvailable.MemoryMB - available.MemoryMB
+	}
+	if available.DiskMB != Unknown {
+		shortfall.DiskMB = e.minAvailable.DiskMB - available.DiskMB
+	}
+	return shortfall
+}
+
+// report fails the LRP instance or task the victim runs in BBS with reason.
+func (e *Evictor) report(logger lager.Logger, victim executor.Container, reason string) {
+	logger = logger.WithData(lager.Data{"container-guid": victim.Guid})
+
+	if isTask(victim) {
+		err := e.bbsClient.CompleteTask(logger, victim.Guid, e.cellID, true, reason, "")
+		if err != nil {
+			logger.Error("failed-to-complete-task", err)
+		}
+		return
+	}
+
+	lrpKey, err := rep.ActualLRPKeyFromTags(victim.Tags)
+	if err != nil {
+		logger.Error("failed-to-read-actual-lrp-key", err)
+		return
+	}
+	instanceKey, err := rep.ActualLRPInstanceKeyFromContainer(victim, e.cellID)
+	if err != nil {
+		logger.Error("failed-to-read-actual-lrp-instance-key", err)
+		return
+	}
+
+	err = e.bbsClient.CrashActualLRP(logger, lrpKey, instanceKey, reason)
+	if err != nil {
+		logger.Error("failed-to-crash-actual-lrp", err, lager.Data{"process-guid": lrpKey.ProcessGuid, "index": lrpKey.Index})
+	}
+}
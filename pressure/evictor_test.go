@@ -0,0 +1,219 @@
+package pressure_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/bbs/fake_bbs"
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
+	"code.cloudfoundry.org/rep/pressure"
+	"code.cloudfoundry.org/rep/pressure/pressurefakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit/ginkgomon"
+)
+
+var _ = Describe("Evictor", func() {
+	const cellID = "cell-id"
+
+	var (
+		logger             *lagertest.TestLogger
+		fakeReader         *pressurefakes.FakeReader
+		fakeExecutorClient *fakes.FakeClient
+		fakeMetrics        *auctioncellrepfakes.FakeContainerMetricsProvider
+		fakeBBSClient      *fake_bbs.FakeInternalClient
+		fakeMetronClient   *mfakes.FakeIngressClient
+		fakeClock          *fakeclock.FakeClock
+		evictor            *pressure.Evictor
+
+		lrpContainer, taskContainer executor.Container
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeReader = new(pressurefakes.FakeReader)
+		fakeExecutorClient = new(fakes.FakeClient)
+		fakeMetrics = new(auctioncellrepfakes.FakeContainerMetricsProvider)
+		fakeBBSClient = new(fake_bbs.FakeInternalClient)
+		fakeMetronClient = new(mfakes.FakeIngressClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		lrpContainer = executor.Container{
+			Guid:     "lrp-container",
+			State:    executor.StateRunning,
+			Resource: executor.Resource{MemoryMB: 512, DiskMB: 512},
+			Tags: executor.Tags{
+				rep.LifecycleTag:    rep.LRPLifecycle,
+				rep.DomainTag:       "domain",
+				rep.ProcessGuidTag:  "process-guid",
+				rep.ProcessIndexTag: "2",
+				rep.InstanceGuidTag: "instance-guid",
+			},
+		}
+		taskContainer = executor.Container{
+			Guid:     "task-guid",
+			State:    executor.StateRunning,
+			Resource: executor.Resource{MemoryMB: 256, DiskMB: 256},
+			Tags:     executor.Tags{rep.LifecycleTag: rep.TaskLifecycle},
+		}
+		fakeExecutorClient.ListContainersReturns([]executor.Container{lrpContainer, taskContainer}, nil)
+
+		evictor = pressure.NewEvictor(
+			logger,
+			cellID,
+			fakeReader,
+			fakeExecutorClient,
+			fakeMetrics,
+			fakeBBSClient,
+			fakeMetronClient,
+			fakeClock,
+			time.Minute,
+			5*time.Minute,
+			pressure.Available{MemoryMB: 1024, DiskMB: 2048},
+		)
+	})
+
+	Context("when the host is not under pressure", func() {
+		BeforeEach(func() {
+			fakeReader.ReadReturns(pressure.Available{MemoryMB: 4096, DiskMB: 4096}, nil)
+		})
+
+		It("destroys nothing", func() {
+			Expect(evictor.Evict(logger)).To(BeEmpty())
+			Expect(fakeExecutorClient.ListContainersCallCount()).To(Equal(0))
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(0))
+			Expect(evictor.Condition()).To(BeEmpty())
+		})
+	})
+
+	Context("when the host is short of memory", func() {
+		BeforeEach(func() {
+			fakeReader.ReadReturns(pressure.Available{MemoryMB: 800, DiskMB: 4096}, nil)
+		})
+
+		It("destroys the task first and reports it failed with a pressure reason", func() {
+			victims := evictor.Evict(logger)
+			Expect(victims).To(ConsistOf(taskContainer))
+
+			Expect(fakeBBSClient.CompleteTaskCallCount()).To(Equal(1))
+			_, taskGuid, taskCellID, failed, reason, _ := fakeBBSClient.CompleteTaskArgsForCall(0)
+			Expect(taskGuid).To(Equal("task-guid"))
+			Expect(taskCellID).To(Equal(cellID))
+			Expect(failed).To(BeTrue())
+			Expect(reason).To(Equal("destroyed to relieve memory pressure on the cell"))
+
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(1))
+			_, guid := fakeExecutorClient.DeleteContainerArgsForCall(0)
+			Expect(guid).To(Equal("task-guid"))
+		})
+
+		It("emits the number of destroyed containers", func() {
+			evictor.Evict(logger)
+
+			Expect(fakeMetronClient.SendMetricCallCount()).To(Equal(1))
+			name, value, _ := fakeMetronClient.SendMetricArgsForCall(0)
+			Expect(name).To(Equal("PressureEvictions"))
+			Expect(value).To(Equal(1))
+		})
+
+		It("frees what the containers are measured to use", func() {
+			fakeMetrics.MetricsReturns(map[string]*containermetrics.CachedContainerMetrics{
+				"task-guid":     {MemoryUsageBytes: 64 * 1024 * 1024},
+				"lrp-container": {MemoryUsageBytes: 400 * 1024 * 1024},
+			})
+
+			Expect(evictor.Evict(logger)).To(ConsistOf(taskContainer, lrpContainer))
+		})
+
+		It("destroys nothing more until the cooldown has passed", func() {
+			Expect(evictor.Evict(logger)).To(HaveLen(1))
+
+			fakeClock.Increment(4 * time.Minute)
+			Expect(evictor.Evict(logger)).To(BeEmpty())
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(1))
+
+			fakeClock.Increment(time.Minute)
+			Expect(evictor.Evict(logger)).To(HaveLen(1))
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("when the host is short of memory and disk", func() {
+		BeforeEach(func() {
+			fakeReader.ReadReturns(pressure.Available{MemoryMB: 512, DiskMB: 1024}, nil)
+		})
+
+		It("crashes the LRP instances it destroys", func() {
+			Expect(evictor.Evict(logger)).To(ConsistOf(taskContainer, lrpContainer))
+
+			Expect(fakeBBSClient.CrashActualLRPCallCount()).To(Equal(1))
+			_, lrpKey, instanceKey, reason := fakeBBSClient.CrashActualLRPArgsForCall(0)
+			Expect(*lrpKey).To(Equal(models.NewActualLRPKey("process-guid", 2, "domain")))
+			Expect(*instanceKey).To(Equal(models.NewActualLRPInstanceKey("instance-guid", cellID)))
+			Expect(reason).To(Equal("destroyed to relieve memory and disk pressure on the cell"))
+
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(2))
+		})
+
+		It("reports the disk pressure until it is relieved", func() {
+			evictor.Evict(logger)
+			Expect(evictor.Condition()).To(Equal(rep.CellConditionDiskPressure))
+
+			fakeReader.ReadReturns(pressure.Available{MemoryMB: 4096, DiskMB: 4096}, nil)
+			evictor.Evict(logger)
+			Expect(evictor.Condition()).To(BeEmpty())
+		})
+	})
+
+	Context("when the available resources cannot be read", func() {
+		BeforeEach(func() {
+			fakeReader.ReadReturns(pressure.Available{}, errors.New("boom"))
+		})
+
+		It("destroys nothing", func() {
+			Expect(evictor.Evict(logger)).To(BeEmpty())
+			Expect(fakeExecutorClient.DeleteContainerCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the available disk is unknown", func() {
+		BeforeEach(func() {
+			fakeReader.ReadReturns(pressure.Available{MemoryMB: 4096, DiskMB: pressure.Unknown}, nil)
+		})
+
+		It("does not consider the disk under pressure", func() {
+			Expect(evictor.Evict(logger)).To(BeEmpty())
+		})
+	})
+
+	Context("when running", func() {
+		BeforeEach(func() {
+			fakeReader.ReadReturns(pressure.Available{MemoryMB: 4096, DiskMB: 4096}, nil)
+		})
+
+		It("checks for pressure every interval", func() {
+			process := ginkgomon.Invoke(evictor)
+			defer ginkgomon.Interrupt(process)
+
+			Consistently(fakeReader.ReadCallCount).Should(Equal(0))
+
+			fakeClock.WaitForWatcherAndIncrement(time.Minute)
+			Eventually(fakeReader.ReadCallCount).Should(Equal(1))
+		})
+
+		It("exits when signalled", func() {
+			process := ginkgomon.Invoke(evictor)
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+})
@@ -0,0 +1 @@
+package pressure // import "code.cloudfoundry.org/rep/pressure"
@@ -0,0 +1,112 @@
+package pressure
+
+import (
+	"sort"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/rep"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// Shortfall is the memory and disk, in MB, the cell needs to free to leave
+// pressure.
+type Shortfall struct {
+	MemoryMB int64
+	DiskMB   int64
+}
+
+func (s Shortfall) relieved() bool {
+	return s.MemoryMB <= 0 && s.DiskMB <= 0
+}
+
+// usage is the memory and disk, in MB, destroying a container frees.
+type usage struct {
+	memoryMB int64
+	diskMB   int64
+}
+
+// short is how much of the resources short in shortfall usage frees.
+func (u usage) short(shortfall Shortfall) int64 {
+	var frees int64
+	if shortfall.MemoryMB > 0 {
+		frees += u.memoryMB
+	}
+	if shortfall.DiskMB > 0 {
+		frees += u.diskMB
+	}
+	return frees
+}
+
+// SelectVictims returns the containers to destroy to free shortfall, in the
+// order to destroy them: tasks before LRP instances, and, among either, the
+// containers measured to use the most of the resources short first, so that
+// as few are destroyed as possible. What a container frees is its usage in
+// metrics, which are keyed by container guid, or its quota when it has none.
+// Labels are set by whoever desires the work, so they never rank it.
+// Containers that free none of the resources short are never selected, and
+// fewer containers than needed are returned when destroying every candidate
+// would not free shortfall.
+func SelectVictims(containers []executor.Container, metrics map[string]*containermetrics.CachedContainerMetrics, shortfall Shortfall) []executor.Container {
+	candidates := make([]executor.Container, 0, len(containers))
+	usages := make(map[string]usage, len(containers))
+	for _, container := range containers {
+		if holdsResources(container) {
+			candidates = append(candidates, container)
+			usages[container.Guid] = measuredUsage(container, metrics[container.Guid])
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iTask, jTask := isTask(candidates[i]), isTask(candidates[j])
+		if iTask != jTask {
+			return iTask
+		}
+		iFrees, jFrees := usages[candidates[i].Guid].short(shortfall), usages[candidates[j].Guid].short(shortfall)
+		if iFrees != jFrees {
+			return iFrees > jFrees
+		}
+		return candidates[i].Guid < candidates[j].Guid
+	})
+
+	var victims []executor.Container
+	for _, container := range candidates {
+		if shortfall.relieved() {
+			break
+		}
+		used := usages[container.Guid]
+		frees := (shortfall.MemoryMB > 0 && used.memoryMB > 0) || (shortfall.DiskMB > 0 && used.diskMB > 0)
+		if !frees {
+			continue
+		}
+
+		victims = append(victims, container)
+		shortfall.MemoryMB -= used.memoryMB
+		shortfall.DiskMB -= used.diskMB
+	}
+
+	return victims
+}
+
+func measuredUsage(container executor.Container, metrics *containermetrics.CachedContainerMetrics) usage {
+	if metrics == nil {
+		return usage{memoryMB: int64(container.MemoryMB), diskMB: int64(container.DiskMB)}
+	}
+	return usage{
+		memoryMB: int64(metrics.MemoryUsageBytes / bytesPerMB),
+		diskMB:   int64(metrics.DiskUsageBytes / bytesPerMB),
+	}
+}
+
+func holdsResources(container executor.Container) bool {
+	switch container.State {
+	case executor.StateInitializing, executor.StateCreated, executor.StateRunning:
+		return true
+	}
+	return false
+}
+
+func isTask(container executor.Container) bool {
+	return container.Tags[rep.LifecycleTag] == rep.TaskLifecycle
+}
@@ -0,0 +1,108 @@
+package pressure_test
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/pressure"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelectVictims", func() {
+	const mb = 1024 * 1024
+
+	container := func(guid, lifecycle string, memoryMB, diskMB int) executor.Container {
+		return executor.Container{
+			Guid:     guid,
+			State:    executor.StateRunning,
+			Resource: executor.Resource{MemoryMB: memoryMB, DiskMB: diskMB},
+			Tags:     executor.Tags{rep.LifecycleTag: lifecycle},
+		}
+	}
+	used := func(memoryMB, diskMB uint64) *containermetrics.CachedContainerMetrics {
+		return &containermetrics.CachedContainerMetrics{MemoryUsageBytes: memoryMB * mb, DiskUsageBytes: diskMB * mb}
+	}
+
+	var (
+		containers []executor.Container
+		metrics    map[string]*containermetrics.CachedContainerMetrics
+	)
+
+	BeforeEach(func() {
+		containers = []executor.Container{
+			container("lrp-busy", rep.LRPLifecycle, 256, 256),
+			container("lrp-idle", rep.LRPLifecycle, 256, 256),
+			container("task-idle", rep.TaskLifecycle, 256, 256),
+			container("lrp-medium", rep.LRPLifecycle, 256, 256),
+			container("task-busy", rep.TaskLifecycle, 256, 256),
+		}
+		metrics = map[string]*containermetrics.CachedContainerMetrics{
+			"lrp-busy":   used(200, 200),
+			"lrp-idle":   used(10, 10),
+			"task-idle":  used(20, 20),
+			"lrp-medium": used(100, 100),
+			"task-busy":  used(150, 150),
+		}
+	})
+
+	guids := func(victims []executor.Container) []string {
+		result := []string{}
+		for _, victim := range victims {
+			result = append(result, victim.Guid)
+		}
+		return result
+	}
+
+	It("destroys tasks before LRP instances and the containers using the most first", func() {
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{MemoryMB: 480})
+		Expect(guids(victims)).To(Equal([]string{"task-busy", "task-idle", "lrp-busy", "lrp-medium", "lrp-idle"}))
+	})
+
+	It("destroys only as many containers as the measured usage needs", func() {
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{MemoryMB: 160, DiskMB: 100})
+		Expect(guids(victims)).To(Equal([]string{"task-busy", "task-idle"}))
+	})
+
+	It("ranks by the usage of the resources short", func() {
+		metrics["task-idle"] = used(500, 0)
+
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{DiskMB: 100})
+		Expect(guids(victims)).To(Equal([]string{"task-busy"}))
+	})
+
+	It("counts the quota of containers without metrics", func() {
+		delete(metrics, "task-idle")
+
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{MemoryMB: 200})
+		Expect(guids(victims)).To(Equal([]string{"task-idle"}))
+	})
+
+	It("ignores the labels of the work", func() {
+		containers[4].Tags[rep.LabelTagPrefix+"priority"] = "100"
+
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{MemoryMB: 100})
+		Expect(guids(victims)).To(Equal([]string{"task-busy"}))
+	})
+
+	It("skips containers that free none of the resources short", func() {
+		containers = append(containers, container("task-diskless", rep.TaskLifecycle, 512, 0))
+		metrics["task-diskless"] = used(512, 0)
+
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{DiskMB: 160})
+		Expect(guids(victims)).To(Equal([]string{"task-busy", "task-idle"}))
+	})
+
+	It("skips containers that do not run", func() {
+		containers[4].State = executor.StateReserved
+		containers[2].State = executor.StateCompleted
+
+		victims := pressure.SelectVictims(containers, metrics, pressure.Shortfall{MemoryMB: 150})
+		Expect(guids(victims)).To(Equal([]string{"lrp-busy"}))
+	})
+
+	It("selects nothing without a shortfall", func() {
+		Expect(pressure.SelectVictims(containers, metrics, pressure.Shortfall{MemoryMB: -100})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,13 @@
+package pressure_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPressure(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pressure Suite")
+}
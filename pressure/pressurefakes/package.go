@@ -0,0 +1 @@
+package pressurefakes // import "code.cloudfoundry.org/rep/pressure/pressurefakes"
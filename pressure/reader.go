@@ -0,0 +1,93 @@
+package pressure
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Unknown is the value of the resources of Available that were not read.
+const Unknown int64 = -1
+
+// Available is the memory and disk the host has left, in MB.
+type Available struct {
+	MemoryMB int64
+	DiskMB   int64
+}
+
+//go:generate counterfeiter -o pressurefakes/fake_reader.go . Reader
+
+// Reader reads the memory and disk available on the host the cell runs on.
+type Reader interface {
+	Read(logger lager.Logger) (Available, error)
+}
+
+type reader struct {
+	procPath string
+	diskPath string
+}
+
+// NewReader returns a Reader that reads the available memory from the proc
+// filesystem mounted at procPath, and the available disk of the filesystem
+// containing diskPath. The available disk is Unknown when diskPath is empty.
+func NewReader(procPath, diskPath string) Reader {
+	return &reader{
+		procPath: procPath,
+		diskPath: diskPath,
+	}
+}
+
+func (r *reader) Read(logger lager.Logger) (Available, error) {
+	available := Available{MemoryMB: Unknown, DiskMB: Unknown}
+
+	memoryMB, err := readMemAvailable(filepath.Join(r.procPath, "meminfo"))
+	if err != nil {
+		logger.Error("failed-to-read-available-memory", err)
+		return Available{}, err
+	}
+	available.MemoryMB = memoryMB
+
+	if r.diskPath != "" {
+		bytes, err := availableBytes(r.diskPath)
+		if err != nil {
+			logger.Error("failed-to-read-available-disk", err, lager.Data{"path": r.diskPath})
+			return Available{}, err
+		}
+		available.DiskMB = bytes / 1024 / 1024
+	}
+
+	return available, nil
+}
+
+// readMemAvailable returns the MemAvailable line of a meminfo file in MB.
+func readMemAvailable(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed meminfo line in %s: %s", path, scanner.Text())
+		}
+		return kb / 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("no MemAvailable line in %s", path)
+}
@@ -0,0 +1,81 @@
+package pressure_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/pressure"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reader", func() {
+	var (
+		procPath string
+		diskPath string
+		reader   pressure.Reader
+		logger   *lagertest.TestLogger
+	)
+
+	writeMeminfo := func(contents string) {
+		Expect(ioutil.WriteFile(filepath.Join(procPath, "meminfo"), []byte(contents), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		procPath, err = ioutil.TempDir("", "proc")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		diskPath = ""
+
+		writeMeminfo("MemTotal:       16384000 kB\nMemFree:         1024000 kB\nMemAvailable:    2097152 kB\n")
+	})
+
+	JustBeforeEach(func() {
+		reader = pressure.NewReader(procPath, diskPath)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(procPath)
+	})
+
+	It("reads the available memory", func() {
+		available, err := reader.Read(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(available.MemoryMB).To(Equal(int64(2048)))
+	})
+
+	Context("when no disk path is configured", func() {
+		It("does not know the available disk", func() {
+			available, err := reader.Read(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(available.DiskMB).To(Equal(pressure.Unknown))
+		})
+	})
+
+	Context("when meminfo has no MemAvailable line", func() {
+		BeforeEach(func() {
+			writeMeminfo("MemTotal:       16384000 kB\n")
+		})
+
+		It("returns an error", func() {
+			_, err := reader.Read(logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when meminfo is malformed", func() {
+		BeforeEach(func() {
+			writeMeminfo("MemAvailable:    lots kB\n")
+		})
+
+		It("returns an error", func() {
+			_, err := reader.Read(logger)
+			Expect(err).To(MatchError(ContainSubstring("malformed meminfo line")))
+		})
+	})
+})
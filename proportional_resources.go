@@ -0,0 +1,136 @@
+package rep
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+
+	"code.cloudfoundry.org/executor"
+)
+
+// ProportionalResourceTag holds the JSON encoded proportional resource of work
+// on its container, so that the cell reports what the work asked for along
+// with the amounts it resolved to.
+const ProportionalResourceTag = "proportional-resource"
+
+var ErrInvalidProportionalResource = errors.New("proportional resources must be percentages of up to 100 with bounds no larger than their maximum")
+
+// ProportionalResource requests the memory and disk of work as percentages of
+// the total resources of the cell it lands on, so that batch work can ask
+// for a share of whatever cell it gets. A zero percentage leaves the absolute
+// amount of the Resource as it is. The resolved amounts are bounded by the
+// minimum and maximum, when they are set.
+type ProportionalResource struct {
+	MemoryPercent float64 `json:",omitempty"`
+	DiskPercent   float64 `json:",omitempty"`
+	MinMemoryMB   int32   `json:",omitempty"`
+	MaxMemoryMB   int32   `json:",omitempty"`
+	MinDiskMB     int32   `json:",omitempty"`
+	MaxDiskMB     int32   `json:",omitempty"`
+}
+
+// Validate returns ErrInvalidProportionalResource for percentages outside of
+// 0 to 100, negative bounds, or a minimum above its maximum.
+func (p *ProportionalResource) Validate() error {
+	if p.MemoryPercent < 0 || p.MemoryPercent > 100 || p.DiskPercent < 0 || p.DiskPercent > 100 {
+		return ErrInvalidProportionalResource
+	}
+	if p.MinMemoryMB < 0 || p.MaxMemoryMB < 0 || p.MinDiskMB < 0 || p.MaxDiskMB < 0 {
+		return ErrInvalidProportionalResource
+	}
+	if (p.MaxMemoryMB > 0 && p.MinMemoryMB > p.MaxMemoryMB) || (p.MaxDiskMB > 0 && p.MinDiskMB > p.MaxDiskMB) {
+		return ErrInvalidProportionalResource
+	}
+	return nil
+}
+
+// Resolve returns a copy of r whose memory and disk are the concrete amounts
+// its proportional resource comes to out of total. The copy keeps the
+// proportional resource, so that the work reports what it asked for next to
+// what it got, and resolving it again against the same total changes nothing.
+func (r *Resource) Resolve(total *Resources) Resource {
+	resolved := r.Copy()
+	p := r.Proportional
+	if p == nil {
+		return resolved
+	}
+	if p.MemoryPercent > 0 {
+		resolved.MemoryMB = proportionOf(total.MemoryMB, p.MemoryPercent, p.MinMemoryMB, p.MaxMemoryMB)
+	}
+	if p.DiskPercent > 0 {
+		resolved.DiskMB = proportionOf(total.DiskMB, p.DiskPercent, p.MinDiskMB, p.MaxDiskMB)
+	}
+	return resolved
+}
+
+// proportionOf returns percent of total, rounded up to a whole megabyte and
+// bounded by min and max when they are set.
+func proportionOf(total int32, percent float64, min, max int32) int32 {
+	amount := int32(math.Ceil(float64(total) * percent / 100))
+	if max > 0 && amount > max {
+		amount = max
+	}
+	if amount < min {
+		amount = min
+	}
+	return amount
+}
+
+// AddProportionalResourceTag records the proportional resource of work on the
+// tags of its container. It does nothing when proportional is nil.
+func AddProportionalResourceTag(tags executor.Tags, proportional *ProportionalResource) {
+	if proportional == nil {
+		return
+	}
+	encoded, _ := json.Marshal(proportional)
+	tags[ProportionalResourceTag] = string(encoded)
+}
+
+// ProportionalResourceFromTags returns the proportional resource recorded on
+// the tags of a container, or nil when it has none.
+func ProportionalResourceFromTags(tags executor.Tags) (*ProportionalResource, error) {
+	encoded, ok := tags[ProportionalResourceTag]
+	if !ok {
+		return nil, nil
+	}
+
+	proportional := &ProportionalResource{}
+	err := json.Unmarshal([]byte(encoded), proportional)
+	if err != nil {
+		return nil, err
+	}
+	return proportional, nil
+}
+
+// proportionTotal returns the total resources the proportional resources of
+// work on rootfs resolve against. On a cell with backends that is the total
+// the backend the work is placed on advertises, as the cell resolves them
+// against when it performs the work, rather than the aggregate of the cell.
+func (c *CellState) proportionTotal(rootfs string) *Resources {
+	if backend := c.backendFor(rootfs); backend != nil {
+		return &backend.TotalResources
+	}
+	return &c.TotalResources
+}
+
+// resolvedLRP returns lrp with its resources resolved against the total
+// resources it is placed on, or lrp itself when it requests none in
+// proportion.
+func (c *CellState) resolvedLRP(lrp *LRP) *LRP {
+	if lrp.Proportional == nil {
+		return lrp
+	}
+	resolved := *lrp
+	resolved.Resource = lrp.Resource.Resolve(c.proportionTotal(lrp.RootFs))
+	return &resolved
+}
+
+// resolvedTask is resolvedLRP for a task.
+func (c *CellState) resolvedTask(task *Task) *Task {
+	if task.Proportional == nil {
+		return task
+	}
+	resolved := *task
+	resolved.Resource = task.Resource.Resolve(c.proportionTotal(task.RootFs))
+	return &resolved
+}
@@ -0,0 +1,142 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProportionalResource", func() {
+	var total rep.Resources
+
+	BeforeEach(func() {
+		total = rep.NewResources(10000, 20000, 100)
+	})
+
+	Describe("Resolve", func() {
+		It("resolves the percentages against the total", func() {
+			res := rep.NewResource(128, 256, 10)
+			res.Proportional = &rep.ProportionalResource{MemoryPercent: 5, DiskPercent: 2.5}
+
+			resolved := res.Resolve(&total)
+			Expect(resolved.MemoryMB).To(Equal(int32(500)))
+			Expect(resolved.DiskMB).To(Equal(int32(500)))
+			Expect(resolved.MaxPids).To(Equal(int32(10)))
+			Expect(resolved.Proportional).To(Equal(res.Proportional))
+		})
+
+		It("rounds up to a whole megabyte", func() {
+			res := rep.Resource{Proportional: &rep.ProportionalResource{MemoryPercent: 0.001}}
+			Expect(res.Resolve(&total).MemoryMB).To(Equal(int32(1)))
+		})
+
+		It("bounds the amounts by their minimum and maximum", func() {
+			res := rep.Resource{Proportional: &rep.ProportionalResource{
+				MemoryPercent: 1, MinMemoryMB: 1024,
+				DiskPercent: 50, MaxDiskMB: 4096,
+			}}
+
+			resolved := res.Resolve(&total)
+			Expect(resolved.MemoryMB).To(Equal(int32(1024)))
+			Expect(resolved.DiskMB).To(Equal(int32(4096)))
+		})
+
+		It("keeps the absolute amounts without a percentage", func() {
+			res := rep.NewResource(128, 256, 10)
+			res.Proportional = &rep.ProportionalResource{DiskPercent: 10}
+
+			resolved := res.Resolve(&total)
+			Expect(resolved.MemoryMB).To(Equal(int32(128)))
+			Expect(resolved.DiskMB).To(Equal(int32(2000)))
+		})
+
+		It("changes nothing when resolved again", func() {
+			res := rep.Resource{Proportional: &rep.ProportionalResource{MemoryPercent: 5}}
+			resolved := res.Resolve(&total)
+			Expect(resolved.Resolve(&total)).To(Equal(resolved))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts percentages of up to 100 within consistent bounds", func() {
+			Expect((&rep.ProportionalResource{MemoryPercent: 100, MinMemoryMB: 1, MaxMemoryMB: 2}).Validate()).To(Succeed())
+		})
+
+		It("rejects percentages above 100", func() {
+			Expect((&rep.ProportionalResource{DiskPercent: 101}).Validate()).To(MatchError(rep.ErrInvalidProportionalResource))
+		})
+
+		It("rejects a minimum above its maximum", func() {
+			Expect((&rep.ProportionalResource{MemoryPercent: 5, MinMemoryMB: 2048, MaxMemoryMB: 1024}).Validate()).To(MatchError(rep.ErrInvalidProportionalResource))
+		})
+
+		It("makes the resource invalid", func() {
+			res := rep.Resource{Proportional: &rep.ProportionalResource{MemoryPercent: -1}}
+			Expect(res.Valid()).To(BeFalse())
+		})
+	})
+
+	It("round trips through container tags", func() {
+		proportional := &rep.ProportionalResource{MemoryPercent: 5, MaxMemoryMB: 4096}
+		tags := executor.Tags{}
+		rep.AddProportionalResourceTag(tags, proportional)
+		Expect(rep.ProportionalResourceFromTags(tags)).To(Equal(proportional))
+	})
+
+	Describe("on a cell", func() {
+		var (
+			cellState rep.CellState
+			lrp       rep.LRP
+		)
+
+		BeforeEach(func() {
+			cellState = rep.NewCellState("cell-id", 0, "https://foo.cell.service.cf.internal", rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}}, rep.NewResources(4000, 20000, 100), total, nil, nil, "", 0, false, nil, nil, nil, 0)
+			lrp = rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.Resource{Proportional: &rep.ProportionalResource{MemoryPercent: 50}}, rep.NewPlacementConstraint("docker:///busybox", nil, nil))
+		})
+
+		It("matches the work by its resolved resources", func() {
+			err := cellState.LRPResourceMatch(&lrp)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(rep.InsufficientResourcesError).Problems).To(HaveKey("memory"))
+
+			lrp.Proportional.MemoryPercent = 20
+			Expect(cellState.LRPResourceMatch(&lrp)).To(Succeed())
+		})
+
+		It("takes the resolved resources of the work", func() {
+			lrp.Proportional.MemoryPercent = 20
+			cellState.AddLRP(&lrp)
+
+			Expect(cellState.AvailableResources.MemoryMB).To(Equal(int32(2000)))
+			Expect(cellState.LRPs[0].MemoryMB).To(Equal(int32(2000)))
+		})
+
+		Context("when the cell has backends", func() {
+			BeforeEach(func() {
+				cellState.Backends = []rep.BackendState{{
+					Name:               "linux",
+					RootFSProviders:    rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+					AvailableResources: rep.NewResources(4000, 20000, 100),
+					TotalResources:     rep.NewResources(4000, 20000, 100),
+				}}
+			})
+
+			It("resolves the work against the total of the backend it is placed on", func() {
+				Expect(cellState.LRPResourceMatch(&lrp)).To(Succeed())
+
+				absolute := rep.NewResource(2000, 0, 0)
+				Expect(cellState.ComputeLRPScore(&lrp, 0, nil)).To(Equal(cellState.ComputeScore(&absolute, 0, nil)))
+
+				task := rep.NewTask("tg", "domain", lrp.Resource, lrp.PlacementConstraint)
+				Expect(cellState.ComputeTaskScore(&task, 0, nil)).To(Equal(cellState.ComputeScore(&absolute, 0, nil)))
+
+				cellState.AddLRP(&lrp)
+				Expect(cellState.LRPs[0].MemoryMB).To(Equal(int32(2000)))
+				Expect(cellState.Backends[0].AvailableResources.MemoryMB).To(Equal(int32(2000)))
+			})
+		})
+	})
+})
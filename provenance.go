@@ -0,0 +1,78 @@
+package rep
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+)
+
+// ProvenanceTag holds the JSON encoded provenance of work on its container.
+const ProvenanceTag = "provenance"
+
+// Provenance identifies the auction decision that placed work on the cell.
+// The cell records it on the container of the work and reports it with the
+// container and its completion, so that any container can be traced back to
+// the auction that created it.
+type Provenance struct {
+	AuctionID    string `json:"auction_id,omitempty"`
+	AuctioneerID string `json:"auctioneer_id,omitempty"`
+	// Attempt counts the auctions the work went through, the first being 1.
+	Attempt int `json:"attempt,omitempty"`
+	// TraceID is the id of the trace of the request the auction was started
+	// for.
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// WithTraceID returns a copy of p with the id of the trace of trace when p
+// has none of its own, or p as it is. It returns nil when p is nil.
+func (p *Provenance) WithTraceID(trace *TraceContext) *Provenance {
+	if p == nil || p.TraceID != "" || trace == nil {
+		return p
+	}
+	traced := *p
+	traced.TraceID = trace.TraceID()
+	return &traced
+}
+
+// AddProvenanceTag records provenance on the tags of a container. It does
+// nothing when provenance is nil.
+func AddProvenanceTag(tags executor.Tags, provenance *Provenance) {
+	if provenance == nil {
+		return
+	}
+	encoded, _ := json.Marshal(provenance)
+	tags[ProvenanceTag] = string(encoded)
+}
+
+// ProvenanceFromTags returns the provenance recorded on the tags of a
+// container, or nil when none is or it cannot be decoded.
+func ProvenanceFromTags(tags executor.Tags) *Provenance {
+	encoded, ok := tags[ProvenanceTag]
+	if !ok {
+		return nil
+	}
+
+	provenance := &Provenance{}
+	if err := json.Unmarshal([]byte(encoded), provenance); err != nil {
+		return nil
+	}
+	return provenance
+}
+
+// WithProvenance returns logger logging provenance along with its messages,
+// or logger when provenance is nil.
+func WithProvenance(logger lager.Logger, provenance *Provenance) lager.Logger {
+	if provenance == nil {
+		return logger
+	}
+	data := lager.Data{
+		"auction-id":    provenance.AuctionID,
+		"auctioneer-id": provenance.AuctioneerID,
+		"attempt":       provenance.Attempt,
+	}
+	if provenance.TraceID != "" {
+		data["trace-id"] = provenance.TraceID
+	}
+	return logger.WithData(data)
+}
@@ -0,0 +1,63 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Provenance", func() {
+	var provenance *rep.Provenance
+
+	BeforeEach(func() {
+		provenance = &rep.Provenance{AuctionID: "auction-1", AuctioneerID: "auctioneer-0", Attempt: 2}
+	})
+
+	It("round trips through container tags", func() {
+		tags := executor.Tags{}
+		rep.AddProvenanceTag(tags, provenance)
+		Expect(tags).To(HaveKey(rep.ProvenanceTag))
+		Expect(rep.ProvenanceFromTags(tags)).To(Equal(provenance))
+	})
+
+	It("records nothing without a provenance", func() {
+		tags := executor.Tags{}
+		rep.AddProvenanceTag(tags, nil)
+		Expect(tags).To(BeEmpty())
+		Expect(rep.ProvenanceFromTags(tags)).To(BeNil())
+	})
+
+	It("ignores a provenance tag that cannot be decoded", func() {
+		Expect(rep.ProvenanceFromTags(executor.Tags{rep.ProvenanceTag: "{"})).To(BeNil())
+	})
+
+	Describe("WithTraceID", func() {
+		trace := &rep.TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+
+		It("takes the id of the trace when the provenance has none", func() {
+			traced := provenance.WithTraceID(trace)
+			Expect(traced.TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(provenance.TraceID).To(BeEmpty())
+		})
+
+		It("keeps the trace id of the provenance", func() {
+			provenance.TraceID = "original"
+			Expect(provenance.WithTraceID(trace).TraceID).To(Equal("original"))
+		})
+
+		It("returns nil without a provenance", func() {
+			var none *rep.Provenance
+			Expect(none.WithTraceID(trace)).To(BeNil())
+		})
+	})
+
+	It("logs the provenance", func() {
+		logger := lagertest.NewTestLogger("test")
+		rep.WithProvenance(logger, provenance).Info("completing")
+		Expect(logger.Buffer()).To(gbytes.Say(`"auction-id":"auction-1"`))
+	})
+})
@@ -0,0 +1,87 @@
+package proxyreadiness
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter -o proxyreadinessfakes/fake_checker.go . Checker
+
+// Checker reports whether the proxy running next to an LRP instance is
+// ready to accept traffic.
+type Checker interface {
+	Name() string
+	Ready(logger lager.Logger, container executor.Container) (bool, error)
+}
+
+type httpChecker struct {
+	client *http.Client
+	port   uint16
+	path   string
+}
+
+// NewHTTPChecker returns a Checker that considers the proxy ready once a
+// GET request for path on port of the container's internal address, such as
+// the Envoy admin readiness endpoint, responds with 200 OK.
+func NewHTTPChecker(client *http.Client, port uint16, path string) Checker {
+	return &httpChecker{
+		client: client,
+		port:   port,
+		path:   path,
+	}
+}
+
+func (c *httpChecker) Name() string {
+	return "http"
+}
+
+func (c *httpChecker) Ready(logger lager.Logger, container executor.Container) (bool, error) {
+	if container.InternalIP == "" {
+		return false, fmt.Errorf("container %s has no internal address", container.Guid)
+	}
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(container.InternalIP, strconv.Itoa(int(c.port))), c.path)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Debug("proxy-not-ready", lager.Data{"status-code": resp.StatusCode})
+		return false, nil
+	}
+	return true, nil
+}
+
+type fileChecker struct {
+	dir string
+}
+
+// NewFileChecker returns a Checker that considers the proxy ready once a
+// file named after the container guid exists in dir.
+func NewFileChecker(dir string) Checker {
+	return &fileChecker{dir: dir}
+}
+
+func (c *fileChecker) Name() string {
+	return "file"
+}
+
+func (c *fileChecker) Ready(logger lager.Logger, container executor.Container) (bool, error) {
+	_, err := os.Stat(filepath.Join(c.dir, container.Guid))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package proxyreadiness_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/proxyreadiness"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		logger    *lagertest.TestLogger
+		container executor.Container
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		container = executor.Container{Guid: "container-guid"}
+	})
+
+	Describe("HTTP", func() {
+		var (
+			fakeServer *ghttp.Server
+			checker    proxyreadiness.Checker
+		)
+
+		BeforeEach(func() {
+			fakeServer = ghttp.NewServer()
+
+			host, portString, err := net.SplitHostPort(fakeServer.Addr())
+			Expect(err).NotTo(HaveOccurred())
+			port, err := strconv.Atoi(portString)
+			Expect(err).NotTo(HaveOccurred())
+
+			container.InternalIP = host
+			checker = proxyreadiness.NewHTTPChecker(http.DefaultClient, uint16(port), "/ready")
+		})
+
+		AfterEach(func() {
+			fakeServer.Close()
+		})
+
+		It("is ready when the endpoint responds with 200 OK", func() {
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/ready"),
+				ghttp.RespondWith(http.StatusOK, "LIVE"),
+			))
+
+			Expect(checker.Ready(logger, container)).To(BeTrue())
+		})
+
+		It("is not ready when the endpoint responds with any other status", func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, "PRE_INITIALIZING"))
+
+			Expect(checker.Ready(logger, container)).To(BeFalse())
+		})
+
+		It("errors when the container has no internal address", func() {
+			container.InternalIP = ""
+
+			_, err := checker.Ready(logger, container)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("File", func() {
+		var (
+			dir     string
+			checker proxyreadiness.Checker
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "proxy-readiness")
+			Expect(err).NotTo(HaveOccurred())
+
+			checker = proxyreadiness.NewFileChecker(dir)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("is not ready until the container's file exists", func() {
+			Expect(checker.Ready(logger, container)).To(BeFalse())
+
+			Expect(ioutil.WriteFile(filepath.Join(dir, "container-guid"), nil, 0644)).To(Succeed())
+			Expect(checker.Ready(logger, container)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1 @@
+package proxyreadiness // import "code.cloudfoundry.org/rep/proxyreadiness"
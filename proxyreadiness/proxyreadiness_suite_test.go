@@ -0,0 +1,13 @@
+package proxyreadiness_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestProxyreadiness(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Proxyreadiness Suite")
+}
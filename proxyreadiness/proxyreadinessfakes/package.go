@@ -0,0 +1 @@
+package proxyreadinessfakes // import "code.cloudfoundry.org/rep/proxyreadiness/proxyreadinessfakes"
@@ -0,0 +1,81 @@
+package proxyreadiness
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+)
+
+var ErrTimedOut = errors.New("timed out waiting for the proxy to become ready")
+
+//go:generate counterfeiter -o proxyreadinessfakes/fake_waiter.go . Waiter
+
+// Waiter blocks until the proxy of an LRP instance is ready, so that the
+// instance is not reported as running while its routes would still fail.
+type Waiter interface {
+	Wait(logger lager.Logger, container executor.Container) error
+}
+
+type waiter struct {
+	checker  Checker
+	clock    clock.Clock
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// NewWaiter returns a Waiter that polls checker every interval until it
+// reports the proxy is ready, or returns ErrTimedOut after timeout.
+// Containers without a proxy are ready immediately.
+func NewWaiter(checker Checker, clock clock.Clock, interval, timeout time.Duration) Waiter {
+	return &waiter{
+		checker:  checker,
+		clock:    clock,
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+func (w *waiter) Wait(logger lager.Logger, container executor.Container) error {
+	if !hasProxy(container) {
+		return nil
+	}
+
+	logger = logger.Session("wait-for-proxy", lager.Data{"checker": w.checker.Name()})
+	logger.Debug("starting")
+	defer logger.Debug("finished")
+
+	timeout := w.clock.NewTimer(w.timeout)
+	defer timeout.Stop()
+
+	ticker := w.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		ready, err := w.checker.Ready(logger, container)
+		if err != nil {
+			logger.Debug("failed-to-check-proxy-readiness", lager.Data{"error": err.Error()})
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ticker.C():
+		case <-timeout.C():
+			logger.Error("timed-out", ErrTimedOut)
+			return ErrTimedOut
+		}
+	}
+}
+
+func hasProxy(container executor.Container) bool {
+	for _, port := range container.Ports {
+		if port.ContainerTLSProxyPort != 0 {
+			return true
+		}
+	}
+	return false
+}
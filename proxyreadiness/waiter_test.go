@@ -0,0 +1,96 @@
+package proxyreadiness_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/proxyreadiness"
+	"code.cloudfoundry.org/rep/proxyreadiness/proxyreadinessfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Waiter", func() {
+	var (
+		logger      *lagertest.TestLogger
+		fakeChecker *proxyreadinessfakes.FakeChecker
+		fakeClock   *fakeclock.FakeClock
+		container   executor.Container
+		waiter      proxyreadiness.Waiter
+		errCh       chan error
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeChecker = new(proxyreadinessfakes.FakeChecker)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		container = executor.Container{
+			Guid:  "container-guid",
+			Ports: []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001, ContainerTLSProxyPort: 61443, HostTLSProxyPort: 61002}},
+		}
+		waiter = proxyreadiness.NewWaiter(fakeChecker, fakeClock, time.Second, 10*time.Second)
+		errCh = make(chan error, 1)
+	})
+
+	JustBeforeEach(func() {
+		go func() {
+			errCh <- waiter.Wait(logger, container)
+		}()
+	})
+
+	Context("when the proxy is ready", func() {
+		BeforeEach(func() {
+			fakeChecker.ReadyReturns(true, nil)
+		})
+
+		It("returns immediately", func() {
+			Eventually(errCh).Should(Receive(BeNil()))
+			Expect(fakeChecker.ReadyCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the proxy becomes ready", func() {
+		BeforeEach(func() {
+			fakeChecker.ReadyReturnsOnCall(0, false, nil)
+			fakeChecker.ReadyReturnsOnCall(1, false, errors.New("connection refused"))
+			fakeChecker.ReadyReturnsOnCall(2, true, nil)
+		})
+
+		It("polls until it is ready", func() {
+			Consistently(errCh).ShouldNot(Receive())
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(fakeChecker.ReadyCallCount).Should(Equal(2))
+			Consistently(errCh).ShouldNot(Receive())
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(errCh).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when the proxy never becomes ready", func() {
+		BeforeEach(func() {
+			fakeChecker.ReadyReturns(false, nil)
+		})
+
+		It("times out", func() {
+			Eventually(fakeChecker.ReadyCallCount).Should(Equal(1))
+			fakeClock.WaitForNWatchersAndIncrement(10*time.Second, 2)
+			Eventually(errCh).Should(Receive(Equal(proxyreadiness.ErrTimedOut)))
+		})
+	})
+
+	Context("when the container has no proxy", func() {
+		BeforeEach(func() {
+			container.Ports = []executor.PortMapping{{ContainerPort: 8080, HostPort: 61001}}
+		})
+
+		It("does not check the proxy", func() {
+			Eventually(errCh).Should(Receive(BeNil()))
+			Expect(fakeChecker.ReadyCallCount()).To(Equal(0))
+		})
+	})
+})
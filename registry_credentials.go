@@ -0,0 +1,102 @@
+package rep
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// RegistryCredentials are the credentials and certificate authorities of the
+// private docker registry the rootfs of an LRP or task is pulled from.
+// CACertificates holds PEM encoded certificates.
+type RegistryCredentials struct {
+	Username       string `json:"username,omitempty"`
+	Password       string `json:"password,omitempty"`
+	CACertificates string `json:"ca_certificates,omitempty"`
+}
+
+type RegistryValidationReason string
+
+const (
+	RegistryIncompleteCredentials  RegistryValidationReason = "incomplete_credentials"
+	RegistryInvalidCertificate     RegistryValidationReason = "invalid_certificate"
+	RegistryExpiredCertificate     RegistryValidationReason = "expired_certificate"
+	RegistryCertificateNotYetValid RegistryValidationReason = "certificate_not_yet_valid"
+	RegistryUnconvertedCredentials RegistryValidationReason = "unconverted_credentials"
+)
+
+const redactedPassword = "[REDACTED]"
+
+// RegistryValidationError is returned when the registry credentials of work
+// cannot be used to pull its rootfs.
+type RegistryValidationError struct {
+	Reason  RegistryValidationReason `json:"reason"`
+	Message string                   `json:"message"`
+}
+
+func (e RegistryValidationError) Error() string {
+	return fmt.Sprintf("invalid registry credentials (%s): %s", e.Reason, e.Message)
+}
+
+// Validate returns a RegistryValidationError when only one of the username
+// and password is set, or when one of the certificate authorities cannot be
+// parsed or is not valid at now.
+func (c *RegistryCredentials) Validate(now time.Time) error {
+	if (c.Username == "") != (c.Password == "") {
+		return RegistryValidationError{Reason: RegistryIncompleteCredentials, Message: "a username and a password are both required"}
+	}
+
+	rest := []byte(c.CACertificates)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			if len(bytes.TrimSpace(rest)) == 0 {
+				break
+			}
+			return RegistryValidationError{Reason: RegistryInvalidCertificate, Message: "the certificate authorities are not PEM encoded"}
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return RegistryValidationError{Reason: RegistryInvalidCertificate, Message: err.Error()}
+		}
+		if now.After(cert.NotAfter) {
+			return RegistryValidationError{
+				Reason:  RegistryExpiredCertificate,
+				Message: fmt.Sprintf("certificate %q expired at %s", cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339)),
+			}
+		}
+		if now.Before(cert.NotBefore) {
+			return RegistryValidationError{
+				Reason:  RegistryCertificateNotYetValid,
+				Message: fmt.Sprintf("certificate %q is not valid before %s", cert.Subject.CommonName, cert.NotBefore.UTC().Format(time.RFC3339)),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Redacted returns a copy of the credentials without their password, to be
+// reported back to whoever sent them.
+func (c *RegistryCredentials) Redacted() *RegistryCredentials {
+	if c == nil {
+		return nil
+	}
+	redacted := *c
+	if redacted.Password != "" {
+		redacted.Password = redactedPassword
+	}
+	return &redacted
+}
+
+// RegistryValidationFailure records the LRP instance or task of a Work that
+// was rejected because its registry credentials failed validation.
+type RegistryValidationFailure struct {
+	InstanceGUID string                  `json:"instance_guid,omitempty"`
+	TaskGuid     string                  `json:"task_guid,omitempty"`
+	Error        RegistryValidationError `json:"error"`
+}
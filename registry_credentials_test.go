@@ -0,0 +1,97 @@
+package rep_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RegistryCredentials", func() {
+	var (
+		now         time.Time
+		credentials rep.RegistryCredentials
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		credentials = rep.RegistryCredentials{
+			Username:       "user",
+			Password:       "password",
+			CACertificates: certificatePEM("registry-ca", now.Add(-time.Hour), now.Add(time.Hour)),
+		}
+	})
+
+	It("redacts the password", func() {
+		redacted := credentials.Redacted()
+		Expect(redacted.Password).To(Equal("[REDACTED]"))
+		Expect(redacted.Username).To(Equal("user"))
+		Expect(redacted.CACertificates).To(Equal(credentials.CACertificates))
+		Expect(credentials.Password).To(Equal("password"))
+	})
+
+	It("validates complete credentials with valid certificates", func() {
+		Expect(credentials.Validate(now)).To(Succeed())
+	})
+
+	It("validates credentials without a username, password or certificates", func() {
+		credentials = rep.RegistryCredentials{}
+		Expect(credentials.Validate(now)).To(Succeed())
+	})
+
+	It("rejects a username without a password", func() {
+		credentials.Password = ""
+		Expect(credentials.Validate(now)).To(MatchError(rep.RegistryValidationError{
+			Reason:  rep.RegistryIncompleteCredentials,
+			Message: "a username and a password are both required",
+		}))
+	})
+
+	It("rejects certificate authorities that are not PEM encoded", func() {
+		credentials.CACertificates = "garbage"
+		err := credentials.Validate(now)
+		Expect(err).To(BeAssignableToTypeOf(rep.RegistryValidationError{}))
+		Expect(err.(rep.RegistryValidationError).Reason).To(Equal(rep.RegistryInvalidCertificate))
+	})
+
+	It("rejects an expired certificate among several", func() {
+		credentials.CACertificates += certificatePEM("expired-ca", now.Add(-2*time.Hour), now.Add(-time.Hour))
+		Expect(credentials.Validate(now)).To(MatchError(rep.RegistryValidationError{
+			Reason:  rep.RegistryExpiredCertificate,
+			Message: `certificate "expired-ca" expired at 2025-12-31T23:00:00Z`,
+		}))
+	})
+
+	It("rejects a certificate that is not valid yet", func() {
+		credentials.CACertificates = certificatePEM("future-ca", now.Add(time.Hour), now.Add(2*time.Hour))
+		err := credentials.Validate(now)
+		Expect(err).To(BeAssignableToTypeOf(rep.RegistryValidationError{}))
+		Expect(err.(rep.RegistryValidationError).Reason).To(Equal(rep.RegistryCertificateNotYetValid))
+	})
+})
+
+func certificatePEM(commonName string, notBefore, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
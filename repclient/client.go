@@ -0,0 +1,373 @@
+package repclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+	"github.com/tedsuo/rata"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Client talks to a single rep. The network accessible routes are served on
+// the rep_url the cell registers, while the operator routes (Ping, Evacuate,
+// the maintenance routes and PlannedRestart) are only served on localhost and
+// DebugConfig and Fragmentation on the admin listener, so a Client is usually
+// created for one of those listeners.
+type Client struct {
+	httpClient       *http.Client
+	tlsConfig        *tls.Config
+	requestGenerator *rata.RequestGenerator
+	attempts         int
+	backoff          time.Duration
+	tracer           Tracer
+}
+
+// New returns a Client for the rep listening at repURL.
+func New(repURL string, options ...Option) (*Client, error) {
+	c := &Client{
+		requestGenerator: rata.NewRequestGenerator(strings.TrimSuffix(repURL, "/"), rep.Routes),
+		attempts:         1,
+	}
+
+	for _, option := range options {
+		err := option(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{
+			Timeout:   defaultTimeout,
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+		}
+	}
+
+	if c.tlsConfig != nil {
+		transport, ok := c.httpClient.Transport.(*http.Transport)
+		if !ok {
+			return nil, errTransportNotConfigurable
+		}
+		transport = transport.Clone()
+		transport.TLSClientConfig = c.tlsConfig
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+
+	return c, nil
+}
+
+// State returns the state of the cell, and whether the cell considers
+// itself healthy. An unhealthy cell still reports its state.
+func (c *Client) State(ctx context.Context) (rep.CellState, bool, error) {
+	var state rep.CellState
+	statusCode, err := c.do(ctx, request{
+		route:      rep.StateRoute,
+		idempotent: true,
+		expected:   []int{http.StatusOK, http.StatusServiceUnavailable},
+		response:   &state,
+	})
+	if err != nil {
+		return rep.CellState{}, false, err
+	}
+	return state, statusCode == http.StatusOK, nil
+}
+
+// ContainerMetrics returns the metrics of every container on the cell.
+func (c *Client) ContainerMetrics(ctx context.Context) (*rep.ContainerMetricsCollection, error) {
+	metrics := &rep.ContainerMetricsCollection{}
+	_, err := c.do(ctx, request{
+		route:      rep.ContainerMetricsRoute,
+		idempotent: true,
+		expected:   []int{http.StatusOK},
+		response:   metrics,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// Perform asks the cell to run work and returns the part of it the cell
+// could not allocate.
+func (c *Client) Perform(ctx context.Context, work rep.Work) (rep.Work, error) {
+	var failedWork rep.Work
+	_, err := c.do(ctx, request{
+		route:    rep.PerformRoute,
+		body:     work,
+		expected: []int{http.StatusOK},
+		response: &failedWork,
+	})
+	if err != nil {
+		return rep.Work{}, err
+	}
+	return failedWork, nil
+}
+
+func (c *Client) UpdateLRPInstance(ctx context.Context, update rep.LRPUpdate) error {
+	_, err := c.do(ctx, request{
+		route: rep.UpdateLRPInstanceRoute,
+		params: rata.Params{
+			"process_guid":  update.ProcessGuid,
+			"instance_guid": update.InstanceGUID,
+		},
+		body:       update,
+		idempotent: true,
+		expected:   []int{http.StatusAccepted},
+	})
+	return err
+}
+
+func (c *Client) StopLRPInstance(ctx context.Context, key models.ActualLRPKey, instanceKey models.ActualLRPInstanceKey) error {
+	_, err := c.do(ctx, request{
+		route: rep.StopLRPInstanceRoute,
+		params: rata.Params{
+			"process_guid":  key.ProcessGuid,
+			"instance_guid": instanceKey.InstanceGuid,
+			"index":         strconv.Itoa(int(key.Index)),
+		},
+		idempotent: true,
+		expected:   []int{http.StatusAccepted},
+	})
+	return err
+}
+
+// StopLRPInstances stops many instances with a single request and returns the
+// outcome for each of them, in the order of the instances.
+func (c *Client) StopLRPInstances(ctx context.Context, instances []rep.StopLRPInstanceRequest) ([]rep.StopLRPInstanceResult, error) {
+	var results []rep.StopLRPInstanceResult
+	_, err := c.do(ctx, request{
+		route:      rep.StopLRPInstancesRoute,
+		body:       instances,
+		idempotent: true,
+		expected:   []int{http.StatusOK},
+		response:   &results,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *Client) CancelTask(ctx context.Context, taskGuid string) error {
+	_, err := c.do(ctx, request{
+		route:      rep.CancelTaskRoute,
+		params:     rata.Params{"task_guid": taskGuid},
+		idempotent: true,
+		expected:   []int{http.StatusAccepted},
+	})
+	return err
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		route:      rep.PingRoute,
+		idempotent: true,
+		expected:   []int{http.StatusOK},
+	})
+	return err
+}
+
+// Evacuate starts evacuating the cell. The rep keeps serving Ping until
+// the evacuation completed.
+func (c *Client) Evacuate(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		route:      rep.EvacuateRoute,
+		idempotent: true,
+		expected:   []int{http.StatusAccepted},
+	})
+	return err
+}
+
+func (c *Client) StartMaintenance(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		route:      rep.StartMaintenanceRoute,
+		idempotent: true,
+		expected:   []int{http.StatusNoContent},
+	})
+	return err
+}
+
+func (c *Client) StopMaintenance(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		route:      rep.StopMaintenanceRoute,
+		idempotent: true,
+		expected:   []int{http.StatusNoContent},
+	})
+	return err
+}
+
+func (c *Client) PlannedRestart(ctx context.Context) error {
+	_, err := c.do(ctx, request{
+		route:      rep.PlannedRestartRoute,
+		idempotent: true,
+		expected:   []int{http.StatusNoContent},
+	})
+	return err
+}
+
+// DebugConfig returns the configuration the rep runs with, with secrets
+// redacted.
+func (c *Client) DebugConfig(ctx context.Context) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	_, err := c.do(ctx, request{
+		route:      rep.DebugConfigRoute,
+		idempotent: true,
+		expected:   []int{http.StatusOK},
+		response:   &config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Fragmentation returns how fragmented the free resources of the cell are,
+// and the relocations that would defragment them.
+func (c *Client) Fragmentation(ctx context.Context) (rep.FragmentationReport, error) {
+	var report rep.FragmentationReport
+	_, err := c.do(ctx, request{
+		route:      rep.FragmentationRoute,
+		idempotent: true,
+		expected:   []int{http.StatusOK},
+		response:   &report,
+	})
+	return report, err
+}
+
+type request struct {
+	route      string
+	params     rata.Params
+	body       interface{}
+	idempotent bool
+	expected   []int
+	response   interface{}
+}
+
+func (c *Client) do(ctx context.Context, r request) (int, error) {
+	var payload []byte
+	if r.body != nil {
+		var err error
+		payload, err = json.Marshal(r.body)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	attempts := 1
+	if r.idempotent {
+		attempts = c.attempts
+	}
+
+	var statusCode int
+	var body []byte
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			err = wait(ctx, c.backoff)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		statusCode, body, err = c.send(ctx, r.route, r.params, payload)
+		if err == nil && !retryable(statusCode, r.expected) {
+			break
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if !contains(r.expected, statusCode) {
+		return statusCode, &StatusError{Route: r.route, StatusCode: statusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	if r.response != nil {
+		err = json.Unmarshal(body, r.response)
+		if err != nil {
+			return statusCode, err
+		}
+	}
+
+	return statusCode, nil
+}
+
+func (c *Client) send(ctx context.Context, route string, params rata.Params, payload []byte) (int, []byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := c.requestGenerator.CreateRequest(route, params, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	done := func(int, error) {}
+	if c.tracer != nil {
+		ctx, done = c.tracer.Trace(ctx, route, req)
+	}
+
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		done(0, err)
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	done(resp.StatusCode, err)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, respBody, nil
+}
+
+func retryable(statusCode int, expected []int) bool {
+	if contains(expected, statusCode) {
+		return false
+	}
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func contains(statusCodes []int, statusCode int) bool {
+	for _, code := range statusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func wait(ctx context.Context, backoff time.Duration) error {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
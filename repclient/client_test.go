@@ -0,0 +1,242 @@
+package repclient_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/repclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+type recordingTracer struct {
+	routes      []string
+	statusCodes []int
+}
+
+func (t *recordingTracer) Trace(ctx context.Context, route string, req *http.Request) (context.Context, func(int, error)) {
+	t.routes = append(t.routes, route)
+	req.Header.Set("X-Trace", route)
+	return ctx, func(statusCode int, err error) {
+		t.statusCodes = append(t.statusCodes, statusCode)
+	}
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fakeServer *ghttp.Server
+		ctx        context.Context
+		options    []repclient.Option
+		client     *repclient.Client
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		ctx = context.Background()
+		options = nil
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = repclient.New(fakeServer.URL(), options...)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	Describe("State", func() {
+		Context("when the cell is healthy", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/state"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, rep.CellState{CellID: "cell-id"}),
+				))
+			})
+
+			It("returns the state", func() {
+				state, healthy, err := client.State(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeTrue())
+				Expect(state.CellID).To(Equal("cell-id"))
+			})
+		})
+
+		Context("when the cell is unhealthy", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWithJSONEncoded(http.StatusServiceUnavailable, rep.CellState{CellID: "cell-id"}))
+				options = []repclient.Option{repclient.WithRetries(3, time.Millisecond)}
+			})
+
+			It("returns the state without retrying", func() {
+				state, healthy, err := client.State(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(healthy).To(BeFalse())
+				Expect(state.CellID).To(Equal("cell-id"))
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+	})
+
+	Describe("Perform", func() {
+		var work rep.Work
+
+		BeforeEach(func() {
+			work = rep.Work{Tasks: []rep.Task{{TaskGuid: "task-guid"}}}
+			options = []repclient.Option{repclient.WithRetries(3, time.Millisecond)}
+		})
+
+		It("posts the work and returns the failed work", func() {
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/work"),
+				ghttp.VerifyContentType("application/json"),
+				ghttp.VerifyJSONRepresenting(work),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, work),
+			))
+
+			failedWork, err := client.Perform(ctx, work)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedWork).To(Equal(work))
+		})
+
+		It("is not retried", func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+
+			_, err := client.Perform(ctx, work)
+			Expect(repclient.IsUnavailable(err)).To(BeTrue())
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("StopLRPInstance", func() {
+		It("stops the instance", func() {
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/lrps/process-guid/instances/instance-guid/stop"),
+				ghttp.RespondWith(http.StatusAccepted, nil),
+			))
+
+			err := client.StopLRPInstance(ctx, models.NewActualLRPKey("process-guid", 2, "domain"), models.NewActualLRPInstanceKey("instance-guid", "cell-id"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns a StatusError when the rep rejects the request", func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusBadRequest, "bad index"))
+
+			err := client.StopLRPInstance(ctx, models.NewActualLRPKey("process-guid", 2, "domain"), models.NewActualLRPInstanceKey("instance-guid", "cell-id"))
+			Expect(err).To(MatchError(&repclient.StatusError{Route: rep.StopLRPInstanceRoute, StatusCode: http.StatusBadRequest, Body: "bad index"}))
+		})
+	})
+
+	Describe("StopLRPInstances", func() {
+		It("stops the instances and returns the outcome for each of them", func() {
+			instances := []rep.StopLRPInstanceRequest{
+				rep.NewStopLRPInstanceRequest(models.NewActualLRPKey("process-guid", 2, "domain"), models.NewActualLRPInstanceKey("instance-guid", "cell-id")),
+			}
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/lrps/instances/stop"),
+				ghttp.VerifyJSONRepresenting(instances),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []rep.StopLRPInstanceResult{{ProcessGuid: "process-guid", InstanceGuid: "instance-guid"}}),
+			))
+
+			results, err := client.StopLRPInstances(ctx, instances)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]rep.StopLRPInstanceResult{{ProcessGuid: "process-guid", InstanceGuid: "instance-guid"}}))
+		})
+	})
+
+	Describe("UpdateLRPInstance", func() {
+		It("returns a StatusError that is a not found error for reps without the route", func() {
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v2/lrps/process-guid/instances/instance-guid"),
+				ghttp.RespondWith(http.StatusNotFound, nil),
+			))
+
+			err := client.UpdateLRPInstance(ctx, rep.NewLRPUpdate("instance-guid", models.NewActualLRPKey("process-guid", 0, "domain"), nil, nil))
+			Expect(repclient.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("the operator routes", func() {
+		It("reaches them", func() {
+			fakeServer.AppendHandlers(
+				ghttp.CombineHandlers(ghttp.VerifyRequest("GET", "/ping"), ghttp.RespondWith(http.StatusOK, nil)),
+				ghttp.CombineHandlers(ghttp.VerifyRequest("POST", "/maintenance"), ghttp.RespondWith(http.StatusNoContent, nil)),
+				ghttp.CombineHandlers(ghttp.VerifyRequest("DELETE", "/maintenance"), ghttp.RespondWith(http.StatusNoContent, nil)),
+				ghttp.CombineHandlers(ghttp.VerifyRequest("POST", "/evacuate"), ghttp.RespondWith(http.StatusAccepted, `{"ping_path":"/ping"}`)),
+				ghttp.CombineHandlers(ghttp.VerifyRequest("GET", "/debug/config"), ghttp.RespondWith(http.StatusOK, `{"cell_id":"cell-id"}`)),
+				ghttp.CombineHandlers(ghttp.VerifyRequest("GET", "/debug/fragmentation"), ghttp.RespondWith(http.StatusOK, `{"memory_score":0.5}`)),
+			)
+
+			Expect(client.Ping(ctx)).To(Succeed())
+			Expect(client.StartMaintenance(ctx)).To(Succeed())
+			Expect(client.StopMaintenance(ctx)).To(Succeed())
+			Expect(client.Evacuate(ctx)).To(Succeed())
+			Expect(client.DebugConfig(ctx)).To(Equal(map[string]interface{}{"cell_id": "cell-id"}))
+			Expect(client.Fragmentation(ctx)).To(Equal(rep.FragmentationReport{MemoryScore: 0.5}))
+		})
+	})
+
+	Describe("retries", func() {
+		BeforeEach(func() {
+			options = []repclient.Option{repclient.WithRetries(3, time.Millisecond)}
+		})
+
+		It("retries idempotent requests while the rep is unavailable", func() {
+			fakeServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, nil),
+				ghttp.RespondWith(http.StatusGatewayTimeout, nil),
+				ghttp.RespondWith(http.StatusOK, nil),
+			)
+
+			Expect(client.Ping(ctx)).To(Succeed())
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("gives up after the configured attempts", func() {
+			fakeServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, nil),
+				ghttp.RespondWith(http.StatusBadGateway, nil),
+				ghttp.RespondWith(http.StatusBadGateway, nil),
+			)
+
+			err := client.Ping(ctx)
+			Expect(err).To(BeAssignableToTypeOf(&repclient.StatusError{}))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("stops retrying when the context is done", func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusBadGateway, nil))
+
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			cancel()
+
+			Expect(client.Ping(ctx)).To(MatchError(ContainSubstring("context canceled")))
+		})
+	})
+
+	Describe("tracing", func() {
+		var tracer *recordingTracer
+
+		BeforeEach(func() {
+			tracer = &recordingTracer{}
+			options = []repclient.Option{repclient.WithTracer(tracer)}
+		})
+
+		It("traces every request", func() {
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("X-Trace", rep.CancelTaskRoute),
+				ghttp.RespondWith(http.StatusAccepted, nil),
+			))
+
+			Expect(client.CancelTask(ctx, "task-guid")).To(Succeed())
+			Expect(tracer.routes).To(Equal([]string{rep.CancelTaskRoute}))
+			Expect(tracer.statusCodes).To(Equal([]int{http.StatusAccepted}))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package repclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var errTransportNotConfigurable = errors.New("TLS can only be configured for clients using an *http.Transport")
+
+// StatusError is returned when the rep responds with an unexpected status
+// code.
+type StatusError struct {
+	Route      string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s: unexpected status code: %d (%s)", e.Route, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%s: unexpected status code: %d (%s): %s", e.Route, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// IsNotFound reports whether err is a StatusError for a 404 response, which
+// older reps return for routes they do not serve yet.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsUnavailable reports whether err is a StatusError for a 503 response.
+func IsUnavailable(err error) bool {
+	return hasStatusCode(err, http.StatusServiceUnavailable)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == statusCode
+}
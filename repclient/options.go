@@ -0,0 +1,79 @@
+package repclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/tlsconfig"
+)
+
+// Tracer is notified of every request the client makes. Trace is called
+// before the request is sent and the returned function once it completed,
+// with the status code of the response or the error it failed with. The
+// context returned by Trace is used for the request, so that spans can be
+// propagated.
+type Tracer interface {
+	Trace(ctx context.Context, route string, req *http.Request) (context.Context, func(statusCode int, err error))
+}
+
+// Option configures a Client.
+type Option func(*Client) error
+
+// WithHTTPClient makes the client send its requests with httpClient instead
+// of a client with a 10 second timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTLSConfig makes the client use tlsConfig for https requests.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Client) error {
+		c.tlsConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithTLSFiles makes the client present the certificate in certFile and
+// trust the certificate authority in caCertFile, as the mutual TLS listener
+// of the rep requires.
+func WithTLSFiles(certFile, keyFile, caCertFile string) Option {
+	return func(c *Client) error {
+		tlsConfig, err := tlsconfig.Build(
+			tlsconfig.WithInternalServiceDefaults(),
+			tlsconfig.WithIdentityFromFile(certFile, keyFile),
+		).Client(tlsconfig.WithAuthorityFromFile(caCertFile))
+		if err != nil {
+			return err
+		}
+		c.tlsConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithRetries retries requests that are safe to repeat up to attempts times
+// in total, waiting backoff before each retry. Requests are retried when they
+// fail to be sent or the rep responds 502, 503 or 504. Perform is never
+// retried, as it is not idempotent.
+func WithRetries(attempts int, backoff time.Duration) Option {
+	return func(c *Client) error {
+		if attempts < 1 {
+			attempts = 1
+		}
+		c.attempts = attempts
+		c.backoff = backoff
+		return nil
+	}
+}
+
+// WithTracer notifies tracer of every request.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) error {
+		c.tracer = tracer
+		return nil
+	}
+}
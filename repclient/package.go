@@ -0,0 +1,5 @@
+// Package repclient is a client for the HTTP API of the rep, for tools that
+// are not the auctioneer. Every method takes a context, requests can be
+// retried and traced through options, and failed requests return a
+// *StatusError carrying the status code of the response.
+package repclient // import "code.cloudfoundry.org/rep/repclient"
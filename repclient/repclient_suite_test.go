@@ -0,0 +1,13 @@
+package repclient_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRepclient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Repclient Suite")
+}
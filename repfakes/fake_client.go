@@ -2,6 +2,7 @@
 package repfakes
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
@@ -12,11 +13,29 @@ import (
 )
 
 type FakeClient struct {
-	CancelTaskStub        func(lager.Logger, string) error
+	CanPlaceStub        func(context.Context, lager.Logger, rep.Work, float64, string) (rep.PlacementChecks, error)
+	canPlaceMutex       sync.RWMutex
+	canPlaceArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.Work
+		arg4 float64
+		arg5 string
+	}
+	canPlaceReturns struct {
+		result1 rep.PlacementChecks
+		result2 error
+	}
+	canPlaceReturnsOnCall map[int]struct {
+		result1 rep.PlacementChecks
+		result2 error
+	}
+	CancelTaskStub        func(context.Context, lager.Logger, string) error
 	cancelTaskMutex       sync.RWMutex
 	cancelTaskArgsForCall []struct {
-		arg1 lager.Logger
-		arg2 string
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
 	}
 	cancelTaskReturns struct {
 		result1 error
@@ -24,11 +43,69 @@ type FakeClient struct {
 	cancelTaskReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PerformStub        func(lager.Logger, rep.Work) (rep.Work, error)
+	CapacitySummaryStub        func(context.Context, lager.Logger) (rep.CapacitySummary, error)
+	capacitySummaryMutex       sync.RWMutex
+	capacitySummaryArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+	}
+	capacitySummaryReturns struct {
+		result1 rep.CapacitySummary
+		result2 error
+	}
+	capacitySummaryReturnsOnCall map[int]struct {
+		result1 rep.CapacitySummary
+		result2 error
+	}
+	ContainersStub        func(context.Context, lager.Logger, string) (rep.ContainerInventory, error)
+	containersMutex       sync.RWMutex
+	containersArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+	}
+	containersReturns struct {
+		result1 rep.ContainerInventory
+		result2 error
+	}
+	containersReturnsOnCall map[int]struct {
+		result1 rep.ContainerInventory
+		result2 error
+	}
+	GrowDiskQuotaStub        func(context.Context, lager.Logger, string, int32) error
+	growDiskQuotaMutex       sync.RWMutex
+	growDiskQuotaArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+		arg4 int32
+	}
+	growDiskQuotaReturns struct {
+		result1 error
+	}
+	growDiskQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InfoStub        func(context.Context, lager.Logger) (rep.Info, error)
+	infoMutex       sync.RWMutex
+	infoArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+	}
+	infoReturns struct {
+		result1 rep.Info
+		result2 error
+	}
+	infoReturnsOnCall map[int]struct {
+		result1 rep.Info
+		result2 error
+	}
+	PerformStub        func(context.Context, lager.Logger, rep.Work) (rep.Work, error)
 	performMutex       sync.RWMutex
 	performArgsForCall []struct {
-		arg1 lager.Logger
-		arg2 rep.Work
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.Work
 	}
 	performReturns struct {
 		result1 rep.Work
@@ -38,15 +115,44 @@ type FakeClient struct {
 		result1 rep.Work
 		result2 error
 	}
+	ReleaseCapacityStub        func(context.Context, lager.Logger, string) error
+	releaseCapacityMutex       sync.RWMutex
+	releaseCapacityArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+	}
+	releaseCapacityReturns struct {
+		result1 error
+	}
+	releaseCapacityReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReserveCapacityStub        func(context.Context, lager.Logger, rep.CapacityReservationRequest) (rep.CapacityReservation, error)
+	reserveCapacityMutex       sync.RWMutex
+	reserveCapacityArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.CapacityReservationRequest
+	}
+	reserveCapacityReturns struct {
+		result1 rep.CapacityReservation
+		result2 error
+	}
+	reserveCapacityReturnsOnCall map[int]struct {
+		result1 rep.CapacityReservation
+		result2 error
+	}
 	SetStateClientStub        func(*http.Client)
 	setStateClientMutex       sync.RWMutex
 	setStateClientArgsForCall []struct {
 		arg1 *http.Client
 	}
-	StateStub        func(lager.Logger) (rep.CellState, error)
+	StateStub        func(context.Context, lager.Logger) (rep.CellState, error)
 	stateMutex       sync.RWMutex
 	stateArgsForCall []struct {
-		arg1 lager.Logger
+		arg1 context.Context
+		arg2 lager.Logger
 	}
 	stateReturns struct {
 		result1 rep.CellState
@@ -66,12 +172,31 @@ type FakeClient struct {
 	stateClientTimeoutReturnsOnCall map[int]struct {
 		result1 time.Duration
 	}
-	StopLRPInstanceStub        func(lager.Logger, models.ActualLRPKey, models.ActualLRPInstanceKey) error
+	StateSinceStub        func(context.Context, lager.Logger, rep.CellState, string) (rep.CellState, string, error)
+	stateSinceMutex       sync.RWMutex
+	stateSinceArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.CellState
+		arg4 string
+	}
+	stateSinceReturns struct {
+		result1 rep.CellState
+		result2 string
+		result3 error
+	}
+	stateSinceReturnsOnCall map[int]struct {
+		result1 rep.CellState
+		result2 string
+		result3 error
+	}
+	StopLRPInstanceStub        func(context.Context, lager.Logger, models.ActualLRPKey, models.ActualLRPInstanceKey) error
 	stopLRPInstanceMutex       sync.RWMutex
 	stopLRPInstanceArgsForCall []struct {
-		arg1 lager.Logger
-		arg2 models.ActualLRPKey
-		arg3 models.ActualLRPInstanceKey
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 models.ActualLRPKey
+		arg4 models.ActualLRPInstanceKey
 	}
 	stopLRPInstanceReturns struct {
 		result1 error
@@ -79,11 +204,27 @@ type FakeClient struct {
 	stopLRPInstanceReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateLRPInstanceStub        func(lager.Logger, rep.LRPUpdate) error
+	StopLRPInstancesStub        func(context.Context, lager.Logger, []rep.StopLRPInstanceRequest) ([]rep.StopLRPInstanceResult, error)
+	stopLRPInstancesMutex       sync.RWMutex
+	stopLRPInstancesArgsForCall []struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 []rep.StopLRPInstanceRequest
+	}
+	stopLRPInstancesReturns struct {
+		result1 []rep.StopLRPInstanceResult
+		result2 error
+	}
+	stopLRPInstancesReturnsOnCall map[int]struct {
+		result1 []rep.StopLRPInstanceResult
+		result2 error
+	}
+	UpdateLRPInstanceStub        func(context.Context, lager.Logger, rep.LRPUpdate) error
 	updateLRPInstanceMutex       sync.RWMutex
 	updateLRPInstanceArgsForCall []struct {
-		arg1 lager.Logger
-		arg2 rep.LRPUpdate
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.LRPUpdate
 	}
 	updateLRPInstanceReturns struct {
 		result1 error
@@ -95,19 +236,88 @@ type FakeClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeClient) CancelTask(arg1 lager.Logger, arg2 string) error {
+func (fake *FakeClient) CanPlace(arg1 context.Context, arg2 lager.Logger, arg3 rep.Work, arg4 float64, arg5 string) (rep.PlacementChecks, error) {
+	fake.canPlaceMutex.Lock()
+	ret, specificReturn := fake.canPlaceReturnsOnCall[len(fake.canPlaceArgsForCall)]
+	fake.canPlaceArgsForCall = append(fake.canPlaceArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.Work
+		arg4 float64
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.CanPlaceStub
+	fakeReturns := fake.canPlaceReturns
+	fake.recordInvocation("CanPlace", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.canPlaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CanPlaceCallCount() int {
+	fake.canPlaceMutex.RLock()
+	defer fake.canPlaceMutex.RUnlock()
+	return len(fake.canPlaceArgsForCall)
+}
+
+func (fake *FakeClient) CanPlaceCalls(stub func(context.Context, lager.Logger, rep.Work, float64, string) (rep.PlacementChecks, error)) {
+	fake.canPlaceMutex.Lock()
+	defer fake.canPlaceMutex.Unlock()
+	fake.CanPlaceStub = stub
+}
+
+func (fake *FakeClient) CanPlaceArgsForCall(i int) (context.Context, lager.Logger, rep.Work, float64, string) {
+	fake.canPlaceMutex.RLock()
+	defer fake.canPlaceMutex.RUnlock()
+	argsForCall := fake.canPlaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeClient) CanPlaceReturns(result1 rep.PlacementChecks, result2 error) {
+	fake.canPlaceMutex.Lock()
+	defer fake.canPlaceMutex.Unlock()
+	fake.CanPlaceStub = nil
+	fake.canPlaceReturns = struct {
+		result1 rep.PlacementChecks
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CanPlaceReturnsOnCall(i int, result1 rep.PlacementChecks, result2 error) {
+	fake.canPlaceMutex.Lock()
+	defer fake.canPlaceMutex.Unlock()
+	fake.CanPlaceStub = nil
+	if fake.canPlaceReturnsOnCall == nil {
+		fake.canPlaceReturnsOnCall = make(map[int]struct {
+			result1 rep.PlacementChecks
+			result2 error
+		})
+	}
+	fake.canPlaceReturnsOnCall[i] = struct {
+		result1 rep.PlacementChecks
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CancelTask(arg1 context.Context, arg2 lager.Logger, arg3 string) error {
 	fake.cancelTaskMutex.Lock()
 	ret, specificReturn := fake.cancelTaskReturnsOnCall[len(fake.cancelTaskArgsForCall)]
 	fake.cancelTaskArgsForCall = append(fake.cancelTaskArgsForCall, struct {
-		arg1 lager.Logger
-		arg2 string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.CancelTaskStub
 	fakeReturns := fake.cancelTaskReturns
-	fake.recordInvocation("CancelTask", []interface{}{arg1, arg2})
+	fake.recordInvocation("CancelTask", []interface{}{arg1, arg2, arg3})
 	fake.cancelTaskMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -121,17 +331,17 @@ func (fake *FakeClient) CancelTaskCallCount() int {
 	return len(fake.cancelTaskArgsForCall)
 }
 
-func (fake *FakeClient) CancelTaskCalls(stub func(lager.Logger, string) error) {
+func (fake *FakeClient) CancelTaskCalls(stub func(context.Context, lager.Logger, string) error) {
 	fake.cancelTaskMutex.Lock()
 	defer fake.cancelTaskMutex.Unlock()
 	fake.CancelTaskStub = stub
 }
 
-func (fake *FakeClient) CancelTaskArgsForCall(i int) (lager.Logger, string) {
+func (fake *FakeClient) CancelTaskArgsForCall(i int) (context.Context, lager.Logger, string) {
 	fake.cancelTaskMutex.RLock()
 	defer fake.cancelTaskMutex.RUnlock()
 	argsForCall := fake.cancelTaskArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeClient) CancelTaskReturns(result1 error) {
@@ -157,19 +367,280 @@ func (fake *FakeClient) CancelTaskReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeClient) Perform(arg1 lager.Logger, arg2 rep.Work) (rep.Work, error) {
+func (fake *FakeClient) CapacitySummary(arg1 context.Context, arg2 lager.Logger) (rep.CapacitySummary, error) {
+	fake.capacitySummaryMutex.Lock()
+	ret, specificReturn := fake.capacitySummaryReturnsOnCall[len(fake.capacitySummaryArgsForCall)]
+	fake.capacitySummaryArgsForCall = append(fake.capacitySummaryArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+	}{arg1, arg2})
+	stub := fake.CapacitySummaryStub
+	fakeReturns := fake.capacitySummaryReturns
+	fake.recordInvocation("CapacitySummary", []interface{}{arg1, arg2})
+	fake.capacitySummaryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CapacitySummaryCallCount() int {
+	fake.capacitySummaryMutex.RLock()
+	defer fake.capacitySummaryMutex.RUnlock()
+	return len(fake.capacitySummaryArgsForCall)
+}
+
+func (fake *FakeClient) CapacitySummaryCalls(stub func(context.Context, lager.Logger) (rep.CapacitySummary, error)) {
+	fake.capacitySummaryMutex.Lock()
+	defer fake.capacitySummaryMutex.Unlock()
+	fake.CapacitySummaryStub = stub
+}
+
+func (fake *FakeClient) CapacitySummaryArgsForCall(i int) (context.Context, lager.Logger) {
+	fake.capacitySummaryMutex.RLock()
+	defer fake.capacitySummaryMutex.RUnlock()
+	argsForCall := fake.capacitySummaryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CapacitySummaryReturns(result1 rep.CapacitySummary, result2 error) {
+	fake.capacitySummaryMutex.Lock()
+	defer fake.capacitySummaryMutex.Unlock()
+	fake.CapacitySummaryStub = nil
+	fake.capacitySummaryReturns = struct {
+		result1 rep.CapacitySummary
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CapacitySummaryReturnsOnCall(i int, result1 rep.CapacitySummary, result2 error) {
+	fake.capacitySummaryMutex.Lock()
+	defer fake.capacitySummaryMutex.Unlock()
+	fake.CapacitySummaryStub = nil
+	if fake.capacitySummaryReturnsOnCall == nil {
+		fake.capacitySummaryReturnsOnCall = make(map[int]struct {
+			result1 rep.CapacitySummary
+			result2 error
+		})
+	}
+	fake.capacitySummaryReturnsOnCall[i] = struct {
+		result1 rep.CapacitySummary
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Containers(arg1 context.Context, arg2 lager.Logger, arg3 string) (rep.ContainerInventory, error) {
+	fake.containersMutex.Lock()
+	ret, specificReturn := fake.containersReturnsOnCall[len(fake.containersArgsForCall)]
+	fake.containersArgsForCall = append(fake.containersArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ContainersStub
+	fakeReturns := fake.containersReturns
+	fake.recordInvocation("Containers", []interface{}{arg1, arg2, arg3})
+	fake.containersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ContainersCallCount() int {
+	fake.containersMutex.RLock()
+	defer fake.containersMutex.RUnlock()
+	return len(fake.containersArgsForCall)
+}
+
+func (fake *FakeClient) ContainersCalls(stub func(context.Context, lager.Logger, string) (rep.ContainerInventory, error)) {
+	fake.containersMutex.Lock()
+	defer fake.containersMutex.Unlock()
+	fake.ContainersStub = stub
+}
+
+func (fake *FakeClient) ContainersArgsForCall(i int) (context.Context, lager.Logger, string) {
+	fake.containersMutex.RLock()
+	defer fake.containersMutex.RUnlock()
+	argsForCall := fake.containersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) ContainersReturns(result1 rep.ContainerInventory, result2 error) {
+	fake.containersMutex.Lock()
+	defer fake.containersMutex.Unlock()
+	fake.ContainersStub = nil
+	fake.containersReturns = struct {
+		result1 rep.ContainerInventory
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ContainersReturnsOnCall(i int, result1 rep.ContainerInventory, result2 error) {
+	fake.containersMutex.Lock()
+	defer fake.containersMutex.Unlock()
+	fake.ContainersStub = nil
+	if fake.containersReturnsOnCall == nil {
+		fake.containersReturnsOnCall = make(map[int]struct {
+			result1 rep.ContainerInventory
+			result2 error
+		})
+	}
+	fake.containersReturnsOnCall[i] = struct {
+		result1 rep.ContainerInventory
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GrowDiskQuota(arg1 context.Context, arg2 lager.Logger, arg3 string, arg4 int32) error {
+	fake.growDiskQuotaMutex.Lock()
+	ret, specificReturn := fake.growDiskQuotaReturnsOnCall[len(fake.growDiskQuotaArgsForCall)]
+	fake.growDiskQuotaArgsForCall = append(fake.growDiskQuotaArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+		arg4 int32
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GrowDiskQuotaStub
+	fakeReturns := fake.growDiskQuotaReturns
+	fake.recordInvocation("GrowDiskQuota", []interface{}{arg1, arg2, arg3, arg4})
+	fake.growDiskQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) GrowDiskQuotaCallCount() int {
+	fake.growDiskQuotaMutex.RLock()
+	defer fake.growDiskQuotaMutex.RUnlock()
+	return len(fake.growDiskQuotaArgsForCall)
+}
+
+func (fake *FakeClient) GrowDiskQuotaCalls(stub func(context.Context, lager.Logger, string, int32) error) {
+	fake.growDiskQuotaMutex.Lock()
+	defer fake.growDiskQuotaMutex.Unlock()
+	fake.GrowDiskQuotaStub = stub
+}
+
+func (fake *FakeClient) GrowDiskQuotaArgsForCall(i int) (context.Context, lager.Logger, string, int32) {
+	fake.growDiskQuotaMutex.RLock()
+	defer fake.growDiskQuotaMutex.RUnlock()
+	argsForCall := fake.growDiskQuotaArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeClient) GrowDiskQuotaReturns(result1 error) {
+	fake.growDiskQuotaMutex.Lock()
+	defer fake.growDiskQuotaMutex.Unlock()
+	fake.GrowDiskQuotaStub = nil
+	fake.growDiskQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GrowDiskQuotaReturnsOnCall(i int, result1 error) {
+	fake.growDiskQuotaMutex.Lock()
+	defer fake.growDiskQuotaMutex.Unlock()
+	fake.GrowDiskQuotaStub = nil
+	if fake.growDiskQuotaReturnsOnCall == nil {
+		fake.growDiskQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.growDiskQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) Info(arg1 context.Context, arg2 lager.Logger) (rep.Info, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+	}{arg1, arg2})
+	stub := fake.InfoStub
+	fakeReturns := fake.infoReturns
+	fake.recordInvocation("Info", []interface{}{arg1, arg2})
+	fake.infoMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *FakeClient) InfoCalls(stub func(context.Context, lager.Logger) (rep.Info, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *FakeClient) InfoArgsForCall(i int) (context.Context, lager.Logger) {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	argsForCall := fake.infoArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) InfoReturns(result1 rep.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 rep.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) InfoReturnsOnCall(i int, result1 rep.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 rep.Info
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 rep.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Perform(arg1 context.Context, arg2 lager.Logger, arg3 rep.Work) (rep.Work, error) {
 	fake.performMutex.Lock()
 	ret, specificReturn := fake.performReturnsOnCall[len(fake.performArgsForCall)]
 	fake.performArgsForCall = append(fake.performArgsForCall, struct {
-		arg1 lager.Logger
-		arg2 rep.Work
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.Work
+	}{arg1, arg2, arg3})
 	stub := fake.PerformStub
 	fakeReturns := fake.performReturns
-	fake.recordInvocation("Perform", []interface{}{arg1, arg2})
+	fake.recordInvocation("Perform", []interface{}{arg1, arg2, arg3})
 	fake.performMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -183,17 +654,17 @@ func (fake *FakeClient) PerformCallCount() int {
 	return len(fake.performArgsForCall)
 }
 
-func (fake *FakeClient) PerformCalls(stub func(lager.Logger, rep.Work) (rep.Work, error)) {
+func (fake *FakeClient) PerformCalls(stub func(context.Context, lager.Logger, rep.Work) (rep.Work, error)) {
 	fake.performMutex.Lock()
 	defer fake.performMutex.Unlock()
 	fake.PerformStub = stub
 }
 
-func (fake *FakeClient) PerformArgsForCall(i int) (lager.Logger, rep.Work) {
+func (fake *FakeClient) PerformArgsForCall(i int) (context.Context, lager.Logger, rep.Work) {
 	fake.performMutex.RLock()
 	defer fake.performMutex.RUnlock()
 	argsForCall := fake.performArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeClient) PerformReturns(result1 rep.Work, result2 error) {
@@ -222,6 +693,135 @@ func (fake *FakeClient) PerformReturnsOnCall(i int, result1 rep.Work, result2 er
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ReleaseCapacity(arg1 context.Context, arg2 lager.Logger, arg3 string) error {
+	fake.releaseCapacityMutex.Lock()
+	ret, specificReturn := fake.releaseCapacityReturnsOnCall[len(fake.releaseCapacityArgsForCall)]
+	fake.releaseCapacityArgsForCall = append(fake.releaseCapacityArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ReleaseCapacityStub
+	fakeReturns := fake.releaseCapacityReturns
+	fake.recordInvocation("ReleaseCapacity", []interface{}{arg1, arg2, arg3})
+	fake.releaseCapacityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) ReleaseCapacityCallCount() int {
+	fake.releaseCapacityMutex.RLock()
+	defer fake.releaseCapacityMutex.RUnlock()
+	return len(fake.releaseCapacityArgsForCall)
+}
+
+func (fake *FakeClient) ReleaseCapacityCalls(stub func(context.Context, lager.Logger, string) error) {
+	fake.releaseCapacityMutex.Lock()
+	defer fake.releaseCapacityMutex.Unlock()
+	fake.ReleaseCapacityStub = stub
+}
+
+func (fake *FakeClient) ReleaseCapacityArgsForCall(i int) (context.Context, lager.Logger, string) {
+	fake.releaseCapacityMutex.RLock()
+	defer fake.releaseCapacityMutex.RUnlock()
+	argsForCall := fake.releaseCapacityArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) ReleaseCapacityReturns(result1 error) {
+	fake.releaseCapacityMutex.Lock()
+	defer fake.releaseCapacityMutex.Unlock()
+	fake.ReleaseCapacityStub = nil
+	fake.releaseCapacityReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ReleaseCapacityReturnsOnCall(i int, result1 error) {
+	fake.releaseCapacityMutex.Lock()
+	defer fake.releaseCapacityMutex.Unlock()
+	fake.ReleaseCapacityStub = nil
+	if fake.releaseCapacityReturnsOnCall == nil {
+		fake.releaseCapacityReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseCapacityReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ReserveCapacity(arg1 context.Context, arg2 lager.Logger, arg3 rep.CapacityReservationRequest) (rep.CapacityReservation, error) {
+	fake.reserveCapacityMutex.Lock()
+	ret, specificReturn := fake.reserveCapacityReturnsOnCall[len(fake.reserveCapacityArgsForCall)]
+	fake.reserveCapacityArgsForCall = append(fake.reserveCapacityArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.CapacityReservationRequest
+	}{arg1, arg2, arg3})
+	stub := fake.ReserveCapacityStub
+	fakeReturns := fake.reserveCapacityReturns
+	fake.recordInvocation("ReserveCapacity", []interface{}{arg1, arg2, arg3})
+	fake.reserveCapacityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ReserveCapacityCallCount() int {
+	fake.reserveCapacityMutex.RLock()
+	defer fake.reserveCapacityMutex.RUnlock()
+	return len(fake.reserveCapacityArgsForCall)
+}
+
+func (fake *FakeClient) ReserveCapacityCalls(stub func(context.Context, lager.Logger, rep.CapacityReservationRequest) (rep.CapacityReservation, error)) {
+	fake.reserveCapacityMutex.Lock()
+	defer fake.reserveCapacityMutex.Unlock()
+	fake.ReserveCapacityStub = stub
+}
+
+func (fake *FakeClient) ReserveCapacityArgsForCall(i int) (context.Context, lager.Logger, rep.CapacityReservationRequest) {
+	fake.reserveCapacityMutex.RLock()
+	defer fake.reserveCapacityMutex.RUnlock()
+	argsForCall := fake.reserveCapacityArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) ReserveCapacityReturns(result1 rep.CapacityReservation, result2 error) {
+	fake.reserveCapacityMutex.Lock()
+	defer fake.reserveCapacityMutex.Unlock()
+	fake.ReserveCapacityStub = nil
+	fake.reserveCapacityReturns = struct {
+		result1 rep.CapacityReservation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ReserveCapacityReturnsOnCall(i int, result1 rep.CapacityReservation, result2 error) {
+	fake.reserveCapacityMutex.Lock()
+	defer fake.reserveCapacityMutex.Unlock()
+	fake.ReserveCapacityStub = nil
+	if fake.reserveCapacityReturnsOnCall == nil {
+		fake.reserveCapacityReturnsOnCall = make(map[int]struct {
+			result1 rep.CapacityReservation
+			result2 error
+		})
+	}
+	fake.reserveCapacityReturnsOnCall[i] = struct {
+		result1 rep.CapacityReservation
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) SetStateClient(arg1 *http.Client) {
 	fake.setStateClientMutex.Lock()
 	fake.setStateClientArgsForCall = append(fake.setStateClientArgsForCall, struct {
@@ -254,18 +854,19 @@ func (fake *FakeClient) SetStateClientArgsForCall(i int) *http.Client {
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) State(arg1 lager.Logger) (rep.CellState, error) {
+func (fake *FakeClient) State(arg1 context.Context, arg2 lager.Logger) (rep.CellState, error) {
 	fake.stateMutex.Lock()
 	ret, specificReturn := fake.stateReturnsOnCall[len(fake.stateArgsForCall)]
 	fake.stateArgsForCall = append(fake.stateArgsForCall, struct {
-		arg1 lager.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 lager.Logger
+	}{arg1, arg2})
 	stub := fake.StateStub
 	fakeReturns := fake.stateReturns
-	fake.recordInvocation("State", []interface{}{arg1})
+	fake.recordInvocation("State", []interface{}{arg1, arg2})
 	fake.stateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -279,17 +880,17 @@ func (fake *FakeClient) StateCallCount() int {
 	return len(fake.stateArgsForCall)
 }
 
-func (fake *FakeClient) StateCalls(stub func(lager.Logger) (rep.CellState, error)) {
+func (fake *FakeClient) StateCalls(stub func(context.Context, lager.Logger) (rep.CellState, error)) {
 	fake.stateMutex.Lock()
 	defer fake.stateMutex.Unlock()
 	fake.StateStub = stub
 }
 
-func (fake *FakeClient) StateArgsForCall(i int) lager.Logger {
+func (fake *FakeClient) StateArgsForCall(i int) (context.Context, lager.Logger) {
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
 	argsForCall := fake.stateArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeClient) StateReturns(result1 rep.CellState, result2 error) {
@@ -371,20 +972,91 @@ func (fake *FakeClient) StateClientTimeoutReturnsOnCall(i int, result1 time.Dura
 	}{result1}
 }
 
-func (fake *FakeClient) StopLRPInstance(arg1 lager.Logger, arg2 models.ActualLRPKey, arg3 models.ActualLRPInstanceKey) error {
+func (fake *FakeClient) StateSince(arg1 context.Context, arg2 lager.Logger, arg3 rep.CellState, arg4 string) (rep.CellState, string, error) {
+	fake.stateSinceMutex.Lock()
+	ret, specificReturn := fake.stateSinceReturnsOnCall[len(fake.stateSinceArgsForCall)]
+	fake.stateSinceArgsForCall = append(fake.stateSinceArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.CellState
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.StateSinceStub
+	fakeReturns := fake.stateSinceReturns
+	fake.recordInvocation("StateSince", []interface{}{arg1, arg2, arg3, arg4})
+	fake.stateSinceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeClient) StateSinceCallCount() int {
+	fake.stateSinceMutex.RLock()
+	defer fake.stateSinceMutex.RUnlock()
+	return len(fake.stateSinceArgsForCall)
+}
+
+func (fake *FakeClient) StateSinceCalls(stub func(context.Context, lager.Logger, rep.CellState, string) (rep.CellState, string, error)) {
+	fake.stateSinceMutex.Lock()
+	defer fake.stateSinceMutex.Unlock()
+	fake.StateSinceStub = stub
+}
+
+func (fake *FakeClient) StateSinceArgsForCall(i int) (context.Context, lager.Logger, rep.CellState, string) {
+	fake.stateSinceMutex.RLock()
+	defer fake.stateSinceMutex.RUnlock()
+	argsForCall := fake.stateSinceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeClient) StateSinceReturns(result1 rep.CellState, result2 string, result3 error) {
+	fake.stateSinceMutex.Lock()
+	defer fake.stateSinceMutex.Unlock()
+	fake.StateSinceStub = nil
+	fake.stateSinceReturns = struct {
+		result1 rep.CellState
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) StateSinceReturnsOnCall(i int, result1 rep.CellState, result2 string, result3 error) {
+	fake.stateSinceMutex.Lock()
+	defer fake.stateSinceMutex.Unlock()
+	fake.StateSinceStub = nil
+	if fake.stateSinceReturnsOnCall == nil {
+		fake.stateSinceReturnsOnCall = make(map[int]struct {
+			result1 rep.CellState
+			result2 string
+			result3 error
+		})
+	}
+	fake.stateSinceReturnsOnCall[i] = struct {
+		result1 rep.CellState
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) StopLRPInstance(arg1 context.Context, arg2 lager.Logger, arg3 models.ActualLRPKey, arg4 models.ActualLRPInstanceKey) error {
 	fake.stopLRPInstanceMutex.Lock()
 	ret, specificReturn := fake.stopLRPInstanceReturnsOnCall[len(fake.stopLRPInstanceArgsForCall)]
 	fake.stopLRPInstanceArgsForCall = append(fake.stopLRPInstanceArgsForCall, struct {
-		arg1 lager.Logger
-		arg2 models.ActualLRPKey
-		arg3 models.ActualLRPInstanceKey
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 models.ActualLRPKey
+		arg4 models.ActualLRPInstanceKey
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.StopLRPInstanceStub
 	fakeReturns := fake.stopLRPInstanceReturns
-	fake.recordInvocation("StopLRPInstance", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("StopLRPInstance", []interface{}{arg1, arg2, arg3, arg4})
 	fake.stopLRPInstanceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -398,17 +1070,17 @@ func (fake *FakeClient) StopLRPInstanceCallCount() int {
 	return len(fake.stopLRPInstanceArgsForCall)
 }
 
-func (fake *FakeClient) StopLRPInstanceCalls(stub func(lager.Logger, models.ActualLRPKey, models.ActualLRPInstanceKey) error) {
+func (fake *FakeClient) StopLRPInstanceCalls(stub func(context.Context, lager.Logger, models.ActualLRPKey, models.ActualLRPInstanceKey) error) {
 	fake.stopLRPInstanceMutex.Lock()
 	defer fake.stopLRPInstanceMutex.Unlock()
 	fake.StopLRPInstanceStub = stub
 }
 
-func (fake *FakeClient) StopLRPInstanceArgsForCall(i int) (lager.Logger, models.ActualLRPKey, models.ActualLRPInstanceKey) {
+func (fake *FakeClient) StopLRPInstanceArgsForCall(i int) (context.Context, lager.Logger, models.ActualLRPKey, models.ActualLRPInstanceKey) {
 	fake.stopLRPInstanceMutex.RLock()
 	defer fake.stopLRPInstanceMutex.RUnlock()
 	argsForCall := fake.stopLRPInstanceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeClient) StopLRPInstanceReturns(result1 error) {
@@ -434,19 +1106,91 @@ func (fake *FakeClient) StopLRPInstanceReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeClient) UpdateLRPInstance(arg1 lager.Logger, arg2 rep.LRPUpdate) error {
+func (fake *FakeClient) StopLRPInstances(arg1 context.Context, arg2 lager.Logger, arg3 []rep.StopLRPInstanceRequest) ([]rep.StopLRPInstanceResult, error) {
+	var arg3Copy []rep.StopLRPInstanceRequest
+	if arg3 != nil {
+		arg3Copy = make([]rep.StopLRPInstanceRequest, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.stopLRPInstancesMutex.Lock()
+	ret, specificReturn := fake.stopLRPInstancesReturnsOnCall[len(fake.stopLRPInstancesArgsForCall)]
+	fake.stopLRPInstancesArgsForCall = append(fake.stopLRPInstancesArgsForCall, struct {
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 []rep.StopLRPInstanceRequest
+	}{arg1, arg2, arg3Copy})
+	stub := fake.StopLRPInstancesStub
+	fakeReturns := fake.stopLRPInstancesReturns
+	fake.recordInvocation("StopLRPInstances", []interface{}{arg1, arg2, arg3Copy})
+	fake.stopLRPInstancesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) StopLRPInstancesCallCount() int {
+	fake.stopLRPInstancesMutex.RLock()
+	defer fake.stopLRPInstancesMutex.RUnlock()
+	return len(fake.stopLRPInstancesArgsForCall)
+}
+
+func (fake *FakeClient) StopLRPInstancesCalls(stub func(context.Context, lager.Logger, []rep.StopLRPInstanceRequest) ([]rep.StopLRPInstanceResult, error)) {
+	fake.stopLRPInstancesMutex.Lock()
+	defer fake.stopLRPInstancesMutex.Unlock()
+	fake.StopLRPInstancesStub = stub
+}
+
+func (fake *FakeClient) StopLRPInstancesArgsForCall(i int) (context.Context, lager.Logger, []rep.StopLRPInstanceRequest) {
+	fake.stopLRPInstancesMutex.RLock()
+	defer fake.stopLRPInstancesMutex.RUnlock()
+	argsForCall := fake.stopLRPInstancesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) StopLRPInstancesReturns(result1 []rep.StopLRPInstanceResult, result2 error) {
+	fake.stopLRPInstancesMutex.Lock()
+	defer fake.stopLRPInstancesMutex.Unlock()
+	fake.StopLRPInstancesStub = nil
+	fake.stopLRPInstancesReturns = struct {
+		result1 []rep.StopLRPInstanceResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StopLRPInstancesReturnsOnCall(i int, result1 []rep.StopLRPInstanceResult, result2 error) {
+	fake.stopLRPInstancesMutex.Lock()
+	defer fake.stopLRPInstancesMutex.Unlock()
+	fake.StopLRPInstancesStub = nil
+	if fake.stopLRPInstancesReturnsOnCall == nil {
+		fake.stopLRPInstancesReturnsOnCall = make(map[int]struct {
+			result1 []rep.StopLRPInstanceResult
+			result2 error
+		})
+	}
+	fake.stopLRPInstancesReturnsOnCall[i] = struct {
+		result1 []rep.StopLRPInstanceResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpdateLRPInstance(arg1 context.Context, arg2 lager.Logger, arg3 rep.LRPUpdate) error {
 	fake.updateLRPInstanceMutex.Lock()
 	ret, specificReturn := fake.updateLRPInstanceReturnsOnCall[len(fake.updateLRPInstanceArgsForCall)]
 	fake.updateLRPInstanceArgsForCall = append(fake.updateLRPInstanceArgsForCall, struct {
-		arg1 lager.Logger
-		arg2 rep.LRPUpdate
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 lager.Logger
+		arg3 rep.LRPUpdate
+	}{arg1, arg2, arg3})
 	stub := fake.UpdateLRPInstanceStub
 	fakeReturns := fake.updateLRPInstanceReturns
-	fake.recordInvocation("UpdateLRPInstance", []interface{}{arg1, arg2})
+	fake.recordInvocation("UpdateLRPInstance", []interface{}{arg1, arg2, arg3})
 	fake.updateLRPInstanceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -460,17 +1204,17 @@ func (fake *FakeClient) UpdateLRPInstanceCallCount() int {
 	return len(fake.updateLRPInstanceArgsForCall)
 }
 
-func (fake *FakeClient) UpdateLRPInstanceCalls(stub func(lager.Logger, rep.LRPUpdate) error) {
+func (fake *FakeClient) UpdateLRPInstanceCalls(stub func(context.Context, lager.Logger, rep.LRPUpdate) error) {
 	fake.updateLRPInstanceMutex.Lock()
 	defer fake.updateLRPInstanceMutex.Unlock()
 	fake.UpdateLRPInstanceStub = stub
 }
 
-func (fake *FakeClient) UpdateLRPInstanceArgsForCall(i int) (lager.Logger, rep.LRPUpdate) {
+func (fake *FakeClient) UpdateLRPInstanceArgsForCall(i int) (context.Context, lager.Logger, rep.LRPUpdate) {
 	fake.updateLRPInstanceMutex.RLock()
 	defer fake.updateLRPInstanceMutex.RUnlock()
 	argsForCall := fake.updateLRPInstanceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeClient) UpdateLRPInstanceReturns(result1 error) {
@@ -499,18 +1243,36 @@ func (fake *FakeClient) UpdateLRPInstanceReturnsOnCall(i int, result1 error) {
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.canPlaceMutex.RLock()
+	defer fake.canPlaceMutex.RUnlock()
 	fake.cancelTaskMutex.RLock()
 	defer fake.cancelTaskMutex.RUnlock()
+	fake.capacitySummaryMutex.RLock()
+	defer fake.capacitySummaryMutex.RUnlock()
+	fake.containersMutex.RLock()
+	defer fake.containersMutex.RUnlock()
+	fake.growDiskQuotaMutex.RLock()
+	defer fake.growDiskQuotaMutex.RUnlock()
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
 	fake.performMutex.RLock()
 	defer fake.performMutex.RUnlock()
+	fake.releaseCapacityMutex.RLock()
+	defer fake.releaseCapacityMutex.RUnlock()
+	fake.reserveCapacityMutex.RLock()
+	defer fake.reserveCapacityMutex.RUnlock()
 	fake.setStateClientMutex.RLock()
 	defer fake.setStateClientMutex.RUnlock()
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
 	fake.stateClientTimeoutMutex.RLock()
 	defer fake.stateClientTimeoutMutex.RUnlock()
+	fake.stateSinceMutex.RLock()
+	defer fake.stateSinceMutex.RUnlock()
 	fake.stopLRPInstanceMutex.RLock()
 	defer fake.stopLRPInstanceMutex.RUnlock()
+	fake.stopLRPInstancesMutex.RLock()
+	defer fake.stopLRPInstancesMutex.RUnlock()
 	fake.updateLRPInstanceMutex.RLock()
 	defer fake.updateLRPInstanceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,20 @@
+package repfakes
+
+import "code.cloudfoundry.org/rep/evacuation/evacuation_context"
+
+var _ evacuation_context.EvacuationReporter = &StubEvacuationReporter{}
+
+// StubEvacuationReporter is a hand-rolled
+// evacuation_context.EvacuationReporter that reports the cell evacuating when
+// EvacuatingFunc returns true. Without EvacuatingFunc the cell is not
+// evacuating.
+type StubEvacuationReporter struct {
+	EvacuatingFunc func() bool
+}
+
+func (s *StubEvacuationReporter) Evacuating() bool {
+	if s.EvacuatingFunc == nil {
+		return false
+	}
+	return s.EvacuatingFunc()
+}
@@ -0,0 +1,50 @@
+package repfakes
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+var _ auctioncellrep.AuctionCellClient = &StubLocalRep{}
+
+// StubLocalRep is a hand-rolled auctioncellrep.AuctionCellClient for tests
+// that do not use counterfeiter. Each method calls the function field of the
+// same name when it is set, and otherwise returns the zero values, so that a
+// StubLocalRep{} reports an empty cell and accepts all work.
+type StubLocalRep struct {
+	StateFunc           func(ctx context.Context, logger lager.Logger) (rep.CellState, bool, error)
+	CapacitySummaryFunc func(ctx context.Context, logger lager.Logger) (rep.CapacitySummary, bool, error)
+	PerformFunc         func(ctx context.Context, logger lager.Logger, work rep.Work) (rep.Work, error)
+	ResetFunc           func(ctx context.Context) error
+}
+
+func (s *StubLocalRep) State(ctx context.Context, logger lager.Logger) (rep.CellState, bool, error) {
+	if s.StateFunc == nil {
+		return rep.CellState{}, true, nil
+	}
+	return s.StateFunc(ctx, logger)
+}
+
+func (s *StubLocalRep) CapacitySummary(ctx context.Context, logger lager.Logger) (rep.CapacitySummary, bool, error) {
+	if s.CapacitySummaryFunc == nil {
+		return rep.CapacitySummary{}, true, nil
+	}
+	return s.CapacitySummaryFunc(ctx, logger)
+}
+
+func (s *StubLocalRep) Perform(ctx context.Context, logger lager.Logger, work rep.Work) (rep.Work, error) {
+	if s.PerformFunc == nil {
+		return rep.Work{}, nil
+	}
+	return s.PerformFunc(ctx, logger, work)
+}
+
+func (s *StubLocalRep) Reset(ctx context.Context) error {
+	if s.ResetFunc == nil {
+		return nil
+	}
+	return s.ResetFunc(ctx)
+}
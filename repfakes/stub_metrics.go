@@ -0,0 +1,75 @@
+package repfakes
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/executor/containermetrics"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+)
+
+var (
+	_ helpers.RequestMetrics       = &StubRequestMetrics{}
+	_ rep.ContainerMetricsProvider = &StubContainerMetricsProvider{}
+)
+
+// StubRequestMetrics is a hand-rolled helpers.RequestMetrics for tests that
+// do not use counterfeiter. Each method calls the function field of the same
+// name when it is set and does nothing otherwise.
+type StubRequestMetrics struct {
+	IncrementRequestsStartedCounterFunc   func(requestType string, delta int)
+	IncrementRequestsSucceededCounterFunc func(requestType string, delta int)
+	IncrementRequestsFailedCounterFunc    func(requestType string, delta int)
+	IncrementRequestsInFlightCounterFunc  func(requestType string, delta int)
+	DecrementRequestsInFlightCounterFunc  func(requestType string, delta int)
+	UpdateLatencyFunc                     func(requestType string, dur time.Duration)
+}
+
+func (s *StubRequestMetrics) IncrementRequestsStartedCounter(requestType string, delta int) {
+	if s.IncrementRequestsStartedCounterFunc != nil {
+		s.IncrementRequestsStartedCounterFunc(requestType, delta)
+	}
+}
+
+func (s *StubRequestMetrics) IncrementRequestsSucceededCounter(requestType string, delta int) {
+	if s.IncrementRequestsSucceededCounterFunc != nil {
+		s.IncrementRequestsSucceededCounterFunc(requestType, delta)
+	}
+}
+
+func (s *StubRequestMetrics) IncrementRequestsFailedCounter(requestType string, delta int) {
+	if s.IncrementRequestsFailedCounterFunc != nil {
+		s.IncrementRequestsFailedCounterFunc(requestType, delta)
+	}
+}
+
+func (s *StubRequestMetrics) IncrementRequestsInFlightCounter(requestType string, delta int) {
+	if s.IncrementRequestsInFlightCounterFunc != nil {
+		s.IncrementRequestsInFlightCounterFunc(requestType, delta)
+	}
+}
+
+func (s *StubRequestMetrics) DecrementRequestsInFlightCounter(requestType string, delta int) {
+	if s.DecrementRequestsInFlightCounterFunc != nil {
+		s.DecrementRequestsInFlightCounterFunc(requestType, delta)
+	}
+}
+
+func (s *StubRequestMetrics) UpdateLatency(requestType string, dur time.Duration) {
+	if s.UpdateLatencyFunc != nil {
+		s.UpdateLatencyFunc(requestType, dur)
+	}
+}
+
+// StubContainerMetricsProvider is a hand-rolled rep.ContainerMetricsProvider
+// that returns the metrics of MetricsFunc, or none without it.
+type StubContainerMetricsProvider struct {
+	MetricsFunc func() map[string]*containermetrics.CachedContainerMetrics
+}
+
+func (s *StubContainerMetricsProvider) Metrics() map[string]*containermetrics.CachedContainerMetrics {
+	if s.MetricsFunc == nil {
+		return nil
+	}
+	return s.MetricsFunc()
+}
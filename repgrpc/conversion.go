@@ -0,0 +1,182 @@
+package repgrpc
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+)
+
+// cellStateMessage returns the message of state. The rootfs providers are
+// encoded in their JSON form, as the binary cell state snapshot does, since
+// the types of providers are registered at run time.
+func cellStateMessage(state rep.CellState) (*CellState, error) {
+	rootFSProviders, err := json.Marshal(state.RootFSProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CellState{
+		RepUrl:                  state.RepURL,
+		CellId:                  state.CellID,
+		CellIndex:               int32(state.CellIndex),
+		RootFsProviders:         rootFSProviders,
+		AvailableResources:      resourcesMessage(state.AvailableResources),
+		TotalResources:          resourcesMessage(state.TotalResources),
+		Lrps:                    lrpMessages(state.LRPs),
+		Tasks:                   taskMessages(state.Tasks),
+		StartingContainerCount:  int32(state.StartingContainerCount),
+		Zone:                    state.Zone,
+		InstanceId:              state.InstanceID,
+		InstanceType:            state.InstanceType,
+		OsFamily:                state.OSFamily,
+		Evacuating:              state.Evacuating,
+		Maintenance:             state.Maintenance,
+		Cordoned:                state.Cordoned,
+		VolumeDrivers:           state.VolumeDrivers,
+		PlacementTags:           state.PlacementTags,
+		OptionalPlacementTags:   state.OptionalPlacementTags,
+		ProxyMemoryAllocationMb: int32(state.ProxyMemoryAllocationMB),
+		FeatureFlags:            state.FeatureFlags,
+		MaxContainerMemoryMb:    state.MaxContainerMemoryMB,
+		MaxContainerDiskMb:      state.MaxContainerDiskMB,
+		UnhealthyReasons:        state.UnhealthyReasons,
+		Conditions:              state.Conditions,
+	}, nil
+}
+
+// workFromMessage returns the work of a perform request.
+func workFromMessage(request *PerformRequest) rep.Work {
+	work := rep.Work{CellID: request.GetCellId()}
+	for _, lrp := range request.GetLrps() {
+		work.LRPs = append(work.LRPs, lrpFromMessage(lrp))
+	}
+	for _, task := range request.GetTasks() {
+		work.Tasks = append(work.Tasks, taskFromMessage(task))
+	}
+	return work
+}
+
+// workMessage returns the response of the work a cell failed to place.
+func workMessage(work rep.Work) *PerformResponse {
+	return &PerformResponse{
+		Lrps:  lrpMessages(work.LRPs),
+		Tasks: taskMessages(work.Tasks),
+	}
+}
+
+func resourcesMessage(resources rep.Resources) *Resources {
+	return &Resources{
+		MemoryMb:       resources.MemoryMB,
+		DiskMb:         resources.DiskMB,
+		Containers:     int32(resources.Containers),
+		CpuEntitlement: resources.CPUEntitlement,
+	}
+}
+
+func resourceMessage(resource rep.Resource) *Resource {
+	var staticHostPorts []uint32
+	for _, port := range resource.StaticHostPorts {
+		staticHostPorts = append(staticHostPorts, uint32(port))
+	}
+
+	return &Resource{
+		MemoryMb:        resource.MemoryMB,
+		DiskMb:          resource.DiskMB,
+		MaxPids:         resource.MaxPids,
+		HostPorts:       resource.HostPorts,
+		StaticHostPorts: staticHostPorts,
+		CpuEntitlement:  resource.CPUEntitlement,
+	}
+}
+
+func resourceFromMessage(message *Resource) rep.Resource {
+	var staticHostPorts []uint16
+	for _, port := range message.GetStaticHostPorts() {
+		staticHostPorts = append(staticHostPorts, uint16(port))
+	}
+
+	resource := rep.NewResource(message.GetMemoryMb(), message.GetDiskMb(), message.GetMaxPids())
+	resource.HostPorts = message.GetHostPorts()
+	resource.StaticHostPorts = staticHostPorts
+	resource.CPUEntitlement = message.GetCpuEntitlement()
+	return resource
+}
+
+func placementConstraintMessage(constraint rep.PlacementConstraint) *PlacementConstraint {
+	return &PlacementConstraint{
+		PlacementTags: constraint.PlacementTags,
+		VolumeDrivers: constraint.VolumeDrivers,
+		RootFs:        constraint.RootFs,
+	}
+}
+
+func placementConstraintFromMessage(message *PlacementConstraint) rep.PlacementConstraint {
+	return rep.NewPlacementConstraint(message.GetRootFs(), message.GetPlacementTags(), message.GetVolumeDrivers())
+}
+
+func lrpMessages(lrps []rep.LRP) []*LRP {
+	var messages []*LRP
+	for i := range lrps {
+		messages = append(messages, &LRP{
+			InstanceGuid:        lrps[i].InstanceGUID,
+			ProcessGuid:         lrps[i].ProcessGuid,
+			Index:               lrps[i].Index,
+			Domain:              lrps[i].Domain,
+			PlacementConstraint: placementConstraintMessage(lrps[i].PlacementConstraint),
+			Resource:            resourceMessage(lrps[i].Resource),
+			State:               lrps[i].State,
+			Labels:              lrps[i].Labels,
+			RequiredLifecycles:  lrps[i].RequiredLifecycles,
+			Group:               lrps[i].Group,
+		})
+	}
+	return messages
+}
+
+func lrpFromMessage(message *LRP) rep.LRP {
+	lrp := rep.NewLRP(
+		message.GetInstanceGuid(),
+		models.NewActualLRPKey(message.GetProcessGuid(), message.GetIndex(), message.GetDomain()),
+		resourceFromMessage(message.GetResource()),
+		placementConstraintFromMessage(message.GetPlacementConstraint()),
+	)
+	lrp.State = message.GetState()
+	lrp.Labels = message.GetLabels()
+	lrp.RequiredLifecycles = message.GetRequiredLifecycles()
+	lrp.Group = message.GetGroup()
+	return lrp
+}
+
+func taskMessages(tasks []rep.Task) []*Task {
+	var messages []*Task
+	for i := range tasks {
+		messages = append(messages, &Task{
+			TaskGuid:            tasks[i].TaskGuid,
+			Domain:              tasks[i].Domain,
+			PlacementConstraint: placementConstraintMessage(tasks[i].PlacementConstraint),
+			Resource:            resourceMessage(tasks[i].Resource),
+			State:               int32(tasks[i].State),
+			Failed:              tasks[i].Failed,
+			Labels:              tasks[i].Labels,
+			RequiredLifecycles:  tasks[i].RequiredLifecycles,
+			Group:               tasks[i].Group,
+		})
+	}
+	return messages
+}
+
+func taskFromMessage(message *Task) rep.Task {
+	task := rep.NewTask(
+		message.GetTaskGuid(),
+		message.GetDomain(),
+		resourceFromMessage(message.GetResource()),
+		placementConstraintFromMessage(message.GetPlacementConstraint()),
+	)
+	task.State = models.Task_State(message.GetState())
+	task.Failed = message.GetFailed()
+	task.Labels = message.GetLabels()
+	task.RequiredLifecycles = message.GetRequiredLifecycles()
+	task.Group = message.GetGroup()
+	return task
+}
@@ -0,0 +1,13 @@
+package repgrpc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRepGRPC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rep gRPC Suite")
+}
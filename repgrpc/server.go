@@ -0,0 +1,264 @@
+package repgrpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/locket/metrics/helpers"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+	"code.cloudfoundry.org/rep/fairqueue"
+	"code.cloudfoundry.org/rep/handlers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the Rep service of the cell.
+type Server struct {
+	UnimplementedRepServer
+
+	cellClient     auctioncellrep.AuctionCellClient
+	executorClient executor.Client
+	infoReporter   handlers.InfoReporter
+	queue          fairqueue.Queue
+	auctionRoutes  handlers.AuctionRoutesCloser
+	metrics        helpers.RequestMetrics
+	clock          clock.Clock
+	logger         lager.Logger
+}
+
+// NewServer returns a server of the auction surface of the cell that serves
+// the calls as the handlers of the JSON routes do, and records them in the
+// same request metrics. When queue is not nil, the work of competing callers
+// is admitted through it.
+func NewServer(
+	cellClient auctioncellrep.AuctionCellClient,
+	executorClient executor.Client,
+	infoReporter handlers.InfoReporter,
+	queue fairqueue.Queue,
+	auctionRoutes handlers.AuctionRoutesCloser,
+	metrics helpers.RequestMetrics,
+	clock clock.Clock,
+	logger lager.Logger,
+) *Server {
+	return &Server{
+		cellClient:     cellClient,
+		executorClient: executorClient,
+		infoReporter:   infoReporter,
+		queue:          queue,
+		auctionRoutes:  auctionRoutes,
+		metrics:        metrics,
+		clock:          clock,
+		logger:         logger.Session("grpc"),
+	}
+}
+
+func (s *Server) State(ctx context.Context, _ *StateRequest) (response *StateResponse, err error) {
+	done := s.recordRequest("State")
+	defer func() { done(err) }()
+
+	logger := s.logger.Session("auction-fetch-state")
+	if err := s.checkAuctionRoutes(logger); err != nil {
+		return nil, err
+	}
+
+	state, healthy, err := s.cellClient.State(ctx, logger)
+	if err != nil {
+		logger.Error("failed-to-fetch-state", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !healthy {
+		logger.Info("cell-not-healthy", lager.Data{"reasons": state.UnhealthyReasons})
+	}
+
+	message, err := cellStateMessage(state)
+	if err != nil {
+		logger.Error("failed-to-encode-state", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &StateResponse{State: message, Healthy: healthy}, nil
+}
+
+func (s *Server) Perform(ctx context.Context, request *PerformRequest) (response *PerformResponse, err error) {
+	done := s.recordRequest("Perform")
+	defer func() { done(err) }()
+
+	logger := s.logger.Session("auction-perform-work")
+	if err := s.checkAuctionRoutes(logger); err != nil {
+		return nil, err
+	}
+
+	limits := s.infoReporter.Info().Limits
+	if limits.MaxWorkBatchSize > 0 && len(request.GetLrps())+len(request.GetTasks()) > limits.MaxWorkBatchSize {
+		tooLarge := rep.NewWorkBatchTooLargeError(limits.MaxWorkBatchSize)
+		logger.Error("work-batch-too-large", tooLarge)
+		return nil, status.Error(codes.ResourceExhausted, tooLarge.Error())
+	}
+
+	if s.queue != nil {
+		release, err := s.queue.Admit(ctx, logger, callerIdentity(ctx))
+		if err != nil {
+			logger.Error("failed-to-admit-work", err)
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		defer release()
+	}
+
+	if trace := traceContext(ctx); trace != nil {
+		ctx = rep.WithTraceContext(ctx, trace)
+		logger = logger.WithData(lager.Data{"trace-id": trace.TraceID()})
+	}
+
+	failedWork, err := s.cellClient.Perform(ctx, logger, workFromMessage(request))
+	if err != nil {
+		logger.Error("failed-to-perform-work", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return workMessage(failedWork), nil
+}
+
+func (s *Server) StopLRP(ctx context.Context, request *StopLRPRequest) (response *StopLRPResponse, err error) {
+	done := s.recordRequest("StopLRPInstance")
+	defer func() { done(err) }()
+
+	logger := s.logger.Session("handling-stop-lrp-instance", lager.Data{
+		"process-guid":  request.GetProcessGuid(),
+		"instance-guid": request.GetInstanceGuid(),
+	})
+
+	if request.GetProcessGuid() == "" {
+		err = status.Error(codes.InvalidArgument, "process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		return nil, err
+	}
+	if request.GetInstanceGuid() == "" {
+		err = status.Error(codes.InvalidArgument, "instance_guid missing from request")
+		logger.Error("missing-instance-guid", err)
+		return nil, err
+	}
+
+	err = s.executorClient.StopContainer(logger, rep.LRPContainerGuid(request.GetProcessGuid(), request.GetInstanceGuid()))
+	if err != nil {
+		logger.Error("failed-to-stop-container", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &StopLRPResponse{}, nil
+}
+
+// CancelTask deletes the container of the task once it returns, as the JSON
+// route does, and records whether deleting it succeeded in the request
+// metrics then.
+func (s *Server) CancelTask(ctx context.Context, request *CancelTaskRequest) (*CancelTaskResponse, error) {
+	start := s.clock.Now()
+	requestType := "CancelTask"
+	s.metrics.IncrementRequestsStartedCounter(requestType, 1)
+	s.metrics.IncrementRequestsInFlightCounter(requestType, 1)
+	defer func() {
+		s.metrics.DecrementRequestsInFlightCounter(requestType, 1)
+		s.metrics.UpdateLatency(requestType, s.clock.Since(start))
+	}()
+
+	taskGuid := request.GetTaskGuid()
+	logger := s.logger.Session("cancel-task", lager.Data{"instance-guid": taskGuid})
+
+	if taskGuid == "" {
+		err := status.Error(codes.InvalidArgument, "task_guid missing from request")
+		logger.Error("missing-task-guid", err)
+		s.metrics.IncrementRequestsFailedCounter(requestType, 1)
+		return nil, err
+	}
+
+	go func() {
+		logger.Info("deleting-container")
+
+		err := s.executorClient.DeleteContainer(logger, taskGuid)
+		switch err {
+		case nil:
+			logger.Info("succeeded-deleting-container")
+			s.metrics.IncrementRequestsSucceededCounter(requestType, 1)
+		case executor.ErrContainerNotFound:
+			logger.Info("container-not-found")
+			s.metrics.IncrementRequestsSucceededCounter(requestType, 1)
+		default:
+			logger.Error("failed-deleting-container", err)
+			s.metrics.IncrementRequestsFailedCounter(requestType, 1)
+		}
+	}()
+
+	return &CancelTaskResponse{}, nil
+}
+
+// recordRequest records the start of a request of requestType and returns
+// the func that records its end with the error it returned.
+func (s *Server) recordRequest(requestType string) func(error) {
+	start := s.clock.Now()
+	s.metrics.IncrementRequestsStartedCounter(requestType, 1)
+	s.metrics.IncrementRequestsInFlightCounter(requestType, 1)
+
+	return func(err error) {
+		s.metrics.DecrementRequestsInFlightCounter(requestType, 1)
+		s.metrics.UpdateLatency(requestType, s.clock.Since(start))
+
+		if err == nil {
+			s.metrics.IncrementRequestsSucceededCounter(requestType, 1)
+		} else {
+			s.metrics.IncrementRequestsFailedCounter(requestType, 1)
+		}
+	}
+}
+
+// checkAuctionRoutes turns the calls of the auction away while the auction
+// routes of the cell are closed, as it would were the cell unavailable.
+func (s *Server) checkAuctionRoutes(logger lager.Logger) error {
+	if s.auctionRoutes.AuctionRoutesStatus().Closed {
+		logger.Info("auction-routes-closed")
+		return status.Error(codes.Unavailable, "auction routes are closed")
+	}
+	return nil
+}
+
+// traceContext is the trace context the caller sent in the metadata of the
+// call, if any.
+func traceContext(ctx context.Context) *rep.TraceContext {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	header := http.Header{}
+	for _, key := range []string{rep.TraceParentHeader, rep.TraceStateHeader, rep.BaggageHeader} {
+		if values := md.Get(key); len(values) > 0 {
+			header.Set(key, values[0])
+		}
+	}
+	return rep.TraceContextFromHeader(header)
+}
+
+// callerIdentity identifies the caller of a call by the host it connects
+// from, qualified by the common name of its client certificate, as the
+// perform handler identifies the callers of the JSON route.
+func callerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host := p.Addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		if identity := tlsInfo.State.PeerCertificates[0].Subject.CommonName; identity != "" {
+			return identity + "@" + host
+		}
+	}
+	return host
+}
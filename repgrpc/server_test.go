@@ -0,0 +1,416 @@
+package repgrpc_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	executorfakes "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/locket/metrics/helpers/helpersfakes"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
+	"code.cloudfoundry.org/rep/fairqueue"
+	"code.cloudfoundry.org/rep/fairqueue/fairqueuefakes"
+	"code.cloudfoundry.org/rep/handlers/handlersfakes"
+	"code.cloudfoundry.org/rep/repgrpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		fakeCellClient     *auctioncellrepfakes.FakeAuctionCellClient
+		fakeExecutorClient *executorfakes.FakeClient
+		fakeInfoReporter   *handlersfakes.FakeInfoReporter
+		fakeAuctionRoutes  *handlersfakes.FakeAuctionRoutesCloser
+		fakeRequestMetrics *helpersfakes.FakeRequestMetrics
+		fakeClock          *fakeclock.FakeClock
+		queue              fairqueue.Queue
+		ctx                context.Context
+
+		server *repgrpc.Server
+	)
+
+	BeforeEach(func() {
+		fakeCellClient = new(auctioncellrepfakes.FakeAuctionCellClient)
+		fakeExecutorClient = new(executorfakes.FakeClient)
+		fakeInfoReporter = new(handlersfakes.FakeInfoReporter)
+		fakeAuctionRoutes = new(handlersfakes.FakeAuctionRoutesCloser)
+		fakeRequestMetrics = new(helpersfakes.FakeRequestMetrics)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		queue = nil
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		server = repgrpc.NewServer(fakeCellClient, fakeExecutorClient, fakeInfoReporter, queue, fakeAuctionRoutes, fakeRequestMetrics, fakeClock, lagertest.NewTestLogger("test"))
+	})
+
+	Describe("State", func() {
+		var state rep.CellState
+
+		BeforeEach(func() {
+			lrp := rep.NewLRP(
+				"instance-guid",
+				models.NewActualLRPKey("process-guid", 1, "domain"),
+				rep.NewResource(1024, 2048, 100),
+				rep.NewPlacementConstraint("preloaded:cflinuxfs4", []string{"pt-1"}, nil),
+			)
+			lrp.State = "RUNNING"
+			lrp.Labels = map[string]string{"team": "payments"}
+			lrp.StaticHostPorts = []uint16{8443}
+
+			task := rep.NewTask("task-guid", "domain", rep.NewResource(256, 512, 10), rep.NewPlacementConstraint("docker:///busybox", nil, nil))
+			task.State = models.Task_Running
+
+			state = rep.CellState{
+				CellID:             "cell-id",
+				CellIndex:          3,
+				Zone:               "z1",
+				RootFSProviders:    rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}},
+				AvailableResources: rep.NewResources(3072, 8192, 10),
+				TotalResources:     rep.NewResources(4096, 10240, 12),
+				LRPs:               []rep.LRP{lrp},
+				Tasks:              []rep.Task{task},
+				PlacementTags:      []string{"pt-1"},
+				Maintenance:        true,
+			}
+
+			fakeCellClient.StateStub = func(context.Context, lager.Logger) (rep.CellState, bool, error) {
+				fakeClock.Increment(50 * time.Millisecond)
+				return state, true, nil
+			}
+		})
+
+		It("returns the state of the cell", func() {
+			response, err := server.State(ctx, &repgrpc.StateRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.GetHealthy()).To(BeTrue())
+
+			Expect(response.GetState()).To(Satisfy(func(message *repgrpc.CellState) bool {
+				return proto.Equal(message, &repgrpc.CellState{
+					CellId:             "cell-id",
+					CellIndex:          3,
+					Zone:               "z1",
+					RootFsProviders:    []byte(`{"docker":{"type":"arbitrary"}}`),
+					AvailableResources: &repgrpc.Resources{MemoryMb: 3072, DiskMb: 8192, Containers: 10},
+					TotalResources:     &repgrpc.Resources{MemoryMb: 4096, DiskMb: 10240, Containers: 12},
+					Lrps: []*repgrpc.LRP{{
+						InstanceGuid:        "instance-guid",
+						ProcessGuid:         "process-guid",
+						Index:               1,
+						Domain:              "domain",
+						PlacementConstraint: &repgrpc.PlacementConstraint{RootFs: "preloaded:cflinuxfs4", PlacementTags: []string{"pt-1"}},
+						Resource:            &repgrpc.Resource{MemoryMb: 1024, DiskMb: 2048, MaxPids: 100, StaticHostPorts: []uint32{8443}},
+						State:               "RUNNING",
+						Labels:              map[string]string{"team": "payments"},
+					}},
+					Tasks: []*repgrpc.Task{{
+						TaskGuid:            "task-guid",
+						Domain:              "domain",
+						PlacementConstraint: &repgrpc.PlacementConstraint{RootFs: "docker:///busybox"},
+						Resource:            &repgrpc.Resource{MemoryMb: 256, DiskMb: 512, MaxPids: 10},
+						State:               int32(models.Task_Running),
+					}},
+					PlacementTags: []string{"pt-1"},
+					Maintenance:   true,
+				})
+			}))
+		})
+
+		It("encodes the rootfs providers so that they decode to those of the cell", func() {
+			response, err := server.State(ctx, &repgrpc.StateRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			var providers rep.RootFSProviders
+			Expect(providers.UnmarshalJSON(response.GetState().GetRootFsProviders())).To(Succeed())
+			Expect(providers).To(Equal(state.RootFSProviders))
+		})
+
+		It("emits the request metrics", func() {
+			_, err := server.State(ctx, &repgrpc.StateRequest{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeRequestMetrics.IncrementRequestsStartedCounterCallCount()).To(Equal(1))
+			requestType, delta := fakeRequestMetrics.IncrementRequestsStartedCounterArgsForCall(0)
+			Expect(requestType).To(Equal("State"))
+			Expect(delta).To(Equal(1))
+
+			Expect(fakeRequestMetrics.DecrementRequestsInFlightCounterCallCount()).To(Equal(1))
+
+			Expect(fakeRequestMetrics.UpdateLatencyCallCount()).To(Equal(1))
+			requestType, latency := fakeRequestMetrics.UpdateLatencyArgsForCall(0)
+			Expect(requestType).To(Equal("State"))
+			Expect(latency).To(Equal(50 * time.Millisecond))
+
+			Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(1))
+			Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(0))
+		})
+
+		Context("when the cell is unhealthy", func() {
+			BeforeEach(func() {
+				fakeCellClient.StateReturns(state, false, nil)
+			})
+
+			It("returns the state and reports the cell unhealthy", func() {
+				response, err := server.State(ctx, &repgrpc.StateRequest{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.GetHealthy()).To(BeFalse())
+				Expect(response.GetState().GetCellId()).To(Equal("cell-id"))
+			})
+		})
+
+		Context("when fetching the state fails", func() {
+			BeforeEach(func() {
+				fakeCellClient.StateReturns(rep.CellState{}, false, errors.New("boom"))
+			})
+
+			It("fails with an internal error and records the failure", func() {
+				_, err := server.State(ctx, &repgrpc.StateRequest{})
+				Expect(status.Code(err)).To(Equal(codes.Internal))
+
+				Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+				Expect(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the auction routes are closed", func() {
+			BeforeEach(func() {
+				fakeAuctionRoutes.AuctionRoutesStatusReturns(rep.AuctionRoutesStatus{Closed: true})
+			})
+
+			It("turns the call away as unavailable", func() {
+				_, err := server.State(ctx, &repgrpc.StateRequest{})
+				Expect(status.Code(err)).To(Equal(codes.Unavailable))
+				Expect(fakeCellClient.StateCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Perform", func() {
+		var request *repgrpc.PerformRequest
+
+		BeforeEach(func() {
+			request = &repgrpc.PerformRequest{
+				CellId: "cell-id",
+				Lrps: []*repgrpc.LRP{{
+					InstanceGuid:        "instance-guid",
+					ProcessGuid:         "process-guid",
+					Index:               2,
+					Domain:              "domain",
+					PlacementConstraint: &repgrpc.PlacementConstraint{RootFs: "preloaded:cflinuxfs4", VolumeDrivers: []string{"vd-1"}},
+					Resource:            &repgrpc.Resource{MemoryMb: 512, DiskMb: 1024, MaxPids: 100, CpuEntitlement: 0.5},
+					Group:               "web",
+				}},
+				Tasks: []*repgrpc.Task{{
+					TaskGuid:            "task-guid",
+					Domain:              "domain",
+					PlacementConstraint: &repgrpc.PlacementConstraint{RootFs: "docker:///busybox"},
+					Resource:            &repgrpc.Resource{MemoryMb: 128, DiskMb: 256},
+					Labels:              map[string]string{"team": "payments"},
+				}},
+			}
+		})
+
+		It("performs the work of the request", func() {
+			_, err := server.Perform(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCellClient.PerformCallCount()).To(Equal(1))
+			_, _, work := fakeCellClient.PerformArgsForCall(0)
+
+			lrp := rep.NewLRP(
+				"instance-guid",
+				models.NewActualLRPKey("process-guid", 2, "domain"),
+				rep.NewResource(512, 1024, 100),
+				rep.NewPlacementConstraint("preloaded:cflinuxfs4", nil, []string{"vd-1"}),
+			)
+			lrp.CPUEntitlement = 0.5
+			lrp.Group = "web"
+
+			task := rep.NewTask("task-guid", "domain", rep.NewResource(128, 256, 0), rep.NewPlacementConstraint("docker:///busybox", nil, nil))
+			task.State = models.Task_Invalid
+			task.Labels = map[string]string{"team": "payments"}
+
+			Expect(work).To(Equal(rep.Work{CellID: "cell-id", LRPs: []rep.LRP{lrp}, Tasks: []rep.Task{task}}))
+		})
+
+		It("returns the work that failed to be placed", func() {
+			fakeCellClient.PerformReturns(rep.Work{Tasks: []rep.Task{rep.NewTask("task-guid", "domain", rep.NewResource(128, 256, 0), rep.NewPlacementConstraint("docker:///busybox", nil, nil))}}, nil)
+
+			response, err := server.Perform(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.GetLrps()).To(BeEmpty())
+			Expect(response.GetTasks()).To(HaveLen(1))
+			Expect(response.GetTasks()[0].GetTaskGuid()).To(Equal("task-guid"))
+		})
+
+		It("performs the work in the trace context of the call", func() {
+			traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(rep.TraceParentHeader, traceParent))
+
+			_, err := server.Perform(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			performCtx, _, _ := fakeCellClient.PerformArgsForCall(0)
+			Expect(rep.TraceContextFromContext(performCtx)).To(Equal(&rep.TraceContext{TraceParent: traceParent}))
+		})
+
+		Context("when the work batch is larger than the limit", func() {
+			BeforeEach(func() {
+				fakeInfoReporter.InfoReturns(rep.Info{Limits: rep.Limits{MaxWorkBatchSize: 1}})
+			})
+
+			It("rejects the work without performing it", func() {
+				_, err := server.Perform(ctx, request)
+				Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+				Expect(fakeCellClient.PerformCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a perform queue admits the work", func() {
+			var fakeQueue *fairqueuefakes.FakeQueue
+
+			BeforeEach(func() {
+				fakeQueue = new(fairqueuefakes.FakeQueue)
+				queue = fakeQueue
+			})
+
+			It("admits the caller by the common name of its certificate and the host it connects from", func() {
+				released := false
+				fakeQueue.AdmitReturns(func() { released = true }, nil)
+
+				ctx = peer.NewContext(ctx, &peer.Peer{
+					Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4321},
+					AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+						PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "auctioneer"}}},
+					}},
+				})
+
+				_, err := server.Perform(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeQueue.AdmitCallCount()).To(Equal(1))
+				_, _, caller := fakeQueue.AdmitArgsForCall(0)
+				Expect(caller).To(Equal("auctioneer@10.0.0.1"))
+				Expect(released).To(BeTrue())
+			})
+
+			It("turns the work away as unavailable when it is not admitted", func() {
+				fakeQueue.AdmitReturns(nil, errors.New("queue full"))
+
+				_, err := server.Perform(ctx, request)
+				Expect(status.Code(err)).To(Equal(codes.Unavailable))
+				Expect(fakeCellClient.PerformCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when performing the work fails", func() {
+			BeforeEach(func() {
+				fakeCellClient.PerformReturns(rep.Work{}, errors.New("boom"))
+			})
+
+			It("fails with an internal error", func() {
+				_, err := server.Perform(ctx, request)
+				Expect(status.Code(err)).To(Equal(codes.Internal))
+				Expect(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the auction routes are closed", func() {
+			BeforeEach(func() {
+				fakeAuctionRoutes.AuctionRoutesStatusReturns(rep.AuctionRoutesStatus{Closed: true})
+			})
+
+			It("turns the work away as unavailable", func() {
+				_, err := server.Perform(ctx, request)
+				Expect(status.Code(err)).To(Equal(codes.Unavailable))
+				Expect(fakeCellClient.PerformCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("StopLRP", func() {
+		It("stops the container of the LRP instance", func() {
+			_, err := server.StopLRP(ctx, &repgrpc.StopLRPRequest{ProcessGuid: "process-guid", InstanceGuid: "instance-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeExecutorClient.StopContainerCallCount()).To(Equal(1))
+			_, guid := fakeExecutorClient.StopContainerArgsForCall(0)
+			Expect(guid).To(Equal(rep.LRPContainerGuid("process-guid", "instance-guid")))
+
+			requestType, _ := fakeRequestMetrics.IncrementRequestsSucceededCounterArgsForCall(0)
+			Expect(requestType).To(Equal("StopLRPInstance"))
+		})
+
+		It("rejects requests without a process or instance guid", func() {
+			_, err := server.StopLRP(ctx, &repgrpc.StopLRPRequest{InstanceGuid: "instance-guid"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			_, err = server.StopLRP(ctx, &repgrpc.StopLRPRequest{ProcessGuid: "process-guid"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			Expect(fakeExecutorClient.StopContainerCallCount()).To(Equal(0))
+		})
+
+		It("fails with an internal error when the container fails to stop", func() {
+			fakeExecutorClient.StopContainerReturns(errors.New("boom"))
+
+			_, err := server.StopLRP(ctx, &repgrpc.StopLRPRequest{ProcessGuid: "process-guid", InstanceGuid: "instance-guid"})
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+		})
+	})
+
+	Describe("CancelTask", func() {
+		It("deletes the container of the task", func() {
+			_, err := server.CancelTask(ctx, &repgrpc.CancelTaskRequest{TaskGuid: "task-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(fakeExecutorClient.DeleteContainerCallCount).Should(Equal(1))
+			_, guid := fakeExecutorClient.DeleteContainerArgsForCall(0)
+			Expect(guid).To(Equal("task-guid"))
+
+			Eventually(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount).Should(Equal(1))
+		})
+
+		It("records the cancel as succeeded when the container is already gone", func() {
+			fakeExecutorClient.DeleteContainerReturns(executor.ErrContainerNotFound)
+
+			_, err := server.CancelTask(ctx, &repgrpc.CancelTaskRequest{TaskGuid: "task-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(fakeRequestMetrics.IncrementRequestsSucceededCounterCallCount).Should(Equal(1))
+			Consistently(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount).Should(Equal(0))
+		})
+
+		It("records the failure when the container fails to be deleted", func() {
+			fakeExecutorClient.DeleteContainerReturns(errors.New("boom"))
+
+			_, err := server.CancelTask(ctx, &repgrpc.CancelTaskRequest{TaskGuid: "task-guid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(fakeRequestMetrics.IncrementRequestsFailedCounterCallCount).Should(Equal(1))
+		})
+
+		It("rejects requests without a task guid", func() {
+			_, err := server.CancelTask(ctx, &repgrpc.CancelTaskRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Consistently(fakeExecutorClient.DeleteContainerCallCount).Should(Equal(0))
+		})
+	})
+})
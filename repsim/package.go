@@ -0,0 +1 @@
+package repsim // import "code.cloudfoundry.org/rep/repsim"
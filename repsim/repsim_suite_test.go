@@ -0,0 +1,13 @@
+package repsim_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRepSim(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rep Sim Suite")
+}
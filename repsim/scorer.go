@@ -0,0 +1,71 @@
+package repsim
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/rep"
+)
+
+var (
+	ErrCellEvacuating       = errors.New("cell is evacuating")
+	ErrRootFSMismatch       = errors.New("rootfs not supported by the cell")
+	ErrPlacementTagMismatch = rep.ErrPlacementTagsMismatch
+	ErrVolumeDriverMismatch = rep.ErrorIncompatibleVolumeDrivers
+)
+
+// Scorer scores a cell for an LRP instance or task. As in the auctioneer,
+// the work goes to the cell with the lowest score, and a cell the work does
+// not fit on returns an error instead.
+type Scorer interface {
+	ScoreLRP(cell *rep.CellState, lrp *rep.LRP) (float64, error)
+	ScoreTask(cell *rep.CellState, task *rep.Task) (float64, error)
+}
+
+// DefaultScorer scores cells with the scores the cell states compute for the
+// auctioneer, every cell by Strategy, or spreading when it is nil.
+type DefaultScorer struct {
+	StartingContainerWeight float64
+	Strategy                rep.ScoringStrategy
+}
+
+func (s DefaultScorer) ScoreLRP(cell *rep.CellState, lrp *rep.LRP) (float64, error) {
+	err := match(cell, &lrp.PlacementConstraint)
+	if err != nil {
+		return 0, err
+	}
+
+	err = cell.LRPResourceMatch(lrp)
+	if err != nil {
+		return 0, err
+	}
+	return cell.ComputeLRPScore(lrp, s.StartingContainerWeight, s.Strategy), nil
+}
+
+func (s DefaultScorer) ScoreTask(cell *rep.CellState, task *rep.Task) (float64, error) {
+	err := match(cell, &task.PlacementConstraint)
+	if err != nil {
+		return 0, err
+	}
+
+	err = cell.TaskResourceMatch(task)
+	if err != nil {
+		return 0, err
+	}
+	return cell.ComputeTaskScore(task, s.StartingContainerWeight, s.Strategy), nil
+}
+
+func match(cell *rep.CellState, constraint *rep.PlacementConstraint) error {
+	if cell.Evacuating {
+		return ErrCellEvacuating
+	}
+	if !cell.MatchRootFS(constraint.RootFs) {
+		return ErrRootFSMismatch
+	}
+	if !cell.MatchPlacementTags(constraint.PlacementTags) {
+		return ErrPlacementTagMismatch
+	}
+	if !cell.MatchVolumeDrivers(constraint.VolumeDrivers) {
+		return ErrVolumeDriverMismatch
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package repsim
+
+import (
+	"sort"
+
+	"code.cloudfoundry.org/rep"
+)
+
+const RejectionNoCell = "no cell fits"
+
+// Report describes how the work of a trace was placed.
+type Report struct {
+	// Placements is the number of LRP instances and tasks placed on each cell.
+	Placements    map[string]int `json:"placements"`
+	Auctioned     int            `json:"auctioned"`
+	Rejected      int            `json:"rejected"`
+	RejectionRate float64        `json:"rejection_rate"`
+	// Rejections counts the rejected work by the error most cells rejected
+	// it with, or RejectionNoCell when there were no cells.
+	Rejections map[string]int `json:"rejections,omitempty"`
+	Timeline   []Sample       `json:"timeline"`
+}
+
+// Sample is the state of the deployment after an event of the trace. The
+// fragmentation scores are those of rep.AnalyzeFragmentation, with each
+// cell as one pool.
+type Sample struct {
+	Time                int64   `json:"time"`
+	Placed              int     `json:"placed"`
+	Rejected            int     `json:"rejected"`
+	MemoryFragmentation float64 `json:"memory_fragmentation"`
+	DiskFragmentation   float64 `json:"disk_fragmentation"`
+}
+
+// Simulate replays the events of trace on copies of its cells, placing each
+// LRP instance and task on the cell scorer scores lowest. The containers
+// placed by an event have started by the next.
+func Simulate(trace Trace, scorer Scorer) Report {
+	cells := make([]rep.CellState, len(trace.Cells))
+	for i := range trace.Cells {
+		cells[i] = trace.Cells[i].Copy()
+	}
+
+	report := Report{
+		Placements: map[string]int{},
+		Rejections: map[string]int{},
+		Timeline:   []Sample{},
+	}
+
+	for _, event := range trace.Events {
+		sample := Sample{Time: event.Time}
+		for i := range cells {
+			cells[i].StartingContainerCount = 0
+			releaseLRPs(&cells[i], event.StoppedInstances)
+			releaseTasks(&cells[i], event.CompletedTasks)
+		}
+
+		for i := range event.LRPs {
+			lrp := &event.LRPs[i]
+			best, reason := place(cells, func(cell *rep.CellState) (float64, error) {
+				return scorer.ScoreLRP(cell, lrp)
+			})
+			if best == nil {
+				report.Rejections[reason]++
+				sample.Rejected++
+				continue
+			}
+			best.AddLRP(lrp)
+			report.Placements[best.CellID]++
+			sample.Placed++
+		}
+
+		for i := range event.Tasks {
+			task := &event.Tasks[i]
+			best, reason := place(cells, func(cell *rep.CellState) (float64, error) {
+				return scorer.ScoreTask(cell, task)
+			})
+			if best == nil {
+				report.Rejections[reason]++
+				sample.Rejected++
+				continue
+			}
+			best.AddTask(task)
+			report.Placements[best.CellID]++
+			sample.Placed++
+		}
+
+		fragmentation := rep.AnalyzeFragmentation(pools(cells), rep.Resource{})
+		sample.MemoryFragmentation = fragmentation.MemoryScore
+		sample.DiskFragmentation = fragmentation.DiskScore
+
+		report.Auctioned += sample.Placed + sample.Rejected
+		report.Rejected += sample.Rejected
+		report.Timeline = append(report.Timeline, sample)
+	}
+
+	if report.Auctioned > 0 {
+		report.RejectionRate = float64(report.Rejected) / float64(report.Auctioned)
+	}
+	return report
+}
+
+// place returns the cell with the lowest score, the first of them on a tie,
+// or nil and the most common reason the cells rejected the work.
+func place(cells []rep.CellState, score func(*rep.CellState) (float64, error)) (*rep.CellState, string) {
+	var best *rep.CellState
+	bestScore := 0.0
+	reasons := map[string]int{}
+
+	for i := range cells {
+		cellScore, err := score(&cells[i])
+		if err != nil {
+			reasons[err.Error()]++
+			continue
+		}
+		if best == nil || cellScore < bestScore {
+			best, bestScore = &cells[i], cellScore
+		}
+	}
+
+	if best != nil {
+		return best, ""
+	}
+	return nil, mostCommon(reasons)
+}
+
+func mostCommon(reasons map[string]int) string {
+	if len(reasons) == 0 {
+		return RejectionNoCell
+	}
+
+	keys := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		keys = append(keys, reason)
+	}
+	sort.Strings(keys)
+
+	common := keys[0]
+	for _, reason := range keys[1:] {
+		if reasons[reason] > reasons[common] {
+			common = reason
+		}
+	}
+	return common
+}
+
+func releaseLRPs(cell *rep.CellState, instanceGuids []string) {
+	for _, guid := range instanceGuids {
+		cell.RemoveLRP(&rep.LRP{InstanceGUID: guid})
+	}
+}
+
+func releaseTasks(cell *rep.CellState, taskGuids []string) {
+	for _, guid := range taskGuids {
+		cell.RemoveTask(&rep.Task{TaskGuid: guid})
+	}
+}
+
+func pools(cells []rep.CellState) []rep.FragmentationPool {
+	pools := make([]rep.FragmentationPool, len(cells))
+	for i := range cells {
+		pools[i] = rep.FragmentationPool{Backend: cells[i].CellID, Available: cells[i].AvailableResources}
+	}
+	return pools
+}
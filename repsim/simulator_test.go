@@ -0,0 +1,102 @@
+package repsim_test
+
+import (
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/repsim"
+	"code.cloudfoundry.org/rep/reptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Simulate", func() {
+	var (
+		trace  repsim.Trace
+		scorer repsim.Scorer
+	)
+
+	BeforeEach(func() {
+		scorer = repsim.DefaultScorer{StartingContainerWeight: 0.25}
+		trace = repsim.Trace{
+			Cells: []rep.CellState{
+				reptest.NewCellState().WithCellID("cell-1").WithTotalResources(1024, 4096, 10).Build(),
+				reptest.NewCellState().WithCellID("cell-2").WithTotalResources(1024, 4096, 10).WithLRPs(2).Build(),
+			},
+		}
+	})
+
+	It("places the work on the cell with the lowest score", func() {
+		trace.Events = []repsim.Event{
+			{Time: 0, LRPs: []rep.LRP{reptest.NewLRP("pg", 0)}},
+		}
+
+		report := repsim.Simulate(trace, scorer)
+		Expect(report.Placements).To(Equal(map[string]int{"cell-1": 1}))
+		Expect(report.Auctioned).To(Equal(1))
+		Expect(report.Rejected).To(BeZero())
+		Expect(report.Timeline).To(HaveLen(1))
+		Expect(report.Timeline[0].Placed).To(Equal(1))
+	})
+
+	It("rejects the work that fits on no cell", func() {
+		big := reptest.NewLRP("big-pg", 0)
+		big.MemoryMB = 2048
+		trace.Events = []repsim.Event{
+			{Time: 0, LRPs: []rep.LRP{big, reptest.NewLRP("pg", 0)}},
+		}
+
+		report := repsim.Simulate(trace, scorer)
+		Expect(report.Auctioned).To(Equal(2))
+		Expect(report.Rejected).To(Equal(1))
+		Expect(report.RejectionRate).To(Equal(0.5))
+		Expect(report.Rejections).To(HaveKeyWithValue("insufficient resources: memory", 1))
+	})
+
+	It("releases the resources of stopped instances and completed tasks", func() {
+		trace.Events = []repsim.Event{
+			{Time: 0, LRPs: []rep.LRP{reptest.NewLRP("pg", 0), reptest.NewLRP("pg", 1)}, Tasks: []rep.Task{reptest.NewTask("tg")}},
+			{Time: 60, StoppedInstances: []string{"pg-ig-0", "pg-ig-1"}, CompletedTasks: []string{"tg"}},
+		}
+
+		report := repsim.Simulate(trace, scorer)
+		Expect(report.Timeline).To(HaveLen(2))
+		Expect(report.Timeline[0].MemoryFragmentation).To(BeNumerically(">", 0))
+		Expect(report.Timeline[1].Time).To(BeEquivalentTo(60))
+		Expect(report.Timeline[1].MemoryFragmentation).To(Equal(1 - 1024.0/1536))
+	})
+
+	It("does not change the cells of the trace", func() {
+		trace.Events = []repsim.Event{
+			{Time: 0, LRPs: []rep.LRP{reptest.NewLRP("pg", 0)}},
+		}
+
+		repsim.Simulate(trace, scorer)
+		Expect(trace.Cells[0].LRPs).To(BeEmpty())
+		Expect(trace.Cells[0].AvailableResources).To(Equal(rep.NewResources(1024, 4096, 10)))
+	})
+
+	It("scores every cell by the strategy of the scorer", func() {
+		scorer = repsim.DefaultScorer{StartingContainerWeight: 0.25, Strategy: rep.BinPackStrategy{}}
+		trace.Events = []repsim.Event{
+			{Time: 0, LRPs: []rep.LRP{reptest.NewLRP("pg", 0)}},
+		}
+
+		report := repsim.Simulate(trace, scorer)
+		Expect(report.Placements).To(Equal(map[string]int{"cell-2": 1}))
+	})
+
+	Context("when a cell is evacuating", func() {
+		BeforeEach(func() {
+			trace.Cells = []rep.CellState{reptest.NewCellState().Evacuating().Build()}
+			trace.Events = []repsim.Event{
+				{Time: 0, Tasks: []rep.Task{reptest.NewTask("tg")}},
+			}
+		})
+
+		It("places no work on it", func() {
+			report := repsim.Simulate(trace, scorer)
+			Expect(report.Placements).To(BeEmpty())
+			Expect(report.Rejections).To(Equal(map[string]int{repsim.ErrCellEvacuating.Error(): 1}))
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package repsim
+
+import (
+	"encoding/json"
+	"io"
+
+	"code.cloudfoundry.org/rep"
+)
+
+// Trace is a recording of the auctions of a deployment: the states of its
+// cells when the recording started and what happened since, in order.
+type Trace struct {
+	Cells  []rep.CellState `json:"cells"`
+	Events []Event         `json:"events"`
+}
+
+// Event is what happened at one point of a trace. The LRPs and tasks of an
+// event are auctioned after its stopped instances and completed tasks have
+// released their resources.
+type Event struct {
+	// Time is when the event happened, in seconds since the recording started.
+	Time             int64      `json:"time"`
+	LRPs             []rep.LRP  `json:"lrps,omitempty"`
+	Tasks            []rep.Task `json:"tasks,omitempty"`
+	StoppedInstances []string   `json:"stopped_instances,omitempty"`
+	CompletedTasks   []string   `json:"completed_tasks,omitempty"`
+}
+
+func LoadTrace(r io.Reader) (Trace, error) {
+	var trace Trace
+	err := json.NewDecoder(r).Decode(&trace)
+	if err != nil {
+		return Trace{}, err
+	}
+
+	for i := range trace.Cells {
+		err = trace.Cells[i].Validate()
+		if err != nil {
+			return Trace{}, err
+		}
+	}
+	return trace, nil
+}
@@ -0,0 +1,37 @@
+package repsim_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/repsim"
+	"code.cloudfoundry.org/rep/reptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadTrace", func() {
+	It("loads a recorded trace", func() {
+		trace := repsim.Trace{
+			Cells: []rep.CellState{reptest.NewCellState().WithLRPs(1).Build()},
+			Events: []repsim.Event{
+				{Time: 30, Tasks: []rep.Task{reptest.NewTask("tg")}, StoppedInstances: []string{"cell-id-pg-0-ig-0"}},
+			},
+		}
+		payload, err := json.Marshal(trace)
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded, err := repsim.LoadTrace(bytes.NewReader(payload))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Events).To(Equal(trace.Events))
+		Expect(loaded.Cells[0].LRPs).To(Equal(trace.Cells[0].LRPs))
+	})
+
+	It("rejects cells that are not valid", func() {
+		_, err := repsim.LoadTrace(strings.NewReader(`{"cells": [{"rep_url": "https://cell"}]}`))
+		Expect(err).To(Equal(rep.CellStateValidationError{Field: "cell_id", Message: "is missing"}))
+	})
+})
@@ -0,0 +1,189 @@
+package reptest
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	DefaultCellID = "cell-id"
+	DefaultZone   = "z1"
+	DefaultDomain = "domain"
+	DefaultRootFS = "cflinuxfs4"
+)
+
+// DefaultTotalResources are the resources of a cell built by NewCellState.
+var DefaultTotalResources = rep.NewResources(16384, 65536, 250)
+
+// DefaultInstanceResource is the resource of the LRP instances and tasks
+// the builders generate.
+var DefaultInstanceResource = rep.NewResource(256, 1024, 1024)
+
+// CellStateBuilder builds a rep.CellState that passes Validate. Unless they
+// are set with WithAvailableResources, the available resources of the state
+// are its total resources less those of its LRP instances and tasks.
+type CellStateBuilder struct {
+	state     rep.CellState
+	available *rep.Resources
+}
+
+// NewCellState returns a builder for an empty linux cell with
+// DefaultTotalResources in DefaultZone that offers DefaultRootFS.
+func NewCellState() *CellStateBuilder {
+	return &CellStateBuilder{
+		state: rep.CellState{
+			CellID:          DefaultCellID,
+			RepURL:          repURL(DefaultCellID),
+			RootFSProviders: rep.RootFSProviders{models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider(DefaultRootFS)},
+			TotalResources:  DefaultTotalResources,
+			Zone:            DefaultZone,
+		},
+	}
+}
+
+func (b *CellStateBuilder) WithCellID(cellID string) *CellStateBuilder {
+	b.state.CellID = cellID
+	b.state.RepURL = repURL(cellID)
+	return b
+}
+
+func (b *CellStateBuilder) WithCellIndex(cellIndex int) *CellStateBuilder {
+	b.state.CellIndex = cellIndex
+	return b
+}
+
+func (b *CellStateBuilder) WithZone(zone string) *CellStateBuilder {
+	b.state.Zone = zone
+	return b
+}
+
+func (b *CellStateBuilder) WithRootFSProviders(providers rep.RootFSProviders) *CellStateBuilder {
+	b.state.RootFSProviders = providers
+	return b
+}
+
+func (b *CellStateBuilder) WithTotalResources(memoryMB, diskMB int32, containers int) *CellStateBuilder {
+	b.state.TotalResources = rep.NewResources(memoryMB, diskMB, containers)
+	return b
+}
+
+func (b *CellStateBuilder) WithAvailableResources(memoryMB, diskMB int32, containers int) *CellStateBuilder {
+	available := rep.NewResources(memoryMB, diskMB, containers)
+	b.available = &available
+	return b
+}
+
+// WithLRPs adds count LRP instances of DefaultInstanceResource, each of its
+// own process.
+func (b *CellStateBuilder) WithLRPs(count int) *CellStateBuilder {
+	for i := 0; i < count; i++ {
+		b.state.LRPs = append(b.state.LRPs, NewLRP(fmt.Sprintf("%s-pg-%d", b.state.CellID, len(b.state.LRPs)), 0))
+	}
+	return b
+}
+
+func (b *CellStateBuilder) WithLRP(lrps ...rep.LRP) *CellStateBuilder {
+	b.state.LRPs = append(b.state.LRPs, lrps...)
+	return b
+}
+
+// WithTasks adds count tasks of DefaultInstanceResource.
+func (b *CellStateBuilder) WithTasks(count int) *CellStateBuilder {
+	for i := 0; i < count; i++ {
+		b.state.Tasks = append(b.state.Tasks, NewTask(fmt.Sprintf("%s-tg-%d", b.state.CellID, len(b.state.Tasks))))
+	}
+	return b
+}
+
+func (b *CellStateBuilder) WithTask(tasks ...rep.Task) *CellStateBuilder {
+	b.state.Tasks = append(b.state.Tasks, tasks...)
+	return b
+}
+
+func (b *CellStateBuilder) WithStartingContainers(count int) *CellStateBuilder {
+	b.state.StartingContainerCount = count
+	return b
+}
+
+func (b *CellStateBuilder) WithPlacementTags(tags ...string) *CellStateBuilder {
+	b.state.PlacementTags = tags
+	return b
+}
+
+func (b *CellStateBuilder) WithOptionalPlacementTags(tags ...string) *CellStateBuilder {
+	b.state.OptionalPlacementTags = tags
+	return b
+}
+
+func (b *CellStateBuilder) WithVolumeDrivers(drivers ...string) *CellStateBuilder {
+	b.state.VolumeDrivers = drivers
+	return b
+}
+
+func (b *CellStateBuilder) Evacuating() *CellStateBuilder {
+	b.state.Evacuating = true
+	return b
+}
+
+func (b *CellStateBuilder) InMaintenance() *CellStateBuilder {
+	b.state.Maintenance = true
+	return b
+}
+
+// Build returns the state. The builder can be built again, and the state
+// shares no slices with it.
+func (b *CellStateBuilder) Build() rep.CellState {
+	state := b.state
+	state.RootFSProviders = b.state.RootFSProviders.Copy()
+	state.LRPs = append([]rep.LRP{}, b.state.LRPs...)
+	state.Tasks = append([]rep.Task{}, b.state.Tasks...)
+	state.PlacementTags = append([]string(nil), b.state.PlacementTags...)
+	state.OptionalPlacementTags = append([]string(nil), b.state.OptionalPlacementTags...)
+	state.VolumeDrivers = append([]string(nil), b.state.VolumeDrivers...)
+
+	if b.available != nil {
+		state.AvailableResources = *b.available
+		return state
+	}
+
+	state.AvailableResources = state.TotalResources
+	for i := range state.LRPs {
+		state.AvailableResources.Subtract(&state.LRPs[i].Resource)
+	}
+	for i := range state.Tasks {
+		state.AvailableResources.Subtract(&state.Tasks[i].Resource)
+	}
+	return state
+}
+
+// NewLRP returns a running instance of processGuid at index with
+// DefaultInstanceResource on DefaultRootFS.
+func NewLRP(processGuid string, index int32) rep.LRP {
+	lrp := rep.NewLRP(
+		fmt.Sprintf("%s-ig-%d", processGuid, index),
+		models.NewActualLRPKey(processGuid, index, DefaultDomain),
+		DefaultInstanceResource,
+		rep.NewPlacementConstraint(models.PreloadedRootFS(DefaultRootFS), nil, nil),
+	)
+	lrp.State = models.ActualLRPStateRunning
+	return lrp
+}
+
+// NewTask returns a running task with DefaultInstanceResource on
+// DefaultRootFS.
+func NewTask(taskGuid string) rep.Task {
+	task := rep.NewTask(
+		taskGuid,
+		DefaultDomain,
+		DefaultInstanceResource,
+		rep.NewPlacementConstraint(models.PreloadedRootFS(DefaultRootFS), nil, nil),
+	)
+	task.State = models.Task_Running
+	return task
+}
+
+func repURL(cellID string) string {
+	return fmt.Sprintf("https://%s.cell.service.cf.internal:1801", cellID)
+}
@@ -0,0 +1,61 @@
+package reptest_test
+
+import (
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/reptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CellStateBuilder", func() {
+	It("builds a valid empty cell", func() {
+		state := reptest.NewCellState().Build()
+		Expect(state.Validate()).To(Succeed())
+		Expect(state.CellID).To(Equal(reptest.DefaultCellID))
+		Expect(state.Zone).To(Equal(reptest.DefaultZone))
+		Expect(state.AvailableResources).To(Equal(reptest.DefaultTotalResources))
+	})
+
+	It("takes the resources of the instances and tasks from the available resources", func() {
+		state := reptest.NewCellState().
+			WithTotalResources(4096, 8192, 10).
+			WithLRPs(2).
+			WithTasks(1).
+			WithZone("z2").
+			Build()
+
+		Expect(state.Validate()).To(Succeed())
+		Expect(state.Zone).To(Equal("z2"))
+		Expect(state.LRPs).To(HaveLen(2))
+		Expect(state.Tasks).To(HaveLen(1))
+		Expect(state.LRPs[0].InstanceGUID).NotTo(Equal(state.LRPs[1].InstanceGUID))
+		Expect(state.AvailableResources).To(Equal(rep.NewResources(4096-3*256, 8192-3*1024, 7)))
+	})
+
+	It("uses the available resources it is given", func() {
+		state := reptest.NewCellState().WithLRPs(2).WithAvailableResources(100, 200, 3).Build()
+		Expect(state.AvailableResources).To(Equal(rep.NewResources(100, 200, 3)))
+	})
+
+	It("builds states that share no slices with the builder", func() {
+		builder := reptest.NewCellState().WithLRPs(1)
+		state := builder.Build()
+		state.LRPs[0].ProcessGuid = "changed"
+
+		Expect(builder.Build().LRPs[0].ProcessGuid).NotTo(Equal("changed"))
+	})
+})
+
+var _ = Describe("WorkBuilder", func() {
+	It("builds the instances of a process and pending tasks", func() {
+		work := reptest.NewWork().ForCell("cell-id").WithLRPs(3).WithTasks(2).Build()
+
+		Expect(work.CellID).To(Equal("cell-id"))
+		Expect(work.LRPs).To(HaveLen(3))
+		Expect(work.LRPs[2].ProcessGuid).To(Equal(work.LRPs[0].ProcessGuid))
+		Expect(work.LRPs[2].Index).To(BeEquivalentTo(2))
+		Expect(work.Tasks).To(HaveLen(2))
+		Expect(work.Tasks[0].TaskGuid).NotTo(Equal(work.Tasks[1].TaskGuid))
+	})
+})
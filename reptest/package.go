@@ -0,0 +1 @@
+package reptest // import "code.cloudfoundry.org/rep/reptest"
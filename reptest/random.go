@@ -0,0 +1,70 @@
+package reptest
+
+import (
+	"fmt"
+	"math/rand"
+
+	"code.cloudfoundry.org/rep"
+)
+
+var (
+	cellSizes = []rep.Resources{
+		rep.NewResources(16384, 65536, 250),
+		rep.NewResources(32768, 131072, 250),
+		rep.NewResources(65536, 262144, 500),
+	}
+	instanceMemorySizes = []int32{128, 256, 512, 1024, 2048, 4096}
+	zones               = []string{"z1", "z2", "z3"}
+)
+
+// RandomCellState generates the state of a cell of one of the usual sizes,
+// in one of three zones, running instances of a few processes of the usual
+// sizes until up to all of its memory is taken. The same r generates the
+// same state.
+func RandomCellState(r *rand.Rand, cellID string) rep.CellState {
+	total := cellSizes[r.Intn(len(cellSizes))]
+	builder := NewCellState().
+		WithCellID(cellID).
+		WithZone(zones[r.Intn(len(zones))]).
+		WithTotalResources(total.MemoryMB, total.DiskMB, total.Containers)
+
+	used := rep.Resources{}
+	target := int32(r.Float64() * float64(total.MemoryMB))
+	processes := 1 + r.Intn(8)
+	for used.Containers < total.Containers {
+		lrp := randomLRP(r, fmt.Sprintf("%s-pg-%d", cellID, r.Intn(processes)), int32(used.Containers))
+		if used.MemoryMB+lrp.MemoryMB > target || used.DiskMB+lrp.DiskMB > total.DiskMB {
+			break
+		}
+		used.Add(rep.NewResources(lrp.MemoryMB, lrp.DiskMB, 1))
+		builder.WithLRP(lrp)
+	}
+
+	return builder.Build()
+}
+
+// RandomWork generates lrps instances and tasks of the usual sizes, as an
+// auctioneer would send them to a cell. The same r generates the same work.
+func RandomWork(r *rand.Rand, lrps, tasks int) rep.Work {
+	builder := NewWork()
+	for i := 0; i < lrps; i++ {
+		builder.WithLRP(unallocated(randomLRP(r, fmt.Sprintf("work-pg-%d", r.Intn(lrps)), int32(i))))
+	}
+	for i := 0; i < tasks; i++ {
+		task := NewTask(fmt.Sprintf("work-tg-%d", i))
+		task.Resource = randomResource(r)
+		builder.WithTask(pending(task))
+	}
+	return builder.Build()
+}
+
+func randomLRP(r *rand.Rand, processGuid string, index int32) rep.LRP {
+	lrp := NewLRP(processGuid, index)
+	lrp.Resource = randomResource(r)
+	return lrp
+}
+
+func randomResource(r *rand.Rand) rep.Resource {
+	memoryMB := instanceMemorySizes[r.Intn(len(instanceMemorySizes))]
+	return rep.NewResource(memoryMB, 2*memoryMB, DefaultInstanceResource.MaxPids)
+}
@@ -0,0 +1,37 @@
+package reptest_test
+
+import (
+	"math/rand"
+
+	"code.cloudfoundry.org/rep/reptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RandomCellState", func() {
+	It("generates valid states that fit on the cell", func() {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 100; i++ {
+			state := reptest.RandomCellState(r, "cell-id")
+			Expect(state.Validate()).To(Succeed())
+			Expect(state.AvailableResources.MemoryMB).To(BeNumerically(">=", 0))
+			Expect(state.AvailableResources.DiskMB).To(BeNumerically(">=", 0))
+			Expect(state.AvailableResources.Containers).To(BeNumerically(">=", 0))
+		}
+	})
+
+	It("generates the same state from the same source", func() {
+		first := reptest.RandomCellState(rand.New(rand.NewSource(42)), "cell-id")
+		second := reptest.RandomCellState(rand.New(rand.NewSource(42)), "cell-id")
+		Expect(first).To(Equal(second))
+	})
+})
+
+var _ = Describe("RandomWork", func() {
+	It("generates the requested work", func() {
+		work := reptest.RandomWork(rand.New(rand.NewSource(1)), 5, 3)
+		Expect(work.LRPs).To(HaveLen(5))
+		Expect(work.Tasks).To(HaveLen(3))
+	})
+})
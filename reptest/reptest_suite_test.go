@@ -0,0 +1,13 @@
+package reptest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRepTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rep Test Suite")
+}
@@ -0,0 +1,67 @@
+package reptest
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+)
+
+// WorkBuilder builds the rep.Work an auctioneer sends to a cell.
+type WorkBuilder struct {
+	work rep.Work
+}
+
+func NewWork() *WorkBuilder {
+	return &WorkBuilder{}
+}
+
+func (b *WorkBuilder) ForCell(cellID string) *WorkBuilder {
+	b.work.CellID = cellID
+	return b
+}
+
+// WithLRPs adds the first count instances of a new process.
+func (b *WorkBuilder) WithLRPs(count int) *WorkBuilder {
+	processGuid := fmt.Sprintf("work-pg-%d", len(b.work.LRPs))
+	for i := 0; i < count; i++ {
+		b.work.LRPs = append(b.work.LRPs, unallocated(NewLRP(processGuid, int32(i))))
+	}
+	return b
+}
+
+func (b *WorkBuilder) WithLRP(lrps ...rep.LRP) *WorkBuilder {
+	b.work.LRPs = append(b.work.LRPs, lrps...)
+	return b
+}
+
+func (b *WorkBuilder) WithTasks(count int) *WorkBuilder {
+	for i := 0; i < count; i++ {
+		b.work.Tasks = append(b.work.Tasks, pending(NewTask(fmt.Sprintf("work-tg-%d", len(b.work.Tasks)))))
+	}
+	return b
+}
+
+func (b *WorkBuilder) WithTask(tasks ...rep.Task) *WorkBuilder {
+	b.work.Tasks = append(b.work.Tasks, tasks...)
+	return b
+}
+
+// Build returns the work. The builder can be built again, and the work
+// shares no slices with it.
+func (b *WorkBuilder) Build() rep.Work {
+	work := b.work
+	work.LRPs = append([]rep.LRP{}, b.work.LRPs...)
+	work.Tasks = append([]rep.Task{}, b.work.Tasks...)
+	return work
+}
+
+func unallocated(lrp rep.LRP) rep.LRP {
+	lrp.State = models.ActualLRPStateUnclaimed
+	return lrp
+}
+
+func pending(task rep.Task) rep.Task {
+	task.State = models.Task_Pending
+	return task
+}
@@ -0,0 +1,65 @@
+package rep
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerTimingHeader is the header the cell reports the RequestTiming of a
+// request in, as Server-Timing metrics named queue and handler.
+const ServerTimingHeader = "Server-Timing"
+
+// RequestTiming is how long the cell held a request: in its accept queue,
+// from the request reaching its connection until a handler started on it,
+// and in the handler until it started to respond.
+type RequestTiming struct {
+	Queue   time.Duration
+	Handler time.Duration
+}
+
+// Header returns the Server-Timing header value of the timing.
+func (t RequestTiming) Header() string {
+	return fmt.Sprintf("queue;dur=%s, handler;dur=%s", milliseconds(t.Queue), milliseconds(t.Handler))
+}
+
+// RequestTimingFromHeader returns the RequestTiming the cell reported in
+// header, and whether it reported one.
+func RequestTimingFromHeader(header http.Header) (RequestTiming, bool) {
+	var timing RequestTiming
+	var queue, handler bool
+	for _, value := range header.Values(ServerTimingHeader) {
+		for _, metric := range strings.Split(value, ",") {
+			params := strings.Split(metric, ";")
+			name := strings.TrimSpace(params[0])
+			if name != "queue" && name != "handler" {
+				continue
+			}
+
+			for _, param := range params[1:] {
+				dur := strings.TrimPrefix(strings.TrimSpace(param), "dur=")
+				if dur == strings.TrimSpace(param) {
+					continue
+				}
+				ms, err := strconv.ParseFloat(dur, 64)
+				if err != nil || ms < 0 {
+					continue
+				}
+
+				d := time.Duration(ms * float64(time.Millisecond))
+				if name == "queue" {
+					timing.Queue, queue = d, true
+				} else {
+					timing.Handler, handler = d, true
+				}
+			}
+		}
+	}
+	return timing, queue && handler
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
@@ -0,0 +1,42 @@
+package rep_test
+
+import (
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequestTiming", func() {
+	It("round trips through the Server-Timing header", func() {
+		timing := rep.RequestTiming{Queue: 1500 * time.Microsecond, Handler: 2 * time.Second}
+		header := http.Header{}
+		header.Set(rep.ServerTimingHeader, timing.Header())
+
+		Expect(header.Get(rep.ServerTimingHeader)).To(Equal("queue;dur=1.500, handler;dur=2000.000"))
+		parsed, ok := rep.RequestTimingFromHeader(header)
+		Expect(ok).To(BeTrue())
+		Expect(parsed).To(Equal(timing))
+	})
+
+	It("ignores the other metrics of the header", func() {
+		header := http.Header{}
+		header.Add(rep.ServerTimingHeader, `cache;desc="hit", queue;dur=4`)
+		header.Add(rep.ServerTimingHeader, "handler;desc=perform;dur=6.5")
+
+		parsed, ok := rep.RequestTimingFromHeader(header)
+		Expect(ok).To(BeTrue())
+		Expect(parsed).To(Equal(rep.RequestTiming{Queue: 4 * time.Millisecond, Handler: 6500 * time.Microsecond}))
+	})
+
+	It("reports no timing when the header lacks either metric", func() {
+		header := http.Header{}
+		header.Set(rep.ServerTimingHeader, "queue;dur=4")
+
+		_, ok := rep.RequestTimingFromHeader(header)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -1,19 +1,32 @@
 package rep
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"net/url"
 	"sort"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/executor"
 	"code.cloudfoundry.org/executor/containermetrics"
 	"code.cloudfoundry.org/routing-info/internalroutes"
 )
 
 var ErrorIncompatibleRootfs = errors.New("rootfs not found")
 
+// ErrorIncompatibleVolumeDrivers is returned for work that mounts volumes
+// through drivers the cell does not advertise, as the volumes could not be
+// mounted on the cell.
+var ErrorIncompatibleVolumeDrivers = errors.New("volume drivers not found")
+
+// OS families a cell can advertise in its CellState.
+const (
+	OSFamilyLinux   = "linux"
+	OSFamilyWindows = "windows"
+)
+
 type CellState struct {
 	RepURL                  string `json:"rep_url"`
 	CellID                  string `json:"cell_id"`
@@ -25,11 +38,206 @@ type CellState struct {
 	Tasks                   []Task
 	StartingContainerCount  int
 	Zone                    string
+	InstanceID              string    `json:",omitempty"`
+	InstanceType            string    `json:",omitempty"`
+	OSFamily                string    `json:",omitempty"`
+	ImageOverhead           *Resource `json:",omitempty"`
 	Evacuating              bool
+	Maintenance             bool `json:",omitempty"`
+	Cordoned                bool `json:",omitempty"`
 	VolumeDrivers           []string
 	PlacementTags           []string
 	OptionalPlacementTags   []string
 	ProxyMemoryAllocationMB int
+	FeatureFlags            []string                   `json:",omitempty"`
+	Backends                []BackendState             `json:",omitempty"`
+	HostPressure            *HostPressure              `json:",omitempty"`
+	HostPressureWeight      float64                    `json:",omitempty"`
+	UsageForecast           *UsageForecast             `json:",omitempty"`
+	UsageForecastWeight     float64                    `json:",omitempty"`
+	RecentLRPs              []RecentLRP                `json:",omitempty"`
+	RecentLRPScoreBonus     float64                    `json:",omitempty"`
+	MaxContainerMemoryMB    int32                      `json:",omitempty"`
+	MaxContainerDiskMB      int32                      `json:",omitempty"`
+	RootFSDiskUsage         map[string]RootFSDiskUsage `json:",omitempty"`
+	AllowedCapabilities     []string                   `json:",omitempty"`
+	AllowedSeccompProfiles  []string                   `json:",omitempty"`
+	AllowedAppArmorProfiles []string                   `json:",omitempty"`
+	TotalHostPorts          int32                      `json:",omitempty"`
+	AvailableHostPorts      int32                      `json:",omitempty"`
+	CapacityReservations    []CapacityReservation      `json:",omitempty"`
+	MaintenanceWindows      []MaintenanceWindow        `json:",omitempty"`
+	MaintenanceScorePenalty float64                    `json:",omitempty"`
+	QuarantinedLRPs         []QuarantinedLRP           `json:",omitempty"`
+	PlacementBlocks         []PlacementBlock           `json:",omitempty"`
+	TenantUsage             []TenantUsage              `json:",omitempty"`
+	TenantCaps              *TenantCaps                `json:",omitempty"`
+	StackContainersLeft     map[string]int             `json:",omitempty"`
+	Lifecycles              []Lifecycle                `json:",omitempty"`
+	Cgroups                 *CgroupInfo                `json:",omitempty"`
+	FailureDomains          []string                   `json:",omitempty"`
+	FailureDomainPenalty    float64                    `json:",omitempty"`
+	LastBBSContact          int64                      `json:",omitempty"`
+	LastGardenContact       int64                      `json:",omitempty"`
+	SupportedIOLimits       []string                   `json:",omitempty"`
+	UnhealthyReasons        []string                   `json:",omitempty"`
+	Conditions              []string                   `json:",omitempty"`
+}
+
+// RecentLRP identifies an LRP instance that ran on the cell recently. A
+// crashed instance that is auctioned again starts faster on a cell that still
+// caches its droplet and image layers and has its volumes mounted.
+type RecentLRP struct {
+	ProcessGUID string
+	Index       int32
+}
+
+// QuarantinedLRP identifies an LRP instance that kept crashing on the cell.
+// The cell refuses to run it again until Until, given in unix nanoseconds.
+type QuarantinedLRP struct {
+	ProcessGUID string
+	Index       int32
+	Until       int64
+}
+
+// MaintenanceWindow is a period in which operators plan to restart the cell.
+// Long-running work placed on the cell shortly before it has to be evacuated
+// again soon after starting.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NextMaintenanceWindow returns the earliest of the cell's upcoming or
+// ongoing maintenance windows, or nil when none is scheduled.
+func (c *CellState) NextMaintenanceWindow() *MaintenanceWindow {
+	if len(c.MaintenanceWindows) == 0 {
+		return nil
+	}
+	return &c.MaintenanceWindows[0]
+}
+
+// RootFSDiskUsage is the disk taken up by the cached images of a rootfs
+// provider. The reclaimable part is used by images no container depends on,
+// and becomes available again when the cache is pruned.
+type RootFSDiskUsage struct {
+	DiskMB            int32
+	ReclaimableDiskMB int32
+}
+
+// HostPressure holds pressure signals of the host a cell runs on, which can
+// be under pressure even when its reservations leave room for more work. The
+// stall averages are percentages over the last 10 seconds.
+type HostPressure struct {
+	CPUSomeAvg10       float64
+	MemorySomeAvg10    float64
+	MemoryFullAvg10    float64
+	IOSomeAvg10        float64
+	IOFullAvg10        float64
+	LoadAverage1       float64
+	LoadAverage5       float64
+	LoadAverage15      float64
+	InodesUsedFraction float64
+}
+
+// Score returns the fraction of the last 10 seconds in which work on the host
+// stalled on its most contended resource.
+func (p *HostPressure) Score() float64 {
+	stalled := p.CPUSomeAvg10
+	for _, avg := range []float64{p.MemorySomeAvg10, p.IOSomeAvg10} {
+		if avg > stalled {
+			stalled = avg
+		}
+	}
+	return stalled / 100.0
+}
+
+// UsageForecast is the memory and disk the containers on a cell are forecast
+// to use HorizonSeconds from now, following the trend of their usage. A cell
+// whose containers are growing can fill up before its reservations run out.
+type UsageForecast struct {
+	HorizonSeconds int64
+	MemoryMB       int32
+	DiskMB         int32
+}
+
+// Score returns the fraction of the total resources of the cell that is
+// forecast to be used on its most used resource, up to 1.
+func (f *UsageForecast) Score(total *Resources) float64 {
+	used := 0.0
+	if total.MemoryMB > 0 {
+		used = float64(f.MemoryMB) / float64(total.MemoryMB)
+	}
+	if total.DiskMB > 0 {
+		if disk := float64(f.DiskMB) / float64(total.DiskMB); disk > used {
+			used = disk
+		}
+	}
+	if used > 1 {
+		return 1
+	}
+	return used
+}
+
+// BackendState describes one of the executor backends of a cell that
+// schedules onto more than one. The resources and rootfs providers of the
+// CellState are the aggregate of those of its backends, but a container only
+// fits the cell when it fits the backend it is placed on.
+type BackendState struct {
+	Name               string
+	RootFSProviders    RootFSProviders
+	AvailableResources Resources
+	TotalResources     Resources
+}
+
+// backendFor returns the backend work on rootfs is placed on: the first one
+// that provides the rootfs, as the cell routes the work it performs, or the
+// first one when none does. It returns nil for a cell without backends.
+func (c *CellState) backendFor(rootfs string) *BackendState {
+	if len(c.Backends) == 0 {
+		return nil
+	}
+
+	rootFSURL, err := ParseRootFS(rootfs)
+	if err == nil {
+		for i := range c.Backends {
+			if c.Backends[i].RootFSProviders.Match(*rootFSURL) {
+				return &c.Backends[i]
+			}
+		}
+	}
+	return &c.Backends[0]
+}
+
+// backendResourceMatch returns an InsufficientResourcesError when the backend
+// work on rootfs is placed on cannot fit a container requesting res, even when
+// the cell as a whole can. The work may also use held, the capacity a
+// reservation of the backend holds for it.
+func (c *CellState) backendResourceMatch(res *Resource, rootfs string, held Resources) error {
+	backend := c.backendFor(rootfs)
+	if backend == nil {
+		return nil
+	}
+
+	available := backend.AvailableResources
+	available.Add(held)
+
+	problems := map[string]struct{}{}
+	required := c.RequiredResource(res)
+	if available.DiskMB < required.DiskMB {
+		problems["disk"] = struct{}{}
+	}
+	if available.MemoryMB < required.MemoryMB {
+		problems["memory"] = struct{}{}
+	}
+	if available.Containers < 1 {
+		problems["containers"] = struct{}{}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return InsufficientResourcesError{Problems: problems}
 }
 
 func NewCellState(
@@ -68,30 +276,199 @@ func NewCellState(
 	}
 }
 
+// AddLRP takes the resources of lrp from the cell. An instance held by one of
+// the cell's capacity reservations takes the reserved capacity instead.
 func (c *CellState) AddLRP(lrp *LRP) {
-	c.AvailableResources.Subtract(&lrp.Resource)
+	lrp = c.resolvedLRP(lrp)
+	backend := c.backendFor(lrp.RootFs)
+	if i := c.reservationHolding(lrp); i >= 0 {
+		held := oneInstanceOf(&c.CapacityReservations[i])
+		c.AvailableResources.Add(held)
+		if backend != nil {
+			backend.AvailableResources.Add(held)
+		}
+		c.CapacityReservations[i].Instances--
+	}
+
+	required := c.RequiredResource(c.withRootFSOverhead(&lrp.Resource, lrp.RootFs))
+	c.AvailableResources.Subtract(&required)
+	if backend != nil {
+		backend.AvailableResources.Subtract(&required)
+	}
+	c.allocateHostPorts(&required)
 	c.StartingContainerCount += 1
 	c.LRPs = append(c.LRPs, *lrp)
+	c.TenantUsage = addTenantUsage(c.TenantUsage, lrp.Labels, &lrp.Resource)
+	takeStackContainer(c.StackContainersLeft, lrp.RootFs)
 }
 
 func (c *CellState) AddTask(task *Task) {
-	c.AvailableResources.Subtract(&task.Resource)
+	task = c.resolvedTask(task)
+	required := c.RequiredResource(c.withRootFSOverhead(&task.Resource, task.RootFs))
+	c.AvailableResources.Subtract(&required)
+	if backend := c.backendFor(task.RootFs); backend != nil {
+		backend.AvailableResources.Subtract(&required)
+	}
+	c.allocateHostPorts(&required)
 	c.StartingContainerCount += 1
 	c.Tasks = append(c.Tasks, *task)
+	c.TenantUsage = addTenantUsage(c.TenantUsage, task.Labels, &task.Resource)
+	takeStackContainer(c.StackContainersLeft, task.RootFs)
+}
+
+// RemoveLRP removes the instance with the instance guid of lrp from the cell
+// and gives back what AddLRP took for it, so that work applied to the state
+// can be rolled back. It returns false when the cell does not run the
+// instance. The instance is not given back to a capacity reservation it was
+// placed from.
+func (c *CellState) RemoveLRP(lrp *LRP) bool {
+	for i := range c.LRPs {
+		if c.LRPs[i].InstanceGUID != lrp.InstanceGUID {
+			continue
+		}
+		removed := c.LRPs[i]
+		c.LRPs = append(append([]LRP{}, c.LRPs[:i]...), c.LRPs[i+1:]...)
+		c.release(&removed.Resource, removed.RootFs, removed.Labels, removed.State == "")
+		return true
+	}
+	return false
+}
+
+// RemoveTask removes the task with the guid of task from the cell and gives
+// back what AddTask took for it. It returns false when the cell does not run
+// the task.
+func (c *CellState) RemoveTask(task *Task) bool {
+	for i := range c.Tasks {
+		if c.Tasks[i].TaskGuid != task.TaskGuid {
+			continue
+		}
+		removed := c.Tasks[i]
+		c.Tasks = append(append([]Task{}, c.Tasks[:i]...), c.Tasks[i+1:]...)
+		c.release(&removed.Resource, removed.RootFs, removed.Labels, removed.State == models.Task_Invalid)
+		return true
+	}
+	return false
+}
+
+// RemoveContainer removes the LRP instance or task running in the container
+// with guid from the cell, as RemoveLRP and RemoveTask do. It returns false
+// when neither runs in the container.
+func (c *CellState) RemoveContainer(guid string) bool {
+	for i := range c.LRPs {
+		if LRPContainerGuid(c.LRPs[i].ProcessGuid, c.LRPs[i].InstanceGUID) == guid {
+			return c.RemoveLRP(&c.LRPs[i])
+		}
+	}
+	return c.RemoveTask(&Task{TaskGuid: guid})
+}
+
+// release gives the cell back what AddLRP and AddTask took for work
+// requesting res on rootfs. Work that was placed rather than reported by the
+// cell, and so has no state, also gives back the starting container it was
+// counted as.
+func (c *CellState) release(res *Resource, rootfs string, labels map[string]string, placed bool) {
+	required := c.RequiredResource(c.withRootFSOverhead(res, rootfs))
+	released := Resources{
+		MemoryMB:       required.MemoryMB,
+		DiskMB:         required.DiskMB,
+		Containers:     1,
+		CPUEntitlement: required.CPUEntitlement,
+	}
+	c.AvailableResources.Add(released)
+	if backend := c.backendFor(rootfs); backend != nil {
+		backend.AvailableResources.Add(released)
+	}
+	if c.TotalHostPorts > 0 {
+		c.AvailableHostPorts += required.HostPorts
+	}
+	if placed && c.StartingContainerCount > 0 {
+		c.StartingContainerCount -= 1
+	}
+	c.TenantUsage = removeTenantUsage(c.TenantUsage, labels, res)
+	returnStackContainer(c.StackContainersLeft, rootfs)
 }
 
+// Copy returns a copy of the state whose LRPs, tasks and the accounting
+// AddLRP, AddTask and their removals update can be changed without changing
+// the state.
+func (c *CellState) Copy() CellState {
+	cell := *c
+	cell.LRPs = append([]LRP{}, c.LRPs...)
+	cell.Tasks = append([]Task{}, c.Tasks...)
+	cell.CapacityReservations = append([]CapacityReservation(nil), c.CapacityReservations...)
+	cell.TenantUsage = append([]TenantUsage(nil), c.TenantUsage...)
+	cell.Backends = append([]BackendState(nil), c.Backends...)
+	if c.StackContainersLeft != nil {
+		cell.StackContainersLeft = make(map[string]int, len(c.StackContainersLeft))
+		for stack, left := range c.StackContainersLeft {
+			cell.StackContainersLeft[stack] = left
+		}
+	}
+	return cell
+}
+
+// allocateHostPorts takes the host ports of res from the cell's pool when the
+// cell tracks one.
+func (c *CellState) allocateHostPorts(res *Resource) {
+	if c.TotalHostPorts > 0 {
+		c.AvailableHostPorts -= res.HostPorts
+	}
+}
+
+// RequiredResource returns the resources a container requesting res takes up
+// on the cell. On Windows cells every container also pays the ImageOverhead
+// of its container image on top of what it requests.
+func (c *CellState) RequiredResource(res *Resource) Resource {
+	required := res.Copy()
+	if c.OSFamily == OSFamilyWindows && c.ImageOverhead != nil {
+		required.MemoryMB += c.ImageOverhead.MemoryMB
+		required.DiskMB += c.ImageOverhead.DiskMB
+	}
+	return required
+}
+
+// ResourceMatch returns an InsufficientResourcesError when the cell cannot
+// fit a container requesting res. A cell that advertises a maximum container
+// size rejects larger containers even when it has the capacity for them. A
+// cell that tracks its host port pool or CPU entitlement rejects containers
+// needing more host ports or CPUs than it has left, and any cell rejects
+// containers requiring capabilities or profiles it does not allow.
 func (c *CellState) ResourceMatch(res *Resource) error {
 	problems := map[string]struct{}{}
+	required := c.RequiredResource(res)
 
-	if c.AvailableResources.DiskMB < res.DiskMB {
+	if c.AvailableResources.DiskMB < required.DiskMB {
 		problems["disk"] = struct{}{}
 	}
-	if c.AvailableResources.MemoryMB < res.MemoryMB {
+	if c.AvailableResources.MemoryMB < required.MemoryMB {
 		problems["memory"] = struct{}{}
 	}
+	if c.MaxContainerDiskMB > 0 && required.DiskMB > c.MaxContainerDiskMB {
+		problems["max container disk"] = struct{}{}
+	}
+	if c.MaxContainerMemoryMB > 0 && required.MemoryMB > c.MaxContainerMemoryMB {
+		problems["max container memory"] = struct{}{}
+	}
 	if c.AvailableResources.Containers < 1 {
 		problems["containers"] = struct{}{}
 	}
+	if c.TotalHostPorts > 0 && res.HostPorts > c.AvailableHostPorts {
+		problems["host ports"] = struct{}{}
+	}
+	if c.TotalResources.CPUEntitlement > 0 && required.CPUEntitlement > c.AvailableResources.CPUEntitlement {
+		problems["cpu entitlement"] = struct{}{}
+	}
+	if res.Security != nil {
+		if !toSet(res.Security.Capabilities).isSubset(toSet(c.AllowedCapabilities)) {
+			problems["capabilities"] = struct{}{}
+		}
+		if !permitsProfile(c.AllowedSeccompProfiles, res.Security.SeccompProfile) {
+			problems["seccomp profile"] = struct{}{}
+		}
+		if !permitsProfile(c.AllowedAppArmorProfiles, res.Security.AppArmorProfile) {
+			problems["apparmor profile"] = struct{}{}
+		}
+	}
 	if len(problems) == 0 {
 		return nil
 	}
@@ -99,6 +476,137 @@ func (c *CellState) ResourceMatch(res *Resource) error {
 	return InsufficientResourcesError{Problems: problems}
 }
 
+// LRPResourceMatch is ResourceMatch for an LRP instance. An instance held by
+// one of the cell's capacity reservations may also use the reserved
+// capacity, and ErrPlacementBlocked is returned for an instance the cell's
+// placement blocks keep off the cell, as is ErrPlacementTagsMismatch for an
+// instance whose placement tags the cell does not carry and
+// ErrorIncompatibleVolumeDrivers for one whose volume drivers it lacks. An
+// instance requesting host ports the cell already holds, that would take its
+// organization over the cell's TenantCaps, that has no containers of its
+// stack left, or that does not fit the backend of the cell it would be placed
+// on, does not fit either.
+func (c *CellState) LRPResourceMatch(lrp *LRP) error {
+	lrp = c.resolvedLRP(lrp)
+	if c.PlacementBlocked(lrp.ProcessGuid, lrp.Domain) {
+		return ErrPlacementBlocked
+	}
+	if !c.MatchPlacementTags(lrp.PlacementTags) {
+		return ErrPlacementTagsMismatch
+	}
+	if !c.MatchVolumeDrivers(lrp.VolumeDrivers) {
+		return ErrorIncompatibleVolumeDrivers
+	}
+
+	err := c.HostPortsMatch(&lrp.Resource)
+	if err != nil {
+		return err
+	}
+	held := Resources{}
+	if i := c.reservationHolding(lrp); i < 0 {
+		err = c.ResourceMatch(c.withRootFSOverhead(&lrp.Resource, lrp.RootFs))
+	} else {
+		held = oneInstanceOf(&c.CapacityReservations[i])
+		reserved := *c
+		reserved.AvailableResources.Add(held)
+		err = reserved.ResourceMatch(c.withRootFSOverhead(&lrp.Resource, lrp.RootFs))
+	}
+	if err != nil {
+		return err
+	}
+	err = c.backendResourceMatch(c.withRootFSOverhead(&lrp.Resource, lrp.RootFs), lrp.RootFs, held)
+	if err != nil {
+		return err
+	}
+	err = c.stackLimitMatch(lrp.RootFs)
+	if err != nil {
+		return err
+	}
+
+	return c.tenantCapMatch(lrp.Labels, &lrp.Resource)
+}
+
+// TaskResourceMatch is ResourceMatch for a task, returning
+// ErrPlacementBlocked for a task the cell's placement blocks keep off the
+// cell, ErrPlacementTagsMismatch for a task whose placement tags the cell
+// does not carry and ErrorIncompatibleVolumeDrivers for a task whose volume
+// drivers it lacks. A task requesting host ports the cell already holds, that
+// would take its organization over the cell's TenantCaps, that has no
+// containers of its stack left, or that does not fit the backend of the cell
+// it would be placed on, does not fit either.
+func (c *CellState) TaskResourceMatch(task *Task) error {
+	task = c.resolvedTask(task)
+	if c.PlacementBlocked("", task.Domain) {
+		return ErrPlacementBlocked
+	}
+	if !c.MatchPlacementTags(task.PlacementTags) {
+		return ErrPlacementTagsMismatch
+	}
+	if !c.MatchVolumeDrivers(task.VolumeDrivers) {
+		return ErrorIncompatibleVolumeDrivers
+	}
+
+	err := c.HostPortsMatch(&task.Resource)
+	if err != nil {
+		return err
+	}
+	err = c.ResourceMatch(c.withRootFSOverhead(&task.Resource, task.RootFs))
+	if err != nil {
+		return err
+	}
+	err = c.backendResourceMatch(c.withRootFSOverhead(&task.Resource, task.RootFs), task.RootFs, Resources{})
+	if err != nil {
+		return err
+	}
+	err = c.stackLimitMatch(task.RootFs)
+	if err != nil {
+		return err
+	}
+
+	return c.tenantCapMatch(task.Labels, &task.Resource)
+}
+
+// reservationHolding returns the index of a capacity reservation that holds
+// lrp on the backend it is placed on, or -1 when none does.
+func (c *CellState) reservationHolding(lrp *LRP) int {
+	backend := c.backendFor(lrp.RootFs)
+	for i := range c.CapacityReservations {
+		if backend != nil && c.CapacityReservations[i].Backend != backend.Name {
+			continue
+		}
+		if c.CapacityReservations[i].Holds(lrp.ProcessGuid, &lrp.Resource) {
+			return i
+		}
+	}
+	return -1
+}
+
+func oneInstanceOf(reservation *CapacityReservation) Resources {
+	return Resources{
+		MemoryMB:   reservation.Resource.MemoryMB,
+		DiskMB:     reservation.Resource.DiskMB,
+		Containers: 1,
+	}
+}
+
+// permitsProfile returns true when no custom profile is required or the
+// required one is permitted.
+func permitsProfile(permitted []string, profile string) bool {
+	if profile == "" {
+		return true
+	}
+	_, ok := toSet(permitted)[profile]
+	return ok
+}
+
+var ErrInvalidDiskQuotaGrowth = errors.New("the disk quota can only grow")
+
+// DiskQuotaGrowthRequest asks for the disk quota of a running container to
+// be grown to DiskMB.
+type DiskQuotaGrowthRequest struct {
+	DiskMB int32 `json:"disk_mb"`
+}
+
 type InsufficientResourcesError struct {
 	Problems map[string]struct{}
 }
@@ -116,15 +624,107 @@ func (i InsufficientResourcesError) Error() string {
 	return fmt.Sprintf("insufficient resources: %s", strings.Join(keys, ", "))
 }
 
-func (c CellState) ComputeScore(res *Resource, startingContainerWeight float64) float64 {
+// ComputeScore scores the cell for work that requires res by the resources
+// it would have left, following strategy, plus the penalties of its starting
+// containers and the pressure on its host. A nil strategy spreads. res
+// resolves its proportions as work placed on the first backend of the cell
+// does; ComputeLRPScore and ComputeTaskScore resolve them against the backend
+// the work is placed on.
+func (c CellState) ComputeScore(res *Resource, startingContainerWeight float64, strategy ScoringStrategy) float64 {
+	resolved := res.Resolve(c.proportionTotal(""))
+	return c.computeScore(&resolved, startingContainerWeight, strategy)
+}
+
+// ComputeTaskScore scores the cell for task like ComputeScore.
+func (c CellState) ComputeTaskScore(task *Task, startingContainerWeight float64, strategy ScoringStrategy) float64 {
+	return c.computeScore(&c.resolvedTask(task).Resource, startingContainerWeight, strategy)
+}
+
+func (c CellState) computeScore(resolved *Resource, startingContainerWeight float64, strategy ScoringStrategy) float64 {
 	remainingResources := c.AvailableResources.Copy()
-	remainingResources.Subtract(res)
+	required := c.RequiredResource(resolved)
+	remainingResources.Subtract(&required)
 	startingContainerScore := float64(c.StartingContainerCount) * startingContainerWeight
-	return remainingResources.ComputeScore(&c.TotalResources) + startingContainerScore
+	hostPressureScore := 0.0
+	if c.HostPressure != nil {
+		hostPressureScore = c.HostPressure.Score() * c.HostPressureWeight
+	}
+	usageForecastScore := 0.0
+	if c.UsageForecast != nil {
+		usageForecastScore = c.UsageForecast.Score(&c.TotalResources) * c.UsageForecastWeight
+	}
+	if strategy == nil {
+		strategy = SpreadStrategy{}
+	}
+	return strategy.ResourceScore(&remainingResources, &c.TotalResources) + startingContainerScore + hostPressureScore + usageForecastScore
+}
+
+// RecentlyHosted reports whether the instance at index of processGuid ran on
+// the cell recently.
+func (c *CellState) RecentlyHosted(processGuid string, index int32) bool {
+	for _, recent := range c.RecentLRPs {
+		if recent.ProcessGUID == processGuid && recent.Index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// PlacementBlocked reports whether one of the cell's placement blocks keeps
+// work of processGuid in domain off the cell.
+func (c *CellState) PlacementBlocked(processGuid, domain string) bool {
+	for i := range c.PlacementBlocks {
+		if c.PlacementBlocks[i].Blocks(processGuid, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Quarantined reports whether the cell quarantined the instance at index of
+// processGuid after it kept crashing.
+func (c *CellState) Quarantined(processGuid string, index int32) bool {
+	for _, quarantined := range c.QuarantinedLRPs {
+		if quarantined.ProcessGUID == processGuid && quarantined.Index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeLRPScore scores the cell for lrp like ComputeScore, lowering the
+// score by RecentLRPScoreBonus when the instance ran on the cell recently and
+// raising it by MaintenanceScorePenalty when a maintenance window is near.
+func (c CellState) ComputeLRPScore(lrp *LRP, startingContainerWeight float64, strategy ScoringStrategy) float64 {
+	score := c.computeScore(&c.resolvedLRP(lrp).Resource, startingContainerWeight, strategy)
+	if c.RecentlyHosted(lrp.ProcessGuid, lrp.Index) {
+		score -= c.RecentLRPScoreBonus
+	}
+	return score + c.MaintenanceScorePenalty
+}
+
+// RootFSOverhead returns the capacity the provider of rootfs reports the
+// rootfs takes up on the cell, on top of what its container requests.
+func (c *CellState) RootFSOverhead(rootfs string) Resource {
+	rootFSURL, err := ParseRootFS(rootfs)
+	if err != nil {
+		return Resource{}
+	}
+
+	return c.RootFSProviders.Overhead(*rootFSURL)
+}
+
+// withRootFSOverhead returns res grown by the RootFSOverhead of rootfs.
+func (c *CellState) withRootFSOverhead(res *Resource, rootfs string) *Resource {
+	overhead := c.RootFSOverhead(rootfs)
+	grown := res.Copy()
+	grown.MemoryMB += overhead.MemoryMB
+	grown.DiskMB += overhead.DiskMB
+	return &grown
 }
 
 func (c *CellState) MatchRootFS(rootfs string) bool {
-	rootFSURL, err := url.Parse(rootfs)
+	rootFSURL, err := ParseRootFS(rootfs)
 	if err != nil {
 		return false
 	}
@@ -194,33 +794,91 @@ type Resources struct {
 	MemoryMB   int32
 	DiskMB     int32
 	Containers int
+	// CPUEntitlement is the CPU entitlement of the containers, in CPUs. Cells
+	// that do not track entitlement leave it zero in their total resources.
+	CPUEntitlement float64 `json:",omitempty"`
 }
 
 func NewResources(memoryMb, diskMb int32, containerCount int) Resources {
-	return Resources{memoryMb, diskMb, containerCount}
+	return Resources{MemoryMB: memoryMb, DiskMB: diskMb, Containers: containerCount}
 }
 
 func (r *Resources) Copy() Resources {
 	return *r
 }
 
+func (r *Resources) Add(other Resources) {
+	r.MemoryMB += other.MemoryMB
+	r.DiskMB += other.DiskMB
+	r.Containers += other.Containers
+	r.CPUEntitlement += other.CPUEntitlement
+}
+
 func (r *Resources) Subtract(res *Resource) {
 	r.MemoryMB -= res.MemoryMB
 	r.DiskMB -= res.DiskMB
 	r.Containers -= 1
+	r.CPUEntitlement -= res.CPUEntitlement
 }
 
+// ComputeScore averages the fractions of the total resources that are used.
+// When the cell tracks CPU entitlement the fraction of it that is entitled
+// counts as well, so that a cell whose CPUs are heavily entitled does not win
+// on memory headroom alone.
 func (r *Resources) ComputeScore(total *Resources) float64 {
 	fractionUsedMemory := 1.0 - float64(r.MemoryMB)/float64(total.MemoryMB)
 	fractionUsedDisk := 1.0 - float64(r.DiskMB)/float64(total.DiskMB)
 	fractionUsedContainers := 1.0 - float64(r.Containers)/float64(total.Containers)
-	return (fractionUsedMemory + fractionUsedDisk + fractionUsedContainers) / 3.0
+	if total.CPUEntitlement <= 0 {
+		return (fractionUsedMemory + fractionUsedDisk + fractionUsedContainers) / 3.0
+	}
+
+	fractionUsedCPU := 1.0 - r.CPUEntitlement/total.CPUEntitlement
+	return (fractionUsedMemory + fractionUsedDisk + fractionUsedContainers + fractionUsedCPU) / 4.0
 }
 
 type Resource struct {
 	MemoryMB int32
 	DiskMB   int32
 	MaxPids  int32
+	Security *SecurityRequirements `json:",omitempty"`
+	// HostPorts is the number of host ports mapped to the ports of the
+	// container, including those of its TLS proxy.
+	HostPorts int32 `json:",omitempty"`
+	// StaticHostPorts are the specific host ports the work requests, which
+	// no other container on the cell may hold. They are mapped in order to
+	// the ports of the container, and those beyond its ports to the container
+	// port of the same number. In a cell state they are the host ports the
+	// container holds.
+	StaticHostPorts []uint16 `json:",omitempty"`
+	// CPUEntitlement is the number of CPUs, possibly fractional, the
+	// container is entitled to when the CPUs of the cell are contended.
+	CPUEntitlement float64 `json:",omitempty"`
+	// IOLimits throttle the disk and network I/O of the container on cells
+	// that support it.
+	IOLimits *IOLimits `json:",omitempty"`
+	// Proportional requests the memory and disk of the container as
+	// percentages of the total resources of the cell. The cell resolves them
+	// to MemoryMB and DiskMB when it places the work, and reports the
+	// resolved amounts.
+	Proportional *ProportionalResource `json:",omitempty"`
+}
+
+// SecurityRequirements are the kernel capabilities beyond the default set,
+// and the custom seccomp and AppArmor profiles, a work item requires. Cells
+// only accept work whose requirements they permit.
+type SecurityRequirements struct {
+	Capabilities    []string `json:",omitempty"`
+	SeccompProfile  string   `json:",omitempty"`
+	AppArmorProfile string   `json:",omitempty"`
+}
+
+// SecurityPermissions are the capabilities and profiles a cell permits work
+// items to require.
+type SecurityPermissions struct {
+	Capabilities     []string
+	SeccompProfiles  []string
+	AppArmorProfiles []string
 }
 
 func NewResource(memoryMb, diskMb int32, maxPids int32) Resource {
@@ -228,11 +886,18 @@ func NewResource(memoryMb, diskMb int32, maxPids int32) Resource {
 }
 
 func (r *Resource) Valid() bool {
-	return r.DiskMB >= 0 && r.MemoryMB >= 0
+	return r.DiskMB >= 0 && r.MemoryMB >= 0 && (r.Proportional == nil || r.Proportional.Validate() == nil)
 }
 
 func (r *Resource) Copy() Resource {
-	return NewResource(r.MemoryMB, r.DiskMB, r.MaxPids)
+	copied := NewResource(r.MemoryMB, r.DiskMB, r.MaxPids)
+	copied.Security = r.Security
+	copied.HostPorts = r.HostPorts
+	copied.StaticHostPorts = r.StaticHostPorts
+	copied.CPUEntitlement = r.CPUEntitlement
+	copied.IOLimits = r.IOLimits
+	copied.Proportional = r.Proportional
+	return copied
 }
 
 type PlacementConstraint struct {
@@ -254,11 +919,32 @@ type LRP struct {
 	models.ActualLRPKey
 	PlacementConstraint
 	Resource
-	State string `json:"state"`
+	State    string               `json:"state"`
+	Network  *ContainerNetwork    `json:"network,omitempty"`
+	Labels   map[string]string    `json:"labels,omitempty"`
+	Registry *RegistryCredentials `json:"registry,omitempty"`
+	// InitSteps run in order before the main process of the instance starts.
+	// Cells report the status of each of the steps of the instances they run.
+	InitSteps        []InitStep       `json:"init_steps,omitempty"`
+	InitStepStatuses []InitStepStatus `json:"init_step_statuses,omitempty"`
+	// TraceContext is the trace context of the auction that placed the
+	// instance, recorded on its container.
+	TraceContext *TraceContext `json:"trace_context,omitempty"`
+	// Directed is set on an instance an operator pinned to the cell.
+	Directed *DirectedPlacement `json:"directed,omitempty"`
+	// RequiredLifecycles are the lifecycles, as a name or a name/version, the
+	// cell needs to run the instance.
+	RequiredLifecycles []string `json:"required_lifecycles,omitempty"`
+	// Group names the unit of a Work the instance is placed with: the cell
+	// places all the LRP instances and tasks of a Work in the same group, or
+	// none of them.
+	Group string `json:"group,omitempty"`
+	// Provenance identifies the auction that placed the instance.
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
 func NewLRP(instanceGUID string, key models.ActualLRPKey, res Resource, pc PlacementConstraint) LRP {
-	return LRP{instanceGUID, key, pc, res, ""}
+	return LRP{instanceGUID, key, pc, res, "", nil, nil, nil, nil, nil, nil, nil, nil, "", nil}
 }
 
 func (lrp *LRP) Identifier() string {
@@ -266,7 +952,42 @@ func (lrp *LRP) Identifier() string {
 }
 
 func (lrp *LRP) Copy() LRP {
-	return NewLRP(lrp.InstanceGUID, lrp.ActualLRPKey, lrp.Resource, lrp.PlacementConstraint)
+	copied := NewLRP(lrp.InstanceGUID, lrp.ActualLRPKey, lrp.Resource, lrp.PlacementConstraint)
+	copied.Labels = lrp.Labels
+	copied.Registry = lrp.Registry
+	copied.InitSteps = lrp.InitSteps
+	copied.TraceContext = lrp.TraceContext
+	copied.Directed = lrp.Directed
+	copied.RequiredLifecycles = lrp.RequiredLifecycles
+	copied.Group = lrp.Group
+	copied.Provenance = lrp.Provenance
+	return copied
+}
+
+type lrpJSON LRP
+
+// MarshalJSON adds the typed forms of the sizes of the LRP to its fields.
+func (lrp LRP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		lrpJSON
+		typedSizes
+	}{lrpJSON(lrp), newTypedSizes(lrp.Resource)})
+}
+
+// UnmarshalJSON accepts sizes with units as well as the legacy number of
+// megabytes.
+func (lrp *LRP) UnmarshalJSON(payload []byte) error {
+	decoded := struct {
+		*lrpJSON
+		decodedSizes
+	}{lrpJSON: (*lrpJSON)(lrp)}
+
+	err := json.Unmarshal(payload, &decoded)
+	if err != nil {
+		return err
+	}
+	decoded.decodedSizes.apply(&lrp.Resource)
+	return nil
 }
 
 type LRPUpdate struct {
@@ -285,17 +1006,52 @@ func NewLRPUpdate(instanceGUID string, key models.ActualLRPKey, internalRoutes i
 	}
 }
 
+// StopLRPInstanceRequest identifies one of the LRP instances to stop with a
+// single request to StopLRPInstancesRoute.
+type StopLRPInstanceRequest struct {
+	models.ActualLRPKey
+	models.ActualLRPInstanceKey
+}
+
+func NewStopLRPInstanceRequest(key models.ActualLRPKey, instanceKey models.ActualLRPInstanceKey) StopLRPInstanceRequest {
+	return StopLRPInstanceRequest{ActualLRPKey: key, ActualLRPInstanceKey: instanceKey}
+}
+
+// StopLRPInstanceResult is the outcome of stopping one of the instances of a
+// StopLRPInstancesRoute request. Error is empty when the instance is
+// stopping.
+type StopLRPInstanceResult struct {
+	ProcessGuid  string `json:"process_guid"`
+	InstanceGuid string `json:"instance_guid"`
+	Error        string `json:"error,omitempty"`
+}
+
 type Task struct {
 	TaskGuid string
 	Domain   string
 	PlacementConstraint
 	Resource
-	State  models.Task_State `json:"state"`
-	Failed bool              `json:"failed"`
+	State    models.Task_State    `json:"state"`
+	Failed   bool                 `json:"failed"`
+	Network  *ContainerNetwork    `json:"network,omitempty"`
+	Labels   map[string]string    `json:"labels,omitempty"`
+	Registry *RegistryCredentials `json:"registry,omitempty"`
+	// TraceContext is the trace context of the auction that placed the task,
+	// recorded on its container.
+	TraceContext *TraceContext `json:"trace_context,omitempty"`
+	// Directed is set on a task an operator pinned to the cell.
+	Directed *DirectedPlacement `json:"directed,omitempty"`
+	// RequiredLifecycles are the lifecycles, as a name or a name/version, the
+	// cell needs to run the task.
+	RequiredLifecycles []string `json:"required_lifecycles,omitempty"`
+	// Group names the unit of a Work the task is placed with, as for an LRP.
+	Group string `json:"group,omitempty"`
+	// Provenance identifies the auction that placed the task.
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
 func NewTask(guid string, domain string, res Resource, pc PlacementConstraint) Task {
-	return Task{guid, domain, pc, res, models.Task_Invalid, false}
+	return Task{guid, domain, pc, res, models.Task_Invalid, false, nil, nil, nil, nil, nil, nil, "", nil}
 }
 
 func (task *Task) Identifier() string {
@@ -306,10 +1062,84 @@ func (task Task) Copy() Task {
 	return task
 }
 
+type taskJSON Task
+
+// MarshalJSON adds the typed forms of the sizes of the task to its fields.
+func (task Task) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		taskJSON
+		typedSizes
+	}{taskJSON(task), newTypedSizes(task.Resource)})
+}
+
+// UnmarshalJSON accepts sizes with units as well as the legacy number of
+// megabytes.
+func (task *Task) UnmarshalJSON(payload []byte) error {
+	decoded := struct {
+		*taskJSON
+		decodedSizes
+	}{taskJSON: (*taskJSON)(task)}
+
+	err := json.Unmarshal(payload, &decoded)
+	if err != nil {
+		return err
+	}
+	decoded.decodedSizes.apply(&task.Resource)
+	return nil
+}
+
+// Network properties the container networking stack records on a container
+// and that the rep reports as part of its ContainerNetwork.
+const (
+	NetworkNameProperty   = "network_name"
+	PolicyGroupIDProperty = "policy_group_id"
+)
+
+// ContainerNetwork is the network assignment of a container, as reported by
+// the executor once the container networking stack has set it up.
+type ContainerNetwork struct {
+	InstanceAddress string                 `json:"instance_address,omitempty"`
+	HostAddress     string                 `json:"host_address,omitempty"`
+	NetworkName     string                 `json:"network_name,omitempty"`
+	PolicyGroupID   string                 `json:"policy_group_id,omitempty"`
+	Ports           []executor.PortMapping `json:"ports,omitempty"`
+}
+
+// Work is the LRP instances and tasks auctioned to a cell. In the response to
+// a perform, it is the work the cell could not allocate, and
+// RegistryValidationFailures and DuplicateWorkFailures explain the work
+// rejected because of its registry credentials or because a concurrent
+// perform holds it.
 type Work struct {
-	LRPs   []LRP
-	Tasks  []Task
-	CellID string `json:"cell_id,omitempty"`
+	LRPs                       []LRP
+	Tasks                      []Task
+	CellID                     string                      `json:"cell_id,omitempty"`
+	RegistryValidationFailures []RegistryValidationFailure `json:"registry_validation_failures,omitempty"`
+	DuplicateWorkFailures      []DuplicateWorkFailure      `json:"duplicate_work_failures,omitempty"`
+	ImageSizeFailures          []ImageSizeFailure          `json:"image_size_failures,omitempty"`
+	DirectedPlacementFailures  []DirectedPlacementFailure  `json:"directed_placement_failures,omitempty"`
+	LifecycleFailures          []LifecycleFailure          `json:"lifecycle_failures,omitempty"`
+	ImageDigestFailures        []ImageDigestFailure        `json:"image_digest_failures,omitempty"`
+	WorkGroupFailures          []WorkGroupFailure          `json:"work_group_failures,omitempty"`
+	PolicyFailures             []PolicyFailure             `json:"policy_failures,omitempty"`
+	HostPortConflictFailures   []HostPortConflictFailure   `json:"host_port_conflict_failures,omitempty"`
+}
+
+var ErrDuplicateWork = errors.New("the work is already being performed by a concurrent request")
+
+// DuplicateWorkFailure records the LRP instance or task of a Work that was
+// rejected because a concurrent perform on the cell already holds it.
+type DuplicateWorkFailure struct {
+	ProcessGuid string `json:"process_guid,omitempty"`
+	Index       int32  `json:"index,omitempty"`
+	TaskGuid    string `json:"task_guid,omitempty"`
+	Error       string `json:"error"`
+}
+
+// BatchSize is the number of LRPs and tasks in the work, which is what
+// Limits.MaxWorkBatchSize bounds.
+func (w Work) BatchSize() int {
+	return len(w.LRPs) + len(w.Tasks)
 }
 
 // StackPathMap maps aliases to rootFS paths on the system.
@@ -328,7 +1158,7 @@ func (m StackPathMap) PathForRootFS(rootFS string) (string, error) {
 		return rootFS, nil
 	}
 
-	url, err := url.Parse(rootFS)
+	url, err := ParseRootFS(rootFS)
 	if err != nil {
 		return "", err
 	}
@@ -367,9 +1197,19 @@ type LRPMetric struct {
 	ProcessGUID  string `json:"process_guid"`
 	Index        int32  `json:"index"`
 	containermetrics.CachedContainerMetrics
+	Network *ContainerNetwork `json:"network,omitempty"`
+	// LogBytesDropped is the log output of the container the cell dropped
+	// for exceeding its log rate limit.
+	LogBytesDropped int64 `json:"log_bytes_dropped,omitempty"`
+	// IOLimits are the I/O limits the cell applied to the container.
+	IOLimits *IOLimits `json:"io_limits,omitempty"`
 }
 
 type TaskMetric struct {
 	TaskGUID string `json:"task_guid"`
 	containermetrics.CachedContainerMetrics
+	Network *ContainerNetwork `json:"network,omitempty"`
+	// LogBytesDropped and IOLimits are as for an LRPMetric.
+	LogBytesDropped int64     `json:"log_bytes_dropped,omitempty"`
+	IOLimits        *IOLimits `json:"io_limits,omitempty"`
 }
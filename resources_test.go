@@ -180,11 +180,563 @@ var _ = Describe("Resources", func() {
 			})
 		})
 
+		Context("when the cell advertises a maximum container size", func() {
+			BeforeEach(func() {
+				cellState.MaxContainerMemoryMB = 100
+				cellState.MaxContainerDiskMB = 200
+			})
+
+			It("does not return an error for containers within the limits", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the container exceeds the maximum memory", func() {
+				BeforeEach(func() {
+					requiredResource.MemoryMB = 150
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("insufficient resources: max container memory"))
+				})
+			})
+
+			Context("when the container exceeds the maximum disk", func() {
+				BeforeEach(func() {
+					requiredResource.DiskMB = 250
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("insufficient resources: max container disk"))
+				})
+			})
+		})
+
+		Context("when the cell tracks its host port pool", func() {
+			BeforeEach(func() {
+				cellState.TotalHostPorts = 100
+				cellState.AvailableHostPorts = 2
+				requiredResource.HostPorts = 2
+			})
+
+			It("does not return an error when enough host ports are left", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the container needs more host ports than are left", func() {
+				BeforeEach(func() {
+					requiredResource.HostPorts = 3
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("insufficient resources: host ports"))
+				})
+			})
+
+			It("takes the host ports of added work from the pool", func() {
+				lrp := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), requiredResource, rep.PlacementConstraint{RootFs: linuxRootFSURL})
+				cellState.AddLRP(&lrp)
+				Expect(cellState.AvailableHostPorts).To(BeEquivalentTo(0))
+			})
+		})
+
+		Context("when the cell tracks its CPU entitlement", func() {
+			BeforeEach(func() {
+				cellState.TotalResources.CPUEntitlement = 4
+				cellState.AvailableResources.CPUEntitlement = 0.5
+				requiredResource.CPUEntitlement = 0.5
+			})
+
+			It("does not return an error when enough CPUs are left", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the container is entitled to more CPUs than are left", func() {
+				BeforeEach(func() {
+					requiredResource.CPUEntitlement = 0.75
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("insufficient resources: cpu entitlement"))
+				})
+			})
+		})
+
+		Context("when the cell does not track its CPU entitlement", func() {
+			BeforeEach(func() {
+				requiredResource.CPUEntitlement = 2
+			})
+
+			It("does not return an error", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the container has security requirements", func() {
+			BeforeEach(func() {
+				requiredResource.Security = &rep.SecurityRequirements{
+					Capabilities:    []string{"CAP_NET_ADMIN"},
+					SeccompProfile:  "unconfined",
+					AppArmorProfile: "diego-privileged",
+				}
+			})
+
+			It("returns an error when the cell does not allow them", func() {
+				Expect(err).To(MatchError("insufficient resources: apparmor profile, capabilities, seccomp profile"))
+			})
+
+			Context("when the cell allows them", func() {
+				BeforeEach(func() {
+					cellState.AllowedCapabilities = []string{"CAP_NET_ADMIN", "CAP_SYS_PTRACE"}
+					cellState.AllowedSeccompProfiles = []string{"unconfined"}
+					cellState.AllowedAppArmorProfiles = []string{"diego-privileged"}
+				})
+
+				It("does not return an error", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("when the cell allows only some of the capabilities", func() {
+				BeforeEach(func() {
+					requiredResource.Security.Capabilities = []string{"CAP_NET_ADMIN", "CAP_SYS_ADMIN"}
+					cellState.AllowedCapabilities = []string{"CAP_NET_ADMIN"}
+					cellState.AllowedSeccompProfiles = []string{"unconfined"}
+					cellState.AllowedAppArmorProfiles = []string{"diego-privileged"}
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("insufficient resources: capabilities"))
+				})
+			})
+		})
+
 		Context("when there is sufficient room", func() {
 			It("does not return an error", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
+
+		Context("when the cell is a Windows cell with an image overhead", func() {
+			BeforeEach(func() {
+				cellState.OSFamily = rep.OSFamilyWindows
+				cellState.ImageOverhead = &rep.Resource{MemoryMB: 100, DiskMB: 1000}
+			})
+
+			Context("when the overhead does not fit next to the resource", func() {
+				BeforeEach(func() {
+					requiredResource.MemoryMB = 900
+					requiredResource.DiskMB = 1000
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError("insufficient resources: disk, memory"))
+				})
+			})
+
+			Context("when the resource and the overhead fit", func() {
+				It("does not return an error", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+
+		Context("when a Linux cell reports an image overhead", func() {
+			BeforeEach(func() {
+				cellState.OSFamily = rep.OSFamilyLinux
+				cellState.ImageOverhead = &rep.Resource{MemoryMB: 100, DiskMB: 1000}
+				requiredResource.MemoryMB = 900
+				requiredResource.DiskMB = 1000
+			})
+
+			It("ignores the overhead", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("RequiredResource", func() {
+		It("returns the requested resource on Linux cells", func() {
+			resource := rep.NewResource(10, 20, 30)
+			Expect(cellState.RequiredResource(&resource)).To(Equal(resource))
+		})
+
+		It("adds the image overhead on Windows cells", func() {
+			cellState.OSFamily = rep.OSFamilyWindows
+			cellState.ImageOverhead = &rep.Resource{MemoryMB: 100, DiskMB: 1000}
+
+			resource := rep.NewResource(10, 20, 30)
+			Expect(cellState.RequiredResource(&resource)).To(Equal(rep.NewResource(110, 1020, 30)))
+		})
+
+		It("subtracts the image overhead from the available resources when adding work", func() {
+			cellState.OSFamily = rep.OSFamilyWindows
+			cellState.ImageOverhead = &rep.Resource{MemoryMB: 100, DiskMB: 1000}
+
+			cellState.AddLRP(buildLRP("ig-6", "pg-5", "domain", 0, models.PreloadedRootFS("windows"), 10, 20, 30, []string{}, []string{}, models.ActualLRPStateClaimed))
+			Expect(cellState.AvailableResources).To(Equal(rep.NewResources(840, 880, 2)))
+		})
+	})
+
+	Describe("ComputeScore", func() {
+		var resource rep.Resource
+
+		BeforeEach(func() {
+			resource = rep.NewResource(50, 100, 10)
+		})
+
+		It("ignores host pressure that is not weighted", func() {
+			score := cellState.ComputeScore(&resource, 0, nil)
+			cellState.HostPressure = &rep.HostPressure{MemorySomeAvg10: 50}
+			Expect(cellState.ComputeScore(&resource, 0, nil)).To(Equal(score))
+		})
+
+		It("adds the weighted host pressure of the most contended resource", func() {
+			score := cellState.ComputeScore(&resource, 0, nil)
+			cellState.HostPressure = &rep.HostPressure{CPUSomeAvg10: 10, MemorySomeAvg10: 50, IOSomeAvg10: 20}
+			cellState.HostPressureWeight = 0.5
+			Expect(cellState.ComputeScore(&resource, 0, nil)).To(BeNumerically("~", score+0.25, 0.0001))
+		})
+
+		It("adds the weighted fraction of the most used resource forecast to be used", func() {
+			cellState.TotalResources = rep.NewResources(1000, 2000, 10)
+			score := cellState.ComputeScore(&resource, 0, nil)
+			cellState.UsageForecast = &rep.UsageForecast{HorizonSeconds: 300, MemoryMB: 800, DiskMB: 500}
+			cellState.UsageForecastWeight = 0.5
+			Expect(cellState.ComputeScore(&resource, 0, nil)).To(BeNumerically("~", score+0.4, 0.0001))
+		})
+
+		It("caps the usage forecast at the total resources of the cell", func() {
+			cellState.TotalResources = rep.NewResources(1000, 2000, 10)
+			score := cellState.ComputeScore(&resource, 0, nil)
+			cellState.UsageForecast = &rep.UsageForecast{HorizonSeconds: 300, MemoryMB: 3000, DiskMB: 500}
+			cellState.UsageForecastWeight = 0.5
+			Expect(cellState.ComputeScore(&resource, 0, nil)).To(BeNumerically("~", score+0.5, 0.0001))
+		})
+
+		Context("when the cell tracks CPU entitlement", func() {
+			BeforeEach(func() {
+				cellState.TotalResources.CPUEntitlement = 4
+				cellState.AvailableResources.CPUEntitlement = 1
+				resource.CPUEntitlement = 0.5
+			})
+
+			It("weighs the entitled fraction of the CPUs with the other resources", func() {
+				Expect(cellState.ComputeScore(&resource, 0, nil)).To(BeNumerically("~", (0.1+0.1+0.8+0.875)/4, 0.0001))
+			})
+
+			It("scores a heavily entitled cell worse than one with the same memory headroom", func() {
+				entitled := cellState.ComputeScore(&resource, 0, nil)
+				cellState.AvailableResources.CPUEntitlement = 4
+				Expect(cellState.ComputeScore(&resource, 0, nil)).To(BeNumerically("<", entitled))
+			})
+
+			It("takes the entitlement of added LRPs from the available entitlement", func() {
+				lrp := *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+				lrp.CPUEntitlement = 0.25
+				cellState.AddLRP(&lrp)
+				Expect(cellState.AvailableResources.CPUEntitlement).To(Equal(0.75))
+			})
+		})
+
+		Context("when the cell does not track CPU entitlement", func() {
+			It("ignores the entitlement of the resource", func() {
+				score := cellState.ComputeScore(&resource, 0, nil)
+				resource.CPUEntitlement = 2
+				Expect(cellState.ComputeScore(&resource, 0, nil)).To(Equal(score))
+			})
+		})
+	})
+
+	Describe("ComputeLRPScore", func() {
+		var lrp rep.LRP
+
+		BeforeEach(func() {
+			lrp = *buildLRP("ig-1", "pg-1", "domain", 2, linuxRootFSURL, 50, 100, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			cellState.RecentLRPScoreBonus = 0.1
+		})
+
+		It("scores like ComputeScore when the instance did not run on the cell", func() {
+			cellState.RecentLRPs = []rep.RecentLRP{{ProcessGUID: "pg-1", Index: 1}, {ProcessGUID: "pg-2", Index: 2}}
+			Expect(cellState.ComputeLRPScore(&lrp, 0.25, nil)).To(Equal(cellState.ComputeScore(&lrp.Resource, 0.25, nil)))
+		})
+
+		It("lowers the score when the same instance ran on the cell recently", func() {
+			cellState.RecentLRPs = []rep.RecentLRP{{ProcessGUID: "pg-1", Index: 2}}
+			Expect(cellState.ComputeLRPScore(&lrp, 0.25, nil)).To(BeNumerically("~", cellState.ComputeScore(&lrp.Resource, 0.25, nil)-0.1, 0.0001))
+		})
+
+		It("raises the score when a maintenance window is near", func() {
+			cellState.MaintenanceScorePenalty = 0.5
+			Expect(cellState.ComputeLRPScore(&lrp, 0.25, nil)).To(BeNumerically("~", cellState.ComputeScore(&lrp.Resource, 0.25, nil)+0.5, 0.0001))
+		})
+	})
+
+	Describe("Capacity reservations", func() {
+		var reservedLRP, otherLRP rep.LRP
+
+		BeforeEach(func() {
+			cellState.AvailableResources = rep.NewResources(100, 100, 1)
+			cellState.CapacityReservations = []rep.CapacityReservation{
+				{ID: "reservation-id", ProcessGuid: "pg-new", Resource: rep.NewResource(500, 500, 10), Instances: 2},
+			}
+			reservedLRP = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 400, 400, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			otherLRP = *buildLRP("ig-other", "pg-other", "domain", 0, linuxRootFSURL, 400, 400, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+		})
+
+		Describe("LRPResourceMatch", func() {
+			It("lets instances held by a reservation use the reserved capacity", func() {
+				Expect(cellState.LRPResourceMatch(&reservedLRP)).To(Succeed())
+			})
+
+			It("does not let other instances use the reserved capacity", func() {
+				Expect(cellState.LRPResourceMatch(&otherLRP)).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"memory": {}, "disk": {}}}))
+			})
+
+			It("does not let instances larger than the reserved shape use the reserved capacity", func() {
+				reservedLRP.MemoryMB = 600
+				Expect(cellState.LRPResourceMatch(&reservedLRP)).To(HaveOccurred())
+			})
+		})
+
+		Describe("AddLRP", func() {
+			It("takes the capacity of a held instance from its reservation", func() {
+				cellState.AddLRP(&reservedLRP)
+				Expect(cellState.CapacityReservations[0].Instances).To(Equal(int32(1)))
+				Expect(cellState.AvailableResources).To(Equal(rep.NewResources(200, 200, 1)))
+			})
+
+			It("takes the capacity of other instances from the unreserved capacity", func() {
+				cellState.AddLRP(&otherLRP)
+				Expect(cellState.CapacityReservations[0].Instances).To(Equal(int32(2)))
+				Expect(cellState.AvailableResources).To(Equal(rep.NewResources(-300, -300, 0)))
+			})
+		})
+	})
+
+	Describe("Backends", func() {
+		var windowsRootFSURL string
+		var linuxLRP, windowsLRP rep.LRP
+		var windowsTask rep.Task
+
+		BeforeEach(func() {
+			windowsRootFSURL = models.PreloadedRootFS("windows")
+			cellState.RootFSProviders = rep.RootFSProviders{models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("linux", "windows")}
+			cellState.AvailableResources = rep.NewResources(2000, 2000, 6)
+			cellState.Backends = []rep.BackendState{
+				{
+					Name:               "default",
+					RootFSProviders:    rep.RootFSProviders{models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("linux")},
+					AvailableResources: rep.NewResources(800, 800, 3),
+				},
+				{
+					Name:               "windows",
+					RootFSProviders:    rep.RootFSProviders{models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("windows")},
+					AvailableResources: rep.NewResources(100, 100, 3),
+				},
+			}
+			linuxLRP = *buildLRP("ig-linux", "pg-linux", "domain", 0, linuxRootFSURL, 500, 500, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			windowsLRP = *buildLRP("ig-windows", "pg-windows", "domain", 0, windowsRootFSURL, 500, 500, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			windowsTask = *buildTask("tg-windows", "domain", windowsRootFSURL, 500, 500, 10, []string{}, []string{}, models.Task_Pending, false)
+		})
+
+		It("fits work against the backend that provides its rootfs", func() {
+			Expect(cellState.LRPResourceMatch(&linuxLRP)).To(Succeed())
+			Expect(cellState.LRPResourceMatch(&windowsLRP)).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"memory": {}, "disk": {}}}))
+			Expect(cellState.TaskResourceMatch(&windowsTask)).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"memory": {}, "disk": {}}}))
+		})
+
+		It("takes the work placed on the cell from its backend", func() {
+			copied := cellState.Copy()
+			copied.AddLRP(&linuxLRP)
+			Expect(copied.Backends[0].AvailableResources).To(Equal(rep.NewResources(300, 300, 2)))
+			Expect(copied.Backends[1].AvailableResources).To(Equal(rep.NewResources(100, 100, 3)))
+			Expect(cellState.Backends[0].AvailableResources).To(Equal(rep.NewResources(800, 800, 3)))
+
+			smallLRP := linuxLRP
+			smallLRP.InstanceGUID = "ig-linux-2"
+			Expect(copied.LRPResourceMatch(&smallLRP)).To(HaveOccurred())
+
+			Expect(copied.RemoveLRP(&linuxLRP)).To(BeTrue())
+			Expect(copied.Backends[0].AvailableResources).To(Equal(rep.NewResources(800, 800, 3)))
+		})
+
+		It("lets instances use only the capacity reserved on their backend", func() {
+			cellState.CapacityReservations = []rep.CapacityReservation{
+				{ID: "reservation-id", ProcessGuid: "pg-windows", Backend: "windows", Resource: rep.NewResource(500, 500, 10), Instances: 1},
+			}
+			Expect(cellState.LRPResourceMatch(&windowsLRP)).To(Succeed())
+
+			linuxInstance := *buildLRP("ig-linux", "pg-windows", "domain", 0, linuxRootFSURL, 900, 900, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			Expect(cellState.LRPResourceMatch(&linuxInstance)).To(HaveOccurred())
+
+			copied := cellState.Copy()
+			copied.AddLRP(&windowsLRP)
+			Expect(copied.CapacityReservations[0].Instances).To(Equal(int32(0)))
+			Expect(copied.Backends[1].AvailableResources).To(Equal(rep.NewResources(100, 100, 3)))
+		})
+	})
+
+	Describe("Placement blocks", func() {
+		var lrp rep.LRP
+		var task rep.Task
+
+		BeforeEach(func() {
+			lrp = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			task = *buildTask("tg-new", "domain", linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.Task_Pending, false)
+		})
+
+		It("matches work no block names", func() {
+			cellState.PlacementBlocks = []rep.PlacementBlock{{ID: "block-id", ProcessGuid: "pg-other"}, {ID: "other-block-id", Domain: "other-domain"}}
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(Succeed())
+			Expect(cellState.TaskResourceMatch(&task)).To(Succeed())
+		})
+
+		It("does not match the instances of a blocked process guid", func() {
+			cellState.PlacementBlocks = []rep.PlacementBlock{{ID: "block-id", ProcessGuid: "pg-new"}}
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(MatchError(rep.ErrPlacementBlocked))
+			Expect(cellState.TaskResourceMatch(&task)).To(Succeed())
+		})
+
+		It("does not match the instances and tasks of a blocked domain", func() {
+			cellState.PlacementBlocks = []rep.PlacementBlock{{ID: "block-id", Domain: "domain"}}
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(MatchError(rep.ErrPlacementBlocked))
+			Expect(cellState.TaskResourceMatch(&task)).To(MatchError(rep.ErrPlacementBlocked))
+		})
+	})
+
+	Describe("Placement tags", func() {
+		var lrp rep.LRP
+		var task rep.Task
+
+		BeforeEach(func() {
+			lrp = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			task = *buildTask("tg-new", "domain", linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.Task_Pending, false)
+			cellState.PlacementTags = []string{"pci"}
+			cellState.OptionalPlacementTags = []string{"gpu"}
+		})
+
+		It("matches work carrying the tags the cell requires", func() {
+			lrp.PlacementTags = []string{"pci"}
+			task.PlacementTags = []string{"pci", "gpu"}
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(Succeed())
+			Expect(cellState.TaskResourceMatch(&task)).To(Succeed())
+		})
+
+		It("does not match work without the tags the cell requires", func() {
+			Expect(cellState.LRPResourceMatch(&lrp)).To(MatchError(rep.ErrPlacementTagsMismatch))
+			Expect(cellState.TaskResourceMatch(&task)).To(MatchError(rep.ErrPlacementTagsMismatch))
+		})
+
+		It("does not match work with tags the cell does not carry", func() {
+			lrp.PlacementTags = []string{"pci", "windows"}
+			task.PlacementTags = []string{"pci", "windows"}
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(MatchError(rep.ErrPlacementTagsMismatch))
+			Expect(cellState.TaskResourceMatch(&task)).To(MatchError(rep.ErrPlacementTagsMismatch))
+		})
+	})
+
+	Describe("Volume drivers", func() {
+		var lrp rep.LRP
+		var task rep.Task
+
+		BeforeEach(func() {
+			cellState.VolumeDrivers = []string{"nfs", "smb"}
+		})
+
+		It("matches work whose volume drivers the cell advertises", func() {
+			lrp = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 10, 10, 10, []string{}, []string{"nfs"}, models.ActualLRPStateUnclaimed)
+			task = *buildTask("tg-new", "domain", linuxRootFSURL, 10, 10, 10, []string{}, []string{"nfs", "smb"}, models.Task_Pending, false)
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(Succeed())
+			Expect(cellState.TaskResourceMatch(&task)).To(Succeed())
+		})
+
+		It("matches work without volumes", func() {
+			lrp = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+			task = *buildTask("tg-new", "domain", linuxRootFSURL, 10, 10, 10, []string{}, []string{}, models.Task_Pending, false)
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(Succeed())
+			Expect(cellState.TaskResourceMatch(&task)).To(Succeed())
+		})
+
+		It("does not match work with a volume driver the cell does not advertise", func() {
+			lrp = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 10, 10, 10, []string{}, []string{"nfs", "cephfs"}, models.ActualLRPStateUnclaimed)
+			task = *buildTask("tg-new", "domain", linuxRootFSURL, 10, 10, 10, []string{}, []string{"cephfs"}, models.Task_Pending, false)
+
+			Expect(cellState.LRPResourceMatch(&lrp)).To(MatchError(rep.ErrorIncompatibleVolumeDrivers))
+			Expect(cellState.TaskResourceMatch(&task)).To(MatchError(rep.ErrorIncompatibleVolumeDrivers))
+		})
+	})
+
+	Describe("Removing work", func() {
+		var (
+			lrp  rep.LRP
+			task rep.Task
+		)
+
+		BeforeEach(func() {
+			cellState.TotalHostPorts = 10
+			cellState.AvailableHostPorts = 10
+			cellState.StackContainersLeft = map[string]int{"linux": 4}
+
+			lrp = *buildLRP("ig-new", "pg-new", "domain", 0, linuxRootFSURL, 100, 200, 10, []string{}, []string{}, "")
+			lrp.HostPorts = 2
+			lrp.Labels = map[string]string{rep.OrganizationLabel: "org", rep.SpaceLabel: "space"}
+			task = *buildTask("tg-new", "domain", linuxRootFSURL, 50, 60, 10, []string{}, []string{}, models.Task_Invalid, false)
+		})
+
+		expectUntouched := func(state rep.CellState) {
+			Expect(state.AvailableResources).To(Equal(rep.NewResources(950, 1900, 3)))
+			Expect(state.AvailableHostPorts).To(BeEquivalentTo(10))
+			Expect(state.StartingContainerCount).To(Equal(7))
+			Expect(state.StackContainersLeft).To(Equal(map[string]int{"linux": 4}))
+			Expect(state.TenantUsage).To(BeEmpty())
+			Expect(state.LRPs).To(HaveLen(5))
+			Expect(state.Tasks).To(HaveLen(2))
+		}
+
+		It("gives back what adding the work took", func() {
+			cellState.AddLRP(&lrp)
+			cellState.AddTask(&task)
+			Expect(cellState.AvailableResources).To(Equal(rep.NewResources(800, 1640, 1)))
+
+			Expect(cellState.RemoveLRP(&lrp)).To(BeTrue())
+			Expect(cellState.RemoveTask(&task)).To(BeTrue())
+			expectUntouched(cellState)
+		})
+
+		It("removes the work running in a container", func() {
+			cellState.AddLRP(&lrp)
+			cellState.AddTask(&task)
+
+			Expect(cellState.RemoveContainer(rep.LRPContainerGuid("pg-new", "ig-new"))).To(BeTrue())
+			Expect(cellState.RemoveContainer("tg-new")).To(BeTrue())
+			expectUntouched(cellState)
+		})
+
+		It("does not count work the cell reported as starting when removing it", func() {
+			Expect(cellState.RemoveLRP(&rep.LRP{InstanceGUID: "ig-1"})).To(BeTrue())
+			Expect(cellState.StartingContainerCount).To(Equal(7))
+			Expect(cellState.AvailableResources).To(Equal(rep.NewResources(960, 1920, 4)))
+		})
+
+		It("leaves the state untouched when the cell does not run the work", func() {
+			Expect(cellState.RemoveLRP(&lrp)).To(BeFalse())
+			Expect(cellState.RemoveTask(&task)).To(BeFalse())
+			Expect(cellState.RemoveContainer("missing")).To(BeFalse())
+			expectUntouched(cellState)
+		})
+
+		It("does not change the LRPs of copies of the state", func() {
+			copied := cellState
+			Expect(cellState.RemoveLRP(&rep.LRP{InstanceGUID: "ig-1"})).To(BeTrue())
+			Expect(copied.LRPs[0].InstanceGUID).To(Equal("ig-1"))
+		})
 	})
 
 	Describe("StackPathMap", func() {
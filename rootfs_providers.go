@@ -3,6 +3,7 @@ package rep
 import (
 	"encoding/json"
 	"net/url"
+	"sync"
 )
 
 type RootFSProvider interface {
@@ -10,6 +11,23 @@ type RootFSProvider interface {
 	Match(url.URL) bool
 }
 
+// MergeableRootFSProvider is implemented by providers that can be combined
+// with another provider of the same scheme, as done for the backends of a
+// cell. Merge reports false when the providers cannot be combined, in which
+// case the scheme accepts any rootfs.
+type MergeableRootFSProvider interface {
+	RootFSProvider
+	Merge(other RootFSProvider) (RootFSProvider, bool)
+}
+
+// RootFSOverheadProvider is implemented by providers whose rootfses take up
+// capacity on the cell beyond what the container requests, for instance
+// images the cell has to download and unpack.
+type RootFSOverheadProvider interface {
+	RootFSProvider
+	Overhead(url.URL) Resource
+}
+
 type RootFSProviderType string
 
 const (
@@ -17,6 +35,69 @@ const (
 	RootFSProviderTypeFixedSet  RootFSProviderType = "fixed_set"
 )
 
+// RootFSProviderDecoder decodes a provider from its JSON representation,
+// which holds its type next to the fields it needs.
+type RootFSProviderDecoder func(payload []byte) (RootFSProvider, error)
+
+var rootFSProviderRegistry = struct {
+	lock     sync.RWMutex
+	decoders map[RootFSProviderType]RootFSProviderDecoder
+	schemes  map[string]func() RootFSProvider
+}{
+	decoders: map[RootFSProviderType]RootFSProviderDecoder{
+		RootFSProviderTypeArbitrary: func([]byte) (RootFSProvider, error) {
+			return ArbitraryRootFSProvider{}, nil
+		},
+		RootFSProviderTypeFixedSet: func(payload []byte) (RootFSProvider, error) {
+			var provider FixedSetRootFSProvider
+			err := provider.UnmarshalJSON(payload)
+			return provider, err
+		},
+	},
+	schemes: map[string]func() RootFSProvider{},
+}
+
+// RegisterRootFSProviderType registers the decoder of providers of
+// providerType, so that cell states advertising them can be decoded. Both
+// the cell and its clients need to register the types they exchange.
+func RegisterRootFSProviderType(providerType RootFSProviderType, decode RootFSProviderDecoder) {
+	rootFSProviderRegistry.lock.Lock()
+	defer rootFSProviderRegistry.lock.Unlock()
+
+	rootFSProviderRegistry.decoders[providerType] = decode
+}
+
+// RegisterRootFSScheme registers the provider a cell uses for the rootfs
+// scheme when it is configured as one of its supported providers. Schemes
+// without a registered provider accept any rootfs.
+func RegisterRootFSScheme(scheme string, newProvider func() RootFSProvider) {
+	rootFSProviderRegistry.lock.Lock()
+	defer rootFSProviderRegistry.lock.Unlock()
+
+	rootFSProviderRegistry.schemes[scheme] = newProvider
+}
+
+// RootFSProviderForScheme returns the provider registered for scheme, or an
+// ArbitraryRootFSProvider when there is none.
+func RootFSProviderForScheme(scheme string) RootFSProvider {
+	rootFSProviderRegistry.lock.RLock()
+	newProvider, ok := rootFSProviderRegistry.schemes[scheme]
+	rootFSProviderRegistry.lock.RUnlock()
+
+	if !ok {
+		return ArbitraryRootFSProvider{}
+	}
+	return newProvider()
+}
+
+func rootFSProviderDecoder(providerType RootFSProviderType) (RootFSProviderDecoder, bool) {
+	rootFSProviderRegistry.lock.RLock()
+	defer rootFSProviderRegistry.lock.RUnlock()
+
+	decode, ok := rootFSProviderRegistry.decoders[providerType]
+	return decode, ok
+}
+
 type RootFSProviders map[string]RootFSProvider
 
 func (p RootFSProviders) Copy() RootFSProviders {
@@ -27,6 +108,27 @@ func (p RootFSProviders) Copy() RootFSProviders {
 	return pCopy
 }
 
+// Merge returns the providers that match any rootfs matched by either p or
+// other.
+func (p RootFSProviders) Merge(other RootFSProviders) RootFSProviders {
+	merged := p.Copy()
+	for scheme, provider := range other {
+		existing, ok := merged[scheme]
+		if !ok {
+			merged[scheme] = provider
+			continue
+		}
+
+		merged[scheme] = ArbitraryRootFSProvider{}
+		if mergeable, ok := existing.(MergeableRootFSProvider); ok {
+			if combined, ok := mergeable.Merge(provider); ok {
+				merged[scheme] = combined
+			}
+		}
+	}
+	return merged
+}
+
 func (p RootFSProviders) Match(rootFS url.URL) bool {
 	provider, ok := p[rootFS.Scheme]
 	if !ok {
@@ -36,6 +138,17 @@ func (p RootFSProviders) Match(rootFS url.URL) bool {
 	return provider.Match(rootFS)
 }
 
+// Overhead returns the capacity the provider of rootFS reports it takes up
+// on the cell, if any.
+func (p RootFSProviders) Overhead(rootFS url.URL) Resource {
+	provider, ok := p[rootFS.Scheme].(RootFSOverheadProvider)
+	if !ok || !provider.Match(rootFS) {
+		return Resource{}
+	}
+
+	return provider.Overhead(rootFS)
+}
+
 func (providers *RootFSProviders) UnmarshalJSON(payload []byte) error {
 	var providerEnvelope map[string]json.RawMessage
 	err := json.Unmarshal(payload, &providerEnvelope)
@@ -56,6 +169,16 @@ func (providers *RootFSProviders) UnmarshalJSON(payload []byte) error {
 	return nil
 }
 
+// GobEncode encodes the providers in their JSON form, which holds the type
+// of each provider, so that they survive the binary cell state snapshot.
+func (providers RootFSProviders) GobEncode() ([]byte, error) {
+	return json.Marshal(providers)
+}
+
+func (providers *RootFSProviders) GobDecode(payload []byte) error {
+	return providers.UnmarshalJSON(payload)
+}
+
 type rootFSProviderEnvelope struct {
 	Type RootFSProviderType `json:"type"`
 }
@@ -67,17 +190,27 @@ func unmarshalRootFSProvider(payload []byte) (RootFSProvider, error) {
 		return nil, err
 	}
 
-	switch envelope.Type {
-	case RootFSProviderTypeArbitrary:
-		return ArbitraryRootFSProvider{}, nil
-	case RootFSProviderTypeFixedSet:
-		var provider FixedSetRootFSProvider
-		err := provider.UnmarshalJSON(payload)
-		return provider, err
+	decode, ok := rootFSProviderDecoder(envelope.Type)
+	if !ok {
+		return UnknownRootFSProvider{ProviderType: envelope.Type, Payload: payload}, nil
 	}
 
-	return nil, nil
+	return decode(payload)
+}
 
+// UnknownRootFSProvider stands in for a provider of a type that is not
+// registered. It matches no rootfs and is serialized as it was received.
+type UnknownRootFSProvider struct {
+	ProviderType RootFSProviderType
+	Payload      json.RawMessage
+}
+
+func (provider UnknownRootFSProvider) Type() RootFSProviderType { return provider.ProviderType }
+
+func (UnknownRootFSProvider) Match(url.URL) bool { return false }
+
+func (provider UnknownRootFSProvider) MarshalJSON() ([]byte, error) {
+	return provider.Payload, nil
 }
 
 type ArbitraryRootFSProvider struct{}
@@ -106,6 +239,23 @@ func (provider FixedSetRootFSProvider) Match(rootfs url.URL) bool {
 	return provider.FixedSet.Contains(rootfs.Opaque)
 }
 
+// Merge unions the sets of two fixed-set providers.
+func (provider FixedSetRootFSProvider) Merge(other RootFSProvider) (RootFSProvider, bool) {
+	otherSet, ok := other.(FixedSetRootFSProvider)
+	if !ok {
+		return nil, false
+	}
+
+	union := NewStringSet()
+	for rootfs := range provider.FixedSet {
+		union[rootfs] = struct{}{}
+	}
+	for rootfs := range otherSet.FixedSet {
+		union[rootfs] = struct{}{}
+	}
+	return FixedSetRootFSProvider{FixedSet: union}, true
+}
+
 func (provider FixedSetRootFSProvider) MarshalJSON() ([]byte, error) {
 	setPayload, err := json.Marshal(provider.FixedSet)
 	if err != nil {
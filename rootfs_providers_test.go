@@ -3,7 +3,9 @@ package rep_test
 import (
 	"encoding/json"
 	"net/url"
+	"strings"
 
+	"code.cloudfoundry.org/bbs/models"
 	"code.cloudfoundry.org/rep"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -115,4 +117,140 @@ var _ = Describe("RootFSProviders", func() {
 			})
 		})
 	})
+
+	Describe("Merge", func() {
+		It("unions fixed sets of the same scheme", func() {
+			merged := providers.Merge(rep.RootFSProviders{
+				"bar": rep.NewFixedSetRootFSProvider("quux", "corge"),
+			})
+
+			Expect(merged["bar"]).To(Equal(rep.NewFixedSetRootFSProvider("baz", "quux", "corge")))
+		})
+
+		It("prefers an arbitrary provider over a fixed set", func() {
+			merged := providers.Merge(rep.RootFSProviders{"bar": arbitrary})
+			Expect(merged["bar"]).To(Equal(arbitrary))
+		})
+
+		It("adds schemes only one side provides", func() {
+			merged := providers.Merge(rep.RootFSProviders{"grault": fixedSet})
+
+			Expect(merged).To(HaveLen(3))
+			Expect(merged["foo"]).To(Equal(arbitrary))
+			Expect(merged["grault"]).To(Equal(fixedSet))
+		})
+
+		It("does not modify the receiver", func() {
+			providers.Merge(rep.RootFSProviders{"grault": fixedSet})
+			Expect(providers).To(HaveLen(2))
+		})
+	})
+
+	Describe("registered provider types", func() {
+		BeforeEach(func() {
+			rep.RegisterRootFSProviderType(prefixProviderType, func(payload []byte) (rep.RootFSProvider, error) {
+				var provider prefixRootFSProvider
+				err := json.Unmarshal(payload, &provider)
+				return provider, err
+			})
+			rep.RegisterRootFSScheme("oci", func() rep.RootFSProvider {
+				return prefixRootFSProvider{ProviderType: prefixProviderType, Prefix: "registry.example.com/", DiskMB: 512}
+			})
+		})
+
+		It("deserializes them with the registered decoder", func() {
+			var providersResult rep.RootFSProviders
+			err := json.Unmarshal([]byte(`{"oci": {"type": "prefix", "prefix": "registry.example.com/", "disk_mb": 512}}`), &providersResult)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(providersResult).To(Equal(rep.RootFSProviders{"oci": rep.RootFSProviderForScheme("oci")}))
+		})
+
+		It("uses their match logic", func() {
+			providers := rep.RootFSProviders{"oci": rep.RootFSProviderForScheme("oci")}
+
+			rootFS, err := url.Parse("oci://registry.example.com/some/image")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers.Match(*rootFS)).To(BeTrue())
+
+			rootFS, err = url.Parse("oci://elsewhere.example.com/some/image")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers.Match(*rootFS)).To(BeFalse())
+		})
+
+		It("reports the overhead of the rootfses they match", func() {
+			providers := rep.RootFSProviders{"oci": rep.RootFSProviderForScheme("oci")}
+
+			rootFS, err := url.Parse("oci://registry.example.com/some/image")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers.Overhead(*rootFS)).To(Equal(rep.Resource{DiskMB: 512}))
+
+			rootFS, err = url.Parse("oci://elsewhere.example.com/some/image")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers.Overhead(*rootFS)).To(BeZero())
+		})
+
+		It("counts the overhead against the capacity of the cell", func() {
+			state := rep.CellState{
+				RootFSProviders:    rep.RootFSProviders{"oci": rep.RootFSProviderForScheme("oci")},
+				AvailableResources: rep.Resources{MemoryMB: 1024, DiskMB: 1024, Containers: 2},
+			}
+			lrp := rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.NewResource(128, 768, 10), rep.PlacementConstraint{RootFs: "oci://registry.example.com/some/image"})
+
+			Expect(state.LRPResourceMatch(&lrp)).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"disk": {}}}))
+
+			lrp.Resource.DiskMB = 512
+			Expect(state.LRPResourceMatch(&lrp)).To(Succeed())
+			state.AddLRP(&lrp)
+			Expect(state.AvailableResources.DiskMB).To(BeZero())
+		})
+
+		It("accepts any rootfs of a scheme without a registered provider", func() {
+			Expect(rep.RootFSProviderForScheme("s3")).To(Equal(rep.ArbitraryRootFSProvider{}))
+		})
+	})
+
+	Describe("unregistered provider types", func() {
+		var unknownJSON string
+
+		BeforeEach(func() {
+			unknownJSON = `{"type": "not-registered", "hosts": ["some-host"]}`
+		})
+
+		It("do not match any rootfs", func() {
+			var providersResult rep.RootFSProviders
+			err := json.Unmarshal([]byte(`{"foo": `+unknownJSON+`}`), &providersResult)
+			Expect(err).NotTo(HaveOccurred())
+
+			rootFS, err := url.Parse("foo://some-host/path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providersResult.Match(*rootFS)).To(BeFalse())
+		})
+
+		It("serialize as they were received", func() {
+			var providersResult rep.RootFSProviders
+			err := json.Unmarshal([]byte(`{"foo": `+unknownJSON+`}`), &providersResult)
+			Expect(err).NotTo(HaveOccurred())
+
+			payload, err := json.Marshal(providersResult)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(MatchJSON(`{"foo": ` + unknownJSON + `}`))
+		})
+	})
 })
+
+const prefixProviderType rep.RootFSProviderType = "prefix"
+
+type prefixRootFSProvider struct {
+	ProviderType rep.RootFSProviderType `json:"type"`
+	Prefix       string                 `json:"prefix"`
+	DiskMB       int32                  `json:"disk_mb"`
+}
+
+func (p prefixRootFSProvider) Type() rep.RootFSProviderType { return p.ProviderType }
+
+func (p prefixRootFSProvider) Match(rootFS url.URL) bool {
+	return strings.HasPrefix(rootFS.Host+rootFS.Path, p.Prefix)
+}
+
+func (p prefixRootFSProvider) Overhead(url.URL) rep.Resource { return rep.Resource{DiskMB: p.DiskMB} }
@@ -0,0 +1,100 @@
+package rep
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RootFSParseError is returned for a rootfs that cannot be parsed even by the
+// tolerant rules of ParseRootFS.
+type RootFSParseError struct {
+	RootFS string
+	Reason string
+}
+
+func (e *RootFSParseError) Error() string {
+	return fmt.Sprintf("invalid rootfs %q: %s", e.RootFS, e.Reason)
+}
+
+// ParseRootFS parses a rootfs reference such as preloaded:cflinuxfs4 or
+// docker:///cloudfoundry/grace#v1. A reference url.Parse accepts once trimmed
+// is parsed as it does. Any other reference, such as one holding a stray
+// percent sign or a host with a tag in place of a port, which docker accepts,
+// is split by hand:
+//
+//   - the scheme is lowercased
+//   - the fragment follows the first '#' and the query the first '?'
+//   - a reference starting with "//" after its scheme has a host up to the
+//     next '/' and a path from there, and any other an opaque part
+//   - no part is unescaped
+//
+// A reference without a valid scheme, or holding control characters, fails
+// with a *RootFSParseError.
+func ParseRootFS(rootfs string) (*url.URL, error) {
+	reference := strings.TrimSpace(rootfs)
+	if reference == "" {
+		return nil, &RootFSParseError{RootFS: rootfs, Reason: "empty reference"}
+	}
+
+	if parsed, err := url.Parse(reference); err == nil {
+		return parsed, nil
+	}
+
+	for _, r := range reference {
+		if r < ' ' || r == 0x7f {
+			return nil, &RootFSParseError{RootFS: rootfs, Reason: "contains control characters"}
+		}
+	}
+
+	colon := strings.IndexByte(reference, ':')
+	if colon <= 0 {
+		return nil, &RootFSParseError{RootFS: rootfs, Reason: "missing scheme"}
+	}
+	scheme := strings.ToLower(reference[:colon])
+	if !validScheme(scheme) {
+		return nil, &RootFSParseError{RootFS: rootfs, Reason: fmt.Sprintf("invalid scheme %q", scheme)}
+	}
+
+	parsed := &url.URL{Scheme: scheme}
+	rest := reference[colon+1:]
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		parsed.Fragment = rest[i+1:]
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		parsed.RawQuery = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "//"):
+		authority := rest[2:]
+		if i := strings.IndexByte(authority, '/'); i >= 0 {
+			parsed.Host = authority[:i]
+			parsed.Path = authority[i:]
+		} else {
+			parsed.Host = authority
+		}
+	case strings.HasPrefix(rest, "/"):
+		parsed.Path = rest
+	default:
+		parsed.Opaque = rest
+	}
+
+	return parsed, nil
+}
+
+// validScheme reports whether scheme is a letter followed by letters, digits,
+// '+', '-' or '.', as RFC 3986 defines it.
+func validScheme(scheme string) bool {
+	for i, r := range scheme {
+		switch {
+		case 'a' <= r && r <= 'z':
+		case i > 0 && ('0' <= r && r <= '9' || r == '+' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package rep_test
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseRootFS", func() {
+	It("parses references url.Parse accepts as it does", func() {
+		for _, rootfs := range []string{"preloaded:cflinuxfs4", "docker:///cloudfoundry/grace#v1", "preloaded+layer:cflinuxfs4?layer=https://blobs/layer.tgz"} {
+			expected, err := url.Parse(rootfs)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed, err := rep.ParseRootFS(rootfs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(expected))
+		}
+	})
+
+	It("trims surrounding whitespace", func() {
+		parsed, err := rep.ParseRootFS(" preloaded:cflinuxfs4\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Opaque).To(Equal("cflinuxfs4"))
+	})
+
+	It("splits references url.Parse rejects without unescaping them", func() {
+		parsed, err := rep.ParseRootFS("Docker://registry.example.com:5000/team/app%zz#v1%")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*parsed).To(Equal(url.URL{Scheme: "docker", Host: "registry.example.com:5000", Path: "/team/app%zz", Fragment: "v1%"}))
+
+		parsed, err = rep.ParseRootFS("docker://grace:v1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Host).To(Equal("grace:v1"))
+
+		parsed, err = rep.ParseRootFS("preloaded:cflinux%fs4?layer=%")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*parsed).To(Equal(url.URL{Scheme: "preloaded", Opaque: "cflinux%fs4", RawQuery: "layer=%"}))
+	})
+
+	It("fails with a typed error on references it cannot parse", func() {
+		for _, rootfs := range []string{"", "cflinux%fs4", "1docker:///grace%", "docker:///grace\x00%"} {
+			_, err := rep.ParseRootFS(rootfs)
+			Expect(err).To(BeAssignableToTypeOf(&rep.RootFSParseError{}), rootfs)
+		}
+	})
+
+	It("lets the cell match rootfses url.Parse rejects", func() {
+		state := rep.CellState{RootFSProviders: rep.RootFSProviders{"docker": rep.ArbitraryRootFSProvider{}}}
+		Expect(state.MatchRootFS("docker:///cloudfoundry/grace#v1%")).To(BeTrue())
+		Expect(state.MatchRootFS("%docker")).To(BeFalse())
+	})
+})
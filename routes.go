@@ -3,19 +3,51 @@ package rep
 import "github.com/tedsuo/rata"
 
 const (
-	StateRoute            = "STATE"
-	ContainerMetricsRoute = "ContainerMetrics"
-	PerformRoute          = "PERFORM"
+	StateRoute                 = "STATE"
+	ContainerMetricsRoute      = "ContainerMetrics"
+	ContainerMetricsBatchRoute = "ContainerMetricsBatch"
+	PerformRoute               = "PERFORM"
+	CanPlaceRoute              = "CanPlace"
+	InfoRoute                  = "Info"
+	ContainersRoute            = "Containers"
+	ContainerEventsRoute       = "ContainerEvents"
+	CapacitySummaryRoute       = "CapacitySummary"
 
 	UpdateLRPInstanceRoute    = "UpdateLRPInstance"
 	UpdateLRPInstanceRoute_r0 = "UpdateLRPInstance_r0"
 	StopLRPInstanceRoute      = "StopLRPInstance"
+	StopLRPInstancesRoute     = "StopLRPInstances"
 	CancelTaskRoute           = "CancelTask"
+	ReserveCapacityRoute      = "ReserveCapacity"
+	ReleaseCapacityRoute      = "ReleaseCapacity"
+	GrowDiskQuotaRoute        = "GrowDiskQuota"
 
 	SimResetRoute = "RESET"
 
-	PingRoute     = "Ping"
-	EvacuateRoute = "Evacuate"
+	PingRoute                   = "Ping"
+	EvacuateRoute               = "Evacuate"
+	StartMaintenanceRoute       = "StartMaintenance"
+	StopMaintenanceRoute        = "StopMaintenance"
+	PlannedRestartRoute         = "PlannedRestart"
+	DebugConfigRoute            = "DebugConfig"
+	OpenAPIRoute                = "OpenAPI"
+	ImageCachePruneRoute        = "ImageCachePrune"
+	BlockPlacementRoute         = "BlockPlacement"
+	UnblockPlacementRoute       = "UnblockPlacement"
+	PlacementBlocksRoute        = "PlacementBlocks"
+	PlacementTagsRoute          = "PlacementTags"
+	UpdatePlacementTagsRoute    = "UpdatePlacementTags"
+	FragmentationRoute          = "Fragmentation"
+	ConsistencyRoute            = "Consistency"
+	CacheStatsRoute             = "CacheStats"
+	SelfTestRoute               = "SelfTest"
+	CapacityReportRoute         = "CapacityReport"
+	AuctionRoutesRoute          = "AuctionRoutes"
+	CloseAuctionRoutesRoute     = "CloseAuctionRoutes"
+	OpenAuctionRoutesRoute      = "OpenAuctionRoutes"
+	RelaxHealthCheckRoute       = "RelaxHealthCheck"
+	RestoreHealthCheckRoute     = "RestoreHealthCheck"
+	HealthCheckRelaxationsRoute = "HealthCheckRelaxations"
 )
 
 func NewRoutes(networkAccessible bool) rata.Routes {
@@ -26,11 +58,21 @@ func NewRoutes(networkAccessible bool) rata.Routes {
 			rata.Route{Path: "/state", Method: "GET", Name: StateRoute},
 			rata.Route{Path: "/container_metrics", Method: "GET", Name: ContainerMetricsRoute},
 			rata.Route{Path: "/work", Method: "POST", Name: PerformRoute},
+			rata.Route{Path: "/can_place", Method: "POST", Name: CanPlaceRoute},
+			rata.Route{Path: "/info", Method: "GET", Name: InfoRoute},
+			rata.Route{Path: "/containers", Method: "GET", Name: ContainersRoute},
+			rata.Route{Path: "/containers/metrics", Method: "GET", Name: ContainerMetricsBatchRoute},
+			rata.Route{Path: "/containers/:container_guid/events", Method: "GET", Name: ContainerEventsRoute},
+			rata.Route{Path: "/capacity", Method: "GET", Name: CapacitySummaryRoute},
 
 			rata.Route{Path: "/v2/lrps/:process_guid/instances/:instance_guid", Method: "PUT", Name: UpdateLRPInstanceRoute},
 			rata.Route{Path: "/v1/lrps/:process_guid/instances/:instance_guid", Method: "PUT", Name: UpdateLRPInstanceRoute_r0},
 			rata.Route{Path: "/v1/lrps/:process_guid/instances/:instance_guid/stop", Method: "POST", Name: StopLRPInstanceRoute},
+			rata.Route{Path: "/v1/lrps/instances/stop", Method: "POST", Name: StopLRPInstancesRoute},
 			rata.Route{Path: "/v1/tasks/:task_guid/cancel", Method: "POST", Name: CancelTaskRoute},
+			rata.Route{Path: "/v1/capacity_reservations", Method: "POST", Name: ReserveCapacityRoute},
+			rata.Route{Path: "/v1/capacity_reservations/:reservation_id", Method: "DELETE", Name: ReleaseCapacityRoute},
+			rata.Route{Path: "/v1/containers/:container_guid/disk_quota", Method: "PUT", Name: GrowDiskQuotaRoute},
 
 			rata.Route{Path: "/sim/reset", Method: "POST", Name: SimResetRoute},
 		)
@@ -38,12 +80,43 @@ func NewRoutes(networkAccessible bool) rata.Routes {
 		routes = append(routes,
 			rata.Route{Path: "/ping", Method: "GET", Name: PingRoute},
 			rata.Route{Path: "/evacuate", Method: "POST", Name: EvacuateRoute},
+			rata.Route{Path: "/maintenance", Method: "POST", Name: StartMaintenanceRoute},
+			rata.Route{Path: "/maintenance", Method: "DELETE", Name: StopMaintenanceRoute},
+			rata.Route{Path: "/planned_restart", Method: "POST", Name: PlannedRestartRoute},
 		)
 	}
 	return routes
 
 }
 
+// NewAdminRoutes returns the operator facing routes. They are served on the
+// admin listener when one is configured and on the localhost-only listener
+// otherwise.
+func NewAdminRoutes() rata.Routes {
+	return rata.Routes{
+		{Path: "/debug/config", Method: "GET", Name: DebugConfigRoute},
+		{Path: "/openapi.json", Method: "GET", Name: OpenAPIRoute},
+		{Path: "/image_cache/prune", Method: "POST", Name: ImageCachePruneRoute},
+		{Path: "/placement_blocks", Method: "POST", Name: BlockPlacementRoute},
+		{Path: "/placement_blocks/:block_id", Method: "DELETE", Name: UnblockPlacementRoute},
+		{Path: "/placement_blocks", Method: "GET", Name: PlacementBlocksRoute},
+		{Path: "/placement_tags", Method: "GET", Name: PlacementTagsRoute},
+		{Path: "/placement_tags", Method: "PUT", Name: UpdatePlacementTagsRoute},
+		{Path: "/debug/fragmentation", Method: "GET", Name: FragmentationRoute},
+		{Path: "/debug/consistency", Method: "GET", Name: ConsistencyRoute},
+		{Path: "/cache_stats", Method: "GET", Name: CacheStatsRoute},
+		{Path: "/selftest", Method: "POST", Name: SelfTestRoute},
+		{Path: "/reports/capacity", Method: "GET", Name: CapacityReportRoute},
+		{Path: "/auction_routes", Method: "GET", Name: AuctionRoutesRoute},
+		{Path: "/auction_routes/close", Method: "POST", Name: CloseAuctionRoutesRoute},
+		{Path: "/auction_routes/open", Method: "POST", Name: OpenAuctionRoutesRoute},
+		{Path: "/health_check_relaxations", Method: "POST", Name: RelaxHealthCheckRoute},
+		{Path: "/health_check_relaxations/:process_guid/:index", Method: "DELETE", Name: RestoreHealthCheckRoute},
+		{Path: "/health_check_relaxations", Method: "GET", Name: HealthCheckRelaxationsRoute},
+	}
+}
+
 var RoutesLocalhostOnly = NewRoutes(false)
 var RoutesNetworkAccessible = NewRoutes(true)
-var Routes = append(RoutesLocalhostOnly, RoutesNetworkAccessible...)
+var RoutesAdmin = NewAdminRoutes()
+var Routes = append(append(RoutesLocalhostOnly, RoutesNetworkAccessible...), RoutesAdmin...)
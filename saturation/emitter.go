@@ -0,0 +1,76 @@
+package saturation
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	"code.cloudfoundry.org/lager"
+)
+
+// Gauge returns the current value of a measure of saturation, such as the
+// depth of a queue or the percentage of a pool in use.
+type Gauge func() int
+
+// Emitter emits every one of its gauges every interval, so that saturation
+// of the rep's queues and pools is visible before it shows up as latency.
+type Emitter struct {
+	logger       lager.Logger
+	metronClient loggingclient.IngressClient
+	clock        clock.Clock
+	interval     time.Duration
+	gauges       map[string]Gauge
+}
+
+// NewEmitter returns an Emitter for gauges by metric name.
+func NewEmitter(logger lager.Logger, metronClient loggingclient.IngressClient, clock clock.Clock, interval time.Duration, gauges map[string]Gauge) *Emitter {
+	return &Emitter{
+		logger:       logger.Session("saturation-emitter"),
+		metronClient: metronClient,
+		clock:        clock,
+		interval:     interval,
+		gauges:       gauges,
+	}
+}
+
+func (e *Emitter) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C():
+			e.emit()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (e *Emitter) emit() {
+	names := make([]string, 0, len(e.gauges))
+	for name := range e.gauges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err := e.metronClient.SendMetric(name, e.gauges[name]())
+		if err != nil {
+			e.logger.Error("failed-to-send-metric", err, lager.Data{"metric": name})
+		}
+	}
+}
+
+// Utilization returns the percentage of max that used is, or zero when max
+// is not positive.
+func Utilization(used, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	return used * 100 / max
+}
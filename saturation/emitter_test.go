@@ -0,0 +1,73 @@
+package saturation_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/saturation"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emitter", func() {
+	var (
+		logger       *lagertest.TestLogger
+		metronClient *mfakes.FakeIngressClient
+		fakeClock    *fakeclock.FakeClock
+		process      ifrit.Process
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		metronClient = new(mfakes.FakeIngressClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+	})
+
+	JustBeforeEach(func() {
+		gauges := map[string]saturation.Gauge{
+			"PerformQueueDepth":       func() int { return 3 },
+			"PerformQueueUtilization": func() int { return saturation.Utilization(1, 4) },
+		}
+		process = ginkgomon.Invoke(saturation.NewEmitter(logger, metronClient, fakeClock, time.Minute, gauges))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+	})
+
+	It("emits the current value of every gauge every interval", func() {
+		Consistently(metronClient.SendMetricCallCount).Should(BeZero())
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(metronClient.SendMetricCallCount).Should(Equal(2))
+
+		name, value, _ := metronClient.SendMetricArgsForCall(0)
+		Expect(name).To(Equal("PerformQueueDepth"))
+		Expect(value).To(Equal(3))
+		name, value, _ = metronClient.SendMetricArgsForCall(1)
+		Expect(name).To(Equal("PerformQueueUtilization"))
+		Expect(value).To(Equal(25))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(metronClient.SendMetricCallCount).Should(Equal(4))
+	})
+
+	Context("when sending a metric fails", func() {
+		BeforeEach(func() {
+			metronClient.SendMetricReturns(errors.New("boom"))
+		})
+
+		It("logs the error and keeps emitting the other gauges", func() {
+			fakeClock.WaitForWatcherAndIncrement(time.Minute)
+			Eventually(logger).Should(gbytes.Say("failed-to-send-metric"))
+			Eventually(metronClient.SendMetricCallCount).Should(Equal(2))
+		})
+	})
+})
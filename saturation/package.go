@@ -0,0 +1 @@
+package saturation // import "code.cloudfoundry.org/rep/saturation"
@@ -0,0 +1,13 @@
+package saturation_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSaturation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Saturation Suite")
+}
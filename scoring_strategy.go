@@ -0,0 +1,48 @@
+package rep
+
+// The built-in scoring strategies. Spread, the default, favours the cells
+// with the most resources left so that work spreads out for availability.
+// Bin-pack favours the fullest cells the work still fits on, so that work
+// consolidates and the emptier cells can be scaled down.
+const (
+	ScoringStrategySpread  = "spread"
+	ScoringStrategyBinPack = "bin-pack"
+)
+
+// ScoringStrategy scores a cell from the resources it would have remaining
+// once the work is placed on it, out of its total resources. As with the other
+// scores of a cell, the lowest score wins.
+type ScoringStrategy interface {
+	ResourceScore(remaining, total *Resources) float64
+}
+
+// SpreadStrategy scores a cell by the fraction of its resources that would be
+// used.
+type SpreadStrategy struct{}
+
+func (SpreadStrategy) ResourceScore(remaining, total *Resources) float64 {
+	return remaining.ComputeScore(total)
+}
+
+// BinPackStrategy scores a cell by the fraction of its resources that would
+// be left.
+type BinPackStrategy struct{}
+
+func (BinPackStrategy) ResourceScore(remaining, total *Resources) float64 {
+	return 1.0 - remaining.ComputeScore(total)
+}
+
+// ScoringStrategyNamed returns the built-in strategy named name. The
+// auctioneer chooses the one strategy it scores every cell by, as the scores
+// of cells scored by different strategies cannot be compared, and passes it to
+// ComputeScore.
+func ScoringStrategyNamed(name string) (ScoringStrategy, bool) {
+	switch name {
+	case ScoringStrategySpread:
+		return SpreadStrategy{}, true
+	case ScoringStrategyBinPack:
+		return BinPackStrategy{}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,62 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type constantStrategy float64
+
+func (s constantStrategy) ResourceScore(remaining, total *rep.Resources) float64 {
+	return float64(s)
+}
+
+var _ = Describe("ScoringStrategy", func() {
+	var (
+		resource  rep.Resource
+		emptyCell rep.CellState
+		fullCell  rep.CellState
+	)
+
+	BeforeEach(func() {
+		resource = rep.NewResource(100, 100, 10)
+		total := rep.NewResources(1000, 1000, 10)
+		emptyCell = rep.CellState{AvailableResources: rep.NewResources(1000, 1000, 10), TotalResources: total}
+		fullCell = rep.CellState{AvailableResources: rep.NewResources(200, 200, 2), TotalResources: total}
+	})
+
+	It("spreads work onto the emptiest cell", func() {
+		spread := rep.SpreadStrategy{}
+		Expect(emptyCell.ComputeScore(&resource, 0, spread)).To(BeNumerically("<", fullCell.ComputeScore(&resource, 0, spread)))
+		Expect(emptyCell.ComputeScore(&resource, 0, nil)).To(Equal(emptyCell.ComputeScore(&resource, 0, spread)))
+	})
+
+	It("packs work onto the fullest cell it fits on when bin-packing", func() {
+		binPack := rep.BinPackStrategy{}
+		Expect(fullCell.ComputeScore(&resource, 0, binPack)).To(BeNumerically("<", emptyCell.ComputeScore(&resource, 0, binPack)))
+		Expect(fullCell.ComputeScore(&resource, 0, binPack)).To(BeNumerically("~", 1.0-(0.9+0.9+0.9)/3, 0.0001))
+	})
+
+	It("scores cells with the strategy it is passed", func() {
+		Expect(emptyCell.ComputeScore(&resource, 0, constantStrategy(0.42))).To(BeNumerically("~", 0.42, 0.0001))
+	})
+
+	Describe("ScoringStrategyNamed", func() {
+		It("returns the built-in strategies", func() {
+			strategy, ok := rep.ScoringStrategyNamed(rep.ScoringStrategySpread)
+			Expect(ok).To(BeTrue())
+			Expect(strategy).To(Equal(rep.SpreadStrategy{}))
+
+			strategy, ok = rep.ScoringStrategyNamed(rep.ScoringStrategyBinPack)
+			Expect(ok).To(BeTrue())
+			Expect(strategy).To(Equal(rep.BinPackStrategy{}))
+		})
+
+		It("does not find strategies that are not built in", func() {
+			_, ok := rep.ScoringStrategyNamed("unknown")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
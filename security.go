@@ -0,0 +1,49 @@
+package rep
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/executor"
+)
+
+// SecurityTag holds the JSON encoded security requirements of work on its
+// container.
+const SecurityTag = "security"
+
+// Permits reports whether p permits every capability and profile
+// requirements asks for. Work without requirements is always permitted.
+func (p SecurityPermissions) Permits(requirements *SecurityRequirements) bool {
+	if requirements == nil {
+		return true
+	}
+	return toSet(requirements.Capabilities).isSubset(toSet(p.Capabilities)) &&
+		permitsProfile(p.SeccompProfiles, requirements.SeccompProfile) &&
+		permitsProfile(p.AppArmorProfiles, requirements.AppArmorProfile)
+}
+
+// AddSecurityTag records the security requirements of work on the tags of
+// its container, so that the executor grants the capabilities and applies
+// the profiles once it creates the container.
+func AddSecurityTag(tags executor.Tags, requirements *SecurityRequirements) {
+	if requirements == nil {
+		return
+	}
+	encoded, _ := json.Marshal(requirements)
+	tags[SecurityTag] = string(encoded)
+}
+
+// SecurityFromTags returns the security requirements recorded on the tags of
+// a container, or nil when it has none.
+func SecurityFromTags(tags executor.Tags) (*SecurityRequirements, error) {
+	encoded, ok := tags[SecurityTag]
+	if !ok {
+		return nil, nil
+	}
+
+	requirements := &SecurityRequirements{}
+	err := json.Unmarshal([]byte(encoded), requirements)
+	if err != nil {
+		return nil, err
+	}
+	return requirements, nil
+}
@@ -0,0 +1,57 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecurityRequirements", func() {
+	permissions := rep.SecurityPermissions{
+		Capabilities:     []string{"CAP_NET_ADMIN", "CAP_SYS_PTRACE"},
+		SeccompProfiles:  []string{"unconfined"},
+		AppArmorProfiles: []string{"diego-privileged"},
+	}
+
+	It("permits the requirements the cell allows", func() {
+		Expect(permissions.Permits(nil)).To(BeTrue())
+		Expect(permissions.Permits(&rep.SecurityRequirements{
+			Capabilities:    []string{"CAP_NET_ADMIN"},
+			SeccompProfile:  "unconfined",
+			AppArmorProfile: "diego-privileged",
+		})).To(BeTrue())
+	})
+
+	It("does not permit a capability or profile the cell does not allow", func() {
+		Expect(permissions.Permits(&rep.SecurityRequirements{Capabilities: []string{"CAP_SYS_ADMIN"}})).To(BeFalse())
+		Expect(permissions.Permits(&rep.SecurityRequirements{SeccompProfile: "custom"})).To(BeFalse())
+		Expect(permissions.Permits(&rep.SecurityRequirements{AppArmorProfile: "custom"})).To(BeFalse())
+		Expect(rep.SecurityPermissions{}.Permits(&rep.SecurityRequirements{Capabilities: []string{"CAP_NET_ADMIN"}})).To(BeFalse())
+	})
+
+	It("round trips through the tags of a container", func() {
+		tags := executor.Tags{}
+		rep.AddSecurityTag(tags, &rep.SecurityRequirements{Capabilities: []string{"CAP_NET_ADMIN"}, SeccompProfile: "unconfined"})
+
+		requirements, err := rep.SecurityFromTags(tags)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requirements).To(Equal(&rep.SecurityRequirements{Capabilities: []string{"CAP_NET_ADMIN"}, SeccompProfile: "unconfined"}))
+	})
+
+	It("leaves the tags alone when the work has no requirements", func() {
+		tags := executor.Tags{}
+		rep.AddSecurityTag(tags, nil)
+		Expect(tags).To(BeEmpty())
+
+		requirements, err := rep.SecurityFromTags(tags)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requirements).To(BeNil())
+	})
+
+	It("fails on malformed requirements", func() {
+		_, err := rep.SecurityFromTags(executor.Tags{rep.SecurityTag: "{"})
+		Expect(err).To(HaveOccurred())
+	})
+})
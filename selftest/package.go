@@ -0,0 +1 @@
+package selftest // import "code.cloudfoundry.org/rep/selftest"
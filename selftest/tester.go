@@ -0,0 +1,166 @@
+package selftest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+const (
+	StageAllocate = "allocate"
+	StageRun      = "run"
+	StageComplete = "complete"
+	StageDestroy  = "destroy"
+
+	containerMemoryMB = 16
+	containerDiskMB   = 16
+	containerMaxPids  = 16
+)
+
+var ErrTimedOut = errors.New("timed out waiting for the self test container to complete")
+
+// Stage is how long one step of a self test took, and why it failed if it
+// did.
+type Stage struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report is the result of a self test. The stages run in order and stop at
+// the first failure, except for destroying the container which is attempted
+// whenever it was allocated.
+type Report struct {
+	ContainerGuid string        `json:"container_guid"`
+	Succeeded     bool          `json:"succeeded"`
+	Duration      time.Duration `json:"duration_ns"`
+	Stages        []Stage       `json:"stages"`
+}
+
+// Tester runs a throwaway container on the cell to verify it can run
+// workloads.
+type Tester interface {
+	Run(logger lager.Logger) Report
+}
+
+type tester struct {
+	executorClient executor.Client
+	generateGuid   func() (string, error)
+	rootFSPath     string
+	clock          clock.Clock
+	pollInterval   time.Duration
+	timeout        time.Duration
+}
+
+// NewTester returns a Tester that allocates a tiny container on rootFSPath,
+// runs a no-op command in it, waits up to timeout for it to complete by
+// polling the executor every pollInterval, and destroys it.
+func NewTester(executorClient executor.Client, generateGuid func() (string, error), rootFSPath string, clock clock.Clock, pollInterval, timeout time.Duration) Tester {
+	return &tester{
+		executorClient: executorClient,
+		generateGuid:   generateGuid,
+		rootFSPath:     rootFSPath,
+		clock:          clock,
+		pollInterval:   pollInterval,
+		timeout:        timeout,
+	}
+}
+
+func (t *tester) Run(logger lager.Logger) Report {
+	start := t.clock.Now()
+	report := Report{}
+	defer func() {
+		report.Duration = t.clock.Since(start)
+	}()
+
+	guid, err := t.generateGuid()
+	if err != nil {
+		logger.Error("failed-to-generate-guid", err)
+		report.Stages = append(report.Stages, Stage{Name: StageAllocate, Error: err.Error()})
+		return report
+	}
+	report.ContainerGuid = "self-test-" + guid
+
+	logger = logger.Session("self-test", lager.Data{"container-guid": report.ContainerGuid})
+	logger.Info("starting")
+	defer logger.Info("finished")
+
+	if !t.stage(logger, &report, StageAllocate, t.allocate) {
+		return report
+	}
+
+	report.Succeeded = t.stage(logger, &report, StageRun, t.run) &&
+		t.stage(logger, &report, StageComplete, t.complete)
+	destroyed := t.stage(logger, &report, StageDestroy, t.destroy)
+	report.Succeeded = report.Succeeded && destroyed
+	return report
+}
+
+func (t *tester) stage(logger lager.Logger, report *Report, name string, step func(lager.Logger, string) error) bool {
+	start := t.clock.Now()
+	err := step(logger, report.ContainerGuid)
+	stage := Stage{Name: name, Duration: t.clock.Since(start)}
+	if err != nil {
+		logger.Error("failed-to-"+name, err)
+		stage.Error = err.Error()
+	}
+	report.Stages = append(report.Stages, stage)
+	return err == nil
+}
+
+func (t *tester) allocate(logger lager.Logger, guid string) error {
+	resource := executor.NewResource(containerMemoryMB, containerDiskMB, containerMaxPids)
+	failures := t.executorClient.AllocateContainers(logger, []executor.AllocationRequest{
+		executor.NewAllocationRequest(guid, &resource, executor.Tags{rep.LifecycleTag: rep.SelfTestLifecycle}),
+	})
+	if len(failures) > 0 {
+		return &failures[0]
+	}
+	return nil
+}
+
+func (t *tester) run(logger lager.Logger, guid string) error {
+	runInfo := executor.RunInfo{
+		RootFSPath: t.rootFSPath,
+		Action:     models.WrapAction(&models.RunAction{Path: "/bin/true", User: "root"}),
+	}
+	runRequest := executor.NewRunRequest(guid, &runInfo, executor.Tags{rep.LifecycleTag: rep.SelfTestLifecycle})
+	return t.executorClient.RunContainer(logger, &runRequest)
+}
+
+func (t *tester) complete(logger lager.Logger, guid string) error {
+	timeout := t.clock.NewTimer(t.timeout)
+	defer timeout.Stop()
+
+	ticker := t.clock.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		container, err := t.executorClient.GetContainer(logger, guid)
+		if err != nil {
+			return err
+		}
+		if container.State == executor.StateCompleted {
+			if container.RunResult.Failed {
+				return fmt.Errorf("container failed: %s", container.RunResult.FailureReason)
+			}
+			return nil
+		}
+
+		select {
+		case <-ticker.C():
+		case <-timeout.C():
+			return ErrTimedOut
+		}
+	}
+}
+
+func (t *tester) destroy(logger lager.Logger, guid string) error {
+	return t.executorClient.DeleteContainer(logger, guid)
+}
@@ -0,0 +1,154 @@
+package selftest_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/executor"
+	executorfakes "code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/selftest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tester", func() {
+	var (
+		logger         *lagertest.TestLogger
+		executorClient *executorfakes.FakeClient
+		fakeClock      *fakeclock.FakeClock
+		tester         selftest.Tester
+		reportCh       chan selftest.Report
+	)
+
+	stageNames := func(report selftest.Report) []string {
+		names := []string{}
+		for _, stage := range report.Stages {
+			names = append(names, stage.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(executorfakes.FakeClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		generateGuid := func() (string, error) { return "some-guid", nil }
+		tester = selftest.NewTester(executorClient, generateGuid, "/var/vcap/packages/cflinuxfs3/rootfs.tar", fakeClock, time.Second, 10*time.Second)
+		reportCh = make(chan selftest.Report, 1)
+
+		executorClient.GetContainerReturns(executor.Container{State: executor.StateCompleted}, nil)
+	})
+
+	JustBeforeEach(func() {
+		go func() {
+			reportCh <- tester.Run(logger)
+		}()
+	})
+
+	It("allocates, runs and destroys a self test container", func() {
+		var report selftest.Report
+		Eventually(reportCh).Should(Receive(&report))
+		Expect(report.Succeeded).To(BeTrue())
+		Expect(report.ContainerGuid).To(Equal("self-test-some-guid"))
+		Expect(stageNames(report)).To(Equal([]string{selftest.StageAllocate, selftest.StageRun, selftest.StageComplete, selftest.StageDestroy}))
+
+		_, requests := executorClient.AllocateContainersArgsForCall(0)
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Guid).To(Equal("self-test-some-guid"))
+		Expect(requests[0].Tags).To(Equal(executor.Tags{rep.LifecycleTag: rep.SelfTestLifecycle}))
+
+		_, runRequest := executorClient.RunContainerArgsForCall(0)
+		Expect(runRequest.Guid).To(Equal("self-test-some-guid"))
+		Expect(runRequest.RootFSPath).To(Equal("/var/vcap/packages/cflinuxfs3/rootfs.tar"))
+		Expect(runRequest.Action.RunAction.Path).To(Equal("/bin/true"))
+
+		_, deletedGuid := executorClient.DeleteContainerArgsForCall(0)
+		Expect(deletedGuid).To(Equal("self-test-some-guid"))
+	})
+
+	Context("when the container takes a while to complete", func() {
+		BeforeEach(func() {
+			executorClient.GetContainerReturnsOnCall(0, executor.Container{State: executor.StateRunning}, nil)
+		})
+
+		It("times the stage it took", func() {
+			Eventually(executorClient.GetContainerCallCount).Should(Equal(1))
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+
+			var report selftest.Report
+			Eventually(reportCh).Should(Receive(&report))
+			Expect(report.Succeeded).To(BeTrue())
+			Expect(report.Stages[2]).To(Equal(selftest.Stage{Name: selftest.StageComplete, Duration: time.Second}))
+			Expect(report.Duration).To(Equal(time.Second))
+		})
+	})
+
+	Context("when the container never completes", func() {
+		BeforeEach(func() {
+			executorClient.GetContainerReturns(executor.Container{State: executor.StateRunning}, nil)
+		})
+
+		It("times out and still destroys the container", func() {
+			Eventually(executorClient.GetContainerCallCount).Should(Equal(1))
+			fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+
+			var report selftest.Report
+			Eventually(reportCh).Should(Receive(&report))
+			Expect(report.Succeeded).To(BeFalse())
+			Expect(report.Stages[2].Error).To(Equal(selftest.ErrTimedOut.Error()))
+			Expect(executorClient.DeleteContainerCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the command fails", func() {
+		BeforeEach(func() {
+			executorClient.GetContainerReturns(executor.Container{
+				State:     executor.StateCompleted,
+				RunResult: executor.ContainerRunResult{Failed: true, FailureReason: "exit status 1"},
+			}, nil)
+		})
+
+		It("reports the failure", func() {
+			var report selftest.Report
+			Eventually(reportCh).Should(Receive(&report))
+			Expect(report.Succeeded).To(BeFalse())
+			Expect(report.Stages[2].Error).To(ContainSubstring("exit status 1"))
+			Expect(stageNames(report)).To(ContainElement(selftest.StageDestroy))
+		})
+	})
+
+	Context("when the container cannot be allocated", func() {
+		BeforeEach(func() {
+			executorClient.AllocateContainersStub = func(_ lager.Logger, requests []executor.AllocationRequest) []executor.AllocationFailure {
+				return []executor.AllocationFailure{executor.NewAllocationFailure(&requests[0], "insufficient resources")}
+			}
+		})
+
+		It("stops without running or destroying anything", func() {
+			var report selftest.Report
+			Eventually(reportCh).Should(Receive(&report))
+			Expect(report.Succeeded).To(BeFalse())
+			Expect(stageNames(report)).To(Equal([]string{selftest.StageAllocate}))
+			Expect(report.Stages[0].Error).To(ContainSubstring("insufficient resources"))
+			Expect(executorClient.RunContainerCallCount()).To(BeZero())
+			Expect(executorClient.DeleteContainerCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the container cannot be destroyed", func() {
+		BeforeEach(func() {
+			executorClient.DeleteContainerReturns(errors.New("boom"))
+		})
+
+		It("fails", func() {
+			var report selftest.Report
+			Eventually(reportCh).Should(Receive(&report))
+			Expect(report.Succeeded).To(BeFalse())
+			Expect(report.Stages[3]).To(Equal(selftest.Stage{Name: selftest.StageDestroy, Error: "boom"}))
+		})
+	})
+})
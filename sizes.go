@@ -0,0 +1,134 @@
+package rep
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Megabytes is a size in megabytes. It decodes from a number of megabytes,
+// as older auctioneers send sizes, or from a string with a unit such as
+// "512M" or "2G", and encodes as a string in the largest unit that holds it
+// exactly. Units are powers of 1024.
+type Megabytes int32
+
+var megabyteUnits = []struct {
+	suffix string
+	factor float64
+}{
+	{"T", 1024 * 1024},
+	{"G", 1024},
+	{"M", 1},
+	{"K", 1.0 / 1024},
+}
+
+// ParseMegabytes parses a size with an optional unit, K, M, G or T,
+// optionally followed by B. A size without a unit is in megabytes, and a
+// size in kilobytes is rounded up to the next megabyte.
+func ParseMegabytes(size string) (Megabytes, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	if len(value) > 1 && strings.HasSuffix(value, "B") && strings.ContainsAny(value[len(value)-2:len(value)-1], "KMGT") {
+		value = strings.TrimSuffix(value, "B")
+	}
+
+	factor := 1.0
+	for _, unit := range megabyteUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSuffix(value, unit.suffix)
+			factor = unit.factor
+			break
+		}
+	}
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || amount < 0 || math.IsNaN(amount) {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	megabytes := math.Ceil(amount * factor)
+	if megabytes > math.MaxInt32 {
+		return 0, fmt.Errorf("size %q is too large", size)
+	}
+	return Megabytes(megabytes), nil
+}
+
+func (m Megabytes) String() string {
+	for _, unit := range megabyteUnits {
+		factor := int64(unit.factor)
+		if factor >= 1 && m != 0 && int64(m)%factor == 0 {
+			return fmt.Sprintf("%d%s", int64(m)/factor, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dM", m)
+}
+
+func (m Megabytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *Megabytes) UnmarshalJSON(payload []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(payload), []byte(`"`)) {
+		var megabytes int32
+		err := json.Unmarshal(payload, &megabytes)
+		if err != nil {
+			return err
+		}
+		// a negative size would encode as a string no rep can parse
+		if megabytes < 0 {
+			return fmt.Errorf("invalid size %d", megabytes)
+		}
+		*m = Megabytes(megabytes)
+		return nil
+	}
+
+	var size string
+	err := json.Unmarshal(payload, &size)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMegabytes(size)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// typedSizes are the sizes of an LRP or a Task in their typed form, which
+// they encode next to the legacy MemoryMB and DiskMB.
+type typedSizes struct {
+	Memory Megabytes `json:"memory"`
+	Disk   Megabytes `json:"disk"`
+}
+
+func newTypedSizes(resource Resource) typedSizes {
+	return typedSizes{Memory: Megabytes(resource.MemoryMB), Disk: Megabytes(resource.DiskMB)}
+}
+
+// decodedSizes shadows the legacy MemoryMB and DiskMB of an LRP or a Task
+// while it is decoded, so that they accept sizes with units. The typed forms
+// take precedence when both are sent.
+type decodedSizes struct {
+	MemoryMB *Megabytes
+	DiskMB   *Megabytes
+	Memory   *Megabytes `json:"memory"`
+	Disk     *Megabytes `json:"disk"`
+}
+
+func (s decodedSizes) apply(resource *Resource) {
+	if s.MemoryMB != nil {
+		resource.MemoryMB = int32(*s.MemoryMB)
+	}
+	if s.Memory != nil {
+		resource.MemoryMB = int32(*s.Memory)
+	}
+	if s.DiskMB != nil {
+		resource.DiskMB = int32(*s.DiskMB)
+	}
+	if s.Disk != nil {
+		resource.DiskMB = int32(*s.Disk)
+	}
+}
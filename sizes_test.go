@@ -0,0 +1,103 @@
+package rep_test
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Megabytes", func() {
+	Describe("ParseMegabytes", func() {
+		It("parses sizes with units", func() {
+			Expect(rep.ParseMegabytes("512M")).To(Equal(rep.Megabytes(512)))
+			Expect(rep.ParseMegabytes("512MB")).To(Equal(rep.Megabytes(512)))
+			Expect(rep.ParseMegabytes("2g")).To(Equal(rep.Megabytes(2048)))
+			Expect(rep.ParseMegabytes("1.5G")).To(Equal(rep.Megabytes(1536)))
+			Expect(rep.ParseMegabytes("1T")).To(Equal(rep.Megabytes(1024 * 1024)))
+		})
+
+		It("treats sizes without a unit as megabytes", func() {
+			Expect(rep.ParseMegabytes("512")).To(Equal(rep.Megabytes(512)))
+		})
+
+		It("rounds kilobytes up to the next megabyte", func() {
+			Expect(rep.ParseMegabytes("1K")).To(Equal(rep.Megabytes(1)))
+			Expect(rep.ParseMegabytes("2049KB")).To(Equal(rep.Megabytes(3)))
+		})
+
+		It("rejects invalid sizes", func() {
+			for _, size := range []string{"", "lots", "100B", "-1M", "4096T", "NaN", "nanG"} {
+				_, err := rep.ParseMegabytes(size)
+				Expect(err).To(HaveOccurred(), size)
+			}
+		})
+	})
+
+	It("formats sizes in the largest exact unit", func() {
+		Expect(rep.Megabytes(512).String()).To(Equal("512M"))
+		Expect(rep.Megabytes(1536).String()).To(Equal("1536M"))
+		Expect(rep.Megabytes(2048).String()).To(Equal("2G"))
+		Expect(rep.Megabytes(1024 * 1024).String()).To(Equal("1T"))
+		Expect(rep.Megabytes(0).String()).To(Equal("0M"))
+	})
+
+	It("decodes legacy numbers and sizes with units", func() {
+		var sizes []rep.Megabytes
+		Expect(json.Unmarshal([]byte(`[512, "2G"]`), &sizes)).To(Succeed())
+		Expect(sizes).To(Equal([]rep.Megabytes{512, 2048}))
+	})
+})
+
+var _ = Describe("Work sizes", func() {
+	var lrp rep.LRP
+
+	BeforeEach(func() {
+		lrp = rep.NewLRP("ig-1", models.NewActualLRPKey("pg-1", 0, "domain"), rep.NewResource(1024, 2048, 10), rep.PlacementConstraint{})
+	})
+
+	It("encodes the typed sizes next to the legacy ones", func() {
+		payload, err := json.Marshal(lrp)
+		Expect(err).NotTo(HaveOccurred())
+
+		var fields map[string]interface{}
+		Expect(json.Unmarshal(payload, &fields)).To(Succeed())
+		Expect(fields).To(HaveKeyWithValue("MemoryMB", BeEquivalentTo(1024)))
+		Expect(fields).To(HaveKeyWithValue("DiskMB", BeEquivalentTo(2048)))
+		Expect(fields).To(HaveKeyWithValue("memory", "1G"))
+		Expect(fields).To(HaveKeyWithValue("disk", "2G"))
+	})
+
+	It("round-trips", func() {
+		payload, err := json.Marshal(lrp)
+		Expect(err).NotTo(HaveOccurred())
+
+		var decoded rep.LRP
+		Expect(json.Unmarshal(payload, &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(lrp))
+	})
+
+	It("accepts units in the legacy fields", func() {
+		var decoded rep.LRP
+		Expect(json.Unmarshal([]byte(`{"instance_guid": "ig-1", "MemoryMB": "512M", "DiskMB": 1024}`), &decoded)).To(Succeed())
+		Expect(decoded.InstanceGUID).To(Equal("ig-1"))
+		Expect(decoded.MemoryMB).To(BeEquivalentTo(512))
+		Expect(decoded.DiskMB).To(BeEquivalentTo(1024))
+	})
+
+	It("prefers the typed sizes over the legacy ones", func() {
+		var decoded rep.Task
+		Expect(json.Unmarshal([]byte(`{"TaskGuid": "tg-1", "MemoryMB": 256, "memory": "1G", "disk": "512M"}`), &decoded)).To(Succeed())
+		Expect(decoded.TaskGuid).To(Equal("tg-1"))
+		Expect(decoded.MemoryMB).To(BeEquivalentTo(1024))
+		Expect(decoded.DiskMB).To(BeEquivalentTo(512))
+	})
+
+	It("rejects invalid sizes", func() {
+		var decoded rep.Task
+		Expect(json.Unmarshal([]byte(`{"TaskGuid": "tg-1", "memory": "lots"}`), &decoded)).To(MatchError(ContainSubstring("invalid size")))
+		Expect(json.Unmarshal([]byte(`{"TaskGuid": "tg-1", "MemoryMB": -1}`), &decoded)).To(MatchError(ContainSubstring("invalid size")))
+	})
+})
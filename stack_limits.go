@@ -0,0 +1,64 @@
+package rep
+
+import "code.cloudfoundry.org/bbs/models"
+
+// StackOf returns the stack that per-stack container limits count a
+// container on rootfs against: the stack of a preloaded rootfs, such as
+// cflinuxfs4, or the scheme of any other rootfs, such as docker.
+func StackOf(rootfs string) string {
+	rootFSURL, err := ParseRootFS(rootfs)
+	if err != nil {
+		return ""
+	}
+
+	if rootFSURL.Scheme == models.PreloadedRootFSScheme || rootFSURL.Scheme == models.PreloadedOCIRootFSScheme {
+		return rootFSURL.Opaque
+	}
+	return rootFSURL.Scheme
+}
+
+// StackContainersLeft returns how many more containers of each stack in
+// limits fit on a cell running lrps and tasks. A stack over its limit has
+// none left.
+func StackContainersLeft(limits map[string]int, lrps []LRP, tasks []Task) map[string]int {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	remaining := make(map[string]int, len(limits))
+	for stack, limit := range limits {
+		remaining[stack] = limit
+	}
+	for i := range lrps {
+		takeStackContainer(remaining, lrps[i].RootFs)
+	}
+	for i := range tasks {
+		takeStackContainer(remaining, tasks[i].RootFs)
+	}
+	return remaining
+}
+
+func takeStackContainer(remaining map[string]int, rootfs string) {
+	stack := StackOf(rootfs)
+	if n, ok := remaining[stack]; ok && n > 0 {
+		remaining[stack] = n - 1
+	}
+}
+
+// returnStackContainer gives back the container of the stack of rootfs that
+// takeStackContainer took.
+func returnStackContainer(remaining map[string]int, rootfs string) {
+	stack := StackOf(rootfs)
+	if n, ok := remaining[stack]; ok {
+		remaining[stack] = n + 1
+	}
+}
+
+// stackLimitMatch returns an InsufficientResourcesError when the cell has no
+// containers of the stack of rootfs left.
+func (c *CellState) stackLimitMatch(rootfs string) error {
+	if n, ok := c.StackContainersLeft[StackOf(rootfs)]; ok && n < 1 {
+		return InsufficientResourcesError{Problems: map[string]struct{}{"stack containers": {}}}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stack container limits", func() {
+	var windowsRootFS, dockerRootFS string
+
+	lrpOn := func(instanceGuid, rootFS string) *rep.LRP {
+		return buildLRP(instanceGuid, "pg-"+instanceGuid, "domain", 0, rootFS, 10, 10, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+	}
+
+	BeforeEach(func() {
+		windowsRootFS = models.PreloadedRootFS("windows2016")
+		dockerRootFS = "docker:///cloudfoundry/grace"
+	})
+
+	Describe("StackOf", func() {
+		It("returns the stack of preloaded rootfses and the scheme of others", func() {
+			Expect(rep.StackOf(windowsRootFS)).To(Equal("windows2016"))
+			Expect(rep.StackOf("preloaded+layer:cflinuxfs3?layer=https://blobstore.internal/layer1.tgz")).To(Equal("cflinuxfs3"))
+			Expect(rep.StackOf(dockerRootFS)).To(Equal("docker"))
+		})
+	})
+
+	Describe("StackContainersLeft", func() {
+		It("counts the work of each limited stack against its limit", func() {
+			task := buildTask("tg-1", "domain", windowsRootFS, 10, 10, 10, []string{}, []string{}, models.Task_Running, false)
+
+			left := rep.StackContainersLeft(
+				map[string]int{"windows2016": 3, "docker": 1},
+				[]rep.LRP{*lrpOn("ig-1", windowsRootFS), *lrpOn("ig-2", dockerRootFS), *lrpOn("ig-3", dockerRootFS)},
+				[]rep.Task{*task},
+			)
+
+			Expect(left).To(Equal(map[string]int{"windows2016": 1, "docker": 0}))
+		})
+
+		It("returns nil without limits", func() {
+			Expect(rep.StackContainersLeft(nil, []rep.LRP{*lrpOn("ig-1", dockerRootFS)}, nil)).To(BeNil())
+		})
+	})
+
+	Describe("resource matching", func() {
+		var cellState rep.CellState
+
+		BeforeEach(func() {
+			cellState = rep.NewCellState(
+				"cell-id",
+				0,
+				"https://foo.cell.service.cf.internal",
+				rep.RootFSProviders{
+					models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("windows2016"),
+					"docker":                     rep.ArbitraryRootFSProvider{},
+				},
+				rep.NewResources(1000, 2000, 10),
+				rep.NewResources(1000, 2000, 10),
+				nil,
+				nil,
+				"my-zone",
+				0,
+				false,
+				nil,
+				nil,
+				nil,
+				0,
+			)
+			cellState.StackContainersLeft = map[string]int{"windows2016": 1}
+		})
+
+		It("rejects work once its stack has no containers left", func() {
+			Expect(cellState.LRPResourceMatch(lrpOn("ig-1", windowsRootFS))).To(Succeed())
+			cellState.AddLRP(lrpOn("ig-1", windowsRootFS))
+			Expect(cellState.StackContainersLeft).To(Equal(map[string]int{"windows2016": 0}))
+
+			err := cellState.LRPResourceMatch(lrpOn("ig-2", windowsRootFS))
+			Expect(err).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"stack containers": {}}}))
+			task := buildTask("tg-1", "domain", windowsRootFS, 10, 10, 10, []string{}, []string{}, models.Task_Pending, false)
+			Expect(cellState.TaskResourceMatch(task)).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"stack containers": {}}}))
+		})
+
+		It("does not limit other stacks", func() {
+			cellState.AddLRP(lrpOn("ig-1", windowsRootFS))
+			Expect(cellState.LRPResourceMatch(lrpOn("ig-2", dockerRootFS))).To(Succeed())
+		})
+	})
+})
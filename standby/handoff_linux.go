@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package standby
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+const maxHandedOverListeners = 16
+
+func sendListeners(conn *net.UnixConn, addrs []string, files []*os.File) error {
+	payload, err := json.Marshal(addrs)
+	if err != nil {
+		return err
+	}
+
+	fds := make([]int, len(files))
+	for i := range files {
+		fds[i] = int(files[i].Fd())
+	}
+	_, _, err = conn.WriteMsgUnix(payload, syscall.UnixRights(fds...), nil)
+	return err
+}
+
+func receiveListeners(conn *net.UnixConn) ([]string, []*os.File, error) {
+	payload := make([]byte, 64*1024)
+	oob := make([]byte, syscall.CmsgSpace(maxHandedOverListeners*4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(payload, oob)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var addrs []string
+	err = json.Unmarshal(payload[:n], &addrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return nil, nil, err
+	}
+	files := []*os.File{}
+	for i := range messages {
+		fds, err := syscall.ParseUnixRights(&messages[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, fd := range fds {
+			files = append(files, os.NewFile(uintptr(fd), "handed-over-listener"))
+		}
+	}
+
+	if len(files) != len(addrs) {
+		for _, file := range files {
+			file.Close()
+		}
+		return nil, nil, errors.New("the listeners handed over do not match their addresses")
+	}
+	return addrs, files, nil
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package standby
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// Warm standbys only run on Linux cells.
+var errHandoffUnsupported = errors.New("handing listeners over is not supported on this platform")
+
+func sendListeners(conn *net.UnixConn, addrs []string, files []*os.File) error {
+	return errHandoffUnsupported
+}
+
+func receiveListeners(conn *net.UnixConn) ([]string, []*os.File, error) {
+	return nil, nil, errHandoffUnsupported
+}
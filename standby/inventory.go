@@ -0,0 +1,141 @@
+package standby
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+)
+
+// Inventory is what a rep leaves behind for the process that replaces it on a
+// planned restart: every container of the cell along with its state. The
+// executor only keeps reservations in memory, so without the inventory the
+// work won at auction but not run yet would only be placed again once the
+// BBS converges. The containers in any other state are kept by garden and
+// checked against what the new executor finds.
+type Inventory struct {
+	Containers []InventoryContainer `json:"containers"`
+}
+
+type InventoryContainer struct {
+	Guid     string            `json:"guid"`
+	State    executor.State    `json:"state"`
+	Resource executor.Resource `json:"resource"`
+	Tags     executor.Tags     `json:"tags,omitempty"`
+}
+
+// SaveInventory writes every container of the cell to path. The file is
+// replaced in one step so that a standby never reads it half written.
+func SaveInventory(logger lager.Logger, executorClient executor.Client, path string) error {
+	logger = logger.Session("save-inventory", lager.Data{"path": path})
+
+	containers, err := executorClient.ListContainers(logger)
+	if err != nil {
+		logger.Error("failed-to-list-containers", err)
+		return err
+	}
+
+	inventory := Inventory{Containers: make([]InventoryContainer, 0, len(containers))}
+	for i := range containers {
+		inventory.Containers = append(inventory.Containers, InventoryContainer{
+			Guid:     containers[i].Guid,
+			State:    containers[i].State,
+			Resource: containers[i].Resource,
+			Tags:     containers[i].Tags,
+		})
+	}
+
+	payload, err := json.Marshal(inventory)
+	if err != nil {
+		logger.Error("failed-to-marshal-inventory", err)
+		return err
+	}
+
+	err = ioutil.WriteFile(path+".tmp", payload, 0600)
+	if err != nil {
+		logger.Error("failed-to-write-inventory", err)
+		return err
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		logger.Error("failed-to-move-inventory", err)
+		return err
+	}
+
+	logger.Info("saved", lager.Data{"containers": len(inventory.Containers)})
+	return nil
+}
+
+// RestoreInventory restores the inventory at path, if there is one. The
+// reserved containers are reserved again, and the others are checked to be
+// found by the executor as well. The inventory is consumed so that a later
+// restart does not restore it twice. An inventory that cannot be read fails
+// the restore, so that the standby does not take over without the work it
+// was handed, while reservations the executor cannot make again are logged
+// and left for the BBS to place elsewhere.
+func RestoreInventory(logger lager.Logger, executorClient executor.Client, path string) error {
+	logger = logger.Session("restore-inventory", lager.Data{"path": path})
+
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		logger.Error("failed-to-read-inventory", err)
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		logger.Error("failed-to-remove-inventory", err)
+		return err
+	}
+
+	var inventory Inventory
+	err = json.Unmarshal(contents, &inventory)
+	if err != nil {
+		logger.Error("failed-to-unmarshal-inventory", err)
+		return err
+	}
+
+	reservations := []executor.AllocationRequest{}
+	expected := map[string]executor.State{}
+	for i := range inventory.Containers {
+		container := &inventory.Containers[i]
+		if container.State == executor.StateReserved {
+			resource := container.Resource
+			reservations = append(reservations, executor.NewAllocationRequest(container.Guid, &resource, container.Tags))
+			continue
+		}
+		expected[container.Guid] = container.State
+	}
+
+	if len(expected) > 0 {
+		containers, err := executorClient.ListContainers(logger)
+		if err != nil {
+			logger.Error("failed-to-list-containers", err)
+			return err
+		}
+		for i := range containers {
+			delete(expected, containers[i].Guid)
+		}
+		for guid, state := range expected {
+			logger.Info("container-missing-after-handoff", lager.Data{"container-guid": guid, "state": state})
+		}
+	}
+
+	restored := 0
+	if len(reservations) > 0 {
+		failures := executorClient.AllocateContainers(logger, reservations)
+		for _, failure := range failures {
+			logger.Error("failed-to-restore-reservation", &failure, lager.Data{"container-guid": failure.Guid})
+		}
+		restored = len(reservations) - len(failures)
+	}
+
+	logger.Info("restored", lager.Data{"reservations": restored, "missing": len(expected)})
+	return nil
+}
@@ -0,0 +1,146 @@
+package standby_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/executor/fakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/standby"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Inventory", func() {
+	var (
+		tmpDir         string
+		inventoryFile  string
+		executorClient *fakes.FakeClient
+		logger         *lagertest.TestLogger
+		reservation    executor.AllocationRequest
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "standby")
+		Expect(err).NotTo(HaveOccurred())
+		inventoryFile = filepath.Join(tmpDir, "inventory.json")
+
+		logger = lagertest.NewTestLogger("test")
+		executorClient = new(fakes.FakeClient)
+
+		resource := executor.NewResource(256, 1024, 100)
+		tags := executor.Tags{rep.LifecycleTag: rep.LRPLifecycle, rep.ProcessGuidTag: "pg-1"}
+		reservation = executor.NewAllocationRequest("reserved-guid", &resource, tags)
+		executorClient.ListContainersReturns([]executor.Container{
+			{Guid: "reserved-guid", State: executor.StateReserved, Resource: resource, Tags: tags},
+			{Guid: "running-guid", State: executor.StateRunning, Resource: resource, Tags: tags},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("SaveInventory and RestoreInventory", func() {
+		BeforeEach(func() {
+			Expect(standby.SaveInventory(logger, executorClient, inventoryFile)).To(Succeed())
+		})
+
+		It("reserves the containers that were reserved again", func() {
+			Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(Succeed())
+
+			Expect(executorClient.AllocateContainersCallCount()).To(Equal(1))
+			_, requests := executorClient.AllocateContainersArgsForCall(0)
+			Expect(requests).To(Equal([]executor.AllocationRequest{reservation}))
+		})
+
+		It("saves every container along with its state", func() {
+			contents, err := ioutil.ReadFile(inventoryFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			var inventory standby.Inventory
+			Expect(json.Unmarshal(contents, &inventory)).To(Succeed())
+			Expect(inventory.Containers).To(HaveLen(2))
+			Expect(inventory.Containers[1].Guid).To(Equal("running-guid"))
+			Expect(inventory.Containers[1].State).To(Equal(executor.StateRunning))
+		})
+
+		Context("when a container is no longer found by the executor", func() {
+			BeforeEach(func() {
+				executorClient.ListContainersReturns(nil, nil)
+			})
+
+			It("logs the missing container", func() {
+				Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(Succeed())
+				Expect(logger).To(gbytes.Say("container-missing-after-handoff.*running-guid"))
+			})
+		})
+
+		Context("when the containers cannot be listed on restore", func() {
+			BeforeEach(func() {
+				executorClient.ListContainersReturns(nil, errors.New("boom"))
+			})
+
+			It("fails the restore", func() {
+				Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(MatchError("boom"))
+			})
+		})
+
+		It("only restores the inventory once", func() {
+			Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(Succeed())
+			Expect(inventoryFile).NotTo(BeAnExistingFile())
+
+			Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(Succeed())
+			Expect(executorClient.AllocateContainersCallCount()).To(Equal(1))
+		})
+
+		Context("when a reservation cannot be made again", func() {
+			BeforeEach(func() {
+				executorClient.AllocateContainersReturns([]executor.AllocationFailure{
+					executor.NewAllocationFailure(&reservation, "insufficient resources"),
+				})
+			})
+
+			It("logs the failure and restores the rest", func() {
+				Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(Succeed())
+				Expect(logger).To(gbytes.Say("failed-to-restore-reservation"))
+			})
+		})
+	})
+
+	Context("when the inventory cannot be decoded", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(inventoryFile, []byte("{"), 0600)).To(Succeed())
+		})
+
+		It("fails the restore", func() {
+			Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).NotTo(Succeed())
+		})
+	})
+
+	Context("when there is no inventory", func() {
+		It("restores nothing", func() {
+			Expect(standby.RestoreInventory(logger, executorClient, inventoryFile)).To(Succeed())
+			Expect(executorClient.AllocateContainersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the containers cannot be listed", func() {
+		BeforeEach(func() {
+			executorClient.ListContainersReturns(nil, errors.New("boom"))
+		})
+
+		It("does not save an inventory", func() {
+			Expect(standby.SaveInventory(logger, executorClient, inventoryFile)).To(MatchError("boom"))
+			Expect(inventoryFile).NotTo(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,236 @@
+package standby
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep/presence"
+	"github.com/tedsuo/ifrit"
+)
+
+var ErrNoListenersHandedOver = errors.New("the replaced rep handed over no listeners")
+
+// Listeners opens the listeners of the rep. A rep started as a warm standby
+// takes the listening sockets over from the rep it replaces on the host, and
+// hands them over in turn to the standby that replaces it. The sockets are
+// passed between the processes over a unix socket rather than bound by both,
+// so that connections queue up on the one socket throughout the restart and
+// are only ever accepted by one rep.
+type Listeners struct {
+	socketPath string
+
+	lock      sync.Mutex
+	inherited map[string]net.Listener
+	opened    map[string]*handedOverListener
+	// replaced stays open for the life of the process that handed the
+	// listeners over, so that its standby sees when it exits.
+	replaced *net.UnixConn
+}
+
+func NewListeners(socketPath string) *Listeners {
+	return &Listeners{
+		socketPath: socketPath,
+		inherited:  map[string]net.Listener{},
+		opened:     map[string]*handedOverListener{},
+	}
+}
+
+// Listen returns the listener for addr handed over by the replaced rep, or
+// listens on addr when there is none.
+func (l *Listeners) Listen(addr string) (net.Listener, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	listener, ok := l.inherited[addr]
+	if ok {
+		delete(l.inherited, addr)
+	} else {
+		var err error
+		listener, err = net.Listen("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opened := &handedOverListener{Listener: listener}
+	l.opened[addr] = opened
+	return opened, nil
+}
+
+// TakeOver receives the listeners of the rep being replaced through the
+// handoff socket. The returned channel is closed once that rep has exited: a
+// standby waits for it before starting its executor, so that only one
+// executor manages the containers of the cell at a time.
+func (l *Listeners) TakeOver(logger lager.Logger) (<-chan struct{}, error) {
+	logger = logger.Session("take-over-listeners", lager.Data{"socket": l.socketPath})
+
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: l.socketPath, Net: "unix"})
+	if err != nil {
+		logger.Error("failed-to-connect", err)
+		return nil, err
+	}
+
+	addrs, files, err := receiveListeners(conn)
+	if err != nil {
+		logger.Error("failed-to-receive-listeners", err)
+		conn.Close()
+		return nil, err
+	}
+	if len(files) == 0 {
+		conn.Close()
+		return nil, ErrNoListenersHandedOver
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for i, file := range files {
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			logger.Error("failed-to-open-listener", err, lager.Data{"addr": addrs[i]})
+			conn.Close()
+			return nil, err
+		}
+		l.inherited[addrs[i]] = listener
+	}
+	logger.Info("took-over", lager.Data{"addrs": addrs})
+
+	replacedExited := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				break
+			}
+		}
+		conn.Close()
+		close(replacedExited)
+	}()
+	return replacedExited, nil
+}
+
+// HandOff serves the handoff socket. Once a planned restart is marked, the
+// first standby to connect is handed the listeners: the inventory of the
+// cell is saved, the listeners are sent to the standby and stop accepting
+// connections in this process, and the runner exits so that the rep shuts
+// down. A handoff whose inventory cannot be saved is refused, leaving this
+// rep serving.
+func (l *Listeners) HandOff(logger lager.Logger, planned presence.PlannedRestartReporter, saveInventory func() error) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		logger := logger.Session("hand-off-listeners", lager.Data{"socket": l.socketPath})
+
+		os.Remove(l.socketPath)
+		socket, err := net.ListenUnix("unix", &net.UnixAddr{Name: l.socketPath, Net: "unix"})
+		if err != nil {
+			logger.Error("failed-to-listen", err)
+			return err
+		}
+		defer socket.Close()
+		err = os.Chmod(l.socketPath, 0600)
+		if err != nil {
+			logger.Error("failed-to-restrict-socket", err)
+			return err
+		}
+
+		handedOver := make(chan error, 1)
+		go func() {
+			for {
+				conn, err := socket.AcceptUnix()
+				if err != nil {
+					return
+				}
+				if err := l.handOff(logger, conn, planned, saveInventory); err != nil {
+					conn.Close()
+					continue
+				}
+				handedOver <- nil
+				return
+			}
+		}()
+
+		close(ready)
+
+		select {
+		case <-signals:
+			return nil
+		case err := <-handedOver:
+			logger.Info("handed-over")
+			return err
+		}
+	})
+}
+
+func (l *Listeners) handOff(logger lager.Logger, conn *net.UnixConn, planned presence.PlannedRestartReporter, saveInventory func() error) error {
+	if !planned.PlannedRestart() {
+		logger.Info("refusing-handoff-without-planned-restart")
+		return errors.New("no planned restart")
+	}
+
+	if saveInventory != nil {
+		if err := saveInventory(); err != nil {
+			logger.Error("refusing-handoff-without-inventory", err)
+			return err
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	addrs := make([]string, 0, len(l.opened))
+	files := make([]*os.File, 0, len(l.opened))
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	for addr, listener := range l.opened {
+		file, err := listener.File()
+		if err != nil {
+			logger.Error("failed-to-duplicate-listener", err, lager.Data{"addr": addr})
+			return err
+		}
+		addrs = append(addrs, addr)
+		files = append(files, file)
+	}
+
+	if err := sendListeners(conn, addrs, files); err != nil {
+		logger.Error("failed-to-send-listeners", err)
+		return err
+	}
+
+	// the standby holds the sockets now, so connections queue up on them
+	// until it accepts them once this rep has exited
+	for _, listener := range l.opened {
+		listener.Close()
+	}
+	l.replaced = conn
+	return nil
+}
+
+// handedOverListener can be closed both when it is handed over and when its
+// server stops.
+type handedOverListener struct {
+	net.Listener
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (l *handedOverListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.closeErr = l.Listener.Close()
+	})
+	return l.closeErr
+}
+
+func (l *handedOverListener) File() (*os.File, error) {
+	filer, ok := l.Listener.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, errors.New("listener cannot be handed over")
+	}
+	return filer.File()
+}
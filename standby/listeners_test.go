@@ -0,0 +1,124 @@
+//go:build linux
+// +build linux
+
+package standby_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/presence/fake_presence"
+	"code.cloudfoundry.org/rep/standby"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Listeners", func() {
+	var (
+		tmpDir        string
+		socketPath    string
+		logger        *lagertest.TestLogger
+		planned       *fake_presence.FakePlannedRestartReporter
+		inventoryErr  error
+		active        *standby.Listeners
+		activeServer  net.Listener
+		handOff       ifrit.Process
+		inventorySave int32
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "standby")
+		Expect(err).NotTo(HaveOccurred())
+		socketPath = filepath.Join(tmpDir, "handoff.sock")
+		logger = lagertest.NewTestLogger("test")
+		planned = new(fake_presence.FakePlannedRestartReporter)
+		inventoryErr = nil
+		inventorySave = 0
+
+		active = standby.NewListeners(socketPath)
+		activeServer, err = active.Listen("127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		handOff = ifrit.Invoke(active.HandOff(logger, planned, func() error {
+			atomic.AddInt32(&inventorySave, 1)
+			return inventoryErr
+		}))
+	})
+
+	AfterEach(func() {
+		handOff.Signal(os.Interrupt)
+		Eventually(handOff.Wait()).Should(Receive())
+		activeServer.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("listens on the address when there is nothing to take over", func() {
+		Expect(activeServer.Addr().String()).NotTo(HaveSuffix(":0"))
+	})
+
+	Context("when a planned restart is marked", func() {
+		BeforeEach(func() {
+			planned.PlannedRestartReturns(true)
+		})
+
+		It("hands the listening socket over to the standby", func() {
+			successor := standby.NewListeners(socketPath)
+			_, err := successor.TakeOver(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(atomic.LoadInt32(&inventorySave)).To(Equal(int32(1)))
+
+			inherited, err := successor.Listen("127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer inherited.Close()
+			Expect(inherited.Addr().String()).To(Equal(activeServer.Addr().String()))
+
+			Eventually(handOff.Wait()).Should(Receive(BeNil()))
+			_, err = activeServer.Accept()
+			Expect(err).To(HaveOccurred())
+
+			conn, err := net.Dial("tcp", inherited.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			accepted, err := inherited.Accept()
+			Expect(err).NotTo(HaveOccurred())
+			accepted.Close()
+		})
+
+		Context("when the inventory cannot be saved", func() {
+			BeforeEach(func() {
+				inventoryErr = errors.New("boom")
+			})
+
+			It("refuses the handoff and keeps serving", func() {
+				_, err := standby.NewListeners(socketPath).TakeOver(logger)
+				Expect(err).To(HaveOccurred())
+				Consistently(handOff.Wait()).ShouldNot(Receive())
+
+				conn, err := net.Dial("tcp", activeServer.Addr().String())
+				Expect(err).NotTo(HaveOccurred())
+				defer conn.Close()
+				accepted, err := activeServer.Accept()
+				Expect(err).NotTo(HaveOccurred())
+				accepted.Close()
+			})
+		})
+	})
+
+	Context("when no planned restart is marked", func() {
+		It("refuses the handoff", func() {
+			_, err := standby.NewListeners(socketPath).TakeOver(logger)
+			Expect(err).To(HaveOccurred())
+			Expect(atomic.LoadInt32(&inventorySave)).To(Equal(int32(0)))
+			Consistently(handOff.Wait()).ShouldNot(Receive())
+		})
+	})
+})
@@ -0,0 +1 @@
+package standby // import "code.cloudfoundry.org/rep/standby"
@@ -0,0 +1,13 @@
+package standby_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestStandby(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Standby Suite")
+}
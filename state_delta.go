@@ -0,0 +1,200 @@
+package rep
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+)
+
+// StateDeltaHeader is set on state responses that carry a CellStateDelta
+// rather than a full CellState. A delta is only returned when the request
+// names a snapshot the cell still remembers with the since query parameter.
+const StateDeltaHeader = "X-Rep-State-Delta"
+
+// CellStateDelta is the change of a CellState since the snapshot identified
+// by Since. State holds every field of the current state except its LRPs and
+// Tasks, which are small enough to send in full. LRPs and Tasks hold the work
+// that was added or changed, and the removed work is identified by its
+// instance and task guids.
+type CellStateDelta struct {
+	Since        string
+	State        CellState
+	LRPs         []LRP    `json:",omitempty"`
+	RemovedLRPs  []string `json:",omitempty"`
+	Tasks        []Task   `json:",omitempty"`
+	RemovedTasks []string `json:",omitempty"`
+}
+
+// StateETag identifies a snapshot of a CellState by a hash of its JSON
+// encoding.
+func StateETag(state CellState) (string, error) {
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// NewCellStateDelta returns the change from the base snapshot identified by
+// since to the current state.
+func NewCellStateDelta(since string, base, current CellState) CellStateDelta {
+	delta := CellStateDelta{Since: since, State: current}
+	delta.State.LRPs = nil
+	delta.State.Tasks = nil
+
+	baseLRPs := make(map[string]*LRP, len(base.LRPs))
+	for i := range base.LRPs {
+		baseLRPs[base.LRPs[i].InstanceGUID] = &base.LRPs[i]
+	}
+	currentLRPs := make(map[string]struct{}, len(current.LRPs))
+	for i := range current.LRPs {
+		lrp := &current.LRPs[i]
+		currentLRPs[lrp.InstanceGUID] = struct{}{}
+		if previous, ok := baseLRPs[lrp.InstanceGUID]; !ok || !reflect.DeepEqual(previous, lrp) {
+			delta.LRPs = append(delta.LRPs, *lrp)
+		}
+	}
+	for i := range base.LRPs {
+		if _, ok := currentLRPs[base.LRPs[i].InstanceGUID]; !ok {
+			delta.RemovedLRPs = append(delta.RemovedLRPs, base.LRPs[i].InstanceGUID)
+		}
+	}
+
+	baseTasks := make(map[string]*Task, len(base.Tasks))
+	for i := range base.Tasks {
+		baseTasks[base.Tasks[i].TaskGuid] = &base.Tasks[i]
+	}
+	currentTasks := make(map[string]struct{}, len(current.Tasks))
+	for i := range current.Tasks {
+		task := &current.Tasks[i]
+		currentTasks[task.TaskGuid] = struct{}{}
+		if previous, ok := baseTasks[task.TaskGuid]; !ok || !reflect.DeepEqual(previous, task) {
+			delta.Tasks = append(delta.Tasks, *task)
+		}
+	}
+	for i := range base.Tasks {
+		if _, ok := currentTasks[base.Tasks[i].TaskGuid]; !ok {
+			delta.RemovedTasks = append(delta.RemovedTasks, base.Tasks[i].TaskGuid)
+		}
+	}
+
+	return delta
+}
+
+// Apply returns the state the delta was computed from when applied to the
+// base snapshot it is relative to.
+func (d CellStateDelta) Apply(base CellState) CellState {
+	state := d.State
+
+	removedLRPs := toSet(d.RemovedLRPs)
+	changedLRPs := make(map[string]int, len(d.LRPs))
+	for i := range d.LRPs {
+		changedLRPs[d.LRPs[i].InstanceGUID] = i
+	}
+	state.LRPs = []LRP{}
+	for _, lrp := range base.LRPs {
+		if _, removed := removedLRPs[lrp.InstanceGUID]; removed {
+			continue
+		}
+		if i, changed := changedLRPs[lrp.InstanceGUID]; changed {
+			lrp = d.LRPs[i]
+			delete(changedLRPs, lrp.InstanceGUID)
+		}
+		state.LRPs = append(state.LRPs, lrp)
+	}
+	for _, lrp := range d.LRPs {
+		if _, added := changedLRPs[lrp.InstanceGUID]; added {
+			state.LRPs = append(state.LRPs, lrp)
+		}
+	}
+
+	removedTasks := toSet(d.RemovedTasks)
+	changedTasks := make(map[string]int, len(d.Tasks))
+	for i := range d.Tasks {
+		changedTasks[d.Tasks[i].TaskGuid] = i
+	}
+	state.Tasks = []Task{}
+	for _, task := range base.Tasks {
+		if _, removed := removedTasks[task.TaskGuid]; removed {
+			continue
+		}
+		if i, changed := changedTasks[task.TaskGuid]; changed {
+			task = d.Tasks[i]
+			delete(changedTasks, task.TaskGuid)
+		}
+		state.Tasks = append(state.Tasks, task)
+	}
+	for _, task := range d.Tasks {
+		if _, added := changedTasks[task.TaskGuid]; added {
+			state.Tasks = append(state.Tasks, task)
+		}
+	}
+
+	return state
+}
+
+// CellStateChanges summarizes a CellStateDelta for logging: the guids of the
+// work that was added, changed or removed, and the names of the other fields
+// of the state that changed.
+type CellStateChanges struct {
+	AddedLRPs    []string `json:"added_lrps,omitempty"`
+	ChangedLRPs  []string `json:"changed_lrps,omitempty"`
+	RemovedLRPs  []string `json:"removed_lrps,omitempty"`
+	AddedTasks   []string `json:"added_tasks,omitempty"`
+	ChangedTasks []string `json:"changed_tasks,omitempty"`
+	RemovedTasks []string `json:"removed_tasks,omitempty"`
+	Fields       []string `json:"fields,omitempty"`
+}
+
+// Empty reports whether nothing changed.
+func (c CellStateChanges) Empty() bool {
+	return len(c.AddedLRPs) == 0 && len(c.ChangedLRPs) == 0 && len(c.RemovedLRPs) == 0 &&
+		len(c.AddedTasks) == 0 && len(c.ChangedTasks) == 0 && len(c.RemovedTasks) == 0 &&
+		len(c.Fields) == 0
+}
+
+// Changes summarizes the delta relative to the base snapshot it was computed
+// from.
+func (d CellStateDelta) Changes(base CellState) CellStateChanges {
+	changes := CellStateChanges{RemovedLRPs: d.RemovedLRPs, RemovedTasks: d.RemovedTasks}
+
+	baseLRPs := make(map[string]struct{}, len(base.LRPs))
+	for i := range base.LRPs {
+		baseLRPs[base.LRPs[i].InstanceGUID] = struct{}{}
+	}
+	for i := range d.LRPs {
+		if _, ok := baseLRPs[d.LRPs[i].InstanceGUID]; ok {
+			changes.ChangedLRPs = append(changes.ChangedLRPs, d.LRPs[i].InstanceGUID)
+		} else {
+			changes.AddedLRPs = append(changes.AddedLRPs, d.LRPs[i].InstanceGUID)
+		}
+	}
+
+	baseTasks := make(map[string]struct{}, len(base.Tasks))
+	for i := range base.Tasks {
+		baseTasks[base.Tasks[i].TaskGuid] = struct{}{}
+	}
+	for i := range d.Tasks {
+		if _, ok := baseTasks[d.Tasks[i].TaskGuid]; ok {
+			changes.ChangedTasks = append(changes.ChangedTasks, d.Tasks[i].TaskGuid)
+		} else {
+			changes.AddedTasks = append(changes.AddedTasks, d.Tasks[i].TaskGuid)
+		}
+	}
+
+	current, previous := reflect.ValueOf(d.State), reflect.ValueOf(base)
+	stateType := current.Type()
+	for i := 0; i < stateType.NumField(); i++ {
+		field := stateType.Field(i)
+		if field.PkgPath != "" || field.Name == "LRPs" || field.Name == "Tasks" {
+			continue
+		}
+		if !reflect.DeepEqual(current.Field(i).Interface(), previous.Field(i).Interface()) {
+			changes.Fields = append(changes.Fields, field.Name)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,99 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CellStateDelta", func() {
+	var base, current rep.CellState
+
+	BeforeEach(func() {
+		rootFS := models.PreloadedRootFS("linux")
+		base = rep.CellState{
+			CellID:             "cell-id",
+			AvailableResources: rep.NewResources(1000, 2000, 10),
+			LRPs: []rep.LRP{
+				*buildLRP("ig-1", "pg-1", "domain", 0, rootFS, 10, 20, 30, []string{}, []string{}, models.ActualLRPStateClaimed),
+				*buildLRP("ig-2", "pg-1", "domain", 1, rootFS, 10, 20, 30, []string{}, []string{}, models.ActualLRPStateRunning),
+			},
+			Tasks: []rep.Task{
+				*buildTask("tg-1", "domain", rootFS, 10, 10, 10, []string{}, []string{}, models.Task_Running, false),
+			},
+		}
+
+		current = base
+		current.AvailableResources = rep.NewResources(990, 1980, 9)
+		current.LRPs = []rep.LRP{
+			*buildLRP("ig-1", "pg-1", "domain", 0, rootFS, 10, 20, 30, []string{}, []string{}, models.ActualLRPStateRunning),
+			*buildLRP("ig-3", "pg-2", "domain", 0, rootFS, 10, 20, 30, []string{}, []string{}, models.ActualLRPStateClaimed),
+		}
+		current.Tasks = []rep.Task{
+			*buildTask("tg-2", "domain", rootFS, 10, 10, 10, []string{}, []string{}, models.Task_Running, false),
+		}
+	})
+
+	It("holds only the work that changed", func() {
+		delta := rep.NewCellStateDelta("some-etag", base, current)
+
+		Expect(delta.Since).To(Equal("some-etag"))
+		Expect(delta.State.AvailableResources).To(Equal(rep.NewResources(990, 1980, 9)))
+		Expect(delta.State.LRPs).To(BeNil())
+		Expect(delta.State.Tasks).To(BeNil())
+
+		Expect(delta.LRPs).To(Equal(current.LRPs))
+		Expect(delta.RemovedLRPs).To(Equal([]string{"ig-2"}))
+		Expect(delta.Tasks).To(Equal(current.Tasks))
+		Expect(delta.RemovedTasks).To(Equal([]string{"tg-1"}))
+	})
+
+	It("is empty when nothing changed", func() {
+		delta := rep.NewCellStateDelta("some-etag", base, base)
+
+		Expect(delta.LRPs).To(BeEmpty())
+		Expect(delta.RemovedLRPs).To(BeEmpty())
+		Expect(delta.Tasks).To(BeEmpty())
+		Expect(delta.RemovedTasks).To(BeEmpty())
+	})
+
+	It("restores the current state when applied to the base", func() {
+		delta := rep.NewCellStateDelta("some-etag", base, current)
+		Expect(delta.Apply(base)).To(Equal(current))
+	})
+
+	Describe("Changes", func() {
+		It("summarizes the work and the fields that changed", func() {
+			delta := rep.NewCellStateDelta("some-etag", base, current)
+			Expect(delta.Changes(base)).To(Equal(rep.CellStateChanges{
+				AddedLRPs:    []string{"ig-3"},
+				ChangedLRPs:  []string{"ig-1"},
+				RemovedLRPs:  []string{"ig-2"},
+				AddedTasks:   []string{"tg-2"},
+				RemovedTasks: []string{"tg-1"},
+				Fields:       []string{"AvailableResources"},
+			}))
+		})
+
+		It("is empty when nothing changed", func() {
+			delta := rep.NewCellStateDelta("some-etag", base, base)
+			Expect(delta.Changes(base).Empty()).To(BeTrue())
+		})
+	})
+
+	Describe("StateETag", func() {
+		It("identifies states by their contents", func() {
+			baseETag, err := rep.StateETag(base)
+			Expect(err).NotTo(HaveOccurred())
+			currentETag, err := rep.StateETag(current)
+			Expect(err).NotTo(HaveOccurred())
+			sameETag, err := rep.StateETag(base)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(baseETag).NotTo(Equal(currentETag))
+			Expect(baseETag).To(Equal(sameETag))
+		})
+	})
+})
@@ -0,0 +1 @@
+package supervisor // import "code.cloudfoundry.org/rep/supervisor"
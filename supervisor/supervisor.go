@@ -0,0 +1,142 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	loggregator "code.cloudfoundry.org/go-loggregator/v8"
+	"code.cloudfoundry.org/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+const (
+	componentRestartsMetric = "ComponentRestarts"
+	componentTag            = "component"
+)
+
+// Supervisor runs a component of the rep and restarts it when it panics,
+// waiting longer after every panic, from minBackoff up to maxBackoff. The
+// backoff starts over once the component stayed up for maxBackoff. Only
+// panics on the goroutine running the component are recovered, and a
+// component that exits or fails is not restarted.
+type Supervisor struct {
+	logger       lager.Logger
+	name         string
+	runner       ifrit.Runner
+	metronClient loggingclient.IngressClient
+	clock        clock.Clock
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+}
+
+func New(logger lager.Logger, name string, runner ifrit.Runner, metronClient loggingclient.IngressClient, clock clock.Clock, minBackoff, maxBackoff time.Duration) *Supervisor {
+	return &Supervisor{
+		logger:       logger.Session("supervisor", lager.Data{"component": name}),
+		name:         name,
+		runner:       runner,
+		metronClient: metronClient,
+		clock:        clock,
+		minBackoff:   minBackoff,
+		maxBackoff:   maxBackoff,
+	}
+}
+
+type exit struct {
+	err      error
+	panicked bool
+}
+
+func (s *Supervisor) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	backoff := s.minBackoff
+	restarts := 0
+
+	for {
+		started := s.clock.Now()
+		result, signalled := s.runOnce(signals, &ready)
+		if !result.panicked || signalled {
+			return result.err
+		}
+
+		restarts++
+		s.emitRestarts(restarts)
+
+		if s.clock.Since(started) >= s.maxBackoff {
+			backoff = s.minBackoff
+		}
+		s.logger.Info("restarting", lager.Data{"restarts": restarts, "backoff": backoff.String()})
+
+		timer := s.clock.NewTimer(backoff)
+		select {
+		case <-timer.C():
+		case <-signals:
+			timer.Stop()
+			return nil
+		}
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// runOnce runs the component until it exits, forwarding signals to it. The
+// ready channel of the supervisor is closed, and set to nil, once the
+// component is first ready.
+func (s *Supervisor) runOnce(signals <-chan os.Signal, ready *chan<- struct{}) (exit, bool) {
+	componentSignals := make(chan os.Signal, 1)
+	componentReady := make(chan struct{})
+	exited := make(chan exit, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err := fmt.Errorf("%s panicked: %v", s.name, r)
+				s.logger.Error("component-panicked", err, lager.Data{"stack": string(debug.Stack())})
+				exited <- exit{err: err, panicked: true}
+			}
+		}()
+		exited <- exit{err: s.runner.Run(componentSignals, componentReady)}
+	}()
+
+	signalled := false
+	readyC := componentReady
+	for {
+		select {
+		case <-readyC:
+			readyC = nil
+			closeReady(ready)
+		case signal := <-signals:
+			signalled = true
+			select {
+			case componentSignals <- signal:
+			default:
+			}
+		case result := <-exited:
+			select {
+			case <-readyC:
+				closeReady(ready)
+			default:
+			}
+			return result, signalled
+		}
+	}
+}
+
+func closeReady(ready *chan<- struct{}) {
+	if *ready != nil {
+		close(*ready)
+		*ready = nil
+	}
+}
+
+func (s *Supervisor) emitRestarts(restarts int) {
+	err := s.metronClient.SendMetric(componentRestartsMetric, restarts, loggregator.WithEnvelopeTag(componentTag, s.name))
+	if err != nil {
+		s.logger.Error("failed-to-send-component-restarts-metric", err)
+	}
+}
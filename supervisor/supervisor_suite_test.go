@@ -0,0 +1,13 @@
+package supervisor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSupervisor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Supervisor Suite")
+}
@@ -0,0 +1,128 @@
+package supervisor_test
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/supervisor"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Supervisor", func() {
+	var (
+		logger           *lagertest.TestLogger
+		fakeMetronClient *mfakes.FakeIngressClient
+		fakeClock        *fakeclock.FakeClock
+		runs             int32
+		panics           int32
+		runner           ifrit.Runner
+		process          ifrit.Process
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeMetronClient = new(mfakes.FakeIngressClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		atomic.StoreInt32(&runs, 0)
+		atomic.StoreInt32(&panics, 0)
+
+		runner = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			atomic.AddInt32(&runs, 1)
+			close(ready)
+			if atomic.LoadInt32(&panics) > 0 {
+				atomic.AddInt32(&panics, -1)
+				panic("boom")
+			}
+			<-signals
+			return nil
+		})
+	})
+
+	JustBeforeEach(func() {
+		process = ifrit.Background(supervisor.New(logger, "bulker", runner, fakeMetronClient, fakeClock, time.Second, 4*time.Second))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	runCount := func() int32 {
+		return atomic.LoadInt32(&runs)
+	}
+
+	It("runs the component until it is signalled", func() {
+		Eventually(process.Ready()).Should(BeClosed())
+		Expect(runCount()).To(BeEquivalentTo(1))
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+		Expect(fakeMetronClient.SendMetricCallCount()).To(BeZero())
+	})
+
+	Context("when the component panics", func() {
+		BeforeEach(func() {
+			atomic.StoreInt32(&panics, 3)
+		})
+
+		It("restarts it with a growing backoff", func() {
+			Eventually(logger).Should(Say("component-panicked"))
+			Eventually(fakeClock.WatcherCount).Should(Equal(1))
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(runCount).Should(BeEquivalentTo(2))
+
+			Eventually(fakeClock.WatcherCount).Should(Equal(1))
+			fakeClock.Increment(time.Second)
+			Consistently(runCount).Should(BeEquivalentTo(2))
+			fakeClock.Increment(time.Second)
+			Eventually(runCount).Should(BeEquivalentTo(3))
+
+			Eventually(fakeClock.WatcherCount).Should(Equal(1))
+			fakeClock.Increment(4 * time.Second)
+			Eventually(runCount).Should(BeEquivalentTo(4))
+			Consistently(process.Wait()).ShouldNot(Receive())
+		})
+
+		It("emits how often the component restarted", func() {
+			Eventually(fakeMetronClient.SendMetricCallCount).Should(Equal(1))
+			name, value, opts := fakeMetronClient.SendMetricArgsForCall(0)
+			Expect(name).To(Equal("ComponentRestarts"))
+			Expect(value).To(Equal(1))
+			Expect(opts).To(HaveLen(1))
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(fakeMetronClient.SendMetricCallCount).Should(Equal(2))
+			_, value, _ = fakeMetronClient.SendMetricArgsForCall(1)
+			Expect(value).To(Equal(2))
+		})
+
+		It("exits when signalled while backing off", func() {
+			Eventually(fakeClock.WatcherCount).Should(Equal(1))
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when the component fails", func() {
+		BeforeEach(func() {
+			runner = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				atomic.AddInt32(&runs, 1)
+				close(ready)
+				return errors.New("failed")
+			})
+		})
+
+		It("does not restart it", func() {
+			Eventually(process.Wait()).Should(Receive(MatchError("failed")))
+			Expect(runCount()).To(BeEquivalentTo(1))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package rep
+
+import "sync"
+
+// SyncCellState guards a CellState so that work can be applied to it and
+// rolled back from several goroutines, such as auctions simulated in
+// parallel against the same cell. The state it guards is its own copy.
+type SyncCellState struct {
+	lock  sync.Mutex
+	state CellState
+}
+
+func NewSyncCellState(state CellState) *SyncCellState {
+	return &SyncCellState{state: state.Copy()}
+}
+
+// State returns a copy of the guarded state.
+func (s *SyncCellState) State() CellState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.state.Copy()
+}
+
+func (s *SyncCellState) AddLRP(lrp *LRP) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.state.AddLRP(lrp)
+}
+
+func (s *SyncCellState) AddTask(task *Task) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.state.AddTask(task)
+}
+
+// PlaceLRP adds lrp when it fits on the cell, checking and taking its
+// resources at once so that concurrent placements cannot both take the last
+// of them.
+func (s *SyncCellState) PlaceLRP(lrp *LRP) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.state.LRPResourceMatch(lrp); err != nil {
+		return err
+	}
+	s.state.AddLRP(lrp)
+	return nil
+}
+
+// PlaceTask adds task when it fits on the cell, as PlaceLRP does.
+func (s *SyncCellState) PlaceTask(task *Task) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.state.TaskResourceMatch(task); err != nil {
+		return err
+	}
+	s.state.AddTask(task)
+	return nil
+}
+
+func (s *SyncCellState) RemoveLRP(lrp *LRP) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.state.RemoveLRP(lrp)
+}
+
+func (s *SyncCellState) RemoveTask(task *Task) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.state.RemoveTask(task)
+}
+
+func (s *SyncCellState) RemoveContainer(guid string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.state.RemoveContainer(guid)
+}
@@ -0,0 +1,75 @@
+package rep_test
+
+import (
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SyncCellState", func() {
+	var (
+		state     rep.CellState
+		syncState *rep.SyncCellState
+	)
+
+	BeforeEach(func() {
+		state = rep.NewCellState("cell-id", 0, "", rep.RootFSProviders{models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("linux")}, rep.NewResources(100, 100, 10), rep.NewResources(100, 100, 10), nil, nil, "zone", 0, false, nil, nil, nil, 0)
+		syncState = rep.NewSyncCellState(state)
+	})
+
+	It("places only the work that fits when placing concurrently", func() {
+		wg := sync.WaitGroup{}
+		placed := make(chan string, 20)
+		for i := 0; i < 20; i++ {
+			lrp := *buildLRP(fmt.Sprintf("ig-%d", i), "pg", "domain", i, models.PreloadedRootFS("linux"), 10, 10, 0, []string{}, []string{}, "")
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				if syncState.PlaceLRP(&lrp) == nil {
+					placed <- lrp.InstanceGUID
+				}
+			}()
+		}
+		wg.Wait()
+		close(placed)
+
+		Expect(placed).To(HaveLen(10))
+		Expect(syncState.State().AvailableResources).To(Equal(rep.NewResources(0, 0, 0)))
+	})
+
+	It("rolls back work removed concurrently", func() {
+		lrps := make([]rep.LRP, 5)
+		for i := range lrps {
+			lrps[i] = *buildLRP(fmt.Sprintf("ig-%d", i), "pg", "domain", i, models.PreloadedRootFS("linux"), 10, 10, 0, []string{}, []string{}, "")
+			syncState.AddLRP(&lrps[i])
+		}
+
+		wg := sync.WaitGroup{}
+		for i := range lrps {
+			wg.Add(1)
+			go func(lrp *rep.LRP) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(syncState.RemoveLRP(lrp)).To(BeTrue())
+			}(&lrps[i])
+		}
+		wg.Wait()
+
+		Expect(syncState.State().AvailableResources).To(Equal(rep.NewResources(100, 100, 10)))
+		Expect(syncState.State().LRPs).To(BeEmpty())
+	})
+
+	It("guards its own copy of the state", func() {
+		syncState.AddTask(buildTask("tg", "domain", models.PreloadedRootFS("linux"), 10, 10, 0, []string{}, []string{}, models.Task_Invalid, false))
+
+		Expect(state.Tasks).To(BeEmpty())
+		Expect(syncState.State().Tasks).To(HaveLen(1))
+		Expect(syncState.RemoveContainer("tg")).To(BeTrue())
+	})
+})
@@ -0,0 +1,158 @@
+package taskcompletion
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+var ErrBatcherStopped = errors.New("task completion batcher is not running")
+
+var errNoResult = errors.New("the batch completer returned no result for the completion")
+
+type pendingCompletion struct {
+	completion Completion
+	attempts   int
+	err        error
+	done       chan error
+}
+
+// Batcher is a Completer that collects the completions reported around the
+// same time, for instance when a nightly batch of tasks finishes, and hands
+// them to its BatchCompleter once flushSize of them are pending or every
+// flushInterval. Completions failing for another reason than an invalid
+// state transition or a missing task are retried with the next flush, for up
+// to maxAttempts attempts. Complete blocks until the completion is reported,
+// so that callers only destroy the container of a task once the BBS knows
+// its result.
+type Batcher struct {
+	logger        lager.Logger
+	completer     BatchCompleter
+	clock         clock.Clock
+	flushSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+
+	pending chan *pendingCompletion
+	stopped chan struct{}
+	queued  int64
+}
+
+func NewBatcher(
+	logger lager.Logger,
+	completer BatchCompleter,
+	clock clock.Clock,
+	flushSize int,
+	flushInterval time.Duration,
+	maxAttempts int,
+) *Batcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &Batcher{
+		logger:        logger.Session("task-completion-batcher"),
+		completer:     completer,
+		clock:         clock,
+		flushSize:     flushSize,
+		flushInterval: flushInterval,
+		maxAttempts:   maxAttempts,
+		pending:       make(chan *pendingCompletion),
+		stopped:       make(chan struct{}),
+	}
+}
+
+func (b *Batcher) Complete(logger lager.Logger, completion Completion) error {
+	pending := &pendingCompletion{completion: completion, done: make(chan error, 1)}
+
+	select {
+	case b.pending <- pending:
+	case <-b.stopped:
+		return ErrBatcherStopped
+	}
+
+	return <-pending.done
+}
+
+// Pending returns the number of completions waiting for the next flush,
+// including those to retry.
+func (b *Batcher) Pending() int {
+	return int(atomic.LoadInt64(&b.queued))
+}
+
+func (b *Batcher) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	defer close(b.stopped)
+	close(ready)
+
+	ticker := b.clock.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	batch := []*pendingCompletion{}
+	for {
+		select {
+		case pending := <-b.pending:
+			batch = append(batch, pending)
+			if len(batch) >= b.flushSize {
+				batch = b.flush(batch)
+			}
+			atomic.StoreInt64(&b.queued, int64(len(batch)))
+		case <-ticker.C():
+			batch = b.flush(batch)
+			atomic.StoreInt64(&b.queued, int64(len(batch)))
+		case <-signals:
+			for _, pending := range b.flush(batch) {
+				pending.done <- pending.err
+			}
+			return nil
+		}
+	}
+}
+
+// flush reports the batch and returns the completions to retry.
+func (b *Batcher) flush(batch []*pendingCompletion) []*pendingCompletion {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	logger := b.logger.Session("flush", lager.Data{"batch-size": len(batch)})
+
+	completions := make([]Completion, len(batch))
+	for i, pending := range batch {
+		completions[i] = pending.completion
+	}
+	errs := b.completer.CompleteBatch(logger, completions)
+
+	retries := []*pendingCompletion{}
+	for i, pending := range batch {
+		pending.attempts++
+		pending.err = errNoResult
+		if i < len(errs) {
+			pending.err = errs[i]
+		}
+
+		if pending.err != nil && retryable(pending.err) && pending.attempts < b.maxAttempts {
+			logger.Info("retrying-completion", lager.Data{"task-guid": pending.completion.TaskGuid, "attempts": pending.attempts, "error": pending.err.Error()})
+			retries = append(retries, pending)
+			continue
+		}
+		pending.done <- pending.err
+	}
+
+	return retries
+}
+
+// retryable reports whether the BBS may accept the completion when it is
+// reported again.
+func retryable(err error) bool {
+	switch models.ConvertError(err).Type {
+	case models.Error_InvalidStateTransition, models.Error_ResourceNotFound:
+		return false
+	default:
+		return true
+	}
+}
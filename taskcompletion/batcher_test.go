@@ -0,0 +1,149 @@
+package taskcompletion_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep/taskcompletion"
+	"code.cloudfoundry.org/rep/taskcompletion/taskcompletionfakes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batcher", func() {
+	var (
+		logger         *lagertest.TestLogger
+		batchCompleter *taskcompletionfakes.FakeBatchCompleter
+		fakeClock      *fakeclock.FakeClock
+		batcher        *taskcompletion.Batcher
+		process        ifrit.Process
+	)
+
+	complete := func(taskGuid string) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- batcher.Complete(logger, taskcompletion.Completion{TaskGuid: taskGuid})
+		}()
+		return errs
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		batchCompleter = new(taskcompletionfakes.FakeBatchCompleter)
+		batchCompleter.CompleteBatchStub = func(_ lager.Logger, completions []taskcompletion.Completion) []error {
+			return make([]error, len(completions))
+		}
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		batcher = taskcompletion.NewBatcher(logger, batchCompleter, fakeClock, 2, time.Second, 2)
+		process = ginkgomon.Invoke(batcher)
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+	})
+
+	It("reports the completions once the batch is full", func() {
+		first := complete("task-1")
+		Consistently(batchCompleter.CompleteBatchCallCount).Should(Equal(0))
+
+		second := complete("task-2")
+		Eventually(first).Should(Receive(BeNil()))
+		Eventually(second).Should(Receive(BeNil()))
+
+		Expect(batchCompleter.CompleteBatchCallCount()).To(Equal(1))
+		_, completions := batchCompleter.CompleteBatchArgsForCall(0)
+		Expect(completions).To(ConsistOf(
+			taskcompletion.Completion{TaskGuid: "task-1"},
+			taskcompletion.Completion{TaskGuid: "task-2"},
+		))
+	})
+
+	It("reports the pending completions every flush interval", func() {
+		errs := complete("task-1")
+		Consistently(errs).ShouldNot(Receive())
+
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+		Eventually(errs).Should(Receive(BeNil()))
+		Expect(batchCompleter.CompleteBatchCallCount()).To(Equal(1))
+	})
+
+	It("counts the completions waiting for the next flush", func() {
+		Expect(batcher.Pending()).To(BeZero())
+
+		errs := complete("task-1")
+		Eventually(batcher.Pending).Should(Equal(1))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Second)
+		Eventually(errs).Should(Receive(BeNil()))
+		Eventually(batcher.Pending).Should(BeZero())
+	})
+
+	It("reports the pending completions when it is signalled", func() {
+		errs := complete("task-1")
+		Consistently(errs).ShouldNot(Receive())
+
+		ginkgomon.Interrupt(process)
+		Eventually(errs).Should(Receive(BeNil()))
+		Expect(batchCompleter.CompleteBatchCallCount()).To(Equal(1))
+	})
+
+	Context("when a completion fails", func() {
+		BeforeEach(func() {
+			batchCompleter.CompleteBatchStub = func(_ lager.Logger, completions []taskcompletion.Completion) []error {
+				errs := make([]error, len(completions))
+				for i := range completions {
+					if completions[i].TaskGuid == "task-1" {
+						errs[i] = errors.New("boom")
+					}
+				}
+				return errs
+			}
+		})
+
+		It("retries it with the next flush, up to the maximum attempts", func() {
+			first := complete("task-1")
+			second := complete("task-2")
+			Eventually(second).Should(Receive(BeNil()))
+			Consistently(first).ShouldNot(Receive())
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(first).Should(Receive(MatchError("boom")))
+
+			Expect(batchCompleter.CompleteBatchCallCount()).To(Equal(2))
+			_, completions := batchCompleter.CompleteBatchArgsForCall(1)
+			Expect(completions).To(Equal([]taskcompletion.Completion{{TaskGuid: "task-1"}}))
+		})
+	})
+
+	Context("when the task no longer exists", func() {
+		BeforeEach(func() {
+			batchCompleter.CompleteBatchReturns([]error{models.ErrResourceNotFound})
+		})
+
+		It("does not retry it", func() {
+			errs := complete("task-1")
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(errs).Should(Receive(Equal(models.ErrResourceNotFound)))
+			Expect(batchCompleter.CompleteBatchCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the batcher has stopped", func() {
+		BeforeEach(func() {
+			ginkgomon.Interrupt(process)
+		})
+
+		It("fails the completion", func() {
+			Expect(batcher.Complete(logger, taskcompletion.Completion{TaskGuid: "task-1"})).To(Equal(taskcompletion.ErrBatcherStopped))
+		})
+	})
+})
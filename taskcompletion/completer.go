@@ -0,0 +1,67 @@
+package taskcompletion
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/bbs"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+)
+
+// Completion is the outcome of a task the rep reports to the BBS.
+type Completion struct {
+	TaskGuid      string
+	Failed        bool
+	FailureReason string
+	Result        string
+	// Provenance identifies the auction that placed the task, when its
+	// container recorded one.
+	Provenance *rep.Provenance
+}
+
+//go:generate counterfeiter -o taskcompletionfakes/fake_completer.go . Completer
+
+// Completer reports the completion of a task to the BBS.
+type Completer interface {
+	Complete(logger lager.Logger, completion Completion) error
+}
+
+//go:generate counterfeiter -o taskcompletionfakes/fake_batch_completer.go . BatchCompleter
+
+// BatchCompleter reports several completions at once and returns the error
+// of each, in order.
+type BatchCompleter interface {
+	CompleteBatch(logger lager.Logger, completions []Completion) []error
+}
+
+// BBSCompleter reports completions to the BBS. The BBS completes one task
+// per request, so a batch is reported with concurrent requests.
+type BBSCompleter struct {
+	bbsClient bbs.InternalClient
+	cellID    string
+}
+
+func NewBBSCompleter(bbsClient bbs.InternalClient, cellID string) *BBSCompleter {
+	return &BBSCompleter{bbsClient: bbsClient, cellID: cellID}
+}
+
+func (c *BBSCompleter) Complete(logger lager.Logger, completion Completion) error {
+	logger = rep.WithProvenance(logger, completion.Provenance)
+	return c.bbsClient.CompleteTask(logger, completion.TaskGuid, c.cellID, completion.Failed, completion.FailureReason, completion.Result)
+}
+
+func (c *BBSCompleter) CompleteBatch(logger lager.Logger, completions []Completion) []error {
+	errs := make([]error, len(completions))
+
+	wg := sync.WaitGroup{}
+	for i := range completions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.Complete(logger, completions[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1 @@
+package taskcompletion // import "code.cloudfoundry.org/rep/taskcompletion"
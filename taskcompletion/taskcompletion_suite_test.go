@@ -0,0 +1,13 @@
+package taskcompletion_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTaskCompletion(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Task Completion Suite")
+}
@@ -0,0 +1 @@
+package taskcompletionfakes // import "code.cloudfoundry.org/rep/taskcompletion/taskcompletionfakes"
@@ -0,0 +1,138 @@
+package rep
+
+import "sort"
+
+// The labels identifying the organization and space work belongs to. Work
+// without an organization label is not accounted to any tenant.
+const (
+	OrganizationLabel = "organization_id"
+	SpaceLabel        = "space_id"
+)
+
+// TenantUsage is the resources the work of one space of an organization
+// reserves on a cell.
+type TenantUsage struct {
+	OrganizationID string
+	SpaceID        string `json:",omitempty"`
+	MemoryMB       int32
+	DiskMB         int32
+	Containers     int
+}
+
+// TenantCaps limit the resources the work of a single organization may
+// reserve on a cell, so that one tenant cannot take over a shared cell. A
+// zero limit leaves the resource unlimited.
+type TenantCaps struct {
+	MemoryMB   int32 `json:",omitempty"`
+	DiskMB     int32 `json:",omitempty"`
+	Containers int   `json:",omitempty"`
+}
+
+// TenantUsages aggregates the resources lrps and tasks reserve per
+// organization and space, ordered by organization and then space.
+func TenantUsages(lrps []LRP, tasks []Task) []TenantUsage {
+	var usages []TenantUsage
+	for i := range lrps {
+		usages = addTenantUsage(usages, lrps[i].Labels, &lrps[i].Resource)
+	}
+	for i := range tasks {
+		usages = addTenantUsage(usages, tasks[i].Labels, &tasks[i].Resource)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].OrganizationID != usages[j].OrganizationID {
+			return usages[i].OrganizationID < usages[j].OrganizationID
+		}
+		return usages[i].SpaceID < usages[j].SpaceID
+	})
+	return usages
+}
+
+func addTenantUsage(usages []TenantUsage, labels map[string]string, res *Resource) []TenantUsage {
+	organization := labels[OrganizationLabel]
+	if organization == "" {
+		return usages
+	}
+	space := labels[SpaceLabel]
+
+	for i := range usages {
+		if usages[i].OrganizationID == organization && usages[i].SpaceID == space {
+			usages[i].MemoryMB += res.MemoryMB
+			usages[i].DiskMB += res.DiskMB
+			usages[i].Containers++
+			return usages
+		}
+	}
+	return append(usages, TenantUsage{
+		OrganizationID: organization,
+		SpaceID:        space,
+		MemoryMB:       res.MemoryMB,
+		DiskMB:         res.DiskMB,
+		Containers:     1,
+	})
+}
+
+// removeTenantUsage takes res off the usage of the space labels belong to,
+// dropping the usage of a space left without containers.
+func removeTenantUsage(usages []TenantUsage, labels map[string]string, res *Resource) []TenantUsage {
+	organization := labels[OrganizationLabel]
+	if organization == "" {
+		return usages
+	}
+	space := labels[SpaceLabel]
+
+	for i := range usages {
+		if usages[i].OrganizationID != organization || usages[i].SpaceID != space {
+			continue
+		}
+		usages[i].MemoryMB -= res.MemoryMB
+		usages[i].DiskMB -= res.DiskMB
+		usages[i].Containers--
+		if usages[i].Containers > 0 {
+			return usages
+		}
+		return append(usages[:i], usages[i+1:]...)
+	}
+	return usages
+}
+
+// OrganizationUsage returns the resources all the spaces of organization
+// reserve on the cell.
+func (c *CellState) OrganizationUsage(organization string) Resources {
+	usage := Resources{}
+	for i := range c.TenantUsage {
+		if c.TenantUsage[i].OrganizationID == organization {
+			usage.MemoryMB += c.TenantUsage[i].MemoryMB
+			usage.DiskMB += c.TenantUsage[i].DiskMB
+			usage.Containers += c.TenantUsage[i].Containers
+		}
+	}
+	return usage
+}
+
+// tenantCapMatch returns an InsufficientResourcesError when work labelled
+// with labels requiring res would take its organization over the cell's
+// TenantCaps.
+func (c *CellState) tenantCapMatch(labels map[string]string, res *Resource) error {
+	organization := labels[OrganizationLabel]
+	if c.TenantCaps == nil || organization == "" {
+		return nil
+	}
+
+	problems := map[string]struct{}{}
+	usage := c.OrganizationUsage(organization)
+	if c.TenantCaps.MemoryMB > 0 && usage.MemoryMB+res.MemoryMB > c.TenantCaps.MemoryMB {
+		problems["tenant memory"] = struct{}{}
+	}
+	if c.TenantCaps.DiskMB > 0 && usage.DiskMB+res.DiskMB > c.TenantCaps.DiskMB {
+		problems["tenant disk"] = struct{}{}
+	}
+	if c.TenantCaps.Containers > 0 && usage.Containers+1 > c.TenantCaps.Containers {
+		problems["tenant containers"] = struct{}{}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return InsufficientResourcesError{Problems: problems}
+}
@@ -0,0 +1,91 @@
+package tenancy
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	loggingclient "code.cloudfoundry.org/diego-logging-client"
+	loggregator "code.cloudfoundry.org/go-loggregator/v8"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep"
+)
+
+const (
+	tenantReservedMemoryMetric = "TenantReservedMemory"
+	tenantReservedDiskMetric   = "TenantReservedDisk"
+	tenantContainerCountMetric = "TenantContainerCount"
+)
+
+// Emitter emits the resources every tenant reserves on the cell every
+// interval, tagged with the organization and space of the tenant.
+type Emitter struct {
+	logger        lager.Logger
+	stateReporter auctioncellrep.StateReporter
+	metronClient  loggingclient.IngressClient
+	clock         clock.Clock
+	interval      time.Duration
+}
+
+func NewEmitter(logger lager.Logger, stateReporter auctioncellrep.StateReporter, metronClient loggingclient.IngressClient, clock clock.Clock, interval time.Duration) *Emitter {
+	return &Emitter{
+		logger:        logger.Session("tenant-usage-emitter"),
+		stateReporter: stateReporter,
+		metronClient:  metronClient,
+		clock:         clock,
+		interval:      interval,
+	}
+}
+
+func (e *Emitter) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C():
+			e.emit()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (e *Emitter) emit() {
+	state, _, err := e.stateReporter.State(context.Background(), e.logger)
+	if err != nil {
+		e.logger.Error("failed-to-fetch-state", err)
+		return
+	}
+
+	for _, usage := range state.TenantUsage {
+		e.emitUsage(usage)
+	}
+}
+
+func (e *Emitter) emitUsage(usage rep.TenantUsage) {
+	tags := []loggregator.EmitGaugeOption{
+		loggregator.WithEnvelopeTag(rep.OrganizationLabel, usage.OrganizationID),
+		loggregator.WithEnvelopeTag(rep.SpaceLabel, usage.SpaceID),
+	}
+	data := lager.Data{"organization": usage.OrganizationID, "space": usage.SpaceID}
+
+	err := e.metronClient.SendMebiBytes(tenantReservedMemoryMetric, int(usage.MemoryMB), tags...)
+	if err != nil {
+		e.logger.Error("failed-to-send-tenant-memory-metric", err, data)
+	}
+
+	err = e.metronClient.SendMebiBytes(tenantReservedDiskMetric, int(usage.DiskMB), tags...)
+	if err != nil {
+		e.logger.Error("failed-to-send-tenant-disk-metric", err, data)
+	}
+
+	err = e.metronClient.SendMetric(tenantContainerCountMetric, usage.Containers, tags...)
+	if err != nil {
+		e.logger.Error("failed-to-send-tenant-containers-metric", err, data)
+	}
+}
@@ -0,0 +1,85 @@
+package tenancy_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	mfakes "code.cloudfoundry.org/diego-logging-client/testhelpers"
+	"code.cloudfoundry.org/lager/lagertest"
+	"code.cloudfoundry.org/rep"
+	"code.cloudfoundry.org/rep/auctioncellrep/auctioncellrepfakes"
+	"code.cloudfoundry.org/rep/tenancy"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emitter", func() {
+	var (
+		logger        *lagertest.TestLogger
+		stateReporter *auctioncellrepfakes.FakeAuctionCellClient
+		metronClient  *mfakes.FakeIngressClient
+		fakeClock     *fakeclock.FakeClock
+		process       ifrit.Process
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		stateReporter = new(auctioncellrepfakes.FakeAuctionCellClient)
+		metronClient = new(mfakes.FakeIngressClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		stateReporter.StateReturns(rep.CellState{
+			TenantUsage: []rep.TenantUsage{
+				{OrganizationID: "org-a", SpaceID: "space-1", MemoryMB: 128, DiskMB: 1024, Containers: 2},
+			},
+		}, true, nil)
+	})
+
+	JustBeforeEach(func() {
+		process = ginkgomon.Invoke(tenancy.NewEmitter(logger, stateReporter, metronClient, fakeClock, time.Minute))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Kill(process)
+	})
+
+	It("emits the resources of every tenant every interval", func() {
+		Consistently(metronClient.SendMetricCallCount).Should(BeZero())
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(metronClient.SendMetricCallCount).Should(Equal(1))
+
+		name, value, opts := metronClient.SendMetricArgsForCall(0)
+		Expect(name).To(Equal("TenantContainerCount"))
+		Expect(value).To(Equal(2))
+		Expect(opts).To(HaveLen(2))
+
+		Expect(metronClient.SendMebiBytesCallCount()).To(Equal(2))
+		name, value, _ = metronClient.SendMebiBytesArgsForCall(0)
+		Expect(name).To(Equal("TenantReservedMemory"))
+		Expect(value).To(Equal(128))
+		name, value, _ = metronClient.SendMebiBytesArgsForCall(1)
+		Expect(name).To(Equal("TenantReservedDisk"))
+		Expect(value).To(Equal(1024))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(metronClient.SendMetricCallCount).Should(Equal(2))
+	})
+
+	Context("when fetching the state fails", func() {
+		BeforeEach(func() {
+			stateReporter.StateReturns(rep.CellState{}, false, errors.New("boom"))
+		})
+
+		It("logs the error and emits nothing", func() {
+			fakeClock.WaitForWatcherAndIncrement(time.Minute)
+			Eventually(logger).Should(gbytes.Say("failed-to-fetch-state"))
+			Expect(metronClient.SendMetricCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1 @@
+package tenancy // import "code.cloudfoundry.org/rep/tenancy"
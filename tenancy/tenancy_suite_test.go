@@ -0,0 +1,13 @@
+package tenancy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTenancy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tenancy Suite")
+}
@@ -0,0 +1,106 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tenancy", func() {
+	var rootFS string
+
+	tenantLabels := func(organization, space string) map[string]string {
+		return map[string]string{rep.OrganizationLabel: organization, rep.SpaceLabel: space}
+	}
+
+	lrpOf := func(instanceGuid, organization, space string, memoryMB, diskMB int32) *rep.LRP {
+		lrp := buildLRP(instanceGuid, "pg-"+instanceGuid, "domain", 0, rootFS, memoryMB, diskMB, 10, []string{}, []string{}, models.ActualLRPStateUnclaimed)
+		if organization != "" {
+			lrp.Labels = tenantLabels(organization, space)
+		}
+		return lrp
+	}
+
+	BeforeEach(func() {
+		rootFS = models.PreloadedRootFS("linux")
+	})
+
+	Describe("TenantUsages", func() {
+		It("aggregates the resources of the work per organization and space", func() {
+			task := buildTask("tg-1", "domain", rootFS, 5, 50, 10, []string{}, []string{}, models.Task_Running, false)
+			task.Labels = tenantLabels("org-a", "space-1")
+
+			usages := rep.TenantUsages([]rep.LRP{
+				*lrpOf("ig-1", "org-b", "space-3", 10, 100),
+				*lrpOf("ig-2", "org-a", "space-2", 20, 200),
+				*lrpOf("ig-3", "org-a", "space-1", 30, 300),
+				*lrpOf("ig-4", "", "", 40, 400),
+			}, []rep.Task{*task})
+
+			Expect(usages).To(Equal([]rep.TenantUsage{
+				{OrganizationID: "org-a", SpaceID: "space-1", MemoryMB: 35, DiskMB: 350, Containers: 2},
+				{OrganizationID: "org-a", SpaceID: "space-2", MemoryMB: 20, DiskMB: 200, Containers: 1},
+				{OrganizationID: "org-b", SpaceID: "space-3", MemoryMB: 10, DiskMB: 100, Containers: 1},
+			}))
+		})
+
+		It("returns nil when no work belongs to a tenant", func() {
+			Expect(rep.TenantUsages([]rep.LRP{*lrpOf("ig-1", "", "", 10, 100)}, nil)).To(BeNil())
+		})
+	})
+
+	Describe("tenant caps", func() {
+		var cellState rep.CellState
+
+		BeforeEach(func() {
+			cellState = rep.NewCellState(
+				"cell-id",
+				0,
+				"https://foo.cell.service.cf.internal",
+				rep.RootFSProviders{models.PreloadedRootFSScheme: rep.NewFixedSetRootFSProvider("linux")},
+				rep.NewResources(1000, 2000, 10),
+				rep.NewResources(1000, 2000, 10),
+				nil,
+				nil,
+				"my-zone",
+				0,
+				false,
+				nil,
+				nil,
+				nil,
+				0,
+			)
+			cellState.TenantCaps = &rep.TenantCaps{MemoryMB: 100, Containers: 2}
+			cellState.AddLRP(lrpOf("ig-1", "org-a", "space-1", 60, 100))
+		})
+
+		It("accounts added work to its tenant", func() {
+			Expect(cellState.OrganizationUsage("org-a")).To(Equal(rep.Resources{MemoryMB: 60, DiskMB: 100, Containers: 1}))
+		})
+
+		It("rejects work that would take its organization over the caps", func() {
+			err := cellState.LRPResourceMatch(lrpOf("ig-2", "org-a", "space-2", 50, 100))
+			Expect(err).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"tenant memory": {}}}))
+		})
+
+		It("rejects work once its organization has as many containers as allowed", func() {
+			cellState.AddLRP(lrpOf("ig-2", "org-a", "space-2", 10, 100))
+			task := buildTask("tg-1", "domain", rootFS, 10, 100, 10, []string{}, []string{}, models.Task_Pending, false)
+			task.Labels = tenantLabels("org-a", "space-1")
+			err := cellState.TaskResourceMatch(task)
+			Expect(err).To(MatchError(rep.InsufficientResourcesError{Problems: map[string]struct{}{"tenant containers": {}}}))
+		})
+
+		It("accepts work of other organizations and of no tenant", func() {
+			Expect(cellState.LRPResourceMatch(lrpOf("ig-2", "org-b", "space-3", 90, 100))).To(Succeed())
+			Expect(cellState.LRPResourceMatch(lrpOf("ig-3", "", "", 90, 100))).To(Succeed())
+		})
+
+		It("does not limit tenants on cells without caps", func() {
+			cellState.TenantCaps = nil
+			Expect(cellState.LRPResourceMatch(lrpOf("ig-2", "org-a", "space-2", 90, 100))).To(Succeed())
+		})
+	})
+})
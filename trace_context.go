@@ -0,0 +1,135 @@
+package rep
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/lager"
+)
+
+// The W3C trace context headers the rep reads from, and its client sets on,
+// requests.
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+	BaggageHeader     = "baggage"
+)
+
+// The tags the trace context of the auction that placed work is recorded
+// in on its container. The executor passes the tags on to the properties of
+// the garden container, so that a slow create can be correlated with the
+// auction.
+const (
+	TraceParentTag = "trace-parent"
+	TraceStateTag  = "trace-state"
+	BaggageTag     = "trace-baggage"
+)
+
+// maxBaggageBytes is the size of the baggage headers are expected to
+// propagate at least. Larger baggage is dropped rather than stored on the
+// container.
+const maxBaggageBytes = 8192
+
+// TraceContext is the W3C trace context of a request.
+type TraceContext struct {
+	TraceParent string `json:"trace_parent"`
+	TraceState  string `json:"trace_state,omitempty"`
+	Baggage     string `json:"baggage,omitempty"`
+}
+
+// TraceContextFromHeader returns the trace context of header, or nil when it
+// has no valid traceparent.
+func TraceContextFromHeader(header http.Header) *TraceContext {
+	return newTraceContext(header.Get(TraceParentHeader), header.Get(TraceStateHeader), header.Get(BaggageHeader))
+}
+
+// TraceContextFromTags returns the trace context recorded on the tags of a
+// container, or nil when none is.
+func TraceContextFromTags(tags executor.Tags) *TraceContext {
+	return newTraceContext(tags[TraceParentTag], tags[TraceStateTag], tags[BaggageTag])
+}
+
+func newTraceContext(traceParent, traceState, baggage string) *TraceContext {
+	if !validTraceParent(traceParent) {
+		return nil
+	}
+	if len(baggage) > maxBaggageBytes {
+		baggage = ""
+	}
+	return &TraceContext{TraceParent: traceParent, TraceState: traceState, Baggage: baggage}
+}
+
+// validTraceParent reports whether traceParent is of the form
+// version-traceid-parentid-flags.
+func validTraceParent(traceParent string) bool {
+	fields := strings.Split(traceParent, "-")
+	if len(fields) < 4 {
+		return false
+	}
+	for i, length := range []int{2, 32, 16, 2} {
+		if len(fields[i]) != length {
+			return false
+		}
+		if _, err := hex.DecodeString(fields[i]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// TraceID returns the id of the trace the context belongs to.
+func (t *TraceContext) TraceID() string {
+	return strings.Split(t.TraceParent, "-")[1]
+}
+
+// SetHeader sets the trace context headers of header.
+func (t *TraceContext) SetHeader(header http.Header) {
+	header.Set(TraceParentHeader, t.TraceParent)
+	if t.TraceState != "" {
+		header.Set(TraceStateHeader, t.TraceState)
+	}
+	if t.Baggage != "" {
+		header.Set(BaggageHeader, t.Baggage)
+	}
+}
+
+// AddTraceContextTags records trace on the tags of a container. It does
+// nothing when trace is nil.
+func AddTraceContextTags(tags executor.Tags, trace *TraceContext) {
+	if trace == nil {
+		return
+	}
+	tags[TraceParentTag] = trace.TraceParent
+	if trace.TraceState != "" {
+		tags[TraceStateTag] = trace.TraceState
+	}
+	if trace.Baggage != "" {
+		tags[BaggageTag] = trace.Baggage
+	}
+}
+
+// WithTraceID returns logger logging the id of the trace recorded on the tags
+// of a container along with its messages, or logger when none is.
+func WithTraceID(logger lager.Logger, tags executor.Tags) lager.Logger {
+	trace := TraceContextFromTags(tags)
+	if trace == nil {
+		return logger
+	}
+	return logger.WithData(lager.Data{"trace-id": trace.TraceID()})
+}
+
+type traceContextKey struct{}
+
+// WithTraceContext returns a copy of ctx carrying trace.
+func WithTraceContext(ctx context.Context, trace *TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, trace)
+}
+
+// TraceContextFromContext returns the trace context ctx carries, or nil.
+func TraceContextFromContext(ctx context.Context) *TraceContext {
+	trace, _ := ctx.Value(traceContextKey{}).(*TraceContext)
+	return trace
+}
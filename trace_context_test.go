@@ -0,0 +1,62 @@
+package rep_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/executor"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TraceContext", func() {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var trace *rep.TraceContext
+
+	BeforeEach(func() {
+		trace = &rep.TraceContext{TraceParent: traceParent, TraceState: "vendor=value", Baggage: "auction=1"}
+	})
+
+	It("round trips through headers", func() {
+		header := http.Header{}
+		trace.SetHeader(header)
+		Expect(header.Get("traceparent")).To(Equal(traceParent))
+		Expect(rep.TraceContextFromHeader(header)).To(Equal(trace))
+	})
+
+	It("round trips through container tags", func() {
+		tags := executor.Tags{}
+		rep.AddTraceContextTags(tags, trace)
+		Expect(tags).To(HaveKeyWithValue(rep.TraceParentTag, traceParent))
+		Expect(rep.TraceContextFromTags(tags)).To(Equal(trace))
+	})
+
+	It("round trips through a context", func() {
+		ctx := rep.WithTraceContext(context.Background(), trace)
+		Expect(rep.TraceContextFromContext(ctx)).To(Equal(trace))
+		Expect(rep.TraceContextFromContext(context.Background())).To(BeNil())
+	})
+
+	It("returns the trace id", func() {
+		Expect(trace.TraceID()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+	})
+
+	It("ignores invalid traceparents", func() {
+		for _, invalid := range []string{"", "00-4bf92f35-00f067aa0ba902b7-01", "00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"} {
+			header := http.Header{}
+			header.Set(rep.TraceParentHeader, invalid)
+			Expect(rep.TraceContextFromHeader(header)).To(BeNil())
+		}
+	})
+
+	It("drops oversized baggage", func() {
+		header := http.Header{}
+		header.Set(rep.TraceParentHeader, traceParent)
+		header.Set(rep.BaggageHeader, strings.Repeat("a", 8193))
+		Expect(rep.TraceContextFromHeader(header)).To(Equal(&rep.TraceContext{TraceParent: traceParent}))
+	})
+})
@@ -0,0 +1,49 @@
+package rep
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The reasons a cell reports in its state for being unhealthy, so that the
+// auctioneer and operators can tell why a cell was left out of the auction
+// without reading its logs.
+const (
+	UnhealthyReasonGardenUnresponsive = "garden unresponsive"
+)
+
+// The conditions a cell reports in its state without being unhealthy, for the
+// auctioneer and operators to take into account.
+const (
+	CellConditionDiskPressure = "disk pressure"
+	CellConditionPresenceLost = "presence lost"
+)
+
+// CellUnhealthyError is returned for the state of a cell that reported itself
+// unhealthy. Reasons are empty for reps that predate them.
+type CellUnhealthyError struct {
+	Reasons []string
+}
+
+func (e CellUnhealthyError) Error() string {
+	if len(e.Reasons) == 0 {
+		return "cell is unhealthy"
+	}
+	return fmt.Sprintf("cell is unhealthy: %s", strings.Join(e.Reasons, ", "))
+}
+
+// unhealthyError returns the CellUnhealthyError for the body of an unhealthy
+// state response, which holds the state, or its delta when isDelta is set.
+func unhealthyError(body []byte, isDelta bool) CellUnhealthyError {
+	var state CellState
+	if isDelta {
+		var delta CellStateDelta
+		if json.Unmarshal(body, &delta) == nil {
+			state = delta.State
+		}
+	} else {
+		json.Unmarshal(body, &state)
+	}
+	return CellUnhealthyError{Reasons: state.UnhealthyReasons}
+}
@@ -0,0 +1,139 @@
+package rep_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// the fixtures hold the wire format of the CellState and Work exchanged with
+// the auctioneer. Older reps and auctioneers still send them, so changes that
+// fail these tests break rolling upgrades.
+var _ = Describe("Wire format", func() {
+	var (
+		lrp  rep.LRP
+		task rep.Task
+	)
+
+	BeforeEach(func() {
+		lrp = rep.NewLRP(
+			"instance-guid",
+			models.NewActualLRPKey("process-guid", 1, "domain"),
+			rep.NewResource(1024, 2048, 1024),
+			rep.NewPlacementConstraint("preloaded:cflinuxfs4", []string{"tag"}, []string{"driver"}),
+		)
+		task = rep.NewTask(
+			"task-guid",
+			"domain",
+			rep.NewResource(256, 512, 0),
+			rep.NewPlacementConstraint("docker:///busybox", []string{"tag"}, []string{"driver"}),
+		)
+	})
+
+	Describe("CellState", func() {
+		var golden []byte
+
+		BeforeEach(func() {
+			var err error
+			golden, err = ioutil.ReadFile("fixtures/cell_state.json")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("decodes every field of the golden state", func() {
+			lrp.State = "RUNNING"
+			task.Failed = true
+
+			var state rep.CellState
+			Expect(json.Unmarshal(golden, &state)).To(Succeed())
+			Expect(state.Validate()).To(Succeed())
+			Expect(state).To(Equal(rep.CellState{
+				RepURL:    "https://cell-id.cell.service.cf.internal:1801",
+				CellID:    "cell-id",
+				CellIndex: 3,
+				RootFSProviders: rep.RootFSProviders{
+					"preloaded": rep.NewFixedSetRootFSProvider("cflinuxfs4"),
+					"docker":    rep.ArbitraryRootFSProvider{},
+				},
+				AvailableResources:      rep.NewResources(4096, 8192, 200),
+				TotalResources:          rep.NewResources(8192, 16384, 250),
+				LRPs:                    []rep.LRP{lrp},
+				Tasks:                   []rep.Task{task},
+				StartingContainerCount:  1,
+				Zone:                    "z1",
+				InstanceID:              "i-1234",
+				OSFamily:                rep.OSFamilyLinux,
+				VolumeDrivers:           []string{"driver"},
+				PlacementTags:           []string{"tag"},
+				OptionalPlacementTags:   []string{"optional-tag"},
+				ProxyMemoryAllocationMB: 32,
+				FeatureFlags:            []string{"local_restart"},
+				MaxContainerMemoryMB:    4096,
+				MaxContainerDiskMB:      8192,
+			}))
+		})
+
+		It("round-trips the golden state", func() {
+			var state rep.CellState
+			Expect(json.Unmarshal(golden, &state)).To(Succeed())
+
+			encoded, err := json.Marshal(state)
+			Expect(err).NotTo(HaveOccurred())
+			expectSameFields(encoded, golden)
+
+			var decoded rep.CellState
+			Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(state))
+		})
+	})
+
+	Describe("Work", func() {
+		var golden []byte
+
+		BeforeEach(func() {
+			var err error
+			golden, err = ioutil.ReadFile("fixtures/work.json")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("decodes every field of the golden work", func() {
+			lrp.Labels = map[string]string{"team": "payments"}
+
+			var work rep.Work
+			Expect(json.Unmarshal(golden, &work)).To(Succeed())
+			Expect(work).To(Equal(rep.Work{
+				LRPs:   []rep.LRP{lrp},
+				Tasks:  []rep.Task{task},
+				CellID: "cell-id",
+			}))
+		})
+
+		It("round-trips the golden work", func() {
+			var work rep.Work
+			Expect(json.Unmarshal(golden, &work)).To(Succeed())
+
+			encoded, err := json.Marshal(work)
+			Expect(err).NotTo(HaveOccurred())
+			expectSameFields(encoded, golden)
+
+			var decoded rep.Work
+			Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(work))
+		})
+	})
+})
+
+// expectSameFields checks that the encoding keeps the top level fields of
+// the golden payload, which a renamed field would lose.
+func expectSameFields(encoded, golden []byte) {
+	var encodedFields, goldenFields map[string]json.RawMessage
+	ExpectWithOffset(1, json.Unmarshal(encoded, &encodedFields)).To(Succeed())
+	ExpectWithOffset(1, json.Unmarshal(golden, &goldenFields)).To(Succeed())
+
+	for field := range goldenFields {
+		ExpectWithOffset(1, encodedFields).To(HaveKey(field))
+	}
+}
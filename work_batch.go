@@ -0,0 +1,189 @@
+package rep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WorkBatchTooLargeError is returned for a Work holding more LRPs and tasks
+// than the MaxWorkBatchSize of the cell, and is the body of the rejection.
+type WorkBatchTooLargeError struct {
+	MaxWorkBatchSize int    `json:"max_work_batch_size"`
+	Message          string `json:"error"`
+}
+
+func NewWorkBatchTooLargeError(maxWorkBatchSize int) WorkBatchTooLargeError {
+	return WorkBatchTooLargeError{
+		MaxWorkBatchSize: maxWorkBatchSize,
+		Message:          fmt.Sprintf("work batch exceeds the limit of %d LRPs and tasks, split it into batches of at most %d", maxWorkBatchSize, maxWorkBatchSize),
+	}
+}
+
+func (e WorkBatchTooLargeError) Error() string {
+	return e.Message
+}
+
+// DecodeWork decodes the JSON Work read from r one LRP and task at a time,
+// returning a WorkBatchTooLargeError as soon as it holds more than
+// maxBatchSize of them rather than after decoding all of them. A zero
+// maxBatchSize decodes the work whatever its size.
+func DecodeWork(r io.Reader, maxBatchSize int) (Work, error) {
+	decoder := json.NewDecoder(r)
+
+	var work Work
+	if maxBatchSize <= 0 {
+		err := decoder.Decode(&work)
+		return work, err
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return Work{}, err
+	}
+	if token != json.Delim('{') {
+		return Work{}, errors.New("work is not a JSON object")
+	}
+
+	batchSize := 0
+	fields := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return Work{}, err
+		}
+		key, _ := token.(string)
+
+		switch {
+		case strings.EqualFold(key, "LRPs"):
+			err = decodeBatchItems(decoder, &batchSize, maxBatchSize, func() error {
+				var lrp LRP
+				err := decoder.Decode(&lrp)
+				work.LRPs = append(work.LRPs, lrp)
+				return err
+			})
+		case strings.EqualFold(key, "Tasks"):
+			err = decodeBatchItems(decoder, &batchSize, maxBatchSize, func() error {
+				var task Task
+				err := decoder.Decode(&task)
+				work.Tasks = append(work.Tasks, task)
+				return err
+			})
+		default:
+			var field json.RawMessage
+			err = decoder.Decode(&field)
+			fields[key] = field
+		}
+		if err != nil {
+			return Work{}, err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return Work{}, err
+	}
+
+	if len(fields) > 0 {
+		payload, err := json.Marshal(fields)
+		if err != nil {
+			return Work{}, err
+		}
+		err = json.Unmarshal(payload, &work)
+		if err != nil {
+			return Work{}, err
+		}
+	}
+
+	return work, nil
+}
+
+// decodeBatchItems decodes the next JSON array of decoder with decodeItem,
+// counting its items into batchSize.
+func decodeBatchItems(decoder *json.Decoder, batchSize *int, maxBatchSize int, decodeItem func() error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return errors.New("work LRPs and tasks are not JSON arrays")
+	}
+
+	for decoder.More() {
+		if *batchSize >= maxBatchSize {
+			return NewWorkBatchTooLargeError(maxBatchSize)
+		}
+		*batchSize++
+
+		err := decodeItem()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// Chunks splits the work into batches of at most size LRPs and tasks, in
+// order. The members of a group are kept in the same batch, so a group larger
+// than size gets a batch of its own that still exceeds it. A zero size leaves
+// the work in a single batch.
+func (w Work) Chunks(size int) []Work {
+	if size <= 0 || w.BatchSize() <= size {
+		return []Work{w}
+	}
+
+	units := []*Work{}
+	groups := map[string]*Work{}
+	unitOf := func(group string) *Work {
+		if unit, ok := groups[group]; ok && group != "" {
+			return unit
+		}
+		unit := &Work{}
+		units = append(units, unit)
+		if group != "" {
+			groups[group] = unit
+		}
+		return unit
+	}
+	for i := range w.LRPs {
+		unit := unitOf(w.LRPs[i].Group)
+		unit.LRPs = append(unit.LRPs, w.LRPs[i])
+	}
+	for i := range w.Tasks {
+		unit := unitOf(w.Tasks[i].Group)
+		unit.Tasks = append(unit.Tasks, w.Tasks[i])
+	}
+
+	chunks := []Work{}
+	chunk := Work{CellID: w.CellID}
+	for _, unit := range units {
+		if chunk.BatchSize() > 0 && chunk.BatchSize()+unit.BatchSize() > size {
+			chunks = append(chunks, chunk)
+			chunk = Work{CellID: w.CellID}
+		}
+		chunk.LRPs = append(chunk.LRPs, unit.LRPs...)
+		chunk.Tasks = append(chunk.Tasks, unit.Tasks...)
+	}
+	return append(chunks, chunk)
+}
+
+// appendWork adds the LRPs, tasks and failures of other to work.
+func appendWork(work *Work, other Work) {
+	work.LRPs = append(work.LRPs, other.LRPs...)
+	work.Tasks = append(work.Tasks, other.Tasks...)
+	work.RegistryValidationFailures = append(work.RegistryValidationFailures, other.RegistryValidationFailures...)
+	work.DuplicateWorkFailures = append(work.DuplicateWorkFailures, other.DuplicateWorkFailures...)
+	work.ImageSizeFailures = append(work.ImageSizeFailures, other.ImageSizeFailures...)
+	work.DirectedPlacementFailures = append(work.DirectedPlacementFailures, other.DirectedPlacementFailures...)
+	work.LifecycleFailures = append(work.LifecycleFailures, other.LifecycleFailures...)
+	work.ImageDigestFailures = append(work.ImageDigestFailures, other.ImageDigestFailures...)
+	work.WorkGroupFailures = append(work.WorkGroupFailures, other.WorkGroupFailures...)
+	work.PolicyFailures = append(work.PolicyFailures, other.PolicyFailures...)
+	work.HostPortConflictFailures = append(work.HostPortConflictFailures, other.HostPortConflictFailures...)
+}
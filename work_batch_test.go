@@ -0,0 +1,90 @@
+package rep_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"code.cloudfoundry.org/bbs/models"
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Work batches", func() {
+	var work rep.Work
+
+	BeforeEach(func() {
+		constraint := rep.NewPlacementConstraint("docker:///busybox", nil, nil)
+		work = rep.Work{
+			LRPs: []rep.LRP{
+				rep.NewLRP("ig-1", models.NewActualLRPKey("pg", 0, "domain"), rep.NewResource(128, 256, 10), constraint),
+				rep.NewLRP("ig-2", models.NewActualLRPKey("pg", 1, "domain"), rep.NewResource(128, 256, 10), constraint),
+			},
+			Tasks: []rep.Task{
+				rep.NewTask("tg-1", "domain", rep.NewResource(128, 256, 10), constraint),
+			},
+			CellID: "cell-id",
+		}
+	})
+
+	encode := func(work rep.Work) *bytes.Buffer {
+		payload, err := json.Marshal(work)
+		Expect(err).NotTo(HaveOccurred())
+		return bytes.NewBuffer(payload)
+	}
+
+	Describe("DecodeWork", func() {
+		It("decodes work within the max batch size like json.Unmarshal", func() {
+			var expected rep.Work
+			Expect(json.Unmarshal(encode(work).Bytes(), &expected)).To(Succeed())
+
+			decoded, err := rep.DecodeWork(encode(work), 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(expected))
+		})
+
+		It("rejects work larger than the max batch size", func() {
+			_, err := rep.DecodeWork(encode(work), 2)
+			Expect(err).To(Equal(rep.NewWorkBatchTooLargeError(2)))
+		})
+
+		It("decodes work of any size without a max batch size", func() {
+			decoded, err := rep.DecodeWork(encode(work), 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded.BatchSize()).To(Equal(3))
+		})
+
+		It("fails on malformed work", func() {
+			_, err := rep.DecodeWork(bytes.NewBufferString(`{"LRPs": {}}`), 2)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Chunks", func() {
+		It("splits the work into batches of at most the given size", func() {
+			chunks := work.Chunks(2)
+			Expect(chunks).To(HaveLen(2))
+			Expect(chunks[0].LRPs).To(Equal(work.LRPs))
+			Expect(chunks[0].Tasks).To(BeEmpty())
+			Expect(chunks[1].Tasks).To(Equal(work.Tasks))
+			Expect(chunks[1].CellID).To(Equal("cell-id"))
+		})
+
+		It("keeps the members of a group together", func() {
+			work.LRPs[0].Group = "group"
+			work.Tasks[0].Group = "group"
+
+			chunks := work.Chunks(2)
+			Expect(chunks).To(HaveLen(2))
+			Expect(chunks[0].LRPs).To(Equal(work.LRPs[:1]))
+			Expect(chunks[0].Tasks).To(Equal(work.Tasks))
+			Expect(chunks[1].LRPs).To(Equal(work.LRPs[1:]))
+		})
+
+		It("leaves work within the size in a single batch", func() {
+			Expect(work.Chunks(3)).To(Equal([]rep.Work{work}))
+			Expect(work.Chunks(0)).To(Equal([]rep.Work{work}))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package rep
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrWorkGroupIncomplete = errors.New("another member of the work group could not be placed on the cell")
+
+// WorkGroupTag records the Group of the LRP instance or task a container is
+// allocated for.
+const WorkGroupTag = "work-group"
+
+// WorkGroupFailure records the LRP instance or task of a Work that was
+// rejected, or whose container was released, because another member of its
+// Group could not be placed on the cell.
+type WorkGroupFailure struct {
+	Group        string `json:"group"`
+	InstanceGUID string `json:"instance_guid,omitempty"`
+	TaskGuid     string `json:"task_guid,omitempty"`
+	Error        string `json:"error"`
+}
+
+// WorkGroupHolds holds back the claims of the containers allocated for a
+// Group until the cell has allocated every member of the group, so that the
+// containers of a group that turns out incomplete are still reserved when
+// they are released.
+type WorkGroupHolds struct {
+	lock sync.Mutex
+	held map[string]*workGroupHold
+}
+
+type workGroupHold struct {
+	count    int
+	released chan struct{}
+}
+
+func NewWorkGroupHolds() *WorkGroupHolds {
+	return &WorkGroupHolds{held: map[string]*workGroupHold{}}
+}
+
+// Hold holds the claims of the containers of group until it is released as
+// many times as it is held.
+func (h *WorkGroupHolds) Hold(group string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	hold, ok := h.held[group]
+	if !ok {
+		hold = &workGroupHold{released: make(chan struct{})}
+		h.held[group] = hold
+	}
+	hold.count++
+}
+
+func (h *WorkGroupHolds) Release(group string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	hold, ok := h.held[group]
+	if !ok {
+		return
+	}
+	hold.count--
+	if hold.count == 0 {
+		close(hold.released)
+		delete(h.held, group)
+	}
+}
+
+// Wait blocks until group is not held, and returns whether it was.
+func (h *WorkGroupHolds) Wait(group string) bool {
+	h.lock.Lock()
+	hold, ok := h.held[group]
+	h.lock.Unlock()
+	if !ok {
+		return false
+	}
+	<-hold.released
+	return true
+}
@@ -0,0 +1,45 @@
+package rep_test
+
+import (
+	"code.cloudfoundry.org/rep"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WorkGroupHolds", func() {
+	var holds *rep.WorkGroupHolds
+
+	BeforeEach(func() {
+		holds = rep.NewWorkGroupHolds()
+	})
+
+	waitFor := func(group string) <-chan bool {
+		waited := make(chan bool, 1)
+		go func() {
+			waited <- holds.Wait(group)
+		}()
+		return waited
+	}
+
+	It("does not wait for a group that is not held", func() {
+		Eventually(waitFor("web")).Should(Receive(BeFalse()))
+	})
+
+	It("waits until the group is released as many times as it is held", func() {
+		holds.Hold("web")
+		holds.Hold("web")
+
+		waited := waitFor("web")
+		holds.Release("web")
+		Consistently(waited).ShouldNot(Receive())
+
+		holds.Release("web")
+		Eventually(waited).Should(Receive(BeTrue()))
+	})
+
+	It("does not hold the other groups", func() {
+		holds.Hold("web")
+		Eventually(waitFor("worker")).Should(Receive(BeFalse()))
+	})
+})